@@ -14,16 +14,20 @@ import (
 )
 
 type Account struct {
-	Id           string                 `json:"id,omitempty"`
-	Scope        *scopes.ScopeInfo      `json:"scope,omitempty"`
-	Name         string                 `json:"name,omitempty"`
-	Description  string                 `json:"description,omitempty"`
-	CreatedTime  time.Time              `json:"created_time,omitempty"`
-	UpdatedTime  time.Time              `json:"updated_time,omitempty"`
-	Version      uint32                 `json:"version,omitempty"`
-	Type         string                 `json:"type,omitempty"`
-	AuthMethodId string                 `json:"auth_method_id,omitempty"`
-	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Id             string                 `json:"id,omitempty"`
+	Scope          *scopes.ScopeInfo      `json:"scope,omitempty"`
+	Name           string                 `json:"name,omitempty"`
+	Description    string                 `json:"description,omitempty"`
+	CreatedTime    time.Time              `json:"created_time,omitempty"`
+	UpdatedTime    time.Time              `json:"updated_time,omitempty"`
+	Version        uint32                 `json:"version,omitempty"`
+	Type           string                 `json:"type,omitempty"`
+	AuthMethodId   string                 `json:"auth_method_id,omitempty"`
+	Attributes     map[string]interface{} `json:"attributes,omitempty"`
+	Disabled       bool                   `json:"disabled,omitempty"`
+	DisabledReason string                 `json:"disabled_reason,omitempty"`
+	DisabledTime   time.Time              `json:"disabled_time,omitempty"`
+	Annotations    map[string]string      `json:"annotations,omitempty"`
 
 	response *api.Response
 }
@@ -45,6 +45,18 @@ func WithAutomaticVersioning(enable bool) Option {
 	}
 }
 
+func WithAnnotations(inAnnotations map[string]string) Option {
+	return func(o *options) {
+		o.postMap["annotations"] = inAnnotations
+	}
+}
+
+func DefaultAnnotations() Option {
+	return func(o *options) {
+		o.postMap["annotations"] = nil
+	}
+}
+
 func WithAttributes(inAttributes map[string]interface{}) Option {
 	return func(o *options) {
 		o.postMap["attributes"] = inAttributes
@@ -69,6 +81,30 @@ func DefaultDescription() Option {
 	}
 }
 
+func WithDisabled(inDisabled bool) Option {
+	return func(o *options) {
+		o.postMap["disabled"] = inDisabled
+	}
+}
+
+func DefaultDisabled() Option {
+	return func(o *options) {
+		o.postMap["disabled"] = nil
+	}
+}
+
+func WithDisabledReason(inDisabledReason string) Option {
+	return func(o *options) {
+		o.postMap["disabled_reason"] = inDisabledReason
+	}
+}
+
+func DefaultDisabledReason() Option {
+	return func(o *options) {
+		o.postMap["disabled_reason"] = nil
+	}
+}
+
 func WithPasswordAccountLoginName(inLoginName string) Option {
 	return func(o *options) {
 		raw, ok := o.postMap["attributes"]
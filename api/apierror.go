@@ -14,6 +14,38 @@ var (
 	ErrUnauthorized     = &Error{Kind: codes.Unauthenticated.String(), response: &Response{resp: &http.Response{StatusCode: http.StatusUnauthorized}}}
 )
 
+// The codes of the errors returned by the controller, set in Error.Code.
+// Unlike messages, codes never change meaning once released, so clients
+// should use them to tell errors apart.
+const (
+	ErrCodeCanceled           = "canceled"
+	ErrCodeUnknown            = "unknown"
+	ErrCodeInvalidArgument    = "invalid_argument"
+	ErrCodeDeadlineExceeded   = "deadline_exceeded"
+	ErrCodeNotFound           = "not_found"
+	ErrCodeAlreadyExists      = "already_exists"
+	ErrCodePermissionDenied   = "permission_denied"
+	ErrCodeResourceExhausted  = "resource_exhausted"
+	ErrCodeFailedPrecondition = "failed_precondition"
+	ErrCodeAborted            = "aborted"
+	ErrCodeOutOfRange         = "out_of_range"
+	ErrCodeUnimplemented      = "unimplemented"
+	ErrCodeInternal           = "internal"
+	ErrCodeUnavailable        = "unavailable"
+	ErrCodeDataLoss           = "data_loss"
+	ErrCodeUnauthenticated    = "unauthenticated"
+
+	// ErrCodeNotUnique is returned when a resource would have the same value
+	// as another for a field which must be unique.
+	ErrCodeNotUnique = "not_unique"
+	// ErrCodeInvalidFieldMask is returned when the update mask of a request is
+	// empty or names fields which can't be updated.
+	ErrCodeInvalidFieldMask = "invalid_field_mask"
+	// ErrCodeRequestTooLarge is returned when the body of a request is larger
+	// than the controller accepts for its endpoint.
+	ErrCodeRequestTooLarge = "request_too_large"
+)
+
 // AsServerError returns an api *Error from the provided error.  If the provided error
 // is not an api Error nil is returned instead.
 func AsServerError(in error) *Error {
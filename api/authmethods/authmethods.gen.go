@@ -24,6 +24,7 @@ type AuthMethod struct {
 	Version     uint32                 `json:"version,omitempty"`
 	Type        string                 `json:"type,omitempty"`
 	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	Annotations map[string]string      `json:"annotations,omitempty"`
 
 	response *api.Response
 }
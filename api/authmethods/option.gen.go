@@ -45,6 +45,18 @@ func WithAutomaticVersioning(enable bool) Option {
 	}
 }
 
+func WithAnnotations(inAnnotations map[string]string) Option {
+	return func(o *options) {
+		o.postMap["annotations"] = inAnnotations
+	}
+}
+
+func DefaultAnnotations() Option {
+	return func(o *options) {
+		o.postMap["annotations"] = nil
+	}
+}
+
 func WithAttributes(inAttributes map[string]interface{}) Option {
 	return func(o *options) {
 		o.postMap["attributes"] = inAttributes
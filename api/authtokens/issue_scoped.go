@@ -0,0 +1,72 @@
+package authtokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Presets of the least privilege restrictions of scoped auth tokens.
+const (
+	// PresetTerraform allows managing resources but not authorizing sessions.
+	PresetTerraform = "terraform"
+	// PresetReadOnly only allows reading and listing resources.
+	PresetReadOnly = "read-only"
+)
+
+// IssueScoped issues an auth token derived from the client's auth token which
+// keeps only its grants in the scopes and is restricted by the preset, e.g.
+// PresetTerraform for provisioning pipelines. The token is valid for ttl,
+// capped at the expiration of the client's auth token, and is deleted with it.
+func (c *Client) IssueScoped(ctx context.Context, preset string, scopeIds []string, ttl time.Duration, opt ...Option) (*AuthTokenReadResult, error) {
+	if c.client == nil {
+		return nil, errors.New("nil client")
+	}
+	if preset == "" {
+		return nil, fmt.Errorf("empty preset value passed into IssueScoped request")
+	}
+	if len(scopeIds) == 0 {
+		return nil, fmt.Errorf("empty scopeIds value passed into IssueScoped request")
+	}
+	if ttl < time.Second {
+		return nil, fmt.Errorf("ttl passed into IssueScoped request must be at least one second")
+	}
+
+	opts, apiOpts := getOpts(opt...)
+
+	opts.postMap["preset"] = preset
+	opts.postMap["scope_ids"] = scopeIds
+	opts.postMap["time_to_live_seconds"] = uint32(ttl / time.Second)
+
+	req, err := c.client.NewRequest(ctx, "POST", "auth-tokens:issue-scoped", opts.postMap, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating IssueScoped request: %w", err)
+	}
+
+	if len(opts.queryMap) > 0 {
+		q := url.Values{}
+		for k, v := range opts.queryMap {
+			q.Add(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during IssueScoped call: %w", err)
+	}
+
+	target := new(AuthTokenReadResult)
+	target.Item = new(AuthToken)
+	apiErr, err := resp.Decode(target.Item)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding IssueScoped response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.response = resp
+	return target, nil
+}
@@ -0,0 +1,49 @@
+package authtokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Refresh issues a new auth token for the login of the client's auth token,
+// which stays valid until it expires. Refreshing fails once the login reaches
+// the maximum auth token lifetime configured on the controller.
+func (c *Client) Refresh(ctx context.Context, opt ...Option) (*AuthTokenReadResult, error) {
+	if c.client == nil {
+		return nil, errors.New("nil client")
+	}
+
+	opts, apiOpts := getOpts(opt...)
+
+	req, err := c.client.NewRequest(ctx, "POST", "auth-tokens:refresh", nil, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Refresh request: %w", err)
+	}
+
+	if len(opts.queryMap) > 0 {
+		q := url.Values{}
+		for k, v := range opts.queryMap {
+			q.Add(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during Refresh call: %w", err)
+	}
+
+	target := new(AuthTokenReadResult)
+	target.Item = new(AuthToken)
+	apiErr, err := resp.Decode(target.Item)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding Refresh response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.response = resp
+	return target, nil
+}
@@ -78,13 +78,28 @@ type Config struct {
 	// of three tries).
 	MaxRetries int
 
-	// Timeout is for setting custom timeout parameter in the HttpClient
+	// RetryWaitMin and RetryWaitMax bound the wait between retries computed
+	// by the Backoff function. They default to DefaultRetryWaitMin and
+	// DefaultRetryWaitMax.
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+
+	// RetryAfterMax is the longest wait honored from the Retry-After header
+	// of a response by the default Backoff function. It defaults to
+	// DefaultRetryAfterMax.
+	RetryAfterMax time.Duration
+
+	// Timeout is for setting custom timeout parameter in the HttpClient. It
+	// does not apply to calls whose context already has a deadline, so a
+	// call can be given a longer or shorter timeout through its context.
 	Timeout time.Duration
 
-	// The Backoff function to use; a default is used if not provided
+	// The Backoff function to use; a RetryAfterBackoff limited by
+	// RetryAfterMax is used if not provided
 	Backoff retryablehttp.Backoff
 
-	// The CheckRetry function to use; a default is used if not provided
+	// The CheckRetry function to use; DefaultRetryPolicy is used if not
+	// provided
 	CheckRetry retryablehttp.CheckRetry
 
 	// Limiter is the rate limiter used by the client. If this pointer is nil,
@@ -155,7 +170,6 @@ func DefaultConfig() (*Config, error) {
 		MinVersion: tls.VersionTLS12,
 	}
 
-	config.Backoff = retryablehttp.LinearJitterBackoff
 	config.MaxRetries = 2
 	config.Headers = make(http.Header)
 
@@ -467,6 +481,25 @@ func (c *Client) SetMaxRetries(retries int) {
 	c.config.MaxRetries = retries
 }
 
+// SetRetryWait sets the bounds of the wait between retries for future
+// requests.
+func (c *Client) SetRetryWait(min, max time.Duration) {
+	c.modifyLock.Lock()
+	defer c.modifyLock.Unlock()
+
+	c.config.RetryWaitMin = min
+	c.config.RetryWaitMax = max
+}
+
+// SetRetryAfterMax sets the longest wait honored from the Retry-After header
+// of a response for future requests.
+func (c *Client) SetRetryAfterMax(max time.Duration) {
+	c.modifyLock.Lock()
+	defer c.modifyLock.Unlock()
+
+	c.config.RetryAfterMax = max
+}
+
 // SetCheckRetry sets the CheckRetry function to be used for future requests.
 func (c *Client) SetCheckRetry(checkRetry retryablehttp.CheckRetry) {
 	c.modifyLock.Lock()
@@ -556,6 +589,9 @@ func (c *Client) Clone() *Client {
 		HttpClient:         config.HttpClient,
 		Headers:            make(http.Header),
 		MaxRetries:         config.MaxRetries,
+		RetryWaitMin:       config.RetryWaitMin,
+		RetryWaitMax:       config.RetryWaitMax,
+		RetryAfterMax:      config.RetryAfterMax,
 		Timeout:            config.Timeout,
 		Backoff:            config.Backoff,
 		CheckRetry:         config.CheckRetry,
@@ -689,6 +725,9 @@ func (c *Client) Do(r *retryablehttp.Request) (*Response, error) {
 	c.modifyLock.RLock()
 	limiter := c.config.Limiter
 	maxRetries := c.config.MaxRetries
+	retryWaitMin := c.config.RetryWaitMin
+	retryWaitMax := c.config.RetryWaitMax
+	retryAfterMax := c.config.RetryAfterMax
 	checkRetry := c.config.CheckRetry
 	backoff := c.config.Backoff
 	httpClient := c.config.HttpClient
@@ -717,7 +756,7 @@ func (c *Client) Do(r *retryablehttp.Request) (*Response, error) {
 		return nil, LastOutputStringError
 	}
 
-	if timeout != 0 {
+	if _, ok := ctx.Deadline(); !ok && timeout != 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, timeout)
 		// This dance is just to ignore vet warnings; we don't want to cancel
@@ -727,7 +766,16 @@ func (c *Client) Do(r *retryablehttp.Request) (*Response, error) {
 	r.Request = r.Request.WithContext(ctx)
 
 	if backoff == nil {
-		backoff = retryablehttp.LinearJitterBackoff
+		if retryAfterMax == 0 {
+			retryAfterMax = DefaultRetryAfterMax
+		}
+		backoff = NewRetryAfterBackoff(retryAfterMax)
+	}
+	if retryWaitMin == 0 {
+		retryWaitMin = DefaultRetryWaitMin
+	}
+	if retryWaitMax == 0 {
+		retryWaitMax = DefaultRetryWaitMax
 	}
 
 	if recoveryKmsWrapper != nil {
@@ -752,14 +800,14 @@ func (c *Client) Do(r *retryablehttp.Request) (*Response, error) {
 				}
 				resp.Request.Header.Set("authorization", "Bearer "+token)
 			}
-			return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+			return DefaultRetryPolicy(ctx, resp, err)
 		}
 	}
 
 	client := &retryablehttp.Client{
 		HTTPClient:   httpClient,
-		RetryWaitMin: 1000 * time.Millisecond,
-		RetryWaitMax: 1500 * time.Millisecond,
+		RetryWaitMin: retryWaitMin,
+		RetryWaitMax: retryWaitMax,
 		RetryMax:     maxRetries,
 		Backoff:      backoff,
 		CheckRetry:   checkRetry,
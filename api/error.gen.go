@@ -4,10 +4,13 @@ package api
 import "bytes"
 
 type Error struct {
-	Kind    string        `json:"kind,omitempty"`
-	Op      string        `json:"op,omitempty"`
-	Message string        `json:"message,omitempty"`
-	Details *ErrorDetails `json:"details,omitempty"`
+	Kind      string        `json:"kind,omitempty"`
+	Op        string        `json:"op,omitempty"`
+	Message   string        `json:"message,omitempty"`
+	Details   *ErrorDetails `json:"details,omitempty"`
+	Code      string        `json:"code,omitempty"`
+	Retryable bool          `json:"retryable,omitempty"`
+	RequestId string        `json:"request_id,omitempty"`
 
 	response *Response
 }
@@ -24,6 +24,7 @@ type Group struct {
 	Version     uint32            `json:"version,omitempty"`
 	MemberIds   []string          `json:"member_ids,omitempty"`
 	Members     []*Member         `json:"members,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
 
 	response *api.Response
 }
@@ -0,0 +1,84 @@
+package hostcatalogs
+
+import (
+	"context"
+	"fmt"
+)
+
+// The formats of the inventory files ImportHosts accepts.
+const (
+	ImportFormatKnownHosts = "known_hosts"
+	ImportFormatSshConfig  = "ssh_config"
+	ImportFormatAnsible    = "ansible_inventory"
+)
+
+// ImportHostsResult is the hosts and host sets created by ImportHosts, or
+// that would be created in a dry run.
+type ImportHostsResult struct {
+	HostCatalogId string             `json:"host_catalog_id,omitempty"`
+	DryRun        bool               `json:"dry_run,omitempty"`
+	Hosts         []*ImportedHost    `json:"hosts,omitempty"`
+	HostSets      []*ImportedHostSet `json:"host_sets,omitempty"`
+}
+
+// ImportedHost is a host of an imported file. Exists is true if the catalog
+// already had a host with its name, which is left unchanged.
+type ImportedHost struct {
+	Id      string `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address,omitempty"`
+	Exists  bool   `json:"exists,omitempty"`
+}
+
+// ImportedHostSet is a host set of an imported file. AddedHosts are the names
+// of the hosts added to it.
+type ImportedHostSet struct {
+	Id         string   `json:"id,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Exists     bool     `json:"exists,omitempty"`
+	AddedHosts []string `json:"added_hosts,omitempty"`
+}
+
+// ImportHosts creates the hosts and host sets of the content of an inventory
+// file in the format in a static host catalog. If hostSetName is not empty
+// all hosts of the file are also added to a host set with that name. With
+// dryRun the changes are returned without being made.
+func (c *Client) ImportHosts(ctx context.Context, hostCatalogId, format, content, hostSetName string, dryRun bool, opt ...Option) (*ImportHostsResult, error) {
+	if hostCatalogId == "" {
+		return nil, fmt.Errorf("empty hostCatalogId value passed into ImportHosts request")
+	}
+	if c.client == nil {
+		return nil, fmt.Errorf("nil client in ImportHosts request")
+	}
+
+	_, apiOpts := getOpts(opt...)
+
+	reqBody := map[string]interface{}{
+		"format":  format,
+		"content": content,
+		"dry_run": dryRun,
+	}
+	if hostSetName != "" {
+		reqBody["host_set_name"] = hostSetName
+	}
+
+	req, err := c.client.NewRequest(ctx, "POST", fmt.Sprintf("host-catalogs/%s:import-hosts", hostCatalogId), reqBody, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating ImportHosts request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during ImportHosts call: %w", err)
+	}
+
+	target := new(ImportHostsResult)
+	apiErr, err := resp.Decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding ImportHosts response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return target, nil
+}
@@ -25,6 +25,7 @@ type Host struct {
 	Type          string                 `json:"type,omitempty"`
 	HostSetIds    []string               `json:"host_set_ids,omitempty"`
 	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	Annotations   map[string]string      `json:"annotations,omitempty"`
 
 	response *api.Response
 }
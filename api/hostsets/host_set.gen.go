@@ -25,6 +25,7 @@ type HostSet struct {
 	Type          string                 `json:"type,omitempty"`
 	HostIds       []string               `json:"host_ids,omitempty"`
 	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	Annotations   map[string]string      `json:"annotations,omitempty"`
 
 	response *api.Response
 }
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// Default bounds of the wait between retries, used if the client config
+// leaves them unset.
+const (
+	DefaultRetryWaitMin = 1000 * time.Millisecond
+	DefaultRetryWaitMax = 1500 * time.Millisecond
+)
+
+// DefaultRetryAfterMax is the longest wait a Retry-After header can ask for,
+// used if the client config leaves it unset. Longer waits are cut to it, so a
+// misbehaving server or proxy can't stall the client for hours.
+const DefaultRetryAfterMax = 30 * time.Second
+
+// DefaultRetryPolicy is the CheckRetry function used if the client config
+// sets none. On top of the connection errors and 5xx responses retried by
+// retryablehttp's default policy, it retries 429 (Too Many Requests)
+// responses from rate limited controllers.
+func DefaultRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	retry, checkErr := retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	if retry || checkErr != nil || ctx.Err() != nil {
+		return retry, checkErr
+	}
+	return resp != nil && resp.StatusCode == http.StatusTooManyRequests, nil
+}
+
+// RetryAfterBackoff waits as long as the Retry-After header of a 429 or 503
+// response asks for, up to DefaultRetryAfterMax, and otherwise backs off
+// linearly with jitter between min and max like
+// retryablehttp.LinearJitterBackoff.
+func RetryAfterBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	return NewRetryAfterBackoff(DefaultRetryAfterMax)(min, max, attemptNum, resp)
+}
+
+// NewRetryAfterBackoff returns a Backoff function like RetryAfterBackoff
+// which waits at most limit for a Retry-After header. It is the Backoff
+// function used if the client config sets none, limited by RetryAfterMax.
+func NewRetryAfterBackoff(limit time.Duration) retryablehttp.Backoff {
+	return func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		if d, ok := retryAfter(resp); ok {
+			if d > limit {
+				return limit
+			}
+			return d
+		}
+		return retryablehttp.LinearJitterBackoff(min, max, attemptNum, resp)
+	}
+}
+
+// retryAfter returns the wait the Retry-After header of a 429 or 503
+// response asks for, given either in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseUint(v, 10, 32); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryAfterBackoff(t *testing.T) {
+	resp := func(code int, retryAfter string) *http.Response {
+		r := &http.Response{StatusCode: code, Header: make(http.Header)}
+		if retryAfter != "" {
+			r.Header.Set("Retry-After", retryAfter)
+		}
+		return r
+	}
+	tests := []struct {
+		name string
+		resp *http.Response
+		want time.Duration
+	}{
+		{"seconds", resp(http.StatusTooManyRequests, "7"), 7 * time.Second},
+		{"unavailable", resp(http.StatusServiceUnavailable, "2"), 2 * time.Second},
+		{"date-in-past", resp(http.StatusTooManyRequests, "Mon, 02 Jan 2006 15:04:05 GMT"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RetryAfterBackoff(time.Millisecond, 2*time.Millisecond, 1, tt.resp))
+		})
+	}
+	t.Run("date", func(t *testing.T) {
+		got := RetryAfterBackoff(time.Millisecond, 2*time.Millisecond, 1,
+			resp(http.StatusTooManyRequests, time.Now().Add(20*time.Second).UTC().Format(http.TimeFormat)))
+		assert.True(t, got > 10*time.Second && got <= 20*time.Second, "unexpected wait %s", got)
+	})
+	t.Run("ignored", func(t *testing.T) {
+		for _, r := range []*http.Response{
+			nil,
+			resp(http.StatusInternalServerError, "60"),
+			resp(http.StatusTooManyRequests, ""),
+			resp(http.StatusTooManyRequests, "soon"),
+		} {
+			// LinearJitterBackoff counts attempts from 1, so the first retry
+			// waits between 2*min and 2*max
+			got := RetryAfterBackoff(time.Millisecond, 2*time.Millisecond, 1, r)
+			assert.True(t, got <= 4*time.Millisecond, "unexpected wait %s", got)
+		}
+	})
+	t.Run("limited", func(t *testing.T) {
+		assert.Equal(t, DefaultRetryAfterMax, RetryAfterBackoff(time.Millisecond, 2*time.Millisecond, 1,
+			resp(http.StatusServiceUnavailable, "36000")))
+		backoff := NewRetryAfterBackoff(5 * time.Second)
+		assert.Equal(t, 5*time.Second, backoff(time.Millisecond, 2*time.Millisecond, 1,
+			resp(http.StatusTooManyRequests, time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))))
+		assert.Equal(t, 2*time.Second, backoff(time.Millisecond, 2*time.Millisecond, 1,
+			resp(http.StatusTooManyRequests, "2")))
+	})
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	for code, want := range map[int]bool{
+		http.StatusOK:                  false,
+		http.StatusNotFound:            false,
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+		http.StatusNotImplemented:      false,
+		http.StatusServiceUnavailable:  true,
+	} {
+		retry, err := DefaultRetryPolicy(ctx, &http.Response{StatusCode: code}, nil)
+		assert.NoError(err)
+		assert.Equal(want, retry, "status %d", code)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	retry, err := DefaultRetryPolicy(cancelled, &http.Response{StatusCode: http.StatusTooManyRequests}, nil)
+	assert.Error(err)
+	assert.False(retry)
+}
+
+func TestClient_RetryTooManyRequests(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(nil)
+	require.NoError(err)
+	require.NoError(client.SetAddr(srv.URL))
+	client.SetRetryWait(time.Millisecond, 2*time.Millisecond)
+
+	req, err := client.NewRequest(context.Background(), "GET", "scopes", nil)
+	require.NoError(err)
+	resp, err := client.Do(req)
+	require.NoError(err)
+	assert.Equal(http.StatusOK, resp.HttpResponse().StatusCode)
+	assert.Equal(2, calls)
+}
+
+func TestClient_RetryAfterMax(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(nil)
+	require.NoError(err)
+	require.NoError(client.SetAddr(srv.URL))
+	client.SetRetryAfterMax(time.Millisecond)
+
+	req, err := client.NewRequest(context.Background(), "GET", "scopes", nil)
+	require.NoError(err)
+	start := time.Now()
+	resp, err := client.Do(req)
+	require.NoError(err)
+	assert.Equal(http.StatusOK, resp.HttpResponse().StatusCode)
+	assert.Equal(2, calls)
+	assert.Less(int64(time.Since(start)), int64(5*time.Second))
+}
+
+func TestClient_ContextDeadlineOverridesTimeout(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(nil)
+	require.NoError(err)
+	require.NoError(client.SetAddr(srv.URL))
+	client.SetMaxRetries(0)
+	client.SetClientTimeout(10 * time.Millisecond)
+
+	req, err := client.NewRequest(context.Background(), "GET", "scopes", nil)
+	require.NoError(err)
+	_, err = client.Do(req)
+	assert.Error(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err = client.NewRequest(ctx, "GET", "scopes", nil)
+	require.NoError(err)
+	resp, err := client.Do(req)
+	require.NoError(err)
+	assert.Equal(http.StatusOK, resp.HttpResponse().StatusCode)
+}
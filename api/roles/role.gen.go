@@ -27,6 +27,7 @@ type Role struct {
 	Principals   []*Principal      `json:"principals,omitempty"`
 	GrantStrings []string          `json:"grant_strings,omitempty"`
 	Grants       []*Grant          `json:"grants,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
 
 	response *api.Response
 }
@@ -0,0 +1,82 @@
+package sessions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/boundary/api"
+)
+
+// ConnectionStats summarizes the connections of a session. Byte totals only
+// include closed connections, since workers report them when a connection
+// closes.
+type ConnectionStats struct {
+	SessionId             string `json:"session_id,omitempty"`
+	ActiveConnectionCount uint32 `json:"active_connection_count,omitempty"`
+	TotalConnectionCount  uint32 `json:"total_connection_count,omitempty"`
+	BytesUp               uint64 `json:"bytes_up,omitempty,string"`
+	BytesDown             uint64 `json:"bytes_down,omitempty,string"`
+}
+
+type ConnectionStatsListResult struct {
+	Items    []*ConnectionStats
+	response *api.Response
+}
+
+func (n ConnectionStatsListResult) GetItems() interface{} {
+	return n.Items
+}
+
+func (n ConnectionStatsListResult) GetResponseBody() *bytes.Buffer {
+	return n.response.Body
+}
+
+func (n ConnectionStatsListResult) GetResponseMap() map[string]interface{} {
+	return n.response.Map
+}
+
+// ListConnectionStats returns the connection stats of the sessions in the
+// project scope.
+func (c *Client) ListConnectionStats(ctx context.Context, scopeId string, opt ...Option) (*ConnectionStatsListResult, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("empty scopeId value passed into ListConnectionStats request")
+	}
+	if c.client == nil {
+		return nil, errors.New("nil client")
+	}
+
+	opts, apiOpts := getOpts(opt...)
+	opts.queryMap["scope_id"] = scopeId
+
+	req, err := c.client.NewRequest(ctx, "GET", "sessions:connection-stats", nil, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating ListConnectionStats request: %w", err)
+	}
+
+	if len(opts.queryMap) > 0 {
+		q := url.Values{}
+		for k, v := range opts.queryMap {
+			q.Add(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during ListConnectionStats call: %w", err)
+	}
+
+	target := new(ConnectionStatsListResult)
+	apiErr, err := resp.Decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding ListConnectionStats response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.response = resp
+	return target, nil
+}
@@ -0,0 +1,243 @@
+package sessions
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// The types of the events of a session watch.
+const (
+	// SessionCreated is a session which was not seen before
+	SessionCreated = "created"
+	// SessionUpdated is a session whose version changed, e.g. because its
+	// status changed
+	SessionUpdated = "updated"
+	// SessionRemoved is a session which is no longer listed, e.g. because it
+	// was deleted; only its Id is set
+	SessionRemoved = "removed"
+	// SessionWatchError is a failure to list the sessions; the watch goes on,
+	// waiting longer between attempts until listing succeeds again
+	SessionWatchError = "error"
+)
+
+const (
+	defaultWatchInterval = 2 * time.Second
+	maxWatchRetryWait    = time.Minute
+)
+
+// SessionEvent is a change of the sessions of a scope seen by Watch.
+type SessionEvent struct {
+	Type    string
+	Time    time.Time
+	Session *Session
+	// Err is only set for SessionWatchError events
+	Err error
+	// ResumeToken can be passed in the WatchConfig of a later watch to resume
+	// it after this event, without the changes reported up to this point
+	// being reported again
+	ResumeToken string
+}
+
+// WatchConfig configures Watch.
+type WatchConfig struct {
+	// Interval is how often the sessions are listed. Defaults to 2 seconds.
+	Interval time.Duration
+	// ResumeToken is the ResumeToken of the last event of a previous watch
+	// of the scope. Only sessions updated since that event are reported, and
+	// sessions removed in between are not; if not set all sessions are
+	// reported as created first.
+	ResumeToken string
+}
+
+// watchCursor is the position of a watch in the sessions ordered by update
+// time and id, which is what resume tokens encode. A resumed watch reports
+// the sessions after it.
+type watchCursor struct {
+	UpdateTime time.Time `json:"t"`
+	Id         string    `json:"id"`
+}
+
+func (c watchCursor) token() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (c watchCursor) isZero() bool {
+	return c.UpdateTime.IsZero() && c.Id == ""
+}
+
+// before reports whether the session comes after the cursor.
+func (c watchCursor) before(s *Session) bool {
+	if s.UpdatedTime.Equal(c.UpdateTime) {
+		return s.Id > c.Id
+	}
+	return s.UpdatedTime.After(c.UpdateTime)
+}
+
+func cursorOf(s *Session) watchCursor {
+	return watchCursor{UpdateTime: s.UpdatedTime, Id: s.Id}
+}
+
+// watchState is what a watch knows of the sessions: the cursor of the last
+// change it reported, and the version of each session of the last listing.
+// Versions is nil until the first listing of a watch.
+type watchState struct {
+	cursor   watchCursor
+	versions map[string]uint32
+}
+
+func parseResumeToken(token string) (watchState, error) {
+	var c watchCursor
+	if token == "" {
+		return watchState{}, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return watchState{}, errors.New("invalid resume token")
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return watchState{}, errors.New("invalid resume token")
+	}
+	return watchState{cursor: c}, nil
+}
+
+// Watch lists the sessions of the scope periodically and sends what changed
+// on the returned channel, until ctx is done when the channel is closed.
+// Failures to list the sessions are sent as SessionWatchError events rather
+// than ending the watch. The controller has no streaming endpoint, so changes
+// between two listings are only seen as their combined result.
+func (c *Client) Watch(ctx context.Context, scopeId string, conf *WatchConfig, opt ...Option) (<-chan *SessionEvent, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("empty scopeId value passed into Watch request")
+	}
+	if c.client == nil {
+		return nil, errors.New("nil client")
+	}
+	if conf == nil {
+		conf = new(WatchConfig)
+	}
+	interval := conf.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	known, err := parseResumeToken(conf.ResumeToken)
+	if err != nil {
+		return nil, err
+	}
+	token := known.cursor.token()
+
+	events := make(chan *SessionEvent)
+	go func() {
+		defer close(events)
+		send := func(e *SessionEvent) bool {
+			select {
+			case events <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		wait := interval
+		for {
+			result, err := c.List(ctx, scopeId, opt...)
+			now := time.Now()
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil:
+				if !send(&SessionEvent{Type: SessionWatchError, Time: now, Err: err, ResumeToken: token}) {
+					return
+				}
+				if wait *= 2; wait > maxWatchRetryWait {
+					wait = maxWatchRetryWait
+				}
+			default:
+				wait = interval
+				var changes []*SessionEvent
+				known, changes = diffSessions(known, result.Items, now)
+				token = known.cursor.token()
+				for _, e := range changes {
+					if !send(e) {
+						return
+					}
+				}
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+	return events, nil
+}
+
+// diffSessions returns the state of the listed sessions and the events
+// turning the known sessions into them. Created and updated sessions are
+// reported in the order of their cursors, so the resume token of each covers
+// the events before it and itself. Removed sessions are reported last. On the
+// first listing of a watch the sessions after the cursor of the resume token
+// are reported, as created if they were created after it.
+func diffSessions(known watchState, listed []*Session, now time.Time) (watchState, []*SessionEvent) {
+	state := watchState{cursor: known.cursor, versions: make(map[string]uint32, len(listed))}
+	var events []*SessionEvent
+
+	sorted := append([]*Session(nil), listed...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].UpdatedTime.Equal(sorted[j].UpdatedTime) {
+			return sorted[i].Id < sorted[j].Id
+		}
+		return sorted[i].UpdatedTime.Before(sorted[j].UpdatedTime)
+	})
+	for _, s := range sorted {
+		state.versions[s.Id] = s.Version
+		var typ string
+		if known.versions == nil {
+			switch {
+			case !known.cursor.before(s):
+				continue
+			case known.cursor.isZero() || s.CreatedTime.After(known.cursor.UpdateTime):
+				typ = SessionCreated
+			default:
+				typ = SessionUpdated
+			}
+		} else {
+			v, ok := known.versions[s.Id]
+			switch {
+			case !ok:
+				typ = SessionCreated
+			case v != s.Version:
+				typ = SessionUpdated
+			default:
+				continue
+			}
+		}
+		if state.cursor.before(s) {
+			state.cursor = cursorOf(s)
+		}
+		events = append(events, &SessionEvent{Type: typ, Time: now, Session: s, ResumeToken: state.cursor.token()})
+	}
+
+	var removed []string
+	for id := range known.versions {
+		if _, ok := state.versions[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	if len(removed) == 0 {
+		return state, events
+	}
+	sort.Strings(removed)
+	token := state.cursor.token()
+	for _, id := range removed {
+		events = append(events, &SessionEvent{Type: SessionRemoved, Time: now, Session: &Session{Id: id}, ResumeToken: token})
+	}
+	return state, events
+}
@@ -0,0 +1,107 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSessions(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	now := time.Now()
+	s1 := &Session{Id: "s_1", Version: 1, CreatedTime: now.Add(-time.Minute), UpdatedTime: now.Add(-time.Minute)}
+	s2 := &Session{Id: "s_2", Version: 1, CreatedTime: now, UpdatedTime: now}
+
+	known, events := diffSessions(watchState{}, []*Session{s2, s1}, now)
+	require.Len(events, 2)
+	assert.Equal(SessionCreated, events[0].Type)
+	assert.Equal("s_1", events[0].Session.Id)
+	assert.Equal(SessionCreated, events[1].Type)
+	assert.Equal("s_2", events[1].Session.Id)
+
+	// Resuming after the first event only reports the second one again
+	resumed, err := parseResumeToken(events[0].ResumeToken)
+	require.NoError(err)
+	_, again := diffSessions(resumed, []*Session{s1, s2}, now)
+	require.Len(again, 1)
+	assert.Equal(SessionCreated, again[0].Type)
+	assert.Equal("s_2", again[0].Session.Id)
+
+	s1 = &Session{Id: "s_1", Version: 2, Status: "active", CreatedTime: s1.CreatedTime, UpdatedTime: now.Add(time.Second)}
+	known, events = diffSessions(known, []*Session{s1}, now)
+	require.Len(events, 2)
+	assert.Equal(SessionUpdated, events[0].Type)
+	assert.Equal("active", events[0].Session.Status)
+	assert.Equal(SessionRemoved, events[1].Type)
+	assert.Equal("s_2", events[1].Session.Id)
+	assert.Equal(events[0].ResumeToken, events[1].ResumeToken)
+
+	// A session updated after the resume token of a created one is reported
+	// as updated
+	resumed, err = parseResumeToken(again[0].ResumeToken)
+	require.NoError(err)
+	_, again = diffSessions(resumed, []*Session{s1}, now)
+	require.Len(again, 1)
+	assert.Equal(SessionUpdated, again[0].Type)
+	assert.Equal("s_1", again[0].Session.Id)
+
+	// Listing the same sessions again reports nothing
+	_, events = diffSessions(known, []*Session{s1}, now)
+	assert.Empty(events)
+
+	_, err = parseResumeToken("not a token")
+	assert.Error(err)
+}
+
+func TestClient_Watch(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	var mu sync.Mutex
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		if n == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status": 400, "code": "InvalidArgument", "message": "bad"}`))
+			return
+		}
+		items := []*Session{{Id: "s_1", Version: 1}}
+		if n > 2 {
+			items[0].Version = 2
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+	}))
+	defer srv.Close()
+
+	client, err := api.NewClient(nil)
+	require.NoError(err)
+	require.NoError(client.SetAddr(srv.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events, err := NewClient(client).Watch(ctx, "p_1234567890", &WatchConfig{Interval: 10 * time.Millisecond})
+	require.NoError(err)
+
+	e := <-events
+	assert.Equal(SessionCreated, e.Type)
+	e = <-events
+	assert.Equal(SessionWatchError, e.Type)
+	assert.Error(e.Err)
+	e = <-events
+	assert.Equal(SessionUpdated, e.Type)
+	assert.Equal(uint32(2), e.Session.Version)
+
+	cancel()
+	for range events {
+	}
+}
@@ -80,3 +80,38 @@ func (c *Client) AuthorizeSession(ctx context.Context, targetId string, opt ...O
 	sar.responseMap = resp.Map
 	return sar, nil
 }
+
+// BatchGet returns the targets with the ids, in the order of the ids, with a
+// single request. Targets which don't exist or which the caller may not read
+// are omitted.
+func (c *Client) BatchGet(ctx context.Context, ids []string, opt ...Option) (*TargetListResult, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("empty ids passed into BatchGet request")
+	}
+	if c.client == nil {
+		return nil, fmt.Errorf("nil client")
+	}
+
+	_, apiOpts := getOpts(opt...)
+
+	req, err := c.client.NewRequest(ctx, "POST", "targets:batch-get", map[string]interface{}{"ids": ids}, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating BatchGet request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during BatchGet call: %w", err)
+	}
+
+	target := new(TargetListResult)
+	apiErr, err := resp.Decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding BatchGet response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.response = resp
+	return target, nil
+}
@@ -18,4 +18,5 @@ type SessionAuthorization struct {
 	Type               string            `json:"type,omitempty"`
 	AuthorizationToken string            `json:"authorization_token,omitempty"`
 	Endpoint           string            `json:"endpoint,omitempty"`
+	ConnectionMetadata map[string]string `json:"connection_metadata,omitempty"`
 }
@@ -28,6 +28,7 @@ type Target struct {
 	SessionMaxSeconds      uint32                 `json:"session_max_seconds,omitempty"`
 	SessionConnectionLimit int32                  `json:"session_connection_limit,omitempty"`
 	Attributes             map[string]interface{} `json:"attributes,omitempty"`
+	Annotations            map[string]string      `json:"annotations,omitempty"`
 
 	response *api.Response
 }
@@ -2,5 +2,8 @@
 package targets
 
 type WorkerInfo struct {
-	Address string `json:"address,omitempty"`
+	Address         string `json:"address,omitempty"`
+	Region          string `json:"region,omitempty"`
+	Zone            string `json:"zone,omitempty"`
+	ControllerRttMs uint32 `json:"controller_rtt_ms,omitempty"`
 }
@@ -1,7 +1,12 @@
 // Code generated by "make api"; DO NOT EDIT.
 package users
 
+import (
+	"time"
+)
+
 type Account struct {
-	Id      string `json:"id,omitempty"`
-	ScopeId string `json:"scope_id,omitempty"`
+	Id            string    `json:"id,omitempty"`
+	ScopeId       string    `json:"scope_id,omitempty"`
+	LastLoginTime time.Time `json:"last_login_time,omitempty"`
 }
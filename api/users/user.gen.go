@@ -14,16 +14,21 @@ import (
 )
 
 type User struct {
-	Id          string            `json:"id,omitempty"`
-	ScopeId     string            `json:"scope_id,omitempty"`
-	Scope       *scopes.ScopeInfo `json:"scope,omitempty"`
-	Name        string            `json:"name,omitempty"`
-	Description string            `json:"description,omitempty"`
-	CreatedTime time.Time         `json:"created_time,omitempty"`
-	UpdatedTime time.Time         `json:"updated_time,omitempty"`
-	Version     uint32            `json:"version,omitempty"`
-	AccountIds  []string          `json:"account_ids,omitempty"`
-	Accounts    []*Account        `json:"accounts,omitempty"`
+	Id             string            `json:"id,omitempty"`
+	ScopeId        string            `json:"scope_id,omitempty"`
+	Scope          *scopes.ScopeInfo `json:"scope,omitempty"`
+	Name           string            `json:"name,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	CreatedTime    time.Time         `json:"created_time,omitempty"`
+	UpdatedTime    time.Time         `json:"updated_time,omitempty"`
+	Version        uint32            `json:"version,omitempty"`
+	AccountIds     []string          `json:"account_ids,omitempty"`
+	Accounts       []*Account        `json:"accounts,omitempty"`
+	Disabled       bool              `json:"disabled,omitempty"`
+	DisabledReason string            `json:"disabled_reason,omitempty"`
+	DisabledTime   time.Time         `json:"disabled_time,omitempty"`
+	LastLoginTime  time.Time         `json:"last_login_time,omitempty"`
+	Annotations    map[string]string `json:"annotations,omitempty"`
 
 	response *api.Response
 }
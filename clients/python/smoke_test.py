@@ -0,0 +1,44 @@
+"""Checks that the generated Python client can be imported, configured and
+has a method for every operation of the OpenAPI document."""
+
+import inspect
+import json
+import pkgutil
+import re
+import sys
+
+import boundary_client
+import boundary_client.api
+
+
+def method_name(operation_id):
+    name = re.sub(r"(?<!^)(?=[A-Z])", "_", operation_id).lower()
+    return re.sub(r"_+", "_", name)
+
+
+def main(doc_path):
+    with open(doc_path) as f:
+        doc = json.load(f)
+
+    conf = boundary_client.Configuration(host="http://127.0.0.1:9200")
+    client = boundary_client.ApiClient(conf)
+
+    methods = set()
+    for info in pkgutil.iter_modules(boundary_client.api.__path__):
+        module = __import__("boundary_client.api." + info.name, fromlist=["*"])
+        for _, cls in inspect.getmembers(module, inspect.isclass):
+            if cls.__name__.endswith("Api"):
+                cls(client)
+                methods.update(name for name, _ in inspect.getmembers(cls, inspect.isfunction))
+
+    missing = []
+    for item in doc["paths"].values():
+        for op in item.values():
+            if method_name(op["operationId"]) not in methods:
+                missing.append(op["operationId"])
+    if missing:
+        sys.exit("operations missing from the python client: " + ", ".join(sorted(missing)))
+
+
+if __name__ == "__main__":
+    main(sys.argv[1])
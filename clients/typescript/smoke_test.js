@@ -0,0 +1,37 @@
+// Checks that the generated TypeScript client can be loaded, configured and
+// has a method for every operation of the OpenAPI document.
+
+const fs = require("fs");
+const path = require("path");
+
+const [clientDir, docPath] = process.argv.slice(2);
+const client = require(path.resolve(clientDir));
+const doc = JSON.parse(fs.readFileSync(docPath, "utf8"));
+
+const conf = new client.Configuration({ basePath: "http://127.0.0.1:9200" });
+
+const methods = new Set();
+for (const [name, cls] of Object.entries(client)) {
+  if (typeof cls !== "function" || !name.endsWith("Api")) {
+    continue;
+  }
+  new cls(conf);
+  for (const method of Object.getOwnPropertyNames(cls.prototype)) {
+    methods.add(method);
+  }
+}
+
+const missing = [];
+for (const item of Object.values(doc.paths)) {
+  for (const op of Object.values(item)) {
+    const id = op.operationId.replace(/_/g, "");
+    const method = id.charAt(0).toLowerCase() + id.slice(1);
+    if (!methods.has(method)) {
+      missing.push(op.operationId);
+    }
+  }
+}
+if (missing.length > 0) {
+  console.error(`operations missing from the typescript client: ${missing.sort().join(", ")}`);
+  process.exit(1);
+}
@@ -12,10 +12,52 @@ import "time"
 const (
 	TcpProxyV1     = "boundary-tcp-proxy-v1"
 	ServiceTokenV1 = "s1"
+
+	// TcpProxyResumableV1 is the subprotocol of TCP proxied connections
+	// which the client can resume after losing its connection to the worker
+	TcpProxyResumableV1 = "boundary-tcp-proxy-resumable-v1"
+
+	// ConnectionRateLimitMetadataKey is the gRPC header metadata key
+	// controllers send the connections each session of the target of a session
+	// may open per minute in when workers look it up
+	ConnectionRateLimitMetadataKey = "boundary-connections-per-minute"
+
+	// PeerIdentityModeMetadataKey, PeerUserNameMetadataKey,
+	// PeerInjectedUserNameMetadataKey and PeerCredentialCheckoutMetadataKey
+	// are the gRPC header metadata keys controllers send how to pass the
+	// identity of the user of a session to the endpoint, the name of the user,
+	// the user name protocol handlers inject and the id of the credential
+	// checkout of the session in when workers look it up
+	PeerIdentityModeMetadataKey       = "boundary-peer-identity-mode"
+	PeerUserNameMetadataKey           = "boundary-peer-user-name-bin"
+	PeerInjectedUserNameMetadataKey   = "boundary-peer-injected-user-name-bin"
+	PeerCredentialCheckoutMetadataKey = "boundary-peer-credential-checkout-id"
+
+	// WorkerBytesPerSecondMetadataKey and WorkerTagsMetadataKey are the gRPC
+	// metadata keys workers send their throughput and their tags, as
+	// key=value values, in with their status
+	WorkerBytesPerSecondMetadataKey = "boundary-worker-bytes-per-second"
+	WorkerTagsMetadataKey           = "boundary-worker-tags"
+
+	// WorkerRegionMetadataKey, WorkerZoneMetadataKey and
+	// WorkerControllerRttMetadataKey are the gRPC metadata keys workers send
+	// their region, their zone and the round trip time in milliseconds of
+	// their previous status report in with their status
+	WorkerRegionMetadataKey        = "boundary-worker-region"
+	WorkerZoneMetadataKey          = "boundary-worker-zone"
+	WorkerControllerRttMetadataKey = "boundary-worker-controller-rtt-ms"
+
+	// ConnectionChecksMetadataKey is the gRPC header metadata key controllers
+	// send the connection checks a worker must perform in, in response to its
+	// status. ConnectionCheckResultsMetadataKey is the metadata key workers
+	// send the results of the checks in with a later status. Both are JSON.
+	ConnectionChecksMetadataKey       = "boundary-connection-checks-bin"
+	ConnectionCheckResultsMetadataKey = "boundary-connection-check-results-bin"
 )
 
 type ContextMaxRequestSizeType int
 type ContextOriginalRequestPathType int
+type ContextRequestIdType int
 
 var (
 	// DefaultMaxRequestDuration is the amount of time we'll wait for a request
@@ -32,6 +74,10 @@ var (
 	// about clashing string identifiers
 	ContextOriginalRequestPathTypeKey ContextOriginalRequestPathType
 
+	// ContextRequestIdTypeKey is a value to keep linters from complaining
+	// about clashing string identifiers
+	ContextRequestIdTypeKey ContextRequestIdType
+
 	// RecoveryTokenValidityPeriod is exported so we can modify it in tests if
 	// we want
 	RecoveryTokenValidityPeriod = 5 * time.Minute
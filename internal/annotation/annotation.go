@@ -0,0 +1,39 @@
+// Package annotation stores annotations, custom string metadata which
+// external systems can attach to any resource, e.g. to record its owner or
+// cost center without using its description.
+package annotation
+
+import (
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+)
+
+const (
+	defaultAnnotationTableName = "annotation"
+
+	// MaxKeyLength is the maximum length of an annotation key.
+	MaxKeyLength = 128
+	// MaxValueLength is the maximum length of an annotation value.
+	MaxValueLength = 1024
+	// MaxAnnotations is the maximum number of annotations of a resource.
+	MaxAnnotations = 64
+
+	// FieldName is the name of the annotations field in update masks. A mask
+	// path of FieldName replaces all annotations of the resource and a mask
+	// path of FieldName followed by a dot and a key sets or removes a single
+	// annotation.
+	FieldName = "annotations"
+)
+
+// An Annotation is a key and value attached to a resource.
+type Annotation struct {
+	ResourceId string `gorm:"primary_key"`
+	Key        string `gorm:"primary_key"`
+	Value      string
+	CreateTime *timestamp.Timestamp `gorm:"default:current_timestamp"`
+	UpdateTime *timestamp.Timestamp `gorm:"default:current_timestamp"`
+}
+
+// TableName returns the table name for the annotation.
+func (a *Annotation) TableName() string {
+	return defaultAnnotationTableName
+}
@@ -0,0 +1,188 @@
+package annotation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/types/resource"
+)
+
+// A Repository stores and retrieves annotations.
+type Repository struct {
+	reader db.Reader
+	writer db.Writer
+}
+
+// NewRepository creates a new Repository.
+func NewRepository(r db.Reader, w db.Writer) (*Repository, error) {
+	switch {
+	case r == nil:
+		return nil, fmt.Errorf("db.Reader: annotation: %w", errors.ErrInvalidParameter)
+	case w == nil:
+		return nil, fmt.Errorf("db.Writer: annotation: %w", errors.ErrInvalidParameter)
+	}
+	return &Repository{
+		reader: r,
+		writer: w,
+	}, nil
+}
+
+// LookupAnnotations returns the annotations of the resource. A resource
+// without annotations returns an empty map.
+func (r *Repository) LookupAnnotations(ctx context.Context, resourceId string) (map[string]string, error) {
+	if resourceId == "" {
+		return nil, fmt.Errorf("lookup annotations: missing resource id: %w", errors.ErrInvalidParameter)
+	}
+	ret, err := lookupAnnotations(ctx, r.reader, resourceId)
+	if err != nil {
+		return nil, fmt.Errorf("lookup annotations: %w", err)
+	}
+	return ret, nil
+}
+
+// ListAnnotations returns the annotations of the resources by resource id.
+// Resources without annotations are not in the returned map.
+func (r *Repository) ListAnnotations(ctx context.Context, resourceIds []string) (map[string]map[string]string, error) {
+	ret := make(map[string]map[string]string)
+	if len(resourceIds) == 0 {
+		return ret, nil
+	}
+	var found []*Annotation
+	if err := r.reader.SearchWhere(ctx, &found, "resource_id in (?)", []interface{}{resourceIds}, db.WithLimit(-1)); err != nil {
+		return nil, fmt.Errorf("list annotations: %w", err)
+	}
+	for _, a := range found {
+		if ret[a.ResourceId] == nil {
+			ret[a.ResourceId] = make(map[string]string)
+		}
+		ret[a.ResourceId][a.Key] = a.Value
+	}
+	return ret, nil
+}
+
+// UpdateAnnotations updates the annotations of the resource of type resType
+// named by fieldMaskPaths to their values in annotations and returns all
+// annotations of the resource. A path of FieldName replaces all annotations
+// while a path of FieldName followed by a dot and a key sets only that key.
+// Keys named by fieldMaskPaths which are not in annotations are removed.
+// Changing the annotations notifies the commit listeners of an update of the
+// resource.
+func (r *Repository) UpdateAnnotations(ctx context.Context, resType resource.Type, resourceId string, annotations map[string]string, fieldMaskPaths []string) (map[string]string, error) {
+	if resourceId == "" {
+		return nil, fmt.Errorf("update annotations: missing resource id: %w", errors.ErrInvalidParameter)
+	}
+	if len(fieldMaskPaths) == 0 {
+		return nil, fmt.Errorf("update annotations: missing field mask paths: %w", errors.ErrInvalidParameter)
+	}
+	var replaceAll bool
+	var keys []string
+	for _, p := range fieldMaskPaths {
+		switch {
+		case strings.EqualFold(p, FieldName):
+			replaceAll = true
+		case len(p) > len(FieldName)+1 && strings.EqualFold(p[:len(FieldName)+1], FieldName+"."):
+			keys = append(keys, p[len(FieldName)+1:])
+		default:
+			return nil, fmt.Errorf("update annotations: invalid field mask path %q: %w", p, errors.ErrInvalidParameter)
+		}
+	}
+	if replaceAll {
+		keys = keys[:0]
+		for k := range annotations {
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range keys {
+		if err := validateAnnotation(k, annotations[k]); err != nil {
+			return nil, fmt.Errorf("update annotations: %w", err)
+		}
+	}
+
+	var ret map[string]string
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(read db.Reader, w db.Writer) error {
+			current, err := lookupAnnotations(ctx, read, resourceId)
+			if err != nil {
+				return err
+			}
+			updated := make(map[string]string, len(current))
+			if !replaceAll {
+				for k, v := range current {
+					updated[k] = v
+				}
+			}
+			for _, k := range keys {
+				delete(updated, k)
+				if v, ok := annotations[k]; ok {
+					updated[k] = v
+				}
+			}
+			if len(updated) > MaxAnnotations {
+				return fmt.Errorf("more than %d annotations: %w", MaxAnnotations, errors.ErrInvalidParameter)
+			}
+
+			var changed bool
+			for k := range current {
+				if _, ok := updated[k]; ok {
+					continue
+				}
+				if _, err := w.Exec(ctx,
+					"delete from annotation where resource_id = ? and key = ?",
+					[]interface{}{resourceId, k}); err != nil {
+					return fmt.Errorf("unable to delete annotation %q: %w", k, err)
+				}
+				changed = true
+			}
+			for k, v := range updated {
+				if cv, ok := current[k]; ok && cv == v {
+					continue
+				}
+				if _, err := w.Exec(ctx,
+					"insert into annotation (resource_id, key, value) values (?, ?, ?) on conflict (resource_id, key) do update set value = excluded.value",
+					[]interface{}{resourceId, k, v}); err != nil {
+					return fmt.Errorf("unable to set annotation %q: %w", k, err)
+				}
+				changed = true
+			}
+			if changed {
+				w.NotifyOnCommit(ctx, db.Change{ResourceType: resType, Op: db.UpdateOp, Id: resourceId})
+			}
+			ret = updated
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update annotations: %w for %s", err, resourceId)
+	}
+	return ret, nil
+}
+
+func lookupAnnotations(ctx context.Context, r db.Reader, resourceId string) (map[string]string, error) {
+	var found []*Annotation
+	if err := r.SearchWhere(ctx, &found, "resource_id = ?", []interface{}{resourceId}); err != nil {
+		return nil, err
+	}
+	ret := make(map[string]string, len(found))
+	for _, a := range found {
+		ret[a.Key] = a.Value
+	}
+	return ret, nil
+}
+
+func validateAnnotation(key, value string) error {
+	switch {
+	case strings.TrimSpace(key) == "":
+		return fmt.Errorf("empty annotation key: %w", errors.ErrInvalidParameter)
+	case len(key) > MaxKeyLength:
+		return fmt.Errorf("annotation key %q longer than %d: %w", key, MaxKeyLength, errors.ErrInvalidParameter)
+	case len(value) > MaxValueLength:
+		return fmt.Errorf("annotation %q value longer than %d: %w", key, MaxValueLength, errors.ErrInvalidParameter)
+	}
+	return nil
+}
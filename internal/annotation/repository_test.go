@@ -0,0 +1,170 @@
+package annotation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_UpdateAnnotations(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	repo, err := NewRepository(rw, rw)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		current   map[string]string
+		update    map[string]string
+		paths     []string
+		want      map[string]string
+		wantIsErr error
+	}{
+		{
+			name:   "set",
+			update: map[string]string{"owner": "alice", "cost-center": "42"},
+			paths:  []string{"annotations.owner", "annotations.cost-center"},
+			want:   map[string]string{"owner": "alice", "cost-center": "42"},
+		},
+		{
+			name:    "set-only-masked",
+			current: map[string]string{"owner": "alice"},
+			update:  map[string]string{"owner": "bob", "cost-center": "42"},
+			paths:   []string{"annotations.owner"},
+			want:    map[string]string{"owner": "bob"},
+		},
+		{
+			name:    "remove",
+			current: map[string]string{"owner": "alice", "cost-center": "42"},
+			paths:   []string{"annotations.owner"},
+			want:    map[string]string{"cost-center": "42"},
+		},
+		{
+			name:    "replace-all",
+			current: map[string]string{"owner": "alice", "cost-center": "42"},
+			update:  map[string]string{"team": "infra"},
+			paths:   []string{"annotations"},
+			want:    map[string]string{"team": "infra"},
+		},
+		{
+			name:      "invalid-path",
+			update:    map[string]string{"owner": "alice"},
+			paths:     []string{"description"},
+			wantIsErr: errors.ErrInvalidParameter,
+		},
+		{
+			name:      "no-paths",
+			update:    map[string]string{"owner": "alice"},
+			wantIsErr: errors.ErrInvalidParameter,
+		},
+		{
+			name:      "key-too-long",
+			update:    map[string]string{strings.Repeat("k", MaxKeyLength+1): "v"},
+			paths:     []string{"annotations"},
+			wantIsErr: errors.ErrInvalidParameter,
+		},
+		{
+			name:      "value-too-long",
+			update:    map[string]string{"owner": strings.Repeat("v", MaxValueLength+1)},
+			paths:     []string{"annotations.owner"},
+			wantIsErr: errors.ErrInvalidParameter,
+		},
+		{
+			name: "too-many",
+			update: func() map[string]string {
+				m := make(map[string]string, MaxAnnotations+1)
+				for i := 0; i <= MaxAnnotations; i++ {
+					m[strings.Repeat("k", i+1)] = "v"
+				}
+				return m
+			}(),
+			paths:     []string{"annotations"},
+			wantIsErr: errors.ErrInvalidParameter,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			u := iam.TestUser(t, iamRepo, org.PublicId)
+			if len(tt.current) > 0 {
+				_, err := repo.UpdateAnnotations(ctx, resource.User, u.PublicId, tt.current, []string{"annotations"})
+				require.NoError(err)
+			}
+			got, err := repo.UpdateAnnotations(ctx, resource.User, u.PublicId, tt.update, tt.paths)
+			if tt.wantIsErr != nil {
+				require.Error(err)
+				assert.Truef(errors.Is(err, tt.wantIsErr), "unexpected error %s", err.Error())
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tt.want, got)
+
+			found, err := repo.LookupAnnotations(ctx, u.PublicId)
+			require.NoError(err)
+			assert.Equal(tt.want, found)
+		})
+	}
+}
+
+func TestRepository_DeletedWithResource(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	repo, err := NewRepository(rw, rw)
+	require.NoError(err)
+	ctx := context.Background()
+
+	u := iam.TestUser(t, iamRepo, org.PublicId)
+	_, err = repo.UpdateAnnotations(ctx, resource.User, u.PublicId, map[string]string{"owner": "alice"}, []string{"annotations"})
+	require.NoError(err)
+
+	_, err = iamRepo.DeleteUser(ctx, u.PublicId)
+	require.NoError(err)
+	found, err := repo.LookupAnnotations(ctx, u.PublicId)
+	require.NoError(err)
+	assert.Empty(found)
+}
+
+func TestRepository_ListAnnotations(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	repo, err := NewRepository(rw, rw)
+	require.NoError(err)
+	ctx := context.Background()
+
+	annotated := iam.TestUser(t, iamRepo, org.PublicId)
+	plain := iam.TestUser(t, iamRepo, org.PublicId)
+	_, err = repo.UpdateAnnotations(ctx, resource.User, annotated.PublicId, map[string]string{"owner": "alice", "team": "infra"}, []string{"annotations"})
+	require.NoError(err)
+
+	got, err := repo.ListAnnotations(ctx, []string{annotated.PublicId, plain.PublicId})
+	require.NoError(err)
+	assert.Equal(map[string]map[string]string{
+		annotated.PublicId: {"owner": "alice", "team": "infra"},
+	}, got)
+
+	got, err = repo.ListAnnotations(ctx, nil)
+	require.NoError(err)
+	assert.Empty(got)
+}
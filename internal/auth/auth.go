@@ -9,20 +9,26 @@ import (
 	"time"
 
 	"github.com/hashicorp/boundary/globals"
+	"github.com/hashicorp/boundary/internal/authtoken"
+	authStore "github.com/hashicorp/boundary/internal/authtoken/store"
+	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
 	"github.com/hashicorp/boundary/internal/gen/controller/tokens"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/policyhook"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/types/action"
 	"github.com/hashicorp/boundary/internal/types/resource"
 	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/hashicorp/boundary/internal/usage"
 	"github.com/hashicorp/boundary/sdk/recovery"
 	"github.com/hashicorp/go-hclog"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"github.com/kr/pretty"
 	"github.com/mr-tron/base58"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -42,6 +48,19 @@ const (
 	AuthTokenTypeRecoveryKms
 )
 
+// SelfId can be used in place of the id of a user or account in a request to
+// refer to the user or account of the request's auth token.
+const SelfId = "self"
+
+// selfActions are the actions always allowed on the caller's own user or
+// account when it is referred to by SelfId, and on the caller's own sessions,
+// regardless of the caller's grants.
+var selfActions = map[resource.Type][]action.Type{
+	resource.User:    {action.Read},
+	resource.Account: {action.ChangePassword},
+	resource.Session: {action.List, action.ReadSelf, action.CancelSelf},
+}
+
 type key int
 
 var verifierKey key
@@ -58,6 +77,7 @@ type RequestInfo struct {
 	// The following are useful for tests
 	scopeIdOverride      string
 	userIdOverride       string
+	accountIdOverride    string
 	DisableAuthzFailures bool
 	DisableAuthEntirely  bool
 }
@@ -68,6 +88,11 @@ type VerifyResults struct {
 	Error       error
 	Scope       *scopes.ScopeInfo
 
+	// GrantsHash is a stable hash of the grants which applied to the request,
+	// suitable for use in cache keys which must not be shared by callers with
+	// different permissions.
+	GrantsHash string
+
 	// RoundTripValue can be set to allow the function performing authentication
 	// (often accompanied by lookup(s)) to return a result of that lookup to the
 	// calling function. It is opaque to this package.
@@ -86,8 +111,23 @@ type verifier struct {
 	requestInfo     RequestInfo
 	res             *perms.Resource
 	act             action.Type
+	self            bool
 	ctx             context.Context
 	acl             perms.ACL
+
+	// Set once the token has been decrypted and validated, so it is done only
+	// once per request even if LookupSelf is called before Verify
+	tokenDecrypted bool
+	tokenValidated bool
+	authToken      *authtoken.AuthToken
+	attenuation    *authtoken.Attenuation
+
+	// userId is the user verified by the last call to Verify
+	userId string
+
+	// usageCounted is set once the request has been counted in the usage of
+	// the scope it was first verified for
+	usageCounted bool
 }
 
 // NewVerifierContext creates a context that carries a verifier object from the
@@ -101,14 +141,17 @@ func NewVerifierContext(ctx context.Context,
 	serversRepoFn common.ServersRepoFactory,
 	kms *kms.Kms,
 	requestInfo RequestInfo) context.Context {
-	return context.WithValue(ctx, verifierKey, &verifier{
+	v := &verifier{
 		logger:          logger,
 		iamRepoFn:       iamRepoFn,
 		authTokenRepoFn: authTokenRepoFn,
 		serversRepoFn:   serversRepoFn,
 		kms:             kms,
 		requestInfo:     requestInfo,
-	})
+	}
+	// Record the verified user as the actor of the changes made by the request
+	ctx = db.NewOplogActorContext(ctx, func() string { return v.userId })
+	return context.WithValue(ctx, verifierKey, v)
 }
 
 // Verify takes in a context that has expected parameters as values and runs an
@@ -146,11 +189,13 @@ func Verify(ctx context.Context, opt ...Option) (ret VerifyResults) {
 			ret.Scope.Type = scope.Project.String()
 		}
 		ret.UserId = v.requestInfo.userIdOverride
+		v.userId = ret.UserId
 		ret.Error = nil
 		return
 	}
 
 	v.act = opts.withAction
+	v.self = opts.withSelf
 	v.res = &perms.Resource{
 		ScopeId: opts.withScopeId,
 		Id:      opts.withId,
@@ -162,17 +207,22 @@ func Verify(ctx context.Context, opt ...Option) (ret VerifyResults) {
 		v.res.ScopeId = scope.Global.String()
 	}
 
-	if v.requestInfo.EncryptedToken != "" {
+	if v.requestInfo.EncryptedToken != "" && !v.tokenDecrypted {
 		v.decryptToken()
 	}
 
 	var authResults perms.ACLResults
 	var err error
-	authResults, ret.UserId, ret.Scope, v.acl, err = v.performAuthCheck()
+	authResults, ret.UserId, ret.Scope, v.acl, ret.GrantsHash, err = v.performAuthCheck()
 	if err != nil {
 		v.logger.Error("error performing authn/authz check", "error", err)
 		return
 	}
+	v.userId = ret.UserId
+	if !v.usageCounted {
+		v.usageCounted = true
+		usage.FromContext(ctx).CountApiCall(ret.Scope.GetId())
+	}
 
 	ret.AuthTokenId = v.requestInfo.PublicId
 	if !authResults.Allowed {
@@ -189,12 +239,45 @@ func Verify(ctx context.Context, opt ...Option) (ret VerifyResults) {
 			}
 			return
 		}
+	} else if err := v.evaluatePolicy(ctx, ret); err != nil {
+		ret.Error = err
+		return
 	}
 
 	ret.Error = nil
 	return
 }
 
+// evaluatePolicy evaluates the request allowed by the grants of its caller
+// with the policy hook of the context, if any, returning the API error of a
+// denial. Requests with the recovery KMS are not evaluated.
+func (v *verifier) evaluatePolicy(ctx context.Context, ret VerifyResults) error {
+	e := policyhook.FromContext(ctx)
+	if e == nil || v.requestInfo.TokenFormat == AuthTokenTypeRecoveryKms {
+		return nil
+	}
+	reqId, _ := ctx.Value(globals.ContextRequestIdTypeKey).(string)
+	err := e.Evaluate(ctx, &policyhook.Input{
+		RequestId:   reqId,
+		UserId:      ret.UserId,
+		AuthTokenId: ret.AuthTokenId,
+		Resource: policyhook.Resource{
+			Id:      v.res.Id,
+			Type:    v.res.Type.String(),
+			ScopeId: ret.Scope.GetId(),
+			Pin:     v.res.Pin,
+		},
+		Action: v.act.String(),
+		Method: v.requestInfo.Method,
+		Path:   v.requestInfo.Path,
+	})
+	var denied *policyhook.DeniedError
+	if errors.As(err, &denied) {
+		return handlers.ApiErrorWithCodeAndMessage(codes.PermissionDenied, "Forbidden: %s", denied.Message)
+	}
+	return err
+}
+
 // AdditionalVerification is used to perform checks of additional resources for
 // actions that need to touch more than one.
 func (r *VerifyResults) AdditionalVerification(ctx context.Context, opt ...Option) (ret VerifyResults) {
@@ -206,6 +289,7 @@ func (r *VerifyResults) AdditionalVerification(ctx context.Context, opt ...Optio
 	ret.Scope = r.Scope
 	ret.UserId = r.UserId
 	ret.AuthTokenId = r.AuthTokenId
+	ret.GrantsHash = r.GrantsHash
 	ret.v = r.v
 
 	opts := getOpts(opt...)
@@ -290,7 +374,7 @@ func (r *VerifyResults) AdditionalVerification(ctx context.Context, opt ...Optio
 	return
 }
 
-func (v verifier) performAuthCheck() (aclResults perms.ACLResults, userId string, scopeInfo *scopes.ScopeInfo, retAcl perms.ACL, retErr error) {
+func (v verifier) performAuthCheck() (aclResults perms.ACLResults, userId string, scopeInfo *scopes.ScopeInfo, retAcl perms.ACL, grantsHash string, retErr error) {
 	// Ensure we return an error by default if we forget to set this somewhere
 	retErr = errors.New("unknown")
 	// Make the linter happy
@@ -298,6 +382,7 @@ func (v verifier) performAuthCheck() (aclResults perms.ACLResults, userId string
 	scopeInfo = new(scopes.ScopeInfo)
 	userId = "u_anon"
 	var accountId string
+	var attenuation *authtoken.Attenuation
 
 	// Validate the token and fetch the corresponding user ID
 	switch v.requestInfo.TokenFormat {
@@ -310,23 +395,13 @@ func (v verifier) performAuthCheck() (aclResults perms.ACLResults, userId string
 		userId = "u_recovery"
 
 	case AuthTokenTypeBearer, AuthTokenTypeSplitCookie:
-		if v.requestInfo.Token == "" {
-			// This will end up staying as the anonymous user
-			break
-		}
-		tokenRepo, err := v.authTokenRepoFn()
+		at, att, err := v.validateToken()
 		if err != nil {
-			retErr = fmt.Errorf("perform auth check: failed to get authtoken repo: %w", err)
+			retErr = fmt.Errorf("perform auth check: %w", err)
 			return
 		}
-		at, err := tokenRepo.ValidateToken(v.ctx, v.requestInfo.PublicId, v.requestInfo.Token)
-		if err != nil {
-			// Continue as the anonymous user as maybe this token is expired but
-			// we can still perform the action
-			v.logger.Error("perform auth check: error validating token; continuing as anonymous user", "error", err)
-			break
-		}
 		if at != nil {
+			attenuation = att
 			accountId = at.GetAuthAccountId()
 			userId = at.GetIamUserId()
 			if userId == "" {
@@ -377,6 +452,7 @@ func (v verifier) performAuthCheck() (aclResults perms.ACLResults, userId string
 	// At this point we don't need to look up grants since it's automatically allowed
 	if v.requestInfo.TokenFormat == AuthTokenTypeRecoveryKms {
 		aclResults.Allowed = true
+		grantsHash = handlers.GrantsHash([]string{userId})
 		retErr = nil
 		return
 	}
@@ -391,8 +467,16 @@ func (v verifier) performAuthCheck() (aclResults perms.ACLResults, userId string
 		retErr = fmt.Errorf("perform auth check: failed to query for user grants: %w", err)
 		return
 	}
+	// A token derived by an exchange only keeps the grants it was limited to
+	grantPairs, err = attenuation.Filter(grantPairs)
+	if err != nil {
+		retErr = fmt.Errorf("perform auth check: failed to filter grants of derived token: %w", err)
+		return
+	}
 	parsedGrants = make([]perms.Grant, 0, len(grantPairs))
+	hashedGrants := make([]string, 0, len(grantPairs))
 	for _, pair := range grantPairs {
+		hashedGrants = append(hashedGrants, pair.ScopeId+":"+pair.Grant)
 		parsed, err := perms.Parse(
 			pair.ScopeId,
 			pair.Grant,
@@ -408,10 +492,158 @@ func (v verifier) performAuthCheck() (aclResults perms.ACLResults, userId string
 
 	retAcl = perms.NewACL(parsedGrants...)
 	aclResults = retAcl.Allowed(*v.res, v.act)
+	if !aclResults.Allowed && v.self && selfAllowed(*v.res, v.act, userId, accountId) {
+		// Derived tokens may only read, so they can't be used to change the
+		// password of the account or cancel sessions
+		aclResults.Allowed = attenuation == nil || v.act == action.Read || v.act == action.ReadSelf || v.act == action.List
+	}
+	if aclResults.Allowed && (!attenuation.AllowsResource(*v.res) || !attenuation.AllowsAction(*v.res, v.act)) {
+		aclResults.Allowed = false
+	}
+	if attenuation != nil && attenuation.TargetId != "" {
+		hashedGrants = append(hashedGrants, "target:"+attenuation.TargetId)
+	}
+	if attenuation != nil && attenuation.Preset != "" {
+		hashedGrants = append(hashedGrants, "preset:"+attenuation.Preset)
+	}
+	for _, g := range hashedGrants {
+		// Templated grants resolve differently per user, so the same grant
+		// strings do not imply the same permissions
+		if strings.Contains(g, "{{") {
+			hashedGrants = append(hashedGrants, "user:"+userId, "account:"+accountId)
+			break
+		}
+	}
+	grantsHash = handlers.GrantsHash(hashedGrants)
 	retErr = nil
 	return
 }
 
+// validateToken validates the decrypted token of the request and returns the
+// auth token it belongs to, along with its restrictions if it was derived by an
+// exchange. A nil auth token is returned if there is no token or it is not
+// valid, e.g. it has expired, in which case the request continues as the
+// anonymous user. The result is cached in the verifier.
+func (v *verifier) validateToken() (*authtoken.AuthToken, *authtoken.Attenuation, error) {
+	if v.tokenValidated {
+		return v.authToken, v.attenuation, nil
+	}
+	if v.requestInfo.Token == "" {
+		// This will end up staying as the anonymous user
+		v.tokenValidated = true
+		return nil, nil, nil
+	}
+	tokenRepo, err := v.authTokenRepoFn()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get authtoken repo: %w", err)
+	}
+	at, err := tokenRepo.ValidateToken(v.ctx, v.requestInfo.PublicId, v.requestInfo.Token)
+	if err != nil {
+		// Continue as the anonymous user as maybe this token is expired but
+		// we can still perform the action
+		v.logger.Error("validate token: error validating token; continuing as anonymous user", "error", err)
+		at = nil
+	}
+	var att *authtoken.Attenuation
+	if at != nil {
+		// Fail closed, since continuing without the restrictions of a derived
+		// token would grant it more than it was limited to
+		if att, err = tokenRepo.LookupAttenuation(v.ctx, at.GetPublicId()); err != nil {
+			return nil, nil, fmt.Errorf("failed to look up token attenuation: %w", err)
+		}
+	}
+	v.tokenValidated = true
+	v.authToken = at
+	v.attenuation = att
+	return at, att, nil
+}
+
+// selfAllowed returns true if the resource is the user or account of the
+// caller, or one of the caller's sessions, and the action is one of the
+// selfActions for its type.
+func selfAllowed(res perms.Resource, act action.Type, userId, accountId string) bool {
+	switch res.Type {
+	case resource.User:
+		if userId == "" || res.Id != userId {
+			return false
+		}
+	case resource.Account:
+		if accountId == "" || res.Id != accountId {
+			return false
+		}
+	case resource.Session:
+		// Sessions aren't identified by the caller's ids, so the session
+		// handler only passes WithSelf for the caller's own sessions, or when
+		// listing only the caller's own sessions
+		if userId == "" || userId == "u_anon" {
+			return false
+		}
+	default:
+		return false
+	}
+	for _, a := range selfActions[res.Type] {
+		if a == act {
+			return true
+		}
+	}
+	return false
+}
+
+// LookupSelf returns the ids of the user and account of the request's auth
+// token, so that a request using SelfId can be resolved to the ids to pass to
+// Verify along with WithSelf. An unauthenticated error is returned if the
+// request has no valid token which maps to a user.
+func LookupSelf(ctx context.Context) (userId, accountId string, err error) {
+	at, _, err := LookupSelfToken(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return at.GetIamUserId(), at.GetAuthAccountId(), nil
+}
+
+// LookupSelfToken returns the request's auth token, along with its
+// restrictions if it was derived by an exchange. An unauthenticated error is
+// returned if the request has no valid token which maps to a user. The token
+// value is not included in the returned auth token.
+func LookupSelfToken(ctx context.Context) (*authtoken.AuthToken, *authtoken.Attenuation, error) {
+	v, ok := ctx.Value(verifierKey).(*verifier)
+	if !ok {
+		// We don't have a logger yet and this should never happen in any
+		// context we won't catch in tests
+		panic("no verifier information found in context")
+	}
+	if v.requestInfo.DisableAuthEntirely {
+		if v.requestInfo.userIdOverride == "" {
+			return nil, nil, handlers.UnauthenticatedError()
+		}
+		return &authtoken.AuthToken{
+			AuthToken: &authStore.AuthToken{
+				IamUserId:     v.requestInfo.userIdOverride,
+				AuthAccountId: v.requestInfo.accountIdOverride,
+			},
+		}, nil, nil
+	}
+
+	v.ctx = ctx
+	switch v.requestInfo.TokenFormat {
+	case AuthTokenTypeBearer, AuthTokenTypeSplitCookie:
+	default:
+		// Recovery tokens don't belong to a user
+		return nil, nil, handlers.UnauthenticatedError()
+	}
+	if v.requestInfo.EncryptedToken != "" && !v.tokenDecrypted {
+		v.decryptToken()
+	}
+	at, att, err := v.validateToken()
+	if err != nil {
+		return nil, nil, fmt.Errorf("lookup self: %w", err)
+	}
+	if at == nil || at.GetIamUserId() == "" {
+		return nil, nil, handlers.UnauthenticatedError()
+	}
+	return at, att, nil
+}
+
 // GetTokenFromRequest pulls the token from either the Authorization header or
 // split cookies and parses it. If it cannot be parsed successfully, the issue
 // is logged and we return blank, so logic will continue as the anonymous user.
@@ -467,6 +699,7 @@ func GetTokenFromRequest(logger hclog.Logger, kmsCache *kms.Kms, req *http.Reque
 }
 
 func (v *verifier) decryptToken() {
+	v.tokenDecrypted = true
 	switch v.requestInfo.TokenFormat {
 	case AuthTokenTypeUnknown:
 		// Nothing to decrypt
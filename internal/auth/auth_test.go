@@ -11,8 +11,11 @@ import (
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/perms"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -133,3 +136,31 @@ func TestAuthTokenAuthenticator(t *testing.T) {
 		})
 	}
 }
+
+func TestSelfAllowed(t *testing.T) {
+	cases := []struct {
+		name string
+		res  perms.Resource
+		act  action.Type
+		want bool
+	}{
+		{name: "own user read", res: perms.Resource{Id: "u_1234567890", Type: resource.User}, act: action.Read, want: true},
+		{name: "own user update", res: perms.Resource{Id: "u_1234567890", Type: resource.User}, act: action.Update},
+		{name: "other user read", res: perms.Resource{Id: "u_0987654321", Type: resource.User}, act: action.Read},
+		{name: "own account change password", res: perms.Resource{Id: "apw_1234567890", Type: resource.Account}, act: action.ChangePassword, want: true},
+		{name: "own account set password", res: perms.Resource{Id: "apw_1234567890", Type: resource.Account}, act: action.SetPassword},
+		{name: "other account change password", res: perms.Resource{Id: "apw_0987654321", Type: resource.Account}, act: action.ChangePassword},
+		{name: "other type", res: perms.Resource{Id: "u_1234567890", Type: resource.Group}, act: action.Read},
+		{name: "own session read self", res: perms.Resource{Id: "s_1234567890", Type: resource.Session}, act: action.ReadSelf, want: true},
+		{name: "own session cancel self", res: perms.Resource{Id: "s_1234567890", Type: resource.Session}, act: action.CancelSelf, want: true},
+		{name: "own session read", res: perms.Resource{Id: "s_1234567890", Type: resource.Session}, act: action.Read},
+		{name: "own sessions list", res: perms.Resource{Type: resource.Session}, act: action.List, want: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, selfAllowed(tc.res, tc.act, "u_1234567890", "apw_1234567890"))
+		})
+	}
+	assert.False(t, selfAllowed(perms.Resource{Type: resource.Account}, action.ChangePassword, "u_1234567890", ""))
+	assert.False(t, selfAllowed(perms.Resource{Id: "s_1234567890", Type: resource.Session}, action.ReadSelf, "u_anon", ""))
+}
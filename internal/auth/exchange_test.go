@@ -0,0 +1,165 @@
+package auth_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/internal/servers/controller"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify_DerivedToken(t *testing.T) {
+	tc := controller.NewTestController(t, nil)
+	defer tc.Shutdown()
+
+	conn := tc.DbConn()
+	token := tc.Token()
+	_, proj := iam.TestScopes(t, tc.IamRepo(), iam.WithUserId(token.UserId), iam.WithSkipAdminRoleCreation(true), iam.WithSkipDefaultRoleCreation(true))
+
+	iamRepoFn := func() (*iam.Repository, error) {
+		return tc.IamRepo(), nil
+	}
+	serversRepoFn := func() (*servers.Repository, error) {
+		return tc.ServersRepo(), nil
+	}
+	authTokenRepoFn := func() (*authtoken.Repository, error) {
+		return tc.AuthTokenRepo(), nil
+	}
+
+	projRole := iam.TestRole(t, conn, proj.GetPublicId())
+	iam.TestUserRole(t, conn, projRole.PublicId, token.UserId)
+	iam.TestRoleGrant(t, conn, projRole.PublicId, "id=*;type=target;actions=read")
+	iam.TestRoleGrant(t, conn, projRole.PublicId, "id=*;type=target;actions=update")
+
+	ctx := context.Background()
+	derived, err := tc.AuthTokenRepo().ExchangeAuthToken(ctx, token.Id,
+		[]perms.GrantPair{{ScopeId: proj.GetPublicId(), Grant: "id=*;type=target;actions=read"}},
+		time.Hour, authtoken.WithTargetId("ttcp_1234567890"))
+	require.NoError(t, err)
+	encrypted, err := authtoken.EncryptToken(ctx, tc.Kms(), derived.GetScopeId(), derived.GetPublicId(), derived.GetToken())
+	require.NoError(t, err)
+
+	verify := func(t *testing.T, publicId, encryptedToken, targetId string, a action.Type) error {
+		t.Helper()
+		ctx := auth.NewVerifierContext(
+			context.Background(),
+			tc.Logger(),
+			iamRepoFn,
+			authTokenRepoFn,
+			serversRepoFn,
+			tc.Kms(),
+			auth.RequestInfo{
+				PublicId:       publicId,
+				EncryptedToken: encryptedToken,
+				TokenFormat:    auth.AuthTokenTypeBearer,
+			})
+		return auth.Verify(ctx,
+			auth.WithId(targetId),
+			auth.WithAction(a),
+			auth.WithScopeId(proj.GetPublicId()),
+			auth.WithType(resource.Target)).Error
+	}
+	parentEncrypted := strings.Split(token.Token, "_")[2]
+
+	t.Run("parent", func(t *testing.T) {
+		assert := assert.New(t)
+		assert.NoError(verify(t, token.Id, parentEncrypted, "ttcp_1234567890", action.Read))
+		assert.NoError(verify(t, token.Id, parentEncrypted, "ttcp_0987654321", action.Read))
+		assert.NoError(verify(t, token.Id, parentEncrypted, "ttcp_1234567890", action.Update))
+	})
+	t.Run("derived", func(t *testing.T) {
+		assert := assert.New(t)
+		assert.NoError(verify(t, derived.GetPublicId(), encrypted, "ttcp_1234567890", action.Read))
+		// Outside of the target restriction
+		assert.Error(verify(t, derived.GetPublicId(), encrypted, "ttcp_0987654321", action.Read))
+		// A grant the derived token did not keep
+		assert.Error(verify(t, derived.GetPublicId(), encrypted, "ttcp_1234567890", action.Update))
+	})
+	t.Run("parent-deleted", func(t *testing.T) {
+		_, err := tc.AuthTokenRepo().DeleteAuthToken(ctx, token.Id)
+		require.NoError(t, err)
+		assert.Error(t, verify(t, derived.GetPublicId(), encrypted, "ttcp_1234567890", action.Read))
+	})
+}
+
+func TestVerify_ScopedToken(t *testing.T) {
+	tc := controller.NewTestController(t, nil)
+	defer tc.Shutdown()
+
+	conn := tc.DbConn()
+	token := tc.Token()
+	org, proj := iam.TestScopes(t, tc.IamRepo(), iam.WithUserId(token.UserId), iam.WithSkipAdminRoleCreation(true), iam.WithSkipDefaultRoleCreation(true))
+
+	iamRepoFn := func() (*iam.Repository, error) {
+		return tc.IamRepo(), nil
+	}
+	serversRepoFn := func() (*servers.Repository, error) {
+		return tc.ServersRepo(), nil
+	}
+	authTokenRepoFn := func() (*authtoken.Repository, error) {
+		return tc.AuthTokenRepo(), nil
+	}
+
+	projRole := iam.TestRole(t, conn, proj.GetPublicId())
+	iam.TestUserRole(t, conn, projRole.PublicId, token.UserId)
+	iam.TestRoleGrant(t, conn, projRole.PublicId, "id=*;type=target;actions=read,update,authorize-session")
+
+	ctx := context.Background()
+	issue := func(t *testing.T, preset authtoken.Preset) (string, string) {
+		t.Helper()
+		tok, err := tc.AuthTokenRepo().IssueScopedAuthToken(ctx, token.Id, preset, []string{proj.GetPublicId()}, time.Hour)
+		require.NoError(t, err)
+		encrypted, err := authtoken.EncryptToken(ctx, tc.Kms(), tok.GetScopeId(), tok.GetPublicId(), tok.GetToken())
+		require.NoError(t, err)
+		return tok.GetPublicId(), encrypted
+	}
+	verify := func(t *testing.T, publicId, encryptedToken string, a action.Type) error {
+		t.Helper()
+		ctx := auth.NewVerifierContext(
+			context.Background(),
+			tc.Logger(),
+			iamRepoFn,
+			authTokenRepoFn,
+			serversRepoFn,
+			tc.Kms(),
+			auth.RequestInfo{
+				PublicId:       publicId,
+				EncryptedToken: encryptedToken,
+				TokenFormat:    auth.AuthTokenTypeBearer,
+			})
+		return auth.Verify(ctx,
+			auth.WithId("ttcp_1234567890"),
+			auth.WithAction(a),
+			auth.WithScopeId(proj.GetPublicId()),
+			auth.WithType(resource.Target)).Error
+	}
+
+	t.Run("terraform", func(t *testing.T) {
+		assert := assert.New(t)
+		id, encrypted := issue(t, authtoken.TerraformPreset)
+		assert.NoError(verify(t, id, encrypted, action.Read))
+		assert.NoError(verify(t, id, encrypted, action.Update))
+		assert.Error(verify(t, id, encrypted, action.AuthorizeSession))
+	})
+	t.Run("read-only", func(t *testing.T) {
+		assert := assert.New(t)
+		id, encrypted := issue(t, authtoken.ReadOnlyPreset)
+		assert.NoError(verify(t, id, encrypted, action.Read))
+		assert.Error(verify(t, id, encrypted, action.Update))
+		assert.Error(verify(t, id, encrypted, action.AuthorizeSession))
+	})
+	t.Run("no-grants-in-scopes", func(t *testing.T) {
+		_, err := tc.AuthTokenRepo().IssueScopedAuthToken(ctx, token.Id, authtoken.TerraformPreset, []string{org.GetPublicId()}, time.Hour)
+		assert.Error(t, err)
+	})
+}
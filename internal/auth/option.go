@@ -19,13 +19,15 @@ type Option func(*options)
 
 // options = how options are represented
 type options struct {
-	withScopeId string
-	withPin     string
-	withId      string
-	withAction  action.Type
-	withType    resource.Type
-	withUserId  string
-	withKms     *kms.Kms
+	withScopeId   string
+	withPin       string
+	withId        string
+	withAction    action.Type
+	withType      resource.Type
+	withUserId    string
+	withAccountId string
+	withKms       *kms.Kms
+	withSelf      bool
 }
 
 func getDefaultOptions() options {
@@ -76,3 +78,20 @@ func WithKms(kms *kms.Kms) Option {
 		o.withKms = kms
 	}
 }
+
+// WithAccountId sets the account id of the caller in test contexts with auth
+// disabled, for use by LookupSelf.
+func WithAccountId(id string) Option {
+	return func(o *options) {
+		o.withAccountId = id
+	}
+}
+
+// WithSelf indicates the resource was referred to by SelfId, or is a session
+// of the caller, so the caller is allowed to perform the self actions on their
+// own user, account or sessions regardless of their grants.
+func WithSelf(self bool) Option {
+	return func(o *options) {
+		o.withSelf = self
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/boundary/internal/auth/password/store"
+	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/oplog"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
@@ -113,8 +114,13 @@ func (c *Argon2Configuration) whereDup() (string, []interface{}) {
 type Argon2Credential struct {
 	*store.Argon2Credential
 	tableName string
+	// scopeId is the scope of the credential's auth method, whose database
+	// key encrypts the credential's salt. It is not stored.
+	scopeId string
 }
 
+var _ db.FieldEncrypter = (*Argon2Credential)(nil)
+
 func newArgon2Credential(accountId string, password string, conf *Argon2Configuration) (*Argon2Credential, error) {
 	if accountId == "" {
 		return nil, fmt.Errorf("new: password argon2 credential: no accountId: %w", errors.ErrInvalidParameter)
@@ -153,6 +159,7 @@ func (c *Argon2Credential) clone() *Argon2Credential {
 	cp := proto.Clone(c.Argon2Credential)
 	return &Argon2Credential{
 		Argon2Credential: cp.(*store.Argon2Credential),
+		scopeId:          c.scopeId,
 	}
 }
 
@@ -169,6 +176,23 @@ func (c *Argon2Credential) SetTableName(n string) {
 	c.tableName = n
 }
 
+// GetScopeId returns the scope of the credential's auth method, whose
+// database key encrypts the credential's salt.
+func (c *Argon2Credential) GetScopeId() string {
+	return c.scopeId
+}
+
+// SetKeyId sets the id of the key used to encrypt the credential's salt.
+func (c *Argon2Credential) SetKeyId(keyId string) {
+	c.KeyId = keyId
+}
+
+// WrappedFields returns the stored credential, whose salt is encrypted. It
+// satisfies the db.FieldEncrypter interface.
+func (c *Argon2Credential) WrappedFields() interface{} {
+	return c.Argon2Credential
+}
+
 func (c *Argon2Credential) encrypt(ctx context.Context, cipher wrapping.Wrapper) error {
 	if err := structwrapping.WrapStruct(ctx, cipher, c.Argon2Credential, nil); err != nil {
 		return fmt.Errorf("error encrypting argon2 credential: %w", err)
@@ -10,6 +10,7 @@ import (
 const (
 	argon2ConfigurationPrefix = "arg2conf"
 	argon2CredentialPrefix    = "arg2cred"
+	recoveryCodePrefix        = "arcode"
 )
 
 func newArgon2ConfigurationId() (string, error) {
@@ -27,3 +28,11 @@ func newArgon2CredentialId() (string, error) {
 	}
 	return id, err
 }
+
+func newRecoveryCodeId() (string, error) {
+	id, err := db.NewPrivateId(recoveryCodePrefix)
+	if err != nil {
+		return "", fmt.Errorf("new password recovery code id: %w", err)
+	}
+	return id, err
+}
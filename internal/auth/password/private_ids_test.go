@@ -19,4 +19,9 @@ func Test_PrivateIds(t *testing.T) {
 		require.NoError(t, err)
 		assert.True(t, strings.HasPrefix(id, argon2CredentialPrefix+"_"))
 	})
+	t.Run("recoveryCode", func(t *testing.T) {
+		id, err := newRecoveryCodeId()
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(id, recoveryCodePrefix+"_"))
+	})
 }
@@ -29,7 +29,20 @@ select acct.name,                        -- Account.Name
    and acct.login_name = $2
    and cred.password_conf_id = conf.private_id
    and cred.password_account_id = acct.public_id
-   and acct.auth_method_id = meth.public_id ;
+   and acct.auth_method_id = meth.public_id
+   -- disabled accounts, and accounts of disabled users, do not authenticate
+   and not exists (
+       select
+         from auth_account_disabled d
+        where d.auth_account_id = acct.public_id
+       )
+   and not exists (
+       select
+         from auth_account a
+         join iam_user_disabled d
+           on d.iam_user_id = a.iam_user_id
+        where a.public_id = acct.public_id
+       ) ;
 `
 	currentConfigForAccountQuery = `
 select *
@@ -40,5 +53,43 @@ select *
          from auth_password_account
         where public_id = $1
     );
+`
+	deleteRecoveryCodesQuery = `
+delete from auth_password_recovery_code
+ where password_account_id = $1;
+`
+	useRecoveryCodeQuery = `
+update auth_password_recovery_code
+   set used_time = now()
+ where private_id = $1
+   and used_time is null;
+`
+	deleteRecoveryTotpQuery = `
+delete from auth_password_recovery_totp
+ where password_account_id = $1;
+`
+	useRecoveryTotpQuery = `
+update auth_password_recovery_totp
+   set last_used_step = $1
+ where password_account_id = $2
+   and last_used_step < $1;
+`
+	// accountInScopeWhere selects the account of an auth method with a login
+	// name, if the auth method is in the scope.
+	accountInScopeWhere = `
+auth_method_id = ?
+and login_name = ?
+and auth_method_id in (select public_id from auth_password_method where scope_id = ?)
+`
+	// enabledAccountWhere selects the accounts which are not disabled and
+	// whose users are not disabled.
+	enabledAccountWhere = `
+public_id not in (select auth_account_id from auth_account_disabled)
+and public_id not in (
+  select a.public_id
+    from auth_account a
+    join iam_user_disabled d
+      on d.iam_user_id = a.iam_user_id
+)
 `
 )
@@ -0,0 +1,90 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+)
+
+const (
+	// DefaultRecoveryCodeCount is the number of recovery codes generated by
+	// GenerateRecoveryCodes when no count is provided.
+	DefaultRecoveryCodeCount = 10
+
+	recoveryCodeTableName      = "auth_password_recovery_code"
+	recoveryCodeLength         = 12
+	recoveryCodeGroupLength    = 4
+	recoveryCodeSaltLength     = 16
+	recoveryCodeAlphabet       = "abcdefghjkmnpqrstuvwxyz23456789"
+	recoveryCodeGroupSeparator = "-"
+)
+
+// A recoveryCode is a single use code which authenticates as a password
+// account. Codes are random, so a salted SHA-256 hash is enough to protect
+// them, unlike passwords which are hashed with argon2.
+type recoveryCode struct {
+	PrivateId         string `gorm:"primary_key"`
+	PasswordAccountId string
+	Salt              []byte
+	CodeHash          []byte
+	CreateTime        *timestamp.Timestamp `gorm:"default:current_timestamp"`
+	UsedTime          *timestamp.Timestamp
+}
+
+// TableName returns the table name.
+func (c *recoveryCode) TableName() string {
+	return recoveryCodeTableName
+}
+
+// newRecoveryCode returns a new random code in its display form, e.g.
+// abcd-efgh-jkmn, and the recovery code storing its hash for the account.
+func newRecoveryCode(accountId string) (string, *recoveryCode, error) {
+	var b strings.Builder
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < recoveryCodeLength; i++ {
+		if i > 0 && i%recoveryCodeGroupLength == 0 {
+			b.WriteString(recoveryCodeGroupSeparator)
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", nil, fmt.Errorf("new recovery code: %w", err)
+		}
+		b.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+	code := b.String()
+
+	salt := make([]byte, recoveryCodeSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", nil, fmt.Errorf("new recovery code: %w", err)
+	}
+	id, err := newRecoveryCodeId()
+	if err != nil {
+		return "", nil, err
+	}
+	return code, &recoveryCode{
+		PrivateId:         id,
+		PasswordAccountId: accountId,
+		Salt:              salt,
+		CodeHash:          hashRecoveryCode(salt, code),
+	}, nil
+}
+
+// matches returns true if code is the recovery code. Case and group
+// separators are ignored.
+func (c *recoveryCode) matches(code string) bool {
+	return subtle.ConstantTimeCompare(c.CodeHash, hashRecoveryCode(c.Salt, code)) == 1
+}
+
+func hashRecoveryCode(salt []byte, code string) []byte {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer(recoveryCodeGroupSeparator, "", " ", "").Replace(code)
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(code))
+	return h.Sum(nil)
+}
@@ -0,0 +1,151 @@
+package password
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+)
+
+const (
+	// RecoveryTotpIssuer is the issuer of the TOTP secrets of recovery codes
+	// shown by authenticator apps.
+	RecoveryTotpIssuer = "Boundary"
+
+	recoveryTotpTableName    = "auth_password_recovery_totp"
+	recoveryTotpSecretLength = 20
+	recoveryTotpDigits       = 6
+	recoveryTotpPeriod       = 30 * time.Second
+	// recoveryTotpSkew is the number of periods before and after the current
+	// one whose codes are accepted, to tolerate clock drift.
+	recoveryTotpSkew = 1
+)
+
+// A recoveryTotp is the TOTP secret of an account with recovery codes. A
+// recovery code only authenticates together with a current TOTP code of the
+// secret, as described in RFC 6238 with its default parameters: HMAC-SHA1, 6
+// digits and a 30 second period, which authenticator apps support.
+type recoveryTotp struct {
+	PasswordAccountId string `gorm:"primary_key"`
+	CtSecret          []byte `gorm:"column:secret;not_null" wrapping:"ct,totp_secret"`
+	Secret            []byte `gorm:"-" wrapping:"pt,totp_secret"`
+	KeyId             string `gorm:"not_null"`
+	// LastUsedStep is the time step of the last code used, so a code can not
+	// be used twice.
+	LastUsedStep int64
+	CreateTime   *timestamp.Timestamp `gorm:"default:current_timestamp"`
+
+	// scopeId is the scope of the account's auth method, whose database key
+	// encrypts the secret. It is not stored.
+	scopeId string
+}
+
+var _ db.FieldEncrypter = (*recoveryTotp)(nil)
+
+// TableName returns the table name.
+func (t *recoveryTotp) TableName() string {
+	return recoveryTotpTableName
+}
+
+// GetScopeId returns the scope of the account's auth method, whose database
+// key encrypts the secret.
+func (t *recoveryTotp) GetScopeId() string {
+	return t.scopeId
+}
+
+// GetKeyId returns the id of the key used to encrypt the secret.
+func (t *recoveryTotp) GetKeyId() string {
+	return t.KeyId
+}
+
+// SetKeyId sets the id of the key used to encrypt the secret.
+func (t *recoveryTotp) SetKeyId(keyId string) {
+	t.KeyId = keyId
+}
+
+// WrappedFields returns the TOTP, whose secret is encrypted. It satisfies the
+// db.FieldEncrypter interface.
+func (t *recoveryTotp) WrappedFields() interface{} {
+	return t
+}
+
+// newRecoveryTotp returns a new random TOTP secret for the account of an
+// auth method in scopeId.
+func newRecoveryTotp(scopeId, accountId string) (*recoveryTotp, error) {
+	secret := make([]byte, recoveryTotpSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("new recovery totp: %w", err)
+	}
+	return &recoveryTotp{
+		PasswordAccountId: accountId,
+		Secret:            secret,
+		scopeId:           scopeId,
+	}, nil
+}
+
+// encodedSecret returns the secret in the unpadded base32 form entered into
+// authenticator apps.
+func (t *recoveryTotp) encodedSecret() string {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(t.Secret)
+}
+
+// url returns the otpauth URL of the secret for the login name, which
+// authenticator apps read from a QR code.
+func (t *recoveryTotp) url(loginName string) string {
+	q := url.Values{}
+	q.Set("secret", t.encodedSecret())
+	q.Set("issuer", RecoveryTotpIssuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", strconv.Itoa(recoveryTotpDigits))
+	q.Set("period", strconv.Itoa(int(recoveryTotpPeriod/time.Second)))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + RecoveryTotpIssuer + ":" + loginName,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+// validate returns the time step of code if it is a code of the secret at
+// now, within the allowed skew, which is later than the last code used.
+func (t *recoveryTotp) validate(code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	current := now.Unix() / int64(recoveryTotpPeriod/time.Second)
+	for step := current - recoveryTotpSkew; step <= current+recoveryTotpSkew; step++ {
+		if step <= t.LastUsedStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(t.Secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpCode returns the code of the secret for the time step, as defined by
+// RFC 6238 and RFC 4226.
+func totpCode(secret []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < recoveryTotpDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", recoveryTotpDigits, value%mod)
+}
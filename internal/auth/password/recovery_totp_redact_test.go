@@ -0,0 +1,21 @@
+package password
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/libs/redact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoveryTotp_Redact(t *testing.T) {
+	assert := assert.New(t)
+	r := &recoveryTotp{
+		PasswordAccountId: "apw_1234567890",
+		Secret:            []byte("totp-secret-plaintext"),
+		CtSecret:          []byte("totp-secret-ciphertext"),
+	}
+	err := fmt.Errorf("failed for %s with %s and %s", r.PasswordAccountId, r.Secret, r.CtSecret)
+	got := redact.Error(err, r).Error()
+	assert.Equal("failed for apw_1234567890 with [REDACTED] and [REDACTED]", got)
+}
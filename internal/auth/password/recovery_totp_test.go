@@ -0,0 +1,90 @@
+package password
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_totpCode(t *testing.T) {
+	t.Parallel()
+	// The SHA1 test vectors of RFC 6238 appendix B, truncated to 6 digits
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, totpCode(secret, tt.unix/30), "time %d", tt.unix)
+	}
+}
+
+func Test_recoveryTotp_validate(t *testing.T) {
+	t.Parallel()
+	totp, err := newRecoveryTotp("o_1234567890", "apw_1234567890")
+	require.NoError(t, err)
+	now := time.Unix(1234567890, 0)
+	step := now.Unix() / 30
+
+	t.Run("current", func(t *testing.T) {
+		got, ok := totp.validate(totpCode(totp.Secret, step), now)
+		assert.True(t, ok)
+		assert.Equal(t, step, got)
+	})
+	t.Run("skew", func(t *testing.T) {
+		got, ok := totp.validate(" "+totpCode(totp.Secret, step-1)+" ", now)
+		assert.True(t, ok)
+		assert.Equal(t, step-1, got)
+		got, ok = totp.validate(totpCode(totp.Secret, step+1), now)
+		assert.True(t, ok)
+		assert.Equal(t, step+1, got)
+	})
+	t.Run("outside-skew", func(t *testing.T) {
+		_, ok := totp.validate(totpCode(totp.Secret, step-2), now)
+		assert.False(t, ok)
+		_, ok = totp.validate(totpCode(totp.Secret, step+2), now)
+		assert.False(t, ok)
+	})
+	t.Run("used", func(t *testing.T) {
+		used := *totp
+		used.LastUsedStep = step
+		_, ok := used.validate(totpCode(totp.Secret, step), now)
+		assert.False(t, ok)
+		got, ok := used.validate(totpCode(totp.Secret, step+1), now)
+		assert.True(t, ok)
+		assert.Equal(t, step+1, got)
+	})
+	t.Run("wrong-code", func(t *testing.T) {
+		_, ok := totp.validate("", now)
+		assert.False(t, ok)
+		_, ok = totp.validate("12345", now)
+		assert.False(t, ok)
+	})
+}
+
+func Test_recoveryTotp_url(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	totp := &recoveryTotp{Secret: []byte("12345678901234567890")}
+	u, err := url.Parse(totp.url("admin"))
+	require.NoError(err)
+	assert.Equal("otpauth", u.Scheme)
+	assert.Equal("totp", u.Host)
+	assert.Equal("/Boundary:admin", u.Path)
+	q := u.Query()
+	assert.Equal("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", q.Get("secret"))
+	assert.Equal(RecoveryTotpIssuer, q.Get("issuer"))
+	assert.Equal("SHA1", q.Get("algorithm"))
+	assert.Equal("6", q.Get("digits"))
+	assert.Equal("30", q.Get("period"))
+}
@@ -77,11 +77,6 @@ func (r *Repository) CreateAccount(ctx context.Context, scopeId string, a *Accou
 	if err != nil {
 		return nil, fmt.Errorf("create: password account: unable to get oplog wrapper: %w", err)
 	}
-	databaseWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeDatabase)
-	if err != nil {
-		return nil, fmt.Errorf("create: password account: unable to get database wrapper: %w", err)
-	}
-
 	var newCred *Argon2Credential
 	var newAccount *Account
 	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
@@ -93,10 +88,8 @@ func (r *Repository) CreateAccount(ctx context.Context, scopeId string, a *Accou
 
 			if cred != nil {
 				newCred = cred.clone()
-				if err := newCred.encrypt(ctx, databaseWrapper); err != nil {
-					return err
-				}
-				if err := w.Create(ctx, newCred, db.WithOplog(oplogWrapper, cred.oplog(oplog.OpType_OP_TYPE_CREATE))); err != nil {
+				newCred.scopeId = scopeId
+				if err := w.Create(ctx, newCred, db.WithOplog(oplogWrapper, cred.oplog(oplog.OpType_OP_TYPE_CREATE)), db.WithFieldWrapper(r.kms.DatabaseFieldWrapper())); err != nil {
 					return err
 				}
 			}
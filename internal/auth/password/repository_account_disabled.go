@@ -0,0 +1,189 @@
+package password
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/auth/password/store"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/securityevent"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultAccountDisabledTableName = "auth_account_disabled"
+
+// AccountDisabled records that an account may not authenticate and why.
+type AccountDisabled struct {
+	*store.AccountDisabled
+	tableName string
+}
+
+func allocAccountDisabled() *AccountDisabled {
+	return &AccountDisabled{
+		AccountDisabled: &store.AccountDisabled{},
+	}
+}
+
+func (d *AccountDisabled) clone() *AccountDisabled {
+	cp := proto.Clone(d.AccountDisabled)
+	return &AccountDisabled{
+		AccountDisabled: cp.(*store.AccountDisabled),
+	}
+}
+
+// TableName returns the table name for disabled accounts.
+func (d *AccountDisabled) TableName() string {
+	if d.tableName != "" {
+		return d.tableName
+	}
+	return defaultAccountDisabledTableName
+}
+
+// SetTableName sets the table name.
+func (d *AccountDisabled) SetTableName(n string) {
+	d.tableName = n
+}
+
+func (d *AccountDisabled) oplog(op oplog.OpType, scopeId, authMethodId string) oplog.Metadata {
+	return oplog.Metadata{
+		"resource-public-id": []string{d.GetAuthAccountId()},
+		"resource-type":      []string{"disabled password account"},
+		"op-type":            []string{op.String()},
+		"scope-id":           []string{scopeId},
+		"auth-method-id":     []string{authMethodId},
+	}
+}
+
+// DisableAccount disables the account, deleting its auth tokens, so it may
+// not authenticate until it is enabled again, and records a lockout security
+// event. The account keeps its user.
+// Disabling a disabled account keeps the original reason. No options are
+// currently supported.
+func (r *Repository) DisableAccount(ctx context.Context, scopeId, withPublicId, reason string, opt ...Option) error {
+	if withPublicId == "" {
+		return fmt.Errorf("disable: password account: missing public id %w", errors.ErrInvalidParameter)
+	}
+	if scopeId == "" {
+		return fmt.Errorf("disable: password account: missing scope id %w", errors.ErrInvalidParameter)
+	}
+	if reason == "" {
+		return fmt.Errorf("disable: password account: missing reason %w", errors.ErrInvalidParameter)
+	}
+	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return fmt.Errorf("disable: password account: unable to get oplog wrapper: %w", err)
+	}
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(read db.Reader, w db.Writer) error {
+			acct := allocAccount()
+			acct.PublicId = withPublicId
+			if err := read.LookupByPublicId(ctx, acct); err != nil {
+				return err
+			}
+			existing := allocAccountDisabled()
+			err := read.LookupWhere(ctx, existing, "auth_account_id = ?", withPublicId)
+			switch {
+			case err == nil:
+				return nil
+			case !errors.IsNotFoundError(err):
+				return err
+			}
+
+			d := allocAccountDisabled()
+			d.AuthAccountId = withPublicId
+			d.Reason = reason
+			if err := w.Create(ctx, d, db.WithOplog(oplogWrapper, d.oplog(oplog.OpType_OP_TYPE_CREATE, scopeId, acct.GetAuthMethodId()))); err != nil {
+				return err
+			}
+			if _, err := w.Exec(ctx,
+				"delete from auth_token where auth_account_id = ?",
+				[]interface{}{withPublicId}); err != nil {
+				return err
+			}
+			return securityevent.Enqueue(ctx, w, &securityevent.Event{
+				Kind:         securityevent.Lockout,
+				ScopeId:      scopeId,
+				AuthMethodId: acct.GetAuthMethodId(),
+			})
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("disable: password account: %w for %s", err, withPublicId)
+	}
+	return nil
+}
+
+// EnableAccount enables the disabled account. Enabling an account which is
+// not disabled does nothing. No options are currently supported.
+func (r *Repository) EnableAccount(ctx context.Context, scopeId, withPublicId string, opt ...Option) error {
+	if withPublicId == "" {
+		return fmt.Errorf("enable: password account: missing public id %w", errors.ErrInvalidParameter)
+	}
+	if scopeId == "" {
+		return fmt.Errorf("enable: password account: missing scope id %w", errors.ErrInvalidParameter)
+	}
+	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return fmt.Errorf("enable: password account: unable to get oplog wrapper: %w", err)
+	}
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(read db.Reader, w db.Writer) error {
+			acct := allocAccount()
+			acct.PublicId = withPublicId
+			if err := read.LookupByPublicId(ctx, acct); err != nil {
+				return err
+			}
+			d := allocAccountDisabled()
+			d.AuthAccountId = withPublicId
+			rowsDeleted, err := w.Delete(ctx, d.clone(), db.WithOplog(oplogWrapper, d.oplog(oplog.OpType_OP_TYPE_DELETE, scopeId, acct.GetAuthMethodId())))
+			if err == nil && rowsDeleted > 1 {
+				return errors.ErrMultipleRecords
+			}
+			return err
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("enable: password account: %w for %s", err, withPublicId)
+	}
+	return nil
+}
+
+// LookupAccountDisabled returns why the account is disabled, or nil if it is
+// not. No options are currently supported.
+func (r *Repository) LookupAccountDisabled(ctx context.Context, withPublicId string, opt ...Option) (*AccountDisabled, error) {
+	if withPublicId == "" {
+		return nil, fmt.Errorf("lookup disabled: password account: missing public id %w", errors.ErrInvalidParameter)
+	}
+	d := allocAccountDisabled()
+	if err := r.reader.LookupWhere(ctx, d, "auth_account_id = ?", withPublicId); err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup disabled: password account: failed %w for %s", err, withPublicId)
+	}
+	return d, nil
+}
+
+// ListDisabledAccounts returns why the disabled accounts of the auth method
+// are disabled. No options are currently supported.
+func (r *Repository) ListDisabledAccounts(ctx context.Context, withAuthMethodId string, opt ...Option) ([]*AccountDisabled, error) {
+	if withAuthMethodId == "" {
+		return nil, fmt.Errorf("list disabled: password account: missing auth method id %w", errors.ErrInvalidParameter)
+	}
+	var disabled []*AccountDisabled
+	if err := r.reader.SearchWhere(ctx, &disabled,
+		"auth_account_id in (select public_id from auth_account where auth_method_id = ?)",
+		[]interface{}{withAuthMethodId}, db.WithLimit(-1)); err != nil {
+		return nil, fmt.Errorf("list disabled: password account: %w", err)
+	}
+	return disabled, nil
+}
@@ -0,0 +1,124 @@
+package password
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/auth/password/store"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_DisableAccount(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	o, _ := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	authMethod := TestAuthMethods(t, conn, o.GetPublicId(), 1)[0]
+	accts := TestAccounts(t, conn, authMethod.PublicId, 2)
+	acct, other := accts[0], accts[1]
+
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("invalid-parameters", func(t *testing.T) {
+		assert := assert.New(t)
+		assert.Truef(errors.Is(repo.DisableAccount(ctx, o.GetPublicId(), "", "reason"), errors.ErrInvalidParameter), "expected invalid parameter")
+		assert.Truef(errors.Is(repo.DisableAccount(ctx, "", acct.PublicId, "reason"), errors.ErrInvalidParameter), "expected invalid parameter")
+		assert.Truef(errors.Is(repo.DisableAccount(ctx, o.GetPublicId(), acct.PublicId, ""), errors.ErrInvalidParameter), "expected invalid parameter")
+		assert.Truef(errors.Is(repo.EnableAccount(ctx, o.GetPublicId(), ""), errors.ErrInvalidParameter), "expected invalid parameter")
+		assert.Truef(errors.Is(repo.EnableAccount(ctx, "", acct.PublicId), errors.ErrInvalidParameter), "expected invalid parameter")
+		_, err := repo.LookupAccountDisabled(ctx, "")
+		assert.Truef(errors.Is(err, errors.ErrInvalidParameter), "expected invalid parameter")
+		_, err = repo.ListDisabledAccounts(ctx, "")
+		assert.Truef(errors.Is(err, errors.ErrInvalidParameter), "expected invalid parameter")
+	})
+	t.Run("account-not-found", func(t *testing.T) {
+		assert := assert.New(t)
+		assert.Truef(errors.Is(repo.DisableAccount(ctx, o.GetPublicId(), "apw_1234567890", "reason"), errors.ErrRecordNotFound), "expected record not found")
+		assert.Truef(errors.Is(repo.EnableAccount(ctx, o.GetPublicId(), "apw_1234567890"), errors.ErrRecordNotFound), "expected record not found")
+	})
+	t.Run("disable-and-enable", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		d, err := repo.LookupAccountDisabled(ctx, acct.PublicId)
+		require.NoError(err)
+		assert.Nil(d)
+
+		require.NoError(repo.DisableAccount(ctx, o.GetPublicId(), acct.PublicId, "first"))
+		assert.NoError(db.TestVerifyOplog(t, rw, acct.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
+		// Disabling again keeps the original reason
+		require.NoError(repo.DisableAccount(ctx, o.GetPublicId(), acct.PublicId, "second"))
+		d, err = repo.LookupAccountDisabled(ctx, acct.PublicId)
+		require.NoError(err)
+		require.NotNil(d)
+		assert.Equal("first", d.Reason)
+		assert.NotNil(d.CreateTime)
+
+		list, err := repo.ListDisabledAccounts(ctx, authMethod.PublicId)
+		require.NoError(err)
+		require.Len(list, 1)
+		assert.Equal(acct.PublicId, list[0].AuthAccountId)
+
+		require.NoError(repo.EnableAccount(ctx, o.GetPublicId(), acct.PublicId))
+		assert.NoError(db.TestVerifyOplog(t, rw, acct.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_DELETE), db.WithCreateNotBefore(10*time.Second)))
+		d, err = repo.LookupAccountDisabled(ctx, acct.PublicId)
+		require.NoError(err)
+		assert.Nil(d)
+		// Enabling an enabled account does nothing
+		require.NoError(repo.EnableAccount(ctx, o.GetPublicId(), acct.PublicId))
+
+		// The account is still there
+		a, err := repo.LookupAccount(ctx, acct.PublicId)
+		require.NoError(err)
+		assert.NotNil(a)
+	})
+	t.Run("disabled-recovery-code-not-used", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		codes, err := repo.GenerateRecoveryCodes(ctx, o.GetPublicId(), other.PublicId, 1)
+		require.NoError(err)
+
+		require.NoError(repo.DisableAccount(ctx, o.GetPublicId(), other.PublicId, "test"))
+		got, err := repo.AuthenticateWithRecoveryCode(ctx, o.GetPublicId(), authMethod.PublicId, other.LoginName, codes.Codes[0], TestRecoveryTotpCode(t, codes.TotpSecret, 0))
+		require.NoError(err)
+		assert.Nil(got)
+		n, err := repo.CountRecoveryCodes(ctx, other.PublicId)
+		require.NoError(err)
+		assert.Equal(1, n, "a disabled account does not use its recovery code")
+
+		require.NoError(repo.EnableAccount(ctx, o.GetPublicId(), other.PublicId))
+		got, err = repo.AuthenticateWithRecoveryCode(ctx, o.GetPublicId(), authMethod.PublicId, other.LoginName, codes.Codes[0], TestRecoveryTotpCode(t, codes.TotpSecret, 0))
+		require.NoError(err)
+		require.NotNil(got)
+		assert.Equal(other.PublicId, got.PublicId)
+	})
+	t.Run("disabled-password-not-verified", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		const loginName, passwd = "kazmierczak", "12345678"
+		created, err := repo.CreateAccount(ctx, o.GetPublicId(), &Account{
+			Account: &store.Account{
+				AuthMethodId: authMethod.PublicId,
+				LoginName:    loginName,
+			},
+		}, WithPassword(passwd))
+		require.NoError(err)
+
+		require.NoError(repo.DisableAccount(ctx, o.GetPublicId(), created.PublicId, "test"))
+		got, err := repo.Authenticate(ctx, o.GetPublicId(), authMethod.PublicId, loginName, passwd)
+		require.NoError(err)
+		assert.Nil(got)
+
+		require.NoError(repo.EnableAccount(ctx, o.GetPublicId(), created.PublicId))
+		got, err = repo.Authenticate(ctx, o.GetPublicId(), authMethod.PublicId, loginName, passwd)
+		require.NoError(err)
+		require.NotNil(got)
+		assert.Equal(created.PublicId, got.PublicId)
+	})
+}
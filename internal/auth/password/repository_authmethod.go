@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/types/resource"
 )
 
 // CreateAuthMethod inserts m into the repository and returns a new
@@ -81,7 +82,11 @@ func (r *Repository) CreateAuthMethod(ctx context.Context, m *AuthMethod, opt ..
 				return err
 			}
 			newAuthMethod = m.clone()
-			return w.Create(ctx, newAuthMethod, db.WithOplog(oplogWrapper, m.oplog(oplog.OpType_OP_TYPE_CREATE)))
+			if err := w.Create(ctx, newAuthMethod, db.WithOplog(oplogWrapper, m.oplog(oplog.OpType_OP_TYPE_CREATE))); err != nil {
+				return err
+			}
+			w.NotifyOnCommit(ctx, db.Change{ResourceType: resource.AuthMethod, Op: db.CreateOp, Id: newAuthMethod.PublicId})
+			return nil
 		},
 	)
 
@@ -157,6 +162,9 @@ func (r *Repository) DeleteAuthMethod(ctx context.Context, scopeId, publicId str
 			if err == nil && rowsDeleted > 1 {
 				return errors.ErrMultipleRecords
 			}
+			if err == nil && rowsDeleted > 0 {
+				w.NotifyOnCommit(ctx, db.Change{ResourceType: resource.AuthMethod, Op: db.DeleteOp, Id: publicId})
+			}
 			return err
 		},
 	)
@@ -242,6 +250,9 @@ func (r *Repository) UpdateAuthMethod(ctx context.Context, authMethod *AuthMetho
 			if err == nil && rowsUpdated > 1 {
 				return errors.ErrMultipleRecords
 			}
+			if err == nil && rowsUpdated > 0 {
+				w.NotifyOnCommit(ctx, db.Change{ResourceType: resource.AuthMethod, Op: db.UpdateOp, Id: upAuthMethod.PublicId})
+			}
 			return err
 		},
 	)
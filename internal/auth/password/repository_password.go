@@ -22,7 +22,8 @@ type authAccount struct {
 
 // Authenticate authenticates loginName and password match for loginName in
 // authMethodId. The account for the loginName is returned if authentication
-// is successful. Returns nil if authentication fails.
+// is successful. Returns nil if authentication fails, or if the account or
+// its user is disabled, which is checked before the password.
 //
 // The CredentialId in the returned account represents a user's current
 // password. A new CredentialId is generated when a user's password is
@@ -45,11 +46,6 @@ func (r *Repository) Authenticate(ctx context.Context, scopeId, authMethodId, lo
 		return nil, fmt.Errorf("password authenticate: no scopeId: %w", errors.ErrInvalidParameter)
 	}
 
-	databaseWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeDatabase)
-	if err != nil {
-		return nil, fmt.Errorf("password authenticate: unable to get database wrapper: %w", err)
-	}
-
 	acct, err := r.authenticate(ctx, scopeId, authMethodId, loginName, password)
 	if err != nil {
 		return nil, fmt.Errorf("password authenticate: %w", err)
@@ -75,16 +71,15 @@ func (r *Repository) Authenticate(ctx context.Context, scopeId, authMethodId, lo
 
 		// do not change the Credential Id
 		cred.PrivateId = acct.CredentialId
-		if err := cred.encrypt(ctx, databaseWrapper); err != nil {
-			return acct.Account, fmt.Errorf("password authenticate: update credential: encrypt: %w", err)
-		}
+		cred.scopeId = scopeId
 
-		fields := []string{"CtSalt", "DerivedKey", "PasswordConfId"}
+		// the salt is encrypted and its key id recorded by the update
+		fields := []string{"Salt", "DerivedKey", "PasswordConfId"}
 		metadata := cred.oplog(oplog.OpType_OP_TYPE_UPDATE)
 
 		_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
 			func(_ db.Reader, w db.Writer) error {
-				rowsUpdated, err := w.Update(ctx, cred, fields, nil, db.WithOplog(oplogWrapper, metadata))
+				rowsUpdated, err := w.Update(ctx, cred, fields, nil, db.WithOplog(oplogWrapper, metadata), db.WithFieldWrapper(r.kms.DatabaseFieldWrapper()))
 				if err == nil && rowsUpdated > 1 {
 					return errors.ErrMultipleRecords
 				}
@@ -137,11 +132,6 @@ func (r *Repository) ChangePassword(ctx context.Context, scopeId, accountId, old
 	if err != nil {
 		return nil, fmt.Errorf("change password: unable to get oplog wrapper: %w", err)
 	}
-	databaseWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeDatabase)
-	if err != nil {
-		return nil, fmt.Errorf("change password: unable to get database wrapper: %w", err)
-	}
-
 	acct, err := r.authenticate(ctx, scopeId, authAccount.GetAuthMethodId(), authAccount.GetLoginName(), old)
 	if err != nil {
 		return nil, fmt.Errorf("change password: %w", err)
@@ -162,9 +152,7 @@ func (r *Repository) ChangePassword(ctx context.Context, scopeId, accountId, old
 		return nil, fmt.Errorf("change password: %w", err)
 	}
 
-	if err := newCred.encrypt(ctx, databaseWrapper); err != nil {
-		return nil, fmt.Errorf("change password: encrypt: %w", err)
-	}
+	newCred.scopeId = scopeId
 
 	oldCred := acct.Argon2Credential
 
@@ -189,7 +177,7 @@ func (r *Repository) ChangePassword(ctx context.Context, scopeId, accountId, old
 			if err != nil {
 				return err
 			}
-			return w.Create(ctx, newCred, db.WithOplog(oplogWrapper, newCred.oplog(oplog.OpType_OP_TYPE_CREATE)))
+			return w.Create(ctx, newCred, db.WithOplog(oplogWrapper, newCred.oplog(oplog.OpType_OP_TYPE_CREATE)), db.WithFieldWrapper(r.kms.DatabaseFieldWrapper()))
 		},
 	)
 	if err != nil {
@@ -228,13 +216,8 @@ func (r *Repository) authenticate(ctx context.Context, scopeId, authMethodId, lo
 		acct = accts[0]
 	}
 
-	// We don't pass a wrapper in here because for ecryption we want to indicate the expected key ID
-	databaseWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeDatabase, kms.WithKeyId(acct.GetKeyId()))
-	if err != nil {
-		return nil, fmt.Errorf("unable to get database wrapper: %w", err)
-	}
-
-	if err := acct.decrypt(ctx, databaseWrapper); err != nil {
+	acct.Argon2Credential.scopeId = scopeId
+	if err := db.DecryptFields(ctx, acct.Argon2Credential, r.kms.DatabaseFieldWrapper()); err != nil {
 		return nil, fmt.Errorf("cannot decrypt credential: %w", err)
 	}
 
@@ -263,11 +246,6 @@ func (r *Repository) SetPassword(ctx context.Context, scopeId, accountId, passwo
 	if err != nil {
 		return nil, fmt.Errorf("set password: unable to get oplog wrapper: %w", err)
 	}
-	databaseWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeDatabase)
-	if err != nil {
-		return nil, fmt.Errorf("set password: unable to get database wrapper: %w", err)
-	}
-
 	var newCred *Argon2Credential
 	if password != "" {
 		cc, err := r.currentConfigForAccount(ctx, accountId)
@@ -284,9 +262,7 @@ func (r *Repository) SetPassword(ctx context.Context, scopeId, accountId, passwo
 		if err != nil {
 			return nil, fmt.Errorf("set password: %w", err)
 		}
-		if err := newCred.encrypt(ctx, databaseWrapper); err != nil {
-			return nil, fmt.Errorf("set password: encrypt: %w", err)
-		}
+		newCred.scopeId = scopeId
 	}
 
 	var acct *Account
@@ -321,7 +297,7 @@ func (r *Repository) SetPassword(ctx context.Context, scopeId, accountId, passwo
 				}
 			}
 			if newCred != nil {
-				return w.Create(ctx, newCred, db.WithOplog(oplogWrapper, newCred.oplog(oplog.OpType_OP_TYPE_CREATE)))
+				return w.Create(ctx, newCred, db.WithOplog(oplogWrapper, newCred.oplog(oplog.OpType_OP_TYPE_CREATE)), db.WithFieldWrapper(r.kms.DatabaseFieldWrapper()))
 			}
 			return nil
 		},
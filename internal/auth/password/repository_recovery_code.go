@@ -0,0 +1,207 @@
+package password
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// RecoveryCodes are the single use recovery codes of an account and the TOTP
+// secret whose current code must be provided with each of them.
+type RecoveryCodes struct {
+	// Codes are the recovery codes, in their display form.
+	Codes []string
+	// TotpSecret is the base32 encoded TOTP secret, to be entered into an
+	// authenticator app.
+	TotpSecret string
+	// TotpUrl is the otpauth URL of the TOTP secret, which authenticator apps
+	// read from a QR code.
+	TotpUrl string
+}
+
+// GenerateRecoveryCodes replaces the recovery codes and TOTP secret of the
+// account of an auth method in scopeId with count new codes and a new secret,
+// and returns them. A count <= 0 generates DefaultRecoveryCodeCount codes. The
+// codes and secret are only returned here; only the hashes of the codes are
+// stored, and the secret is encrypted with the database key of scopeId.
+//
+// Each code can be used once with AuthenticateWithRecoveryCode, together with
+// a current TOTP code of the secret, so an administrator who lost the password
+// of the account can still authenticate without using the recovery KMS.
+func (r *Repository) GenerateRecoveryCodes(ctx context.Context, scopeId, accountId string, count int) (*RecoveryCodes, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("generate recovery codes: no scope id: %w", errors.ErrInvalidParameter)
+	}
+	if accountId == "" {
+		return nil, fmt.Errorf("generate recovery codes: no account id: %w", errors.ErrInvalidParameter)
+	}
+	if count <= 0 {
+		count = DefaultRecoveryCodeCount
+	}
+	acct, err := r.LookupAccount(ctx, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("generate recovery codes: lookup account: %w", err)
+	}
+	if acct == nil {
+		return nil, fmt.Errorf("generate recovery codes: account %s not found: %w", accountId, errors.ErrRecordNotFound)
+	}
+	var inScope []*Account
+	if err := r.reader.SearchWhere(ctx, &inScope, accountInScopeWhere, []interface{}{acct.AuthMethodId, acct.LoginName, scopeId}); err != nil {
+		return nil, fmt.Errorf("generate recovery codes: lookup account: %w", err)
+	}
+	if len(inScope) == 0 {
+		return nil, fmt.Errorf("generate recovery codes: account %s not found in scope %s: %w", accountId, scopeId, errors.ErrRecordNotFound)
+	}
+
+	ret := &RecoveryCodes{Codes: make([]string, 0, count)}
+	stored := make([]interface{}, 0, count)
+	for i := 0; i < count; i++ {
+		code, rc, err := newRecoveryCode(accountId)
+		if err != nil {
+			return nil, fmt.Errorf("generate recovery codes: %w", err)
+		}
+		ret.Codes = append(ret.Codes, code)
+		stored = append(stored, rc)
+	}
+	totp, err := newRecoveryTotp(scopeId, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("generate recovery codes: %w", err)
+	}
+	ret.TotpSecret = totp.encodedSecret()
+	ret.TotpUrl = totp.url(acct.LoginName)
+
+	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			if _, err := w.Exec(ctx, deleteRecoveryCodesQuery, []interface{}{accountId}); err != nil {
+				return fmt.Errorf("unable to delete previous codes: %w", err)
+			}
+			if _, err := w.Exec(ctx, deleteRecoveryTotpQuery, []interface{}{accountId}); err != nil {
+				return fmt.Errorf("unable to delete previous totp secret: %w", err)
+			}
+			// the secret is encrypted and its key id recorded by the create
+			if err := w.Create(ctx, totp, db.WithFieldWrapper(r.kms.DatabaseFieldWrapper())); err != nil {
+				return fmt.Errorf("unable to store totp secret: %w", err)
+			}
+			return w.CreateItems(ctx, stored)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("generate recovery codes: %w", err)
+	}
+	return ret, nil
+}
+
+// AuthenticateWithRecoveryCode authenticates loginName of authMethodId, an
+// auth method in scopeId, with one of its unused recovery codes and a current
+// TOTP code of its recovery TOTP secret. The recovery code and the TOTP code
+// are then used and can not authenticate again. It returns the account if
+// both codes are valid. Neither code is used unless both are valid.
+//
+// Returns nil, nil if the account does not exist in the scope, it or its user
+// is disabled, or either code is not valid.
+func (r *Repository) AuthenticateWithRecoveryCode(ctx context.Context, scopeId, authMethodId, loginName, code, totpCode string) (*Account, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("recovery code authenticate: no scopeId: %w", errors.ErrInvalidParameter)
+	}
+	if authMethodId == "" {
+		return nil, fmt.Errorf("recovery code authenticate: no authMethodId: %w", errors.ErrInvalidParameter)
+	}
+	if loginName == "" {
+		return nil, fmt.Errorf("recovery code authenticate: no loginName: %w", errors.ErrInvalidParameter)
+	}
+	if code == "" {
+		return nil, fmt.Errorf("recovery code authenticate: no recovery code: %w", errors.ErrInvalidParameter)
+	}
+	if totpCode == "" {
+		return nil, fmt.Errorf("recovery code authenticate: no totp code: %w", errors.ErrInvalidParameter)
+	}
+
+	var acct *Account
+	_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(read db.Reader, w db.Writer) error {
+			acct = nil
+			var accts []*Account
+			// Disabled accounts are not found, so their codes are not used
+			if err := read.SearchWhere(ctx, &accts, accountInScopeWhere+"and "+enabledAccountWhere, []interface{}{authMethodId, loginName, scopeId}); err != nil {
+				return fmt.Errorf("lookup account: %w", err)
+			}
+			if len(accts) == 0 {
+				return nil
+			}
+			found := accts[0]
+
+			totp := &recoveryTotp{scopeId: scopeId}
+			if err := read.LookupWhere(ctx, totp, "password_account_id = ?", found.PublicId); err != nil {
+				if errors.IsNotFoundError(err) {
+					return nil
+				}
+				return fmt.Errorf("lookup totp secret: %w", err)
+			}
+			if err := db.DecryptFields(ctx, totp, r.kms.DatabaseFieldWrapper()); err != nil {
+				return fmt.Errorf("lookup totp secret: %w", err)
+			}
+			step, ok := totp.validate(totpCode, time.Now())
+			if !ok {
+				return nil
+			}
+
+			var codes []*recoveryCode
+			if err := read.SearchWhere(ctx, &codes, "password_account_id = ? and used_time is null", []interface{}{found.PublicId}); err != nil {
+				return fmt.Errorf("lookup codes: %w", err)
+			}
+			for _, rc := range codes {
+				if !rc.matches(code) {
+					continue
+				}
+				// The codes are only used if this call is the one using them,
+				// so concurrent attempts with the same codes can't both
+				// succeed
+				rowsUpdated, err := w.Exec(ctx, useRecoveryTotpQuery, []interface{}{step, found.PublicId})
+				if err != nil {
+					return fmt.Errorf("use totp code: %w", err)
+				}
+				if rowsUpdated != 1 {
+					return nil
+				}
+				rowsUpdated, err = w.Exec(ctx, useRecoveryCodeQuery, []interface{}{rc.PrivateId})
+				if err != nil {
+					return fmt.Errorf("use code: %w", err)
+				}
+				if rowsUpdated != 1 {
+					return errRecoveryCodeUsed
+				}
+				acct = found
+				return nil
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		if stderrors.Is(err, errRecoveryCodeUsed) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("recovery code authenticate: %w", err)
+	}
+	return acct, nil
+}
+
+// errRecoveryCodeUsed rolls back the use of a TOTP code when the recovery code
+// it was provided with was used concurrently.
+var errRecoveryCodeUsed = stderrors.New("recovery code already used")
+
+// CountRecoveryCodes returns the number of unused recovery codes of the
+// account.
+func (r *Repository) CountRecoveryCodes(ctx context.Context, accountId string) (int, error) {
+	if accountId == "" {
+		return 0, fmt.Errorf("count recovery codes: no account id: %w", errors.ErrInvalidParameter)
+	}
+	var codes []*recoveryCode
+	if err := r.reader.SearchWhere(ctx, &codes, "password_account_id = ? and used_time is null", []interface{}{accountId}, db.WithLimit(-1)); err != nil {
+		return 0, fmt.Errorf("count recovery codes: %w", err)
+	}
+	return len(codes), nil
+}
@@ -0,0 +1,268 @@
+package password
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_GenerateRecoveryCodes(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	o, _ := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	authMethod := TestAuthMethods(t, conn, o.GetPublicId(), 1)[0]
+	acct := TestAccounts(t, conn, authMethod.PublicId, 1)[0]
+
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("invalid-no-scope-id", func(t *testing.T) {
+		codes, err := repo.GenerateRecoveryCodes(ctx, "", acct.PublicId, 0)
+		assert.Truef(t, errors.Is(err, errors.ErrInvalidParameter), "unexpected error %v", err)
+		assert.Nil(t, codes)
+	})
+	t.Run("invalid-no-account-id", func(t *testing.T) {
+		codes, err := repo.GenerateRecoveryCodes(ctx, o.GetPublicId(), "", 0)
+		assert.Truef(t, errors.Is(err, errors.ErrInvalidParameter), "unexpected error %v", err)
+		assert.Nil(t, codes)
+	})
+	t.Run("account-not-found", func(t *testing.T) {
+		codes, err := repo.GenerateRecoveryCodes(ctx, o.GetPublicId(), "apw_1234567890", 0)
+		assert.Truef(t, errors.Is(err, errors.ErrRecordNotFound), "unexpected error %v", err)
+		assert.Nil(t, codes)
+	})
+	t.Run("account-in-other-scope", func(t *testing.T) {
+		codes, err := repo.GenerateRecoveryCodes(ctx, "global", acct.PublicId, 0)
+		assert.Truef(t, errors.Is(err, errors.ErrRecordNotFound), "unexpected error %v", err)
+		assert.Nil(t, codes)
+	})
+	t.Run("default-count", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		codes, err := repo.GenerateRecoveryCodes(ctx, o.GetPublicId(), acct.PublicId, 0)
+		require.NoError(err)
+		assert.Len(codes.Codes, DefaultRecoveryCodeCount)
+		seen := make(map[string]bool)
+		for _, c := range codes.Codes {
+			assert.Len(c, 14)
+			assert.Len(strings.Split(c, "-"), 3)
+			assert.False(seen[c], "duplicate code %s", c)
+			seen[c] = true
+		}
+		assert.NotEmpty(codes.TotpSecret)
+		assert.Contains(codes.TotpUrl, "secret="+codes.TotpSecret)
+		n, err := repo.CountRecoveryCodes(ctx, acct.PublicId)
+		require.NoError(err)
+		assert.Equal(DefaultRecoveryCodeCount, n)
+
+		var stored recoveryTotp
+		require.NoError(rw.LookupWhere(ctx, &stored, "password_account_id = ?", acct.PublicId))
+		assert.NotEmpty(stored.CtSecret)
+		assert.NotEmpty(stored.KeyId)
+	})
+	t.Run("replaces-previous", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		old, err := repo.GenerateRecoveryCodes(ctx, o.GetPublicId(), acct.PublicId, 3)
+		require.NoError(err)
+		codes, err := repo.GenerateRecoveryCodes(ctx, o.GetPublicId(), acct.PublicId, 2)
+		require.NoError(err)
+		assert.Len(codes.Codes, 2)
+		assert.NotEqual(old.TotpSecret, codes.TotpSecret)
+		n, err := repo.CountRecoveryCodes(ctx, acct.PublicId)
+		require.NoError(err)
+		assert.Equal(2, n)
+
+		got, err := repo.AuthenticateWithRecoveryCode(ctx, o.GetPublicId(), authMethod.PublicId, acct.LoginName, old.Codes[0], TestRecoveryTotpCode(t, codes.TotpSecret, 0))
+		require.NoError(err)
+		assert.Nil(got)
+		got, err = repo.AuthenticateWithRecoveryCode(ctx, o.GetPublicId(), authMethod.PublicId, acct.LoginName, codes.Codes[0], TestRecoveryTotpCode(t, old.TotpSecret, 0))
+		require.NoError(err)
+		assert.Nil(got)
+	})
+}
+
+func TestRepository_AuthenticateWithRecoveryCode(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	o, _ := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	authMethod := TestAuthMethods(t, conn, o.GetPublicId(), 1)[0]
+	accts := TestAccounts(t, conn, authMethod.PublicId, 2)
+	acct, other := accts[0], accts[1]
+
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	codes, err := repo.GenerateRecoveryCodes(ctx, o.GetPublicId(), acct.PublicId, 3)
+	require.NoError(t, err)
+	otherCodes, err := repo.GenerateRecoveryCodes(ctx, o.GetPublicId(), other.PublicId, 1)
+	require.NoError(t, err)
+	scopeId := o.GetPublicId()
+
+	var tests = []struct {
+		name         string
+		scopeId      string
+		authMethodId string
+		loginName    string
+		code         string
+		totpCode     string
+		want         *Account
+		wantIsErr    error
+	}{
+		{
+			name:         "invalid-no-scopeId",
+			authMethodId: authMethod.PublicId,
+			loginName:    acct.LoginName,
+			code:         codes.Codes[0],
+			totpCode:     TestRecoveryTotpCode(t, codes.TotpSecret, 0),
+			wantIsErr:    errors.ErrInvalidParameter,
+		},
+		{
+			name:      "invalid-no-authMethodId",
+			scopeId:   scopeId,
+			loginName: acct.LoginName,
+			code:      codes.Codes[0],
+			totpCode:  TestRecoveryTotpCode(t, codes.TotpSecret, 0),
+			wantIsErr: errors.ErrInvalidParameter,
+		},
+		{
+			name:         "invalid-no-loginName",
+			scopeId:      scopeId,
+			authMethodId: authMethod.PublicId,
+			code:         codes.Codes[0],
+			totpCode:     TestRecoveryTotpCode(t, codes.TotpSecret, 0),
+			wantIsErr:    errors.ErrInvalidParameter,
+		},
+		{
+			name:         "invalid-no-code",
+			scopeId:      scopeId,
+			authMethodId: authMethod.PublicId,
+			loginName:    acct.LoginName,
+			totpCode:     TestRecoveryTotpCode(t, codes.TotpSecret, 0),
+			wantIsErr:    errors.ErrInvalidParameter,
+		},
+		{
+			name:         "invalid-no-totp-code",
+			scopeId:      scopeId,
+			authMethodId: authMethod.PublicId,
+			loginName:    acct.LoginName,
+			code:         codes.Codes[0],
+			wantIsErr:    errors.ErrInvalidParameter,
+		},
+		{
+			name:         "wrong-scope",
+			scopeId:      "global",
+			authMethodId: authMethod.PublicId,
+			loginName:    acct.LoginName,
+			code:         codes.Codes[0],
+			totpCode:     TestRecoveryTotpCode(t, codes.TotpSecret, 0),
+		},
+		{
+			name:         "unknown-login-name",
+			scopeId:      scopeId,
+			authMethodId: authMethod.PublicId,
+			loginName:    "unknown",
+			code:         codes.Codes[0],
+			totpCode:     TestRecoveryTotpCode(t, codes.TotpSecret, 0),
+		},
+		{
+			name:         "wrong-code",
+			scopeId:      scopeId,
+			authMethodId: authMethod.PublicId,
+			loginName:    acct.LoginName,
+			code:         "aaaa-aaaa-aaaa",
+			totpCode:     TestRecoveryTotpCode(t, codes.TotpSecret, 0),
+		},
+		{
+			name:         "code-of-other-account",
+			scopeId:      scopeId,
+			authMethodId: authMethod.PublicId,
+			loginName:    acct.LoginName,
+			code:         otherCodes.Codes[0],
+			totpCode:     TestRecoveryTotpCode(t, codes.TotpSecret, 0),
+		},
+		{
+			name:         "totp-of-other-account",
+			scopeId:      scopeId,
+			authMethodId: authMethod.PublicId,
+			loginName:    acct.LoginName,
+			code:         codes.Codes[0],
+			totpCode:     TestRecoveryTotpCode(t, otherCodes.TotpSecret, 0),
+		},
+		{
+			name:         "expired-totp-code",
+			scopeId:      scopeId,
+			authMethodId: authMethod.PublicId,
+			loginName:    acct.LoginName,
+			code:         codes.Codes[0],
+			totpCode:     TestRecoveryTotpCode(t, codes.TotpSecret, -10),
+		},
+		{
+			name:         "valid",
+			scopeId:      scopeId,
+			authMethodId: authMethod.PublicId,
+			loginName:    acct.LoginName,
+			code:         codes.Codes[0],
+			totpCode:     TestRecoveryTotpCode(t, codes.TotpSecret, 0),
+			want:         acct,
+		},
+		{
+			name:         "already-used",
+			scopeId:      scopeId,
+			authMethodId: authMethod.PublicId,
+			loginName:    acct.LoginName,
+			code:         codes.Codes[0],
+			totpCode:     TestRecoveryTotpCode(t, codes.TotpSecret, 1),
+		},
+		{
+			name:         "replayed-totp-code",
+			scopeId:      scopeId,
+			authMethodId: authMethod.PublicId,
+			loginName:    acct.LoginName,
+			code:         codes.Codes[1],
+			totpCode:     TestRecoveryTotpCode(t, codes.TotpSecret, 0),
+		},
+		{
+			name:         "valid-uppercase-no-separators",
+			scopeId:      scopeId,
+			authMethodId: authMethod.PublicId,
+			loginName:    acct.LoginName,
+			code:         strings.ToUpper(strings.ReplaceAll(codes.Codes[1], "-", "")),
+			totpCode:     TestRecoveryTotpCode(t, codes.TotpSecret, 1),
+			want:         acct,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := repo.AuthenticateWithRecoveryCode(ctx, tt.scopeId, tt.authMethodId, tt.loginName, tt.code, tt.totpCode)
+			if tt.wantIsErr != nil {
+				assert.Truef(errors.Is(err, tt.wantIsErr), "want err: %q got: %q", tt.wantIsErr, err)
+				assert.Nil(got)
+				return
+			}
+			require.NoError(err)
+			if tt.want == nil {
+				assert.Nil(got)
+				return
+			}
+			require.NotNil(got)
+			assert.Equal(tt.want.PublicId, got.PublicId)
+		})
+	}
+
+	n, err := repo.CountRecoveryCodes(ctx, acct.PublicId)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
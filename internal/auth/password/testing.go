@@ -2,8 +2,10 @@ package password
 
 import (
 	"context"
+	"encoding/base32"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/jinzhu/gorm"
@@ -78,3 +80,14 @@ func TestAccounts(t *testing.T, conn *gorm.DB, authMethodId string, count int) [
 	}
 	return auts
 }
+
+// TestRecoveryTotpCode returns the TOTP code of the base32 encoded secret
+// returned by GenerateRecoveryCodes for the time step offset periods from
+// now.
+func TestRecoveryTotpCode(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	require.NoError(t, err)
+	step := time.Now().Unix()/int64(recoveryTotpPeriod/time.Second) + offset
+	return totpCode(key, step)
+}
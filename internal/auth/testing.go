@@ -8,5 +8,6 @@ func DisabledAuthTestContext(opt ...Option) context.Context {
 	opts := getOpts(opt...)
 	reqInfo.scopeIdOverride = opts.withScopeId
 	reqInfo.userIdOverride = opts.withUserId
+	reqInfo.accountIdOverride = opts.withAccountId
 	return NewVerifierContext(context.Background(), nil, nil, nil, nil, opts.withKms, reqInfo)
 }
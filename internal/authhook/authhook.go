@@ -0,0 +1,272 @@
+// Package authhook calls the webhooks configured to take part in
+// authentication. Pre-authenticate hooks are called after the credentials of
+// an authentication are verified and before its auth token is issued, and can
+// deny it with a reason, e.g. for an external risk engine. Post-authenticate
+// hooks are called after the token is issued, e.g. for enrichment or
+// notifications.
+//
+// Each hook has a timeout and a failure policy applied when it can't be
+// called, times out or answers with an unexpected status: with the open
+// policy the failure is ignored, with the closed policy the authentication is
+// refused. Hooks can be limited to some auth methods, so auth methods can have
+// hooks with different policies.
+//
+// Payloads are JSON Requests, signed with the secret of the hook: the
+// SignatureHeader of each call is the hex encoded HMAC-SHA256 of the value of
+// its TimestampHeader, a period and the body, prefixed with "sha256=".
+package authhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// SignatureHeader is the header of the signature of a call.
+	SignatureHeader = "X-Boundary-Signature"
+
+	// TimestampHeader is the header of the time of a call, in seconds since
+	// the Unix epoch, which is included in its signature so calls can't be
+	// replayed later.
+	TimestampHeader = "X-Boundary-Timestamp"
+
+	// DefaultTimeout is the timeout of hooks which don't set one.
+	DefaultTimeout = 5 * time.Second
+
+	// maxResponseSize is the most bytes of the body of an answer read.
+	maxResponseSize = 64 * 1024
+)
+
+// A Stage is when a hook is called during authentication.
+type Stage string
+
+const (
+	// PreAuthenticate hooks are called before the auth token is issued.
+	PreAuthenticate Stage = "pre-authenticate"
+
+	// PostAuthenticate hooks are called after the auth token is issued.
+	PostAuthenticate Stage = "post-authenticate"
+)
+
+// A FailurePolicy is what happens to an authentication when a hook fails.
+type FailurePolicy string
+
+const (
+	// FailOpen ignores the failure of the hook.
+	FailOpen FailurePolicy = "open"
+
+	// FailClosed refuses the authentication.
+	FailClosed FailurePolicy = "closed"
+)
+
+// A Hook is a webhook called during authentication.
+type Hook struct {
+	Stage Stage
+	Url   string
+
+	// Secret signs the payloads sent to the hook.
+	Secret string
+
+	// AuthMethodIds limits the hook to the authentications with these auth
+	// methods. The hook is called for all auth methods if empty.
+	AuthMethodIds []string
+
+	// Timeout is DefaultTimeout if zero.
+	Timeout time.Duration
+
+	// FailurePolicy is FailOpen if empty.
+	FailurePolicy FailurePolicy
+}
+
+// A Request is the payload sent to hooks.
+type Request struct {
+	Stage        Stage     `json:"stage"`
+	ScopeId      string    `json:"scope_id"`
+	AuthMethodId string    `json:"auth_method_id"`
+	AccountId    string    `json:"account_id"`
+	LoginName    string    `json:"login_name"`
+	UserId       string    `json:"user_id"`
+	AuthTokenId  string    `json:"auth_token_id,omitempty"`
+	Time         time.Time `json:"time"`
+}
+
+// A Response is the answer of a pre-authenticate hook. A 2xx answer without a
+// body allows the authentication.
+type Response struct {
+	// Deny refuses the authentication.
+	Deny bool `json:"deny"`
+
+	// Reason is returned to the client of a denied authentication.
+	Reason string `json:"reason"`
+}
+
+// DeniedError is returned for authentications refused by a hook.
+type DeniedError struct {
+	// Reason is the reason given by the hook, or a description of its failure
+	// if it failed with the closed policy.
+	Reason string
+}
+
+// Error returns the reason of the denial.
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("authentication denied: %s", e.Reason)
+}
+
+// Runner calls the hooks of each stage of authentication.
+type Runner struct {
+	hooks  []*Hook
+	client *http.Client
+	logger hclog.Logger
+}
+
+// NewRunner returns a Runner calling the hooks, in the order given, with the
+// client, or with http.DefaultClient if nil.
+func NewRunner(hooks []*Hook, client *http.Client, logger hclog.Logger) (*Runner, error) {
+	if logger == nil {
+		return nil, fmt.Errorf("new auth hook runner: missing logger: %w", errors.ErrInvalidParameter)
+	}
+	for _, h := range hooks {
+		switch h.Stage {
+		case PreAuthenticate, PostAuthenticate:
+		default:
+			return nil, fmt.Errorf("new auth hook runner: unknown stage %q: %w", h.Stage, errors.ErrInvalidParameter)
+		}
+		switch h.FailurePolicy {
+		case "", FailOpen, FailClosed:
+		default:
+			return nil, fmt.Errorf("new auth hook runner: unknown failure policy %q: %w", h.FailurePolicy, errors.ErrInvalidParameter)
+		}
+		if h.Url == "" {
+			return nil, fmt.Errorf("new auth hook runner: missing url: %w", errors.ErrInvalidParameter)
+		}
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Runner{hooks: hooks, client: client, logger: logger}, nil
+}
+
+// Run calls the hooks of the stage of req which apply to its auth method, in
+// order, setting its Stage and its Time if zero. It returns a DeniedError if a
+// pre-authenticate hook denies the authentication, or if a hook with the
+// closed policy fails. The remaining hooks are then not called. A nil Runner
+// has no hooks.
+func (r *Runner) Run(ctx context.Context, stage Stage, req *Request) error {
+	if r == nil {
+		return nil
+	}
+	if req == nil {
+		return fmt.Errorf("run auth hooks: missing request: %w", errors.ErrInvalidParameter)
+	}
+	req.Stage = stage
+	if req.Time.IsZero() {
+		req.Time = time.Now()
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("run auth hooks: %w", err)
+	}
+	for _, h := range r.hooks {
+		if h.Stage != stage || !h.appliesTo(req.AuthMethodId) {
+			continue
+		}
+		resp, err := r.call(ctx, h, body)
+		if err != nil {
+			r.logger.Warn("auth hook failed", "stage", stage, "url", h.Url, "auth_method_id", req.AuthMethodId, "failure_policy", h.failurePolicy(), "error", err)
+			if h.failurePolicy() == FailClosed {
+				return &DeniedError{Reason: "authentication hook failed"}
+			}
+			continue
+		}
+		if stage == PreAuthenticate && resp.Deny {
+			reason := resp.Reason
+			if reason == "" {
+				reason = "denied by authentication hook"
+			}
+			return &DeniedError{Reason: reason}
+		}
+	}
+	return nil
+}
+
+// call sends the signed body to the hook and returns its answer.
+func (r *Runner) call(ctx context.Context, h *Hook, body []byte) (*Response, error) {
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, h.Url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(TimestampHeader, ts)
+	httpReq.Header.Set(SignatureHeader, Sign(h.Secret, ts, body))
+
+	httpResp, err := r.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %d", httpResp.StatusCode)
+	}
+	respBody, err := ioutil.ReadAll(io.LimitReader(httpResp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return resp, nil
+	}
+	if err := json.Unmarshal(respBody, resp); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return resp, nil
+}
+
+// Sign returns the signature of a call at the timestamp ts with the body,
+// for the value of the SignatureHeader.
+func Sign(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (h *Hook) appliesTo(authMethodId string) bool {
+	if len(h.AuthMethodIds) == 0 {
+		return true
+	}
+	for _, id := range h.AuthMethodIds {
+		if id == authMethodId {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Hook) failurePolicy() FailurePolicy {
+	if h.FailurePolicy == "" {
+		return FailOpen
+	}
+	return h.FailurePolicy
+}
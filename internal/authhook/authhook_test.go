@@ -0,0 +1,138 @@
+package authhook
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner_Run(t *testing.T) {
+	t.Parallel()
+	const secret = "secret"
+
+	// hookServer answers calls with the status and body, recording the
+	// payloads of the calls with a valid signature
+	hookServer := func(t *testing.T, status int, body string, delay time.Duration) (*httptest.Server, *[]*Request) {
+		t.Helper()
+		var got []*Request
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, Sign(secret, r.Header.Get(TimestampHeader), b), r.Header.Get(SignatureHeader))
+			req := new(Request)
+			require.NoError(t, json.Unmarshal(b, req))
+			got = append(got, req)
+			time.Sleep(delay)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}))
+		t.Cleanup(srv.Close)
+		return srv, &got
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		srv, got := hookServer(t, http.StatusOK, "", 0)
+		r, err := NewRunner([]*Hook{{Stage: PreAuthenticate, Url: srv.URL, Secret: secret}}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		require.NoError(r.Run(context.Background(), PreAuthenticate, &Request{AuthMethodId: "ampw_1", UserId: "u_1"}))
+		require.Len(*got, 1)
+		assert.Equal(PreAuthenticate, (*got)[0].Stage)
+		assert.Equal("u_1", (*got)[0].UserId)
+		assert.False((*got)[0].Time.IsZero())
+	})
+	t.Run("denied", func(t *testing.T) {
+		require := require.New(t)
+		srv, _ := hookServer(t, http.StatusOK, `{"deny": true, "reason": "too risky"}`, 0)
+		r, err := NewRunner([]*Hook{{Stage: PreAuthenticate, Url: srv.URL, Secret: secret}}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		err = r.Run(context.Background(), PreAuthenticate, &Request{AuthMethodId: "ampw_1"})
+		var denied *DeniedError
+		require.True(stderrors.As(err, &denied))
+		require.Equal("too risky", denied.Reason)
+	})
+	t.Run("post-cannot-deny", func(t *testing.T) {
+		require := require.New(t)
+		srv, got := hookServer(t, http.StatusOK, `{"deny": true}`, 0)
+		r, err := NewRunner([]*Hook{{Stage: PostAuthenticate, Url: srv.URL, Secret: secret}}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		require.NoError(r.Run(context.Background(), PostAuthenticate, &Request{AuthMethodId: "ampw_1"}))
+		require.Len(*got, 1)
+	})
+	t.Run("other-stage-and-auth-method", func(t *testing.T) {
+		require := require.New(t)
+		srv, got := hookServer(t, http.StatusOK, "", 0)
+		r, err := NewRunner([]*Hook{
+			{Stage: PostAuthenticate, Url: srv.URL, Secret: secret},
+			{Stage: PreAuthenticate, Url: srv.URL, Secret: secret, AuthMethodIds: []string{"ampw_2"}},
+		}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		require.NoError(r.Run(context.Background(), PreAuthenticate, &Request{AuthMethodId: "ampw_1"}))
+		require.Empty(*got)
+	})
+	t.Run("failure-policies", func(t *testing.T) {
+		srv, _ := hookServer(t, http.StatusInternalServerError, "", 0)
+		slow, _ := hookServer(t, http.StatusOK, "", 200*time.Millisecond)
+		tests := []struct {
+			name    string
+			hook    *Hook
+			wantErr bool
+		}{
+			{name: "status-open", hook: &Hook{Url: srv.URL}},
+			{name: "status-closed", hook: &Hook{Url: srv.URL, FailurePolicy: FailClosed}, wantErr: true},
+			{name: "timeout-open", hook: &Hook{Url: slow.URL, Timeout: 10 * time.Millisecond, FailurePolicy: FailOpen}},
+			{name: "timeout-closed", hook: &Hook{Url: slow.URL, Timeout: 10 * time.Millisecond, FailurePolicy: FailClosed}, wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				require := require.New(t)
+				for _, stage := range []Stage{PreAuthenticate, PostAuthenticate} {
+					hook := *tt.hook
+					hook.Stage, hook.Secret = stage, secret
+					r, err := NewRunner([]*Hook{&hook}, nil, hclog.NewNullLogger())
+					require.NoError(err)
+					err = r.Run(context.Background(), stage, &Request{AuthMethodId: "ampw_1"})
+					if tt.wantErr {
+						var denied *DeniedError
+						require.True(stderrors.As(err, &denied))
+						continue
+					}
+					require.NoError(err)
+				}
+			})
+		}
+	})
+	t.Run("nil-runner", func(t *testing.T) {
+		var r *Runner
+		require.NoError(t, r.Run(context.Background(), PreAuthenticate, &Request{}))
+	})
+}
+
+func TestNewRunner(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		hook *Hook
+	}{
+		{name: "unknown-stage", hook: &Hook{Stage: "during", Url: "http://127.0.0.1"}},
+		{name: "unknown-policy", hook: &Hook{Stage: PreAuthenticate, Url: "http://127.0.0.1", FailurePolicy: "ajar"}},
+		{name: "missing-url", hook: &Hook{Stage: PreAuthenticate}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRunner([]*Hook{tt.hook}, nil, hclog.NewNullLogger())
+			require.Error(t, err)
+		})
+	}
+	_, err := NewRunner(nil, nil, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,142 @@
+package authtoken
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+)
+
+const defaultAttenuationTableName = "auth_token_attenuation"
+
+// A Preset restricts the actions an auth token issued by IssueScopedAuthToken
+// may perform, whatever its grants allow.
+type Preset string
+
+const (
+	// TerraformPreset allows managing resources but not authorizing sessions
+	// or acting on sessions, so the token of a provisioning pipeline can't be
+	// used to connect to targets.
+	TerraformPreset Preset = "terraform"
+
+	// ReadOnlyPreset only allows reading and listing resources.
+	ReadOnlyPreset Preset = "read-only"
+)
+
+// Presets are all presets.
+var Presets = []Preset{TerraformPreset, ReadOnlyPreset}
+
+func validPreset(p Preset) bool {
+	for _, v := range Presets {
+		if p == v {
+			return true
+		}
+	}
+	return false
+}
+
+// An Attenuation holds the restrictions of an auth token derived from a parent
+// auth token by ExchangeAuthToken or IssueScopedAuthToken. Auth tokens which
+// were not derived have no Attenuation.
+type Attenuation struct {
+	AuthTokenId string `gorm:"primary_key"`
+	ParentId    string
+	// Grants is the json encoded list of grants the token is limited to.
+	Grants   string
+	TargetId string
+	// Preset is the Preset restricting the actions of the token, if any.
+	Preset     string
+	CreateTime *timestamp.Timestamp `gorm:"default:current_timestamp"`
+
+	grants []perms.GrantPair `gorm:"-"`
+}
+
+func newAttenuation(authTokenId, parentId string, grants []perms.GrantPair, targetId string, preset Preset) (*Attenuation, error) {
+	if grants == nil {
+		grants = []perms.GrantPair{}
+	}
+	enc, err := json.Marshal(grants)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode grants: %w", err)
+	}
+	return &Attenuation{
+		AuthTokenId: authTokenId,
+		ParentId:    parentId,
+		Grants:      string(enc),
+		TargetId:    targetId,
+		Preset:      string(preset),
+		grants:      grants,
+	}, nil
+}
+
+// TableName returns the table name for the attenuation.
+func (a *Attenuation) TableName() string {
+	return defaultAttenuationTableName
+}
+
+// GrantPairs returns the grants the token is limited to.
+func (a *Attenuation) GrantPairs() ([]perms.GrantPair, error) {
+	if a.grants != nil {
+		return a.grants, nil
+	}
+	var grants []perms.GrantPair
+	if err := json.Unmarshal([]byte(a.Grants), &grants); err != nil {
+		return nil, fmt.Errorf("unable to decode grants: %w", err)
+	}
+	a.grants = grants
+	return grants, nil
+}
+
+// Filter returns the grants which are both in grants and in the grants the
+// token is limited to. Grants removed from the user since the token was
+// derived are therefore not regained. A nil Attenuation returns grants
+// unchanged.
+func (a *Attenuation) Filter(grants []perms.GrantPair) ([]perms.GrantPair, error) {
+	if a == nil {
+		return grants, nil
+	}
+	limit, err := a.GrantPairs()
+	if err != nil {
+		return nil, err
+	}
+	allowed := make(map[perms.GrantPair]bool, len(limit))
+	for _, g := range limit {
+		allowed[g] = true
+	}
+	var ret []perms.GrantPair
+	for _, g := range grants {
+		if allowed[g] {
+			ret = append(ret, g)
+		}
+	}
+	return ret, nil
+}
+
+// AllowsResource returns false if the token is restricted to a target and the
+// resource is not that target. A nil Attenuation allows any resource.
+func (a *Attenuation) AllowsResource(r perms.Resource) bool {
+	if a == nil || a.TargetId == "" {
+		return true
+	}
+	return r.Type == resource.Target && r.Id == a.TargetId
+}
+
+// AllowsAction returns false if the token is restricted by a preset which
+// doesn't allow the action on the resource. Unknown presets allow no action. A
+// nil Attenuation allows any action.
+func (a *Attenuation) AllowsAction(r perms.Resource, act action.Type) bool {
+	if a == nil || a.Preset == "" {
+		return true
+	}
+	switch Preset(a.Preset) {
+	case TerraformPreset:
+		return r.Type != resource.Session && act != action.AuthorizeSession
+	case ReadOnlyPreset:
+		return act == action.Read || act == action.ReadSelf || act == action.List
+	default:
+		return false
+	}
+}
@@ -59,6 +59,33 @@ func (s *AuthToken) toWritableAuthToken() *writableAuthToken {
 	}
 }
 
+var (
+	_ db.FieldEncrypter = (*writableAuthToken)(nil)
+	_ db.FieldEncrypter = (*AuthToken)(nil)
+)
+
+// SetKeyId sets the id of the key used to encrypt the auth token's value.
+func (s *writableAuthToken) SetKeyId(keyId string) {
+	s.KeyId = keyId
+}
+
+// WrappedFields returns the stored auth token, whose value is encrypted. It
+// satisfies the db.FieldEncrypter interface.
+func (s *writableAuthToken) WrappedFields() interface{} {
+	return s.AuthToken
+}
+
+// SetKeyId sets the id of the key used to encrypt the auth token's value.
+func (s *AuthToken) SetKeyId(keyId string) {
+	s.KeyId = keyId
+}
+
+// WrappedFields returns the stored auth token, whose value is encrypted. It
+// satisfies the db.FieldEncrypter interface.
+func (s *AuthToken) WrappedFields() interface{} {
+	return s.AuthToken
+}
+
 // encrypt the entry's data using the provided cipher (wrapping.Wrapper)
 func (s *writableAuthToken) encrypt(ctx context.Context, cipher wrapping.Wrapper) error {
 	// structwrapping doesn't support embedding, so we'll pass in the store.Entry directly
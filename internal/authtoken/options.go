@@ -9,6 +9,7 @@ import (
 var (
 	defaultTokenTimeToLiveDuration  = 7 * 24 * time.Hour
 	defaultTokenTimeToStaleDuration = 24 * time.Hour
+	defaultTokenMaxLifetimeDuration = 30 * 24 * time.Hour
 )
 
 // getOpts - iterate the inbound Options and return a struct
@@ -28,7 +29,10 @@ type options struct {
 	withTokenValue               bool
 	withTokenTimeToLiveDuration  time.Duration
 	withTokenTimeToStaleDuration time.Duration
+	withTokenMaxLifetimeDuration time.Duration
 	withLimit                    int
+	withTargetId                 string
+	withReadOnly                 bool
 }
 
 func getDefaultOptions() options {
@@ -36,6 +40,7 @@ func getDefaultOptions() options {
 		withLimit:                    db.DefaultLimit,
 		withTokenTimeToLiveDuration:  defaultTokenTimeToLiveDuration,
 		withTokenTimeToStaleDuration: defaultTokenTimeToStaleDuration,
+		withTokenMaxLifetimeDuration: defaultTokenMaxLifetimeDuration,
 	}
 }
 
@@ -65,6 +70,16 @@ func WithTokenTimeToStaleDuration(dur time.Duration) Option {
 	}
 }
 
+// WithTokenMaxLifetimeDuration allows setting how long after authenticating
+// auth tokens can still be refreshed.
+func WithTokenMaxLifetimeDuration(dur time.Duration) Option {
+	return func(o *options) {
+		if dur > 0 {
+			o.withTokenMaxLifetimeDuration = dur
+		}
+	}
+}
+
 // WithLimit provides an option to provide a limit.  Intentionally allowing
 // negative integers.   If WithLimit < 0, then unlimited results are returned.
 // If WithLimit == 0, then default limits are used for results.
@@ -75,3 +90,20 @@ func WithLimit(limit int) Option {
 		}
 	}
 }
+
+// WithTargetId provides an option to restrict an auth token derived by
+// ExchangeAuthToken to a target.
+func WithTargetId(id string) Option {
+	return func(o *options) {
+		o.withTargetId = id
+	}
+}
+
+// WithReadOnly provides an option to keep ValidateToken from writing to the
+// database, e.g. when it is a standby. Expired and stale tokens are then not
+// deleted and the approximate last access times of tokens are not updated.
+func WithReadOnly(enable bool) Option {
+	return func(o *options) {
+		o.withReadOnly = enable
+	}
+}
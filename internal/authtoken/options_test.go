@@ -34,4 +34,28 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withTokenTimeToStaleDuration = 1 * time.Hour
 		assert.Equal(opts, testOpts)
 	})
+
+	t.Run("WithTokenMaxLifetimeDuration", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithTokenMaxLifetimeDuration(1 * time.Hour))
+		testOpts := getDefaultOptions()
+		testOpts.withTokenMaxLifetimeDuration = 1 * time.Hour
+		assert.Equal(opts, testOpts)
+	})
+
+	t.Run("WithTargetId", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithTargetId("ttcp_1234567890"))
+		testOpts := getDefaultOptions()
+		testOpts.withTargetId = "ttcp_1234567890"
+		assert.Equal(opts, testOpts)
+	})
+
+	t.Run("WithReadOnly", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithReadOnly(true))
+		testOpts := getDefaultOptions()
+		testOpts.withReadOnly = true
+		assert.Equal(opts, testOpts)
+	})
 }
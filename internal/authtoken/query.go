@@ -0,0 +1,33 @@
+package authtoken
+
+const (
+	// latestRefreshQuery returns the id of the unexpired auth token with the
+	// latest expiration among the auth token with the id $1 and the auth
+	// tokens refreshed from it, directly or not.
+	latestRefreshQuery = `
+with recursive refreshed(auth_token_id) as (
+  select $1::text
+  union
+  select r.auth_token_id
+    from auth_token_refresh r
+    join refreshed
+      on r.refreshed_from_id = refreshed.auth_token_id
+)
+select at.public_id
+  from auth_token at
+  join refreshed
+    on at.public_id = refreshed.auth_token_id
+ where at.expiration_time > now()
+ order by at.expiration_time desc
+ limit 1;
+`
+
+	// recordLoginQuery records a login of a user with an account, updating
+	// the last login time if the user logged in with the account before.
+	recordLoginQuery = `
+insert into iam_user_login (iam_user_id, auth_account_id, last_login_time)
+values (?, ?, current_timestamp)
+on conflict (iam_user_id, auth_account_id) do update
+  set last_login_time = excluded.last_login_time;
+`
+)
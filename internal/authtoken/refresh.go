@@ -0,0 +1,22 @@
+package authtoken
+
+import (
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+)
+
+const defaultRefreshTableName = "auth_token_refresh"
+
+// A refresh records the login time of an auth token issued by
+// RefreshAuthToken. Auth tokens issued by authenticating have no refresh and
+// their login time is their create time.
+type refresh struct {
+	AuthTokenId     string `gorm:"primary_key"`
+	RefreshedFromId string
+	LoginTime       *timestamp.Timestamp
+	CreateTime      *timestamp.Timestamp `gorm:"default:current_timestamp"`
+}
+
+// TableName returns the table name for the refresh.
+func (r *refresh) TableName() string {
+	return defaultRefreshTableName
+}
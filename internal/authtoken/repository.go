@@ -12,6 +12,8 @@ import (
 	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/securityevent"
 )
 
 var (
@@ -28,6 +30,8 @@ type Repository struct {
 	limit               int
 	timeToLiveDuration  time.Duration
 	timeToStaleDuration time.Duration
+	maxLifetimeDuration time.Duration
+	readOnly            bool
 }
 
 // NewRepository creates a new Repository. The returned repository is not safe for concurrent go
@@ -51,12 +55,15 @@ func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repo
 		limit:               opts.withLimit,
 		timeToLiveDuration:  opts.withTokenTimeToLiveDuration,
 		timeToStaleDuration: opts.withTokenTimeToStaleDuration,
+		maxLifetimeDuration: opts.withTokenMaxLifetimeDuration,
+		readOnly:            opts.withReadOnly,
 	}, nil
 }
 
 // CreateAuthToken inserts an Auth Token into the repository and returns a new Auth Token.  The returned auth token
 // contains the auth token value. The provided IAM User ID must be associated to the provided auth account id
-// or an error will be returned. All options are ignored.
+// or an error will be returned. Creating the token records a login of the user with the account. All options are
+// ignored.
 func (r *Repository) CreateAuthToken(ctx context.Context, withIamUser *iam.User, withAuthAccountId string, opt ...Option) (*AuthToken, error) {
 	if withIamUser == nil {
 		return nil, fmt.Errorf("create: auth token: no user: %w", errors.ErrInvalidParameter)
@@ -83,11 +90,6 @@ func (r *Repository) CreateAuthToken(ctx context.Context, withIamUser *iam.User,
 	}
 	at.Token = token
 
-	databaseWrapper, err := r.kms.GetWrapper(ctx, withIamUser.GetScopeId(), kms.KeyPurposeDatabase)
-	if err != nil {
-		return nil, fmt.Errorf("create: unable to get database wrapper: %w", err)
-	}
-
 	// We truncate the expiration time to the nearest second to make testing in different platforms with
 	// different time resolutions easier.
 	expiration, err := ptypes.TimestampProto(time.Now().Add(r.timeToLiveDuration).Truncate(time.Second))
@@ -115,15 +117,16 @@ func (r *Repository) CreateAuthToken(ctx context.Context, withIamUser *iam.User,
 			at.IamUserId = acct.GetIamUserId()
 
 			newAuthToken = at.toWritableAuthToken()
-			if err := newAuthToken.encrypt(ctx, databaseWrapper); err != nil {
-				return err
-			}
 			// tokens are not replicated, so they don't need oplog entries.
-			if err := w.Create(ctx, newAuthToken); err != nil {
+			// The token is encrypted and its key id recorded by the create.
+			if err := w.Create(ctx, newAuthToken, db.WithFieldWrapper(r.kms.DatabaseFieldWrapper())); err != nil {
 				return err
 			}
 			newAuthToken.CtToken = nil
 
+			if _, err := w.Exec(ctx, recordLoginQuery, []interface{}{at.IamUserId, at.AuthAccountId}); err != nil {
+				return fmt.Errorf("record login: %w", err)
+			}
 			return nil
 		},
 	)
@@ -134,6 +137,335 @@ func (r *Repository) CreateAuthToken(ctx context.Context, withIamUser *iam.User,
 	return newAuthToken.toAuthToken(), nil
 }
 
+// ExchangeAuthToken creates an auth token derived from the parent auth token
+// with the provided id, for the same user and account. The derived token is
+// limited to the provided grants, which must all currently apply to the parent
+// token, and expires after ttl or when the parent expires, whichever is sooner.
+// A ttl <= 0 uses the repository's time-to-live. The derived token is deleted
+// when its parent is deleted. If the parent is restricted to a target or by a
+// preset the derived token is restricted the same way. Supports the
+// WithTargetId option to restrict the derived token to a target. The returned
+// auth token contains the auth token value.
+func (r *Repository) ExchangeAuthToken(ctx context.Context, parentId string, grants []perms.GrantPair, ttl time.Duration, opt ...Option) (*AuthToken, error) {
+	if parentId == "" {
+		return nil, fmt.Errorf("exchange: auth token: missing parent id: %w", errors.ErrInvalidParameter)
+	}
+	opts := getOpts(opt...)
+	selectGrants := func(parentGrants []perms.GrantPair) ([]perms.GrantPair, error) {
+		held := make(map[perms.GrantPair]bool, len(parentGrants))
+		for _, g := range parentGrants {
+			held[g] = true
+		}
+		for _, g := range grants {
+			if !held[g] {
+				return nil, fmt.Errorf("grant %q in scope %s is not held by the parent: %w", g.Grant, g.ScopeId, errors.ErrInvalidParameter)
+			}
+		}
+		return grants, nil
+	}
+	return r.deriveAuthToken(ctx, "exchange", parentId, selectGrants, ttl, opts.withTargetId, "")
+}
+
+// IssueScopedAuthToken creates an auth token derived from the parent auth
+// token with the provided id, like ExchangeAuthToken, keeping the grants of
+// the parent in the provided scopes and restricted by the preset, so it can
+// only act on resources of those scopes and only perform the actions the
+// preset allows. The parent must hold grants in at least one of the scopes,
+// and if it is restricted by a preset it must be the same. The returned auth
+// token contains the auth token value. All options are ignored.
+func (r *Repository) IssueScopedAuthToken(ctx context.Context, parentId string, preset Preset, scopeIds []string, ttl time.Duration, opt ...Option) (*AuthToken, error) {
+	if parentId == "" {
+		return nil, fmt.Errorf("issue scoped: auth token: missing parent id: %w", errors.ErrInvalidParameter)
+	}
+	if !validPreset(preset) {
+		return nil, fmt.Errorf("issue scoped: auth token: unknown preset %q: %w", preset, errors.ErrInvalidParameter)
+	}
+	if len(scopeIds) == 0 {
+		return nil, fmt.Errorf("issue scoped: auth token: missing scope ids: %w", errors.ErrInvalidParameter)
+	}
+	inScopes := make(map[string]bool, len(scopeIds))
+	for _, id := range scopeIds {
+		inScopes[id] = true
+	}
+	selectGrants := func(parentGrants []perms.GrantPair) ([]perms.GrantPair, error) {
+		var grants []perms.GrantPair
+		for _, g := range parentGrants {
+			if inScopes[g.ScopeId] {
+				grants = append(grants, g)
+			}
+		}
+		if len(grants) == 0 {
+			return nil, fmt.Errorf("parent holds no grants in the scopes: %w", errors.ErrInvalidParameter)
+		}
+		return grants, nil
+	}
+	return r.deriveAuthToken(ctx, "issue scoped", parentId, selectGrants, ttl, "", preset)
+}
+
+// deriveAuthToken creates an auth token derived from the parent auth token
+// with the provided id, limited to the grants returned by selectGrants from
+// the grants of the parent, to the target if not empty and to the preset if
+// not empty. op prefixes the errors returned.
+func (r *Repository) deriveAuthToken(ctx context.Context, op, parentId string, selectGrants func([]perms.GrantPair) ([]perms.GrantPair, error), ttl time.Duration, targetId string, preset Preset) (*AuthToken, error) {
+	parent, err := r.LookupAuthToken(ctx, parentId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: auth token: parent lookup: %w", op, err)
+	}
+	if parent == nil {
+		return nil, fmt.Errorf("%s: auth token: parent %s: %w", op, parentId, errors.ErrRecordNotFound)
+	}
+	parentAtt, err := r.LookupAttenuation(ctx, parentId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: auth token: %w", op, err)
+	}
+
+	if parentAtt != nil && parentAtt.TargetId != "" {
+		switch targetId {
+		case "":
+			targetId = parentAtt.TargetId
+		case parentAtt.TargetId:
+		default:
+			return nil, fmt.Errorf("%s: auth token: parent is restricted to target %s: %w", op, parentAtt.TargetId, errors.ErrInvalidParameter)
+		}
+	}
+	if parentAtt != nil && parentAtt.Preset != "" {
+		switch preset {
+		case "":
+			preset = Preset(parentAtt.Preset)
+		case Preset(parentAtt.Preset):
+		default:
+			return nil, fmt.Errorf("%s: auth token: parent is restricted by preset %s: %w", op, parentAtt.Preset, errors.ErrInvalidParameter)
+		}
+	}
+
+	iamRepo, err := iam.NewRepository(r.reader, r.writer, r.kms)
+	if err != nil {
+		return nil, fmt.Errorf("%s: auth token: %w", op, err)
+	}
+	userGrants, err := iamRepo.GrantsForUser(ctx, parent.GetIamUserId())
+	if err != nil {
+		return nil, fmt.Errorf("%s: auth token: %w", op, err)
+	}
+	parentGrants, err := parentAtt.Filter(userGrants)
+	if err != nil {
+		return nil, fmt.Errorf("%s: auth token: parent grants: %w", op, err)
+	}
+	grants, err := selectGrants(parentGrants)
+	if err != nil {
+		return nil, fmt.Errorf("%s: auth token: %w", op, err)
+	}
+
+	if ttl <= 0 {
+		ttl = r.timeToLiveDuration
+	}
+	parentExp, err := ptypes.Timestamp(parent.GetExpirationTime().GetTimestamp())
+	if err != nil {
+		return nil, fmt.Errorf("%s: auth token: parent expiration time: %w", op, err)
+	}
+	exp := time.Now().Add(ttl).Truncate(time.Second)
+	if exp.After(parentExp) {
+		exp = parentExp
+	}
+	expiration, err := ptypes.TimestampProto(exp)
+	if err != nil {
+		return nil, err
+	}
+
+	at := allocAuthToken()
+	at.AuthAccountId = parent.GetAuthAccountId()
+	at.ExpirationTime = &timestamp.Timestamp{Timestamp: expiration}
+	if at.PublicId, err = newAuthTokenId(); err != nil {
+		return nil, fmt.Errorf("%s: auth token id: %w", op, err)
+	}
+	if at.Token, err = newAuthToken(); err != nil {
+		return nil, fmt.Errorf("%s: auth token value: %w", op, err)
+	}
+	att, err := newAttenuation(at.PublicId, parentId, grants, targetId, preset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: auth token: %w", op, err)
+	}
+
+	at.ScopeId = parent.GetScopeId()
+
+	var newAuthToken *writableAuthToken
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			newAuthToken = at.toWritableAuthToken()
+			// tokens are not replicated, so they don't need oplog entries.
+			if err := w.Create(ctx, newAuthToken, db.WithFieldWrapper(r.kms.DatabaseFieldWrapper())); err != nil {
+				return err
+			}
+			if err := w.Create(ctx, att); err != nil {
+				return err
+			}
+			newAuthToken.CtToken = nil
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: auth token: %w", op, err)
+	}
+	ret := newAuthToken.toAuthToken()
+	ret.ScopeId = parent.GetScopeId()
+	ret.AuthMethodId = parent.GetAuthMethodId()
+	ret.IamUserId = parent.GetIamUserId()
+	return ret, nil
+}
+
+// RefreshAuthToken issues a new auth token for the account of the auth token
+// with the provided id, so clients can keep a login alive without the user
+// authenticating again. The new token expires after the repository's
+// time-to-live, but never later than the maximum lifetime after the user
+// authenticated, which is carried over from token to token. The refreshed
+// token stays valid until it expires or is deleted, so sessions authorized
+// with it are not affected. Tokens derived by ExchangeAuthToken can not be
+// refreshed. The returned auth token contains the auth token value. All
+// options are ignored.
+func (r *Repository) RefreshAuthToken(ctx context.Context, id string, opt ...Option) (*AuthToken, error) {
+	if id == "" {
+		return nil, fmt.Errorf("refresh: auth token: missing public id: %w", errors.ErrInvalidParameter)
+	}
+	old, err := r.LookupAuthToken(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("refresh: auth token: lookup: %w", err)
+	}
+	if old == nil {
+		return nil, fmt.Errorf("refresh: auth token: %s: %w", id, errors.ErrRecordNotFound)
+	}
+	att, err := r.LookupAttenuation(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("refresh: auth token: %w", err)
+	}
+	if att != nil {
+		return nil, fmt.Errorf("refresh: auth token: derived auth tokens can not be refreshed: %w", errors.ErrInvalidParameter)
+	}
+
+	loginTime := old.GetCreateTime()
+	oldRefresh := &refresh{}
+	switch err := r.reader.LookupWhere(ctx, oldRefresh, "auth_token_id = ?", id); {
+	case err == nil:
+		loginTime = oldRefresh.LoginTime
+	case !errors.Is(err, errors.ErrRecordNotFound):
+		return nil, fmt.Errorf("refresh: auth token: lookup login time: %w", err)
+	}
+	login, err := ptypes.Timestamp(loginTime.GetTimestamp())
+	if err != nil {
+		return nil, fmt.Errorf("refresh: auth token: login time: %w", err)
+	}
+	oldExp, err := ptypes.Timestamp(old.GetExpirationTime().GetTimestamp())
+	if err != nil {
+		return nil, fmt.Errorf("refresh: auth token: expiration time: %w", err)
+	}
+
+	exp := time.Now().Add(r.timeToLiveDuration).Truncate(time.Second)
+	if maxExp := login.Add(r.maxLifetimeDuration); exp.After(maxExp) {
+		exp = maxExp
+	}
+	if !exp.After(oldExp) {
+		return nil, fmt.Errorf("refresh: auth token: maximum lifetime reached, authenticate again: %w", errors.ErrInvalidParameter)
+	}
+	expiration, err := ptypes.TimestampProto(exp)
+	if err != nil {
+		return nil, err
+	}
+
+	at := allocAuthToken()
+	at.AuthAccountId = old.GetAuthAccountId()
+	at.ExpirationTime = &timestamp.Timestamp{Timestamp: expiration}
+	if at.PublicId, err = newAuthTokenId(); err != nil {
+		return nil, fmt.Errorf("refresh: auth token id: %w", err)
+	}
+	if at.Token, err = newAuthToken(); err != nil {
+		return nil, fmt.Errorf("refresh: auth token value: %w", err)
+	}
+	newRefresh := &refresh{
+		AuthTokenId:     at.PublicId,
+		RefreshedFromId: id,
+		LoginTime:       loginTime,
+	}
+
+	at.ScopeId = old.GetScopeId()
+
+	var newAuthToken *writableAuthToken
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			newAuthToken = at.toWritableAuthToken()
+			// tokens are not replicated, so they don't need oplog entries.
+			if err := w.Create(ctx, newAuthToken, db.WithFieldWrapper(r.kms.DatabaseFieldWrapper())); err != nil {
+				return err
+			}
+			if err := w.Create(ctx, newRefresh); err != nil {
+				return err
+			}
+			newAuthToken.CtToken = nil
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("refresh: auth token: %w", err)
+	}
+	ret := newAuthToken.toAuthToken()
+	ret.ScopeId = old.GetScopeId()
+	ret.AuthMethodId = old.GetAuthMethodId()
+	ret.IamUserId = old.GetIamUserId()
+	return ret, nil
+}
+
+// LookupRefreshedAuthToken returns the unexpired auth token which expires
+// last among the auth token with the provided id and the auth tokens
+// refreshed from it by RefreshAuthToken, directly or not. This lets resources
+// bound to an auth token, like sessions, follow the login as it is refreshed.
+// Returns nil, nil if there is no such auth token. For security reasons, the
+// actual token is not included in the returned AuthToken. All options are
+// ignored.
+func (r *Repository) LookupRefreshedAuthToken(ctx context.Context, id string, opt ...Option) (*AuthToken, error) {
+	if id == "" {
+		return nil, fmt.Errorf("lookup refreshed: auth token: missing public id: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, latestRefreshQuery, []interface{}{id})
+	if err != nil {
+		return nil, fmt.Errorf("lookup refreshed: auth token: query failed: %w", err)
+	}
+	defer rows.Close()
+	var latestId string
+	for rows.Next() {
+		if err := rows.Scan(&latestId); err != nil {
+			return nil, fmt.Errorf("lookup refreshed: auth token: scan row failed: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("lookup refreshed: auth token: %w", err)
+	}
+	if latestId == "" {
+		return nil, nil
+	}
+	return r.LookupAuthToken(ctx, latestId)
+}
+
+// LookupAttenuation returns the restrictions of the auth token with the
+// provided id if it was derived from another auth token by
+// ExchangeAuthToken or IssueScopedAuthToken. Returns nil, nil if the auth
+// token is not restricted. All options are ignored.
+func (r *Repository) LookupAttenuation(ctx context.Context, authTokenId string, opt ...Option) (*Attenuation, error) {
+	if authTokenId == "" {
+		return nil, fmt.Errorf("lookup attenuation: missing auth token id: %w", errors.ErrInvalidParameter)
+	}
+	att := &Attenuation{}
+	if err := r.reader.LookupWhere(ctx, att, "auth_token_id = ?", authTokenId); err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup attenuation: %w", err)
+	}
+	return att, nil
+}
+
 // LookupAuthToken returns the AuthToken for the provided id. Returns nil, nil if no AuthToken is found for id.
 // For security reasons, the actual token is not included in the returned AuthToken.
 // All exported options are ignored.
@@ -145,21 +477,16 @@ func (r *Repository) LookupAuthToken(ctx context.Context, id string, opt ...Opti
 
 	at := allocAuthToken()
 	at.PublicId = id
-	if err := r.reader.LookupByPublicId(ctx, at); err != nil {
+	var lookupOpts []db.Option
+	if opts.withTokenValue {
+		lookupOpts = append(lookupOpts, db.WithFieldWrapper(r.kms.DatabaseFieldWrapper()))
+	}
+	if err := r.reader.LookupByPublicId(ctx, at, lookupOpts...); err != nil {
 		if errors.Is(err, errors.ErrRecordNotFound) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("auth token: lookup: %w", err)
 	}
-	if opts.withTokenValue {
-		databaseWrapper, err := r.kms.GetWrapper(ctx, at.GetScopeId(), kms.KeyPurposeDatabase, kms.WithKeyId(at.GetKeyId()))
-		if err != nil {
-			return nil, fmt.Errorf("lookup: unable to get database wrapper: %w", err)
-		}
-		if err := at.decrypt(ctx, databaseWrapper); err != nil {
-			return nil, fmt.Errorf("lookup: auth token: cannot decrypt auth token value: %w", err)
-		}
-	}
 
 	at.CtToken = nil
 	at.KeyId = ""
@@ -170,7 +497,7 @@ func (r *Repository) LookupAuthToken(ctx context.Context, id string, opt ...Opti
 // approximate last accessed time may be updated depending on how long it has been since the last time the token
 // was validated.  If a token is returned it is guaranteed to be valid. For security reasons, the actual token
 // value is not included in the returned AuthToken. If no valid auth token is found nil, nil is returned.
-// All options are ignored.
+// All options are ignored. If the repository was created WithReadOnly, nothing is written to the database.
 //
 // NOTE: Do not log or add the token string to any errors to avoid leaking it as it is a secret.
 func (r *Repository) ValidateToken(ctx context.Context, id, token string, opt ...Option) (*AuthToken, error) {
@@ -208,6 +535,9 @@ func (r *Repository) ValidateToken(ctx context.Context, id, token string, opt ..
 	// TODO (jimlambrt 9/2020) - investigate the need for the timeSkew and see
 	// if it can be eliminated.
 	if now.After(exp.Add(-timeSkew)) || sinceLastAccessed >= r.timeToStaleDuration {
+		if r.readOnly {
+			return nil, nil
+		}
 		// If the token has expired or has become too stale, delete it from the DB.
 		_, err = r.writer.DoTx(
 			ctx,
@@ -234,7 +564,7 @@ func (r *Repository) ValidateToken(ctx context.Context, id, token string, opt ..
 	// retAT.Token set to empty string so the value is not returned as described in the methods' doc.
 	retAT.Token = ""
 
-	if sinceLastAccessed >= lastAccessedUpdateDuration {
+	if sinceLastAccessed >= lastAccessedUpdateDuration && !r.readOnly {
 		// To save the db from being updated too frequently, we only update the
 		// LastAccessTime if it hasn't been updated within lastAccessedUpdateDuration.
 		// TODO: Make this duration configurable.
@@ -287,7 +617,8 @@ func (r *Repository) ListAuthTokens(ctx context.Context, withOrgId string, opt .
 }
 
 // DeleteAuthToken deletes the token with the provided id from the repository returning a count of the
-// number of records deleted.  All options are ignored.
+// number of records deleted.  Deleting a token records an auth token revocation security event.
+// All options are ignored.
 func (r *Repository) DeleteAuthToken(ctx context.Context, id string, opt ...Option) (int, error) {
 	if id == "" {
 		return db.NoRowsAffected, fmt.Errorf("delete: auth token: missing public id: %w", errors.ErrInvalidParameter)
@@ -316,7 +647,14 @@ func (r *Repository) DeleteAuthToken(ctx context.Context, id string, opt ...Opti
 			if err == nil && rowsDeleted > 1 {
 				return errors.ErrMultipleRecords
 			}
-			return err
+			if err != nil || rowsDeleted == 0 {
+				return err
+			}
+			return securityevent.Enqueue(ctx, w, &securityevent.Event{
+				Kind:         securityevent.AuthTokenRevoked,
+				ScopeId:      at.GetScopeId(),
+				AuthMethodId: at.GetAuthMethodId(),
+			})
 		},
 	)
 
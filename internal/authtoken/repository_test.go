@@ -11,14 +11,18 @@ import (
 	"github.com/hashicorp/boundary/internal/auth/password"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/perms"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
 
 	"github.com/hashicorp/boundary/internal/authtoken/store"
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
 )
 
 func TestRepository_New(t *testing.T) {
@@ -259,10 +263,69 @@ func TestRepository_CreateAuthToken(t *testing.T) {
 			assert.Equal(got.CreateTime, got.ApproximateLastAccessTime)
 			// We should find no oplog since tokens are not replicated, so they don't need oplog entries.
 			assert.Error(db.TestVerifyOplog(t, rw, got.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_CREATE)))
+
+			// Creating the token records the login of the user
+			logins, err := iamRepo.LookupUserLogins(context.Background(), tt.iamUser.GetPublicId())
+			require.NoError(err)
+			assert.Contains(logins.Accounts, tt.authAcctId)
+			assert.NotNil(logins.LastLoginTime)
 		})
 	}
 }
 
+func TestRepository_CreateAuthToken_Disabled(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+
+	org, _ := iam.TestScopes(t, iamRepo)
+	am := password.TestAuthMethods(t, conn, org.GetPublicId(), 1)[0]
+	acct := password.TestAccounts(t, conn, am.GetPublicId(), 1)[0]
+	u, err := iamRepo.LookupUserWithLogin(ctx, acct.GetPublicId(), iam.WithAutoVivify(true))
+	require.NoError(t, err)
+
+	pwRepo, err := password.NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+
+	t.Run("disabled-account", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		at, err := repo.CreateAuthToken(ctx, u, acct.GetPublicId())
+		require.NoError(err)
+
+		require.NoError(pwRepo.DisableAccount(ctx, org.GetPublicId(), acct.GetPublicId(), "test"))
+		got, err := repo.LookupAuthToken(ctx, at.GetPublicId())
+		require.NoError(err)
+		assert.Nil(got, "existing auth tokens are deleted")
+		_, err = repo.CreateAuthToken(ctx, u, acct.GetPublicId())
+		assert.Error(err)
+
+		require.NoError(pwRepo.EnableAccount(ctx, org.GetPublicId(), acct.GetPublicId()))
+		_, err = repo.CreateAuthToken(ctx, u, acct.GetPublicId())
+		assert.NoError(err)
+	})
+	t.Run("disabled-user", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		at, err := repo.CreateAuthToken(ctx, u, acct.GetPublicId())
+		require.NoError(err)
+
+		require.NoError(iamRepo.DisableUser(ctx, u.GetPublicId(), "test"))
+		got, err := repo.LookupAuthToken(ctx, at.GetPublicId())
+		require.NoError(err)
+		assert.Nil(got, "existing auth tokens are deleted")
+		_, err = repo.CreateAuthToken(ctx, u, acct.GetPublicId())
+		assert.Error(err)
+
+		require.NoError(iamRepo.EnableUser(ctx, u.GetPublicId()))
+		_, err = repo.CreateAuthToken(ctx, u, acct.GetPublicId())
+		assert.NoError(err)
+	})
+}
+
 func TestRepository_LookupAuthToken(t *testing.T) {
 	conn, _ := db.TestSetup(t, "postgres")
 	rw := db.New(conn)
@@ -590,6 +653,280 @@ func TestRepository_DeleteAuthToken(t *testing.T) {
 	}
 }
 
+func TestRepository_ExchangeAuthToken(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	parent := TestAuthToken(t, conn, kms, org.GetPublicId())
+	role := iam.TestRole(t, conn, org.GetPublicId())
+	iam.TestUserRole(t, conn, role.GetPublicId(), parent.GetIamUserId())
+	iam.TestRoleGrant(t, conn, role.GetPublicId(), "id=*;type=target;actions=read")
+	iam.TestRoleGrant(t, conn, role.GetPublicId(), "id=*;type=target;actions=authorize-session")
+	readGrant := perms.GrantPair{ScopeId: org.GetPublicId(), Grant: "id=*;type=target;actions=read"}
+	authzGrant := perms.GrantPair{ScopeId: org.GetPublicId(), Grant: "id=*;type=target;actions=authorize-session"}
+
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ExchangeAuthToken(ctx, parent.GetPublicId(), []perms.GrantPair{readGrant}, time.Hour)
+		require.NoError(err)
+		assert.NotEmpty(got.GetToken())
+		assert.NotEqual(parent.GetPublicId(), got.GetPublicId())
+		assert.Equal(parent.GetIamUserId(), got.GetIamUserId())
+		assert.Equal(parent.GetAuthAccountId(), got.GetAuthAccountId())
+
+		exp, err := ptypes.Timestamp(got.GetExpirationTime().GetTimestamp())
+		require.NoError(err)
+		assert.WithinDuration(time.Now().Add(time.Hour), exp, time.Minute)
+
+		att, err := repo.LookupAttenuation(ctx, got.GetPublicId())
+		require.NoError(err)
+		require.NotNil(att)
+		assert.Equal(parent.GetPublicId(), att.ParentId)
+		assert.Empty(att.TargetId)
+		grants, err := att.GrantPairs()
+		require.NoError(err)
+		assert.Equal([]perms.GrantPair{readGrant}, grants)
+
+		att, err = repo.LookupAttenuation(ctx, parent.GetPublicId())
+		require.NoError(err)
+		assert.Nil(att)
+	})
+	t.Run("ttl-limited-by-parent", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ExchangeAuthToken(ctx, parent.GetPublicId(), nil, 365*24*time.Hour)
+		require.NoError(err)
+		assert.True(proto.Equal(parent.GetExpirationTime(), got.GetExpirationTime()))
+	})
+	t.Run("grant-not-held", func(t *testing.T) {
+		_, err := repo.ExchangeAuthToken(ctx, parent.GetPublicId(), []perms.GrantPair{{ScopeId: org.GetPublicId(), Grant: "id=*;actions=*"}}, time.Hour)
+		assert.Truef(t, errors.Is(err, errors.ErrInvalidParameter), "unexpected error: %v", err)
+	})
+	t.Run("target-restriction", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		derived, err := repo.ExchangeAuthToken(ctx, parent.GetPublicId(), []perms.GrantPair{readGrant, authzGrant}, time.Hour, WithTargetId("ttcp_1234567890"))
+		require.NoError(err)
+
+		// Grants not held by the derived token cannot be regained
+		_, err = repo.ExchangeAuthToken(ctx, derived.GetPublicId(), []perms.GrantPair{{ScopeId: org.GetPublicId(), Grant: "id=*;actions=*"}}, time.Hour)
+		assert.Truef(errors.Is(err, errors.ErrInvalidParameter), "unexpected error: %v", err)
+
+		// The target restriction is inherited and cannot be changed
+		_, err = repo.ExchangeAuthToken(ctx, derived.GetPublicId(), []perms.GrantPair{authzGrant}, time.Hour, WithTargetId("ttcp_0987654321"))
+		assert.Truef(errors.Is(err, errors.ErrInvalidParameter), "unexpected error: %v", err)
+		got, err := repo.ExchangeAuthToken(ctx, derived.GetPublicId(), []perms.GrantPair{authzGrant}, time.Hour)
+		require.NoError(err)
+		att, err := repo.LookupAttenuation(ctx, got.GetPublicId())
+		require.NoError(err)
+		assert.Equal("ttcp_1234567890", att.TargetId)
+	})
+	t.Run("parent-not-found", func(t *testing.T) {
+		badId, err := newAuthTokenId()
+		require.NoError(t, err)
+		_, err = repo.ExchangeAuthToken(ctx, badId, nil, time.Hour)
+		assert.Truef(t, errors.Is(err, errors.ErrRecordNotFound), "unexpected error: %v", err)
+		_, err = repo.ExchangeAuthToken(ctx, "", nil, time.Hour)
+		assert.Truef(t, errors.Is(err, errors.ErrInvalidParameter), "unexpected error: %v", err)
+	})
+	t.Run("delete-parent-deletes-derived", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		p := TestAuthToken(t, conn, kms, org.GetPublicId())
+		derived, err := repo.ExchangeAuthToken(ctx, p.GetPublicId(), nil, time.Hour)
+		require.NoError(err)
+		derivedTwice, err := repo.ExchangeAuthToken(ctx, derived.GetPublicId(), nil, time.Hour)
+		require.NoError(err)
+
+		deleted, err := repo.DeleteAuthToken(ctx, p.GetPublicId())
+		require.NoError(err)
+		assert.Equal(1, deleted)
+		for _, id := range []string{derived.GetPublicId(), derivedTwice.GetPublicId()} {
+			got, err := repo.LookupAuthToken(ctx, id)
+			require.NoError(err)
+			assert.Nil(got)
+		}
+	})
+}
+
+func TestRepository_IssueScopedAuthToken(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, proj := iam.TestScopes(t, iamRepo)
+	parent := TestAuthToken(t, conn, kms, org.GetPublicId())
+	orgRole := iam.TestRole(t, conn, org.GetPublicId())
+	iam.TestUserRole(t, conn, orgRole.GetPublicId(), parent.GetIamUserId())
+	iam.TestRoleGrant(t, conn, orgRole.GetPublicId(), "id=*;type=auth-method;actions=read")
+	projRole := iam.TestRole(t, conn, proj.GetPublicId())
+	iam.TestUserRole(t, conn, projRole.GetPublicId(), parent.GetIamUserId())
+	iam.TestRoleGrant(t, conn, projRole.GetPublicId(), "id=*;type=target;actions=*")
+	projGrant := perms.GrantPair{ScopeId: proj.GetPublicId(), Grant: "id=*;type=target;actions=*"}
+
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.IssueScopedAuthToken(ctx, parent.GetPublicId(), TerraformPreset, []string{proj.GetPublicId()}, time.Hour)
+		require.NoError(err)
+		assert.NotEmpty(got.GetToken())
+		assert.Equal(parent.GetIamUserId(), got.GetIamUserId())
+
+		att, err := repo.LookupAttenuation(ctx, got.GetPublicId())
+		require.NoError(err)
+		require.NotNil(att)
+		assert.Equal(string(TerraformPreset), att.Preset)
+		grants, err := att.GrantPairs()
+		require.NoError(err)
+		assert.Equal([]perms.GrantPair{projGrant}, grants)
+
+		// Tokens exchanged from a scoped token keep its preset, which can't
+		// be changed
+		derived, err := repo.ExchangeAuthToken(ctx, got.GetPublicId(), []perms.GrantPair{projGrant}, time.Hour)
+		require.NoError(err)
+		att, err = repo.LookupAttenuation(ctx, derived.GetPublicId())
+		require.NoError(err)
+		assert.Equal(string(TerraformPreset), att.Preset)
+		_, err = repo.IssueScopedAuthToken(ctx, got.GetPublicId(), ReadOnlyPreset, []string{proj.GetPublicId()}, time.Hour)
+		assert.Truef(errors.Is(err, errors.ErrInvalidParameter), "unexpected error: %v", err)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			parentId string
+			preset   Preset
+			scopeIds []string
+		}{
+			{name: "missing-parent", preset: TerraformPreset, scopeIds: []string{proj.GetPublicId()}},
+			{name: "unknown-preset", parentId: parent.GetPublicId(), preset: "admin", scopeIds: []string{proj.GetPublicId()}},
+			{name: "missing-scopes", parentId: parent.GetPublicId(), preset: TerraformPreset},
+			{name: "no-grants-in-scopes", parentId: parent.GetPublicId(), preset: TerraformPreset, scopeIds: []string{"o_1234567890"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := repo.IssueScopedAuthToken(ctx, tt.parentId, tt.preset, tt.scopeIds, time.Hour)
+				assert.Truef(t, errors.Is(err, errors.ErrInvalidParameter), "unexpected error: %v", err)
+			})
+		}
+	})
+}
+
+func TestAttenuation_AllowsAction(t *testing.T) {
+	target := perms.Resource{Type: resource.Target, Id: "ttcp_1234567890", ScopeId: "p_1234567890"}
+	sess := perms.Resource{Type: resource.Session, Id: "s_1234567890", ScopeId: "p_1234567890"}
+	tests := []struct {
+		name   string
+		att    *Attenuation
+		res    perms.Resource
+		action action.Type
+		want   bool
+	}{
+		{name: "nil", res: target, action: action.AuthorizeSession, want: true},
+		{name: "no-preset", att: &Attenuation{}, res: target, action: action.AuthorizeSession, want: true},
+		{name: "terraform-update", att: &Attenuation{Preset: string(TerraformPreset)}, res: target, action: action.Update, want: true},
+		{name: "terraform-authorize-session", att: &Attenuation{Preset: string(TerraformPreset)}, res: target, action: action.AuthorizeSession},
+		{name: "terraform-session", att: &Attenuation{Preset: string(TerraformPreset)}, res: sess, action: action.Read},
+		{name: "read-only-read", att: &Attenuation{Preset: string(ReadOnlyPreset)}, res: target, action: action.Read, want: true},
+		{name: "read-only-list", att: &Attenuation{Preset: string(ReadOnlyPreset)}, res: sess, action: action.List, want: true},
+		{name: "read-only-update", att: &Attenuation{Preset: string(ReadOnlyPreset)}, res: target, action: action.Update},
+		{name: "unknown", att: &Attenuation{Preset: "admin"}, res: target, action: action.Read},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.att.AllowsAction(tt.res, tt.action))
+		})
+	}
+}
+
+func TestRepository_RefreshAuthToken(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	login := TestAuthToken(t, conn, kms, org.GetPublicId())
+	loginTime, err := ptypes.Timestamp(login.GetCreateTime().GetTimestamp())
+	require.NoError(t, err)
+
+	const day = 24 * time.Hour
+	repo, err := NewRepository(rw, rw, kms, WithTokenTimeToLiveDuration(8*day), WithTokenMaxLifetimeDuration(9*day))
+	require.NoError(t, err)
+	longerRepo, err := NewRepository(rw, rw, kms, WithTokenTimeToLiveDuration(10*day), WithTokenMaxLifetimeDuration(9*day))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("missing-id", func(t *testing.T) {
+		got, err := repo.RefreshAuthToken(ctx, "")
+		assert.Truef(t, errors.Is(err, errors.ErrInvalidParameter), "unexpected error %v", err)
+		assert.Nil(t, got)
+	})
+	t.Run("not-found", func(t *testing.T) {
+		got, err := repo.RefreshAuthToken(ctx, "at_1234567890")
+		assert.Truef(t, errors.Is(err, errors.ErrRecordNotFound), "unexpected error %v", err)
+		assert.Nil(t, got)
+	})
+	t.Run("derived", func(t *testing.T) {
+		derived, err := repo.ExchangeAuthToken(ctx, login.GetPublicId(), nil, time.Hour)
+		require.NoError(t, err)
+		got, err := repo.RefreshAuthToken(ctx, derived.GetPublicId())
+		assert.Truef(t, errors.Is(err, errors.ErrInvalidParameter), "unexpected error %v", err)
+		assert.Nil(t, got)
+	})
+
+	var refreshed *AuthToken
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		var err error
+		refreshed, err = repo.RefreshAuthToken(ctx, login.GetPublicId())
+		require.NoError(err)
+		assert.NotEmpty(refreshed.GetToken())
+		assert.NotEqual(login.GetPublicId(), refreshed.GetPublicId())
+		assert.Equal(login.GetIamUserId(), refreshed.GetIamUserId())
+		assert.Equal(login.GetAuthAccountId(), refreshed.GetAuthAccountId())
+
+		exp, err := ptypes.Timestamp(refreshed.GetExpirationTime().GetTimestamp())
+		require.NoError(err)
+		assert.WithinDuration(time.Now().Add(8*day), exp, time.Minute)
+
+		// The refreshed token stays valid
+		got, err := repo.LookupAuthToken(ctx, login.GetPublicId())
+		require.NoError(err)
+		assert.NotNil(got)
+	})
+	t.Run("capped-by-max-lifetime", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		require.NotNil(refreshed)
+		got, err := longerRepo.RefreshAuthToken(ctx, refreshed.GetPublicId())
+		require.NoError(err)
+		exp, err := ptypes.Timestamp(got.GetExpirationTime().GetTimestamp())
+		require.NoError(err)
+		assert.WithinDuration(loginTime.Add(9*day), exp, time.Second)
+
+		latest, err := repo.LookupRefreshedAuthToken(ctx, login.GetPublicId())
+		require.NoError(err)
+		require.NotNil(latest)
+		assert.Equal(got.GetPublicId(), latest.GetPublicId())
+
+		got, err = longerRepo.RefreshAuthToken(ctx, got.GetPublicId())
+		assert.Truef(errors.Is(err, errors.ErrInvalidParameter), "unexpected error %v", err)
+		assert.Nil(got)
+	})
+	t.Run("lookup-refreshed-not-found", func(t *testing.T) {
+		got, err := repo.LookupRefreshedAuthToken(ctx, "at_1234567890")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
+
 func TestRepository_ListAuthTokens(t *testing.T) {
 	conn, _ := db.TestSetup(t, "postgres")
 	rw := db.New(conn)
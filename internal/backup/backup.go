@@ -0,0 +1,111 @@
+// Package backup provides a logical export of all resources of a Boundary
+// installation into a versioned archive and the import of such an archive into
+// a fresh database, e.g. to migrate to another database provider.
+//
+// The root keys, which are the only values encrypted by the root KMS, are
+// rewrapped to a migration KMS in the archive and from it to the root KMS of
+// the importing installation, so neither installation's root KMS is needed by
+// the other. All other encrypted values are exported as is since they are
+// encrypted by keys derived from the root keys.
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"path"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ManifestFileName is the name of the archive entry containing the
+	// Manifest.
+	ManifestFileName = "manifest.json"
+
+	// SignatureFileName is the name of the archive entry containing the
+	// signature of the Manifest by the migration KMS.
+	SignatureFileName = "manifest.sig"
+
+	// manifestAad is used as additional authenticated data when signing the
+	// manifest, so a signature cannot be confused with any other ciphertext
+	// produced by the same wrapper.
+	manifestAad = "boundary-installation-export-manifest"
+
+	// ArchiveVersion is the version of the format of the archives written by
+	// Export. Import only reads archives of this version.
+	ArchiveVersion = 1
+
+	tablesDir = "tables"
+)
+
+// excludedTables are not exported since they record the migrations of the
+// database, which the importing database has run itself.
+var excludedTables = map[string]bool{
+	"schema_migrations":       true,
+	"schema_migration_record": true,
+	"schema_fingerprint":      true,
+}
+
+// Manifest describes the contents of an archive. Tables lists the exported
+// tables; Files maps the archive entry of each table to the hex encoded
+// SHA-256 digest of its contents and Counts to its number of rows.
+type Manifest struct {
+	Version int `json:"version"`
+	// SchemaVersion is the version of the last migration of the exported
+	// database. Archives are only imported into databases of the same version.
+	SchemaVersion int64     `json:"schema_version"`
+	CreateTime    time.Time `json:"create_time"`
+	// KeyId is the id of the key of the migration KMS which wraps the root
+	// keys in the archive.
+	KeyId  string            `json:"key_id"`
+	Tables []string          `json:"tables"`
+	Files  map[string]string `json:"files"`
+	Counts map[string]int    `json:"counts"`
+}
+
+// tableFileName returns the name of the archive entry of the rows of the table.
+func tableFileName(table string) string {
+	return path.Join(tablesDir, table+".json")
+}
+
+// signManifest signs the sha256 digest of the manifest using the wrapper and
+// returns the marshaled signature.
+func signManifest(ctx context.Context, wrapper wrapping.Wrapper, manifest []byte) ([]byte, error) {
+	const op = "backup.signManifest"
+	digest := sha256.Sum256(manifest)
+	blobInfo, err := wrapper.Encrypt(ctx, digest[:], []byte(manifestAad))
+	if err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to sign manifest"))
+	}
+	sig, err := proto.Marshal(blobInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to marshal signature"))
+	}
+	return sig, nil
+}
+
+// verifyManifest verifies that sig is a signature of manifest produced by
+// signManifest using the same key as wrapper.
+func verifyManifest(ctx context.Context, wrapper wrapping.Wrapper, manifest, sig []byte) error {
+	const op = "backup.verifyManifest"
+	if len(sig) == 0 {
+		return errors.New(errors.InvalidParameter, op, "missing signature")
+	}
+	blobInfo := new(wrapping.EncryptedBlobInfo)
+	if err := proto.Unmarshal(sig, blobInfo); err != nil {
+		return errors.Wrap(err, op, errors.WithMsg("unable to unmarshal signature"))
+	}
+	signed, err := wrapper.Decrypt(ctx, blobInfo, []byte(manifestAad))
+	if err != nil {
+		return errors.Wrap(err, op, errors.WithMsg("unable to verify signature"))
+	}
+	digest := sha256.Sum256(manifest)
+	if subtle.ConstantTimeCompare(signed, digest[:]) != 1 {
+		return errors.New(errors.InvalidParameter, op, "manifest does not match signature")
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rootWrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, rootWrapper)
+	org, prj := iam.TestScopes(t, iamRepo)
+	user := iam.TestUser(t, iamRepo, org.GetPublicId())
+
+	migrationWrapper := db.TestWrapper(t)
+	exporter, err := NewExporter(db.New(conn), rootWrapper, migrationWrapper)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	exported, err := exporter.Export(ctx, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, ArchiveVersion, exported.Version)
+	assert.NotZero(t, exported.SchemaVersion)
+	assert.NotContains(t, exported.Tables, "schema_migrations")
+	assert.GreaterOrEqual(t, exported.Counts["iam_scope"], 3)
+	assert.NotZero(t, exported.Counts["kms_root_key_version"])
+
+	t.Run("import", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		conn, _ := db.TestSetup(t, "postgres")
+		newRootWrapper := db.TestWrapper(t)
+		importer, err := NewImporter(db.New(conn), newRootWrapper, migrationWrapper)
+		require.NoError(err)
+		imported, err := importer.Import(ctx, bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(err)
+		assert.Equal(exported.Counts, imported.Counts)
+
+		repo := iam.TestRepo(t, conn, newRootWrapper)
+		got, err := repo.LookupScope(ctx, prj.GetPublicId())
+		require.NoError(err)
+		assert.Equal(org.GetPublicId(), got.GetParentId())
+		gotUser, _, err := repo.LookupUser(ctx, user.GetPublicId())
+		require.NoError(err)
+		assert.Equal(user.GetPublicId(), gotUser.GetPublicId())
+
+		// the keys of the scopes are decrypted with the new root KMS
+		kmsCache := kms.TestKms(t, conn, newRootWrapper)
+		_, err = kmsCache.GetWrapper(ctx, org.GetPublicId(), kms.KeyPurposeDatabase)
+		require.NoError(err)
+
+		// the database is no longer fresh
+		_, err = importer.Import(ctx, bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.Error(err)
+	})
+	t.Run("wrong-migration-kms", func(t *testing.T) {
+		conn, _ := db.TestSetup(t, "postgres")
+		importer, err := NewImporter(db.New(conn), db.TestWrapper(t), db.TestWrapper(t))
+		require.NoError(t, err)
+		_, err = importer.Import(ctx, bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.Error(t, err)
+	})
+	t.Run("tampered", func(t *testing.T) {
+		require := require.New(t)
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(err)
+		var tampered bytes.Buffer
+		zw := zip.NewWriter(&tampered)
+		for _, f := range zr.File {
+			w, err := zw.Create(f.Name)
+			require.NoError(err)
+			rc, err := f.Open()
+			require.NoError(err)
+			_, err = io.Copy(w, rc)
+			require.NoError(err)
+			require.NoError(rc.Close())
+			if f.Name == tableFileName("iam_scope") {
+				_, err = w.Write([]byte(`{"public_id": "o_1234567890", "type": "org", "parent_id": "global"}` + "\n"))
+				require.NoError(err)
+			}
+		}
+		require.NoError(zw.Close())
+
+		conn, _ := db.TestSetup(t, "postgres")
+		importer, err := NewImporter(db.New(conn), db.TestWrapper(t), migrationWrapper)
+		require.NoError(err)
+		_, err = importer.Import(ctx, bytes.NewReader(tampered.Bytes()), int64(tampered.Len()))
+		require.Error(err)
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	_, err := NewExporter(nil, wrapper, wrapper)
+	assert.Error(t, err)
+	_, err = NewExporter(rw, nil, wrapper)
+	assert.Error(t, err)
+	_, err = NewImporter(rw, wrapper, nil)
+	assert.Error(t, err)
+}
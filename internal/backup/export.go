@@ -0,0 +1,260 @@
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/kms"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+)
+
+// rewrapFunc rewraps the values of an exported or imported row which are
+// encrypted by a KMS, decrypting them with from and encrypting them with to.
+type rewrapFunc func(ctx context.Context, row map[string]interface{}, from, to wrapping.Wrapper) error
+
+// rewrappedTables are the tables whose rows hold values encrypted by the root
+// KMS.
+var rewrappedTables = map[string]rewrapFunc{
+	"kms_root_key_version": rewrapRootKeyVersion,
+}
+
+// Exporter writes archives of all resources of an installation.
+type Exporter struct {
+	writer           db.Writer
+	rootWrapper      wrapping.Wrapper
+	migrationWrapper wrapping.Wrapper
+}
+
+// NewExporter creates a new Exporter which reads from w, within a
+// transaction, and rewraps the root keys from rootWrapper to
+// migrationWrapper, which also signs the archive manifest.
+func NewExporter(w db.Writer, rootWrapper, migrationWrapper wrapping.Wrapper) (*Exporter, error) {
+	const op = "backup.NewExporter"
+	switch {
+	case w == nil:
+		return nil, errors.New(errors.InvalidParameter, op, "nil writer")
+	case rootWrapper == nil:
+		return nil, errors.New(errors.InvalidParameter, op, "nil root wrapper")
+	case migrationWrapper == nil:
+		return nil, errors.New(errors.InvalidParameter, op, "nil migration wrapper")
+	}
+	return &Exporter{
+		writer:           w,
+		rootWrapper:      rootWrapper,
+		migrationWrapper: migrationWrapper,
+	}, nil
+}
+
+// Export writes a zip archive to w containing the rows of every table of the
+// database, except those recording its migrations, read from a single
+// snapshot. Each table is written to its own entry with one JSON document per
+// row, streamed from the database. The archive includes a manifest of the
+// schema version and of SHA-256 digests of every entry, signed by the
+// migration KMS.
+func (e *Exporter) Export(ctx context.Context, w io.Writer) (*Manifest, error) {
+	const op = "backup.(Exporter).Export"
+	if w == nil {
+		return nil, errors.New(errors.InvalidParameter, op, "nil writer")
+	}
+	manifest := &Manifest{
+		Version:    ArchiveVersion,
+		CreateTime: time.Now().UTC(),
+		KeyId:      e.migrationWrapper.KeyID(),
+		Files:      map[string]string{},
+		Counts:     map[string]int{},
+	}
+	zw := zip.NewWriter(w)
+	_, err := e.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{}, func(r db.Reader, txw db.Writer) error {
+		// read all tables from the same snapshot
+		if _, err := txw.Exec(ctx, "set transaction isolation level repeatable read read only", nil); err != nil {
+			return errors.Wrap(err, op, errors.WithMsg("unable to set isolation level"))
+		}
+		var err error
+		if manifest.SchemaVersion, err = schemaVersion(ctx, r); err != nil {
+			return errors.Wrap(err, op)
+		}
+		if manifest.Tables, err = exportedTables(ctx, r); err != nil {
+			return errors.Wrap(err, op)
+		}
+		for _, t := range manifest.Tables {
+			digest, count, err := e.writeTable(ctx, r, zw, t)
+			if err != nil {
+				return errors.Wrap(err, op, errors.WithMsg(t))
+			}
+			manifest.Files[tableFileName(t)] = digest
+			manifest.Counts[t] = count
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to marshal manifest"))
+	}
+	sig, err := signManifest(ctx, e.migrationWrapper, manifestBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+	for _, entry := range []struct {
+		name string
+		data []byte
+	}{
+		{name: ManifestFileName, data: manifestBytes},
+		{name: SignatureFileName, data: sig},
+	} {
+		f, err := zw.Create(entry.name)
+		if err != nil {
+			return nil, errors.Wrap(err, op, errors.WithMsg("unable to create "+entry.name))
+		}
+		if _, err := f.Write(entry.data); err != nil {
+			return nil, errors.Wrap(err, op, errors.WithMsg("unable to write "+entry.name))
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to close archive"))
+	}
+	return manifest, nil
+}
+
+// writeTable writes the rows of the table to its archive entry, rewrapping
+// them if needed, and returns the digest of the entry and its number of rows.
+func (e *Exporter) writeTable(ctx context.Context, r db.Reader, zw *zip.Writer, table string) (string, int, error) {
+	const op = "backup.(Exporter).writeTable"
+	f, err := zw.Create(tableFileName(table))
+	if err != nil {
+		return "", 0, errors.Wrap(err, op, errors.WithMsg("unable to create archive entry"))
+	}
+	rows, err := r.Query(ctx, exportRowsQuery(table), nil)
+	if err != nil {
+		return "", 0, errors.Wrap(err, op)
+	}
+	defer rows.Close()
+
+	h := sha256.New()
+	out := io.MultiWriter(f, h)
+	rewrap := rewrappedTables[table]
+	count := 0
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return "", 0, errors.Wrap(err, op)
+		}
+		if rewrap != nil {
+			if row, err = rewrapRow(ctx, row, rewrap, e.rootWrapper, e.migrationWrapper); err != nil {
+				return "", 0, errors.Wrap(err, op)
+			}
+		}
+		if _, err := io.WriteString(out, row+"\n"); err != nil {
+			return "", 0, errors.Wrap(err, op)
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return "", 0, errors.Wrap(err, op)
+	}
+	return hex.EncodeToString(h.Sum(nil)), count, nil
+}
+
+// rewrapRow rewraps the JSON encoded row with fn.
+func rewrapRow(ctx context.Context, row string, fn rewrapFunc, from, to wrapping.Wrapper) (string, error) {
+	const op = "backup.rewrapRow"
+	// keep numbers as they are exported
+	dec := json.NewDecoder(strings.NewReader(row))
+	dec.UseNumber()
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		return "", errors.Wrap(err, op)
+	}
+	if err := fn(ctx, m, from, to); err != nil {
+		return "", errors.Wrap(err, op)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", errors.Wrap(err, op)
+	}
+	return string(b), nil
+}
+
+// rewrapRootKeyVersion rewraps the key of a root key version, which postgres
+// encodes as a hex string prefixed with \x.
+func rewrapRootKeyVersion(ctx context.Context, row map[string]interface{}, from, to wrapping.Wrapper) error {
+	const op = "backup.rewrapRootKeyVersion"
+	ct, ok := row["key"].(string)
+	if !ok || !strings.HasPrefix(ct, `\x`) {
+		return errors.New(errors.InvalidParameter, op, "missing key")
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(ct, `\x`))
+	if err != nil {
+		return errors.Wrap(err, op, errors.WithMsg("unable to decode key"))
+	}
+	k := kms.AllocRootKeyVersion()
+	k.CtKey = b
+	if err := k.Decrypt(ctx, from); err != nil {
+		return errors.Wrap(err, op)
+	}
+	if err := k.Encrypt(ctx, to); err != nil {
+		return errors.Wrap(err, op)
+	}
+	row["key"] = `\x` + hex.EncodeToString(k.CtKey)
+	return nil
+}
+
+// schemaVersion returns the version of the last migration of the database.
+func schemaVersion(ctx context.Context, r db.Reader) (int64, error) {
+	const op = "backup.schemaVersion"
+	rows, err := r.Query(ctx, schemaVersionQuery, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, op)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, errors.Wrap(err, op)
+		}
+		return 0, errors.New(errors.RecordNotFound, op, "database is not initialized")
+	}
+	var version int64
+	var dirty bool
+	if err := rows.Scan(&version, &dirty); err != nil {
+		return 0, errors.Wrap(err, op)
+	}
+	if dirty {
+		return 0, errors.New(errors.InvalidParameter, op, "database has a failed migration")
+	}
+	return version, nil
+}
+
+// exportedTables returns the tables of the database which are exported.
+func exportedTables(ctx context.Context, r db.Reader) ([]string, error) {
+	const op = "backup.exportedTables"
+	rows, err := r.Query(ctx, exportTablesQuery, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+	defer rows.Close()
+	var tables []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, errors.Wrap(err, op)
+		}
+		if !excludedTables[t] {
+			tables = append(tables, t)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+	return tables, nil
+}
@@ -0,0 +1,260 @@
+package backup
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+)
+
+// importBatchSize is the number of rows inserted by each statement of an
+// import.
+const importBatchSize = 500
+
+// Importer restores archives written by an Exporter.
+type Importer struct {
+	writer           db.Writer
+	rootWrapper      wrapping.Wrapper
+	migrationWrapper wrapping.Wrapper
+}
+
+// NewImporter creates a new Importer which writes to w and rewraps the root
+// keys from migrationWrapper, which also verifies the archive manifest, to
+// rootWrapper.
+func NewImporter(w db.Writer, rootWrapper, migrationWrapper wrapping.Wrapper) (*Importer, error) {
+	const op = "backup.NewImporter"
+	switch {
+	case w == nil:
+		return nil, errors.New(errors.InvalidParameter, op, "nil writer")
+	case rootWrapper == nil:
+		return nil, errors.New(errors.InvalidParameter, op, "nil root wrapper")
+	case migrationWrapper == nil:
+		return nil, errors.New(errors.InvalidParameter, op, "nil migration wrapper")
+	}
+	return &Importer{
+		writer:           w,
+		rootWrapper:      rootWrapper,
+		migrationWrapper: migrationWrapper,
+	}, nil
+}
+
+// Import restores the archive of size bytes read from r into a fresh
+// database: one which has been migrated to the schema version of the archive
+// but not initialized further. The manifest must be signed by the migration
+// KMS and the digest of every table must match it. All rows are imported in a
+// single transaction, replacing the rows the migrations created, with triggers
+// disabled so they are imported exactly as exported; this requires the
+// database user to be a superuser.
+func (i *Importer) Import(ctx context.Context, r io.ReaderAt, size int64) (*Manifest, error) {
+	const op = "backup.(Importer).Import"
+	if r == nil {
+		return nil, errors.New(errors.InvalidParameter, op, "nil reader")
+	}
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to read archive"))
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	manifestBytes, err := readFile(files, ManifestFileName)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+	sig, err := readFile(files, SignatureFileName)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+	if err := verifyManifest(ctx, i.migrationWrapper, manifestBytes, sig); err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+	manifest := new(Manifest)
+	if err := json.Unmarshal(manifestBytes, manifest); err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to unmarshal manifest"))
+	}
+	if manifest.Version != ArchiveVersion {
+		return nil, errors.New(errors.InvalidParameter, op, fmt.Sprintf("unsupported archive version %d", manifest.Version))
+	}
+	for _, t := range manifest.Tables {
+		if excludedTables[t] {
+			return nil, errors.New(errors.InvalidParameter, op, fmt.Sprintf("archive contains excluded table %s", t))
+		}
+		if _, ok := files[tableFileName(t)]; !ok {
+			return nil, errors.New(errors.InvalidParameter, op, fmt.Sprintf("archive is missing table %s", t))
+		}
+	}
+
+	_, err = i.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{}, func(r db.Reader, w db.Writer) error {
+		version, err := schemaVersion(ctx, r)
+		if err != nil {
+			return errors.Wrap(err, op)
+		}
+		if version != manifest.SchemaVersion {
+			return errors.New(errors.InvalidParameter, op, fmt.Sprintf("archive has schema version %d but database has schema version %d", manifest.SchemaVersion, version))
+		}
+		initialized, err := isInitialized(ctx, r)
+		if err != nil {
+			return errors.Wrap(err, op)
+		}
+		if initialized {
+			return errors.New(errors.InvalidParameter, op, "database is already initialized")
+		}
+		if _, err := w.Exec(ctx, disableTriggersQuery, nil); err != nil {
+			return errors.Wrap(err, op, errors.WithMsg("unable to disable triggers"))
+		}
+		if len(manifest.Tables) > 0 {
+			if _, err := w.Exec(ctx, truncateQuery(manifest.Tables), nil); err != nil {
+				return errors.Wrap(err, op, errors.WithMsg("unable to truncate tables"))
+			}
+		}
+		for _, t := range manifest.Tables {
+			count, err := i.importTable(ctx, w, files[tableFileName(t)], t, manifest.Files[tableFileName(t)])
+			if err != nil {
+				return errors.Wrap(err, op, errors.WithMsg(t))
+			}
+			if count != manifest.Counts[t] {
+				return errors.New(errors.InvalidParameter, op, fmt.Sprintf("table %s has %d rows but manifest has %d", t, count, manifest.Counts[t]))
+			}
+		}
+		return resetSequences(ctx, r, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// importTable inserts the rows of the table from its archive entry in batches,
+// rewrapping them if needed, and verifies the digest of the entry. It returns
+// the number of rows.
+func (i *Importer) importTable(ctx context.Context, w db.Writer, f *zip.File, table, digest string) (int, error) {
+	const op = "backup.(Importer).importTable"
+	rc, err := f.Open()
+	if err != nil {
+		return 0, errors.Wrap(err, op)
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	scanner := bufio.NewScanner(io.TeeReader(rc, h))
+	// rows can be much larger than the default limit of a line
+	scanner.Buffer(nil, 64<<20)
+	rewrap := rewrappedTables[table]
+	count := 0
+	batch := make([]string, 0, importBatchSize)
+	insert := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if _, err := w.Exec(ctx, importRowsQuery(table), []interface{}{"[" + strings.Join(batch, ",") + "]"}); err != nil {
+			return errors.Wrap(err, op)
+		}
+		batch = batch[:0]
+		return nil
+	}
+	for scanner.Scan() {
+		row := scanner.Text()
+		if rewrap != nil {
+			if row, err = rewrapRow(ctx, row, rewrap, i.migrationWrapper, i.rootWrapper); err != nil {
+				return 0, errors.Wrap(err, op)
+			}
+		}
+		batch = append(batch, row)
+		count++
+		if len(batch) == importBatchSize {
+			if err := insert(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, errors.Wrap(err, op)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != digest {
+		return 0, errors.New(errors.InvalidParameter, op, "digest does not match manifest")
+	}
+	if err := insert(); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// resetSequences advances the sequences generating the values of columns past
+// the imported values.
+func resetSequences(ctx context.Context, r db.Reader, w db.Writer) error {
+	const op = "backup.resetSequences"
+	rows, err := r.Query(ctx, identityColumnsQuery, nil)
+	if err != nil {
+		return errors.Wrap(err, op)
+	}
+	var queries []string
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			rows.Close()
+			return errors.Wrap(err, op)
+		}
+		queries = append(queries, setSequenceQuery(table, column))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, op)
+	}
+	for _, q := range queries {
+		if _, err := w.Exec(ctx, q, nil); err != nil {
+			return errors.Wrap(err, op)
+		}
+	}
+	return nil
+}
+
+// isInitialized returns whether the database was initialized beyond its
+// migrations.
+func isInitialized(ctx context.Context, r db.Reader) (bool, error) {
+	const op = "backup.isInitialized"
+	rows, err := r.Query(ctx, initializedQuery, nil)
+	if err != nil {
+		return false, errors.Wrap(err, op)
+	}
+	defer rows.Close()
+	var initialized bool
+	if rows.Next() {
+		if err := rows.Scan(&initialized); err != nil {
+			return false, errors.Wrap(err, op)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, errors.Wrap(err, op)
+	}
+	return initialized, nil
+}
+
+// readFile returns the contents of the archive entry.
+func readFile(files map[string]*zip.File, name string) ([]byte, error) {
+	const op = "backup.readFile"
+	f, ok := files[name]
+	if !ok {
+		return nil, errors.New(errors.InvalidParameter, op, fmt.Sprintf("archive is missing %s", name))
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+	defer rc.Close()
+	b, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+	return b, nil
+}
@@ -0,0 +1,67 @@
+package backup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+const exportTablesQuery = `
+select table_name
+  from information_schema.tables
+ where table_schema = current_schema()
+   and table_type = 'BASE TABLE'
+ order by table_name
+`
+
+const schemaVersionQuery = `
+select version, dirty
+  from schema_migrations
+`
+
+// identityColumnsQuery returns the columns whose values are generated by a
+// sequence, which must be advanced past the imported values.
+const identityColumnsQuery = `
+select table_name, column_name
+  from information_schema.columns
+ where table_schema = current_schema()
+   and (is_identity = 'YES' or column_default like 'nextval(%')
+`
+
+// initializedQuery returns whether the database has root keys, which are
+// created once the database is initialized and mean it isn't fresh.
+const initializedQuery = `
+select exists (select 1 from kms_root_key)
+`
+
+// Triggers and foreign key constraints, which are implemented by triggers,
+// are disabled while importing so the rows are imported exactly as exported,
+// in any order.
+const disableTriggersQuery = `
+set local session_replication_role = replica
+`
+
+func exportRowsQuery(table string) string {
+	return fmt.Sprintf("select row_to_json(t)::text from %s t", pq.QuoteIdentifier(table))
+}
+
+func truncateQuery(tables []string) string {
+	quoted := make([]string, 0, len(tables))
+	for _, t := range tables {
+		quoted = append(quoted, pq.QuoteIdentifier(t))
+	}
+	return fmt.Sprintf("truncate table %s", strings.Join(quoted, ", "))
+}
+
+// importRowsQuery inserts the rows of a json array of rows, overriding the
+// values generated for identity columns with the exported ones.
+func importRowsQuery(table string) string {
+	t := pq.QuoteIdentifier(table)
+	return fmt.Sprintf("insert into %s overriding system value select * from json_populate_recordset(null::%s, ?)", t, t)
+}
+
+func setSequenceQuery(table, column string) string {
+	t, c := pq.QuoteIdentifier(table), pq.QuoteIdentifier(column)
+	return fmt.Sprintf("select setval(pg_get_serial_sequence('%s', '%s'), coalesce(max(%s), 0) + 1, false) from %s", t, column, c, t)
+}
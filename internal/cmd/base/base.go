@@ -78,6 +78,9 @@ type Command struct {
 	FlagVersion       int
 
 	client *api.Client
+
+	// keyringToken is set if the client token was read from a keyring
+	keyringToken *keyringToken
 }
 
 // New returns a new instance of a base.Command type
@@ -221,6 +224,11 @@ func (c *Command) Client(opt ...Option) (*api.Client, error) {
 		authToken := c.ReadTokenFromKeyring(keyringType, tokenName)
 		if authToken != nil {
 			c.client.SetToken(authToken.Token)
+			c.keyringToken = &keyringToken{
+				keyringType: keyringType,
+				tokenName:   tokenName,
+				authToken:   authToken,
+			}
 		}
 	}
 
@@ -365,6 +373,42 @@ func (c *Command) ReadTokenFromKeyring(keyringType, tokenName string) *authtoken
 	return nil
 }
 
+// SaveTokenToKeyring stores token in the keyring as tokenName, so later
+// commands read it with ReadTokenFromKeyring.
+func (c *Command) SaveTokenToKeyring(keyringType, tokenName string, token *authtokens.AuthToken) error {
+	marshaled, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("Error marshaling auth token to save to keyring: %w", err)
+	}
+
+	switch keyringType {
+	case "wincred", "keychain":
+		if err := zkeyring.Set("HashiCorp Boundary Auth Token", tokenName, base64.RawStdEncoding.EncodeToString(marshaled)); err != nil {
+			return fmt.Errorf("Error saving auth token to %q keyring: %w", keyringType, err)
+		}
+
+	default:
+		krConfig := nkeyring.Config{
+			LibSecretCollectionName: "login",
+			PassPrefix:              "HashiCorp_Boundary",
+			AllowedBackends:         []nkeyring.BackendType{nkeyring.BackendType(keyringType)},
+		}
+
+		kr, err := nkeyring.Open(krConfig)
+		if err != nil {
+			return fmt.Errorf("Error opening %q keyring: %w", keyringType, err)
+		}
+
+		if err := kr.Set(nkeyring.Item{
+			Key:  tokenName,
+			Data: []byte(base64.RawStdEncoding.EncodeToString(marshaled)),
+		}); err != nil {
+			return fmt.Errorf("Error storing token in %q keyring: %w", keyringType, err)
+		}
+	}
+	return nil
+}
+
 type FlagSetBit uint
 
 const (
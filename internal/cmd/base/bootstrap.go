@@ -0,0 +1,238 @@
+package base
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/auth/password"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/hashicorp/vault/sdk/helper/base62"
+)
+
+// BootstrapRequest describes the initial resources created by Bootstrap.
+type BootstrapRequest struct {
+	// OrgName is the name of the initial org scope.
+	OrgName string
+	// AuthMethodName is the name of the global password auth method.
+	AuthMethodName string
+	// LoginName is the login name of the admin account.
+	LoginName string
+	// UserName is the name of the admin user.
+	UserName string
+	// Password is the password of the admin account. A random password is
+	// generated if it is empty. It is only used when the account is created.
+	Password string
+}
+
+// BootstrapResource is a resource found or created by Bootstrap.
+type BootstrapResource struct {
+	Id      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	ScopeId string `json:"scope_id"`
+	// Created is false if the resource already existed.
+	Created bool `json:"created"`
+}
+
+// BootstrapResult holds the resources of a bootstrap.
+type BootstrapResult struct {
+	LoginRole  *BootstrapResource `json:"login_role"`
+	AuthMethod *BootstrapResource `json:"auth_method"`
+	Account    *BootstrapResource `json:"account"`
+	User       *BootstrapResource `json:"user"`
+	AdminRole  *BootstrapResource `json:"admin_role"`
+	Org        *BootstrapResource `json:"org_scope"`
+
+	// GeneratedPassword is the password generated for the admin account. It is
+	// only set if the account was created without a requested password.
+	GeneratedPassword string `json:"-"`
+}
+
+// Bootstrap creates the global KMS keys and the initial resources of a new
+// installation: the login role, a global password auth method with an admin
+// account and user, a role granting the user admin rights in the global scope,
+// and an org scope. It is idempotent: resources which already exist, found by
+// their names, are left unchanged, so it can be run on every deployment. The
+// database must already be migrated.
+func (b *Server) Bootstrap(ctx context.Context, req BootstrapRequest) (*BootstrapResult, error) {
+	switch {
+	case req.OrgName == "":
+		return nil, fmt.Errorf("bootstrap: missing org name: %w", errors.ErrInvalidParameter)
+	case req.AuthMethodName == "":
+		return nil, fmt.Errorf("bootstrap: missing auth method name: %w", errors.ErrInvalidParameter)
+	case req.LoginName == "":
+		return nil, fmt.Errorf("bootstrap: missing login name: %w", errors.ErrInvalidParameter)
+	case req.UserName == "":
+		return nil, fmt.Errorf("bootstrap: missing user name: %w", errors.ErrInvalidParameter)
+	}
+	rw := db.New(b.Database)
+
+	kmsRepo, err := kms.NewRepository(rw, rw)
+	if err != nil {
+		return nil, fmt.Errorf("error creating kms repository: %w", err)
+	}
+	rootKeys, err := kmsRepo.ListRootKeys(ctx, kms.WithLimit(-1))
+	if err != nil {
+		return nil, fmt.Errorf("error listing root keys: %w", err)
+	}
+	var haveGlobalKeys bool
+	for _, k := range rootKeys {
+		if k.ScopeId == scope.Global.String() {
+			haveGlobalKeys = true
+			break
+		}
+	}
+	if !haveGlobalKeys {
+		if err := b.CreateGlobalKmsKeys(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	kmsCache, err := kms.NewKms(kmsRepo, kms.WithLogger(b.Logger.Named("kms")))
+	if err != nil {
+		return nil, fmt.Errorf("error creating kms cache: %w", err)
+	}
+	if err := kmsCache.AddExternalWrappers(
+		kms.WithRootWrapper(b.RootKms),
+	); err != nil {
+		return nil, fmt.Errorf("error adding config keys to kms: %w", err)
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-b.ShutdownCh:
+			cancel()
+		case <-cancelCtx.Done():
+		}
+	}()
+
+	iamRepo, err := iam.NewRepository(rw, rw, kmsCache, iam.WithRandomReader(b.SecureRandomReader))
+	if err != nil {
+		return nil, fmt.Errorf("error creating iam repository: %w", err)
+	}
+	pwRepo, err := password.NewRepository(rw, rw, kmsCache)
+	if err != nil {
+		return nil, fmt.Errorf("error creating password repository: %w", err)
+	}
+	res := &BootstrapResult{}
+
+	loginRole, created, err := ensureLoginRole(cancelCtx, iamRepo)
+	if err != nil {
+		return nil, fmt.Errorf("error bootstrapping login role: %w", err)
+	}
+	res.LoginRole = &BootstrapResource{Id: loginRole.PublicId, Name: loginRole.Name, ScopeId: loginRole.ScopeId, Created: created}
+
+	// Auth method
+	var am *password.AuthMethod
+	ams, err := pwRepo.ListAuthMethods(cancelCtx, scope.Global.String(), password.WithLimit(-1))
+	if err != nil {
+		return nil, fmt.Errorf("error listing auth methods: %w", err)
+	}
+	for _, m := range ams {
+		if m.Name == req.AuthMethodName {
+			am = m
+			break
+		}
+	}
+	res.AuthMethod = &BootstrapResource{Name: req.AuthMethodName, ScopeId: scope.Global.String()}
+	if am == nil {
+		if am, err = createAuthMethod(cancelCtx, pwRepo, req.AuthMethodName); err != nil {
+			return nil, err
+		}
+		res.AuthMethod.Created = true
+	}
+	res.AuthMethod.Id = am.PublicId
+
+	// Account
+	var acct *password.Account
+	accts, err := pwRepo.ListAccounts(cancelCtx, am.PublicId, password.WithLimit(-1))
+	if err != nil {
+		return nil, fmt.Errorf("error listing accounts: %w", err)
+	}
+	for _, a := range accts {
+		if a.LoginName == req.LoginName {
+			acct = a
+			break
+		}
+	}
+	res.Account = &BootstrapResource{Name: req.LoginName, ScopeId: scope.Global.String()}
+	if acct == nil {
+		pw := req.Password
+		if pw == "" {
+			if pw, err = base62.Random(20); err != nil {
+				return nil, fmt.Errorf("unable to generate password: %w", err)
+			}
+			res.GeneratedPassword = pw
+		}
+		if acct, err = createAccount(cancelCtx, pwRepo, am.PublicId, req.LoginName, pw); err != nil {
+			return nil, err
+		}
+		res.Account.Created = true
+	}
+	res.Account.Id = acct.PublicId
+
+	// User, which may exist without being associated with the account if a
+	// previous bootstrap failed in between
+	u, err := iamRepo.LookupUserWithLogin(cancelCtx, acct.PublicId)
+	switch {
+	case err == nil:
+		res.User = &BootstrapResource{Id: u.PublicId, Name: u.Name, ScopeId: u.ScopeId}
+	case errors.Is(err, errors.ErrRecordNotFound):
+		users, err := iamRepo.ListUsers(cancelCtx, scope.Global.String(), iam.WithLimit(-1))
+		if err != nil {
+			return nil, fmt.Errorf("error listing users: %w", err)
+		}
+		for _, existing := range users {
+			if existing.Name == req.UserName {
+				u = existing
+				break
+			}
+		}
+		res.User = &BootstrapResource{Name: req.UserName, ScopeId: scope.Global.String()}
+		if u == nil {
+			if u, err = createAdminUser(cancelCtx, iamRepo, req.UserName, acct.PublicId); err != nil {
+				return nil, err
+			}
+			res.User.Created = true
+		} else if _, err = iamRepo.AddUserAccounts(cancelCtx, u.PublicId, u.Version, []string{acct.PublicId}); err != nil {
+			return nil, fmt.Errorf("error associating initial admin user with account: %w", err)
+		}
+		res.User.Id = u.PublicId
+	default:
+		return nil, fmt.Errorf("error looking up user of account: %w", err)
+	}
+
+	adminRole, created, err := ensureAdminRole(cancelCtx, iamRepo, u.PublicId)
+	if err != nil {
+		return nil, fmt.Errorf("error bootstrapping admin role: %w", err)
+	}
+	res.AdminRole = &BootstrapResource{Id: adminRole.PublicId, Name: adminRole.Name, ScopeId: adminRole.ScopeId, Created: created}
+
+	// Org, created with the admin user so it is granted admin rights within it
+	var org *iam.Scope
+	orgs, err := iamRepo.ListOrgs(cancelCtx, iam.WithLimit(-1))
+	if err != nil {
+		return nil, fmt.Errorf("error listing orgs: %w", err)
+	}
+	for _, o := range orgs {
+		if o.Name == req.OrgName {
+			org = o
+			break
+		}
+	}
+	res.Org = &BootstrapResource{Name: req.OrgName, ScopeId: scope.Global.String()}
+	if org == nil {
+		if org, err = createOrg(cancelCtx, iamRepo, req.OrgName, u.PublicId, iam.WithRandomReader(b.SecureRandomReader)); err != nil {
+			return nil, err
+		}
+		res.Org.Created = true
+	}
+	res.Org.Id = org.PublicId
+
+	return res, nil
+}
@@ -0,0 +1,131 @@
+package base_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBootstrapServer returns a server connected to a new, migrated database
+// and a repository to inspect the resources it creates.
+func testBootstrapServer(t *testing.T) (*base.Server, *iam.Repository) {
+	t.Helper()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	b := base.NewServer(&base.Command{ShutdownCh: make(chan struct{})})
+	b.Database = conn
+	b.RootKms = wrapper
+	b.Logger = hclog.NewNullLogger()
+
+	rw := db.New(conn)
+	repo, err := iam.NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(t, err)
+	return b, repo
+}
+
+func testBootstrapRequest() base.BootstrapRequest {
+	return base.BootstrapRequest{
+		OrgName:        "Generated org scope",
+		AuthMethodName: "Generated global scope initial auth method",
+		LoginName:      "admin",
+		UserName:       "admin",
+	}
+}
+
+// assertRoleGrants asserts the role has exactly the number of grants and
+// principals.
+func assertRoleGrants(t *testing.T, repo *iam.Repository, roleId string, grants, principals int) {
+	t.Helper()
+	g, err := repo.ListRoleGrants(context.Background(), roleId)
+	require.NoError(t, err)
+	assert.Len(t, g, grants)
+	p, err := repo.ListPrincipalRoles(context.Background(), roleId)
+	require.NoError(t, err)
+	assert.Len(t, p, principals)
+}
+
+func TestServer_Bootstrap(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid request", func(t *testing.T) {
+		b := base.NewServer(&base.Command{ShutdownCh: make(chan struct{})})
+		req := testBootstrapRequest()
+		req.OrgName = ""
+		_, err := b.Bootstrap(ctx, req)
+		assert.Error(t, err)
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		b, repo := testBootstrapServer(t)
+
+		first, err := b.Bootstrap(ctx, testBootstrapRequest())
+		require.NoError(err)
+		for _, r := range []*base.BootstrapResource{first.LoginRole, first.AuthMethod, first.Account, first.User, first.AdminRole, first.Org} {
+			assert.NotEmpty(r.Id)
+			assert.True(r.Created, r.Id)
+		}
+		assert.NotEmpty(first.GeneratedPassword)
+		assertRoleGrants(t, repo, first.LoginRole.Id, 3, 1)
+		assertRoleGrants(t, repo, first.AdminRole.Id, 1, 1)
+		u, err := repo.LookupUserWithLogin(ctx, first.Account.Id)
+		require.NoError(err)
+		assert.Equal(first.User.Id, u.PublicId)
+
+		// A second run finds everything and changes nothing, including the
+		// password of the account
+		req := testBootstrapRequest()
+		req.Password = "a-new-password"
+		second, err := b.Bootstrap(ctx, req)
+		require.NoError(err)
+		assert.Empty(second.GeneratedPassword)
+		for _, pair := range [][2]*base.BootstrapResource{
+			{first.LoginRole, second.LoginRole},
+			{first.AuthMethod, second.AuthMethod},
+			{first.Account, second.Account},
+			{first.User, second.User},
+			{first.AdminRole, second.AdminRole},
+			{first.Org, second.Org},
+		} {
+			assert.Equal(pair[0].Id, pair[1].Id)
+			assert.False(pair[1].Created, pair[1].Id)
+		}
+		assertRoleGrants(t, repo, second.LoginRole.Id, 3, 1)
+		assertRoleGrants(t, repo, second.AdminRole.Id, 1, 1)
+	})
+
+	t.Run("after database init", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		b, repo := testBootstrapServer(t)
+
+		// Resources created by "database init" are the ones bootstrap finds
+		require.NoError(b.CreateGlobalKmsKeys(ctx))
+		role, err := b.CreateInitialLoginRole(ctx)
+		require.NoError(err)
+		am, u, err := b.CreateInitialAuthMethod(ctx)
+		require.NoError(err)
+		org, _, err := b.CreateInitialScopes(ctx)
+		require.NoError(err)
+
+		req := testBootstrapRequest()
+		req.LoginName = b.DevLoginName
+		res, err := b.Bootstrap(ctx, req)
+		require.NoError(err)
+		assert.Equal(role.PublicId, res.LoginRole.Id)
+		assert.Equal(am.PublicId, res.AuthMethod.Id)
+		assert.Equal(u.PublicId, res.User.Id)
+		assert.Equal(org.PublicId, res.Org.Id)
+		for _, r := range []*base.BootstrapResource{res.LoginRole, res.AuthMethod, res.Account, res.User, res.AdminRole, res.Org} {
+			assert.False(r.Created, r.Id)
+		}
+		assertRoleGrants(t, repo, res.LoginRole.Id, 3, 1)
+		assertRoleGrants(t, repo, res.AdminRole.Id, 1, 1)
+	})
+}
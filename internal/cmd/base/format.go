@@ -144,6 +144,12 @@ func PrintApiError(in *api.Error) string {
 	if in.Op != "" {
 		nonAttributeMap["Operation"] = in.Op
 	}
+	if in.Code != "" {
+		nonAttributeMap["Code"] = in.Code
+	}
+	if in.RequestId != "" {
+		nonAttributeMap["Request ID"] = in.RequestId
+	}
 
 	maxLength := MaxAttributesLength(nonAttributeMap, nil, nil)
 
@@ -15,6 +15,24 @@ import (
 	"github.com/hashicorp/vault/sdk/helper/base62"
 )
 
+// Names of the initial resources. Bootstrap looks resources up by these names,
+// so changing them makes it create new resources on existing installations.
+const (
+	initialLoginRoleName  = "Login and Default Grants"
+	initialAdminRoleName  = "Administration"
+	initialAuthMethodName = "Generated global scope initial auth method"
+	initialUserName       = "admin"
+	initialOrgName        = "Generated org scope"
+)
+
+// initialLoginRoleGrants are the grants given to anonymous users by the
+// initial login role.
+var initialLoginRoleGrants = []string{
+	"type=scope;actions=list",
+	"id=*;type=auth-method;actions=authenticate,list",
+	"id={{account.id}};actions=read,change-password",
+}
+
 func (b *Server) CreateInitialLoginRole(ctx context.Context) (*iam.Role, error) {
 	rw := db.New(b.Database)
 
@@ -42,27 +60,10 @@ func (b *Server) CreateInitialLoginRole(ctx context.Context) (*iam.Role, error)
 		return nil, fmt.Errorf("unable to create repo for initial login role: %w", err)
 	}
 
-	pr, err := iam.NewRole(scope.Global.String(),
-		iam.WithName("Login and Default Grants"),
-		iam.WithDescription(`Role created for login capability and account self-management for users of the global scope at its creation time`),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error creating in memory role for generated grants: %w", err)
-	}
-	role, err := iamRepo.CreateRole(cancelCtx, pr)
+	role, _, err := ensureLoginRole(cancelCtx, iamRepo)
 	if err != nil {
 		return nil, fmt.Errorf("error creating role for default generated grants: %w", err)
 	}
-	if _, err := iamRepo.AddRoleGrants(cancelCtx, role.PublicId, role.Version, []string{
-		"type=scope;actions=list",
-		"id=*;type=auth-method;actions=authenticate,list",
-		"id={{account.id}};actions=read,change-password",
-	}); err != nil {
-		return nil, fmt.Errorf("error creating grant for default generated grants: %w", err)
-	}
-	if _, err := iamRepo.AddPrincipalRoles(cancelCtx, role.PublicId, role.Version+1, []string{"u_anon"}, nil); err != nil {
-		return nil, fmt.Errorf("error adding principal to role for default generated grants: %w", err)
-	}
 
 	return role, nil
 }
@@ -89,13 +90,6 @@ func (b *Server) CreateInitialAuthMethod(ctx context.Context) (*password.AuthMet
 	if err != nil {
 		return nil, nil, fmt.Errorf("error creating password repo: %w", err)
 	}
-	authMethod, err := password.NewAuthMethod(scope.Global.String(),
-		password.WithName("Generated global scope initial auth method"),
-		password.WithDescription("Provides initial administrative authentication into Boundary"),
-	)
-	if err != nil {
-		return nil, nil, fmt.Errorf("error creating new in memory auth method: %w", err)
-	}
 	if b.DevAuthMethodId == "" {
 		b.DevAuthMethodId, err = db.NewPublicId(password.AuthMethodPrefix)
 		if err != nil {
@@ -109,10 +103,9 @@ func (b *Server) CreateInitialAuthMethod(ctx context.Context) (*password.AuthMet
 		cancel()
 	}()
 
-	am, err := pwRepo.CreateAuthMethod(cancelCtx, authMethod,
-		password.WithPublicId(b.DevAuthMethodId))
+	am, err := createAuthMethod(cancelCtx, pwRepo, initialAuthMethodName, password.WithPublicId(b.DevAuthMethodId))
 	if err != nil {
-		return nil, nil, fmt.Errorf("error saving auth method to the db: %w", err)
+		return nil, nil, err
 	}
 	b.InfoKeys = append(b.InfoKeys, "generated auth method id")
 	b.Info["generated auth method id"] = b.DevAuthMethodId
@@ -134,13 +127,9 @@ func (b *Server) CreateInitialAuthMethod(ctx context.Context) (*password.AuthMet
 	b.InfoKeys = append(b.InfoKeys, "generated auth method password")
 	b.Info["generated auth method password"] = b.DevPassword
 
-	acct, err := password.NewAccount(b.DevAuthMethodId, password.WithLoginName(b.DevLoginName))
-	if err != nil {
-		return nil, nil, fmt.Errorf("error creating new in memory auth account: %w", err)
-	}
-	acct, err = pwRepo.CreateAccount(cancelCtx, scope.Global.String(), acct, password.WithPassword(b.DevPassword))
+	acct, err := createAccount(cancelCtx, pwRepo, b.DevAuthMethodId, b.DevLoginName, b.DevPassword)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error saving auth account to the db: %w", err)
+		return nil, nil, err
 	}
 	b.InfoKeys = append(b.InfoKeys, "generated auth method login name")
 	b.Info["generated auth method login name"] = acct.GetLoginName()
@@ -157,41 +146,74 @@ func (b *Server) CreateInitialAuthMethod(ctx context.Context) (*password.AuthMet
 			return nil, nil, fmt.Errorf("error generating initial user id: %w", err)
 		}
 	}
-	opts := []iam.Option{
-		iam.WithName("admin"),
-		iam.WithDescription(`Initial admin user within the "global" scope`),
-		iam.WithPublicId(b.DevUserId),
+	u, err := createAdminUser(cancelCtx, iamRepo, initialUserName, acct.GetPublicId(), iam.WithPublicId(b.DevUserId))
+	if err != nil {
+		return nil, nil, err
 	}
-	u, err := iam.NewUser(scope.Global.String(), opts...)
+	// Create a role tying them together
+	if _, _, err := ensureAdminRole(cancelCtx, iamRepo, u.GetPublicId()); err != nil {
+		return nil, nil, fmt.Errorf("error creating role for default generated grants: %w", err)
+	}
+
+	return am, u, nil
+}
+
+// CreateInitialRecoveryCodes generates single use recovery codes and their
+// TOTP secret for the account created by CreateInitialAuthMethod. Each code
+// can be used once in place of the account's password, together with a
+// current code of the TOTP secret.
+func (b *Server) CreateInitialRecoveryCodes(ctx context.Context) (*password.RecoveryCodes, error) {
+	rw := db.New(b.Database)
+
+	kmsRepo, err := kms.NewRepository(rw, rw)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error creating in memory user: %w", err)
+		return nil, fmt.Errorf("error creating kms repository: %w", err)
 	}
-	if u, err = iamRepo.CreateUser(cancelCtx, u, opts...); err != nil {
-		return nil, nil, fmt.Errorf("error creating initial admin user: %w", err)
+	kmsCache, err := kms.NewKms(kmsRepo, kms.WithLogger(b.Logger.Named("kms")))
+	if err != nil {
+		return nil, fmt.Errorf("error creating kms cache: %w", err)
 	}
-	if _, err = iamRepo.AddUserAccounts(cancelCtx, u.GetPublicId(), u.GetVersion(), []string{acct.GetPublicId()}); err != nil {
-		return nil, nil, fmt.Errorf("error associating initial admin user with account: %w", err)
+	if err := kmsCache.AddExternalWrappers(
+		kms.WithRootWrapper(b.RootKms),
+	); err != nil {
+		return nil, fmt.Errorf("error adding config keys to kms: %w", err)
 	}
-	// Create a role tying them together
-	pr, err := iam.NewRole(scope.Global.String(),
-		iam.WithName("Administration"),
-		iam.WithDescription(`Provides admin grants within the "global" scope to the initial user`),
-	)
+
+	pwRepo, err := password.NewRepository(rw, rw, kmsCache)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error creating in memory role for generated grants: %w", err)
+		return nil, fmt.Errorf("error creating password repo: %w", err)
 	}
-	defPermsRole, err := iamRepo.CreateRole(cancelCtx, pr)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-b.ShutdownCh:
+			cancel()
+		case <-cancelCtx.Done():
+		}
+	}()
+
+	accts, err := pwRepo.ListAccounts(cancelCtx, b.DevAuthMethodId)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error creating role for default generated grants: %w", err)
+		return nil, fmt.Errorf("error listing auth accounts: %w", err)
 	}
-	if _, err := iamRepo.AddRoleGrants(cancelCtx, defPermsRole.PublicId, defPermsRole.Version, []string{"id=*;type=*;actions=*"}); err != nil {
-		return nil, nil, fmt.Errorf("error creating grant for default generated grants: %w", err)
+	var acctId string
+	for _, acct := range accts {
+		if acct.GetLoginName() == b.DevLoginName {
+			acctId = acct.GetPublicId()
+			break
+		}
 	}
-	if _, err := iamRepo.AddPrincipalRoles(cancelCtx, defPermsRole.PublicId, defPermsRole.Version+1, []string{u.GetPublicId()}, nil); err != nil {
-		return nil, nil, fmt.Errorf("error adding principal to role for default generated grants: %w", err)
+	if acctId == "" {
+		return nil, fmt.Errorf("unable to find initial auth account with login name %q", b.DevLoginName)
 	}
 
-	return am, u, nil
+	codes, err := pwRepo.GenerateRecoveryCodes(cancelCtx, scope.Global.String(), acctId, password.DefaultRecoveryCodeCount)
+	if err != nil {
+		return nil, fmt.Errorf("error generating recovery codes: %w", err)
+	}
+	return codes, nil
 }
 
 func (b *Server) CreateInitialScopes(ctx context.Context) (*iam.Scope, *iam.Scope, error) {
@@ -229,19 +251,12 @@ func (b *Server) CreateInitialScopes(ctx context.Context) (*iam.Scope, *iam.Scop
 			return nil, nil, fmt.Errorf("error generating initial org id: %w", err)
 		}
 	}
-	opts := []iam.Option{
-		iam.WithName("Generated org scope"),
-		iam.WithDescription("Provides an initial org scope in Boundary"),
+	orgScope, err := createOrg(cancelCtx, iamRepo, initialOrgName, b.DevUserId,
 		iam.WithRandomReader(b.SecureRandomReader),
 		iam.WithPublicId(b.DevOrgId),
-	}
-	orgScope, err := iam.NewOrg(opts...)
-	if err != nil {
-		return nil, nil, fmt.Errorf("error creating new in memory org scope: %w", err)
-	}
-	orgScope, err = iamRepo.CreateScope(cancelCtx, orgScope, b.DevUserId, opts...)
+	)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error saving org scope to the db: %w", err)
+		return nil, nil, err
 	}
 	b.InfoKeys = append(b.InfoKeys, "generated org scope id")
 	b.Info["generated org scope id"] = b.DevOrgId
@@ -252,7 +267,7 @@ func (b *Server) CreateInitialScopes(ctx context.Context) (*iam.Scope, *iam.Scop
 			return nil, nil, fmt.Errorf("error generating initial project id: %w", err)
 		}
 	}
-	opts = []iam.Option{
+	opts := []iam.Option{
 		iam.WithName("Generated project scope"),
 		iam.WithDescription("Provides an initial project scope in Boundary"),
 		iam.WithRandomReader(b.SecureRandomReader),
@@ -438,3 +453,156 @@ func (b *Server) CreateInitialTarget(ctx context.Context) (target.Target, error)
 
 	return tt, nil
 }
+
+// ensureLoginRole returns the initial login role, creating it if needed. It
+// returns true if the role was created.
+func ensureLoginRole(ctx context.Context, repo *iam.Repository) (*iam.Role, bool, error) {
+	return ensureRole(ctx, repo, initialLoginRoleName,
+		`Role created for login capability and account self-management for users of the global scope at its creation time`,
+		initialLoginRoleGrants,
+		[]string{"u_anon"})
+}
+
+// ensureAdminRole returns the initial admin role, creating it if needed, with
+// the user as a principal. It returns true if the role was created.
+func ensureAdminRole(ctx context.Context, repo *iam.Repository, userId string) (*iam.Role, bool, error) {
+	return ensureRole(ctx, repo, initialAdminRoleName,
+		`Provides admin grants within the "global" scope to the initial user`,
+		[]string{"id=*;type=*;actions=*"},
+		[]string{userId})
+}
+
+// ensureRole returns the global role with the name, creating it if needed, and
+// adds the grants and principals it is missing. It returns true if the role
+// was created.
+func ensureRole(ctx context.Context, repo *iam.Repository, name, description string, grants, principals []string) (*iam.Role, bool, error) {
+	roles, err := repo.ListRoles(ctx, scope.Global.String(), iam.WithLimit(-1))
+	if err != nil {
+		return nil, false, fmt.Errorf("error listing roles: %w", err)
+	}
+	var role *iam.Role
+	for _, r := range roles {
+		if r.Name == name {
+			role = r
+			break
+		}
+	}
+	var created bool
+	if role == nil {
+		r, err := iam.NewRole(scope.Global.String(), iam.WithName(name), iam.WithDescription(description))
+		if err != nil {
+			return nil, false, fmt.Errorf("error creating in memory role: %w", err)
+		}
+		if role, err = repo.CreateRole(ctx, r); err != nil {
+			return nil, false, fmt.Errorf("error creating role: %w", err)
+		}
+		created = true
+	}
+	version := role.Version
+
+	currentGrants, err := repo.ListRoleGrants(ctx, role.PublicId, iam.WithLimit(-1))
+	if err != nil {
+		return nil, false, fmt.Errorf("error listing role grants: %w", err)
+	}
+	hasGrant := make(map[string]bool, len(currentGrants))
+	for _, g := range currentGrants {
+		hasGrant[g.RawGrant] = true
+	}
+	var missingGrants []string
+	for _, g := range grants {
+		if !hasGrant[g] {
+			missingGrants = append(missingGrants, g)
+		}
+	}
+	if len(missingGrants) > 0 {
+		if _, err := repo.AddRoleGrants(ctx, role.PublicId, version, missingGrants); err != nil {
+			return nil, false, fmt.Errorf("error adding role grants: %w", err)
+		}
+		version++
+	}
+
+	currentPrincipals, err := repo.ListPrincipalRoles(ctx, role.PublicId, iam.WithLimit(-1))
+	if err != nil {
+		return nil, false, fmt.Errorf("error listing role principals: %w", err)
+	}
+	hasPrincipal := make(map[string]bool, len(currentPrincipals))
+	for _, p := range currentPrincipals {
+		hasPrincipal[p.PrincipalId] = true
+	}
+	var missingPrincipals []string
+	for _, p := range principals {
+		if !hasPrincipal[p] {
+			missingPrincipals = append(missingPrincipals, p)
+		}
+	}
+	if len(missingPrincipals) > 0 {
+		if _, err := repo.AddPrincipalRoles(ctx, role.PublicId, version, missingPrincipals); err != nil {
+			return nil, false, fmt.Errorf("error adding role principals: %w", err)
+		}
+	}
+	return role, created, nil
+}
+
+// createAuthMethod creates a global password auth method with the name.
+func createAuthMethod(ctx context.Context, repo *password.Repository, name string, opt ...password.Option) (*password.AuthMethod, error) {
+	am, err := password.NewAuthMethod(scope.Global.String(),
+		password.WithName(name),
+		password.WithDescription("Provides initial administrative authentication into Boundary"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating new in memory auth method: %w", err)
+	}
+	if am, err = repo.CreateAuthMethod(ctx, am, opt...); err != nil {
+		return nil, fmt.Errorf("error saving auth method to the db: %w", err)
+	}
+	return am, nil
+}
+
+// createAccount creates an account of the global password auth method.
+func createAccount(ctx context.Context, repo *password.Repository, authMethodId, loginName, pw string) (*password.Account, error) {
+	acct, err := password.NewAccount(authMethodId, password.WithLoginName(loginName))
+	if err != nil {
+		return nil, fmt.Errorf("error creating new in memory auth account: %w", err)
+	}
+	if acct, err = repo.CreateAccount(ctx, scope.Global.String(), acct, password.WithPassword(pw)); err != nil {
+		return nil, fmt.Errorf("error saving auth account to the db: %w", err)
+	}
+	return acct, nil
+}
+
+// createAdminUser creates a global user with the name and associates it with
+// the account.
+func createAdminUser(ctx context.Context, repo *iam.Repository, name, accountId string, opt ...iam.Option) (*iam.User, error) {
+	opts := append([]iam.Option{
+		iam.WithName(name),
+		iam.WithDescription(`Initial admin user within the "global" scope`),
+	}, opt...)
+	u, err := iam.NewUser(scope.Global.String(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating in memory user: %w", err)
+	}
+	if u, err = repo.CreateUser(ctx, u, opts...); err != nil {
+		return nil, fmt.Errorf("error creating initial admin user: %w", err)
+	}
+	if _, err = repo.AddUserAccounts(ctx, u.GetPublicId(), u.GetVersion(), []string{accountId}); err != nil {
+		return nil, fmt.Errorf("error associating initial admin user with account: %w", err)
+	}
+	return u, nil
+}
+
+// createOrg creates an org scope with the name, granting the user admin rights
+// within it.
+func createOrg(ctx context.Context, repo *iam.Repository, name, userId string, opt ...iam.Option) (*iam.Scope, error) {
+	opts := append([]iam.Option{
+		iam.WithName(name),
+		iam.WithDescription("Provides an initial org scope in Boundary"),
+	}, opt...)
+	org, err := iam.NewOrg(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating new in memory org scope: %w", err)
+	}
+	if org, err = repo.CreateScope(ctx, org, userId, opts...); err != nil {
+		return nil, fmt.Errorf("error saving org scope to the db: %w", err)
+	}
+	return org, nil
+}
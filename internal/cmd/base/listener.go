@@ -14,6 +14,8 @@ import (
 	_ "crypto/sha512"
 
 	"github.com/hashicorp/boundary/internal/libs/alpnmux"
+	"github.com/hashicorp/boundary/internal/libs/endpoint"
+	"github.com/hashicorp/boundary/internal/libs/fips"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/shared-secure-libs/configutil"
 	"github.com/hashicorp/shared-secure-libs/listenerutil"
@@ -49,8 +51,10 @@ var BuiltinListeners = map[string]ListenerFactory{
 }
 
 // New creates a new listener of the given type with the given
-// configuration. The type is looked up in the BuiltinListeners map.
-func NewListener(l *configutil.Listener, logger hclog.Logger, ui cli.Ui) (*alpnmux.ALPNMux, map[string]string, reloadutil.ReloadFunc, error) {
+// configuration. The type is looked up in the BuiltinListeners map. If
+// fipsMode is enabled, the TLS of the listener is restricted to approved
+// algorithms.
+func NewListener(l *configutil.Listener, logger hclog.Logger, ui cli.Ui, fipsMode fips.Mode) (*alpnmux.ALPNMux, map[string]string, reloadutil.ReloadFunc, error) {
 	f, ok := BuiltinListeners[l.Type]
 	if !ok {
 		return nil, nil, nil, fmt.Errorf("unknown listener type: %q", l.Type)
@@ -102,6 +106,11 @@ func NewListener(l *configutil.Listener, logger hclog.Logger, ui cli.Ui) (*alpnm
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	if fipsMode.Enabled() {
+		if err := fips.RestrictTLS(tlsConfig); err != nil {
+			return nil, nil, nil, err
+		}
+	}
 	// Register no proto, "http/1.1", and "h2", with same TLS config
 	if _, err = alpnMux.RegisterProto("", tlsConfig); err != nil {
 		return nil, nil, nil, err
@@ -128,21 +137,16 @@ func tcpListenerFactory(purpose string, l *configutil.Listener, logger hclog.Log
 		}
 	}
 
-	host, port, err := net.SplitHostPort(l.Address)
+	defaultPort := "9200"
+	switch purpose {
+	case "cluster":
+		defaultPort = "9201"
+	case "proxy":
+		defaultPort = "9202"
+	}
+	host, port, err := endpoint.SplitHostPort(l.Address, defaultPort)
 	if err != nil {
-		if strings.Contains(err.Error(), "missing port") {
-			switch purpose {
-			case "cluster":
-				port = "9201"
-			case "proxy":
-				port = "9202"
-			default:
-				port = "9200"
-			}
-			host = l.Address
-		} else {
-			return "", nil, fmt.Errorf("error splitting host/port: %w", err)
-		}
+		return "", nil, fmt.Errorf("error splitting host/port: %w", err)
 	}
 
 	if host == "" {
@@ -155,8 +159,9 @@ func tcpListenerFactory(purpose string, l *configutil.Listener, logger hclog.Log
 	bindProto := "tcp"
 
 	// If they've passed 0.0.0.0, we only want to bind on IPv4
-	// rather than golang's dual stack default
-	if strings.HasPrefix(l.Address, "0.0.0.0:") || l.Address == "0.0.0.0" {
+	// rather than golang's dual stack default. :: keeps the dual stack
+	// default so IPv4 clients can still connect.
+	if host == "0.0.0.0" {
 		bindProto = "tcp4"
 	}
 
@@ -266,3 +271,27 @@ func (ln TCPKeepAliveListener) Accept() (net.Conn, error) {
 	}
 	return tc, nil
 }
+
+// validateFipsListener returns an error if l serves plaintext beyond the
+// host in FIPS mode. Cluster and proxy listeners use their own TLS, so only
+// API listeners can have TLS disabled.
+func validateFipsListener(l *configutil.Listener) error {
+	if !l.TLSDisable || l.Type == "unix" {
+		return nil
+	}
+	var api bool
+	for _, purpose := range l.Purpose {
+		api = api || strings.EqualFold(purpose, "api")
+	}
+	if !api {
+		return nil
+	}
+	host, _, err := endpoint.SplitHostPort(l.Address, "")
+	if err != nil {
+		return fmt.Errorf("error splitting host/port: %w", err)
+	}
+	if ip := net.ParseIP(host); host == "" || host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		return nil
+	}
+	return fmt.Errorf("Listener at %s cannot disable TLS in FIPS mode unless bound to a loopback address", l.Address)
+}
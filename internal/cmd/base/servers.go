@@ -20,8 +20,11 @@ import (
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/docker"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/libs/endpoint"
+	"github.com/hashicorp/boundary/internal/libs/fips"
 	"github.com/hashicorp/boundary/internal/types/scope"
 	"github.com/hashicorp/boundary/sdk/strutil"
+	"github.com/hashicorp/boundary/sdk/wrapper/pkcs11"
 	"github.com/hashicorp/boundary/version"
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/go-hclog"
@@ -53,6 +56,7 @@ type Server struct {
 	RootKms            wrapping.Wrapper
 	WorkerAuthKms      wrapping.Wrapper
 	RecoveryKms        wrapping.Wrapper
+	FipsMode           fips.Mode
 	Kms                *kms.Kms
 	SecureRandomReader io.Reader
 
@@ -285,7 +289,14 @@ func (b *Server) SetupListeners(ui cli.Ui, config *configutil.SharedConfig, allo
 			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
 		}
 
-		lnMux, props, reloadFunc, err := NewListener(lnConfig, b.Logger, ui)
+		if b.FipsMode.Enabled() {
+			lnConfig.TLSCipherSuites = fips.CipherSuites
+			if err := validateFipsListener(lnConfig); err != nil {
+				return err
+			}
+		}
+
+		lnMux, props, reloadFunc, err := NewListener(lnConfig, b.Logger, ui, b.FipsMode)
 		if err != nil {
 			return fmt.Errorf("Error initializing listener of type %s: %w", lnConfig.Type, err)
 		}
@@ -343,6 +354,22 @@ func (b *Server) SetupListeners(ui cli.Ui, config *configutil.SharedConfig, allo
 	return nil
 }
 
+// SetupFips sets the FIPS mode of the server and, if it is enabled, validates
+// the KMS configurations. It must be called before the KMSes and listeners
+// are set up.
+func (b *Server) SetupFips(config *config.Config) error {
+	b.FipsMode = fips.Resolve(config.FipsMode)
+	b.InfoKeys = append(b.InfoKeys, "fips mode")
+	b.Info["fips mode"] = b.FipsMode.String()
+	if !b.FipsMode.Enabled() {
+		return nil
+	}
+	if err := fips.ValidateKms(config.SharedConfig.Seals); err != nil {
+		return fmt.Errorf("Error validating KMS configuration: %w", err)
+	}
+	return nil
+}
+
 func (b *Server) SetupKMSes(ui cli.Ui, config *config.Config) error {
 	sharedConfig := config.SharedConfig
 	for _, kms := range sharedConfig.Seals {
@@ -360,21 +387,9 @@ func (b *Server) SetupKMSes(ui cli.Ui, config *config.Config) error {
 				return fmt.Errorf("Unknown KMS purpose %q", kms.Purpose)
 			}
 
-			kmsLogger := b.Logger.ResetNamed(fmt.Sprintf("kms-%s-%s", purpose, kms.Type))
-
-			origPurpose := kms.Purpose
-			kms.Purpose = []string{purpose}
-			wrapper, wrapperConfigError := configutil.ConfigureWrapper(kms, &b.InfoKeys, &b.Info, kmsLogger)
-			kms.Purpose = origPurpose
-			if wrapperConfigError != nil {
-				if !errwrap.ContainsType(wrapperConfigError, new(logical.KeyNotFoundError)) {
-					return fmt.Errorf(
-						"Error parsing KMS configuration: %s", wrapperConfigError)
-				}
-			}
-			if wrapper == nil {
-				return fmt.Errorf(
-					"After configuration nil KMS returned, KMS type was %s", kms.Type)
+			wrapper, err := b.configureKms(kms, purpose, &b.InfoKeys, &b.Info)
+			if err != nil {
+				return err
 			}
 
 			switch purpose {
@@ -418,6 +433,80 @@ func (b *Server) SetupKMSes(ui cli.Ui, config *config.Config) error {
 	return nil
 }
 
+// configureKms returns the wrapper of the KMS block for the purpose, adding
+// its information to infoKeys and info.
+func (b *Server) configureKms(kms *configutil.KMS, purpose string, infoKeys *[]string, info *map[string]string) (wrapping.Wrapper, error) {
+	kmsLogger := b.Logger.ResetNamed(fmt.Sprintf("kms-%s-%s", purpose, kms.Type))
+
+	origPurpose := kms.Purpose
+	kms.Purpose = []string{purpose}
+	var wrapper wrapping.Wrapper
+	var wrapperConfigError error
+	switch kms.Type {
+	case pkcs11.Type:
+		wrapper, wrapperConfigError = pkcs11.ConfigureWrapper(kms, infoKeys, info)
+	default:
+		wrapper, wrapperConfigError = configutil.ConfigureWrapper(kms, infoKeys, info, kmsLogger)
+	}
+	kms.Purpose = origPurpose
+	if wrapperConfigError != nil {
+		if !errwrap.ContainsType(wrapperConfigError, new(logical.KeyNotFoundError)) {
+			return nil, fmt.Errorf(
+				"Error parsing KMS configuration: %s", wrapperConfigError)
+		}
+	}
+	if wrapper == nil {
+		return nil, fmt.Errorf(
+			"After configuration nil KMS returned, KMS type was %s", kms.Type)
+	}
+	return wrapper, nil
+}
+
+// WorkerAuthKmsConfig returns the worker-auth KMS block of the configuration,
+// or nil if it has none.
+func WorkerAuthKmsConfig(config *config.Config) *configutil.KMS {
+	if config == nil || config.SharedConfig == nil {
+		return nil
+	}
+	for _, kms := range config.SharedConfig.Seals {
+		for _, purpose := range kms.Purpose {
+			if strings.ToLower(purpose) == "worker-auth" {
+				return kms
+			}
+		}
+	}
+	return nil
+}
+
+// ReloadWorkerAuthKms returns a new wrapper for the worker-auth KMS block of
+// the configuration, so the worker-auth KMS can be rotated without a restart.
+// The wrapper is finalized on shutdown, along with the one it replaces, which
+// is kept until then for the connections still using it.
+func (b *Server) ReloadWorkerAuthKms(config *config.Config) (wrapping.Wrapper, error) {
+	kms := WorkerAuthKmsConfig(config)
+	if kms == nil {
+		return nil, errors.New("no worker-auth KMS block found")
+	}
+	if b.FipsMode.Enabled() {
+		if err := fips.ValidateKms([]*configutil.KMS{kms}); err != nil {
+			return nil, fmt.Errorf("Error validating KMS configuration: %w", err)
+		}
+	}
+	var infoKeys []string
+	info := make(map[string]string)
+	wrapper, err := b.configureKms(kms, "worker-auth", &infoKeys, &info)
+	if err != nil {
+		return nil, err
+	}
+	b.ShutdownFuncs = append(b.ShutdownFuncs, func() error {
+		if err := wrapper.Finalize(context.Background()); err != nil {
+			return fmt.Errorf("Error finalizing kms of type %s and purpose worker-auth: %v", kms.Type, err)
+		}
+		return nil
+	})
+	return wrapper, nil
+}
+
 func (b *Server) RunShutdownFuncs() error {
 	var mErr *multierror.Error
 	for _, f := range b.ShutdownFuncs {
@@ -614,14 +703,9 @@ func (b *Server) SetupControllerPublicClusterAddress(conf *config.Config, flagVa
 			}
 		}
 	}
-	host, port, err := net.SplitHostPort(conf.Controller.PublicClusterAddr)
+	host, port, err := endpoint.SplitHostPort(conf.Controller.PublicClusterAddr, "9201")
 	if err != nil {
-		if strings.Contains(err.Error(), "missing port") {
-			port = "9201"
-			host = conf.Controller.PublicClusterAddr
-		} else {
-			return fmt.Errorf("Error splitting public cluster adddress host/port: %w", err)
-		}
+		return fmt.Errorf("Error splitting public cluster adddress host/port: %w", err)
 	}
 	conf.Controller.PublicClusterAddr = net.JoinHostPort(host, port)
 	return nil
@@ -645,14 +729,9 @@ func (b *Server) SetupWorkerPublicAddress(conf *config.Config, flagValue string)
 			}
 		}
 	}
-	host, port, err := net.SplitHostPort(conf.Worker.PublicAddr)
+	host, port, err := endpoint.SplitHostPort(conf.Worker.PublicAddr, "9202")
 	if err != nil {
-		if strings.Contains(err.Error(), "missing port") {
-			port = "9202"
-			host = conf.Worker.PublicAddr
-		} else {
-			return fmt.Errorf("Error splitting public adddress host/port: %w", err)
-		}
+		return fmt.Errorf("Error splitting public adddress host/port: %w", err)
 	}
 	conf.Worker.PublicAddr = net.JoinHostPort(host, port)
 	return nil
@@ -0,0 +1,129 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/authtokens"
+)
+
+const (
+	// tokenRenewalMinWindow is the minimum time before its expiration at which
+	// an auth token is refreshed, unless that is more than half of its
+	// remaining lifetime. Tokens are refreshed after 90% of their remaining
+	// lifetime when that is earlier.
+	tokenRenewalMinWindow = 5 * time.Minute
+
+	// tokenRenewalRetryInterval is the time between attempts to refresh an
+	// auth token after a failed attempt.
+	tokenRenewalRetryInterval = 30 * time.Second
+)
+
+// keyringToken is an auth token read from a keyring, and where it was read
+// from so renewed tokens can be saved in its place.
+type keyringToken struct {
+	keyringType string
+	tokenName   string
+	authToken   *authtokens.AuthToken
+}
+
+// RenewTokenInBackground keeps the auth token of client valid until ctx is
+// done, by refreshing it before it expires, so long running commands such as
+// connect keep working after the token they started with expires. Tokens read
+// from a keyring are replaced in the keyring with their refreshed token.
+//
+// Renewal stops once the token can not be refreshed anymore, e.g. because the
+// login reached the maximum auth token lifetime configured on the controller,
+// in which case a warning asks the user to authenticate again. Nothing is
+// done for clients using the recovery KMS or without a token.
+func (c *Command) RenewTokenInBackground(ctx context.Context, client *api.Client) {
+	if client.Token() == "" || client.RecoveryKmsWrapper() != nil {
+		return
+	}
+	go c.renewToken(ctx, client)
+}
+
+func (c *Command) renewToken(ctx context.Context, client *api.Client) {
+	expiration := c.tokenExpiration(ctx, client)
+	for {
+		timer := time.NewTimer(tokenRenewalWait(time.Until(expiration)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		result, err := authtokens.NewClient(client).Refresh(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return
+
+		case err != nil:
+			if apiErr := api.AsServerError(err); apiErr != nil && apiErr.ResponseStatus() == http.StatusBadRequest {
+				c.UI.Warn(fmt.Sprintf("Unable to refresh auth token: %s. The auth token expires at %s; authenticate again to keep using Boundary after that.",
+					PrintApiError(apiErr), expiration.Local().Format(time.RFC1123)))
+				return
+			}
+			if !expiration.IsZero() && time.Now().After(expiration) {
+				c.UI.Warn(fmt.Sprintf("Unable to refresh auth token before it expired: %s", err))
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(tokenRenewalRetryInterval):
+			}
+
+		default:
+			token := result.Item
+			client.SetToken(token.Token)
+			expiration = token.ExpirationTime
+			if c.keyringToken != nil {
+				if err := c.SaveTokenToKeyring(c.keyringToken.keyringType, c.keyringToken.tokenName, token); err != nil {
+					c.UI.Warn(fmt.Sprintf("Refreshed auth token could not be saved: %s", err))
+				}
+			}
+		}
+	}
+}
+
+// tokenExpiration returns the expiration time of the token of client, or the
+// zero time if it is unknown, which makes the token be refreshed right away.
+func (c *Command) tokenExpiration(ctx context.Context, client *api.Client) time.Time {
+	token := client.Token()
+	if c.keyringToken != nil && c.keyringToken.authToken.Token == token {
+		return c.keyringToken.authToken.ExpirationTime
+	}
+	// Tokens are the public id of the auth token followed by an encrypted
+	// part, e.g. at_1234567890_...
+	parts := strings.SplitN(token, "_", 3)
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	result, err := authtokens.NewClient(client).Read(ctx, parts[0]+"_"+parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	return result.Item.ExpirationTime
+}
+
+// tokenRenewalWait returns how long to wait before refreshing a token which
+// expires in remaining.
+func tokenRenewalWait(remaining time.Duration) time.Duration {
+	window := remaining / 10
+	if window < tokenRenewalMinWindow {
+		window = tokenRenewalMinWindow
+	}
+	if window > remaining/2 {
+		window = remaining / 2
+	}
+	if remaining <= 0 {
+		return 0
+	}
+	return remaining - window
+}
@@ -220,6 +220,11 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Func:    "decrypt",
 			}, nil
 		},
+		"config validate": func() (cli.Command, error) {
+			return &config.ValidateCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
 		"config get-token": func() (cli.Command, error) {
 			return &config.TokenCommand{
 				Command: base.NewCommand(ui),
@@ -286,6 +291,31 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Command: base.NewCommand(ui),
 			}, nil
 		},
+		"database bootstrap": func() (cli.Command, error) {
+			return &database.BootstrapCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"database export": func() (cli.Command, error) {
+			return &database.ExportCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"database export-compliance": func() (cli.Command, error) {
+			return &database.ExportComplianceCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"database import": func() (cli.Command, error) {
+			return &database.ImportCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"database repair": func() (cli.Command, error) {
+			return &database.RepairCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
 
 		"groups": func() (cli.Command, error) {
 			return &groups.Command{
@@ -376,6 +406,11 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Func:    "create",
 			}, nil
 		},
+		"host-catalogs import": func() (cli.Command, error) {
+			return &hostcatalogs.ImportCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
 		"host-catalogs update": func() (cli.Command, error) {
 			return &hostcatalogs.Command{
 				Command: base.NewCommand(ui),
@@ -1,14 +1,11 @@
 package authenticate
 
 import (
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
-	nkeyring "github.com/99designs/keyring"
 	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/api/authmethods"
 	"github.com/hashicorp/boundary/api/authtokens"
@@ -17,7 +14,6 @@ import (
 	"github.com/mitchellh/cli"
 	"github.com/mitchellh/go-wordwrap"
 	"github.com/posener/complete"
-	zkeyring "github.com/zalando/go-keyring"
 )
 
 var _ cli.Command = (*PasswordCommand)(nil)
@@ -30,8 +26,10 @@ var envAuthMethodId = "BOUNDARY_AUTHENTICATE_AUTH_METHOD_ID"
 type PasswordCommand struct {
 	*base.Command
 
-	flagLoginName string
-	flagPassword  string
+	flagLoginName    string
+	flagPassword     string
+	flagRecoveryCode string
+	flagTotpCode     string
 }
 
 func (c *PasswordCommand) Synopsis() string {
@@ -46,6 +44,10 @@ func (c *PasswordCommand) Help() string {
 		"",
 		`    $ boundary authenticate password -auth-method-id ampw_1234567890 -login-name foo -password "bar"`,
 		"",
+		"  Authenticate with a single use recovery code and a current code of its TOTP secret instead of the password:",
+		"",
+		`    $ boundary authenticate password -auth-method-id ampw_1234567890 -login-name admin -recovery-code "abcd-efgh-jkmn" -totp-code 123456`,
+		"",
 		"",
 	}) + c.Flags().Help()
 }
@@ -68,6 +70,18 @@ func (c *PasswordCommand) Flags() *base.FlagSets {
 		Usage:  "The password associated with the login name",
 	})
 
+	f.StringVar(&base.StringVar{
+		Name:   "recovery-code",
+		Target: &c.flagRecoveryCode,
+		Usage:  "A single use recovery code to authenticate with instead of the password",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "totp-code",
+		Target: &c.flagTotpCode,
+		Usage:  "The current code of the TOTP secret of the recovery codes, required with -recovery-code",
+	})
+
 	f.StringVar(&base.StringVar{
 		Name:   "auth-method-id",
 		EnvVar: "BOUNDARY_AUTH_METHOD_ID",
@@ -101,9 +115,15 @@ func (c *PasswordCommand) Run(args []string) int {
 	case c.FlagAuthMethodId == "":
 		c.UI.Error("Auth method ID must be provided via -auth-method-id")
 		return 1
+	case c.flagPassword != "" && c.flagRecoveryCode != "":
+		c.UI.Error("Only one of -password and -recovery-code can be provided")
+		return 1
+	case c.flagRecoveryCode != "" && c.flagTotpCode == "":
+		c.UI.Error("TOTP code must be provided via -totp-code with -recovery-code")
+		return 1
 	}
 
-	if c.flagPassword == "" {
+	if c.flagPassword == "" && c.flagRecoveryCode == "" {
 		fmt.Print("Password is not set as flag or in env, please enter it now (will be hidden): ")
 		value, err := password.Read(os.Stdin)
 		fmt.Print("\n")
@@ -123,11 +143,16 @@ func (c *PasswordCommand) Run(args []string) int {
 	// note: Authenticate() calls SetToken() under the hood to set the
 	// auth bearer on the client so we do not need to do anything with the
 	// returned token after this call, so we ignore it
-	result, err := authmethods.NewClient(client).Authenticate(c.Context, c.FlagAuthMethodId,
-		map[string]interface{}{
-			"login_name": c.flagLoginName,
-			"password":   c.flagPassword,
-		})
+	credentials := map[string]interface{}{
+		"login_name": c.flagLoginName,
+	}
+	if c.flagRecoveryCode != "" {
+		credentials["recovery_code"] = c.flagRecoveryCode
+		credentials["totp_code"] = c.flagTotpCode
+	} else {
+		credentials["password"] = c.flagPassword
+	}
+	result, err := authmethods.NewClient(client).Authenticate(c.Context, c.FlagAuthMethodId, credentials)
 	if err != nil {
 		if apiErr := api.AsServerError(err); apiErr != nil {
 			c.UI.Error(fmt.Sprintf("Error from controller when performing authentication: %s", base.PrintApiError(apiErr)))
@@ -168,41 +193,9 @@ func (c *PasswordCommand) Run(args []string) int {
 		tokenName != "none" &&
 		keyringType != "" &&
 		tokenName != "" {
-		marshaled, err := json.Marshal(token)
-		if err != nil {
-			c.UI.Error(fmt.Sprintf("Error marshaling auth token to save to keyring: %s", err))
+		if err := c.SaveTokenToKeyring(keyringType, tokenName, token); err != nil {
+			c.UI.Error(err.Error())
 			gotErr = true
-		} else {
-			switch keyringType {
-			case "wincred", "keychain":
-				if err := zkeyring.Set("HashiCorp Boundary Auth Token", tokenName, base64.RawStdEncoding.EncodeToString(marshaled)); err != nil {
-					c.UI.Error(fmt.Sprintf("Error saving auth token to %q keyring: %s", keyringType, err))
-					gotErr = true
-				}
-
-			default:
-				krConfig := nkeyring.Config{
-					LibSecretCollectionName: "login",
-					PassPrefix:              "HashiCorp_Boundary",
-					AllowedBackends:         []nkeyring.BackendType{nkeyring.BackendType(keyringType)},
-				}
-
-				kr, err := nkeyring.Open(krConfig)
-				if err != nil {
-					c.UI.Error(fmt.Sprintf("Error opening %q keyring: %s", keyringType, err))
-					gotErr = true
-					break
-				}
-
-				if err := kr.Set(nkeyring.Item{
-					Key:  tokenName,
-					Data: []byte(base64.RawStdEncoding.EncodeToString(marshaled)),
-				}); err != nil {
-					c.UI.Error(fmt.Sprintf("Error storing token in %q keyring: %s", keyringType, err))
-					gotErr = true
-					break
-				}
-			}
 		}
 	}
 
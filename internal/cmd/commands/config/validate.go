@@ -0,0 +1,210 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/sdk/wrapper"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	_ "github.com/lib/pq"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*ValidateCommand)(nil)
+var _ cli.CommandAutocomplete = (*ValidateCommand)(nil)
+
+// databaseCheckTimeout bounds how long the optional database check waits for
+// the database to answer.
+const databaseCheckTimeout = 10 * time.Second
+
+type ValidateCommand struct {
+	*base.Command
+
+	flagConfig        string
+	flagConfigKms     string
+	flagCheckDatabase bool
+}
+
+func (c *ValidateCommand) Synopsis() string {
+	return "Validate Boundary's configuration file"
+}
+
+func (c *ValidateCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary config validate [options]",
+		"",
+		"  Parse and validate a controller and/or worker configuration file without starting a server, reporting all problems found with their file and line positions:",
+		"",
+		"    $ boundary config validate -config=/etc/boundary/controller.hcl",
+		"",
+		"  The command exits with status 1 if any problem is found, so it can be used in CI pipelines. The same validation is performed by \"boundary server -validate-config\".",
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *ValidateCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:   "config",
+		Target: &c.flagConfig,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: "Path to the configuration file to validate.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "config-kms",
+		Target: &c.flagConfigKms,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: `Path to a configuration file containing a "kms" block marked for "config" purpose, to perform decryption of the main configuration file. If not set, will look for such a block in the main configuration file, which has some drawbacks; see the help output for "boundary config encrypt -h" for details.`,
+	})
+
+	f.BoolVar(&base.BoolVar{
+		Name:   "check-database",
+		Target: &c.flagCheckDatabase,
+		Usage:  "If set, also checks that the database of the controller can be reached with its configured URL.",
+	})
+
+	return set
+}
+
+func (c *ValidateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ValidateCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ValidateCommand) Run(args []string) int {
+	f := c.Flags()
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if len(c.flagConfig) == 0 {
+		c.UI.Error("Must specify a config file using -config")
+		return 1
+	}
+
+	wrapperPath := c.flagConfig
+	if c.flagConfigKms != "" {
+		wrapperPath = c.flagConfigKms
+	}
+	wrapper, err := wrapper.GetWrapperFromPath(wrapperPath, "config")
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if wrapper != nil {
+		if err := wrapper.Init(c.Context); err != nil {
+			c.UI.Error(fmt.Errorf("Could not initialize kms: %w", err).Error())
+			return 1
+		}
+		defer func() {
+			if err := wrapper.Finalize(c.Context); err != nil {
+				c.UI.Warn(fmt.Errorf("Error finalizing config kms: %w", err).Error())
+			}
+		}()
+	}
+
+	return RunValidate(c.Command, c.flagConfig, wrapper, c.flagCheckDatabase)
+}
+
+// RunValidate validates the configuration file at path, decrypted with the
+// wrapper if not nil, prints the problems found in the output format of the
+// command and returns the exit code. If checkDatabase is true and the file is
+// otherwise valid, it also checks that the controller's database can be
+// reached.
+func RunValidate(c *base.Command, path string, wrapper wrapping.Wrapper, checkDatabase bool) int {
+	problems, err := config.ValidateFile(path, wrapper)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error reading config file: %w", err).Error())
+		return 1
+	}
+	if len(problems) == 0 && checkDatabase {
+		if err := checkDatabaseReachable(c.Context, path, wrapper); err != nil {
+			problems = append(problems, &config.ValidationError{Message: err.Error()})
+		}
+	}
+
+	switch base.Format(c.UI) {
+	case "json":
+		out := make([]map[string]interface{}, 0, len(problems))
+		for _, p := range problems {
+			m := map[string]interface{}{
+				"file":    path,
+				"message": p.Message,
+			}
+			if p.Pos.IsValid() {
+				m["line"] = p.Pos.Line
+				m["column"] = p.Pos.Column
+			}
+			out = append(out, m)
+		}
+		b, err := base.JsonFormatter{}.Format(map[string]interface{}{
+			"valid":  len(problems) == 0,
+			"errors": out,
+		})
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+	default:
+		if len(problems) == 0 {
+			c.UI.Info(fmt.Sprintf("Configuration file %s is valid.", path))
+			break
+		}
+		for _, p := range problems {
+			c.UI.Error(p.Error())
+		}
+		c.UI.Error(fmt.Sprintf("Found %d problem(s) in configuration file %s.", len(problems), path))
+	}
+
+	if len(problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// checkDatabaseReachable pings the database of the controller in the
+// configuration file. It does nothing for a configuration without controller.
+func checkDatabaseReachable(ctx context.Context, path string, wrapper wrapping.Wrapper) error {
+	conf, err := config.LoadFile(path, wrapper)
+	if err != nil {
+		return err
+	}
+	if conf.Controller == nil || conf.Controller.Database == nil {
+		return nil
+	}
+	dbaseUrl, err := config.ParseAddress(conf.Controller.Database.Url)
+	if err != nil && err != config.ErrNotAUrl {
+		return fmt.Errorf("error parsing database url: %w", err)
+	}
+	dbase, err := sql.Open("postgres", strings.TrimSpace(dbaseUrl))
+	if err != nil {
+		return fmt.Errorf("unable to open database: %w", err)
+	}
+	defer dbase.Close()
+	ctx, cancel := context.WithTimeout(ctx, databaseCheckTimeout)
+	defer cancel()
+	if err := dbase.PingContext(ctx); err != nil {
+		return fmt.Errorf("unable to reach database: %w", err)
+	}
+	return nil
+}
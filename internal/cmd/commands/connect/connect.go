@@ -26,6 +26,7 @@ import (
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	targetspb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
 	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/boundary/sdk/strutil"
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 	"github.com/mitchellh/cli"
@@ -37,6 +38,13 @@ import (
 	"nhooyr.io/websocket/wspb"
 )
 
+const (
+	// resumeInitialBackoff and resumeMaxBackoff bound how long the client
+	// waits between attempts to resume a connection
+	resumeInitialBackoff = 250 * time.Millisecond
+	resumeMaxBackoff     = 5 * time.Second
+)
+
 type SessionInfo struct {
 	Address         string    `json:"address"`
 	Port            int       `json:"port"`
@@ -44,6 +52,8 @@ type SessionInfo struct {
 	Expiration      time.Time `json:"expiration"`
 	ConnectionLimit int32     `json:"connection_limit"`
 	SessionId       string    `json:"session_id"`
+
+	ConnectionMetadata map[string]string `json:"connection_metadata,omitempty"`
 }
 
 type ConnectionInfo struct {
@@ -68,6 +78,12 @@ type Command struct {
 	flagHostId     string
 	flagExec       string
 	flagUsername   string
+	flagProtocol   string
+
+	flagWorkerRegion string
+	flagWorkerZone   string
+
+	flagHeartbeatInterval time.Duration
 
 	// HTTP
 	httpFlags
@@ -85,6 +101,10 @@ type Command struct {
 
 	sessionAuthzData *targetspb.SessionAuthorizationData
 
+	// connectionMetadata is the connection metadata of the target returned
+	// with the session authorization, if any
+	connectionMetadata map[string]string
+
 	connWg             *sync.WaitGroup
 	listenerCloseOnce  sync.Once
 	listener           *net.TCPListener
@@ -174,7 +194,7 @@ func (c *Command) Flags() *base.FlagSets {
 		Target:     &c.flagExec,
 		EnvVar:     "BOUNDARY_CONNECT_EXEC",
 		Completion: complete.PredictAnything,
-		Usage:      `If set, after connecting to the worker, the given binary will be executed. This should be a binary on your path, or an absolute path. If all command flags are followed by " -- " (space, two hyphens, space), then any arguments after that will be sent directly to the binary.`,
+		Usage:      `If set, after connecting to the worker, the given binary will be executed. This should be a binary on your path, or an absolute path. If all command flags are followed by " -- " (space, two hyphens, space), then any arguments after that will be sent directly to the binary. The connection metadata of the target is passed to the binary as BOUNDARY_CONNECTION_METADATA_<KEY> environment variables, and replaces {{boundary.metadata.<key>}} in its arguments.`,
 	})
 
 	f.StringVar(&base.StringVar{
@@ -199,6 +219,39 @@ func (c *Command) Flags() *base.FlagSets {
 		Usage:      "Target scope name, if authorizing the session via scope parameters and target name. Mutually exclusive with -scope-id.",
 	})
 
+	f.StringVar(&base.StringVar{
+		Name:       "protocol",
+		Target:     &c.flagProtocol,
+		EnvVar:     "BOUNDARY_CONNECT_PROTOCOL",
+		Completion: complete.PredictAnything,
+		Usage:      "The subprotocol of a custom protocol handler of the worker to proxy connections with. If not set, connections are proxied as plain TCP.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "worker-region",
+		Target:     &c.flagWorkerRegion,
+		EnvVar:     "BOUNDARY_CONNECT_WORKER_REGION",
+		Completion: complete.PredictAnything,
+		Usage:      "Prefer the workers of the session in this region, as advertised by their configuration. If no worker matches, the worker chosen by the controller is used.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "worker-zone",
+		Target:     &c.flagWorkerZone,
+		EnvVar:     "BOUNDARY_CONNECT_WORKER_ZONE",
+		Completion: complete.PredictAnything,
+		Usage:      "Prefer the workers of the session in this zone, over those only in the region set with -worker-region.",
+	})
+
+	f.DurationVar(&base.DurationVar{
+		Name:       "heartbeat-interval",
+		Target:     &c.flagHeartbeatInterval,
+		Default:    proxy.DefaultHeartbeatInterval,
+		EnvVar:     "BOUNDARY_CONNECT_HEARTBEAT_INTERVAL",
+		Completion: complete.PredictAnything,
+		Usage:      "How often to ping the worker over each proxied connection. Connections whose worker does not answer within the interval are closed. Set to 0 to disable.",
+	})
+
 	f.BoolVar(&base.BoolVar{
 		Name:       "output-json-errors",
 		Target:     &c.outputJsonErrors,
@@ -346,6 +399,7 @@ func (c *Command) Run(args []string) (retCode int) {
 			// and pull the token out of there
 			sessionAuthz := new(targets.SessionAuthorization)
 			if err := json.Unmarshal([]byte(authzString), sessionAuthz); err == nil {
+				c.connectionMetadata = sessionAuthz.ConnectionMetadata
 				authzString = sessionAuthz.AuthorizationToken
 			}
 		}
@@ -386,7 +440,13 @@ func (c *Command) Run(args []string) (retCode int) {
 			c.Error(fmt.Sprintf("Error trying to authorize a session against target: %s", err.Error()))
 			return 2
 		}
-		authzString = sar.GetItem().(*targets.SessionAuthorization).AuthorizationToken
+		sessionAuthz := sar.GetItem().(*targets.SessionAuthorization)
+		authzString = sessionAuthz.AuthorizationToken
+		c.connectionMetadata = sessionAuthz.ConnectionMetadata
+
+		// Sessions stay authorized as long as the auth token or one refreshed
+		// from it is valid, so keep refreshing it while connected
+		c.RenewTokenInBackground(c.Context, client)
 	}
 
 	marshaled, err := base58.FastBase58Decoding(authzString)
@@ -411,7 +471,7 @@ func (c *Command) Run(args []string) (retCode int) {
 	}
 
 	c.connectionsLeft.Store(c.sessionAuthzData.ConnectionLimit)
-	workerAddr := c.sessionAuthzData.GetWorkerInfo()[0].GetAddress()
+	workerAddr := preferredWorker(c.sessionAuthzData.GetWorkerInfo(), c.flagWorkerRegion, c.flagWorkerZone).GetAddress()
 
 	parsedCert, err := x509.ParseCertificate(c.sessionAuthzData.Certificate)
 	if err != nil {
@@ -488,6 +548,8 @@ func (c *Command) Run(args []string) (retCode int) {
 			Expiration:      c.expiration,
 			ConnectionLimit: c.sessionAuthzData.GetConnectionLimit(),
 			SessionId:       c.sessionAuthzData.GetSessionId(),
+
+			ConnectionMetadata: c.connectionMetadata,
 		}
 
 		switch base.Format(c.UI) {
@@ -619,6 +681,90 @@ func (c *Command) handleConnection(
 
 	defer c.connWg.Done()
 
+	// Prefer connections which survive losing the websocket to the worker
+	subprotocols := []string{globals.TcpProxyResumableV1, globals.TcpProxyV1}
+	if c.flagProtocol != "" {
+		subprotocols = []string{c.flagProtocol}
+	}
+	conn, negProto, handshakeResult, err := c.dialWorker(workerAddr, tofuToken, transport, subprotocols, nil)
+	if err != nil {
+		return err
+	}
+
+	if handshakeResult.GetConnectionsLeft() != -1 {
+		c.connsLeftCh <- handshakeResult.GetConnectionsLeft()
+	}
+
+	if negProto == globals.TcpProxyResumableV1 {
+		return c.proxyResumable(listeningConn, conn, workerAddr, tofuToken, transport)
+	}
+
+	// Get a wrapped net.Conn so we can use io.Copy
+	netConn := websocket.NetConn(c.proxyCtx, conn, websocket.MessageBinary)
+
+	// Close the connection if the worker stops answering heartbeats, so a
+	// half-open connection doesn't hang until the session expires
+	heartbeatCtx, heartbeatCancel := context.WithCancel(c.proxyCtx)
+	defer heartbeatCancel()
+	go func() {
+		if err := proxy.Heartbeat(heartbeatCtx, conn, c.flagHeartbeatInterval); err != nil {
+			c.Error(fmt.Sprintf("Closing connection: %s", err))
+			listeningConn.Close()
+			netConn.Close()
+		}
+	}()
+
+	localWg := new(sync.WaitGroup)
+	localWg.Add(2)
+
+	go func() {
+		defer localWg.Done()
+		io.Copy(netConn, listeningConn)
+		netConn.Close()
+		listeningConn.Close()
+	}()
+	go func() {
+		defer localWg.Done()
+		io.Copy(listeningConn, netConn)
+		listeningConn.Close()
+		netConn.Close()
+	}()
+	localWg.Wait()
+
+	return nil
+}
+
+// dialWorker opens a websocket to the worker offering subprotocols, sending
+// header if set, and runs the proxy handshake. It returns the websocket, the
+// negotiated subprotocol and the handshake result.
+// preferredWorker returns the first of the workers, ordered by the
+// controller, in the zone, else the first in the region, else the first
+// worker. An empty zone or region matches no worker.
+func preferredWorker(workers []*targetspb.WorkerInfo, region, zone string) *targetspb.WorkerInfo {
+	if zone != "" {
+		for _, w := range workers {
+			if w.GetZone() == zone && (region == "" || w.GetRegion() == region) {
+				return w
+			}
+		}
+	}
+	if region != "" {
+		for _, w := range workers {
+			if w.GetRegion() == region {
+				return w
+			}
+		}
+	}
+	return workers[0]
+}
+
+func (c *Command) dialWorker(
+	workerAddr string,
+	tofuToken string,
+	transport *http.Transport,
+	subprotocols []string,
+	header http.Header) (*websocket.Conn, string, *proxy.HandshakeResult, error) {
+
 	conn, resp, err := websocket.Dial(
 		c.proxyCtx,
 		fmt.Sprintf("wss://%s/v1/proxy", workerAddr),
@@ -626,34 +772,35 @@ func (c *Command) handleConnection(
 			HTTPClient: &http.Client{
 				Transport: transport,
 			},
-			Subprotocols: []string{globals.TcpProxyV1},
+			HTTPHeader:   header,
+			Subprotocols: subprotocols,
 		},
 	)
 	if err != nil {
 		switch {
 		case strings.Contains(err.Error(), "tls: internal error"):
-			return errors.New("Session is unauthorized")
+			return nil, "", nil, errors.New("Session is unauthorized")
 		case strings.Contains(err.Error(), "connect: connection refused"):
-			return fmt.Errorf("Unable to connect to worker at %s", workerAddr)
+			return nil, "", nil, fmt.Errorf("Unable to connect to worker at %s", workerAddr)
 		default:
-			return fmt.Errorf("Error dialing the worker: %w", err)
+			return nil, "", nil, fmt.Errorf("Error dialing the worker: %w", err)
 		}
 	}
 
 	if resp == nil {
-		return errors.New("Response from worker is nil")
+		return nil, "", nil, errors.New("Response from worker is nil")
 	}
 	if resp.Header == nil {
-		return errors.New("Response header is nil")
+		return nil, "", nil, errors.New("Response header is nil")
 	}
 	negProto := resp.Header.Get("Sec-WebSocket-Protocol")
-	if negProto != globals.TcpProxyV1 {
-		return fmt.Errorf("Unexpected negotiated protocol: %s", negProto)
+	if !strutil.StrListContains(subprotocols, negProto) {
+		return nil, "", nil, fmt.Errorf("Unexpected negotiated protocol: %s", negProto)
 	}
 
 	handshake := proxy.ClientHandshake{TofuToken: tofuToken}
 	if err := wspb.Write(c.proxyCtx, conn, &handshake); err != nil {
-		return fmt.Errorf("error sending handshake to worker: %w", err)
+		return nil, "", nil, fmt.Errorf("error sending handshake to worker: %w", err)
 	}
 	var handshakeResult proxy.HandshakeResult
 	if err := wspb.Read(c.proxyCtx, conn, &handshakeResult); err != nil {
@@ -662,45 +809,130 @@ func (c *Command) handleConnection(
 			// There's no reason to think we'd be able to authorize any more
 			// connections after the first has failed
 			c.connsLeftCh <- 0
-			return errors.New("Unable to authorize connection")
+			return nil, "", nil, errors.New("Unable to authorize connection")
 		}
 		switch {
 		case strings.Contains(err.Error(), "tofu token not allowed"):
 			// Nothing will be able to be done here, so cancel the context too
 			c.proxyCancel()
-			return errors.New("Session is already in use")
+			return nil, "", nil, errors.New("Session is already in use")
+		case strings.Contains(err.Error(), "connection cannot be resumed"):
+			return nil, "", nil, fmt.Errorf("%w: %s", proxy.ErrCannotResume, err)
 		default:
-			return fmt.Errorf("error reading handshake result: %w", err)
+			return nil, "", nil, fmt.Errorf("error reading handshake result: %w", err)
 		}
 	}
+	return conn, negProto, &handshakeResult, nil
+}
 
-	if handshakeResult.GetConnectionsLeft() != -1 {
-		c.connsLeftCh <- handshakeResult.GetConnectionsLeft()
+// proxyResumable proxies listeningConn over a resumable connection on conn.
+// If the websocket to the worker is lost the connection is resumed over a new
+// one, so the local client only sees an error if the worker can't be reached
+// again within the grace period it announced.
+func (c *Command) proxyResumable(
+	listeningConn *net.TCPConn,
+	conn *websocket.Conn,
+	workerAddr string,
+	tofuToken string,
+	transport *http.Transport) error {
+
+	hello, err := proxy.ReadResumeHello(c.proxyCtx, conn)
+	if err != nil {
+		return fmt.Errorf("error reading resume hello: %w", err)
 	}
+	grace := time.Duration(hello.GracePeriodSeconds) * time.Second
 
-	// Get a wrapped net.Conn so we can use io.Copy
-	netConn := websocket.NetConn(c.proxyCtx, conn, websocket.MessageBinary)
+	ctx, cancel := context.WithCancel(c.proxyCtx)
+	defer cancel()
+	breaks := make(chan error, 1)
+	rc := proxy.NewResumableConn(ctx, conn, c.flagHeartbeatInterval, grace, func(err error) {
+		breaks <- err
+	})
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-breaks:
+				if err := c.resume(ctx, rc, hello.ConnectionId, grace, workerAddr, tofuToken, transport); err != nil && !rc.Closed() {
+					c.Error(fmt.Sprintf("Closing connection: %s", err))
+					rc.Close()
+				}
+			}
+		}
+	}()
 
 	localWg := new(sync.WaitGroup)
 	localWg.Add(2)
 
 	go func() {
 		defer localWg.Done()
-		io.Copy(netConn, listeningConn)
-		netConn.Close()
+		io.Copy(rc, listeningConn)
+		rc.Close()
 		listeningConn.Close()
 	}()
 	go func() {
 		defer localWg.Done()
-		io.Copy(listeningConn, netConn)
+		io.Copy(listeningConn, rc)
 		listeningConn.Close()
-		netConn.Close()
+		rc.Close()
 	}()
 	localWg.Wait()
 
 	return nil
 }
 
+// resume resumes rc over a new websocket to the worker, retrying with backoff
+// until the grace period of the connection is over.
+func (c *Command) resume(
+	ctx context.Context,
+	rc *proxy.ResumableConn,
+	connectionId string,
+	grace time.Duration,
+	workerAddr string,
+	tofuToken string,
+	transport *http.Transport) error {
+
+	deadline := time.Now().Add(grace)
+	backoff := resumeInitialBackoff
+	var lastErr error
+	for time.Now().Before(deadline) {
+		header := http.Header{}
+		header.Set(proxy.ResumeConnectionHeader, connectionId)
+		header.Set(proxy.ResumeReceivedHeader, strconv.FormatUint(rc.Received(), 10))
+		conn, _, _, err := c.dialWorker(workerAddr, tofuToken, transport, []string{globals.TcpProxyResumableV1}, header)
+		if err == nil {
+			var hello *proxy.ResumeHello
+			if hello, err = proxy.ReadResumeHello(ctx, conn); err == nil {
+				if _, err = rc.Resume(conn, hello.Received, nil); err == nil {
+					return nil
+				}
+			}
+			conn.Close(websocket.StatusGoingAway, "unable to resume connection")
+		}
+		lastErr = err
+		switch {
+		case errors.Is(err, proxy.ErrCannotResume),
+			websocket.CloseStatus(err) == websocket.StatusPolicyViolation,
+			ctx.Err() != nil:
+			// Retrying won't help
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > resumeMaxBackoff {
+			backoff = resumeMaxBackoff
+		}
+	}
+	if lastErr == nil {
+		lastErr = errors.New("grace period is over")
+	}
+	return fmt.Errorf("unable to resume connection: %w", lastErr)
+}
+
 func (c *Command) updateConnsLeft(connsLeft int32) {
 	c.connectionsLeft.Store(connsLeft)
 
@@ -770,6 +1002,9 @@ func (c *Command) handleExec(passthroughArgs []string) {
 		args[i] = stringReplacer(args[i], "port", port)
 		args[i] = stringReplacer(args[i], "ip", ip)
 		args[i] = stringReplacer(args[i], "addr", addr)
+		for k, v := range c.connectionMetadata {
+			args[i] = stringReplacer(args[i], "metadata."+k, v)
+		}
 	}
 
 	// NOTE: exec.CommandContext is a hard kill, so if used it leaves the
@@ -781,6 +1016,9 @@ func (c *Command) handleExec(passthroughArgs []string) {
 		fmt.Sprintf("BOUNDARY_PROXIED_IP=%s", ip),
 		fmt.Sprintf("BOUNDARY_PROXIED_ADDR=%s", addr),
 	)
+	for k, v := range c.connectionMetadata {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("BOUNDARY_CONNECTION_METADATA_%s=%s", strings.ToUpper(k), v))
+	}
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
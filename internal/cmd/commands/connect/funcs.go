@@ -24,9 +24,31 @@ func generateSessionInfoTableOutput(in SessionInfo) string {
 		base.WrapMap(2, maxLength+2, nonAttributeMap),
 	}
 
+	if len(in.ConnectionMetadata) > 0 {
+		metadataMap := make(map[string]interface{}, len(in.ConnectionMetadata))
+		for k, v := range in.ConnectionMetadata {
+			metadataMap[k] = v
+		}
+		ret = append(ret,
+			"",
+			"  Connection Metadata:",
+			base.WrapMap(4, 0, metadataMap),
+		)
+	}
+
 	return base.WrapForHelpText(ret)
 }
 
+// username returns the username to pass through to the client: the one set
+// with -username, or else the username in the connection metadata of the
+// target, if any.
+func (c *Command) username() string {
+	if c.flagUsername != "" {
+		return c.flagUsername
+	}
+	return c.connectionMetadata["username"]
+}
+
 func generateConnectionInfoTableOutput(in ConnectionInfo) string {
 	var ret []string
 
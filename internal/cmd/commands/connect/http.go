@@ -38,7 +38,7 @@ func httpOptions(c *Command, set *base.FlagSets) {
 		Target:     &c.flagHttpPath,
 		EnvVar:     "BOUNDARY_CONNECT_HTTP_PATH",
 		Completion: complete.PredictNothing,
-		Usage:      `Specifies a path that will be appended to the generated URL.`,
+		Usage:      `Specifies a path that will be appended to the generated URL. Defaults to the "path" connection metadata of the target, if set.`,
 	})
 
 	f.StringVar(&base.StringVar{
@@ -96,8 +96,12 @@ func (h *httpFlags) buildArgs(c *Command, port, ip, addr string) ([]string, erro
 		} else {
 			uri = fmt.Sprintf("%s://%s", h.flagHttpScheme, addr)
 		}
-		if h.flagHttpPath != "" {
-			uri = fmt.Sprintf("%s/%s", uri, strings.TrimPrefix(h.flagHttpPath, "/"))
+		path := h.flagHttpPath
+		if path == "" {
+			path = c.connectionMetadata["path"]
+		}
+		if path != "" {
+			uri = fmt.Sprintf("%s/%s", uri, strings.TrimPrefix(path, "/"))
 		}
 		args = append(args, uri)
 	}
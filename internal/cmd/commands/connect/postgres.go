@@ -28,7 +28,7 @@ func postgresOptions(c *Command, set *base.FlagSets) {
 		Target:     &c.flagUsername,
 		EnvVar:     "BOUNDARY_CONNECT_USERNAME",
 		Completion: complete.PredictNothing,
-		Usage:      `Specifies the username to pass through to the client`,
+		Usage:      `Specifies the username to pass through to the client. Defaults to the "username" connection metadata of the target, if set.`,
 	})
 }
 
@@ -45,8 +45,11 @@ func (p *postgresFlags) buildArgs(c *Command, port, ip, addr string) []string {
 	switch p.flagPostgresStyle {
 	case "psql":
 		args = append(args, "-p", port, "-h", ip)
-		if c.flagUsername != "" {
-			args = append(args, "-U", c.flagUsername)
+		if username := c.username(); username != "" {
+			args = append(args, "-U", username)
+		}
+		if database := c.connectionMetadata["database"]; database != "" {
+			args = append(args, "-d", database)
 		}
 	}
 	return args
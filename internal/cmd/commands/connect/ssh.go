@@ -29,7 +29,7 @@ func sshOptions(c *Command, set *base.FlagSets) {
 		Target:     &c.flagUsername,
 		EnvVar:     "BOUNDARY_CONNECT_USERNAME",
 		Completion: complete.PredictNothing,
-		Usage:      `Specifies the username to pass through to the client`,
+		Usage:      `Specifies the username to pass through to the client. Defaults to the "username" connection metadata of the target, if set.`,
 	})
 }
 
@@ -51,8 +51,8 @@ func (s *sshFlags) buildArgs(c *Command, port, ip, addr string) []string {
 	case "putty":
 		args = append(args, "-P", port, ip)
 	}
-	if c.flagUsername != "" {
-		args = append(args, "-l", c.flagUsername)
+	if username := c.username(); username != "" {
+		args = append(args, "-l", username)
 	}
 	return args
 }
@@ -0,0 +1,437 @@
+package database
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/migrations"
+	"github.com/hashicorp/boundary/sdk/wrapper"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/shared-secure-libs/configutil"
+	"github.com/hashicorp/vault/sdk/helper/base62"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*BootstrapCommand)(nil)
+var _ cli.CommandAutocomplete = (*BootstrapCommand)(nil)
+
+type BootstrapCommand struct {
+	*base.Command
+	srv *base.Server
+
+	Config *config.Config
+
+	configWrapper wrapping.Wrapper
+
+	flagConfig             string
+	flagConfigKms          string
+	flagLogLevel           string
+	flagLogFormat          string
+	flagMigrationUrl       string
+	flagAllowDevMigrations bool
+	flagOrgName            string
+	flagAuthMethodName     string
+	flagLoginName          string
+	flagUserName           string
+	flagPassword           string
+	flagPasswordOutput     string
+	flagPasswordOutputKms  string
+}
+
+func (c *BootstrapCommand) Synopsis() string {
+	return "Non-interactively initialize Boundary's database and initial resources"
+}
+
+func (c *BootstrapCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary database bootstrap [options]",
+		"",
+		"  Migrate Boundary's database and create its initial resources for automated installs, printing the resources as JSON:",
+		"",
+		"    $ boundary database bootstrap -config=/etc/boundary/controller.hcl -password=env://BOUNDARY_ADMIN_PASSWORD",
+		"",
+		"  The following resources are created, in the global scope unless indicated:",
+		"",
+		"    Global-scope KMS keys",
+		"    Initial Login Role",
+		"    Password-Type Auth Method",
+		"      Admin Account",
+		"    Admin User",
+		"    Administration Role",
+		"    Org Scope",
+		"",
+		"  Bootstrapping is idempotent: resources which already exist, found by their names, are left unchanged and reported with \"created\" set to false, so it can be run on every deployment. In particular the password of an existing admin account is not changed.",
+		"",
+		"  If no password is provided, one is generated and written to the file given by -password-output, encrypted with the \"config\" purpose KMS. It can be read back with:",
+		"",
+		"    $ boundary config decrypt -config=admin-password.hcl -config-kms=/etc/boundary/controller.hcl",
+		"",
+		"  For a full list of examples, please see the documentation.",
+	}) + c.Flags().Help()
+}
+
+func (c *BootstrapCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetNone)
+
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:   "config",
+		Target: &c.flagConfig,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: "Path to the configuration file.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "config-kms",
+		Target: &c.flagConfigKms,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: `Path to a configuration file containing a "kms" block marked for "config" purpose, to perform decryption of the main configuration file. If not set, will look for such a block in the main configuration file, which has some drawbacks; see the help output for "boundary config encrypt -h" for details.`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "log-level",
+		Target:     &c.flagLogLevel,
+		EnvVar:     "BOUNDARY_LOG_LEVEL",
+		Completion: complete.PredictSet("trace", "debug", "info", "warn", "err"),
+		Usage: "Log verbosity level. Supported values (in order of more detail to less) are " +
+			"\"trace\", \"debug\", \"info\", \"warn\", and \"err\".",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "log-format",
+		Target:     &c.flagLogFormat,
+		Completion: complete.PredictSet("standard", "json"),
+		Usage:      `Log format. Supported values are "standard" and "json".`,
+	})
+
+	f = set.NewFlagSet("Bootstrap Options")
+
+	f.BoolVar(&base.BoolVar{
+		Name:   "allow-development-migrations",
+		Target: &c.flagAllowDevMigrations,
+		Usage:  "If set the bootstrap will continue even if the schema includes database update steps that may not be supported in the next official release.  Boundary does not provide a rollback mechanism so a backup should be taken independently if needed.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "migration-url",
+		Target: &c.flagMigrationUrl,
+		Usage:  `If set, overrides a migration URL set in config, and specifies the URL used to connect to the database for migrations. This can refer to a file on disk (file://) from which a URL will be read; an env var (env://) from which the URL will be read; or a direct database URL.`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:    "org-name",
+		Target:  &c.flagOrgName,
+		Default: "Generated org scope",
+		Usage:   "The name of the initial org scope.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:    "auth-method-name",
+		Target:  &c.flagAuthMethodName,
+		Default: "Generated global scope initial auth method",
+		Usage:   "The name of the initial password auth method.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:    "login-name",
+		Target:  &c.flagLoginName,
+		Default: "admin",
+		Usage:   "The login name of the admin account.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:    "user-name",
+		Target:  &c.flagUserName,
+		Default: "admin",
+		Usage:   "The name of the admin user.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "password",
+		Target: &c.flagPassword,
+		Usage:  `The password of the admin account. This can refer to a file on disk (file://) from which the password will be read; an env var (env://) from which the password will be read; or a direct value. If not set, a password is generated and written to the file given by -password-output.`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "password-output",
+		Target: &c.flagPasswordOutput,
+		Usage:  `The file to write a generated password to, encrypted with the "config" purpose KMS. Required if -password is not set. The file is only written if the admin account is created.`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "password-output-kms",
+		Target: &c.flagPasswordOutputKms,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: `Path to a configuration file containing a "kms" block marked for "config" purpose, used to encrypt the generated password. If not set, the configuration file is used.`,
+	})
+
+	return set
+}
+
+func (c *BootstrapCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *BootstrapCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *BootstrapCommand) Run(args []string) int {
+	if result := c.ParseFlagsAndConfig(args); result > 0 {
+		return result
+	}
+
+	if c.configWrapper != nil {
+		defer func() {
+			if err := c.configWrapper.Finalize(c.Context); err != nil {
+				c.UI.Warn(fmt.Errorf("Error finalizing config kms: %w", err).Error())
+			}
+		}()
+	}
+
+	if migrations.DevMigration != c.flagAllowDevMigrations {
+		if migrations.DevMigration {
+			c.UI.Error(base.WrapAtLength("This version of the binary has " +
+				"dev database schema updates which may not be supported in the " +
+				"next official release. To proceed anyways please use the " +
+				"'-allow-development-migrations' flag."))
+			return 2
+		} else {
+			c.UI.Error(base.WrapAtLength("The '-allow-development-migrations' " +
+				"flag was set but this binary has no dev database schema updates."))
+			return 3
+		}
+	}
+
+	// Resolve the password before changing anything so that a generated one
+	// is stored before the account using it is created
+	var generatedPasswordFile string
+	password := c.flagPassword
+	if password != "" {
+		var err error
+		password, err = config.ParseAddress(password)
+		if err != nil && err != config.ErrNotAUrl {
+			c.UI.Error(fmt.Errorf("Error parsing password: %w", err).Error())
+			return 1
+		}
+		password = strings.TrimSpace(password)
+		if password == "" {
+			c.UI.Error("The password provided with -password is empty")
+			return 1
+		}
+	} else {
+		if c.flagPasswordOutput == "" {
+			c.UI.Error("Must specify either -password or -password-output")
+			return 1
+		}
+		var err error
+		if password, err = base62.Random(20); err != nil {
+			c.UI.Error(fmt.Errorf("Error generating password: %w", err).Error())
+			return 1
+		}
+		if generatedPasswordFile, err = c.writeSealedPassword(password); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		defer os.Remove(generatedPasswordFile)
+	}
+
+	c.srv = base.NewServer(&base.Command{UI: c.UI})
+
+	if err := c.srv.SetupLogging(c.flagLogLevel, c.flagLogFormat, c.Config.LogLevel, c.Config.LogFormat); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := c.srv.SetupFips(c.Config); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if err := c.srv.SetupKMSes(c.UI, c.Config); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer func() {
+		if err := c.srv.RunShutdownFuncs(); err != nil {
+			c.UI.Error(fmt.Errorf("Error running shutdown tasks: %w", err).Error())
+		}
+	}()
+
+	if c.srv.RootKms == nil {
+		c.UI.Error("Root KMS not found after parsing KMS blocks")
+		return 1
+	}
+
+	if c.Config.Controller.Database == nil {
+		c.UI.Error(`"controller.database" config block not found`)
+		return 1
+	}
+
+	urlToParse := c.Config.Controller.Database.Url
+	if urlToParse == "" {
+		c.UI.Error(`"url" not specified in "database" config block"`)
+		return 1
+	}
+	migrationUrlToParse := c.Config.Controller.Database.MigrationUrl
+	if c.flagMigrationUrl != "" {
+		migrationUrlToParse = c.flagMigrationUrl
+	}
+	if migrationUrlToParse == "" {
+		migrationUrlToParse = urlToParse
+	}
+
+	dbaseUrl, err := config.ParseAddress(urlToParse)
+	if err != nil && err != config.ErrNotAUrl {
+		c.UI.Error(fmt.Errorf("Error parsing database url: %w", err).Error())
+		return 1
+	}
+	migrationUrl, err := config.ParseAddress(migrationUrlToParse)
+	if err != nil && err != config.ErrNotAUrl {
+		c.UI.Error(fmt.Errorf("Error parsing migration url: %w", err).Error())
+		return 1
+	}
+
+	if _, err := db.InitStore("postgres", nil, strings.TrimSpace(migrationUrl)); err != nil {
+		c.UI.Error(fmt.Errorf("Error running database migrations: %w", err).Error())
+		return 1
+	}
+
+	c.srv.DatabaseUrl = strings.TrimSpace(dbaseUrl)
+	if err := c.srv.ConnectToDatabase("postgres"); err != nil {
+		c.UI.Error(fmt.Errorf("Error connecting to database after migrations: %w", err).Error())
+		return 1
+	}
+
+	res, err := c.srv.Bootstrap(c.Context, base.BootstrapRequest{
+		OrgName:        c.flagOrgName,
+		AuthMethodName: c.flagAuthMethodName,
+		LoginName:      c.flagLoginName,
+		UserName:       c.flagUserName,
+		Password:       password,
+	})
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error bootstrapping: %w", err).Error())
+		return 1
+	}
+
+	out := map[string]interface{}{
+		"login_role":  res.LoginRole,
+		"auth_method": res.AuthMethod,
+		"account":     res.Account,
+		"user":        res.User,
+		"admin_role":  res.AdminRole,
+		"org_scope":   res.Org,
+	}
+	if generatedPasswordFile != "" && res.Account.Created {
+		if err := os.Rename(generatedPasswordFile, c.flagPasswordOutput); err != nil {
+			c.UI.Error(fmt.Errorf("Error moving generated password file into place; the password was written to %s: %w", generatedPasswordFile, err).Error())
+			return 1
+		}
+		out["password_output"] = c.flagPasswordOutput
+	}
+
+	b, err := base.JsonFormatter{}.Format(out)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+		return 1
+	}
+	c.UI.Output(string(b))
+	return 0
+}
+
+// writeSealedPassword encrypts the password with the "config" purpose KMS and
+// writes it next to the password output file, returning the path written. The
+// file is moved into place once the account using the password is created.
+func (c *BootstrapCommand) writeSealedPassword(password string) (string, error) {
+	kmsPath := c.flagConfig
+	if c.flagPasswordOutputKms != "" {
+		kmsPath = c.flagPasswordOutputKms
+	}
+	w, err := wrapper.GetWrapperFromPath(kmsPath, "config")
+	if err != nil {
+		return "", fmt.Errorf("Error getting KMS to encrypt the password: %w", err)
+	}
+	if w == nil {
+		return "", fmt.Errorf(`No "kms" block with "config" purpose found in %s to encrypt the password`, kmsPath)
+	}
+	if err := w.Init(c.Context); err != nil {
+		return "", fmt.Errorf("Error initializing KMS: %w", err)
+	}
+	defer func() {
+		if err := w.Finalize(c.Context); err != nil {
+			c.UI.Warn(fmt.Errorf("Error encountered when finalizing KMS: %w", err).Error())
+		}
+	}()
+
+	sealed, err := configutil.EncryptDecrypt(fmt.Sprintf(`password = "{{encrypt(%s)}}"`, password), false, false, w)
+	if err != nil {
+		return "", fmt.Errorf("Error encrypting password: %w", err)
+	}
+	tmp := c.flagPasswordOutput + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(sealed+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("Error writing password file: %w", err)
+	}
+	return tmp, nil
+}
+
+func (c *BootstrapCommand) ParseFlagsAndConfig(args []string) int {
+	var err error
+
+	f := c.Flags()
+
+	if err = f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if len(c.flagConfig) == 0 {
+		c.UI.Error("Must specify a config file using -config")
+		return 1
+	}
+
+	wrapperPath := c.flagConfig
+	if c.flagConfigKms != "" {
+		wrapperPath = c.flagConfigKms
+	}
+	wrapper, err := wrapper.GetWrapperFromPath(wrapperPath, "config")
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if wrapper != nil {
+		c.configWrapper = wrapper
+		if err := wrapper.Init(c.Context); err != nil {
+			c.UI.Error(fmt.Errorf("Could not initialize kms: %w", err).Error())
+			return 1
+		}
+	}
+
+	c.Config, err = config.LoadFile(c.flagConfig, wrapper)
+	if err != nil {
+		c.UI.Error("Error parsing config: " + err.Error())
+		return 1
+	}
+
+	if c.Config.Controller == nil {
+		c.UI.Error(`"controller" config block not found`)
+		return 1
+	}
+
+	return 0
+}
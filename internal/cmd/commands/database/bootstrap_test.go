@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/sdk/wrapper"
+	"github.com/hashicorp/shared-secure-libs/configutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigKms = `
+kms "aead" {
+  purpose = "config"
+  aead_type = "aes-gcm"
+  key = "c964AJj8VW8w4hKz/Jd8MvuLt0kkcjVuFqMiMvTvvN8="
+}
+`
+
+func TestBootstrapCommand_SealedPassword(t *testing.T) {
+	dir := t.TempDir()
+	kmsPath := filepath.Join(dir, "kms.hcl")
+	require.NoError(t, ioutil.WriteFile(kmsPath, []byte(testConfigKms), 0600))
+	outputPath := filepath.Join(dir, "admin-password.hcl")
+
+	t.Run("round trip", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		c := &BootstrapCommand{
+			Command:               base.NewCommand(cli.NewMockUi()),
+			flagConfig:            filepath.Join(dir, "missing.hcl"),
+			flagPasswordOutput:    outputPath,
+			flagPasswordOutputKms: kmsPath,
+		}
+		tmp, err := c.writeSealedPassword("passw0rd-1234")
+		require.NoError(err)
+		assert.Equal(outputPath+".tmp", tmp)
+		fi, err := os.Stat(tmp)
+		require.NoError(err)
+		assert.Equal(os.FileMode(0600), fi.Mode().Perm())
+
+		sealed, err := ioutil.ReadFile(tmp)
+		require.NoError(err)
+		assert.NotContains(string(sealed), "passw0rd-1234")
+
+		// The file decrypts as "boundary config decrypt" would
+		w, err := wrapper.GetWrapperFromPath(kmsPath, "config")
+		require.NoError(err)
+		require.NoError(w.Init(context.Background()))
+		defer w.Finalize(context.Background())
+		opened, err := configutil.EncryptDecrypt(string(sealed), true, true, w)
+		require.NoError(err)
+		assert.Equal(`password = "passw0rd-1234"`, strings.TrimSpace(opened))
+	})
+
+	t.Run("no config kms", func(t *testing.T) {
+		configPath := filepath.Join(dir, "controller.hcl")
+		require.NoError(t, ioutil.WriteFile(configPath, []byte(`controller {}`), 0600))
+		c := &BootstrapCommand{
+			Command:            base.NewCommand(cli.NewMockUi()),
+			flagConfig:         configPath,
+			flagPasswordOutput: filepath.Join(dir, "other-password.hcl"),
+		}
+		_, err := c.writeSealedPassword("passw0rd-1234")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `No "kms" block with "config" purpose found`)
+		_, err = os.Stat(c.flagPasswordOutput + ".tmp")
+		assert.True(t, os.IsNotExist(err))
+	})
+}
@@ -27,6 +27,23 @@ func (c *Command) Help() string {
 		"",
 		`      $ boundary database init`,
 		"",
+		"    Idempotently initialize the database and initial resources for automated installs:",
+		"",
+		`      $ boundary database bootstrap -password=env://BOUNDARY_ADMIN_PASSWORD`,
+		"",
+		"    Export a signed compliance archive for a scope:",
+		"",
+		`      $ boundary database export-compliance -scope-id o_1234567890 -start-time 2020-10-01T00:00:00Z -output audit.zip`,
+		"",
+		"    Export all resources, e.g. to migrate to another database provider, and import them into a fresh database:",
+		"",
+		`      $ boundary database export -migration-kms migration.hcl -output boundary.zip`,
+		`      $ boundary database import -migration-kms migration.hcl -input boundary.zip`,
+		"",
+		"    Repair the recorded checksums of changed migrations:",
+		"",
+		`      $ boundary database repair`,
+		"",
 		"  Please see the database subcommand help for detailed usage information.",
 	})
 }
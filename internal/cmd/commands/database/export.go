@@ -0,0 +1,279 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/backup"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/sdk/wrapper"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*ExportCommand)(nil)
+var _ cli.CommandAutocomplete = (*ExportCommand)(nil)
+
+type ExportCommand struct {
+	*base.Command
+	srv *base.Server
+
+	Config *config.Config
+
+	configWrapper    wrapping.Wrapper
+	migrationWrapper wrapping.Wrapper
+
+	flagConfig       string
+	flagConfigKms    string
+	flagMigrationKms string
+	flagLogLevel     string
+	flagLogFormat    string
+	flagOutput       string
+}
+
+func (c *ExportCommand) Synopsis() string {
+	return "Export all resources of Boundary's database into an archive"
+}
+
+func (c *ExportCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary database export [options]",
+		"",
+		"  Export all resources of the database into an archive which can be imported into a fresh database, e.g. of another database provider, with \"boundary database import\":",
+		"",
+		`    $ boundary database export -config=/etc/boundary/controller.hcl -migration-kms=/etc/boundary/migration.hcl -output=boundary.zip`,
+		"",
+		"  The root keys are rewrapped from the root KMS to the KMS marked for \"migration\" purpose in the file given by -migration-kms, which also signs the archive. The importing installation needs the same migration KMS but not the root KMS of the exporting one. All other encrypted values remain encrypted by keys derived from the root keys.",
+		"",
+		"  For a full list of examples, please see the documentation.",
+	}) + c.Flags().Help()
+}
+
+func (c *ExportCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:   "config",
+		Target: &c.flagConfig,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: "Path to the configuration file.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "config-kms",
+		Target: &c.flagConfigKms,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: `Path to a configuration file containing a "kms" block marked for "config" purpose, to perform decryption of the main configuration file. If not set, will look for such a block in the main configuration file, which has some drawbacks; see the help output for "boundary config encrypt -h" for details.`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "log-level",
+		Target:     &c.flagLogLevel,
+		EnvVar:     "BOUNDARY_LOG_LEVEL",
+		Completion: complete.PredictSet("trace", "debug", "info", "warn", "err"),
+		Usage: "Log verbosity level. Supported values (in order of more detail to less) are " +
+			"\"trace\", \"debug\", \"info\", \"warn\", and \"err\".",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "log-format",
+		Target:     &c.flagLogFormat,
+		Completion: complete.PredictSet("standard", "json"),
+		Usage:      `Log format. Supported values are "standard" and "json".`,
+	})
+
+	f = set.NewFlagSet("Export Options")
+
+	f.StringVar(&base.StringVar{
+		Name:   "migration-kms",
+		Target: &c.flagMigrationKms,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: `Path to a configuration file containing a "kms" block marked for "migration" purpose, to which the root keys are rewrapped.`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "output",
+		Target:     &c.flagOutput,
+		Completion: complete.PredictFiles("*.zip"),
+		Usage:      "Path of the archive to create. The file must not already exist.",
+	})
+
+	return set
+}
+
+func (c *ExportCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ExportCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ExportCommand) Run(args []string) (retCode int) {
+	if result := c.ParseFlagsAndConfig(args); result > 0 {
+		return result
+	}
+
+	if c.configWrapper != nil {
+		defer func() {
+			if err := c.configWrapper.Finalize(c.Context); err != nil {
+				c.UI.Warn(fmt.Errorf("Error finalizing config kms: %w", err).Error())
+			}
+		}()
+	}
+	defer func() {
+		if err := c.migrationWrapper.Finalize(c.Context); err != nil {
+			c.UI.Warn(fmt.Errorf("Error finalizing migration kms: %w", err).Error())
+		}
+	}()
+
+	c.srv = base.NewServer(&base.Command{UI: c.UI})
+
+	if err := c.srv.SetupLogging(c.flagLogLevel, c.flagLogFormat, c.Config.LogLevel, c.Config.LogFormat); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := c.srv.SetupFips(c.Config); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if err := c.srv.SetupKMSes(c.UI, c.Config); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.srv.RootKms == nil {
+		c.UI.Error("Root KMS not found after parsing KMS blocks")
+		return 1
+	}
+
+	if c.Config.Controller.Database == nil {
+		c.UI.Error(`"controller.database" config block not found`)
+		return 1
+	}
+
+	urlToParse := c.Config.Controller.Database.Url
+	if urlToParse == "" {
+		c.UI.Error(`"url" not specified in "database" config block"`)
+		return 1
+	}
+
+	dbaseUrl, err := config.ParseAddress(urlToParse)
+	if err != nil && err != config.ErrNotAUrl {
+		c.UI.Error(fmt.Errorf("Error parsing database url: %w", err).Error())
+		return 1
+	}
+
+	c.srv.DatabaseUrl = strings.TrimSpace(dbaseUrl)
+	if err := c.srv.ConnectToDatabase("postgres"); err != nil {
+		c.UI.Error(fmt.Errorf("Error connecting to database: %w", err).Error())
+		return 1
+	}
+	defer c.srv.Database.Close()
+
+	exporter, err := backup.NewExporter(db.New(c.srv.Database), c.srv.RootKms, c.migrationWrapper)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error creating exporter: %w", err).Error())
+		return 1
+	}
+
+	out, err := os.OpenFile(c.flagOutput, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error creating output file: %w", err).Error())
+		return 1
+	}
+	manifest, err := exporter.Export(c.Context, out)
+	if closeErr := out.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("error closing output file: %w", closeErr)
+	}
+	if err != nil {
+		// Don't leave a partial archive behind
+		_ = os.Remove(c.flagOutput)
+		c.UI.Error(fmt.Errorf("Error exporting database: %w", err).Error())
+		return 1
+	}
+
+	switch base.Format(c.UI) {
+	case "table":
+		c.UI.Output(generateBackupManifestTableOutput("Database export information:", c.flagOutput, manifest))
+	case "json":
+		b, err := base.JsonFormatter{}.Format(manifest)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+	}
+
+	return 0
+}
+
+func (c *ExportCommand) ParseFlagsAndConfig(args []string) int {
+	var err error
+
+	f := c.Flags()
+
+	if err = f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	// Validation
+	switch {
+	case len(c.flagConfig) == 0:
+		c.UI.Error("Must specify a config file using -config")
+		return 1
+	case c.flagMigrationKms == "":
+		c.UI.Error("Must specify a migration KMS file using -migration-kms")
+		return 1
+	case c.flagOutput == "":
+		c.UI.Error("Must specify an output file using -output")
+		return 1
+	}
+
+	if c.migrationWrapper, err = loadMigrationWrapper(c.Context, c.flagMigrationKms); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	wrapperPath := c.flagConfig
+	if c.flagConfigKms != "" {
+		wrapperPath = c.flagConfigKms
+	}
+	wrapper, err := wrapper.GetWrapperFromPath(wrapperPath, "config")
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if wrapper != nil {
+		c.configWrapper = wrapper
+		if err := wrapper.Init(c.Context); err != nil {
+			c.UI.Error(fmt.Errorf("Could not initialize kms: %w", err).Error())
+			return 1
+		}
+	}
+
+	c.Config, err = config.LoadFile(c.flagConfig, wrapper)
+	if err != nil {
+		c.UI.Error("Error parsing config: " + err.Error())
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,300 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/compliance"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/sdk/wrapper"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*ExportComplianceCommand)(nil)
+var _ cli.CommandAutocomplete = (*ExportComplianceCommand)(nil)
+
+type ExportComplianceCommand struct {
+	*base.Command
+	srv *base.Server
+
+	Config *config.Config
+
+	configWrapper wrapping.Wrapper
+
+	flagConfig    string
+	flagConfigKms string
+	flagLogLevel  string
+	flagLogFormat string
+	flagScopeId   string
+	flagStartTime string
+	flagEndTime   string
+	flagOutput    string
+}
+
+func (c *ExportComplianceCommand) Synopsis() string {
+	return "Export a signed compliance archive of a scope's data"
+}
+
+func (c *ExportComplianceCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary database export-compliance [options]",
+		"",
+		"  Export the users, grants, session metadata and audit events of a scope and all of its child scopes into a signed zip archive:",
+		"",
+		`    $ boundary database export-compliance -config=/etc/boundary/controller.hcl -scope-id=o_1234567890 -start-time=2020-10-01T00:00:00Z -end-time=2020-11-01T00:00:00Z -output=audit.zip`,
+		"",
+		"  Sessions and audit events are limited to those created within the given time range. The archive contains a manifest with the SHA-256 digest of each file, signed using the root KMS.",
+		"",
+		"  For a full list of examples, please see the documentation.",
+	}) + c.Flags().Help()
+}
+
+func (c *ExportComplianceCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:   "config",
+		Target: &c.flagConfig,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: "Path to the configuration file.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "config-kms",
+		Target: &c.flagConfigKms,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: `Path to a configuration file containing a "kms" block marked for "config" purpose, to perform decryption of the main configuration file. If not set, will look for such a block in the main configuration file, which has some drawbacks; see the help output for "boundary config encrypt -h" for details.`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "log-level",
+		Target:     &c.flagLogLevel,
+		EnvVar:     "BOUNDARY_LOG_LEVEL",
+		Completion: complete.PredictSet("trace", "debug", "info", "warn", "err"),
+		Usage: "Log verbosity level. Supported values (in order of more detail to less) are " +
+			"\"trace\", \"debug\", \"info\", \"warn\", and \"err\".",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "log-format",
+		Target:     &c.flagLogFormat,
+		Completion: complete.PredictSet("standard", "json"),
+		Usage:      `Log format. Supported values are "standard" and "json".`,
+	})
+
+	f = set.NewFlagSet("Export Options")
+
+	f.StringVar(&base.StringVar{
+		Name:   "scope-id",
+		Target: &c.flagScopeId,
+		Usage:  "The ID of the scope to export. Data from all child scopes is included.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "start-time",
+		Target: &c.flagStartTime,
+		Usage:  "The RFC3339 formatted start of the time range (inclusive) of exported sessions and audit events.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "end-time",
+		Target: &c.flagEndTime,
+		Usage:  "The RFC3339 formatted end of the time range (exclusive) of exported sessions and audit events. Defaults to the current time.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "output",
+		Target:     &c.flagOutput,
+		Completion: complete.PredictFiles("*.zip"),
+		Usage:      "Path of the archive to create. The file must not already exist.",
+	})
+
+	return set
+}
+
+func (c *ExportComplianceCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ExportComplianceCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ExportComplianceCommand) Run(args []string) (retCode int) {
+	if result := c.ParseFlagsAndConfig(args); result > 0 {
+		return result
+	}
+
+	if c.configWrapper != nil {
+		defer func() {
+			if err := c.configWrapper.Finalize(c.Context); err != nil {
+				c.UI.Warn(fmt.Errorf("Error finalizing config kms: %w", err).Error())
+			}
+		}()
+	}
+
+	startTime, err := time.Parse(time.RFC3339, c.flagStartTime)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error parsing start time: %w", err).Error())
+		return 1
+	}
+	endTime := time.Now()
+	if c.flagEndTime != "" {
+		endTime, err = time.Parse(time.RFC3339, c.flagEndTime)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error parsing end time: %w", err).Error())
+			return 1
+		}
+	}
+	if !startTime.Before(endTime) {
+		c.UI.Error("Start time must be before end time")
+		return 1
+	}
+
+	c.srv = base.NewServer(&base.Command{UI: c.UI})
+
+	if err := c.srv.SetupLogging(c.flagLogLevel, c.flagLogFormat, c.Config.LogLevel, c.Config.LogFormat); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := c.srv.SetupFips(c.Config); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if err := c.srv.SetupKMSes(c.UI, c.Config); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.srv.RootKms == nil {
+		c.UI.Error("Root KMS not found after parsing KMS blocks")
+		return 1
+	}
+
+	if c.Config.Controller.Database == nil {
+		c.UI.Error(`"controller.database" config block not found`)
+		return 1
+	}
+
+	urlToParse := c.Config.Controller.Database.Url
+	if urlToParse == "" {
+		c.UI.Error(`"url" not specified in "database" config block"`)
+		return 1
+	}
+
+	dbaseUrl, err := config.ParseAddress(urlToParse)
+	if err != nil && err != config.ErrNotAUrl {
+		c.UI.Error(fmt.Errorf("Error parsing database url: %w", err).Error())
+		return 1
+	}
+
+	c.srv.DatabaseUrl = strings.TrimSpace(dbaseUrl)
+	if err := c.srv.ConnectToDatabase("postgres"); err != nil {
+		c.UI.Error(fmt.Errorf("Error connecting to database: %w", err).Error())
+		return 1
+	}
+	defer c.srv.Database.Close()
+
+	exporter, err := compliance.NewExporter(db.New(c.srv.Database), c.srv.RootKms)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error creating exporter: %w", err).Error())
+		return 1
+	}
+
+	out, err := os.OpenFile(c.flagOutput, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error creating output file: %w", err).Error())
+		return 1
+	}
+	manifest, err := exporter.Export(c.Context, out, c.flagScopeId, startTime, endTime)
+	if closeErr := out.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("error closing output file: %w", closeErr)
+	}
+	if err != nil {
+		// Don't leave a partial archive behind
+		_ = os.Remove(c.flagOutput)
+		c.UI.Error(fmt.Errorf("Error exporting compliance archive: %w", err).Error())
+		return 1
+	}
+
+	switch base.Format(c.UI) {
+	case "table":
+		c.UI.Output(generateComplianceManifestTableOutput(c.flagOutput, manifest))
+	case "json":
+		b, err := base.JsonFormatter{}.Format(manifest)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+	}
+
+	return 0
+}
+
+func (c *ExportComplianceCommand) ParseFlagsAndConfig(args []string) int {
+	var err error
+
+	f := c.Flags()
+
+	if err = f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	// Validation
+	switch {
+	case len(c.flagConfig) == 0:
+		c.UI.Error("Must specify a config file using -config")
+		return 1
+	case c.flagScopeId == "":
+		c.UI.Error("Must specify a scope using -scope-id")
+		return 1
+	case c.flagStartTime == "":
+		c.UI.Error("Must specify a start time using -start-time")
+		return 1
+	case c.flagOutput == "":
+		c.UI.Error("Must specify an output file using -output")
+		return 1
+	}
+
+	wrapperPath := c.flagConfig
+	if c.flagConfigKms != "" {
+		wrapperPath = c.flagConfigKms
+	}
+	wrapper, err := wrapper.GetWrapperFromPath(wrapperPath, "config")
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if wrapper != nil {
+		c.configWrapper = wrapper
+		if err := wrapper.Init(c.Context); err != nil {
+			c.UI.Error(fmt.Errorf("Could not initialize kms: %w", err).Error())
+			return 1
+		}
+	}
+
+	c.Config, err = config.LoadFile(c.flagConfig, wrapper)
+	if err != nil {
+		c.UI.Error("Error parsing config: " + err.Error())
+		return 1
+	}
+
+	return 0
+}
@@ -1,9 +1,16 @@
 package database
 
 import (
+	"context"
 	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/hashicorp/boundary/internal/backup"
 	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/compliance"
+	"github.com/hashicorp/boundary/sdk/wrapper"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
 )
 
 type RoleInfo struct {
@@ -34,13 +41,16 @@ func generateInitialRoleTableOutput(in *RoleInfo) string {
 }
 
 type AuthInfo struct {
-	AuthMethodId   string `json:"auth_method_id"`
-	AuthMethodName string `json:"auth_method_name"`
-	LoginName      string `json:"login_name"`
-	Password       string `json:"password"`
-	ScopeId        string `json:"scope_id"`
-	UserId         string `json:"user_id"`
-	UserName       string `json:"user_name"`
+	AuthMethodId       string   `json:"auth_method_id"`
+	AuthMethodName     string   `json:"auth_method_name"`
+	LoginName          string   `json:"login_name"`
+	Password           string   `json:"password"`
+	ScopeId            string   `json:"scope_id"`
+	UserId             string   `json:"user_id"`
+	UserName           string   `json:"user_name"`
+	RecoveryCodes      []string `json:"recovery_codes,omitempty"`
+	RecoveryTotpSecret string   `json:"recovery_totp_secret,omitempty"`
+	RecoveryTotpUrl    string   `json:"recovery_totp_url,omitempty"`
 }
 
 func generateInitialAuthTableOutput(in *AuthInfo) string {
@@ -66,6 +76,22 @@ func generateInitialAuthTableOutput(in *AuthInfo) string {
 		"Initial auth information:",
 		base.WrapMap(2, maxLength+2, nonAttributeMap),
 	}
+	if len(in.RecoveryCodes) > 0 {
+		ret = append(ret,
+			"",
+			"  Recovery codes (each can be used once in place of the password, with a",
+			"  current code of the recovery TOTP secret):",
+		)
+		for _, code := range in.RecoveryCodes {
+			ret = append(ret, "    "+code)
+		}
+		ret = append(ret,
+			"",
+			"  Recovery TOTP secret (add it to an authenticator app):",
+			"    "+in.RecoveryTotpSecret,
+			"    "+in.RecoveryTotpUrl,
+		)
+	}
 
 	return base.WrapForHelpText(ret)
 }
@@ -174,3 +200,93 @@ func generateInitialTargetTableOutput(in *TargetInfo) string {
 
 	return base.WrapForHelpText(ret)
 }
+
+func generateComplianceManifestTableOutput(path string, in *compliance.Manifest) string {
+	nonAttributeMap := map[string]interface{}{
+		"Archive":    path,
+		"Scope ID":   in.ScopeId,
+		"Start Time": in.StartTime.Format(time.RFC3339),
+		"End Time":   in.EndTime.Format(time.RFC3339),
+		"Key ID":     in.KeyId,
+	}
+
+	maxLength := 0
+	for k := range nonAttributeMap {
+		if len(k) > maxLength {
+			maxLength = len(k)
+		}
+	}
+
+	countsMap := make(map[string]interface{}, len(in.Counts))
+	countsLength := 0
+	for k, v := range in.Counts {
+		countsMap[k] = v
+		if len(k) > countsLength {
+			countsLength = len(k)
+		}
+	}
+
+	ret := []string{
+		"",
+		"Compliance export information:",
+		base.WrapMap(2, maxLength+2, nonAttributeMap),
+		"",
+		"  Exported records:",
+		base.WrapMap(4, countsLength+2, countsMap),
+	}
+
+	return base.WrapForHelpText(ret)
+}
+
+func generateBackupManifestTableOutput(header, path string, in *backup.Manifest) string {
+	nonAttributeMap := map[string]interface{}{
+		"Archive":         path,
+		"Archive Version": in.Version,
+		"Schema Version":  strconv.FormatInt(in.SchemaVersion, 10),
+		"Created":         in.CreateTime.Format(time.RFC3339),
+		"Key ID":          in.KeyId,
+	}
+
+	maxLength := 0
+	for k := range nonAttributeMap {
+		if len(k) > maxLength {
+			maxLength = len(k)
+		}
+	}
+
+	countsMap := make(map[string]interface{}, len(in.Counts))
+	countsLength := 0
+	for k, v := range in.Counts {
+		countsMap[k] = v
+		if len(k) > countsLength {
+			countsLength = len(k)
+		}
+	}
+
+	ret := []string{
+		"",
+		header,
+		base.WrapMap(2, maxLength+2, nonAttributeMap),
+		"",
+		"  Rows by table:",
+		base.WrapMap(4, countsLength+2, countsMap),
+	}
+
+	return base.WrapForHelpText(ret)
+}
+
+// loadMigrationWrapper returns the initialized wrapper of the kms block marked
+// for "migration" purpose in the file at path.
+func loadMigrationWrapper(ctx context.Context, path string) (wrapping.Wrapper, error) {
+	w, err := wrapper.GetWrapperFromPath(path, "migration")
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		return nil, fmt.Errorf(`No "kms" block marked for "migration" purpose found in %s`, path)
+	}
+	if err := w.Init(ctx); err != nil {
+		return nil, fmt.Errorf("Could not initialize migration kms: %w", err)
+	}
+	return w, nil
+}
@@ -0,0 +1,284 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/backup"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/sdk/wrapper"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*ImportCommand)(nil)
+var _ cli.CommandAutocomplete = (*ImportCommand)(nil)
+
+type ImportCommand struct {
+	*base.Command
+	srv *base.Server
+
+	Config *config.Config
+
+	configWrapper    wrapping.Wrapper
+	migrationWrapper wrapping.Wrapper
+
+	flagConfig       string
+	flagConfigKms    string
+	flagMigrationKms string
+	flagLogLevel     string
+	flagLogFormat    string
+	flagInput        string
+}
+
+func (c *ImportCommand) Synopsis() string {
+	return "Import an archive of all resources into a fresh Boundary database"
+}
+
+func (c *ImportCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary database import [options]",
+		"",
+		"  Import an archive written by \"boundary database export\" into a fresh database, i.e. one which hasn't been initialized with \"boundary database init\":",
+		"",
+		`    $ boundary database import -config=/etc/boundary/controller.hcl -migration-kms=/etc/boundary/migration.hcl -input=boundary.zip`,
+		"",
+		"  The database is migrated to the schema version of this binary first, which must be the schema version of the archive. The archive must be signed by the KMS marked for \"migration\" purpose in the file given by -migration-kms, from which the root keys are rewrapped to the root KMS. All rows are imported in a single transaction with triggers disabled, which requires the database user to be a superuser.",
+		"",
+		"  For a full list of examples, please see the documentation.",
+	}) + c.Flags().Help()
+}
+
+func (c *ImportCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:   "config",
+		Target: &c.flagConfig,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: "Path to the configuration file.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "config-kms",
+		Target: &c.flagConfigKms,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: `Path to a configuration file containing a "kms" block marked for "config" purpose, to perform decryption of the main configuration file. If not set, will look for such a block in the main configuration file, which has some drawbacks; see the help output for "boundary config encrypt -h" for details.`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "log-level",
+		Target:     &c.flagLogLevel,
+		EnvVar:     "BOUNDARY_LOG_LEVEL",
+		Completion: complete.PredictSet("trace", "debug", "info", "warn", "err"),
+		Usage: "Log verbosity level. Supported values (in order of more detail to less) are " +
+			"\"trace\", \"debug\", \"info\", \"warn\", and \"err\".",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "log-format",
+		Target:     &c.flagLogFormat,
+		Completion: complete.PredictSet("standard", "json"),
+		Usage:      `Log format. Supported values are "standard" and "json".`,
+	})
+
+	f = set.NewFlagSet("Import Options")
+
+	f.StringVar(&base.StringVar{
+		Name:   "migration-kms",
+		Target: &c.flagMigrationKms,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: `Path to a configuration file containing a "kms" block marked for "migration" purpose, from which the root keys are rewrapped.`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "input",
+		Target:     &c.flagInput,
+		Completion: complete.PredictFiles("*.zip"),
+		Usage:      "Path of the archive to import.",
+	})
+
+	return set
+}
+
+func (c *ImportCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ImportCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ImportCommand) Run(args []string) (retCode int) {
+	if result := c.ParseFlagsAndConfig(args); result > 0 {
+		return result
+	}
+
+	if c.configWrapper != nil {
+		defer func() {
+			if err := c.configWrapper.Finalize(c.Context); err != nil {
+				c.UI.Warn(fmt.Errorf("Error finalizing config kms: %w", err).Error())
+			}
+		}()
+	}
+	defer func() {
+		if err := c.migrationWrapper.Finalize(c.Context); err != nil {
+			c.UI.Warn(fmt.Errorf("Error finalizing migration kms: %w", err).Error())
+		}
+	}()
+
+	c.srv = base.NewServer(&base.Command{UI: c.UI})
+
+	if err := c.srv.SetupLogging(c.flagLogLevel, c.flagLogFormat, c.Config.LogLevel, c.Config.LogFormat); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := c.srv.SetupFips(c.Config); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if err := c.srv.SetupKMSes(c.UI, c.Config); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.srv.RootKms == nil {
+		c.UI.Error("Root KMS not found after parsing KMS blocks")
+		return 1
+	}
+
+	if c.Config.Controller.Database == nil {
+		c.UI.Error(`"controller.database" config block not found`)
+		return 1
+	}
+
+	urlToParse := c.Config.Controller.Database.Url
+	if urlToParse == "" {
+		c.UI.Error(`"url" not specified in "database" config block"`)
+		return 1
+	}
+
+	dbaseUrl, err := config.ParseAddress(urlToParse)
+	if err != nil && err != config.ErrNotAUrl {
+		c.UI.Error(fmt.Errorf("Error parsing database url: %w", err).Error())
+		return 1
+	}
+
+	c.srv.DatabaseUrl = strings.TrimSpace(dbaseUrl)
+	if _, err := db.InitStore("postgres", nil, c.srv.DatabaseUrl); err != nil {
+		c.UI.Error(fmt.Errorf("Error running database migrations: %w", err).Error())
+		return 1
+	}
+	if err := c.srv.ConnectToDatabase("postgres"); err != nil {
+		c.UI.Error(fmt.Errorf("Error connecting to database: %w", err).Error())
+		return 1
+	}
+	defer c.srv.Database.Close()
+
+	importer, err := backup.NewImporter(db.New(c.srv.Database), c.srv.RootKms, c.migrationWrapper)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error creating importer: %w", err).Error())
+		return 1
+	}
+
+	in, err := os.Open(c.flagInput)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error opening input file: %w", err).Error())
+		return 1
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error reading input file: %w", err).Error())
+		return 1
+	}
+	manifest, err := importer.Import(c.Context, in, info.Size())
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error importing database: %w", err).Error())
+		return 1
+	}
+
+	switch base.Format(c.UI) {
+	case "table":
+		c.UI.Output(generateBackupManifestTableOutput("Database import information:", c.flagInput, manifest))
+	case "json":
+		b, err := base.JsonFormatter{}.Format(manifest)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+	}
+
+	return 0
+}
+
+func (c *ImportCommand) ParseFlagsAndConfig(args []string) int {
+	var err error
+
+	f := c.Flags()
+
+	if err = f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	// Validation
+	switch {
+	case len(c.flagConfig) == 0:
+		c.UI.Error("Must specify a config file using -config")
+		return 1
+	case c.flagMigrationKms == "":
+		c.UI.Error("Must specify a migration KMS file using -migration-kms")
+		return 1
+	case c.flagInput == "":
+		c.UI.Error("Must specify an input file using -input")
+		return 1
+	}
+
+	if c.migrationWrapper, err = loadMigrationWrapper(c.Context, c.flagMigrationKms); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	wrapperPath := c.flagConfig
+	if c.flagConfigKms != "" {
+		wrapperPath = c.flagConfigKms
+	}
+	wrapper, err := wrapper.GetWrapperFromPath(wrapperPath, "config")
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if wrapper != nil {
+		c.configWrapper = wrapper
+		if err := wrapper.Init(c.Context); err != nil {
+			c.UI.Error(fmt.Errorf("Could not initialize kms: %w", err).Error())
+			return 1
+		}
+	}
+
+	c.Config, err = config.LoadFile(c.flagConfig, wrapper)
+	if err != nil {
+		c.UI.Error("Error parsing config: " + err.Error())
+		return 1
+	}
+
+	return 0
+}
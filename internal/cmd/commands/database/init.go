@@ -41,6 +41,7 @@ type InitCommand struct {
 	flagAllowDevMigrations           bool
 	flagSkipInitialLoginRoleCreation bool
 	flagSkipAuthMethodCreation       bool
+	flagSkipRecoveryCodesCreation    bool
 	flagSkipScopesCreation           bool
 	flagSkipHostResourcesCreation    bool
 	flagSkipTargetCreation           bool
@@ -71,6 +72,10 @@ func (c *InitCommand) Help() string {
 		"",
 		"  If flags are used to skip any of these resources, any resources that would be created afterwards are also skipped.",
 		"",
+		"  Single use recovery codes and a TOTP secret are also generated for the initial admin account. Each code can be used once in place of its password together with a current code of the TOTP secret, e.g. with \"boundary authenticate password -recovery-code -totp-code\".",
+		"",
+		"  For automated installs, see \"boundary database bootstrap\", which can be safely re-run and prints its results as JSON.",
+		"",
 		"  For a full list of examples, please see the documentation.",
 	}) + c.Flags().Help()
 }
@@ -136,6 +141,12 @@ func (c *InitCommand) Flags() *base.FlagSets {
 		Usage:  "If not set, an auth method will not be created as part of initialization. If set, the recovery KMS will be needed to perform any actions.",
 	})
 
+	f.BoolVar(&base.BoolVar{
+		Name:   "skip-recovery-codes-creation",
+		Target: &c.flagSkipRecoveryCodesCreation,
+		Usage:  "If set, single use recovery codes for the initial admin account will not be created as part of initialization.",
+	})
+
 	f.BoolVar(&base.BoolVar{
 		Name:   "skip-scopes-creation",
 		Target: &c.flagSkipScopesCreation,
@@ -205,6 +216,10 @@ func (c *InitCommand) Run(args []string) (retCode int) {
 		return 1
 	}
 
+	if err := c.srv.SetupFips(c.Config); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
 	if err := c.srv.SetupKMSes(c.UI, c.Config); err != nil {
 		c.UI.Error(err.Error())
 		return 1
@@ -370,6 +385,16 @@ func (c *InitCommand) Run(args []string) (retCode int) {
 		UserId:         c.srv.DevUserId,
 		UserName:       user.Name,
 	}
+	if !c.flagSkipRecoveryCodesCreation {
+		codes, err := c.srv.CreateInitialRecoveryCodes(c.Context)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error creating initial recovery codes: %w", err).Error())
+			return 1
+		}
+		authMethodInfo.RecoveryCodes = codes.Codes
+		authMethodInfo.RecoveryTotpSecret = codes.TotpSecret
+		authMethodInfo.RecoveryTotpUrl = codes.TotpUrl
+	}
 	switch base.Format(c.UI) {
 	case "table":
 		c.UI.Output(generateInitialAuthTableOutput(authMethodInfo))
@@ -0,0 +1,209 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/sdk/wrapper"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*RepairCommand)(nil)
+var _ cli.CommandAutocomplete = (*RepairCommand)(nil)
+
+type RepairCommand struct {
+	*base.Command
+
+	Config *config.Config
+
+	configWrapper wrapping.Wrapper
+
+	flagConfig       string
+	flagConfigKms    string
+	flagMigrationUrl string
+}
+
+func (c *RepairCommand) Synopsis() string {
+	return "Repair the recorded checksums of Boundary's database migrations and the fingerprint of its schema"
+}
+
+func (c *RepairCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary database repair [options]",
+		"",
+		"  Update the recorded checksums of the database migrations which differ from the migrations in this binary:",
+		"",
+		"    $ boundary database repair -config=/etc/boundary/controller.hcl",
+		"",
+		"  Migrations refuse to run while an applied migration differs from the migration of the same version in the binary. The fingerprint of the current schema is recorded as well, since migrations and controllers refuse to run while the schema differs from the schema created by its migrations. The schema is not changed, so only repair after checking that the differences are harmless.",
+		"",
+		"  For a full list of examples, please see the documentation.",
+	}) + c.Flags().Help()
+}
+
+func (c *RepairCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:   "config",
+		Target: &c.flagConfig,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: "Path to the configuration file.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "config-kms",
+		Target: &c.flagConfigKms,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: `Path to a configuration file containing a "kms" block marked for "config" purpose, to perform decryption of the main configuration file. If not set, will look for such a block in the main configuration file, which has some drawbacks; see the help output for "boundary config encrypt -h" for details.`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "migration-url",
+		Target: &c.flagMigrationUrl,
+		Usage:  `If set, overrides a migration URL set in config, and specifies the URL used to connect to the database. This can refer to a file on disk (file://) from which a URL will be read; an env var (env://) from which the URL will be read; or a direct database URL.`,
+	})
+
+	return set
+}
+
+func (c *RepairCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *RepairCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *RepairCommand) Run(args []string) int {
+	if result := c.ParseFlagsAndConfig(args); result > 0 {
+		return result
+	}
+
+	if c.configWrapper != nil {
+		defer func() {
+			if err := c.configWrapper.Finalize(c.Context); err != nil {
+				c.UI.Warn(fmt.Errorf("Error finalizing config kms: %w", err).Error())
+			}
+		}()
+	}
+
+	if c.Config.Controller == nil || c.Config.Controller.Database == nil {
+		c.UI.Error(`"controller.database" config block not found`)
+		return 1
+	}
+
+	// Repairing is a migration task so prefer the migration URL
+	urlToParse := c.Config.Controller.Database.MigrationUrl
+	if c.flagMigrationUrl != "" {
+		urlToParse = c.flagMigrationUrl
+	}
+	if urlToParse == "" {
+		urlToParse = c.Config.Controller.Database.Url
+	}
+	if urlToParse == "" {
+		c.UI.Error(`"url" not specified in "database" config block"`)
+		return 1
+	}
+	migrationUrl, err := config.ParseAddress(urlToParse)
+	if err != nil && err != config.ErrNotAUrl {
+		c.UI.Error(fmt.Errorf("Error parsing migration url: %w", err).Error())
+		return 1
+	}
+
+	repaired, err := db.RepairMigrations(c.Context, "postgres", strings.TrimSpace(migrationUrl))
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error repairing migrations: %w", err).Error())
+		return 1
+	}
+	fingerprintRepaired, err := db.RepairSchemaFingerprint(c.Context, "postgres", strings.TrimSpace(migrationUrl))
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error repairing schema fingerprint: %w", err).Error())
+		return 1
+	}
+
+	switch base.Format(c.UI) {
+	case "table":
+		if len(repaired) == 0 {
+			c.UI.Info("No migration checksums needed repair.")
+		} else {
+			versions := make([]string, 0, len(repaired))
+			for _, v := range repaired {
+				versions = append(versions, fmt.Sprintf("%d", v))
+			}
+			c.UI.Info(fmt.Sprintf("Repaired the checksums of migrations %s.", strings.Join(versions, ", ")))
+		}
+		if fingerprintRepaired {
+			c.UI.Info("Recorded the fingerprint of the current schema.")
+		}
+	case "json":
+		if repaired == nil {
+			repaired = []uint{}
+		}
+		b, err := base.JsonFormatter{}.Format(map[string]interface{}{
+			"repaired_versions":           repaired,
+			"schema_fingerprint_repaired": fingerprintRepaired,
+		})
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+	}
+
+	return 0
+}
+
+func (c *RepairCommand) ParseFlagsAndConfig(args []string) int {
+	var err error
+
+	f := c.Flags()
+
+	if err = f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if len(c.flagConfig) == 0 {
+		c.UI.Error("Must specify a config file using -config")
+		return 1
+	}
+
+	wrapperPath := c.flagConfig
+	if c.flagConfigKms != "" {
+		wrapperPath = c.flagConfigKms
+	}
+	wrapper, err := wrapper.GetWrapperFromPath(wrapperPath, "config")
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	if wrapper != nil {
+		c.configWrapper = wrapper
+		if err := wrapper.Init(c.Context); err != nil {
+			c.UI.Error(fmt.Errorf("Could not initialize kms: %w", err).Error())
+			return 1
+		}
+	}
+
+	c.Config, err = config.LoadFile(c.flagConfig, wrapper)
+	if err != nil {
+		c.UI.Error("Error parsing config: " + err.Error())
+		return 1
+	}
+
+	return 0
+}
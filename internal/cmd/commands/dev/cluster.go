@@ -0,0 +1,193 @@
+package dev
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/libs/endpoint"
+	"github.com/hashicorp/boundary/internal/servers/controller"
+	"github.com/hashicorp/boundary/internal/servers/worker"
+)
+
+// devNodePortOffset is how far the ports of each additional controller and
+// worker are from those of the previous one, so the n-th controller listens
+// on 9200+100*(n-1) and 9201+100*(n-1) and the n-th worker on
+// 9202+100*(n-1) by default.
+const devNodePortOffset = 100
+
+// devNode is an additional controller or worker of the dev environment. It
+// has its own server, sharing the logger, KMSes and database of the first
+// one, and its own listeners.
+type devNode struct {
+	name       string
+	server     *base.Server
+	config     *config.Config
+	controller *controller.Controller
+	worker     *worker.Worker
+	stopped    bool
+}
+
+// offsetAddress returns the listener address addr of the first node moved to
+// the n-th node: the port is moved up by devNodePortOffset per node, and
+// Unix domain socket paths get the node number appended.
+func offsetAddress(addr string, n int) (string, error) {
+	if strings.HasPrefix(addr, "/") {
+		return fmt.Sprintf("%s.%d", addr, n), nil
+	}
+	host, port, err := endpoint.SplitHostPort(addr, "")
+	if err != nil {
+		return "", err
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return "", fmt.Errorf("invalid port in address %q", addr)
+	}
+	p += devNodePortOffset * (n - 1)
+	if p > 65535 {
+		return "", fmt.Errorf("port of address %q for node %d is out of range", addr, n)
+	}
+	return net.JoinHostPort(host, strconv.Itoa(p)), nil
+}
+
+// listenerAddress returns the address of the first node's listener with the
+// purpose.
+func (c *Command) listenerAddress(purpose string) string {
+	for _, l := range c.Config.Listeners {
+		if len(l.Purpose) == 1 && l.Purpose[0] == purpose {
+			return l.Address
+		}
+	}
+	return ""
+}
+
+// newDevNode creates the server of the n-th controller or worker with conf,
+// moving its listeners away from those of the first node and binding them.
+func (c *Command) newDevNode(name string, n int, conf *config.Config, purposes []string) (*devNode, error) {
+	b := base.NewServer(&base.Command{
+		Context:    c.Context,
+		UI:         c.UI,
+		ShutdownCh: c.ShutdownCh,
+	})
+	b.Logger = c.Logger.Named(name)
+	b.FipsMode = c.FipsMode
+	b.RootKms = c.RootKms
+	b.WorkerAuthKms = c.WorkerAuthKms
+	b.RecoveryKms = c.RecoveryKms
+	b.DevAuthMethodId = c.DevAuthMethodId
+	b.DevLoginName = c.DevLoginName
+	b.DevPassword = c.DevPassword
+
+	for _, l := range conf.Listeners {
+		addr, err := offsetAddress(c.listenerAddress(l.Purpose[0]), n)
+		if err != nil {
+			return nil, fmt.Errorf("Error setting up %s listener of %s: %w", l.Purpose[0], name, err)
+		}
+		l.Address = addr
+		if strings.HasPrefix(addr, "/") {
+			l.Type = "unix"
+		}
+	}
+	if err := b.SetupListeners(nil, conf.SharedConfig, purposes); err != nil {
+		return nil, fmt.Errorf("Error setting up listeners of %s: %w", name, err)
+	}
+	for _, l := range b.Listeners {
+		key := fmt.Sprintf("[%s] %s addr", name, l.Config.Purpose[0])
+		c.InfoKeys = append(c.InfoKeys, key)
+		c.Info[key] = l.Config.Address
+	}
+	return &devNode{name: name, server: b, config: conf}, nil
+}
+
+// newDevController creates the n-th controller of the dev environment. It is
+// started by start once the database exists.
+func (c *Command) newDevController(n int) (*devNode, error) {
+	conf, err := config.DevController()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating controller dev config: %w", err)
+	}
+	name := fmt.Sprintf("dev-controller-%d", n)
+	conf.Controller.Name = name
+	node, err := c.newDevNode(name, n, conf, []string{"api", "cluster"})
+	if err != nil {
+		return nil, err
+	}
+	if err := node.server.SetupControllerPublicClusterAddress(conf, ""); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// newDevWorker creates the n-th worker of the dev environment, connecting to
+// the controllers at the cluster addresses.
+func (c *Command) newDevWorker(n int, controllers []string) (*devNode, error) {
+	conf, err := config.DevWorker()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating worker dev config: %w", err)
+	}
+	name := fmt.Sprintf("dev-worker-%d", n)
+	conf.Worker.Name = name
+	conf.Worker.Controllers = controllers
+	node, err := c.newDevNode(name, n, conf, []string{"proxy"})
+	if err != nil {
+		return nil, err
+	}
+	if err := node.server.SetupWorkerPublicAddress(conf, ""); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// start connects the node to the database and starts its controller or
+// worker.
+func (n *devNode) start(databaseUrl string) error {
+	switch {
+	case n.config.Controller != nil:
+		n.server.DatabaseUrl = databaseUrl
+		if err := n.server.ConnectToDatabase("postgres"); err != nil {
+			return fmt.Errorf("Error connecting %s to database: %w", n.name, err)
+		}
+		var err error
+		if n.controller, err = controller.New(&controller.Config{RawConfig: n.config, Server: n.server}); err != nil {
+			return fmt.Errorf("Error initializing %s: %w", n.name, err)
+		}
+		if err := n.controller.Start(); err != nil {
+			return fmt.Errorf("Error starting %s: %w", n.name, err)
+		}
+	default:
+		var err error
+		if n.worker, err = worker.New(&worker.Config{RawConfig: n.config, Server: n.server}); err != nil {
+			return fmt.Errorf("Error initializing %s: %w", n.name, err)
+		}
+		if err := n.worker.Start(); err != nil {
+			return fmt.Errorf("Error starting %s: %w", n.name, err)
+		}
+	}
+	return nil
+}
+
+// shutdown stops the controller or worker of the node, if started, and
+// closes its listeners. It does nothing if the node was already shut down.
+func (n *devNode) shutdown() error {
+	if n.stopped {
+		return nil
+	}
+	n.stopped = true
+	var err error
+	switch {
+	case n.controller != nil:
+		err = n.controller.Shutdown(false)
+	case n.worker != nil:
+		err = n.worker.Shutdown(false)
+	}
+	if rerr := n.server.RunShutdownFuncs(); err == nil {
+		err = rerr
+	}
+	if err != nil {
+		return fmt.Errorf("Error shutting down %s: %w", n.name, err)
+	}
+	return nil
+}
@@ -2,7 +2,6 @@ package dev
 
 import (
 	"fmt"
-	"net"
 	"runtime"
 	"strings"
 
@@ -12,6 +11,7 @@ import (
 	"github.com/hashicorp/boundary/internal/docker"
 	"github.com/hashicorp/boundary/internal/host/static"
 	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/libs/endpoint"
 	"github.com/hashicorp/boundary/internal/servers/controller"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/servers/worker"
@@ -36,6 +36,11 @@ type Command struct {
 	controller *controller.Controller
 	worker     *worker.Worker
 
+	// extraControllers and extraWorkers are the controllers and workers
+	// after the first ones
+	extraControllers []*devNode
+	extraWorkers     []*devNode
+
 	flagLogLevel                     string
 	flagLogFormat                    string
 	flagCombineLogs                  bool
@@ -55,6 +60,8 @@ type Command struct {
 	flagRecoveryKey                  string
 	flagDatabaseUrl                  string
 	flagDisableDatabaseDestruction   bool
+	flagControllerCount              int
+	flagWorkerCount                  int
 }
 
 func (c *Command) Synopsis() string {
@@ -186,6 +193,22 @@ func (c *Command) Flags() *base.FlagSets {
 		Usage:  "Public address at which the worker is reachable for session proxying.",
 	})
 
+	f.IntVar(&base.IntVar{
+		Name:    "controller-count",
+		Default: 1,
+		Target:  &c.flagControllerCount,
+		EnvVar:  "BOUNDARY_DEV_CONTROLLER_COUNT",
+		Usage:   "Number of controllers to start, sharing the database. Each controller after the first listens on the ports of the first one plus 100 per controller.",
+	})
+
+	f.IntVar(&base.IntVar{
+		Name:    "worker-count",
+		Default: 1,
+		Target:  &c.flagWorkerCount,
+		EnvVar:  "BOUNDARY_DEV_WORKER_COUNT",
+		Usage:   "Number of workers to start, each connecting to all controllers. Each worker after the first listens on the proxy port of the first one plus 100 per worker.",
+	})
+
 	f.BoolVar(&base.BoolVar{
 		Name:   "disable-database-destruction",
 		Target: &c.flagDisableDatabaseDestruction,
@@ -273,13 +296,10 @@ func (c *Command) Run(args []string) int {
 		c.DevPassword = c.flagPassword
 	}
 	c.DevTargetDefaultPort = c.flagTargetDefaultPort
-	host, port, err := net.SplitHostPort(c.flagHostAddress)
+	host, port, err := endpoint.SplitHostPort(c.flagHostAddress, "")
 	if err != nil {
-		if !strings.Contains(err.Error(), "missing port") {
-			c.UI.Error(fmt.Errorf("Invalid host address specified: %w", err).Error())
-			return 1
-		}
-		host = c.flagHostAddress
+		c.UI.Error(fmt.Errorf("Invalid host address specified: %w", err).Error())
+		return 1
 	}
 	if port != "" {
 		c.UI.Error(`Port must not be specified as part of the dev host address`)
@@ -289,6 +309,10 @@ func (c *Command) Run(args []string) int {
 		c.UI.Error(`Specified target session max sessions cannot be negative`)
 		return 1
 	}
+	if c.flagControllerCount < 1 || c.flagWorkerCount < 1 {
+		c.UI.Error(`Controller and worker counts must be at least 1`)
+		return 1
+	}
 	c.DevTargetSessionMaxSeconds = c.flagTargetSessionMaxSeconds
 	c.DevTargetSessionConnectionLimit = c.flagTargetSessionConnectionLimit
 	c.DevHostAddress = host
@@ -340,6 +364,10 @@ func (c *Command) Run(args []string) int {
 	if c.flagRecoveryKey != "" {
 		c.Config.DevRecoveryKey = c.flagRecoveryKey
 	}
+	if err := c.SetupFips(c.Config); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
 	if err := c.SetupKMSes(c.UI, c.Config); err != nil {
 		c.UI.Error(err.Error())
 		return 1
@@ -391,6 +419,33 @@ func (c *Command) Run(args []string) int {
 		}
 	}()
 
+	// Additional controllers and workers get their own listeners; all
+	// workers connect to all controllers
+	defer func() {
+		for _, n := range append(c.extraWorkers, c.extraControllers...) {
+			if err := n.shutdown(); err != nil {
+				c.UI.Error(err.Error())
+			}
+		}
+	}()
+	for n := 2; n <= c.flagControllerCount; n++ {
+		node, err := c.newDevController(n)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		c.extraControllers = append(c.extraControllers, node)
+		c.Config.Worker.Controllers = append(c.Config.Worker.Controllers, node.config.Controller.PublicClusterAddr)
+	}
+	for n := 2; n <= c.flagWorkerCount; n++ {
+		node, err := c.newDevWorker(n, append([]string(nil), c.Config.Worker.Controllers...))
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		c.extraWorkers = append(c.extraWorkers, node)
+	}
+
 	switch c.flagDatabaseUrl {
 	case "":
 		var opts []base.Option
@@ -446,6 +501,15 @@ func (c *Command) Run(args []string) int {
 			c.UI.Error(retErr.Error())
 			return 1
 		}
+		for _, n := range c.extraControllers {
+			if err := n.start(c.DatabaseUrl); err != nil {
+				c.UI.Error(err.Error())
+				if err := c.controller.Shutdown(false); err != nil {
+					c.UI.Error(fmt.Errorf("Error with controller shutdown: %w", err).Error())
+				}
+				return 1
+			}
+		}
 	}
 	{
 		conf := &worker.Config{
@@ -472,6 +536,18 @@ func (c *Command) Run(args []string) int {
 			}
 			return 1
 		}
+		for _, n := range c.extraWorkers {
+			if err := n.start(c.DatabaseUrl); err != nil {
+				c.UI.Error(err.Error())
+				if err := c.worker.Shutdown(false); err != nil {
+					c.UI.Error(fmt.Errorf("Error shutting down worker: %w", err).Error())
+				}
+				if err := c.controller.Shutdown(false); err != nil {
+					c.UI.Error(fmt.Errorf("Error with controller shutdown: %w", err).Error())
+				}
+				return 1
+			}
+		}
 	}
 
 	// Wait for shutdown
@@ -482,9 +558,19 @@ func (c *Command) Run(args []string) int {
 		case <-c.ShutdownCh:
 			c.UI.Output("==> Boundary dev environment shutdown triggered")
 
+			for _, n := range c.extraWorkers {
+				if err := n.shutdown(); err != nil {
+					c.UI.Error(err.Error())
+				}
+			}
 			if err := c.worker.Shutdown(false); err != nil {
 				c.UI.Error(fmt.Errorf("Error shutting down worker: %w", err).Error())
 			}
+			for _, n := range c.extraControllers {
+				if err := n.shutdown(); err != nil {
+					c.UI.Error(err.Error())
+				}
+			}
 
 			if err := c.controller.Shutdown(false); err != nil {
 				c.UI.Error(fmt.Errorf("Error shutting down controller: %w", err).Error())
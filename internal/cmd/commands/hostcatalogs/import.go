@@ -0,0 +1,200 @@
+package hostcatalogs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/hostcatalogs"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/common"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*ImportCommand)(nil)
+var _ cli.CommandAutocomplete = (*ImportCommand)(nil)
+
+type ImportCommand struct {
+	*base.Command
+
+	flagFile        string
+	flagFormat      string
+	flagHostSetName string
+	flagDryRun      bool
+}
+
+func (c *ImportCommand) Synopsis() string {
+	return "Import hosts from an SSH or Ansible inventory file into a static-type host catalog"
+}
+
+func (c *ImportCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary host-catalogs import [options] [args]",
+		"",
+		"  Create the hosts and host sets of an inventory file in a static-type host catalog. Hosts and host sets are matched to those of the catalog by name: existing hosts are left unchanged and existing host sets get the hosts they are missing. Example:",
+		"",
+		`    $ boundary host-catalogs import -id hcst_1234567890 -format ansible_inventory -file ./inventory.ini`,
+		"",
+		"  The supported formats are known_hosts for an OpenSSH known_hosts file, ssh_config for an OpenSSH client configuration file and ansible_inventory for an Ansible INI inventory, whose groups become host sets. Use -dry-run to show the changes without making them:",
+		"",
+		`    $ boundary host-catalogs import -id hcst_1234567890 -format known_hosts -file ~/.ssh/known_hosts -host-set-name known -dry-run`,
+		"",
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *ImportCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetHTTP | base.FlagSetClient | base.FlagSetOutputFormat)
+	f := set.NewFlagSet("Command Options")
+	common.PopulateCommonFlags(c.Command, f, "static-type host catalog", []string{"id"})
+
+	f.StringVar(&base.StringVar{
+		Name:       "file",
+		Target:     &c.flagFile,
+		Completion: complete.PredictFiles("*"),
+		Usage:      `The inventory file to import, or "-" to read it from standard input.`,
+	})
+	f.StringVar(&base.StringVar{
+		Name:       "format",
+		Target:     &c.flagFormat,
+		Completion: complete.PredictSet(hostcatalogs.ImportFormatKnownHosts, hostcatalogs.ImportFormatSshConfig, hostcatalogs.ImportFormatAnsible),
+		Usage:      "The format of the file: known_hosts, ssh_config or ansible_inventory.",
+	})
+	f.StringVar(&base.StringVar{
+		Name:   "host-set-name",
+		Target: &c.flagHostSetName,
+		Usage:  "If set, all hosts of the file are also added to a host set with this name.",
+	})
+	f.BoolVar(&base.BoolVar{
+		Name:   "dry-run",
+		Target: &c.flagDryRun,
+		Usage:  "If set, the hosts and host sets which would be created are shown without creating them.",
+	})
+
+	return set
+}
+
+func (c *ImportCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *ImportCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ImportCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	switch {
+	case c.FlagId == "":
+		c.UI.Error("ID is required but not passed in via -id")
+		return 1
+	case c.flagFile == "":
+		c.UI.Error("File is required but not passed in via -file")
+		return 1
+	case c.flagFormat == "":
+		c.UI.Error("Format is required but not passed in via -format")
+		return 1
+	}
+
+	var content []byte
+	var err error
+	switch c.flagFile {
+	case "-":
+		content, err = ioutil.ReadAll(os.Stdin)
+	default:
+		content, err = ioutil.ReadFile(c.flagFile)
+	}
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading inventory file: %s", err.Error()))
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating API client: %s", err.Error()))
+		return 2
+	}
+
+	hostcatalogClient := hostcatalogs.NewClient(client)
+	result, err := hostcatalogClient.ImportHosts(c.Context, c.FlagId, c.flagFormat, string(content), c.flagHostSetName, c.flagDryRun)
+	if err != nil {
+		if apiErr := api.AsServerError(err); apiErr != nil {
+			c.UI.Error(fmt.Sprintf("Error from controller when importing hosts: %s", base.PrintApiError(apiErr)))
+			return 1
+		}
+		c.UI.Error(fmt.Sprintf("Error trying to import hosts: %s", err.Error()))
+		return 2
+	}
+
+	switch base.Format(c.UI) {
+	case "table":
+		c.UI.Output(generateImportTableOutput(result))
+	case "json":
+		b, err := base.JsonFormatter{}.Format(result)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+	}
+
+	return 0
+}
+
+func generateImportTableOutput(in *hostcatalogs.ImportHostsResult) string {
+	var ret []string
+	switch {
+	case in.DryRun:
+		ret = append(ret, "", "Planned import (dry run, no changes were made):")
+	default:
+		ret = append(ret, "", "Import results:")
+	}
+
+	ret = append(ret, "", "  Hosts:")
+	if len(in.Hosts) == 0 {
+		ret = append(ret, "    No hosts found in the file")
+	}
+	for _, h := range in.Hosts {
+		action := "create"
+		if h.Exists {
+			action = "exists"
+		}
+		line := fmt.Sprintf("    %-7s %s (%s)", action, h.Name, h.Address)
+		if h.Id != "" {
+			line += fmt.Sprintf(" %s", h.Id)
+		}
+		ret = append(ret, line)
+	}
+
+	if len(in.HostSets) > 0 {
+		ret = append(ret, "", "  Host Sets:")
+	}
+	for _, s := range in.HostSets {
+		action := "create"
+		if s.Exists {
+			action = "update"
+		}
+		line := fmt.Sprintf("    %-7s %s", action, s.Name)
+		if s.Id != "" {
+			line += fmt.Sprintf(" %s", s.Id)
+		}
+		ret = append(ret, line)
+		switch len(s.AddedHosts) {
+		case 0:
+			ret = append(ret, "              no hosts to add")
+		default:
+			ret = append(ret, fmt.Sprintf("              add hosts: %s", strings.Join(s.AddedHosts, ", ")))
+		}
+	}
+
+	return base.WrapForHelpText(ret)
+}
@@ -2,11 +2,13 @@ package server
 
 import (
 	"fmt"
+	"reflect"
 	"runtime"
 	"strings"
 
 	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/cmd/base"
+	configcmd "github.com/hashicorp/boundary/internal/cmd/commands/config"
 	"github.com/hashicorp/boundary/internal/cmd/config"
 	"github.com/hashicorp/boundary/internal/servers/controller"
 	"github.com/hashicorp/boundary/internal/servers/worker"
@@ -14,6 +16,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/shared-secure-libs/configutil"
 	"github.com/hashicorp/vault/sdk/helper/mlock"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
@@ -36,11 +39,16 @@ type Command struct {
 
 	configWrapper wrapping.Wrapper
 
-	flagConfig      string
-	flagConfigKms   string
-	flagLogLevel    string
-	flagLogFormat   string
-	flagCombineLogs bool
+	// workerAuthKmsConf is the worker-auth KMS block the worker's TLS
+	// material was last rotated to, to detect changes on reload
+	workerAuthKmsConf *configutil.KMS
+
+	flagConfig         string
+	flagConfigKms      string
+	flagLogLevel       string
+	flagLogFormat      string
+	flagCombineLogs    bool
+	flagValidateConfig bool
 }
 
 func (c *Command) Synopsis() string {
@@ -102,6 +110,12 @@ func (c *Command) Flags() *base.FlagSets {
 		Usage:      `Log format. Supported values are "standard" and "json".`,
 	})
 
+	f.BoolVar(&base.BoolVar{
+		Name:   "validate-config",
+		Target: &c.flagValidateConfig,
+		Usage:  `If set, validates the configuration file, reports all problems found and exits without starting the server; see "boundary config validate -h" for details.`,
+	})
+
 	return set
 }
 
@@ -116,10 +130,7 @@ func (c *Command) AutocompleteFlags() complete.Flags {
 func (c *Command) Run(args []string) int {
 	c.CombineLogs = c.flagCombineLogs
 
-	if result := c.ParseFlagsAndConfig(args); result > 0 {
-		return result
-	}
-
+	result := c.ParseFlagsAndConfig(args)
 	if c.configWrapper != nil {
 		defer func() {
 			if err := c.configWrapper.Finalize(c.Context); err != nil {
@@ -127,6 +138,9 @@ func (c *Command) Run(args []string) int {
 			}
 		}()
 	}
+	if result > 0 || c.flagValidateConfig {
+		return result
+	}
 
 	if err := c.SetupLogging(c.flagLogLevel, c.flagLogFormat, c.Config.LogLevel, c.Config.LogFormat); err != nil {
 		c.UI.Error(err.Error())
@@ -140,6 +154,10 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
+	if err := c.SetupFips(c.Config); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
 	if err := c.SetupKMSes(c.UI, c.Config); err != nil {
 		c.UI.Error(err.Error())
 		return 1
@@ -339,6 +357,10 @@ func (c *Command) ParseFlagsAndConfig(args []string) int {
 		return 1
 	}
 
+	if c.flagValidateConfig {
+		return configcmd.RunValidate(c.Command, c.flagConfig, wrapper, false)
+	}
+
 	c.Config, err = config.LoadFile(c.flagConfig, wrapper)
 	if err != nil {
 		c.UI.Error("Error parsing config: " + err.Error())
@@ -391,6 +413,8 @@ func (c *Command) StartWorker() error {
 		Server:    c.Server,
 	}
 
+	c.workerAuthKmsConf = base.WorkerAuthKmsConfig(c.Config)
+
 	var err error
 	c.worker, err = worker.New(conf)
 	if err != nil {
@@ -481,6 +505,31 @@ func (c *Command) WaitForInterrupt() int {
 				c.Logger.SetLevel(level)
 			}
 
+			if c.Config.Controller != nil {
+				var listenerAccess []*config.ListenerAccess
+				if newConf.Controller != nil {
+					listenerAccess = newConf.Controller.ListenerAccess
+				}
+				if err := c.controller.ReloadListenerAccess(listenerAccess); err != nil {
+					c.Logger.Error("could not reload listener access", "error", err)
+				}
+				var workerAccess *config.WorkerAccess
+				if newConf.Controller != nil {
+					workerAccess = newConf.Controller.WorkerAccess
+				}
+				if err := c.controller.ReloadWorkerAccess(workerAccess); err != nil {
+					c.Logger.Error("could not reload worker access", "error", err)
+				}
+			}
+
+			// A combined server shares the worker-auth KMS with its
+			// controller, so it is only rotated for a worker alone
+			if c.Config.Worker != nil && c.Config.Controller == nil {
+				if err := c.reloadWorkerAuthKms(newConf); err != nil {
+					c.Logger.Error("could not rotate worker tls", "error", err)
+				}
+			}
+
 		RUNRELOADFUNCS:
 			if err := c.Reload(); err != nil {
 				c.UI.Error(fmt.Errorf("Error(s) were encountered during controller reload: %w", err).Error())
@@ -496,6 +545,24 @@ func (c *Command) WaitForInterrupt() int {
 	return 0
 }
 
+// reloadWorkerAuthKms rotates the TLS material of the worker to the
+// worker-auth KMS of newConf, if its block differs from the one in use.
+func (c *Command) reloadWorkerAuthKms(newConf *config.Config) error {
+	newKms := base.WorkerAuthKmsConfig(newConf)
+	if newKms == nil || reflect.DeepEqual(newKms, c.workerAuthKmsConf) {
+		return nil
+	}
+	wrapper, err := c.ReloadWorkerAuthKms(newConf)
+	if err != nil {
+		return err
+	}
+	if err := c.worker.RotateTls(wrapper); err != nil {
+		return err
+	}
+	c.workerAuthKmsConf = newKms
+	return nil
+}
+
 func (c *Command) Reload() error {
 	c.ReloadFuncsLock.RLock()
 	defer c.ReloadFuncsLock.RUnlock()
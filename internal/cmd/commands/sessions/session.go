@@ -21,6 +21,9 @@ type Command struct {
 	*base.Command
 
 	Func string
+
+	flagWatch         bool
+	flagWatchInterval time.Duration
 }
 
 func (c *Command) Synopsis() string {
@@ -53,12 +56,26 @@ func (c *Command) Help() string {
 		helpStr = base.WrapForHelpText([]string{
 			"Usage: boundary sessions cancel [options] [args]",
 			"",
-			"  Cancel the session specified by ID. Example:",
+			"  Cancel the session specified by ID. Users may always cancel their own sessions. Example:",
 			"",
 			`    $ boundary sessions cancel -id s_1234567890`,
 			"",
 			"",
 		})
+	case "list":
+		helpStr = base.WrapForHelpText([]string{
+			"Usage: boundary sessions list [options] [args]",
+			"",
+			"  List sessions within the project scope specified by ID. Users without a grant to list the sessions of the scope are shown only their own sessions. Example:",
+			"",
+			`    $ boundary sessions list -scope-id p_1234567890`,
+			"",
+			"  Watch the sessions which are not terminated, with their connection counts and byte totals:",
+			"",
+			`    $ boundary sessions list -scope-id p_1234567890 -watch`,
+			"",
+			"",
+		})
 	default:
 		helpStr = helpMap[c.Func]()
 	}
@@ -70,6 +87,20 @@ func (c *Command) Flags() *base.FlagSets {
 	f := set.NewFlagSet("Command Options")
 	common.PopulateCommonFlags(c.Command, f, resource.Session.String(), flagsMap[c.Func])
 
+	if c.Func == "list" {
+		f.BoolVar(&base.BoolVar{
+			Name:   "watch",
+			Target: &c.flagWatch,
+			Usage:  "If set, the sessions which are not terminated are shown with their connection counts and byte totals, refreshed until interrupted.",
+		})
+		f.DurationVar(&base.DurationVar{
+			Name:    "watch-interval",
+			Target:  &c.flagWatchInterval,
+			Default: 2 * time.Second,
+			Usage:   "How often the sessions are refreshed when -watch is set.",
+		})
+	}
+
 	return set
 }
 
@@ -110,6 +141,14 @@ func (c *Command) Run(args []string) int {
 
 	sessionClient := sessions.NewClient(client)
 
+	if c.flagWatch {
+		if c.flagWatchInterval <= 0 {
+			c.UI.Error("Watch interval must be positive")
+			return 1
+		}
+		return c.runWatch(sessionClient)
+	}
+
 	var result api.GenericResult
 	var listResult api.GenericListResult
 
@@ -0,0 +1,170 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/sessions"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+)
+
+// clearScreen moves the cursor home and clears the terminal so each frame of
+// the watch output replaces the previous one.
+const clearScreen = "\033[H\033[2J"
+
+// watchedSession is a session with its connection stats, as output by list
+// -watch in JSON format.
+type watchedSession struct {
+	*sessions.Session
+	ActiveConnectionCount uint32 `json:"active_connection_count"`
+	TotalConnectionCount  uint32 `json:"total_connection_count"`
+	BytesUp               uint64 `json:"bytes_up"`
+	BytesDown             uint64 `json:"bytes_down"`
+}
+
+// watchFrame is a single refresh of list -watch in JSON format.
+type watchFrame struct {
+	Time  time.Time         `json:"time"`
+	Items []*watchedSession `json:"items"`
+}
+
+// runWatch renders the sessions of the scope which are not terminated, with
+// their connection counts and byte totals, every c.flagWatchInterval until the
+// command is interrupted. In JSON format each refresh is output as one line.
+// An error on the first refresh fails the command; later errors are shown and
+// the command keeps watching.
+func (c *Command) runWatch(sessionClient *sessions.Client) int {
+	ticker := time.NewTicker(c.flagWatchInterval)
+	defer ticker.Stop()
+
+	for first := true; ; first = false {
+		frame, err := c.fetchWatchFrame(c.Context, sessionClient)
+		switch {
+		case err != nil && first:
+			if apiErr := api.AsServerError(err); apiErr != nil {
+				c.UI.Error(fmt.Sprintf("Error from controller when performing list on sessions: %s", base.PrintApiError(apiErr)))
+				return 1
+			}
+			c.UI.Error(fmt.Sprintf("Error trying to list sessions: %s", err.Error()))
+			return 2
+		case err != nil:
+			if c.Context.Err() != nil {
+				return 0
+			}
+			c.UI.Error(fmt.Sprintf("%s Error refreshing sessions: %s", time.Now().Format(time.RFC3339), err.Error()))
+		default:
+			switch base.Format(c.UI) {
+			case "json":
+				b, err := base.JsonFormatter{}.Format(frame)
+				if err != nil {
+					c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+					return 1
+				}
+				c.UI.Output(string(b))
+			case "table":
+				c.UI.Output(clearScreen + c.generateWatchTableOutput(frame))
+			}
+		}
+
+		select {
+		case <-c.Context.Done():
+			return 0
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetchWatchFrame lists the sessions of the scope and their connection stats.
+// Terminated sessions are left out.
+func (c *Command) fetchWatchFrame(ctx context.Context, sessionClient *sessions.Client) (*watchFrame, error) {
+	listResult, err := sessionClient.List(ctx, c.FlagScopeId)
+	if err != nil {
+		return nil, err
+	}
+	statsResult, err := sessionClient.ListConnectionStats(ctx, c.FlagScopeId)
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]*sessions.ConnectionStats, len(statsResult.Items))
+	for _, s := range statsResult.Items {
+		stats[s.SessionId] = s
+	}
+
+	frame := &watchFrame{
+		Time:  time.Now(),
+		Items: []*watchedSession{},
+	}
+	for _, sess := range listResult.Items {
+		if sess.Status == "terminated" {
+			continue
+		}
+		ws := &watchedSession{Session: sess}
+		if s, ok := stats[sess.Id]; ok {
+			ws.ActiveConnectionCount = s.ActiveConnectionCount
+			ws.TotalConnectionCount = s.TotalConnectionCount
+			ws.BytesUp = s.BytesUp
+			ws.BytesDown = s.BytesDown
+		}
+		frame.Items = append(frame.Items, ws)
+	}
+	sort.Slice(frame.Items, func(i, j int) bool {
+		return frame.Items[i].CreatedTime.Before(frame.Items[j].CreatedTime)
+	})
+	return frame, nil
+}
+
+func (c *Command) generateWatchTableOutput(frame *watchFrame) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sessions in scope %s at %s (refreshing every %s, press Ctrl-C to exit)\n\n",
+		c.FlagScopeId, frame.Time.Local().Format(time.RFC1123), c.flagWatchInterval)
+	if len(frame.Items) == 0 {
+		b.WriteString("No active sessions found\n")
+		return b.String()
+	}
+
+	tw := tabwriter.NewWriter(&b, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tUSER ID\tTARGET ID\tCONNECTIONS\tBYTES UP\tBYTES DOWN\tEXPIRES IN")
+	var active, total uint32
+	var up, down uint64
+	for _, s := range frame.Items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d/%d\t%s\t%s\t%s\n",
+			s.Id,
+			s.Status,
+			s.UserId,
+			s.TargetId,
+			s.ActiveConnectionCount,
+			s.TotalConnectionCount,
+			formatBytes(s.BytesUp),
+			formatBytes(s.BytesDown),
+			s.ExpirationTime.Sub(frame.Time).Truncate(time.Second),
+		)
+		active += s.ActiveConnectionCount
+		total += s.TotalConnectionCount
+		up += s.BytesUp
+		down += s.BytesDown
+	}
+	fmt.Fprintf(tw, "\t\t\t\t%d/%d\t%s\t%s\t\n", active, total, formatBytes(up), formatBytes(down))
+	tw.Flush()
+
+	b.WriteString("\nConnections are shown as active/total. Bytes are reported when connections close.\n")
+	return b.String()
+}
+
+// formatBytes formats n using binary units, e.g. 1.5 KiB.
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
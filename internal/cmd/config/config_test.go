@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/shared-secure-libs/configutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDevController(t *testing.T) {
@@ -115,3 +116,54 @@ func TestDevWorker(t *testing.T) {
 
 	assert.Equal(t, exp, actual)
 }
+
+func TestParse_ListenerAccess(t *testing.T) {
+	conf, err := Parse(`
+controller {
+	name = "c1"
+	listener_access {
+		purpose = "api"
+		allow = ["10.0.0.0/8", "2001:db8::/32"]
+		deny = ["10.1.0.0/16"]
+	}
+	listener_access {
+		purpose = "cluster"
+		allow = ["10.2.0.0/16"]
+	}
+}
+`)
+	require.NoError(t, err)
+	assert.Equal(t, "c1", conf.Controller.Name)
+	assert.Equal(t, []*ListenerAccess{
+		{Purpose: "api", Allow: []string{"10.0.0.0/8", "2001:db8::/32"}, Deny: []string{"10.1.0.0/16"}},
+		{Purpose: "cluster", Allow: []string{"10.2.0.0/16"}},
+	}, conf.Controller.ListenerAccess)
+}
+
+func TestParse_AuthHooks(t *testing.T) {
+	conf, err := Parse(`
+controller {
+	name = "c1"
+	auth_hook {
+		stage = "pre-authenticate"
+		url = "https://risk.example.com/boundary"
+		auth_method_ids = ["ampw_1234567890"]
+		timeout = "2s"
+	}
+	auth_hook {
+		stage = "post-authenticate"
+		url = "https://audit.example.com/boundary"
+		auth_method_ids = ["ampw_1234567890", "ampw_0987654321"]
+	}
+}
+`)
+	require.NoError(t, err)
+	require.Len(t, conf.Controller.AuthHooks, 2)
+	pre, post := conf.Controller.AuthHooks[0], conf.Controller.AuthHooks[1]
+	assert.Equal(t, "pre-authenticate", pre.Stage)
+	assert.Equal(t, []string{"ampw_1234567890"}, pre.AuthMethodIds)
+	assert.Equal(t, 2*time.Second, pre.TimeoutDuration)
+	assert.Equal(t, "post-authenticate", post.Stage)
+	assert.Equal(t, []string{"ampw_1234567890", "ampw_0987654321"}, post.AuthMethodIds)
+	assert.Zero(t, post.TimeoutDuration)
+}
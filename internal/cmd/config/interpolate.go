@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// interpolationRe matches a call of an interpolation function at the start of
+// the text, e.g. env("BOUNDARY_DB_URL") or file("/run/secrets/root-key").
+var interpolationRe = regexp.MustCompile(`^(env|file)\(\s*("(?:[^"\\\n]|\\.)*")\s*\)`)
+
+// Interpolate replaces the calls of the interpolation functions in the
+// configuration d with the quoted strings they return, so secrets such as
+// database URLs, KMS keys and TLS materials can be injected by orchestration
+// systems without templating the whole file. env("NAME") returns the value of
+// the environment variable NAME, which must be set. file("path") returns the
+// contents of the file at path, relative to the working directory, without
+// trailing newlines.
+//
+// Calls are only replaced where a value is expected, not within strings or
+// comments, and are replaced on a single line so the positions of lines are
+// kept.
+func Interpolate(d string) (string, error) {
+	var b strings.Builder
+	line := 1
+	for i := 0; i < len(d); {
+		c := d[i]
+		switch {
+		case c == '\n':
+			line++
+			b.WriteByte(c)
+			i++
+
+		case c == '"':
+			// Copy the string up to and including its closing quote
+			j := i + 1
+			for ; j < len(d) && d[j] != '"' && d[j] != '\n'; j++ {
+				if d[j] == '\\' {
+					j++
+				}
+			}
+			if j < len(d) && d[j] == '"' {
+				j++
+			}
+			b.WriteString(d[i:j])
+			i = j
+
+		case c == '#' || strings.HasPrefix(d[i:], "//"):
+			j := strings.IndexByte(d[i:], '\n')
+			if j < 0 {
+				j = len(d) - i
+			}
+			b.WriteString(d[i : i+j])
+			i += j
+
+		case strings.HasPrefix(d[i:], "/*"):
+			j := strings.Index(d[i+2:], "*/")
+			if j < 0 {
+				j = len(d) - i - 2
+			} else {
+				j += 2
+			}
+			comment := d[i : i+2+j]
+			line += strings.Count(comment, "\n")
+			b.WriteString(comment)
+			i += len(comment)
+
+		case strings.HasPrefix(d[i:], "<<"):
+			// Copy the heredoc up to and including its end marker
+			nl := strings.IndexByte(d[i:], '\n')
+			if nl < 0 {
+				b.WriteString(d[i:])
+				i = len(d)
+				continue
+			}
+			marker := strings.TrimSpace(strings.TrimLeft(d[i+2:i+nl], "-"))
+			j := i + nl
+			for j < len(d) {
+				next := strings.IndexByte(d[j+1:], '\n')
+				if next < 0 {
+					next = len(d) - j - 1
+				}
+				end := strings.TrimSpace(d[j+1:j+1+next]) == marker
+				j += 1 + next
+				if end {
+					break
+				}
+			}
+			heredoc := d[i:j]
+			line += strings.Count(heredoc, "\n")
+			b.WriteString(heredoc)
+			i = j
+
+		case isIdentByte(c) && (i == 0 || !isIdentByte(d[i-1])):
+			m := interpolationRe.FindStringSubmatch(d[i:])
+			if m == nil {
+				b.WriteByte(c)
+				i++
+				continue
+			}
+			arg, err := strconv.Unquote(m[2])
+			if err != nil {
+				return "", &interpolationError{line: line, err: fmt.Errorf("invalid argument of %s: %w", m[1], err)}
+			}
+			v, err := interpolate(m[1], arg)
+			if err != nil {
+				return "", &interpolationError{line: line, err: err}
+			}
+			b.WriteString(strconv.Quote(v))
+			i += len(m[0])
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), nil
+}
+
+// interpolationError is an error of the interpolation function called on
+// line.
+type interpolationError struct {
+	line int
+	err  error
+}
+
+func (e *interpolationError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.err)
+}
+
+func (e *interpolationError) Unwrap() error {
+	return e.err
+}
+
+func interpolate(fn, arg string) (string, error) {
+	switch fn {
+	case "env":
+		v, ok := os.LookupEnv(arg)
+		if !ok {
+			return "", fmt.Errorf("environment variable %q referenced by env() is not set", arg)
+		}
+		return v, nil
+	case "file":
+		contents, err := ioutil.ReadFile(arg)
+		if err != nil {
+			return "", fmt.Errorf("error reading file %q referenced by file(): %w", arg, err)
+		}
+		return strings.TrimRight(string(contents), "\r\n"), nil
+	default:
+		return "", fmt.Errorf("unknown interpolation function %q", fn)
+	}
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '-' || c == '.' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
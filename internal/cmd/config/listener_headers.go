@@ -0,0 +1,198 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/shared-secure-libs/configutil"
+	"github.com/hashicorp/vault/sdk/helper/parseutil"
+)
+
+const (
+	// DefaultCorsMaxAge is how long browsers may cache the answers to CORS
+	// preflight requests by default.
+	DefaultCorsMaxAge = 5 * time.Minute
+
+	// hstsPreloadMinMaxAge is the smallest HSTS max age accepted for the
+	// preload lists of browsers.
+	hstsPreloadMinMaxAge = 365 * 24 * time.Hour
+)
+
+// DefaultCorsAllowedMethods are the methods allowed for cross-origin requests
+// by default.
+var DefaultCorsAllowedMethods = []string{
+	http.MethodDelete,
+	http.MethodGet,
+	http.MethodOptions,
+	http.MethodPost,
+	http.MethodPatch,
+}
+
+// ListenerHeaders are the CORS and security headers sent by an api listener,
+// set with these keys of its listener block besides cors_enabled,
+// cors_allowed_origins and cors_allowed_headers:
+//
+// cors_allowed_methods: the methods allowed for cross-origin requests
+//
+// cors_exposed_headers: the headers of responses readable by cross-origin
+// callers besides the request id
+//
+// cors_max_age: how long the answers to preflight requests may be cached,
+// denoted by time.Duration
+//
+// cors_allow_credentials: whether cross-origin requests may send cookies,
+// which can't be combined with allowing all origins
+//
+// hsts_max_age, hsts_include_subdomains and hsts_preload: the
+// Strict-Transport-Security header, sent if the max age is set. It can be set
+// on listeners with TLS disabled for TLS terminated by a frontend.
+//
+// ui_content_security_policy: the Content-Security-Policy header of the
+// admin UI, e.g. to allow framing it with frame-ancestors
+type ListenerHeaders struct {
+	CorsAllowedMethods   []string
+	CorsExposedHeaders   []string
+	CorsMaxAge           time.Duration
+	CorsAllowCredentials bool
+
+	HstsMaxAge            time.Duration
+	HstsIncludeSubdomains bool
+	HstsPreload           bool
+
+	UiContentSecurityPolicy string
+}
+
+// ParseListenerHeaders returns the headers of the listener, validating them.
+// A nil listener has the default headers.
+func ParseListenerHeaders(l *configutil.Listener) (*ListenerHeaders, error) {
+	h := &ListenerHeaders{
+		CorsAllowedMethods: DefaultCorsAllowedMethods,
+		CorsMaxAge:         DefaultCorsMaxAge,
+	}
+	if l == nil {
+		return h, nil
+	}
+	raw := l.RawConfig
+	var err error
+
+	if v, ok := raw["cors_allowed_methods"]; ok {
+		if h.CorsAllowedMethods, err = parseutil.ParseCommaStringSlice(v); err != nil {
+			return nil, fmt.Errorf("invalid cors_allowed_methods: %w", err)
+		}
+		for i, m := range h.CorsAllowedMethods {
+			m = strings.ToUpper(m)
+			switch m {
+			case http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPatch, http.MethodPost, http.MethodPut:
+			default:
+				return nil, fmt.Errorf("invalid cors_allowed_methods: unknown method %q", m)
+			}
+			h.CorsAllowedMethods[i] = m
+		}
+	}
+	if v, ok := raw["cors_exposed_headers"]; ok {
+		if h.CorsExposedHeaders, err = parseutil.ParseCommaStringSlice(v); err != nil {
+			return nil, fmt.Errorf("invalid cors_exposed_headers: %w", err)
+		}
+		for _, eh := range h.CorsExposedHeaders {
+			if !validHeaderName(eh) {
+				return nil, fmt.Errorf("invalid cors_exposed_headers: invalid header name %q", eh)
+			}
+		}
+	}
+	for _, ah := range l.CorsAllowedHeaders {
+		if !validHeaderName(ah) {
+			return nil, fmt.Errorf("invalid cors_allowed_headers: invalid header name %q", ah)
+		}
+	}
+	if v, ok := raw["cors_max_age"]; ok {
+		if h.CorsMaxAge, err = parseutil.ParseDurationSecond(v); err != nil {
+			return nil, fmt.Errorf("invalid cors_max_age: %w", err)
+		}
+		if h.CorsMaxAge < 0 {
+			return nil, fmt.Errorf("invalid cors_max_age: must not be negative")
+		}
+	}
+	if v, ok := raw["cors_allow_credentials"]; ok {
+		if h.CorsAllowCredentials, err = parseutil.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid cors_allow_credentials: %w", err)
+		}
+	}
+	for _, o := range l.CorsAllowedOrigins {
+		switch {
+		case o == "*" && len(l.CorsAllowedOrigins) > 1:
+			return nil, fmt.Errorf(`invalid cors_allowed_origins: "*" must be the only origin`)
+		case o == "*" && h.CorsAllowCredentials:
+			return nil, fmt.Errorf(`invalid cors_allowed_origins: "*" can't be combined with cors_allow_credentials`)
+		case o == "" || strings.ContainsAny(o, " \t\r\n,"):
+			return nil, fmt.Errorf("invalid cors_allowed_origins: invalid origin %q", o)
+		}
+	}
+
+	if v, ok := raw["hsts_max_age"]; ok {
+		if h.HstsMaxAge, err = parseutil.ParseDurationSecond(v); err != nil {
+			return nil, fmt.Errorf("invalid hsts_max_age: %w", err)
+		}
+		if h.HstsMaxAge < 0 {
+			return nil, fmt.Errorf("invalid hsts_max_age: must not be negative")
+		}
+	}
+	if v, ok := raw["hsts_include_subdomains"]; ok {
+		if h.HstsIncludeSubdomains, err = parseutil.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid hsts_include_subdomains: %w", err)
+		}
+	}
+	if v, ok := raw["hsts_preload"]; ok {
+		if h.HstsPreload, err = parseutil.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid hsts_preload: %w", err)
+		}
+	}
+	switch {
+	case (h.HstsIncludeSubdomains || h.HstsPreload) && h.HstsMaxAge == 0:
+		return nil, fmt.Errorf("invalid hsts_include_subdomains or hsts_preload: hsts_max_age is not set")
+	case h.HstsPreload && (!h.HstsIncludeSubdomains || h.HstsMaxAge < hstsPreloadMinMaxAge):
+		return nil, fmt.Errorf("invalid hsts_preload: requires hsts_include_subdomains and an hsts_max_age of at least a year")
+	}
+
+	if v, ok := raw["ui_content_security_policy"]; ok {
+		csp, ok := v.(string)
+		if !ok || strings.TrimSpace(csp) == "" || strings.ContainsAny(csp, "\r\n") {
+			return nil, fmt.Errorf("invalid ui_content_security_policy: must be a single line of directives")
+		}
+		h.UiContentSecurityPolicy = strings.TrimSpace(csp)
+	}
+	return h, nil
+}
+
+// StrictTransportSecurity returns the value of the Strict-Transport-Security
+// header, or an empty string if it is not sent.
+func (h *ListenerHeaders) StrictTransportSecurity() string {
+	if h.HstsMaxAge <= 0 {
+		return ""
+	}
+	v := fmt.Sprintf("max-age=%d", int64(h.HstsMaxAge/time.Second))
+	if h.HstsIncludeSubdomains {
+		v += "; includeSubDomains"
+	}
+	if h.HstsPreload {
+		v += "; preload"
+	}
+	return v
+}
+
+// validHeaderName returns true if name is a valid HTTP header name.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
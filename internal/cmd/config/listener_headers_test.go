@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListenerHeaders(t *testing.T) {
+	const base = `
+listener "tcp" {
+	purpose = "api"
+	tls_disable = true
+	cors_enabled = true
+%s
+}
+`
+	parse := func(keys string) (*ListenerHeaders, error) {
+		c, err := Parse(fmt.Sprintf(base, keys))
+		if err != nil {
+			return nil, err
+		}
+		return ParseListenerHeaders(c.Listeners[0])
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		h, err := parse(`cors_allowed_origins = ["*"]`)
+		require.NoError(err)
+		assert.Equal(DefaultCorsAllowedMethods, h.CorsAllowedMethods)
+		assert.Equal(DefaultCorsMaxAge, h.CorsMaxAge)
+		assert.Empty(h.StrictTransportSecurity())
+		assert.Empty(h.UiContentSecurityPolicy)
+
+		h, err = ParseListenerHeaders(nil)
+		require.NoError(err)
+		assert.Equal(DefaultCorsMaxAge, h.CorsMaxAge)
+	})
+	t.Run("configured", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		h, err := parse(`
+	cors_allowed_origins = ["https://admin.example.com"]
+	cors_allowed_methods = ["get", "post"]
+	cors_exposed_headers = ["X-Total-Count"]
+	cors_max_age = "1h"
+	cors_allow_credentials = true
+	hsts_max_age = "8760h"
+	hsts_include_subdomains = true
+	hsts_preload = true
+	ui_content_security_policy = "default-src 'self'; frame-ancestors https://portal.example.com"
+`)
+		require.NoError(err)
+		assert.Equal([]string{"GET", "POST"}, h.CorsAllowedMethods)
+		assert.Equal([]string{"X-Total-Count"}, h.CorsExposedHeaders)
+		assert.Equal(time.Hour, h.CorsMaxAge)
+		assert.True(h.CorsAllowCredentials)
+		assert.Equal("max-age=31536000; includeSubDomains; preload", h.StrictTransportSecurity())
+		assert.Equal("default-src 'self'; frame-ancestors https://portal.example.com", h.UiContentSecurityPolicy)
+	})
+
+	invalid := map[string]string{
+		"unknown-method":          `cors_allowed_methods = ["FETCH"]`,
+		"invalid-exposed-header":  `cors_exposed_headers = ["X Total"]`,
+		"negative-max-age":        `cors_max_age = "-1s"`,
+		"wildcard-and-origins":    `cors_allowed_origins = ["*", "https://example.com"]`,
+		"wildcard-credentials":    "cors_allowed_origins = [\"*\"]\ncors_allow_credentials = true",
+		"subdomains-without-hsts": `hsts_include_subdomains = true`,
+		"short-preload":           "hsts_max_age = \"24h\"\nhsts_include_subdomains = true\nhsts_preload = true",
+		"multiline-csp":           "ui_content_security_policy = \"default-src 'self';\\nscript-src 'self'\"",
+	}
+	for name, keys := range invalid {
+		keys := keys
+		t.Run(name, func(t *testing.T) {
+			_, err := parse(keys)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,514 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/parser"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/hashicorp/shared-secure-libs/configutil"
+	"github.com/hashicorp/vault/sdk/helper/parseutil"
+)
+
+// syslogFacilities are the names of the syslog facilities a worker's
+// connection log may use.
+var syslogFacilities = map[string]bool{
+	"KERN": true, "USER": true, "MAIL": true, "DAEMON": true, "AUTH": true,
+	"SYSLOG": true, "LPR": true, "NEWS": true, "UUCP": true, "CRON": true,
+	"AUTHPRIV": true, "FTP": true, "LOCAL0": true, "LOCAL1": true,
+	"LOCAL2": true, "LOCAL3": true, "LOCAL4": true, "LOCAL5": true,
+	"LOCAL6": true, "LOCAL7": true,
+}
+
+// ValidationError is a problem found in a configuration file by Validate.
+type ValidationError struct {
+	// Pos is the position of the problem in the file. It is not valid for
+	// problems which are not about a specific part of the file, e.g. a
+	// missing block.
+	Pos     token.Pos
+	Message string
+}
+
+// Error returns the message prefixed with the position, if known.
+func (e *ValidationError) Error() string {
+	if !e.Pos.IsValid() {
+		if e.Pos.Filename != "" {
+			return fmt.Sprintf("%s: %s", e.Pos.Filename, e.Message)
+		}
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// ValidateFile reads the configuration file at path, decrypting it with the
+// wrapper if not nil, and validates it. The returned error is only set if the
+// file cannot be read or decrypted; problems with its content are returned as
+// ValidationErrors.
+func ValidateFile(path string, wrapper wrapping.Wrapper) ([]*ValidationError, error) {
+	d, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw := string(d)
+	if wrapper != nil {
+		if raw, err = configutil.EncryptDecrypt(raw, true, true, wrapper); err != nil {
+			return nil, err
+		}
+	}
+	return Validate(path, raw), nil
+}
+
+// Validate parses the configuration d and checks that a controller and/or
+// worker can be started with it, returning all problems found rather than only
+// the first one. filename is only used in the positions of the problems.
+func Validate(filename, d string) []*ValidationError {
+	v := &validator{filename: filename}
+	d, err := Interpolate(d)
+	if err != nil {
+		var interpErr *interpolationError
+		if errors.As(err, &interpErr) {
+			v.add(token.Pos{Line: interpErr.line}, interpErr.err.Error())
+		} else {
+			v.add(token.Pos{}, err.Error())
+		}
+		return v.errs
+	}
+	file, err := hcl.Parse(d)
+	if err != nil {
+		var posErr *parser.PosError
+		if errors.As(err, &posErr) {
+			v.add(posErr.Pos, posErr.Err.Error())
+		} else {
+			v.add(token.Pos{}, err.Error())
+		}
+		return v.errs
+	}
+	root, ok := file.Node.(*ast.ObjectList)
+	if !ok {
+		v.add(token.Pos{}, "configuration is not an object")
+		return v.errs
+	}
+	v.validate(root)
+	if len(v.errs) == 0 {
+		// Decoding stops at the first problem, and most are reported with
+		// their positions above, so it only catches the remaining ones, e.g.
+		// the contents of listener and kms blocks.
+		if _, err := Parse(d); err != nil {
+			v.add(token.Pos{}, err.Error())
+		}
+	}
+	sort.SliceStable(v.errs, func(i, j int) bool { return v.errs[i].Pos.Line < v.errs[j].Pos.Line })
+	return v.errs
+}
+
+type validator struct {
+	filename string
+	errs     []*ValidationError
+}
+
+func (v *validator) add(pos token.Pos, format string, a ...interface{}) {
+	pos.Filename = v.filename
+	msg := format
+	if len(a) > 0 {
+		msg = fmt.Sprintf(format, a...)
+	}
+	v.errs = append(v.errs, &ValidationError{Pos: pos, Message: msg})
+}
+
+func (v *validator) validate(root *ast.ObjectList) {
+	controllers := root.Filter("controller")
+	workers := root.Filter("worker")
+	if len(controllers.Items) == 0 && len(workers.Items) == 0 {
+		v.add(token.Pos{}, "neither worker nor controller specified in configuration file")
+	}
+	isController := len(controllers.Items) > 0
+	isWorker := len(workers.Items) > 0
+	for i, item := range controllers.Items {
+		if i > 0 {
+			v.add(itemPos(item), "only one controller block is permitted")
+			continue
+		}
+		v.validateController(item)
+	}
+	for i, item := range workers.Items {
+		if i > 0 {
+			v.add(itemPos(item), "only one worker block is permitted")
+			continue
+		}
+		v.validateWorker(item)
+	}
+	for i, item := range root.Filter("dns_cache").Items {
+		if i > 0 {
+			v.add(itemPos(item), "only one dns_cache block is permitted")
+			continue
+		}
+		if dcObj, ok := v.object(item, "dns_cache"); ok {
+			v.checkKeys(dcObj, "dns_cache", DnsCache{})
+			v.checkDuration(dcObj, "ttl")
+			v.checkDuration(dcObj, "negative_ttl")
+		}
+	}
+	v.validateListeners(root, isController, isWorker)
+	v.validateKms(root, isController, isWorker)
+}
+
+func (v *validator) validateController(item *ast.ObjectItem) {
+	obj, ok := v.object(item, "controller")
+	if !ok {
+		return
+	}
+	v.checkKeys(obj, "controller", Controller{})
+	v.checkName(item, obj, "controller")
+	v.checkDuration(obj, "auth_token_time_to_live")
+	v.checkDuration(obj, "auth_token_time_to_stale")
+	v.checkDuration(obj, "auth_token_max_lifetime")
+	v.checkDuration(obj, "pending_session_time_to_live")
+	v.checkDuration(obj, "idle_session_time_to_live")
+
+	databases := obj.Filter("database")
+	switch len(databases.Items) {
+	case 0:
+		v.add(itemPos(item), `"controller" block has no "database" block`)
+	default:
+		if dbObj, ok := v.object(databases.Items[0], "database"); ok {
+			v.checkKeys(dbObj, "database", Database{})
+			if url, ok := literalString(dbObj, "url"); !ok || strings.TrimSpace(url) == "" {
+				v.add(itemPos(databases.Items[0]), `"database" block has no "url"`)
+			}
+			switch d, _ := literalString(dbObj, "schema_drift"); d {
+			case "", "fail", "warn":
+			default:
+				v.add(itemPos(dbObj.Filter("schema_drift").Items[0]), "unknown schema_drift %q", d)
+			}
+		}
+	}
+	for _, rc := range obj.Filter("response_cache").Items {
+		if rcObj, ok := v.object(rc, "response_cache"); ok {
+			v.checkKeys(rcObj, "response_cache", ResponseCache{})
+			v.checkDuration(rcObj, "time_to_live")
+		}
+	}
+	for _, sc := range obj.Filter("statement_cache").Items {
+		if scObj, ok := v.object(sc, "statement_cache"); ok {
+			v.checkKeys(scObj, "statement_cache", StatementCache{})
+		}
+	}
+	for _, ws := range obj.Filter("worker_selection").Items {
+		if wsObj, ok := v.object(ws, "worker_selection"); ok {
+			v.checkKeys(wsObj, "worker_selection", WorkerSelection{})
+			v.checkDuration(wsObj, "affinity_window")
+		}
+	}
+	purposes := map[string]bool{}
+	for _, la := range obj.Filter("listener_access").Items {
+		laObj, ok := v.object(la, "listener_access")
+		if !ok {
+			continue
+		}
+		v.checkKeys(laObj, "listener_access", ListenerAccess{})
+		switch p, _ := literalString(laObj, "purpose"); p {
+		case "api", "cluster":
+			if purposes[p] {
+				v.add(itemPos(la), "more than one listener_access block with purpose %q", p)
+			}
+			purposes[p] = true
+		case "":
+			v.add(itemPos(la), `"listener_access" block has no "purpose"`)
+		default:
+			v.add(itemPos(la), "unknown listener_access purpose %q", p)
+		}
+		for _, key := range []string{"allow", "deny"} {
+			for _, cidr := range literalStrings(laObj, key) {
+				if _, _, err := net.ParseCIDR(cidr); err != nil {
+					v.add(itemPos(laObj.Filter(key).Items[0]), "invalid CIDR %q in %q", cidr, key)
+				}
+			}
+		}
+	}
+	for _, wa := range obj.Filter("worker_access").Items {
+		waObj, ok := v.object(wa, "worker_access")
+		if !ok {
+			continue
+		}
+		v.checkKeys(waObj, "worker_access", WorkerAccess{})
+		for _, cidr := range literalStrings(waObj, "allowed_cidrs") {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				v.add(itemPos(waObj.Filter("allowed_cidrs").Items[0]), "invalid CIDR %q in %q", cidr, "allowed_cidrs")
+			}
+		}
+		if p, ok := literalString(waObj, "name_pattern"); ok {
+			if _, err := regexp.Compile(p); err != nil {
+				v.add(itemPos(waObj.Filter("name_pattern").Items[0]), "invalid worker_access name_pattern %q: %s", p, err)
+			}
+		}
+	}
+	for _, ah := range obj.Filter("auth_hook").Items {
+		ahObj, ok := v.object(ah, "auth_hook")
+		if !ok {
+			continue
+		}
+		v.checkKeys(ahObj, "auth_hook", AuthHook{})
+		v.checkDuration(ahObj, "timeout")
+		switch s, _ := literalString(ahObj, "stage"); s {
+		case "pre-authenticate", "post-authenticate":
+		case "":
+			v.add(itemPos(ah), `"auth_hook" block has no "stage"`)
+		default:
+			v.add(itemPos(ah), "unknown auth_hook stage %q", s)
+		}
+		switch p, _ := literalString(ahObj, "failure_policy"); p {
+		case "", "open", "closed":
+		default:
+			v.add(itemPos(ahObj.Filter("failure_policy").Items[0]), "unknown auth_hook failure_policy %q", p)
+		}
+		if u, ok := literalString(ahObj, "url"); !ok || strings.TrimSpace(u) == "" {
+			v.add(itemPos(ah), `"auth_hook" block has no "url"`)
+		} else if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			v.add(itemPos(ahObj.Filter("url").Items[0]), "invalid auth_hook url %q", u)
+		}
+	}
+	for _, rl := range obj.Filter("request_limits").Items {
+		if rlObj, ok := v.object(rl, "request_limits"); ok {
+			v.checkKeys(rlObj, "request_limits", RequestLimits{})
+			v.checkDuration(rlObj, "queue_timeout")
+			for _, mbs := range rlObj.Filter("max_body_sizes").Items {
+				sizes, ok := mbs.Val.(*ast.ObjectType)
+				if !ok {
+					continue
+				}
+				for _, size := range sizes.List.Items {
+					if len(size.Keys) == 0 {
+						continue
+					}
+					switch class, _ := size.Keys[0].Token.Value().(string); class {
+					case "authenticate", "import", "default":
+					default:
+						v.add(itemPos(size), "unknown request_limits endpoint class %q", class)
+					}
+				}
+			}
+		}
+	}
+	for _, sf := range obj.Filter("secret_fingerprints").Items {
+		sfObj, ok := v.object(sf, "secret_fingerprints")
+		if !ok {
+			continue
+		}
+		v.checkKeys(sfObj, "secret_fingerprints", SecretFingerprints{})
+		if k, ok := literalString(sfObj, "key"); !ok || strings.TrimSpace(k) == "" {
+			v.add(itemPos(sf), `"secret_fingerprints" block has no "key"`)
+		} else if len(k) < 16 {
+			v.add(itemPos(sfObj.Filter("key").Items[0]), "secret_fingerprints key must be at least 16 bytes")
+		}
+	}
+}
+
+func (v *validator) validateWorker(item *ast.ObjectItem) {
+	obj, ok := v.object(item, "worker")
+	if !ok {
+		return
+	}
+	v.checkKeys(obj, "worker", Worker{})
+	v.checkName(item, obj, "worker")
+	v.checkDuration(obj, "session_cache_window")
+	v.checkDuration(obj, "heartbeat_interval")
+	v.checkDuration(obj, "tcp_keepalive")
+	v.checkLabel(obj, "region")
+	v.checkLabel(obj, "zone")
+	for _, cl := range obj.Filter("connection_log").Items {
+		if clObj, ok := v.object(cl, "connection_log"); ok {
+			v.checkKeys(clObj, "connection_log", ConnectionLog{})
+			if f, ok := literalString(clObj, "syslog_facility"); ok && !syslogFacilities[strings.ToUpper(f)] {
+				v.add(itemPos(clObj.Filter("syslog_facility").Items[0]), "unknown syslog facility %q", f)
+			}
+		}
+	}
+}
+
+func (v *validator) validateListeners(root *ast.ObjectList, isController, isWorker bool) {
+	found := map[string]bool{}
+	for _, item := range root.Filter("listener").Items {
+		obj, ok := v.object(item, "listener")
+		if !ok {
+			continue
+		}
+		purposes := literalStrings(obj, "purpose")
+		switch len(purposes) {
+		case 0:
+			v.add(itemPos(item), "listener specified without a purpose")
+		case 1:
+			switch purposes[0] {
+			case "api", "cluster", "proxy":
+				found[purposes[0]] = true
+			default:
+				v.add(itemPos(item), "unknown listener purpose %q", purposes[0])
+			}
+		default:
+			v.add(itemPos(item), "specifying a listener with more than one purpose is not supported")
+		}
+	}
+	if isController {
+		for _, p := range []string{"api", "cluster"} {
+			if !found[p] {
+				v.add(token.Pos{}, "config activates controller but no listener with %q purpose found", p)
+			}
+		}
+	}
+	if isWorker && !found["proxy"] {
+		v.add(token.Pos{}, `config activates worker but no listener with "proxy" purpose found`)
+	}
+}
+
+func (v *validator) validateKms(root *ast.ObjectList, isController, isWorker bool) {
+	found := map[string]bool{}
+	for _, item := range root.Filter("kms").Items {
+		obj, ok := v.object(item, "kms")
+		if !ok {
+			continue
+		}
+		purposes := literalStrings(obj, "purpose")
+		if len(purposes) == 0 {
+			v.add(itemPos(item), "KMS block missing 'purpose'")
+		}
+		for _, p := range purposes {
+			switch p {
+			case "root", "worker-auth", "recovery", "config":
+				if found[p] && p != "config" {
+					v.add(itemPos(item), "more than one KMS block with purpose %q", p)
+				}
+				found[p] = true
+			default:
+				v.add(itemPos(item), "unknown KMS purpose %q", p)
+			}
+		}
+	}
+	if isController && !found["root"] {
+		v.add(token.Pos{}, `config activates controller but no KMS block with "root" purpose found`)
+	}
+	if (isController || isWorker) && !found["worker-auth"] {
+		v.add(token.Pos{}, `no KMS block with "worker-auth" purpose found`)
+	}
+}
+
+// itemPos returns the position of the item. Filtering an object list strips
+// the filtered keys from its items, in which case the position of the value is
+// used, which is on the same line for blocks and assignments.
+func itemPos(item *ast.ObjectItem) token.Pos {
+	if len(item.Keys) > 0 {
+		return item.Keys[0].Pos()
+	}
+	return item.Val.Pos()
+}
+
+// object returns the object value of the block item.
+func (v *validator) object(item *ast.ObjectItem, name string) (*ast.ObjectList, bool) {
+	obj, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		v.add(itemPos(item), "%q must be a block", name)
+		return nil, false
+	}
+	return obj.List, true
+}
+
+func (v *validator) checkName(item *ast.ObjectItem, obj *ast.ObjectList, block string) {
+	if name, ok := literalString(obj, "name"); !ok || strings.TrimSpace(name) == "" {
+		v.add(itemPos(item), "%s has no name set; it must be the unique name of this instance", block)
+	}
+}
+
+func (v *validator) checkDuration(obj *ast.ObjectList, key string) {
+	for _, item := range obj.Filter(key).Items {
+		lit, ok := item.Val.(*ast.LiteralType)
+		if !ok {
+			v.add(itemPos(item), "%q must be a duration", key)
+			continue
+		}
+		if _, err := parseutil.ParseDurationSecond(lit.Token.Value()); err != nil {
+			v.add(itemPos(item), "%q is not a valid duration: %s", key, err)
+		}
+	}
+}
+
+// checkLabel reports a value which is not a label workers can send to
+// controllers as gRPC metadata: printable ASCII without spaces.
+func (v *validator) checkLabel(obj *ast.ObjectList, key string) {
+	val, ok := literalString(obj, key)
+	if !ok {
+		return
+	}
+	for _, c := range val {
+		if c <= ' ' || c > '~' {
+			v.add(itemPos(obj.Filter(key).Items[0]), "%q must be printable ASCII without spaces", key)
+			return
+		}
+	}
+}
+
+// checkKeys reports the keys of the block which are not fields of the struct
+// it is decoded into.
+func (v *validator) checkKeys(obj *ast.ObjectList, block string, into interface{}) {
+	valid := map[string]bool{}
+	t := reflect.TypeOf(into)
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("hcl"), ",")[0]
+		if tag != "" && tag != "-" {
+			valid[tag] = true
+		}
+	}
+	for _, item := range obj.Items {
+		if len(item.Keys) == 0 {
+			continue
+		}
+		key, _ := item.Keys[0].Token.Value().(string)
+		if !valid[key] {
+			v.add(itemPos(item), "unknown key %q in %q block", key, block)
+		}
+	}
+}
+
+// literalString returns the string value of the key in the object.
+func literalString(obj *ast.ObjectList, key string) (string, bool) {
+	items := obj.Filter(key).Items
+	if len(items) == 0 {
+		return "", false
+	}
+	lit, ok := items[0].Val.(*ast.LiteralType)
+	if !ok {
+		return "", false
+	}
+	s, ok := lit.Token.Value().(string)
+	return s, ok
+}
+
+// literalStrings returns the string values of the key in the object, which
+// may be a single string or a list of strings.
+func literalStrings(obj *ast.ObjectList, key string) []string {
+	var ret []string
+	for _, item := range obj.Filter(key).Items {
+		switch val := item.Val.(type) {
+		case *ast.LiteralType:
+			if s, ok := val.Token.Value().(string); ok {
+				ret = append(ret, s)
+			}
+		case *ast.ListType:
+			for _, n := range val.List {
+				if lit, ok := n.(*ast.LiteralType); ok {
+					if s, ok := lit.Token.Value().(string); ok {
+						ret = append(ret, s)
+					}
+				}
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,376 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validateTestKms = `
+kms "aead" {
+	purpose = "root"
+	aead_type = "aes-gcm"
+	key = "sP1fnF5Xz85RrXyELHFeZg9Ad2qt4Z4bgNHVGtD6ung="
+}
+
+kms "aead" {
+	purpose = "worker-auth"
+	aead_type = "aes-gcm"
+	key = "8fZBjCUfN0TzjEGLQldGY4+iE9AkOvCfjh7+p0GtRBQ="
+}
+`
+
+const validateTestListeners = `
+listener "tcp" {
+	purpose = "api"
+}
+
+listener "tcp" {
+	purpose = "cluster"
+}
+
+listener "tcp" {
+	purpose = "proxy"
+}
+`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		conf string
+		// want are the expected problems in order, matched by a part of
+		// their messages.
+		want []ValidationError
+	}{
+		{
+			name: "valid",
+			conf: `
+controller {
+	name = "c1"
+	auth_token_time_to_live = "24h"
+	database {
+		url = "postgres://localhost"
+		schema_drift = "warn"
+	}
+	worker_selection {
+		strategy = "weighted"
+		tag_weights = {
+			"region=east" = 2
+		}
+		affinity = true
+		affinity_window = "30m"
+	}
+	request_limits {
+		max_body_sizes = {
+			authenticate = 16384
+			import = 10485760
+		}
+		max_in_flight = 200
+		max_queued = 50
+		queue_timeout = "3s"
+	}
+	listener_access {
+		purpose = "api"
+		allow = ["10.0.0.0/8", "2001:db8::/32"]
+		deny = ["10.1.0.0/16"]
+	}
+	listener_access {
+		purpose = "cluster"
+		allow = ["10.2.0.0/16"]
+	}
+	worker_access {
+		allowed_cidrs = ["10.2.0.0/16"]
+		name_pattern = "^worker-[0-9]+$"
+		required_tags = {
+			region = "eu-west-1"
+		}
+	}
+	auth_hook {
+		stage = "pre-authenticate"
+		url = "https://risk.example.com/boundary"
+		secret = "s3cret"
+		auth_method_ids = ["ampw_1234567890"]
+		timeout = "2s"
+		failure_policy = "closed"
+	}
+}
+
+worker {
+	name = "w1"
+	region = "us-east-1"
+	zone = "us-east-1a"
+	tags {
+		region = "east"
+	}
+	connection_log {
+		file = "/var/log/boundary/connections.log"
+		syslog = true
+		syslog_facility = "local3"
+	}
+}
+
+dns_cache {
+	enabled = true
+	ttl = "1m"
+	negative_ttl = "10s"
+}
+` + validateTestKms + validateTestListeners,
+		},
+		{
+			name: "bad-connection-log",
+			conf: `
+worker {
+	name = "w1"
+	zone = "us east 1a"
+	connection_log {
+		path = "/var/log/boundary/connections.log"
+		syslog_facility = "LOCAL9"
+	}
+}
+` + validateTestKms + validateTestListeners,
+			want: []ValidationError{
+				{Message: `"zone" must be printable ASCII without spaces`},
+				{Message: `unknown key "path" in "connection_log" block`},
+				{Message: `unknown syslog facility "LOCAL9"`},
+			},
+		},
+		{
+			name: "bad-schema-drift",
+			conf: `
+controller {
+	name = "c1"
+	database {
+		url = "postgres://localhost"
+		schema_drift = "ignore"
+	}
+}
+` + validateTestKms + validateTestListeners,
+			want: []ValidationError{
+				{Message: `unknown schema_drift "ignore"`},
+			},
+		},
+		{
+			name: "bad-dns-cache",
+			conf: `
+worker {
+	name = "w1"
+}
+
+dns_cache {
+	enabled = true
+	ttl = "forever"
+	max_entries = 100
+}
+` + validateTestKms + validateTestListeners,
+			want: []ValidationError{
+				{Message: `"ttl" is not a valid duration`},
+				{Message: `unknown key "max_entries" in "dns_cache" block`},
+			},
+		},
+		{
+			name: "bad-request-limits",
+			conf: `
+controller {
+	name = "c1"
+	database {
+		url = "postgres://localhost"
+	}
+	request_limits {
+		max_body_sizes = {
+			upload = 1024
+		}
+		max_inflight = 10
+		queue_timeout = "soon"
+	}
+}
+` + validateTestKms + validateTestListeners,
+			want: []ValidationError{
+				{Message: `unknown request_limits endpoint class "upload"`},
+				{Message: `unknown key "max_inflight" in "request_limits" block`},
+				{Message: `"queue_timeout" is not a valid duration`},
+			},
+		},
+		{
+			name: "bad-listener-access",
+			conf: `
+controller {
+	name = "c1"
+	database {
+		url = "postgres://localhost"
+	}
+	listener_access {
+		purpose = "api"
+		allow = ["10.0.0.0/8", "10.0.0.1"]
+	}
+	listener_access {
+		purpose = "api"
+		deny = ["bogus"]
+	}
+	listener_access {
+		purpose = "proxy"
+		alow = ["10.0.0.0/8"]
+	}
+}
+` + validateTestKms + validateTestListeners,
+			want: []ValidationError{
+				{Message: `invalid CIDR "10.0.0.1" in "allow"`},
+				{Message: `more than one listener_access block with purpose "api"`},
+				{Message: `invalid CIDR "bogus" in "deny"`},
+				{Message: `unknown listener_access purpose "proxy"`},
+				{Message: `unknown key "alow" in "listener_access" block`},
+			},
+		},
+		{
+			name: "bad-worker-access",
+			conf: `
+controller {
+	name = "c1"
+	database {
+		url = "postgres://localhost"
+	}
+	worker_access {
+		allowed_cidrs = ["10.0.0.0/8", "10.0.0.1"]
+		name_pattern = "worker-(["
+		required_tag = { region = "eu" }
+	}
+}
+` + validateTestKms + validateTestListeners,
+			want: []ValidationError{
+				{Message: `invalid CIDR "10.0.0.1" in "allowed_cidrs"`},
+				{Message: `invalid worker_access name_pattern "worker-(["`},
+				{Message: `unknown key "required_tag" in "worker_access" block`},
+			},
+		},
+		{
+			name: "bad-auth-hook",
+			conf: `
+controller {
+	name = "c1"
+	database {
+		url = "postgres://localhost"
+	}
+	auth_hook {
+		stage = "during"
+		url = "ftp://hooks.example.com"
+		failure_policy = "ajar"
+		timeout = "soon"
+	}
+	auth_hook {
+		stage = "pre-authenticate"
+		secret = "s3cret"
+	}
+}
+` + validateTestKms + validateTestListeners,
+			want: []ValidationError{
+				{Message: `unknown auth_hook stage "during"`},
+				{Message: `invalid auth_hook url "ftp://hooks.example.com"`},
+				{Message: `unknown auth_hook failure_policy "ajar"`},
+				{Message: `"timeout" is not a valid duration`},
+				{Message: `"auth_hook" block has no "url"`},
+			},
+		},
+		{
+			name: "bad-secret-fingerprints",
+			conf: `
+controller {
+	name = "c1"
+	database {
+		url = "postgres://localhost"
+	}
+	secret_fingerprints {
+		key = "short"
+		url = "https://scanner.example.com"
+	}
+	secret_fingerprints {
+	}
+}
+` + validateTestKms + validateTestListeners,
+			want: []ValidationError{
+				{Message: "secret_fingerprints key must be at least 16 bytes"},
+				{Message: `unknown key "url" in "secret_fingerprints" block`},
+				{Message: `"secret_fingerprints" block has no "key"`},
+			},
+		},
+		{
+			name: "syntax-error",
+			conf: `
+controller {
+	name = "c1"
+`,
+			want: []ValidationError{{Message: "RBRACE"}},
+		},
+		{
+			name: "no-server",
+			conf: validateTestKms + validateTestListeners,
+			want: []ValidationError{{Message: "neither worker nor controller specified in configuration file"}},
+		},
+		{
+			name: "all-problems",
+			conf: `
+controller {
+	nme = "c1"
+	auth_token_time_to_live = "forever"
+}
+
+worker {
+	name = "w1"
+}
+
+listener "tcp" {
+	purpose = "api"
+}
+
+listener "tcp" {
+	purpose = "bogus"
+}
+` + validateTestKms,
+			want: []ValidationError{
+				{Message: `config activates controller but no listener with "cluster" purpose found`},
+				{Message: `config activates worker but no listener with "proxy" purpose found`},
+				{Message: `controller has no name set; it must be the unique name of this instance`},
+				{Message: `"controller" block has no "database" block`},
+				{Message: `unknown key "nme" in "controller" block`},
+				{Message: `"auth_token_time_to_live" is not a valid duration`},
+				{Message: `unknown listener purpose "bogus"`},
+			},
+		},
+		{
+			name: "missing-kms",
+			conf: `
+worker {
+	name = "w1"
+}
+` + validateTestListeners,
+			want: []ValidationError{{Message: `no KMS block with "worker-auth" purpose found`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got := Validate("test.hcl", tt.conf)
+			require.Len(got, len(tt.want), "unexpected problems %v", got)
+			for i, g := range got {
+				assert.Equal("test.hcl", g.Pos.Filename)
+				assert.Contains(g.Message, tt.want[i].Message)
+			}
+		})
+	}
+}
+
+func TestValidate_Positions(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conf := `
+controller {
+	name = "c1"
+	database {
+		url = "postgres://localhost"
+		unknown = true
+	}
+}
+` + validateTestKms + validateTestListeners
+	got := Validate("test.hcl", conf)
+	require.Len(got, 1)
+	assert.Equal(6, got[0].Pos.Line)
+	assert.Equal(`test.hcl:6:3: unknown key "unknown" in "database" block`, got[0].Error())
+}
@@ -0,0 +1,323 @@
+// Package compliance provides the ability to export a scope's users, grants,
+// session metadata and audit events into a signed archive which can be handed
+// to auditors.
+package compliance
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ManifestFileName is the name of the archive entry containing the
+	// Manifest.
+	ManifestFileName = "manifest.json"
+
+	// SignatureFileName is the name of the archive entry containing the
+	// signature of the Manifest.
+	SignatureFileName = "manifest.sig"
+
+	// manifestAad is used as additional authenticated data when signing the
+	// manifest, so a signature cannot be confused with any other ciphertext
+	// produced by the same wrapper.
+	manifestAad = "boundary-compliance-export-manifest"
+
+	manifestVersion = 1
+)
+
+// Manifest describes the contents of a compliance archive. Files maps each
+// data entry in the archive to the hex encoded SHA-256 digest of its contents.
+type Manifest struct {
+	Version    int               `json:"version"`
+	ScopeId    string            `json:"scope_id"`
+	StartTime  time.Time         `json:"start_time"`
+	EndTime    time.Time         `json:"end_time"`
+	CreateTime time.Time         `json:"create_time"`
+	KeyId      string            `json:"key_id"`
+	Files      map[string]string `json:"files"`
+	Counts     map[string]int    `json:"counts"`
+}
+
+// Exporter writes compliance archives.
+type Exporter struct {
+	reader  db.Reader
+	wrapper wrapping.Wrapper
+}
+
+// NewExporter creates a new Exporter which reads from r and signs the archive
+// manifest with wrapper.
+func NewExporter(r db.Reader, wrapper wrapping.Wrapper) (*Exporter, error) {
+	const op = "compliance.NewExporter"
+	if r == nil {
+		return nil, errors.New(errors.InvalidParameter, op, "nil reader")
+	}
+	if wrapper == nil {
+		return nil, errors.New(errors.InvalidParameter, op, "nil wrapper")
+	}
+	return &Exporter{
+		reader:  r,
+		wrapper: wrapper,
+	}, nil
+}
+
+// section is a single data file in the archive along with the query used to
+// populate it.
+type section struct {
+	name  string
+	query string
+	// timeBound is true when the query is constrained by the export's time
+	// range.
+	timeBound bool
+	write     func(*Exporter, *sectionWriter, *sql.Rows) error
+}
+
+var sections = []section{
+	{name: "users.json", query: exportUsersQuery, write: writeRows(func() interface{} { return &User{} })},
+	{name: "grants.json", query: exportGrantsQuery, write: writeRows(func() interface{} { return &Grant{} })},
+	{name: "principal_roles.json", query: exportPrincipalRolesQuery, write: writeRows(func() interface{} { return &PrincipalRole{} })},
+	{name: "sessions.json", query: exportSessionsQuery, timeBound: true, write: writeRows(func() interface{} { return &Session{} })},
+	{name: "audit_events.json", query: exportAuditEventsQuery, timeBound: true, write: writeAuditEvents},
+}
+
+// Export writes a zip archive to w containing the users, grants, principal
+// role assignments, sessions and audit events for the scope and its
+// descendants. Sessions and audit events are limited to those created within
+// [startTime, endTime). Each data file contains the canonical JSON, as written
+// by db.CanonicalJSON, of one document per line and rows are streamed from the
+// database, so the export never holds the full result set in memory. The archive includes a manifest of SHA-256 digests for
+// every data file and a signature of the manifest produced by the Exporter's
+// wrapper.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, scopeId string, startTime, endTime time.Time) (*Manifest, error) {
+	const op = "compliance.(Exporter).Export"
+	if w == nil {
+		return nil, errors.New(errors.InvalidParameter, op, "nil writer")
+	}
+	if scopeId == "" {
+		return nil, errors.New(errors.InvalidParameter, op, "missing scope id")
+	}
+	if startTime.IsZero() || endTime.IsZero() {
+		return nil, errors.New(errors.InvalidParameter, op, "missing time range")
+	}
+	if !startTime.Before(endTime) {
+		return nil, errors.New(errors.InvalidParameter, op, "start time must be before end time")
+	}
+
+	manifest := &Manifest{
+		Version:    manifestVersion,
+		ScopeId:    scopeId,
+		StartTime:  startTime.UTC(),
+		EndTime:    endTime.UTC(),
+		CreateTime: time.Now().UTC(),
+		KeyId:      e.wrapper.KeyID(),
+		Files:      make(map[string]string, len(sections)),
+		Counts:     make(map[string]int, len(sections)),
+	}
+
+	zw := zip.NewWriter(w)
+	for _, s := range sections {
+		args := []interface{}{scopeId}
+		if s.timeBound {
+			args = append(args, startTime, endTime)
+		}
+		digest, count, err := e.writeSection(ctx, zw, s, args)
+		if err != nil {
+			return nil, errors.Wrap(err, op, errors.WithMsg(s.name))
+		}
+		manifest.Files[s.name] = digest
+		manifest.Counts[s.name] = count
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to marshal manifest"))
+	}
+	sig, err := SignManifest(ctx, e.wrapper, manifestBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+	for _, entry := range []struct {
+		name string
+		data []byte
+	}{
+		{name: ManifestFileName, data: manifestBytes},
+		{name: SignatureFileName, data: sig},
+	} {
+		f, err := zw.Create(entry.name)
+		if err != nil {
+			return nil, errors.Wrap(err, op, errors.WithMsg("unable to create "+entry.name))
+		}
+		if _, err := f.Write(entry.data); err != nil {
+			return nil, errors.Wrap(err, op, errors.WithMsg("unable to write "+entry.name))
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to close archive"))
+	}
+	return manifest, nil
+}
+
+func (e *Exporter) writeSection(ctx context.Context, zw *zip.Writer, s section, args []interface{}) (string, int, error) {
+	const op = "compliance.(Exporter).writeSection"
+	f, err := zw.Create(s.name)
+	if err != nil {
+		return "", 0, errors.Wrap(err, op, errors.WithMsg("unable to create archive entry"))
+	}
+	rows, err := e.reader.Query(ctx, s.query, args)
+	if err != nil {
+		return "", 0, errors.Wrap(err, op)
+	}
+	defer rows.Close()
+
+	sw := newSectionWriter(f)
+	if err := s.write(e, sw, rows); err != nil {
+		return "", 0, errors.Wrap(err, op)
+	}
+	if err := rows.Err(); err != nil {
+		return "", 0, errors.Wrap(err, op)
+	}
+	return hex.EncodeToString(sw.hash.Sum(nil)), sw.count, nil
+}
+
+// sectionWriter writes the canonical JSON of one document per line while
+// computing the digest of everything written, so exports of the same records
+// have the same digests whatever the time zone of the database session.
+type sectionWriter struct {
+	hash  hash.Hash
+	w     io.Writer
+	count int
+}
+
+func newSectionWriter(w io.Writer) *sectionWriter {
+	h := sha256.New()
+	return &sectionWriter{
+		hash: h,
+		w:    io.MultiWriter(w, h),
+	}
+}
+
+func (sw *sectionWriter) write(v interface{}) error {
+	b, err := db.CanonicalJSON(v)
+	if err != nil {
+		return err
+	}
+	if _, err := sw.w.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	sw.count++
+	return nil
+}
+
+// writeRows returns a section writer which scans each row into a new value
+// created by alloc and writes it to the section.
+func writeRows(alloc func() interface{}) func(*Exporter, *sectionWriter, *sql.Rows) error {
+	return func(e *Exporter, sw *sectionWriter, rows *sql.Rows) error {
+		for rows.Next() {
+			v := alloc()
+			if err := e.reader.ScanRows(rows, v); err != nil {
+				return err
+			}
+			if err := sw.write(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// writeAuditEvents groups the per metadata rows returned by
+// exportAuditEventsQuery into a single AuditEvent per oplog entry.
+func writeAuditEvents(_ *Exporter, sw *sectionWriter, rows *sql.Rows) error {
+	var current *AuditEvent
+	for rows.Next() {
+		var (
+			id             uint64
+			createTime     time.Time
+			aggregate, key string
+			value          string
+		)
+		if err := rows.Scan(&id, &createTime, &aggregate, &key, &value); err != nil {
+			return err
+		}
+		if current == nil || current.Id != id {
+			if current != nil {
+				if err := sw.write(current); err != nil {
+					return err
+				}
+			}
+			current = &AuditEvent{
+				Id:            id,
+				CreateTime:    createTime,
+				AggregateName: aggregate,
+				Metadata:      map[string][]string{},
+			}
+		}
+		current.Metadata[key] = append(current.Metadata[key], value)
+	}
+	if current != nil {
+		return sw.write(current)
+	}
+	return nil
+}
+
+// SignManifest signs the sha256 digest of the manifest using the wrapper and
+// returns the marshaled signature.
+func SignManifest(ctx context.Context, wrapper wrapping.Wrapper, manifest []byte) ([]byte, error) {
+	const op = "compliance.SignManifest"
+	if wrapper == nil {
+		return nil, errors.New(errors.InvalidParameter, op, "nil wrapper")
+	}
+	if len(manifest) == 0 {
+		return nil, errors.New(errors.InvalidParameter, op, "missing manifest")
+	}
+	digest := sha256.Sum256(manifest)
+	blobInfo, err := wrapper.Encrypt(ctx, digest[:], []byte(manifestAad))
+	if err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to sign manifest"))
+	}
+	sig, err := proto.Marshal(blobInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to marshal signature"))
+	}
+	return sig, nil
+}
+
+// VerifyManifest verifies that sig is a signature of manifest produced by
+// SignManifest using the same key as wrapper.
+func VerifyManifest(ctx context.Context, wrapper wrapping.Wrapper, manifest, sig []byte) error {
+	const op = "compliance.VerifyManifest"
+	if wrapper == nil {
+		return errors.New(errors.InvalidParameter, op, "nil wrapper")
+	}
+	if len(manifest) == 0 {
+		return errors.New(errors.InvalidParameter, op, "missing manifest")
+	}
+	if len(sig) == 0 {
+		return errors.New(errors.InvalidParameter, op, "missing signature")
+	}
+	blobInfo := new(wrapping.EncryptedBlobInfo)
+	if err := proto.Unmarshal(sig, blobInfo); err != nil {
+		return errors.Wrap(err, op, errors.WithMsg("unable to unmarshal signature"))
+	}
+	signed, err := wrapper.Decrypt(ctx, blobInfo, []byte(manifestAad))
+	if err != nil {
+		return errors.Wrap(err, op, errors.WithMsg("unable to verify signature"))
+	}
+	digest := sha256.Sum256(manifest)
+	if subtle.ConstantTimeCompare(signed, digest[:]) != 1 {
+		return errors.New(errors.InvalidParameter, op, "manifest does not match signature")
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package compliance
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExporter(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+
+	t.Run("valid", func(t *testing.T) {
+		e, err := NewExporter(rw, wrapper)
+		require.NoError(t, err)
+		assert.NotNil(t, e)
+	})
+	t.Run("nil-reader", func(t *testing.T) {
+		e, err := NewExporter(nil, wrapper)
+		require.Error(t, err)
+		assert.Nil(t, e)
+	})
+	t.Run("nil-wrapper", func(t *testing.T) {
+		e, err := NewExporter(rw, nil)
+		require.Error(t, err)
+		assert.Nil(t, e)
+	})
+}
+
+func TestExporter_Export(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+
+	s := session.TestDefaultSession(t, conn, wrapper, iamRepo)
+	proj, err := iamRepo.LookupScope(ctx, s.ScopeId)
+	require.NoError(t, err)
+	orgId := proj.ParentId
+
+	u := iam.TestUser(t, iamRepo, orgId)
+	role := iam.TestRole(t, conn, orgId)
+	iam.TestRoleGrant(t, conn, role.PublicId, "id=*;actions=read")
+	iam.TestUserRole(t, conn, role.PublicId, u.PublicId)
+
+	e, err := NewExporter(rw, wrapper)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	manifest, err := e.Export(ctx, &buf, orgId, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, orgId, manifest.ScopeId)
+	assert.GreaterOrEqual(t, manifest.Counts["users.json"], 1)
+	assert.GreaterOrEqual(t, manifest.Counts["grants.json"], 1)
+	assert.GreaterOrEqual(t, manifest.Counts["principal_roles.json"], 1)
+	assert.Equal(t, 1, manifest.Counts["sessions.json"])
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = ioutil.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+	}
+	require.NoError(t, VerifyManifest(ctx, wrapper, files[ManifestFileName], files[SignatureFileName]))
+
+	var users []User
+	scanner := bufio.NewScanner(bytes.NewReader(files["users.json"]))
+	for scanner.Scan() {
+		var got User
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &got))
+		users = append(users, got)
+		// records are written in canonical form, so rewriting them
+		// doesn't change their digest
+		canonical, err := db.CanonicalJSON(got)
+		require.NoError(t, err)
+		assert.Equal(t, string(scanner.Bytes()), string(canonical))
+	}
+	var found bool
+	for _, got := range users {
+		if got.PublicId == u.PublicId {
+			found = true
+		}
+	}
+	assert.True(t, found)
+
+	var sessions []Session
+	scanner = bufio.NewScanner(bytes.NewReader(files["sessions.json"]))
+	for scanner.Scan() {
+		var got Session
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &got))
+		sessions = append(sessions, got)
+	}
+	require.Len(t, sessions, 1)
+	assert.Equal(t, s.PublicId, sessions[0].PublicId)
+
+	t.Run("outside-time-range", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		var buf bytes.Buffer
+		manifest, err := e.Export(ctx, &buf, orgId, time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour))
+		require.NoError(err)
+		assert.Equal(0, manifest.Counts["sessions.json"])
+		assert.Equal(0, manifest.Counts["audit_events.json"])
+	})
+	t.Run("invalid-time-range", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := e.Export(ctx, &buf, orgId, time.Now(), time.Now().Add(-time.Hour))
+		require.Error(t, err)
+	})
+	t.Run("missing-scope", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := e.Export(ctx, &buf, "", time.Now().Add(-time.Hour), time.Now())
+		require.Error(t, err)
+	})
+}
+
+func TestVerifyManifest(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	wrapper := db.TestWrapper(t)
+	manifest := []byte(`{"version":1}`)
+
+	sig, err := SignManifest(ctx, wrapper, manifest)
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, VerifyManifest(ctx, wrapper, manifest, sig))
+	})
+	t.Run("tampered-manifest", func(t *testing.T) {
+		assert.Error(t, VerifyManifest(ctx, wrapper, []byte(`{"version":2}`), sig))
+	})
+	t.Run("different-key", func(t *testing.T) {
+		assert.Error(t, VerifyManifest(ctx, db.TestWrapper(t), manifest, sig))
+	})
+	t.Run("missing-signature", func(t *testing.T) {
+		assert.Error(t, VerifyManifest(ctx, wrapper, manifest, nil))
+	})
+}
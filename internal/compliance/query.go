@@ -0,0 +1,116 @@
+package compliance
+
+const (
+	// scopeTreeCte selects the scope given by $1 and all of its descendants.
+	scopeTreeCte = `
+with recursive scope_tree(public_id) as (
+	select public_id
+	from iam_scope
+	where public_id = $1
+	union
+	select s.public_id
+	from iam_scope s
+	join scope_tree t on s.parent_id = t.public_id
+)
+`
+
+	exportUsersQuery = scopeTreeCte + `
+select
+	u.public_id,
+	u.scope_id,
+	coalesce(u.name, '') as name,
+	coalesce(u.description, '') as description,
+	u.create_time,
+	u.update_time
+from
+	iam_user u
+where
+	u.scope_id in (select public_id from scope_tree)
+order by u.public_id;
+`
+
+	exportGrantsQuery = scopeTreeCte + `
+select
+	r.public_id as role_id,
+	r.scope_id,
+	r.grant_scope_id,
+	coalesce(r.name, '') as role_name,
+	g.canonical_grant,
+	g.raw_grant,
+	g.create_time
+from
+	iam_role r
+join iam_role_grant g on g.role_id = r.public_id
+where
+	r.scope_id in (select public_id from scope_tree)
+order by r.public_id, g.canonical_grant;
+`
+
+	exportPrincipalRolesQuery = scopeTreeCte + `
+select
+	pr.role_id,
+	pr.role_scope_id,
+	pr.principal_id,
+	pr.principal_scope_id,
+	pr.type,
+	pr.create_time
+from
+	iam_principal_role pr
+where
+	pr.role_scope_id in (select public_id from scope_tree)
+order by pr.role_id, pr.principal_id;
+`
+
+	exportSessionsQuery = scopeTreeCte + `
+select
+	s.public_id,
+	coalesce(s.user_id, '') as user_id,
+	coalesce(s.target_id, '') as target_id,
+	coalesce(s.host_id, '') as host_id,
+	coalesce(s.host_set_id, '') as host_set_id,
+	coalesce(s.auth_token_id, '') as auth_token_id,
+	coalesce(s.scope_id, '') as scope_id,
+	coalesce(s.server_id, '') as server_id,
+	coalesce(s.endpoint, '') as endpoint,
+	coalesce(s.termination_reason, '') as termination_reason,
+	s.connection_limit,
+	s.create_time,
+	s.update_time,
+	(select count(*) from session_connection c where c.session_id = s.public_id) as connection_count,
+	(select coalesce(sum(c.bytes_up), 0) from session_connection c where c.session_id = s.public_id) as bytes_up,
+	(select coalesce(sum(c.bytes_down), 0) from session_connection c where c.session_id = s.public_id) as bytes_down
+from
+	session s
+where
+	s.scope_id in (select public_id from scope_tree) and
+	s.create_time >= $2 and
+	s.create_time < $3
+order by s.create_time, s.public_id;
+`
+
+	// exportAuditEventsQuery returns one row per oplog metadata entry, ordered
+	// by the oplog entry id so the rows for a single entry are contiguous and
+	// can be grouped while streaming.
+	exportAuditEventsQuery = scopeTreeCte + `
+select
+	e.id as entry_id,
+	e.create_time,
+	e.aggregate_name,
+	m.key,
+	coalesce(m.value, '') as value
+from
+	oplog_entry e
+join oplog_metadata m on m.entry_id = e.id
+where
+	e.create_time >= $2 and
+	e.create_time < $3 and
+	e.id in (
+		select entry_id
+		from oplog_metadata
+		where
+			key = 'scope-id' and
+			value in (select public_id from scope_tree)
+	)
+order by e.id, m.key, m.value;
+`
+)
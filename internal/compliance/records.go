@@ -0,0 +1,65 @@
+package compliance
+
+import "time"
+
+// User is an iam user as written to a compliance archive.
+type User struct {
+	PublicId    string    `json:"public_id"`
+	ScopeId     string    `json:"scope_id"`
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreateTime  time.Time `json:"create_time"`
+	UpdateTime  time.Time `json:"update_time"`
+}
+
+// Grant is a role grant as written to a compliance archive.
+type Grant struct {
+	RoleId         string    `json:"role_id"`
+	ScopeId        string    `json:"scope_id"`
+	GrantScopeId   string    `json:"grant_scope_id"`
+	RoleName       string    `json:"role_name,omitempty"`
+	CanonicalGrant string    `json:"canonical_grant"`
+	RawGrant       string    `json:"raw_grant"`
+	CreateTime     time.Time `json:"create_time"`
+}
+
+// PrincipalRole is the assignment of a user or group to a role as written to
+// a compliance archive.
+type PrincipalRole struct {
+	RoleId           string    `json:"role_id"`
+	RoleScopeId      string    `json:"role_scope_id"`
+	PrincipalId      string    `json:"principal_id"`
+	PrincipalScopeId string    `json:"principal_scope_id"`
+	Type             string    `json:"type"`
+	CreateTime       time.Time `json:"create_time"`
+}
+
+// Session is the metadata of a session as written to a compliance archive.
+// Session certificates and tokens are intentionally not included.
+type Session struct {
+	PublicId          string    `json:"public_id"`
+	UserId            string    `json:"user_id,omitempty"`
+	TargetId          string    `json:"target_id,omitempty"`
+	HostId            string    `json:"host_id,omitempty"`
+	HostSetId         string    `json:"host_set_id,omitempty"`
+	AuthTokenId       string    `json:"auth_token_id,omitempty"`
+	ScopeId           string    `json:"scope_id,omitempty"`
+	ServerId          string    `json:"server_id,omitempty"`
+	Endpoint          string    `json:"endpoint,omitempty"`
+	TerminationReason string    `json:"termination_reason,omitempty"`
+	ConnectionLimit   int32     `json:"connection_limit"`
+	CreateTime        time.Time `json:"create_time"`
+	UpdateTime        time.Time `json:"update_time"`
+	ConnectionCount   int64     `json:"connection_count"`
+	BytesUp           int64     `json:"bytes_up"`
+	BytesDown         int64     `json:"bytes_down"`
+}
+
+// AuditEvent is an oplog entry as written to a compliance archive. Only the
+// entry's metadata is exported; the encrypted entry data is not.
+type AuditEvent struct {
+	Id            uint64              `json:"id"`
+	CreateTime    time.Time           `json:"create_time"`
+	AggregateName string              `json:"aggregate_name"`
+	Metadata      map[string][]string `json:"metadata"`
+}
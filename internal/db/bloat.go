@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// BloatTables are the tables with the most churn, whose bloat the controllers
+// sample: sessions and their connections, the oplog and auth tokens.
+var BloatTables = []string{
+	"session",
+	"session_state",
+	"session_connection",
+	"session_connection_state",
+	"oplog_entry",
+	"auth_token",
+}
+
+const (
+	// bloatDeadTupleRatio is the ratio of dead tuples above which a table is
+	// reported as bloated, if it has at least bloatMinDeadTuples dead tuples.
+	bloatDeadTupleRatio = 0.2
+	bloatMinDeadTuples  = 10000
+
+	// bloatVacuumAge is how long a table with at least bloatMinDeadTuples
+	// dead tuples may go without being vacuumed before it is reported.
+	bloatVacuumAge = 24 * time.Hour
+
+	// bloatIndexRatio is how many times larger than their table indexes may
+	// grow before they are reported as bloated, once they reach
+	// bloatMinIndexBytes.
+	bloatIndexRatio    = 3
+	bloatMinIndexBytes = 64 << 20
+)
+
+const tableBloatQuery = `
+select relname,
+       n_live_tup,
+       n_dead_tup,
+       pg_table_size(relid),
+       pg_indexes_size(relid),
+       greatest(last_vacuum, last_autovacuum),
+       last_autovacuum,
+       autovacuum_count
+  from pg_stat_user_tables
+ where schemaname = current_schema()
+`
+
+// TableBloat is a sample of the size and vacuum statistics of a table, from
+// which its bloat is estimated.
+type TableBloat struct {
+	Table      string
+	LiveTuples int64
+	DeadTuples int64
+	// TableBytes is the size of the table, including its TOAST table.
+	TableBytes int64
+	// IndexBytes is the size of all indexes of the table.
+	IndexBytes int64
+	// LastVacuum is when the table was last vacuumed, manually or by
+	// autovacuum. It is zero if the table was never vacuumed.
+	LastVacuum time.Time
+	// LastAutovacuum is zero if autovacuum never vacuumed the table.
+	LastAutovacuum  time.Time
+	AutovacuumCount int64
+}
+
+// DeadTupleRatio returns the ratio of dead tuples among all tuples of the
+// table.
+func (b *TableBloat) DeadTupleRatio() float64 {
+	total := b.LiveTuples + b.DeadTuples
+	if total == 0 {
+		return 0
+	}
+	return float64(b.DeadTuples) / float64(total)
+}
+
+// Advisories returns descriptions of the problems the sample shows at the time
+// now, e.g. a table whose dead tuples autovacuum doesn't keep up with. It
+// returns none if the table looks healthy.
+func (b *TableBloat) Advisories(now time.Time) []string {
+	var ret []string
+	if b.DeadTuples >= bloatMinDeadTuples {
+		if ratio := b.DeadTupleRatio(); ratio > bloatDeadTupleRatio {
+			ret = append(ret, fmt.Sprintf("%.0f%% of the tuples of table %s are dead; autovacuum may not be keeping up, consider lowering its autovacuum_vacuum_scale_factor", ratio*100, b.Table))
+		}
+		switch {
+		case b.LastVacuum.IsZero():
+			ret = append(ret, fmt.Sprintf("table %s has %d dead tuples and was never vacuumed", b.Table, b.DeadTuples))
+		case now.Sub(b.LastVacuum) > bloatVacuumAge:
+			ret = append(ret, fmt.Sprintf("table %s has %d dead tuples and was last vacuumed %s ago; check for long running transactions blocking vacuum", b.Table, b.DeadTuples, now.Sub(b.LastVacuum).Truncate(time.Minute)))
+		}
+	}
+	if b.TableBytes > 0 && b.IndexBytes >= bloatMinIndexBytes && b.IndexBytes > bloatIndexRatio*b.TableBytes {
+		ret = append(ret, fmt.Sprintf("the indexes of table %s are %d times larger than the table; consider reindexing it", b.Table, b.IndexBytes/b.TableBytes))
+	}
+	return ret
+}
+
+// SampleTableBloat returns the size and vacuum statistics of the tables of the
+// current schema whose names are in tables, ordered as in tables. Tables which
+// don't exist are skipped.
+func SampleTableBloat(ctx context.Context, r Reader, tables []string) ([]*TableBloat, error) {
+	const op = "sample table bloat"
+	if r == nil {
+		return nil, fmt.Errorf("%s: missing reader: %w", op, errors.ErrInvalidParameter)
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("%s: missing tables: %w", op, errors.ErrInvalidParameter)
+	}
+	rows, err := r.Query(ctx, tableBloatQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+	byTable := make(map[string]*TableBloat, len(tables))
+	for _, t := range tables {
+		byTable[t] = nil
+	}
+	for rows.Next() {
+		b := new(TableBloat)
+		var lastVacuum, lastAutovacuum sql.NullTime
+		if err := rows.Scan(&b.Table, &b.LiveTuples, &b.DeadTuples, &b.TableBytes, &b.IndexBytes, &lastVacuum, &lastAutovacuum, &b.AutovacuumCount); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		if _, ok := byTable[b.Table]; !ok {
+			continue
+		}
+		b.LastVacuum = lastVacuum.Time
+		b.LastAutovacuum = lastAutovacuum.Time
+		byTable[b.Table] = b
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	ret := make([]*TableBloat, 0, len(tables))
+	for _, t := range tables {
+		if b := byTable[t]; b != nil {
+			ret = append(ret, b)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleTableBloat(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := TestSetup(t, "postgres")
+	rw := New(conn)
+	ctx := context.Background()
+
+	got, err := SampleTableBloat(ctx, rw, []string{"auth_token", "no_such_table", "session"})
+	require.NoError(err)
+	require.Len(got, 2)
+	assert.Equal("auth_token", got[0].Table)
+	assert.Equal("session", got[1].Table)
+	for _, b := range got {
+		assert.GreaterOrEqual(b.LiveTuples, int64(0))
+		assert.GreaterOrEqual(b.TableBytes, int64(0))
+		assert.Empty(b.Advisories(time.Now()))
+	}
+
+	_, err = SampleTableBloat(ctx, rw, nil)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	_, err = SampleTableBloat(ctx, nil, BloatTables)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+}
+
+func TestTableBloat_Advisories(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tests := []struct {
+		name  string
+		bloat TableBloat
+		want  int
+	}{
+		{
+			name:  "healthy",
+			bloat: TableBloat{Table: "session", LiveTuples: 100000, DeadTuples: 10000, TableBytes: 1 << 20, IndexBytes: 1 << 20, LastVacuum: now.Add(-time.Hour)},
+		},
+		{
+			name:  "few-dead-tuples",
+			bloat: TableBloat{Table: "session", LiveTuples: 100, DeadTuples: 900},
+		},
+		{
+			name:  "dead-tuple-ratio",
+			bloat: TableBloat{Table: "session", LiveTuples: 10000, DeadTuples: 90000, LastVacuum: now.Add(-time.Hour)},
+			want:  1,
+		},
+		{
+			name:  "never-vacuumed",
+			bloat: TableBloat{Table: "oplog_entry", LiveTuples: 1000000, DeadTuples: 20000},
+			want:  1,
+		},
+		{
+			name:  "vacuum-stale",
+			bloat: TableBloat{Table: "oplog_entry", LiveTuples: 10000, DeadTuples: 20000, LastVacuum: now.Add(-48 * time.Hour)},
+			want:  2,
+		},
+		{
+			name:  "index-bloat",
+			bloat: TableBloat{Table: "auth_token", TableBytes: 32 << 20, IndexBytes: 128 << 20},
+			want:  1,
+		},
+		{
+			name:  "small-indexes",
+			bloat: TableBloat{Table: "auth_token", TableBytes: 8 << 10, IndexBytes: 64 << 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, tt.bloat.Advisories(now), tt.want)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package db
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// canonicalTimeFormat is the format of timestamps in canonical JSON: UTC with
+// microseconds, rounded as postgres stores them, so a value hashes the same
+// before and after a round trip through the database.
+const canonicalTimeFormat = "2006-01-02T15:04:05.000000Z"
+
+// FieldOrder is the order of the fields of objects in canonical JSON.
+type FieldOrder int
+
+const (
+	// SortedFieldOrder sorts the fields of objects by name. It is the
+	// default.
+	SortedFieldOrder FieldOrder = iota
+
+	// DeclaredFieldOrder keeps the fields of objects in the order they are
+	// declared: the order of the fields of a struct or of a proto message in
+	// its .proto file. The keys of maps are always sorted.
+	DeclaredFieldOrder
+)
+
+// WithFieldOrder sets the order of the fields of objects in canonical JSON.
+func WithFieldOrder(order FieldOrder) Option {
+	return func(o *Options) {
+		o.withFieldOrder = order
+	}
+}
+
+// CanonicalJSON returns a stable serialization of v, suitable for computing
+// the hashes of audit records and ETags: the same value always serializes to
+// the same bytes. Proto messages are serialized with their proto field names
+// and other values as by encoding/json. The serialization has no insignificant
+// whitespace, fields ordered as set by WithFieldOrder, sorted by default, and
+// every string in RFC 3339 format normalized to UTC with microseconds.
+// Numbers are kept as they are serialized.
+func CanonicalJSON(v interface{}, opt ...Option) ([]byte, error) {
+	const op = "db.CanonicalJSON"
+	if v == nil {
+		return nil, errors.New(errors.InvalidParameter, op, "nil value")
+	}
+	opts := GetOpts(opt...)
+	var raw []byte
+	var err error
+	switch m := v.(type) {
+	case proto.Message:
+		raw, err = protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	default:
+		raw, err = json.Marshal(v)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to marshal value"))
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, dec, opts.withFieldOrder); err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+	return buf.Bytes(), nil
+}
+
+// CanonicalHash returns the hex encoded SHA-256 digest of the canonical JSON
+// of v.
+func CanonicalHash(v interface{}, opt ...Option) (string, error) {
+	const op = "db.CanonicalHash"
+	b, err := CanonicalJSON(v, opt...)
+	if err != nil {
+		return "", errors.Wrap(err, op)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalField is a field of an object, with its value already written in
+// canonical form.
+type canonicalField struct {
+	name  string
+	value []byte
+}
+
+// writeCanonical writes the next JSON value of dec to buf in canonical form.
+func writeCanonical(buf *bytes.Buffer, dec *json.Decoder, order FieldOrder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			var fields []canonicalField
+			for dec.More() {
+				nameTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				name, ok := nameTok.(string)
+				if !ok {
+					return fmt.Errorf("unexpected object key %v", nameTok)
+				}
+				var value bytes.Buffer
+				if err := writeCanonical(&value, dec, order); err != nil {
+					return err
+				}
+				fields = append(fields, canonicalField{name: name, value: value.Bytes()})
+			}
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+			if order == SortedFieldOrder {
+				sort.SliceStable(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+			}
+			buf.WriteByte('{')
+			for i, f := range fields {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				if err := writeCanonicalString(buf, f.name); err != nil {
+					return err
+				}
+				buf.WriteByte(':')
+				buf.Write(f.value)
+			}
+			buf.WriteByte('}')
+		case '[':
+			buf.WriteByte('[')
+			for i := 0; dec.More(); i++ {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				if err := writeCanonical(buf, dec, order); err != nil {
+					return err
+				}
+			}
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+			buf.WriteByte(']')
+		default:
+			return fmt.Errorf("unexpected delimiter %v", t)
+		}
+	case string:
+		if ts, err := time.Parse(time.RFC3339Nano, t); err == nil {
+			t = ts.Round(time.Microsecond).UTC().Format(canonicalTimeFormat)
+		}
+		return writeCanonicalString(buf, t)
+	case json.Number:
+		buf.WriteString(t.String())
+	case bool:
+		if t {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case nil:
+		buf.WriteString("null")
+	default:
+		return fmt.Errorf("unexpected token %v", t)
+	}
+	return nil
+}
+
+// writeCanonicalString writes s as a JSON string without escaping HTML
+// characters, which encoding/json escapes by default.
+func writeCanonicalString(buf *bytes.Buffer, s string) error {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(b.Bytes(), []byte("\n")))
+	return nil
+}
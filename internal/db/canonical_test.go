@@ -0,0 +1,110 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db/db_test"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	type record struct {
+		Zeta     string            `json:"zeta"`
+		Alpha    int               `json:"alpha"`
+		Time     time.Time         `json:"time"`
+		Metadata map[string]string `json:"metadata"`
+		Html     string            `json:"html"`
+	}
+	at := time.Date(2021, 3, 4, 5, 6, 7, 123456789, time.UTC)
+	zone := time.FixedZone("test", -5*60*60)
+	r := record{
+		Zeta:     "last",
+		Alpha:    1,
+		Time:     at,
+		Metadata: map[string]string{"b": "2", "a": "1"},
+		Html:     "<a&b>",
+	}
+	want := `{"alpha":1,"html":"<a&b>","metadata":{"a":"1","b":"2"},"time":"2021-03-04T05:06:07.123457Z","zeta":"last"}`
+
+	tests := []struct {
+		name string
+		v    interface{}
+		opt  []Option
+		want string
+	}{
+		{
+			name: "sorted",
+			v:    r,
+			want: want,
+		},
+		{
+			name: "other-time-zone",
+			v: func() record {
+				r := r
+				r.Time = at.In(zone)
+				return r
+			}(),
+			want: want,
+		},
+		{
+			name: "declared",
+			v:    r,
+			opt:  []Option{WithFieldOrder(DeclaredFieldOrder)},
+			want: `{"zeta":"last","alpha":1,"time":"2021-03-04T05:06:07.123457Z","metadata":{"a":"1","b":"2"},"html":"<a&b>"}`,
+		},
+		{
+			name: "nested",
+			v:    []interface{}{map[string]interface{}{"y": []int{2, 1}, "x": nil}, true},
+			want: `[{"x":null,"y":[2,1]},true]`,
+		},
+		{
+			name: "proto",
+			v: &db_test.StoreTestUser{
+				PublicId:   "u_1234567890",
+				Name:       "alice",
+				CreateTime: &timestamp.Timestamp{Timestamp: timestamppb.New(at)},
+			},
+			want: `{"create_time":{"timestamp":"2021-03-04T05:06:07.123457Z"},"name":"alice","public_id":"u_1234567890"}`,
+		},
+		{
+			name: "proto-declared",
+			v: &db_test.StoreTestUser{
+				PublicId:   "u_1234567890",
+				Name:       "alice",
+				CreateTime: &timestamp.Timestamp{Timestamp: timestamppb.New(at)},
+			},
+			opt:  []Option{WithFieldOrder(DeclaredFieldOrder)},
+			want: `{"create_time":{"timestamp":"2021-03-04T05:06:07.123457Z"},"public_id":"u_1234567890","name":"alice"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalJSON(tt.v, tt.opt...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		_, err := CanonicalJSON(nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestCanonicalHash(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	at := time.Now()
+	h1, err := CanonicalHash(map[string]interface{}{"id": "1", "time": at})
+	require.NoError(err)
+	assert.Len(h1, 64)
+	h2, err := CanonicalHash(map[string]interface{}{"time": at.In(time.FixedZone("test", 60*60)), "id": "1"})
+	require.NoError(err)
+	assert.Equal(h1, h2)
+	h3, err := CanonicalHash(map[string]interface{}{"id": "2", "time": at})
+	require.NoError(err)
+	assert.NotEqual(h1, h3)
+}
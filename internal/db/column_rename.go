@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// DefaultBackfillBatchSize is the number of rows BackfillColumnRenames copies
+// in each statement when no batch size is provided.
+const DefaultBackfillBatchSize = 1000
+
+// ColumnRename is a column of a table being renamed without a maintenance
+// window. A migration adds NewColumn and calls expand_column_rename, which
+// makes the database copy the writes of either column to the other, so
+// controllers of the previous release keep using Column while those of the
+// new release use NewColumn. The rows written before the expansion are
+// backfilled by BackfillColumnRenames, and once ColumnRenameStatus reports
+// none left a migration of a later release calls contract_column_rename,
+// which drops Column, and the rename is removed from ColumnRenames. See the
+// column_rename migration for an example.
+type ColumnRename struct {
+	Table     string
+	Column    string
+	NewColumn string
+	// KeyColumn is the column of the primary key of the table, by which rows
+	// are backfilled in batches
+	KeyColumn string
+}
+
+// ColumnRenames are the column renames expanded but not contracted yet, which
+// the controllers backfill. There are none at the moment.
+var ColumnRenames = []ColumnRename{}
+
+func (r ColumnRename) validate() error {
+	if r.Table == "" || r.Column == "" || r.NewColumn == "" || r.KeyColumn == "" {
+		return fmt.Errorf("incomplete column rename of table %q: %w", r.Table, errors.ErrInvalidParameter)
+	}
+	return nil
+}
+
+// pendingCondition returns the condition of the rows whose new column is not
+// backfilled.
+func (r ColumnRename) pendingCondition() string {
+	return fmt.Sprintf("%s is not null and %s is null", r.Column, r.NewColumn)
+}
+
+// BackfillColumnRenames copies the old column of the rows of the renames to
+// their new column, batchSize rows per statement so that no statement locks
+// many rows, returning the number of rows changed. Rows locked by others,
+// such as another controller backfilling, are skipped and backfilled later.
+// Backfilled rows are updated, so their update time and version change.
+func BackfillColumnRenames(ctx context.Context, w Writer, renames []ColumnRename, batchSize int) (int, error) {
+	const op = "backfill column renames"
+	if w == nil {
+		return NoRowsAffected, fmt.Errorf("%s: missing writer: %w", op, errors.ErrInvalidParameter)
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBackfillBatchSize
+	}
+	var backfilled int
+	for _, r := range renames {
+		if err := r.validate(); err != nil {
+			return backfilled, fmt.Errorf("%s: %w", op, err)
+		}
+		q := fmt.Sprintf(
+			"update %[1]s set %[3]s = %[2]s where %[4]s in (select %[4]s from %[1]s where %[5]s limit ? for update skip locked)",
+			r.Table, r.Column, r.NewColumn, r.KeyColumn, r.pendingCondition())
+		for {
+			if err := ctx.Err(); err != nil {
+				return backfilled, fmt.Errorf("%s: %w", op, err)
+			}
+			n, err := w.Exec(ctx, q, []interface{}{batchSize})
+			if err != nil {
+				return backfilled, fmt.Errorf("%s: %s.%s: %w", op, r.Table, r.NewColumn, err)
+			}
+			backfilled += n
+			if n < batchSize {
+				break
+			}
+		}
+	}
+	return backfilled, nil
+}
+
+// ColumnRenameStatus returns the number of rows of the rename whose new column
+// is not backfilled yet. The rename can be contracted once it is zero.
+func ColumnRenameStatus(ctx context.Context, r Reader, rename ColumnRename) (int, error) {
+	const op = "column rename status"
+	if r == nil {
+		return 0, fmt.Errorf("%s: missing reader: %w", op, errors.ErrInvalidParameter)
+	}
+	if err := rename.validate(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	rows, err := r.Query(ctx, fmt.Sprintf("select count(*) from %s where %s", rename.Table, rename.pendingCondition()), nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s.%s: %w", op, rename.Table, rename.NewColumn, err)
+	}
+	defer rows.Close()
+	var pending int
+	if rows.Next() {
+		if err := rows.Scan(&pending); err != nil {
+			return 0, fmt.Errorf("%s: %s.%s: %w", op, rename.Table, rename.NewColumn, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s: %s.%s: %w", op, rename.Table, rename.NewColumn, err)
+	}
+	return pending, nil
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnRename(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := TestSetup(t, "postgres")
+	rw := New(conn)
+	ctx := context.Background()
+
+	exec := func(q string, args ...interface{}) {
+		t.Helper()
+		_, err := rw.Exec(ctx, q, args)
+		require.NoError(err)
+	}
+	columns := func(id int) (description, summary sql.NullString) {
+		t.Helper()
+		rows, err := rw.Query(ctx, "select description, summary from db_test_rename where id = ?", []interface{}{id})
+		require.NoError(err)
+		defer rows.Close()
+		require.True(rows.Next())
+		require.NoError(rows.Scan(&description, &summary))
+		return description, summary
+	}
+
+	exec(`create table db_test_rename (id integer primary key, description text)`)
+	exec(`insert into db_test_rename (id, description) values (1, 'one'), (2, 'two'), (3, null)`)
+
+	// expand
+	exec(`alter table db_test_rename add column summary text`)
+	exec(`select expand_column_rename('db_test_rename', 'description', 'summary')`)
+	rename := ColumnRename{
+		Table:     "db_test_rename",
+		Column:    "description",
+		NewColumn: "summary",
+		KeyColumn: "id",
+	}
+
+	// writes of the previous release
+	exec(`insert into db_test_rename (id, description) values (4, 'four')`)
+	d, s := columns(4)
+	assert.Equal("four", d.String)
+	assert.Equal("four", s.String)
+	exec(`update db_test_rename set description = 'updated four' where id = 4`)
+	d, s = columns(4)
+	assert.Equal("updated four", d.String)
+	assert.Equal("updated four", s.String)
+
+	// writes of the new release
+	exec(`insert into db_test_rename (id, summary) values (5, 'five')`)
+	d, s = columns(5)
+	assert.Equal("five", d.String)
+	assert.Equal("five", s.String)
+	exec(`update db_test_rename set summary = null where id = 5`)
+	d, s = columns(5)
+	assert.False(d.Valid)
+	assert.False(s.Valid)
+
+	// backfill
+	pending, err := ColumnRenameStatus(ctx, rw, rename)
+	require.NoError(err)
+	assert.Equal(2, pending)
+	_, err = rw.Exec(ctx, `select contract_column_rename('db_test_rename', 'description', 'summary')`, nil)
+	assert.Error(err)
+
+	n, err := BackfillColumnRenames(ctx, rw, []ColumnRename{rename}, 1)
+	require.NoError(err)
+	assert.Equal(2, n)
+	_, s = columns(2)
+	assert.Equal("two", s.String)
+	_, s = columns(3)
+	assert.False(s.Valid)
+	pending, err = ColumnRenameStatus(ctx, rw, rename)
+	require.NoError(err)
+	assert.Equal(0, pending)
+	n, err = BackfillColumnRenames(ctx, rw, []ColumnRename{rename}, 0)
+	require.NoError(err)
+	assert.Equal(0, n)
+
+	// contract
+	exec(`select contract_column_rename('db_test_rename', 'description', 'summary')`)
+	exec(`insert into db_test_rename (id, summary) values (6, 'six')`)
+	_, err = rw.Exec(ctx, `select description from db_test_rename`, nil)
+	assert.Error(err)
+
+	_, err = BackfillColumnRenames(ctx, nil, []ColumnRename{rename}, 0)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	_, err = BackfillColumnRenames(ctx, rw, []ColumnRename{{Table: "db_test_rename"}}, 0)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	_, err = ColumnRenameStatus(ctx, nil, rename)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+}
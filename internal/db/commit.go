@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/boundary/internal/types/resource"
+)
+
+// A Change is a write to a resource which commit listeners are notified of
+// once it is committed.
+type Change struct {
+	ResourceType resource.Type
+	Op           OpType
+	Id           string
+}
+
+// A CommitListener is called with the changes of a transaction after it is
+// committed, in the order they were recorded. It is called synchronously by
+// the writer which committed them, so it must not block.
+type CommitListener func(ctx context.Context, changes []Change)
+
+type commitListener struct {
+	fn       CommitListener
+	resTypes map[resource.Type]bool
+}
+
+var (
+	commitListenersLock sync.RWMutex
+	commitListeners     = make(map[*commitListener]struct{})
+)
+
+// RegisterCommitListener registers the listener to be notified of the
+// committed changes to resources of the types, or of all types if none are
+// given, and returns a func to unregister it. Listeners are notified exactly
+// once per committed change and never of changes which are rolled back,
+// including those of attempts of a DoTx which are retried.
+func RegisterCommitListener(l CommitListener, resTypes ...resource.Type) func() {
+	cl := &commitListener{fn: l}
+	if len(resTypes) > 0 {
+		cl.resTypes = make(map[resource.Type]bool, len(resTypes))
+		for _, t := range resTypes {
+			cl.resTypes[t] = true
+		}
+	}
+	commitListenersLock.Lock()
+	commitListeners[cl] = struct{}{}
+	commitListenersLock.Unlock()
+	return func() {
+		commitListenersLock.Lock()
+		delete(commitListeners, cl)
+		commitListenersLock.Unlock()
+	}
+}
+
+// notifyCommitListeners notifies the registered listeners of the committed
+// changes they are interested in.
+func notifyCommitListeners(ctx context.Context, changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+	commitListenersLock.RLock()
+	listeners := make([]*commitListener, 0, len(commitListeners))
+	for cl := range commitListeners {
+		listeners = append(listeners, cl)
+	}
+	commitListenersLock.RUnlock()
+	for _, cl := range listeners {
+		if cl.resTypes == nil {
+			cl.fn(ctx, changes)
+			continue
+		}
+		var matched []Change
+		for _, c := range changes {
+			if cl.resTypes[c.ResourceType] {
+				matched = append(matched, c)
+			}
+		}
+		if len(matched) > 0 {
+			cl.fn(ctx, matched)
+		}
+	}
+}
+
+// NotifyOnCommit records the changes to notify the commit listeners of. Within
+// a DoTx they are notified after the transaction commits; otherwise every
+// write is committed on its own, so they are notified immediately.
+func (rw *Db) NotifyOnCommit(ctx context.Context, changes ...Change) {
+	if rw.pendingChanges != nil {
+		*rw.pendingChanges = append(*rw.pendingChanges, changes...)
+		return
+	}
+	notifyCommitListeners(ctx, changes)
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDb_NotifyOnCommit(t *testing.T) {
+	t.Parallel()
+	conn, _ := TestSetup(t, "postgres")
+	ctx := context.Background()
+
+	var lock sync.Mutex
+	var got []Change
+	unregister := RegisterCommitListener(func(_ context.Context, changes []Change) {
+		lock.Lock()
+		defer lock.Unlock()
+		got = append(got, changes...)
+	}, resource.Scope)
+	defer unregister()
+	take := func() []Change {
+		lock.Lock()
+		defer lock.Unlock()
+		ret := got
+		got = nil
+		return ret
+	}
+
+	t.Run("committed-once", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		w := &Db{underlying: conn}
+		attempts := 0
+		_, err := w.DoTx(ctx, 2, ConstBackoff{}, func(_ Reader, w Writer) error {
+			attempts++
+			w.NotifyOnCommit(ctx, Change{ResourceType: resource.Scope, Op: CreateOp, Id: "o_1"})
+			if attempts < 2 {
+				return oplog.ErrTicketAlreadyRedeemed
+			}
+			// Changes to other types are not notified to the listener
+			w.NotifyOnCommit(ctx, Change{ResourceType: resource.Target, Op: UpdateOp, Id: "ttcp_1"})
+			assert.Empty(take())
+			return nil
+		})
+		require.NoError(err)
+		assert.Equal([]Change{{ResourceType: resource.Scope, Op: CreateOp, Id: "o_1"}}, take())
+	})
+	t.Run("rolled-back", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		w := &Db{underlying: conn}
+		_, err := w.DoTx(ctx, 2, ConstBackoff{}, func(_ Reader, w Writer) error {
+			w.NotifyOnCommit(ctx, Change{ResourceType: resource.Scope, Op: DeleteOp, Id: "o_2"})
+			return stderrors.New("rollback")
+		})
+		require.Error(err)
+		assert.Empty(take())
+	})
+	t.Run("no-transaction", func(t *testing.T) {
+		assert := assert.New(t)
+		w := New(conn)
+		w.NotifyOnCommit(ctx, Change{ResourceType: resource.Scope, Op: UpdateOp, Id: "o_3"})
+		assert.Equal([]Change{{ResourceType: resource.Scope, Op: UpdateOp, Id: "o_3"}}, take())
+	})
+	t.Run("unregistered", func(t *testing.T) {
+		assert := assert.New(t)
+		unregister()
+		New(conn).NotifyOnCommit(ctx, Change{ResourceType: resource.Scope, Op: UpdateOp, Id: "o_4"})
+		assert.Empty(take())
+	})
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db/db_test"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type copyTestUser struct {
+	*db_test.TestUser
+}
+
+func (u *copyTestUser) CopyColumns() []string {
+	return []string{"public_id", "name"}
+}
+
+func (u *copyTestUser) CopyValues() ([]interface{}, error) {
+	return []interface{}{u.PublicId, u.Name}, nil
+}
+
+func TestDb_CopyFrom(t *testing.T) {
+	t.Parallel()
+	conn, _ := TestSetup(t, "postgres")
+	rw := New(conn)
+	ctx := context.Background()
+
+	testUsers := func(t *testing.T, cnt int) ([]Copyable, []string) {
+		t.Helper()
+		var resources []Copyable
+		var ids []string
+		for i := 0; i < cnt; i++ {
+			u, err := db_test.NewTestUser()
+			require.NoError(t, err)
+			u.Name = "copy-" + u.PublicId
+			resources = append(resources, &copyTestUser{TestUser: u})
+			ids = append(ids, u.PublicId)
+		}
+		return resources, ids
+	}
+
+	t.Run("copy", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		resources, ids := testUsers(t, 100)
+		var copied int
+		_, err := rw.DoTx(ctx, StdRetryCnt, ExpBackoff{}, func(_ Reader, w Writer) error {
+			var err error
+			copied, err = w.CopyFrom(ctx, resources)
+			return err
+		})
+		require.NoError(err)
+		assert.Equal(100, copied)
+
+		var found []*db_test.TestUser
+		require.NoError(rw.SearchWhere(ctx, &found, "public_id in (?)", []interface{}{ids}))
+		assert.Len(found, 100)
+	})
+	t.Run("with-oplog", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		resources, _ := testUsers(t, 3)
+		oplogId := testId(t)
+		_, err := rw.DoTx(ctx, StdRetryCnt, ExpBackoff{}, func(_ Reader, w Writer) error {
+			_, err := w.CopyFrom(ctx, resources, WithOplog(TestWrapper(t), oplog.Metadata{
+				"resource-public-id": []string{oplogId},
+				"op-type":            []string{oplog.OpType_OP_TYPE_CREATE.String()},
+			}))
+			return err
+		})
+		require.NoError(err)
+		assert.NoError(TestVerifyOplog(t, rw, oplogId, WithOperation(oplog.OpType_OP_TYPE_CREATE)))
+	})
+	t.Run("rolled-back", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		resources, ids := testUsers(t, 2)
+		// the duplicate public id fails the copy, rolling back all rows
+		resources = append(resources, resources[0])
+		_, err := rw.DoTx(ctx, 0, ExpBackoff{}, func(_ Reader, w Writer) error {
+			_, err := w.CopyFrom(ctx, resources)
+			return err
+		})
+		require.Error(err)
+		var found []*db_test.TestUser
+		require.NoError(rw.SearchWhere(ctx, &found, "public_id in (?)", []interface{}{ids}))
+		assert.Empty(found)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert := assert.New(t)
+		resources, _ := testUsers(t, 1)
+		_, err := rw.CopyFrom(ctx, resources)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter), "outside of a transaction")
+		_, err = rw.DoTx(ctx, 0, ExpBackoff{}, func(_ Reader, w Writer) error {
+			_, err := w.CopyFrom(ctx, nil)
+			return err
+		})
+		assert.True(errors.Is(err, errors.ErrInvalidParameter), "no resources")
+	})
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -63,6 +64,12 @@ func Migrate(connectionUrl string, migrationsDirectory string) error {
 
 // InitStore will execute the migrations needed to initialize the store. It
 // returns true if migrations actually ran; false if we were already current.
+// Each data migration registered for the dialect runs right after the sql
+// migration of its version. Before migrating, the sql migrations already
+// applied are verified against their recorded checksums and an error wrapping
+// ErrMigrationChecksumMismatch is returned if any differ, and the schema is
+// verified against its recorded fingerprint and an error wrapping
+// ErrSchemaDrift is returned if it differs.
 func InitStore(dialect string, cleanup func() error, url string) (bool, error) {
 	var mErr *multierror.Error
 	// run migrations
@@ -87,10 +94,20 @@ func InitStore(dialect string, cleanup func() error, url string) (bool, error) {
 		return false, mErr.ErrorOrNil()
 
 	}
-	if err := m.Up(); err != nil {
-		if err == migrate.ErrNoChange {
-			return false, nil
+	defer m.Close()
+	conn, err := gorm.Open(dialect, url)
+	if err != nil {
+		mErr = multierror.Append(mErr, fmt.Errorf("error opening database for migrations: %w", err))
+		if cleanup != nil {
+			if err := cleanup(); err != nil {
+				mErr = multierror.Append(mErr, fmt.Errorf("error cleaning up from opening database: %w", err))
+			}
 		}
+		return false, mErr.ErrorOrNil()
+	}
+	defer conn.Close()
+	ran, err := runMigrations(context.Background(), dialect, m, conn)
+	if err != nil {
 		mErr = multierror.Append(mErr, fmt.Errorf("error running migrations: %w", err))
 		if cleanup != nil {
 			if err := cleanup(); err != nil {
@@ -99,7 +116,7 @@ func InitStore(dialect string, cleanup func() error, url string) (bool, error) {
 		}
 		return false, mErr.ErrorOrNil()
 	}
-	return true, mErr.ErrorOrNil()
+	return ran, mErr.ErrorOrNil()
 }
 
 func GetGormLogFormatter(log hclog.Logger) func(values ...interface{}) (messages []interface{}) {
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/go-kms-wrapping/structwrapping"
+)
+
+// FieldWrapperFunc returns the wrapper used to encrypt and decrypt the
+// "wrapping" tagged fields of resources in a scope. If keyId is not empty the
+// wrapper for that key version must be returned.
+type FieldWrapperFunc func(ctx context.Context, scopeId, keyId string) (wrapping.Wrapper, error)
+
+// FieldEncrypter is implemented by resources with fields that are encrypted
+// with the database key of their scope using "wrapping" struct tags, so the
+// fields can be encrypted and decrypted by Create, Update and LookupById when
+// the WithFieldWrapper option is used, or by EncryptFields and DecryptFields
+// for resources written and read with Exec and Query. A plaintext field is
+// tagged `wrapping:"pt,<name>"` and its ciphertext field is tagged
+// `wrapping:"ct,<name>"`.
+//
+// KMS key versions and oplog entries are not FieldEncrypters: they are
+// encrypted with the key above them in the KMS hierarchy and with the oplog
+// key of their scope, not with a scope's database key.
+type FieldEncrypter interface {
+	// GetScopeId returns the scope whose wrapper encrypts the resource.
+	GetScopeId() string
+	// GetKeyId returns the id of the key the resource was encrypted with.
+	GetKeyId() string
+	// SetKeyId records the id of the key the resource was encrypted with.
+	SetKeyId(string)
+	// WrappedFields returns the struct with the tagged fields. It is required
+	// since structwrapping doesn't support embedded structs.
+	WrappedFields() interface{}
+}
+
+// EncryptFields encrypts the tagged fields of fe with the wrapper fn returns
+// for its scope and records the id of the key used.
+func EncryptFields(ctx context.Context, fe FieldEncrypter, fn FieldWrapperFunc) error {
+	if fe == nil {
+		return fmt.Errorf("encrypt fields: missing resource: %w", errors.ErrInvalidParameter)
+	}
+	if fn == nil {
+		return fmt.Errorf("encrypt fields: missing field wrapper: %w", errors.ErrInvalidParameter)
+	}
+	w, err := fn(ctx, fe.GetScopeId(), "")
+	if err != nil {
+		return fmt.Errorf("unable to get field wrapper: %w", err)
+	}
+	if err := structwrapping.WrapStruct(ctx, w, fe.WrappedFields(), nil); err != nil {
+		return fmt.Errorf("unable to encrypt fields: %w", err)
+	}
+	fe.SetKeyId(w.KeyID())
+	return nil
+}
+
+// DecryptFields decrypts the tagged fields of fe with the wrapper fn returns
+// for its scope and key id. It does nothing if fe has no encrypted data.
+func DecryptFields(ctx context.Context, fe FieldEncrypter, fn FieldWrapperFunc) error {
+	if fe == nil {
+		return fmt.Errorf("decrypt fields: missing resource: %w", errors.ErrInvalidParameter)
+	}
+	if fn == nil {
+		return fmt.Errorf("decrypt fields: missing field wrapper: %w", errors.ErrInvalidParameter)
+	}
+	if !hasCiphertext(fe.WrappedFields()) {
+		return nil
+	}
+	w, err := fn(ctx, fe.GetScopeId(), fe.GetKeyId())
+	if err != nil {
+		return fmt.Errorf("unable to get field wrapper: %w", err)
+	}
+	if err := structwrapping.UnwrapStruct(ctx, w, fe.WrappedFields(), nil); err != nil {
+		return fmt.Errorf("unable to decrypt fields: %w", err)
+	}
+	return nil
+}
+
+// encryptFields encrypts the tagged fields of i and records the key id used if
+// i is a FieldEncrypter and a field wrapper was provided.
+func encryptFields(ctx context.Context, i interface{}, opts Options) error {
+	fe, ok := i.(FieldEncrypter)
+	if !ok || opts.withFieldWrapper == nil {
+		return nil
+	}
+	return EncryptFields(ctx, fe, opts.withFieldWrapper)
+}
+
+// decryptFields decrypts the tagged fields of i if i is a FieldEncrypter with
+// encrypted data and a field wrapper was provided.
+func decryptFields(ctx context.Context, i interface{}, opts Options) error {
+	fe, ok := i.(FieldEncrypter)
+	if !ok || opts.withFieldWrapper == nil {
+		return nil
+	}
+	return DecryptFields(ctx, fe, opts.withFieldWrapper)
+}
+
+// encryptedFieldPaths replaces plaintext field names in the update paths with
+// the names of their ciphertext fields. If any path names a plaintext field
+// the returned paths also include KeyId, so the key used is always recorded.
+func encryptedFieldPaths(i interface{}, fieldMaskPaths, setToNullPaths []string) ([]string, []string, bool, error) {
+	fe, ok := i.(FieldEncrypter)
+	if !ok {
+		return fieldMaskPaths, setToNullPaths, false, nil
+	}
+	ptToCt, err := wrappedFieldNames(fe.WrappedFields())
+	if err != nil {
+		return nil, nil, false, err
+	}
+	var found bool
+	mapPaths := func(paths []string) []string {
+		mapped := make([]string, 0, len(paths))
+		for _, p := range paths {
+			if ct, ok := ptToCt[strings.ToLower(p)]; ok {
+				found = true
+				p = ct
+			}
+			mapped = append(mapped, p)
+		}
+		return mapped
+	}
+	fieldMaskPaths, setToNullPaths = mapPaths(fieldMaskPaths), mapPaths(setToNullPaths)
+	if found && !contains(fieldMaskPaths, "KeyId") {
+		fieldMaskPaths = append(fieldMaskPaths, "KeyId")
+	}
+	return fieldMaskPaths, setToNullPaths, found, nil
+}
+
+// wrappedFieldNames returns a map of the lower cased names of the plaintext
+// fields of the struct to the names of their ciphertext fields.
+func wrappedFieldNames(s interface{}) (map[string]string, error) {
+	t := reflect.TypeOf(s)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("wrapped fields are not a struct: %w", errors.ErrInvalidParameter)
+	}
+	pt, ct := map[string]string{}, map[string]string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("wrapping"), ",")
+		if len(tag) != 2 {
+			continue
+		}
+		switch tag[0] {
+		case "pt":
+			pt[tag[1]] = f.Name
+		case "ct":
+			ct[tag[1]] = f.Name
+		}
+	}
+	ptToCt := make(map[string]string, len(pt))
+	for name, ptField := range pt {
+		ctField, ok := ct[name]
+		if !ok {
+			return nil, fmt.Errorf("no ciphertext field for wrapped field %s: %w", ptField, errors.ErrInvalidParameter)
+		}
+		ptToCt[strings.ToLower(ptField)] = ctField
+	}
+	return ptToCt, nil
+}
+
+// hasCiphertext returns true if any ciphertext field of the struct is set.
+func hasCiphertext(s interface{}) bool {
+	v := reflect.ValueOf(s)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := v.Field(i)
+		if strings.HasPrefix(t.Field(i).Tag.Get("wrapping"), "ct,") && f.Kind() == reflect.Slice && f.Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEncryptedResource struct {
+	ScopeId  string
+	KeyId    string
+	Name     string
+	CtSecret []byte `wrapping:"ct,secret"`
+	Secret   []byte `wrapping:"pt,secret"`
+}
+
+func (r *testEncryptedResource) GetScopeId() string         { return r.ScopeId }
+func (r *testEncryptedResource) GetKeyId() string           { return r.KeyId }
+func (r *testEncryptedResource) SetKeyId(keyId string)      { r.KeyId = keyId }
+func (r *testEncryptedResource) WrappedFields() interface{} { return r }
+
+func Test_encryptDecryptFields(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	wrapper := TestWrapper(t)
+	var gotScopeId, gotKeyId string
+	opts := GetOpts(WithFieldWrapper(func(_ context.Context, scopeId, keyId string) (wrapping.Wrapper, error) {
+		gotScopeId, gotKeyId = scopeId, keyId
+		return wrapper, nil
+	}))
+
+	t.Run("round-trip", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		r := &testEncryptedResource{ScopeId: "global", Secret: []byte("secret")}
+		require.NoError(encryptFields(ctx, r, opts))
+		assert.Equal("global", gotScopeId)
+		assert.Empty(gotKeyId)
+		assert.NotEmpty(r.CtSecret)
+		assert.Equal(wrapper.KeyID(), r.KeyId)
+
+		found := &testEncryptedResource{ScopeId: r.ScopeId, KeyId: r.KeyId, CtSecret: r.CtSecret}
+		require.NoError(decryptFields(ctx, found, opts))
+		assert.Equal(r.KeyId, gotKeyId)
+		assert.Equal([]byte("secret"), found.Secret)
+	})
+	t.Run("no-ciphertext", func(t *testing.T) {
+		r := &testEncryptedResource{ScopeId: "global"}
+		require.NoError(t, decryptFields(ctx, r, opts))
+		assert.Empty(t, r.Secret)
+	})
+	t.Run("no-wrapper-option", func(t *testing.T) {
+		r := &testEncryptedResource{ScopeId: "global", Secret: []byte("secret")}
+		require.NoError(t, encryptFields(ctx, r, GetOpts()))
+		assert.Empty(t, r.CtSecret)
+		assert.Empty(t, r.KeyId)
+	})
+	t.Run("wrapper-error", func(t *testing.T) {
+		errOpts := GetOpts(WithFieldWrapper(func(context.Context, string, string) (wrapping.Wrapper, error) {
+			return nil, stderrors.New("no wrapper")
+		}))
+		r := &testEncryptedResource{ScopeId: "global", Secret: []byte("secret")}
+		require.Error(t, encryptFields(ctx, r, errOpts))
+		r.CtSecret = []byte("ct")
+		require.Error(t, decryptFields(ctx, r, errOpts))
+	})
+	t.Run("missing-args", func(t *testing.T) {
+		assert := assert.New(t)
+		r := &testEncryptedResource{ScopeId: "global", Secret: []byte("secret")}
+		assert.Error(EncryptFields(ctx, nil, opts.withFieldWrapper))
+		assert.Error(EncryptFields(ctx, r, nil))
+		assert.Error(DecryptFields(ctx, nil, opts.withFieldWrapper))
+		assert.Error(DecryptFields(ctx, r, nil))
+	})
+}
+
+func Test_encryptedFieldPaths(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		resource      interface{}
+		fieldMask     []string
+		nullPaths     []string
+		wantFieldMask []string
+		wantNullPaths []string
+		wantFound     bool
+	}{
+		{
+			name:          "plaintext-field",
+			resource:      &testEncryptedResource{},
+			fieldMask:     []string{"Name", "secret"},
+			wantFieldMask: []string{"Name", "CtSecret", "KeyId"},
+			wantNullPaths: []string{},
+			wantFound:     true,
+		},
+		{
+			name:          "null-plaintext-field",
+			resource:      &testEncryptedResource{},
+			fieldMask:     []string{"Name"},
+			nullPaths:     []string{"Secret"},
+			wantFieldMask: []string{"Name", "KeyId"},
+			wantNullPaths: []string{"CtSecret"},
+			wantFound:     true,
+		},
+		{
+			name:          "no-plaintext-field",
+			resource:      &testEncryptedResource{},
+			fieldMask:     []string{"Name"},
+			wantFieldMask: []string{"Name"},
+			wantNullPaths: []string{},
+		},
+		{
+			name:          "not-encrypter",
+			resource:      &struct{ Name string }{},
+			fieldMask:     []string{"Name"},
+			wantFieldMask: []string{"Name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			gotFieldMask, gotNullPaths, gotFound, err := encryptedFieldPaths(tt.resource, tt.fieldMask, tt.nullPaths)
+			require.NoError(err)
+			assert.Equal(tt.wantFieldMask, gotFieldMask)
+			assert.Equal(tt.wantNullPaths, gotNullPaths)
+			assert.Equal(tt.wantFound, gotFound)
+		})
+	}
+}
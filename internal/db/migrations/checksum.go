@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Checksum is the checksum of the up migration of a version.
+type Checksum struct {
+	Version uint
+	// Name is the name of the up migration file.
+	Name string
+	// Checksum is the hex encoded sha256 of the up migration.
+	Checksum string
+}
+
+// Checksums returns the checksums of the up migrations of the dialect, ordered
+// by version.
+func Checksums(dialect string) ([]Checksum, error) {
+	var migrationsMap map[string]*fakeFile
+	switch dialect {
+	case "postgres":
+		migrationsMap = postgresMigrations
+	default:
+		return nil, fmt.Errorf("unknown migrations dialect %s", dialect)
+	}
+	ret := make([]Checksum, 0, len(migrationsMap)/2)
+	for k, f := range migrationsMap {
+		name := strings.TrimPrefix(k, "migrations/")
+		if !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+		i := strings.Index(name, "_")
+		if i < 1 {
+			return nil, fmt.Errorf("migration %s has no version", name)
+		}
+		v, err := strconv.ParseUint(name[:i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version: %w", name, err)
+		}
+		sum := sha256.Sum256(f.bytes)
+		ret = append(ret, Checksum{Version: uint(v), Name: name, Checksum: hex.EncodeToString(sum[:])})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Version < ret[j].Version })
+	return ret, nil
+}
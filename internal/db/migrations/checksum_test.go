@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksums(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	got, err := Checksums("postgres")
+	require.NoError(err)
+	require.NotEmpty(got)
+	for i, c := range got {
+		assert.True(strings.HasSuffix(c.Name, ".up.sql"), c.Name)
+		assert.Len(c.Checksum, 64)
+		if i > 0 {
+			assert.Greater(c.Version, got[i-1].Version)
+		}
+	}
+	again, err := Checksums("postgres")
+	require.NoError(err)
+	assert.Equal(got, again)
+
+	_, err = Checksums("unknown")
+	assert.Error(err)
+}
@@ -1486,3877 +1486,6624 @@ commit;
 
 `),
 	},
-	"migrations/11_auth_token.down.sql": {
-		name: "11_auth_token.down.sql",
+	"migrations/100_scope_usage.down.sql": {
+		name: "100_scope_usage.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop view auth_token_account cascade;
-  drop table auth_token cascade;
-
-  drop function update_last_access_time cascade;
-  drop function immutable_auth_token_columns cascade;
-  drop function expire_time_not_older_than_token cascade;
+  drop table scope_usage_daily;
 
 commit;
 
 `),
 	},
-	"migrations/11_auth_token.up.sql": {
-		name: "11_auth_token.up.sql",
+	"migrations/100_scope_usage.up.sql": {
+		name: "100_scope_usage.up.sql",
 		bytes: []byte(`
 begin;
 
-  -- an auth token belongs to 1 and only 1 auth account
-  -- an auth account can have 0 to many auth tokens
-  create table auth_token (
-    public_id wt_public_id primary key,
-    token bytea not null unique,
-    -- TODO: Make key_id a foreign key once we have DEKs
-    key_id text not null
-      constraint key_id_must_not_be_empty
-      check(length(trim(key_id)) > 0),
-    auth_account_id wt_public_id not null
-      references auth_account(public_id)
-      on delete cascade
-      on update cascade,
-    create_time wt_timestamp,
-    update_time wt_timestamp,
-    -- This column is not updated every time this auth token is accessed.
-    -- It is updated after X minutes from the last time it was updated on
-    -- a per row basis.
-    approximate_last_access_time wt_timestamp
-      constraint last_access_time_must_not_be_after_expiration_time
-      check(
-        approximate_last_access_time <= expiration_time
-      ),
-    expiration_time wt_timestamp
-      constraint create_time_must_not_be_after_expiration_time
-      check(
-        create_time <= expiration_time
-      )
+  -- scope_usage_daily holds the usage of each scope by UTC day, for
+  -- chargeback: the API calls made in the scope, which the controllers add
+  -- every few minutes, and the seconds the sessions of the project were
+  -- active, which the controllers periodically roll up from session_state.
+  -- Usages keep their scope ids after the scopes are deleted.
+  create table scope_usage_daily (
+    usage_date date not null,
+    scope_id wt_scope_id not null,
+    api_calls bigint not null default 0
+      check(api_calls >= 0),
+    session_seconds bigint not null default 0
+      check(session_seconds >= 0),
+    primary key (usage_date, scope_id)
   );
 
-  create view auth_token_account as
-        select at.public_id,
-               at.token,
-               at.auth_account_id,
-               at.create_time,
-               at.update_time,
-               at.approximate_last_access_time,
-               at.expiration_time,
-               aa.scope_id,
-               aa.iam_user_id,
-               aa.auth_method_id
-          from auth_token as at
-    inner join auth_account as aa
-            on at.auth_account_id = aa.public_id;
+  create index scope_usage_daily_scope_id_ix
+    on scope_usage_daily (scope_id, usage_date);
 
-  create or replace function
-    update_last_access_time()
-    returns trigger
-  as $$
-  begin
-    if new.approximate_last_access_time is distinct from old.approximate_last_access_time then
-      new.approximate_last_access_time = now();
-    end if;
-    return new;
-  end;
-  $$ language plpgsql;
+commit;
 
-  comment on function
-    update_last_access_time()
-  is
-    'function used in before update triggers to properly set last_access_time columns';
+`),
+	},
+	"migrations/101_target_connection_rate_limit.down.sql": {
+		name: "101_target_connection_rate_limit.down.sql",
+		bytes: []byte(`
+begin;
 
-  create or replace function
-    immutable_auth_token_columns()
-    returns trigger
-  as $$
-  begin
-    if new.auth_account_id is distinct from old.auth_account_id then
-      raise exception 'auth_account_id is read-only';
-    end if;
-    if new.token is distinct from old.token then
-      raise exception 'token is read-only';
-    end if;
-    return new;
-  end;
-  $$ language plpgsql;
+  drop table target_connection_rate_limit;
 
-  comment on function
-    immutable_auth_token_columns()
-  is
-    'function used in before update triggers to make specific columns immutable';
+commit;
 
-  -- This allows the expiration to be calculated on the server side and still hold the constraint that
-  -- the expiration time cant be before the creation time of the auth token.
-  create or replace function
-    expire_time_not_older_than_token()
-    returns trigger
-  as $$
-  begin
-    if new.expiration_time < new.create_time then
-      new.expiration_time = new.create_time;
-    end if;
-    return new;
-  end;
-  $$ language plpgsql;
+`),
+	},
+	"migrations/101_target_connection_rate_limit.up.sql": {
+		name: "101_target_connection_rate_limit.up.sql",
+		bytes: []byte(`
+begin;
 
-  comment on function
-      expire_time_not_older_than_token()
-  is
-    'function used in before insert triggers to ensure expiration time is not older than create time';
+  -- target_connection_rate_limit records the most connections a session of a
+  -- target may open per minute on a worker, enforced by workers before
+  -- authorizing each connection. A target without a row in this table is not
+  -- limited.
+  create table target_connection_rate_limit (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    connections_per_minute integer not null
+      check(connections_per_minute > 0),
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
 
   create trigger
     default_create_time_column
-  before insert on auth_token
+  before insert on target_connection_rate_limit
     for each row execute procedure default_create_time();
 
-  create trigger
-    expire_time_not_older_than_token
-  before insert on auth_token
-    for each row execute procedure expire_time_not_older_than_token();
-
   create trigger
     update_time_column
-  before update on auth_token
+  before update on target_connection_rate_limit
     for each row execute procedure update_time_column();
 
   create trigger
-    update_last_access_time
-  before update on auth_token
-    for each row execute procedure update_last_access_time();
+    immutable_columns
+  before
+  update on target_connection_rate_limit
+    for each row execute procedure immutable_columns('target_id', 'create_time');
+
+commit;
+
+`),
+	},
+	"migrations/102_scope_event_sink.down.sql": {
+		name: "102_scope_event_sink.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table scope_event_sink;
+
+commit;
+
+`),
+	},
+	"migrations/102_scope_event_sink.up.sql": {
+		name: "102_scope_event_sink.up.sql",
+		bytes: []byte(`
+begin;
 
+  -- scope_event_sink is a webhook of an org which receives the outbox events
+  -- of the org and of its projects, so tenants can have their own audit
+  -- feeds. The secret signing the deliveries is encrypted with the database
+  -- key of the org. redact_fields are the payload fields, as dot separated
+  -- paths, whose values are redacted before delivery to the sink. Sinks are
+  -- immutable; they are replaced by deleting and creating them.
+  create table scope_event_sink (
+    public_id wt_public_id primary key,
+    scope_id wt_scope_id not null
+      references iam_scope_org(scope_id)
+      on delete cascade
+      on update cascade,
+    url text not null
+      constraint url_must_not_be_empty
+      check(length(trim(url)) > 0),
+    secret bytea not null,
+    key_id text not null
+      constraint key_id_must_not_be_empty
+      check(length(trim(key_id)) > 0),
+    redact_fields text[] not null default '{}',
+    create_time wt_timestamp
+  );
+
+  create index scope_event_sink_scope_id_ix
+    on scope_event_sink (scope_id);
 
   create trigger
-    immutable_auth_token_columns
-  before update on auth_token
-    for each row execute procedure immutable_auth_token_columns();
+    default_create_time_column
+  before insert on scope_event_sink
+    for each row execute procedure default_create_time();
 
-  create trigger 
+  create trigger
     immutable_columns
   before
-  update on auth_token
-    for each row execute procedure immutable_columns('public_id', 'auth_account_id', 'create_time');
+  update on scope_event_sink
+    for each row execute procedure immutable_columns('public_id', 'scope_id', 'url', 'secret', 'key_id', 'redact_fields', 'create_time');
 
 commit;
 
 `),
 	},
-	"migrations/12_auth_password.down.sql": {
-		name: "12_auth_password.down.sql",
+	"migrations/103_server_mode.down.sql": {
+		name: "103_server_mode.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop table auth_password_credential;
-  drop table auth_password_conf cascade;
-  drop table if exists auth_password_account;
-  drop table if exists auth_password_method;
+  alter table server drop column mode;
 
-  drop function insert_auth_password_credential_subtype;
-  drop function insert_auth_password_conf_subtype;
+commit;
+
+`),
+	},
+	"migrations/103_server_mode.up.sql": {
+		name: "103_server_mode.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- mode is the mode a controller reported in its last status update:
+  -- read-only controllers serve only reads, so workers are not sent to them.
+  -- Workers are always read-write.
+  alter table server
+    add column mode text not null default 'read-write'
+      constraint server_mode_must_be_valid
+      check(mode in ('read-write', 'read-only'));
 
 commit;
 
 `),
 	},
-	"migrations/12_auth_password.up.sql": {
-		name: "12_auth_password.up.sql",
+	"migrations/104_oplog_staging_commit_order.down.sql": {
+		name: "104_oplog_staging_commit_order.down.sql",
 		bytes: []byte(`
 begin;
 
-/*
+  drop trigger set_oplog_entry_staging_commit_seq on oplog_entry_staging;
+  drop function set_oplog_entry_staging_commit_seq;
+  drop sequence oplog_entry_staging_commit_seq;
+  alter table oplog_entry_staging
+    drop column commit_seq,
+    drop column encrypted;
 
-       ┌────────────────┐                 ┌──────────────────────┐             ┌────────────────────────────┐
-       │  auth_method   │                 │ auth_password_method │             │     auth_password_conf     │
-       ├────────────────┤                 ├──────────────────────┤             ├────────────────────────────┤
-       │ public_id (pk) │                 │ public_id (pk,fk)    │            ╱│ private_id         (pk,fk) │
-       │ scope_id  (fk) │┼┼─────────────○┼│ scope_id  (fk)       │┼┼─────────○─│ password_method_id (fk)    │
-       │                │                 │ ...                  │            ╲│                            │
-       └────────────────┘                 └──────────────────────┘             └────────────────────────────┘
-                ┼                                     ┼                                       ┼
-                ┼                                     ┼                                       ┼
-                │                                     │                                       │
-                │ ▲fk1                                │ ▲fk1                                  │ ▲fk1
-                │                                     │                                       │
-                ○                                     ○                                       ○
-               ╱│╲                                   ╱│╲                                     ╱│╲
-  ┌──────────────────────────┐          ┌──────────────────────────┐          ┌───────────────────────────────┐
-  │       auth_account       │          │  auth_password_account   │          │   auth_password_credential    │
-  ├──────────────────────────┤          ├──────────────────────────┤          ├───────────────────────────────┤
-  │ public_id         (pk)   │          │ public_id      (pk,fk2)  │          │ private_id          (pk)      │
-  │ scope_id          (fk1)  │   ◀fk2   │ scope_id       (fk1,fk2) │   ◀fk2   │ password_method_id  (fk1,fk2) │
-  │ auth_method_id    (fk1)  │┼┼──────○┼│ auth_method_id (fk1,fk2) │┼┼──────○┼│ password_conf_id    (fk1)     │
-  │ iam_user_scope_id (fk2)  │          │ ...                      │          │ password_account_id (fk2)     │
-  │ iam_user_id       (fk2)  │          └──────────────────────────┘          └───────────────────────────────┘
-  └──────────────────────────┘
+commit;
 
-  An auth_method is a base type. An auth_password_method is an auth_method
-  subtype. For every row in auth_password_method there is one row in auth_method
-  with the same public_id and scope_id.
+`),
+	},
+	"migrations/104_oplog_staging_commit_order.up.sql": {
+		name: "104_oplog_staging_commit_order.up.sql",
+		bytes: []byte(`
+begin;
 
-  Similarly, an auth_account is a base type. An auth_password_account is an
-  auth_account subtype. For every row in auth_password_account there is one row
-  in auth_account with the same public_id, scope_id, and auth_method_id.
+  -- Entries are now encrypted with the oplog wrapper of their scope before
+  -- they are staged. encrypted is false for the entries staged before, whose
+  -- data is encrypted when they are flushed.
+  --
+  -- commit_seq orders the staged entries by the commit of the transaction
+  -- which staged them rather than by when they were staged, since a
+  -- transaction may stage an entry before another transaction which commits
+  -- first. It is null until the transaction commits.
+  alter table oplog_entry_staging
+    add column encrypted boolean not null default false,
+    add column commit_seq bigint;
+
+  create sequence oplog_entry_staging_commit_seq;
+
+  -- The entries staged before are all committed and keep their order
+  update oplog_entry_staging set commit_seq = id;
+  select setval('oplog_entry_staging_commit_seq', coalesce(max(id), 0) + 1, false)
+    from oplog_entry_staging;
+
+  create index oplog_entry_staging_commit_seq_ix
+    on oplog_entry_staging (commit_seq);
+
+  -- set_oplog_entry_staging_commit_seq numbers the entries staged by a
+  -- transaction as it commits, in the order they were staged. The advisory
+  -- lock is held until the commit completes, so transactions which staged
+  -- entries commit one at a time in the order of their numbers, and an entry
+  -- is never visible before an entry with a lower number.
+  create function set_oplog_entry_staging_commit_seq()
+    returns trigger
+  as $$
+  begin
+    perform pg_advisory_xact_lock(hashtext('oplog_entry_staging_commit_seq'));
+    update oplog_entry_staging
+       set commit_seq = nextval('oplog_entry_staging_commit_seq')
+     where id = new.id;
+    return null;
+  end;
+  $$ language plpgsql;
 
-  Both auth_password_conf and auth_password_credential are base types. Each
-  password key derivation function will require a auth_password_conf and
-  auth_password_credential table.
+  create constraint trigger
+    set_oplog_entry_staging_commit_seq
+  after insert on oplog_entry_staging
+    deferrable initially deferred
+    for each row execute procedure set_oplog_entry_staging_commit_seq();
 
-  An auth_method can have 0 or 1 auth_password_method.
-  An auth_account can have 0 or 1 auth_password_account.
+commit;
 
-  An auth_password_method belongs to 1 auth_method.
-  An auth_password_method can have 0 to many auth_password_accounts.
-  An auth_password_method can have 0 to many auth_password_confs.
+`),
+	},
+	"migrations/105_auth_password_recovery_totp.down.sql": {
+		name: "105_auth_password_recovery_totp.down.sql",
+		bytes: []byte(`
+begin;
 
-  An auth_password_account belongs to 1 auth_account.
-  An auth_password_account belongs to 1 auth_password_method.
-  An auth_password_account can have 0 or 1 auth_password_credential.
+  drop table auth_password_recovery_totp;
 
-  An auth_password_conf belongs to 1 auth_password_method.
-  An auth_password_conf can have 0 to many auth_password_credentials.
+commit;
 
-  An auth_password_credential belongs to 1 auth_password_account.
-  An auth_password_credential belongs to 1 auth_password_conf.
+`),
+	},
+	"migrations/105_auth_password_recovery_totp.up.sql": {
+		name: "105_auth_password_recovery_totp.up.sql",
+		bytes: []byte(`
+begin;
 
-*/
-
-  create table auth_password_method (
-    public_id wt_public_id
-      primary key,
-    scope_id wt_scope_id
-      not null,
-    password_conf_id wt_private_id, -- FK to auth_password_conf added below
-    name text,
-    description text,
-    create_time wt_timestamp,
-    update_time wt_timestamp,
-    min_login_name_length int
-      not null
-      default 3,
-    min_password_length int
-      not null
-      default 8,
-    version wt_version,
-    foreign key (scope_id, public_id)
-      references auth_method (scope_id, public_id)
-      on delete cascade
-      on update cascade,
-    unique(scope_id, name),
-    unique(scope_id, public_id)
-  );
+  -- auth_password_recovery_totp holds the TOTP secret of a password account
+  -- with recovery codes. A recovery code only authenticates together with a
+  -- current code of the secret, so a leaked list of recovery codes is not
+  -- enough to authenticate. The secret is encrypted with the database key of
+  -- the auth method's scope. last_used_step is the time step of the last TOTP
+  -- code used, so a code can not be replayed.
+  create table auth_password_recovery_totp (
+    password_account_id wt_public_id primary key
+      references auth_password_account(public_id)
+      on delete cascade
+      on update cascade,
+    secret bytea not null
+      constraint auth_password_recovery_totp_secret_must_not_be_empty
+      check(length(secret) > 0),
+    key_id text not null
+      constraint key_id_must_not_be_empty
+      check(length(trim(key_id)) > 0),
+    last_used_step bigint not null default 0,
+    create_time wt_timestamp
+  );
 
   create trigger
-    update_version_column
-  after update on auth_password_method
-    for each row execute procedure update_version_column();
+    default_create_time_column
+  before
+  insert on auth_password_recovery_totp
+    for each row execute procedure default_create_time();
 
   create trigger
-    insert_auth_method_subtype
-  before insert on auth_password_method
-    for each row execute procedure insert_auth_method_subtype();
+    immutable_columns
+  before
+  update on auth_password_recovery_totp
+    for each row execute procedure immutable_columns('password_account_id', 'secret', 'key_id', 'create_time');
 
-  create table auth_password_account (
-    public_id wt_public_id
-      primary key,
-    auth_method_id wt_public_id
-      not null,
-    -- NOTE(mgaffney): The scope_id type is not wt_scope_id because the domain
-    -- check is executed before the insert trigger which retrieves the scope_id
-    -- causing an insert to fail.
-    scope_id text not null,
-    name text,
-    description text,
-    create_time wt_timestamp,
-    update_time wt_timestamp,
-    login_name text not null
-      constraint login_name_must_be_lowercase
-      check(lower(trim(login_name)) = login_name)
-      constraint login_name_must_not_be_empty
-      check(length(trim(login_name)) > 0),
-    version wt_version,
-    foreign key (scope_id, auth_method_id)
-      references auth_password_method (scope_id, public_id)
-      on delete cascade
-      on update cascade,
-    foreign key (scope_id, auth_method_id, public_id)
-      references auth_account (scope_id, auth_method_id, public_id)
+commit;
+
+`),
+	},
+	"migrations/106_auth_account_disabled_oplog.down.sql": {
+		name: "106_auth_account_disabled_oplog.down.sql",
+		bytes: []byte(`
+begin;
+
+  delete from oplog_ticket where name = 'auth_account_disabled';
+
+commit;
+
+`),
+	},
+	"migrations/106_auth_account_disabled_oplog.up.sql": {
+		name: "106_auth_account_disabled_oplog.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- Disabling and enabling accounts is recorded in the oplog.
+  insert into oplog_ticket
+    (name, version)
+  values
+    ('auth_account_disabled', 1);
+
+commit;
+
+`),
+	},
+	"migrations/107_iam_user_enabled.down.sql": {
+		name: "107_iam_user_enabled.down.sql",
+		bytes: []byte(`
+begin;
+
+  delete from oplog_ticket where name = 'iam_user_disabled';
+  drop table iam_user_enabled;
+
+commit;
+
+`),
+	},
+	"migrations/107_iam_user_enabled.up.sql": {
+		name: "107_iam_user_enabled.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- iam_user_enabled records when disabled users were last enabled. Their
+  -- inactivity restarts then, so the controllers don't disable them again
+  -- right away, without counting it as a login.
+  create table iam_user_enabled (
+    iam_user_id wt_user_id primary key
+      references iam_user(public_id)
       on delete cascade
       on update cascade,
-    unique(auth_method_id, name),
-    unique(auth_method_id, login_name),
-    unique(auth_method_id, public_id)
+    enable_time timestamp with time zone not null
+      default current_timestamp
   );
 
-  create trigger
-    update_version_column
-  after update on auth_password_account
-    for each row execute procedure update_version_column();
+  -- Disabling and enabling users is recorded in the oplog.
+  insert into oplog_ticket
+    (name, version)
+  values
+    ('iam_user_disabled', 1);
 
-  create trigger
-    insert_auth_account_subtype
-  before insert on auth_password_account
-    for each row execute procedure insert_auth_account_subtype();
+commit;
 
-  create table auth_password_conf (
-    private_id wt_private_id
-      primary key,
-    password_method_id wt_public_id
-      not null
-      references auth_password_method (public_id)
-      on delete cascade
-      on update cascade
-      deferrable initially deferred,
-    unique(password_method_id, private_id)
+`),
+	},
+	"migrations/108_iam_principal_grants_version.down.sql": {
+		name: "108_iam_principal_grants_version.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop trigger delete_iam_principal_grants_version on iam_group;
+  drop trigger delete_iam_principal_grants_version on iam_user;
+  drop function delete_iam_principal_grants_version;
+  drop trigger iam_role_grant_scope_grants_version on iam_role;
+  drop function iam_role_grant_scope_grants_version;
+  drop trigger iam_role_grants_version on iam_role_grant;
+  drop function iam_role_grants_version;
+  drop trigger iam_group_member_grants_version on iam_group_member_user;
+  drop function iam_group_member_grants_version;
+  drop trigger iam_principal_role_grants_version on iam_group_role_expiration;
+  drop trigger iam_principal_role_grants_version on iam_user_role_expiration;
+  drop trigger iam_principal_role_grants_version on iam_group_role;
+  drop trigger iam_principal_role_grants_version on iam_user_role;
+  drop function iam_principal_role_grants_version;
+  drop function increment_iam_role_grants_version;
+  drop function increment_iam_principal_grants_version;
+  drop table iam_principal_grants_version;
+
+  create table iam_grants_version (
+    id boolean primary key default true
+      constraint iam_grants_version_must_have_a_single_row
+      check(id),
+    version bigint not null default 1
   );
 
-  alter table auth_password_method
-    add constraint current_conf_fkey
-    foreign key (public_id, password_conf_id)
-    references auth_password_conf (password_method_id, private_id)
-    on delete cascade
-    on update cascade
-    deferrable initially deferred;
+  insert into iam_grants_version default values;
 
-  -- insert_auth_password_conf_subtype() is a trigger function for subtypes of
-  -- auth_password_conf
   create or replace function
-    insert_auth_password_conf_subtype()
+    increment_iam_grants_version()
     returns trigger
   as $$
   begin
-    insert into auth_password_conf
-      (private_id, password_method_id)
-    values
-      (new.private_id, new.password_method_id);
-    return new;
+    update iam_grants_version set version = version + 1;
+    return null;
   end;
   $$ language plpgsql;
 
-  create table auth_password_credential (
-    private_id wt_private_id
-      primary key,
-    password_account_id wt_public_id
-      not null
-      unique,
-    password_conf_id wt_private_id,
-    password_method_id wt_public_id
-      not null,
-    foreign key (password_method_id, password_conf_id)
-      references auth_password_conf (password_method_id, private_id)
-      on delete cascade
-      on update cascade,
-    foreign key (password_method_id, password_account_id)
-      references auth_password_account (auth_method_id, public_id)
-      on delete cascade
-      on update cascade,
-    unique(password_method_id, password_conf_id, password_account_id)
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_role
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_role_grant
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_user_role
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_group_role
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_user_role_expiration
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_group_role_expiration
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_group_member_user
+    for each statement execute procedure increment_iam_grants_version();
+
+commit;
+
+`),
+	},
+	"migrations/108_iam_principal_grants_version.up.sql": {
+		name: "108_iam_principal_grants_version.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- The single row of iam_grants_version was updated by every change to the
+  -- roles, their grants and principals, and the groups of users, so all of
+  -- them contended on it and each invalidated the cached grants of all users.
+  -- It is replaced by a version per principal, i.e. user or group, which is
+  -- incremented by the changes to the roles it has, their grants and, for
+  -- users, the groups they are members of. Controllers caching the grants of
+  -- a user compare the versions of the user, u_anon, u_auth and the groups of
+  -- the user with those their cache entry was loaded at.
+  drop trigger increment_iam_grants_version on iam_role;
+  drop trigger increment_iam_grants_version on iam_role_grant;
+  drop trigger increment_iam_grants_version on iam_user_role;
+  drop trigger increment_iam_grants_version on iam_group_role;
+  drop trigger increment_iam_grants_version on iam_user_role_expiration;
+  drop trigger increment_iam_grants_version on iam_group_role_expiration;
+  drop trigger increment_iam_grants_version on iam_group_member_user;
+  drop function increment_iam_grants_version;
+  drop table iam_grants_version;
+
+  -- A principal without a row has version 0.
+  create table iam_principal_grants_version (
+    principal_id text primary key,
+    version bigint not null default 1
   );
 
-  -- insert_auth_password_credential_subtype() is a trigger function for
-  -- subtypes of auth_password_credential
   create or replace function
-    insert_auth_password_credential_subtype()
+    increment_iam_principal_grants_version(p_principal_id text)
+    returns void
+  as $$
+    insert into iam_principal_grants_version (principal_id)
+    values (p_principal_id)
+        on conflict (principal_id) do update
+       set version = iam_principal_grants_version.version + 1;
+  $$ language sql;
+
+  -- increment_iam_role_grants_version() increments the versions of all
+  -- principals of the role.
+  create or replace function
+    increment_iam_role_grants_version(p_role_id text)
+    returns void
+  as $$
+    insert into iam_principal_grants_version (principal_id)
+    select principal_id
+      from iam_user_role
+     where role_id = p_role_id
+     union
+    select principal_id
+      from iam_group_role
+     where role_id = p_role_id
+        on conflict (principal_id) do update
+       set version = iam_principal_grants_version.version + 1;
+  $$ language sql;
+
+  create or replace function
+    iam_principal_role_grants_version()
     returns trigger
   as $$
   begin
+    if tg_op in ('UPDATE', 'DELETE') then
+      perform increment_iam_principal_grants_version(old.principal_id);
+    end if;
+    if tg_op = 'INSERT' or (tg_op = 'UPDATE' and new.principal_id <> old.principal_id) then
+      perform increment_iam_principal_grants_version(new.principal_id);
+    end if;
+    return null;
+  end;
+  $$ language plpgsql;
 
-    select auth_password_account.auth_method_id
-      into new.password_method_id
-    from auth_password_account
-    where auth_password_account.public_id = new.password_account_id;
+  create trigger iam_principal_role_grants_version
+  after insert or update or delete on iam_user_role
+    for each row execute procedure iam_principal_role_grants_version();
 
-    insert into auth_password_credential
-      (private_id, password_account_id, password_conf_id, password_method_id)
-    values
-      (new.private_id, new.password_account_id, new.password_conf_id, new.password_method_id);
-    return new;
+  create trigger iam_principal_role_grants_version
+  after insert or update or delete on iam_group_role
+    for each row execute procedure iam_principal_role_grants_version();
+
+  create trigger iam_principal_role_grants_version
+  after insert or update or delete on iam_user_role_expiration
+    for each row execute procedure iam_principal_role_grants_version();
+
+  create trigger iam_principal_role_grants_version
+  after insert or update or delete on iam_group_role_expiration
+    for each row execute procedure iam_principal_role_grants_version();
+
+  create or replace function
+    iam_group_member_grants_version()
+    returns trigger
+  as $$
+  begin
+    if tg_op in ('UPDATE', 'DELETE') then
+      perform increment_iam_principal_grants_version(old.member_id);
+    end if;
+    if tg_op = 'INSERT' or (tg_op = 'UPDATE' and new.member_id <> old.member_id) then
+      perform increment_iam_principal_grants_version(new.member_id);
+    end if;
+    return null;
   end;
   $$ language plpgsql;
 
-  -- update_auth_password_credential_subtype() is an after update trigger
-  -- function for subtypes of auth_password_credential
+  create trigger iam_group_member_grants_version
+  after insert or update or delete on iam_group_member_user
+    for each row execute procedure iam_group_member_grants_version();
+
   create or replace function
-    update_auth_password_credential_subtype()
+    iam_role_grants_version()
     returns trigger
   as $$
   begin
-    /*
-      The configuration id of a credential is updated when a credential is
-      rehashed during authentication.
-    */
-    if new.password_conf_id is distinct from old.password_conf_id then
-      update auth_password_credential
-         set password_conf_id = new.password_conf_id
-       where private_id = new.private_id;
+    if tg_op in ('UPDATE', 'DELETE') then
+      perform increment_iam_role_grants_version(old.role_id);
     end if;
-    return null; -- result is ignored since this is an after trigger
+    if tg_op = 'INSERT' or (tg_op = 'UPDATE' and new.role_id <> old.role_id) then
+      perform increment_iam_role_grants_version(new.role_id);
+    end if;
+    return null;
   end;
   $$ language plpgsql;
 
-  -- delete_auth_password_credential_subtype() is an after delete trigger
-  -- function for subtypes of auth_password_credential
+  create trigger iam_role_grants_version
+  after insert or update or delete on iam_role_grant
+    for each row execute procedure iam_role_grants_version();
+
+  -- Only the grant scope of a role changes its grants; its version is also
+  -- updated by the changes to its principals.
   create or replace function
-    delete_auth_password_credential_subtype()
+    iam_role_grant_scope_grants_version()
     returns trigger
   as $$
   begin
-    delete
-      from auth_password_credential
-     where private_id = old.private_id;
-    return null; -- result is ignored since this is an after trigger
+    perform increment_iam_role_grants_version(new.public_id);
+    return null;
   end;
   $$ language plpgsql;
 
-  --
-  -- triggers for time columns
-  --
+  create trigger iam_role_grant_scope_grants_version
+  after update on iam_role
+    for each row
+    when (old.grant_scope_id is distinct from new.grant_scope_id)
+    execute procedure iam_role_grant_scope_grants_version();
 
-  create trigger
-    update_time_column
-  before
-  update on auth_password_method
-    for each row execute procedure update_time_column();
+  create or replace function
+    delete_iam_principal_grants_version()
+    returns trigger
+  as $$
+  begin
+    delete from iam_principal_grants_version
+     where principal_id = old.public_id;
+    return null;
+  end;
+  $$ language plpgsql;
 
-  create trigger
-    immutable_columns
-  before
-  update on auth_password_method
-    for each row execute procedure immutable_columns('create_time');
+  create trigger delete_iam_principal_grants_version
+  after delete on iam_user
+    for each row execute procedure delete_iam_principal_grants_version();
 
-  create trigger
-    default_create_time_column
-  before
-  insert on auth_password_method
-    for each row execute procedure default_create_time();
-
-  create trigger
-    update_time_column
-  before
-  update on auth_password_account
-    for each row execute procedure update_time_column();
-
-  create trigger
-    immutable_columns
-  before
-  update on auth_password_account
-    for each row execute procedure immutable_columns('create_time');
-
-  create trigger
-    default_create_time_column
-  before
-  insert on auth_password_account
-    for each row execute procedure default_create_time();
-
-  -- The tickets for oplog are the subtypes not the base types because no updates
-  -- are done to any values in the base types.
-  insert into oplog_ticket
-    (name, version)
-  values
-    ('auth_password_method', 1),
-    ('auth_password_account', 1),
-    ('auth_password_credential', 1);
+  create trigger delete_iam_principal_grants_version
+  after delete on iam_group
+    for each row execute procedure delete_iam_principal_grants_version();
 
 commit;
 
 `),
 	},
-	"migrations/13_auth_password_argon.down.sql": {
-		name: "13_auth_password_argon.down.sql",
+	"migrations/11_auth_token.down.sql": {
+		name: "11_auth_token.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop table auth_password_argon2_cred;
-  drop table auth_password_argon2_conf;
+  drop view auth_token_account cascade;
+  drop table auth_token cascade;
+
+  drop function update_last_access_time cascade;
+  drop function immutable_auth_token_columns cascade;
+  drop function expire_time_not_older_than_token cascade;
 
 commit;
 
 `),
 	},
-	"migrations/13_auth_password_argon.up.sql": {
-		name: "13_auth_password_argon.up.sql",
+	"migrations/11_auth_token.up.sql": {
+		name: "11_auth_token.up.sql",
 		bytes: []byte(`
 begin;
 
-  create table auth_password_argon2_conf (
-    private_id wt_private_id primary key
-      references auth_password_conf (private_id)
+  -- an auth token belongs to 1 and only 1 auth account
+  -- an auth account can have 0 to many auth tokens
+  create table auth_token (
+    public_id wt_public_id primary key,
+    token bytea not null unique,
+    -- TODO: Make key_id a foreign key once we have DEKs
+    key_id text not null
+      constraint key_id_must_not_be_empty
+      check(length(trim(key_id)) > 0),
+    auth_account_id wt_public_id not null
+      references auth_account(public_id)
       on delete cascade
       on update cascade,
-    password_method_id wt_public_id not null,
     create_time wt_timestamp,
-    iterations int not null default 3
-      constraint iterations_must_be_greater_than_0
-      check(iterations > 0),
-    memory int not null default 65536
-      constraint memory_must_be_greater_than_0
-      check(memory > 0),
-    threads int not null default 1
-      constraint threads_must_be_greater_than_0
-      check(threads > 0),
-    -- salt_length unit is bytes
-    salt_length int not null default 32
-    -- minimum of 16 bytes (128 bits)
-      constraint salt_must_be_at_least_16_bytes
-      check(salt_length >= 16),
-    -- key_length unit is bytes
-    key_length int not null default 32
-    -- minimum of 16 bytes (128 bits)
-      constraint key_length_must_be_at_least_16_bytes
-      check(key_length >= 16),
-    unique(password_method_id, iterations, memory, threads, salt_length, key_length),
-    unique (password_method_id, private_id),
-    foreign key (password_method_id, private_id)
-      references auth_password_conf (password_method_id, private_id)
-      on delete cascade
-      on update cascade
-      deferrable initially deferred
+    update_time wt_timestamp,
+    -- This column is not updated every time this auth token is accessed.
+    -- It is updated after X minutes from the last time it was updated on
+    -- a per row basis.
+    approximate_last_access_time wt_timestamp
+      constraint last_access_time_must_not_be_after_expiration_time
+      check(
+        approximate_last_access_time <= expiration_time
+      ),
+    expiration_time wt_timestamp
+      constraint create_time_must_not_be_after_expiration_time
+      check(
+        create_time <= expiration_time
+      )
   );
 
+  create view auth_token_account as
+        select at.public_id,
+               at.token,
+               at.auth_account_id,
+               at.create_time,
+               at.update_time,
+               at.approximate_last_access_time,
+               at.expiration_time,
+               aa.scope_id,
+               aa.iam_user_id,
+               aa.auth_method_id
+          from auth_token as at
+    inner join auth_account as aa
+            on at.auth_account_id = aa.public_id;
+
   create or replace function
-    read_only_auth_password_argon2_conf()
+    update_last_access_time()
     returns trigger
   as $$
   begin
-    raise exception 'auth_password_argon2_conf is read-only';
+    if new.approximate_last_access_time is distinct from old.approximate_last_access_time then
+      new.approximate_last_access_time = now();
+    end if;
+    return new;
   end;
   $$ language plpgsql;
 
-  create trigger
-    read_only_auth_password_argon2_conf
-  before
-  update on auth_password_argon2_conf
-    for each row execute procedure read_only_auth_password_argon2_conf();
-
-  create trigger
-    insert_auth_password_conf_subtype
-  before insert on auth_password_argon2_conf
-    for each row execute procedure insert_auth_password_conf_subtype();
-
-  create table auth_password_argon2_cred (
-    private_id wt_private_id primary key
-      references auth_password_credential (private_id)
-      on delete cascade
-      on update cascade,
-    password_account_id wt_public_id not null,
-    password_conf_id wt_private_id,
-    -- NOTE(mgaffney): The password_method_id type is not wt_public_id because
-    -- the domain check is executed before the insert trigger which retrieves
-    -- the password_method_id causing an insert to fail.
-    password_method_id text not null,
-    create_time wt_timestamp,
-    update_time wt_timestamp,
-    salt bytea not null -- cannot be changed unless derived_key is changed too
-      constraint salt_must_not_be_empty
-      check(length(salt) > 0),
-    derived_key bytea not null
-      constraint derived_key_must_not_be_empty
-      check(length(derived_key) > 0),
-    -- TODO: Make key_id a foreign key once we have DEKs
-    key_id text not null
-      constraint key_id_must_not_be_empty
-      check(length(trim(key_id)) > 0),
-    foreign key (password_method_id, password_conf_id)
-      references auth_password_argon2_conf (password_method_id, private_id)
-      on delete cascade
-      on update cascade,
-    foreign key (password_method_id, password_conf_id, password_account_id)
-      references auth_password_credential (password_method_id, password_conf_id, password_account_id)
-      on delete cascade
-      on update cascade
-      deferrable initially deferred
-  );
-
-  create trigger
-    insert_auth_password_credential_subtype
-  before insert on auth_password_argon2_cred
-    for each row execute procedure insert_auth_password_credential_subtype();
+  comment on function
+    update_last_access_time()
+  is
+    'function used in before update triggers to properly set last_access_time columns';
 
-  create trigger
-    update_auth_password_credential_subtype
-  after update on auth_password_argon2_cred
-    for each row execute procedure update_auth_password_credential_subtype();
+  create or replace function
+    immutable_auth_token_columns()
+    returns trigger
+  as $$
+  begin
+    if new.auth_account_id is distinct from old.auth_account_id then
+      raise exception 'auth_account_id is read-only';
+    end if;
+    if new.token is distinct from old.token then
+      raise exception 'token is read-only';
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
 
-  create trigger
-    delete_auth_password_credential_subtype
-  after delete on auth_password_argon2_cred
-    for each row execute procedure delete_auth_password_credential_subtype();
+  comment on function
+    immutable_auth_token_columns()
+  is
+    'function used in before update triggers to make specific columns immutable';
 
-  --
-  -- triggers for time columns
-  --
+  -- This allows the expiration to be calculated on the server side and still hold the constraint that
+  -- the expiration time cant be before the creation time of the auth token.
+  create or replace function
+    expire_time_not_older_than_token()
+    returns trigger
+  as $$
+  begin
+    if new.expiration_time < new.create_time then
+      new.expiration_time = new.create_time;
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
 
-  create trigger
-    immutable_columns
-  before
-  update on auth_password_argon2_conf
-    for each row execute procedure immutable_columns('create_time');
+  comment on function
+      expire_time_not_older_than_token()
+  is
+    'function used in before insert triggers to ensure expiration time is not older than create time';
 
   create trigger
     default_create_time_column
-  before
-  insert on auth_password_argon2_conf
+  before insert on auth_token
     for each row execute procedure default_create_time();
 
+  create trigger
+    expire_time_not_older_than_token
+  before insert on auth_token
+    for each row execute procedure expire_time_not_older_than_token();
+
   create trigger
     update_time_column
-  before
-  update on auth_password_argon2_cred
+  before update on auth_token
     for each row execute procedure update_time_column();
 
   create trigger
-    immutable_columns
-  before
-  update on auth_password_argon2_cred
-    for each row execute procedure immutable_columns('create_time');
+    update_last_access_time
+  before update on auth_token
+    for each row execute procedure update_last_access_time();
+
 
   create trigger
-    default_create_time_column
+    immutable_auth_token_columns
+  before update on auth_token
+    for each row execute procedure immutable_auth_token_columns();
+
+  create trigger 
+    immutable_columns
   before
-  insert on auth_password_argon2_cred
-    for each row execute procedure default_create_time();
-
-  -- The tickets for oplog are the subtypes not the base types because no updates
-  -- are done to any values in the base types.
-  insert into oplog_ticket
-    (name, version)
-  values
-    ('auth_password_argon2_conf', 1),
-    ('auth_password_argon2_cred', 1);
+  update on auth_token
+    for each row execute procedure immutable_columns('public_id', 'auth_account_id', 'create_time');
 
 commit;
 
 `),
 	},
-	"migrations/14_auth_password_views.down.sql": {
-		name: "14_auth_password_views.down.sql",
+	"migrations/12_auth_password.down.sql": {
+		name: "12_auth_password.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop view auth_password_current_conf;
-  drop view auth_password_conf_union;
+  drop table auth_password_credential;
+  drop table auth_password_conf cascade;
+  drop table if exists auth_password_account;
+  drop table if exists auth_password_method;
+
+  drop function insert_auth_password_credential_subtype;
+  drop function insert_auth_password_conf_subtype;
 
 commit;
 
 `),
 	},
-	"migrations/14_auth_password_views.up.sql": {
-		name: "14_auth_password_views.up.sql",
+	"migrations/12_auth_password.up.sql": {
+		name: "12_auth_password.up.sql",
 		bytes: []byte(`
 begin;
 
-  -- auth_password_conf_union is a union of the configuration settings
-  -- of all supported key derivation functions.
-  -- It will be updated as new key derivation functions are supported.
-  create or replace view auth_password_conf_union as
-      -- Do not change the order of the columns when adding new configurations.
-      -- Union with new tables appending new columns as needed.
-      select c.password_method_id, c.private_id as password_conf_id, c.private_id,
-             'argon2' as conf_type,
-             c.iterations, c.memory, c.threads, c.salt_length, c.key_length
-        from auth_password_argon2_conf c;
-
-  -- auth_password_current_conf provides a view of the current password
-  -- configuration for each password auth method.
-  -- The view will be updated as new key derivation functions are supported
-  -- but the query to create the view should not need to be updated.
-  create or replace view auth_password_current_conf as
-      -- Rerun this query whenever auth_password_conf_union is updated.
-      select pm.min_login_name_length, pm.min_password_length, c.*
-        from auth_password_method pm
-  inner join auth_password_conf_union c
-          on pm.password_conf_id = c.password_conf_id;
+/*
 
-commit;
+       ┌────────────────┐                 ┌──────────────────────┐             ┌────────────────────────────┐
+       │  auth_method   │                 │ auth_password_method │             │     auth_password_conf     │
+       ├────────────────┤                 ├──────────────────────┤             ├────────────────────────────┤
+       │ public_id (pk) │                 │ public_id (pk,fk)    │            ╱│ private_id         (pk,fk) │
+       │ scope_id  (fk) │┼┼─────────────○┼│ scope_id  (fk)       │┼┼─────────○─│ password_method_id (fk)    │
+       │                │                 │ ...                  │            ╲│                            │
+       └────────────────┘                 └──────────────────────┘             └────────────────────────────┘
+                ┼                                     ┼                                       ┼
+                ┼                                     ┼                                       ┼
+                │                                     │                                       │
+                │ ▲fk1                                │ ▲fk1                                  │ ▲fk1
+                │                                     │                                       │
+                ○                                     ○                                       ○
+               ╱│╲                                   ╱│╲                                     ╱│╲
+  ┌──────────────────────────┐          ┌──────────────────────────┐          ┌───────────────────────────────┐
+  │       auth_account       │          │  auth_password_account   │          │   auth_password_credential    │
+  ├──────────────────────────┤          ├──────────────────────────┤          ├───────────────────────────────┤
+  │ public_id         (pk)   │          │ public_id      (pk,fk2)  │          │ private_id          (pk)      │
+  │ scope_id          (fk1)  │   ◀fk2   │ scope_id       (fk1,fk2) │   ◀fk2   │ password_method_id  (fk1,fk2) │
+  │ auth_method_id    (fk1)  │┼┼──────○┼│ auth_method_id (fk1,fk2) │┼┼──────○┼│ password_conf_id    (fk1)     │
+  │ iam_user_scope_id (fk2)  │          │ ...                      │          │ password_account_id (fk2)     │
+  │ iam_user_id       (fk2)  │          └──────────────────────────┘          └───────────────────────────────┘
+  └──────────────────────────┘
 
-`),
-	},
-	"migrations/20_host.down.sql": {
-		name: "20_host.down.sql",
-		bytes: []byte(`
-begin;
+  An auth_method is a base type. An auth_password_method is an auth_method
+  subtype. For every row in auth_password_method there is one row in auth_method
+  with the same public_id and scope_id.
 
-  drop table host_set;
-  drop table host;
-  drop table host_catalog;
+  Similarly, an auth_account is a base type. An auth_password_account is an
+  auth_account subtype. For every row in auth_password_account there is one row
+  in auth_account with the same public_id, scope_id, and auth_method_id.
 
-  drop function insert_host_set_subtype;
-  drop function insert_host_subtype;
-  drop function insert_host_catalog_subtype;
+  Both auth_password_conf and auth_password_credential are base types. Each
+  password key derivation function will require a auth_password_conf and
+  auth_password_credential table.
 
-  delete
-    from oplog_ticket
-   where name in (
-          'host_catalog',
-          'host',
-          'host_set'
-        );
+  An auth_method can have 0 or 1 auth_password_method.
+  An auth_account can have 0 or 1 auth_password_account.
 
-commit;
+  An auth_password_method belongs to 1 auth_method.
+  An auth_password_method can have 0 to many auth_password_accounts.
+  An auth_password_method can have 0 to many auth_password_confs.
 
-`),
-	},
-	"migrations/20_host.up.sql": {
-		name: "20_host.up.sql",
-		bytes: []byte(`
-begin;
+  An auth_password_account belongs to 1 auth_account.
+  An auth_password_account belongs to 1 auth_password_method.
+  An auth_password_account can have 0 or 1 auth_password_credential.
 
-/*
+  An auth_password_conf belongs to 1 auth_password_method.
+  An auth_password_conf can have 0 to many auth_password_credentials.
 
-                               ┌─────────────────┐
-                               │      host       │
-                               ├─────────────────┤
-                               │ public_id  (pk) │
-                               │ catalog_id (fk) │
-                               │                 │
-                               └─────────────────┘
-                                       ╲│╱
-                                        ○
-                                        │
-                                        ┼
-                                        ┼
-  ┌─────────────────┐          ┌─────────────────┐
-  │    iam_scope    │          │  host_catalog   │
-  ├─────────────────┤          ├─────────────────┤
-  │ public_id (pk)  │         ╱│ public_id (pk)  │
-  │                 │┼┼──────○─│ scope_id  (fk)  │
-  │                 │         ╲│                 │
-  └─────────────────┘          └─────────────────┘
-                                        ┼
-                                        ┼
-                                        │
-                                        ○
-                                       ╱│╲
-                               ┌─────────────────┐
-                               │    host_set     │
-                               ├─────────────────┤
-                               │ public_id  (pk) │
-                               │ catalog_id (fk) │
-                               │                 │
-                               └─────────────────┘
+  An auth_password_credential belongs to 1 auth_password_account.
+  An auth_password_credential belongs to 1 auth_password_conf.
 
 */
 
-  -- host_catalog
-  create table host_catalog (
+  create table auth_password_method (
     public_id wt_public_id
       primary key,
     scope_id wt_scope_id
+      not null,
+    password_conf_id wt_private_id, -- FK to auth_password_conf added below
+    name text,
+    description text,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    min_login_name_length int
       not null
-      references iam_scope (public_id)
+      default 3,
+    min_password_length int
+      not null
+      default 8,
+    version wt_version,
+    foreign key (scope_id, public_id)
+      references auth_method (scope_id, public_id)
       on delete cascade
       on update cascade,
-
-    -- The order of columns is important for performance. See:
-    -- https://dba.stackexchange.com/questions/58970/enforcing-constraints-two-tables-away/58972#58972
-    -- https://dba.stackexchange.com/questions/27481/is-a-composite-index-also-good-for-queries-on-the-first-field
+    unique(scope_id, name),
     unique(scope_id, public_id)
   );
 
-  create trigger immutable_columns before update on host_catalog
-    for each row execute procedure immutable_columns('public_id', 'scope_id');
-
-  -- insert_host_catalog_subtype() is a before insert trigger
-  -- function for subtypes of host_catalog
-  create or replace function insert_host_catalog_subtype()
-    returns trigger
-  as $$
-  begin
-    insert into host_catalog
-      (public_id, scope_id)
-    values
-      (new.public_id, new.scope_id);
-    return new;
-  end;
-  $$ language plpgsql;
+  create trigger
+    update_version_column
+  after update on auth_password_method
+    for each row execute procedure update_version_column();
 
-  -- delete_host_catalog_subtype() is an after delete trigger
-  -- function for subtypes of host_catalog
-  create or replace function delete_host_catalog_subtype()
-    returns trigger
-  as $$
-  begin
-    delete from host_catalog
-    where public_id = old.public_id;
-    return null; -- result is ignored since this is an after trigger
-  end;
-  $$ language plpgsql;
+  create trigger
+    insert_auth_method_subtype
+  before insert on auth_password_method
+    for each row execute procedure insert_auth_method_subtype();
 
-  -- host
-  create table host (
-    public_id wt_public_id primary key,
-    catalog_id wt_public_id not null
-      references host_catalog (public_id)
+  create table auth_password_account (
+    public_id wt_public_id
+      primary key,
+    auth_method_id wt_public_id
+      not null,
+    -- NOTE(mgaffney): The scope_id type is not wt_scope_id because the domain
+    -- check is executed before the insert trigger which retrieves the scope_id
+    -- causing an insert to fail.
+    scope_id text not null,
+    name text,
+    description text,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    login_name text not null
+      constraint login_name_must_be_lowercase
+      check(lower(trim(login_name)) = login_name)
+      constraint login_name_must_not_be_empty
+      check(length(trim(login_name)) > 0),
+    version wt_version,
+    foreign key (scope_id, auth_method_id)
+      references auth_password_method (scope_id, public_id)
       on delete cascade
       on update cascade,
-    unique(catalog_id, public_id)
+    foreign key (scope_id, auth_method_id, public_id)
+      references auth_account (scope_id, auth_method_id, public_id)
+      on delete cascade
+      on update cascade,
+    unique(auth_method_id, name),
+    unique(auth_method_id, login_name),
+    unique(auth_method_id, public_id)
   );
 
-  create trigger immutable_columns before update on host
-    for each row execute procedure immutable_columns('public_id', 'catalog_id');
+  create trigger
+    update_version_column
+  after update on auth_password_account
+    for each row execute procedure update_version_column();
 
-  -- insert_host_subtype() is a before insert trigger
-  -- function for subtypes of host
-  create or replace function insert_host_subtype()
-    returns trigger
-  as $$
-  begin
-    insert into host
-      (public_id, catalog_id)
-    values
-      (new.public_id, new.catalog_id);
-    return new;
-  end;
-  $$ language plpgsql;
+  create trigger
+    insert_auth_account_subtype
+  before insert on auth_password_account
+    for each row execute procedure insert_auth_account_subtype();
 
-  -- delete_host_subtype() is an after delete trigger
-  -- function for subtypes of host
-  create or replace function delete_host_subtype()
+  create table auth_password_conf (
+    private_id wt_private_id
+      primary key,
+    password_method_id wt_public_id
+      not null
+      references auth_password_method (public_id)
+      on delete cascade
+      on update cascade
+      deferrable initially deferred,
+    unique(password_method_id, private_id)
+  );
+
+  alter table auth_password_method
+    add constraint current_conf_fkey
+    foreign key (public_id, password_conf_id)
+    references auth_password_conf (password_method_id, private_id)
+    on delete cascade
+    on update cascade
+    deferrable initially deferred;
+
+  -- insert_auth_password_conf_subtype() is a trigger function for subtypes of
+  -- auth_password_conf
+  create or replace function
+    insert_auth_password_conf_subtype()
     returns trigger
   as $$
   begin
-    delete from host
-    where public_id = old.public_id;
-    return null; -- result is ignored since this is an after trigger
+    insert into auth_password_conf
+      (private_id, password_method_id)
+    values
+      (new.private_id, new.password_method_id);
+    return new;
   end;
   $$ language plpgsql;
 
-  -- host_set
-  create table host_set (
-    public_id wt_public_id primary key,
-    catalog_id wt_public_id not null
-      references host_catalog (public_id)
+  create table auth_password_credential (
+    private_id wt_private_id
+      primary key,
+    password_account_id wt_public_id
+      not null
+      unique,
+    password_conf_id wt_private_id,
+    password_method_id wt_public_id
+      not null,
+    foreign key (password_method_id, password_conf_id)
+      references auth_password_conf (password_method_id, private_id)
       on delete cascade
       on update cascade,
-    unique(catalog_id, public_id)
+    foreign key (password_method_id, password_account_id)
+      references auth_password_account (auth_method_id, public_id)
+      on delete cascade
+      on update cascade,
+    unique(password_method_id, password_conf_id, password_account_id)
   );
 
-  create trigger immutable_columns before update on host_set
-    for each row execute procedure immutable_columns('public_id', 'catalog_id');
-
-  -- insert_host_set_subtype() is a before insert trigger
-  -- function for subtypes of host_set
-  create or replace function insert_host_set_subtype()
+  -- insert_auth_password_credential_subtype() is a trigger function for
+  -- subtypes of auth_password_credential
+  create or replace function
+    insert_auth_password_credential_subtype()
     returns trigger
   as $$
   begin
-    insert into host_set
-      (public_id, catalog_id)
+
+    select auth_password_account.auth_method_id
+      into new.password_method_id
+    from auth_password_account
+    where auth_password_account.public_id = new.password_account_id;
+
+    insert into auth_password_credential
+      (private_id, password_account_id, password_conf_id, password_method_id)
     values
-      (new.public_id, new.catalog_id);
+      (new.private_id, new.password_account_id, new.password_conf_id, new.password_method_id);
     return new;
   end;
   $$ language plpgsql;
 
-  -- delete_host_set_subtype() is an after delete trigger
-  -- function for subtypes of host_set
-  create or replace function delete_host_set_subtype()
+  -- update_auth_password_credential_subtype() is an after update trigger
+  -- function for subtypes of auth_password_credential
+  create or replace function
+    update_auth_password_credential_subtype()
     returns trigger
   as $$
   begin
-    delete from host_set
-    where public_id = old.public_id;
+    /*
+      The configuration id of a credential is updated when a credential is
+      rehashed during authentication.
+    */
+    if new.password_conf_id is distinct from old.password_conf_id then
+      update auth_password_credential
+         set password_conf_id = new.password_conf_id
+       where private_id = new.private_id;
+    end if;
     return null; -- result is ignored since this is an after trigger
   end;
   $$ language plpgsql;
 
-  insert into oplog_ticket (name, version)
+  -- delete_auth_password_credential_subtype() is an after delete trigger
+  -- function for subtypes of auth_password_credential
+  create or replace function
+    delete_auth_password_credential_subtype()
+    returns trigger
+  as $$
+  begin
+    delete
+      from auth_password_credential
+     where private_id = old.private_id;
+    return null; -- result is ignored since this is an after trigger
+  end;
+  $$ language plpgsql;
+
+  --
+  -- triggers for time columns
+  --
+
+  create trigger
+    update_time_column
+  before
+  update on auth_password_method
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_password_method
+    for each row execute procedure immutable_columns('create_time');
+
+  create trigger
+    default_create_time_column
+  before
+  insert on auth_password_method
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before
+  update on auth_password_account
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_password_account
+    for each row execute procedure immutable_columns('create_time');
+
+  create trigger
+    default_create_time_column
+  before
+  insert on auth_password_account
+    for each row execute procedure default_create_time();
+
+  -- The tickets for oplog are the subtypes not the base types because no updates
+  -- are done to any values in the base types.
+  insert into oplog_ticket
+    (name, version)
   values
-    ('host_catalog', 1),
-    ('host', 1),
-    ('host_set', 1);
+    ('auth_password_method', 1),
+    ('auth_password_account', 1),
+    ('auth_password_credential', 1);
 
 commit;
 
 `),
 	},
-	"migrations/22_static_host.down.sql": {
-		name: "22_static_host.down.sql",
+	"migrations/13_auth_password_argon.down.sql": {
+		name: "13_auth_password_argon.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop table static_host_set_member cascade;
-  drop table static_host_set cascade;
-  drop table static_host cascade;
-  drop table static_host_catalog cascade;
-
-  delete
-    from oplog_ticket
-   where name in (
-          'static_host_catalog',
-          'static_host',
-          'static_host_set',
-          'static_host_set_member'
-        );
+  drop table auth_password_argon2_cred;
+  drop table auth_password_argon2_conf;
 
 commit;
 
 `),
 	},
-	"migrations/22_static_host.up.sql": {
-		name: "22_static_host.up.sql",
+	"migrations/13_auth_password_argon.up.sql": {
+		name: "13_auth_password_argon.up.sql",
 		bytes: []byte(`
 begin;
 
-/*
-
-  ┌─────────────────┐          ┌─────────────────────┐
-  │      host       │          │     static_host     │
-  ├─────────────────┤          ├─────────────────────┤
-  │ public_id  (pk) │          │ public_id  (pk)     │
-  │ catalog_id (fk) │┼┼──────○┼│ catalog_id (fk)     │┼┼─────────────────────┐
-  │                 │          │ address             │             ◀fk1      │
-  └─────────────────┘          └─────────────────────┘                       │
-          ╲│╱                            ╲│╱                                 │
-           ○                              ○                                  │
-           │                              │                                  │
-           ┼                              ┼                                  ○
-           ┼                              ┼                                 ╱│╲
-  ┌─────────────────┐          ┌─────────────────────┐          ┌────────────────────────┐
-  │  host_catalog   │          │ static_host_catalog │          │ static_host_set_member │
-  ├─────────────────┤          ├─────────────────────┤          ├────────────────────────┤
-  │ public_id (pk)  │          │ public_id (pk)      │          │ host_id    (pk,fk1)    │
-  │ scope_id  (fk)  │┼┼──────○┼│ scope_id  (fk)      │          │ set_id     (pk,fk2)    │
-  │                 │          │                     │          │ catalog_id (fk1,fk2)   │
-  └─────────────────┘          └─────────────────────┘          └────────────────────────┘
-           ┼                              ┼                                 ╲│╱
-           ┼                              ┼                                  ○
-           │                              │                                  │
-           ○                              ○                                  │
-          ╱│╲                            ╱│╲                                 │
-  ┌─────────────────┐          ┌─────────────────────┐                       │
-  │    host_set     │          │   static_host_set   │                       │
-  ├─────────────────┤          ├─────────────────────┤                       │
-  │ public_id  (pk) │          │ public_id  (pk)     │             ◀fk2      │
-  │ catalog_id (fk) │┼┼──────○┼│ catalog_id (fk)     │┼┼─────────────────────┘
-  │                 │          │                     │
-  └─────────────────┘          └─────────────────────┘
-
-*/
-
-  create table static_host_catalog (
-    public_id wt_public_id
-      primary key,
-    scope_id wt_scope_id
-      not null
-      references iam_scope (public_id)
+  create table auth_password_argon2_conf (
+    private_id wt_private_id primary key
+      references auth_password_conf (private_id)
       on delete cascade
       on update cascade,
-    name text,
-    description text,
+    password_method_id wt_public_id not null,
     create_time wt_timestamp,
-    update_time wt_timestamp,
-    version wt_version,
-    foreign key (scope_id, public_id)
-      references host_catalog (scope_id, public_id)
-      on delete cascade
-      on update cascade,
-    unique(scope_id, name)
-  );
-
-  create trigger update_version_column after update on static_host_catalog
-    for each row execute procedure update_version_column();
-
-  create trigger update_time_column before update on static_host_catalog
-    for each row execute procedure update_time_column();
-
-  create trigger default_create_time_column before insert on static_host_catalog
-    for each row execute procedure default_create_time();
-
-  create trigger immutable_columns before update on static_host_catalog
-    for each row execute procedure immutable_columns('public_id', 'scope_id','create_time');
-
-  create trigger insert_host_catalog_subtype before insert on static_host_catalog
-    for each row execute procedure insert_host_catalog_subtype();
-
-  create trigger delete_host_catalog_subtype after delete on static_host_catalog
-    for each row execute procedure delete_host_catalog_subtype();
-
-  create table static_host (
-    public_id wt_public_id primary key,
-    catalog_id wt_public_id not null
-      references static_host_catalog (public_id)
-      on delete cascade
-      on update cascade,
-    name text,
-    description text,
-    address text not null
-      constraint address_must_be_more_than_2_characters
-      check(length(trim(address)) > 2)
-      constraint address_must_be_less_than_256_characters
-      check(length(trim(address)) < 256),
-    create_time wt_timestamp,
-    update_time wt_timestamp,
-    version wt_version,
-    unique(catalog_id, name),
-
-    foreign key (catalog_id, public_id)
-      references host (catalog_id, public_id)
+    iterations int not null default 3
+      constraint iterations_must_be_greater_than_0
+      check(iterations > 0),
+    memory int not null default 65536
+      constraint memory_must_be_greater_than_0
+      check(memory > 0),
+    threads int not null default 1
+      constraint threads_must_be_greater_than_0
+      check(threads > 0),
+    -- salt_length unit is bytes
+    salt_length int not null default 32
+    -- minimum of 16 bytes (128 bits)
+      constraint salt_must_be_at_least_16_bytes
+      check(salt_length >= 16),
+    -- key_length unit is bytes
+    key_length int not null default 32
+    -- minimum of 16 bytes (128 bits)
+      constraint key_length_must_be_at_least_16_bytes
+      check(key_length >= 16),
+    unique(password_method_id, iterations, memory, threads, salt_length, key_length),
+    unique (password_method_id, private_id),
+    foreign key (password_method_id, private_id)
+      references auth_password_conf (password_method_id, private_id)
       on delete cascade
-      on update cascade,
-
-    -- The order of columns is important for performance. See:
-    -- https://dba.stackexchange.com/questions/58970/enforcing-constraints-two-tables-away/58972#58972
-    -- https://dba.stackexchange.com/questions/27481/is-a-composite-index-also-good-for-queries-on-the-first-field
-    unique(catalog_id, public_id)
+      on update cascade
+      deferrable initially deferred
   );
 
-  create trigger update_version_column after update on static_host
-    for each row execute procedure update_version_column();
-
-  create trigger update_time_column before update on static_host
-    for each row execute procedure update_time_column();
-
-  create trigger default_create_time_column before insert on static_host
-    for each row execute procedure default_create_time();
-
-  create trigger immutable_columns before update on static_host
-    for each row execute procedure immutable_columns('public_id', 'catalog_id','create_time');
+  create or replace function
+    read_only_auth_password_argon2_conf()
+    returns trigger
+  as $$
+  begin
+    raise exception 'auth_password_argon2_conf is read-only';
+  end;
+  $$ language plpgsql;
 
-  create trigger insert_host_subtype before insert on static_host
-    for each row execute procedure insert_host_subtype();
+  create trigger
+    read_only_auth_password_argon2_conf
+  before
+  update on auth_password_argon2_conf
+    for each row execute procedure read_only_auth_password_argon2_conf();
 
-  create trigger delete_host_subtype after delete on static_host
-    for each row execute procedure delete_host_subtype();
+  create trigger
+    insert_auth_password_conf_subtype
+  before insert on auth_password_argon2_conf
+    for each row execute procedure insert_auth_password_conf_subtype();
 
-  create table static_host_set (
-    public_id wt_public_id primary key,
-    catalog_id wt_public_id not null
-      references static_host_catalog (public_id)
+  create table auth_password_argon2_cred (
+    private_id wt_private_id primary key
+      references auth_password_credential (private_id)
       on delete cascade
       on update cascade,
-    name text,
-    description text,
+    password_account_id wt_public_id not null,
+    password_conf_id wt_private_id,
+    -- NOTE(mgaffney): The password_method_id type is not wt_public_id because
+    -- the domain check is executed before the insert trigger which retrieves
+    -- the password_method_id causing an insert to fail.
+    password_method_id text not null,
     create_time wt_timestamp,
     update_time wt_timestamp,
-    version wt_version,
-    unique(catalog_id, name),
-    foreign key (catalog_id, public_id)
-      references host_set (catalog_id, public_id)
+    salt bytea not null -- cannot be changed unless derived_key is changed too
+      constraint salt_must_not_be_empty
+      check(length(salt) > 0),
+    derived_key bytea not null
+      constraint derived_key_must_not_be_empty
+      check(length(derived_key) > 0),
+    -- TODO: Make key_id a foreign key once we have DEKs
+    key_id text not null
+      constraint key_id_must_not_be_empty
+      check(length(trim(key_id)) > 0),
+    foreign key (password_method_id, password_conf_id)
+      references auth_password_argon2_conf (password_method_id, private_id)
       on delete cascade
       on update cascade,
-    unique(catalog_id, public_id)
+    foreign key (password_method_id, password_conf_id, password_account_id)
+      references auth_password_credential (password_method_id, password_conf_id, password_account_id)
+      on delete cascade
+      on update cascade
+      deferrable initially deferred
   );
 
-  create trigger update_version_column after update on static_host_set
-    for each row execute procedure update_version_column();
-
-  create trigger update_time_column before update on static_host_set
-    for each row execute procedure update_time_column();
+  create trigger
+    insert_auth_password_credential_subtype
+  before insert on auth_password_argon2_cred
+    for each row execute procedure insert_auth_password_credential_subtype();
 
-  create trigger default_create_time_column before insert on static_host_set
-    for each row execute procedure default_create_time();
+  create trigger
+    update_auth_password_credential_subtype
+  after update on auth_password_argon2_cred
+    for each row execute procedure update_auth_password_credential_subtype();
 
-  create trigger immutable_columns before update on static_host_set
-    for each row execute procedure immutable_columns('public_id', 'catalog_id','create_time');
+  create trigger
+    delete_auth_password_credential_subtype
+  after delete on auth_password_argon2_cred
+    for each row execute procedure delete_auth_password_credential_subtype();
 
-  create trigger insert_host_set_subtype before insert on static_host_set
-    for each row execute procedure insert_host_set_subtype();
+  --
+  -- triggers for time columns
+  --
 
-  create trigger delete_host_set_subtype after delete on static_host_set
-    for each row execute procedure delete_host_set_subtype();
+  create trigger
+    immutable_columns
+  before
+  update on auth_password_argon2_conf
+    for each row execute procedure immutable_columns('create_time');
 
-  create table static_host_set_member (
-    host_id wt_public_id not null,
-    set_id wt_public_id not null,
-    catalog_id wt_public_id not null,
-    primary key(host_id, set_id),
-    foreign key (catalog_id, host_id) -- fk1
-      references static_host (catalog_id, public_id)
-      on delete cascade
-      on update cascade,
-    foreign key (catalog_id, set_id) -- fk2
-      references static_host_set (catalog_id, public_id)
-      on delete cascade
-      on update cascade
-  );
+  create trigger
+    default_create_time_column
+  before
+  insert on auth_password_argon2_conf
+    for each row execute procedure default_create_time();
 
-  create trigger immutable_columns before update on static_host_set_member
-    for each row execute procedure immutable_columns('host_id', 'set_id', 'catalog_id');
+  create trigger
+    update_time_column
+  before
+  update on auth_password_argon2_cred
+    for each row execute procedure update_time_column();
 
-  create or replace function insert_static_host_set_member()
-    returns trigger
-  as $$
-  begin
-    select static_host_set.catalog_id
-      into new.catalog_id
-    from static_host_set
-    where static_host_set.public_id = new.set_id;
-    return new;
-  end;
-  $$ language plpgsql;
+  create trigger
+    immutable_columns
+  before
+  update on auth_password_argon2_cred
+    for each row execute procedure immutable_columns('create_time');
 
-  create trigger insert_static_host_set_member before insert on static_host_set_member
-    for each row execute procedure insert_static_host_set_member();
+  create trigger
+    default_create_time_column
+  before
+  insert on auth_password_argon2_cred
+    for each row execute procedure default_create_time();
 
-  insert into oplog_ticket (name, version)
+  -- The tickets for oplog are the subtypes not the base types because no updates
+  -- are done to any values in the base types.
+  insert into oplog_ticket
+    (name, version)
   values
-    ('static_host_catalog', 1),
-    ('static_host', 1),
-    ('static_host_set', 1),
-    ('static_host_set_member', 1);
+    ('auth_password_argon2_conf', 1),
+    ('auth_password_argon2_cred', 1);
 
 commit;
 
 `),
 	},
-	"migrations/30_keys.down.sql": {
-		name: "30_keys.down.sql",
+	"migrations/14_auth_password_views.down.sql": {
+		name: "14_auth_password_views.down.sql",
 		bytes: []byte(`
 begin;
 
-drop function kms_version_column cascade;
+  drop view auth_password_current_conf;
+  drop view auth_password_conf_union;
 
 commit;
 
 `),
 	},
-	"migrations/30_keys.up.sql": {
-		name: "30_keys.up.sql",
+	"migrations/14_auth_password_views.up.sql": {
+		name: "14_auth_password_views.up.sql",
 		bytes: []byte(`
 begin;
 
--- kms_version_column() will increment the version column whenever row data
--- is inserted and should only be used in an before insert trigger.  This
--- function will overwrite any explicit values to the version column.
-create or replace function
-  kms_version_column()
-  returns trigger
-as $$
-declare 
-  _key_id text;
-  _max bigint; 
-begin
-  execute format('SELECT $1.%I', tg_argv[0]) into _key_id using new;
-  execute format('select max(version) + 1 from %I where %I = $1', tg_relid::regclass, tg_argv[0]) using _key_id into _max;
-  if _max is null then
-  	_max = 1;
-  end if;
-  new.version = _max;
-  return new;
-end;
-$$ language plpgsql;
+  -- auth_password_conf_union is a union of the configuration settings
+  -- of all supported key derivation functions.
+  -- It will be updated as new key derivation functions are supported.
+  create or replace view auth_password_conf_union as
+      -- Do not change the order of the columns when adding new configurations.
+      -- Union with new tables appending new columns as needed.
+      select c.password_method_id, c.private_id as password_conf_id, c.private_id,
+             'argon2' as conf_type,
+             c.iterations, c.memory, c.threads, c.salt_length, c.key_length
+        from auth_password_argon2_conf c;
+
+  -- auth_password_current_conf provides a view of the current password
+  -- configuration for each password auth method.
+  -- The view will be updated as new key derivation functions are supported
+  -- but the query to create the view should not need to be updated.
+  create or replace view auth_password_current_conf as
+      -- Rerun this query whenever auth_password_conf_union is updated.
+      select pm.min_login_name_length, pm.min_password_length, c.*
+        from auth_password_method pm
+  inner join auth_password_conf_union c
+          on pm.password_conf_id = c.password_conf_id;
+
+commit;
 
-comment on function
-  kms_version_column()
-is
-  'function used in before insert triggers to properly set version columns for kms_* tables with a version column';
-  
-  commit;
 `),
 	},
-	"migrations/31_keys.down.sql": {
-		name: "31_keys.down.sql",
+	"migrations/20_host.down.sql": {
+		name: "20_host.down.sql",
 		bytes: []byte(`
 begin;
 
-drop table kms_root_key cascade;
-drop table kms_root_key_version cascade;
-drop table kms_database_key cascade;
-drop table kms_database_key_version cascade;
-drop table kms_oplog_key cascade;
-drop table kms_oplog_key_version cascade;
-drop table kms_session_key cascade;
-drop table kms_session_key_version cascade;
+  drop table host_set;
+  drop table host;
+  drop table host_catalog;
+
+  drop function insert_host_set_subtype;
+  drop function insert_host_subtype;
+  drop function insert_host_catalog_subtype;
+
+  delete
+    from oplog_ticket
+   where name in (
+          'host_catalog',
+          'host',
+          'host_set'
+        );
 
 commit;
 
 `),
 	},
-	"migrations/31_keys.up.sql": {
-		name: "31_keys.up.sql",
+	"migrations/20_host.up.sql": {
+		name: "20_host.up.sql",
 		bytes: []byte(`
 begin;
 
 /*
-             ┌────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┐            
-             ├────────────────────────────────────────────────────────────────────────────────────────────┐                           ○            
-             ├────────────────────────────────────────────────────────────────┐                           ○                           ┼            
-             ├────────────────────────────────────┐                           ○                           ┼              ┌────────────────────────┐
-             │                                    ○                           ┼              ┌────────────────────────┐  │     kms_token_key      │
-             ┼                                    ┼              ┌────────────────────────┐  │    kms_session_key     │  ├────────────────────────┤
-┌────────────────────────┐           ┌────────────────────────┐  │     kms_oplog_key      │  ├────────────────────────┤  │private_id              │
-│      kms_root_key      │           │    kms_database_key    │  ├────────────────────────┤  │private_id              │  │root_key_id             │
-├────────────────────────┤           ├────────────────────────┤  │private_id              │  │root_key_id             │  │                        │
-│private_id              │           │private_id              │  │root_key_id             │  │                        │  │                        │
-│scope_id                │           │root_key_id             │  │                        │  │                        │  │                        │
-│                        │           │                        │  │                        │  │                        │  └────────────────────────┘
-└────────────────────────┘           └────────────────────────┘  └────────────────────────┘  └────────────────────────┘               ┼            
-             ┼                                    ┼                           ┼                           ┼                           │            
-             │                                    │                           │                           │                           │            
-             │                                    │                           │                           │                           │            
-             │                                    │                           │                           │                           │            
-             │                                    │                           │                           │                           │            
-             ┼                                    ┼                           ┼                           ┼                           │            
-            ╱│╲                                  ╱│╲                         ╱│╲                         ╱│╲                          ┼            
-┌────────────────────────┐           ┌────────────────────────┐  ┌────────────────────────┐  ┌────────────────────────┐              ╱│╲           
-│  kms_root_key_version  │           │kms_database_key_version│  │ kms_oplog_key_version  │  │kms_session_key_version │  ┌────────────────────────┐
-├────────────────────────┤           ├────────────────────────┤  ├────────────────────────┤  ├────────────────────────┤  │ kms_token_key_version  │
-│private_id              │           │private_id              │  │private_id              │  │private_id              │  ├────────────────────────┤
-│root_key_id             │           │database_key_id         │  │oplog_key_id            │  │session_key_id          │  │private_id              │
-│key                     │           │root_key_id             │  │root_key_id             │  │root_key_id             │  │token_key_id            │
-│version                 │           │key                     │  │key                     │  │key                     │  │root_key_id             │
-│                        │           │version                 │  │version                 │  │version                 │  │key                     │
-└────────────────────────┘           └────────────────────────┘  │                        │  │                        │  │version                 │
-             ┼                                    ┼              └────────────────────────┘  │                        │  │                        │
-             │                                    ○                           ┼              └────────────────────────┘  │                        │
-             ├────────────────────────────────────┘                           ○                           ┼              └────────────────────────┘
-             ├────────────────────────────────────────────────────────────────┘                           ○                           ┼            
-             ├────────────────────────────────────────────────────────────────────────────────────────────┘                           ○            
-             └────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┘
-*/
 
-create table kms_root_key (
-  private_id wt_private_id primary key,
-  scope_id wt_scope_id not null unique -- there can only be one root key for a scope.
-    references iam_scope(public_id) 
-    on delete cascade 
-    on update cascade,
-  create_time wt_timestamp
-);
+                               ┌─────────────────┐
+                               │      host       │
+                               ├─────────────────┤
+                               │ public_id  (pk) │
+                               │ catalog_id (fk) │
+                               │                 │
+                               └─────────────────┘
+                                       ╲│╱
+                                        ○
+                                        │
+                                        ┼
+                                        ┼
+  ┌─────────────────┐          ┌─────────────────┐
+  │    iam_scope    │          │  host_catalog   │
+  ├─────────────────┤          ├─────────────────┤
+  │ public_id (pk)  │         ╱│ public_id (pk)  │
+  │                 │┼┼──────○─│ scope_id  (fk)  │
+  │                 │         ╲│                 │
+  └─────────────────┘          └─────────────────┘
+                                        ┼
+                                        ┼
+                                        │
+                                        ○
+                                       ╱│╲
+                               ┌─────────────────┐
+                               │    host_set     │
+                               ├─────────────────┤
+                               │ public_id  (pk) │
+                               │ catalog_id (fk) │
+                               │                 │
+                               └─────────────────┘
 
- -- define the immutable fields for kms_root_key (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_root_key
-  for each row execute procedure immutable_columns('private_id', 'scope_id', 'create_time');
+*/
 
-create trigger 
-  default_create_time_column
-before
-insert on kms_root_key
-  for each row execute procedure default_create_time();
+  -- host_catalog
+  create table host_catalog (
+    public_id wt_public_id
+      primary key,
+    scope_id wt_scope_id
+      not null
+      references iam_scope (public_id)
+      on delete cascade
+      on update cascade,
 
-create table kms_root_key_version (
-  private_id wt_private_id primary key,
-  root_key_id  wt_private_id not null
-    references kms_root_key(private_id) 
-    on delete cascade 
-    on update cascade,
-  version wt_version,
-  key bytea not null,
-  create_time wt_timestamp,
-  unique(root_key_id, version)
-);
+    -- The order of columns is important for performance. See:
+    -- https://dba.stackexchange.com/questions/58970/enforcing-constraints-two-tables-away/58972#58972
+    -- https://dba.stackexchange.com/questions/27481/is-a-composite-index-also-good-for-queries-on-the-first-field
+    unique(scope_id, public_id)
+  );
 
- -- define the immutable fields for kms_root_key_version (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_root_key_version
-  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'version', 'key', 'create_time');
+  create trigger immutable_columns before update on host_catalog
+    for each row execute procedure immutable_columns('public_id', 'scope_id');
 
-create trigger 
-  default_create_time_column
-before
-insert on kms_root_key_version
-  for each row execute procedure default_create_time();
+  -- insert_host_catalog_subtype() is a before insert trigger
+  -- function for subtypes of host_catalog
+  create or replace function insert_host_catalog_subtype()
+    returns trigger
+  as $$
+  begin
+    insert into host_catalog
+      (public_id, scope_id)
+    values
+      (new.public_id, new.scope_id);
+    return new;
+  end;
+  $$ language plpgsql;
 
+  -- delete_host_catalog_subtype() is an after delete trigger
+  -- function for subtypes of host_catalog
+  create or replace function delete_host_catalog_subtype()
+    returns trigger
+  as $$
+  begin
+    delete from host_catalog
+    where public_id = old.public_id;
+    return null; -- result is ignored since this is an after trigger
+  end;
+  $$ language plpgsql;
 
-create trigger
-	kms_version_column
-before insert on kms_root_key_version
-	for each row execute procedure kms_version_column('root_key_id');
+  -- host
+  create table host (
+    public_id wt_public_id primary key,
+    catalog_id wt_public_id not null
+      references host_catalog (public_id)
+      on delete cascade
+      on update cascade,
+    unique(catalog_id, public_id)
+  );
 
-create table kms_database_key (
-  private_id wt_private_id primary key,
-  root_key_id wt_private_id not null unique -- there can be only one database dek per root key
-    references kms_root_key(private_id)
-    on delete cascade
-    on update cascade,
-  create_time wt_timestamp
-);
+  create trigger immutable_columns before update on host
+    for each row execute procedure immutable_columns('public_id', 'catalog_id');
 
- -- define the immutable fields for kms_database_key (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_database_key
-  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+  -- insert_host_subtype() is a before insert trigger
+  -- function for subtypes of host
+  create or replace function insert_host_subtype()
+    returns trigger
+  as $$
+  begin
+    insert into host
+      (public_id, catalog_id)
+    values
+      (new.public_id, new.catalog_id);
+    return new;
+  end;
+  $$ language plpgsql;
 
-create trigger 
-  default_create_time_column
-before
-insert on kms_database_key
+  -- delete_host_subtype() is an after delete trigger
+  -- function for subtypes of host
+  create or replace function delete_host_subtype()
+    returns trigger
+  as $$
+  begin
+    delete from host
+    where public_id = old.public_id;
+    return null; -- result is ignored since this is an after trigger
+  end;
+  $$ language plpgsql;
+
+  -- host_set
+  create table host_set (
+    public_id wt_public_id primary key,
+    catalog_id wt_public_id not null
+      references host_catalog (public_id)
+      on delete cascade
+      on update cascade,
+    unique(catalog_id, public_id)
+  );
+
+  create trigger immutable_columns before update on host_set
+    for each row execute procedure immutable_columns('public_id', 'catalog_id');
+
+  -- insert_host_set_subtype() is a before insert trigger
+  -- function for subtypes of host_set
+  create or replace function insert_host_set_subtype()
+    returns trigger
+  as $$
+  begin
+    insert into host_set
+      (public_id, catalog_id)
+    values
+      (new.public_id, new.catalog_id);
+    return new;
+  end;
+  $$ language plpgsql;
+
+  -- delete_host_set_subtype() is an after delete trigger
+  -- function for subtypes of host_set
+  create or replace function delete_host_set_subtype()
+    returns trigger
+  as $$
+  begin
+    delete from host_set
+    where public_id = old.public_id;
+    return null; -- result is ignored since this is an after trigger
+  end;
+  $$ language plpgsql;
+
+  insert into oplog_ticket (name, version)
+  values
+    ('host_catalog', 1),
+    ('host', 1),
+    ('host_set', 1);
+
+commit;
+
+`),
+	},
+	"migrations/22_static_host.down.sql": {
+		name: "22_static_host.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table static_host_set_member cascade;
+  drop table static_host_set cascade;
+  drop table static_host cascade;
+  drop table static_host_catalog cascade;
+
+  delete
+    from oplog_ticket
+   where name in (
+          'static_host_catalog',
+          'static_host',
+          'static_host_set',
+          'static_host_set_member'
+        );
+
+commit;
+
+`),
+	},
+	"migrations/22_static_host.up.sql": {
+		name: "22_static_host.up.sql",
+		bytes: []byte(`
+begin;
+
+/*
+
+  ┌─────────────────┐          ┌─────────────────────┐
+  │      host       │          │     static_host     │
+  ├─────────────────┤          ├─────────────────────┤
+  │ public_id  (pk) │          │ public_id  (pk)     │
+  │ catalog_id (fk) │┼┼──────○┼│ catalog_id (fk)     │┼┼─────────────────────┐
+  │                 │          │ address             │             ◀fk1      │
+  └─────────────────┘          └─────────────────────┘                       │
+          ╲│╱                            ╲│╱                                 │
+           ○                              ○                                  │
+           │                              │                                  │
+           ┼                              ┼                                  ○
+           ┼                              ┼                                 ╱│╲
+  ┌─────────────────┐          ┌─────────────────────┐          ┌────────────────────────┐
+  │  host_catalog   │          │ static_host_catalog │          │ static_host_set_member │
+  ├─────────────────┤          ├─────────────────────┤          ├────────────────────────┤
+  │ public_id (pk)  │          │ public_id (pk)      │          │ host_id    (pk,fk1)    │
+  │ scope_id  (fk)  │┼┼──────○┼│ scope_id  (fk)      │          │ set_id     (pk,fk2)    │
+  │                 │          │                     │          │ catalog_id (fk1,fk2)   │
+  └─────────────────┘          └─────────────────────┘          └────────────────────────┘
+           ┼                              ┼                                 ╲│╱
+           ┼                              ┼                                  ○
+           │                              │                                  │
+           ○                              ○                                  │
+          ╱│╲                            ╱│╲                                 │
+  ┌─────────────────┐          ┌─────────────────────┐                       │
+  │    host_set     │          │   static_host_set   │                       │
+  ├─────────────────┤          ├─────────────────────┤                       │
+  │ public_id  (pk) │          │ public_id  (pk)     │             ◀fk2      │
+  │ catalog_id (fk) │┼┼──────○┼│ catalog_id (fk)     │┼┼─────────────────────┘
+  │                 │          │                     │
+  └─────────────────┘          └─────────────────────┘
+
+*/
+
+  create table static_host_catalog (
+    public_id wt_public_id
+      primary key,
+    scope_id wt_scope_id
+      not null
+      references iam_scope (public_id)
+      on delete cascade
+      on update cascade,
+    name text,
+    description text,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    version wt_version,
+    foreign key (scope_id, public_id)
+      references host_catalog (scope_id, public_id)
+      on delete cascade
+      on update cascade,
+    unique(scope_id, name)
+  );
+
+  create trigger update_version_column after update on static_host_catalog
+    for each row execute procedure update_version_column();
+
+  create trigger update_time_column before update on static_host_catalog
+    for each row execute procedure update_time_column();
+
+  create trigger default_create_time_column before insert on static_host_catalog
+    for each row execute procedure default_create_time();
+
+  create trigger immutable_columns before update on static_host_catalog
+    for each row execute procedure immutable_columns('public_id', 'scope_id','create_time');
+
+  create trigger insert_host_catalog_subtype before insert on static_host_catalog
+    for each row execute procedure insert_host_catalog_subtype();
+
+  create trigger delete_host_catalog_subtype after delete on static_host_catalog
+    for each row execute procedure delete_host_catalog_subtype();
+
+  create table static_host (
+    public_id wt_public_id primary key,
+    catalog_id wt_public_id not null
+      references static_host_catalog (public_id)
+      on delete cascade
+      on update cascade,
+    name text,
+    description text,
+    address text not null
+      constraint address_must_be_more_than_2_characters
+      check(length(trim(address)) > 2)
+      constraint address_must_be_less_than_256_characters
+      check(length(trim(address)) < 256),
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    version wt_version,
+    unique(catalog_id, name),
+
+    foreign key (catalog_id, public_id)
+      references host (catalog_id, public_id)
+      on delete cascade
+      on update cascade,
+
+    -- The order of columns is important for performance. See:
+    -- https://dba.stackexchange.com/questions/58970/enforcing-constraints-two-tables-away/58972#58972
+    -- https://dba.stackexchange.com/questions/27481/is-a-composite-index-also-good-for-queries-on-the-first-field
+    unique(catalog_id, public_id)
+  );
+
+  create trigger update_version_column after update on static_host
+    for each row execute procedure update_version_column();
+
+  create trigger update_time_column before update on static_host
+    for each row execute procedure update_time_column();
+
+  create trigger default_create_time_column before insert on static_host
+    for each row execute procedure default_create_time();
+
+  create trigger immutable_columns before update on static_host
+    for each row execute procedure immutable_columns('public_id', 'catalog_id','create_time');
+
+  create trigger insert_host_subtype before insert on static_host
+    for each row execute procedure insert_host_subtype();
+
+  create trigger delete_host_subtype after delete on static_host
+    for each row execute procedure delete_host_subtype();
+
+  create table static_host_set (
+    public_id wt_public_id primary key,
+    catalog_id wt_public_id not null
+      references static_host_catalog (public_id)
+      on delete cascade
+      on update cascade,
+    name text,
+    description text,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    version wt_version,
+    unique(catalog_id, name),
+    foreign key (catalog_id, public_id)
+      references host_set (catalog_id, public_id)
+      on delete cascade
+      on update cascade,
+    unique(catalog_id, public_id)
+  );
+
+  create trigger update_version_column after update on static_host_set
+    for each row execute procedure update_version_column();
+
+  create trigger update_time_column before update on static_host_set
+    for each row execute procedure update_time_column();
+
+  create trigger default_create_time_column before insert on static_host_set
+    for each row execute procedure default_create_time();
+
+  create trigger immutable_columns before update on static_host_set
+    for each row execute procedure immutable_columns('public_id', 'catalog_id','create_time');
+
+  create trigger insert_host_set_subtype before insert on static_host_set
+    for each row execute procedure insert_host_set_subtype();
+
+  create trigger delete_host_set_subtype after delete on static_host_set
+    for each row execute procedure delete_host_set_subtype();
+
+  create table static_host_set_member (
+    host_id wt_public_id not null,
+    set_id wt_public_id not null,
+    catalog_id wt_public_id not null,
+    primary key(host_id, set_id),
+    foreign key (catalog_id, host_id) -- fk1
+      references static_host (catalog_id, public_id)
+      on delete cascade
+      on update cascade,
+    foreign key (catalog_id, set_id) -- fk2
+      references static_host_set (catalog_id, public_id)
+      on delete cascade
+      on update cascade
+  );
+
+  create trigger immutable_columns before update on static_host_set_member
+    for each row execute procedure immutable_columns('host_id', 'set_id', 'catalog_id');
+
+  create or replace function insert_static_host_set_member()
+    returns trigger
+  as $$
+  begin
+    select static_host_set.catalog_id
+      into new.catalog_id
+    from static_host_set
+    where static_host_set.public_id = new.set_id;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger insert_static_host_set_member before insert on static_host_set_member
+    for each row execute procedure insert_static_host_set_member();
+
+  insert into oplog_ticket (name, version)
+  values
+    ('static_host_catalog', 1),
+    ('static_host', 1),
+    ('static_host_set', 1),
+    ('static_host_set_member', 1);
+
+commit;
+
+`),
+	},
+	"migrations/30_keys.down.sql": {
+		name: "30_keys.down.sql",
+		bytes: []byte(`
+begin;
+
+drop function kms_version_column cascade;
+
+commit;
+
+`),
+	},
+	"migrations/30_keys.up.sql": {
+		name: "30_keys.up.sql",
+		bytes: []byte(`
+begin;
+
+-- kms_version_column() will increment the version column whenever row data
+-- is inserted and should only be used in an before insert trigger.  This
+-- function will overwrite any explicit values to the version column.
+create or replace function
+  kms_version_column()
+  returns trigger
+as $$
+declare 
+  _key_id text;
+  _max bigint; 
+begin
+  execute format('SELECT $1.%I', tg_argv[0]) into _key_id using new;
+  execute format('select max(version) + 1 from %I where %I = $1', tg_relid::regclass, tg_argv[0]) using _key_id into _max;
+  if _max is null then
+  	_max = 1;
+  end if;
+  new.version = _max;
+  return new;
+end;
+$$ language plpgsql;
+
+comment on function
+  kms_version_column()
+is
+  'function used in before insert triggers to properly set version columns for kms_* tables with a version column';
+  
+  commit;
+`),
+	},
+	"migrations/31_keys.down.sql": {
+		name: "31_keys.down.sql",
+		bytes: []byte(`
+begin;
+
+drop table kms_root_key cascade;
+drop table kms_root_key_version cascade;
+drop table kms_database_key cascade;
+drop table kms_database_key_version cascade;
+drop table kms_oplog_key cascade;
+drop table kms_oplog_key_version cascade;
+drop table kms_session_key cascade;
+drop table kms_session_key_version cascade;
+
+commit;
+
+`),
+	},
+	"migrations/31_keys.up.sql": {
+		name: "31_keys.up.sql",
+		bytes: []byte(`
+begin;
+
+/*
+             ┌────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┐            
+             ├────────────────────────────────────────────────────────────────────────────────────────────┐                           ○            
+             ├────────────────────────────────────────────────────────────────┐                           ○                           ┼            
+             ├────────────────────────────────────┐                           ○                           ┼              ┌────────────────────────┐
+             │                                    ○                           ┼              ┌────────────────────────┐  │     kms_token_key      │
+             ┼                                    ┼              ┌────────────────────────┐  │    kms_session_key     │  ├────────────────────────┤
+┌────────────────────────┐           ┌────────────────────────┐  │     kms_oplog_key      │  ├────────────────────────┤  │private_id              │
+│      kms_root_key      │           │    kms_database_key    │  ├────────────────────────┤  │private_id              │  │root_key_id             │
+├────────────────────────┤           ├────────────────────────┤  │private_id              │  │root_key_id             │  │                        │
+│private_id              │           │private_id              │  │root_key_id             │  │                        │  │                        │
+│scope_id                │           │root_key_id             │  │                        │  │                        │  │                        │
+│                        │           │                        │  │                        │  │                        │  └────────────────────────┘
+└────────────────────────┘           └────────────────────────┘  └────────────────────────┘  └────────────────────────┘               ┼            
+             ┼                                    ┼                           ┼                           ┼                           │            
+             │                                    │                           │                           │                           │            
+             │                                    │                           │                           │                           │            
+             │                                    │                           │                           │                           │            
+             │                                    │                           │                           │                           │            
+             ┼                                    ┼                           ┼                           ┼                           │            
+            ╱│╲                                  ╱│╲                         ╱│╲                         ╱│╲                          ┼            
+┌────────────────────────┐           ┌────────────────────────┐  ┌────────────────────────┐  ┌────────────────────────┐              ╱│╲           
+│  kms_root_key_version  │           │kms_database_key_version│  │ kms_oplog_key_version  │  │kms_session_key_version │  ┌────────────────────────┐
+├────────────────────────┤           ├────────────────────────┤  ├────────────────────────┤  ├────────────────────────┤  │ kms_token_key_version  │
+│private_id              │           │private_id              │  │private_id              │  │private_id              │  ├────────────────────────┤
+│root_key_id             │           │database_key_id         │  │oplog_key_id            │  │session_key_id          │  │private_id              │
+│key                     │           │root_key_id             │  │root_key_id             │  │root_key_id             │  │token_key_id            │
+│version                 │           │key                     │  │key                     │  │key                     │  │root_key_id             │
+│                        │           │version                 │  │version                 │  │version                 │  │key                     │
+└────────────────────────┘           └────────────────────────┘  │                        │  │                        │  │version                 │
+             ┼                                    ┼              └────────────────────────┘  │                        │  │                        │
+             │                                    ○                           ┼              └────────────────────────┘  │                        │
+             ├────────────────────────────────────┘                           ○                           ┼              └────────────────────────┘
+             ├────────────────────────────────────────────────────────────────┘                           ○                           ┼            
+             ├────────────────────────────────────────────────────────────────────────────────────────────┘                           ○            
+             └────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┘
+*/
+
+create table kms_root_key (
+  private_id wt_private_id primary key,
+  scope_id wt_scope_id not null unique -- there can only be one root key for a scope.
+    references iam_scope(public_id) 
+    on delete cascade 
+    on update cascade,
+  create_time wt_timestamp
+);
+
+ -- define the immutable fields for kms_root_key (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_root_key
+  for each row execute procedure immutable_columns('private_id', 'scope_id', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on kms_root_key
+  for each row execute procedure default_create_time();
+
+create table kms_root_key_version (
+  private_id wt_private_id primary key,
+  root_key_id  wt_private_id not null
+    references kms_root_key(private_id) 
+    on delete cascade 
+    on update cascade,
+  version wt_version,
+  key bytea not null,
+  create_time wt_timestamp,
+  unique(root_key_id, version)
+);
+
+ -- define the immutable fields for kms_root_key_version (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_root_key_version
+  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'version', 'key', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on kms_root_key_version
+  for each row execute procedure default_create_time();
+
+
+create trigger
+	kms_version_column
+before insert on kms_root_key_version
+	for each row execute procedure kms_version_column('root_key_id');
+
+create table kms_database_key (
+  private_id wt_private_id primary key,
+  root_key_id wt_private_id not null unique -- there can be only one database dek per root key
+    references kms_root_key(private_id)
+    on delete cascade
+    on update cascade,
+  create_time wt_timestamp
+);
+
+ -- define the immutable fields for kms_database_key (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_database_key
+  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on kms_database_key
+  for each row execute procedure default_create_time();
+
+create table kms_database_key_version (
+  private_id wt_private_id primary key,
+  database_key_id wt_private_id not null
+    references kms_database_key(private_id) 
+    on delete cascade 
+    on update cascade, 
+  root_key_version_id wt_private_id not null
+    references kms_root_key_version(private_id) 
+    on delete cascade 
+    on update cascade,
+  version wt_version,
+  key bytea not null,
+  create_time wt_timestamp,
+  unique(database_key_id, version)
+);
+
+ -- define the immutable fields for kms_database_key_version (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_database_key_version
+  for each row execute procedure immutable_columns('private_id', 'database_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
+  
+create trigger 
+  default_create_time_column
+before
+insert on kms_database_key_version
+  for each row execute procedure default_create_time();
+
+create trigger
+	kms_version_column
+before insert on kms_database_key_version
+	for each row execute procedure kms_version_column('database_key_id');
+
+create table kms_oplog_key (
+  private_id wt_private_id primary key,
+  root_key_id wt_private_id not null unique -- there can be only one oplog dek per root key
+    references kms_root_key(private_id)
+    on delete cascade
+    on update cascade,
+  create_time wt_timestamp
+);
+
+ -- define the immutable fields for kms_oplog_key (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_oplog_key
+  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on kms_oplog_key
+  for each row execute procedure default_create_time();
+
+create table kms_oplog_key_version (
+  private_id wt_private_id primary key,
+  oplog_key_id wt_private_id not null
+    references kms_oplog_key(private_id) 
+    on delete cascade 
+    on update cascade, 
+  root_key_version_id wt_private_id not null
+    references kms_root_key_version(private_id) 
+    on delete cascade 
+    on update cascade,
+  version wt_version,
+  key bytea not null,
+  create_time wt_timestamp,
+  unique(oplog_key_id, version)
+);
+
+ -- define the immutable fields for kms_oplog_key_version (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_oplog_key_version
+  for each row execute procedure immutable_columns('private_id', 'oplog_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
+  
+create trigger 
+  default_create_time_column
+before
+insert on kms_oplog_key_version
+  for each row execute procedure default_create_time();
+
+create trigger
+	kms_version_column
+before insert on kms_oplog_key_version
+	for each row execute procedure kms_version_column('oplog_key_id');
+
+create table kms_session_key (
+  private_id wt_private_id primary key,
+  root_key_id wt_private_id not null unique -- there can be only one session dek per root key
+    references kms_root_key(private_id)
+    on delete cascade
+    on update cascade,
+  create_time wt_timestamp
+);
+
+ -- define the immutable fields for kms_oplog_key (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_session_key
+  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on kms_session_key
+  for each row execute procedure default_create_time();
+
+create table kms_session_key_version (
+  private_id wt_private_id primary key,
+  session_key_id wt_private_id not null
+    references kms_session_key(private_id) 
+    on delete cascade 
+    on update cascade, 
+  root_key_version_id wt_private_id not null
+    references kms_root_key_version(private_id) 
+    on delete cascade 
+    on update cascade,
+  version wt_version,
+  key bytea not null,
+  create_time wt_timestamp,
+  unique(session_key_id, version)
+);
+
+
+ -- define the immutable fields for kms_session_key_version (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_session_key_version
+  for each row execute procedure immutable_columns('private_id', 'session_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
+  
+create trigger 
+  default_create_time_column
+before
+insert on kms_session_key_version
+  for each row execute procedure default_create_time();
+
+create trigger
+	kms_version_column
+before insert on kms_session_key_version
+	for each row execute procedure kms_version_column('session_key_id');
+
+create table kms_token_key (
+  private_id wt_private_id primary key,
+  root_key_id wt_private_id not null unique -- there can be only one token dek per root key
+    references kms_root_key(private_id)
+    on delete cascade
+    on update cascade,
+  create_time wt_timestamp
+);
+
+ -- define the immutable fields for kms_token_key (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_token_key
+  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on kms_token_key
+  for each row execute procedure default_create_time();
+
+create table kms_token_key_version (
+  private_id wt_private_id primary key,
+  token_key_id wt_private_id not null
+    references kms_token_key(private_id) 
+    on delete cascade 
+    on update cascade, 
+  root_key_version_id wt_private_id not null
+    references kms_root_key_version(private_id) 
+    on delete cascade 
+    on update cascade,
+  version wt_version,
+  key bytea not null,
+  create_time wt_timestamp,
+  unique(token_key_id, version)
+);
+
+ -- define the immutable fields for kms_token_key_version (all of them)
+create trigger 
+  immutable_columns
+before
+update on kms_token_key_version
+  for each row execute procedure immutable_columns('private_id', 'token_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
+  
+create trigger 
+  default_create_time_column
+before
+insert on kms_token_key_version
+  for each row execute procedure default_create_time();
+
+create trigger
+	kms_version_column
+before insert on kms_token_key_version
+	for each row execute procedure kms_version_column('token_key_id');
+
+  insert into oplog_ticket
+    (name, version)
+  values
+    ('kms_root_key', 1),
+    ('kms_root_key_version', 1);
+    
+commit;
+`),
+	},
+	"migrations/40_targets.down.sql": {
+		name: "40_targets.down.sql",
+		bytes: []byte(`
+begin;
+
+drop function insert_target_subtype;
+drop function delete_target_subtype;
+drop function target_scope_valid;
+drop function target_host_set_scope_valid
+
+commit;
+
+`),
+	},
+	"migrations/40_targets.up.sql": {
+		name: "40_targets.up.sql",
+		bytes: []byte(`
+begin;
+
+
+-- insert_target_subtype() is a before insert trigger
+-- function for subtypes of target
+create or replace function
+  insert_target_subtype()
+  returns trigger
+as $$
+begin
+  insert into target
+    (public_id, scope_id)
+  values
+    (new.public_id, new.scope_id);
+  return new;
+end;
+$$ language plpgsql;
+
+-- delete_target_subtype() is an after delete trigger
+-- function for subtypes of host
+create or replace function delete_target_subtype()
+  returns trigger
+as $$
+begin
+  delete from target
+  where 
+    public_id = old.public_id;
+  return null; -- result is ignored since this is an after trigger
+end;
+$$ language plpgsql;
+
+-- target_scope_valid() is a before insert trigger function for target
+create or replace function
+  target_scope_valid()
+  returns trigger
+as $$
+declare scope_type text;
+begin
+  -- Fetch the type of scope
+  select isc.type from iam_scope isc where isc.public_id = new.scope_id into scope_type;
+  if scope_type = 'project' then
+    return new;
+  end if;
+  raise exception 'invalid target scope type % (must be project)', scope_type;
+end;
+$$ language plpgsql;
+
+-- target_host_set_scope_valid() is a before insert trigger function for target_host_set 
+create or replace function
+  target_host_set_scope_valid()
+  returns trigger
+as $$
+begin
+    perform from
+      host_catalog hc,
+      host_set hs,
+      target t,
+      iam_scope s
+    where
+      hc.public_id = hs.catalog_id and 
+      hc.scope_id = t.scope_id and
+      t.public_id = new.target_id;
+if not found then
+  raise exception 'target scope and host set scope are not equal';
+end if;
+return new;
+end;
+$$ language plpgsql;
+
+commit;
+`),
+	},
+	"migrations/41_targets.down.sql": {
+		name: "41_targets.down.sql",
+		bytes: []byte(`
+begin;
+
+drop table target cascade;
+drop table target_host_set cascade;
+drop table target_tcp;
+drop view target_all_subtypes;
+drop view target_host_set_catalog;
+
+
+delete
+from oplog_ticket
+where name in (
+        'target_tcp'
+    );
+
+commit;
+`),
+	},
+	"migrations/41_targets.up.sql": {
+		name: "41_targets.up.sql",
+		bytes: []byte(`
+/*
+                                   ┌─────────────────┐
+┌─────────────────┐                │   target_tcp    │
+│     target      │                ├─────────────────┤
+├─────────────────┤                │public_id        │
+│public_id        │┼─────────────○┼│scope_id         │
+│scope_id         │                │default_port     │
+│                 │                │name (not null)  │
+└─────────────────┘                │description      │
+         ┼                         └─────────────────┘
+         │                                            
+         ○                                            
+        ╱│╲                                           
+┌─────────────────┐                                   
+│ target_host_set │                                   
+├─────────────────┤                                   
+│target_id        │                                   
+│host_set_id      │                                   
+│                 │                                   
+└─────────────────┘                                   
+        ╲│╱                                           
+         ○                                            
+         │                                            
+         │                                            
+         ┼                                            
+┌─────────────────┐                                   
+│    host_set     │                                   
+├─────────────────┤                                   
+│public_id        │                                   
+│catalog_id       │                                   
+│                 │                                   
+└─────────────────┘                                                                            
+
+*/
+
+begin;
+
+create table target (
+  public_id wt_public_id primary key,
+  scope_id wt_scope_id not null 
+    references iam_scope(public_id) 
+    on delete cascade 
+    on update cascade,
+  create_time wt_timestamp
+);
+
+create trigger 
+  immutable_columns
+before
+update on target
+  for each row execute procedure immutable_columns('public_id', 'scope_id', 'create_time');
+
+create trigger 
+  default_create_time_column
+before
+insert on target
+  for each row execute procedure default_create_time();
+
+create trigger 
+  target_scope_valid
+before insert on target
+  for each row execute procedure target_scope_valid();
+
+
+create table target_host_set(
+  target_id wt_public_id
+    references target(public_id)
+    on delete cascade
+    on update cascade,
+  host_set_id wt_public_id
+    references host_set(public_id)
+    on delete cascade
+    on update cascade,
+  primary key(target_id, host_set_id),
+  create_time wt_timestamp
+);
+
+create trigger 
+  immutable_columns
+before
+update on target_host_set
+  for each row execute procedure immutable_columns('target_id', 'host_set_id', 'create_time');
+
+create trigger 
+  target_host_set_scope_valid
+before
+insert on target_host_set
+  for each row execute procedure target_host_set_scope_valid();
+
+create table target_tcp (
+  public_id wt_public_id primary key
+    references target(public_id)
+    on delete cascade
+    on update cascade,
+  scope_id wt_scope_id not null 
+    references iam_scope(public_id) 
+    on delete cascade 
+    on update cascade,
+  name text not null, -- name is not optional for a target subtype
+  description text,
+  default_port int, -- default_port can be null
+   -- max duration of the session in seconds.
+   -- default is 8 hours
+  session_max_seconds int not null default 28800
+    constraint session_max_seconds_must_be_greater_than_0
+    check(session_max_seconds > 0),
+  -- limit on number of session connections allowed. -1 equals no limit
+  session_connection_limit int not null default 1
+    constraint session_connection_limit_must_be_greater_than_0_or_negative_1
+    check(session_connection_limit > 0 or session_connection_limit = -1),
+  create_time wt_timestamp,
+  update_time wt_timestamp,
+  version wt_version,
+  unique(scope_id, name) -- name must be unique within a scope
+);
+
+create trigger
+  insert_target_subtype
+before insert on target_tcp
+  for each row execute procedure insert_target_subtype();
+
+create trigger
+  delete_target_subtype
+after delete on target_tcp
+  for each row execute procedure delete_target_subtype();
+
+ -- define the immutable fields for target 
+create trigger 
+  immutable_columns
+before
+update on target_tcp
+  for each row execute procedure immutable_columns('public_id', 'scope_id', 'create_time');
+
+create trigger
+  update_version_column
+after update on target_tcp
+  for each row execute procedure update_version_column();
+
+create trigger
+  update_time_column
+before update on target_tcp
+  for each row execute procedure update_time_column();
+
+create trigger 
+  default_create_time_column
+before
+insert on target_tcp
   for each row execute procedure default_create_time();
 
-create table kms_database_key_version (
-  private_id wt_private_id primary key,
-  database_key_id wt_private_id not null
-    references kms_database_key(private_id) 
-    on delete cascade 
-    on update cascade, 
-  root_key_version_id wt_private_id not null
-    references kms_root_key_version(private_id) 
-    on delete cascade 
-    on update cascade,
-  version wt_version,
-  key bytea not null,
-  create_time wt_timestamp,
-  unique(database_key_id, version)
-);
+create trigger 
+  target_scope_valid
+before insert on target_tcp
+  for each row execute procedure target_scope_valid();
+
+
+-- target_all_subtypes is a union of all target subtypes 
+create view target_all_subtypes
+as 
+select 
+  public_id, 
+  scope_id, 
+  name, 
+  description, 
+  default_port, 
+  session_max_seconds,
+  session_connection_limit,
+  version, 
+  create_time,
+  update_time,
+  'tcp' as type
+  from target_tcp;
+
+create view target_set
+as
+select 
+  hs.public_id,
+  hs.catalog_id,
+  ths.target_id
+from
+  target_host_set ths,
+  host_set hs
+where
+  hs.public_id = ths.host_set_id;
+
+insert into oplog_ticket
+  (name, version)
+values
+  ('target_tcp', 1);
+
+commit;
+
+`),
+	},
+	"migrations/50_session.down.sql": {
+		name: "50_session.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table session_state;
+  drop table session_state_enm;
+  drop table session;
+  drop table session_termination_reason_enm;
+  drop function insert_session_state;
+  drop function insert_new_session_state;
+  drop function insert_session;
+  drop function update_session_state_on_termination_reason;
+  drop function insert_session_state;
+
+
+  delete
+  from oplog_ticket
+  where name in (
+          'session'
+      );
+
+commit;
+
+`),
+	},
+	"migrations/50_session.up.sql": {
+		name: "50_session.up.sql",
+		bytes: []byte(`
+begin;
+
+/*
+
+             ┌─────────────────┐               ┌─────────────────┐   ┌─────────────────┐
+             │  iam_scope_org  │               │    iam_user     │   │   auth_token    │
+             ├─────────────────┤               ├─────────────────┤   ├─────────────────┤
+             │ public_id  (pk) │               │ public_id (pk)  │   │ public_id  (pk) │
+             │                 │               │                 │   │                 │
+             └─────────────────┘               └─────────────────┘   └─────────────────┘
+                 ▲fk7 ┼                            ▲fk1 ┼                ▲fk6 ┼
+                      ┼                                 ┼                     ┼
+                      ├─────────────────────────────────┴─────────────────────┘
+                      │
+                      ○
+                     ╱│╲
+        ┌──────────────────────────┐          ┌─────────────────┐   ┌─────────────────┐
+        │         session          │╲  fk3▶   │     server      │   │     target      │
+        ├──────────────────────────┤─○──────○┼├─────────────────┤   ├─────────────────┤
+        │ public_id          (pk)  │╱         │ private_id (pk) │   │ public_id  (pk) │
+        │ user_id            (fk1) │          │ type       (pk) │   │                 │
+        │ host_id            (fk2) │          └─────────────────┘   └─────────────────┘
+        │ server_id          (fk3) │                                    ▲fk4 ┼
+        │ server_type        (fk3) │╲                                        ┼
+        │ target_id          (fk4) │─○─────────────────┬─────────────────────┤
+        │ host_set_id        (fk5) │╱                  ┼                     ┼
+        │ auth_token_id      (fk6) │              ▼fk5 ┼                ▼fk2 ┼
+        │ scope_id           (fk7) │          ┌─────────────────┐   ┌─────────────────┐
+        │ termination_reason (fk8) │          │    host_set     │   │      host       │
+        └──────────────────────────┘          ├─────────────────┤   ├─────────────────┤
+                 ▲fk1 ┼           ╲│╱         │ public_id  (pk) │   │ public_id  (pk) │
+                      ┼            ○          │                 │   │                 │
+                      │            │          └─────────────────┘   └─────────────────┘
+                      │            │
+                      └─┐          │
+                        │          │            ┌───────────────────────────────┐
+                        │          │            │session_termination_reason_enm │
+                        │          │     fk8▶   ├───────────────────────────────┤
+                        ┼          └──────────○┼│ name                          │
+                       ╱│╲                      └───────────────────────────────┘
+  ┌──────────────────────────────────────────┐
+  │              session_state               │
+  ├──────────────────────────────────────────┤┼○┐
+  │ session_id        (pk,fk1,fk2,unq1,unq2) │  │◀fk2
+  │ state             (fk3)                  │  │
+  │ previous_end_time (fk2,unq1)             │┼○┘
+  │ start_time        (pk)                   │
+  │ end_time          (unq2)                 │
+  └──────────────────────────────────────────┘
+                       ╲│╱
+                        ○
+                        │
+                        │
+                        ┼
+                  ▼fk3  ┼
+        ┌───────────────────────────────┐
+        │       session_state_enm       │
+        ├───────────────────────────────┤
+        │ name                          │
+        └───────────────────────────────┘
+
+*/
+
+  create table session_termination_reason_enm (
+    name text primary key
+      constraint only_predefined_session_termination_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'terminated',
+          'network error',
+          'system error',
+          'connection limit',
+          'canceled'
+        )
+      )
+  );
+
+  insert into session_termination_reason_enm (name)
+  values
+    ('unknown'),
+    ('timed out'),
+    ('closed by end-user'),
+    ('terminated'),
+    ('network error'),
+    ('system error'),
+    ('connection limit'),
+    ('canceled');
+
+  create table session (
+    public_id wt_public_id primary key,
+    -- the user of the session
+    user_id text -- fk1
+      -- not using the wt_user_id domain type because it is marked 'not null'
+      references iam_user (public_id)
+      on delete set null
+      on update cascade,
+    -- the host the user is connected to via this session
+    host_id wt_public_id -- fk2
+      references host (public_id)
+      on delete set null
+      on update cascade,
+    -- the worker proxying the connection between the user and the host
+    server_id text, -- fk3
+    server_type text,-- fk3
+    foreign key (server_id, server_type)
+      references server (private_id, type)
+      on delete set null
+      on update cascade,
+    -- the target the host was chosen from and the user was authorized to
+    -- connect to
+    target_id wt_public_id -- fk4
+      references target (public_id)
+      on delete set null
+      on update cascade,
+    -- the host set the host was chosen from and the user was authorized to
+    -- connect to via the target
+    host_set_id wt_public_id -- fk5
+      references host_set (public_id)
+      on delete set null
+      on update cascade,
+    -- the auth token of the user when this session was created
+    auth_token_id wt_public_id -- fk6
+      references auth_token (public_id)
+      on delete set null
+      on update cascade,
+    -- the project which owns this session
+    scope_id wt_scope_id -- fk7
+      references iam_scope_project (scope_id)
+      on delete set null
+      on update cascade,
+    -- Certificate to use when connecting (or if using custom certs, to
+	  -- serve as the "login"). Raw DER bytes.  
+    certificate bytea not null,
+    -- after this time the connection will be expired, e.g. forcefully terminated
+    expiration_time wt_timestamp, -- maybe null
+    -- limit on number of session connections allowed.  default of 0 equals no limit
+    connection_limit int not null default 1
+      constraint connection_limit_must_be_greater_than_0_or_negative_1
+      check(connection_limit > 0 or connection_limit = -1), 
+    -- trust of first use token 
+    tofu_token bytea, -- will be null when session is first created
+    -- the reason this session ended (null until terminated)
+     -- TODO: Make key_id a foreign key once we have DEKs
+    key_id text, -- will be null on insert
+      -- references kms_database_key_version(private_id) 
+      -- on delete restrict
+      -- on update cascade,
+    termination_reason text -- fk8
+      references session_termination_reason_enm (name)
+      on delete restrict
+      on update cascade,
+    version wt_version,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    endpoint text -- not part of the warehouse, used to send info to the worker
+  );
+
+  create trigger 
+    immutable_columns
+  before
+  update on session
+    for each row execute procedure immutable_columns('public_id', 'certificate', 'expiration_time', 'connection_limit', 'create_time', 'endpoint');
+  
+  -- session table has some cascades of FK to null, so we need to be careful
+  -- which columns trigger an update of the version column
+  create trigger 
+    update_version_column 
+  after update of version, termination_reason, key_id, tofu_token, server_id, server_type on session
+    for each row execute procedure update_version_column();
+    
+  create trigger 
+    update_time_column 
+  before update on session 
+    for each row execute procedure update_time_column();
+    
+  create trigger 
+    default_create_time_column
+  before
+  insert on session
+    for each row execute procedure default_create_time();
+
+  create or replace function
+    insert_session()
+    returns trigger
+  as $$
+  begin
+    case 
+      when new.user_id is null then
+        raise exception 'user_id is null';
+      when new.host_id is null then
+        raise exception 'host_id is null';
+      when new.target_id is null then
+        raise exception 'target_id is null';
+      when new.host_set_id is null then
+        raise exception 'host_set_id is null';
+      when new.auth_token_id is null then
+        raise exception 'auth_token_id is null';
+      when new.scope_id is null then
+        raise exception 'scope_id is null';
+      when new.endpoint is null then
+        raise exception 'endpoint is null';
+    else
+    end case;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger 
+    insert_session
+  before insert on session
+    for each row execute procedure insert_session();
+
+  create or replace function 
+    insert_new_session_state()
+    returns trigger
+  as $$
+  begin
+    insert into session_state (session_id, state)
+    values
+      (new.public_id, 'pending');
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger 
+    insert_new_session_state
+  after insert on session
+    for each row execute procedure insert_new_session_state();
+
+  -- update_connection_state_on_closed_reason() is used in an update insert trigger on the
+  -- session_connection table.  it will valiadate that all the session's
+  -- connections are closed, and then insert a state of "closed" in
+  -- session_connection_state for the closed session connection. 
+  create or replace function 
+    update_session_state_on_termination_reason()
+    returns trigger
+  as $$
+  begin
+    if new.termination_reason is not null then
+      perform  from 
+        session
+      where 
+        public_id = new.public_id and 
+        public_id not in (
+            select session_id 
+              from session_connection
+            where
+              public_id in (
+                select connection_id
+                  from session_connection_state
+                where 
+                  state != 'closed' and 
+                  end_time is null
+              )
+        );
+      if not found then 
+        raise 'session %s has open connections', new.public_id;
+      end if;
+
+      -- check to see if there's a terminated state already, before inserting a
+      -- new one.
+      perform from
+        session_state ss
+      where
+        ss.session_id = new.public_id and 
+        ss.state = 'terminated';
+      if found then 
+        return new;
+      end if;
+
+      insert into session_state (session_id, state)
+      values
+        (new.public_id, 'terminated');
+      end if;
+      return new;
+  end;
+  $$ language plpgsql;
+
+
+  create trigger 
+    update_session_state_on_termination_reason
+  after update of termination_reason on session
+    for each row execute procedure update_session_state_on_termination_reason();
+ 
+
+  -- cancel_session will insert a cancel state for the session, if there's isn't
+  -- a canceled state already.  It's used by cancel_session_with_null_fk.
+  create or replace function
+    cancel_session(in sessionId text) returns void 
+  as $$
+  declare
+    rows_affected numeric;
+  begin 
+    insert into session_state(session_id, state) 
+    select 
+	    sessionId::text, 'canceling' 
+    from
+      session s
+    where 
+      s.public_id = sessionId::text and
+      s.public_id not in (
+        select 
+          session_id 
+        from 
+          session_state 
+        where 
+          session_id = sessionId::text and 
+          state = 'canceling'
+      ) limit 1;
+      get diagnostics rows_affected = row_count;
+      if rows_affected > 1 then
+          raise exception 'cancel session: more than one row affected: %', rows_affected; 
+      end if;
+  end;
+  $$ language plpgsql;
+
+  -- cancel_session_with_null_fk is intended to be a before update trigger that
+  -- sets the session's state to cancel if a FK is set to null.
+  create or replace function 
+    cancel_session_with_null_fk()
+    returns trigger
+  as $$
+  begin
+   case 
+      when new.user_id is null then
+        perform cancel_session(new.public_id);
+      when new.host_id is null then
+        perform cancel_session(new.public_id);
+      when new.target_id is null then
+        perform cancel_session(new.public_id);
+      when new.host_set_id is null then
+        perform cancel_session(new.public_id);
+      when new.auth_token_id is null then
+        perform cancel_session(new.public_id);
+      when new.scope_id is null then
+        perform cancel_session(new.public_id);
+    end case;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger 
+    cancel_session_with_null_fk
+  before update of user_id, host_id, target_id, host_set_id, auth_token_id, scope_id on session
+    for each row execute procedure cancel_session_with_null_fk();
+
+  create table session_state_enm (
+    name text primary key
+      constraint only_predefined_session_states_allowed
+      check (
+        name in ('pending', 'active', 'canceling', 'terminated')
+      )
+  );
+
+  insert into session_state_enm (name)
+  values
+    ('pending'),
+    ('active'),
+    ('canceling'),
+    ('terminated');
+
+/*
+
+
+                                              ┌────────────────┐
+         start                                │                │
+           .                                  │   Canceling    │
+          (●)                           ┌────▶│                │─────┐
+           '                            │     │                │     │
+           │                            │     └────────────────┘     │
+           │                            │                            │
+           ▼                            │                            ▼
+  ┌────────────────┐           ┌────────────────┐           ┌────────────────┐
+  │                │           │                │           │                │
+  │    Pending     │           │     Active     │           │   Terminated   │
+  │                │──────────▶│                │──────────▶│                │
+  │                │           │                │           │                │
+  └────────────────┘           └────────────────┘           └────────────────┘
+           │                                                         │
+           │                                                         │
+           │                                                         │
+           │                                                         │
+           └──────────────────────▶  ◉ End  ◀────────────────────────┘
+
+*/
+
+  -- Design influenced by:
+  -- Joe Celko's SQL for Smarties: Advanced SQL Programming, 5th edition
+  -- Chapter 12, p270
+  create table session_state (
+    session_id wt_public_id not null -- fk1, fk2
+      references session (public_id)
+      on delete cascade
+      on update cascade,
+    state text not null -- fk3
+      references session_state_enm(name)
+      on delete restrict
+      on update cascade,
+    previous_end_time timestamp with time zone, -- fk2 -- null means first state
+    start_time timestamp with time zone default current_timestamp not null,
+      constraint previous_end_time_and_start_time_in_sequence
+        check (previous_end_time <= start_time),
+    end_time timestamp with time zone, -- null means unfinished current state
+      constraint start_and_end_times_in_sequence
+        check (start_time <= end_time),
+      constraint end_times_in_sequence
+        check (previous_end_time <> end_time),
+    primary key (session_id, start_time),
+    unique (session_id, previous_end_time), -- null means first state
+    unique (session_id, end_time), -- one null current state
+    foreign key (session_id, previous_end_time) -- self-reference
+      references session_state (session_id, end_time)
+  );
+
+
+  create trigger 
+    immutable_columns
+  before
+  update on session_state
+    for each row execute procedure immutable_columns('session_id', 'state', 'start_time', 'previous_end_time');
+
+    
+  create or replace function
+    insert_session_state()
+    returns trigger
+  as $$
+  begin
+
+    update session_state
+       set end_time = now()
+     where session_id = new.session_id
+       and end_time is null;
+
+    if not found then
+      new.previous_end_time = null;
+      new.start_time = now();
+      new.end_time = null;
+      return new;
+    end if;
+
+    new.previous_end_time = now();
+    new.start_time = now();
+    new.end_time = null;
+    return new;
+
+  end;
+  $$ language plpgsql;
+
+
+  create trigger insert_session_state before insert on session_state
+    for each row execute procedure insert_session_state();
+
+  create view session_with_state as
+  select
+    s.public_id,
+    s.user_id,
+    s.host_id,
+    s.server_id,
+    s.server_type,
+    s.target_id,
+    s.host_set_id,
+    s.auth_token_id,
+    s.scope_id,
+    s.certificate,
+    s.expiration_time,
+    s.connection_limit,
+    s.tofu_token,
+    s.key_id,
+    s.termination_reason,
+    s.version,
+    s.create_time,
+    s.update_time,
+    s.endpoint,
+    ss.state,
+    ss.previous_end_time,
+    ss.start_time,
+    ss.end_time
+  from  
+    session s,
+    session_state ss
+  where 
+    s.public_id = ss.session_id;
+
+commit;
+
+`),
+	},
+	"migrations/51_connection.down.sql": {
+		name: "51_connection.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table session_connection_state;
+  drop table session_connection_state_enm;
+  drop table session_connection;
+  drop table session_connection_closed_reason_enm;
+  drop function insert_session_connection_state;
+  drop function insert_new_connection_state;
+  drop function update_connection_state_on_closed_reason;
+commit;
+
+`),
+	},
+	"migrations/51_connection.up.sql": {
+		name: "51_connection.up.sql",
+		bytes: []byte(`
+begin;
+
+/*
+
+               ┌────────────────┐
+               │    session     │
+               ├────────────────┤
+               │ public_id (pk) │
+               │                │
+               │                │
+               └────────────────┘
+                   ▲fk1 ┼
+                        ┼
+                        │
+                        │
+                        ┼
+                       ╱│╲                  ┌──────────────────────────────────────┐
+            ┌───────────────────────┐       │ session_connection_closed_reason_enm │
+            │  session_connection   │╲ fk2▶ ├──────────────────────────────────────┤
+            ├───────────────────────┤─○───○┼│ name                                 │
+            │ public_id     (pk)    │╱      └──────────────────────────────────────┘
+            │ session_id    (fk1)   │
+            │ closed_reason (fk2)   │
+            └───────────────────────┘
+                   ▲fk1 ┼
+                        ┼
+                        │
+                        │
+                        ┼
+                       ╱│╲
+  ┌──────────────────────────────────────────┐
+  │         session_connection_state         │
+  ├──────────────────────────────────────────┤┼○┐
+  │ connection_id     (pk,fk1,fk2,unq1,unq2) │  │◀fk2
+  │ state             (fk3)                  │  │
+  │ previous_end_time (fk2,unq1)             │┼○┘
+  │ start_time        (pk)                   │
+  │ end_time          (unq2)                 │
+  └──────────────────────────────────────────┘
+                       ╲│╱
+                        ○
+                        │
+                        │
+                        ┼
+                  ▼fk3  ┼
+        ┌───────────────────────────────┐
+        │ session_connection_state_enm  │
+        ├───────────────────────────────┤
+        │ name                          │
+        └───────────────────────────────┘
+
+*/
+
+  create table session_connection_closed_reason_enm (
+    name text primary key
+      constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error'
+        )
+      )
+  );
+
+  insert into session_connection_closed_reason_enm (name)
+  values
+    ('unknown'),
+    ('timed out'),
+    ('closed by end-user'),
+    ('canceled'),
+    ('network error'),
+    ('system error');
+
+  -- A session connection is one connection proxied by a worker from a client to
+  -- a endpoint for a session. The client initiates the connection to the worker
+  -- and the worker initiates the connection to the endpoint.
+  -- A session can have zero or more session connections.
+  create table session_connection (
+    public_id wt_public_id primary key,
+    session_id wt_public_id not null
+      references session (public_id)
+      on delete cascade
+      on update cascade,
+    -- the client_tcp_address is the network address of the client which initiated
+    -- the connection to a worker
+    client_tcp_address inet,  -- maybe null on insert
+    -- the client_tcp_port is the network port at the address of the client the
+    -- worker proxied a connection for the user
+    client_tcp_port integer  -- maybe null on insert
+      constraint client_tcp_port_must_be_greater_than_0
+      check(client_tcp_port > 0)
+      constraint client_tcp_port_must_less_than_or_equal_to_65535
+      check(client_tcp_port <= 65535),
+    -- the endpoint_tcp_address is the network address of the endpoint which the
+    -- worker initiated the connection to, for the user
+    endpoint_tcp_address inet, -- maybe be null on insert
+    -- the endpoint_tcp_port is the network port at the address of the endpoint the
+    -- worker proxied a connection to, for the user
+    endpoint_tcp_port integer -- maybe null on insert
+      constraint endpoint_tcp_port_must_be_greater_than_0
+      check(endpoint_tcp_port > 0)
+      constraint endpoint_tcp_port_must_less_than_or_equal_to_65535
+      check(endpoint_tcp_port <= 65535),
+    -- the total number of bytes received by the worker from the client and sent
+    -- to the endpoint for this connection
+    bytes_up bigint -- can be null
+      constraint bytes_up_must_be_null_or_a_non_negative_number
+      check (
+        bytes_up is null
+        or
+        bytes_up >= 0
+      ),
+    -- the total number of bytes received by the worker from the endpoint and sent
+    -- to the client for this connection
+    bytes_down bigint -- can be null
+      constraint bytes_down_must_be_null_or_a_non_negative_number
+      check (
+        bytes_down is null
+        or
+        bytes_down >= 0
+      ),
+    closed_reason text
+      references session_connection_closed_reason_enm (name)
+      on delete restrict
+      on update cascade,
+    version wt_version,
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
+
+  create trigger 
+    immutable_columns
+  before
+  update on session_connection
+    for each row execute procedure immutable_columns('public_id', 'session_id', 'create_time');
+
+  create trigger 
+    update_version_column 
+  after update on session_connection
+    for each row execute procedure update_version_column();
+    
+  create trigger 
+    update_time_column 
+  before update on session_connection 
+    for each row execute procedure update_time_column();
+    
+  create trigger 
+    default_create_time_column
+  before
+  insert on session_connection
+    for each row execute procedure default_create_time();
+
+  -- insert_new_connection_state() is used in an after insert trigger on the
+  -- session_connection table.  it will insert a state of "authorized" in
+  -- session_connection_state for the new session connection. 
+  create or replace function 
+    insert_new_connection_state()
+    returns trigger
+  as $$
+  begin
+    insert into session_connection_state (connection_id, state)
+    values
+      (new.public_id, 'authorized');
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger 
+    insert_new_connection_state
+  after insert on session_connection
+    for each row execute procedure insert_new_connection_state();
+
+  -- update_connection_state_on_closed_reason() is used in an update trigger on the
+  -- session_connection table.  it will insert a state of "closed" in
+  -- session_connection_state for the closed session connection. 
+  create or replace function 
+    update_connection_state_on_closed_reason()
+    returns trigger
+  as $$
+  begin
+    if new.closed_reason is not null then
+      -- check to see if there's a closed state already, before inserting a
+      -- new one.
+      perform from
+        session_connection_state cs
+      where
+        cs.connection_id = new.public_id and 
+        cs.state = 'closed';
+      if not found then 
+        insert into session_connection_state (connection_id, state)
+        values
+          (new.public_id, 'closed');
+      end if;
+      -- whenever we close a connection, we want to terminate the session if
+      -- possible.  
+      perform terminate_session_if_possible(new.session_id);
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger 
+    update_connection_state_on_closed_reason
+  after update of closed_reason on session_connection
+    for each row execute procedure update_connection_state_on_closed_reason();
+
+  create table session_connection_state_enm (
+    name text primary key
+      constraint only_predefined_session_connection_states_allowed
+      check (
+        name in ('authorized', 'connected', 'closed')
+      )
+  );
+
+  insert into session_connection_state_enm (name)
+  values
+    ('authorized'),
+    ('connected'),
+    ('closed');
+
+
+  create table session_connection_state (
+    connection_id wt_public_id not null
+      references session_connection (public_id)
+      on delete cascade
+      on update cascade,
+    state text not null
+      references session_connection_state_enm(name)
+      on delete restrict
+      on update cascade,
+    previous_end_time timestamp with time zone, -- fk2 -- null means first state
+    start_time timestamp with time zone default current_timestamp not null,
+      constraint previous_end_time_and_start_time_in_sequence
+        check (previous_end_time <= start_time),
+    end_time timestamp with time zone, -- null means unfinished current state
+      constraint start_and_end_times_in_sequence
+        check (start_time <= end_time),
+      constraint end_times_in_sequence
+        check (previous_end_time <> end_time),
+    primary key (connection_id, start_time),
+    unique (connection_id, previous_end_time), -- null means first state
+    unique (connection_id, end_time), -- one null current state
+    foreign key (connection_id, previous_end_time) -- self-reference
+      references session_connection_state (connection_id, end_time)
+  );
+
+  create trigger 
+    immutable_columns
+  before
+  update on session_connection_state
+    for each row execute procedure immutable_columns('connection_id', 'state', 'start_time', 'previous_end_time');
+
+  create or replace function
+    insert_session_connection_state()
+    returns trigger
+  as $$
+  begin
+
+    update session_connection_state
+       set end_time = now()
+     where connection_id = new.connection_id
+       and end_time is null;
+
+    if not found then
+      new.previous_end_time = null;
+      new.start_time = now();
+      new.end_time = null;
+      return new;
+    end if;
+
+    new.previous_end_time = now();
+    new.start_time = now();
+    new.end_time = null;
+    return new;
+
+  end;
+  $$ language plpgsql;
+
+  create trigger insert_session_connection_state before insert on session_connection_state
+    for each row execute procedure insert_session_connection_state();
+
+-- terminate_session_if_possible takes a session id and terminates the session
+-- if the following conditions are met:
+--    * the session is expired and all its connections are closed.
+--    * the session is canceling and all its connections are closed
+--    * the session has exhausted its connection limit and all its connections
+--      are closed.  
+--
+--      Note: this function should align closely with the domain function
+--      TerminateCompletedSessions 
+create or replace function 
+    terminate_session_if_possible(terminate_session_id text)
+    returns void
+  as $$
+  begin 
+    -- is terminate_session_id in a canceling state
+    with canceling_session(session_id) as
+    (
+      select 
+        session_id
+      from
+        session_state ss
+      where 
+        ss.session_id = terminate_session_id and
+        ss.state = 'canceling' and 
+        ss.end_time is null
+    )
+    update session us
+      set termination_reason = 
+      case 
+        -- timed out sessions
+        when now() > us.expiration_time then 'timed out'
+        -- canceling sessions
+        when us.public_id in(
+          select 
+            session_id 
+          from 
+            canceling_session cs 
+          where
+            us.public_id = cs.session_id
+          ) then 'canceled' 
+        -- default: session connection limit reached.
+        else 'connection limit'
+      end
+    where
+      -- limit update to just the terminating_session_id
+      us.public_id = terminate_session_id and
+      termination_reason is null and
+      -- session expired or connection limit reached
+      (
+        -- expired sessions...
+        now() > us.expiration_time or 
+        -- connection limit reached...
+        (
+          -- handle unlimited connections...
+          connection_limit != -1 and
+          (
+            select count (*) 
+              from session_connection sc 
+            where 
+              sc.session_id = us.public_id
+          ) >= connection_limit
+        ) or 
+        -- canceled sessions
+        us.public_id in (
+          select 
+            session_id
+          from
+            canceling_session cs
+          where 
+            us.public_id = cs.session_id 
+        )
+      ) and 
+      -- make sure there are no existing connections
+      us.public_id not in (
+        select 
+          session_id 
+        from 
+            session_connection
+          where public_id in (
+          select 
+            connection_id
+          from 
+            session_connection_state
+          where 
+            state != 'closed' and
+            end_time is null
+        )
+    );
+ end;
+  $$ language plpgsql;
+
+
+commit;
+
+`),
+	},
+	"migrations/60_wh_domain_types.down.sql": {
+		name: "60_wh_domain_types.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop function wh_current_time_id;
+  drop function wh_current_date_id;
+  drop function wh_time_id;
+  drop function wh_date_id;
+  drop domain wh_dim_text;
+  drop domain wh_timestamp;
+  drop domain wh_public_id;
+  drop domain wh_dim_id;
+  drop function wh_dim_id;
+  drop domain wh_bytes_transmitted;
+  drop domain wh_inet_port;
+
+commit;
+
+`),
+	},
+	"migrations/60_wh_domain_types.up.sql": {
+		name: "60_wh_domain_types.up.sql",
+		bytes: []byte(`
+begin;
+
+  create extension if not exists "pgcrypto";
+
+  create domain wh_inet_port as integer
+  check(
+    value > 0
+    and
+    value <= 65535
+  );
+  comment on domain wh_inet_port is
+  'An ordinal number between 1 and 65535 representing a network port';
+
+  create domain wh_bytes_transmitted as bigint
+  check(
+    value >= 0
+  );
+  comment on domain wh_bytes_transmitted is
+  'A non-negative integer representing the number of bytes transmitted';
+
+  -- wh_dim_id generates and returns a random ID which should be considered as
+  -- universally unique.
+  create or replace function wh_dim_id()
+    returns text
+  as $$
+    select encode(digest(gen_random_bytes(16), 'sha256'), 'base64');
+  $$ language sql;
+
+  create domain wh_dim_id as text
+  check(
+    length(trim(value)) > 0
+  );
+  comment on domain wh_dim_id is
+  'Random ID generated with pgcrypto';
+
+  create domain wh_public_id as text
+  check(
+    value = 'None'
+    or
+    length(trim(value)) > 10
+  );
+  comment on domain wh_public_id is
+  'Equivalent to wt_public_id but also allows the value to be ''None''';
+
+  create domain wh_timestamp as timestamp with time zone not null;
+  comment on domain wh_timestamp is
+  'Timestamp used in warehouse tables';
+
+  create domain wh_dim_text as text not null
+  check(
+    length(trim(value)) > 0
+  );
+  comment on domain wh_dim_text is
+  'Text fields in dimension tables are always not null and always not empty strings';
+
+  -- wh_date_id returns the wh_date_dimension id for ts.
+  create or replace function wh_date_id(ts wh_timestamp)
+    returns integer
+  as $$
+    select to_char(ts, 'YYYYMMDD')::integer;
+  $$ language sql;
+
+  -- wh_time_id returns the wh_time_of_day_dimension id for ts.
+  create or replace function wh_time_id(ts wh_timestamp)
+    returns integer
+  as $$
+    select to_char(ts, 'SSSS')::integer;
+  $$ language sql;
+
+  -- wh_date_id returns the wh_date_dimension id for current_timestamp.
+  create or replace function wh_current_date_id()
+    returns integer
+  as $$
+    select wh_date_id(current_timestamp);
+  $$ language sql;
+
+  -- wh_time_id returns the wh_time_of_day_dimension id for current_timestamp.
+  create or replace function wh_current_time_id()
+    returns integer
+  as $$
+    select wh_time_id(current_timestamp);
+  $$ language sql;
+
+commit;
+
+`),
+	},
+	"migrations/62_wh_datetime.down.sql": {
+		name: "62_wh_datetime.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table wh_time_of_day_dimension;
+  drop table wh_date_dimension;
+
+commit;
+
+`),
+	},
+	"migrations/62_wh_datetime.up.sql": {
+		name: "62_wh_datetime.up.sql",
+		bytes: []byte(`
+begin;
+
+  create table wh_date_dimension (
+    id                            integer      primary key,
+    date                          date         not null,
+    calendar_quarter              wh_dim_text,
+    calendar_month                wh_dim_text,
+    calendar_year                 smallint     not null,
+    day_of_week                   wh_dim_text,
+    day_of_week_number            smallint     not null,
+    day_of_week_number_iso        smallint     not null,
+    day_of_week_number_zero_based smallint     not null,
+    day_number_in_calendar_month  smallint     not null,
+    day_number_in_calendar_year   smallint     not null,
+    weekday_indicator             wh_dim_text
+  );
+
+  insert into wh_date_dimension (
+    id, date,
+    calendar_quarter, calendar_month, calendar_year,
+    day_of_week, day_of_week_number, day_of_week_number_iso, day_of_week_number_zero_based,
+    day_number_in_calendar_month, day_number_in_calendar_year,
+    weekday_indicator
+  ) values (
+    -1, 'infinity',
+    'None', 'None', -1,
+    'None', -1, -1, -1,
+    -1, -1,
+    'None'
+  );
+
+  insert
+    into wh_date_dimension
+  select to_char(t.day, 'YYYYMMDD')::integer as id,
+         t.day::date                         as date,
+         'Q' || to_char(t.day, 'Q')          as calendar_quarter,
+         to_char(t.day, 'Month')             as calendar_month,
+         extract(year from t.day)            as calendar_year,
+         to_char(t.day, 'Day')               as day_of_week,
+         to_char(t.day, 'D')::int            as day_of_week_number,
+         extract(isodow from t.day)          as day_of_week_number_iso,
+         extract(dow from t.day)             as day_of_week_number_zero_based,
+         extract(day from t.day)             as day_number_in_calendar_month,
+         extract(doy from t.day)             as day_number_in_calendar_year,
+         case extract(isodow from t.day)
+           when 6 then 'Weekend'
+           when 7 then 'Weekend'
+           else 'Weekday'
+         end                                 as weekday_indicator
+    from generate_series(
+           date_trunc('day', timestamp '2019-10-09'),
+           date_trunc('day', timestamp '2019-10-09' + interval '50 years'),
+           interval '1 day'
+         ) as t(day);
+
+  create table wh_time_of_day_dimension (
+    id                 integer      primary key,
+    time_no_zone       time         not null,
+    time_at_utc        timetz       not null,
+    hour_of_day        smallint     not null,
+    minute_of_hour     smallint     not null,
+    second_of_minute   smallint     not null,
+    display_time_24    wh_dim_text,
+    display_time_12    wh_dim_text,
+    meridiem_indicator wh_dim_text
+  );
+
+  set timezone = 'UTC';
+
+  insert into wh_time_of_day_dimension (
+    id, time_no_zone, time_at_utc,
+    hour_of_day, minute_of_hour, second_of_minute,
+    display_time_24, display_time_12, meridiem_indicator
+  ) values (
+    -1, 'allballs', 'allballs',
+    -1, -1, -1,
+    'None', 'None', 'None'
+  );
+
+  insert
+    into wh_time_of_day_dimension
+  select to_char(t.second, 'SSSS')::integer as id,
+         t.second::time                     as time_no_zone,
+         t.second::time                     as time_at_utc,
+         extract(hour from t.second)        as hour_of_day,
+         extract(minute from t.second)      as minute_of_hour,
+         extract(second from t.second)      as second_of_minute,
+         to_char(t.second, 'HH24:MI:SS')    as display_time_24,
+         to_char(t.second, 'HH12:MI:SS AM') as display_time_12,
+         to_char(t.second, 'PM')            as meridiem_indicator
+    from generate_series(
+           date_trunc('day', current_timestamp),
+           date_trunc('day', current_timestamp) + interval '24 hours' - interval '1 second',
+           interval '1 second'
+         ) as t(second);
+
+commit;
+
+`),
+	},
+	"migrations/65_wh_session_dimensions.down.sql": {
+		name: "65_wh_session_dimensions.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop view whx_user_dimension_target;
+  drop view whx_user_dimension_source;
+  drop view whx_host_dimension_target;
+  drop view whx_host_dimension_source;
+  drop table wh_user_dimension;
+  drop table wh_host_dimension;
+
+commit;
+
+`),
+	},
+	"migrations/65_wh_session_dimensions.up.sql": {
+		name: "65_wh_session_dimensions.up.sql",
+		bytes: []byte(`
+begin;
+
+  create table wh_host_dimension (
+    -- random id generated using encode(digest(gen_random_bytes(16), 'sha256'), 'base64')
+    -- this is done to prevent conflicts with rows in other clusters
+    -- which enables warehouse data from multiple clusters to be loaded into a
+    -- single database instance
+    id                              wh_dim_id     primary key default wh_dim_id(),
+
+    host_id                         wh_public_id  not null,
+    host_type                       wh_dim_text,
+    host_name                       wh_dim_text,
+    host_description                wh_dim_text,
+    host_address                    wh_dim_text,
+
+    host_set_id                     wh_public_id  not null,
+    host_set_type                   wh_dim_text,
+    host_set_name                   wh_dim_text,
+    host_set_description            wh_dim_text,
+
+    host_catalog_id                 wh_public_id  not null,
+    host_catalog_type               wh_dim_text,
+    host_catalog_name               wh_dim_text,
+    host_catalog_description        wh_dim_text,
+
+    target_id                       wh_public_id  not null,
+    target_type                     wh_dim_text,
+    target_name                     wh_dim_text,
+    target_description              wh_dim_text,
+    target_default_port_number      integer       not null,
+    target_session_max_seconds      integer       not null,
+    target_session_connection_limit integer       not null,
+
+    project_id                      wt_scope_id   not null,
+    project_name                    wh_dim_text,
+    project_description             wh_dim_text,
+
+    host_organization_id            wt_scope_id   not null,
+    host_organization_name          wh_dim_text,
+    host_organization_description   wh_dim_text,
+
+    current_row_indicator           wh_dim_text,
+    row_effective_time              wh_timestamp,
+    row_expiration_time             wh_timestamp
+  );
+
+  -- https://www.postgresql.org/docs/current/indexes-partial.html
+  create unique index wh_host_dim_current_constraint
+    on wh_host_dimension (target_id, host_set_id, host_id)
+    where current_row_indicator = 'Current';
+
+  -- The whx_host_dimension_source and whx_host_dimension_target views are used
+  -- by an insert trigger to determine if the current row for the dimension has
+  -- changed and new one needs to be inserted. The first column in the target
+  -- view must be the current warehouse id and all remaining columns must match
+  -- the columns in the source view.
+
+  -- The whx_host_dimension_source view shows the current values in the
+  -- operational tables of the host dimension.
+  create view whx_host_dimension_source as
+  select -- id is the first column in the target view
+         h.public_id                     as host_id,
+         'static host'                   as host_type,
+         coalesce(h.name, 'None')        as host_name,
+         coalesce(h.description, 'None') as host_description,
+         coalesce(h.address, 'Unknown')  as host_address,
+         s.public_id                     as host_set_id,
+         'static host set'               as host_set_type,
+         coalesce(s.name, 'None')        as host_set_name,
+         coalesce(s.description, 'None') as host_set_description,
+         c.public_id                     as host_catalog_id,
+         'static host catalog'           as host_catalog_type,
+         coalesce(c.name, 'None')        as host_catalog_name,
+         coalesce(c.description, 'None') as host_catalog_description,
+         t.public_id                     as target_id,
+         'tcp target'                    as target_type,
+         coalesce(t.name, 'None')        as target_name,
+         coalesce(t.description, 'None') as target_description,
+         coalesce(t.default_port, 0)     as target_default_port_number,
+         t.session_max_seconds           as target_session_max_seconds,
+         t.session_connection_limit      as target_session_connection_limit,
+         p.public_id                     as project_id,
+         coalesce(p.name, 'None')        as project_name,
+         coalesce(p.description, 'None') as project_description,
+         o.public_id                     as host_organization_id,
+         coalesce(o.name, 'None')        as host_organization_name,
+         coalesce(o.description, 'None') as host_organization_description
+    from static_host as h,
+         static_host_catalog as c,
+         static_host_set_member as m,
+         static_host_set as s,
+         target_host_set as ts,
+         target_tcp as t,
+         iam_scope as p,
+         iam_scope as o
+   where h.catalog_id = c.public_id
+     and h.public_id = m.host_id
+     and s.public_id = m.set_id
+     and t.public_id = ts.target_id
+     and s.public_id = ts.host_set_id
+     and p.public_id = t.scope_id
+     and p.type = 'project'
+     and o.public_id = p.parent_id
+     and o.type = 'org'
+  ;
+
+  -- The whx_host_dimension_target view shows the rows in the wh_host_dimension
+  -- table marked as 'Current'.
+  create view whx_host_dimension_target as
+  select id,
+         host_id,
+         host_type,
+         host_name,
+         host_description,
+         host_address,
+         host_set_id,
+         host_set_type,
+         host_set_name,
+         host_set_description,
+         host_catalog_id,
+         host_catalog_type,
+         host_catalog_name,
+         host_catalog_description,
+         target_id,
+         target_type,
+         target_name,
+         target_description,
+         target_default_port_number,
+         target_session_max_seconds,
+         target_session_connection_limit,
+         project_id,
+         project_name,
+         project_description,
+         host_organization_id,
+         host_organization_name,
+         host_organization_description
+    from wh_host_dimension
+   where current_row_indicator = 'Current'
+  ;
+
+  create table wh_user_dimension (
+    -- random id generated using encode(digest(gen_random_bytes(16), 'sha256'), 'base64')
+    -- this is done to prevent conflicts with rows in other clusters
+    -- which enables warehouse data from multiple clusters to be loaded into a
+    -- single database instance
+    id                            wh_dim_id     primary key default wh_dim_id(),
+
+    user_id                       wt_user_id    not null,
+    user_name                     wh_dim_text,
+    user_description              wh_dim_text,
+
+    auth_account_id               wh_public_id  not null,
+    auth_account_type             wh_dim_text,
+    auth_account_name             wh_dim_text,
+    auth_account_description      wh_dim_text,
+
+    auth_method_id                wh_public_id  not null,
+    auth_method_type              wh_dim_text,
+    auth_method_name              wh_dim_text,
+    auth_method_description       wh_dim_text,
+
+    user_organization_id          wt_scope_id   not null,
+    user_organization_name        wh_dim_text,
+    user_organization_description wh_dim_text,
+
+    current_row_indicator         wh_dim_text,
+    row_effective_time            wh_timestamp,
+    row_expiration_time           wh_timestamp
+  );
+
+  -- The whx_user_dimension_source and whx_user_dimension_target views are used
+  -- by an insert trigger to determine if the current row for the dimension has
+  -- changed and new one needs to be inserted. The first column in the target
+  -- view must be the current warehouse id and all remaining columns must match
+  -- the columns in the source view.
+
+  -- The whx_user_dimension_source view shows the current values in the
+  -- operational tables of the user dimension.
+  create view whx_user_dimension_source as
+       select -- id is the first column in the target view
+              u.public_id                       as user_id,
+              coalesce(u.name, 'None')          as user_name,
+              coalesce(u.description, 'None')   as user_description,
+              coalesce(aa.public_id, 'None')    as auth_account_id,
+              case when aa.public_id is null then 'None'
+                   else 'password auth account'
+                   end                          as auth_account_type,
+              coalesce(apa.name, 'None')        as auth_account_name,
+              coalesce(apa.description, 'None') as auth_account_description,
+              coalesce(am.public_id, 'None')    as auth_method_id,
+              case when am.public_id is null then 'None'
+                   else 'password auth method'
+                   end                          as auth_method_type,
+              coalesce(apm.name, 'None')        as auth_method_name,
+              coalesce(apm.description, 'None') as auth_method_description,
+              org.public_id                     as user_organization_id,
+              coalesce(org.name, 'None')        as user_organization_name,
+              coalesce(org.description, 'None') as user_organization_description
+         from iam_user as u
+    left join auth_account as aa on           u.public_id = aa.iam_user_id
+    left join auth_method as am on            aa.auth_method_id = am.public_id
+    left join auth_password_account as apa on aa.public_id = apa.public_id
+    left join auth_password_method as apm on  am.public_id = apm.public_id
+         join iam_scope as org on             u.scope_id = org.public_id
+  ;
+
+  -- The whx_user_dimension_target view shows the rows in the wh_user_dimension
+  -- table marked as 'Current'.
+  create view whx_user_dimension_target as
+    select id,
+           user_id,
+           user_name,
+           user_description,
+           auth_account_id,
+           auth_account_type,
+           auth_account_name,
+           auth_account_description,
+           auth_method_id,
+           auth_method_type,
+           auth_method_name,
+           auth_method_description,
+           user_organization_id,
+           user_organization_name,
+           user_organization_description
+      from wh_user_dimension
+     where current_row_indicator = 'Current'
+  ;
+
+commit;
+
+`),
+	},
+	"migrations/66_wh_session_dimensions.down.sql": {
+		name: "66_wh_session_dimensions.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop function wh_upsert_user;
+  drop function wh_upsert_host;
+
+commit;
+
+`),
+	},
+	"migrations/66_wh_session_dimensions.up.sql": {
+		name: "66_wh_session_dimensions.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- wh_upsert_host returns the wh_host_dimension id for p_host_id,
+  -- p_host_set_id, and p_target_id. wh_upsert_host compares the current values
+  -- in the wh_host_dimension with the current values in the operational tables
+  -- for the provide parameters. If the values between the operational tables
+  -- and the wh_host_dimension differ, a new row is inserted in the
+  -- wh_host_dimension to match the current values in the operational tables and
+  -- the new id is returned. If the values do not differ, the current id is
+  -- returned.
+  create or replace function wh_upsert_host(p_host_id wt_public_id, p_host_set_id wt_public_id, p_target_id wt_public_id)
+    returns wh_dim_id
+  as $$
+  declare
+    src     whx_host_dimension_target%rowtype;
+    target  whx_host_dimension_target%rowtype;
+    new_row wh_host_dimension%rowtype;
+  begin
+    select * into target
+      from whx_host_dimension_target as t
+     where t.host_id               = p_host_id
+       and t.host_set_id           = p_host_set_id
+       and t.target_id             = p_target_id;
+
+    select target.id, t.* into src
+      from whx_host_dimension_source as t
+     where t.host_id               = p_host_id
+       and t.host_set_id           = p_host_set_id
+       and t.target_id             = p_target_id;
+
+    if src is distinct from target then
+
+      -- expire the current row
+      update wh_host_dimension
+         set current_row_indicator = 'Expired',
+             row_expiration_time   = current_timestamp
+       where host_id               = p_host_id
+         and host_set_id           = p_host_set_id
+         and target_id             = p_target_id
+         and current_row_indicator = 'Current';
+
+      -- insert a new row
+      insert into wh_host_dimension (
+             host_id,                    host_type,                  host_name,                       host_description,         host_address,
+             host_set_id,                host_set_type,              host_set_name,                   host_set_description,
+             host_catalog_id,            host_catalog_type,          host_catalog_name,               host_catalog_description,
+             target_id,                  target_type,                target_name,                     target_description,
+             target_default_port_number, target_session_max_seconds, target_session_connection_limit,
+             project_id,                 project_name,               project_description,
+             host_organization_id,       host_organization_name,     host_organization_description,
+             current_row_indicator,      row_effective_time,         row_expiration_time
+      )
+      select host_id,                    host_type,                  host_name,                       host_description,         host_address,
+             host_set_id,                host_set_type,              host_set_name,                   host_set_description,
+             host_catalog_id,            host_catalog_type,          host_catalog_name,               host_catalog_description,
+             target_id,                  target_type,                target_name,                     target_description,
+             target_default_port_number, target_session_max_seconds, target_session_connection_limit,
+             project_id,                 project_name,               project_description,
+             host_organization_id,       host_organization_name,     host_organization_description,
+             'Current',                  current_timestamp,          'infinity'::timestamptz
+        from whx_host_dimension_source
+       where host_id               = p_host_id
+         and host_set_id           = p_host_set_id
+         and target_id             = p_target_id
+      returning * into new_row;
+
+      return new_row.id;
+    end if;
+    return target.id;
+
+  end;
+  $$ language plpgsql;
+
+  -- wh_upsert_user returns the wh_user_dimension id for p_user_id and
+  -- p_auth_token_id. wh_upsert_user compares the current values in the
+  -- wh_user_dimension with the current values in the operational tables for the
+  -- provide parameters. If the values between the operational tables and the
+  -- wh_user_dimension differ, a new row is inserted in the wh_user_dimension to
+  -- match the current values in the operational tables and the new id is
+  -- returned. If the values do not differ, the current id is returned.
+  create or replace function wh_upsert_user(p_user_id wt_user_id, p_auth_token_id wt_public_id)
+    returns wh_dim_id
+  as $$
+  declare
+    src     whx_user_dimension_target%rowtype;
+    target  whx_user_dimension_target%rowtype;
+    new_row wh_user_dimension%rowtype;
+    acct_id wt_public_id;
+  begin
+    select auth_account_id into strict acct_id
+      from auth_token
+     where public_id = p_auth_token_id;
+
+    select * into target
+      from whx_user_dimension_target as t
+     where t.user_id               = p_user_id
+       and t.auth_account_id       = acct_id;
+
+    select target.id, t.* into src
+      from whx_user_dimension_source as t
+     where t.user_id               = p_user_id
+       and t.auth_account_id       = acct_id;
+
+    if src is distinct from target then
+
+      -- expire the current row
+      update wh_user_dimension
+         set current_row_indicator = 'Expired',
+             row_expiration_time   = current_timestamp
+       where user_id               = p_user_id
+         and auth_account_id       = acct_id
+         and current_row_indicator = 'Current';
+
+      -- insert a new row
+      insert into wh_user_dimension (
+             user_id,               user_name,              user_description,
+             auth_account_id,       auth_account_type,      auth_account_name,             auth_account_description,
+             auth_method_id,        auth_method_type,       auth_method_name,              auth_method_description,
+             user_organization_id,  user_organization_name, user_organization_description,
+             current_row_indicator, row_effective_time,     row_expiration_time
+      )
+      select user_id,               user_name,              user_description,
+             auth_account_id,       auth_account_type,      auth_account_name,             auth_account_description,
+             auth_method_id,        auth_method_type,       auth_method_name,              auth_method_description,
+             user_organization_id,  user_organization_name, user_organization_description,
+             'Current',             current_timestamp,      'infinity'::timestamptz
+        from whx_user_dimension_source
+       where user_id               = p_user_id
+         and auth_account_id       = acct_id
+      returning * into new_row;
+
+      return new_row.id;
+    end if;
+    return target.id;
+
+  end;
+  $$ language plpgsql;
+commit;
+
+`),
+	},
+	"migrations/68_wh_session_facts.down.sql": {
+		name: "68_wh_session_facts.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table wh_session_connection_accumulating_fact;
+  drop table wh_session_accumulating_fact;
+
+commit;
+
+`),
+	},
+	"migrations/68_wh_session_facts.up.sql": {
+		name: "68_wh_session_facts.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- Column names for numeric fields that are not a measurement end in id or
+  -- number. This naming convention enables automatic field type detection in
+  -- certain data analysis tools.
+  -- https://help.tableau.com/current/pro/desktop/en-us/data_clean_adm.htm
+
+  -- The wh_session_accumulating_fact table is an accumulating snapshot.
+  -- The table wh_session_accumulating_fact is an accumulating fact table.
+  -- The grain of the fact table is one row per session.
+  create table wh_session_accumulating_fact (
+    session_id wt_public_id primary key,
+    -- auth token id is a degenerate dimension
+    auth_token_id wt_public_id not null,
+
+    -- foreign keys to the dimension tables
+    host_id wh_dim_id not null
+      references wh_host_dimension (id)
+      on delete restrict
+      on update cascade,
+    user_id wh_dim_id not null
+      references wh_user_dimension (id)
+      on delete restrict
+      on update cascade,
+
+    -- TODO(mgaffney) 09/2020: add dimension and foreign key for the session
+    -- termination reason
+
+    -- date and time foreign keys
+    session_pending_date_id integer not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_pending_time_id integer not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_pending_time wh_timestamp,
+
+    session_active_date_id integer default -1 not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_active_time_id integer default -1 not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_active_time wh_timestamp default 'infinity'::timestamptz,
+
+    session_canceling_date_id integer default -1 not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_canceling_time_id integer default -1 not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_canceling_time wh_timestamp default 'infinity'::timestamptz,
+
+    session_terminated_date_id integer default -1 not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_terminated_time_id integer default -1 not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    session_terminated_time wh_timestamp default 'infinity'::timestamptz,
+
+    -- TODO(mgaffney) 09/2020: add columns for session expiration
+
+    -- TODO(mgaffney) 09/2020: add connection limit. This may need a dimension
+    -- table and foreign key column to represent unlimited connections.
+
+    -- The total number of connections made during the session.
+    total_connection_count bigint, -- will be null until the first connection is created
+
+    -- The total number of bytes received by workers from the client and sent
+    -- to the endpoint for this session.
+    -- total_bytes_up is a fully additive measurement.
+    total_bytes_up wh_bytes_transmitted, -- will be null until the first connection is closed
+    -- The total number of bytes received by workers from the endpoint and sent
+    -- to the client for this session.
+    -- total_bytes_down is a fully additive measurement.
+    total_bytes_down wh_bytes_transmitted -- will be null until the first connection is closed
+  );
+
+  -- The wh_session_connection_accumulating_fact table is an accumulating fact table.
+  -- The grain of the fact table is one row per session connection.
+  create table wh_session_connection_accumulating_fact  (
+    connection_id wt_public_id primary key,
+    -- session_id is a degenerate dimension
+    session_id wt_public_id not null
+      references wh_session_accumulating_fact (session_id)
+      on delete cascade
+      on update cascade,
+
+    -- foreign keys to the dimension tables
+    host_id wh_dim_id not null
+      references wh_host_dimension (id)
+      on delete restrict
+      on update cascade,
+    user_id wh_dim_id not null
+      references wh_user_dimension (id)
+      on delete restrict
+      on update cascade,
+
+    -- TODO(mgaffney) 09/2020: add dimension and foreign key for the connection
+    -- closed reason
+
+    -- date and time foreign keys and timestamps
+    connection_authorized_date_id integer not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    connection_authorized_time_id integer not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    connection_authorized_time wh_timestamp,
+
+    connection_connected_date_id integer default -1 not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    connection_connected_time_id integer default -1 not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    connection_connected_time wh_timestamp default 'infinity'::timestamptz,
+
+    connection_closed_date_id integer default -1 not null
+      references wh_date_dimension (id)
+      on delete restrict
+      on update cascade,
+    connection_closed_time_id integer default -1 not null
+      references wh_time_of_day_dimension (id)
+      on delete restrict
+      on update cascade,
+    connection_closed_time wh_timestamp default 'infinity'::timestamptz,
+
+    -- TODO(mgaffney) 09/2020: add a connection_duration_in_seconds column
+
+    -- The client address and port are degenerate dimensions
+    client_tcp_address inet, -- can be null
+    client_tcp_port_number wh_inet_port, -- can be null
+
+    -- The endpoint address and port are degenerate dimensions
+    endpoint_tcp_address inet, -- can be null
+    endpoint_tcp_port_number wh_inet_port, -- can be null
+
+    -- the connection_count must always be 1
+    -- this is a common pattern in data warehouse models
+    -- See The Data Warehouse Toolkit, Third Edition
+    -- by Ralph Kimball and Margy Ross for more information
+    connection_count smallint default 1 not null
+      constraint connection_count_must_be_1
+      check(connection_count = 1),
+
+    -- The total number of bytes received by the worker from the client and sent
+    -- to the endpoint for this connection.
+    -- bytes_up is a fully additive measurement.
+    bytes_up wh_bytes_transmitted, -- can be null
+    -- The total number of bytes received by the worker from the endpoint and sent
+    -- to the client for this connection.
+    -- bytes_down is a fully additive measurement.
+    bytes_down wh_bytes_transmitted -- can be null
+  );
+
+  -- TODO(mgaffney) 09/2020: Research and test if the comment fields are used by
+  -- data analysis tools.
+  comment on table wh_session_connection_accumulating_fact is
+    'The Wh Session Connection Accumulating Fact table is an accumulating fact table. '
+    'The grain of the fact table is one row per session connection.';
+  comment on column wh_session_connection_accumulating_fact.bytes_up is
+    'Bytes Up is the total number of bytes received by the worker from the '
+    'client and sent to the endpoint for this connection. Bytes Up is a fully '
+    'additive measurement.';
+  comment on column wh_session_connection_accumulating_fact.bytes_down is
+    'Bytes Down is the total number of bytes received by the worker from the '
+    'endpoint and sent to the client for this connection. Bytes Down is a fully '
+    'additive measurement.';
+
+  create index on wh_session_connection_accumulating_fact(session_id);
+
+commit;
+
+`),
+	},
+	"migrations/69_wh_session_facts.down.sql": {
+		name: "69_wh_session_facts.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop trigger wh_insert_session_connection_state on session_connection_state;
+  drop function wh_insert_session_connection_state;
+
+  drop trigger wh_insert_session_state on session_state;
+  drop function wh_insert_session_state;
+
+  drop trigger wh_update_session_connection on session_connection;
+  drop function wh_update_session_connection;
+
+  drop trigger wh_insert_session_connection on session_connection;
+  drop function wh_insert_session_connection;
+
+  drop trigger wh_insert_session on session;
+  drop function wh_insert_session;
+
+  drop function wh_rollup_connections;
+
+commit;
+
+`),
+	},
+	"migrations/69_wh_session_facts.up.sql": {
+		name: "69_wh_session_facts.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- wh_rollup_connections calculates the aggregate values from
+  -- wh_session_connection_accumulating_fact for p_session_id and updates
+  -- wh_session_accumulating_fact for p_session_id with those values.
+  create or replace function wh_rollup_connections(p_session_id wt_public_id)
+    returns void
+  as $$
+  declare
+    session_row wh_session_accumulating_fact%rowtype;
+  begin
+    with
+    session_totals (session_id, total_connection_count, total_bytes_up, total_bytes_down) as (
+      select session_id,
+             sum(connection_count),
+             sum(bytes_up),
+             sum(bytes_down)
+        from wh_session_connection_accumulating_fact
+       where session_id = p_session_id
+       group by session_id
+    )
+    update wh_session_accumulating_fact
+       set total_connection_count = session_totals.total_connection_count,
+           total_bytes_up         = session_totals.total_bytes_up,
+           total_bytes_down       = session_totals.total_bytes_down
+      from session_totals
+     where wh_session_accumulating_fact.session_id = session_totals.session_id
+    returning wh_session_accumulating_fact.* into strict session_row;
+  end;
+  $$ language plpgsql;
+
+  --
+  -- Session triggers
+  --
+
+  -- wh_insert_session returns an after insert trigger for the session table
+  -- which inserts a row in wh_session_accumulating_fact for the new session.
+  -- wh_insert_session also calls the wh_upsert_host and wh_upsert_user
+  -- functions which can result in new rows in wh_host_dimension and
+  -- wh_user_dimension respectively.
+  create or replace function wh_insert_session()
+    returns trigger
+  as $$
+  declare
+    new_row wh_session_accumulating_fact%rowtype;
+  begin
+    with
+    pending_timestamp (date_dim_id, time_dim_id, ts) as (
+      select wh_date_id(start_time), wh_time_id(start_time), start_time
+        from session_state
+       where session_id = new.public_id
+         and state = 'pending'
+    )
+    insert into wh_session_accumulating_fact (
+           session_id,
+           auth_token_id,
+           host_id,
+           user_id,
+           session_pending_date_id,
+           session_pending_time_id,
+           session_pending_time
+    )
+    select new.public_id,
+           new.auth_token_id,
+           wh_upsert_host(new.host_id, new.host_set_id, new.target_id),
+           wh_upsert_user(new.user_id, new.auth_token_id),
+           pending_timestamp.date_dim_id,
+           pending_timestamp.time_dim_id,
+           pending_timestamp.ts
+      from pending_timestamp
+      returning * into strict new_row;
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger wh_insert_session
+    after insert on session
+    for each row
+    execute function wh_insert_session();
+
+  --
+  -- Session Connection triggers
+  --
+
+  -- wh_insert_session_connection returns an after insert trigger for the
+  -- session_connection table which inserts a row in
+  -- wh_session_connection_accumulating_fact for the new session connection.
+  -- wh_insert_session_connection also calls wh_rollup_connections which can
+  -- result in updates to wh_session_accumulating_fact.
+  create or replace function wh_insert_session_connection()
+    returns trigger
+  as $$
+  declare
+    new_row wh_session_connection_accumulating_fact%rowtype;
+  begin
+    with
+    authorized_timestamp (date_dim_id, time_dim_id, ts) as (
+      select wh_date_id(start_time), wh_time_id(start_time), start_time
+        from session_connection_state
+       where connection_id = new.public_id
+         and state = 'authorized'
+    ),
+    session_dimension (host_dim_id, user_dim_id) as (
+      select host_id, user_id
+        from wh_session_accumulating_fact
+       where session_id = new.session_id
+    )
+    insert into wh_session_connection_accumulating_fact (
+           connection_id,
+           session_id,
+           host_id,
+           user_id,
+           connection_authorized_date_id,
+           connection_authorized_time_id,
+           connection_authorized_time,
+           client_tcp_address,
+           client_tcp_port_number,
+           endpoint_tcp_address,
+           endpoint_tcp_port_number,
+           bytes_up,
+           bytes_down
+    )
+    select new.public_id,
+           new.session_id,
+           session_dimension.host_dim_id,
+           session_dimension.user_dim_id,
+           authorized_timestamp.date_dim_id,
+           authorized_timestamp.time_dim_id,
+           authorized_timestamp.ts,
+           new.client_tcp_address,
+           new.client_tcp_port,
+           new.endpoint_tcp_address,
+           new.endpoint_tcp_port,
+           new.bytes_up,
+           new.bytes_down
+      from authorized_timestamp,
+           session_dimension
+      returning * into strict new_row;
+    perform wh_rollup_connections(new.session_id);
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger wh_insert_session_connection
+    after insert on session_connection
+    for each row
+    execute function wh_insert_session_connection();
+
+  -- wh_update_session_connection returns an after update trigger for the
+  -- session_connection table which updates a row in
+  -- wh_session_connection_accumulating_fact for the session connection.
+  -- wh_update_session_connection also calls wh_rollup_connections which can
+  -- result in updates to wh_session_accumulating_fact.
+  create or replace function wh_update_session_connection()
+    returns trigger
+  as $$
+  declare
+    updated_row wh_session_connection_accumulating_fact%rowtype;
+  begin
+        update wh_session_connection_accumulating_fact
+           set client_tcp_address       = new.client_tcp_address,
+               client_tcp_port_number   = new.client_tcp_port,
+               endpoint_tcp_address     = new.endpoint_tcp_address,
+               endpoint_tcp_port_number = new.endpoint_tcp_port,
+               bytes_up                 = new.bytes_up,
+               bytes_down               = new.bytes_down
+         where connection_id = new.public_id
+     returning * into strict updated_row;
+    perform wh_rollup_connections(new.session_id);
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger wh_update_session_connection
+    after update on session_connection
+    for each row
+    execute function wh_update_session_connection();
+
+  --
+  -- Session State trigger
+  --
+
+  -- wh_insert_session_state returns an after insert trigger for the
+  -- session_state table which updates wh_session_accumulating_fact.
+  create or replace function wh_insert_session_state()
+    returns trigger
+  as $$
+  declare
+    date_col text;
+    time_col text;
+    ts_col text;
+    q text;
+    session_row wh_session_accumulating_fact%rowtype;
+  begin
+    if new.state = 'pending' then
+      -- The pending state is the first state which is handled by the
+      -- wh_insert_session trigger. The update statement in this trigger will
+      -- fail for the pending state because the row for the session has not yet
+      -- been inserted into the wh_session_accumulating_fact table.
+      return null;
+    end if;
+
+    date_col = 'session_' || new.state || '_date_id';
+    time_col = 'session_' || new.state || '_time_id';
+    ts_col   = 'session_' || new.state || '_time';
+
+    q = format('update wh_session_accumulating_fact
+                   set (%I, %I, %I) = (select wh_date_id(%L), wh_time_id(%L), %L::timestamptz)
+                 where session_id = %L
+                returning *',
+                date_col,       time_col,       ts_col,
+                new.start_time, new.start_time, new.start_time,
+                new.session_id);
+    execute q into strict session_row;
+
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger wh_insert_session_state
+    after insert on session_state
+    for each row
+    execute function wh_insert_session_state();
+
+  --
+  -- Session Connection State trigger
+  --
+
+  -- wh_insert_session_connection_state returns an after insert trigger for the
+  -- session_connection_state table which updates
+  -- wh_session_connection_accumulating_fact.
+  create or replace function wh_insert_session_connection_state()
+    returns trigger
+  as $$
+  declare
+    date_col text;
+    time_col text;
+    ts_col text;
+    q text;
+    connection_row wh_session_connection_accumulating_fact%rowtype;
+  begin
+    if new.state = 'authorized' then
+      -- The authorized state is the first state which is handled by the
+      -- wh_insert_session_connection trigger. The update statement in this
+      -- trigger will fail for the authorized state because the row for the
+      -- session connection has not yet been inserted into the
+      -- wh_session_connection_accumulating_fact table.
+      return null;
+    end if;
+
+    date_col = 'connection_' || new.state || '_date_id';
+    time_col = 'connection_' || new.state || '_time_id';
+    ts_col   = 'connection_' || new.state || '_time';
+
+    q = format('update wh_session_connection_accumulating_fact
+                   set (%I, %I, %I) = (select wh_date_id(%L), wh_time_id(%L), %L::timestamptz)
+                 where connection_id = %L
+                returning *',
+                date_col,       time_col,       ts_col,
+                new.start_time, new.start_time, new.start_time,
+                new.connection_id);
+    execute q into strict connection_row;
+
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger wh_insert_session_connection_state
+    after insert on session_connection_state
+    for each row
+    execute function wh_insert_session_connection_state();
+
+commit;
+
+`),
+	},
+	"migrations/70_oplog_staging.down.sql": {
+		name: "70_oplog_staging.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table oplog_entry_staging;
+
+commit;
+
+`),
+	},
+	"migrations/70_oplog_staging.up.sql": {
+		name: "70_oplog_staging.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- oplog_entry_staging holds oplog entries written while the controller is
+  -- in asynchronous oplog mode. An entry is inserted in the same transaction
+  -- as the change it describes, so it only becomes visible if that change is
+  -- committed. A background worker encrypts staged entries, writes them to
+  -- oplog_entry in id order and deletes them from this table. The data is not
+  -- encrypted while an entry is staged.
+  create table oplog_entry_staging (
+    id bigint generated always as identity primary key,
+    create_time wt_timestamp,
+    version text not null,
+    aggregate_name text not null,
+    -- the scope whose oplog key is used to encrypt the entry. Staged entries
+    -- are deleted with their scope, whose keys are required to encrypt them.
+    scope_id wt_scope_id not null
+      references iam_scope(public_id)
+      on delete cascade
+      on update cascade,
+    "data" bytea not null,
+    -- json encoded list of the entry's metadata key/value pairs
+    metadata bytea not null
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on oplog_entry_staging
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_entry_staging
+    for each row execute procedure immutable_columns('id', 'create_time', 'version', 'aggregate_name', 'scope_id', 'data', 'metadata');
+
+commit;
+
+`),
+	},
+	"migrations/71_auth_token_attenuation.down.sql": {
+		name: "71_auth_token_attenuation.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop trigger delete_derived_auth_tokens on auth_token;
+  drop function delete_derived_auth_tokens;
+  drop table auth_token_attenuation;
+
+commit;
+
+`),
+	},
+	"migrations/71_auth_token_attenuation.up.sql": {
+		name: "71_auth_token_attenuation.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- auth_token_attenuation records the restrictions of an auth token derived
+  -- from a parent auth token by an exchange. An auth token without a row in
+  -- this table is not restricted.
+  create table auth_token_attenuation (
+    auth_token_id wt_public_id primary key
+      references auth_token(public_id)
+      on delete cascade
+      on update cascade,
+    -- derived tokens are deleted by the delete_derived_auth_tokens trigger
+    -- before their parent, so a derived token never outlives its parent and
+    -- never loses its restrictions.
+    parent_id wt_public_id not null
+      references auth_token(public_id)
+      on delete restrict
+      on update cascade,
+    -- the json encoded list of the scope ids and grants the derived token is
+    -- limited to.
+    grants text not null,
+    -- if not empty, the derived token may only be used for this target.
+    target_id text,
+    create_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on auth_token_attenuation
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_token_attenuation
+    for each row execute procedure immutable_columns('auth_token_id', 'parent_id', 'grants', 'target_id', 'create_time');
+
+  create or replace function
+    delete_derived_auth_tokens()
+    returns trigger
+  as $$
+  begin
+    delete from auth_token
+     where public_id in (
+       select auth_token_id
+         from auth_token_attenuation
+        where parent_id = old.public_id
+     );
+    return old;
+  end;
+  $$ language plpgsql;
+
+  comment on function
+    delete_derived_auth_tokens()
+  is
+    'function used in before delete triggers to delete the auth tokens derived from an auth token';
+
+  create trigger
+    delete_derived_auth_tokens
+  before delete on auth_token
+    for each row execute procedure delete_derived_auth_tokens();
+
+commit;
+
+`),
+	},
+	"migrations/72_target_connection_authorization.down.sql": {
+		name: "72_target_connection_authorization.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table target_connection_authorization;
 
- -- define the immutable fields for kms_database_key_version (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_database_key_version
-  for each row execute procedure immutable_columns('private_id', 'database_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
-  
-create trigger 
-  default_create_time_column
-before
-insert on kms_database_key_version
-  for each row execute procedure default_create_time();
+commit;
 
-create trigger
-	kms_version_column
-before insert on kms_database_key_version
-	for each row execute procedure kms_version_column('database_key_id');
+`),
+	},
+	"migrations/72_target_connection_authorization.up.sql": {
+		name: "72_target_connection_authorization.up.sql",
+		bytes: []byte(`
+begin;
 
-create table kms_oplog_key (
-  private_id wt_private_id primary key,
-  root_key_id wt_private_id not null unique -- there can be only one oplog dek per root key
-    references kms_root_key(private_id)
-    on delete cascade
-    on update cascade,
-  create_time wt_timestamp
-);
+  -- target_connection_authorization records the targets which require each
+  -- new connection to a session to be authorized again, so revoking a
+  -- permission applies to new connections of sessions already established.
+  -- A target without a row in this table only authorizes the session.
+  create table target_connection_authorization (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    create_time wt_timestamp
+  );
 
- -- define the immutable fields for kms_oplog_key (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_oplog_key
-  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+  create trigger
+    default_create_time_column
+  before insert on target_connection_authorization
+    for each row execute procedure default_create_time();
 
-create trigger 
-  default_create_time_column
-before
-insert on kms_oplog_key
-  for each row execute procedure default_create_time();
+  create trigger
+    immutable_columns
+  before
+  update on target_connection_authorization
+    for each row execute procedure immutable_columns('target_id', 'create_time');
 
-create table kms_oplog_key_version (
-  private_id wt_private_id primary key,
-  oplog_key_id wt_private_id not null
-    references kms_oplog_key(private_id) 
-    on delete cascade 
-    on update cascade, 
-  root_key_version_id wt_private_id not null
-    references kms_root_key_version(private_id) 
-    on delete cascade 
-    on update cascade,
-  version wt_version,
-  key bytea not null,
-  create_time wt_timestamp,
-  unique(oplog_key_id, version)
-);
+commit;
 
- -- define the immutable fields for kms_oplog_key_version (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_oplog_key_version
-  for each row execute procedure immutable_columns('private_id', 'oplog_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
-  
-create trigger 
-  default_create_time_column
-before
-insert on kms_oplog_key_version
-  for each row execute procedure default_create_time();
+`),
+	},
+	"migrations/73_annotations.down.sql": {
+		name: "73_annotations.down.sql",
+		bytes: []byte(`
+begin;
 
-create trigger
-	kms_version_column
-before insert on kms_oplog_key_version
-	for each row execute procedure kms_version_column('oplog_key_id');
+  drop trigger delete_annotations on iam_scope;
+  drop trigger delete_annotations on iam_user;
+  drop trigger delete_annotations on iam_group;
+  drop trigger delete_annotations on iam_role;
+  drop trigger delete_annotations on auth_method;
+  drop trigger delete_annotations on auth_account;
+  drop trigger delete_annotations on auth_token;
+  drop trigger delete_annotations on host_catalog;
+  drop trigger delete_annotations on host;
+  drop trigger delete_annotations on host_set;
+  drop trigger delete_annotations on target;
+  drop trigger delete_annotations on session;
+  drop function delete_annotations;
+  drop table annotation;
 
-create table kms_session_key (
-  private_id wt_private_id primary key,
-  root_key_id wt_private_id not null unique -- there can be only one session dek per root key
-    references kms_root_key(private_id)
-    on delete cascade
-    on update cascade,
-  create_time wt_timestamp
-);
+commit;
 
- -- define the immutable fields for kms_oplog_key (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_session_key
-  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+`),
+	},
+	"migrations/73_annotations.up.sql": {
+		name: "73_annotations.up.sql",
+		bytes: []byte(`
+begin;
 
-create trigger 
-  default_create_time_column
-before
-insert on kms_session_key
-  for each row execute procedure default_create_time();
+  -- annotation stores custom metadata of resources, so external systems can
+  -- record e.g. ownership or cost center of a resource. Since any resource can
+  -- be annotated resource_id is not a foreign key; annotations are deleted
+  -- with their resource by the delete_annotations triggers below.
+  create table annotation (
+    resource_id wt_public_id not null,
+    key text not null
+      constraint annotation_key_must_not_be_empty
+      check(length(trim(key)) > 0)
+      constraint annotation_key_must_not_be_too_long
+      check(length(key) <= 128),
+    value text not null
+      constraint annotation_value_must_not_be_too_long
+      check(length(value) <= 1024),
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    primary key(resource_id, key)
+  );
 
-create table kms_session_key_version (
-  private_id wt_private_id primary key,
-  session_key_id wt_private_id not null
-    references kms_session_key(private_id) 
-    on delete cascade 
-    on update cascade, 
-  root_key_version_id wt_private_id not null
-    references kms_root_key_version(private_id) 
-    on delete cascade 
-    on update cascade,
-  version wt_version,
-  key bytea not null,
-  create_time wt_timestamp,
-  unique(session_key_id, version)
-);
+  create trigger
+    default_create_time_column
+  before insert on annotation
+    for each row execute procedure default_create_time();
 
+  create trigger
+    update_time_column
+  before update on annotation
+    for each row execute procedure update_time_column();
 
- -- define the immutable fields for kms_session_key_version (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_session_key_version
-  for each row execute procedure immutable_columns('private_id', 'session_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
-  
-create trigger 
-  default_create_time_column
-before
-insert on kms_session_key_version
-  for each row execute procedure default_create_time();
+  create trigger
+    immutable_columns
+  before
+  update on annotation
+    for each row execute procedure immutable_columns('resource_id', 'key', 'create_time');
 
-create trigger
-	kms_version_column
-before insert on kms_session_key_version
-	for each row execute procedure kms_version_column('session_key_id');
+  create or replace function
+    delete_annotations()
+    returns trigger
+  as $$
+  begin
+    delete from annotation
+     where resource_id = old.public_id;
+    return null;
+  end;
+  $$ language plpgsql;
 
-create table kms_token_key (
-  private_id wt_private_id primary key,
-  root_key_id wt_private_id not null unique -- there can be only one token dek per root key
-    references kms_root_key(private_id)
-    on delete cascade
-    on update cascade,
-  create_time wt_timestamp
-);
+  comment on function
+    delete_annotations()
+  is
+    'function used in after delete triggers to delete the annotations of a resource';
 
- -- define the immutable fields for kms_token_key (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_token_key
-  for each row execute procedure immutable_columns('private_id', 'root_key_id', 'create_time');
+  create trigger
+    delete_annotations
+  after delete on iam_scope
+    for each row execute procedure delete_annotations();
 
-create trigger 
-  default_create_time_column
-before
-insert on kms_token_key
-  for each row execute procedure default_create_time();
+  create trigger
+    delete_annotations
+  after delete on iam_user
+    for each row execute procedure delete_annotations();
 
-create table kms_token_key_version (
-  private_id wt_private_id primary key,
-  token_key_id wt_private_id not null
-    references kms_token_key(private_id) 
-    on delete cascade 
-    on update cascade, 
-  root_key_version_id wt_private_id not null
-    references kms_root_key_version(private_id) 
-    on delete cascade 
-    on update cascade,
-  version wt_version,
-  key bytea not null,
-  create_time wt_timestamp,
-  unique(token_key_id, version)
-);
+  create trigger
+    delete_annotations
+  after delete on iam_group
+    for each row execute procedure delete_annotations();
 
- -- define the immutable fields for kms_token_key_version (all of them)
-create trigger 
-  immutable_columns
-before
-update on kms_token_key_version
-  for each row execute procedure immutable_columns('private_id', 'token_key_id', 'root_key_version_id', 'version', 'key', 'create_time');
-  
-create trigger 
-  default_create_time_column
-before
-insert on kms_token_key_version
-  for each row execute procedure default_create_time();
+  create trigger
+    delete_annotations
+  after delete on iam_role
+    for each row execute procedure delete_annotations();
 
-create trigger
-	kms_version_column
-before insert on kms_token_key_version
-	for each row execute procedure kms_version_column('token_key_id');
+  create trigger
+    delete_annotations
+  after delete on auth_method
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on auth_account
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on auth_token
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on host_catalog
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on host
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on host_set
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on target
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on session
+    for each row execute procedure delete_annotations();
 
-  insert into oplog_ticket
-    (name, version)
-  values
-    ('kms_root_key', 1),
-    ('kms_root_key_version', 1);
-    
 commit;
+
 `),
 	},
-	"migrations/40_targets.down.sql": {
-		name: "40_targets.down.sql",
+	"migrations/74_outbox.down.sql": {
+		name: "74_outbox.down.sql",
 		bytes: []byte(`
 begin;
 
-drop function insert_target_subtype;
-drop function delete_target_subtype;
-drop function target_scope_valid;
-drop function target_host_set_scope_valid
+  drop table outbox_message;
 
 commit;
 
 `),
 	},
-	"migrations/40_targets.up.sql": {
-		name: "40_targets.up.sql",
+	"migrations/74_outbox.up.sql": {
+		name: "74_outbox.up.sql",
 		bytes: []byte(`
 begin;
 
+  -- outbox_message holds external side effects, e.g. webhooks or events,
+  -- enqueued by repository operations. A message is inserted in the same
+  -- transaction as the change it notifies about, so it is only delivered if
+  -- that change is committed. A background dispatcher delivers messages in id
+  -- order, deletes delivered messages and reschedules failed deliveries with
+  -- backoff until they succeed or exceed their maximum attempts.
+  create table outbox_message (
+    id bigint generated always as identity primary key,
+    create_time wt_timestamp,
+    kind text not null
+      constraint outbox_message_kind_must_not_be_empty
+      check(length(trim(kind)) > 0),
+    payload bytea not null,
+    attempts integer not null default 0
+      constraint outbox_message_attempts_must_not_be_negative
+      check(attempts >= 0),
+    next_attempt_time timestamp with time zone not null default current_timestamp,
+    last_error text,
+    -- set when the message exceeded its maximum delivery attempts; failed
+    -- messages are no longer delivered and are kept for inspection.
+    failed_time timestamp with time zone
+  );
 
--- insert_target_subtype() is a before insert trigger
--- function for subtypes of target
-create or replace function
-  insert_target_subtype()
-  returns trigger
-as $$
-begin
-  insert into target
-    (public_id, scope_id)
-  values
-    (new.public_id, new.scope_id);
-  return new;
-end;
-$$ language plpgsql;
-
--- delete_target_subtype() is an after delete trigger
--- function for subtypes of host
-create or replace function delete_target_subtype()
-  returns trigger
-as $$
-begin
-  delete from target
-  where 
-    public_id = old.public_id;
-  return null; -- result is ignored since this is an after trigger
-end;
-$$ language plpgsql;
+  create index outbox_message_next_attempt_time_ix
+    on outbox_message (next_attempt_time)
+    where failed_time is null;
 
--- target_scope_valid() is a before insert trigger function for target
-create or replace function
-  target_scope_valid()
-  returns trigger
-as $$
-declare scope_type text;
-begin
-  -- Fetch the type of scope
-  select isc.type from iam_scope isc where isc.public_id = new.scope_id into scope_type;
-  if scope_type = 'project' then
-    return new;
-  end if;
-  raise exception 'invalid target scope type % (must be project)', scope_type;
-end;
-$$ language plpgsql;
+  create trigger
+    default_create_time_column
+  before
+  insert on outbox_message
+    for each row execute procedure default_create_time();
 
--- target_host_set_scope_valid() is a before insert trigger function for target_host_set 
-create or replace function
-  target_host_set_scope_valid()
-  returns trigger
-as $$
-begin
-    perform from
-      host_catalog hc,
-      host_set hs,
-      target t,
-      iam_scope s
-    where
-      hc.public_id = hs.catalog_id and 
-      hc.scope_id = t.scope_id and
-      t.public_id = new.target_id;
-if not found then
-  raise exception 'target scope and host set scope are not equal';
-end if;
-return new;
-end;
-$$ language plpgsql;
+  create trigger
+    immutable_columns
+  before
+  update on outbox_message
+    for each row execute procedure immutable_columns('id', 'create_time', 'kind', 'payload');
 
 commit;
+
 `),
 	},
-	"migrations/41_targets.down.sql": {
-		name: "41_targets.down.sql",
+	"migrations/75_schema_migration_record.down.sql": {
+		name: "75_schema_migration_record.down.sql",
 		bytes: []byte(`
 begin;
 
-drop table target cascade;
-drop table target_host_set cascade;
-drop table target_tcp;
-drop view target_all_subtypes;
-drop view target_host_set_catalog;
-
-
-delete
-from oplog_ticket
-where name in (
-        'target_tcp'
-    );
+  drop table schema_migration_record;
 
 commit;
+
 `),
 	},
-	"migrations/41_targets.up.sql": {
-		name: "41_targets.up.sql",
+	"migrations/75_schema_migration_record.up.sql": {
+		name: "75_schema_migration_record.up.sql",
 		bytes: []byte(`
-/*
-                                   ┌─────────────────┐
-┌─────────────────┐                │   target_tcp    │
-│     target      │                ├─────────────────┤
-├─────────────────┤                │public_id        │
-│public_id        │┼─────────────○┼│scope_id         │
-│scope_id         │                │default_port     │
-│                 │                │name (not null)  │
-└─────────────────┘                │description      │
-         ┼                         └─────────────────┘
-         │                                            
-         ○                                            
-        ╱│╲                                           
-┌─────────────────┐                                   
-│ target_host_set │                                   
-├─────────────────┤                                   
-│target_id        │                                   
-│host_set_id      │                                   
-│                 │                                   
-└─────────────────┘                                   
-        ╲│╱                                           
-         ○                                            
-         │                                            
-         │                                            
-         ┼                                            
-┌─────────────────┐                                   
-│    host_set     │                                   
-├─────────────────┤                                   
-│public_id        │                                   
-│catalog_id       │                                   
-│                 │                                   
-└─────────────────┘                                                                            
-
-*/
-
 begin;
 
-create table target (
-  public_id wt_public_id primary key,
-  scope_id wt_scope_id not null 
-    references iam_scope(public_id) 
-    on delete cascade 
-    on update cascade,
-  create_time wt_timestamp
-);
-
-create trigger 
-  immutable_columns
-before
-update on target
-  for each row execute procedure immutable_columns('public_id', 'scope_id', 'create_time');
-
-create trigger 
-  default_create_time_column
-before
-insert on target
-  for each row execute procedure default_create_time();
-
-create trigger 
-  target_scope_valid
-before insert on target
-  for each row execute procedure target_scope_valid();
-
+  -- schema_migration_record records the migrations applied to the database by
+  -- InitStore: the checksum of each sql migration, so that a changed migration
+  -- is detected before further migrations are applied, and each go data
+  -- migration, so that it only runs once. Sql migrations applied before this
+  -- table existed are recorded with the checksums of the binary which first
+  -- runs InitStore after it is created.
+  create table schema_migration_record (
+    version bigint not null
+      constraint schema_migration_record_version_must_be_positive
+      check(version > 0),
+    kind text not null
+      constraint schema_migration_record_kind_must_be_sql_or_data
+      check(kind in ('sql', 'data')),
+    name text not null
+      constraint schema_migration_record_name_must_not_be_empty
+      check(length(trim(name)) > 0),
+    -- hex encoded sha256 of the up migration; null for data migrations
+    checksum text
+      constraint schema_migration_record_checksum_required_for_sql
+      check((kind = 'sql') = (checksum is not null)),
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    primary key(version, kind)
+  );
 
-create table target_host_set(
-  target_id wt_public_id
-    references target(public_id)
-    on delete cascade
-    on update cascade,
-  host_set_id wt_public_id
-    references host_set(public_id)
-    on delete cascade
-    on update cascade,
-  primary key(target_id, host_set_id),
-  create_time wt_timestamp
-);
+  create trigger
+    default_create_time_column
+  before
+  insert on schema_migration_record
+    for each row execute procedure default_create_time();
 
-create trigger 
-  immutable_columns
-before
-update on target_host_set
-  for each row execute procedure immutable_columns('target_id', 'host_set_id', 'create_time');
+  create trigger
+    update_time_column
+  before update on schema_migration_record
+    for each row execute procedure update_time_column();
 
-create trigger 
-  target_host_set_scope_valid
-before
-insert on target_host_set
-  for each row execute procedure target_host_set_scope_valid();
+  create trigger
+    immutable_columns
+  before
+  update on schema_migration_record
+    for each row execute procedure immutable_columns('version', 'kind', 'create_time');
 
-create table target_tcp (
-  public_id wt_public_id primary key
-    references target(public_id)
-    on delete cascade
-    on update cascade,
-  scope_id wt_scope_id not null 
-    references iam_scope(public_id) 
-    on delete cascade 
-    on update cascade,
-  name text not null, -- name is not optional for a target subtype
-  description text,
-  default_port int, -- default_port can be null
-   -- max duration of the session in seconds.
-   -- default is 8 hours
-  session_max_seconds int not null default 28800
-    constraint session_max_seconds_must_be_greater_than_0
-    check(session_max_seconds > 0),
-  -- limit on number of session connections allowed. -1 equals no limit
-  session_connection_limit int not null default 1
-    constraint session_connection_limit_must_be_greater_than_0_or_negative_1
-    check(session_connection_limit > 0 or session_connection_limit = -1),
-  create_time wt_timestamp,
-  update_time wt_timestamp,
-  version wt_version,
-  unique(scope_id, name) -- name must be unique within a scope
-);
+commit;
 
-create trigger
-  insert_target_subtype
-before insert on target_tcp
-  for each row execute procedure insert_target_subtype();
+`),
+	},
+	"migrations/76_auth_password_recovery_code.down.sql": {
+		name: "76_auth_password_recovery_code.down.sql",
+		bytes: []byte(`
+begin;
 
-create trigger
-  delete_target_subtype
-after delete on target_tcp
-  for each row execute procedure delete_target_subtype();
+  drop table auth_password_recovery_code;
+  drop function auth_password_recovery_code_used_once;
 
- -- define the immutable fields for target 
-create trigger 
-  immutable_columns
-before
-update on target_tcp
-  for each row execute procedure immutable_columns('public_id', 'scope_id', 'create_time');
+commit;
 
-create trigger
-  update_version_column
-after update on target_tcp
-  for each row execute procedure update_version_column();
+`),
+	},
+	"migrations/76_auth_password_recovery_code.up.sql": {
+		name: "76_auth_password_recovery_code.up.sql",
+		bytes: []byte(`
+begin;
 
-create trigger
-  update_time_column
-before update on target_tcp
-  for each row execute procedure update_time_column();
+  -- auth_password_recovery_code holds the single use recovery codes of a
+  -- password account, which authenticate as the account if its password is
+  -- lost. Only a salted hash of each code is stored. A code is used by setting
+  -- its used_time, which can only be done once.
+  create table auth_password_recovery_code (
+    private_id wt_private_id primary key,
+    password_account_id wt_public_id not null
+      references auth_password_account(public_id)
+      on delete cascade
+      on update cascade,
+    salt bytea not null
+      constraint auth_password_recovery_code_salt_must_not_be_empty
+      check(length(salt) > 0),
+    code_hash bytea not null
+      constraint auth_password_recovery_code_code_hash_must_not_be_empty
+      check(length(code_hash) > 0),
+    create_time wt_timestamp,
+    used_time timestamp with time zone
+  );
 
-create trigger 
-  default_create_time_column
-before
-insert on target_tcp
-  for each row execute procedure default_create_time();
+  create index auth_password_recovery_code_password_account_id_ix
+    on auth_password_recovery_code (password_account_id)
+    where used_time is null;
 
-create trigger 
-  target_scope_valid
-before insert on target_tcp
-  for each row execute procedure target_scope_valid();
+  create trigger
+    default_create_time_column
+  before
+  insert on auth_password_recovery_code
+    for each row execute procedure default_create_time();
 
+  create trigger
+    immutable_columns
+  before
+  update on auth_password_recovery_code
+    for each row execute procedure immutable_columns('private_id', 'password_account_id', 'salt', 'code_hash', 'create_time');
 
--- target_all_subtypes is a union of all target subtypes 
-create view target_all_subtypes
-as 
-select 
-  public_id, 
-  scope_id, 
-  name, 
-  description, 
-  default_port, 
-  session_max_seconds,
-  session_connection_limit,
-  version, 
-  create_time,
-  update_time,
-  'tcp' as type
-  from target_tcp;
+  create or replace function
+    auth_password_recovery_code_used_once()
+    returns trigger
+  as $$
+  begin
+    if old.used_time is not null then
+      raise exception 'recovery code % was already used', old.private_id;
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
 
-create view target_set
-as
-select 
-  hs.public_id,
-  hs.catalog_id,
-  ths.target_id
-from
-  target_host_set ths,
-  host_set hs
-where
-  hs.public_id = ths.host_set_id;
+  create trigger
+    auth_password_recovery_code_used_once
+  before
+  update on auth_password_recovery_code
+    for each row execute procedure auth_password_recovery_code_used_once();
 
-insert into oplog_ticket
-  (name, version)
-values
-  ('target_tcp', 1);
+commit;
+
+`),
+	},
+	"migrations/77_auth_token_refresh.down.sql": {
+		name: "77_auth_token_refresh.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table auth_token_refresh;
 
 commit;
 
 `),
 	},
-	"migrations/50_session.down.sql": {
-		name: "50_session.down.sql",
+	"migrations/77_auth_token_refresh.up.sql": {
+		name: "77_auth_token_refresh.up.sql",
 		bytes: []byte(`
 begin;
 
-  drop table session_state;
-  drop table session_state_enm;
-  drop table session;
-  drop table session_termination_reason_enm;
-  drop function insert_session_state;
-  drop function insert_new_session_state;
-  drop function insert_session;
-  drop function update_session_state_on_termination_reason;
-  drop function insert_session_state;
+  -- auth_token_refresh records the login time of an auth token issued by
+  -- refreshing another auth token. The login time is carried over from token
+  -- to token, so refreshing can't extend a login beyond the maximum lifetime
+  -- of auth tokens. An auth token without a row in this table was issued by
+  -- authenticating and its login time is its create time.
+  create table auth_token_refresh (
+    auth_token_id wt_public_id primary key
+      references auth_token(public_id)
+      on delete cascade
+      on update cascade,
+    -- the public id of the auth token this token was refreshed from. it is
+    -- not a foreign key since the refreshed token expires independently.
+    refreshed_from_id wt_public_id not null,
+    login_time timestamp with time zone not null,
+    create_time wt_timestamp
+  );
 
+  create trigger
+    default_create_time_column
+  before insert on auth_token_refresh
+    for each row execute procedure default_create_time();
 
-  delete
-  from oplog_ticket
-  where name in (
-          'session'
-      );
+  create trigger
+    immutable_columns
+  before
+  update on auth_token_refresh
+    for each row execute procedure immutable_columns('auth_token_id', 'refreshed_from_id', 'login_time', 'create_time');
 
 commit;
 
 `),
 	},
-	"migrations/50_session.up.sql": {
-		name: "50_session.up.sql",
+	"migrations/78_target_bandwidth_limit.down.sql": {
+		name: "78_target_bandwidth_limit.down.sql",
 		bytes: []byte(`
 begin;
 
-/*
+  drop table target_bandwidth_limit;
 
-             ┌─────────────────┐               ┌─────────────────┐   ┌─────────────────┐
-             │  iam_scope_org  │               │    iam_user     │   │   auth_token    │
-             ├─────────────────┤               ├─────────────────┤   ├─────────────────┤
-             │ public_id  (pk) │               │ public_id (pk)  │   │ public_id  (pk) │
-             │                 │               │                 │   │                 │
-             └─────────────────┘               └─────────────────┘   └─────────────────┘
-                 ▲fk7 ┼                            ▲fk1 ┼                ▲fk6 ┼
-                      ┼                                 ┼                     ┼
-                      ├─────────────────────────────────┴─────────────────────┘
-                      │
-                      ○
-                     ╱│╲
-        ┌──────────────────────────┐          ┌─────────────────┐   ┌─────────────────┐
-        │         session          │╲  fk3▶   │     server      │   │     target      │
-        ├──────────────────────────┤─○──────○┼├─────────────────┤   ├─────────────────┤
-        │ public_id          (pk)  │╱         │ private_id (pk) │   │ public_id  (pk) │
-        │ user_id            (fk1) │          │ type       (pk) │   │                 │
-        │ host_id            (fk2) │          └─────────────────┘   └─────────────────┘
-        │ server_id          (fk3) │                                    ▲fk4 ┼
-        │ server_type        (fk3) │╲                                        ┼
-        │ target_id          (fk4) │─○─────────────────┬─────────────────────┤
-        │ host_set_id        (fk5) │╱                  ┼                     ┼
-        │ auth_token_id      (fk6) │              ▼fk5 ┼                ▼fk2 ┼
-        │ scope_id           (fk7) │          ┌─────────────────┐   ┌─────────────────┐
-        │ termination_reason (fk8) │          │    host_set     │   │      host       │
-        └──────────────────────────┘          ├─────────────────┤   ├─────────────────┤
-                 ▲fk1 ┼           ╲│╱         │ public_id  (pk) │   │ public_id  (pk) │
-                      ┼            ○          │                 │   │                 │
-                      │            │          └─────────────────┘   └─────────────────┘
-                      │            │
-                      └─┐          │
-                        │          │            ┌───────────────────────────────┐
-                        │          │            │session_termination_reason_enm │
-                        │          │     fk8▶   ├───────────────────────────────┤
-                        ┼          └──────────○┼│ name                          │
-                       ╱│╲                      └───────────────────────────────┘
-  ┌──────────────────────────────────────────┐
-  │              session_state               │
-  ├──────────────────────────────────────────┤┼○┐
-  │ session_id        (pk,fk1,fk2,unq1,unq2) │  │◀fk2
-  │ state             (fk3)                  │  │
-  │ previous_end_time (fk2,unq1)             │┼○┘
-  │ start_time        (pk)                   │
-  │ end_time          (unq2)                 │
-  └──────────────────────────────────────────┘
-                       ╲│╱
-                        ○
-                        │
-                        │
-                        ┼
-                  ▼fk3  ┼
-        ┌───────────────────────────────┐
-        │       session_state_enm       │
-        ├───────────────────────────────┤
-        │ name                          │
-        └───────────────────────────────┘
+commit;
 
-*/
+`),
+	},
+	"migrations/78_target_bandwidth_limit.up.sql": {
+		name: "78_target_bandwidth_limit.up.sql",
+		bytes: []byte(`
+begin;
 
-  create table session_termination_reason_enm (
-    name text primary key
-      constraint only_predefined_session_termination_reasons_allowed
-      check (
-        name in (
-          'unknown',
-          'timed out',
-          'closed by end-user',
-          'terminated',
-          'network error',
-          'system error',
-          'connection limit',
-          'canceled'
-        )
-      )
+  -- target_bandwidth_limit records the bandwidth caps of targets, enforced by
+  -- workers when proxying their connections. A limit of 0 is unlimited. A
+  -- target without a row in this table is not limited.
+  create table target_bandwidth_limit (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    connection_bytes_per_second bigint not null default 0
+      check(connection_bytes_per_second >= 0),
+    session_bytes_per_second bigint not null default 0
+      check(session_bytes_per_second >= 0),
+    create_time wt_timestamp,
+    update_time wt_timestamp
   );
 
-  insert into session_termination_reason_enm (name)
-  values
-    ('unknown'),
-    ('timed out'),
-    ('closed by end-user'),
-    ('terminated'),
-    ('network error'),
-    ('system error'),
-    ('connection limit'),
-    ('canceled');
+  create trigger
+    default_create_time_column
+  before insert on target_bandwidth_limit
+    for each row execute procedure default_create_time();
 
-  create table session (
-    public_id wt_public_id primary key,
-    -- the user of the session
-    user_id text -- fk1
-      -- not using the wt_user_id domain type because it is marked 'not null'
-      references iam_user (public_id)
-      on delete set null
-      on update cascade,
-    -- the host the user is connected to via this session
-    host_id wt_public_id -- fk2
-      references host (public_id)
-      on delete set null
-      on update cascade,
-    -- the worker proxying the connection between the user and the host
-    server_id text, -- fk3
-    server_type text,-- fk3
-    foreign key (server_id, server_type)
-      references server (private_id, type)
-      on delete set null
-      on update cascade,
-    -- the target the host was chosen from and the user was authorized to
-    -- connect to
-    target_id wt_public_id -- fk4
-      references target (public_id)
-      on delete set null
-      on update cascade,
-    -- the host set the host was chosen from and the user was authorized to
-    -- connect to via the target
-    host_set_id wt_public_id -- fk5
-      references host_set (public_id)
-      on delete set null
-      on update cascade,
-    -- the auth token of the user when this session was created
-    auth_token_id wt_public_id -- fk6
-      references auth_token (public_id)
-      on delete set null
-      on update cascade,
-    -- the project which owns this session
-    scope_id wt_scope_id -- fk7
-      references iam_scope_project (scope_id)
-      on delete set null
-      on update cascade,
-    -- Certificate to use when connecting (or if using custom certs, to
-	  -- serve as the "login"). Raw DER bytes.  
-    certificate bytea not null,
-    -- after this time the connection will be expired, e.g. forcefully terminated
-    expiration_time wt_timestamp, -- maybe null
-    -- limit on number of session connections allowed.  default of 0 equals no limit
-    connection_limit int not null default 1
-      constraint connection_limit_must_be_greater_than_0_or_negative_1
-      check(connection_limit > 0 or connection_limit = -1), 
-    -- trust of first use token 
-    tofu_token bytea, -- will be null when session is first created
-    -- the reason this session ended (null until terminated)
-     -- TODO: Make key_id a foreign key once we have DEKs
-    key_id text, -- will be null on insert
-      -- references kms_database_key_version(private_id) 
-      -- on delete restrict
-      -- on update cascade,
-    termination_reason text -- fk8
-      references session_termination_reason_enm (name)
+  create trigger
+    update_time_column
+  before update on target_bandwidth_limit
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on target_bandwidth_limit
+    for each row execute procedure immutable_columns('target_id', 'create_time');
+
+commit;
+
+`),
+	},
+	"migrations/79_server_worker_state.down.sql": {
+		name: "79_server_worker_state.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table server_worker_state;
+
+commit;
+
+`),
+	},
+	"migrations/79_server_worker_state.up.sql": {
+		name: "79_server_worker_state.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- server_worker_state records the state of workers from their last status
+  -- report, so controllers can take into account workers connected to other
+  -- controllers when authorizing sessions. The state is encrypted with the
+  -- global scope database key.
+  create table server_worker_state (
+    worker_id text not null,
+    worker_type text not null default 'worker'
+      constraint worker_type_must_be_worker
+      check(worker_type = 'worker'),
+    controller_id text not null
+      constraint controller_id_must_not_be_empty
+      check(length(trim(controller_id)) > 0),
+    key_id text not null
+      references kms_database_key_version(private_id)
       on delete restrict
       on update cascade,
-    version wt_version,
+    state bytea not null,
     create_time wt_timestamp,
     update_time wt_timestamp,
-    endpoint text -- not part of the warehouse, used to send info to the worker
+    primary key (worker_id),
+    foreign key (worker_id, worker_type)
+      references server(private_id, type)
+      on delete cascade
+      on update cascade
   );
 
-  create trigger 
+  create trigger
+    default_create_time_column
+  before insert on server_worker_state
+    for each row execute procedure default_create_time();
+
+  create trigger
     immutable_columns
   before
-  update on session
-    for each row execute procedure immutable_columns('public_id', 'certificate', 'expiration_time', 'connection_limit', 'create_time', 'endpoint');
-  
-  -- session table has some cascades of FK to null, so we need to be careful
-  -- which columns trigger an update of the version column
-  create trigger 
-    update_version_column 
-  after update of version, termination_reason, key_id, tofu_token, server_id, server_type on session
-    for each row execute procedure update_version_column();
-    
-  create trigger 
-    update_time_column 
-  before update on session 
-    for each row execute procedure update_time_column();
-    
-  create trigger 
+  update on server_worker_state
+    for each row execute procedure immutable_columns('worker_id', 'worker_type', 'create_time');
+
+commit;
+
+`),
+	},
+	"migrations/80_server_connection_check.down.sql": {
+		name: "80_server_connection_check.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table server_connection_check;
+
+commit;
+
+`),
+	},
+	"migrations/80_server_connection_check.up.sql": {
+		name: "80_server_connection_check.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- server_connection_check records checks of the connectivity from a worker
+  -- to the endpoint of a target, requested by admins before handing the target
+  -- to users. A check is dispatched to its worker in the response to a status
+  -- report of the worker, which reports the result with a later status.
+  create table server_connection_check (
+    check_id wt_private_id primary key,
+    target_id wt_public_id not null
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    worker_id text not null,
+    worker_type text not null default 'worker'
+      constraint worker_type_must_be_worker
+      check(worker_type = 'worker'),
+    endpoint text not null
+      constraint endpoint_must_not_be_empty
+      check(length(trim(endpoint)) > 0),
+    tls boolean not null default false,
+    dispatch_time timestamp with time zone,
+    complete_time timestamp with time zone,
+    reachable boolean not null default false,
+    latency_ms bigint not null default 0,
+    error text not null default '',
+    create_time wt_timestamp,
+    foreign key (worker_id, worker_type)
+      references server(private_id, type)
+      on delete cascade
+      on update cascade
+  );
+
+  create trigger
     default_create_time_column
-  before
-  insert on session
+  before insert on server_connection_check
     for each row execute procedure default_create_time();
 
-  create or replace function
-    insert_session()
-    returns trigger
-  as $$
-  begin
-    case 
-      when new.user_id is null then
-        raise exception 'user_id is null';
-      when new.host_id is null then
-        raise exception 'host_id is null';
-      when new.target_id is null then
-        raise exception 'target_id is null';
-      when new.host_set_id is null then
-        raise exception 'host_set_id is null';
-      when new.auth_token_id is null then
-        raise exception 'auth_token_id is null';
-      when new.scope_id is null then
-        raise exception 'scope_id is null';
-      when new.endpoint is null then
-        raise exception 'endpoint is null';
-    else
-    end case;
-    return new;
-  end;
-  $$ language plpgsql;
+  create trigger
+    immutable_columns
+  before
+  update on server_connection_check
+    for each row execute procedure immutable_columns('check_id', 'target_id', 'worker_id', 'worker_type', 'endpoint', 'tls', 'create_time');
 
-  create trigger 
-    insert_session
-  before insert on session
-    for each row execute procedure insert_session();
+commit;
 
-  create or replace function 
-    insert_new_session_state()
-    returns trigger
-  as $$
-  begin
-    insert into session_state (session_id, state)
-    values
-      (new.public_id, 'pending');
-    return new;
-  end;
-  $$ language plpgsql;
+`),
+	},
+	"migrations/81_iam_user_login.down.sql": {
+		name: "81_iam_user_login.down.sql",
+		bytes: []byte(`
+begin;
 
-  create trigger 
-    insert_new_session_state
-  after insert on session
-    for each row execute procedure insert_new_session_state();
+  drop table iam_user_disabled;
+  drop table iam_scope_inactivity_policy;
+  drop table iam_user_login;
 
-  -- update_connection_state_on_closed_reason() is used in an update insert trigger on the
-  -- session_connection table.  it will valiadate that all the session's
-  -- connections are closed, and then insert a state of "closed" in
-  -- session_connection_state for the closed session connection. 
-  create or replace function 
-    update_session_state_on_termination_reason()
-    returns trigger
-  as $$
-  begin
-    if new.termination_reason is not null then
-      perform  from 
-        session
-      where 
-        public_id = new.public_id and 
-        public_id not in (
-            select session_id 
-              from session_connection
-            where
-              public_id in (
-                select connection_id
-                  from session_connection_state
-                where 
-                  state != 'closed' and 
-                  end_time is null
-              )
-        );
-      if not found then 
-        raise 'session %s has open connections', new.public_id;
-      end if;
+commit;
 
-      -- check to see if there's a terminated state already, before inserting a
-      -- new one.
-      perform from
-        session_state ss
-      where
-        ss.session_id = new.public_id and 
-        ss.state = 'terminated';
-      if found then 
-        return new;
-      end if;
+`),
+	},
+	"migrations/81_iam_user_login.up.sql": {
+		name: "81_iam_user_login.up.sql",
+		bytes: []byte(`
+begin;
 
-      insert into session_state (session_id, state)
-      values
-        (new.public_id, 'terminated');
-      end if;
-      return new;
-  end;
-  $$ language plpgsql;
+  -- iam_user_login records the last successful authentication of a user with
+  -- each of its accounts. Tokens issued by refreshing or exchanging an auth
+  -- token are not logins.
+  create table iam_user_login (
+    iam_user_id wt_user_id not null
+      references iam_user(public_id)
+      on delete cascade
+      on update cascade,
+    auth_account_id wt_public_id not null
+      references auth_account(public_id)
+      on delete cascade
+      on update cascade,
+    last_login_time timestamp with time zone not null
+      default current_timestamp,
+    primary key(iam_user_id, auth_account_id)
+  );
 
+  create index iam_user_login_auth_account_id_ix
+    on iam_user_login (auth_account_id);
 
-  create trigger 
-    update_session_state_on_termination_reason
-  after update of termination_reason on session
-    for each row execute procedure update_session_state_on_termination_reason();
- 
+  -- iam_scope_inactivity_policy records how many days the users of a scope
+  -- may go without logging in before they are reported as inactive and, if
+  -- disable_inactive is set, disabled by the controllers. A user who never
+  -- logged in is inactive from its create time.
+  create table iam_scope_inactivity_policy (
+    scope_id wt_scope_id primary key
+      references iam_scope(public_id)
+      on delete cascade
+      on update cascade,
+    max_inactive_days integer not null
+      check(max_inactive_days > 0),
+    disable_inactive boolean not null default false,
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
 
-  -- cancel_session will insert a cancel state for the session, if there's isn't
-  -- a canceled state already.  It's used by cancel_session_with_null_fk.
-  create or replace function
-    cancel_session(in sessionId text) returns void 
-  as $$
-  declare
-    rows_affected numeric;
-  begin 
-    insert into session_state(session_id, state) 
-    select 
-	    sessionId::text, 'canceling' 
-    from
-      session s
-    where 
-      s.public_id = sessionId::text and
-      s.public_id not in (
-        select 
-          session_id 
-        from 
-          session_state 
-        where 
-          session_id = sessionId::text and 
-          state = 'canceling'
-      ) limit 1;
-      get diagnostics rows_affected = row_count;
-      if rows_affected > 1 then
-          raise exception 'cancel session: more than one row affected: %', rows_affected; 
-      end if;
-  end;
-  $$ language plpgsql;
+  create trigger
+    default_create_time_column
+  before insert on iam_scope_inactivity_policy
+    for each row execute procedure default_create_time();
 
-  -- cancel_session_with_null_fk is intended to be a before update trigger that
-  -- sets the session's state to cancel if a FK is set to null.
-  create or replace function 
-    cancel_session_with_null_fk()
-    returns trigger
-  as $$
-  begin
-   case 
-      when new.user_id is null then
-        perform cancel_session(new.public_id);
-      when new.host_id is null then
-        perform cancel_session(new.public_id);
-      when new.target_id is null then
-        perform cancel_session(new.public_id);
-      when new.host_set_id is null then
-        perform cancel_session(new.public_id);
-      when new.auth_token_id is null then
-        perform cancel_session(new.public_id);
-      when new.scope_id is null then
-        perform cancel_session(new.public_id);
-    end case;
-    return new;
-  end;
-  $$ language plpgsql;
+  create trigger
+    update_time_column
+  before update on iam_scope_inactivity_policy
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_scope_inactivity_policy
+    for each row execute procedure immutable_columns('scope_id', 'create_time');
+
+  -- iam_user_disabled records the users which may not authenticate. Their
+  -- auth tokens are deleted when they are disabled.
+  create table iam_user_disabled (
+    iam_user_id wt_user_id primary key
+      references iam_user(public_id)
+      on delete cascade
+      on update cascade,
+    reason text not null,
+    create_time wt_timestamp
+  );
 
-  create trigger 
-    cancel_session_with_null_fk
-  before update of user_id, host_id, target_id, host_set_id, auth_token_id, scope_id on session
-    for each row execute procedure cancel_session_with_null_fk();
+  create trigger
+    default_create_time_column
+  before insert on iam_user_disabled
+    for each row execute procedure default_create_time();
 
-  create table session_state_enm (
-    name text primary key
-      constraint only_predefined_session_states_allowed
-      check (
-        name in ('pending', 'active', 'canceling', 'terminated')
-      )
-  );
+  create trigger
+    immutable_columns
+  before
+  update on iam_user_disabled
+    for each row execute procedure immutable_columns('iam_user_id', 'reason', 'create_time');
 
-  insert into session_state_enm (name)
-  values
-    ('pending'),
-    ('active'),
-    ('canceling'),
-    ('terminated');
+commit;
 
-/*
+`),
+	},
+	"migrations/82_auth_account_disabled.down.sql": {
+		name: "82_auth_account_disabled.down.sql",
+		bytes: []byte(`
+begin;
 
+  drop trigger auth_token_principal_enabled on auth_token;
+  drop function auth_token_principal_enabled;
+  drop table auth_account_disabled;
 
-                                              ┌────────────────┐
-         start                                │                │
-           .                                  │   Canceling    │
-          (●)                           ┌────▶│                │─────┐
-           '                            │     │                │     │
-           │                            │     └────────────────┘     │
-           │                            │                            │
-           ▼                            │                            ▼
-  ┌────────────────┐           ┌────────────────┐           ┌────────────────┐
-  │                │           │                │           │                │
-  │    Pending     │           │     Active     │           │   Terminated   │
-  │                │──────────▶│                │──────────▶│                │
-  │                │           │                │           │                │
-  └────────────────┘           └────────────────┘           └────────────────┘
-           │                                                         │
-           │                                                         │
-           │                                                         │
-           │                                                         │
-           └──────────────────────▶  ◉ End  ◀────────────────────────┘
+commit;
 
-*/
+`),
+	},
+	"migrations/82_auth_account_disabled.up.sql": {
+		name: "82_auth_account_disabled.up.sql",
+		bytes: []byte(`
+begin;
 
-  -- Design influenced by:
-  -- Joe Celko's SQL for Smarties: Advanced SQL Programming, 5th edition
-  -- Chapter 12, p270
-  create table session_state (
-    session_id wt_public_id not null -- fk1, fk2
-      references session (public_id)
+  -- auth_account_disabled records the accounts which may not authenticate.
+  -- Their auth tokens are deleted when they are disabled. Like disabled users,
+  -- disabled accounts keep their user association so they can be enabled
+  -- again.
+  create table auth_account_disabled (
+    auth_account_id wt_public_id primary key
+      references auth_account(public_id)
       on delete cascade
       on update cascade,
-    state text not null -- fk3
-      references session_state_enm(name)
-      on delete restrict
-      on update cascade,
-    previous_end_time timestamp with time zone, -- fk2 -- null means first state
-    start_time timestamp with time zone default current_timestamp not null,
-      constraint previous_end_time_and_start_time_in_sequence
-        check (previous_end_time <= start_time),
-    end_time timestamp with time zone, -- null means unfinished current state
-      constraint start_and_end_times_in_sequence
-        check (start_time <= end_time),
-      constraint end_times_in_sequence
-        check (previous_end_time <> end_time),
-    primary key (session_id, start_time),
-    unique (session_id, previous_end_time), -- null means first state
-    unique (session_id, end_time), -- one null current state
-    foreign key (session_id, previous_end_time) -- self-reference
-      references session_state (session_id, end_time)
+    reason text not null,
+    create_time wt_timestamp
   );
 
+  create trigger
+    default_create_time_column
+  before insert on auth_account_disabled
+    for each row execute procedure default_create_time();
 
-  create trigger 
+  create trigger
     immutable_columns
   before
-  update on session_state
-    for each row execute procedure immutable_columns('session_id', 'state', 'start_time', 'previous_end_time');
+  update on auth_account_disabled
+    for each row execute procedure immutable_columns('auth_account_id', 'reason', 'create_time');
 
-    
   create or replace function
-    insert_session_state()
+    auth_token_principal_enabled()
     returns trigger
   as $$
   begin
-
-    update session_state
-       set end_time = now()
-     where session_id = new.session_id
-       and end_time is null;
-
-    if not found then
-      new.previous_end_time = null;
-      new.start_time = now();
-      new.end_time = null;
-      return new;
+    perform
+       from auth_account_disabled
+      where auth_account_id = new.auth_account_id;
+    if found then
+      raise exception 'auth account % is disabled', new.auth_account_id;
+    end if;
+    perform
+       from auth_account a
+       join iam_user_disabled d
+         on d.iam_user_id = a.iam_user_id
+      where a.public_id = new.auth_account_id;
+    if found then
+      raise exception 'iam user of auth account % is disabled', new.auth_account_id;
     end if;
-
-    new.previous_end_time = now();
-    new.start_time = now();
-    new.end_time = null;
     return new;
-
   end;
   $$ language plpgsql;
 
+  comment on function
+    auth_token_principal_enabled()
+  is
+    'function used in before insert triggers to prevent issuing auth tokens to disabled accounts and users';
 
-  create trigger insert_session_state before insert on session_state
-    for each row execute procedure insert_session_state();
-
-  create view session_with_state as
-  select
-    s.public_id,
-    s.user_id,
-    s.host_id,
-    s.server_id,
-    s.server_type,
-    s.target_id,
-    s.host_set_id,
-    s.auth_token_id,
-    s.scope_id,
-    s.certificate,
-    s.expiration_time,
-    s.connection_limit,
-    s.tofu_token,
-    s.key_id,
-    s.termination_reason,
-    s.version,
-    s.create_time,
-    s.update_time,
-    s.endpoint,
-    ss.state,
-    ss.previous_end_time,
-    ss.start_time,
-    ss.end_time
-  from  
-    session s,
-    session_state ss
-  where 
-    s.public_id = ss.session_id;
+  create trigger
+    auth_token_principal_enabled
+  before insert on auth_token
+    for each row execute procedure auth_token_principal_enabled();
 
 commit;
 
 `),
 	},
-	"migrations/51_connection.down.sql": {
-		name: "51_connection.down.sql",
+	"migrations/83_iam_principal_role_expiration.down.sql": {
+		name: "83_iam_principal_role_expiration.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop table session_connection_state;
-  drop table session_connection_state_enm;
-  drop table session_connection;
-  drop table session_connection_closed_reason_enm;
-  drop function insert_session_connection_state;
-  drop function insert_new_connection_state;
-  drop function update_connection_state_on_closed_reason;
+  drop table iam_group_role_expiration;
+  drop table iam_user_role_expiration;
+
 commit;
 
 `),
 	},
-	"migrations/51_connection.up.sql": {
-		name: "51_connection.up.sql",
+	"migrations/83_iam_principal_role_expiration.up.sql": {
+		name: "83_iam_principal_role_expiration.up.sql",
 		bytes: []byte(`
 begin;
 
-/*
+  -- iam_user_role_expiration and iam_group_role_expiration record when a
+  -- temporary assignment of a role to a principal expires. Expired assignments
+  -- are ignored when resolving grants and are deleted by a scheduled job. The
+  -- expiration is deleted along with its assignment, so an assignment which is
+  -- removed and added again is permanent unless given a new expiration.
+  create table iam_user_role_expiration (
+    role_id wt_role_id,
+    principal_id wt_user_id,
+    expiration_time timestamp with time zone not null,
+    create_time wt_timestamp,
+    primary key (role_id, principal_id),
+    foreign key (role_id, principal_id)
+      references iam_user_role(role_id, principal_id)
+      on delete cascade
+      on update cascade
+  );
 
-               ┌────────────────┐
-               │    session     │
-               ├────────────────┤
-               │ public_id (pk) │
-               │                │
-               │                │
-               └────────────────┘
-                   ▲fk1 ┼
-                        ┼
-                        │
-                        │
-                        ┼
-                       ╱│╲                  ┌──────────────────────────────────────┐
-            ┌───────────────────────┐       │ session_connection_closed_reason_enm │
-            │  session_connection   │╲ fk2▶ ├──────────────────────────────────────┤
-            ├───────────────────────┤─○───○┼│ name                                 │
-            │ public_id     (pk)    │╱      └──────────────────────────────────────┘
-            │ session_id    (fk1)   │
-            │ closed_reason (fk2)   │
-            └───────────────────────┘
-                   ▲fk1 ┼
-                        ┼
-                        │
-                        │
-                        ┼
-                       ╱│╲
-  ┌──────────────────────────────────────────┐
-  │         session_connection_state         │
-  ├──────────────────────────────────────────┤┼○┐
-  │ connection_id     (pk,fk1,fk2,unq1,unq2) │  │◀fk2
-  │ state             (fk3)                  │  │
-  │ previous_end_time (fk2,unq1)             │┼○┘
-  │ start_time        (pk)                   │
-  │ end_time          (unq2)                 │
-  └──────────────────────────────────────────┘
-                       ╲│╱
-                        ○
-                        │
-                        │
-                        ┼
-                  ▼fk3  ┼
-        ┌───────────────────────────────┐
-        │ session_connection_state_enm  │
-        ├───────────────────────────────┤
-        │ name                          │
-        └───────────────────────────────┘
+  create trigger
+    default_create_time_column
+  before insert on iam_user_role_expiration
+    for each row execute procedure default_create_time();
 
-*/
+  create trigger
+    immutable_columns
+  before
+  update on iam_user_role_expiration
+    for each row execute procedure immutable_columns('role_id', 'principal_id', 'create_time');
 
-  create table session_connection_closed_reason_enm (
-    name text primary key
-      constraint only_predefined_session_connection_closed_reasons_allowed
-      check (
-        name in (
-          'unknown',
-          'timed out',
-          'closed by end-user',
-          'canceled',
-          'network error',
-          'system error'
-        )
+  create index iam_user_role_expiration_time_ix
+    on iam_user_role_expiration (expiration_time);
+
+  create table iam_group_role_expiration (
+    role_id wt_role_id,
+    principal_id wt_public_id,
+    expiration_time timestamp with time zone not null,
+    create_time wt_timestamp,
+    primary key (role_id, principal_id),
+    foreign key (role_id, principal_id)
+      references iam_group_role(role_id, principal_id)
+      on delete cascade
+      on update cascade
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on iam_group_role_expiration
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_group_role_expiration
+    for each row execute procedure immutable_columns('role_id', 'principal_id', 'create_time');
+
+  create index iam_group_role_expiration_time_ix
+    on iam_group_role_expiration (expiration_time);
+
+commit;
+
+`),
+	},
+	"migrations/84_iam_access_request.down.sql": {
+		name: "84_iam_access_request.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table iam_access_request_event;
+  drop table iam_access_request;
+  drop table iam_access_request_status_enm;
+
+commit;
+
+`),
+	},
+	"migrations/84_iam_access_request.up.sql": {
+		name: "84_iam_access_request.up.sql",
+		bytes: []byte(`
+begin;
+
+  create table iam_access_request_status_enm (
+    name text primary key
+      constraint only_predefined_access_request_statuses_allowed
+      check (
+        name in ('pending', 'approved', 'denied', 'cancelled')
       )
   );
 
-  insert into session_connection_closed_reason_enm (name)
+  insert into iam_access_request_status_enm (name)
   values
-    ('unknown'),
-    ('timed out'),
-    ('closed by end-user'),
-    ('canceled'),
-    ('network error'),
-    ('system error');
-
-  -- A session connection is one connection proxied by a worker from a client to
-  -- a endpoint for a session. The client initiates the connection to the worker
-  -- and the worker initiates the connection to the endpoint.
-  -- A session can have zero or more session connections.
-  create table session_connection (
+    ('pending'),
+    ('approved'),
+    ('denied'),
+    ('cancelled');
+
+  -- iam_access_request is a request by a user to be assigned a role for a
+  -- limited duration. Approving it assigns the role to the user until the
+  -- expiration time, using iam_user_role_expiration. Only pending requests may
+  -- be decided and a user may not decide its own request.
+  create table iam_access_request (
     public_id wt_public_id primary key,
-    session_id wt_public_id not null
-      references session (public_id)
+    role_id wt_role_id not null
+      references iam_role(public_id)
       on delete cascade
       on update cascade,
-    -- the client_tcp_address is the network address of the client which initiated
-    -- the connection to a worker
-    client_tcp_address inet,  -- maybe null on insert
-    -- the client_tcp_port is the network port at the address of the client the
-    -- worker proxied a connection for the user
-    client_tcp_port integer  -- maybe null on insert
-      constraint client_tcp_port_must_be_greater_than_0
-      check(client_tcp_port > 0)
-      constraint client_tcp_port_must_less_than_or_equal_to_65535
-      check(client_tcp_port <= 65535),
-    -- the endpoint_tcp_address is the network address of the endpoint which the
-    -- worker initiated the connection to, for the user
-    endpoint_tcp_address inet, -- maybe be null on insert
-    -- the endpoint_tcp_port is the network port at the address of the endpoint the
-    -- worker proxied a connection to, for the user
-    endpoint_tcp_port integer -- maybe null on insert
-      constraint endpoint_tcp_port_must_be_greater_than_0
-      check(endpoint_tcp_port > 0)
-      constraint endpoint_tcp_port_must_less_than_or_equal_to_65535
-      check(endpoint_tcp_port <= 65535),
-    -- the total number of bytes received by the worker from the client and sent
-    -- to the endpoint for this connection
-    bytes_up bigint -- can be null
-      constraint bytes_up_must_be_null_or_a_non_negative_number
+    requester_id wt_user_id not null
+      references iam_user(public_id)
+      on delete cascade
+      on update cascade,
+    justification text not null
+      constraint justification_must_not_be_empty
+      check(length(trim(justification)) > 0),
+    duration_seconds integer not null
+      constraint duration_seconds_must_be_greater_than_0
+      check(duration_seconds > 0),
+    status text not null default 'pending'
+      references iam_access_request_status_enm(name)
+      on delete restrict
+      on update cascade,
+    decider_id wt_user_id
+      references iam_user(public_id)
+      on delete set null
+      on update cascade,
+    decision_comment text,
+    decision_time timestamp with time zone,
+    expiration_time timestamp with time zone,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    constraint requester_may_not_decide
+      check(decider_id is null or decider_id <> requester_id)
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on iam_access_request
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on iam_access_request
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_access_request
+    for each row execute procedure immutable_columns('public_id', 'role_id', 'requester_id', 'justification', 'duration_seconds', 'create_time');
+
+  create index iam_access_request_role_id_ix
+    on iam_access_request (role_id);
+  create index iam_access_request_requester_id_ix
+    on iam_access_request (requester_id);
+
+  -- iam_access_request_event is the audit trail of an access request: its
+  -- creation and every decision on it, with the user acting.
+  create table iam_access_request_event (
+    id bigint generated always as identity primary key,
+    access_request_id wt_public_id not null
+      references iam_access_request(public_id)
+      on delete cascade
+      on update cascade,
+    event text not null
+      constraint only_predefined_access_request_events_allowed
       check (
-        bytes_up is null
-        or
-        bytes_up >= 0
+        event in ('requested', 'approved', 'denied', 'cancelled')
       ),
-    -- the total number of bytes received by the worker from the endpoint and sent
-    -- to the client for this connection
-    bytes_down bigint -- can be null
-      constraint bytes_down_must_be_null_or_a_non_negative_number
+    actor_id text not null,
+    comment text,
+    create_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on iam_access_request_event
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_access_request_event
+    for each row execute procedure immutable_columns('id', 'access_request_id', 'event', 'actor_id', 'comment', 'create_time');
+
+  create index iam_access_request_event_access_request_id_ix
+    on iam_access_request_event (access_request_id);
+
+commit;
+
+`),
+	},
+	"migrations/85_target_credential_checkout.down.sql": {
+		name: "85_target_credential_checkout.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop trigger check_in_target_credential on session_state;
+  drop function check_in_target_credential;
+  drop table target_credential_checkout_waiter;
+  drop table target_credential_checkout;
+  drop table target_credential_checkout_policy;
+
+commit;
+
+`),
+	},
+	"migrations/85_target_credential_checkout.up.sql": {
+		name: "85_target_credential_checkout.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- target_credential_checkout_policy marks targets backed by a shared
+  -- credential which only one session may hold at a time. When the credential
+  -- is checked out, a new session is rejected immediately ('reject') or waits
+  -- in line for up to queue_timeout_seconds ('queue'). If rotate_on_check_in
+  -- is set, a rotation of the credential is requested through the outbox
+  -- whenever it is checked in. A target without a row in this table has no
+  -- checkout.
+  create table target_credential_checkout_policy (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    mode text not null
+      constraint only_predefined_credential_checkout_modes_allowed
       check (
-        bytes_down is null
-        or
-        bytes_down >= 0
+        mode in ('reject', 'queue')
       ),
-    closed_reason text
-      references session_connection_closed_reason_enm (name)
-      on delete restrict
-      on update cascade,
-    version wt_version,
+    queue_timeout_seconds integer not null default 0
+      constraint queue_timeout_seconds_must_not_be_negative
+      check(queue_timeout_seconds >= 0),
+    rotate_on_check_in boolean not null default false,
     create_time wt_timestamp,
     update_time wt_timestamp
   );
 
-  create trigger 
+  create trigger
+    default_create_time_column
+  before insert on target_credential_checkout_policy
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on target_credential_checkout_policy
+    for each row execute procedure update_time_column();
+
+  create trigger
     immutable_columns
   before
-  update on session_connection
-    for each row execute procedure immutable_columns('public_id', 'session_id', 'create_time');
+  update on target_credential_checkout_policy
+    for each row execute procedure immutable_columns('target_id', 'create_time');
+
+  -- target_credential_checkout holds the checked out credentials, at most one
+  -- per target. A checkout is reserved before its session is created and
+  -- attached to the session afterwards; reservations never attached are
+  -- stale after a minute. The checkout is checked in when its session is
+  -- terminated or deleted.
+  create table target_credential_checkout (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    checkout_id text not null unique,
+    user_id wt_user_id not null,
+    session_id wt_public_id unique
+      references session(public_id)
+      on delete cascade
+      on update cascade,
+    create_time wt_timestamp
+  );
 
-  create trigger 
-    update_version_column 
-  after update on session_connection
-    for each row execute procedure update_version_column();
-    
-  create trigger 
-    update_time_column 
-  before update on session_connection 
-    for each row execute procedure update_time_column();
-    
-  create trigger 
+  create trigger
     default_create_time_column
+  before insert on target_credential_checkout
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
   before
-  insert on session_connection
+  update on target_credential_checkout
+    for each row execute procedure immutable_columns('target_id', 'checkout_id', 'user_id', 'create_time');
+
+  -- target_credential_checkout_waiter is the line of sessions waiting for
+  -- the credential of a target with the 'queue' mode, served in id order.
+  -- Waiters refresh last_seen_time while waiting; waiters not seen for 30
+  -- seconds, e.g. of a controller which stopped, no longer hold up the line.
+  create table target_credential_checkout_waiter (
+    id bigint generated always as identity primary key,
+    target_id wt_public_id not null
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    user_id wt_user_id not null,
+    create_time wt_timestamp,
+    last_seen_time timestamp with time zone not null default current_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on target_credential_checkout_waiter
     for each row execute procedure default_create_time();
 
-  -- insert_new_connection_state() is used in an after insert trigger on the
-  -- session_connection table.  it will insert a state of "authorized" in
-  -- session_connection_state for the new session connection. 
-  create or replace function 
-    insert_new_connection_state()
+  create index target_credential_checkout_waiter_target_id_ix
+    on target_credential_checkout_waiter (target_id, id);
+
+  -- check_in_target_credential checks in the credential held by a session
+  -- when it is terminated and, if its target's policy says so, requests a
+  -- rotation of the credential through the outbox.
+  create or replace function
+    check_in_target_credential()
     returns trigger
   as $$
+  declare
+    checked_in record;
   begin
-    insert into session_connection_state (connection_id, state)
-    values
-      (new.public_id, 'authorized');
+    if new.state <> 'terminated' then
+      return new;
+    end if;
+    delete from target_credential_checkout
+     where session_id = new.session_id
+    returning target_id, checkout_id, user_id into checked_in;
+    if found then
+      insert into outbox_message (kind, payload)
+      select 'target.credential_rotation',
+             convert_to(json_build_object(
+               'target_id', checked_in.target_id,
+               'checkout_id', checked_in.checkout_id,
+               'session_id', new.session_id,
+               'user_id', checked_in.user_id
+             )::text, 'UTF8')
+        from target_credential_checkout_policy p
+       where p.target_id = checked_in.target_id
+         and p.rotate_on_check_in;
+    end if;
     return new;
   end;
   $$ language plpgsql;
 
-  create trigger 
-    insert_new_connection_state
-  after insert on session_connection
-    for each row execute procedure insert_new_connection_state();
+  create trigger
+    check_in_target_credential
+  after insert on session_state
+    for each row execute procedure check_in_target_credential();
 
-  -- update_connection_state_on_closed_reason() is used in an update trigger on the
-  -- session_connection table.  it will insert a state of "closed" in
-  -- session_connection_state for the closed session connection. 
-  create or replace function 
-    update_connection_state_on_closed_reason()
+commit;
+
+`),
+	},
+	"migrations/86_target_credential_rotation_schema_version.down.sql": {
+		name: "86_target_credential_rotation_schema_version.down.sql",
+		bytes: []byte(`
+begin;
+
+  -- restore the rotation requests without a schema version.
+  create or replace function
+    check_in_target_credential()
     returns trigger
   as $$
+  declare
+    checked_in record;
   begin
-    if new.closed_reason is not null then
-      -- check to see if there's a closed state already, before inserting a
-      -- new one.
-      perform from
-        session_connection_state cs
-      where
-        cs.connection_id = new.public_id and 
-        cs.state = 'closed';
-      if not found then 
-        insert into session_connection_state (connection_id, state)
-        values
-          (new.public_id, 'closed');
-      end if;
-      -- whenever we close a connection, we want to terminate the session if
-      -- possible.  
-      perform terminate_session_if_possible(new.session_id);
+    if new.state <> 'terminated' then
+      return new;
+    end if;
+    delete from target_credential_checkout
+     where session_id = new.session_id
+    returning target_id, checkout_id, user_id into checked_in;
+    if found then
+      insert into outbox_message (kind, payload)
+      select 'target.credential_rotation',
+             convert_to(json_build_object(
+               'target_id', checked_in.target_id,
+               'checkout_id', checked_in.checkout_id,
+               'session_id', new.session_id,
+               'user_id', checked_in.user_id
+             )::text, 'UTF8')
+        from target_credential_checkout_policy p
+       where p.target_id = checked_in.target_id
+         and p.rotate_on_check_in;
     end if;
     return new;
   end;
   $$ language plpgsql;
 
-  create trigger 
-    update_connection_state_on_closed_reason
-  after update of closed_reason on session_connection
-    for each row execute procedure update_connection_state_on_closed_reason();
-
-  create table session_connection_state_enm (
-    name text primary key
-      constraint only_predefined_session_connection_states_allowed
-      check (
-        name in ('authorized', 'connected', 'closed')
-      )
-  );
-
-  insert into session_connection_state_enm (name)
-  values
-    ('authorized'),
-    ('connected'),
-    ('closed');
-
-
-  create table session_connection_state (
-    connection_id wt_public_id not null
-      references session_connection (public_id)
-      on delete cascade
-      on update cascade,
-    state text not null
-      references session_connection_state_enm(name)
-      on delete restrict
-      on update cascade,
-    previous_end_time timestamp with time zone, -- fk2 -- null means first state
-    start_time timestamp with time zone default current_timestamp not null,
-      constraint previous_end_time_and_start_time_in_sequence
-        check (previous_end_time <= start_time),
-    end_time timestamp with time zone, -- null means unfinished current state
-      constraint start_and_end_times_in_sequence
-        check (start_time <= end_time),
-      constraint end_times_in_sequence
-        check (previous_end_time <> end_time),
-    primary key (connection_id, start_time),
-    unique (connection_id, previous_end_time), -- null means first state
-    unique (connection_id, end_time), -- one null current state
-    foreign key (connection_id, previous_end_time) -- self-reference
-      references session_connection_state (connection_id, end_time)
-  );
+commit;
 
-  create trigger 
-    immutable_columns
-  before
-  update on session_connection_state
-    for each row execute procedure immutable_columns('connection_id', 'state', 'start_time', 'previous_end_time');
+`),
+	},
+	"migrations/86_target_credential_rotation_schema_version.up.sql": {
+		name: "86_target_credential_rotation_schema_version.up.sql",
+		bytes: []byte(`
+begin;
 
+  -- check_in_target_credential is replaced to include the version of the
+  -- schema of the payload in the rotation requests, see the eventschema
+  -- package.
   create or replace function
-    insert_session_connection_state()
+    check_in_target_credential()
     returns trigger
   as $$
+  declare
+    checked_in record;
   begin
-
-    update session_connection_state
-       set end_time = now()
-     where connection_id = new.connection_id
-       and end_time is null;
-
-    if not found then
-      new.previous_end_time = null;
-      new.start_time = now();
-      new.end_time = null;
+    if new.state <> 'terminated' then
       return new;
     end if;
-
-    new.previous_end_time = now();
-    new.start_time = now();
-    new.end_time = null;
+    delete from target_credential_checkout
+     where session_id = new.session_id
+    returning target_id, checkout_id, user_id into checked_in;
+    if found then
+      insert into outbox_message (kind, payload)
+      select 'target.credential_rotation',
+             convert_to(json_build_object(
+               'schema_version', 1,
+               'target_id', checked_in.target_id,
+               'checkout_id', checked_in.checkout_id,
+               'session_id', new.session_id,
+               'user_id', checked_in.user_id
+             )::text, 'UTF8')
+        from target_credential_checkout_policy p
+       where p.target_id = checked_in.target_id
+         and p.rotate_on_check_in;
+    end if;
     return new;
-
   end;
   $$ language plpgsql;
 
-  create trigger insert_session_connection_state before insert on session_connection_state
-    for each row execute procedure insert_session_connection_state();
-
--- terminate_session_if_possible takes a session id and terminates the session
--- if the following conditions are met:
---    * the session is expired and all its connections are closed.
---    * the session is canceling and all its connections are closed
---    * the session has exhausted its connection limit and all its connections
---      are closed.  
---
---      Note: this function should align closely with the domain function
---      TerminateCompletedSessions 
-create or replace function 
-    terminate_session_if_possible(terminate_session_id text)
-    returns void
-  as $$
-  begin 
-    -- is terminate_session_id in a canceling state
-    with canceling_session(session_id) as
-    (
-      select 
-        session_id
-      from
-        session_state ss
-      where 
-        ss.session_id = terminate_session_id and
-        ss.state = 'canceling' and 
-        ss.end_time is null
-    )
-    update session us
-      set termination_reason = 
-      case 
-        -- timed out sessions
-        when now() > us.expiration_time then 'timed out'
-        -- canceling sessions
-        when us.public_id in(
-          select 
-            session_id 
-          from 
-            canceling_session cs 
-          where
-            us.public_id = cs.session_id
-          ) then 'canceled' 
-        -- default: session connection limit reached.
-        else 'connection limit'
-      end
-    where
-      -- limit update to just the terminating_session_id
-      us.public_id = terminate_session_id and
-      termination_reason is null and
-      -- session expired or connection limit reached
-      (
-        -- expired sessions...
-        now() > us.expiration_time or 
-        -- connection limit reached...
-        (
-          -- handle unlimited connections...
-          connection_limit != -1 and
-          (
-            select count (*) 
-              from session_connection sc 
-            where 
-              sc.session_id = us.public_id
-          ) >= connection_limit
-        ) or 
-        -- canceled sessions
-        us.public_id in (
-          select 
-            session_id
-          from
-            canceling_session cs
-          where 
-            us.public_id = cs.session_id 
-        )
-      ) and 
-      -- make sure there are no existing connections
-      us.public_id not in (
-        select 
-          session_id 
-        from 
-            session_connection
-          where public_id in (
-          select 
-            connection_id
-          from 
-            session_connection_state
-          where 
-            state != 'closed' and
-            end_time is null
-        )
-    );
- end;
-  $$ language plpgsql;
-
-
 commit;
 
 `),
 	},
-	"migrations/60_wh_domain_types.down.sql": {
-		name: "60_wh_domain_types.down.sql",
+	"migrations/87_iam_project_template.down.sql": {
+		name: "87_iam_project_template.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop function wh_current_time_id;
-  drop function wh_current_date_id;
-  drop function wh_time_id;
-  drop function wh_date_id;
-  drop domain wh_dim_text;
-  drop domain wh_timestamp;
-  drop domain wh_public_id;
-  drop domain wh_dim_id;
-  drop function wh_dim_id;
-  drop domain wh_bytes_transmitted;
-  drop domain wh_inet_port;
+  drop table iam_scope_project_target_default;
+  drop table iam_scope_project_template_role_grant;
+  drop table iam_scope_project_template_role;
+  drop table iam_scope_project_template;
 
 commit;
 
 `),
 	},
-	"migrations/60_wh_domain_types.up.sql": {
-		name: "60_wh_domain_types.up.sql",
+	"migrations/87_iam_project_template.up.sql": {
+		name: "87_iam_project_template.up.sql",
 		bytes: []byte(`
 begin;
 
-  create extension if not exists "pgcrypto";
-
-  create domain wh_inet_port as integer
-  check(
-    value > 0
-    and
-    value <= 65535
+  -- iam_scope_project_template is the template of the projects of an org,
+  -- applied when a project is created in the org. The target settings are
+  -- copied to the new project as the defaults of its targets; a null setting
+  -- keeps the default of the target table.
+  create table iam_scope_project_template (
+    org_id wt_scope_id primary key
+      references iam_scope_org(scope_id)
+      on delete cascade
+      on update cascade,
+    target_default_port integer
+      constraint target_default_port_must_be_greater_than_0
+      check(target_default_port > 0),
+    target_session_max_seconds integer
+      constraint target_session_max_seconds_must_be_greater_than_0
+      check(target_session_max_seconds > 0),
+    target_session_connection_limit integer
+      constraint target_session_connection_limit_must_be_greater_than_0_or_negative_1
+      check(target_session_connection_limit > 0 or target_session_connection_limit = -1),
+    create_time wt_timestamp,
+    update_time wt_timestamp
   );
-  comment on domain wh_inet_port is
-  'An ordinal number between 1 and 65535 representing a network port';
 
-  create domain wh_bytes_transmitted as bigint
-  check(
-    value >= 0
-  );
-  comment on domain wh_bytes_transmitted is
-  'A non-negative integer representing the number of bytes transmitted';
+  create trigger
+    default_create_time_column
+  before insert on iam_scope_project_template
+    for each row execute procedure default_create_time();
 
-  -- wh_dim_id generates and returns a random ID which should be considered as
-  -- universally unique.
-  create or replace function wh_dim_id()
-    returns text
-  as $$
-    select encode(digest(gen_random_bytes(16), 'sha256'), 'base64');
-  $$ language sql;
+  create trigger
+    update_time_column
+  before update on iam_scope_project_template
+    for each row execute procedure update_time_column();
 
-  create domain wh_dim_id as text
-  check(
-    length(trim(value)) > 0
+  create trigger
+    immutable_columns
+  before
+  update on iam_scope_project_template
+    for each row execute procedure immutable_columns('org_id', 'create_time');
+
+  -- iam_scope_project_template_role holds the roles created in every new
+  -- project of an org, with the grants in
+  -- iam_scope_project_template_role_grant.
+  create table iam_scope_project_template_role (
+    org_id wt_scope_id not null
+      references iam_scope_project_template(org_id)
+      on delete cascade
+      on update cascade,
+    name text not null
+      constraint name_must_not_be_empty
+      check(length(trim(name)) > 0),
+    description text,
+    primary key(org_id, name)
   );
-  comment on domain wh_dim_id is
-  'Random ID generated with pgcrypto';
 
-  create domain wh_public_id as text
-  check(
-    value = 'None'
-    or
-    length(trim(value)) > 10
+  create table iam_scope_project_template_role_grant (
+    org_id wt_scope_id not null,
+    role_name text not null,
+    raw_grant text not null
+      constraint raw_grant_must_not_be_empty
+      check(length(trim(raw_grant)) > 0),
+    primary key(org_id, role_name, raw_grant),
+    foreign key (org_id, role_name)
+      references iam_scope_project_template_role(org_id, name)
+      on delete cascade
+      on update cascade
   );
-  comment on domain wh_public_id is
-  'Equivalent to wt_public_id but also allows the value to be ''None''';
-
-  create domain wh_timestamp as timestamp with time zone not null;
-  comment on domain wh_timestamp is
-  'Timestamp used in warehouse tables';
 
-  create domain wh_dim_text as text not null
-  check(
-    length(trim(value)) > 0
+  -- iam_scope_project_target_default holds the defaults of the settings of
+  -- the targets of a project, copied from the template of its org when the
+  -- project was created. A null setting keeps the default of the target
+  -- table.
+  create table iam_scope_project_target_default (
+    project_id wt_scope_id primary key
+      references iam_scope_project(scope_id)
+      on delete cascade
+      on update cascade,
+    default_port integer,
+    session_max_seconds integer,
+    session_connection_limit integer,
+    create_time wt_timestamp
   );
-  comment on domain wh_dim_text is
-  'Text fields in dimension tables are always not null and always not empty strings';
 
-  -- wh_date_id returns the wh_date_dimension id for ts.
-  create or replace function wh_date_id(ts wh_timestamp)
-    returns integer
-  as $$
-    select to_char(ts, 'YYYYMMDD')::integer;
-  $$ language sql;
+  create trigger
+    default_create_time_column
+  before insert on iam_scope_project_target_default
+    for each row execute procedure default_create_time();
 
-  -- wh_time_id returns the wh_time_of_day_dimension id for ts.
-  create or replace function wh_time_id(ts wh_timestamp)
-    returns integer
-  as $$
-    select to_char(ts, 'SSSS')::integer;
-  $$ language sql;
+commit;
 
-  -- wh_date_id returns the wh_date_dimension id for current_timestamp.
-  create or replace function wh_current_date_id()
-    returns integer
-  as $$
-    select wh_date_id(current_timestamp);
-  $$ language sql;
+`),
+	},
+	"migrations/88_target_peer_identity.down.sql": {
+		name: "88_target_peer_identity.down.sql",
+		bytes: []byte(`
+begin;
 
-  -- wh_time_id returns the wh_time_of_day_dimension id for current_timestamp.
-  create or replace function wh_current_time_id()
-    returns integer
-  as $$
-    select wh_time_id(current_timestamp);
-  $$ language sql;
+  drop table target_peer_identity;
+
+commit;
+
+`),
+	},
+	"migrations/88_target_peer_identity.up.sql": {
+		name: "88_target_peer_identity.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- target_peer_identity records how workers pass the identity of the user of
+  -- a session to the endpoints of a target, so the logs of an endpoint reached
+  -- with a shared credential can attribute actions to the user. A target
+  -- without a row in this table does not pass it.
+  create table target_peer_identity (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    mode text not null
+      check(mode in ('proxy_v2')),
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on target_peer_identity
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on target_peer_identity
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on target_peer_identity
+    for each row execute procedure immutable_columns('target_id', 'create_time');
 
 commit;
 
 `),
 	},
-	"migrations/62_wh_datetime.down.sql": {
-		name: "62_wh_datetime.down.sql",
+	"migrations/89_security_event.down.sql": {
+		name: "89_security_event.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop table wh_time_of_day_dimension;
-  drop table wh_date_dimension;
+  drop table security_event_rollup;
+  drop table security_event;
 
 commit;
 
 `),
 	},
-	"migrations/62_wh_datetime.up.sql": {
-		name: "62_wh_datetime.up.sql",
+	"migrations/89_security_event.up.sql": {
+		name: "89_security_event.up.sql",
 		bytes: []byte(`
 begin;
 
-  create table wh_date_dimension (
-    id                            integer      primary key,
-    date                          date         not null,
-    calendar_quarter              wh_dim_text,
-    calendar_month                wh_dim_text,
-    calendar_year                 smallint     not null,
-    day_of_week                   wh_dim_text,
-    day_of_week_number            smallint     not null,
-    day_of_week_number_iso        smallint     not null,
-    day_of_week_number_zero_based smallint     not null,
-    day_number_in_calendar_month  smallint     not null,
-    day_number_in_calendar_year   smallint     not null,
-    weekday_indicator             wh_dim_text
-  );
-
-  insert into wh_date_dimension (
-    id, date,
-    calendar_quarter, calendar_month, calendar_year,
-    day_of_week, day_of_week_number, day_of_week_number_iso, day_of_week_number_zero_based,
-    day_number_in_calendar_month, day_number_in_calendar_year,
-    weekday_indicator
-  ) values (
-    -1, 'infinity',
-    'None', 'None', -1,
-    'None', -1, -1, -1,
-    -1, -1,
-    'None'
-  );
-
-  insert
-    into wh_date_dimension
-  select to_char(t.day, 'YYYYMMDD')::integer as id,
-         t.day::date                         as date,
-         'Q' || to_char(t.day, 'Q')          as calendar_quarter,
-         to_char(t.day, 'Month')             as calendar_month,
-         extract(year from t.day)            as calendar_year,
-         to_char(t.day, 'Day')               as day_of_week,
-         to_char(t.day, 'D')::int            as day_of_week_number,
-         extract(isodow from t.day)          as day_of_week_number_iso,
-         extract(dow from t.day)             as day_of_week_number_zero_based,
-         extract(day from t.day)             as day_number_in_calendar_month,
-         extract(doy from t.day)             as day_number_in_calendar_year,
-         case extract(isodow from t.day)
-           when 6 then 'Weekend'
-           when 7 then 'Weekend'
-           else 'Weekday'
-         end                                 as weekday_indicator
-    from generate_series(
-           date_trunc('day', timestamp '2019-10-09'),
-           date_trunc('day', timestamp '2019-10-09' + interval '50 years'),
-           interval '1 day'
-         ) as t(day);
-
-  create table wh_time_of_day_dimension (
-    id                 integer      primary key,
-    time_no_zone       time         not null,
-    time_at_utc        timetz       not null,
-    hour_of_day        smallint     not null,
-    minute_of_hour     smallint     not null,
-    second_of_minute   smallint     not null,
-    display_time_24    wh_dim_text,
-    display_time_12    wh_dim_text,
-    meridiem_indicator wh_dim_text
+  -- security_event records the authentication successes and failures,
+  -- lockouts, auth token revocations and uses of the recovery KMS as they
+  -- happen. The controllers periodically roll them up into
+  -- security_event_rollup, deleting them, so this table only holds the events
+  -- of the last few minutes. Events keep their scope and auth method ids after
+  -- these are deleted.
+  create table security_event (
+    id bigint generated always as identity primary key,
+    kind text not null
+      check(kind in (
+        'authentication_succeeded',
+        'authentication_failed',
+        'lockout',
+        'auth_token_revoked',
+        'recovery_kms_used'
+      )),
+    scope_id wt_scope_id not null,
+    -- auth_method_id is empty for events not about an auth method, such as
+    -- uses of the recovery KMS.
+    auth_method_id text not null default '',
+    create_time timestamp with time zone not null default current_timestamp
   );
 
-  set timezone = 'UTC';
-
-  insert into wh_time_of_day_dimension (
-    id, time_no_zone, time_at_utc,
-    hour_of_day, minute_of_hour, second_of_minute,
-    display_time_24, display_time_12, meridiem_indicator
-  ) values (
-    -1, 'allballs', 'allballs',
-    -1, -1, -1,
-    'None', 'None', 'None'
+  create index security_event_create_time_ix
+    on security_event (create_time);
+
+  -- security_event_rollup counts the security events of each kind by hour,
+  -- scope and auth method.
+  create table security_event_rollup (
+    bucket_time timestamp with time zone not null,
+    kind text not null,
+    scope_id wt_scope_id not null,
+    auth_method_id text not null,
+    event_count bigint not null
+      check(event_count > 0),
+    primary key (bucket_time, kind, scope_id, auth_method_id)
   );
 
-  insert
-    into wh_time_of_day_dimension
-  select to_char(t.second, 'SSSS')::integer as id,
-         t.second::time                     as time_no_zone,
-         t.second::time                     as time_at_utc,
-         extract(hour from t.second)        as hour_of_day,
-         extract(minute from t.second)      as minute_of_hour,
-         extract(second from t.second)      as second_of_minute,
-         to_char(t.second, 'HH24:MI:SS')    as display_time_24,
-         to_char(t.second, 'HH12:MI:SS AM') as display_time_12,
-         to_char(t.second, 'PM')            as meridiem_indicator
-    from generate_series(
-           date_trunc('day', current_timestamp),
-           date_trunc('day', current_timestamp) + interval '24 hours' - interval '1 second',
-           interval '1 second'
-         ) as t(second);
+  create index security_event_rollup_scope_id_ix
+    on security_event_rollup (scope_id, bucket_time);
 
 commit;
 
 `),
 	},
-	"migrations/65_wh_session_dimensions.down.sql": {
-		name: "65_wh_session_dimensions.down.sql",
+	"migrations/90_auth_token_preset.down.sql": {
+		name: "90_auth_token_preset.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop view whx_user_dimension_target;
-  drop view whx_user_dimension_source;
-  drop view whx_host_dimension_target;
-  drop view whx_host_dimension_source;
-  drop table wh_user_dimension;
-  drop table wh_host_dimension;
+  drop trigger immutable_columns on auth_token_attenuation;
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_token_attenuation
+    for each row execute procedure immutable_columns('auth_token_id', 'parent_id', 'grants', 'target_id', 'create_time');
+
+  alter table auth_token_attenuation
+    drop column preset;
 
 commit;
 
 `),
 	},
-	"migrations/65_wh_session_dimensions.up.sql": {
-		name: "65_wh_session_dimensions.up.sql",
+	"migrations/90_auth_token_preset.up.sql": {
+		name: "90_auth_token_preset.up.sql",
 		bytes: []byte(`
 begin;
 
-  create table wh_host_dimension (
-    -- random id generated using encode(digest(gen_random_bytes(16), 'sha256'), 'base64')
-    -- this is done to prevent conflicts with rows in other clusters
-    -- which enables warehouse data from multiple clusters to be loaded into a
-    -- single database instance
-    id                              wh_dim_id     primary key default wh_dim_id(),
-
-    host_id                         wh_public_id  not null,
-    host_type                       wh_dim_text,
-    host_name                       wh_dim_text,
-    host_description                wh_dim_text,
-    host_address                    wh_dim_text,
-
-    host_set_id                     wh_public_id  not null,
-    host_set_type                   wh_dim_text,
-    host_set_name                   wh_dim_text,
-    host_set_description            wh_dim_text,
-
-    host_catalog_id                 wh_public_id  not null,
-    host_catalog_type               wh_dim_text,
-    host_catalog_name               wh_dim_text,
-    host_catalog_description        wh_dim_text,
-
-    target_id                       wh_public_id  not null,
-    target_type                     wh_dim_text,
-    target_name                     wh_dim_text,
-    target_description              wh_dim_text,
-    target_default_port_number      integer       not null,
-    target_session_max_seconds      integer       not null,
-    target_session_connection_limit integer       not null,
-
-    project_id                      wt_scope_id   not null,
-    project_name                    wh_dim_text,
-    project_description             wh_dim_text,
-
-    host_organization_id            wt_scope_id   not null,
-    host_organization_name          wh_dim_text,
-    host_organization_description   wh_dim_text,
-
-    current_row_indicator           wh_dim_text,
-    row_effective_time              wh_timestamp,
-    row_expiration_time             wh_timestamp
-  );
-
-  -- https://www.postgresql.org/docs/current/indexes-partial.html
-  create unique index wh_host_dim_current_constraint
-    on wh_host_dimension (target_id, host_set_id, host_id)
-    where current_row_indicator = 'Current';
-
-  -- The whx_host_dimension_source and whx_host_dimension_target views are used
-  -- by an insert trigger to determine if the current row for the dimension has
-  -- changed and new one needs to be inserted. The first column in the target
-  -- view must be the current warehouse id and all remaining columns must match
-  -- the columns in the source view.
-
-  -- The whx_host_dimension_source view shows the current values in the
-  -- operational tables of the host dimension.
-  create view whx_host_dimension_source as
-  select -- id is the first column in the target view
-         h.public_id                     as host_id,
-         'static host'                   as host_type,
-         coalesce(h.name, 'None')        as host_name,
-         coalesce(h.description, 'None') as host_description,
-         coalesce(h.address, 'Unknown')  as host_address,
-         s.public_id                     as host_set_id,
-         'static host set'               as host_set_type,
-         coalesce(s.name, 'None')        as host_set_name,
-         coalesce(s.description, 'None') as host_set_description,
-         c.public_id                     as host_catalog_id,
-         'static host catalog'           as host_catalog_type,
-         coalesce(c.name, 'None')        as host_catalog_name,
-         coalesce(c.description, 'None') as host_catalog_description,
-         t.public_id                     as target_id,
-         'tcp target'                    as target_type,
-         coalesce(t.name, 'None')        as target_name,
-         coalesce(t.description, 'None') as target_description,
-         coalesce(t.default_port, 0)     as target_default_port_number,
-         t.session_max_seconds           as target_session_max_seconds,
-         t.session_connection_limit      as target_session_connection_limit,
-         p.public_id                     as project_id,
-         coalesce(p.name, 'None')        as project_name,
-         coalesce(p.description, 'None') as project_description,
-         o.public_id                     as host_organization_id,
-         coalesce(o.name, 'None')        as host_organization_name,
-         coalesce(o.description, 'None') as host_organization_description
-    from static_host as h,
-         static_host_catalog as c,
-         static_host_set_member as m,
-         static_host_set as s,
-         target_host_set as ts,
-         target_tcp as t,
-         iam_scope as p,
-         iam_scope as o
-   where h.catalog_id = c.public_id
-     and h.public_id = m.host_id
-     and s.public_id = m.set_id
-     and t.public_id = ts.target_id
-     and s.public_id = ts.host_set_id
-     and p.public_id = t.scope_id
-     and p.type = 'project'
-     and o.public_id = p.parent_id
-     and o.type = 'org'
-  ;
+  -- preset restricts the actions of an auth token issued for a least
+  -- privilege preset, whatever its grants allow. It is empty for tokens
+  -- which are not restricted by a preset.
+  alter table auth_token_attenuation
+    add column preset text not null default ''
+      check(preset in ('', 'terraform', 'read-only'));
 
-  -- The whx_host_dimension_target view shows the rows in the wh_host_dimension
-  -- table marked as 'Current'.
-  create view whx_host_dimension_target as
-  select id,
-         host_id,
-         host_type,
-         host_name,
-         host_description,
-         host_address,
-         host_set_id,
-         host_set_type,
-         host_set_name,
-         host_set_description,
-         host_catalog_id,
-         host_catalog_type,
-         host_catalog_name,
-         host_catalog_description,
-         target_id,
-         target_type,
-         target_name,
-         target_description,
-         target_default_port_number,
-         target_session_max_seconds,
-         target_session_connection_limit,
-         project_id,
-         project_name,
-         project_description,
-         host_organization_id,
-         host_organization_name,
-         host_organization_description
-    from wh_host_dimension
-   where current_row_indicator = 'Current'
-  ;
+  drop trigger immutable_columns on auth_token_attenuation;
 
-  create table wh_user_dimension (
-    -- random id generated using encode(digest(gen_random_bytes(16), 'sha256'), 'base64')
-    -- this is done to prevent conflicts with rows in other clusters
-    -- which enables warehouse data from multiple clusters to be loaded into a
-    -- single database instance
-    id                            wh_dim_id     primary key default wh_dim_id(),
+  create trigger
+    immutable_columns
+  before
+  update on auth_token_attenuation
+    for each row execute procedure immutable_columns('auth_token_id', 'parent_id', 'grants', 'target_id', 'preset', 'create_time');
 
-    user_id                       wt_user_id    not null,
-    user_name                     wh_dim_text,
-    user_description              wh_dim_text,
+commit;
 
-    auth_account_id               wh_public_id  not null,
-    auth_account_type             wh_dim_text,
-    auth_account_name             wh_dim_text,
-    auth_account_description      wh_dim_text,
+`),
+	},
+	"migrations/91_secret_fingerprint.down.sql": {
+		name: "91_secret_fingerprint.down.sql",
+		bytes: []byte(`
+begin;
 
-    auth_method_id                wh_public_id  not null,
-    auth_method_type              wh_dim_text,
-    auth_method_name              wh_dim_text,
-    auth_method_description       wh_dim_text,
+  drop table secret_fingerprint;
 
-    user_organization_id          wt_scope_id   not null,
-    user_organization_name        wh_dim_text,
-    user_organization_description wh_dim_text,
+commit;
 
-    current_row_indicator         wh_dim_text,
-    row_effective_time            wh_timestamp,
-    row_expiration_time           wh_timestamp
+`),
+	},
+	"migrations/91_secret_fingerprint.up.sql": {
+		name: "91_secret_fingerprint.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- secret_fingerprint records the fingerprints of the secrets brokered to
+  -- clients, the hex encoded HMAC-SHA256 of each secret, so a leaked secret
+  -- can be traced to the session it was brokered for. The secrets themselves
+  -- are never stored. Fingerprints keep their session, target, user and scope
+  -- ids after these are deleted, since leaks are often found long after the
+  -- session ended.
+  create table secret_fingerprint (
+    id bigint generated always as identity primary key,
+    fingerprint text not null
+      check(length(fingerprint) = 64),
+    kind text not null
+      check(kind in ('session_private_key')),
+    session_id text not null,
+    target_id text not null,
+    user_id text not null,
+    scope_id text not null,
+    create_time timestamp with time zone not null default current_timestamp
   );
 
-  -- The whx_user_dimension_source and whx_user_dimension_target views are used
-  -- by an insert trigger to determine if the current row for the dimension has
-  -- changed and new one needs to be inserted. The first column in the target
-  -- view must be the current warehouse id and all remaining columns must match
-  -- the columns in the source view.
+  create index secret_fingerprint_fingerprint_ix
+    on secret_fingerprint (fingerprint);
 
-  -- The whx_user_dimension_source view shows the current values in the
-  -- operational tables of the user dimension.
-  create view whx_user_dimension_source as
-       select -- id is the first column in the target view
-              u.public_id                       as user_id,
-              coalesce(u.name, 'None')          as user_name,
-              coalesce(u.description, 'None')   as user_description,
-              coalesce(aa.public_id, 'None')    as auth_account_id,
-              case when aa.public_id is null then 'None'
-                   else 'password auth account'
-                   end                          as auth_account_type,
-              coalesce(apa.name, 'None')        as auth_account_name,
-              coalesce(apa.description, 'None') as auth_account_description,
-              coalesce(am.public_id, 'None')    as auth_method_id,
-              case when am.public_id is null then 'None'
-                   else 'password auth method'
-                   end                          as auth_method_type,
-              coalesce(apm.name, 'None')        as auth_method_name,
-              coalesce(apm.description, 'None') as auth_method_description,
-              org.public_id                     as user_organization_id,
-              coalesce(org.name, 'None')        as user_organization_name,
-              coalesce(org.description, 'None') as user_organization_description
-         from iam_user as u
-    left join auth_account as aa on           u.public_id = aa.iam_user_id
-    left join auth_method as am on            aa.auth_method_id = am.public_id
-    left join auth_password_account as apa on aa.public_id = apa.public_id
-    left join auth_password_method as apm on  am.public_id = apm.public_id
-         join iam_scope as org on             u.scope_id = org.public_id
-  ;
+commit;
 
-  -- The whx_user_dimension_target view shows the rows in the wh_user_dimension
-  -- table marked as 'Current'.
-  create view whx_user_dimension_target as
-    select id,
-           user_id,
-           user_name,
-           user_description,
-           auth_account_id,
-           auth_account_type,
-           auth_account_name,
-           auth_account_description,
-           auth_method_id,
-           auth_method_type,
-           auth_method_name,
-           auth_method_description,
-           user_organization_id,
-           user_organization_name,
-           user_organization_description
-      from wh_user_dimension
-     where current_row_indicator = 'Current'
-  ;
+`),
+	},
+	"migrations/92_session_worker_affinity.down.sql": {
+		name: "92_session_worker_affinity.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop index session_user_id_target_id_create_time_ix;
 
 commit;
 
 `),
 	},
-	"migrations/66_wh_session_dimensions.down.sql": {
-		name: "66_wh_session_dimensions.down.sql",
+	"migrations/92_session_worker_affinity.up.sql": {
+		name: "92_session_worker_affinity.up.sql",
 		bytes: []byte(`
 begin;
 
-  drop function wh_upsert_user;
-  drop function wh_upsert_host;
+  -- Supports finding the worker of the previous session of a user to a
+  -- target, which is preferred for their next session when worker affinity
+  -- is enabled.
+  create index session_user_id_target_id_create_time_ix
+    on session (user_id, target_id, create_time);
 
 commit;
 
 `),
 	},
-	"migrations/66_wh_session_dimensions.up.sql": {
-		name: "66_wh_session_dimensions.up.sql",
+	"migrations/93_schema_fingerprint.down.sql": {
+		name: "93_schema_fingerprint.down.sql",
 		bytes: []byte(`
 begin;
 
-  -- wh_upsert_host returns the wh_host_dimension id for p_host_id,
-  -- p_host_set_id, and p_target_id. wh_upsert_host compares the current values
-  -- in the wh_host_dimension with the current values in the operational tables
-  -- for the provide parameters. If the values between the operational tables
-  -- and the wh_host_dimension differ, a new row is inserted in the
-  -- wh_host_dimension to match the current values in the operational tables and
-  -- the new id is returned. If the values do not differ, the current id is
-  -- returned.
-  create or replace function wh_upsert_host(p_host_id wt_public_id, p_host_set_id wt_public_id, p_target_id wt_public_id)
-    returns wh_dim_id
-  as $$
-  declare
-    src     whx_host_dimension_target%rowtype;
-    target  whx_host_dimension_target%rowtype;
-    new_row wh_host_dimension%rowtype;
-  begin
-    select * into target
-      from whx_host_dimension_target as t
-     where t.host_id               = p_host_id
-       and t.host_set_id           = p_host_set_id
-       and t.target_id             = p_target_id;
+  drop table schema_fingerprint;
 
-    select target.id, t.* into src
-      from whx_host_dimension_source as t
-     where t.host_id               = p_host_id
-       and t.host_set_id           = p_host_set_id
-       and t.target_id             = p_target_id;
+commit;
 
-    if src is distinct from target then
+`),
+	},
+	"migrations/93_schema_fingerprint.up.sql": {
+		name: "93_schema_fingerprint.up.sql",
+		bytes: []byte(`
+begin;
 
-      -- expire the current row
-      update wh_host_dimension
-         set current_row_indicator = 'Expired',
-             row_expiration_time   = current_timestamp
-       where host_id               = p_host_id
-         and host_set_id           = p_host_set_id
-         and target_id             = p_target_id
-         and current_row_indicator = 'Current';
+  -- schema_fingerprint records the fingerprint of the schema right after the
+  -- migrations of each version are applied by InitStore: the hex encoded
+  -- sha256 of its tables, columns, constraints and indexes. The schema is
+  -- verified against the fingerprint of its version before migrating and when
+  -- a controller starts, so changes made outside of migrations are detected.
+  create table schema_fingerprint (
+    version bigint primary key
+      constraint schema_fingerprint_version_must_be_positive
+      check(version > 0),
+    fingerprint text not null
+      constraint schema_fingerprint_fingerprint_must_be_sha256
+      check(length(fingerprint) = 64),
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
 
-      -- insert a new row
-      insert into wh_host_dimension (
-             host_id,                    host_type,                  host_name,                       host_description,         host_address,
-             host_set_id,                host_set_type,              host_set_name,                   host_set_description,
-             host_catalog_id,            host_catalog_type,          host_catalog_name,               host_catalog_description,
-             target_id,                  target_type,                target_name,                     target_description,
-             target_default_port_number, target_session_max_seconds, target_session_connection_limit,
-             project_id,                 project_name,               project_description,
-             host_organization_id,       host_organization_name,     host_organization_description,
-             current_row_indicator,      row_effective_time,         row_expiration_time
-      )
-      select host_id,                    host_type,                  host_name,                       host_description,         host_address,
-             host_set_id,                host_set_type,              host_set_name,                   host_set_description,
-             host_catalog_id,            host_catalog_type,          host_catalog_name,               host_catalog_description,
-             target_id,                  target_type,                target_name,                     target_description,
-             target_default_port_number, target_session_max_seconds, target_session_connection_limit,
-             project_id,                 project_name,               project_description,
-             host_organization_id,       host_organization_name,     host_organization_description,
-             'Current',                  current_timestamp,          'infinity'::timestamptz
-        from whx_host_dimension_source
-       where host_id               = p_host_id
-         and host_set_id           = p_host_set_id
-         and target_id             = p_target_id
-      returning * into new_row;
+  create trigger
+    default_create_time_column
+  before
+  insert on schema_fingerprint
+    for each row execute procedure default_create_time();
 
-      return new_row.id;
-    end if;
-    return target.id;
+  create trigger
+    update_time_column
+  before update on schema_fingerprint
+    for each row execute procedure update_time_column();
 
-  end;
-  $$ language plpgsql;
+  create trigger
+    immutable_columns
+  before
+  update on schema_fingerprint
+    for each row execute procedure immutable_columns('version', 'create_time');
 
-  -- wh_upsert_user returns the wh_user_dimension id for p_user_id and
-  -- p_auth_token_id. wh_upsert_user compares the current values in the
-  -- wh_user_dimension with the current values in the operational tables for the
-  -- provide parameters. If the values between the operational tables and the
-  -- wh_user_dimension differ, a new row is inserted in the wh_user_dimension to
-  -- match the current values in the operational tables and the new id is
-  -- returned. If the values do not differ, the current id is returned.
-  create or replace function wh_upsert_user(p_user_id wt_user_id, p_auth_token_id wt_public_id)
-    returns wh_dim_id
-  as $$
-  declare
-    src     whx_user_dimension_target%rowtype;
-    target  whx_user_dimension_target%rowtype;
-    new_row wh_user_dimension%rowtype;
-    acct_id wt_public_id;
-  begin
-    select auth_account_id into strict acct_id
-      from auth_token
-     where public_id = p_auth_token_id;
+commit;
 
-    select * into target
-      from whx_user_dimension_target as t
-     where t.user_id               = p_user_id
-       and t.auth_account_id       = acct_id;
+`),
+	},
+	"migrations/94_target_connection_metadata.down.sql": {
+		name: "94_target_connection_metadata.down.sql",
+		bytes: []byte(`
+begin;
 
-    select target.id, t.* into src
-      from whx_user_dimension_source as t
-     where t.user_id               = p_user_id
-       and t.auth_account_id       = acct_id;
+  drop table target_connection_metadata;
 
-    if src is distinct from target then
+commit;
 
-      -- expire the current row
-      update wh_user_dimension
-         set current_row_indicator = 'Expired',
-             row_expiration_time   = current_timestamp
-       where user_id               = p_user_id
-         and auth_account_id       = acct_id
-         and current_row_indicator = 'Current';
+`),
+	},
+	"migrations/94_target_connection_metadata.up.sql": {
+		name: "94_target_connection_metadata.up.sql",
+		bytes: []byte(`
+begin;
 
-      -- insert a new row
-      insert into wh_user_dimension (
-             user_id,               user_name,              user_description,
-             auth_account_id,       auth_account_type,      auth_account_name,             auth_account_description,
-             auth_method_id,        auth_method_type,       auth_method_name,              auth_method_description,
-             user_organization_id,  user_organization_name, user_organization_description,
-             current_row_indicator, row_effective_time,     row_expiration_time
-      )
-      select user_id,               user_name,              user_description,
-             auth_account_id,       auth_account_type,      auth_account_name,             auth_account_description,
-             auth_method_id,        auth_method_type,       auth_method_name,              auth_method_description,
-             user_organization_id,  user_organization_name, user_organization_description,
-             'Current',             current_timestamp,      'infinity'::timestamptz
-        from whx_user_dimension_source
-       where user_id               = p_user_id
-         and auth_account_id       = acct_id
-      returning * into new_row;
+  -- target_connection_metadata records the key/value metadata of targets
+  -- returned with their session authorizations, e.g. the name of the database
+  -- behind a target, which connect helpers use to fill in client flags.
+  create table target_connection_metadata (
+    target_id wt_public_id
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    key text not null
+      constraint target_connection_metadata_key_must_be_valid
+      check(key ~ '^[a-z][a-z0-9_]{0,63}$'),
+    value text not null
+      constraint target_connection_metadata_value_too_long
+      check(length(value) <= 1024),
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    primary key(target_id, key)
+  );
 
-      return new_row.id;
-    end if;
-    return target.id;
+  create trigger
+    default_create_time_column
+  before insert on target_connection_metadata
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on target_connection_metadata
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on target_connection_metadata
+    for each row execute procedure immutable_columns('target_id', 'key', 'create_time');
 
-  end;
-  $$ language plpgsql;
 commit;
 
 `),
 	},
-	"migrations/68_wh_session_facts.down.sql": {
-		name: "68_wh_session_facts.down.sql",
+	"migrations/95_iam_grants_version.down.sql": {
+		name: "95_iam_grants_version.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop table wh_session_connection_accumulating_fact;
-  drop table wh_session_accumulating_fact;
+  drop trigger increment_iam_grants_version on iam_role;
+  drop trigger increment_iam_grants_version on iam_role_grant;
+  drop trigger increment_iam_grants_version on iam_user_role;
+  drop trigger increment_iam_grants_version on iam_group_role;
+  drop trigger increment_iam_grants_version on iam_user_role_expiration;
+  drop trigger increment_iam_grants_version on iam_group_role_expiration;
+  drop trigger increment_iam_grants_version on iam_group_member_user;
+  drop function increment_iam_grants_version;
+  drop table iam_grants_version;
 
 commit;
 
 `),
 	},
-	"migrations/68_wh_session_facts.up.sql": {
-		name: "68_wh_session_facts.up.sql",
+	"migrations/95_iam_grants_version.up.sql": {
+		name: "95_iam_grants_version.up.sql",
 		bytes: []byte(`
 begin;
 
-  -- Column names for numeric fields that are not a measurement end in id or
-  -- number. This naming convention enables automatic field type detection in
-  -- certain data analysis tools.
-  -- https://help.tableau.com/current/pro/desktop/en-us/data_clean_adm.htm
-
-  -- The wh_session_accumulating_fact table is an accumulating snapshot.
-  -- The table wh_session_accumulating_fact is an accumulating fact table.
-  -- The grain of the fact table is one row per session.
-  create table wh_session_accumulating_fact (
-    session_id wt_public_id primary key,
-    -- auth token id is a degenerate dimension
-    auth_token_id wt_public_id not null,
-
-    -- foreign keys to the dimension tables
-    host_id wh_dim_id not null
-      references wh_host_dimension (id)
-      on delete restrict
-      on update cascade,
-    user_id wh_dim_id not null
-      references wh_user_dimension (id)
-      on delete restrict
-      on update cascade,
-
-    -- TODO(mgaffney) 09/2020: add dimension and foreign key for the session
-    -- termination reason
-
-    -- date and time foreign keys
-    session_pending_date_id integer not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_pending_time_id integer not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_pending_time wh_timestamp,
-
-    session_active_date_id integer default -1 not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_active_time_id integer default -1 not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_active_time wh_timestamp default 'infinity'::timestamptz,
-
-    session_canceling_date_id integer default -1 not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_canceling_time_id integer default -1 not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_canceling_time wh_timestamp default 'infinity'::timestamptz,
-
-    session_terminated_date_id integer default -1 not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_terminated_time_id integer default -1 not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    session_terminated_time wh_timestamp default 'infinity'::timestamptz,
+  -- iam_grants_version has a single row whose version is incremented by every
+  -- change to the roles, their grants and principals, and the groups of
+  -- users. Controllers caching the grants of users compare it with the
+  -- version their cache was loaded at to discard stale grants.
+  create table iam_grants_version (
+    id boolean primary key default true
+      constraint iam_grants_version_must_have_a_single_row
+      check(id),
+    version bigint not null default 1
+  );
 
-    -- TODO(mgaffney) 09/2020: add columns for session expiration
+  insert into iam_grants_version default values;
 
-    -- TODO(mgaffney) 09/2020: add connection limit. This may need a dimension
-    -- table and foreign key column to represent unlimited connections.
+  create or replace function
+    increment_iam_grants_version()
+    returns trigger
+  as $$
+  begin
+    update iam_grants_version set version = version + 1;
+    return null;
+  end;
+  $$ language plpgsql;
 
-    -- The total number of connections made during the session.
-    total_connection_count bigint, -- will be null until the first connection is created
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_role
+    for each statement execute procedure increment_iam_grants_version();
 
-    -- The total number of bytes received by workers from the client and sent
-    -- to the endpoint for this session.
-    -- total_bytes_up is a fully additive measurement.
-    total_bytes_up wh_bytes_transmitted, -- will be null until the first connection is closed
-    -- The total number of bytes received by workers from the endpoint and sent
-    -- to the client for this session.
-    -- total_bytes_down is a fully additive measurement.
-    total_bytes_down wh_bytes_transmitted -- will be null until the first connection is closed
-  );
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_role_grant
+    for each statement execute procedure increment_iam_grants_version();
 
-  -- The wh_session_connection_accumulating_fact table is an accumulating fact table.
-  -- The grain of the fact table is one row per session connection.
-  create table wh_session_connection_accumulating_fact  (
-    connection_id wt_public_id primary key,
-    -- session_id is a degenerate dimension
-    session_id wt_public_id not null
-      references wh_session_accumulating_fact (session_id)
-      on delete cascade
-      on update cascade,
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_user_role
+    for each statement execute procedure increment_iam_grants_version();
 
-    -- foreign keys to the dimension tables
-    host_id wh_dim_id not null
-      references wh_host_dimension (id)
-      on delete restrict
-      on update cascade,
-    user_id wh_dim_id not null
-      references wh_user_dimension (id)
-      on delete restrict
-      on update cascade,
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_group_role
+    for each statement execute procedure increment_iam_grants_version();
 
-    -- TODO(mgaffney) 09/2020: add dimension and foreign key for the connection
-    -- closed reason
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_user_role_expiration
+    for each statement execute procedure increment_iam_grants_version();
 
-    -- date and time foreign keys and timestamps
-    connection_authorized_date_id integer not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    connection_authorized_time_id integer not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    connection_authorized_time wh_timestamp,
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_group_role_expiration
+    for each statement execute procedure increment_iam_grants_version();
 
-    connection_connected_date_id integer default -1 not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    connection_connected_time_id integer default -1 not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    connection_connected_time wh_timestamp default 'infinity'::timestamptz,
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_group_member_user
+    for each statement execute procedure increment_iam_grants_version();
 
-    connection_closed_date_id integer default -1 not null
-      references wh_date_dimension (id)
-      on delete restrict
-      on update cascade,
-    connection_closed_time_id integer default -1 not null
-      references wh_time_of_day_dimension (id)
-      on delete restrict
-      on update cascade,
-    connection_closed_time wh_timestamp default 'infinity'::timestamptz,
+commit;
 
-    -- TODO(mgaffney) 09/2020: add a connection_duration_in_seconds column
+`),
+	},
+	"migrations/96_server_config_fingerprint.down.sql": {
+		name: "96_server_config_fingerprint.down.sql",
+		bytes: []byte(`
+begin;
 
-    -- The client address and port are degenerate dimensions
-    client_tcp_address inet, -- can be null
-    client_tcp_port_number wh_inet_port, -- can be null
+  drop table server_config_fingerprint;
 
-    -- The endpoint address and port are degenerate dimensions
-    endpoint_tcp_address inet, -- can be null
-    endpoint_tcp_port_number wh_inet_port, -- can be null
+commit;
 
-    -- the connection_count must always be 1
-    -- this is a common pattern in data warehouse models
-    -- See The Data Warehouse Toolkit, Third Edition
-    -- by Ralph Kimball and Margy Ross for more information
-    connection_count smallint default 1 not null
-      constraint connection_count_must_be_1
-      check(connection_count = 1),
+`),
+	},
+	"migrations/96_server_config_fingerprint.up.sql": {
+		name: "96_server_config_fingerprint.up.sql",
+		bytes: []byte(`
+begin;
 
-    -- The total number of bytes received by the worker from the client and sent
-    -- to the endpoint for this connection.
-    -- bytes_up is a fully additive measurement.
-    bytes_up wh_bytes_transmitted, -- can be null
-    -- The total number of bytes received by the worker from the endpoint and sent
-    -- to the client for this connection.
-    -- bytes_down is a fully additive measurement.
-    bytes_down wh_bytes_transmitted -- can be null
+  -- server_config_fingerprint records the fingerprint of the security
+  -- relevant configuration of each controller from its last status update,
+  -- so controllers can detect peers whose configuration diverges from theirs,
+  -- e.g. during a partially rolled out configuration change. Sections holds
+  -- the fingerprints of the sections of the configuration as a JSON object,
+  -- to tell which of them diverge.
+  create table server_config_fingerprint (
+    controller_id text not null,
+    controller_type text not null default 'controller'
+      constraint controller_type_must_be_controller
+      check(controller_type = 'controller'),
+    fingerprint text not null
+      constraint fingerprint_must_not_be_empty
+      check(length(trim(fingerprint)) > 0),
+    sections text not null,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    primary key (controller_id),
+    foreign key (controller_id, controller_type)
+      references server(private_id, type)
+      on delete cascade
+      on update cascade
   );
 
-  -- TODO(mgaffney) 09/2020: Research and test if the comment fields are used by
-  -- data analysis tools.
-  comment on table wh_session_connection_accumulating_fact is
-    'The Wh Session Connection Accumulating Fact table is an accumulating fact table. '
-    'The grain of the fact table is one row per session connection.';
-  comment on column wh_session_connection_accumulating_fact.bytes_up is
-    'Bytes Up is the total number of bytes received by the worker from the '
-    'client and sent to the endpoint for this connection. Bytes Up is a fully '
-    'additive measurement.';
-  comment on column wh_session_connection_accumulating_fact.bytes_down is
-    'Bytes Down is the total number of bytes received by the worker from the '
-    'endpoint and sent to the client for this connection. Bytes Down is a fully '
-    'additive measurement.';
+  create trigger
+    default_create_time_column
+  before insert on server_config_fingerprint
+    for each row execute procedure default_create_time();
 
-  create index on wh_session_connection_accumulating_fact(session_id);
+  create trigger
+    immutable_columns
+  before
+  update on server_config_fingerprint
+    for each row execute procedure immutable_columns('controller_id', 'controller_type', 'create_time');
 
 commit;
 
 `),
 	},
-	"migrations/69_wh_session_facts.down.sql": {
-		name: "69_wh_session_facts.down.sql",
+	"migrations/97_oplog_metadata_search.down.sql": {
+		name: "97_oplog_metadata_search.down.sql",
 		bytes: []byte(`
 begin;
 
-  drop trigger wh_insert_session_connection_state on session_connection_state;
-  drop function wh_insert_session_connection_state;
-
-  drop trigger wh_insert_session_state on session_state;
-  drop function wh_insert_session_state;
-
-  drop trigger wh_update_session_connection on session_connection;
-  drop function wh_update_session_connection;
-
-  drop trigger wh_insert_session_connection on session_connection;
-  drop function wh_insert_session_connection;
+  create index if not exists idx_oplog_metatadata_key on oplog_metadata(key);
 
-  drop trigger wh_insert_session on session;
-  drop function wh_insert_session;
-
-  drop function wh_rollup_connections;
+  drop index oplog_metadata_entry_id_ix;
+  drop index oplog_metadata_key_value_entry_id_ix;
 
 commit;
 
 `),
 	},
-	"migrations/69_wh_session_facts.up.sql": {
-		name: "69_wh_session_facts.up.sql",
+	"migrations/97_oplog_metadata_search.up.sql": {
+		name: "97_oplog_metadata_search.up.sql",
 		bytes: []byte(`
 begin;
 
-  -- wh_rollup_connections calculates the aggregate values from
-  -- wh_session_connection_accumulating_fact for p_session_id and updates
-  -- wh_session_accumulating_fact for p_session_id with those values.
-  create or replace function wh_rollup_connections(p_session_id wt_public_id)
-    returns void
-  as $$
-  declare
-    session_row wh_session_accumulating_fact%rowtype;
-  begin
-    with
-    session_totals (session_id, total_connection_count, total_bytes_up, total_bytes_down) as (
-      select session_id,
-             sum(connection_count),
-             sum(bytes_up),
-             sum(bytes_down)
-        from wh_session_connection_accumulating_fact
-       where session_id = p_session_id
-       group by session_id
-    )
-    update wh_session_accumulating_fact
-       set total_connection_count = session_totals.total_connection_count,
-           total_bytes_up         = session_totals.total_bytes_up,
-           total_bytes_down       = session_totals.total_bytes_down
-      from session_totals
-     where wh_session_accumulating_fact.session_id = session_totals.session_id
-    returning wh_session_accumulating_fact.* into strict session_row;
-  end;
-  $$ language plpgsql;
+  -- Searching the oplog by metadata finds the entries with a key and one of
+  -- its values, which oplog_metadata_key_value_entry_id_ix answers from the
+  -- index alone, then reads the metadata of the entries found by entry id.
+  create index oplog_metadata_key_value_entry_id_ix
+    on oplog_metadata (key, value, entry_id);
+
+  create index oplog_metadata_entry_id_ix
+    on oplog_metadata (entry_id);
+
+  -- Its key is the prefix of oplog_metadata_key_value_entry_id_ix
+  drop index idx_oplog_metatadata_key;
+
+commit;
+
+`),
+	},
+	"migrations/98_column_rename.down.sql": {
+		name: "98_column_rename.down.sql",
+		bytes: []byte(`
+begin;
 
-  --
-  -- Session triggers
-  --
+  drop function contract_column_rename;
+  drop function expand_column_rename;
+  drop function column_rename_sync;
 
-  -- wh_insert_session returns an after insert trigger for the session table
-  -- which inserts a row in wh_session_accumulating_fact for the new session.
-  -- wh_insert_session also calls the wh_upsert_host and wh_upsert_user
-  -- functions which can result in new rows in wh_host_dimension and
-  -- wh_user_dimension respectively.
-  create or replace function wh_insert_session()
-    returns trigger
-  as $$
-  declare
-    new_row wh_session_accumulating_fact%rowtype;
-  begin
-    with
-    pending_timestamp (date_dim_id, time_dim_id, ts) as (
-      select wh_date_id(start_time), wh_time_id(start_time), start_time
-        from session_state
-       where session_id = new.public_id
-         and state = 'pending'
-    )
-    insert into wh_session_accumulating_fact (
-           session_id,
-           auth_token_id,
-           host_id,
-           user_id,
-           session_pending_date_id,
-           session_pending_time_id,
-           session_pending_time
-    )
-    select new.public_id,
-           new.auth_token_id,
-           wh_upsert_host(new.host_id, new.host_set_id, new.target_id),
-           wh_upsert_user(new.user_id, new.auth_token_id),
-           pending_timestamp.date_dim_id,
-           pending_timestamp.time_dim_id,
-           pending_timestamp.ts
-      from pending_timestamp
-      returning * into strict new_row;
-    return null;
-  end;
-  $$ language plpgsql;
+commit;
 
-  create trigger wh_insert_session
-    after insert on session
-    for each row
-    execute function wh_insert_session();
+`),
+	},
+	"migrations/98_column_rename.up.sql": {
+		name: "98_column_rename.up.sql",
+		bytes: []byte(`
+begin;
 
+  -- A column is renamed without a maintenance window by expanding the schema,
+  -- backfilling and contracting it in three releases:
   --
-  -- Session Connection triggers
+  --   1. expand: a migration adds the new column, nullable and without a
+  --      default, and calls expand_column_rename. Until the column is
+  --      contracted each write of either column is copied to the other, so
+  --      controllers of the previous release, which only know the old column,
+  --      and of this release, which use the new one, see the same data. The
+  --      rename is listed in db.ColumnRenames, so the controllers backfill the
+  --      new column of the rows written before the expansion.
+  --   2. contract: once db.ColumnRenameStatus reports no rows left to backfill
+  --      and no controller of the previous release is left, a migration of a
+  --      later release calls contract_column_rename, then adds the constraints
+  --      of the new column.
+  --
+  -- For example, renaming the description of db_test_user to summary:
   --
+  --   alter table db_test_user add column summary text;
+  --   select expand_column_rename('db_test_user', 'description', 'summary');
+  --
+  -- and in a later release:
+  --
+  --   select contract_column_rename('db_test_user', 'description', 'summary');
 
-  -- wh_insert_session_connection returns an after insert trigger for the
-  -- session_connection table which inserts a row in
-  -- wh_session_connection_accumulating_fact for the new session connection.
-  -- wh_insert_session_connection also calls wh_rollup_connections which can
-  -- result in updates to wh_session_accumulating_fact.
-  create or replace function wh_insert_session_connection()
+  -- column_rename_sync() copies the writes of one column to the other. It
+  -- should only be used in a before insert or update trigger created by
+  -- expand_column_rename, with the old and new column names as parameters. A
+  -- write of the new column wins over a write of the old one.
+  create function column_rename_sync()
     returns trigger
   as $$
   declare
-    new_row wh_session_connection_accumulating_fact%rowtype;
+    old_column text := tg_argv[0];
+    new_column text := tg_argv[1];
+    rec jsonb := to_jsonb(new);
+    prev jsonb;
   begin
-    with
-    authorized_timestamp (date_dim_id, time_dim_id, ts) as (
-      select wh_date_id(start_time), wh_time_id(start_time), start_time
-        from session_connection_state
-       where connection_id = new.public_id
-         and state = 'authorized'
-    ),
-    session_dimension (host_dim_id, user_dim_id) as (
-      select host_id, user_id
-        from wh_session_accumulating_fact
-       where session_id = new.session_id
-    )
-    insert into wh_session_connection_accumulating_fact (
-           connection_id,
-           session_id,
-           host_id,
-           user_id,
-           connection_authorized_date_id,
-           connection_authorized_time_id,
-           connection_authorized_time,
-           client_tcp_address,
-           client_tcp_port_number,
-           endpoint_tcp_address,
-           endpoint_tcp_port_number,
-           bytes_up,
-           bytes_down
-    )
-    select new.public_id,
-           new.session_id,
-           session_dimension.host_dim_id,
-           session_dimension.user_dim_id,
-           authorized_timestamp.date_dim_id,
-           authorized_timestamp.time_dim_id,
-           authorized_timestamp.ts,
-           new.client_tcp_address,
-           new.client_tcp_port,
-           new.endpoint_tcp_address,
-           new.endpoint_tcp_port,
-           new.bytes_up,
-           new.bytes_down
-      from authorized_timestamp,
-           session_dimension
-      returning * into strict new_row;
-    perform wh_rollup_connections(new.session_id);
-    return null;
+    if tg_op = 'INSERT' then
+      if rec->new_column = 'null'::jsonb then
+        return jsonb_populate_record(new, jsonb_build_object(new_column, rec->old_column));
+      end if;
+      return jsonb_populate_record(new, jsonb_build_object(old_column, rec->new_column));
+    end if;
+    prev := to_jsonb(old);
+    if rec->new_column is distinct from prev->new_column then
+      return jsonb_populate_record(new, jsonb_build_object(old_column, rec->new_column));
+    end if;
+    if rec->old_column is distinct from prev->old_column then
+      return jsonb_populate_record(new, jsonb_build_object(new_column, rec->old_column));
+    end if;
+    return new;
   end;
   $$ language plpgsql;
 
-  create trigger wh_insert_session_connection
-    after insert on session_connection
-    for each row
-    execute function wh_insert_session_connection();
+  comment on function
+    column_rename_sync()
+  is
+    'function used in before insert or update triggers to keep the old and new columns of a rename in sync';
 
-  -- wh_update_session_connection returns an after update trigger for the
-  -- session_connection table which updates a row in
-  -- wh_session_connection_accumulating_fact for the session connection.
-  -- wh_update_session_connection also calls wh_rollup_connections which can
-  -- result in updates to wh_session_accumulating_fact.
-  create or replace function wh_update_session_connection()
-    returns trigger
+  -- expand_column_rename() starts copying the writes of the old column of the
+  -- table to the new column and back. The new column must have been added with
+  -- the type of the old column.
+  create function expand_column_rename(table_name regclass, old_column name, new_column name)
+    returns void
   as $$
-  declare
-    updated_row wh_session_connection_accumulating_fact%rowtype;
   begin
-        update wh_session_connection_accumulating_fact
-           set client_tcp_address       = new.client_tcp_address,
-               client_tcp_port_number   = new.client_tcp_port,
-               endpoint_tcp_address     = new.endpoint_tcp_address,
-               endpoint_tcp_port_number = new.endpoint_tcp_port,
-               bytes_up                 = new.bytes_up,
-               bytes_down               = new.bytes_down
-         where connection_id = new.public_id
-     returning * into strict updated_row;
-    perform wh_rollup_connections(new.session_id);
-    return null;
+    execute format(
+      'create trigger %I before insert or update on %s for each row execute function column_rename_sync(%L, %L)',
+      old_column || '_' || new_column || '_rename_sync', table_name, old_column, new_column);
   end;
   $$ language plpgsql;
 
-  create trigger wh_update_session_connection
-    after update on session_connection
-    for each row
-    execute function wh_update_session_connection();
-
-  --
-  -- Session State trigger
-  --
+  comment on function
+    expand_column_rename(regclass, name, name)
+  is
+    'function used in migrations to start the rename of a column';
 
-  -- wh_insert_session_state returns an after insert trigger for the
-  -- session_state table which updates wh_session_accumulating_fact.
-  create or replace function wh_insert_session_state()
-    returns trigger
+  -- contract_column_rename() stops copying the writes of the old column of the
+  -- table and drops it. It raises error code 23502 if rows of the table are
+  -- not backfilled.
+  create function contract_column_rename(table_name regclass, old_column name, new_column name)
+    returns void
   as $$
   declare
-    date_col text;
-    time_col text;
-    ts_col text;
-    q text;
-    session_row wh_session_accumulating_fact%rowtype;
+    pending boolean;
   begin
-    if new.state = 'pending' then
-      -- The pending state is the first state which is handled by the
-      -- wh_insert_session trigger. The update statement in this trigger will
-      -- fail for the pending state because the row for the session has not yet
-      -- been inserted into the wh_session_accumulating_fact table.
-      return null;
+    execute format('select exists (select from %s where %I is not null and %I is null)',
+      table_name, old_column, new_column) into pending;
+    if pending then
+      raise exception 'column rename not backfilled: %.% to %', table_name, old_column, new_column using
+        errcode = '23502',
+        table = table_name::text,
+        column = new_column;
     end if;
+    execute format('drop trigger %I on %s', old_column || '_' || new_column || '_rename_sync', table_name);
+    execute format('alter table %s drop column %I', table_name, old_column);
+  end;
+  $$ language plpgsql;
 
-    date_col = 'session_' || new.state || '_date_id';
-    time_col = 'session_' || new.state || '_time_id';
-    ts_col   = 'session_' || new.state || '_time';
+  comment on function
+    contract_column_rename(regclass, name, name)
+  is
+    'function used in migrations to complete the rename of a column';
 
-    q = format('update wh_session_accumulating_fact
-                   set (%I, %I, %I) = (select wh_date_id(%L), wh_time_id(%L), %L::timestamptz)
-                 where session_id = %L
-                returning *',
-                date_col,       time_col,       ts_col,
-                new.start_time, new.start_time, new.start_time,
-                new.session_id);
-    execute q into strict session_row;
+commit;
 
-    return null;
-  end;
-  $$ language plpgsql;
+`),
+	},
+	"migrations/99_target_user_name_template.down.sql": {
+		name: "99_target_user_name_template.down.sql",
+		bytes: []byte(`
+begin;
 
-  create trigger wh_insert_session_state
-    after insert on session_state
-    for each row
-    execute function wh_insert_session_state();
+  drop table target_user_name_template;
 
-  --
-  -- Session Connection State trigger
-  --
+commit;
 
-  -- wh_insert_session_connection_state returns an after insert trigger for the
-  -- session_connection_state table which updates
-  -- wh_session_connection_accumulating_fact.
-  create or replace function wh_insert_session_connection_state()
-    returns trigger
-  as $$
-  declare
-    date_col text;
-    time_col text;
-    ts_col text;
-    q text;
-    connection_row wh_session_connection_accumulating_fact%rowtype;
-  begin
-    if new.state = 'authorized' then
-      -- The authorized state is the first state which is handled by the
-      -- wh_insert_session_connection trigger. The update statement in this
-      -- trigger will fail for the authorized state because the row for the
-      -- session connection has not yet been inserted into the
-      -- wh_session_connection_accumulating_fact table.
-      return null;
-    end if;
+`),
+	},
+	"migrations/99_target_user_name_template.up.sql": {
+		name: "99_target_user_name_template.up.sql",
+		bytes: []byte(`
+begin;
 
-    date_col = 'connection_' || new.state || '_date_id';
-    time_col = 'connection_' || new.state || '_time_id';
-    ts_col   = 'connection_' || new.state || '_time';
+  -- target_user_name_template records the template of the user name which
+  -- protocol handlers inject into the connections of the sessions of a target,
+  -- so a shared target can map each user to their own account on its
+  -- endpoints. A target without a row in this table injects the name of the
+  -- user.
+  create table target_user_name_template (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    template text not null
+      check(length(trim(template)) > 0),
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
 
-    q = format('update wh_session_connection_accumulating_fact
-                   set (%I, %I, %I) = (select wh_date_id(%L), wh_time_id(%L), %L::timestamptz)
-                 where connection_id = %L
-                returning *',
-                date_col,       time_col,       ts_col,
-                new.start_time, new.start_time, new.start_time,
-                new.connection_id);
-    execute q into strict connection_row;
+  create trigger
+    default_create_time_column
+  before insert on target_user_name_template
+    for each row execute procedure default_create_time();
 
-    return null;
-  end;
-  $$ language plpgsql;
+  create trigger
+    update_time_column
+  before update on target_user_name_template
+    for each row execute procedure update_time_column();
 
-  create trigger wh_insert_session_connection_state
-    after insert on session_connection_state
-    for each row
-    execute function wh_insert_session_connection_state();
+  create trigger
+    immutable_columns
+  before
+  update on target_user_name_template
+    for each row execute procedure immutable_columns('target_id', 'create_time');
 
 commit;
 
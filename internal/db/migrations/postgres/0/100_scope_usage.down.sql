@@ -0,0 +1,5 @@
+begin;
+
+  drop table scope_usage_daily;
+
+commit;
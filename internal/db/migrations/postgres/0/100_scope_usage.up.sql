@@ -0,0 +1,21 @@
+begin;
+
+  -- scope_usage_daily holds the usage of each scope by UTC day, for
+  -- chargeback: the API calls made in the scope, which the controllers add
+  -- every few minutes, and the seconds the sessions of the project were
+  -- active, which the controllers periodically roll up from session_state.
+  -- Usages keep their scope ids after the scopes are deleted.
+  create table scope_usage_daily (
+    usage_date date not null,
+    scope_id wt_scope_id not null,
+    api_calls bigint not null default 0
+      check(api_calls >= 0),
+    session_seconds bigint not null default 0
+      check(session_seconds >= 0),
+    primary key (usage_date, scope_id)
+  );
+
+  create index scope_usage_daily_scope_id_ix
+    on scope_usage_daily (scope_id, usage_date);
+
+commit;
@@ -0,0 +1,34 @@
+begin;
+
+  -- target_connection_rate_limit records the most connections a session of a
+  -- target may open per minute on a worker, enforced by workers before
+  -- authorizing each connection. A target without a row in this table is not
+  -- limited.
+  create table target_connection_rate_limit (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    connections_per_minute integer not null
+      check(connections_per_minute > 0),
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on target_connection_rate_limit
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on target_connection_rate_limit
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on target_connection_rate_limit
+    for each row execute procedure immutable_columns('target_id', 'create_time');
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  drop table scope_event_sink;
+
+commit;
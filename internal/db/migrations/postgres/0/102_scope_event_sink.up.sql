@@ -0,0 +1,40 @@
+begin;
+
+  -- scope_event_sink is a webhook of an org which receives the outbox events
+  -- of the org and of its projects, so tenants can have their own audit
+  -- feeds. The secret signing the deliveries is encrypted with the database
+  -- key of the org. redact_fields are the payload fields, as dot separated
+  -- paths, whose values are redacted before delivery to the sink. Sinks are
+  -- immutable; they are replaced by deleting and creating them.
+  create table scope_event_sink (
+    public_id wt_public_id primary key,
+    scope_id wt_scope_id not null
+      references iam_scope_org(scope_id)
+      on delete cascade
+      on update cascade,
+    url text not null
+      constraint url_must_not_be_empty
+      check(length(trim(url)) > 0),
+    secret bytea not null,
+    key_id text not null
+      constraint key_id_must_not_be_empty
+      check(length(trim(key_id)) > 0),
+    redact_fields text[] not null default '{}',
+    create_time wt_timestamp
+  );
+
+  create index scope_event_sink_scope_id_ix
+    on scope_event_sink (scope_id);
+
+  create trigger
+    default_create_time_column
+  before insert on scope_event_sink
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on scope_event_sink
+    for each row execute procedure immutable_columns('public_id', 'scope_id', 'url', 'secret', 'key_id', 'redact_fields', 'create_time');
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  alter table server drop column mode;
+
+commit;
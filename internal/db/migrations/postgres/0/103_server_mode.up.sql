@@ -0,0 +1,11 @@
+begin;
+
+  -- mode is the mode a controller reported in its last status update:
+  -- read-only controllers serve only reads, so workers are not sent to them.
+  -- Workers are always read-write.
+  alter table server
+    add column mode text not null default 'read-write'
+      constraint server_mode_must_be_valid
+      check(mode in ('read-write', 'read-only'));
+
+commit;
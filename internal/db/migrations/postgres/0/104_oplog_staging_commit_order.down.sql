@@ -0,0 +1,10 @@
+begin;
+
+  drop trigger set_oplog_entry_staging_commit_seq on oplog_entry_staging;
+  drop function set_oplog_entry_staging_commit_seq;
+  drop sequence oplog_entry_staging_commit_seq;
+  alter table oplog_entry_staging
+    drop column commit_seq,
+    drop column encrypted;
+
+commit;
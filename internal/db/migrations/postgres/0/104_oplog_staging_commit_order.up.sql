@@ -0,0 +1,48 @@
+begin;
+
+  -- Entries are now encrypted with the oplog wrapper of their scope before
+  -- they are staged. encrypted is false for the entries staged before, whose
+  -- data is encrypted when they are flushed.
+  --
+  -- commit_seq orders the staged entries by the commit of the transaction
+  -- which staged them rather than by when they were staged, since a
+  -- transaction may stage an entry before another transaction which commits
+  -- first. It is null until the transaction commits.
+  alter table oplog_entry_staging
+    add column encrypted boolean not null default false,
+    add column commit_seq bigint;
+
+  create sequence oplog_entry_staging_commit_seq;
+
+  -- The entries staged before are all committed and keep their order
+  update oplog_entry_staging set commit_seq = id;
+  select setval('oplog_entry_staging_commit_seq', coalesce(max(id), 0) + 1, false)
+    from oplog_entry_staging;
+
+  create index oplog_entry_staging_commit_seq_ix
+    on oplog_entry_staging (commit_seq);
+
+  -- set_oplog_entry_staging_commit_seq numbers the entries staged by a
+  -- transaction as it commits, in the order they were staged. The advisory
+  -- lock is held until the commit completes, so transactions which staged
+  -- entries commit one at a time in the order of their numbers, and an entry
+  -- is never visible before an entry with a lower number.
+  create function set_oplog_entry_staging_commit_seq()
+    returns trigger
+  as $$
+  begin
+    perform pg_advisory_xact_lock(hashtext('oplog_entry_staging_commit_seq'));
+    update oplog_entry_staging
+       set commit_seq = nextval('oplog_entry_staging_commit_seq')
+     where id = new.id;
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create constraint trigger
+    set_oplog_entry_staging_commit_seq
+  after insert on oplog_entry_staging
+    deferrable initially deferred
+    for each row execute procedure set_oplog_entry_staging_commit_seq();
+
+commit;
@@ -0,0 +1,36 @@
+begin;
+
+  -- auth_password_recovery_totp holds the TOTP secret of a password account
+  -- with recovery codes. A recovery code only authenticates together with a
+  -- current code of the secret, so a leaked list of recovery codes is not
+  -- enough to authenticate. The secret is encrypted with the database key of
+  -- the auth method's scope. last_used_step is the time step of the last TOTP
+  -- code used, so a code can not be replayed.
+  create table auth_password_recovery_totp (
+    password_account_id wt_public_id primary key
+      references auth_password_account(public_id)
+      on delete cascade
+      on update cascade,
+    secret bytea not null
+      constraint auth_password_recovery_totp_secret_must_not_be_empty
+      check(length(secret) > 0),
+    key_id text not null
+      constraint key_id_must_not_be_empty
+      check(length(trim(key_id)) > 0),
+    last_used_step bigint not null default 0,
+    create_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on auth_password_recovery_totp
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_password_recovery_totp
+    for each row execute procedure immutable_columns('password_account_id', 'secret', 'key_id', 'create_time');
+
+commit;
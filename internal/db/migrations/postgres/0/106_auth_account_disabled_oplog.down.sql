@@ -0,0 +1,5 @@
+begin;
+
+  delete from oplog_ticket where name = 'auth_account_disabled';
+
+commit;
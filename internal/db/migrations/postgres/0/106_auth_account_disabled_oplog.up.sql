@@ -0,0 +1,9 @@
+begin;
+
+  -- Disabling and enabling accounts is recorded in the oplog.
+  insert into oplog_ticket
+    (name, version)
+  values
+    ('auth_account_disabled', 1);
+
+commit;
@@ -0,0 +1,6 @@
+begin;
+
+  delete from oplog_ticket where name = 'iam_user_disabled';
+  drop table iam_user_enabled;
+
+commit;
@@ -0,0 +1,21 @@
+begin;
+
+  -- iam_user_enabled records when disabled users were last enabled. Their
+  -- inactivity restarts then, so the controllers don't disable them again
+  -- right away, without counting it as a login.
+  create table iam_user_enabled (
+    iam_user_id wt_user_id primary key
+      references iam_user(public_id)
+      on delete cascade
+      on update cascade,
+    enable_time timestamp with time zone not null
+      default current_timestamp
+  );
+
+  -- Disabling and enabling users is recorded in the oplog.
+  insert into oplog_ticket
+    (name, version)
+  values
+    ('iam_user_disabled', 1);
+
+commit;
@@ -0,0 +1,68 @@
+begin;
+
+  drop trigger delete_iam_principal_grants_version on iam_group;
+  drop trigger delete_iam_principal_grants_version on iam_user;
+  drop function delete_iam_principal_grants_version;
+  drop trigger iam_role_grant_scope_grants_version on iam_role;
+  drop function iam_role_grant_scope_grants_version;
+  drop trigger iam_role_grants_version on iam_role_grant;
+  drop function iam_role_grants_version;
+  drop trigger iam_group_member_grants_version on iam_group_member_user;
+  drop function iam_group_member_grants_version;
+  drop trigger iam_principal_role_grants_version on iam_group_role_expiration;
+  drop trigger iam_principal_role_grants_version on iam_user_role_expiration;
+  drop trigger iam_principal_role_grants_version on iam_group_role;
+  drop trigger iam_principal_role_grants_version on iam_user_role;
+  drop function iam_principal_role_grants_version;
+  drop function increment_iam_role_grants_version;
+  drop function increment_iam_principal_grants_version;
+  drop table iam_principal_grants_version;
+
+  create table iam_grants_version (
+    id boolean primary key default true
+      constraint iam_grants_version_must_have_a_single_row
+      check(id),
+    version bigint not null default 1
+  );
+
+  insert into iam_grants_version default values;
+
+  create or replace function
+    increment_iam_grants_version()
+    returns trigger
+  as $$
+  begin
+    update iam_grants_version set version = version + 1;
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_role
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_role_grant
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_user_role
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_group_role
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_user_role_expiration
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_group_role_expiration
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_group_member_user
+    for each statement execute procedure increment_iam_grants_version();
+
+commit;
@@ -0,0 +1,161 @@
+begin;
+
+  -- The single row of iam_grants_version was updated by every change to the
+  -- roles, their grants and principals, and the groups of users, so all of
+  -- them contended on it and each invalidated the cached grants of all users.
+  -- It is replaced by a version per principal, i.e. user or group, which is
+  -- incremented by the changes to the roles it has, their grants and, for
+  -- users, the groups they are members of. Controllers caching the grants of
+  -- a user compare the versions of the user, u_anon, u_auth and the groups of
+  -- the user with those their cache entry was loaded at.
+  drop trigger increment_iam_grants_version on iam_role;
+  drop trigger increment_iam_grants_version on iam_role_grant;
+  drop trigger increment_iam_grants_version on iam_user_role;
+  drop trigger increment_iam_grants_version on iam_group_role;
+  drop trigger increment_iam_grants_version on iam_user_role_expiration;
+  drop trigger increment_iam_grants_version on iam_group_role_expiration;
+  drop trigger increment_iam_grants_version on iam_group_member_user;
+  drop function increment_iam_grants_version;
+  drop table iam_grants_version;
+
+  -- A principal without a row has version 0.
+  create table iam_principal_grants_version (
+    principal_id text primary key,
+    version bigint not null default 1
+  );
+
+  create or replace function
+    increment_iam_principal_grants_version(p_principal_id text)
+    returns void
+  as $$
+    insert into iam_principal_grants_version (principal_id)
+    values (p_principal_id)
+        on conflict (principal_id) do update
+       set version = iam_principal_grants_version.version + 1;
+  $$ language sql;
+
+  -- increment_iam_role_grants_version() increments the versions of all
+  -- principals of the role.
+  create or replace function
+    increment_iam_role_grants_version(p_role_id text)
+    returns void
+  as $$
+    insert into iam_principal_grants_version (principal_id)
+    select principal_id
+      from iam_user_role
+     where role_id = p_role_id
+     union
+    select principal_id
+      from iam_group_role
+     where role_id = p_role_id
+        on conflict (principal_id) do update
+       set version = iam_principal_grants_version.version + 1;
+  $$ language sql;
+
+  create or replace function
+    iam_principal_role_grants_version()
+    returns trigger
+  as $$
+  begin
+    if tg_op in ('UPDATE', 'DELETE') then
+      perform increment_iam_principal_grants_version(old.principal_id);
+    end if;
+    if tg_op = 'INSERT' or (tg_op = 'UPDATE' and new.principal_id <> old.principal_id) then
+      perform increment_iam_principal_grants_version(new.principal_id);
+    end if;
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger iam_principal_role_grants_version
+  after insert or update or delete on iam_user_role
+    for each row execute procedure iam_principal_role_grants_version();
+
+  create trigger iam_principal_role_grants_version
+  after insert or update or delete on iam_group_role
+    for each row execute procedure iam_principal_role_grants_version();
+
+  create trigger iam_principal_role_grants_version
+  after insert or update or delete on iam_user_role_expiration
+    for each row execute procedure iam_principal_role_grants_version();
+
+  create trigger iam_principal_role_grants_version
+  after insert or update or delete on iam_group_role_expiration
+    for each row execute procedure iam_principal_role_grants_version();
+
+  create or replace function
+    iam_group_member_grants_version()
+    returns trigger
+  as $$
+  begin
+    if tg_op in ('UPDATE', 'DELETE') then
+      perform increment_iam_principal_grants_version(old.member_id);
+    end if;
+    if tg_op = 'INSERT' or (tg_op = 'UPDATE' and new.member_id <> old.member_id) then
+      perform increment_iam_principal_grants_version(new.member_id);
+    end if;
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger iam_group_member_grants_version
+  after insert or update or delete on iam_group_member_user
+    for each row execute procedure iam_group_member_grants_version();
+
+  create or replace function
+    iam_role_grants_version()
+    returns trigger
+  as $$
+  begin
+    if tg_op in ('UPDATE', 'DELETE') then
+      perform increment_iam_role_grants_version(old.role_id);
+    end if;
+    if tg_op = 'INSERT' or (tg_op = 'UPDATE' and new.role_id <> old.role_id) then
+      perform increment_iam_role_grants_version(new.role_id);
+    end if;
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger iam_role_grants_version
+  after insert or update or delete on iam_role_grant
+    for each row execute procedure iam_role_grants_version();
+
+  -- Only the grant scope of a role changes its grants; its version is also
+  -- updated by the changes to its principals.
+  create or replace function
+    iam_role_grant_scope_grants_version()
+    returns trigger
+  as $$
+  begin
+    perform increment_iam_role_grants_version(new.public_id);
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger iam_role_grant_scope_grants_version
+  after update on iam_role
+    for each row
+    when (old.grant_scope_id is distinct from new.grant_scope_id)
+    execute procedure iam_role_grant_scope_grants_version();
+
+  create or replace function
+    delete_iam_principal_grants_version()
+    returns trigger
+  as $$
+  begin
+    delete from iam_principal_grants_version
+     where principal_id = old.public_id;
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger delete_iam_principal_grants_version
+  after delete on iam_user
+    for each row execute procedure delete_iam_principal_grants_version();
+
+  create trigger delete_iam_principal_grants_version
+  after delete on iam_group
+    for each row execute procedure delete_iam_principal_grants_version();
+
+commit;
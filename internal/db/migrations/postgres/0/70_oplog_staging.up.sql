@@ -0,0 +1,37 @@
+begin;
+
+  -- oplog_entry_staging holds oplog entries written while the controller is
+  -- in asynchronous oplog mode. An entry is inserted in the same transaction
+  -- as the change it describes, so it only becomes visible if that change is
+  -- committed. A background worker encrypts staged entries, writes them to
+  -- oplog_entry in id order and deletes them from this table. The data is not
+  -- encrypted while an entry is staged.
+  create table oplog_entry_staging (
+    id bigint generated always as identity primary key,
+    create_time wt_timestamp,
+    version text not null,
+    aggregate_name text not null,
+    -- the scope whose oplog key is used to encrypt the entry. Staged entries
+    -- are deleted with their scope, whose keys are required to encrypt them.
+    scope_id wt_scope_id not null
+      references iam_scope(public_id)
+      on delete cascade
+      on update cascade,
+    "data" bytea not null,
+    -- json encoded list of the entry's metadata key/value pairs
+    metadata bytea not null
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on oplog_entry_staging
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_entry_staging
+    for each row execute procedure immutable_columns('id', 'create_time', 'version', 'aggregate_name', 'scope_id', 'data', 'metadata');
+
+commit;
@@ -0,0 +1,7 @@
+begin;
+
+  drop trigger delete_derived_auth_tokens on auth_token;
+  drop function delete_derived_auth_tokens;
+  drop table auth_token_attenuation;
+
+commit;
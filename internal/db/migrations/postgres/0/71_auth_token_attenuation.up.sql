@@ -0,0 +1,62 @@
+begin;
+
+  -- auth_token_attenuation records the restrictions of an auth token derived
+  -- from a parent auth token by an exchange. An auth token without a row in
+  -- this table is not restricted.
+  create table auth_token_attenuation (
+    auth_token_id wt_public_id primary key
+      references auth_token(public_id)
+      on delete cascade
+      on update cascade,
+    -- derived tokens are deleted by the delete_derived_auth_tokens trigger
+    -- before their parent, so a derived token never outlives its parent and
+    -- never loses its restrictions.
+    parent_id wt_public_id not null
+      references auth_token(public_id)
+      on delete restrict
+      on update cascade,
+    -- the json encoded list of the scope ids and grants the derived token is
+    -- limited to.
+    grants text not null,
+    -- if not empty, the derived token may only be used for this target.
+    target_id text,
+    create_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on auth_token_attenuation
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_token_attenuation
+    for each row execute procedure immutable_columns('auth_token_id', 'parent_id', 'grants', 'target_id', 'create_time');
+
+  create or replace function
+    delete_derived_auth_tokens()
+    returns trigger
+  as $$
+  begin
+    delete from auth_token
+     where public_id in (
+       select auth_token_id
+         from auth_token_attenuation
+        where parent_id = old.public_id
+     );
+    return old;
+  end;
+  $$ language plpgsql;
+
+  comment on function
+    delete_derived_auth_tokens()
+  is
+    'function used in before delete triggers to delete the auth tokens derived from an auth token';
+
+  create trigger
+    delete_derived_auth_tokens
+  before delete on auth_token
+    for each row execute procedure delete_derived_auth_tokens();
+
+commit;
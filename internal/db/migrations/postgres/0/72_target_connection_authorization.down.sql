@@ -0,0 +1,5 @@
+begin;
+
+  drop table target_connection_authorization;
+
+commit;
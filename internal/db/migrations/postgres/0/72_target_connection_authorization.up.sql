@@ -0,0 +1,26 @@
+begin;
+
+  -- target_connection_authorization records the targets which require each
+  -- new connection to a session to be authorized again, so revoking a
+  -- permission applies to new connections of sessions already established.
+  -- A target without a row in this table only authorizes the session.
+  create table target_connection_authorization (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    create_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on target_connection_authorization
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on target_connection_authorization
+    for each row execute procedure immutable_columns('target_id', 'create_time');
+
+commit;
@@ -0,0 +1,18 @@
+begin;
+
+  drop trigger delete_annotations on iam_scope;
+  drop trigger delete_annotations on iam_user;
+  drop trigger delete_annotations on iam_group;
+  drop trigger delete_annotations on iam_role;
+  drop trigger delete_annotations on auth_method;
+  drop trigger delete_annotations on auth_account;
+  drop trigger delete_annotations on auth_token;
+  drop trigger delete_annotations on host_catalog;
+  drop trigger delete_annotations on host;
+  drop trigger delete_annotations on host_set;
+  drop trigger delete_annotations on target;
+  drop trigger delete_annotations on session;
+  drop function delete_annotations;
+  drop table annotation;
+
+commit;
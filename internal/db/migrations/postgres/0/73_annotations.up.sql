@@ -0,0 +1,114 @@
+begin;
+
+  -- annotation stores custom metadata of resources, so external systems can
+  -- record e.g. ownership or cost center of a resource. Since any resource can
+  -- be annotated resource_id is not a foreign key; annotations are deleted
+  -- with their resource by the delete_annotations triggers below.
+  create table annotation (
+    resource_id wt_public_id not null,
+    key text not null
+      constraint annotation_key_must_not_be_empty
+      check(length(trim(key)) > 0)
+      constraint annotation_key_must_not_be_too_long
+      check(length(key) <= 128),
+    value text not null
+      constraint annotation_value_must_not_be_too_long
+      check(length(value) <= 1024),
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    primary key(resource_id, key)
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on annotation
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on annotation
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on annotation
+    for each row execute procedure immutable_columns('resource_id', 'key', 'create_time');
+
+  create or replace function
+    delete_annotations()
+    returns trigger
+  as $$
+  begin
+    delete from annotation
+     where resource_id = old.public_id;
+    return null;
+  end;
+  $$ language plpgsql;
+
+  comment on function
+    delete_annotations()
+  is
+    'function used in after delete triggers to delete the annotations of a resource';
+
+  create trigger
+    delete_annotations
+  after delete on iam_scope
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on iam_user
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on iam_group
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on iam_role
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on auth_method
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on auth_account
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on auth_token
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on host_catalog
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on host
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on host_set
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on target
+    for each row execute procedure delete_annotations();
+
+  create trigger
+    delete_annotations
+  after delete on session
+    for each row execute procedure delete_annotations();
+
+commit;
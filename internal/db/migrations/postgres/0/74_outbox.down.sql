@@ -0,0 +1,5 @@
+begin;
+
+  drop table outbox_message;
+
+commit;
@@ -0,0 +1,42 @@
+begin;
+
+  -- outbox_message holds external side effects, e.g. webhooks or events,
+  -- enqueued by repository operations. A message is inserted in the same
+  -- transaction as the change it notifies about, so it is only delivered if
+  -- that change is committed. A background dispatcher delivers messages in id
+  -- order, deletes delivered messages and reschedules failed deliveries with
+  -- backoff until they succeed or exceed their maximum attempts.
+  create table outbox_message (
+    id bigint generated always as identity primary key,
+    create_time wt_timestamp,
+    kind text not null
+      constraint outbox_message_kind_must_not_be_empty
+      check(length(trim(kind)) > 0),
+    payload bytea not null,
+    attempts integer not null default 0
+      constraint outbox_message_attempts_must_not_be_negative
+      check(attempts >= 0),
+    next_attempt_time timestamp with time zone not null default current_timestamp,
+    last_error text,
+    -- set when the message exceeded its maximum delivery attempts; failed
+    -- messages are no longer delivered and are kept for inspection.
+    failed_time timestamp with time zone
+  );
+
+  create index outbox_message_next_attempt_time_ix
+    on outbox_message (next_attempt_time)
+    where failed_time is null;
+
+  create trigger
+    default_create_time_column
+  before
+  insert on outbox_message
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on outbox_message
+    for each row execute procedure immutable_columns('id', 'create_time', 'kind', 'payload');
+
+commit;
@@ -0,0 +1,45 @@
+begin;
+
+  -- schema_migration_record records the migrations applied to the database by
+  -- InitStore: the checksum of each sql migration, so that a changed migration
+  -- is detected before further migrations are applied, and each go data
+  -- migration, so that it only runs once. Sql migrations applied before this
+  -- table existed are recorded with the checksums of the binary which first
+  -- runs InitStore after it is created.
+  create table schema_migration_record (
+    version bigint not null
+      constraint schema_migration_record_version_must_be_positive
+      check(version > 0),
+    kind text not null
+      constraint schema_migration_record_kind_must_be_sql_or_data
+      check(kind in ('sql', 'data')),
+    name text not null
+      constraint schema_migration_record_name_must_not_be_empty
+      check(length(trim(name)) > 0),
+    -- hex encoded sha256 of the up migration; null for data migrations
+    checksum text
+      constraint schema_migration_record_checksum_required_for_sql
+      check((kind = 'sql') = (checksum is not null)),
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    primary key(version, kind)
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on schema_migration_record
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on schema_migration_record
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on schema_migration_record
+    for each row execute procedure immutable_columns('version', 'kind', 'create_time');
+
+commit;
@@ -0,0 +1,6 @@
+begin;
+
+  drop table auth_password_recovery_code;
+  drop function auth_password_recovery_code_used_once;
+
+commit;
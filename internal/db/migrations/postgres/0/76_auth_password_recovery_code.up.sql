@@ -0,0 +1,57 @@
+begin;
+
+  -- auth_password_recovery_code holds the single use recovery codes of a
+  -- password account, which authenticate as the account if its password is
+  -- lost. Only a salted hash of each code is stored. A code is used by setting
+  -- its used_time, which can only be done once.
+  create table auth_password_recovery_code (
+    private_id wt_private_id primary key,
+    password_account_id wt_public_id not null
+      references auth_password_account(public_id)
+      on delete cascade
+      on update cascade,
+    salt bytea not null
+      constraint auth_password_recovery_code_salt_must_not_be_empty
+      check(length(salt) > 0),
+    code_hash bytea not null
+      constraint auth_password_recovery_code_code_hash_must_not_be_empty
+      check(length(code_hash) > 0),
+    create_time wt_timestamp,
+    used_time timestamp with time zone
+  );
+
+  create index auth_password_recovery_code_password_account_id_ix
+    on auth_password_recovery_code (password_account_id)
+    where used_time is null;
+
+  create trigger
+    default_create_time_column
+  before
+  insert on auth_password_recovery_code
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_password_recovery_code
+    for each row execute procedure immutable_columns('private_id', 'password_account_id', 'salt', 'code_hash', 'create_time');
+
+  create or replace function
+    auth_password_recovery_code_used_once()
+    returns trigger
+  as $$
+  begin
+    if old.used_time is not null then
+      raise exception 'recovery code % was already used', old.private_id;
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger
+    auth_password_recovery_code_used_once
+  before
+  update on auth_password_recovery_code
+    for each row execute procedure auth_password_recovery_code_used_once();
+
+commit;
@@ -0,0 +1,31 @@
+begin;
+
+  -- auth_token_refresh records the login time of an auth token issued by
+  -- refreshing another auth token. The login time is carried over from token
+  -- to token, so refreshing can't extend a login beyond the maximum lifetime
+  -- of auth tokens. An auth token without a row in this table was issued by
+  -- authenticating and its login time is its create time.
+  create table auth_token_refresh (
+    auth_token_id wt_public_id primary key
+      references auth_token(public_id)
+      on delete cascade
+      on update cascade,
+    -- the public id of the auth token this token was refreshed from. it is
+    -- not a foreign key since the refreshed token expires independently.
+    refreshed_from_id wt_public_id not null,
+    login_time timestamp with time zone not null,
+    create_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on auth_token_refresh
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_token_refresh
+    for each row execute procedure immutable_columns('auth_token_id', 'refreshed_from_id', 'login_time', 'create_time');
+
+commit;
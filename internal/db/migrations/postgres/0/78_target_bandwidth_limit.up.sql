@@ -0,0 +1,35 @@
+begin;
+
+  -- target_bandwidth_limit records the bandwidth caps of targets, enforced by
+  -- workers when proxying their connections. A limit of 0 is unlimited. A
+  -- target without a row in this table is not limited.
+  create table target_bandwidth_limit (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    connection_bytes_per_second bigint not null default 0
+      check(connection_bytes_per_second >= 0),
+    session_bytes_per_second bigint not null default 0
+      check(session_bytes_per_second >= 0),
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on target_bandwidth_limit
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on target_bandwidth_limit
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on target_bandwidth_limit
+    for each row execute procedure immutable_columns('target_id', 'create_time');
+
+commit;
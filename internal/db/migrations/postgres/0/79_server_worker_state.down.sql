@@ -0,0 +1,5 @@
+begin;
+
+  drop table server_worker_state;
+
+commit;
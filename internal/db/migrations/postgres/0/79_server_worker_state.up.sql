@@ -0,0 +1,40 @@
+begin;
+
+  -- server_worker_state records the state of workers from their last status
+  -- report, so controllers can take into account workers connected to other
+  -- controllers when authorizing sessions. The state is encrypted with the
+  -- global scope database key.
+  create table server_worker_state (
+    worker_id text not null,
+    worker_type text not null default 'worker'
+      constraint worker_type_must_be_worker
+      check(worker_type = 'worker'),
+    controller_id text not null
+      constraint controller_id_must_not_be_empty
+      check(length(trim(controller_id)) > 0),
+    key_id text not null
+      references kms_database_key_version(private_id)
+      on delete restrict
+      on update cascade,
+    state bytea not null,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    primary key (worker_id),
+    foreign key (worker_id, worker_type)
+      references server(private_id, type)
+      on delete cascade
+      on update cascade
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on server_worker_state
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on server_worker_state
+    for each row execute procedure immutable_columns('worker_id', 'worker_type', 'create_time');
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  drop table server_connection_check;
+
+commit;
@@ -0,0 +1,44 @@
+begin;
+
+  -- server_connection_check records checks of the connectivity from a worker
+  -- to the endpoint of a target, requested by admins before handing the target
+  -- to users. A check is dispatched to its worker in the response to a status
+  -- report of the worker, which reports the result with a later status.
+  create table server_connection_check (
+    check_id wt_private_id primary key,
+    target_id wt_public_id not null
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    worker_id text not null,
+    worker_type text not null default 'worker'
+      constraint worker_type_must_be_worker
+      check(worker_type = 'worker'),
+    endpoint text not null
+      constraint endpoint_must_not_be_empty
+      check(length(trim(endpoint)) > 0),
+    tls boolean not null default false,
+    dispatch_time timestamp with time zone,
+    complete_time timestamp with time zone,
+    reachable boolean not null default false,
+    latency_ms bigint not null default 0,
+    error text not null default '',
+    create_time wt_timestamp,
+    foreign key (worker_id, worker_type)
+      references server(private_id, type)
+      on delete cascade
+      on update cascade
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on server_connection_check
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on server_connection_check
+    for each row execute procedure immutable_columns('check_id', 'target_id', 'worker_id', 'worker_type', 'endpoint', 'tls', 'create_time');
+
+commit;
@@ -0,0 +1,7 @@
+begin;
+
+  drop table iam_user_disabled;
+  drop table iam_scope_inactivity_policy;
+  drop table iam_user_login;
+
+commit;
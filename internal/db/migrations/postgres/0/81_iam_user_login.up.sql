@@ -0,0 +1,77 @@
+begin;
+
+  -- iam_user_login records the last successful authentication of a user with
+  -- each of its accounts. Tokens issued by refreshing or exchanging an auth
+  -- token are not logins.
+  create table iam_user_login (
+    iam_user_id wt_user_id not null
+      references iam_user(public_id)
+      on delete cascade
+      on update cascade,
+    auth_account_id wt_public_id not null
+      references auth_account(public_id)
+      on delete cascade
+      on update cascade,
+    last_login_time timestamp with time zone not null
+      default current_timestamp,
+    primary key(iam_user_id, auth_account_id)
+  );
+
+  create index iam_user_login_auth_account_id_ix
+    on iam_user_login (auth_account_id);
+
+  -- iam_scope_inactivity_policy records how many days the users of a scope
+  -- may go without logging in before they are reported as inactive and, if
+  -- disable_inactive is set, disabled by the controllers. A user who never
+  -- logged in is inactive from its create time.
+  create table iam_scope_inactivity_policy (
+    scope_id wt_scope_id primary key
+      references iam_scope(public_id)
+      on delete cascade
+      on update cascade,
+    max_inactive_days integer not null
+      check(max_inactive_days > 0),
+    disable_inactive boolean not null default false,
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on iam_scope_inactivity_policy
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on iam_scope_inactivity_policy
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_scope_inactivity_policy
+    for each row execute procedure immutable_columns('scope_id', 'create_time');
+
+  -- iam_user_disabled records the users which may not authenticate. Their
+  -- auth tokens are deleted when they are disabled.
+  create table iam_user_disabled (
+    iam_user_id wt_user_id primary key
+      references iam_user(public_id)
+      on delete cascade
+      on update cascade,
+    reason text not null,
+    create_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on iam_user_disabled
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_user_disabled
+    for each row execute procedure immutable_columns('iam_user_id', 'reason', 'create_time');
+
+commit;
@@ -0,0 +1,7 @@
+begin;
+
+  drop trigger auth_token_principal_enabled on auth_token;
+  drop function auth_token_principal_enabled;
+  drop table auth_account_disabled;
+
+commit;
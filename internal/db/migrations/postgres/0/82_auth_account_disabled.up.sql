@@ -0,0 +1,60 @@
+begin;
+
+  -- auth_account_disabled records the accounts which may not authenticate.
+  -- Their auth tokens are deleted when they are disabled. Like disabled users,
+  -- disabled accounts keep their user association so they can be enabled
+  -- again.
+  create table auth_account_disabled (
+    auth_account_id wt_public_id primary key
+      references auth_account(public_id)
+      on delete cascade
+      on update cascade,
+    reason text not null,
+    create_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on auth_account_disabled
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_account_disabled
+    for each row execute procedure immutable_columns('auth_account_id', 'reason', 'create_time');
+
+  create or replace function
+    auth_token_principal_enabled()
+    returns trigger
+  as $$
+  begin
+    perform
+       from auth_account_disabled
+      where auth_account_id = new.auth_account_id;
+    if found then
+      raise exception 'auth account % is disabled', new.auth_account_id;
+    end if;
+    perform
+       from auth_account a
+       join iam_user_disabled d
+         on d.iam_user_id = a.iam_user_id
+      where a.public_id = new.auth_account_id;
+    if found then
+      raise exception 'iam user of auth account % is disabled', new.auth_account_id;
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  comment on function
+    auth_token_principal_enabled()
+  is
+    'function used in before insert triggers to prevent issuing auth tokens to disabled accounts and users';
+
+  create trigger
+    auth_token_principal_enabled
+  before insert on auth_token
+    for each row execute procedure auth_token_principal_enabled();
+
+commit;
@@ -0,0 +1,6 @@
+begin;
+
+  drop table iam_group_role_expiration;
+  drop table iam_user_role_expiration;
+
+commit;
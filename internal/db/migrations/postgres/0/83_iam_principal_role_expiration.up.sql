@@ -0,0 +1,60 @@
+begin;
+
+  -- iam_user_role_expiration and iam_group_role_expiration record when a
+  -- temporary assignment of a role to a principal expires. Expired assignments
+  -- are ignored when resolving grants and are deleted by a scheduled job. The
+  -- expiration is deleted along with its assignment, so an assignment which is
+  -- removed and added again is permanent unless given a new expiration.
+  create table iam_user_role_expiration (
+    role_id wt_role_id,
+    principal_id wt_user_id,
+    expiration_time timestamp with time zone not null,
+    create_time wt_timestamp,
+    primary key (role_id, principal_id),
+    foreign key (role_id, principal_id)
+      references iam_user_role(role_id, principal_id)
+      on delete cascade
+      on update cascade
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on iam_user_role_expiration
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_user_role_expiration
+    for each row execute procedure immutable_columns('role_id', 'principal_id', 'create_time');
+
+  create index iam_user_role_expiration_time_ix
+    on iam_user_role_expiration (expiration_time);
+
+  create table iam_group_role_expiration (
+    role_id wt_role_id,
+    principal_id wt_public_id,
+    expiration_time timestamp with time zone not null,
+    create_time wt_timestamp,
+    primary key (role_id, principal_id),
+    foreign key (role_id, principal_id)
+      references iam_group_role(role_id, principal_id)
+      on delete cascade
+      on update cascade
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on iam_group_role_expiration
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_group_role_expiration
+    for each row execute procedure immutable_columns('role_id', 'principal_id', 'create_time');
+
+  create index iam_group_role_expiration_time_ix
+    on iam_group_role_expiration (expiration_time);
+
+commit;
@@ -0,0 +1,7 @@
+begin;
+
+  drop table iam_access_request_event;
+  drop table iam_access_request;
+  drop table iam_access_request_status_enm;
+
+commit;
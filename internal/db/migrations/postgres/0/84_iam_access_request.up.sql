@@ -0,0 +1,108 @@
+begin;
+
+  create table iam_access_request_status_enm (
+    name text primary key
+      constraint only_predefined_access_request_statuses_allowed
+      check (
+        name in ('pending', 'approved', 'denied', 'cancelled')
+      )
+  );
+
+  insert into iam_access_request_status_enm (name)
+  values
+    ('pending'),
+    ('approved'),
+    ('denied'),
+    ('cancelled');
+
+  -- iam_access_request is a request by a user to be assigned a role for a
+  -- limited duration. Approving it assigns the role to the user until the
+  -- expiration time, using iam_user_role_expiration. Only pending requests may
+  -- be decided and a user may not decide its own request.
+  create table iam_access_request (
+    public_id wt_public_id primary key,
+    role_id wt_role_id not null
+      references iam_role(public_id)
+      on delete cascade
+      on update cascade,
+    requester_id wt_user_id not null
+      references iam_user(public_id)
+      on delete cascade
+      on update cascade,
+    justification text not null
+      constraint justification_must_not_be_empty
+      check(length(trim(justification)) > 0),
+    duration_seconds integer not null
+      constraint duration_seconds_must_be_greater_than_0
+      check(duration_seconds > 0),
+    status text not null default 'pending'
+      references iam_access_request_status_enm(name)
+      on delete restrict
+      on update cascade,
+    decider_id wt_user_id
+      references iam_user(public_id)
+      on delete set null
+      on update cascade,
+    decision_comment text,
+    decision_time timestamp with time zone,
+    expiration_time timestamp with time zone,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    constraint requester_may_not_decide
+      check(decider_id is null or decider_id <> requester_id)
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on iam_access_request
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on iam_access_request
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_access_request
+    for each row execute procedure immutable_columns('public_id', 'role_id', 'requester_id', 'justification', 'duration_seconds', 'create_time');
+
+  create index iam_access_request_role_id_ix
+    on iam_access_request (role_id);
+  create index iam_access_request_requester_id_ix
+    on iam_access_request (requester_id);
+
+  -- iam_access_request_event is the audit trail of an access request: its
+  -- creation and every decision on it, with the user acting.
+  create table iam_access_request_event (
+    id bigint generated always as identity primary key,
+    access_request_id wt_public_id not null
+      references iam_access_request(public_id)
+      on delete cascade
+      on update cascade,
+    event text not null
+      constraint only_predefined_access_request_events_allowed
+      check (
+        event in ('requested', 'approved', 'denied', 'cancelled')
+      ),
+    actor_id text not null,
+    comment text,
+    create_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on iam_access_request_event
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_access_request_event
+    for each row execute procedure immutable_columns('id', 'access_request_id', 'event', 'actor_id', 'comment', 'create_time');
+
+  create index iam_access_request_event_access_request_id_ix
+    on iam_access_request_event (access_request_id);
+
+commit;
@@ -0,0 +1,9 @@
+begin;
+
+  drop trigger check_in_target_credential on session_state;
+  drop function check_in_target_credential;
+  drop table target_credential_checkout_waiter;
+  drop table target_credential_checkout;
+  drop table target_credential_checkout_policy;
+
+commit;
@@ -0,0 +1,135 @@
+begin;
+
+  -- target_credential_checkout_policy marks targets backed by a shared
+  -- credential which only one session may hold at a time. When the credential
+  -- is checked out, a new session is rejected immediately ('reject') or waits
+  -- in line for up to queue_timeout_seconds ('queue'). If rotate_on_check_in
+  -- is set, a rotation of the credential is requested through the outbox
+  -- whenever it is checked in. A target without a row in this table has no
+  -- checkout.
+  create table target_credential_checkout_policy (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    mode text not null
+      constraint only_predefined_credential_checkout_modes_allowed
+      check (
+        mode in ('reject', 'queue')
+      ),
+    queue_timeout_seconds integer not null default 0
+      constraint queue_timeout_seconds_must_not_be_negative
+      check(queue_timeout_seconds >= 0),
+    rotate_on_check_in boolean not null default false,
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on target_credential_checkout_policy
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on target_credential_checkout_policy
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on target_credential_checkout_policy
+    for each row execute procedure immutable_columns('target_id', 'create_time');
+
+  -- target_credential_checkout holds the checked out credentials, at most one
+  -- per target. A checkout is reserved before its session is created and
+  -- attached to the session afterwards; reservations never attached are
+  -- stale after a minute. The checkout is checked in when its session is
+  -- terminated or deleted.
+  create table target_credential_checkout (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    checkout_id text not null unique,
+    user_id wt_user_id not null,
+    session_id wt_public_id unique
+      references session(public_id)
+      on delete cascade
+      on update cascade,
+    create_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on target_credential_checkout
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on target_credential_checkout
+    for each row execute procedure immutable_columns('target_id', 'checkout_id', 'user_id', 'create_time');
+
+  -- target_credential_checkout_waiter is the line of sessions waiting for
+  -- the credential of a target with the 'queue' mode, served in id order.
+  -- Waiters refresh last_seen_time while waiting; waiters not seen for 30
+  -- seconds, e.g. of a controller which stopped, no longer hold up the line.
+  create table target_credential_checkout_waiter (
+    id bigint generated always as identity primary key,
+    target_id wt_public_id not null
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    user_id wt_user_id not null,
+    create_time wt_timestamp,
+    last_seen_time timestamp with time zone not null default current_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on target_credential_checkout_waiter
+    for each row execute procedure default_create_time();
+
+  create index target_credential_checkout_waiter_target_id_ix
+    on target_credential_checkout_waiter (target_id, id);
+
+  -- check_in_target_credential checks in the credential held by a session
+  -- when it is terminated and, if its target's policy says so, requests a
+  -- rotation of the credential through the outbox.
+  create or replace function
+    check_in_target_credential()
+    returns trigger
+  as $$
+  declare
+    checked_in record;
+  begin
+    if new.state <> 'terminated' then
+      return new;
+    end if;
+    delete from target_credential_checkout
+     where session_id = new.session_id
+    returning target_id, checkout_id, user_id into checked_in;
+    if found then
+      insert into outbox_message (kind, payload)
+      select 'target.credential_rotation',
+             convert_to(json_build_object(
+               'target_id', checked_in.target_id,
+               'checkout_id', checked_in.checkout_id,
+               'session_id', new.session_id,
+               'user_id', checked_in.user_id
+             )::text, 'UTF8')
+        from target_credential_checkout_policy p
+       where p.target_id = checked_in.target_id
+         and p.rotate_on_check_in;
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger
+    check_in_target_credential
+  after insert on session_state
+    for each row execute procedure check_in_target_credential();
+
+commit;
@@ -0,0 +1,34 @@
+begin;
+
+  -- restore the rotation requests without a schema version.
+  create or replace function
+    check_in_target_credential()
+    returns trigger
+  as $$
+  declare
+    checked_in record;
+  begin
+    if new.state <> 'terminated' then
+      return new;
+    end if;
+    delete from target_credential_checkout
+     where session_id = new.session_id
+    returning target_id, checkout_id, user_id into checked_in;
+    if found then
+      insert into outbox_message (kind, payload)
+      select 'target.credential_rotation',
+             convert_to(json_build_object(
+               'target_id', checked_in.target_id,
+               'checkout_id', checked_in.checkout_id,
+               'session_id', new.session_id,
+               'user_id', checked_in.user_id
+             )::text, 'UTF8')
+        from target_credential_checkout_policy p
+       where p.target_id = checked_in.target_id
+         and p.rotate_on_check_in;
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+commit;
@@ -0,0 +1,8 @@
+begin;
+
+  drop table iam_scope_project_target_default;
+  drop table iam_scope_project_template_role_grant;
+  drop table iam_scope_project_template_role;
+  drop table iam_scope_project_template;
+
+commit;
@@ -0,0 +1,89 @@
+begin;
+
+  -- iam_scope_project_template is the template of the projects of an org,
+  -- applied when a project is created in the org. The target settings are
+  -- copied to the new project as the defaults of its targets; a null setting
+  -- keeps the default of the target table.
+  create table iam_scope_project_template (
+    org_id wt_scope_id primary key
+      references iam_scope_org(scope_id)
+      on delete cascade
+      on update cascade,
+    target_default_port integer
+      constraint target_default_port_must_be_greater_than_0
+      check(target_default_port > 0),
+    target_session_max_seconds integer
+      constraint target_session_max_seconds_must_be_greater_than_0
+      check(target_session_max_seconds > 0),
+    target_session_connection_limit integer
+      constraint target_session_connection_limit_must_be_greater_than_0_or_negative_1
+      check(target_session_connection_limit > 0 or target_session_connection_limit = -1),
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on iam_scope_project_template
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on iam_scope_project_template
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on iam_scope_project_template
+    for each row execute procedure immutable_columns('org_id', 'create_time');
+
+  -- iam_scope_project_template_role holds the roles created in every new
+  -- project of an org, with the grants in
+  -- iam_scope_project_template_role_grant.
+  create table iam_scope_project_template_role (
+    org_id wt_scope_id not null
+      references iam_scope_project_template(org_id)
+      on delete cascade
+      on update cascade,
+    name text not null
+      constraint name_must_not_be_empty
+      check(length(trim(name)) > 0),
+    description text,
+    primary key(org_id, name)
+  );
+
+  create table iam_scope_project_template_role_grant (
+    org_id wt_scope_id not null,
+    role_name text not null,
+    raw_grant text not null
+      constraint raw_grant_must_not_be_empty
+      check(length(trim(raw_grant)) > 0),
+    primary key(org_id, role_name, raw_grant),
+    foreign key (org_id, role_name)
+      references iam_scope_project_template_role(org_id, name)
+      on delete cascade
+      on update cascade
+  );
+
+  -- iam_scope_project_target_default holds the defaults of the settings of
+  -- the targets of a project, copied from the template of its org when the
+  -- project was created. A null setting keeps the default of the target
+  -- table.
+  create table iam_scope_project_target_default (
+    project_id wt_scope_id primary key
+      references iam_scope_project(scope_id)
+      on delete cascade
+      on update cascade,
+    default_port integer,
+    session_max_seconds integer,
+    session_connection_limit integer,
+    create_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on iam_scope_project_target_default
+    for each row execute procedure default_create_time();
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  drop table target_peer_identity;
+
+commit;
@@ -0,0 +1,34 @@
+begin;
+
+  -- target_peer_identity records how workers pass the identity of the user of
+  -- a session to the endpoints of a target, so the logs of an endpoint reached
+  -- with a shared credential can attribute actions to the user. A target
+  -- without a row in this table does not pass it.
+  create table target_peer_identity (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    mode text not null
+      check(mode in ('proxy_v2')),
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on target_peer_identity
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on target_peer_identity
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on target_peer_identity
+    for each row execute procedure immutable_columns('target_id', 'create_time');
+
+commit;
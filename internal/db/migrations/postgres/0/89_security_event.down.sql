@@ -0,0 +1,6 @@
+begin;
+
+  drop table security_event_rollup;
+  drop table security_event;
+
+commit;
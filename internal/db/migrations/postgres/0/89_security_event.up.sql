@@ -0,0 +1,44 @@
+begin;
+
+  -- security_event records the authentication successes and failures,
+  -- lockouts, auth token revocations and uses of the recovery KMS as they
+  -- happen. The controllers periodically roll them up into
+  -- security_event_rollup, deleting them, so this table only holds the events
+  -- of the last few minutes. Events keep their scope and auth method ids after
+  -- these are deleted.
+  create table security_event (
+    id bigint generated always as identity primary key,
+    kind text not null
+      check(kind in (
+        'authentication_succeeded',
+        'authentication_failed',
+        'lockout',
+        'auth_token_revoked',
+        'recovery_kms_used'
+      )),
+    scope_id wt_scope_id not null,
+    -- auth_method_id is empty for events not about an auth method, such as
+    -- uses of the recovery KMS.
+    auth_method_id text not null default '',
+    create_time timestamp with time zone not null default current_timestamp
+  );
+
+  create index security_event_create_time_ix
+    on security_event (create_time);
+
+  -- security_event_rollup counts the security events of each kind by hour,
+  -- scope and auth method.
+  create table security_event_rollup (
+    bucket_time timestamp with time zone not null,
+    kind text not null,
+    scope_id wt_scope_id not null,
+    auth_method_id text not null,
+    event_count bigint not null
+      check(event_count > 0),
+    primary key (bucket_time, kind, scope_id, auth_method_id)
+  );
+
+  create index security_event_rollup_scope_id_ix
+    on security_event_rollup (scope_id, bucket_time);
+
+commit;
@@ -0,0 +1,14 @@
+begin;
+
+  drop trigger immutable_columns on auth_token_attenuation;
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_token_attenuation
+    for each row execute procedure immutable_columns('auth_token_id', 'parent_id', 'grants', 'target_id', 'create_time');
+
+  alter table auth_token_attenuation
+    drop column preset;
+
+commit;
@@ -0,0 +1,18 @@
+begin;
+
+  -- preset restricts the actions of an auth token issued for a least
+  -- privilege preset, whatever its grants allow. It is empty for tokens
+  -- which are not restricted by a preset.
+  alter table auth_token_attenuation
+    add column preset text not null default ''
+      check(preset in ('', 'terraform', 'read-only'));
+
+  drop trigger immutable_columns on auth_token_attenuation;
+
+  create trigger
+    immutable_columns
+  before
+  update on auth_token_attenuation
+    for each row execute procedure immutable_columns('auth_token_id', 'parent_id', 'grants', 'target_id', 'preset', 'create_time');
+
+commit;
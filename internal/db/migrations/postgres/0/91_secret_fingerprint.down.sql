@@ -0,0 +1,5 @@
+begin;
+
+  drop table secret_fingerprint;
+
+commit;
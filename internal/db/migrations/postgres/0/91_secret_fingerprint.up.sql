@@ -0,0 +1,25 @@
+begin;
+
+  -- secret_fingerprint records the fingerprints of the secrets brokered to
+  -- clients, the hex encoded HMAC-SHA256 of each secret, so a leaked secret
+  -- can be traced to the session it was brokered for. The secrets themselves
+  -- are never stored. Fingerprints keep their session, target, user and scope
+  -- ids after these are deleted, since leaks are often found long after the
+  -- session ended.
+  create table secret_fingerprint (
+    id bigint generated always as identity primary key,
+    fingerprint text not null
+      check(length(fingerprint) = 64),
+    kind text not null
+      check(kind in ('session_private_key')),
+    session_id text not null,
+    target_id text not null,
+    user_id text not null,
+    scope_id text not null,
+    create_time timestamp with time zone not null default current_timestamp
+  );
+
+  create index secret_fingerprint_fingerprint_ix
+    on secret_fingerprint (fingerprint);
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  drop index session_user_id_target_id_create_time_ix;
+
+commit;
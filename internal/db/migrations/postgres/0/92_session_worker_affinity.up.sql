@@ -0,0 +1,9 @@
+begin;
+
+  -- Supports finding the worker of the previous session of a user to a
+  -- target, which is preferred for their next session when worker affinity
+  -- is enabled.
+  create index session_user_id_target_id_create_time_ix
+    on session (user_id, target_id, create_time);
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+  drop table schema_fingerprint;
+
+commit;
@@ -0,0 +1,36 @@
+begin;
+
+  -- schema_fingerprint records the fingerprint of the schema right after the
+  -- migrations of each version are applied by InitStore: the hex encoded
+  -- sha256 of its tables, columns, constraints and indexes. The schema is
+  -- verified against the fingerprint of its version before migrating and when
+  -- a controller starts, so changes made outside of migrations are detected.
+  create table schema_fingerprint (
+    version bigint primary key
+      constraint schema_fingerprint_version_must_be_positive
+      check(version > 0),
+    fingerprint text not null
+      constraint schema_fingerprint_fingerprint_must_be_sha256
+      check(length(fingerprint) = 64),
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on schema_fingerprint
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on schema_fingerprint
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on schema_fingerprint
+    for each row execute procedure immutable_columns('version', 'create_time');
+
+commit;
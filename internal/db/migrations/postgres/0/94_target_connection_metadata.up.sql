@@ -0,0 +1,38 @@
+begin;
+
+  -- target_connection_metadata records the key/value metadata of targets
+  -- returned with their session authorizations, e.g. the name of the database
+  -- behind a target, which connect helpers use to fill in client flags.
+  create table target_connection_metadata (
+    target_id wt_public_id
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    key text not null
+      constraint target_connection_metadata_key_must_be_valid
+      check(key ~ '^[a-z][a-z0-9_]{0,63}$'),
+    value text not null
+      constraint target_connection_metadata_value_too_long
+      check(length(value) <= 1024),
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    primary key(target_id, key)
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on target_connection_metadata
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on target_connection_metadata
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on target_connection_metadata
+    for each row execute procedure immutable_columns('target_id', 'key', 'create_time');
+
+commit;
@@ -0,0 +1,13 @@
+begin;
+
+  drop trigger increment_iam_grants_version on iam_role;
+  drop trigger increment_iam_grants_version on iam_role_grant;
+  drop trigger increment_iam_grants_version on iam_user_role;
+  drop trigger increment_iam_grants_version on iam_group_role;
+  drop trigger increment_iam_grants_version on iam_user_role_expiration;
+  drop trigger increment_iam_grants_version on iam_group_role_expiration;
+  drop trigger increment_iam_grants_version on iam_group_member_user;
+  drop function increment_iam_grants_version;
+  drop table iam_grants_version;
+
+commit;
@@ -0,0 +1,54 @@
+begin;
+
+  -- iam_grants_version has a single row whose version is incremented by every
+  -- change to the roles, their grants and principals, and the groups of
+  -- users. Controllers caching the grants of users compare it with the
+  -- version their cache was loaded at to discard stale grants.
+  create table iam_grants_version (
+    id boolean primary key default true
+      constraint iam_grants_version_must_have_a_single_row
+      check(id),
+    version bigint not null default 1
+  );
+
+  insert into iam_grants_version default values;
+
+  create or replace function
+    increment_iam_grants_version()
+    returns trigger
+  as $$
+  begin
+    update iam_grants_version set version = version + 1;
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_role
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_role_grant
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_user_role
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_group_role
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_user_role_expiration
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_group_role_expiration
+    for each statement execute procedure increment_iam_grants_version();
+
+  create trigger increment_iam_grants_version
+  after insert or update or delete on iam_group_member_user
+    for each statement execute procedure increment_iam_grants_version();
+
+commit;
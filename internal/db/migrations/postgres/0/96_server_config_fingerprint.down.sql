@@ -0,0 +1,5 @@
+begin;
+
+  drop table server_config_fingerprint;
+
+commit;
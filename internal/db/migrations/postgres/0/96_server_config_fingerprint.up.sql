@@ -0,0 +1,38 @@
+begin;
+
+  -- server_config_fingerprint records the fingerprint of the security
+  -- relevant configuration of each controller from its last status update,
+  -- so controllers can detect peers whose configuration diverges from theirs,
+  -- e.g. during a partially rolled out configuration change. Sections holds
+  -- the fingerprints of the sections of the configuration as a JSON object,
+  -- to tell which of them diverge.
+  create table server_config_fingerprint (
+    controller_id text not null,
+    controller_type text not null default 'controller'
+      constraint controller_type_must_be_controller
+      check(controller_type = 'controller'),
+    fingerprint text not null
+      constraint fingerprint_must_not_be_empty
+      check(length(trim(fingerprint)) > 0),
+    sections text not null,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    primary key (controller_id),
+    foreign key (controller_id, controller_type)
+      references server(private_id, type)
+      on delete cascade
+      on update cascade
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on server_config_fingerprint
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on server_config_fingerprint
+    for each row execute procedure immutable_columns('controller_id', 'controller_type', 'create_time');
+
+commit;
@@ -0,0 +1,8 @@
+begin;
+
+  create index if not exists idx_oplog_metatadata_key on oplog_metadata(key);
+
+  drop index oplog_metadata_entry_id_ix;
+  drop index oplog_metadata_key_value_entry_id_ix;
+
+commit;
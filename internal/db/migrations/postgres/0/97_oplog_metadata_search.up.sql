@@ -0,0 +1,15 @@
+begin;
+
+  -- Searching the oplog by metadata finds the entries with a key and one of
+  -- its values, which oplog_metadata_key_value_entry_id_ix answers from the
+  -- index alone, then reads the metadata of the entries found by entry id.
+  create index oplog_metadata_key_value_entry_id_ix
+    on oplog_metadata (key, value, entry_id);
+
+  create index oplog_metadata_entry_id_ix
+    on oplog_metadata (entry_id);
+
+  -- Its key is the prefix of oplog_metadata_key_value_entry_id_ix
+  drop index idx_oplog_metatadata_key;
+
+commit;
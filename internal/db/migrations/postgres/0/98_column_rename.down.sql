@@ -0,0 +1,7 @@
+begin;
+
+  drop function contract_column_rename;
+  drop function expand_column_rename;
+  drop function column_rename_sync;
+
+commit;
@@ -0,0 +1,107 @@
+begin;
+
+  -- A column is renamed without a maintenance window by expanding the schema,
+  -- backfilling and contracting it in three releases:
+  --
+  --   1. expand: a migration adds the new column, nullable and without a
+  --      default, and calls expand_column_rename. Until the column is
+  --      contracted each write of either column is copied to the other, so
+  --      controllers of the previous release, which only know the old column,
+  --      and of this release, which use the new one, see the same data. The
+  --      rename is listed in db.ColumnRenames, so the controllers backfill the
+  --      new column of the rows written before the expansion.
+  --   2. contract: once db.ColumnRenameStatus reports no rows left to backfill
+  --      and no controller of the previous release is left, a migration of a
+  --      later release calls contract_column_rename, then adds the constraints
+  --      of the new column.
+  --
+  -- For example, renaming the description of db_test_user to summary:
+  --
+  --   alter table db_test_user add column summary text;
+  --   select expand_column_rename('db_test_user', 'description', 'summary');
+  --
+  -- and in a later release:
+  --
+  --   select contract_column_rename('db_test_user', 'description', 'summary');
+
+  -- column_rename_sync() copies the writes of one column to the other. It
+  -- should only be used in a before insert or update trigger created by
+  -- expand_column_rename, with the old and new column names as parameters. A
+  -- write of the new column wins over a write of the old one.
+  create function column_rename_sync()
+    returns trigger
+  as $$
+  declare
+    old_column text := tg_argv[0];
+    new_column text := tg_argv[1];
+    rec jsonb := to_jsonb(new);
+    prev jsonb;
+  begin
+    if tg_op = 'INSERT' then
+      if rec->new_column = 'null'::jsonb then
+        return jsonb_populate_record(new, jsonb_build_object(new_column, rec->old_column));
+      end if;
+      return jsonb_populate_record(new, jsonb_build_object(old_column, rec->new_column));
+    end if;
+    prev := to_jsonb(old);
+    if rec->new_column is distinct from prev->new_column then
+      return jsonb_populate_record(new, jsonb_build_object(old_column, rec->new_column));
+    end if;
+    if rec->old_column is distinct from prev->old_column then
+      return jsonb_populate_record(new, jsonb_build_object(new_column, rec->old_column));
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  comment on function
+    column_rename_sync()
+  is
+    'function used in before insert or update triggers to keep the old and new columns of a rename in sync';
+
+  -- expand_column_rename() starts copying the writes of the old column of the
+  -- table to the new column and back. The new column must have been added with
+  -- the type of the old column.
+  create function expand_column_rename(table_name regclass, old_column name, new_column name)
+    returns void
+  as $$
+  begin
+    execute format(
+      'create trigger %I before insert or update on %s for each row execute function column_rename_sync(%L, %L)',
+      old_column || '_' || new_column || '_rename_sync', table_name, old_column, new_column);
+  end;
+  $$ language plpgsql;
+
+  comment on function
+    expand_column_rename(regclass, name, name)
+  is
+    'function used in migrations to start the rename of a column';
+
+  -- contract_column_rename() stops copying the writes of the old column of the
+  -- table and drops it. It raises error code 23502 if rows of the table are
+  -- not backfilled.
+  create function contract_column_rename(table_name regclass, old_column name, new_column name)
+    returns void
+  as $$
+  declare
+    pending boolean;
+  begin
+    execute format('select exists (select from %s where %I is not null and %I is null)',
+      table_name, old_column, new_column) into pending;
+    if pending then
+      raise exception 'column rename not backfilled: %.% to %', table_name, old_column, new_column using
+        errcode = '23502',
+        table = table_name::text,
+        column = new_column;
+    end if;
+    execute format('drop trigger %I on %s', old_column || '_' || new_column || '_rename_sync', table_name);
+    execute format('alter table %s drop column %I', table_name, old_column);
+  end;
+  $$ language plpgsql;
+
+  comment on function
+    contract_column_rename(regclass, name, name)
+  is
+    'function used in migrations to complete the rename of a column';
+
+commit;
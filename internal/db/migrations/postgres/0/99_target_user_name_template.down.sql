@@ -0,0 +1,5 @@
+begin;
+
+  drop table target_user_name_template;
+
+commit;
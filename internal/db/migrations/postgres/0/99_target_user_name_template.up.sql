@@ -0,0 +1,35 @@
+begin;
+
+  -- target_user_name_template records the template of the user name which
+  -- protocol handlers inject into the connections of the sessions of a target,
+  -- so a shared target can map each user to their own account on its
+  -- endpoints. A target without a row in this table injects the name of the
+  -- user.
+  create table target_user_name_template (
+    target_id wt_public_id primary key
+      references target(public_id)
+      on delete cascade
+      on update cascade,
+    template text not null
+      check(length(trim(template)) > 0),
+    create_time wt_timestamp,
+    update_time wt_timestamp
+  );
+
+  create trigger
+    default_create_time_column
+  before insert on target_user_name_template
+    for each row execute procedure default_create_time();
+
+  create trigger
+    update_time_column
+  before update on target_user_name_template
+    for each row execute procedure update_time_column();
+
+  create trigger
+    immutable_columns
+  before
+  update on target_user_name_template
+    for each row execute procedure immutable_columns('target_id', 'create_time');
+
+commit;
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/oplog/store"
+)
+
+// OplogActorMetadataKey is the oplog entry metadata key of the id of the
+// actor, e.g. the user, who made the change recorded by the entry.
+const OplogActorMetadataKey = oplog.ActorIdMetadataKey
+
+type oplogActorKey struct{}
+
+// OplogActorFunc returns the id of the actor making changes, or an empty
+// string if it isn't known.
+type OplogActorFunc func() string
+
+// NewOplogActorContext returns a context with which oplog entries record the
+// actor returned by fn in their OplogActorMetadataKey metadata. fn is called
+// when each entry is written, so the actor may be determined after the context
+// is created.
+func NewOplogActorContext(ctx context.Context, fn OplogActorFunc) context.Context {
+	return context.WithValue(ctx, oplogActorKey{}, fn)
+}
+
+// addOplogActor adds the actor of the context, if any, to the metadata of the
+// entry.
+func addOplogActor(ctx context.Context, entry *oplog.Entry) {
+	fn, ok := ctx.Value(oplogActorKey{}).(OplogActorFunc)
+	if !ok || fn == nil {
+		return
+	}
+	if actor := fn(); actor != "" {
+		entry.Metadata = append(entry.Metadata, &store.Metadata{Key: OplogActorMetadataKey, Value: actor})
+	}
+}
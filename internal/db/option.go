@@ -19,9 +19,10 @@ type Option func(*Options)
 
 // Options - how Options are represented.
 type Options struct {
-	withOplog  bool
-	oplogOpts  oplogOpts
-	withLookup bool
+	withOplog     bool
+	oplogOpts     oplogOpts
+	withLookup    bool
+	withReturning bool
 	// WithLimit must be accessible in other packages.
 	WithLimit int
 	// WithFieldMaskPaths must be accessible from other packages.
@@ -40,6 +41,27 @@ type Options struct {
 	withWhereClause     string
 	withWhereClauseArgs []interface{}
 	withOrder           string
+
+	withPreloads []preload
+
+	withAsyncOplog bool
+
+	withStatementCache *StatementCache
+
+	withFieldWrapper FieldWrapperFunc
+
+	withFieldOrder FieldOrder
+
+	withPaging         bool
+	withStartPageAfter string
+	withNextPageCursor *string
+}
+
+// preload is an association which is loaded after a search.
+type preload struct {
+	resources  interface{}
+	foreignKey string
+	keyField   string
 }
 
 type oplogOpts struct {
@@ -55,6 +77,7 @@ func getDefaultOptions() Options {
 			metadata: oplog.Metadata{},
 		},
 		withLookup:         false,
+		withReturning:      false,
 		WithFieldMaskPaths: []string{},
 		WithNullPaths:      []string{},
 		WithLimit:          0,
@@ -69,6 +92,16 @@ func WithLookup(enable bool) Option {
 	}
 }
 
+// WithReturning enables populating the fields generated by the db (e.g.
+// create_time, update_time and version) from a RETURNING clause of the insert
+// or update statement, which saves a round trip to the db per write. It is
+// disabled by default.
+func WithReturning(enable bool) Option {
+	return func(o *Options) {
+		o.withReturning = enable
+	}
+}
+
 // WithOplog provides an option to write an oplog entry. WithOplog and
 // NewOplogMsg cannot be used together.
 func WithOplog(wrapper wrapping.Wrapper, md oplog.Metadata) Option {
@@ -155,3 +188,63 @@ func WithOrder(withOrder string) Option {
 		o.withOrder = withOrder
 	}
 }
+
+// WithPreload provides an option to load the rows associated with the results
+// of a search using one additional query, instead of one query per result. The
+// rows of the resources table whose foreignKey column matches the keyField
+// struct field of any search result are loaded into resources, which must be a
+// pointer to a slice. WithPreload may be provided more than once to load
+// several associations.
+func WithPreload(resources interface{}, foreignKey, keyField string) Option {
+	return func(o *Options) {
+		o.withPreloads = append(o.withPreloads, preload{
+			resources:  resources,
+			foreignKey: foreignKey,
+			keyField:   keyField,
+		})
+	}
+}
+
+// WithAsyncOplog provides an option to New to stage encrypted oplog entries in
+// the same transaction as the change they describe, to be written to the oplog
+// in commit order by a background worker (see oplog.FlushStagedEntries),
+// rather than writing them directly. Entries whose metadata does not include a single
+// scope-id are always written directly.
+func WithAsyncOplog(enable bool) Option {
+	return func(o *Options) {
+		o.withAsyncOplog = enable
+	}
+}
+
+// WithStatementCache provides an option to run the raw queries of Exec and
+// Query with the prepared statements of the cache.
+func WithStatementCache(c *StatementCache) Option {
+	return func(o *Options) {
+		o.withStatementCache = c
+	}
+}
+
+// WithFieldWrapper provides an option to encrypt and decrypt the "wrapping"
+// tagged fields of a FieldEncrypter, using the wrapper fn returns for the
+// resource's scope.
+func WithFieldWrapper(fn FieldWrapperFunc) Option {
+	return func(o *Options) {
+		o.withFieldWrapper = fn
+	}
+}
+
+// WithStartPageAfter provides an option to page through the results of
+// SearchWhere in keyset order, by create_time then public_id, without
+// scanning the results of the previous pages. A page starts after the result
+// of the cursor, or at the first result if the cursor is empty, and has up to
+// WithLimit results. The opaque cursor of the next page is set in nextCursor,
+// if not nil, or "" when there are no more results. The resources must
+// implement ResourcePageKeyer, and the option cannot be combined with
+// WithOrder.
+func WithStartPageAfter(cursor string, nextCursor *string) Option {
+	return func(o *Options) {
+		o.withPaging = true
+		o.withStartPageAfter = cursor
+		o.withNextPageCursor = nextCursor
+	}
+}
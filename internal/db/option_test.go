@@ -1,9 +1,11 @@
 package db
 
 import (
+	"context"
 	"testing"
 
 	"github.com/hashicorp/boundary/internal/oplog"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -48,6 +50,33 @@ func Test_getOpts(t *testing.T) {
 		testOpts.withLookup = true
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithReturning", func(t *testing.T) {
+		assert := assert.New(t)
+		// test default of false
+		opts := GetOpts()
+		testOpts := getDefaultOptions()
+		testOpts.withReturning = false
+		assert.Equal(opts, testOpts)
+
+		// try setting to true
+		opts = GetOpts(WithReturning(true))
+		testOpts = getDefaultOptions()
+		testOpts.withReturning = true
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithFieldOrder", func(t *testing.T) {
+		assert := assert.New(t)
+		// test default of SortedFieldOrder
+		opts := GetOpts()
+		testOpts := getDefaultOptions()
+		testOpts.withFieldOrder = SortedFieldOrder
+		assert.Equal(opts, testOpts)
+
+		opts = GetOpts(WithFieldOrder(DeclaredFieldOrder))
+		testOpts = getDefaultOptions()
+		testOpts.withFieldOrder = DeclaredFieldOrder
+		assert.Equal(opts, testOpts)
+	})
 	t.Run("WithFieldMaskPaths", func(t *testing.T) {
 		assert := assert.New(t)
 		// test default of []string{}
@@ -175,4 +204,64 @@ func Test_getOpts(t *testing.T) {
 		testOpts.withOrder = "version desc"
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithPreload", func(t *testing.T) {
+		assert := assert.New(t)
+		// test default of none
+		opts := GetOpts()
+		testOpts := getDefaultOptions()
+		assert.Equal(opts, testOpts)
+
+		var first, second []string
+		opts = GetOpts(WithPreload(&first, "user_id", "Id"), WithPreload(&second, "car_id", "Id"))
+		testOpts.withPreloads = []preload{
+			{resources: &first, foreignKey: "user_id", keyField: "Id"},
+			{resources: &second, foreignKey: "car_id", keyField: "Id"},
+		}
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithAsyncOplog", func(t *testing.T) {
+		assert := assert.New(t)
+		// test default of false
+		opts := GetOpts()
+		testOpts := getDefaultOptions()
+		testOpts.withAsyncOplog = false
+		assert.Equal(opts, testOpts)
+
+		opts = GetOpts(WithAsyncOplog(true))
+		testOpts.withAsyncOplog = true
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithStatementCache", func(t *testing.T) {
+		assert := assert.New(t)
+		// test default of nil
+		opts := GetOpts()
+		assert.Nil(opts.withStatementCache)
+
+		c := &StatementCache{}
+		opts = GetOpts(WithStatementCache(c))
+		assert.Equal(c, opts.withStatementCache)
+	})
+	t.Run("WithFieldWrapper", func(t *testing.T) {
+		assert := assert.New(t)
+		// test default of nil
+		opts := GetOpts()
+		assert.Nil(opts.withFieldWrapper)
+
+		opts = GetOpts(WithFieldWrapper(func(context.Context, string, string) (wrapping.Wrapper, error) {
+			return nil, nil
+		}))
+		assert.NotNil(opts.withFieldWrapper)
+	})
+	t.Run("WithStartPageAfter", func(t *testing.T) {
+		assert := assert.New(t)
+		// test default of no paging
+		opts := GetOpts()
+		assert.False(opts.withPaging)
+
+		var next string
+		opts = GetOpts(WithStartPageAfter("cursor", &next))
+		assert.True(opts.withPaging)
+		assert.Equal("cursor", opts.withStartPageAfter)
+		assert.Equal(&next, opts.withNextPageCursor)
+	})
 }
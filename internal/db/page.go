@@ -0,0 +1,80 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// pageOrder is the keyset order of the results of a search paged with
+// WithStartPageAfter.
+const pageOrder = "create_time asc, public_id asc"
+
+// ResourcePageKeyer defines an interface that SearchWhere uses to get the
+// keyset of the last result of a page, when paging with WithStartPageAfter.
+type ResourcePageKeyer interface {
+	GetPublicId() string
+	GetCreateTime() *timestamp.Timestamp
+}
+
+// pageCursor is the keyset of the last result of a page. It is encoded as an
+// opaque string for callers, which they must not parse.
+type pageCursor struct {
+	CreateTime time.Time `json:"t"`
+	PublicId   string    `json:"id"`
+}
+
+func (c pageCursor) encode() (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodePageCursor(s string) (pageCursor, error) {
+	var c pageCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("invalid page cursor: %w", errors.ErrInvalidParameter)
+	}
+	if err := json.Unmarshal(b, &c); err != nil || c.PublicId == "" || c.CreateTime.IsZero() {
+		return c, fmt.Errorf("invalid page cursor: %w", errors.ErrInvalidParameter)
+	}
+	return c, nil
+}
+
+// endPage trims the results of a search for a page of limit results, for
+// which up to limit+1 were searched, and returns the cursor of the next page,
+// or "" if there are no more results.
+func endPage(resources interface{}, limit int) (string, error) {
+	v := reflect.Indirect(reflect.ValueOf(resources))
+	if v.Kind() != reflect.Slice {
+		return "", stderrors.New("error paging requires search resources to be a slice")
+	}
+	if limit <= 0 || v.Len() <= limit {
+		return "", nil
+	}
+	v.SetLen(limit)
+	elem := v.Index(limit - 1)
+	if elem.Kind() != reflect.Ptr {
+		// The elements of a slice are addressable, so structs whose methods
+		// have pointer receivers are keyed too
+		elem = elem.Addr()
+	}
+	last, ok := elem.Interface().(ResourcePageKeyer)
+	if !ok {
+		return "", stderrors.New("error paging requires search resources to implement ResourcePageKeyer")
+	}
+	c := pageCursor{
+		CreateTime: last.GetCreateTime().GetTimestamp().AsTime(),
+		PublicId:   last.GetPublicId(),
+	}
+	return c.encode()
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/boundary/internal/db/db_test"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageCursor(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	c := pageCursor{CreateTime: time.Date(2021, 1, 2, 3, 4, 5, 6000, time.UTC), PublicId: "u_1234567890"}
+	s, err := c.encode()
+	require.NoError(err)
+	got, err := decodePageCursor(s)
+	require.NoError(err)
+	assert.True(c.CreateTime.Equal(got.CreateTime))
+	assert.Equal(c.PublicId, got.PublicId)
+
+	for _, bad := range []string{"not base64!", "bm90IGpzb24", "e30"} {
+		_, err := decodePageCursor(bad)
+		assert.Truef(errors.Is(err, errors.ErrInvalidParameter), "cursor %q", bad)
+	}
+}
+
+func TestEndPage(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	newUser := func(t *testing.T, i int) *db_test.TestUser {
+		ts, err := ptypes.TimestampProto(start.Add(time.Duration(i) * time.Second))
+		require.NoError(t, err)
+		return &db_test.TestUser{StoreTestUser: &db_test.StoreTestUser{
+			PublicId:   "u_" + string(rune('a'+i)),
+			CreateTime: &timestamp.Timestamp{Timestamp: ts},
+		}}
+	}
+
+	t.Run("more-results", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		users := []*db_test.TestUser{newUser(t, 0), newUser(t, 1), newUser(t, 2)}
+		next, err := endPage(&users, 2)
+		require.NoError(err)
+		assert.Len(users, 2)
+		c, err := decodePageCursor(next)
+		require.NoError(err)
+		assert.Equal("u_b", c.PublicId)
+		assert.True(start.Add(time.Second).Equal(c.CreateTime))
+	})
+	t.Run("values", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		users := []db_test.TestUser{*newUser(t, 0), *newUser(t, 1)}
+		next, err := endPage(&users, 1)
+		require.NoError(err)
+		assert.Len(users, 1)
+		c, err := decodePageCursor(next)
+		require.NoError(err)
+		assert.Equal("u_a", c.PublicId)
+	})
+	t.Run("last-page", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		users := []*db_test.TestUser{newUser(t, 0), newUser(t, 1)}
+		next, err := endPage(&users, 2)
+		require.NoError(err)
+		assert.Len(users, 2)
+		assert.Empty(next)
+	})
+	t.Run("unlimited", func(t *testing.T) {
+		users := []*db_test.TestUser{newUser(t, 0), newUser(t, 1)}
+		next, err := endPage(&users, -1)
+		require.NoError(t, err)
+		assert.Empty(t, next)
+	})
+	t.Run("not-keyed", func(t *testing.T) {
+		values := []string{"a", "b"}
+		_, err := endPage(&values, 1)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// PiiCategory is a category of personally identifiable information with its
+// own retention.
+type PiiCategory string
+
+const (
+	// PiiClientAddress is the network address of clients
+	PiiClientAddress PiiCategory = "client_address"
+	// PiiUserAgent is the user agent of clients
+	PiiUserAgent PiiCategory = "user_agent"
+	// PiiIdpClaims is the claims about users received from identity
+	// providers
+	PiiIdpClaims PiiCategory = "idp_claims"
+)
+
+// ValidPiiCategory returns true if c is a known category of personally
+// identifiable information.
+func ValidPiiCategory(c PiiCategory) bool {
+	switch c {
+	case PiiClientAddress, PiiUserAgent, PiiIdpClaims:
+		return true
+	}
+	return false
+}
+
+// PiiField is a set of columns of a table holding personally identifiable
+// information. The columns are nulled once the row is older than the
+// retention of their category, while the rest of the row is kept for audit
+// continuity. The columns must be nullable.
+type PiiField struct {
+	Table   string
+	Columns []string
+	// TimeColumn is the column of the time the information was recorded,
+	// which the retention is counted from
+	TimeColumn string
+	Category   PiiCategory
+}
+
+// PiiFields are the columns holding personally identifiable information. The
+// connections of sessions are also copied to the warehouse, which keeps them
+// after the sessions are deleted. No user agents or IdP claims are stored
+// yet; their columns must be added here when they are.
+var PiiFields = []PiiField{
+	{
+		Table:      "session_connection",
+		Columns:    []string{"client_tcp_address", "client_tcp_port"},
+		TimeColumn: "create_time",
+		Category:   PiiClientAddress,
+	},
+	{
+		Table:      "wh_session_connection_accumulating_fact",
+		Columns:    []string{"client_tcp_address", "client_tcp_port_number"},
+		TimeColumn: "connection_authorized_time",
+		Category:   PiiClientAddress,
+	},
+}
+
+// RedactExpiredPii nulls the columns of the fields which are older than the
+// retention of their category, returning the number of rows changed. Fields
+// of categories without a positive retention are kept.
+func RedactExpiredPii(ctx context.Context, w Writer, fields []PiiField, retention map[PiiCategory]time.Duration) (int, error) {
+	const op = "redact expired pii"
+	if w == nil {
+		return NoRowsAffected, fmt.Errorf("%s: missing writer: %w", op, errors.ErrInvalidParameter)
+	}
+	now := time.Now()
+	var redacted int
+	for _, f := range fields {
+		r := retention[f.Category]
+		if r <= 0 {
+			continue
+		}
+		if f.Table == "" || f.TimeColumn == "" || len(f.Columns) == 0 {
+			return redacted, fmt.Errorf("%s: incomplete field of table %q: %w", op, f.Table, errors.ErrInvalidParameter)
+		}
+		n, err := w.Exec(ctx, f.redactQuery(), []interface{}{now.Add(-r)})
+		if err != nil {
+			return redacted, fmt.Errorf("%s: %s: %w", op, f.Table, err)
+		}
+		redacted += n
+	}
+	return redacted, nil
+}
+
+// redactQuery returns the statement nulling the columns of the rows recorded
+// before its parameter, skipping those already nulled.
+func (f PiiField) redactQuery() string {
+	set := make([]string, 0, len(f.Columns))
+	notNull := make([]string, 0, len(f.Columns))
+	for _, c := range f.Columns {
+		set = append(set, c+" = null")
+		notNull = append(notNull, c+" is not null")
+	}
+	return fmt.Sprintf("update %s set %s where %s < ? and (%s)",
+		f.Table, strings.Join(set, ", "), f.TimeColumn, strings.Join(notNull, " or "))
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPiiFields_Schema(t *testing.T) {
+	t.Parallel()
+	conn, _ := TestSetup(t, "postgres")
+	rw := New(conn)
+	ctx := context.Background()
+
+	for _, f := range PiiFields {
+		assert.True(t, ValidPiiCategory(f.Category), "table %s", f.Table)
+		for _, c := range append([]string{f.TimeColumn}, f.Columns...) {
+			rows, err := rw.Query(ctx,
+				"select is_nullable from information_schema.columns where table_name = ? and column_name = ?",
+				[]interface{}{f.Table, c})
+			require.NoError(t, err)
+			require.True(t, rows.Next(), "missing column %s.%s", f.Table, c)
+			var nullable string
+			require.NoError(t, rows.Scan(&nullable))
+			rows.Close()
+			if c != f.TimeColumn {
+				assert.Equal(t, "YES", nullable, "column %s.%s", f.Table, c)
+			}
+		}
+	}
+}
+
+func TestRedactExpiredPii(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := TestSetup(t, "postgres")
+	rw := New(conn)
+	ctx := context.Background()
+
+	_, err := rw.Exec(ctx, `
+create table db_test_pii (
+  id integer primary key,
+  client_address inet,
+  client_port integer,
+  create_time timestamp with time zone not null
+)`, nil)
+	require.NoError(err)
+	now := time.Now()
+	_, err = rw.Exec(ctx, `
+insert into db_test_pii (id, client_address, client_port, create_time)
+values
+  (1, '10.0.0.1', 1234, ?),
+  (2, '10.0.0.2', 1234, ?),
+  (3, null,       null, ?)`,
+		[]interface{}{now.Add(-48 * time.Hour), now, now.Add(-48 * time.Hour)})
+	require.NoError(err)
+
+	fields := []PiiField{{
+		Table:      "db_test_pii",
+		Columns:    []string{"client_address", "client_port"},
+		TimeColumn: "create_time",
+		Category:   PiiClientAddress,
+	}}
+
+	n, err := RedactExpiredPii(ctx, rw, fields, map[PiiCategory]time.Duration{PiiUserAgent: time.Hour})
+	require.NoError(err)
+	assert.Equal(0, n)
+
+	retention := map[PiiCategory]time.Duration{PiiClientAddress: 24 * time.Hour}
+	n, err = RedactExpiredPii(ctx, rw, fields, retention)
+	require.NoError(err)
+	assert.Equal(1, n)
+	n, err = RedactExpiredPii(ctx, rw, fields, retention)
+	require.NoError(err)
+	assert.Equal(0, n)
+
+	rows, err := rw.Query(ctx, "select id from db_test_pii where client_address is not null or client_port is not null", nil)
+	require.NoError(err)
+	defer rows.Close()
+	var kept []int
+	for rows.Next() {
+		var id int
+		require.NoError(rows.Scan(&id))
+		kept = append(kept, id)
+	}
+	require.NoError(rows.Err())
+	assert.Equal([]int{2}, kept)
+
+	_, err = RedactExpiredPii(ctx, nil, fields, retention)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	_, err = RedactExpiredPii(ctx, rw, []PiiField{{Table: "db_test_pii", Category: PiiClientAddress}}, retention)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+}
+
+func TestValidPiiCategory(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	assert.True(ValidPiiCategory(PiiClientAddress))
+	assert.True(ValidPiiCategory(PiiUserAgent))
+	assert.True(ValidPiiCategory(PiiIdpClaims))
+	assert.False(ValidPiiCategory("email"))
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/boundary/internal/db/common"
 	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/libs/redact"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/oplog/store"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
@@ -35,13 +36,19 @@ type Reader interface {
 	// LookupByPublicId will lookup resource by its public_id which must be unique.
 	LookupByPublicId(ctx context.Context, resource ResourcePublicIder, opt ...Option) error
 
+	// LookupByPublicIds will lookup the resources with the public ids using a
+	// single query and return them in the order of the ids. Ids which aren't
+	// found are skipped.
+	LookupByPublicIds(ctx context.Context, resources interface{}, publicIds []string, opt ...Option) error
+
 	// LookupWhere will lookup and return the first resource using a where clause with parameters
 	LookupWhere(ctx context.Context, resource interface{}, where string, args ...interface{}) error
 
 	// SearchWhere will search for all the resources it can find using a where
 	// clause with parameters. Supports the WithLimit option.  If
 	// WithLimit < 0, then unlimited results are returned.  If WithLimit == 0, then
-	// default limits are used for results.
+	// default limits are used for results. Supports the WithStartPageAfter
+	// option to page through the results.
 	SearchWhere(ctx context.Context, resources interface{}, where string, args []interface{}, opt ...Option) error
 
 	// Query will run the raw query and return the *sql.Rows results. Query will
@@ -101,6 +108,12 @@ type Writer interface {
 	// should be to rollback. Delete returns the number of rows deleted or an error.
 	DeleteItems(ctx context.Context, deleteItems []interface{}, opt ...Option) (int, error)
 
+	// CopyFrom creates the resources in bulk using the postgres COPY
+	// protocol and returns the number of rows copied. It must be called
+	// within a transaction. Supported options: WithOplog, which writes a
+	// single entry with a message for each resource.
+	CopyFrom(ctx context.Context, resources []Copyable, opt ...Option) (int, error)
+
 	// Exec will execute the sql with the values as parameters. The int returned
 	// is the number of rows affected by the sql. No options are currently
 	// supported.
@@ -120,6 +133,10 @@ type Writer interface {
 		msgs []*oplog.Message,
 		opt ...Option,
 	) error
+
+	// NotifyOnCommit records changes to notify the listeners registered with
+	// RegisterCommitListener of once they are committed.
+	NotifyOnCommit(ctx context.Context, changes ...Change)
 }
 
 const (
@@ -167,14 +184,29 @@ type VetForWriter interface {
 // Db uses a gorm DB connection for read/write
 type Db struct {
 	underlying *gorm.DB
+
+	// asyncOplog stages oplog entries to be written by a background worker
+	// rather than writing them directly.
+	asyncOplog bool
+
+	// pendingChanges collects the changes to notify the commit listeners of
+	// when the transaction of a DoTx commits; it is nil outside of one.
+	pendingChanges *[]Change
+
+	// stmtCache caches the prepared statements of Exec and Query; it is nil
+	// if they aren't prepared.
+	stmtCache *StatementCache
 }
 
 // ensure that Db implements the interfaces of: Reader and Writer
 var _ Reader = (*Db)(nil)
 var _ Writer = (*Db)(nil)
 
-func New(underlying *gorm.DB) *Db {
-	return &Db{underlying: underlying}
+// New creates a Db using the underlying connection. Supported options:
+// WithAsyncOplog and WithStatementCache.
+func New(underlying *gorm.DB, opt ...Option) *Db {
+	opts := GetOpts(opt...)
+	return &Db{underlying: underlying, asyncOplog: opts.withAsyncOplog, stmtCache: opts.withStatementCache}
 }
 
 // Exec will execute the sql with the values as parameters. The int returned
@@ -184,6 +216,22 @@ func (rw *Db) Exec(ctx context.Context, sql string, values []interface{}, opt ..
 	if sql == "" {
 		return NoRowsAffected, fmt.Errorf("missing sql: %w", errors.ErrInvalidParameter)
 	}
+	stmt, release, err := rw.cachedStmt(ctx, sql)
+	if err != nil {
+		return NoRowsAffected, fmt.Errorf("exec: failed: %w", err)
+	}
+	if stmt != nil {
+		res, err := stmt.ExecContext(ctx, values...)
+		release()
+		if err != nil {
+			return NoRowsAffected, fmt.Errorf("exec: failed: %w", err)
+		}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return NoRowsAffected, fmt.Errorf("exec: failed: %w", err)
+		}
+		return int(rowsAffected), nil
+	}
 	gormDb := rw.underlying.Exec(sql, values...)
 	if gormDb.Error != nil {
 		return NoRowsAffected, fmt.Errorf("exec: failed: %w", gormDb.Error)
@@ -199,6 +247,15 @@ func (rw *Db) Query(ctx context.Context, sql string, values []interface{}, opt .
 	if sql == "" {
 		return nil, fmt.Errorf("raw missing sql: %w", errors.ErrInvalidParameter)
 	}
+	stmt, release, err := rw.cachedStmt(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("query: failed: %w", err)
+	}
+	if stmt != nil {
+		// The rows keep the statement open until they are closed
+		defer release()
+		return stmt.QueryContext(ctx, values...)
+	}
 	gormDb := rw.underlying.Raw(sql, values...)
 	if gormDb.Error != nil {
 		return nil, fmt.Errorf("exec: failed: %w", gormDb.Error)
@@ -230,10 +287,13 @@ func (rw *Db) lookupAfterWrite(ctx context.Context, i interface{}, opt ...Option
 	return nil
 }
 
-// Create an object in the db with options: WithOplog, NewOplogMsg and
-// WithLookup.  WithOplog will write an oplog entry for the create.
-// NewOplogMsg will return in-memory oplog message.  WithOplog and NewOplogMsg
-// cannot be used together.  WithLookup with to force a lookup after create.
+// Create an object in the db with options: WithOplog, NewOplogMsg,
+// WithLookup, WithReturning and WithFieldWrapper.  WithOplog will write an
+// oplog entry for the create. NewOplogMsg will return in-memory oplog message.
+// WithOplog and NewOplogMsg cannot be used together.  WithLookup with to force
+// a lookup after create. WithReturning populates the fields generated by the
+// db in the insert statement itself.  WithFieldWrapper will
+// encrypt the fields of a FieldEncrypter.
 func (rw *Db) Create(ctx context.Context, i interface{}, opt ...Option) error {
 	if rw.underlying == nil {
 		return fmt.Errorf("create: missing underlying db: %w", errors.ErrInvalidParameter)
@@ -257,6 +317,10 @@ func (rw *Db) Create(ctx context.Context, i interface{}, opt ...Option) error {
 	// db to manage them
 	setFieldsToNil(i, []string{"CreateTime", "UpdateTime"})
 
+	if err := encryptFields(ctx, i, opts); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
 	if !opts.withSkipVetForWrite {
 		if vetter, ok := i.(VetForWriter); ok {
 			if err := vetter.VetForWrite(ctx, rw, CreateOp); err != nil {
@@ -272,8 +336,12 @@ func (rw *Db) Create(ctx context.Context, i interface{}, opt ...Option) error {
 			return fmt.Errorf("create: unable to get ticket: %w", err)
 		}
 	}
-	if err := rw.underlying.Create(i).Error; err != nil {
-		return fmt.Errorf("create: failed: %w", err)
+	underlying := rw.underlying
+	if opts.withReturning {
+		underlying = underlying.Set(returningSetting, ctx)
+	}
+	if err := underlying.Create(i).Error; err != nil {
+		return fmt.Errorf("create: failed: %w", redact.Error(err, i))
 	}
 	if withOplog {
 		if err := rw.addOplog(ctx, CreateOp, opts, ticket, i); err != nil {
@@ -367,10 +435,12 @@ func (rw *Db) CreateItems(ctx context.Context, createItems []interface{}, opt ..
 // which almost always should be to rollback.  Update returns the number of
 // rows updated.
 //
-// Supported options: WithOplog, NewOplogMsg and WithVersion.
+// Supported options: WithOplog, NewOplogMsg, WithReturning and WithVersion.
 // WithOplog will write an oplog entry for the update. NewOplogMsg
 // will return in-memory oplog message.  WithOplog and NewOplogMsg cannot be
-// used together.   If WithVersion is used, then the update will include the
+// used together.  WithReturning populates the updated resource in the update
+// statement itself, otherwise it is looked up after the update.
+// If WithVersion is used, then the update will include the
 // version number in the update where clause, which basically makes the update
 // use optimistic locking and the update will only succeed if the existing rows
 // version matches the WithVersion option.  Zero is not a valid value for the
@@ -391,6 +461,15 @@ func (rw *Db) Update(ctx context.Context, i interface{}, fieldMaskPaths []string
 		return NoRowsAffected, fmt.Errorf("update: both WithOplog and NewOplogMsg options have been specified: %w", errors.ErrInvalidParameter)
 	}
 
+	var withEncryptedFields bool
+	if opts.withFieldWrapper != nil {
+		var err error
+		fieldMaskPaths, setToNullPaths, withEncryptedFields, err = encryptedFieldPaths(i, fieldMaskPaths, setToNullPaths)
+		if err != nil {
+			return NoRowsAffected, fmt.Errorf("update: %w", err)
+		}
+	}
+
 	// we need to filter out some non-updatable fields (like: CreateTime, etc)
 	fieldMaskPaths = filterPaths(fieldMaskPaths)
 	setToNullPaths = filterPaths(setToNullPaths)
@@ -398,6 +477,12 @@ func (rw *Db) Update(ctx context.Context, i interface{}, fieldMaskPaths []string
 		return NoRowsAffected, fmt.Errorf("update: after filtering non-updated fields, there are no fields left in fieldMaskPaths or setToNullPaths")
 	}
 
+	if withEncryptedFields {
+		if err := encryptFields(ctx, i, opts); err != nil {
+			return NoRowsAffected, fmt.Errorf("update: %w", err)
+		}
+	}
+
 	updateFields, err := common.UpdateFields(i, fieldMaskPaths, setToNullPaths)
 	if err != nil {
 		return NoRowsAffected, fmt.Errorf("update: getting update fields failed: %w", err)
@@ -441,7 +526,10 @@ func (rw *Db) Update(ctx context.Context, i interface{}, fieldMaskPaths []string
 			return NoRowsAffected, fmt.Errorf("update: unable to get ticket: %w", err)
 		}
 	}
-	var underlying *gorm.DB
+	underlying := rw.underlying
+	if opts.withReturning {
+		underlying = underlying.Set(returningSetting, ctx)
+	}
 	switch {
 	case opts.WithVersion != nil || opts.withWhereClause != "":
 		var where []string
@@ -458,15 +546,15 @@ func (rw *Db) Update(ctx context.Context, i interface{}, fieldMaskPaths []string
 		if opts.withWhereClause != "" {
 			where, args = append(where, opts.withWhereClause), append(args, opts.withWhereClauseArgs...)
 		}
-		underlying = rw.underlying.Model(i).Where(strings.Join(where, " and "), args...).Updates(updateFields)
+		underlying = underlying.Model(i).Where(strings.Join(where, " and "), args...).Updates(updateFields)
 	default:
-		underlying = rw.underlying.Model(i).Updates(updateFields)
+		underlying = underlying.Model(i).Updates(updateFields)
 	}
 	if underlying.Error != nil {
 		if err == gorm.ErrRecordNotFound {
 			return NoRowsAffected, fmt.Errorf("update: failed: %w", errors.ErrRecordNotFound)
 		}
-		return NoRowsAffected, fmt.Errorf("update: failed: %w", underlying.Error)
+		return NoRowsAffected, fmt.Errorf("update: failed: %w", redact.Error(underlying.Error, i))
 	}
 	rowsUpdated := int(underlying.RowsAffected)
 	if rowsUpdated > 0 && (withOplog || opts.newOplogMsg != nil) {
@@ -495,6 +583,11 @@ func (rw *Db) Update(ctx context.Context, i interface{}, fieldMaskPaths []string
 			*opts.newOplogMsg = *msg
 		}
 	}
+	// the resource was initialized from the db by the update statement
+	// itself if it returned the updated row
+	if opts.withReturning && rowsUpdated > 0 {
+		return rowsUpdated, nil
+	}
 	// we need to force a lookupAfterWrite so the resource returned is correctly initialized
 	// from the db
 	opt = append(opt, WithLookup(true))
@@ -549,7 +642,7 @@ func (rw *Db) Delete(ctx context.Context, i interface{}, opt ...Option) (int, er
 	}
 	db = db.Delete(i)
 	if db.Error != nil {
-		return NoRowsAffected, fmt.Errorf("delete: failed %w", db.Error)
+		return NoRowsAffected, fmt.Errorf("delete: failed %w", redact.Error(db.Error, i))
 	}
 	rowsDeleted := int(db.RowsAffected)
 	if rowsDeleted > 0 && (withOplog || opts.newOplogMsg != nil) {
@@ -749,12 +842,7 @@ func (rw *Db) addOplogForItems(ctx context.Context, opType OpType, opts Options,
 	if err != nil {
 		return fmt.Errorf("oplog for items: unable to create oplog entry %w", err)
 	}
-	if err := entry.WriteEntryWith(
-		ctx,
-		&oplog.GormWriter{Tx: rw.underlying},
-		ticket,
-		oplogMsgs...,
-	); err != nil {
+	if err := rw.writeOplogEntry(ctx, entry, oplogArgs.metadata, ticket, oplogMsgs...); err != nil {
 		return fmt.Errorf("oplog for items: unable to write oplog entry %w", err)
 	}
 	return nil
@@ -786,12 +874,7 @@ func (rw *Db) addOplog(ctx context.Context, opType OpType, opts Options, ticket
 	if err != nil {
 		return fmt.Errorf("add oplog: %w", err)
 	}
-	err = entry.WriteEntryWith(
-		ctx,
-		&oplog.GormWriter{Tx: rw.underlying},
-		ticket,
-		msg,
-	)
+	err = rw.writeOplogEntry(ctx, entry, oplogArgs.metadata, ticket, msg)
 	if err != nil {
 		return fmt.Errorf("add oplog: unable to write oplog entry: %w", err)
 	}
@@ -834,18 +917,28 @@ func (rw *Db) WriteOplogEntryWith(ctx context.Context, wrapper wrapping.Wrapper,
 	if err != nil {
 		return fmt.Errorf("write oplog: unable to create oplog entry: %w", err)
 	}
-	err = entry.WriteEntryWith(
-		ctx,
-		&oplog.GormWriter{Tx: rw.underlying},
-		ticket,
-		msgs...,
-	)
+	err = rw.writeOplogEntry(ctx, entry, metadata, ticket, msgs...)
 	if err != nil {
 		return fmt.Errorf("write oplog: unable to write oplog entry: %w", err)
 	}
 	return nil
 }
 
+// writeOplogEntry writes the entry to the oplog, recording the actor of ctx
+// if it has one (see NewOplogActorContext). When the Db is in
+// asynchronous oplog mode and the metadata identifies a single scope, the
+// entry is instead encrypted and staged to be written by a background worker.
+func (rw *Db) writeOplogEntry(ctx context.Context, entry *oplog.Entry, metadata oplog.Metadata, ticket *store.Ticket, msgs ...*oplog.Message) error {
+	w := &oplog.GormWriter{Tx: rw.underlying}
+	addOplogActor(ctx, entry)
+	if rw.asyncOplog {
+		if scopeIds := metadata["scope-id"]; len(scopeIds) == 1 && scopeIds[0] != "" {
+			return entry.StageEntryWith(ctx, w, ticket, scopeIds[0], msgs...)
+		}
+	}
+	return entry.WriteEntryWith(ctx, w, ticket, msgs...)
+}
+
 func (rw *Db) newOplogMessage(ctx context.Context, opType OpType, i interface{}, opt ...Option) (*oplog.Message, error) {
 	opts := GetOpts(opt...)
 	replayable, ok := i.(oplog.ReplayableMessage)
@@ -874,7 +967,8 @@ func (rw *Db) newOplogMessage(ctx context.Context, opType OpType, i interface{},
 // DoTx will wrap the Handler func passed within a transaction with retries
 // you should ensure that any objects written to the db in your TxHandler are retryable, which
 // means that the object may be sent to the db several times (retried), so things like the primary key must
-// be reset before retry
+// be reset before retry. The changes recorded with NotifyOnCommit by the
+// TxHandler are notified to the commit listeners once the transaction commits.
 func (w *Db) DoTx(ctx context.Context, retries uint, backOff Backoff, Handler TxHandler) (RetryInfo, error) {
 	if w.underlying == nil {
 		return RetryInfo{}, stderrors.New("do underlying db is nil")
@@ -888,7 +982,13 @@ func (w *Db) DoTx(ctx context.Context, retries uint, backOff Backoff, Handler Tx
 		// step one of this, start a transaction...
 		newTx := w.underlying.BeginTx(ctx, nil)
 
-		rw := &Db{newTx}
+		var changes []Change
+		rw := &Db{underlying: newTx, asyncOplog: w.asyncOplog, pendingChanges: &changes}
+		if w.stmtCache != nil && w.underlying.CommonDB() == w.stmtCache.underlying {
+			// Statements may only be used in transactions of the connection
+			// they were prepared on
+			rw.stmtCache = w.stmtCache
+		}
 		if err := Handler(rw, rw); err != nil {
 			if err := newTx.Rollback().Error; err != nil {
 				return info, err
@@ -909,12 +1009,14 @@ func (w *Db) DoTx(ctx context.Context, retries uint, backOff Backoff, Handler Tx
 			}
 			return info, err
 		}
+		notifyCommitListeners(ctx, changes)
 		return info, nil // it all worked!!!
 	}
 }
 
-// LookupByPublicId will lookup resource by its public_id or private_id, which
-// must be unique. Options are ignored.
+// LookupById will lookup resource by its public_id or private_id, which
+// must be unique. Supports the WithFieldWrapper option to decrypt the fields of
+// a FieldEncrypter.
 func (rw *Db) LookupById(ctx context.Context, resourceWithIder interface{}, opt ...Option) error {
 	if rw.underlying == nil {
 		return fmt.Errorf("lookup by id: underlying db nil %w", errors.ErrInvalidParameter)
@@ -932,6 +1034,9 @@ func (rw *Db) LookupById(ctx context.Context, resourceWithIder interface{}, opt
 		}
 		return err
 	}
+	if err := decryptFields(ctx, resourceWithIder, GetOpts(opt...)); err != nil {
+		return fmt.Errorf("lookup by id: %w", err)
+	}
 	return nil
 }
 
@@ -954,11 +1059,67 @@ func primaryKeyWhere(resourceWithIder interface{}) (pkey string, w string, e err
 }
 
 // LookupByPublicId will lookup resource by its public_id, which must be unique.
-// Options are ignored.
+// Supports the WithFieldWrapper option.
 func (rw *Db) LookupByPublicId(ctx context.Context, resource ResourcePublicIder, opt ...Option) error {
 	return rw.LookupById(ctx, resource, opt...)
 }
 
+// LookupByPublicIds will lookup the resources with the public ids using a
+// single query. resources must be a pointer to a slice of
+// ResourcePublicIders, which is set to the found resources in the order of
+// publicIds. Ids which aren't found are skipped and a repeated id is only
+// returned once. Supports the WithPreload option.
+func (rw *Db) LookupByPublicIds(ctx context.Context, resources interface{}, publicIds []string, opt ...Option) error {
+	if rw.underlying == nil {
+		return fmt.Errorf("lookup by public ids: underlying db nil %w", errors.ErrInvalidParameter)
+	}
+	rv := reflect.ValueOf(resources)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("lookup by public ids: interface parameter must be a pointer to a slice: %w", errors.ErrInvalidParameter)
+	}
+	if !rv.Elem().Type().Elem().Implements(reflect.TypeOf((*ResourcePublicIder)(nil)).Elem()) {
+		return fmt.Errorf("lookup by public ids: slice elements must have public ids: %w", errors.ErrInvalidParameter)
+	}
+	position := make(map[string]int, len(publicIds))
+	ids := make([]string, 0, len(publicIds))
+	for _, id := range publicIds {
+		if id == "" {
+			return fmt.Errorf("lookup by public ids: empty public id: %w", errors.ErrInvalidParameter)
+		}
+		if _, ok := position[id]; !ok {
+			position[id] = len(ids)
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		rv.Elem().Set(reflect.MakeSlice(rv.Elem().Type(), 0, 0))
+		return nil
+	}
+	opt = append(opt, WithLimit(-1))
+	if err := rw.SearchWhere(ctx, resources, "public_id in (?)", []interface{}{ids}, opt...); err != nil {
+		return fmt.Errorf("lookup by public ids: %w", err)
+	}
+
+	// Postgres returns the rows in no particular order, so they are put in
+	// the order of the ids
+	found := rv.Elem()
+	ordered := make([]reflect.Value, len(ids))
+	for i := 0; i < found.Len(); i++ {
+		r := found.Index(i)
+		if p, ok := position[r.Interface().(ResourcePublicIder).GetPublicId()]; ok {
+			ordered[p] = r
+		}
+	}
+	sorted := reflect.MakeSlice(found.Type(), 0, found.Len())
+	for _, r := range ordered {
+		if r.IsValid() {
+			sorted = reflect.Append(sorted, r)
+		}
+	}
+	found.Set(sorted)
+	return nil
+}
+
 // LookupWhere will lookup the first resource using a where clause with parameters (it only returns the first one)
 func (rw *Db) LookupWhere(ctx context.Context, resource interface{}, where string, args ...interface{}) error {
 	if rw.underlying == nil {
@@ -979,7 +1140,8 @@ func (rw *Db) LookupWhere(ctx context.Context, resource interface{}, where strin
 // SearchWhere will search for all the resources it can find using a where
 // clause with parameters.  Supports the WithLimit option.  If
 // WithLimit < 0, then unlimited results are returned.  If WithLimit == 0, then
-// default limits are used for results.  Supports the WithOrder option.
+// default limits are used for results.  Supports the WithOrder option, and the
+// WithStartPageAfter option to page through the results in keyset order.
 func (rw *Db) SearchWhere(ctx context.Context, resources interface{}, where string, args []interface{}, opt ...Option) error {
 	opts := GetOpts(opt...)
 	if rw.underlying == nil {
@@ -989,15 +1151,37 @@ func (rw *Db) SearchWhere(ctx context.Context, resources interface{}, where stri
 		return stderrors.New("error interface parameter must to be a pointer for search by")
 	}
 	var err error
-	db := rw.underlying.Order(opts.withOrder)
+	order := opts.withOrder
+	if opts.withPaging {
+		if order != "" {
+			return stderrors.New("error search by cannot be ordered when paging")
+		}
+		order = pageOrder
+	}
+	db := rw.underlying.Order(order)
 
 	// Perform limiting
+	limit := opts.WithLimit
+	if limit == 0 { // zero signals the default value and default limits
+		limit = DefaultLimit
+	}
 	switch {
-	case opts.WithLimit < 0: // any negative number signals unlimited results
-	case opts.WithLimit == 0: // zero signals the default value and default limits
-		db = db.Limit(DefaultLimit)
+	case limit < 0: // any negative number signals unlimited results
+	case opts.withPaging:
+		// Search one more result than the page holds to know whether there
+		// is a next page
+		db = db.Limit(limit + 1)
 	default:
-		db = db.Limit(opts.WithLimit)
+		db = db.Limit(limit)
+	}
+
+	// Start the page after the result of the cursor
+	if opts.withStartPageAfter != "" {
+		c, err := decodePageCursor(opts.withStartPageAfter)
+		if err != nil {
+			return fmt.Errorf("search by: %w", err)
+		}
+		db = db.Where("(create_time, public_id) > (?, ?)", c.CreateTime, c.PublicId)
 	}
 
 	// Perform argument subst
@@ -1013,9 +1197,96 @@ func (rw *Db) SearchWhere(ctx context.Context, resources interface{}, where stri
 		// searching with a slice parameter does not return a gorm.ErrRecordNotFound
 		return err
 	}
+	if opts.withPaging {
+		next, err := endPage(resources, limit)
+		if err != nil {
+			return err
+		}
+		if opts.withNextPageCursor != nil {
+			*opts.withNextPageCursor = next
+		}
+	}
+	for _, p := range opts.withPreloads {
+		if err := rw.preload(resources, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preload loads the associated rows described by p for the search results in
+// resources using a single query.
+func (rw *Db) preload(resources interface{}, p preload) error {
+	if p.foreignKey == "" || p.keyField == "" {
+		return stderrors.New("error preload foreign key and key field are required")
+	}
+	if reflect.ValueOf(p.resources).Kind() != reflect.Ptr {
+		return stderrors.New("error preload resources must be a pointer")
+	}
+	keys, err := preloadKeys(resources, p.keyField)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := rw.underlying.Where(fmt.Sprintf("%s in (?)", p.foreignKey), keys).Find(p.resources).Error; err != nil {
+		return fmt.Errorf("error preloading %s: %w", p.foreignKey, err)
+	}
 	return nil
 }
 
+// preloadKeys returns the distinct, non-zero values of the keyField struct
+// field of the resources, which must be a pointer to a slice of structs or
+// struct pointers.
+func preloadKeys(resources interface{}, keyField string) ([]interface{}, error) {
+	v := reflect.Indirect(reflect.ValueOf(resources))
+	if v.Kind() != reflect.Slice {
+		return nil, stderrors.New("error preload requires search resources to be a slice")
+	}
+	elemType := v.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, stderrors.New("error preload requires search resources to be a slice of structs")
+	}
+	field, ok := elemType.FieldByName(keyField)
+	if !ok {
+		return nil, fmt.Errorf("error preload key field %s not found", keyField)
+	}
+	seen := make(map[interface{}]struct{}, v.Len())
+	keys := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		key, ok := fieldByIndex(v.Index(i), field.Index)
+		if !ok || key.IsZero() {
+			continue
+		}
+		k := key.Interface()
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but returns false rather
+// than panicking when it encounters a nil embedded struct pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
+
 // filterPaths will filter out non-updatable fields
 func filterPaths(paths []string) []string {
 	if len(paths) == 0 {
@@ -785,6 +785,44 @@ func TestDb_Create(t *testing.T) {
 	})
 }
 
+func TestDb_WithReturning(t *testing.T) {
+	t.Parallel()
+	db, _ := TestSetup(t, "postgres")
+	w := Db{underlying: db}
+	for _, returning := range []bool{true, false} {
+		t.Run(fmt.Sprintf("returning-%t", returning), func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			id, err := uuid.GenerateUUID()
+			require.NoError(err)
+			user, err := db_test.NewTestUser()
+			require.NoError(err)
+			user.Name = "foo-" + id
+			require.NoError(w.Create(context.Background(), user, WithReturning(returning)))
+			assert.NotEmpty(user.Id)
+			assert.NotNil(user.CreateTime)
+			assert.NotNil(user.UpdateTime)
+
+			found, err := db_test.NewTestUser()
+			require.NoError(err)
+			found.PublicId = user.PublicId
+			require.NoError(w.LookupByPublicId(context.Background(), found))
+			assert.True(proto.Equal(found, user))
+
+			user.Name = "bar-" + id
+			rowsUpdated, err := w.Update(context.Background(), user, []string{"Name"}, nil, WithReturning(returning))
+			require.NoError(err)
+			assert.Equal(1, rowsUpdated)
+
+			found, err = db_test.NewTestUser()
+			require.NoError(err)
+			found.PublicId = user.PublicId
+			require.NoError(w.LookupByPublicId(context.Background(), found))
+			assert.True(proto.Equal(found, user))
+			assert.Equal("bar-"+id, user.Name)
+		})
+	}
+}
+
 func TestDb_LookupByPublicId(t *testing.T) {
 	t.Parallel()
 	db, _ := TestSetup(t, "postgres")
@@ -840,6 +878,48 @@ func TestDb_LookupByPublicId(t *testing.T) {
 	})
 }
 
+func TestDb_LookupByPublicIds(t *testing.T) {
+	t.Parallel()
+	db, _ := TestSetup(t, "postgres")
+	u1 := testUser(t, db, "user1", "", "")
+	u2 := testUser(t, db, "user2", "", "")
+	u3 := testUser(t, db, "user3", "", "")
+	t.Run("ordered", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		w := Db{underlying: db}
+		var found []*db_test.TestUser
+		err := w.LookupByPublicIds(context.Background(), &found, []string{u3.PublicId, "missing", u1.PublicId, u2.PublicId, u1.PublicId})
+		require.NoError(err)
+		require.Len(found, 3)
+		assert.Equal(u3.PublicId, found[0].PublicId)
+		assert.Equal(u1.PublicId, found[1].PublicId)
+		assert.Equal(u2.PublicId, found[2].PublicId)
+	})
+	t.Run("none", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		w := Db{underlying: db}
+		var found []*db_test.TestUser
+		require.NoError(w.LookupByPublicIds(context.Background(), &found, nil))
+		assert.Empty(found)
+		require.NoError(w.LookupByPublicIds(context.Background(), &found, []string{"missing"}))
+		assert.Empty(found)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert := assert.New(t)
+		w := Db{underlying: db}
+		var found []*db_test.TestUser
+		err := w.LookupByPublicIds(context.Background(), found, []string{u1.PublicId})
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		err = w.LookupByPublicIds(context.Background(), &found, []string{""})
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		var names []string
+		err = w.LookupByPublicIds(context.Background(), &names, []string{u1.PublicId})
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		err = (&Db{}).LookupByPublicIds(context.Background(), &found, []string{u1.PublicId})
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+}
+
 func TestDb_LookupWhere(t *testing.T) {
 	t.Parallel()
 	db, _ := TestSetup(t, "postgres")
@@ -1012,6 +1092,141 @@ func TestDb_SearchWhere(t *testing.T) {
 	}
 }
 
+func TestDb_SearchWhere_WithPreload(t *testing.T) {
+	t.Parallel()
+	conn, _ := TestSetup(t, "postgres")
+	rw := Db{underlying: conn}
+	ctx := context.Background()
+
+	userWithRentals := testUser(t, conn, "preload-with-rentals", "", "")
+	userWithoutRentals := testUser(t, conn, "preload-without-rentals", "", "")
+	otherUser := testUser(t, conn, "preload-other", "", "")
+	for _, u := range []*db_test.TestUser{userWithRentals, userWithRentals, otherUser} {
+		r, err := db_test.NewTestRental()
+		require.NoError(t, err)
+		r.UserId = u.Id
+		require.NoError(t, conn.Create(r).Error)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		var foundUsers []*db_test.TestUser
+		var foundRentals []*db_test.TestRental
+		err := rw.SearchWhere(ctx, &foundUsers, "public_id in (?)",
+			[]interface{}{[]string{userWithRentals.PublicId, userWithoutRentals.PublicId}},
+			WithPreload(&foundRentals, "user_id", "Id"))
+		require.NoError(err)
+		assert.Len(foundUsers, 2)
+		require.Len(foundRentals, 2)
+		for _, r := range foundRentals {
+			assert.Equal(userWithRentals.Id, r.UserId)
+		}
+	})
+	t.Run("no-results", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		var foundUsers []*db_test.TestUser
+		var foundRentals []*db_test.TestRental
+		err := rw.SearchWhere(ctx, &foundUsers, "public_id = ?", []interface{}{"bad-id"},
+			WithPreload(&foundRentals, "user_id", "Id"))
+		require.NoError(err)
+		assert.Len(foundUsers, 0)
+		assert.Len(foundRentals, 0)
+	})
+	t.Run("unknown-key-field", func(t *testing.T) {
+		var foundUsers []*db_test.TestUser
+		var foundRentals []*db_test.TestRental
+		err := rw.SearchWhere(ctx, &foundUsers, "public_id = ?", []interface{}{userWithRentals.PublicId},
+			WithPreload(&foundRentals, "user_id", "NotAField"))
+		require.Error(t, err)
+	})
+	t.Run("resources-not-a-pointer", func(t *testing.T) {
+		var foundUsers []*db_test.TestUser
+		var foundRentals []*db_test.TestRental
+		err := rw.SearchWhere(ctx, &foundUsers, "public_id = ?", []interface{}{userWithRentals.PublicId},
+			WithPreload(foundRentals, "user_id", "Id"))
+		require.Error(t, err)
+	})
+}
+
+func TestDb_AsyncOplog(t *testing.T) {
+	t.Parallel()
+	conn, _ := TestSetup(t, "postgres")
+	ctx := context.Background()
+	wrapper := TestWrapper(t)
+	wrapperFn := func(context.Context, string) (wrapping.Wrapper, error) {
+		return wrapper, nil
+	}
+	w := New(conn, WithAsyncOplog(true))
+
+	t.Run("staged", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		id := testId(t)
+		user := testUser(t, conn, "foo-"+id, id, id)
+		user.Name = "friendly-" + id
+		_, err := w.DoTx(ctx, StdRetryCnt, ExpBackoff{}, func(r Reader, tx Writer) error {
+			rowsUpdated, err := tx.Update(ctx, user, []string{"Name"}, nil,
+				WithOplog(wrapper, oplog.Metadata{
+					"scope-id":           []string{"global"},
+					"resource-public-id": []string{user.PublicId},
+					"op-type":            []string{oplog.OpType_OP_TYPE_UPDATE.String()},
+				}),
+			)
+			require.NoError(err)
+			assert.Equal(1, rowsUpdated)
+			return nil
+		})
+		require.NoError(err)
+
+		// The entry isn't in the oplog until it's flushed
+		err = TestVerifyOplog(t, w, user.PublicId, WithOperation(oplog.OpType_OP_TYPE_UPDATE), WithCreateNotBefore(10*time.Second))
+		require.Error(err)
+
+		flushed, err := oplog.FlushStagedEntries(ctx, conn, wrapperFn, -1)
+		require.NoError(err)
+		assert.GreaterOrEqual(flushed, 1)
+		err = TestVerifyOplog(t, w, user.PublicId, WithOperation(oplog.OpType_OP_TYPE_UPDATE), WithCreateNotBefore(10*time.Second))
+		require.NoError(err)
+	})
+	t.Run("rolled-back", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		id := testId(t)
+		user := testUser(t, conn, "foo-"+id, id, id)
+		user.Name = "friendly-" + id
+		_, err := w.DoTx(ctx, StdRetryCnt, ExpBackoff{}, func(r Reader, tx Writer) error {
+			_, err := tx.Update(ctx, user, []string{"Name"}, nil,
+				WithOplog(wrapper, oplog.Metadata{
+					"scope-id":           []string{"global"},
+					"resource-public-id": []string{user.PublicId},
+					"op-type":            []string{oplog.OpType_OP_TYPE_UPDATE.String()},
+				}),
+			)
+			require.NoError(err)
+			return stderrors.New("rollback")
+		})
+		require.Error(err)
+
+		var staged []*oplog.StagedEntry
+		require.NoError(conn.Find(&staged).Error)
+		assert.Empty(staged)
+	})
+	t.Run("no-scope-id", func(t *testing.T) {
+		require := require.New(t)
+		id := testId(t)
+		user := testUser(t, conn, "foo-"+id, id, id)
+		user.Name = "friendly-" + id
+		_, err := w.Update(ctx, user, []string{"Name"}, nil,
+			WithOplog(wrapper, oplog.Metadata{
+				"resource-public-id": []string{user.PublicId},
+				"op-type":            []string{oplog.OpType_OP_TYPE_UPDATE.String()},
+			}),
+		)
+		require.NoError(err)
+		// Without a scope the entry is written directly
+		err = TestVerifyOplog(t, w, user.PublicId, WithOperation(oplog.OpType_OP_TYPE_UPDATE), WithCreateNotBefore(10*time.Second))
+		require.NoError(err)
+	})
+}
+
 func TestDb_Exec(t *testing.T) {
 	t.Parallel()
 	t.Run("update", func(t *testing.T) {
@@ -1108,7 +1323,7 @@ func TestDb_DoTx(t *testing.T) {
 	})
 	t.Run("nil-tx", func(t *testing.T) {
 		assert, require := assert.New(t), require.New(t)
-		w := &Db{nil}
+		w := &Db{underlying: nil}
 		attempts := 0
 		got, err := w.DoTx(context.Background(), 1, ExpBackoff{}, func(Reader, Writer) error { attempts += 1; return nil })
 		require.Error(err)
@@ -1882,6 +2097,66 @@ func TestDb_DeleteItems(t *testing.T) {
 	}
 }
 
+func TestDb_SearchWhere_WithStartPageAfter(t *testing.T) {
+	t.Parallel()
+	conn, _ := TestSetup(t, "postgres")
+	rw := Db{underlying: conn}
+	ctx := context.Background()
+
+	want := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		u := testUser(t, conn, fmt.Sprintf("paged-%d", i), "", "")
+		want[u.PublicId] = true
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		found := map[string]bool{}
+		var pageLens []int
+		var next string
+		for {
+			var foundUsers []*db_test.TestUser
+			err := rw.SearchWhere(ctx, &foundUsers, "name like ?", []interface{}{"paged-%"},
+				WithLimit(2), WithStartPageAfter(next, &next))
+			require.NoError(err)
+			pageLens = append(pageLens, len(foundUsers))
+			for _, u := range foundUsers {
+				assert.False(found[u.PublicId], "user %s found twice", u.PublicId)
+				found[u.PublicId] = true
+			}
+			if next == "" {
+				break
+			}
+			require.Less(len(pageLens), 5, "too many pages")
+		}
+		assert.Equal([]int{2, 2, 1}, pageLens)
+		assert.Equal(want, found)
+	})
+	t.Run("unlimited", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		next := "not-reset"
+		var foundUsers []*db_test.TestUser
+		err := rw.SearchWhere(ctx, &foundUsers, "name like ?", []interface{}{"paged-%"},
+			WithLimit(-1), WithStartPageAfter("", &next))
+		require.NoError(err)
+		assert.Len(foundUsers, 5)
+		assert.Empty(next)
+	})
+	t.Run("invalid-cursor", func(t *testing.T) {
+		var foundUsers []*db_test.TestUser
+		err := rw.SearchWhere(ctx, &foundUsers, "name like ?", []interface{}{"paged-%"},
+			WithStartPageAfter("not-a-cursor", nil))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrInvalidParameter))
+	})
+	t.Run("with-order", func(t *testing.T) {
+		var foundUsers []*db_test.TestUser
+		err := rw.SearchWhere(ctx, &foundUsers, "name like ?", []interface{}{"paged-%"},
+			WithOrder("name asc"), WithStartPageAfter("", nil))
+		require.Error(t, err)
+	})
+}
+
 func testUser(t *testing.T, conn *gorm.DB, name, email, phoneNumber string) *db_test.TestUser {
 	t.Helper()
 	require := require.New(t)
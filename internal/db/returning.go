@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// returningSetting is the gorm setting which makes the create and update
+// callbacks populate the resource from a RETURNING clause of the insert or
+// update statement, instead of reloading the columns the db generated with an
+// extra select. Its value is the context of the write.
+const returningSetting = "boundary:returning"
+
+// The create and update callbacks are wrapped rather than replaced: writes
+// without the returning setting, which only Create and Update set, and only
+// with WithReturning(true), run gorm's callbacks unchanged.
+func init() {
+	create := gorm.DefaultCallback.Create().Get("gorm:create")
+	gorm.DefaultCallback.Create().Replace("gorm:create", func(scope *gorm.Scope) {
+		if _, ok := scope.Get(returningSetting); !ok {
+			create(scope)
+			return
+		}
+		createReturningCallback(scope)
+	})
+	update := gorm.DefaultCallback.Update().Get("gorm:update")
+	gorm.DefaultCallback.Update().Replace("gorm:update", func(scope *gorm.Scope) {
+		if _, ok := scope.Get(returningSetting); !ok {
+			update(scope)
+			return
+		}
+		updateReturningCallback(scope)
+	})
+}
+
+// createReturningCallback inserts the resource like gorm's create callback but
+// returns all its columns, including the ones generated by the db (e.g.
+// create_time, update_time and version), and sets them on the resource.
+func createReturningCallback(scope *gorm.Scope) {
+	if scope.HasError() {
+		return
+	}
+	var columns, placeholders []string
+	for _, field := range scope.Fields() {
+		if !field.IsNormal || field.IsIgnored {
+			continue
+		}
+		if field.IsBlank && (field.HasDefaultValue || field.IsPrimaryKey) {
+			continue
+		}
+		columns = append(columns, scope.Quote(field.DBName))
+		placeholders = append(placeholders, scope.AddToVars(field.Field.Interface()))
+	}
+	returning, fields := returningColumns(scope)
+	if len(columns) == 0 {
+		scope.Raw(fmt.Sprintf("insert into %s default values returning %s", scope.QuotedTableName(), returning))
+	} else {
+		scope.Raw(fmt.Sprintf("insert into %s (%s) values (%s) returning %s",
+			scope.QuotedTableName(), strings.Join(columns, ","), strings.Join(placeholders, ","), returning))
+	}
+	scope.DB().RowsAffected = scanReturning(scope, fields)
+}
+
+// updateReturningCallback updates the resource like gorm's update callback
+// but returns all its columns, including the ones changed by triggers (e.g.
+// update_time and version), and sets them on the resource. Only updates of
+// attributes, as done by Updates, are supported.
+func updateReturningCallback(scope *gorm.Scope) {
+	if scope.HasError() {
+		return
+	}
+	attrs, ok := scope.InstanceGet("gorm:update_attrs")
+	if !ok {
+		scope.Err(fmt.Errorf("update returning: missing update attributes"))
+		return
+	}
+	updateMap := attrs.(map[string]interface{})
+	columns := make([]string, 0, len(updateMap))
+	for c := range updateMap {
+		columns = append(columns, c)
+	}
+	// sort the columns so the generated sql is the same every time
+	sort.Strings(columns)
+	sets := make([]string, 0, len(columns))
+	for _, c := range columns {
+		sets = append(sets, fmt.Sprintf("%s = %s", scope.Quote(c), scope.AddToVars(updateMap[c])))
+	}
+	if len(sets) == 0 {
+		return
+	}
+	returning, fields := returningColumns(scope)
+	scope.Raw(fmt.Sprintf("update %s set %s %s returning %s",
+		scope.QuotedTableName(), strings.Join(sets, ", "), scope.CombinedConditionSql(), returning))
+	scope.DB().RowsAffected = scanReturning(scope, fields)
+}
+
+// returningColumns returns the quoted columns of the resource of the scope
+// for a RETURNING clause and their fields, in the same order.
+func returningColumns(scope *gorm.Scope) (string, []*gorm.Field) {
+	var columns []string
+	var fields []*gorm.Field
+	for _, field := range scope.Fields() {
+		if !field.IsNormal || field.IsIgnored {
+			continue
+		}
+		columns = append(columns, scope.Quote(field.DBName))
+		fields = append(fields, field)
+	}
+	return strings.Join(columns, ","), fields
+}
+
+// queryContexter is implemented by *sql.DB and *sql.Tx.
+type queryContexter interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// scanReturning executes the sql of the scope with the context of the write
+// and sets the fields from the returned rows. It returns the number of rows
+// returned.
+func scanReturning(scope *gorm.Scope, fields []*gorm.Field) int64 {
+	q, ok := scope.SQLDB().(queryContexter)
+	if !ok {
+		scope.Err(fmt.Errorf("returning: unsupported connection %T", scope.SQLDB()))
+		return 0
+	}
+	ctx := context.Background()
+	if v, ok := scope.Get(returningSetting); ok {
+		if c, ok := v.(context.Context); ok {
+			ctx = c
+		}
+	}
+	rows, err := q.QueryContext(ctx, scope.SQL, scope.SQLVars...)
+	if scope.Err(err) != nil {
+		return 0
+	}
+	defer rows.Close()
+	var n int64
+	for rows.Next() {
+		n++
+		// scan through pointers, since the returned columns may be null
+		values := make([]interface{}, len(fields))
+		for i, f := range fields {
+			values[i] = reflect.New(reflect.PtrTo(f.Struct.Type)).Interface()
+		}
+		if scope.Err(rows.Scan(values...)) != nil {
+			return n
+		}
+		for i, f := range fields {
+			v := reflect.ValueOf(values[i]).Elem()
+			if v.IsNil() {
+				f.Field.Set(reflect.Zero(f.Struct.Type))
+				continue
+			}
+			f.Field.Set(v.Elem())
+		}
+	}
+	scope.Err(rows.Err())
+	return n
+}
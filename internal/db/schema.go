@@ -0,0 +1,329 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/hashicorp/boundary/internal/db/migrations"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/jinzhu/gorm"
+)
+
+// MinDataMigrationVersion is the lowest version of a data migration: the
+// version of the sql migration which creates the table recording applied
+// migrations.
+const MinDataMigrationVersion = 75
+
+// ErrMigrationChecksumMismatch is returned by InitStore and VerifyMigrations
+// when a sql migration already applied to the database differs from the one of
+// the same version in this binary. It is fixed by RepairMigrations once the
+// difference has been checked to be harmless.
+var ErrMigrationChecksumMismatch = stderrors.New("migration checksum mismatch")
+
+// DataMigrationFunc migrates data in go, e.g. to rewrap encrypted fields. It is
+// called within the transaction which records that the migration ran, so it
+// either runs completely or not at all.
+type DataMigrationFunc func(ctx context.Context, r Reader, w Writer) error
+
+// A DataMigration is a go migration run by InitStore right after the sql
+// migration of the same version is applied, before any later migration.
+type DataMigration struct {
+	Version uint
+	Name    string
+	Up      DataMigrationFunc
+}
+
+var dataMigrations = struct {
+	sync.RWMutex
+	byDialect map[string]map[uint]DataMigration
+}{byDialect: map[string]map[uint]DataMigration{}}
+
+// RegisterDataMigration registers the data migration of the dialect. Data
+// migrations must be registered before InitStore is called, and a version can
+// have at most one data migration. Its version must be at least
+// MinDataMigrationVersion and have a sql migration, which may be empty.
+func RegisterDataMigration(dialect string, m DataMigration) error {
+	const op = "register data migration"
+	switch {
+	case dialect == "":
+		return fmt.Errorf("%s: missing dialect: %w", op, errors.ErrInvalidParameter)
+	case m.Version < MinDataMigrationVersion:
+		return fmt.Errorf("%s: version %d is less than %d: %w", op, m.Version, MinDataMigrationVersion, errors.ErrInvalidParameter)
+	case strings.TrimSpace(m.Name) == "":
+		return fmt.Errorf("%s: missing name: %w", op, errors.ErrInvalidParameter)
+	case m.Up == nil:
+		return fmt.Errorf("%s: missing up func: %w", op, errors.ErrInvalidParameter)
+	}
+	dataMigrations.Lock()
+	defer dataMigrations.Unlock()
+	byVersion := dataMigrations.byDialect[dialect]
+	if byVersion == nil {
+		byVersion = map[uint]DataMigration{}
+		dataMigrations.byDialect[dialect] = byVersion
+	}
+	if existing, ok := byVersion[m.Version]; ok {
+		return fmt.Errorf("%s: version %d already has data migration %s: %w", op, m.Version, existing.Name, errors.ErrInvalidParameter)
+	}
+	byVersion[m.Version] = m
+	return nil
+}
+
+func dialectDataMigrations(dialect string) []DataMigration {
+	dataMigrations.RLock()
+	defer dataMigrations.RUnlock()
+	ret := make([]DataMigration, 0, len(dataMigrations.byDialect[dialect]))
+	for _, m := range dataMigrations.byDialect[dialect] {
+		ret = append(ret, m)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Version < ret[j].Version })
+	return ret
+}
+
+// migrationRecord is a migration recorded in the schema_migration_record table.
+type migrationRecord struct {
+	Version uint
+	Kind    string
+	Name    string
+	// Checksum is null for data migrations
+	Checksum sql.NullString
+}
+
+// TableName returns the table name for the migration record.
+func (r *migrationRecord) TableName() string {
+	return migrationRecordTable
+}
+
+const (
+	sqlMigrationKind  = "sql"
+	dataMigrationKind = "data"
+)
+
+// runMigrations applies the migrations of m which are not yet applied, running
+// each data migration after the sql migration of its version. It first verifies
+// the checksums of the sql migrations already applied and the fingerprint of
+// the schema, and afterwards records the checksums of those newly applied and
+// the fingerprint of the migrated schema. It returns true if any migration
+// ran.
+func runMigrations(ctx context.Context, dialect string, m *migrate.Migrate, conn *gorm.DB) (bool, error) {
+	rw := New(conn)
+	if err := verifyMigrations(ctx, dialect, rw); err != nil {
+		return false, err
+	}
+	if err := verifySchemaFingerprint(ctx, rw); err != nil {
+		return false, err
+	}
+	var ran bool
+	for _, dm := range dialectDataMigrations(dialect) {
+		current, dirty, err := m.Version()
+		switch {
+		case err == migrate.ErrNilVersion:
+		case err != nil:
+			return ran, fmt.Errorf("unable to get schema version: %w", err)
+		case dirty:
+			return ran, fmt.Errorf("schema version %d is dirty", current)
+		}
+		if err == migrate.ErrNilVersion || current < dm.Version {
+			if err := m.Migrate(dm.Version); err != nil {
+				return ran, fmt.Errorf("unable to migrate to version %d: %w", dm.Version, err)
+			}
+			ran = true
+		}
+		dataRan, err := runDataMigration(ctx, rw, dm)
+		if err != nil {
+			return ran, err
+		}
+		ran = ran || dataRan
+	}
+	switch err := m.Up(); {
+	case err == nil:
+		ran = true
+	case err != migrate.ErrNoChange:
+		return ran, err
+	}
+	if err := recordMigrations(ctx, dialect, m, rw); err != nil {
+		return ran, err
+	}
+	if err := recordSchemaFingerprint(ctx, m, rw); err != nil {
+		return ran, err
+	}
+	return ran, nil
+}
+
+// runDataMigration runs the data migration unless it was already recorded,
+// recording it in the same transaction.
+func runDataMigration(ctx context.Context, rw *Db, dm DataMigration) (bool, error) {
+	var ran bool
+	_, err := rw.DoTx(ctx, 0, ExpBackoff{}, func(r Reader, w Writer) error {
+		var recorded []*migrationRecord
+		if err := r.SearchWhere(ctx, &recorded, "version = ? and kind = ?", []interface{}{dm.Version, dataMigrationKind}); err != nil {
+			return fmt.Errorf("unable to read migration records: %w", err)
+		}
+		if len(recorded) > 0 {
+			return nil
+		}
+		if err := dm.Up(ctx, r, w); err != nil {
+			return err
+		}
+		if _, err := w.Exec(ctx, insertMigrationRecordQuery, []interface{}{dm.Version, dataMigrationKind, dm.Name, nil}); err != nil {
+			return fmt.Errorf("unable to record data migration: %w", err)
+		}
+		ran = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("data migration %d %s: %w", dm.Version, dm.Name, err)
+	}
+	return ran, nil
+}
+
+// recordMigrations records the checksums of the applied sql migrations which
+// are not yet recorded.
+func recordMigrations(ctx context.Context, dialect string, m *migrate.Migrate, rw *Db) error {
+	current, _, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("unable to get schema version: %w", err)
+	}
+	if current < MinDataMigrationVersion {
+		// The record table does not exist yet
+		return nil
+	}
+	checksums, err := migrations.Checksums(dialect)
+	if err != nil {
+		return err
+	}
+	_, err = rw.DoTx(ctx, StdRetryCnt, ExpBackoff{}, func(_ Reader, w Writer) error {
+		for _, c := range checksums {
+			if c.Version > current {
+				break
+			}
+			if _, err := w.Exec(ctx, insertMigrationRecordQuery, []interface{}{c.Version, sqlMigrationKind, c.Name, c.Checksum}); err != nil {
+				return fmt.Errorf("unable to record migration %d: %w", c.Version, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to record migrations: %w", err)
+	}
+	return nil
+}
+
+// mismatchedMigrations returns the recorded sql migrations whose checksum
+// differs from the migration of the same version in this binary, and the
+// checksums of this binary by version.
+func mismatchedMigrations(ctx context.Context, dialect string, r Reader) ([]*migrationRecord, map[uint]migrations.Checksum, error) {
+	var recorded []*migrationRecord
+	if err := r.SearchWhere(ctx, &recorded, "kind = ?", []interface{}{sqlMigrationKind}, WithLimit(-1)); err != nil {
+		if errors.IsMissingTableError(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("unable to read migration records: %w", err)
+	}
+	checksums, err := migrations.Checksums(dialect)
+	if err != nil {
+		return nil, nil, err
+	}
+	byVersion := make(map[uint]migrations.Checksum, len(checksums))
+	for _, c := range checksums {
+		byVersion[c.Version] = c
+	}
+	var mismatched []*migrationRecord
+	for _, rec := range recorded {
+		if c, ok := byVersion[rec.Version]; !ok || c.Checksum != rec.Checksum.String {
+			mismatched = append(mismatched, rec)
+		}
+	}
+	sort.Slice(mismatched, func(i, j int) bool { return mismatched[i].Version < mismatched[j].Version })
+	return mismatched, byVersion, nil
+}
+
+func verifyMigrations(ctx context.Context, dialect string, r Reader) error {
+	mismatched, _, err := mismatchedMigrations(ctx, dialect, r)
+	if err != nil {
+		return err
+	}
+	if len(mismatched) > 0 {
+		names := make([]string, 0, len(mismatched))
+		for _, rec := range mismatched {
+			names = append(names, rec.Name)
+		}
+		return fmt.Errorf("applied migrations %s differ from this binary: %w", strings.Join(names, ", "), ErrMigrationChecksumMismatch)
+	}
+	return nil
+}
+
+// VerifyMigrations returns an error wrapping ErrMigrationChecksumMismatch if
+// any sql migration applied to the database differs from the migration of the
+// same version in this binary.
+func VerifyMigrations(ctx context.Context, dialect string, url string) error {
+	conn, err := gorm.Open(dialect, url)
+	if err != nil {
+		return fmt.Errorf("verify migrations: unable to open database: %w", err)
+	}
+	defer conn.Close()
+	if err := verifyMigrations(ctx, dialect, New(conn)); err != nil {
+		return fmt.Errorf("verify migrations: %w", err)
+	}
+	return nil
+}
+
+// RepairMigrations updates the recorded checksums of the applied sql
+// migrations which differ from the migrations of the same versions in this
+// binary, and returns the versions repaired. It does not change the schema, so
+// it must only be used once the differences have been checked to be harmless,
+// e.g. a migration whose comments were edited.
+func RepairMigrations(ctx context.Context, dialect string, url string) ([]uint, error) {
+	conn, err := gorm.Open(dialect, url)
+	if err != nil {
+		return nil, fmt.Errorf("repair migrations: unable to open database: %w", err)
+	}
+	defer conn.Close()
+	var repaired []uint
+	_, err = New(conn).DoTx(ctx, 0, ExpBackoff{}, func(r Reader, w Writer) error {
+		repaired = nil
+		mismatched, checksums, err := mismatchedMigrations(ctx, dialect, r)
+		if err != nil {
+			return err
+		}
+		for _, rec := range mismatched {
+			c, ok := checksums[rec.Version]
+			if !ok {
+				return fmt.Errorf("applied migration %s is not in this binary", rec.Name)
+			}
+			if _, err := w.Exec(ctx, repairMigrationRecordQuery, []interface{}{c.Name, c.Checksum, rec.Version, sqlMigrationKind}); err != nil {
+				return fmt.Errorf("unable to repair migration %d: %w", rec.Version, err)
+			}
+			repaired = append(repaired, rec.Version)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repair migrations: %w", err)
+	}
+	return repaired, nil
+}
+
+const (
+	migrationRecordTable = "schema_migration_record"
+
+	insertMigrationRecordQuery = `
+insert into schema_migration_record
+  (version, kind, name, checksum)
+values
+  (?, ?, ?, ?)
+on conflict (version, kind) do nothing;
+`
+	repairMigrationRecordQuery = `
+update schema_migration_record
+   set name = ?,
+       checksum = ?
+ where version = ?
+   and kind = ?;
+`
+)
@@ -0,0 +1,245 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/jinzhu/gorm"
+)
+
+// MinSchemaFingerprintVersion is the lowest version whose schema fingerprint
+// is recorded: the version of the sql migration which creates the table
+// recording them.
+const MinSchemaFingerprintVersion = 93
+
+// ErrSchemaDrift is returned by InitStore and VerifySchemaFingerprint when the
+// schema of the database differs from the schema its migrations created, e.g.
+// because it was changed by hand. It is fixed by reverting the changes, or by
+// RepairSchemaFingerprint once they have been checked to be harmless.
+var ErrSchemaDrift = stderrors.New("schema drift")
+
+const (
+	// schemaObjectsQuery describes each table, column, constraint and index
+	// of the current schema on a row, in a stable order.
+	schemaObjectsQuery = `
+select 'table ' || table_name
+  from information_schema.tables
+ where table_schema = current_schema()
+   and table_type = 'BASE TABLE'
+union all
+select 'column ' || table_name || '.' || column_name || ' ' || data_type || ' ' || is_nullable || ' ' || coalesce(column_default, '')
+  from information_schema.columns
+ where table_schema = current_schema()
+union all
+select 'constraint ' || rel.relname || '.' || con.conname || ' ' || pg_get_constraintdef(con.oid)
+  from pg_constraint con
+  join pg_class rel on rel.oid = con.conrelid
+ where rel.relnamespace = current_schema()::regnamespace
+union all
+select 'index ' || tablename || '.' || indexname || ' ' || indexdef
+  from pg_indexes
+ where schemaname = current_schema()
+order by 1;
+`
+	schemaVersionQuery = `
+select version, dirty
+  from schema_migrations;
+`
+	schemaFingerprintQuery = `
+select fingerprint
+  from schema_fingerprint
+ where version = ?;
+`
+	insertSchemaFingerprintQuery = `
+insert into schema_fingerprint
+  (version, fingerprint)
+values
+  (?, ?)
+on conflict (version) do nothing;
+`
+	repairSchemaFingerprintQuery = `
+insert into schema_fingerprint
+  (version, fingerprint)
+values
+  (?, ?)
+on conflict (version) do update
+  set fingerprint = excluded.fingerprint;
+`
+)
+
+// schemaFingerprint returns the hex encoded sha256 of the description of the
+// tables, columns, constraints and indexes of the current schema.
+func schemaFingerprint(ctx context.Context, r Reader) (string, error) {
+	rows, err := r.Query(ctx, schemaObjectsQuery, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to read schema: %w", err)
+	}
+	defer rows.Close()
+	h := sha256.New()
+	for rows.Next() {
+		var object string
+		if err := rows.Scan(&object); err != nil {
+			return "", fmt.Errorf("unable to read schema: %w", err)
+		}
+		h.Write([]byte(object))
+		h.Write([]byte{'\n'})
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("unable to read schema: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// schemaVersion returns the version of the last migration applied to the
+// database, or zero if none was.
+func schemaVersion(ctx context.Context, r Reader) (uint, error) {
+	rows, err := r.Query(ctx, schemaVersionQuery, nil)
+	if err != nil {
+		if errors.IsMissingTableError(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("unable to get schema version: %w", err)
+	}
+	defer rows.Close()
+	var version uint
+	var dirty bool
+	if rows.Next() {
+		if err := rows.Scan(&version, &dirty); err != nil {
+			return 0, fmt.Errorf("unable to get schema version: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("unable to get schema version: %w", err)
+	}
+	if dirty {
+		return 0, fmt.Errorf("schema version %d is dirty", version)
+	}
+	return version, nil
+}
+
+// recordedSchemaFingerprint returns the fingerprint recorded for the version,
+// or an empty string if there is none.
+func recordedSchemaFingerprint(ctx context.Context, r Reader, version uint) (string, error) {
+	rows, err := r.Query(ctx, schemaFingerprintQuery, []interface{}{version})
+	if err != nil {
+		return "", fmt.Errorf("unable to read schema fingerprint: %w", err)
+	}
+	defer rows.Close()
+	var fingerprint string
+	if rows.Next() {
+		if err := rows.Scan(&fingerprint); err != nil {
+			return "", fmt.Errorf("unable to read schema fingerprint: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("unable to read schema fingerprint: %w", err)
+	}
+	return fingerprint, nil
+}
+
+// recordSchemaFingerprint records the fingerprint of the schema for the
+// version migrated to by m, unless one is already recorded.
+func recordSchemaFingerprint(ctx context.Context, m *migrate.Migrate, rw *Db) error {
+	current, _, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("unable to get schema version: %w", err)
+	}
+	if current < MinSchemaFingerprintVersion {
+		// The fingerprint table does not exist yet
+		return nil
+	}
+	fingerprint, err := schemaFingerprint(ctx, rw)
+	if err != nil {
+		return err
+	}
+	if _, err := rw.Exec(ctx, insertSchemaFingerprintQuery, []interface{}{current, fingerprint}); err != nil {
+		return fmt.Errorf("unable to record schema fingerprint: %w", err)
+	}
+	return nil
+}
+
+func verifySchemaFingerprint(ctx context.Context, r Reader) error {
+	version, err := schemaVersion(ctx, r)
+	if err != nil {
+		return err
+	}
+	if version < MinSchemaFingerprintVersion {
+		return nil
+	}
+	want, err := recordedSchemaFingerprint(ctx, r, version)
+	if err != nil {
+		return err
+	}
+	if want == "" {
+		// Nothing to compare to until the migrations of the version are
+		// applied again by InitStore
+		return nil
+	}
+	got, err := schemaFingerprint(ctx, r)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("schema of version %d differs from the schema created by its migrations: %w", version, ErrSchemaDrift)
+	}
+	return nil
+}
+
+// VerifySchemaFingerprint returns an error wrapping ErrSchemaDrift if the
+// tables, columns, constraints or indexes of the database differ from those
+// recorded when the database was migrated to its current version. Databases
+// migrated to a version below MinSchemaFingerprintVersion are not verified.
+func VerifySchemaFingerprint(ctx context.Context, r Reader) error {
+	if err := verifySchemaFingerprint(ctx, r); err != nil {
+		return fmt.Errorf("verify schema fingerprint: %w", err)
+	}
+	return nil
+}
+
+// RepairSchemaFingerprint records the fingerprint of the current schema for
+// the current version, replacing the one recorded, and returns true if they
+// differed. It does not change the schema, so it must only be used once the
+// differences have been checked to be harmless, e.g. an index added by hand.
+func RepairSchemaFingerprint(ctx context.Context, dialect string, url string) (bool, error) {
+	conn, err := gorm.Open(dialect, url)
+	if err != nil {
+		return false, fmt.Errorf("repair schema fingerprint: unable to open database: %w", err)
+	}
+	defer conn.Close()
+	var repaired bool
+	_, err = New(conn).DoTx(ctx, 0, ExpBackoff{}, func(r Reader, w Writer) error {
+		repaired = false
+		version, err := schemaVersion(ctx, r)
+		if err != nil {
+			return err
+		}
+		if version < MinSchemaFingerprintVersion {
+			return nil
+		}
+		recorded, err := recordedSchemaFingerprint(ctx, r, version)
+		if err != nil {
+			return err
+		}
+		fingerprint, err := schemaFingerprint(ctx, r)
+		if err != nil {
+			return err
+		}
+		if fingerprint == recorded {
+			return nil
+		}
+		if _, err := w.Exec(ctx, repairSchemaFingerprintQuery, []interface{}{version, fingerprint}); err != nil {
+			return fmt.Errorf("unable to record schema fingerprint: %w", err)
+		}
+		repaired = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("repair schema fingerprint: %w", err)
+	}
+	return repaired, nil
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db/migrations"
+)
+
+// migrationLockId is the key of the session-level advisory lock held while a
+// controller migrates the database, so that only one controller migrates it
+// at a time.
+const migrationLockId = 6203140812
+
+// SchemaStatus is the version of the schema of a database and the version of
+// the latest migration of this binary.
+type SchemaStatus struct {
+	Current  uint
+	Required uint
+}
+
+// UpToDate returns true if all migrations of this binary are applied. A
+// schema newer than the binary is up to date: older binaries keep working
+// while a fleet of controllers is upgraded.
+func (s *SchemaStatus) UpToDate() bool {
+	return s.Current >= s.Required
+}
+
+// GetSchemaStatus returns the status of the schema of the database for the
+// migrations of the dialect in this binary.
+func GetSchemaStatus(ctx context.Context, dialect string, r Reader) (*SchemaStatus, error) {
+	checksums, err := migrations.Checksums(dialect)
+	if err != nil {
+		return nil, fmt.Errorf("get schema status: %w", err)
+	}
+	current, err := schemaVersion(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("get schema status: %w", err)
+	}
+	s := &SchemaStatus{Current: current}
+	if len(checksums) > 0 {
+		s.Required = checksums[len(checksums)-1].Version
+	}
+	return s, nil
+}
+
+// InitStoreExclusive runs InitStore while holding an advisory lock of the
+// database, so that if several controllers are started as migrators only one
+// of them migrates the database and the others find it migrated once they
+// get the lock. It returns true if migrations ran.
+func InitStoreExclusive(ctx context.Context, dialect string, url string) (bool, error) {
+	conn, err := sql.Open(dialect, url)
+	if err != nil {
+		return false, fmt.Errorf("init store exclusive: unable to open database: %w", err)
+	}
+	defer conn.Close()
+	// The lock belongs to the session, so it is taken and released on the
+	// same connection
+	lockConn, err := conn.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("init store exclusive: unable to connect to database: %w", err)
+	}
+	defer lockConn.Close()
+	if _, err := lockConn.ExecContext(ctx, "select pg_advisory_lock($1)", migrationLockId); err != nil {
+		return false, fmt.Errorf("init store exclusive: unable to get migration lock: %w", err)
+	}
+	defer lockConn.ExecContext(context.Background(), "select pg_advisory_unlock($1)", migrationLockId)
+	return InitStore(dialect, nil, url)
+}
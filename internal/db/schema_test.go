@@ -0,0 +1,173 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyAndRepairMigrations(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, url := TestSetup(t, "postgres")
+	rw := New(conn)
+	ctx := context.Background()
+
+	require.NoError(VerifyMigrations(ctx, "postgres", url))
+	repaired, err := RepairMigrations(ctx, "postgres", url)
+	require.NoError(err)
+	assert.Empty(repaired)
+
+	_, err = rw.Exec(ctx, "update schema_migration_record set checksum = 'changed' where version = ? and kind = ?", []interface{}{MinDataMigrationVersion, sqlMigrationKind})
+	require.NoError(err)
+	err = VerifyMigrations(ctx, "postgres", url)
+	require.Error(err)
+	assert.True(errors.Is(err, ErrMigrationChecksumMismatch))
+
+	repaired, err = RepairMigrations(ctx, "postgres", url)
+	require.NoError(err)
+	assert.Equal([]uint{MinDataMigrationVersion}, repaired)
+	require.NoError(VerifyMigrations(ctx, "postgres", url))
+}
+
+func TestVerifyAndRepairSchemaFingerprint(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, url := TestSetup(t, "postgres")
+	rw := New(conn)
+	ctx := context.Background()
+
+	fingerprint, err := schemaFingerprint(ctx, rw)
+	require.NoError(err)
+	version, err := schemaVersion(ctx, rw)
+	require.NoError(err)
+	recorded, err := recordedSchemaFingerprint(ctx, rw, version)
+	require.NoError(err)
+	assert.Equal(fingerprint, recorded)
+
+	require.NoError(VerifySchemaFingerprint(ctx, rw))
+	repaired, err := RepairSchemaFingerprint(ctx, "postgres", url)
+	require.NoError(err)
+	assert.False(repaired)
+
+	// A column added outside of migrations is detected, and blocks migrating
+	_, err = rw.Exec(ctx, "alter table iam_scope add column drifted text", nil)
+	require.NoError(err)
+	err = VerifySchemaFingerprint(ctx, rw)
+	require.Error(err)
+	assert.True(errors.Is(err, ErrSchemaDrift))
+	_, err = InitStore("postgres", nil, url)
+	require.Error(err)
+	assert.True(errors.Is(err, ErrSchemaDrift))
+
+	repaired, err = RepairSchemaFingerprint(ctx, "postgres", url)
+	require.NoError(err)
+	assert.True(repaired)
+	require.NoError(VerifySchemaFingerprint(ctx, rw))
+}
+
+func TestSchemaStatus(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, url := TestSetup(t, "postgres")
+	rw := New(conn)
+	ctx := context.Background()
+
+	status, err := GetSchemaStatus(ctx, "postgres", rw)
+	require.NoError(err)
+	assert.True(status.UpToDate())
+	assert.Equal(status.Required, status.Current)
+
+	// Migrating a migrated database concurrently runs nothing
+	type result struct {
+		ran bool
+		err error
+	}
+	results := make(chan result, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			ran, err := InitStoreExclusive(ctx, "postgres", url)
+			results <- result{ran: ran, err: err}
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		r := <-results
+		assert.NoError(r.err)
+		assert.False(r.ran)
+	}
+
+	// A database behind this binary is not up to date
+	_, err = rw.Exec(ctx, "update schema_migrations set version = ?", []interface{}{status.Required - 1})
+	require.NoError(err)
+	status, err = GetSchemaStatus(ctx, "postgres", rw)
+	require.NoError(err)
+	assert.False(status.UpToDate())
+	assert.Equal(status.Required-1, status.Current)
+}
+
+func TestRunDataMigration(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := TestSetup(t, "postgres")
+	rw := New(conn)
+	ctx := context.Background()
+
+	var calls int
+	dm := DataMigration{
+		Version: MinDataMigrationVersion,
+		Name:    "test",
+		Up: func(ctx context.Context, _ Reader, w Writer) error {
+			calls++
+			return nil
+		},
+	}
+	ran, err := runDataMigration(ctx, rw, dm)
+	require.NoError(err)
+	assert.True(ran)
+	ran, err = runDataMigration(ctx, rw, dm)
+	require.NoError(err)
+	assert.False(ran)
+	assert.Equal(1, calls)
+
+	failing := DataMigration{
+		Version: MinDataMigrationVersion + 1,
+		Name:    "failing",
+		Up: func(ctx context.Context, _ Reader, w Writer) error {
+			if _, err := w.Exec(ctx, "delete from schema_migration_record", nil); err != nil {
+				return err
+			}
+			return errors.ErrInvalidParameter
+		},
+	}
+	_, err = runDataMigration(ctx, rw, failing)
+	require.Error(err)
+	var recorded []*migrationRecord
+	require.NoError(rw.SearchWhere(ctx, &recorded, "kind = ?", []interface{}{dataMigrationKind}))
+	require.Len(recorded, 1, "the failed migration must be rolled back")
+	assert.Equal("test", recorded[0].Name)
+}
+
+func TestRegisterDataMigration_Validation(t *testing.T) {
+	t.Parallel()
+	up := func(context.Context, Reader, Writer) error { return nil }
+	tests := []struct {
+		name    string
+		dialect string
+		m       DataMigration
+	}{
+		{name: "no-dialect", m: DataMigration{Version: MinDataMigrationVersion, Name: "m", Up: up}},
+		{name: "low-version", dialect: "postgres", m: DataMigration{Version: MinDataMigrationVersion - 1, Name: "m", Up: up}},
+		{name: "no-name", dialect: "postgres", m: DataMigration{Version: MinDataMigrationVersion, Up: up}},
+		{name: "no-up", dialect: "postgres", m: DataMigration{Version: MinDataMigrationVersion, Name: "m"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterDataMigration(tt.dialect, tt.m)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, errors.ErrInvalidParameter))
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package db
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/jinzhu/gorm"
+)
+
+// DefaultStatementCacheMaxEntries is the maximum number of prepared statements
+// a StatementCache holds when no maximum is given.
+const DefaultStatementCacheMaxEntries = 500
+
+// StatementCache caches the prepared statements of the raw queries run by
+// Exec and Query, keyed by their text, so the queries run most often are
+// planned once per connection rather than on every call. database/sql
+// prepares a cached statement again on every connection of the pool it is
+// used on. Only queries with postgres placeholders ($1) are cached, since
+// those with ? placeholders are rewritten on every call. Once full, the least
+// recently used statement is evicted. It is safe for concurrent use.
+type StatementCache struct {
+	underlying *sql.DB
+	maxEntries int
+
+	hits   uint64
+	misses uint64
+
+	lock sync.Mutex
+	// stmts holds the elements of lru by query; lru holds the cached
+	// statements, the most recently used first
+	stmts map[string]*list.Element
+	lru   *list.List
+}
+
+// cachedStatement is a statement of a StatementCache. It is closed once it is
+// evicted and no caller uses it anymore.
+type cachedStatement struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// NewStatementCache creates a StatementCache for the underlying connection
+// holding at most maxEntries statements, or
+// DefaultStatementCacheMaxEntries if it is not positive.
+func NewStatementCache(underlying *gorm.DB, maxEntries int) (*StatementCache, error) {
+	if underlying == nil {
+		return nil, fmt.Errorf("new statement cache: missing underlying db: %w", errors.ErrInvalidParameter)
+	}
+	sqlDb := underlying.DB()
+	if sqlDb == nil {
+		return nil, fmt.Errorf("new statement cache: underlying db is not a connection pool: %w", errors.ErrInvalidParameter)
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultStatementCacheMaxEntries
+	}
+	return &StatementCache{
+		underlying: sqlDb,
+		maxEntries: maxEntries,
+		stmts:      make(map[string]*list.Element),
+		lru:        list.New(),
+	}, nil
+}
+
+// cacheable returns true if the query can be run with a prepared statement.
+func (c *StatementCache) cacheable(query string) bool {
+	return c != nil && !strings.Contains(query, "?")
+}
+
+// acquire returns the prepared statement of the query, preparing it on a
+// miss and evicting the least recently used statement if the cache is full.
+// The statement must be released once it is used.
+func (c *StatementCache) acquire(ctx context.Context, query string) (*cachedStatement, error) {
+	c.lock.Lock()
+	if e, ok := c.stmts[query]; ok {
+		c.lru.MoveToFront(e)
+		cs := e.Value.(*cachedStatement)
+		cs.refs++
+		c.lock.Unlock()
+		atomic.AddUint64(&c.hits, 1)
+		metrics.IncrCounter([]string{"db", "statement_cache", "hit"}, 1)
+		return cs, nil
+	}
+	c.lock.Unlock()
+	atomic.AddUint64(&c.misses, 1)
+	metrics.IncrCounter([]string{"db", "statement_cache", "miss"}, 1)
+
+	s, err := c.underlying.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	c.lock.Lock()
+	if e, ok := c.stmts[query]; ok {
+		// Prepared concurrently by another caller
+		c.lru.MoveToFront(e)
+		cs := e.Value.(*cachedStatement)
+		cs.refs++
+		c.lock.Unlock()
+		s.Close()
+		return cs, nil
+	}
+	cs := &cachedStatement{query: query, stmt: s, refs: 1}
+	c.stmts[query] = c.lru.PushFront(cs)
+	var evicted []*sql.Stmt
+	for c.lru.Len() > c.maxEntries {
+		if s := c.evictLocked(c.lru.Back()); s != nil {
+			evicted = append(evicted, s)
+		}
+		metrics.IncrCounter([]string{"db", "statement_cache", "eviction"}, 1)
+	}
+	c.lock.Unlock()
+	for _, s := range evicted {
+		s.Close()
+	}
+	return cs, nil
+}
+
+// evictLocked removes the statement of e from the cache. It returns the
+// statement if no caller uses it anymore, so it must be closed. c.lock must be
+// held.
+func (c *StatementCache) evictLocked(e *list.Element) *sql.Stmt {
+	cs := c.lru.Remove(e).(*cachedStatement)
+	delete(c.stmts, cs.query)
+	cs.evicted = true
+	if cs.refs > 0 {
+		return nil
+	}
+	return cs.stmt
+}
+
+// release marks the statement as no longer used by a caller of acquire,
+// closing it if it was evicted meanwhile. Results of the statement still open,
+// like rows, stay valid until they are closed.
+func (c *StatementCache) release(cs *cachedStatement) {
+	c.lock.Lock()
+	cs.refs--
+	closeStmt := cs.evicted && cs.refs == 0
+	c.lock.Unlock()
+	if closeStmt {
+		cs.stmt.Close()
+	}
+}
+
+// Len returns the number of cached statements.
+func (c *StatementCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.lru.Len()
+}
+
+// Stats returns the number of queries run with a cached statement and those
+// which were not cached yet.
+func (c *StatementCache) Stats() (hits, misses uint64) {
+	if c == nil {
+		return 0, 0
+	}
+	return atomic.LoadUint64(&c.hits), atomic.LoadUint64(&c.misses)
+}
+
+// Close closes the cached statements and empties the cache. Statements in use
+// are closed once they are released.
+func (c *StatementCache) Close() error {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	var unused []*sql.Stmt
+	for c.lru.Len() > 0 {
+		if s := c.evictLocked(c.lru.Front()); s != nil {
+			unused = append(unused, s)
+		}
+	}
+	c.lock.Unlock()
+	var firstErr error
+	for _, s := range unused {
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// cachedStmt returns the cached statement of the query for the db, within its
+// transaction if it has one, or nil if the query isn't run with a cached
+// statement. Statements of a transaction are closed when it ends. release must
+// be called once the statement was run, unless it is nil.
+func (rw *Db) cachedStmt(ctx context.Context, query string) (stmt *sql.Stmt, release func(), err error) {
+	if !rw.stmtCache.cacheable(query) {
+		return nil, nil, nil
+	}
+	var tx *sql.Tx
+	switch common := rw.underlying.CommonDB().(type) {
+	case *sql.Tx:
+		tx = common
+	case *sql.DB:
+		if common != rw.stmtCache.underlying {
+			return nil, nil, nil
+		}
+	default:
+		return nil, nil, nil
+	}
+	cs, err := rw.stmtCache.acquire(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	release = func() { rw.stmtCache.release(cs) }
+	if tx != nil {
+		return tx.StmtContext(ctx, cs.stmt), release, nil
+	}
+	return cs.stmt, release, nil
+}
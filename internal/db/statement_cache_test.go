@@ -0,0 +1,180 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db/db_test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementCache(t *testing.T) {
+	t.Parallel()
+	conn, _ := TestSetup(t, "postgres")
+	ctx := context.Background()
+
+	t.Run("exec-and-query", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		c, err := NewStatementCache(conn, 2)
+		require.NoError(err)
+		defer c.Close()
+		rw := New(conn, WithStatementCache(c))
+		user := testUser(t, conn, "cached", "", "")
+
+		const update = "update db_test_user set name = $1 where public_id = $2"
+		n, err := rw.Exec(ctx, update, []interface{}{"cached-1", user.PublicId})
+		require.NoError(err)
+		assert.Equal(1, n)
+		n, err = rw.Exec(ctx, update, []interface{}{"cached-2", user.PublicId})
+		require.NoError(err)
+		assert.Equal(1, n)
+		hits, misses := c.Stats()
+		assert.Equal(uint64(1), hits)
+		assert.Equal(uint64(1), misses)
+
+		// Statements are used within transactions too
+		_, err = rw.DoTx(ctx, StdRetryCnt, ExpBackoff{}, func(_ Reader, w Writer) error {
+			_, err := w.Exec(ctx, update, []interface{}{"cached-3", user.PublicId})
+			return err
+		})
+		require.NoError(err)
+		hits, _ = c.Stats()
+		assert.Equal(uint64(2), hits)
+
+		const lookup = "select name from db_test_user where public_id = $1"
+		for i := 0; i < 2; i++ {
+			rows, err := rw.Query(ctx, lookup, []interface{}{user.PublicId})
+			require.NoError(err)
+			var names []string
+			for rows.Next() {
+				var name string
+				require.NoError(rows.Scan(&name))
+				names = append(names, name)
+			}
+			require.NoError(rows.Err())
+			rows.Close()
+			assert.Equal([]string{"cached-3"}, names)
+		}
+		hits, misses = c.Stats()
+		assert.Equal(uint64(3), hits)
+		assert.Equal(uint64(2), misses)
+	})
+	t.Run("not-cached", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		c, err := NewStatementCache(conn, 1)
+		require.NoError(err)
+		defer c.Close()
+		rw := New(conn, WithStatementCache(c))
+		user := testUser(t, conn, "not-cached", "", "")
+
+		// Queries with ? placeholders are rewritten, so they aren't cached
+		_, err = rw.Exec(ctx, "update db_test_user set name = ? where public_id = ?", []interface{}{"not-cached-1", user.PublicId})
+		require.NoError(err)
+		hits, misses := c.Stats()
+		assert.Zero(hits)
+		assert.Zero(misses)
+
+		// Once the cache is full, the least recently used statement is
+		// evicted
+		const updateName = "update db_test_user set name = $1 where public_id = $2"
+		const updateEmail = "update db_test_user set email = $1 where public_id = $2"
+		_, err = rw.Exec(ctx, updateName, []interface{}{"not-cached-2", user.PublicId})
+		require.NoError(err)
+		_, err = rw.Exec(ctx, updateEmail, []interface{}{"not-cached@example.com", user.PublicId})
+		require.NoError(err)
+		_, err = rw.Exec(ctx, updateEmail, []interface{}{"not-cached@example.com", user.PublicId})
+		require.NoError(err)
+		hits, misses = c.Stats()
+		assert.Equal(uint64(1), hits)
+		assert.Equal(uint64(2), misses)
+		assert.Equal(1, c.Len())
+		_, err = rw.Exec(ctx, updateName, []interface{}{"not-cached-2", user.PublicId})
+		require.NoError(err)
+		hits, misses = c.Stats()
+		assert.Equal(uint64(1), hits)
+		assert.Equal(uint64(3), misses)
+
+		found := &db_test.TestUser{StoreTestUser: &db_test.StoreTestUser{PublicId: user.PublicId}}
+		require.NoError(rw.LookupByPublicId(ctx, found))
+		assert.Equal("not-cached-2", found.Name)
+		assert.Equal("not-cached@example.com", found.Email)
+	})
+	t.Run("evicted-in-use", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		c, err := NewStatementCache(conn, 1)
+		require.NoError(err)
+		defer c.Close()
+		rw := New(conn, WithStatementCache(c))
+		user := testUser(t, conn, "evicted-in-use", "", "")
+
+		// Rows of an evicted statement can still be read
+		rows, err := rw.Query(ctx, "select name from db_test_user where public_id = $1", []interface{}{user.PublicId})
+		require.NoError(err)
+		_, err = rw.Exec(ctx, "update db_test_user set email = $1 where public_id = $2", []interface{}{"evicted@example.com", user.PublicId})
+		require.NoError(err)
+		assert.Equal(1, c.Len())
+		var names []string
+		for rows.Next() {
+			var name string
+			require.NoError(rows.Scan(&name))
+			names = append(names, name)
+		}
+		require.NoError(rows.Err())
+		rows.Close()
+		assert.Equal([]string{"evicted-in-use"}, names)
+
+		// An acquired statement is closed once released after eviction
+		cs, err := c.acquire(ctx, "select email from db_test_user where public_id = $1")
+		require.NoError(err)
+		require.NoError(c.Close())
+		assert.Zero(c.Len())
+		rows, err = cs.stmt.QueryContext(ctx, user.PublicId)
+		require.NoError(err)
+		rows.Close()
+		c.release(cs)
+		_, err = cs.stmt.QueryContext(ctx, user.PublicId)
+		assert.Error(err)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewStatementCache(nil, 0)
+		assert.Error(t, err)
+	})
+}
+
+// BenchmarkStatementCache compares running a query with and without a
+// prepared statement.
+func BenchmarkStatementCache(b *testing.B) {
+	conn, _ := TestSetup(b, "postgres")
+	ctx := context.Background()
+	const query = `
+select u.public_id, u.name
+  from db_test_user u
+ where u.public_id = $1
+   and u.name is not null
+ order by u.public_id
+`
+	run := func(b *testing.B, rw *Db) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			rows, err := rw.Query(ctx, query, []interface{}{"u_1234567890"})
+			if err != nil {
+				b.Fatal(err)
+			}
+			rows.Close()
+		}
+	}
+	b.Run("uncached", func(b *testing.B) {
+		run(b, New(conn))
+	})
+	b.Run("cached", func(b *testing.B) {
+		c, err := NewStatementCache(conn, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer c.Close()
+		run(b, New(conn, WithStatementCache(c)))
+		hits, misses := c.Stats()
+		b.ReportMetric(float64(hits)/float64(hits+misses), "hit-rate")
+	})
+}
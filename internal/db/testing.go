@@ -19,7 +19,7 @@ import (
 )
 
 // setup the tests (initialize the database one-time and intialized testDatabaseURL). Do not close the returned db.
-func TestSetup(t *testing.T, dialect string, opt ...TestOption) (*gorm.DB, string) {
+func TestSetup(t testing.TB, dialect string, opt ...TestOption) (*gorm.DB, string) {
 	var cleanup func() error
 	var url string
 	var err error
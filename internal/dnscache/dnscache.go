@@ -0,0 +1,203 @@
+// Package dnscache caches the host name lookups of controllers and workers,
+// so a spike of connections to the same endpoints or upstreams makes one
+// lookup per host rather than one per connection. Concurrent lookups of a
+// host which isn't cached are coalesced into one.
+//
+// The Go resolver doesn't return the TTLs of the records it looks up, so
+// answers are cached for the configured TTL and failed lookups for the
+// configured negative TTL, which keeps a failing resolver from being retried
+// on every connection. Hits, misses and failures are counted in metrics under
+// dns_cache, and the time of lookups is measured as dns_cache.lookup.
+package dnscache
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/armon/go-metrics"
+)
+
+const (
+	// DefaultTtl is how long answers are cached when no TTL is given.
+	DefaultTtl = 30 * time.Second
+
+	// DefaultNegativeTtl is how long failed lookups are cached when no
+	// negative TTL is given.
+	DefaultNegativeTtl = 5 * time.Second
+
+	// maxEntries is the number of cached hosts above which expired entries
+	// are removed when a host is added.
+	maxEntries = 4096
+)
+
+// A LookupFunc looks up the addresses of a host, as net.Resolver.LookupIPAddr.
+type LookupFunc func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+// entry is a cached lookup. done is closed once the lookup finished and ips,
+// err and expires are set.
+type entry struct {
+	done    chan struct{}
+	ips     []net.IPAddr
+	err     error
+	expires time.Time
+}
+
+// A Resolver caches the lookups of host names. It is safe for concurrent use.
+// A nil Resolver looks up every host with net.DefaultResolver.
+type Resolver struct {
+	lookup      LookupFunc
+	ttl         time.Duration
+	negativeTtl time.Duration
+
+	hits     uint64
+	misses   uint64
+	failures uint64
+
+	lock    sync.Mutex
+	entries map[string]*entry
+}
+
+// NewResolver returns a Resolver caching answers for ttl, or DefaultTtl if
+// it is not positive, and failed lookups for negativeTtl, or
+// DefaultNegativeTtl if it is zero. Failed lookups are not cached if
+// negativeTtl is negative.
+func NewResolver(ttl, negativeTtl time.Duration) *Resolver {
+	if ttl <= 0 {
+		ttl = DefaultTtl
+	}
+	if negativeTtl == 0 {
+		negativeTtl = DefaultNegativeTtl
+	}
+	return &Resolver{
+		lookup:      net.DefaultResolver.LookupIPAddr,
+		ttl:         ttl,
+		negativeTtl: negativeTtl,
+		entries:     make(map[string]*entry),
+	}
+}
+
+// LookupIPAddr returns the addresses of the host, from the cache if it was
+// looked up within its TTL. IP addresses are returned as is. A lookup made
+// for a context which is canceled before it finishes isn't cached.
+func (r *Resolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IPAddr{{IP: ip}}, nil
+	}
+	if r == nil {
+		return net.DefaultResolver.LookupIPAddr(ctx, host)
+	}
+
+	r.lock.Lock()
+	e, ok := r.entries[host]
+	if ok {
+		select {
+		case <-e.done:
+			if time.Now().After(e.expires) {
+				ok = false
+			}
+		default:
+			// A lookup of the host is in flight, which is waited for below
+		}
+	}
+	if ok {
+		r.lock.Unlock()
+		atomic.AddUint64(&r.hits, 1)
+		metrics.IncrCounter([]string{"dns_cache", "hit"}, 1)
+		select {
+		case <-e.done:
+			return e.ips, e.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	e = &entry{done: make(chan struct{})}
+	r.add(host, e)
+	r.lock.Unlock()
+	atomic.AddUint64(&r.misses, 1)
+	metrics.IncrCounter([]string{"dns_cache", "miss"}, 1)
+
+	start := time.Now()
+	e.ips, e.err = r.lookup(ctx, host)
+	metrics.MeasureSince([]string{"dns_cache", "lookup"}, start)
+	ttl := r.ttl
+	if e.err != nil {
+		atomic.AddUint64(&r.failures, 1)
+		metrics.IncrCounter([]string{"dns_cache", "failure"}, 1)
+		ttl = r.negativeTtl
+		if ctx.Err() != nil {
+			// The lookup didn't fail for the host; waiters get the error but
+			// the next lookup is made again
+			ttl = -1
+		}
+	}
+	e.expires = time.Now().Add(ttl)
+	close(e.done)
+	return e.ips, e.err
+}
+
+// add caches the entry of the host, first removing the expired entries if
+// the cache is full. The lock must be held.
+func (r *Resolver) add(host string, e *entry) {
+	if len(r.entries) >= maxEntries {
+		now := time.Now()
+		for h, old := range r.entries {
+			select {
+			case <-old.done:
+				if now.After(old.expires) {
+					delete(r.entries, h)
+				}
+			default:
+			}
+		}
+	}
+	r.entries[host] = e
+}
+
+// DialContext returns a dial function, e.g. for an http.Transport, dialing
+// the addresses of the host of addr in the order they were looked up with d,
+// until one connects.
+func (r *Resolver) DialContext(d *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if r == nil {
+			return d.DialContext(ctx, network, addr)
+		}
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return d.DialContext(ctx, network, addr)
+		}
+		ips, err := r.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var firstErr error
+		for _, ip := range ips {
+			conn, err := d.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		}
+		if firstErr == nil {
+			firstErr = fmt.Errorf("no addresses found for %s", host)
+		}
+		return nil, firstErr
+	}
+}
+
+// Stats returns the number of lookups answered from the cache, those which
+// were not cached and those of the latter which failed.
+func (r *Resolver) Stats() (hits, misses, failures uint64) {
+	if r == nil {
+		return 0, 0, 0
+	}
+	return atomic.LoadUint64(&r.hits), atomic.LoadUint64(&r.misses), atomic.LoadUint64(&r.failures)
+}
@@ -0,0 +1,156 @@
+package dnscache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_LookupIPAddr(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	addrs := []net.IPAddr{{IP: net.ParseIP("10.0.0.1")}}
+	errLookup := errors.New("lookup failed")
+
+	t.Run("cached", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		r := NewResolver(time.Hour, 0)
+		var lookups int32
+		r.lookup = func(context.Context, string) ([]net.IPAddr, error) {
+			atomic.AddInt32(&lookups, 1)
+			return addrs, nil
+		}
+		for i := 0; i < 3; i++ {
+			got, err := r.LookupIPAddr(ctx, "db.example.com")
+			require.NoError(err)
+			assert.Equal(addrs, got)
+		}
+		assert.Equal(int32(1), atomic.LoadInt32(&lookups))
+		hits, misses, failures := r.Stats()
+		assert.Equal(uint64(2), hits)
+		assert.Equal(uint64(1), misses)
+		assert.Zero(failures)
+	})
+	t.Run("expired", func(t *testing.T) {
+		assert := assert.New(t)
+		r := NewResolver(time.Nanosecond, 0)
+		var lookups int32
+		r.lookup = func(context.Context, string) ([]net.IPAddr, error) {
+			atomic.AddInt32(&lookups, 1)
+			return addrs, nil
+		}
+		_, err := r.LookupIPAddr(ctx, "db.example.com")
+		assert.NoError(err)
+		time.Sleep(time.Millisecond)
+		_, err = r.LookupIPAddr(ctx, "db.example.com")
+		assert.NoError(err)
+		assert.Equal(int32(2), atomic.LoadInt32(&lookups))
+	})
+	t.Run("negative", func(t *testing.T) {
+		assert := assert.New(t)
+		r := NewResolver(time.Hour, time.Hour)
+		var lookups int32
+		r.lookup = func(context.Context, string) ([]net.IPAddr, error) {
+			atomic.AddInt32(&lookups, 1)
+			return nil, errLookup
+		}
+		for i := 0; i < 2; i++ {
+			_, err := r.LookupIPAddr(ctx, "missing.example.com")
+			assert.Equal(errLookup, err)
+		}
+		assert.Equal(int32(1), atomic.LoadInt32(&lookups))
+		_, _, failures := r.Stats()
+		assert.Equal(uint64(1), failures)
+	})
+	t.Run("negative-disabled", func(t *testing.T) {
+		assert := assert.New(t)
+		r := NewResolver(time.Hour, -1)
+		var lookups int32
+		r.lookup = func(context.Context, string) ([]net.IPAddr, error) {
+			atomic.AddInt32(&lookups, 1)
+			return nil, errLookup
+		}
+		for i := 0; i < 2; i++ {
+			_, err := r.LookupIPAddr(ctx, "missing.example.com")
+			assert.Equal(errLookup, err)
+		}
+		assert.Equal(int32(2), atomic.LoadInt32(&lookups))
+	})
+	t.Run("coalesced", func(t *testing.T) {
+		assert := assert.New(t)
+		r := NewResolver(time.Hour, 0)
+		var lookups int32
+		release := make(chan struct{})
+		r.lookup = func(context.Context, string) ([]net.IPAddr, error) {
+			atomic.AddInt32(&lookups, 1)
+			<-release
+			return addrs, nil
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				got, err := r.LookupIPAddr(ctx, "db.example.com")
+				assert.NoError(err)
+				assert.Equal(addrs, got)
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		assert.Equal(int32(1), atomic.LoadInt32(&lookups))
+	})
+	t.Run("ip", func(t *testing.T) {
+		assert := assert.New(t)
+		r := NewResolver(time.Hour, 0)
+		r.lookup = func(context.Context, string) ([]net.IPAddr, error) {
+			t.Fatal("unexpected lookup")
+			return nil, nil
+		}
+		got, err := r.LookupIPAddr(ctx, "10.0.0.2")
+		assert.NoError(err)
+		assert.Equal([]net.IPAddr{{IP: net.ParseIP("10.0.0.2")}}, got)
+	})
+}
+
+func TestResolver_DialContext(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	require.NoError(err)
+
+	r := NewResolver(time.Hour, 0)
+	r.lookup = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		assert.Equal("endpoint.example.com", host)
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+	dial := r.DialContext(&net.Dialer{})
+	for i := 0; i < 2; i++ {
+		conn, err := dial(context.Background(), "tcp", net.JoinHostPort("endpoint.example.com", port))
+		require.NoError(err)
+		conn.Close()
+	}
+	hits, misses, _ := r.Stats()
+	assert.Equal(uint64(1), hits)
+	assert.Equal(uint64(1), misses)
+}
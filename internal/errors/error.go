@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/boundary/internal/libs/redact"
 	"github.com/lib/pq"
 )
 
@@ -126,7 +127,8 @@ func Convert(e error) *Err {
 		if pqError.Code.Class() == "23" { // class of integrity constraint violations
 			switch pqError.Code {
 			case "23505": // unique_violation
-				return E(WithMsg(pqError.Detail), WithWrap(ErrNotUnique)).(*Err)
+				// The detail has the duplicate values, which may be secrets
+				return E(WithMsg(redact.KeyDetail(pqError.Detail)), WithWrap(ErrNotUnique)).(*Err)
 			case "23502": // not_null_violation
 				msg := fmt.Sprintf("%s must not be empty", pqError.Column)
 				return E(WithMsg(msg), WithWrap(ErrNotNull)).(*Err)
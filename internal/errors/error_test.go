@@ -453,6 +453,16 @@ func TestConvertError(t *testing.T) {
 		assert.True(errors.Is(e, errors.ErrNotUnique))
 		assert.Equal("Key (name)=(alice) already exists.: unique constraint violation: integrity violation: error #1002", e.Error())
 	})
+	t.Run("ErrCodeUniqueSensitive", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		e := errors.Convert(&pq.Error{
+			Code:   pq.ErrorCode("23505"),
+			Detail: "Key (tofu_token)=(s3cr3t-t0ken) already exists.",
+		})
+		require.NotNil(e)
+		assert.True(errors.Is(e, errors.ErrNotUnique))
+		assert.Equal("Key (tofu_token)=([REDACTED]) already exists.: unique constraint violation: integrity violation: error #1002", e.Error())
+	})
 	t.Run("ErrCodeNotNull", func(t *testing.T) {
 		assert, require := assert.New(t), require.New(t)
 		_, err := rw.Exec(ctx, truncateTable, nil)
@@ -0,0 +1,222 @@
+// Package eventschema is the registry of the versioned JSON schemas of the
+// payloads of the events Boundary emits to external consumers: outbox
+// messages, e.g. webhooks, and worker connection records. The controller
+// serves them at /events/schemas so consumers can validate payloads. Each
+// payload has a schema_version property with the version it conforms to.
+//
+// Published versions of a schema never change. A new version must be
+// compatible with the previous one: every payload valid against the new
+// version must be valid against the previous one, so consumers of the
+// previous version keep working. That allows adding properties, but not
+// removing or changing them or adding enum values. Changes which aren't
+// compatible need a new kind.
+package eventschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// A Schema is a version of the JSON schema of the payloads of an event kind.
+type Schema struct {
+	Kind    string
+	Version int
+	// Document is the JSON schema
+	Document string
+}
+
+// Kinds returns the kinds of events with schemas, sorted.
+func Kinds() []string {
+	kinds := make([]string, 0, len(schemas))
+	for kind := range schemas {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Versions returns the schemas of the kind, oldest first, or nil if the kind
+// has no schema.
+func Versions(kind string) []*Schema {
+	docs := schemas[kind]
+	versions := make([]*Schema, 0, len(docs))
+	for i, doc := range docs {
+		versions = append(versions, &Schema{Kind: kind, Version: i + 1, Document: doc})
+	}
+	return versions
+}
+
+// Lookup returns the version of the schema of the kind, or nil if it does
+// not exist.
+func Lookup(kind string, version int) *Schema {
+	docs := schemas[kind]
+	if version < 1 || version > len(docs) {
+		return nil
+	}
+	return &Schema{Kind: kind, Version: version, Document: docs[version-1]}
+}
+
+// Latest returns the latest version of the schema of the kind, or nil if the
+// kind has no schema.
+func Latest(kind string) *Schema {
+	return Lookup(kind, len(schemas[kind]))
+}
+
+// Validate returns an error if the payload isn't valid against the schema.
+// Only the subset of JSON schema used by the registered schemas is
+// supported.
+func (s *Schema) Validate(payload []byte) error {
+	doc, err := s.parse()
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+	return doc.validate("payload", v)
+}
+
+func (s *Schema) parse() (*schemaDoc, error) {
+	var doc schemaDoc
+	if err := json.Unmarshal([]byte(s.Document), &doc); err != nil {
+		return nil, fmt.Errorf("invalid schema %s v%d: %w", s.Kind, s.Version, err)
+	}
+	return &doc, nil
+}
+
+// schemaDoc is the subset of JSON schema used by the registered schemas.
+type schemaDoc struct {
+	Type                 string                `json:"type"`
+	Format               string                `json:"format,omitempty"`
+	Enum                 []interface{}         `json:"enum,omitempty"`
+	Properties           map[string]*schemaDoc `json:"properties,omitempty"`
+	Required             []string              `json:"required,omitempty"`
+	AdditionalProperties *bool                 `json:"additionalProperties,omitempty"`
+	Items                *schemaDoc            `json:"items,omitempty"`
+}
+
+func (d *schemaDoc) validate(path string, v interface{}) error {
+	switch d.Type {
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: must be an object", path)
+		}
+		for _, name := range d.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		for name, pv := range obj {
+			p, ok := d.Properties[name]
+			switch {
+			case ok:
+				if err := p.validate(path+"."+name, pv); err != nil {
+					return err
+				}
+			case d.AdditionalProperties != nil && !*d.AdditionalProperties:
+				return fmt.Errorf("%s: unknown property %q", path, name)
+			}
+		}
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: must be an array", path)
+		}
+		if d.Items != nil {
+			for i, iv := range arr {
+				if err := d.Items.validate(fmt.Sprintf("%s[%d]", path, i), iv); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%s: must be a string", path)
+		}
+	case "integer":
+		if n, ok := v.(float64); !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s: must be an integer", path)
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s: must be a number", path)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: must be a boolean", path)
+		}
+	}
+	if len(d.Enum) > 0 && !containsValue(d.Enum, v) {
+		return fmt.Errorf("%s: %v is not one of %v", path, v, d.Enum)
+	}
+	return nil
+}
+
+// compatible returns an error if a payload valid against next may not be
+// valid against prev.
+func compatible(path string, prev, next *schemaDoc) error {
+	if prev.Type != next.Type {
+		return fmt.Errorf("%s: type changed from %q to %q", path, prev.Type, next.Type)
+	}
+	if prev.Format != next.Format {
+		return fmt.Errorf("%s: format changed from %q to %q", path, prev.Format, next.Format)
+	}
+	if len(prev.Enum) > 0 {
+		if len(next.Enum) == 0 {
+			return fmt.Errorf("%s: enum removed", path)
+		}
+		for _, v := range next.Enum {
+			if !containsValue(prev.Enum, v) {
+				return fmt.Errorf("%s: enum value %v added", path, v)
+			}
+		}
+	}
+	for _, name := range prev.Required {
+		if !containsValue(toValues(next.Required), name) {
+			return fmt.Errorf("%s: property %q is no longer required", path, name)
+		}
+	}
+	for name, p := range prev.Properties {
+		np, ok := next.Properties[name]
+		if !ok {
+			return fmt.Errorf("%s: property %q removed", path, name)
+		}
+		if err := compatible(path+"."+name, p, np); err != nil {
+			return err
+		}
+	}
+	if prev.AdditionalProperties != nil && !*prev.AdditionalProperties {
+		for name := range next.Properties {
+			if _, ok := prev.Properties[name]; !ok {
+				return fmt.Errorf("%s: property %q added but additional properties were not allowed", path, name)
+			}
+		}
+	}
+	switch {
+	case prev.Items != nil && next.Items != nil:
+		return compatible(path+"[]", prev.Items, next.Items)
+	case prev.Items != nil:
+		return fmt.Errorf("%s: items removed", path)
+	}
+	return nil
+}
+
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, ev := range values {
+		if ev == v {
+			return true
+		}
+	}
+	return false
+}
+
+func toValues(ss []string) []interface{} {
+	values := make([]interface{}, 0, len(ss))
+	for _, s := range ss {
+		values = append(values, s)
+	}
+	return values
+}
@@ -0,0 +1,347 @@
+package eventschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/secretfingerprint"
+	"github.com/hashicorp/boundary/internal/securityevent"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSchemas enforces the compatibility policy: published versions never
+// change, and each version is compatible with the previous one. A new version
+// must be added to testdata along with the registry.
+func TestSchemas(t *testing.T) {
+	for _, kind := range Kinds() {
+		t.Run(kind, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			versions := Versions(kind)
+			require.NotEmpty(versions)
+			var prev *schemaDoc
+			for _, s := range versions {
+				golden, err := ioutil.ReadFile(filepath.Join("testdata", fmt.Sprintf("%s.v%d.json", kind, s.Version)))
+				require.NoError(err, "published schemas must be in testdata")
+				assert.Equal(strings.TrimSpace(string(golden)), s.Document, "published schema %s v%d changed", kind, s.Version)
+
+				var meta struct {
+					Id string `json:"$id"`
+				}
+				require.NoError(json.Unmarshal([]byte(s.Document), &meta))
+				assert.Equal(fmt.Sprintf("/events/schemas/%s/v%d", kind, s.Version), meta.Id)
+
+				doc, err := s.parse()
+				require.NoError(err)
+				assert.Equal("object", doc.Type)
+				assert.Contains(doc.Required, "schema_version")
+				if prev != nil {
+					assert.NoError(compatible("payload", prev, doc), "%s v%d is not compatible with v%d", kind, s.Version, s.Version-1)
+				}
+				prev = doc
+			}
+			assert.Equal(versions[len(versions)-1], Latest(kind))
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	assert := assert.New(t)
+	s := Lookup(iam.AccessRequestEventKind, iam.AccessRequestEventSchemaVersion)
+	if assert.NotNil(s) {
+		assert.Equal(iam.AccessRequestEventKind, s.Kind)
+		assert.Equal(iam.AccessRequestEventSchemaVersion, s.Version)
+	}
+	assert.Nil(Lookup(iam.AccessRequestEventKind, 0))
+	assert.Nil(Lookup(iam.AccessRequestEventKind, 100))
+	assert.Nil(Lookup("unknown", 1))
+	assert.Nil(Latest("unknown"))
+	assert.Empty(Versions("unknown"))
+}
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		name    string
+		prev    string
+		next    string
+		wantErr string
+	}{
+		{
+			name: "unchanged",
+			prev: `{"type": "object", "properties": {"a": {"type": "string"}}, "required": ["a"]}`,
+			next: `{"type": "object", "properties": {"a": {"type": "string"}}, "required": ["a"]}`,
+		},
+		{
+			name: "property-added",
+			prev: `{"type": "object", "properties": {"a": {"type": "string"}}}`,
+			next: `{"type": "object", "properties": {"a": {"type": "string"}, "b": {"type": "integer"}}, "required": ["b"]}`,
+		},
+		{
+			name: "enum-value-removed",
+			prev: `{"type": "object", "properties": {"a": {"type": "string", "enum": ["x", "y"]}}}`,
+			next: `{"type": "object", "properties": {"a": {"type": "string", "enum": ["x"]}}}`,
+		},
+		{
+			name:    "property-removed",
+			prev:    `{"type": "object", "properties": {"a": {"type": "string"}}}`,
+			next:    `{"type": "object", "properties": {}}`,
+			wantErr: `property "a" removed`,
+		},
+		{
+			name:    "type-changed",
+			prev:    `{"type": "object", "properties": {"a": {"type": "string"}}}`,
+			next:    `{"type": "object", "properties": {"a": {"type": "integer"}}}`,
+			wantErr: "payload.a: type changed",
+		},
+		{
+			name:    "format-changed",
+			prev:    `{"type": "object", "properties": {"a": {"type": "string", "format": "date-time"}}}`,
+			next:    `{"type": "object", "properties": {"a": {"type": "string"}}}`,
+			wantErr: "payload.a: format changed",
+		},
+		{
+			name:    "no-longer-required",
+			prev:    `{"type": "object", "properties": {"a": {"type": "string"}}, "required": ["a"]}`,
+			next:    `{"type": "object", "properties": {"a": {"type": "string"}}}`,
+			wantErr: `property "a" is no longer required`,
+		},
+		{
+			name:    "enum-value-added",
+			prev:    `{"type": "object", "properties": {"a": {"type": "string", "enum": ["x"]}}}`,
+			next:    `{"type": "object", "properties": {"a": {"type": "string", "enum": ["x", "y"]}}}`,
+			wantErr: "enum value y added",
+		},
+		{
+			name:    "nested-items",
+			prev:    `{"type": "object", "properties": {"a": {"type": "array", "items": {"type": "string"}}}}`,
+			next:    `{"type": "object", "properties": {"a": {"type": "array", "items": {"type": "boolean"}}}}`,
+			wantErr: "payload.a[]: type changed",
+		},
+		{
+			name:    "closed-object",
+			prev:    `{"type": "object", "properties": {}, "additionalProperties": false}`,
+			next:    `{"type": "object", "properties": {"a": {"type": "string"}}}`,
+			wantErr: `property "a" added`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			var prev, next schemaDoc
+			require.NoError(json.Unmarshal([]byte(tt.prev), &prev))
+			require.NoError(json.Unmarshal([]byte(tt.next), &next))
+			err := compatible("payload", &prev, &next)
+			if tt.wantErr != "" {
+				require.Error(err)
+				assert.Contains(err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	s := &Schema{
+		Kind:    "test",
+		Version: 1,
+		Document: `{
+  "type": "object",
+  "properties": {
+    "id": {"type": "string"},
+    "count": {"type": "integer"},
+    "state": {"type": "string", "enum": ["on", "off"]},
+    "tags": {"type": "array", "items": {"type": "string"}}
+  },
+  "required": ["id"]
+}`,
+	}
+	tests := []struct {
+		name    string
+		payload string
+		wantErr string
+	}{
+		{name: "valid", payload: `{"id": "a", "count": 2, "state": "on", "tags": ["x"]}`},
+		{name: "unknown-property", payload: `{"id": "a", "other": true}`},
+		{name: "not-json", payload: `{`, wantErr: "invalid payload"},
+		{name: "not-object", payload: `[]`, wantErr: "payload: must be an object"},
+		{name: "missing-required", payload: `{"count": 2}`, wantErr: `missing required property "id"`},
+		{name: "wrong-type", payload: `{"id": 1}`, wantErr: "payload.id: must be a string"},
+		{name: "not-integer", payload: `{"id": "a", "count": 1.5}`, wantErr: "payload.count: must be an integer"},
+		{name: "not-in-enum", payload: `{"id": "a", "state": "dim"}`, wantErr: "payload.state: dim is not one of"},
+		{name: "wrong-item", payload: `{"id": "a", "tags": [1]}`, wantErr: "payload.tags[0]: must be a string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Validate([]byte(tt.payload))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestPayloads checks that the payloads emitted are valid against the latest
+// version of their schema and don't have properties missing from it.
+func TestPayloads(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	ctx := context.Background()
+
+	lastPayload := func(t *testing.T, kind string) []byte {
+		t.Helper()
+		rows, err := rw.Query(ctx, "select payload from outbox_message where kind = $1 order by id desc limit 1", []interface{}{kind})
+		require.NoError(t, err)
+		defer rows.Close()
+		var payload []byte
+		for rows.Next() {
+			require.NoError(t, rows.Scan(&payload))
+		}
+		require.NotNil(t, payload)
+		return payload
+	}
+
+	assertValid := func(t *testing.T, kind string, version int, payload []byte) {
+		t.Helper()
+		assert, require := assert.New(t), require.New(t)
+		s := Latest(kind)
+		require.NotNil(s)
+		assert.Equal(s.Version, version, "the payloads of %s must be of the latest version", kind)
+		assert.NoError(s.Validate(payload))
+
+		doc, err := s.parse()
+		require.NoError(err)
+		var obj map[string]interface{}
+		require.NoError(json.Unmarshal(payload, &obj))
+		for name := range obj {
+			assert.Contains(doc.Properties, name, "property %q of %s payloads is missing from the schema", name, kind)
+		}
+		assert.EqualValues(version, obj["schema_version"])
+	}
+
+	t.Run(iam.AccessRequestEventKind, func(t *testing.T) {
+		org, _ := iam.TestScopes(t, iamRepo)
+		role := iam.TestRole(t, conn, org.PublicId)
+		requester := iam.TestUser(t, iamRepo, org.PublicId)
+		_, err := iamRepo.CreateAccessRequest(ctx, role.PublicId, requester.PublicId, "on call", time.Hour)
+		require.NoError(t, err)
+		assertValid(t, iam.AccessRequestEventKind, iam.AccessRequestEventSchemaVersion, lastPayload(t, iam.AccessRequestEventKind))
+	})
+
+	t.Run(securityevent.OutboxKind, func(t *testing.T) {
+		repo, err := securityevent.NewRepository(rw, rw)
+		require.NoError(t, err)
+		require.NoError(t, repo.Record(ctx, &securityevent.Event{
+			Kind:         securityevent.AuthenticationFailed,
+			ScopeId:      "global",
+			AuthMethodId: "ampw_1234567890",
+		}))
+		assertValid(t, securityevent.OutboxKind, securityevent.SchemaVersion, lastPayload(t, securityevent.OutboxKind))
+	})
+
+	t.Run(secretfingerprint.OutboxKind, func(t *testing.T) {
+		repo, err := secretfingerprint.NewRepository(rw, rw, []byte("0123456789abcdef0123456789abcdef"))
+		require.NoError(t, err)
+		require.NoError(t, repo.Register(ctx, secretfingerprint.SessionPrivateKey, []byte("secret"), &secretfingerprint.Registration{
+			SessionId: "s_1234567890",
+			TargetId:  "ttcp_1234567890",
+			UserId:    "u_1234567890",
+			ScopeId:   "p_1234567890",
+		}))
+		assertValid(t, secretfingerprint.OutboxKind, secretfingerprint.SchemaVersion, lastPayload(t, secretfingerprint.OutboxKind))
+	})
+
+	t.Run(servers.ListenerAccessDeniedKind, func(t *testing.T) {
+		require := require.New(t)
+		serversRepo, err := servers.NewRepository(rw, rw, kms)
+		require.NoError(err)
+		now := time.Now()
+		require.NoError(serversRepo.RecordListenerAccessDenials(ctx, []*servers.ListenerAccessDenial{{
+			Controller: "c1",
+			Purpose:    "api",
+			RemoteAddr: "192.0.2.1",
+			Count:      3,
+			FirstTime:  now.Add(-time.Minute),
+			LastTime:   now,
+		}}))
+		assertValid(t, servers.ListenerAccessDeniedKind, servers.ListenerAccessDeniedSchemaVersion, lastPayload(t, servers.ListenerAccessDeniedKind))
+	})
+
+	t.Run(servers.WorkerAccessDeniedKind, func(t *testing.T) {
+		require := require.New(t)
+		serversRepo, err := servers.NewRepository(rw, rw, kms)
+		require.NoError(err)
+		now := time.Now()
+		require.NoError(serversRepo.RecordWorkerAccessDenials(ctx, []*servers.WorkerAccessDenial{{
+			Controller: "c1",
+			WorkerName: "rogue",
+			RemoteAddr: "192.0.2.1",
+			Reason:     "name",
+			Count:      2,
+			FirstTime:  now.Add(-time.Minute),
+			LastTime:   now,
+		}}))
+		assertValid(t, servers.WorkerAccessDeniedKind, servers.WorkerAccessDeniedSchemaVersion, lastPayload(t, servers.WorkerAccessDeniedKind))
+	})
+
+	t.Run(servers.ConfigDivergenceKind, func(t *testing.T) {
+		require := require.New(t)
+		serversRepo, err := servers.NewRepository(rw, rw, kms)
+		require.NoError(err)
+		require.NoError(serversRepo.RecordConfigDivergence(ctx, &servers.ConfigDivergence{
+			Controller:      "c1",
+			Peer:            "c2",
+			Fingerprint:     "fp1",
+			PeerFingerprint: "fp2",
+			Sections:        []string{"kms"},
+			DetectTime:      time.Now(),
+		}))
+		assertValid(t, servers.ConfigDivergenceKind, servers.ConfigDivergenceSchemaVersion, lastPayload(t, servers.ConfigDivergenceKind))
+	})
+
+	t.Run(target.CredentialRotationKind, func(t *testing.T) {
+		require := require.New(t)
+		sessionRepo, err := session.NewRepository(rw, rw, kms)
+		require.NoError(err)
+		targetRepo, err := target.NewRepository(rw, rw, kms)
+		require.NoError(err)
+		s := session.TestDefaultSession(t, conn, wrapper, iamRepo)
+		require.NoError(targetRepo.SetCredentialCheckoutPolicy(ctx, s.TargetId, target.CredentialCheckoutReject, 0, true))
+		checkout, err := targetRepo.CheckOutCredential(ctx, s.TargetId, s.UserId)
+		require.NoError(err)
+		require.NoError(targetRepo.AttachCredentialCheckout(ctx, checkout.CheckoutId, s.PublicId))
+		_, err = sessionRepo.TerminateSession(ctx, s.PublicId, s.Version, session.ClosedByUser)
+		require.NoError(err)
+		assertValid(t, target.CredentialRotationKind, target.CredentialRotationSchemaVersion, lastPayload(t, target.CredentialRotationKind))
+	})
+
+	t.Run(session.AutoCancelEventKind, func(t *testing.T) {
+		require := require.New(t)
+		sessionRepo, err := session.NewRepository(rw, rw, kms)
+		require.NoError(err)
+		session.TestDefaultSession(t, conn, wrapper, iamRepo)
+		canceled, err := sessionRepo.CancelUnusedSessions(ctx, time.Nanosecond, 0)
+		require.NoError(err)
+		require.NotEmpty(canceled)
+		assertValid(t, session.AutoCancelEventKind, session.AutoCancelSchemaVersion, lastPayload(t, session.AutoCancelEventKind))
+	})
+}
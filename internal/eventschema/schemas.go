@@ -0,0 +1,271 @@
+package eventschema
+
+import (
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/secretfingerprint"
+	"github.com/hashicorp/boundary/internal/securityevent"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/hashicorp/boundary/internal/target"
+)
+
+// WorkerConnectionKind is the kind of the records of proxied connections
+// written to the connection log of workers.
+const WorkerConnectionKind = "worker.connection"
+
+// schemas are the versions of the schemas of each kind, oldest first. Only
+// append to them: see the package documentation for what a new version may
+// change.
+var schemas = map[string][]string{
+	iam.AccessRequestEventKind: {
+		`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "/events/schemas/iam.access_request/v1",
+  "title": "Access request event",
+  "description": "The creation of an access request or a decision on it.",
+  "type": "object",
+  "properties": {
+    "schema_version": {"type": "integer", "description": "The version of the schema the payload conforms to."},
+    "access_request_id": {"type": "string"},
+    "role_id": {"type": "string", "description": "The role requested."},
+    "requester_id": {"type": "string", "description": "The user which requested the role."},
+    "event": {"type": "string", "enum": ["requested", "approved", "denied", "cancelled"]},
+    "actor_id": {"type": "string", "description": "The user which caused the event."},
+    "comment": {"type": "string", "description": "The justification of the request or the comment of the decision."},
+    "create_time": {"type": "string", "format": "date-time"}
+  },
+  "required": ["schema_version", "access_request_id", "role_id", "requester_id", "event", "actor_id", "create_time"]
+}`,
+	},
+	target.CredentialRotationKind: {
+		`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "/events/schemas/target.credential_rotation/v1",
+  "title": "Credential rotation request",
+  "description": "A request to rotate the credential of a target, made when a session checks it in.",
+  "type": "object",
+  "properties": {
+    "schema_version": {"type": "integer", "description": "The version of the schema the payload conforms to."},
+    "target_id": {"type": "string"},
+    "checkout_id": {"type": "string"},
+    "session_id": {"type": "string", "description": "The session which checked the credential in."},
+    "user_id": {"type": "string", "description": "The user of the session."}
+  },
+  "required": ["schema_version", "target_id", "checkout_id", "session_id", "user_id"]
+}`,
+	},
+	securityevent.OutboxKind: {
+		`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "/events/schemas/auth.security_event/v1",
+  "title": "Security event",
+  "description": "An authentication success or failure, a lockout of an account or user, an auth token revocation or a use of the recovery KMS.",
+  "type": "object",
+  "properties": {
+    "schema_version": {"type": "integer", "description": "The version of the schema the payload conforms to."},
+    "kind": {"type": "string", "enum": ["authentication_succeeded", "authentication_failed", "lockout", "auth_token_revoked", "recovery_kms_used"]},
+    "scope_id": {"type": "string", "description": "The scope of the auth method, account, user or auth token, or global for uses of the recovery KMS."},
+    "auth_method_id": {"type": "string", "description": "The auth method, if the event is about one."},
+    "time": {"type": "string", "format": "date-time"}
+  },
+  "required": ["schema_version", "kind", "scope_id", "time"]
+}`,
+	},
+	secretfingerprint.OutboxKind: {
+		`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "/events/schemas/session.secret_fingerprint/v1",
+  "title": "Secret fingerprint",
+  "description": "The fingerprint of a secret brokered to the user of a session, so a leaked secret can be traced to the session.",
+  "type": "object",
+  "properties": {
+    "schema_version": {"type": "integer", "description": "The version of the schema the payload conforms to."},
+    "fingerprint": {"type": "string", "description": "The hex encoded HMAC-SHA256 of the secret, keyed with the fingerprint key of the controllers."},
+    "kind": {"type": "string", "enum": ["session_private_key"]},
+    "session_id": {"type": "string"},
+    "target_id": {"type": "string"},
+    "user_id": {"type": "string"},
+    "scope_id": {"type": "string", "description": "The scope of the target."},
+    "time": {"type": "string", "format": "date-time"}
+  },
+  "required": ["schema_version", "fingerprint", "kind", "session_id", "target_id", "user_id", "scope_id", "time"]
+}`,
+	},
+	servers.ListenerAccessDeniedKind: {
+		`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "/events/schemas/controller.listener_access_denied/v1",
+  "title": "Listener access denial",
+  "description": "The connections from an address refused by the allow and deny lists of the listeners of a controller.",
+  "type": "object",
+  "properties": {
+    "schema_version": {"type": "integer", "description": "The version of the schema the payload conforms to."},
+    "controller": {"type": "string", "description": "The name of the controller."},
+    "purpose": {"type": "string", "enum": ["api", "cluster"], "description": "The purpose of the listeners."},
+    "remote_addr": {"type": "string", "description": "The IP address the connections came from."},
+    "count": {"type": "integer", "description": "The number of connections refused."},
+    "first_time": {"type": "string", "format": "date-time", "description": "When the first connection was refused."},
+    "last_time": {"type": "string", "format": "date-time", "description": "When the last connection was refused."}
+  },
+  "required": ["schema_version", "controller", "purpose", "remote_addr", "count", "first_time", "last_time"]
+}`,
+	},
+	servers.WorkerAccessDeniedKind: {
+		`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "/events/schemas/controller.worker_access_denied/v1",
+  "title": "Worker access denial",
+  "description": "The attempts of a worker to authenticate to a controller or update its status refused by the worker access allow list of the controller.",
+  "type": "object",
+  "properties": {
+    "schema_version": {"type": "integer", "description": "The version of the schema the payload conforms to."},
+    "controller": {"type": "string", "description": "The name of the controller."},
+    "worker_name": {"type": "string", "description": "The name the worker authenticated with."},
+    "remote_addr": {"type": "string", "description": "The IP address the worker connected from."},
+    "reason": {"type": "string", "enum": ["address", "name", "tags"], "description": "The condition of the allow list the worker did not match on its last refused attempt."},
+    "count": {"type": "integer", "description": "The number of attempts refused."},
+    "first_time": {"type": "string", "format": "date-time", "description": "When the first attempt was refused."},
+    "last_time": {"type": "string", "format": "date-time", "description": "When the last attempt was refused."}
+  },
+  "required": ["schema_version", "controller", "worker_name", "remote_addr", "reason", "count", "first_time", "last_time"]
+}`,
+	},
+	session.AutoCancelEventKind: {
+		`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "/events/schemas/session.auto_canceled/v1",
+  "title": "Session auto-cancellation",
+  "description": "The cancellation by a controller of a session no client used within the pending or idle session time to live.",
+  "type": "object",
+  "properties": {
+    "schema_version": {"type": "integer", "description": "The version of the schema the payload conforms to."},
+    "session_id": {"type": "string"},
+    "target_id": {"type": "string"},
+    "user_id": {"type": "string"},
+    "scope_id": {"type": "string", "description": "The scope of the target."},
+    "reason": {"type": "string", "enum": ["pending", "idle"], "description": "Whether no client activated the session, or the client made no connection."},
+    "time": {"type": "string", "format": "date-time"}
+  },
+  "required": ["schema_version", "session_id", "target_id", "user_id", "scope_id", "reason", "time"]
+}`,
+	},
+	servers.ConfigDivergenceKind: {
+		`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "/events/schemas/controller.config_divergence/v1",
+  "title": "Controller configuration divergence",
+  "description": "The security relevant configuration of a controller differs from that of a peer, e.g. during a partially rolled out configuration change.",
+  "type": "object",
+  "properties": {
+    "schema_version": {"type": "integer", "description": "The version of the schema the payload conforms to."},
+    "controller": {"type": "string", "description": "The name of the controller which detected the divergence."},
+    "peer": {"type": "string", "description": "The name of the peer controller."},
+    "fingerprint": {"type": "string", "description": "The fingerprint of the configuration of the controller."},
+    "peer_fingerprint": {"type": "string", "description": "The fingerprint of the configuration of the peer."},
+    "sections": {"type": "array", "items": {"type": "string"}, "description": "The sections of the configuration which differ, such as kms, auth or rate_limits."},
+    "detect_time": {"type": "string", "format": "date-time"}
+  },
+  "required": ["schema_version", "controller", "peer", "fingerprint", "peer_fingerprint", "sections", "detect_time"]
+}`,
+	},
+	WorkerConnectionKind: {
+		`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "/events/schemas/worker.connection/v1",
+  "title": "Connection record",
+  "description": "The record of a connection proxied by a worker, written when it is closed.",
+  "type": "object",
+  "properties": {
+    "schema_version": {"type": "integer", "description": "The version of the schema the payload conforms to."},
+    "time": {"type": "string", "format": "date-time"},
+    "worker": {"type": "string", "description": "The name of the worker."},
+    "session_id": {"type": "string"},
+    "connection_id": {"type": "string"},
+    "user_id": {"type": "string"},
+    "target_id": {"type": "string"},
+    "host_id": {"type": "string"},
+    "client_addr": {"type": "string"},
+    "endpoint": {"type": "string"},
+    "endpoint_addr": {"type": "string", "description": "The address the endpoint resolved to."},
+    "bytes_up": {"type": "integer"},
+    "bytes_down": {"type": "integer"},
+    "start_time": {"type": "string", "format": "date-time"},
+    "duration_ms": {"type": "integer"},
+    "close_reason": {"type": "string"}
+  },
+  "required": ["schema_version", "time", "worker", "session_id", "connection_id", "user_id", "target_id", "host_id", "client_addr", "endpoint", "bytes_up", "bytes_down", "start_time", "duration_ms", "close_reason"]
+}`,
+		`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "/events/schemas/worker.connection/v2",
+  "title": "Connection record",
+  "description": "The record of a connection proxied by a worker, written when it is closed.",
+  "type": "object",
+  "properties": {
+    "schema_version": {"type": "integer", "description": "The version of the schema the payload conforms to."},
+    "time": {"type": "string", "format": "date-time"},
+    "worker": {"type": "string", "description": "The name of the worker."},
+    "session_id": {"type": "string"},
+    "connection_id": {"type": "string"},
+    "user_id": {"type": "string"},
+    "user_name": {"type": "string", "description": "The name of the user, if it has one."},
+    "target_id": {"type": "string"},
+    "host_id": {"type": "string"},
+    "client_addr": {"type": "string"},
+    "endpoint": {"type": "string"},
+    "endpoint_addr": {"type": "string", "description": "The address the endpoint resolved to."},
+    "bytes_up": {"type": "integer"},
+    "bytes_down": {"type": "integer"},
+    "start_time": {"type": "string", "format": "date-time"},
+    "duration_ms": {"type": "integer"},
+    "close_reason": {"type": "string"},
+    "credential_checkout_id": {"type": "string", "description": "The checkout of the shared credential of the target used by the session, if any."},
+    "peer_identity": {"type": "string", "description": "How the identity of the user was passed to the endpoint, if it was."}
+  },
+  "required": ["schema_version", "time", "worker", "session_id", "connection_id", "user_id", "target_id", "host_id", "client_addr", "endpoint", "bytes_up", "bytes_down", "start_time", "duration_ms", "close_reason"]
+}`,
+		`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "/events/schemas/worker.connection/v3",
+  "title": "Connection record",
+  "description": "The record of a connection proxied by a worker, written when it is closed.",
+  "type": "object",
+  "properties": {
+    "schema_version": {"type": "integer", "description": "The version of the schema the payload conforms to."},
+    "time": {"type": "string", "format": "date-time"},
+    "worker": {"type": "string", "description": "The name of the worker."},
+    "session_id": {"type": "string"},
+    "connection_id": {"type": "string"},
+    "user_id": {"type": "string"},
+    "user_name": {"type": "string", "description": "The name of the user, if it has one."},
+    "target_id": {"type": "string"},
+    "host_id": {"type": "string"},
+    "client_addr": {"type": "string"},
+    "endpoint": {"type": "string"},
+    "endpoint_addr": {"type": "string", "description": "The address the endpoint resolved to."},
+    "bytes_up": {"type": "integer"},
+    "bytes_down": {"type": "integer"},
+    "start_time": {"type": "string", "format": "date-time"},
+    "duration_ms": {"type": "integer"},
+    "close_reason": {"type": "string"},
+    "credential_checkout_id": {"type": "string", "description": "The checkout of the shared credential of the target used by the session, if any."},
+    "peer_identity": {"type": "string", "description": "How the identity of the user was passed to the endpoint, if it was."},
+    "protocol": {"type": "string", "description": "The subprotocol of the custom protocol handler which proxied the connection, if any."},
+    "protocol_events": {
+      "type": "array",
+      "description": "The events recorded by the custom protocol handler, if any.",
+      "items": {
+        "type": "object",
+        "properties": {
+          "time": {"type": "string", "format": "date-time"},
+          "kind": {"type": "string"},
+          "attributes": {"type": "object"}
+        },
+        "required": ["time", "kind"]
+      }
+    }
+  },
+  "required": ["schema_version", "time", "worker", "session_id", "connection_id", "user_id", "target_id", "host_id", "client_addr", "endpoint", "bytes_up", "bytes_down", "start_time", "duration_ms", "close_reason"]
+}`,
+	},
+}
@@ -0,0 +1,112 @@
+package eventsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/authhook"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/outbox"
+	"github.com/hashicorp/go-hclog"
+)
+
+// DefaultTimeout is the timeout of each delivery to a sink.
+const DefaultTimeout = 5 * time.Second
+
+// A Deliverer delivers outbox events to the sinks of their scopes.
+type Deliverer struct {
+	repoFn func() (*Repository, error)
+	client *http.Client
+	logger hclog.Logger
+}
+
+// NewDeliverer returns a Deliverer looking up sinks with the repository of
+// repoFn and delivering to them with the client, or with http.DefaultClient if
+// nil.
+func NewDeliverer(repoFn func() (*Repository, error), client *http.Client, logger hclog.Logger) (*Deliverer, error) {
+	switch {
+	case repoFn == nil:
+		return nil, fmt.Errorf("new event sink deliverer: missing repository: %w", errors.ErrInvalidParameter)
+	case logger == nil:
+		return nil, fmt.Errorf("new event sink deliverer: missing logger: %w", errors.ErrInvalidParameter)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Deliverer{repoFn: repoFn, client: client, logger: logger}, nil
+}
+
+// Deliver is an outbox.Handler delivering the message to the sinks of the
+// scope_id of its payload. It returns an error if the delivery to any sink
+// fails, so the message is delivered again later. Messages without a scope
+// have no sinks.
+func (d *Deliverer) Deliver(ctx context.Context, m *outbox.Message) error {
+	var p struct {
+		ScopeId string `json:"scope_id"`
+	}
+	if err := json.Unmarshal(m.Payload, &p); err != nil {
+		// Delivering the message again can't succeed
+		d.logger.Error("unable to parse event payload for event sinks", "kind", m.Kind, "id", m.Id, "error", err)
+		return nil
+	}
+	if p.ScopeId == "" {
+		return nil
+	}
+	repo, err := d.repoFn()
+	if err != nil {
+		return err
+	}
+	sinks, err := repo.ListEventSinks(ctx, p.ScopeId)
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for _, s := range sinks {
+		if err := d.send(ctx, s, m); err != nil {
+			d.logger.Warn("event sink delivery failed", "sink_id", s.PublicId, "url", s.Url, "kind", m.Kind, "id", m.Id, "error", err)
+			failed = append(failed, s.PublicId)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("delivery to event sinks %s failed", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// send delivers the message to the sink, redacting its payload and signing
+// it with the secret of the sink.
+func (d *Deliverer) send(ctx context.Context, s *Sink, m *outbox.Message) error {
+	body, err := redact(m.Payload, s.RedactFields)
+	if err != nil {
+		return fmt.Errorf("unable to redact payload: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventKindHeader, m.Kind)
+	req.Header.Set(EventIdHeader, strconv.FormatUint(m.Id, 10))
+	req.Header.Set(authhook.TimestampHeader, ts)
+	req.Header.Set(authhook.SignatureHeader, authhook.Sign(s.Secret, ts, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
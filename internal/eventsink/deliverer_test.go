@@ -0,0 +1,88 @@
+package eventsink
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/authhook"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/outbox"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliverer_Deliver(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, prj := iam.TestScopes(t, iamRepo)
+	rw := db.New(conn)
+	repoFn := func() (*Repository, error) {
+		return NewRepository(rw, rw, kmsCache)
+	}
+	repo, err := repoFn()
+	require.NoError(err)
+	ctx := context.Background()
+
+	type delivery struct {
+		header http.Header
+		body   string
+	}
+	var lock sync.Mutex
+	var deliveries []delivery
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		assert.NoError(err)
+		lock.Lock()
+		defer lock.Unlock()
+		deliveries = append(deliveries, delivery{header: r.Header, body: string(b)})
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	take := func() []delivery {
+		lock.Lock()
+		defer lock.Unlock()
+		ret := deliveries
+		deliveries = nil
+		return ret
+	}
+
+	_, err = repo.CreateSink(ctx, &Sink{ScopeId: org.GetPublicId(), Url: srv.URL, Secret: "secret", RedactFields: []string{"user_id"}})
+	require.NoError(err)
+	d, err := NewDeliverer(repoFn, srv.Client(), hclog.NewNullLogger())
+	require.NoError(err)
+
+	payload := `{"schema_version":1,"scope_id":"` + prj.GetPublicId() + `","user_id":"u_1234567890"}`
+	m := &outbox.Message{Id: 42, Kind: "session.auto_canceled", Payload: []byte(payload)}
+	require.NoError(d.Deliver(ctx, m))
+	got := take()
+	require.Len(got, 1)
+	assert.JSONEq(`{"schema_version":1,"scope_id":"`+prj.GetPublicId()+`","user_id":"[REDACTED]"}`, got[0].body)
+	assert.Equal("session.auto_canceled", got[0].header.Get(EventKindHeader))
+	assert.Equal("42", got[0].header.Get(EventIdHeader))
+	ts := got[0].header.Get(authhook.TimestampHeader)
+	assert.Equal(authhook.Sign("secret", ts, []byte(got[0].body)), got[0].header.Get(authhook.SignatureHeader))
+
+	// Events of other scopes and without a scope aren't delivered
+	require.NoError(d.Deliver(ctx, &outbox.Message{Id: 43, Kind: "auth.security_event", Payload: []byte(`{"scope_id":"global"}`)}))
+	require.NoError(d.Deliver(ctx, &outbox.Message{Id: 44, Kind: "controller.config_divergence", Payload: []byte(`{"schema_version":1}`)}))
+	assert.Empty(take())
+
+	// Failed deliveries are retried by the outbox
+	lock.Lock()
+	status = http.StatusServiceUnavailable
+	lock.Unlock()
+	assert.Error(d.Deliver(ctx, m))
+	assert.Len(take(), 1)
+}
@@ -0,0 +1,78 @@
+// Package eventsink delivers the outbox events of orgs to the webhooks the
+// orgs configure, so multi-tenant operators can give tenants their own audit
+// feeds. A Sink of an org receives the events whose payloads have the scope_id
+// of the org or of one of its projects, with the payload fields it redacts
+// replaced by RedactedValue. Events without a scope are not delivered to
+// sinks.
+//
+// Deliveries are POSTs of the JSON payload of the event, signed like
+// authentication hook calls: the authhook.SignatureHeader of each delivery is
+// the hex encoded HMAC-SHA256 of the value of its authhook.TimestampHeader, a
+// period and the body, prefixed with "sha256=". The EventKindHeader is the
+// kind of the event, and the EventIdHeader identifies it. Delivery is at least
+// once: an event is delivered again to all sinks of its scope if any of them
+// fails, so sinks should ignore events whose id they have already received.
+package eventsink
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	// SinkPrefix is the prefix of the ids of sinks.
+	SinkPrefix = "evs"
+
+	// EventKindHeader is the header of the outbox kind of a delivered event.
+	EventKindHeader = "X-Boundary-Event-Kind"
+
+	// EventIdHeader is the header of the id of a delivered event, which is
+	// the same for every delivery of the event.
+	EventIdHeader = "X-Boundary-Event-Id"
+
+	// RedactedValue replaces the values of redacted fields.
+	RedactedValue = "[REDACTED]"
+)
+
+// A Sink is a webhook of an org receiving the events of the org and its
+// projects. Secret signs the deliveries; it is only set on sinks returned for
+// delivery.
+type Sink struct {
+	PublicId string
+	ScopeId  string
+	Url      string
+	Secret   string
+	// RedactFields are the payload fields, as dot separated paths into
+	// nested objects, whose values are replaced with RedactedValue.
+	RedactFields []string
+	CreateTime   time.Time
+}
+
+// redact returns the payload with the values of the fields replaced with
+// RedactedValue. Fields which aren't in the payload are ignored.
+func redact(payload []byte, fields []string) ([]byte, error) {
+	if len(fields) == 0 {
+		return payload, nil
+	}
+	var p map[string]interface{}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		obj := p
+		path := strings.Split(f, ".")
+		for _, name := range path[:len(path)-1] {
+			next, ok := obj[name].(map[string]interface{})
+			if !ok {
+				obj = nil
+				break
+			}
+			obj = next
+		}
+		if _, ok := obj[path[len(path)-1]]; ok {
+			obj[path[len(path)-1]] = RedactedValue
+		}
+	}
+	return json.Marshal(p)
+}
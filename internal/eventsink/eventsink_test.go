@@ -0,0 +1,46 @@
+package eventsink
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+	payload := []byte(`{"schema_version":1,"scope_id":"p_1234567890","user_id":"u_1234567890","client":{"address":"10.0.0.1","port":22}}`)
+	tests := []struct {
+		name   string
+		fields []string
+		want   string
+	}{
+		{
+			name: "none",
+			want: string(payload),
+		},
+		{
+			name:   "top-level",
+			fields: []string{"user_id"},
+			want:   `{"client":{"address":"10.0.0.1","port":22},"schema_version":1,"scope_id":"p_1234567890","user_id":"[REDACTED]"}`,
+		},
+		{
+			name:   "nested",
+			fields: []string{"client.address"},
+			want:   `{"client":{"address":"[REDACTED]","port":22},"schema_version":1,"scope_id":"p_1234567890","user_id":"u_1234567890"}`,
+		},
+		{
+			name:   "missing",
+			fields: []string{"auth_method_id", "client.address.host", "user_id.name"},
+			want:   `{"client":{"address":"10.0.0.1","port":22},"schema_version":1,"scope_id":"p_1234567890","user_id":"u_1234567890"}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := redact(payload, tt.fields)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package eventsink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/lib/pq"
+)
+
+const (
+	// listSinksQuery returns the sinks of an org.
+	listSinksQuery = `
+	select public_id, scope_id, url, redact_fields, create_time
+		from scope_event_sink
+	where scope_id = ?
+	order by create_time, public_id`
+
+	// listEventSinksQuery returns the sinks receiving the events of a scope:
+	// those of the scope and of its parent.
+	listEventSinksQuery = `
+	select public_id, scope_id, url, secret, key_id, redact_fields, create_time
+		from scope_event_sink
+	where scope_id = ?
+		or scope_id = (select parent_id from iam_scope where public_id = ?)
+	order by create_time, public_id`
+)
+
+// wrappedSecret encrypts the secret of a sink with the database key of the
+// sink's org.
+type wrappedSecret struct {
+	ScopeId  string
+	KeyId    string
+	Secret   []byte `wrapping:"pt,secret"`
+	CtSecret []byte `wrapping:"ct,secret"`
+}
+
+var _ db.FieldEncrypter = (*wrappedSecret)(nil)
+
+func (ws *wrappedSecret) GetScopeId() string {
+	return ws.ScopeId
+}
+
+func (ws *wrappedSecret) GetKeyId() string {
+	return ws.KeyId
+}
+
+func (ws *wrappedSecret) SetKeyId(keyId string) {
+	ws.KeyId = keyId
+}
+
+func (ws *wrappedSecret) WrappedFields() interface{} {
+	return ws
+}
+
+// A Repository stores the event sinks of orgs.
+type Repository struct {
+	reader db.Reader
+	writer db.Writer
+	kms    *kms.Kms
+}
+
+// NewRepository creates a new Repository.
+func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms) (*Repository, error) {
+	switch {
+	case r == nil:
+		return nil, fmt.Errorf("db.Reader: event sink: %w", errors.ErrInvalidParameter)
+	case w == nil:
+		return nil, fmt.Errorf("db.Writer: event sink: %w", errors.ErrInvalidParameter)
+	case kms == nil:
+		return nil, fmt.Errorf("kms: event sink: %w", errors.ErrInvalidParameter)
+	}
+	return &Repository{
+		reader: r,
+		writer: w,
+		kms:    kms,
+	}, nil
+}
+
+// CreateSink stores a sink of the org of s.ScopeId with its url, secret and
+// redacted fields, and returns it without its secret. The secret is
+// encrypted with the database key of the org.
+func (r *Repository) CreateSink(ctx context.Context, s *Sink) (*Sink, error) {
+	if s == nil {
+		return nil, fmt.Errorf("create event sink: missing sink: %w", errors.ErrInvalidParameter)
+	}
+	if !strings.HasPrefix(s.ScopeId, scope.Org.Prefix()+"_") {
+		return nil, fmt.Errorf("create event sink: scope %q is not an org: %w", s.ScopeId, errors.ErrInvalidParameter)
+	}
+	if err := ValidateUrl(s.Url); err != nil {
+		return nil, fmt.Errorf("create event sink: %w: %v", errors.ErrInvalidParameter, err)
+	}
+	if s.Secret == "" {
+		return nil, fmt.Errorf("create event sink: missing secret: %w", errors.ErrInvalidParameter)
+	}
+	for _, f := range s.RedactFields {
+		if strings.TrimSpace(f) == "" {
+			return nil, fmt.Errorf("create event sink: empty redact field: %w", errors.ErrInvalidParameter)
+		}
+	}
+	id, err := db.NewPublicId(SinkPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("create event sink: %w", err)
+	}
+	ws := &wrappedSecret{ScopeId: s.ScopeId, Secret: []byte(s.Secret)}
+	if err := db.EncryptFields(ctx, ws, r.kms.DatabaseFieldWrapper()); err != nil {
+		return nil, fmt.Errorf("create event sink: unable to encrypt secret: %w", err)
+	}
+	fields := pq.StringArray(s.RedactFields)
+	if fields == nil {
+		fields = pq.StringArray{}
+	}
+	if _, err := r.writer.Exec(ctx,
+		"insert into scope_event_sink (public_id, scope_id, url, secret, key_id, redact_fields) values (?, ?, ?, ?, ?, ?)",
+		[]interface{}{id, s.ScopeId, s.Url, ws.CtSecret, ws.KeyId, fields}); err != nil {
+		return nil, fmt.Errorf("create event sink: %w for %s", err, s.ScopeId)
+	}
+	sinks, err := r.ListSinks(ctx, s.ScopeId)
+	if err != nil {
+		return nil, fmt.Errorf("create event sink: %w", err)
+	}
+	for _, created := range sinks {
+		if created.PublicId == id {
+			return created, nil
+		}
+	}
+	return nil, fmt.Errorf("create event sink: %s not found after create", id)
+}
+
+// ListSinks returns the sinks of the org, oldest first, without their
+// secrets.
+func (r *Repository) ListSinks(ctx context.Context, scopeId string) ([]*Sink, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list event sinks: missing scope id: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, listSinksQuery, []interface{}{scopeId})
+	if err != nil {
+		return nil, fmt.Errorf("list event sinks: %w for %s", err, scopeId)
+	}
+	defer rows.Close()
+	var sinks []*Sink
+	for rows.Next() {
+		var s Sink
+		var fields pq.StringArray
+		if err := rows.Scan(&s.PublicId, &s.ScopeId, &s.Url, &fields, &s.CreateTime); err != nil {
+			return nil, fmt.Errorf("list event sinks: %w for %s", err, scopeId)
+		}
+		s.RedactFields = fields
+		sinks = append(sinks, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list event sinks: %w for %s", err, scopeId)
+	}
+	return sinks, nil
+}
+
+// ListEventSinks returns the sinks receiving the events of the scope, those
+// of the scope if it is an org and of its org if it is a project, with their
+// secrets decrypted.
+func (r *Repository) ListEventSinks(ctx context.Context, scopeId string) ([]*Sink, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list sinks of events: missing scope id: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, listEventSinksQuery, []interface{}{scopeId, scopeId})
+	if err != nil {
+		return nil, fmt.Errorf("list sinks of events: %w for %s", err, scopeId)
+	}
+	defer rows.Close()
+	var sinks []*Sink
+	for rows.Next() {
+		var s Sink
+		var ws wrappedSecret
+		var fields pq.StringArray
+		if err := rows.Scan(&s.PublicId, &s.ScopeId, &s.Url, &ws.CtSecret, &ws.KeyId, &fields, &s.CreateTime); err != nil {
+			return nil, fmt.Errorf("list sinks of events: %w for %s", err, scopeId)
+		}
+		ws.ScopeId = s.ScopeId
+		if err := db.DecryptFields(ctx, &ws, r.kms.DatabaseFieldWrapper()); err != nil {
+			return nil, fmt.Errorf("list sinks of events: unable to decrypt secret: %w for %s", err, s.PublicId)
+		}
+		s.Secret = string(ws.Secret)
+		s.RedactFields = fields
+		sinks = append(sinks, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list sinks of events: %w for %s", err, scopeId)
+	}
+	return sinks, nil
+}
+
+// DeleteSink deletes the sink of the org and returns the number of sinks
+// deleted, which is 0 if the org has no such sink.
+func (r *Repository) DeleteSink(ctx context.Context, scopeId, id string) (int, error) {
+	switch {
+	case scopeId == "":
+		return 0, fmt.Errorf("delete event sink: missing scope id: %w", errors.ErrInvalidParameter)
+	case id == "":
+		return 0, fmt.Errorf("delete event sink: missing id: %w", errors.ErrInvalidParameter)
+	}
+	n, err := r.writer.Exec(ctx,
+		"delete from scope_event_sink where public_id = ? and scope_id = ?",
+		[]interface{}{id, scopeId})
+	if err != nil {
+		return 0, fmt.Errorf("delete event sink: %w for %s", err, id)
+	}
+	return n, nil
+}
+
+// ValidateUrl returns an error if u is not an absolute http or https URL.
+func ValidateUrl(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("url %q is not an absolute http or https url", u)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package eventsink
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_Sinks(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, prj := iam.TestScopes(t, iamRepo)
+	otherOrg, _ := iam.TestScopes(t, iamRepo)
+	rw := db.New(conn)
+	repo, err := NewRepository(rw, rw, kmsCache)
+	require.NoError(err)
+	ctx := context.Background()
+
+	s, err := repo.CreateSink(ctx, &Sink{
+		ScopeId:      org.GetPublicId(),
+		Url:          "https://audit.example.com/events",
+		Secret:       "secret",
+		RedactFields: []string{"user_id"},
+	})
+	require.NoError(err)
+	assert.Contains(s.PublicId, SinkPrefix+"_")
+	assert.Equal(org.GetPublicId(), s.ScopeId)
+	assert.Empty(s.Secret)
+	assert.Equal([]string{"user_id"}, s.RedactFields)
+	assert.False(s.CreateTime.IsZero())
+
+	_, err = repo.CreateSink(ctx, &Sink{ScopeId: otherOrg.GetPublicId(), Url: "https://other.example.com", Secret: "other"})
+	require.NoError(err)
+
+	sinks, err := repo.ListSinks(ctx, org.GetPublicId())
+	require.NoError(err)
+	assert.Equal([]*Sink{s}, sinks)
+
+	// The events of the org and of its projects go to its sinks
+	for _, scopeId := range []string{org.GetPublicId(), prj.GetPublicId()} {
+		sinks, err := repo.ListEventSinks(ctx, scopeId)
+		require.NoError(err)
+		require.Len(sinks, 1)
+		assert.Equal(s.PublicId, sinks[0].PublicId)
+		assert.Equal("secret", sinks[0].Secret)
+	}
+	sinks, err = repo.ListEventSinks(ctx, "global")
+	require.NoError(err)
+	assert.Empty(sinks)
+
+	// Sinks can only be deleted through their org
+	n, err := repo.DeleteSink(ctx, otherOrg.GetPublicId(), s.PublicId)
+	require.NoError(err)
+	assert.Zero(n)
+	n, err = repo.DeleteSink(ctx, org.GetPublicId(), s.PublicId)
+	require.NoError(err)
+	assert.Equal(1, n)
+	sinks, err = repo.ListSinks(ctx, org.GetPublicId())
+	require.NoError(err)
+	assert.Empty(sinks)
+}
+
+func TestRepository_CreateSinkInvalid(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, prj := iam.TestScopes(t, iamRepo)
+	rw := db.New(conn)
+	repo, err := NewRepository(rw, rw, kmsCache)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		sink *Sink
+	}{
+		{name: "nil", sink: nil},
+		{name: "project", sink: &Sink{ScopeId: prj.GetPublicId(), Url: "https://audit.example.com", Secret: "secret"}},
+		{name: "global", sink: &Sink{ScopeId: "global", Url: "https://audit.example.com", Secret: "secret"}},
+		{name: "relative-url", sink: &Sink{ScopeId: org.GetPublicId(), Url: "/events", Secret: "secret"}},
+		{name: "unsupported-url", sink: &Sink{ScopeId: org.GetPublicId(), Url: "ftp://audit.example.com", Secret: "secret"}},
+		{name: "missing-secret", sink: &Sink{ScopeId: org.GetPublicId(), Url: "https://audit.example.com"}},
+		{name: "empty-redact-field", sink: &Sink{ScopeId: org.GetPublicId(), Url: "https://audit.example.com", Secret: "secret", RedactFields: []string{" "}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.CreateSink(context.Background(), tt.sink)
+			assert.True(t, errors.Is(err, errors.ErrInvalidParameter))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package eventsink
+
+import (
+	"fmt"
+	"testing"
+
+	libredact "github.com/hashicorp/boundary/internal/libs/redact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrappedSecret_Redact(t *testing.T) {
+	assert := assert.New(t)
+	ws := &wrappedSecret{
+		ScopeId:  "o_1234567890",
+		Secret:   []byte("sink-secret-plaintext"),
+		CtSecret: []byte("sink-secret-ciphertext"),
+	}
+	err := fmt.Errorf("failed for %s with %s and %s", ws.ScopeId, ws.Secret, ws.CtSecret)
+	got := libredact.Error(err, ws).Error()
+	assert.Equal("failed for o_1234567890 with [REDACTED] and [REDACTED]", got)
+}
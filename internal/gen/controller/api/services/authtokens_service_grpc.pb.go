@@ -29,6 +29,21 @@ type AuthTokenServiceClient interface {
 	// DeleteAuthToken removes a Auth Token from Boundary. If the provided
 	// Auth Token id is malformed or not provided an error is returned.
 	DeleteAuthToken(ctx context.Context, in *DeleteAuthTokenRequest, opts ...grpc.CallOption) (*DeleteAuthTokenResponse, error)
+	// ExchangeAuthToken issues an Auth Token derived from the caller's Auth
+	// Token, limited to a subset of its grants, a shorter time to live and
+	// optionally a single target. Deleting the caller's Auth Token deletes the
+	// derived Auth Token.
+	ExchangeAuthToken(ctx context.Context, in *ExchangeAuthTokenRequest, opts ...grpc.CallOption) (*ExchangeAuthTokenResponse, error)
+	// RefreshAuthToken issues a new Auth Token for the login of the caller's
+	// Auth Token, which stays valid until it expires.
+	RefreshAuthToken(ctx context.Context, in *RefreshAuthTokenRequest, opts ...grpc.CallOption) (*RefreshAuthTokenResponse, error)
+	// IssueScopedAuthToken issues an Auth Token derived from the caller's Auth
+	// Token which keeps only its grants in the requested Scopes and is
+	// restricted by a least privilege preset. The terraform preset lets
+	// provisioning pipelines manage resources with a token that can't authorize
+	// Sessions, even if it leaks. Deleting the caller's Auth Token deletes the
+	// issued one.
+	IssueScopedAuthToken(ctx context.Context, in *IssueScopedAuthTokenRequest, opts ...grpc.CallOption) (*IssueScopedAuthTokenResponse, error)
 }
 
 type authTokenServiceClient struct {
@@ -66,6 +81,33 @@ func (c *authTokenServiceClient) DeleteAuthToken(ctx context.Context, in *Delete
 	return out, nil
 }
 
+func (c *authTokenServiceClient) ExchangeAuthToken(ctx context.Context, in *ExchangeAuthTokenRequest, opts ...grpc.CallOption) (*ExchangeAuthTokenResponse, error) {
+	out := new(ExchangeAuthTokenResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.AuthTokenService/ExchangeAuthToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authTokenServiceClient) RefreshAuthToken(ctx context.Context, in *RefreshAuthTokenRequest, opts ...grpc.CallOption) (*RefreshAuthTokenResponse, error) {
+	out := new(RefreshAuthTokenResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.AuthTokenService/RefreshAuthToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authTokenServiceClient) IssueScopedAuthToken(ctx context.Context, in *IssueScopedAuthTokenRequest, opts ...grpc.CallOption) (*IssueScopedAuthTokenResponse, error) {
+	out := new(IssueScopedAuthTokenResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.AuthTokenService/IssueScopedAuthToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthTokenServiceServer is the server API for AuthTokenService service.
 // All implementations must embed UnimplementedAuthTokenServiceServer
 // for forward compatibility
@@ -82,6 +124,21 @@ type AuthTokenServiceServer interface {
 	// DeleteAuthToken removes a Auth Token from Boundary. If the provided
 	// Auth Token id is malformed or not provided an error is returned.
 	DeleteAuthToken(context.Context, *DeleteAuthTokenRequest) (*DeleteAuthTokenResponse, error)
+	// ExchangeAuthToken issues an Auth Token derived from the caller's Auth
+	// Token, limited to a subset of its grants, a shorter time to live and
+	// optionally a single target. Deleting the caller's Auth Token deletes the
+	// derived Auth Token.
+	ExchangeAuthToken(context.Context, *ExchangeAuthTokenRequest) (*ExchangeAuthTokenResponse, error)
+	// RefreshAuthToken issues a new Auth Token for the login of the caller's
+	// Auth Token, which stays valid until it expires.
+	RefreshAuthToken(context.Context, *RefreshAuthTokenRequest) (*RefreshAuthTokenResponse, error)
+	// IssueScopedAuthToken issues an Auth Token derived from the caller's Auth
+	// Token which keeps only its grants in the requested Scopes and is
+	// restricted by a least privilege preset. The terraform preset lets
+	// provisioning pipelines manage resources with a token that can't authorize
+	// Sessions, even if it leaks. Deleting the caller's Auth Token deletes the
+	// issued one.
+	IssueScopedAuthToken(context.Context, *IssueScopedAuthTokenRequest) (*IssueScopedAuthTokenResponse, error)
 	mustEmbedUnimplementedAuthTokenServiceServer()
 }
 
@@ -98,6 +155,15 @@ func (UnimplementedAuthTokenServiceServer) ListAuthTokens(context.Context, *List
 func (UnimplementedAuthTokenServiceServer) DeleteAuthToken(context.Context, *DeleteAuthTokenRequest) (*DeleteAuthTokenResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteAuthToken not implemented")
 }
+func (UnimplementedAuthTokenServiceServer) ExchangeAuthToken(context.Context, *ExchangeAuthTokenRequest) (*ExchangeAuthTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExchangeAuthToken not implemented")
+}
+func (UnimplementedAuthTokenServiceServer) RefreshAuthToken(context.Context, *RefreshAuthTokenRequest) (*RefreshAuthTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshAuthToken not implemented")
+}
+func (UnimplementedAuthTokenServiceServer) IssueScopedAuthToken(context.Context, *IssueScopedAuthTokenRequest) (*IssueScopedAuthTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueScopedAuthToken not implemented")
+}
 func (UnimplementedAuthTokenServiceServer) mustEmbedUnimplementedAuthTokenServiceServer() {}
 
 // UnsafeAuthTokenServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -165,6 +231,60 @@ func _AuthTokenService_DeleteAuthToken_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthTokenService_ExchangeAuthToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExchangeAuthTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthTokenServiceServer).ExchangeAuthToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.AuthTokenService/ExchangeAuthToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthTokenServiceServer).ExchangeAuthToken(ctx, req.(*ExchangeAuthTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthTokenService_RefreshAuthToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshAuthTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthTokenServiceServer).RefreshAuthToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.AuthTokenService/RefreshAuthToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthTokenServiceServer).RefreshAuthToken(ctx, req.(*RefreshAuthTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthTokenService_IssueScopedAuthToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueScopedAuthTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthTokenServiceServer).IssueScopedAuthToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.AuthTokenService/IssueScopedAuthToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthTokenServiceServer).IssueScopedAuthToken(ctx, req.(*IssueScopedAuthTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _AuthTokenService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.api.services.v1.AuthTokenService",
 	HandlerType: (*AuthTokenServiceServer)(nil),
@@ -181,6 +301,18 @@ var _AuthTokenService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteAuthToken",
 			Handler:    _AuthTokenService_DeleteAuthToken_Handler,
 		},
+		{
+			MethodName: "ExchangeAuthToken",
+			Handler:    _AuthTokenService_ExchangeAuthToken_Handler,
+		},
+		{
+			MethodName: "RefreshAuthToken",
+			Handler:    _AuthTokenService_RefreshAuthToken_Handler,
+		},
+		{
+			MethodName: "IssueScopedAuthToken",
+			Handler:    _AuthTokenService_IssueScopedAuthToken_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/api/services/v1/authtokens_service.proto",
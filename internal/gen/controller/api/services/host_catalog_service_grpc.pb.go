@@ -43,6 +43,12 @@ type HostCatalogServiceClient interface {
 	// sets from Boundary. If the provided Host Catalog IDs is malformed or not
 	// provided DeleteHostCatalog returns an error.
 	DeleteHostCatalog(ctx context.Context, in *DeleteHostCatalogRequest, opts ...grpc.CallOption) (*DeleteHostCatalogResponse, error)
+	// ImportHostCatalogHosts creates the Hosts and Host Sets of an SSH
+	// known_hosts, SSH client configuration or Ansible inventory file in a
+	// static Host Catalog. Hosts and Host Sets are matched to those of the
+	// catalog by name. A dry run requires reading the catalog; an import also
+	// requires creating Hosts and Host Sets in it.
+	ImportHostCatalogHosts(ctx context.Context, in *ImportHostCatalogHostsRequest, opts ...grpc.CallOption) (*ImportHostCatalogHostsResponse, error)
 }
 
 type hostCatalogServiceClient struct {
@@ -98,6 +104,15 @@ func (c *hostCatalogServiceClient) DeleteHostCatalog(ctx context.Context, in *De
 	return out, nil
 }
 
+func (c *hostCatalogServiceClient) ImportHostCatalogHosts(ctx context.Context, in *ImportHostCatalogHostsRequest, opts ...grpc.CallOption) (*ImportHostCatalogHostsResponse, error) {
+	out := new(ImportHostCatalogHostsResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.HostCatalogService/ImportHostCatalogHosts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HostCatalogServiceServer is the server API for HostCatalogService service.
 // All implementations must embed UnimplementedHostCatalogServiceServer
 // for forward compatibility
@@ -128,6 +143,12 @@ type HostCatalogServiceServer interface {
 	// sets from Boundary. If the provided Host Catalog IDs is malformed or not
 	// provided DeleteHostCatalog returns an error.
 	DeleteHostCatalog(context.Context, *DeleteHostCatalogRequest) (*DeleteHostCatalogResponse, error)
+	// ImportHostCatalogHosts creates the Hosts and Host Sets of an SSH
+	// known_hosts, SSH client configuration or Ansible inventory file in a
+	// static Host Catalog. Hosts and Host Sets are matched to those of the
+	// catalog by name. A dry run requires reading the catalog; an import also
+	// requires creating Hosts and Host Sets in it.
+	ImportHostCatalogHosts(context.Context, *ImportHostCatalogHostsRequest) (*ImportHostCatalogHostsResponse, error)
 	mustEmbedUnimplementedHostCatalogServiceServer()
 }
 
@@ -150,6 +171,9 @@ func (UnimplementedHostCatalogServiceServer) UpdateHostCatalog(context.Context,
 func (UnimplementedHostCatalogServiceServer) DeleteHostCatalog(context.Context, *DeleteHostCatalogRequest) (*DeleteHostCatalogResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteHostCatalog not implemented")
 }
+func (UnimplementedHostCatalogServiceServer) ImportHostCatalogHosts(context.Context, *ImportHostCatalogHostsRequest) (*ImportHostCatalogHostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportHostCatalogHosts not implemented")
+}
 func (UnimplementedHostCatalogServiceServer) mustEmbedUnimplementedHostCatalogServiceServer() {}
 
 // UnsafeHostCatalogServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -253,6 +277,24 @@ func _HostCatalogService_DeleteHostCatalog_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HostCatalogService_ImportHostCatalogHosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportHostCatalogHostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostCatalogServiceServer).ImportHostCatalogHosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.HostCatalogService/ImportHostCatalogHosts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostCatalogServiceServer).ImportHostCatalogHosts(ctx, req.(*ImportHostCatalogHostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _HostCatalogService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.api.services.v1.HostCatalogService",
 	HandlerType: (*HostCatalogServiceServer)(nil),
@@ -277,6 +319,10 @@ var _HostCatalogService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteHostCatalog",
 			Handler:    _HostCatalogService_DeleteHostCatalog_Handler,
 		},
+		{
+			MethodName: "ImportHostCatalogHosts",
+			Handler:    _HostCatalogService_ImportHostCatalogHosts_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/api/services/v1/host_catalog_service.proto",
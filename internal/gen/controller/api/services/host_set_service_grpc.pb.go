@@ -65,6 +65,11 @@ type HostSetServiceClient interface {
 	// or references a non-existing scope or catalog, or if a Host id is included
 	// which is not in the provided Host Set.
 	RemoveHostSetHosts(ctx context.Context, in *RemoveHostSetHostsRequest, opts ...grpc.CallOption) (*RemoveHostSetHostsResponse, error)
+	// CloneHostSet creates a new Host Set in the catalog of a Host Set with its
+	// Hosts. The description of the Host Set is copied unless one is provided.
+	// The caller must be allowed to read the Host Set and to create Host Sets
+	// in its catalog.
+	CloneHostSet(ctx context.Context, in *CloneHostSetRequest, opts ...grpc.CallOption) (*CloneHostSetResponse, error)
 }
 
 type hostSetServiceClient struct {
@@ -147,6 +152,15 @@ func (c *hostSetServiceClient) RemoveHostSetHosts(ctx context.Context, in *Remov
 	return out, nil
 }
 
+func (c *hostSetServiceClient) CloneHostSet(ctx context.Context, in *CloneHostSetRequest, opts ...grpc.CallOption) (*CloneHostSetResponse, error) {
+	out := new(CloneHostSetResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.HostSetService/CloneHostSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HostSetServiceServer is the server API for HostSetService service.
 // All implementations must embed UnimplementedHostSetServiceServer
 // for forward compatibility
@@ -199,6 +213,11 @@ type HostSetServiceServer interface {
 	// or references a non-existing scope or catalog, or if a Host id is included
 	// which is not in the provided Host Set.
 	RemoveHostSetHosts(context.Context, *RemoveHostSetHostsRequest) (*RemoveHostSetHostsResponse, error)
+	// CloneHostSet creates a new Host Set in the catalog of a Host Set with its
+	// Hosts. The description of the Host Set is copied unless one is provided.
+	// The caller must be allowed to read the Host Set and to create Host Sets
+	// in its catalog.
+	CloneHostSet(context.Context, *CloneHostSetRequest) (*CloneHostSetResponse, error)
 	mustEmbedUnimplementedHostSetServiceServer()
 }
 
@@ -230,6 +249,9 @@ func (UnimplementedHostSetServiceServer) SetHostSetHosts(context.Context, *SetHo
 func (UnimplementedHostSetServiceServer) RemoveHostSetHosts(context.Context, *RemoveHostSetHostsRequest) (*RemoveHostSetHostsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveHostSetHosts not implemented")
 }
+func (UnimplementedHostSetServiceServer) CloneHostSet(context.Context, *CloneHostSetRequest) (*CloneHostSetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneHostSet not implemented")
+}
 func (UnimplementedHostSetServiceServer) mustEmbedUnimplementedHostSetServiceServer() {}
 
 // UnsafeHostSetServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -387,6 +409,24 @@ func _HostSetService_RemoveHostSetHosts_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HostSetService_CloneHostSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneHostSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostSetServiceServer).CloneHostSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.HostSetService/CloneHostSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostSetServiceServer).CloneHostSet(ctx, req.(*CloneHostSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _HostSetService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.api.services.v1.HostSetService",
 	HandlerType: (*HostSetServiceServer)(nil),
@@ -423,6 +463,10 @@ var _HostSetService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveHostSetHosts",
 			Handler:    _HostSetService_RemoveHostSetHosts_Handler,
 		},
+		{
+			MethodName: "CloneHostSet",
+			Handler:    _HostSetService_CloneHostSet_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/api/services/v1/host_set_service.proto",
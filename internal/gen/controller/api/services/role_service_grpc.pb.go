@@ -78,6 +78,34 @@ type RoleServiceClient interface {
 	// grants will be removed. If missing, malformed, or references a non-existing
 	// resource, an error is returned.
 	RemoveRoleGrants(ctx context.Context, in *RemoveRoleGrantsRequest, opts ...grpc.CallOption) (*RemoveRoleGrantsResponse, error)
+	// AddTemporaryRolePrincipals adds principals to a Role until the expiration
+	// time, after which they no longer get its grants and are removed from it.
+	// It returns the temporary principals of the Role.
+	AddTemporaryRolePrincipals(ctx context.Context, in *AddTemporaryRolePrincipalsRequest, opts ...grpc.CallOption) (*AddTemporaryRolePrincipalsResponse, error)
+	// ListRolePrincipalExpirations returns the temporary principals of a Role
+	// and when they are removed from it.
+	ListRolePrincipalExpirations(ctx context.Context, in *ListRolePrincipalExpirationsRequest, opts ...grpc.CallOption) (*ListRolePrincipalExpirationsResponse, error)
+	// RequestRoleAccess creates a pending request by the caller to be assigned
+	// a Role for a duration. It requires the request-access action on the Role.
+	RequestRoleAccess(ctx context.Context, in *RequestRoleAccessRequest, opts ...grpc.CallOption) (*RequestRoleAccessResponse, error)
+	// ListRoleAccessRequests returns the access requests for a Role, newest
+	// first. Callers which may read the Role get all of them, callers which may
+	// only request access to it get their own.
+	ListRoleAccessRequests(ctx context.Context, in *ListRoleAccessRequestsRequest, opts ...grpc.CallOption) (*ListRoleAccessRequestsResponse, error)
+	// GetAccessRequest returns an access request with its events. Callers
+	// which may read its Role may read any request, its requester may read its
+	// own.
+	GetAccessRequest(ctx context.Context, in *GetAccessRequestRequest, opts ...grpc.CallOption) (*GetAccessRequestResponse, error)
+	// ApproveAccessRequest approves a pending access request, assigning its
+	// Role to its requester until its duration has passed. It requires the
+	// approve action on the Role and the caller may not be the requester.
+	ApproveAccessRequest(ctx context.Context, in *ApproveAccessRequestRequest, opts ...grpc.CallOption) (*ApproveAccessRequestResponse, error)
+	// DenyAccessRequest denies a pending access request. It requires the deny
+	// action on the Role and the caller may not be the requester.
+	DenyAccessRequest(ctx context.Context, in *DenyAccessRequestRequest, opts ...grpc.CallOption) (*DenyAccessRequestResponse, error)
+	// CancelAccessRequest cancels the caller's pending access request. It
+	// requires the request-access action on the Role.
+	CancelAccessRequest(ctx context.Context, in *CancelAccessRequestRequest, opts ...grpc.CallOption) (*CancelAccessRequestResponse, error)
 }
 
 type roleServiceClient struct {
@@ -187,6 +215,78 @@ func (c *roleServiceClient) RemoveRoleGrants(ctx context.Context, in *RemoveRole
 	return out, nil
 }
 
+func (c *roleServiceClient) AddTemporaryRolePrincipals(ctx context.Context, in *AddTemporaryRolePrincipalsRequest, opts ...grpc.CallOption) (*AddTemporaryRolePrincipalsResponse, error) {
+	out := new(AddTemporaryRolePrincipalsResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.RoleService/AddTemporaryRolePrincipals", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roleServiceClient) ListRolePrincipalExpirations(ctx context.Context, in *ListRolePrincipalExpirationsRequest, opts ...grpc.CallOption) (*ListRolePrincipalExpirationsResponse, error) {
+	out := new(ListRolePrincipalExpirationsResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.RoleService/ListRolePrincipalExpirations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roleServiceClient) RequestRoleAccess(ctx context.Context, in *RequestRoleAccessRequest, opts ...grpc.CallOption) (*RequestRoleAccessResponse, error) {
+	out := new(RequestRoleAccessResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.RoleService/RequestRoleAccess", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roleServiceClient) ListRoleAccessRequests(ctx context.Context, in *ListRoleAccessRequestsRequest, opts ...grpc.CallOption) (*ListRoleAccessRequestsResponse, error) {
+	out := new(ListRoleAccessRequestsResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.RoleService/ListRoleAccessRequests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roleServiceClient) GetAccessRequest(ctx context.Context, in *GetAccessRequestRequest, opts ...grpc.CallOption) (*GetAccessRequestResponse, error) {
+	out := new(GetAccessRequestResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.RoleService/GetAccessRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roleServiceClient) ApproveAccessRequest(ctx context.Context, in *ApproveAccessRequestRequest, opts ...grpc.CallOption) (*ApproveAccessRequestResponse, error) {
+	out := new(ApproveAccessRequestResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.RoleService/ApproveAccessRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roleServiceClient) DenyAccessRequest(ctx context.Context, in *DenyAccessRequestRequest, opts ...grpc.CallOption) (*DenyAccessRequestResponse, error) {
+	out := new(DenyAccessRequestResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.RoleService/DenyAccessRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roleServiceClient) CancelAccessRequest(ctx context.Context, in *CancelAccessRequestRequest, opts ...grpc.CallOption) (*CancelAccessRequestResponse, error) {
+	out := new(CancelAccessRequestResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.RoleService/CancelAccessRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RoleServiceServer is the server API for RoleService service.
 // All implementations must embed UnimplementedRoleServiceServer
 // for forward compatibility
@@ -252,6 +352,34 @@ type RoleServiceServer interface {
 	// grants will be removed. If missing, malformed, or references a non-existing
 	// resource, an error is returned.
 	RemoveRoleGrants(context.Context, *RemoveRoleGrantsRequest) (*RemoveRoleGrantsResponse, error)
+	// AddTemporaryRolePrincipals adds principals to a Role until the expiration
+	// time, after which they no longer get its grants and are removed from it.
+	// It returns the temporary principals of the Role.
+	AddTemporaryRolePrincipals(context.Context, *AddTemporaryRolePrincipalsRequest) (*AddTemporaryRolePrincipalsResponse, error)
+	// ListRolePrincipalExpirations returns the temporary principals of a Role
+	// and when they are removed from it.
+	ListRolePrincipalExpirations(context.Context, *ListRolePrincipalExpirationsRequest) (*ListRolePrincipalExpirationsResponse, error)
+	// RequestRoleAccess creates a pending request by the caller to be assigned
+	// a Role for a duration. It requires the request-access action on the Role.
+	RequestRoleAccess(context.Context, *RequestRoleAccessRequest) (*RequestRoleAccessResponse, error)
+	// ListRoleAccessRequests returns the access requests for a Role, newest
+	// first. Callers which may read the Role get all of them, callers which may
+	// only request access to it get their own.
+	ListRoleAccessRequests(context.Context, *ListRoleAccessRequestsRequest) (*ListRoleAccessRequestsResponse, error)
+	// GetAccessRequest returns an access request with its events. Callers
+	// which may read its Role may read any request, its requester may read its
+	// own.
+	GetAccessRequest(context.Context, *GetAccessRequestRequest) (*GetAccessRequestResponse, error)
+	// ApproveAccessRequest approves a pending access request, assigning its
+	// Role to its requester until its duration has passed. It requires the
+	// approve action on the Role and the caller may not be the requester.
+	ApproveAccessRequest(context.Context, *ApproveAccessRequestRequest) (*ApproveAccessRequestResponse, error)
+	// DenyAccessRequest denies a pending access request. It requires the deny
+	// action on the Role and the caller may not be the requester.
+	DenyAccessRequest(context.Context, *DenyAccessRequestRequest) (*DenyAccessRequestResponse, error)
+	// CancelAccessRequest cancels the caller's pending access request. It
+	// requires the request-access action on the Role.
+	CancelAccessRequest(context.Context, *CancelAccessRequestRequest) (*CancelAccessRequestResponse, error)
 	mustEmbedUnimplementedRoleServiceServer()
 }
 
@@ -292,6 +420,30 @@ func (UnimplementedRoleServiceServer) SetRoleGrants(context.Context, *SetRoleGra
 func (UnimplementedRoleServiceServer) RemoveRoleGrants(context.Context, *RemoveRoleGrantsRequest) (*RemoveRoleGrantsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveRoleGrants not implemented")
 }
+func (UnimplementedRoleServiceServer) AddTemporaryRolePrincipals(context.Context, *AddTemporaryRolePrincipalsRequest) (*AddTemporaryRolePrincipalsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTemporaryRolePrincipals not implemented")
+}
+func (UnimplementedRoleServiceServer) ListRolePrincipalExpirations(context.Context, *ListRolePrincipalExpirationsRequest) (*ListRolePrincipalExpirationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRolePrincipalExpirations not implemented")
+}
+func (UnimplementedRoleServiceServer) RequestRoleAccess(context.Context, *RequestRoleAccessRequest) (*RequestRoleAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestRoleAccess not implemented")
+}
+func (UnimplementedRoleServiceServer) ListRoleAccessRequests(context.Context, *ListRoleAccessRequestsRequest) (*ListRoleAccessRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRoleAccessRequests not implemented")
+}
+func (UnimplementedRoleServiceServer) GetAccessRequest(context.Context, *GetAccessRequestRequest) (*GetAccessRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccessRequest not implemented")
+}
+func (UnimplementedRoleServiceServer) ApproveAccessRequest(context.Context, *ApproveAccessRequestRequest) (*ApproveAccessRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveAccessRequest not implemented")
+}
+func (UnimplementedRoleServiceServer) DenyAccessRequest(context.Context, *DenyAccessRequestRequest) (*DenyAccessRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DenyAccessRequest not implemented")
+}
+func (UnimplementedRoleServiceServer) CancelAccessRequest(context.Context, *CancelAccessRequestRequest) (*CancelAccessRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelAccessRequest not implemented")
+}
 func (UnimplementedRoleServiceServer) mustEmbedUnimplementedRoleServiceServer() {}
 
 // UnsafeRoleServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -503,6 +655,150 @@ func _RoleService_RemoveRoleGrants_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RoleService_AddTemporaryRolePrincipals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTemporaryRolePrincipalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoleServiceServer).AddTemporaryRolePrincipals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.RoleService/AddTemporaryRolePrincipals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoleServiceServer).AddTemporaryRolePrincipals(ctx, req.(*AddTemporaryRolePrincipalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoleService_ListRolePrincipalExpirations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRolePrincipalExpirationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoleServiceServer).ListRolePrincipalExpirations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.RoleService/ListRolePrincipalExpirations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoleServiceServer).ListRolePrincipalExpirations(ctx, req.(*ListRolePrincipalExpirationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoleService_RequestRoleAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestRoleAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoleServiceServer).RequestRoleAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.RoleService/RequestRoleAccess",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoleServiceServer).RequestRoleAccess(ctx, req.(*RequestRoleAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoleService_ListRoleAccessRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRoleAccessRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoleServiceServer).ListRoleAccessRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.RoleService/ListRoleAccessRequests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoleServiceServer).ListRoleAccessRequests(ctx, req.(*ListRoleAccessRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoleService_GetAccessRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccessRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoleServiceServer).GetAccessRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.RoleService/GetAccessRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoleServiceServer).GetAccessRequest(ctx, req.(*GetAccessRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoleService_ApproveAccessRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveAccessRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoleServiceServer).ApproveAccessRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.RoleService/ApproveAccessRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoleServiceServer).ApproveAccessRequest(ctx, req.(*ApproveAccessRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoleService_DenyAccessRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DenyAccessRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoleServiceServer).DenyAccessRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.RoleService/DenyAccessRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoleServiceServer).DenyAccessRequest(ctx, req.(*DenyAccessRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoleService_CancelAccessRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelAccessRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoleServiceServer).CancelAccessRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.RoleService/CancelAccessRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoleServiceServer).CancelAccessRequest(ctx, req.(*CancelAccessRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _RoleService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.api.services.v1.RoleService",
 	HandlerType: (*RoleServiceServer)(nil),
@@ -551,6 +847,38 @@ var _RoleService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveRoleGrants",
 			Handler:    _RoleService_RemoveRoleGrants_Handler,
 		},
+		{
+			MethodName: "AddTemporaryRolePrincipals",
+			Handler:    _RoleService_AddTemporaryRolePrincipals_Handler,
+		},
+		{
+			MethodName: "ListRolePrincipalExpirations",
+			Handler:    _RoleService_ListRolePrincipalExpirations_Handler,
+		},
+		{
+			MethodName: "RequestRoleAccess",
+			Handler:    _RoleService_RequestRoleAccess_Handler,
+		},
+		{
+			MethodName: "ListRoleAccessRequests",
+			Handler:    _RoleService_ListRoleAccessRequests_Handler,
+		},
+		{
+			MethodName: "GetAccessRequest",
+			Handler:    _RoleService_GetAccessRequest_Handler,
+		},
+		{
+			MethodName: "ApproveAccessRequest",
+			Handler:    _RoleService_ApproveAccessRequest_Handler,
+		},
+		{
+			MethodName: "DenyAccessRequest",
+			Handler:    _RoleService_DenyAccessRequest_Handler,
+		},
+		{
+			MethodName: "CancelAccessRequest",
+			Handler:    _RoleService_CancelAccessRequest_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/api/services/v1/role_service.proto",
@@ -4,6 +4,7 @@ package services
 
 import (
 	context "context"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -43,6 +44,45 @@ type ScopeServiceClient interface {
 	// DeleteScope remotes a Scope and all child resources from Boundary. If the
 	// provided Scope IDs are malformed or not provided an error is returned.
 	DeleteScope(ctx context.Context, in *DeleteScopeRequest, opts ...grpc.CallOption) (*DeleteScopeResponse, error)
+	// GetScopeInactivityPolicy returns the inactivity policy of an org or the
+	// global Scope.
+	GetScopeInactivityPolicy(ctx context.Context, in *GetScopeInactivityPolicyRequest, opts ...grpc.CallOption) (*GetScopeInactivityPolicyResponse, error)
+	// SetScopeInactivityPolicy sets the inactivity policy of an org or the
+	// global Scope. Setting max_inactive_days to 0 removes it.
+	SetScopeInactivityPolicy(ctx context.Context, in *SetScopeInactivityPolicyRequest, opts ...grpc.CallOption) (*SetScopeInactivityPolicyResponse, error)
+	// GetScopeProjectTemplate returns the project template of an org. An org
+	// without a template returns a template with no roles and no target
+	// settings.
+	GetScopeProjectTemplate(ctx context.Context, in *GetScopeProjectTemplateRequest, opts ...grpc.CallOption) (*GetScopeProjectTemplateResponse, error)
+	// SetScopeProjectTemplate sets the project template of an org, replacing
+	// its current one. Projects already created in the org are not changed.
+	SetScopeProjectTemplate(ctx context.Context, in *SetScopeProjectTemplateRequest, opts ...grpc.CallOption) (*SetScopeProjectTemplateResponse, error)
+	// DeleteScopeProjectTemplate removes the project template of an org.
+	DeleteScopeProjectTemplate(ctx context.Context, in *DeleteScopeProjectTemplateRequest, opts ...grpc.CallOption) (*DeleteScopeProjectTemplateResponse, error)
+	// GetScopeSecurityEvents returns the counts of the authentication successes
+	// and failures, lockouts, auth token revocations and uses of the recovery
+	// KMS of an org or the global Scope by time bucket and auth method. If
+	// recursive is set the global Scope includes the events of the orgs the
+	// caller may read. Time buckets without events are omitted.
+	GetScopeSecurityEvents(ctx context.Context, in *GetScopeSecurityEventsRequest, opts ...grpc.CallOption) (*GetScopeSecurityEventsResponse, error)
+	// GetScopeUsage returns the daily API calls and Session minutes of a Scope
+	// for chargeback, from the UTC day start through end. The range defaults to
+	// the current UTC month through today. If recursive is set the global Scope
+	// includes the usage of the orgs and projects, and an org that of the
+	// projects, the caller may read. Days without usage are omitted.
+	GetScopeUsage(ctx context.Context, in *GetScopeUsageRequest, opts ...grpc.CallOption) (*GetScopeUsageResponse, error)
+	// ExportScopeUsage returns the daily usage items of GetScopeUsage as CSV
+	// with a header row. The totals are left out, since they are the sums of
+	// the rows of each Scope.
+	ExportScopeUsage(ctx context.Context, in *ExportScopeUsageRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+	// ListScopeEventSinks returns the event sinks of an org.
+	ListScopeEventSinks(ctx context.Context, in *ListScopeEventSinksRequest, opts ...grpc.CallOption) (*ListScopeEventSinksResponse, error)
+	// AddScopeEventSink adds an event sink to an org delivering to the url,
+	// signed with the secret, with the values of the redacted fields of the
+	// payloads replaced. The secret is write only.
+	AddScopeEventSink(ctx context.Context, in *AddScopeEventSinkRequest, opts ...grpc.CallOption) (*AddScopeEventSinkResponse, error)
+	// RemoveScopeEventSink removes an event sink from an org.
+	RemoveScopeEventSink(ctx context.Context, in *RemoveScopeEventSinkRequest, opts ...grpc.CallOption) (*RemoveScopeEventSinkResponse, error)
 }
 
 type scopeServiceClient struct {
@@ -98,6 +138,105 @@ func (c *scopeServiceClient) DeleteScope(ctx context.Context, in *DeleteScopeReq
 	return out, nil
 }
 
+func (c *scopeServiceClient) GetScopeInactivityPolicy(ctx context.Context, in *GetScopeInactivityPolicyRequest, opts ...grpc.CallOption) (*GetScopeInactivityPolicyResponse, error) {
+	out := new(GetScopeInactivityPolicyResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/GetScopeInactivityPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scopeServiceClient) SetScopeInactivityPolicy(ctx context.Context, in *SetScopeInactivityPolicyRequest, opts ...grpc.CallOption) (*SetScopeInactivityPolicyResponse, error) {
+	out := new(SetScopeInactivityPolicyResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/SetScopeInactivityPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scopeServiceClient) GetScopeProjectTemplate(ctx context.Context, in *GetScopeProjectTemplateRequest, opts ...grpc.CallOption) (*GetScopeProjectTemplateResponse, error) {
+	out := new(GetScopeProjectTemplateResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/GetScopeProjectTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scopeServiceClient) SetScopeProjectTemplate(ctx context.Context, in *SetScopeProjectTemplateRequest, opts ...grpc.CallOption) (*SetScopeProjectTemplateResponse, error) {
+	out := new(SetScopeProjectTemplateResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/SetScopeProjectTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scopeServiceClient) DeleteScopeProjectTemplate(ctx context.Context, in *DeleteScopeProjectTemplateRequest, opts ...grpc.CallOption) (*DeleteScopeProjectTemplateResponse, error) {
+	out := new(DeleteScopeProjectTemplateResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/DeleteScopeProjectTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scopeServiceClient) GetScopeSecurityEvents(ctx context.Context, in *GetScopeSecurityEventsRequest, opts ...grpc.CallOption) (*GetScopeSecurityEventsResponse, error) {
+	out := new(GetScopeSecurityEventsResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/GetScopeSecurityEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scopeServiceClient) GetScopeUsage(ctx context.Context, in *GetScopeUsageRequest, opts ...grpc.CallOption) (*GetScopeUsageResponse, error) {
+	out := new(GetScopeUsageResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/GetScopeUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scopeServiceClient) ExportScopeUsage(ctx context.Context, in *ExportScopeUsageRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error) {
+	out := new(httpbody.HttpBody)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/ExportScopeUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scopeServiceClient) ListScopeEventSinks(ctx context.Context, in *ListScopeEventSinksRequest, opts ...grpc.CallOption) (*ListScopeEventSinksResponse, error) {
+	out := new(ListScopeEventSinksResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/ListScopeEventSinks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scopeServiceClient) AddScopeEventSink(ctx context.Context, in *AddScopeEventSinkRequest, opts ...grpc.CallOption) (*AddScopeEventSinkResponse, error) {
+	out := new(AddScopeEventSinkResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/AddScopeEventSink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scopeServiceClient) RemoveScopeEventSink(ctx context.Context, in *RemoveScopeEventSinkRequest, opts ...grpc.CallOption) (*RemoveScopeEventSinkResponse, error) {
+	out := new(RemoveScopeEventSinkResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.ScopeService/RemoveScopeEventSink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ScopeServiceServer is the server API for ScopeService service.
 // All implementations must embed UnimplementedScopeServiceServer
 // for forward compatibility
@@ -128,6 +267,45 @@ type ScopeServiceServer interface {
 	// DeleteScope remotes a Scope and all child resources from Boundary. If the
 	// provided Scope IDs are malformed or not provided an error is returned.
 	DeleteScope(context.Context, *DeleteScopeRequest) (*DeleteScopeResponse, error)
+	// GetScopeInactivityPolicy returns the inactivity policy of an org or the
+	// global Scope.
+	GetScopeInactivityPolicy(context.Context, *GetScopeInactivityPolicyRequest) (*GetScopeInactivityPolicyResponse, error)
+	// SetScopeInactivityPolicy sets the inactivity policy of an org or the
+	// global Scope. Setting max_inactive_days to 0 removes it.
+	SetScopeInactivityPolicy(context.Context, *SetScopeInactivityPolicyRequest) (*SetScopeInactivityPolicyResponse, error)
+	// GetScopeProjectTemplate returns the project template of an org. An org
+	// without a template returns a template with no roles and no target
+	// settings.
+	GetScopeProjectTemplate(context.Context, *GetScopeProjectTemplateRequest) (*GetScopeProjectTemplateResponse, error)
+	// SetScopeProjectTemplate sets the project template of an org, replacing
+	// its current one. Projects already created in the org are not changed.
+	SetScopeProjectTemplate(context.Context, *SetScopeProjectTemplateRequest) (*SetScopeProjectTemplateResponse, error)
+	// DeleteScopeProjectTemplate removes the project template of an org.
+	DeleteScopeProjectTemplate(context.Context, *DeleteScopeProjectTemplateRequest) (*DeleteScopeProjectTemplateResponse, error)
+	// GetScopeSecurityEvents returns the counts of the authentication successes
+	// and failures, lockouts, auth token revocations and uses of the recovery
+	// KMS of an org or the global Scope by time bucket and auth method. If
+	// recursive is set the global Scope includes the events of the orgs the
+	// caller may read. Time buckets without events are omitted.
+	GetScopeSecurityEvents(context.Context, *GetScopeSecurityEventsRequest) (*GetScopeSecurityEventsResponse, error)
+	// GetScopeUsage returns the daily API calls and Session minutes of a Scope
+	// for chargeback, from the UTC day start through end. The range defaults to
+	// the current UTC month through today. If recursive is set the global Scope
+	// includes the usage of the orgs and projects, and an org that of the
+	// projects, the caller may read. Days without usage are omitted.
+	GetScopeUsage(context.Context, *GetScopeUsageRequest) (*GetScopeUsageResponse, error)
+	// ExportScopeUsage returns the daily usage items of GetScopeUsage as CSV
+	// with a header row. The totals are left out, since they are the sums of
+	// the rows of each Scope.
+	ExportScopeUsage(context.Context, *ExportScopeUsageRequest) (*httpbody.HttpBody, error)
+	// ListScopeEventSinks returns the event sinks of an org.
+	ListScopeEventSinks(context.Context, *ListScopeEventSinksRequest) (*ListScopeEventSinksResponse, error)
+	// AddScopeEventSink adds an event sink to an org delivering to the url,
+	// signed with the secret, with the values of the redacted fields of the
+	// payloads replaced. The secret is write only.
+	AddScopeEventSink(context.Context, *AddScopeEventSinkRequest) (*AddScopeEventSinkResponse, error)
+	// RemoveScopeEventSink removes an event sink from an org.
+	RemoveScopeEventSink(context.Context, *RemoveScopeEventSinkRequest) (*RemoveScopeEventSinkResponse, error)
 	mustEmbedUnimplementedScopeServiceServer()
 }
 
@@ -150,6 +328,39 @@ func (UnimplementedScopeServiceServer) UpdateScope(context.Context, *UpdateScope
 func (UnimplementedScopeServiceServer) DeleteScope(context.Context, *DeleteScopeRequest) (*DeleteScopeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteScope not implemented")
 }
+func (UnimplementedScopeServiceServer) GetScopeInactivityPolicy(context.Context, *GetScopeInactivityPolicyRequest) (*GetScopeInactivityPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScopeInactivityPolicy not implemented")
+}
+func (UnimplementedScopeServiceServer) SetScopeInactivityPolicy(context.Context, *SetScopeInactivityPolicyRequest) (*SetScopeInactivityPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetScopeInactivityPolicy not implemented")
+}
+func (UnimplementedScopeServiceServer) GetScopeProjectTemplate(context.Context, *GetScopeProjectTemplateRequest) (*GetScopeProjectTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScopeProjectTemplate not implemented")
+}
+func (UnimplementedScopeServiceServer) SetScopeProjectTemplate(context.Context, *SetScopeProjectTemplateRequest) (*SetScopeProjectTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetScopeProjectTemplate not implemented")
+}
+func (UnimplementedScopeServiceServer) DeleteScopeProjectTemplate(context.Context, *DeleteScopeProjectTemplateRequest) (*DeleteScopeProjectTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteScopeProjectTemplate not implemented")
+}
+func (UnimplementedScopeServiceServer) GetScopeSecurityEvents(context.Context, *GetScopeSecurityEventsRequest) (*GetScopeSecurityEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScopeSecurityEvents not implemented")
+}
+func (UnimplementedScopeServiceServer) GetScopeUsage(context.Context, *GetScopeUsageRequest) (*GetScopeUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScopeUsage not implemented")
+}
+func (UnimplementedScopeServiceServer) ExportScopeUsage(context.Context, *ExportScopeUsageRequest) (*httpbody.HttpBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportScopeUsage not implemented")
+}
+func (UnimplementedScopeServiceServer) ListScopeEventSinks(context.Context, *ListScopeEventSinksRequest) (*ListScopeEventSinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScopeEventSinks not implemented")
+}
+func (UnimplementedScopeServiceServer) AddScopeEventSink(context.Context, *AddScopeEventSinkRequest) (*AddScopeEventSinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddScopeEventSink not implemented")
+}
+func (UnimplementedScopeServiceServer) RemoveScopeEventSink(context.Context, *RemoveScopeEventSinkRequest) (*RemoveScopeEventSinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveScopeEventSink not implemented")
+}
 func (UnimplementedScopeServiceServer) mustEmbedUnimplementedScopeServiceServer() {}
 
 // UnsafeScopeServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -253,6 +464,204 @@ func _ScopeService_DeleteScope_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ScopeService_GetScopeInactivityPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScopeInactivityPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).GetScopeInactivityPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/GetScopeInactivityPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).GetScopeInactivityPolicy(ctx, req.(*GetScopeInactivityPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScopeService_SetScopeInactivityPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetScopeInactivityPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).SetScopeInactivityPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/SetScopeInactivityPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).SetScopeInactivityPolicy(ctx, req.(*SetScopeInactivityPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScopeService_GetScopeProjectTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScopeProjectTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).GetScopeProjectTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/GetScopeProjectTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).GetScopeProjectTemplate(ctx, req.(*GetScopeProjectTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScopeService_SetScopeProjectTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetScopeProjectTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).SetScopeProjectTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/SetScopeProjectTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).SetScopeProjectTemplate(ctx, req.(*SetScopeProjectTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScopeService_DeleteScopeProjectTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteScopeProjectTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).DeleteScopeProjectTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/DeleteScopeProjectTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).DeleteScopeProjectTemplate(ctx, req.(*DeleteScopeProjectTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScopeService_GetScopeSecurityEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScopeSecurityEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).GetScopeSecurityEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/GetScopeSecurityEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).GetScopeSecurityEvents(ctx, req.(*GetScopeSecurityEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScopeService_GetScopeUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScopeUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).GetScopeUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/GetScopeUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).GetScopeUsage(ctx, req.(*GetScopeUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScopeService_ExportScopeUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportScopeUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).ExportScopeUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/ExportScopeUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).ExportScopeUsage(ctx, req.(*ExportScopeUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScopeService_ListScopeEventSinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScopeEventSinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).ListScopeEventSinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/ListScopeEventSinks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).ListScopeEventSinks(ctx, req.(*ListScopeEventSinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScopeService_AddScopeEventSink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddScopeEventSinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).AddScopeEventSink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/AddScopeEventSink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).AddScopeEventSink(ctx, req.(*AddScopeEventSinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScopeService_RemoveScopeEventSink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveScopeEventSinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeServiceServer).RemoveScopeEventSink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.ScopeService/RemoveScopeEventSink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeServiceServer).RemoveScopeEventSink(ctx, req.(*RemoveScopeEventSinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ScopeService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.api.services.v1.ScopeService",
 	HandlerType: (*ScopeServiceServer)(nil),
@@ -277,6 +686,50 @@ var _ScopeService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteScope",
 			Handler:    _ScopeService_DeleteScope_Handler,
 		},
+		{
+			MethodName: "GetScopeInactivityPolicy",
+			Handler:    _ScopeService_GetScopeInactivityPolicy_Handler,
+		},
+		{
+			MethodName: "SetScopeInactivityPolicy",
+			Handler:    _ScopeService_SetScopeInactivityPolicy_Handler,
+		},
+		{
+			MethodName: "GetScopeProjectTemplate",
+			Handler:    _ScopeService_GetScopeProjectTemplate_Handler,
+		},
+		{
+			MethodName: "SetScopeProjectTemplate",
+			Handler:    _ScopeService_SetScopeProjectTemplate_Handler,
+		},
+		{
+			MethodName: "DeleteScopeProjectTemplate",
+			Handler:    _ScopeService_DeleteScopeProjectTemplate_Handler,
+		},
+		{
+			MethodName: "GetScopeSecurityEvents",
+			Handler:    _ScopeService_GetScopeSecurityEvents_Handler,
+		},
+		{
+			MethodName: "GetScopeUsage",
+			Handler:    _ScopeService_GetScopeUsage_Handler,
+		},
+		{
+			MethodName: "ExportScopeUsage",
+			Handler:    _ScopeService_ExportScopeUsage_Handler,
+		},
+		{
+			MethodName: "ListScopeEventSinks",
+			Handler:    _ScopeService_ListScopeEventSinks_Handler,
+		},
+		{
+			MethodName: "AddScopeEventSink",
+			Handler:    _ScopeService_AddScopeEventSink_Handler,
+		},
+		{
+			MethodName: "RemoveScopeEventSink",
+			Handler:    _ScopeService_RemoveScopeEventSink_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/api/services/v1/scope_service.proto",
@@ -31,6 +31,9 @@ type SessionServiceClient interface {
 	// is returned if the request attempts to cancel a Session that does
 	// not exist.
 	CancelSession(ctx context.Context, in *CancelSessionRequest, opts ...grpc.CallOption) (*CancelSessionResponse, error)
+	// ListSessionConnectionStats returns the connection counts and byte totals
+	// of the Sessions in the provided project scope.
+	ListSessionConnectionStats(ctx context.Context, in *ListSessionConnectionStatsRequest, opts ...grpc.CallOption) (*ListSessionConnectionStatsResponse, error)
 }
 
 type sessionServiceClient struct {
@@ -68,6 +71,15 @@ func (c *sessionServiceClient) CancelSession(ctx context.Context, in *CancelSess
 	return out, nil
 }
 
+func (c *sessionServiceClient) ListSessionConnectionStats(ctx context.Context, in *ListSessionConnectionStatsRequest, opts ...grpc.CallOption) (*ListSessionConnectionStatsResponse, error) {
+	out := new(ListSessionConnectionStatsResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.SessionService/ListSessionConnectionStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SessionServiceServer is the server API for SessionService service.
 // All implementations must embed UnimplementedSessionServiceServer
 // for forward compatibility
@@ -86,6 +98,9 @@ type SessionServiceServer interface {
 	// is returned if the request attempts to cancel a Session that does
 	// not exist.
 	CancelSession(context.Context, *CancelSessionRequest) (*CancelSessionResponse, error)
+	// ListSessionConnectionStats returns the connection counts and byte totals
+	// of the Sessions in the provided project scope.
+	ListSessionConnectionStats(context.Context, *ListSessionConnectionStatsRequest) (*ListSessionConnectionStatsResponse, error)
 	mustEmbedUnimplementedSessionServiceServer()
 }
 
@@ -102,6 +117,9 @@ func (UnimplementedSessionServiceServer) ListSessions(context.Context, *ListSess
 func (UnimplementedSessionServiceServer) CancelSession(context.Context, *CancelSessionRequest) (*CancelSessionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CancelSession not implemented")
 }
+func (UnimplementedSessionServiceServer) ListSessionConnectionStats(context.Context, *ListSessionConnectionStatsRequest) (*ListSessionConnectionStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessionConnectionStats not implemented")
+}
 func (UnimplementedSessionServiceServer) mustEmbedUnimplementedSessionServiceServer() {}
 
 // UnsafeSessionServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -169,6 +187,24 @@ func _SessionService_CancelSession_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SessionService_ListSessionConnectionStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionConnectionStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).ListSessionConnectionStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.SessionService/ListSessionConnectionStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).ListSessionConnectionStats(ctx, req.(*ListSessionConnectionStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SessionService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.api.services.v1.SessionService",
 	HandlerType: (*SessionServiceServer)(nil),
@@ -185,6 +221,10 @@ var _SessionService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CancelSession",
 			Handler:    _SessionService_CancelSession_Handler,
 		},
+		{
+			MethodName: "ListSessionConnectionStats",
+			Handler:    _SessionService_ListSessionConnectionStats_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/api/services/v1/session_service.proto",
@@ -70,6 +70,70 @@ type TargetServiceClient interface {
 	// returned.  An error is returned if a Host Set is attempted to be
 	// removed from the Target when the Target does not have the Host Set.
 	RemoveTargetHostSets(ctx context.Context, in *RemoveTargetHostSetsRequest, opts ...grpc.CallOption) (*RemoveTargetHostSetsResponse, error)
+	// GetTargetConnectionAuthorization returns whether connections to Sessions
+	// of the Target must be authorized again.
+	GetTargetConnectionAuthorization(ctx context.Context, in *GetTargetConnectionAuthorizationRequest, opts ...grpc.CallOption) (*GetTargetConnectionAuthorizationResponse, error)
+	// SetTargetConnectionAuthorization sets whether connections to Sessions of
+	// the Target must be authorized again.
+	SetTargetConnectionAuthorization(ctx context.Context, in *SetTargetConnectionAuthorizationRequest, opts ...grpc.CallOption) (*SetTargetConnectionAuthorizationResponse, error)
+	// GetTargetHistory returns a page of the history of the Target, rendered
+	// from its oplog entries, newest first. The values of sensitive fields are
+	// redacted.
+	GetTargetHistory(ctx context.Context, in *GetTargetHistoryRequest, opts ...grpc.CallOption) (*GetTargetHistoryResponse, error)
+	// GetTargetBandwidthLimit returns the bandwidth limits of the Target.
+	GetTargetBandwidthLimit(ctx context.Context, in *GetTargetBandwidthLimitRequest, opts ...grpc.CallOption) (*GetTargetBandwidthLimitResponse, error)
+	// SetTargetBandwidthLimit sets the bandwidth limits of the Target. They
+	// apply to Sessions looked up by workers afterwards.
+	SetTargetBandwidthLimit(ctx context.Context, in *SetTargetBandwidthLimitRequest, opts ...grpc.CallOption) (*SetTargetBandwidthLimitResponse, error)
+	// TestTargetConnection asks the worker Sessions of the Target would use to
+	// connect to its Hosts, and optionally to perform a TLS handshake with them,
+	// and waits for the worker to report the results.
+	TestTargetConnection(ctx context.Context, in *TestTargetConnectionRequest, opts ...grpc.CallOption) (*TestTargetConnectionResponse, error)
+	// GetTargetCredentialCheckout returns the credential checkout policy of a
+	// Target and the current holder of its credential.
+	GetTargetCredentialCheckout(ctx context.Context, in *GetTargetCredentialCheckoutRequest, opts ...grpc.CallOption) (*GetTargetCredentialCheckoutResponse, error)
+	// SetTargetCredentialCheckout sets the credential checkout policy of a
+	// Target. An empty mode removes it. It applies to sessions authorized
+	// afterwards.
+	SetTargetCredentialCheckout(ctx context.Context, in *SetTargetCredentialCheckoutRequest, opts ...grpc.CallOption) (*SetTargetCredentialCheckoutResponse, error)
+	// CloneTarget creates a new Target in the scope of a Target with its
+	// configuration, Host Sets, connection authorization, bandwidth limit and
+	// credential checkout policy. The description of the Target is copied
+	// unless one is provided. The caller must be allowed to read the Target and
+	// to create Targets in its scope.
+	CloneTarget(ctx context.Context, in *CloneTargetRequest, opts ...grpc.CallOption) (*CloneTargetResponse, error)
+	// GetTargetPeerIdentity returns how workers pass the identity of the User
+	// of a Session to the endpoints of a Target.
+	GetTargetPeerIdentity(ctx context.Context, in *GetTargetPeerIdentityRequest, opts ...grpc.CallOption) (*GetTargetPeerIdentityResponse, error)
+	// SetTargetPeerIdentity sets how workers pass the identity of the User of a
+	// Session to the endpoints of a Target. It applies to Sessions looked up by
+	// workers afterwards.
+	SetTargetPeerIdentity(ctx context.Context, in *SetTargetPeerIdentityRequest, opts ...grpc.CallOption) (*SetTargetPeerIdentityResponse, error)
+	// BatchGetTargets returns the Targets with the ids, in the order of the
+	// ids, looking them all up with a single query. Targets which don't exist
+	// or which the caller may not read are omitted rather than failing the
+	// request.
+	BatchGetTargets(ctx context.Context, in *BatchGetTargetsRequest, opts ...grpc.CallOption) (*BatchGetTargetsResponse, error)
+	// GetTargetConnectionMetadata returns the connection metadata of a Target.
+	GetTargetConnectionMetadata(ctx context.Context, in *GetTargetConnectionMetadataRequest, opts ...grpc.CallOption) (*GetTargetConnectionMetadataResponse, error)
+	// SetTargetConnectionMetadata replaces the connection metadata of a Target.
+	// An empty map removes it. It is returned with Sessions authorized
+	// afterwards.
+	SetTargetConnectionMetadata(ctx context.Context, in *SetTargetConnectionMetadataRequest, opts ...grpc.CallOption) (*SetTargetConnectionMetadataResponse, error)
+	// GetTargetUserNameTemplate returns the template of the user name protocol
+	// handlers inject into the connections of the Sessions of a Target.
+	GetTargetUserNameTemplate(ctx context.Context, in *GetTargetUserNameTemplateRequest, opts ...grpc.CallOption) (*GetTargetUserNameTemplateResponse, error)
+	// SetTargetUserNameTemplate sets the template of the user name protocol
+	// handlers inject into the connections of the Sessions of a Target. It
+	// applies to Sessions looked up by workers afterwards.
+	SetTargetUserNameTemplate(ctx context.Context, in *SetTargetUserNameTemplateRequest, opts ...grpc.CallOption) (*SetTargetUserNameTemplateResponse, error)
+	// GetTargetConnectionRateLimit returns the number of connections each
+	// Session of a Target may open per minute.
+	GetTargetConnectionRateLimit(ctx context.Context, in *GetTargetConnectionRateLimitRequest, opts ...grpc.CallOption) (*GetTargetConnectionRateLimitResponse, error)
+	// SetTargetConnectionRateLimit sets the number of connections each Session
+	// of a Target may open per minute. It applies to Sessions looked up by
+	// workers afterwards.
+	SetTargetConnectionRateLimit(ctx context.Context, in *SetTargetConnectionRateLimitRequest, opts ...grpc.CallOption) (*SetTargetConnectionRateLimitResponse, error)
 }
 
 type targetServiceClient struct {
@@ -161,6 +225,168 @@ func (c *targetServiceClient) RemoveTargetHostSets(ctx context.Context, in *Remo
 	return out, nil
 }
 
+func (c *targetServiceClient) GetTargetConnectionAuthorization(ctx context.Context, in *GetTargetConnectionAuthorizationRequest, opts ...grpc.CallOption) (*GetTargetConnectionAuthorizationResponse, error) {
+	out := new(GetTargetConnectionAuthorizationResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/GetTargetConnectionAuthorization", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) SetTargetConnectionAuthorization(ctx context.Context, in *SetTargetConnectionAuthorizationRequest, opts ...grpc.CallOption) (*SetTargetConnectionAuthorizationResponse, error) {
+	out := new(SetTargetConnectionAuthorizationResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/SetTargetConnectionAuthorization", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) GetTargetHistory(ctx context.Context, in *GetTargetHistoryRequest, opts ...grpc.CallOption) (*GetTargetHistoryResponse, error) {
+	out := new(GetTargetHistoryResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/GetTargetHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) GetTargetBandwidthLimit(ctx context.Context, in *GetTargetBandwidthLimitRequest, opts ...grpc.CallOption) (*GetTargetBandwidthLimitResponse, error) {
+	out := new(GetTargetBandwidthLimitResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/GetTargetBandwidthLimit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) SetTargetBandwidthLimit(ctx context.Context, in *SetTargetBandwidthLimitRequest, opts ...grpc.CallOption) (*SetTargetBandwidthLimitResponse, error) {
+	out := new(SetTargetBandwidthLimitResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/SetTargetBandwidthLimit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) TestTargetConnection(ctx context.Context, in *TestTargetConnectionRequest, opts ...grpc.CallOption) (*TestTargetConnectionResponse, error) {
+	out := new(TestTargetConnectionResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/TestTargetConnection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) GetTargetCredentialCheckout(ctx context.Context, in *GetTargetCredentialCheckoutRequest, opts ...grpc.CallOption) (*GetTargetCredentialCheckoutResponse, error) {
+	out := new(GetTargetCredentialCheckoutResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/GetTargetCredentialCheckout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) SetTargetCredentialCheckout(ctx context.Context, in *SetTargetCredentialCheckoutRequest, opts ...grpc.CallOption) (*SetTargetCredentialCheckoutResponse, error) {
+	out := new(SetTargetCredentialCheckoutResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/SetTargetCredentialCheckout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) CloneTarget(ctx context.Context, in *CloneTargetRequest, opts ...grpc.CallOption) (*CloneTargetResponse, error) {
+	out := new(CloneTargetResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/CloneTarget", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) GetTargetPeerIdentity(ctx context.Context, in *GetTargetPeerIdentityRequest, opts ...grpc.CallOption) (*GetTargetPeerIdentityResponse, error) {
+	out := new(GetTargetPeerIdentityResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/GetTargetPeerIdentity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) SetTargetPeerIdentity(ctx context.Context, in *SetTargetPeerIdentityRequest, opts ...grpc.CallOption) (*SetTargetPeerIdentityResponse, error) {
+	out := new(SetTargetPeerIdentityResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/SetTargetPeerIdentity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) BatchGetTargets(ctx context.Context, in *BatchGetTargetsRequest, opts ...grpc.CallOption) (*BatchGetTargetsResponse, error) {
+	out := new(BatchGetTargetsResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/BatchGetTargets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) GetTargetConnectionMetadata(ctx context.Context, in *GetTargetConnectionMetadataRequest, opts ...grpc.CallOption) (*GetTargetConnectionMetadataResponse, error) {
+	out := new(GetTargetConnectionMetadataResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/GetTargetConnectionMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) SetTargetConnectionMetadata(ctx context.Context, in *SetTargetConnectionMetadataRequest, opts ...grpc.CallOption) (*SetTargetConnectionMetadataResponse, error) {
+	out := new(SetTargetConnectionMetadataResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/SetTargetConnectionMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) GetTargetUserNameTemplate(ctx context.Context, in *GetTargetUserNameTemplateRequest, opts ...grpc.CallOption) (*GetTargetUserNameTemplateResponse, error) {
+	out := new(GetTargetUserNameTemplateResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/GetTargetUserNameTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) SetTargetUserNameTemplate(ctx context.Context, in *SetTargetUserNameTemplateRequest, opts ...grpc.CallOption) (*SetTargetUserNameTemplateResponse, error) {
+	out := new(SetTargetUserNameTemplateResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/SetTargetUserNameTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) GetTargetConnectionRateLimit(ctx context.Context, in *GetTargetConnectionRateLimitRequest, opts ...grpc.CallOption) (*GetTargetConnectionRateLimitResponse, error) {
+	out := new(GetTargetConnectionRateLimitResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/GetTargetConnectionRateLimit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *targetServiceClient) SetTargetConnectionRateLimit(ctx context.Context, in *SetTargetConnectionRateLimitRequest, opts ...grpc.CallOption) (*SetTargetConnectionRateLimitResponse, error) {
+	out := new(SetTargetConnectionRateLimitResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.TargetService/SetTargetConnectionRateLimit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TargetServiceServer is the server API for TargetService service.
 // All implementations must embed UnimplementedTargetServiceServer
 // for forward compatibility
@@ -218,6 +444,70 @@ type TargetServiceServer interface {
 	// returned.  An error is returned if a Host Set is attempted to be
 	// removed from the Target when the Target does not have the Host Set.
 	RemoveTargetHostSets(context.Context, *RemoveTargetHostSetsRequest) (*RemoveTargetHostSetsResponse, error)
+	// GetTargetConnectionAuthorization returns whether connections to Sessions
+	// of the Target must be authorized again.
+	GetTargetConnectionAuthorization(context.Context, *GetTargetConnectionAuthorizationRequest) (*GetTargetConnectionAuthorizationResponse, error)
+	// SetTargetConnectionAuthorization sets whether connections to Sessions of
+	// the Target must be authorized again.
+	SetTargetConnectionAuthorization(context.Context, *SetTargetConnectionAuthorizationRequest) (*SetTargetConnectionAuthorizationResponse, error)
+	// GetTargetHistory returns a page of the history of the Target, rendered
+	// from its oplog entries, newest first. The values of sensitive fields are
+	// redacted.
+	GetTargetHistory(context.Context, *GetTargetHistoryRequest) (*GetTargetHistoryResponse, error)
+	// GetTargetBandwidthLimit returns the bandwidth limits of the Target.
+	GetTargetBandwidthLimit(context.Context, *GetTargetBandwidthLimitRequest) (*GetTargetBandwidthLimitResponse, error)
+	// SetTargetBandwidthLimit sets the bandwidth limits of the Target. They
+	// apply to Sessions looked up by workers afterwards.
+	SetTargetBandwidthLimit(context.Context, *SetTargetBandwidthLimitRequest) (*SetTargetBandwidthLimitResponse, error)
+	// TestTargetConnection asks the worker Sessions of the Target would use to
+	// connect to its Hosts, and optionally to perform a TLS handshake with them,
+	// and waits for the worker to report the results.
+	TestTargetConnection(context.Context, *TestTargetConnectionRequest) (*TestTargetConnectionResponse, error)
+	// GetTargetCredentialCheckout returns the credential checkout policy of a
+	// Target and the current holder of its credential.
+	GetTargetCredentialCheckout(context.Context, *GetTargetCredentialCheckoutRequest) (*GetTargetCredentialCheckoutResponse, error)
+	// SetTargetCredentialCheckout sets the credential checkout policy of a
+	// Target. An empty mode removes it. It applies to sessions authorized
+	// afterwards.
+	SetTargetCredentialCheckout(context.Context, *SetTargetCredentialCheckoutRequest) (*SetTargetCredentialCheckoutResponse, error)
+	// CloneTarget creates a new Target in the scope of a Target with its
+	// configuration, Host Sets, connection authorization, bandwidth limit and
+	// credential checkout policy. The description of the Target is copied
+	// unless one is provided. The caller must be allowed to read the Target and
+	// to create Targets in its scope.
+	CloneTarget(context.Context, *CloneTargetRequest) (*CloneTargetResponse, error)
+	// GetTargetPeerIdentity returns how workers pass the identity of the User
+	// of a Session to the endpoints of a Target.
+	GetTargetPeerIdentity(context.Context, *GetTargetPeerIdentityRequest) (*GetTargetPeerIdentityResponse, error)
+	// SetTargetPeerIdentity sets how workers pass the identity of the User of a
+	// Session to the endpoints of a Target. It applies to Sessions looked up by
+	// workers afterwards.
+	SetTargetPeerIdentity(context.Context, *SetTargetPeerIdentityRequest) (*SetTargetPeerIdentityResponse, error)
+	// BatchGetTargets returns the Targets with the ids, in the order of the
+	// ids, looking them all up with a single query. Targets which don't exist
+	// or which the caller may not read are omitted rather than failing the
+	// request.
+	BatchGetTargets(context.Context, *BatchGetTargetsRequest) (*BatchGetTargetsResponse, error)
+	// GetTargetConnectionMetadata returns the connection metadata of a Target.
+	GetTargetConnectionMetadata(context.Context, *GetTargetConnectionMetadataRequest) (*GetTargetConnectionMetadataResponse, error)
+	// SetTargetConnectionMetadata replaces the connection metadata of a Target.
+	// An empty map removes it. It is returned with Sessions authorized
+	// afterwards.
+	SetTargetConnectionMetadata(context.Context, *SetTargetConnectionMetadataRequest) (*SetTargetConnectionMetadataResponse, error)
+	// GetTargetUserNameTemplate returns the template of the user name protocol
+	// handlers inject into the connections of the Sessions of a Target.
+	GetTargetUserNameTemplate(context.Context, *GetTargetUserNameTemplateRequest) (*GetTargetUserNameTemplateResponse, error)
+	// SetTargetUserNameTemplate sets the template of the user name protocol
+	// handlers inject into the connections of the Sessions of a Target. It
+	// applies to Sessions looked up by workers afterwards.
+	SetTargetUserNameTemplate(context.Context, *SetTargetUserNameTemplateRequest) (*SetTargetUserNameTemplateResponse, error)
+	// GetTargetConnectionRateLimit returns the number of connections each
+	// Session of a Target may open per minute.
+	GetTargetConnectionRateLimit(context.Context, *GetTargetConnectionRateLimitRequest) (*GetTargetConnectionRateLimitResponse, error)
+	// SetTargetConnectionRateLimit sets the number of connections each Session
+	// of a Target may open per minute. It applies to Sessions looked up by
+	// workers afterwards.
+	SetTargetConnectionRateLimit(context.Context, *SetTargetConnectionRateLimitRequest) (*SetTargetConnectionRateLimitResponse, error)
 	mustEmbedUnimplementedTargetServiceServer()
 }
 
@@ -252,6 +542,60 @@ func (UnimplementedTargetServiceServer) SetTargetHostSets(context.Context, *SetT
 func (UnimplementedTargetServiceServer) RemoveTargetHostSets(context.Context, *RemoveTargetHostSetsRequest) (*RemoveTargetHostSetsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveTargetHostSets not implemented")
 }
+func (UnimplementedTargetServiceServer) GetTargetConnectionAuthorization(context.Context, *GetTargetConnectionAuthorizationRequest) (*GetTargetConnectionAuthorizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTargetConnectionAuthorization not implemented")
+}
+func (UnimplementedTargetServiceServer) SetTargetConnectionAuthorization(context.Context, *SetTargetConnectionAuthorizationRequest) (*SetTargetConnectionAuthorizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTargetConnectionAuthorization not implemented")
+}
+func (UnimplementedTargetServiceServer) GetTargetHistory(context.Context, *GetTargetHistoryRequest) (*GetTargetHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTargetHistory not implemented")
+}
+func (UnimplementedTargetServiceServer) GetTargetBandwidthLimit(context.Context, *GetTargetBandwidthLimitRequest) (*GetTargetBandwidthLimitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTargetBandwidthLimit not implemented")
+}
+func (UnimplementedTargetServiceServer) SetTargetBandwidthLimit(context.Context, *SetTargetBandwidthLimitRequest) (*SetTargetBandwidthLimitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTargetBandwidthLimit not implemented")
+}
+func (UnimplementedTargetServiceServer) TestTargetConnection(context.Context, *TestTargetConnectionRequest) (*TestTargetConnectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TestTargetConnection not implemented")
+}
+func (UnimplementedTargetServiceServer) GetTargetCredentialCheckout(context.Context, *GetTargetCredentialCheckoutRequest) (*GetTargetCredentialCheckoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTargetCredentialCheckout not implemented")
+}
+func (UnimplementedTargetServiceServer) SetTargetCredentialCheckout(context.Context, *SetTargetCredentialCheckoutRequest) (*SetTargetCredentialCheckoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTargetCredentialCheckout not implemented")
+}
+func (UnimplementedTargetServiceServer) CloneTarget(context.Context, *CloneTargetRequest) (*CloneTargetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneTarget not implemented")
+}
+func (UnimplementedTargetServiceServer) GetTargetPeerIdentity(context.Context, *GetTargetPeerIdentityRequest) (*GetTargetPeerIdentityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTargetPeerIdentity not implemented")
+}
+func (UnimplementedTargetServiceServer) SetTargetPeerIdentity(context.Context, *SetTargetPeerIdentityRequest) (*SetTargetPeerIdentityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTargetPeerIdentity not implemented")
+}
+func (UnimplementedTargetServiceServer) BatchGetTargets(context.Context, *BatchGetTargetsRequest) (*BatchGetTargetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetTargets not implemented")
+}
+func (UnimplementedTargetServiceServer) GetTargetConnectionMetadata(context.Context, *GetTargetConnectionMetadataRequest) (*GetTargetConnectionMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTargetConnectionMetadata not implemented")
+}
+func (UnimplementedTargetServiceServer) SetTargetConnectionMetadata(context.Context, *SetTargetConnectionMetadataRequest) (*SetTargetConnectionMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTargetConnectionMetadata not implemented")
+}
+func (UnimplementedTargetServiceServer) GetTargetUserNameTemplate(context.Context, *GetTargetUserNameTemplateRequest) (*GetTargetUserNameTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTargetUserNameTemplate not implemented")
+}
+func (UnimplementedTargetServiceServer) SetTargetUserNameTemplate(context.Context, *SetTargetUserNameTemplateRequest) (*SetTargetUserNameTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTargetUserNameTemplate not implemented")
+}
+func (UnimplementedTargetServiceServer) GetTargetConnectionRateLimit(context.Context, *GetTargetConnectionRateLimitRequest) (*GetTargetConnectionRateLimitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTargetConnectionRateLimit not implemented")
+}
+func (UnimplementedTargetServiceServer) SetTargetConnectionRateLimit(context.Context, *SetTargetConnectionRateLimitRequest) (*SetTargetConnectionRateLimitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTargetConnectionRateLimit not implemented")
+}
 func (UnimplementedTargetServiceServer) mustEmbedUnimplementedTargetServiceServer() {}
 
 // UnsafeTargetServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -427,6 +771,330 @@ func _TargetService_RemoveTargetHostSets_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TargetService_GetTargetConnectionAuthorization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetConnectionAuthorizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).GetTargetConnectionAuthorization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/GetTargetConnectionAuthorization",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).GetTargetConnectionAuthorization(ctx, req.(*GetTargetConnectionAuthorizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_SetTargetConnectionAuthorization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTargetConnectionAuthorizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).SetTargetConnectionAuthorization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/SetTargetConnectionAuthorization",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).SetTargetConnectionAuthorization(ctx, req.(*SetTargetConnectionAuthorizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_GetTargetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).GetTargetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/GetTargetHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).GetTargetHistory(ctx, req.(*GetTargetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_GetTargetBandwidthLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetBandwidthLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).GetTargetBandwidthLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/GetTargetBandwidthLimit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).GetTargetBandwidthLimit(ctx, req.(*GetTargetBandwidthLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_SetTargetBandwidthLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTargetBandwidthLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).SetTargetBandwidthLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/SetTargetBandwidthLimit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).SetTargetBandwidthLimit(ctx, req.(*SetTargetBandwidthLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_TestTargetConnection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestTargetConnectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).TestTargetConnection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/TestTargetConnection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).TestTargetConnection(ctx, req.(*TestTargetConnectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_GetTargetCredentialCheckout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetCredentialCheckoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).GetTargetCredentialCheckout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/GetTargetCredentialCheckout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).GetTargetCredentialCheckout(ctx, req.(*GetTargetCredentialCheckoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_SetTargetCredentialCheckout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTargetCredentialCheckoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).SetTargetCredentialCheckout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/SetTargetCredentialCheckout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).SetTargetCredentialCheckout(ctx, req.(*SetTargetCredentialCheckoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_CloneTarget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneTargetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).CloneTarget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/CloneTarget",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).CloneTarget(ctx, req.(*CloneTargetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_GetTargetPeerIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetPeerIdentityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).GetTargetPeerIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/GetTargetPeerIdentity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).GetTargetPeerIdentity(ctx, req.(*GetTargetPeerIdentityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_SetTargetPeerIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTargetPeerIdentityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).SetTargetPeerIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/SetTargetPeerIdentity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).SetTargetPeerIdentity(ctx, req.(*SetTargetPeerIdentityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_BatchGetTargets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetTargetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).BatchGetTargets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/BatchGetTargets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).BatchGetTargets(ctx, req.(*BatchGetTargetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_GetTargetConnectionMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetConnectionMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).GetTargetConnectionMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/GetTargetConnectionMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).GetTargetConnectionMetadata(ctx, req.(*GetTargetConnectionMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_SetTargetConnectionMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTargetConnectionMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).SetTargetConnectionMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/SetTargetConnectionMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).SetTargetConnectionMetadata(ctx, req.(*SetTargetConnectionMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_GetTargetUserNameTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetUserNameTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).GetTargetUserNameTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/GetTargetUserNameTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).GetTargetUserNameTemplate(ctx, req.(*GetTargetUserNameTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_SetTargetUserNameTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTargetUserNameTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).SetTargetUserNameTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/SetTargetUserNameTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).SetTargetUserNameTemplate(ctx, req.(*SetTargetUserNameTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_GetTargetConnectionRateLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetConnectionRateLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).GetTargetConnectionRateLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/GetTargetConnectionRateLimit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).GetTargetConnectionRateLimit(ctx, req.(*GetTargetConnectionRateLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TargetService_SetTargetConnectionRateLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTargetConnectionRateLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TargetServiceServer).SetTargetConnectionRateLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.TargetService/SetTargetConnectionRateLimit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TargetServiceServer).SetTargetConnectionRateLimit(ctx, req.(*SetTargetConnectionRateLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _TargetService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.api.services.v1.TargetService",
 	HandlerType: (*TargetServiceServer)(nil),
@@ -467,6 +1135,78 @@ var _TargetService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveTargetHostSets",
 			Handler:    _TargetService_RemoveTargetHostSets_Handler,
 		},
+		{
+			MethodName: "GetTargetConnectionAuthorization",
+			Handler:    _TargetService_GetTargetConnectionAuthorization_Handler,
+		},
+		{
+			MethodName: "SetTargetConnectionAuthorization",
+			Handler:    _TargetService_SetTargetConnectionAuthorization_Handler,
+		},
+		{
+			MethodName: "GetTargetHistory",
+			Handler:    _TargetService_GetTargetHistory_Handler,
+		},
+		{
+			MethodName: "GetTargetBandwidthLimit",
+			Handler:    _TargetService_GetTargetBandwidthLimit_Handler,
+		},
+		{
+			MethodName: "SetTargetBandwidthLimit",
+			Handler:    _TargetService_SetTargetBandwidthLimit_Handler,
+		},
+		{
+			MethodName: "TestTargetConnection",
+			Handler:    _TargetService_TestTargetConnection_Handler,
+		},
+		{
+			MethodName: "GetTargetCredentialCheckout",
+			Handler:    _TargetService_GetTargetCredentialCheckout_Handler,
+		},
+		{
+			MethodName: "SetTargetCredentialCheckout",
+			Handler:    _TargetService_SetTargetCredentialCheckout_Handler,
+		},
+		{
+			MethodName: "CloneTarget",
+			Handler:    _TargetService_CloneTarget_Handler,
+		},
+		{
+			MethodName: "GetTargetPeerIdentity",
+			Handler:    _TargetService_GetTargetPeerIdentity_Handler,
+		},
+		{
+			MethodName: "SetTargetPeerIdentity",
+			Handler:    _TargetService_SetTargetPeerIdentity_Handler,
+		},
+		{
+			MethodName: "BatchGetTargets",
+			Handler:    _TargetService_BatchGetTargets_Handler,
+		},
+		{
+			MethodName: "GetTargetConnectionMetadata",
+			Handler:    _TargetService_GetTargetConnectionMetadata_Handler,
+		},
+		{
+			MethodName: "SetTargetConnectionMetadata",
+			Handler:    _TargetService_SetTargetConnectionMetadata_Handler,
+		},
+		{
+			MethodName: "GetTargetUserNameTemplate",
+			Handler:    _TargetService_GetTargetUserNameTemplate_Handler,
+		},
+		{
+			MethodName: "SetTargetUserNameTemplate",
+			Handler:    _TargetService_SetTargetUserNameTemplate_Handler,
+		},
+		{
+			MethodName: "GetTargetConnectionRateLimit",
+			Handler:    _TargetService_GetTargetConnectionRateLimit_Handler,
+		},
+		{
+			MethodName: "SetTargetConnectionRateLimit",
+			Handler:    _TargetService_SetTargetConnectionRateLimit_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/api/services/v1/target_service.proto",
@@ -65,6 +65,16 @@ type UserServiceClient interface {
 	// will be removed from. If the provided Account ids is not associated with the
 	// provided User, an error is returned.
 	RemoveUserAccounts(ctx context.Context, in *RemoveUserAccountsRequest, opts ...grpc.CallOption) (*RemoveUserAccountsResponse, error)
+	// GetSelfGrants returns the grants which apply to the caller, including the
+	// grants given to the anonymous and authenticated users, so end users can
+	// see their effective permissions without needing grants to read roles. If
+	// the caller's auth token was derived by an exchange only the grants it
+	// keeps are returned.
+	GetSelfGrants(ctx context.Context, in *GetSelfGrantsRequest, opts ...grpc.CallOption) (*GetSelfGrantsResponse, error)
+	// ListInactiveUsers returns the Users of a Scope which have not logged in
+	// for the number of days, least recently active first. If days is not set
+	// the maximum inactive days of the Scope's inactivity policy is used.
+	ListInactiveUsers(ctx context.Context, in *ListInactiveUsersRequest, opts ...grpc.CallOption) (*ListInactiveUsersResponse, error)
 }
 
 type userServiceClient struct {
@@ -147,6 +157,24 @@ func (c *userServiceClient) RemoveUserAccounts(ctx context.Context, in *RemoveUs
 	return out, nil
 }
 
+func (c *userServiceClient) GetSelfGrants(ctx context.Context, in *GetSelfGrantsRequest, opts ...grpc.CallOption) (*GetSelfGrantsResponse, error) {
+	out := new(GetSelfGrantsResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.UserService/GetSelfGrants", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListInactiveUsers(ctx context.Context, in *ListInactiveUsersRequest, opts ...grpc.CallOption) (*ListInactiveUsersResponse, error) {
+	out := new(ListInactiveUsersResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.UserService/ListInactiveUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility
@@ -199,6 +227,16 @@ type UserServiceServer interface {
 	// will be removed from. If the provided Account ids is not associated with the
 	// provided User, an error is returned.
 	RemoveUserAccounts(context.Context, *RemoveUserAccountsRequest) (*RemoveUserAccountsResponse, error)
+	// GetSelfGrants returns the grants which apply to the caller, including the
+	// grants given to the anonymous and authenticated users, so end users can
+	// see their effective permissions without needing grants to read roles. If
+	// the caller's auth token was derived by an exchange only the grants it
+	// keeps are returned.
+	GetSelfGrants(context.Context, *GetSelfGrantsRequest) (*GetSelfGrantsResponse, error)
+	// ListInactiveUsers returns the Users of a Scope which have not logged in
+	// for the number of days, least recently active first. If days is not set
+	// the maximum inactive days of the Scope's inactivity policy is used.
+	ListInactiveUsers(context.Context, *ListInactiveUsersRequest) (*ListInactiveUsersResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -230,6 +268,12 @@ func (UnimplementedUserServiceServer) SetUserAccounts(context.Context, *SetUserA
 func (UnimplementedUserServiceServer) RemoveUserAccounts(context.Context, *RemoveUserAccountsRequest) (*RemoveUserAccountsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveUserAccounts not implemented")
 }
+func (UnimplementedUserServiceServer) GetSelfGrants(context.Context, *GetSelfGrantsRequest) (*GetSelfGrantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSelfGrants not implemented")
+}
+func (UnimplementedUserServiceServer) ListInactiveUsers(context.Context, *ListInactiveUsersRequest) (*ListInactiveUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInactiveUsers not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 
 // UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -387,6 +431,42 @@ func _UserService_RemoveUserAccounts_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_GetSelfGrants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSelfGrantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetSelfGrants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.UserService/GetSelfGrants",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetSelfGrants(ctx, req.(*GetSelfGrantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListInactiveUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInactiveUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListInactiveUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.UserService/ListInactiveUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListInactiveUsers(ctx, req.(*ListInactiveUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _UserService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.api.services.v1.UserService",
 	HandlerType: (*UserServiceServer)(nil),
@@ -423,6 +503,14 @@ var _UserService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveUserAccounts",
 			Handler:    _UserService_RemoveUserAccounts_Handler,
 		},
+		{
+			MethodName: "GetSelfGrants",
+			Handler:    _UserService_GetSelfGrants_Handler,
+		},
+		{
+			MethodName: "ListInactiveUsers",
+			Handler:    _UserService_ListInactiveUsers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controller/api/services/v1/user_service.proto",
@@ -52,6 +52,24 @@ func (h *Host) SetTableName(n string) {
 	h.tableName = n
 }
 
+// CopyColumns returns the columns of static_host written by db.CopyFrom.
+func (h *Host) CopyColumns() []string {
+	return []string{"public_id", "catalog_id", "name", "description", "address"}
+}
+
+// CopyValues returns the values of the columns returned by CopyColumns. An
+// empty name or description is copied as null.
+func (h *Host) CopyValues() ([]interface{}, error) {
+	return []interface{}{h.PublicId, h.CatalogId, nullString(h.Name), nullString(h.Description), h.Address}, nil
+}
+
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
 func allocHost() *Host {
 	return &Host{
 		Host: &store.Host{},
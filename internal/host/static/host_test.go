@@ -193,3 +193,19 @@ func TestHost_SetTableName(t *testing.T) {
 		})
 	}
 }
+
+func TestHost_CopyValues(t *testing.T) {
+	assert := assert.New(t)
+	h := &Host{
+		Host: &store.Host{
+			PublicId:  "hst_1234567890",
+			CatalogId: "hcst_1234567890",
+			Name:      "web1",
+			Address:   "10.0.0.1",
+		},
+	}
+	values, err := h.CopyValues()
+	assert.NoError(err)
+	assert.Len(values, len(h.CopyColumns()))
+	assert.Equal([]interface{}{"hst_1234567890", "hcst_1234567890", "web1", nil, "10.0.0.1"}, values)
+}
@@ -0,0 +1,325 @@
+package static
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// The formats of the host inventories ParseInventory reads.
+const (
+	// InventoryKnownHosts is an OpenSSH known_hosts file. Each line is a
+	// host named after its first host name, which is also its address.
+	// Hashed host names, negated patterns and revoked keys are skipped.
+	InventoryKnownHosts = "known_hosts"
+
+	// InventorySshConfig is an OpenSSH client configuration file. Each alias
+	// of a Host block is a host whose address is the HostName of the block,
+	// or the alias if it has none. Patterns and Match blocks are skipped.
+	InventorySshConfig = "ssh_config"
+
+	// InventoryAnsible is an Ansible INI inventory. Each host is named after
+	// its inventory name and its address is its ansible_host variable, or
+	// its name if it has none. Each group is a host set, including the hosts
+	// of its child groups. Host ranges are not supported.
+	InventoryAnsible = "ansible_inventory"
+)
+
+// maxInventoryLineLength is the longest line ParseInventory accepts.
+const maxInventoryLineLength = 64 * 1024
+
+// An Inventory is the hosts and host sets read from an inventory file.
+type Inventory struct {
+	Hosts []*InventoryHost
+	Sets  []*InventorySet
+}
+
+// An InventoryHost is a host read from an inventory file.
+type InventoryHost struct {
+	Name    string
+	Address string
+}
+
+// An InventorySet is a host set read from an inventory file. Hosts are the
+// names of its hosts.
+type InventorySet struct {
+	Name  string
+	Hosts []string
+}
+
+// ParseInventory reads the hosts and host sets of an inventory file in the
+// format, one of InventoryKnownHosts, InventorySshConfig or
+// InventoryAnsible. The hosts are ordered as first found in the file and
+// each name is only returned once.
+func ParseInventory(format string, r io.Reader) (*Inventory, error) {
+	const op = "static.ParseInventory"
+	p := &inventoryParser{
+		hosts: make(map[string]*InventoryHost),
+	}
+	var parseLine func(string) error
+	switch format {
+	case InventoryKnownHosts:
+		parseLine = p.knownHostsLine
+	case InventorySshConfig:
+		parseLine = p.sshConfigLine
+	case InventoryAnsible:
+		p.groups = make(map[string]*ansibleGroup)
+		parseLine = p.ansibleLine
+	default:
+		return nil, errors.New(errors.InvalidParameter, op, fmt.Sprintf("unknown inventory format %q", format))
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxInventoryLineLength)
+	var n int
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if err := parseLine(line); err != nil {
+			return nil, errors.New(errors.InvalidParameter, op, fmt.Sprintf("line %d: %s", n, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to read inventory"))
+	}
+
+	inv := &Inventory{Hosts: p.order}
+	if p.groups != nil {
+		sets, err := p.ansibleSets()
+		if err != nil {
+			return nil, errors.New(errors.InvalidParameter, op, err.Error())
+		}
+		inv.Sets = sets
+	}
+	return inv, nil
+}
+
+type inventoryParser struct {
+	hosts map[string]*InventoryHost
+	order []*InventoryHost
+
+	// aliases are the host names of the current ssh_config Host block
+	aliases []string
+
+	groups       map[string]*ansibleGroup
+	groupOrder   []string
+	currentGroup *ansibleGroup
+	// inChildren is true in the children section of currentGroup
+	inChildren bool
+	// inVars is true in a vars section, whose lines are skipped
+	inVars bool
+}
+
+type ansibleGroup struct {
+	name     string
+	hosts    []string
+	children []string
+}
+
+// addHost adds the host unless a host with the name was already added. It
+// returns the host with the name.
+func (p *inventoryParser) addHost(name, address string) (*InventoryHost, error) {
+	if h, ok := p.hosts[name]; ok {
+		return h, nil
+	}
+	if l := len(strings.TrimSpace(address)); l < MinHostAddressLength || l > MaxHostAddressLength {
+		return nil, fmt.Errorf("address of host %q must be between %d and %d characters", name, MinHostAddressLength, MaxHostAddressLength)
+	}
+	h := &InventoryHost{Name: name, Address: address}
+	p.hosts[name] = h
+	p.order = append(p.order, h)
+	return h, nil
+}
+
+func (p *inventoryParser) knownHostsLine(line string) error {
+	fields := strings.Fields(line)
+	if strings.HasPrefix(fields[0], "@") {
+		if fields[0] == "@revoked" {
+			return nil
+		}
+		fields = fields[1:]
+	}
+	if len(fields) < 3 {
+		return fmt.Errorf("expected host names, key type and key")
+	}
+	// Hashed host names can't be recovered
+	if strings.HasPrefix(fields[0], "|") {
+		return nil
+	}
+	for _, name := range strings.Split(fields[0], ",") {
+		if strings.HasPrefix(name, "!") || strings.ContainsAny(name, "*?") {
+			continue
+		}
+		// Host names with a non-default port are written as [name]:port
+		if strings.HasPrefix(name, "[") {
+			if i := strings.Index(name, "]"); i > 0 {
+				name = name[1:i]
+			}
+		}
+		_, err := p.addHost(name, name)
+		return err
+	}
+	return nil
+}
+
+func (p *inventoryParser) sshConfigLine(line string) error {
+	keyword, args := splitSshConfigLine(line)
+	switch strings.ToLower(keyword) {
+	case "host":
+		p.aliases = p.aliases[:0]
+		for _, alias := range args {
+			if strings.HasPrefix(alias, "!") || strings.ContainsAny(alias, "*?") {
+				continue
+			}
+			if _, err := p.addHost(alias, alias); err != nil {
+				return err
+			}
+			p.aliases = append(p.aliases, alias)
+		}
+	case "match":
+		p.aliases = p.aliases[:0]
+	case "hostname":
+		if len(args) != 1 {
+			return fmt.Errorf("expected a single host name")
+		}
+		if l := len(args[0]); l < MinHostAddressLength || l > MaxHostAddressLength {
+			return fmt.Errorf("host name must be between %d and %d characters", MinHostAddressLength, MaxHostAddressLength)
+		}
+		for _, alias := range p.aliases {
+			p.hosts[alias].Address = args[0]
+		}
+	}
+	return nil
+}
+
+// splitSshConfigLine splits a line of an ssh_config file into its keyword and
+// arguments. The keyword may be separated from its arguments by an equal sign
+// and arguments may be quoted.
+func splitSshConfigLine(line string) (string, []string) {
+	i := strings.IndexAny(line, " \t=")
+	if i < 0 {
+		return line, nil
+	}
+	keyword := line[:i]
+	rest := strings.TrimLeft(line[i:], " \t")
+	rest = strings.TrimPrefix(rest, "=")
+	var args []string
+	for _, f := range strings.Fields(rest) {
+		args = append(args, strings.Trim(f, `"`))
+	}
+	return keyword, args
+}
+
+func (p *inventoryParser) ansibleLine(line string) error {
+	if strings.HasPrefix(line, "[") {
+		if !strings.HasSuffix(line, "]") {
+			return fmt.Errorf("unterminated section header")
+		}
+		section := strings.TrimSpace(line[1 : len(line)-1])
+		name, kind := section, ""
+		if i := strings.Index(section, ":"); i >= 0 {
+			name, kind = section[:i], section[i+1:]
+		}
+		if name == "" {
+			return fmt.Errorf("missing group name")
+		}
+		switch kind {
+		case "":
+		case "children", "vars":
+		default:
+			return fmt.Errorf("unknown section kind %q", kind)
+		}
+		p.currentGroup = p.ansibleGroup(name)
+		p.inChildren = kind == "children"
+		p.inVars = kind == "vars"
+		return nil
+	}
+	if p.inVars {
+		return nil
+	}
+	fields := strings.Fields(line)
+	if p.inChildren {
+		child := p.ansibleGroup(fields[0])
+		p.currentGroup.children = append(p.currentGroup.children, child.name)
+		return nil
+	}
+
+	name := fields[0]
+	if strings.ContainsAny(name, "[]") {
+		return fmt.Errorf("host ranges are not supported: %q", name)
+	}
+	address := name
+	for _, v := range fields[1:] {
+		if strings.HasPrefix(v, "ansible_host=") {
+			address = strings.Trim(strings.TrimPrefix(v, "ansible_host="), `"'`)
+		}
+	}
+	h, err := p.addHost(name, address)
+	if err != nil {
+		return err
+	}
+	group := p.currentGroup
+	if group == nil {
+		group = p.ansibleGroup("ungrouped")
+	}
+	group.hosts = append(group.hosts, h.Name)
+	return nil
+}
+
+// ansibleGroup returns the group with the name, adding it if it is new.
+func (p *inventoryParser) ansibleGroup(name string) *ansibleGroup {
+	g, ok := p.groups[name]
+	if !ok {
+		g = &ansibleGroup{name: name}
+		p.groups[name] = g
+		p.groupOrder = append(p.groupOrder, name)
+	}
+	return g
+}
+
+// ansibleSets returns a set for each group with hosts, including the hosts of
+// its child groups.
+func (p *inventoryParser) ansibleSets() ([]*InventorySet, error) {
+	var sets []*InventorySet
+	for _, name := range p.groupOrder {
+		members := make(map[string]bool)
+		if err := p.collectAnsibleHosts(name, members, map[string]bool{}); err != nil {
+			return nil, err
+		}
+		if len(members) == 0 {
+			continue
+		}
+		set := &InventorySet{Name: name}
+		for h := range members {
+			set.Hosts = append(set.Hosts, h)
+		}
+		sort.Strings(set.Hosts)
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+func (p *inventoryParser) collectAnsibleHosts(name string, members, visiting map[string]bool) error {
+	if visiting[name] {
+		return fmt.Errorf("group %q is its own child", name)
+	}
+	visiting[name] = true
+	defer delete(visiting, name)
+	g := p.groups[name]
+	for _, h := range g.hosts {
+		members[h] = true
+	}
+	for _, c := range g.children {
+		if err := p.collectAnsibleHosts(c, members, visiting); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package static
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInventory(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		format    string
+		in        string
+		want      *Inventory
+		wantIsErr errors.Code
+	}{
+		{
+			name:   "known-hosts",
+			format: InventoryKnownHosts,
+			in: `# comment
+web1.example.com,10.0.0.1 ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIO
+[db1.example.com]:2222 ecdsa-sha2-nistp256 AAAAE2VjZHNh
+|1|JfKTdBh7rNbXkVAQCRp4OQoPfmI=|USECr3SWf1JUPsms5AqfD5QfxkM= ssh-rsa AAAAB3NzaC1yc2E
+@cert-authority *.example.com ssh-rsa AAAAB3NzaC1yc2E
+@revoked bad.example.com ssh-rsa AAAAB3NzaC1yc2E
+10.0.0.1 ssh-rsa AAAAB3NzaC1yc2E
+web1.example.com ssh-rsa AAAAB3NzaC1yc2E
+`,
+			want: &Inventory{
+				Hosts: []*InventoryHost{
+					{Name: "web1.example.com", Address: "web1.example.com"},
+					{Name: "db1.example.com", Address: "db1.example.com"},
+					{Name: "10.0.0.1", Address: "10.0.0.1"},
+				},
+			},
+		},
+		{
+			name:      "known-hosts-missing-key",
+			format:    InventoryKnownHosts,
+			in:        "web1.example.com ssh-rsa\n",
+			wantIsErr: errors.InvalidParameter,
+		},
+		{
+			name:   "ssh-config",
+			format: InventorySshConfig,
+			in: `Host *
+    User admin
+
+Host bastion jump
+    HostName 192.168.1.10
+    Port 2222
+
+Host web-*
+    User web
+
+Host db1
+    HostName=db1.internal
+
+Match host foo
+    HostName ignored.example.com
+
+host legacy !excluded
+`,
+			want: &Inventory{
+				Hosts: []*InventoryHost{
+					{Name: "bastion", Address: "192.168.1.10"},
+					{Name: "jump", Address: "192.168.1.10"},
+					{Name: "db1", Address: "db1.internal"},
+					{Name: "legacy", Address: "legacy"},
+				},
+			},
+		},
+		{
+			name:      "ssh-config-short-host-name",
+			format:    InventorySshConfig,
+			in:        "Host web\n  HostName a\n",
+			wantIsErr: errors.InvalidParameter,
+		},
+		{
+			name:   "ansible",
+			format: InventoryAnsible,
+			in: `mail.example.com
+
+[webservers]
+web1 ansible_host=10.0.1.1 ansible_user=admin
+web2 ansible_host="10.0.1.2"
+
+[dbservers]
+db1.example.com
+
+[dbservers:vars]
+ntp_server=ntp.example.com
+
+[production:children]
+webservers
+dbservers
+
+[empty]
+`,
+			want: &Inventory{
+				Hosts: []*InventoryHost{
+					{Name: "mail.example.com", Address: "mail.example.com"},
+					{Name: "web1", Address: "10.0.1.1"},
+					{Name: "web2", Address: "10.0.1.2"},
+					{Name: "db1.example.com", Address: "db1.example.com"},
+				},
+				Sets: []*InventorySet{
+					{Name: "ungrouped", Hosts: []string{"mail.example.com"}},
+					{Name: "webservers", Hosts: []string{"web1", "web2"}},
+					{Name: "dbservers", Hosts: []string{"db1.example.com"}},
+					{Name: "production", Hosts: []string{"db1.example.com", "web1", "web2"}},
+				},
+			},
+		},
+		{
+			name:      "ansible-range",
+			format:    InventoryAnsible,
+			in:        "[web]\nweb[01:10].example.com\n",
+			wantIsErr: errors.InvalidParameter,
+		},
+		{
+			name:      "ansible-cycle",
+			format:    InventoryAnsible,
+			in:        "[a:children]\nb\n[b:children]\na\n[b]\nweb1\n",
+			wantIsErr: errors.InvalidParameter,
+		},
+		{
+			name:      "ansible-unknown-section",
+			format:    InventoryAnsible,
+			in:        "[web:other]\n",
+			wantIsErr: errors.InvalidParameter,
+		},
+		{
+			name:      "unknown-format",
+			format:    "hosts",
+			in:        "127.0.0.1 localhost\n",
+			wantIsErr: errors.InvalidParameter,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := ParseInventory(tt.format, strings.NewReader(tt.in))
+			if tt.wantIsErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantIsErr), err), "want err: %q got: %q", tt.wantIsErr, err)
+				assert.Nil(got)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
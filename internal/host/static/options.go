@@ -19,6 +19,7 @@ type options struct {
 	withLimit       int
 	withAddress     string
 	withPublicId    string
+	withDryRun      bool
 }
 
 func getDefaultOptions() options {
@@ -65,3 +66,11 @@ func WithPublicId(id string) Option {
 		o.withPublicId = id
 	}
 }
+
+// WithDryRun provides an option to plan the changes of an operation without
+// making them.
+func WithDryRun(dryRun bool) Option {
+	return func(o *options) {
+		o.withDryRun = dryRun
+	}
+}
@@ -38,4 +38,10 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withPublicId = "test"
 		assert.Equal(t, opts, testOpts)
 	})
+	t.Run("WithDryRun", func(t *testing.T) {
+		opts := getOpts(WithDryRun(true))
+		testOpts := getDefaultOptions()
+		testOpts.withDryRun = true
+		assert.Equal(t, opts, testOpts)
+	})
 }
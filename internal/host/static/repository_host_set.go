@@ -274,3 +274,95 @@ func (r *Repository) DeleteSet(ctx context.Context, scopeId string, publicId str
 
 	return rowsDeleted, nil
 }
+
+// CloneSet creates a new host set in the catalog of the host set setId with
+// the given name and returns it with its hosts. The new host set has the
+// description and the hosts of setId. The WithDescription option overrides
+// the description copied. All other options are ignored.
+func (r *Repository) CloneSet(ctx context.Context, scopeId string, setId string, name string, opt ...Option) (*HostSet, []*Host, error) {
+	const op = "static.CloneSet"
+	if scopeId == "" {
+		return nil, nil, errors.New(errors.InvalidParameter, op, "no scope id")
+	}
+	if setId == "" {
+		return nil, nil, errors.New(errors.InvalidParameter, op, "no set id")
+	}
+	if name == "" {
+		return nil, nil, errors.New(errors.InvalidParameter, op, "no name")
+	}
+	opts := getOpts(opt...)
+
+	src := allocHostSet()
+	src.PublicId = setId
+	if err := r.reader.LookupByPublicId(ctx, src); err != nil {
+		return nil, nil, errors.Wrap(err, op, errors.WithMsg(fmt.Sprintf("in %s", setId)))
+	}
+
+	s, err := NewHostSet(src.CatalogId, WithName(name), WithDescription(src.Description))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+	if opts.withDescription != "" {
+		s.Description = opts.withDescription
+	}
+	if s.PublicId, err = newHostSetId(); err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+
+	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op, errors.WithMsg("unable to get oplog wrapper"))
+	}
+
+	var newHostSet *HostSet
+	var hosts []*Host
+	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			// Read the members in the transaction so the clone gets those of
+			// setId when it is created
+			var srcMembers []*HostSetMember
+			if err := reader.SearchWhere(ctx, &srcMembers, "set_id = ?", []interface{}{setId}, db.WithLimit(-1)); err != nil {
+				return errors.Wrap(err, op, errors.WithMsg("unable to read members"))
+			}
+			hostIds := make([]string, 0, len(srcMembers))
+			for _, m := range srcMembers {
+				hostIds = append(hostIds, m.HostId)
+			}
+
+			ticket, err := w.GetTicket(s)
+			if err != nil {
+				return errors.Wrap(err, op, errors.WithMsg("unable to get ticket"))
+			}
+			msgs := make([]*oplog.Message, 0, len(hostIds)+1)
+			setMsg := new(oplog.Message)
+			newHostSet = s.clone()
+			if err := w.Create(ctx, newHostSet, db.NewOplogMsg(setMsg)); err != nil {
+				return err
+			}
+			msgs = append(msgs, setMsg)
+			if len(hostIds) > 0 {
+				members, err := r.newMembers(newHostSet.PublicId, hostIds)
+				if err != nil {
+					return errors.Wrap(err, op)
+				}
+				memberMsgs, err := createMembers(ctx, w, members)
+				if err != nil {
+					return err
+				}
+				msgs = append(msgs, memberMsgs...)
+			}
+			if err := w.WriteOplogEntryWith(ctx, oplogWrapper, ticket, s.oplog(oplog.OpType_OP_TYPE_CREATE), msgs); err != nil {
+				return errors.Wrap(err, op, errors.WithMsg("unable to write oplog"))
+			}
+			hosts, err = getHosts(ctx, reader, newHostSet.PublicId, unlimited)
+			return err
+		},
+	)
+	if err != nil {
+		if errors.IsUniqueError(err) {
+			return nil, nil, errors.Wrap(err, op, errors.WithMsg(fmt.Sprintf("in catalog: %s: name %s already exists", s.CatalogId, s.Name)))
+		}
+		return nil, nil, errors.Wrap(err, op, errors.WithMsg(fmt.Sprintf("in %s", setId)))
+	}
+	return newHostSet, hosts, nil
+}
@@ -1028,3 +1028,116 @@ func TestRepository_DeleteSet(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_CloneSet(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, prj := iam.TestScopes(t, iamRepo)
+
+	catalog := TestCatalogs(t, conn, prj.PublicId, 1)[0]
+	hostSet := TestSets(t, conn, catalog.PublicId, 1)[0]
+	hosts := TestHosts(t, conn, catalog.PublicId, 3)
+	TestSetMembers(t, conn, hostSet.PublicId, hosts)
+	emptyHostSet := TestSets(t, conn, catalog.PublicId, 1)[0]
+
+	hostSetId, err := newHostSetId()
+	require.NoError(t, err)
+
+	var tests = []struct {
+		name      string
+		scopeId   string
+		setId     string
+		setName   string
+		opts      []Option
+		wantDesc  string
+		wantHosts []*Host
+		wantIsErr errors.Code
+	}{
+		{
+			name:      "with-no-scope-id",
+			setId:     hostSet.PublicId,
+			setName:   "with-no-scope-id",
+			wantIsErr: errors.InvalidParameter,
+		},
+		{
+			name:      "with-no-set-id",
+			scopeId:   prj.PublicId,
+			setName:   "with-no-set-id",
+			wantIsErr: errors.InvalidParameter,
+		},
+		{
+			name:      "with-no-name",
+			scopeId:   prj.PublicId,
+			setId:     hostSet.PublicId,
+			wantIsErr: errors.InvalidParameter,
+		},
+		{
+			name:      "with-non-existing-host-set-id",
+			scopeId:   prj.PublicId,
+			setId:     hostSetId,
+			setName:   "with-non-existing-host-set-id",
+			wantIsErr: errors.RecordNotFound,
+		},
+		{
+			name:      "with-hosts",
+			scopeId:   prj.PublicId,
+			setId:     hostSet.PublicId,
+			setName:   "with-hosts",
+			wantDesc:  hostSet.Description,
+			wantHosts: hosts,
+		},
+		{
+			name:     "with-empty-hosts-and-description",
+			scopeId:  prj.PublicId,
+			setId:    emptyHostSet.PublicId,
+			setName:  "with-empty-hosts-and-description",
+			opts:     []Option{WithDescription("clone")},
+			wantDesc: "clone",
+		},
+		{
+			name:      "with-duplicate-name",
+			scopeId:   prj.PublicId,
+			setId:     hostSet.PublicId,
+			setName:   "with-hosts",
+			wantIsErr: errors.NotUnique,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			repo, err := NewRepository(rw, rw, kms)
+			require.NoError(err)
+			require.NotNil(repo)
+			got, gotHosts, err := repo.CloneSet(context.Background(), tt.scopeId, tt.setId, tt.setName, tt.opts...)
+			if tt.wantIsErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantIsErr), err), "want err: %q got: %q", tt.wantIsErr, err)
+				assert.Nil(got)
+				return
+			}
+			require.NoError(err)
+			require.NotNil(got)
+			assert.NotEqual(tt.setId, got.PublicId)
+			assert.Equal(catalog.PublicId, got.CatalogId)
+			assert.Equal(tt.setName, got.Name)
+			assert.Equal(tt.wantDesc, got.Description)
+			assert.Equal(uint32(1), got.Version)
+			require.Len(gotHosts, len(tt.wantHosts))
+			opts := []cmp.Option{
+				cmpopts.SortSlices(func(x, y *Host) bool { return x.PublicId < y.PublicId }),
+				protocmp.Transform(),
+			}
+			assert.Empty(cmp.Diff(tt.wantHosts, gotHosts, opts...))
+			assert.NoError(db.TestVerifyOplog(t, rw, got.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
+
+			// The source keeps its hosts
+			_, srcHosts, err := repo.LookupSet(context.Background(), tt.setId)
+			require.NoError(err)
+			assert.Len(srcHosts, len(tt.wantHosts))
+		})
+	}
+}
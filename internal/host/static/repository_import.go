@@ -0,0 +1,244 @@
+package static
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+)
+
+// An ImportPlan is the changes an import of an inventory makes to a catalog.
+type ImportPlan struct {
+	Hosts []*ImportHost
+	Sets  []*ImportSet
+}
+
+// An ImportHost is a host of an inventory. Exists is true if the catalog
+// already had a host with the name, which is then left unchanged; otherwise
+// the host is created. PublicId is empty for hosts planned in a dry run.
+type ImportHost struct {
+	PublicId string
+	Name     string
+	Address  string
+	Exists   bool
+}
+
+// An ImportSet is a host set of an inventory. Exists is true if the catalog
+// already had a host set with the name; otherwise the host set is created.
+// AddHosts are the names of the hosts added to the host set. PublicId is
+// empty for host sets planned in a dry run.
+type ImportSet struct {
+	PublicId string
+	Name     string
+	Exists   bool
+	AddHosts []string
+}
+
+// ImportInventory creates the hosts and host sets of inv in the catalog
+// catalogId in one transaction and returns the changes made. Hosts and host
+// sets are matched to those of the catalog by name: existing hosts are left
+// unchanged and existing host sets only get the hosts they are missing.
+//
+// With the WithDryRun option the changes are planned but not made. All
+// other options are ignored.
+func (r *Repository) ImportInventory(ctx context.Context, scopeId string, catalogId string, inv *Inventory, opt ...Option) (*ImportPlan, error) {
+	const op = "static.ImportInventory"
+	if scopeId == "" {
+		return nil, errors.New(errors.InvalidParameter, op, "no scope id")
+	}
+	if catalogId == "" {
+		return nil, errors.New(errors.InvalidParameter, op, "no catalog id")
+	}
+	if inv == nil {
+		return nil, errors.New(errors.InvalidParameter, op, "nil inventory")
+	}
+	opts := getOpts(opt...)
+
+	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg("unable to get oplog wrapper"))
+	}
+
+	var plan *ImportPlan
+	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			var existingSets map[string]*HostSet
+			var err error
+			plan, existingSets, err = planImport(ctx, reader, catalogId, inv)
+			if err != nil {
+				return err
+			}
+			if opts.withDryRun {
+				return nil
+			}
+
+			hostIds := make(map[string]string, len(plan.Hosts))
+			var hosts []db.Copyable
+			var newIds []string
+			for _, ih := range plan.Hosts {
+				if ih.Exists {
+					hostIds[ih.Name] = ih.PublicId
+					continue
+				}
+				h, err := NewHost(catalogId, WithName(ih.Name), WithAddress(ih.Address))
+				if err != nil {
+					return errors.Wrap(err, op)
+				}
+				if h.PublicId, err = newHostId(); err != nil {
+					return errors.Wrap(err, op)
+				}
+				hosts = append(hosts, h)
+				newIds = append(newIds, h.PublicId)
+				ih.PublicId = h.PublicId
+				hostIds[ih.Name] = h.PublicId
+			}
+			if len(hosts) > 0 {
+				metadata := oplog.Metadata{
+					"resource-public-id": newIds,
+					"resource-type":      []string{"static-host"},
+					"op-type":            []string{oplog.OpType_OP_TYPE_CREATE.String()},
+					"catalog-id":         []string{catalogId},
+				}
+				if _, err := w.CopyFrom(ctx, hosts, db.WithOplog(oplogWrapper, metadata)); err != nil {
+					return errors.Wrap(err, op, errors.WithMsg("unable to create hosts"))
+				}
+			}
+
+			for _, is := range plan.Sets {
+				ids := make([]string, 0, len(is.AddHosts))
+				for _, name := range is.AddHosts {
+					ids = append(ids, hostIds[name])
+				}
+				if is.Exists {
+					if len(ids) == 0 {
+						continue
+					}
+					set := existingSets[is.Name]
+					members, err := r.newMembers(set.PublicId, ids)
+					if err != nil {
+						return errors.Wrap(err, op)
+					}
+					msgs, err := createMembers(ctx, w, members)
+					if err != nil {
+						return err
+					}
+					updated := newHostSetForMembers(set.PublicId, set.Version)
+					if err := updateVersion(ctx, w, oplogWrapper, updated.oplog(oplog.OpType_OP_TYPE_CREATE), msgs, updated, set.Version); err != nil {
+						return err
+					}
+					continue
+				}
+
+				s, err := NewHostSet(catalogId, WithName(is.Name))
+				if err != nil {
+					return errors.Wrap(err, op)
+				}
+				if s.PublicId, err = newHostSetId(); err != nil {
+					return errors.Wrap(err, op)
+				}
+				ticket, err := w.GetTicket(s)
+				if err != nil {
+					return errors.Wrap(err, op, errors.WithMsg("unable to get ticket"))
+				}
+				setMsg := new(oplog.Message)
+				if err := w.Create(ctx, s, db.NewOplogMsg(setMsg)); err != nil {
+					return errors.Wrap(err, op, errors.WithMsg(fmt.Sprintf("unable to create host set %s", is.Name)))
+				}
+				msgs := []*oplog.Message{setMsg}
+				if len(ids) > 0 {
+					members, err := r.newMembers(s.PublicId, ids)
+					if err != nil {
+						return errors.Wrap(err, op)
+					}
+					memberMsgs, err := createMembers(ctx, w, members)
+					if err != nil {
+						return err
+					}
+					msgs = append(msgs, memberMsgs...)
+				}
+				if err := w.WriteOplogEntryWith(ctx, oplogWrapper, ticket, s.oplog(oplog.OpType_OP_TYPE_CREATE), msgs); err != nil {
+					return errors.Wrap(err, op, errors.WithMsg("unable to write oplog"))
+				}
+				is.PublicId = s.PublicId
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, op, errors.WithMsg(fmt.Sprintf("in catalog: %s", catalogId)))
+	}
+	return plan, nil
+}
+
+// planImport matches the hosts and host sets of inv with those of the
+// catalog. It returns the plan and the existing host sets of the catalog by
+// name.
+func planImport(ctx context.Context, reader db.Reader, catalogId string, inv *Inventory) (*ImportPlan, map[string]*HostSet, error) {
+	const op = "static.planImport"
+	var hosts []*Host
+	if err := reader.SearchWhere(ctx, &hosts, "catalog_id = ?", []interface{}{catalogId}, db.WithLimit(-1)); err != nil {
+		return nil, nil, errors.Wrap(err, op, errors.WithMsg("unable to read hosts"))
+	}
+	hostsByName := make(map[string]*Host, len(hosts))
+	for _, h := range hosts {
+		if h.Name != "" {
+			hostsByName[h.Name] = h
+		}
+	}
+	var sets []*HostSet
+	if err := reader.SearchWhere(ctx, &sets, "catalog_id = ?", []interface{}{catalogId}, db.WithLimit(-1)); err != nil {
+		return nil, nil, errors.Wrap(err, op, errors.WithMsg("unable to read host sets"))
+	}
+	setsByName := make(map[string]*HostSet, len(sets))
+	for _, s := range sets {
+		if s.Name != "" {
+			setsByName[s.Name] = s
+		}
+	}
+
+	plan := &ImportPlan{}
+	inventoryHosts := make(map[string]bool, len(inv.Hosts))
+	for _, h := range inv.Hosts {
+		if inventoryHosts[h.Name] {
+			return nil, nil, errors.New(errors.InvalidParameter, op, fmt.Sprintf("duplicate host %s", h.Name))
+		}
+		inventoryHosts[h.Name] = true
+		ih := &ImportHost{Name: h.Name, Address: h.Address}
+		if existing, ok := hostsByName[h.Name]; ok {
+			ih.PublicId = existing.PublicId
+			ih.Address = existing.Address
+			ih.Exists = true
+		}
+		plan.Hosts = append(plan.Hosts, ih)
+	}
+
+	for _, s := range inv.Sets {
+		is := &ImportSet{Name: s.Name}
+		members := make(map[string]bool)
+		if existing, ok := setsByName[s.Name]; ok {
+			is.PublicId = existing.PublicId
+			is.Exists = true
+			var current []*HostSetMember
+			if err := reader.SearchWhere(ctx, &current, "set_id = ?", []interface{}{existing.PublicId}, db.WithLimit(-1)); err != nil {
+				return nil, nil, errors.Wrap(err, op, errors.WithMsg("unable to read host set members"))
+			}
+			for _, m := range current {
+				members[m.HostId] = true
+			}
+		}
+		for _, name := range s.Hosts {
+			if !inventoryHosts[name] {
+				return nil, nil, errors.New(errors.InvalidParameter, op, fmt.Sprintf("host set %s has unknown host %s", s.Name, name))
+			}
+			if existing, ok := hostsByName[name]; ok && members[existing.PublicId] {
+				continue
+			}
+			is.AddHosts = append(is.AddHosts, name)
+		}
+		plan.Sets = append(plan.Sets, is)
+	}
+	return plan, setsByName, nil
+}
@@ -0,0 +1,129 @@
+package static
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ImportInventory(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, prj := iam.TestScopes(t, iamRepo)
+	ctx := context.Background()
+
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+
+	t.Run("parameters", func(t *testing.T) {
+		assert := assert.New(t)
+		catalog := TestCatalogs(t, conn, prj.PublicId, 1)[0]
+		_, err := repo.ImportInventory(ctx, "", catalog.PublicId, &Inventory{})
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "got: %q", err)
+		_, err = repo.ImportInventory(ctx, prj.PublicId, "", &Inventory{})
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "got: %q", err)
+		_, err = repo.ImportInventory(ctx, prj.PublicId, catalog.PublicId, nil)
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "got: %q", err)
+		_, err = repo.ImportInventory(ctx, prj.PublicId, catalog.PublicId, &Inventory{
+			Sets: []*InventorySet{{Name: "set", Hosts: []string{"missing"}}},
+		})
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "got: %q", err)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		catalog := TestCatalogs(t, conn, prj.PublicId, 1)[0]
+
+		// An existing host and host set with the names of the inventory
+		existingHost, err := NewHost(catalog.PublicId, WithName("web1"), WithAddress("10.0.0.99"))
+		require.NoError(err)
+		existingHost, err = repo.CreateHost(ctx, prj.PublicId, existingHost)
+		require.NoError(err)
+		existingSet, err := NewHostSet(catalog.PublicId, WithName("web"))
+		require.NoError(err)
+		existingSet, err = repo.CreateSet(ctx, prj.PublicId, existingSet)
+		require.NoError(err)
+
+		inv := &Inventory{
+			Hosts: []*InventoryHost{
+				{Name: "web1", Address: "10.0.0.1"},
+				{Name: "web2", Address: "10.0.0.2"},
+				{Name: "db1", Address: "10.0.1.1"},
+			},
+			Sets: []*InventorySet{
+				{Name: "web", Hosts: []string{"web1", "web2"}},
+				{Name: "db", Hosts: []string{"db1"}},
+			},
+		}
+
+		// A dry run plans without changes
+		plan, err := repo.ImportInventory(ctx, prj.PublicId, catalog.PublicId, inv, WithDryRun(true))
+		require.NoError(err)
+		assert.Equal(&ImportPlan{
+			Hosts: []*ImportHost{
+				{PublicId: existingHost.PublicId, Name: "web1", Address: "10.0.0.99", Exists: true},
+				{Name: "web2", Address: "10.0.0.2"},
+				{Name: "db1", Address: "10.0.1.1"},
+			},
+			Sets: []*ImportSet{
+				{PublicId: existingSet.PublicId, Name: "web", Exists: true, AddHosts: []string{"web1", "web2"}},
+				{Name: "db", AddHosts: []string{"db1"}},
+			},
+		}, plan)
+		hosts, err := repo.ListHosts(ctx, catalog.PublicId)
+		require.NoError(err)
+		assert.Len(hosts, 1)
+		sets, err := repo.ListSets(ctx, catalog.PublicId)
+		require.NoError(err)
+		assert.Len(sets, 1)
+
+		plan, err = repo.ImportInventory(ctx, prj.PublicId, catalog.PublicId, inv)
+		require.NoError(err)
+		require.Len(plan.Hosts, 3)
+		require.Len(plan.Sets, 2)
+		for _, h := range plan.Hosts {
+			assert.NotEmpty(h.PublicId)
+		}
+		assert.Equal(existingSet.PublicId, plan.Sets[0].PublicId)
+		assert.NotEmpty(plan.Sets[1].PublicId)
+		assert.NoError(db.TestVerifyOplog(t, rw, plan.Hosts[1].PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE)))
+		assert.NoError(db.TestVerifyOplog(t, rw, plan.Hosts[2].PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE)))
+		assert.NoError(db.TestVerifyOplog(t, rw, plan.Sets[1].PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE)))
+
+		hosts, err = repo.ListHosts(ctx, catalog.PublicId)
+		require.NoError(err)
+		assert.Len(hosts, 3)
+		gotSet, setHosts, err := repo.LookupSet(ctx, existingSet.PublicId)
+		require.NoError(err)
+		assert.Equal(existingSet.Version+1, gotSet.Version)
+		assert.Len(setHosts, 2)
+		_, setHosts, err = repo.LookupSet(ctx, plan.Sets[1].PublicId)
+		require.NoError(err)
+		require.Len(setHosts, 1)
+		assert.Equal(plan.Hosts[2].PublicId, setHosts[0].PublicId)
+
+		// Importing again changes nothing
+		plan, err = repo.ImportInventory(ctx, prj.PublicId, catalog.PublicId, inv)
+		require.NoError(err)
+		for _, h := range plan.Hosts {
+			assert.True(h.Exists)
+		}
+		for _, s := range plan.Sets {
+			assert.True(s.Exists)
+			assert.Empty(s.AddHosts)
+		}
+		gotSet, _, err = repo.LookupSet(ctx, existingSet.PublicId)
+		require.NoError(err)
+		assert.Equal(existingSet.Version+1, gotSet.Version)
+	})
+}
@@ -0,0 +1,74 @@
+package iam
+
+import (
+	stderrors "errors"
+	"time"
+)
+
+// AccessRequestEventKind is the outbox message kind of the events of access
+// requests. The payload is an AccessRequestEvent encoded as JSON, with the
+// schema_version of its schema in the event schema registry.
+const AccessRequestEventKind = "iam.access_request"
+
+// AccessRequestEventSchemaVersion is the version of the schema of the
+// payloads of AccessRequestEventKind messages.
+const AccessRequestEventSchemaVersion = 1
+
+// The statuses of an access request. Only pending requests may be decided.
+const (
+	AccessRequestPending   = "pending"
+	AccessRequestApproved  = "approved"
+	AccessRequestDenied    = "denied"
+	AccessRequestCancelled = "cancelled"
+)
+
+// The events recorded for an access request.
+const (
+	AccessRequestEventRequested = "requested"
+	AccessRequestEventApproved  = "approved"
+	AccessRequestEventDenied    = "denied"
+	AccessRequestEventCancelled = "cancelled"
+)
+
+var (
+	// ErrAccessRequestNotPending indicates that an access request cannot be
+	// decided or cancelled because it is not pending.
+	ErrAccessRequestNotPending = stderrors.New("access request is not pending")
+
+	// ErrAccessRequestSelfDecision indicates that a user tried to approve or
+	// deny its own access request.
+	ErrAccessRequestSelfDecision = stderrors.New("access request cannot be decided by its requester")
+)
+
+// An AccessRequest is a request by a user to be assigned a role for a limited
+// duration, e.g. to elevate its access while on call. Approving it assigns the
+// role to the user until the ExpirationTime.
+type AccessRequest struct {
+	PublicId      string
+	RoleId        string
+	RequesterId   string
+	Justification string
+	Duration      time.Duration
+	Status        string
+	// DeciderId is the user which approved or denied the request
+	DeciderId       string
+	DecisionComment string
+	DecisionTime    *time.Time
+	// ExpirationTime is when the role assignment of an approved request
+	// expires
+	ExpirationTime *time.Time
+	CreateTime     time.Time
+	UpdateTime     time.Time
+}
+
+// An AccessRequestEvent records the creation of an access request or a
+// decision on it, for auditing.
+type AccessRequestEvent struct {
+	AccessRequestId string    `json:"access_request_id"`
+	RoleId          string    `json:"role_id"`
+	RequesterId     string    `json:"requester_id"`
+	Event           string    `json:"event"`
+	ActorId         string    `json:"actor_id"`
+	Comment         string    `json:"comment,omitempty"`
+	CreateTime      time.Time `json:"create_time"`
+}
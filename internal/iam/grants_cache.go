@@ -0,0 +1,253 @@
+package iam
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/boundary/internal/perms"
+)
+
+const (
+	// DefaultGrantsCacheVersionCheckInterval is how often a grants cache
+	// checks the versions of the cached grants of a user in the database by
+	// default, which is how long a change to roles or groups may take to
+	// apply to authorization checks.
+	DefaultGrantsCacheVersionCheckInterval = 1 * time.Second
+
+	// grantsCacheMaxAge is how long the grants of a user are cached at most,
+	// even if they don't change.
+	grantsCacheMaxAge = 5 * time.Minute
+
+	// grantsCacheMaxEntries is the most users whose grants are cached; the
+	// cache is emptied when it is full.
+	grantsCacheMaxEntries = 10000
+)
+
+// GrantsCache caches the grants of users so resolving them for authorization
+// checks doesn't query the database. The cached grants of a user are tagged
+// with the grants versions of the principals they come from: the user,
+// u_anon and u_auth, and the groups of the user. The version of a principal
+// is incremented by every change to its roles, their grants or, for users,
+// their groups. Cached grants are discarded once one of their versions
+// changes or a temporary role assignment of the user expires. It is safe for
+// concurrent use.
+type GrantsCache struct {
+	versionCheckInterval time.Duration
+
+	lock    sync.Mutex
+	entries map[string]*grantsCacheEntry
+}
+
+type grantsCacheEntry struct {
+	versions       principalVersions
+	versionChecked time.Time
+	grants         []perms.GrantPair
+	validUntil     time.Time
+}
+
+// principalVersions are the grants versions of principals by id.
+type principalVersions map[string]int64
+
+func (v principalVersions) equal(o principalVersions) bool {
+	if len(v) != len(o) {
+		return false
+	}
+	for id, version := range v {
+		if ov, ok := o[id]; !ok || ov != version {
+			return false
+		}
+	}
+	return true
+}
+
+// NewGrantsCache returns a grants cache checking the versions of the cached
+// grants of a user in the database at most every versionCheckInterval, or
+// every DefaultGrantsCacheVersionCheckInterval if it is not positive.
+func NewGrantsCache(versionCheckInterval time.Duration) *GrantsCache {
+	if versionCheckInterval <= 0 {
+		versionCheckInterval = DefaultGrantsCacheVersionCheckInterval
+	}
+	return &GrantsCache{
+		versionCheckInterval: versionCheckInterval,
+		entries:              make(map[string]*grantsCacheEntry),
+	}
+}
+
+// get returns a copy of the cache entry of the user if it has not expired.
+func (c *GrantsCache) get(userId string) (grantsCacheEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e, ok := c.entries[userId]
+	if !ok || !time.Now().Before(e.validUntil) {
+		return grantsCacheEntry{}, false
+	}
+	cp := *e
+	cp.grants = append([]perms.GrantPair(nil), e.grants...)
+	return cp, true
+}
+
+// checked records that the versions of the cached grants of the user were
+// found unchanged.
+func (c *GrantsCache) checked(userId string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.entries[userId]; ok {
+		e.versionChecked = time.Now()
+	}
+}
+
+// put caches the grants of the user loaded at the versions until the next
+// expiration of one of its role assignments, if any is sooner than
+// grantsCacheMaxAge.
+func (c *GrantsCache) put(userId string, versions principalVersions, grants []perms.GrantPair, nextExpiration time.Time) {
+	now := time.Now()
+	validUntil := now.Add(grantsCacheMaxAge)
+	if !nextExpiration.IsZero() && nextExpiration.Before(validUntil) {
+		validUntil = nextExpiration
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.entries) >= grantsCacheMaxEntries {
+		c.entries = make(map[string]*grantsCacheEntry)
+	}
+	c.entries[userId] = &grantsCacheEntry{
+		versions:       versions,
+		versionChecked: now,
+		grants:         append([]perms.GrantPair(nil), grants...),
+		validUntil:     validUntil,
+	}
+}
+
+// cachedGrantsForUser returns the grants of the user from the cache of the
+// repository, loading them on a miss. The versions are read before the grants
+// are, so a change made while loading them is seen by the next check.
+func (r *Repository) cachedGrantsForUser(ctx context.Context, userId string) ([]perms.GrantPair, error) {
+	e, ok := r.grantsCache.get(userId)
+	if ok && time.Since(e.versionChecked) < r.grantsCache.versionCheckInterval {
+		metrics.IncrCounter([]string{"controller", "grants_cache", "hit"}, 1)
+		return e.grants, nil
+	}
+	versions, err := r.grantsVersions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if ok && versions.equal(e.versions) {
+		r.grantsCache.checked(userId)
+		metrics.IncrCounter([]string{"controller", "grants_cache", "hit"}, 1)
+		return e.grants, nil
+	}
+	metrics.IncrCounter([]string{"controller", "grants_cache", "miss"}, 1)
+	grants, err := r.grantsForUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	next, err := r.nextGrantsExpiration(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	r.grantsCache.put(userId, versions, grants, next)
+	return grants, nil
+}
+
+// grantsVersions returns the grants versions of the principals the grants of
+// the user come from, which are 0 for principals whose roles never changed.
+func (r *Repository) grantsVersions(ctx context.Context, userId string) (principalVersions, error) {
+	const query = `
+with
+users (id) as (
+  select public_id
+    from iam_user
+   where public_id in (%s)
+),
+principals (id) as (
+  select id
+    from users
+   union
+  select m.group_id
+    from iam_group_member_user m,
+         users
+   where m.member_id = users.id
+)
+select p.id,
+       coalesce(v.version, 0)
+  from principals p
+  left
+  join iam_principal_grants_version v
+    on v.principal_id = p.id;
+`
+	users := `'u_anon', 'u_auth', $1`
+	if userId == "u_anon" {
+		users = `$1`
+	}
+	rows, err := r.reader.Query(ctx, fmt.Sprintf(query, users), []interface{}{userId})
+	if err != nil {
+		return nil, fmt.Errorf("get grants versions for user: %w", err)
+	}
+	defer rows.Close()
+	versions := make(principalVersions)
+	for rows.Next() {
+		var id string
+		var version int64
+		if err := rows.Scan(&id, &version); err != nil {
+			return nil, fmt.Errorf("get grants versions for user: %w", err)
+		}
+		versions[id] = version
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get grants versions for user: %w", err)
+	}
+	return versions, nil
+}
+
+// nextGrantsExpiration returns when the next of the temporary role
+// assignments the grants of the user come from expires, or the zero time if
+// none of them is temporary.
+func (r *Repository) nextGrantsExpiration(ctx context.Context, userId string) (time.Time, error) {
+	const query = `
+with
+users (id) as (
+  select public_id
+    from iam_user
+   where public_id in (%s)
+)
+select min(expiration_time)
+  from (
+        select e.expiration_time
+          from iam_user_role_expiration e,
+               users
+         where e.principal_id = users.id
+         union all
+        select e.expiration_time
+          from iam_group_role_expiration e
+         inner
+          join iam_group_member_user m
+            on m.group_id = e.principal_id,
+               users
+         where m.member_id = users.id
+       ) expirations
+ where expiration_time > now();
+`
+	users := `'u_anon', 'u_auth', $1`
+	if userId == "u_anon" {
+		users = `$1`
+	}
+	rows, err := r.reader.Query(ctx, fmt.Sprintf(query, users), []interface{}{userId})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get grants expiration for user: %w", err)
+	}
+	defer rows.Close()
+	var next sql.NullTime
+	if rows.Next() {
+		if err := rows.Scan(&next); err != nil {
+			return time.Time{}, fmt.Errorf("get grants expiration for user: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return time.Time{}, fmt.Errorf("get grants expiration for user: %w", err)
+	}
+	return next.Time, nil
+}
@@ -0,0 +1,154 @@
+package iam
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_CachedGrantsForUser(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	cache := NewGrantsCache(time.Hour)
+	repo := TestRepo(t, conn, wrapper, WithGrantsCache(cache))
+	ctx := context.Background()
+
+	org, _ := TestScopes(t, repo)
+	user := TestUser(t, repo, org.PublicId)
+	role := TestRole(t, conn, org.PublicId)
+	TestRoleGrant(t, conn, role.PublicId, "id=*;type=*;actions=read")
+
+	hasRoleGrant := func(grants []perms.GrantPair) bool {
+		for _, g := range grants {
+			if g.Grant == "id=*;type=*;actions=read" {
+				return true
+			}
+		}
+		return false
+	}
+	// expireVersionCheck makes the next lookups read the versions of the
+	// grants, as if the version check interval had passed
+	expireVersionCheck := func() {
+		cache.lock.Lock()
+		defer cache.lock.Unlock()
+		for _, e := range cache.entries {
+			e.versionChecked = time.Time{}
+		}
+	}
+	versionsOf := func(userId string) principalVersions {
+		cache.lock.Lock()
+		defer cache.lock.Unlock()
+		return cache.entries[userId].versions
+	}
+
+	grants, err := repo.GrantsForUser(ctx, user.PublicId)
+	require.NoError(t, err)
+	assert.False(t, hasRoleGrant(grants))
+	versions := versionsOf(user.PublicId)
+	assert.Contains(t, versions, "u_anon")
+	assert.Contains(t, versions, "u_auth")
+
+	bystander := TestUser(t, repo, org.PublicId)
+	_, err = repo.GrantsForUser(ctx, bystander.PublicId)
+	require.NoError(t, err)
+	bystanderVersions := versionsOf(bystander.PublicId)
+
+	_, err = repo.AddPrincipalRoles(ctx, role.PublicId, role.Version, []string{user.PublicId})
+	require.NoError(t, err)
+
+	t.Run("cached", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		// The version isn't checked again within the interval
+		grants, err := repo.GrantsForUser(ctx, user.PublicId)
+		require.NoError(err)
+		assert.False(hasRoleGrant(grants))
+	})
+
+	t.Run("invalidated", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		expireVersionCheck()
+		grants, err := repo.GrantsForUser(ctx, user.PublicId)
+		require.NoError(err)
+		assert.True(hasRoleGrant(grants))
+		assert.Greater(versionsOf(user.PublicId)[user.PublicId], versions[user.PublicId])
+	})
+
+	t.Run("other-users-unchanged", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		expireVersionCheck()
+		_, err := repo.GrantsForUser(ctx, bystander.PublicId)
+		require.NoError(err)
+		assert.Equal(bystanderVersions, versionsOf(bystander.PublicId))
+	})
+
+	t.Run("group", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		member := TestUser(t, repo, org.PublicId)
+		group := TestGroup(t, conn, org.PublicId)
+		TestGroupMember(t, conn, group.PublicId, member.PublicId)
+		grants, err := repo.GrantsForUser(ctx, member.PublicId)
+		require.NoError(err)
+		assert.False(hasRoleGrant(grants))
+		assert.Contains(versionsOf(member.PublicId), group.PublicId)
+
+		_, err = repo.AddPrincipalRoles(ctx, role.PublicId, role.Version+1, []string{group.PublicId})
+		require.NoError(err)
+		role.Version++
+		expireVersionCheck()
+		grants, err = repo.GrantsForUser(ctx, member.PublicId)
+		require.NoError(err)
+		assert.True(hasRoleGrant(grants))
+	})
+
+	t.Run("expiration", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		other := TestUser(t, repo, org.PublicId)
+		exp := time.Now().Add(time.Hour)
+		_, err := repo.AddPrincipalRoles(ctx, role.PublicId, role.Version+1, []string{other.PublicId}, WithExpirationTime(exp))
+		require.NoError(err)
+		expireVersionCheck()
+		grants, err := repo.GrantsForUser(ctx, other.PublicId)
+		require.NoError(err)
+		assert.True(hasRoleGrant(grants))
+
+		cache.lock.Lock()
+		e := cache.entries[other.PublicId]
+		cache.lock.Unlock()
+		require.NotNil(e)
+		assert.WithinDuration(exp, e.validUntil, time.Second)
+	})
+}
+
+func TestGrantsCache_Put(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	c := NewGrantsCache(0)
+	assert.Equal(DefaultGrantsCacheVersionCheckInterval, c.versionCheckInterval)
+
+	grants := []perms.GrantPair{{ScopeId: "global", Grant: "id=*;type=*;actions=read"}}
+	versions := principalVersions{"u_1234567890": 2, "u_anon": 1, "u_auth": 0}
+	c.put("u_1234567890", versions, grants, time.Time{})
+	got, ok := c.get("u_1234567890")
+	assert.True(ok)
+	assert.Equal(grants, got.grants)
+	assert.Equal(versions, got.versions)
+	assert.True(versions.equal(principalVersions{"u_1234567890": 2, "u_anon": 1, "u_auth": 0}))
+	assert.False(versions.equal(principalVersions{"u_1234567890": 3, "u_anon": 1, "u_auth": 0}))
+	assert.False(versions.equal(principalVersions{"u_1234567890": 2, "u_anon": 1, "u_auth": 0, "g_1234567890": 1}))
+
+	// Users without grants are cached too
+	c.put("u_1111111111", versions, nil, time.Time{})
+	_, ok = c.get("u_1111111111")
+	assert.True(ok)
+
+	// Expired entries are misses
+	c.put("u_0987654321", versions, grants, time.Now().Add(-time.Second))
+	_, ok = c.get("u_0987654321")
+	assert.False(ok)
+}
@@ -26,6 +26,11 @@ func Test_PublicIds(t *testing.T) {
 		require.NoError(t, err)
 		assert.True(t, strings.HasPrefix(id, GroupPrefix+"_"))
 	})
+	t.Run("access-request", func(t *testing.T) {
+		id, err := newAccessRequestId()
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(id, AccessRequestPrefix+"_"))
+	})
 	t.Run("scopes", func(t *testing.T) {
 		id, err := newScopeId(scope.Org)
 		require.NoError(t, err)
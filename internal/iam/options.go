@@ -1,6 +1,9 @@
 package iam
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 // getOpts - iterate the inbound Options and return a struct
 func getOpts(opt ...Option) options {
@@ -27,8 +30,11 @@ type options struct {
 	withDisassociate            bool
 	withSkipAdminRoleCreation   bool
 	withSkipDefaultRoleCreation bool
+	withSkipProjectTemplate     bool
 	withUserId                  string
 	withRandomReader            io.Reader
+	withExpirationTime          time.Time
+	withGrantsCache             *GrantsCache
 }
 
 func getDefaultOptions() options {
@@ -129,6 +135,14 @@ func WithSkipDefaultRoleCreation(enable bool) Option {
 	}
 }
 
+// WithSkipProjectTemplate provides an option to disable applying the project
+// template of its org when a new project is created.
+func WithSkipProjectTemplate(enable bool) Option {
+	return func(o *options) {
+		o.withSkipProjectTemplate = enable
+	}
+}
+
 // WithUserId provides an option to specify the user ID to use when creating roles with new scopes.
 func WithUserId(id string) Option {
 	return func(o *options) {
@@ -142,3 +156,19 @@ func WithRandomReader(reader io.Reader) Option {
 		o.withRandomReader = reader
 	}
 }
+
+// WithExpirationTime provides an option to specify when principals added to a
+// role are removed from it again.
+func WithExpirationTime(t time.Time) Option {
+	return func(o *options) {
+		o.withExpirationTime = t
+	}
+}
+
+// WithGrantsCache provides an option to cache the grants of users in the
+// cache.
+func WithGrantsCache(c *GrantsCache) Option {
+	return func(o *options) {
+		o.withGrantsCache = c
+	}
+}
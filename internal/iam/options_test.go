@@ -2,6 +2,7 @@ package iam
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -74,4 +75,19 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withDisassociate = true
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithExpirationTime", func(t *testing.T) {
+		assert := assert.New(t)
+		exp := time.Now().Add(time.Hour)
+		opts := getOpts(WithExpirationTime(exp))
+		testOpts := getDefaultOptions()
+		testOpts.withExpirationTime = exp
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithSkipProjectTemplate", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithSkipProjectTemplate(true))
+		testOpts := getDefaultOptions()
+		testOpts.withSkipProjectTemplate = true
+		assert.Equal(opts, testOpts)
+	})
 }
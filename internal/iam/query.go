@@ -108,4 +108,229 @@ const (
 	select * from final
 	order by action, member_id;
 	`
+
+	// userLoginsQuery returns the last login time of the user $1 with each of
+	// its accounts.
+	userLoginsQuery = `
+	select auth_account_id, last_login_time
+		from iam_user_login
+	where iam_user_id = $1`
+
+	// scopeUserLoginsQuery returns the last login time of each user of the
+	// scope $1 with each of its accounts, ordered by user.
+	scopeUserLoginsQuery = `
+	select l.iam_user_id, l.auth_account_id, l.last_login_time
+		from iam_user_login l
+	inner join iam_user u
+		on u.public_id = l.iam_user_id
+	where u.scope_id = $1
+	order by l.iam_user_id`
+
+	// inactiveUsersQuery returns the users of the scope $1 which have not
+	// logged in for $2 days, or were created or last enabled that long ago
+	// and never logged in since, least recently active first. The anonymous,
+	// authenticated and recovery users are never inactive.
+	inactiveUsersQuery = `
+	select u.public_id, coalesce(u.name, ''), u.create_time, l.last_login_time, d.iam_user_id is not null
+		from iam_user u
+	left join (
+		select iam_user_id, max(last_login_time) as last_login_time
+			from iam_user_login
+		group by iam_user_id
+	) l
+		on l.iam_user_id = u.public_id
+	left join iam_user_enabled e
+		on e.iam_user_id = u.public_id
+	left join iam_user_disabled d
+		on d.iam_user_id = u.public_id
+	where
+		u.scope_id = $1 and
+		u.public_id not in ('u_anon', 'u_auth', 'u_recovery') and
+		greatest(l.last_login_time, e.enable_time, u.create_time) < now() - make_interval(days => $2)
+	order by greatest(l.last_login_time, e.enable_time, u.create_time)`
+
+	// inactiveUsersToDisableQuery returns the ids, scopes and disable
+	// reasons of the inactive users which are not disabled yet, of the scopes
+	// with an inactivity policy disabling them.
+	inactiveUsersToDisableQuery = `
+	select u.public_id, u.scope_id, 'inactive for ' || p.max_inactive_days || ' days'
+		from iam_user u
+	join iam_scope_inactivity_policy p
+		on p.scope_id = u.scope_id and p.disable_inactive
+	left join (
+		select iam_user_id, max(last_login_time) as last_login_time
+			from iam_user_login
+		group by iam_user_id
+	) l
+		on l.iam_user_id = u.public_id
+	left join iam_user_enabled e
+		on e.iam_user_id = u.public_id
+	left join iam_user_disabled d
+		on d.iam_user_id = u.public_id
+	where
+		d.iam_user_id is null and
+		u.public_id not in ('u_anon', 'u_auth', 'u_recovery') and
+		greatest(l.last_login_time, e.enable_time, u.create_time) < now() - make_interval(days => p.max_inactive_days)`
+
+	// deleteUserAuthTokensQuery deletes the auth tokens of a user.
+	deleteUserAuthTokensQuery = `
+	delete from auth_token
+	where auth_account_id in (
+		select public_id
+			from auth_account
+		where iam_user_id = ?
+	)`
+
+	// principalRoleExpirationsQuery returns when the temporary assignments of
+	// the role $1 to its users and groups expire.
+	principalRoleExpirationsQuery = `
+	select principal_id, expiration_time
+		from iam_user_role_expiration
+	where role_id = $1
+	union all
+	select principal_id, expiration_time
+		from iam_group_role_expiration
+	where role_id = $1
+	order by principal_id`
+
+	// deleteExpiredPrincipalRolesQuery removes the expired temporary
+	// assignments of roles to users and groups, bumps the version of their
+	// roles and returns the removed assignments.
+	deleteExpiredPrincipalRolesQuery = `
+	with
+	expired_user_roles (role_id, principal_id, expiration_time) as (
+		delete from iam_user_role r
+			using iam_user_role_expiration e
+		where
+			r.role_id = e.role_id and
+			r.principal_id = e.principal_id and
+			e.expiration_time <= now()
+		returning r.role_id, r.principal_id, e.expiration_time
+	),
+	expired_group_roles (role_id, principal_id, expiration_time) as (
+		delete from iam_group_role r
+			using iam_group_role_expiration e
+		where
+			r.role_id = e.role_id and
+			r.principal_id = e.principal_id and
+			e.expiration_time <= now()
+		returning r.role_id, r.principal_id, e.expiration_time
+	),
+	expired (role_id, principal_id, expiration_time) as (
+		select role_id, principal_id, expiration_time from expired_user_roles
+		union all
+		select role_id, principal_id, expiration_time from expired_group_roles
+	),
+	updated_roles as (
+		update iam_role
+			set version = version + 1
+		where public_id in (select role_id from expired)
+	)
+	select role_id, principal_id, expiration_time from expired`
+
+	// accessRequestColumns are the columns of an access request, in the
+	// order scanned by scanAccessRequest.
+	accessRequestColumns = `
+	public_id, role_id, requester_id, justification, duration_seconds, status,
+	coalesce(decider_id, ''), coalesce(decision_comment, ''), decision_time,
+	expiration_time, create_time, update_time`
+
+	// lookupAccessRequestQuery returns the access request $1.
+	lookupAccessRequestQuery = `
+	select ` + accessRequestColumns + `
+		from iam_access_request
+	where public_id = $1`
+
+	// listAccessRequestsQuery returns the access requests for the role $1,
+	// only those of the requester $2 unless it is empty, newest first.
+	listAccessRequestsQuery = `
+	select ` + accessRequestColumns + `
+		from iam_access_request
+	where
+		role_id = $1 and
+		($2::text = '' or requester_id = $2)
+	order by create_time desc, public_id`
+
+	// insertAccessRequestQuery creates a pending access request.
+	insertAccessRequestQuery = `
+	insert into iam_access_request
+		(public_id, role_id, requester_id, justification, duration_seconds)
+	values
+		(?, ?, ?, ?, ?)`
+
+	// approveAccessRequestQuery approves the pending access request, starting
+	// its duration.
+	approveAccessRequestQuery = `
+	update iam_access_request
+		set status = 'approved',
+			decider_id = ?,
+			decision_comment = nullif(?, ''),
+			decision_time = now(),
+			expiration_time = now() + make_interval(secs => duration_seconds)
+	where public_id = ? and status = 'pending'`
+
+	// denyAccessRequestQuery denies the pending access request.
+	denyAccessRequestQuery = `
+	update iam_access_request
+		set status = 'denied',
+			decider_id = ?,
+			decision_comment = nullif(?, ''),
+			decision_time = now()
+	where public_id = ? and status = 'pending'`
+
+	// cancelAccessRequestQuery cancels the pending access request of the
+	// requester.
+	cancelAccessRequestQuery = `
+	update iam_access_request
+		set status = 'cancelled'
+	where public_id = ? and requester_id = ? and status = 'pending'`
+
+	// grantAccessRequestRoleQuery assigns the role of the approved access
+	// request to its requester.
+	grantAccessRequestRoleQuery = `
+	insert into iam_user_role (role_id, principal_id)
+	select role_id, requester_id
+		from iam_access_request
+	where public_id = ?
+	on conflict do nothing`
+
+	// expireAccessRequestRoleQuery sets the expiration of the role assignment
+	// made for the approved access request.
+	expireAccessRequestRoleQuery = `
+	insert into iam_user_role_expiration (role_id, principal_id, expiration_time)
+	select role_id, requester_id, expiration_time
+		from iam_access_request
+	where public_id = ?`
+
+	// extendAccessRequestRoleQuery extends an existing temporary assignment
+	// of the role of the approved access request to its requester to the
+	// request's expiration. Permanent assignments are left permanent.
+	extendAccessRequestRoleQuery = `
+	update iam_user_role_expiration e
+		set expiration_time = r.expiration_time
+		from iam_access_request r
+	where
+		r.public_id = ? and
+		e.role_id = r.role_id and
+		e.principal_id = r.requester_id and
+		e.expiration_time < r.expiration_time`
+
+	// insertAccessRequestEventQuery records an event of the access request
+	// and returns its time.
+	insertAccessRequestEventQuery = `
+	insert into iam_access_request_event
+		(access_request_id, event, actor_id, comment)
+	values
+		($1, $2, $3, nullif($4, ''))
+	returning create_time`
+
+	// accessRequestEventsQuery returns the events of the access request $1,
+	// oldest first.
+	accessRequestEventsQuery = `
+	select e.access_request_id, r.role_id, r.requester_id, e.event, e.actor_id, coalesce(e.comment, ''), e.create_time
+		from iam_access_request_event e
+	join iam_access_request r
+		on r.public_id = e.access_request_id
+	where e.access_request_id = $1
+	order by e.id`
 )
@@ -24,10 +24,15 @@ type Repository struct {
 
 	// defaultLimit provides a default for limiting the number of results returned from the repo
 	defaultLimit int
+
+	// grantsCache caches the grants of users; it is nil if they are read
+	// from the database every time
+	grantsCache *GrantsCache
 }
 
 // NewRepository creates a new iam Repository. Supports the options: WithLimit
-// which sets a default limit on results returned by repo operations.
+// which sets a default limit on results returned by repo operations, and
+// WithGrantsCache which sets the cache of the grants of users.
 func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repository, error) {
 	if r == nil {
 		return nil, errors.New("error creating db repository with nil reader")
@@ -48,6 +53,7 @@ func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repo
 		writer:       w,
 		kms:          kms,
 		defaultLimit: opts.withLimit,
+		grantsCache:  opts.withGrantsCache,
 	}, nil
 }
 
@@ -94,11 +100,15 @@ func (r *Repository) create(ctx context.Context, resource Resource, opt ...Optio
 		db.ExpBackoff{},
 		func(_ db.Reader, w db.Writer) error {
 			returnedResource = resourceCloner.Clone()
-			return w.Create(
+			if err := w.Create(
 				ctx,
 				returnedResource,
 				db.WithOplog(oplogWrapper, metadata),
-			)
+			); err != nil {
+				return err
+			}
+			w.NotifyOnCommit(ctx, db.Change{ResourceType: resource.ResourceType(), Op: db.CreateOp, Id: returnedResource.(Resource).GetPublicId()})
+			return nil
 		},
 	)
 	return returnedResource.(Resource), err
@@ -165,6 +175,9 @@ func (r *Repository) update(ctx context.Context, resource Resource, version uint
 				// return err, which will result in a rollback of the update
 				return errors.New("error more than 1 resource would have been updated ")
 			}
+			if err == nil && rowsUpdated > 0 {
+				w.NotifyOnCommit(ctx, db.Change{ResourceType: resource.ResourceType(), Op: db.UpdateOp, Id: resource.GetPublicId()})
+			}
 			return err
 		},
 	)
@@ -212,6 +225,9 @@ func (r *Repository) delete(ctx context.Context, resource Resource, opt ...Optio
 				// return err, which will result in a rollback of the delete
 				return errors.New("error more than 1 resource would have been deleted ")
 			}
+			if err == nil && rowsDeleted > 0 {
+				w.NotifyOnCommit(ctx, db.Change{ResourceType: resource.ResourceType(), Op: db.DeleteOp, Id: resource.GetPublicId()})
+			}
 			return err
 		},
 	)
@@ -0,0 +1,322 @@
+package iam
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/outbox"
+)
+
+// CreateAccessRequest creates a pending request by the requester to be
+// assigned the role for the duration, which is rounded down to whole seconds.
+// The request is recorded as an event and enqueued in the outbox as an
+// AccessRequestEventKind message. No options are currently supported.
+func (r *Repository) CreateAccessRequest(ctx context.Context, roleId, requesterId, justification string, duration time.Duration, opt ...Option) (*AccessRequest, error) {
+	switch {
+	case roleId == "":
+		return nil, fmt.Errorf("create access request: missing role id: %w", errors.ErrInvalidParameter)
+	case requesterId == "":
+		return nil, fmt.Errorf("create access request: missing requester id: %w", errors.ErrInvalidParameter)
+	case strings.TrimSpace(justification) == "":
+		return nil, fmt.Errorf("create access request: missing justification: %w", errors.ErrInvalidParameter)
+	case duration < time.Second:
+		return nil, fmt.Errorf("create access request: duration must be at least a second: %w", errors.ErrInvalidParameter)
+	}
+	id, err := newAccessRequestId()
+	if err != nil {
+		return nil, fmt.Errorf("create access request: %w", err)
+	}
+	var req *AccessRequest
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(read db.Reader, w db.Writer) error {
+			if _, err := w.Exec(ctx, insertAccessRequestQuery,
+				[]interface{}{id, roleId, requesterId, justification, int64(duration / time.Second)}); err != nil {
+				return err
+			}
+			var err error
+			req, err = lookupAccessRequest(ctx, read, id)
+			if err != nil {
+				return err
+			}
+			return recordAccessRequestEvent(ctx, read, w, req, AccessRequestEventRequested, requesterId, justification)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create access request: %w for role %s", err, roleId)
+	}
+	return req, nil
+}
+
+// LookupAccessRequest returns the access request, or nil if it does not
+// exist. No options are currently supported.
+func (r *Repository) LookupAccessRequest(ctx context.Context, withPublicId string, opt ...Option) (*AccessRequest, error) {
+	if withPublicId == "" {
+		return nil, fmt.Errorf("lookup access request: missing public id: %w", errors.ErrInvalidParameter)
+	}
+	req, err := lookupAccessRequest(ctx, r.reader, withPublicId)
+	if err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup access request: %w for %s", err, withPublicId)
+	}
+	return req, nil
+}
+
+// ListAccessRequests returns the access requests for the role, newest first.
+// Supports the WithUserId option to only return the requests of a requester.
+func (r *Repository) ListAccessRequests(ctx context.Context, roleId string, opt ...Option) ([]*AccessRequest, error) {
+	if roleId == "" {
+		return nil, fmt.Errorf("list access requests: missing role id: %w", errors.ErrInvalidParameter)
+	}
+	opts := getOpts(opt...)
+	rows, err := r.reader.Query(ctx, listAccessRequestsQuery, []interface{}{roleId, opts.withUserId})
+	if err != nil {
+		return nil, fmt.Errorf("list access requests: %w for role %s", err, roleId)
+	}
+	defer rows.Close()
+	var reqs []*AccessRequest
+	for rows.Next() {
+		req, err := scanAccessRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list access requests: %w for role %s", err, roleId)
+		}
+		reqs = append(reqs, req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list access requests: %w for role %s", err, roleId)
+	}
+	return reqs, nil
+}
+
+// ListAccessRequestEvents returns the events of the access request, oldest
+// first. No options are currently supported.
+func (r *Repository) ListAccessRequestEvents(ctx context.Context, withPublicId string, opt ...Option) ([]*AccessRequestEvent, error) {
+	if withPublicId == "" {
+		return nil, fmt.Errorf("list access request events: missing public id: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, accessRequestEventsQuery, []interface{}{withPublicId})
+	if err != nil {
+		return nil, fmt.Errorf("list access request events: %w for %s", err, withPublicId)
+	}
+	defer rows.Close()
+	var events []*AccessRequestEvent
+	for rows.Next() {
+		var e AccessRequestEvent
+		if err := rows.Scan(&e.AccessRequestId, &e.RoleId, &e.RequesterId, &e.Event, &e.ActorId, &e.Comment, &e.CreateTime); err != nil {
+			return nil, fmt.Errorf("list access request events: %w for %s", err, withPublicId)
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list access request events: %w for %s", err, withPublicId)
+	}
+	return events, nil
+}
+
+// ApproveAccessRequest approves the pending access request on behalf of the
+// decider and assigns the role to the requester until the request's duration
+// has passed. If the requester already has the role temporarily, its
+// assignment is extended; a permanent assignment is left permanent. The
+// decider may not be the requester. No options are currently supported.
+func (r *Repository) ApproveAccessRequest(ctx context.Context, withPublicId, deciderId, comment string, opt ...Option) (*AccessRequest, error) {
+	req, err := r.decideAccessRequest(ctx, withPublicId, deciderId, comment, true)
+	if err != nil {
+		return nil, fmt.Errorf("approve access request: %w", err)
+	}
+	return req, nil
+}
+
+// DenyAccessRequest denies the pending access request on behalf of the
+// decider. The decider may not be the requester. No options are currently
+// supported.
+func (r *Repository) DenyAccessRequest(ctx context.Context, withPublicId, deciderId, comment string, opt ...Option) (*AccessRequest, error) {
+	req, err := r.decideAccessRequest(ctx, withPublicId, deciderId, comment, false)
+	if err != nil {
+		return nil, fmt.Errorf("deny access request: %w", err)
+	}
+	return req, nil
+}
+
+func (r *Repository) decideAccessRequest(ctx context.Context, withPublicId, deciderId, comment string, approve bool) (*AccessRequest, error) {
+	switch {
+	case withPublicId == "":
+		return nil, fmt.Errorf("missing public id: %w", errors.ErrInvalidParameter)
+	case deciderId == "":
+		return nil, fmt.Errorf("missing decider id: %w", errors.ErrInvalidParameter)
+	}
+	var req *AccessRequest
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(read db.Reader, w db.Writer) error {
+			current, err := lookupAccessRequest(ctx, read, withPublicId)
+			if err != nil {
+				return err
+			}
+			if current.RequesterId == deciderId {
+				return ErrAccessRequestSelfDecision
+			}
+			query, event := denyAccessRequestQuery, AccessRequestEventDenied
+			if approve {
+				query, event = approveAccessRequestQuery, AccessRequestEventApproved
+			}
+			rowsUpdated, err := w.Exec(ctx, query, []interface{}{deciderId, comment, withPublicId})
+			if err != nil {
+				return err
+			}
+			if rowsUpdated != 1 {
+				return ErrAccessRequestNotPending
+			}
+			if approve {
+				rowsInserted, err := w.Exec(ctx, grantAccessRequestRoleQuery, []interface{}{withPublicId})
+				if err != nil {
+					return err
+				}
+				expireQuery := extendAccessRequestRoleQuery
+				if rowsInserted == 1 {
+					expireQuery = expireAccessRequestRoleQuery
+				}
+				if _, err := w.Exec(ctx, expireQuery, []interface{}{withPublicId}); err != nil {
+					return err
+				}
+				if _, err := w.Exec(ctx, "update iam_role set version = version + 1 where public_id = ?", []interface{}{current.RoleId}); err != nil {
+					return err
+				}
+			}
+			if req, err = lookupAccessRequest(ctx, read, withPublicId); err != nil {
+				return err
+			}
+			return recordAccessRequestEvent(ctx, read, w, req, event, deciderId, comment)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s", err, withPublicId)
+	}
+	return req, nil
+}
+
+// CancelAccessRequest cancels the pending access request of the requester.
+// No options are currently supported.
+func (r *Repository) CancelAccessRequest(ctx context.Context, withPublicId, requesterId string, opt ...Option) (*AccessRequest, error) {
+	switch {
+	case withPublicId == "":
+		return nil, fmt.Errorf("cancel access request: missing public id: %w", errors.ErrInvalidParameter)
+	case requesterId == "":
+		return nil, fmt.Errorf("cancel access request: missing requester id: %w", errors.ErrInvalidParameter)
+	}
+	var req *AccessRequest
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(read db.Reader, w db.Writer) error {
+			current, err := lookupAccessRequest(ctx, read, withPublicId)
+			if err != nil {
+				return err
+			}
+			if current.RequesterId != requesterId {
+				return fmt.Errorf("not the requester: %w", errors.ErrInvalidParameter)
+			}
+			rowsUpdated, err := w.Exec(ctx, cancelAccessRequestQuery, []interface{}{withPublicId, requesterId})
+			if err != nil {
+				return err
+			}
+			if rowsUpdated != 1 {
+				return ErrAccessRequestNotPending
+			}
+			if req, err = lookupAccessRequest(ctx, read, withPublicId); err != nil {
+				return err
+			}
+			return recordAccessRequestEvent(ctx, read, w, req, AccessRequestEventCancelled, requesterId, "")
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cancel access request: %w for %s", err, withPublicId)
+	}
+	return req, nil
+}
+
+// recordAccessRequestEvent records the event of the access request and
+// enqueues it in the outbox, within the transaction of w.
+func recordAccessRequestEvent(ctx context.Context, read db.Reader, w db.Writer, req *AccessRequest, event, actorId, comment string) error {
+	e := AccessRequestEvent{
+		AccessRequestId: req.PublicId,
+		RoleId:          req.RoleId,
+		RequesterId:     req.RequesterId,
+		Event:           event,
+		ActorId:         actorId,
+		Comment:         comment,
+	}
+	rows, err := read.Query(ctx, insertAccessRequestEventQuery, []interface{}{e.AccessRequestId, e.Event, e.ActorId, e.Comment})
+	if err != nil {
+		return fmt.Errorf("unable to record %s event: %w", event, err)
+	}
+	for rows.Next() {
+		if err := rows.Scan(&e.CreateTime); err != nil {
+			rows.Close()
+			return fmt.Errorf("unable to record %s event: %w", event, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("unable to record %s event: %w", event, err)
+	}
+	rows.Close()
+	payload, err := json.Marshal(struct {
+		SchemaVersion int `json:"schema_version"`
+		*AccessRequestEvent
+	}{AccessRequestEventSchemaVersion, &e})
+	if err != nil {
+		return fmt.Errorf("unable to encode %s event: %w", event, err)
+	}
+	return outbox.Enqueue(ctx, w, AccessRequestEventKind, payload)
+}
+
+func lookupAccessRequest(ctx context.Context, read db.Reader, withPublicId string) (*AccessRequest, error) {
+	rows, err := read.Query(ctx, lookupAccessRequestQuery, []interface{}{withPublicId})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.ErrRecordNotFound
+	}
+	return scanAccessRequest(rows)
+}
+
+func scanAccessRequest(rows *sql.Rows) (*AccessRequest, error) {
+	var req AccessRequest
+	var durationSeconds int64
+	if err := rows.Scan(
+		&req.PublicId,
+		&req.RoleId,
+		&req.RequesterId,
+		&req.Justification,
+		&durationSeconds,
+		&req.Status,
+		&req.DeciderId,
+		&req.DecisionComment,
+		&req.DecisionTime,
+		&req.ExpirationTime,
+		&req.CreateTime,
+		&req.UpdateTime,
+	); err != nil {
+		return nil, err
+	}
+	req.Duration = time.Duration(durationSeconds) * time.Second
+	return &req, nil
+}
@@ -0,0 +1,148 @@
+package iam
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_AccessRequest(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+
+	org, _ := TestScopes(t, repo)
+	role := TestRole(t, conn, org.PublicId)
+	requester := TestUser(t, repo, org.PublicId)
+	approver := TestUser(t, repo, org.PublicId)
+
+	outboxMessages := func(t *testing.T) int {
+		t.Helper()
+		rows, err := rw.Query(ctx, "select count(*) from outbox_message where kind = $1", []interface{}{AccessRequestEventKind})
+		require.NoError(t, err)
+		defer rows.Close()
+		var n int
+		for rows.Next() {
+			require.NoError(t, rows.Scan(&n))
+		}
+		return n
+	}
+
+	t.Run("invalid-parameters", func(t *testing.T) {
+		assert := assert.New(t)
+		_, err := repo.CreateAccessRequest(ctx, "", requester.PublicId, "on call", time.Hour)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, err = repo.CreateAccessRequest(ctx, role.PublicId, "", "on call", time.Hour)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, err = repo.CreateAccessRequest(ctx, role.PublicId, requester.PublicId, " ", time.Hour)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, err = repo.CreateAccessRequest(ctx, role.PublicId, requester.PublicId, "on call", time.Millisecond)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, err = repo.ApproveAccessRequest(ctx, "", approver.PublicId, "")
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, err = repo.CancelAccessRequest(ctx, "", requester.PublicId)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+	t.Run("approve", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		before := outboxMessages(t)
+		req, err := repo.CreateAccessRequest(ctx, role.PublicId, requester.PublicId, "incident 42", time.Hour)
+		require.NoError(err)
+		assert.Equal(AccessRequestPending, req.Status)
+		assert.Equal(time.Hour, req.Duration)
+		assert.Nil(req.ExpirationTime)
+
+		_, err = repo.ApproveAccessRequest(ctx, req.PublicId, requester.PublicId, "")
+		assert.True(errors.Is(err, ErrAccessRequestSelfDecision))
+
+		req, err = repo.ApproveAccessRequest(ctx, req.PublicId, approver.PublicId, "go ahead")
+		require.NoError(err)
+		assert.Equal(AccessRequestApproved, req.Status)
+		assert.Equal(approver.PublicId, req.DeciderId)
+		assert.Equal("go ahead", req.DecisionComment)
+		require.NotNil(req.ExpirationTime)
+		require.NotNil(req.DecisionTime)
+		assert.True(req.DecisionTime.Add(time.Hour).Equal(*req.ExpirationTime))
+
+		exps, err := repo.ListPrincipalRoleExpirations(ctx, role.PublicId)
+		require.NoError(err)
+		require.Len(exps, 1)
+		assert.Equal(requester.PublicId, exps[0].PrincipalId)
+		assert.True(req.ExpirationTime.Equal(exps[0].ExpirationTime))
+
+		_, err = repo.DenyAccessRequest(ctx, req.PublicId, approver.PublicId, "")
+		assert.True(errors.Is(err, ErrAccessRequestNotPending))
+
+		events, err := repo.ListAccessRequestEvents(ctx, req.PublicId)
+		require.NoError(err)
+		require.Len(events, 2)
+		assert.Equal(AccessRequestEventRequested, events[0].Event)
+		assert.Equal(requester.PublicId, events[0].ActorId)
+		assert.Equal("incident 42", events[0].Comment)
+		assert.Equal(AccessRequestEventApproved, events[1].Event)
+		assert.Equal(approver.PublicId, events[1].ActorId)
+		assert.Equal(before+2, outboxMessages(t))
+	})
+	t.Run("approve-permanent-principal", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		TestUserRole(t, conn, role.PublicId, approver.PublicId)
+		req, err := repo.CreateAccessRequest(ctx, role.PublicId, approver.PublicId, "already assigned", time.Hour)
+		require.NoError(err)
+		_, err = repo.ApproveAccessRequest(ctx, req.PublicId, requester.PublicId, "")
+		require.NoError(err)
+
+		// The permanent assignment stays permanent
+		exps, err := repo.ListPrincipalRoleExpirations(ctx, role.PublicId)
+		require.NoError(err)
+		for _, e := range exps {
+			assert.NotEqual(approver.PublicId, e.PrincipalId)
+		}
+	})
+	t.Run("deny-and-cancel", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		denied, err := repo.CreateAccessRequest(ctx, role.PublicId, requester.PublicId, "curious", time.Minute)
+		require.NoError(err)
+		denied, err = repo.DenyAccessRequest(ctx, denied.PublicId, approver.PublicId, "no incident")
+		require.NoError(err)
+		assert.Equal(AccessRequestDenied, denied.Status)
+		assert.Nil(denied.ExpirationTime)
+
+		cancelled, err := repo.CreateAccessRequest(ctx, role.PublicId, requester.PublicId, "mistake", time.Minute)
+		require.NoError(err)
+		_, err = repo.CancelAccessRequest(ctx, cancelled.PublicId, approver.PublicId)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		cancelled, err = repo.CancelAccessRequest(ctx, cancelled.PublicId, requester.PublicId)
+		require.NoError(err)
+		assert.Equal(AccessRequestCancelled, cancelled.Status)
+		_, err = repo.CancelAccessRequest(ctx, cancelled.PublicId, requester.PublicId)
+		assert.True(errors.Is(err, ErrAccessRequestNotPending))
+	})
+	t.Run("lookup-and-list", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.LookupAccessRequest(ctx, "areq_1234567890")
+		require.NoError(err)
+		assert.Nil(got)
+
+		all, err := repo.ListAccessRequests(ctx, role.PublicId)
+		require.NoError(err)
+		assert.Len(all, 4)
+		mine, err := repo.ListAccessRequests(ctx, role.PublicId, WithUserId(requester.PublicId))
+		require.NoError(err)
+		assert.Len(mine, 3)
+		for _, r := range mine {
+			assert.Equal(requester.PublicId, r.RequesterId)
+		}
+
+		got, err = repo.LookupAccessRequest(ctx, mine[0].PublicId)
+		require.NoError(err)
+		assert.Equal(mine[0], got)
+	})
+}
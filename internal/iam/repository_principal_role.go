@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/errors"
@@ -15,7 +16,9 @@ import (
 // groupIds) to a role (roleId).  The role's current db version must match the
 // roleVersion or an error will be returned.  The list of current PrincipalRoles
 // after the adds will be returned on success. Zero is not a valid value for
-// the WithVersion option and will return an error.
+// the WithVersion option and will return an error. The WithExpirationTime
+// option makes the added principals temporary: their assignments are ignored
+// once it has passed and removed by DeleteExpiredPrincipalRoles.
 func (r *Repository) AddPrincipalRoles(ctx context.Context, roleId string, roleVersion uint32, principalIds []string, opt ...Option) ([]PrincipalRole, error) {
 	if roleId == "" {
 		return nil, fmt.Errorf("add principal roles: missing role id: %w", errors.ErrInvalidParameter)
@@ -30,6 +33,10 @@ func (r *Repository) AddPrincipalRoles(ctx context.Context, roleId string, roleV
 	if len(userIds) == 0 && len(groupIds) == 0 {
 		return nil, fmt.Errorf("add principal roles: missing either user or groups to add: %w", errors.ErrInvalidParameter)
 	}
+	opts := getOpts(opt...)
+	if exp := opts.withExpirationTime; !exp.IsZero() && !exp.After(time.Now()) {
+		return nil, fmt.Errorf("add principal roles: expiration time must be in the future: %w", errors.ErrInvalidParameter)
+	}
 
 	newUserRoles := make([]interface{}, 0, len(userIds))
 	for _, id := range userIds {
@@ -97,6 +104,22 @@ func (r *Repository) AddPrincipalRoles(ctx context.Context, roleId string, roleV
 				}
 				msgs = append(msgs, grpOplogMsgs...)
 			}
+			if exp := opts.withExpirationTime; !exp.IsZero() {
+				for _, id := range userIds {
+					if _, err := w.Exec(ctx,
+						"insert into iam_user_role_expiration (role_id, principal_id, expiration_time) values (?, ?, ?)",
+						[]interface{}{roleId, id, exp}); err != nil {
+						return fmt.Errorf("add principal roles: unable to set user expiration: %w", err)
+					}
+				}
+				for _, id := range groupIds {
+					if _, err := w.Exec(ctx,
+						"insert into iam_group_role_expiration (role_id, principal_id, expiration_time) values (?, ?, ?)",
+						[]interface{}{roleId, id, exp}); err != nil {
+						return fmt.Errorf("add principal roles: unable to set group expiration: %w", err)
+					}
+				}
+			}
 			metadata := oplog.Metadata{
 				"op-type":            []string{oplog.OpType_OP_TYPE_CREATE.String()},
 				"scope-id":           []string{scope.PublicId},
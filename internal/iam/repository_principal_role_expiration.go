@@ -0,0 +1,77 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// PrincipalRoleExpiration is when the temporary assignment of a role to a
+// principal (user or group) expires.
+type PrincipalRoleExpiration struct {
+	RoleId         string
+	PrincipalId    string
+	ExpirationTime time.Time
+}
+
+// ListPrincipalRoleExpirations returns when the temporary assignments of the
+// role to its principals expire. Principals which were added without an
+// expiration are not returned. No options are currently supported.
+func (r *Repository) ListPrincipalRoleExpirations(ctx context.Context, roleId string, opt ...Option) ([]PrincipalRoleExpiration, error) {
+	if roleId == "" {
+		return nil, fmt.Errorf("list principal role expirations: missing role id: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, principalRoleExpirationsQuery, []interface{}{roleId})
+	if err != nil {
+		return nil, fmt.Errorf("list principal role expirations: %w for %s", err, roleId)
+	}
+	defer rows.Close()
+	var expirations []PrincipalRoleExpiration
+	for rows.Next() {
+		e := PrincipalRoleExpiration{RoleId: roleId}
+		if err := rows.Scan(&e.PrincipalId, &e.ExpirationTime); err != nil {
+			return nil, fmt.Errorf("list principal role expirations: %w for %s", err, roleId)
+		}
+		expirations = append(expirations, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list principal role expirations: %w for %s", err, roleId)
+	}
+	return expirations, nil
+}
+
+// DeleteExpiredPrincipalRoles removes the temporary assignments of roles to
+// principals which have expired, incrementing the version of their roles, and
+// returns the removed assignments. It is called periodically by the
+// controllers.
+func (r *Repository) DeleteExpiredPrincipalRoles(ctx context.Context) ([]PrincipalRoleExpiration, error) {
+	var removed []PrincipalRoleExpiration
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(read db.Reader, _ db.Writer) error {
+			removed = nil
+			rows, err := read.Query(ctx, deleteExpiredPrincipalRolesQuery, nil)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var e PrincipalRoleExpiration
+				if err := rows.Scan(&e.RoleId, &e.PrincipalId, &e.ExpirationTime); err != nil {
+					return err
+				}
+				removed = append(removed, e)
+			}
+			return rows.Err()
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("delete expired principal roles: %w", err)
+	}
+	return removed, nil
+}
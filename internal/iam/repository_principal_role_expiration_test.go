@@ -0,0 +1,98 @@
+package iam
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_PrincipalRoleExpiration(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+
+	org, _ := TestScopes(t, repo)
+	role := TestRole(t, conn, org.PublicId)
+	TestRoleGrant(t, conn, role.PublicId, "id=*;type=*;actions=read")
+	permanent := TestUser(t, repo, org.PublicId)
+	temporary := TestUser(t, repo, org.PublicId)
+	grp := TestGroup(t, conn, org.PublicId)
+	TestGroupMember(t, conn, grp.PublicId, temporary.PublicId)
+
+	hasRoleGrant := func(t *testing.T, userId string) bool {
+		t.Helper()
+		grants, err := repo.GrantsForUser(ctx, userId)
+		require.NoError(t, err)
+		for _, g := range grants {
+			if g.Grant == "id=*;type=*;actions=read" {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("invalid-expiration", func(t *testing.T) {
+		assert := assert.New(t)
+		_, err := repo.AddPrincipalRoles(ctx, role.PublicId, role.Version, []string{temporary.PublicId}, WithExpirationTime(time.Now().Add(-time.Minute)))
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+
+		_, err = repo.ListPrincipalRoleExpirations(ctx, "")
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+
+	_, err := repo.AddPrincipalRoles(ctx, role.PublicId, role.Version, []string{permanent.PublicId})
+	require.NoError(t, err)
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	_, err = repo.AddPrincipalRoles(ctx, role.PublicId, role.Version+1, []string{temporary.PublicId, grp.PublicId}, WithExpirationTime(exp))
+	require.NoError(t, err)
+
+	t.Run("active", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		exps, err := repo.ListPrincipalRoleExpirations(ctx, role.PublicId)
+		require.NoError(err)
+		require.Len(exps, 2)
+		for _, e := range exps {
+			assert.Contains([]string{temporary.PublicId, grp.PublicId}, e.PrincipalId)
+			assert.True(exp.Equal(e.ExpirationTime))
+		}
+		assert.True(hasRoleGrant(t, permanent.PublicId))
+		assert.True(hasRoleGrant(t, temporary.PublicId))
+
+		removed, err := repo.DeleteExpiredPrincipalRoles(ctx)
+		require.NoError(err)
+		assert.Empty(removed)
+	})
+	t.Run("expired", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, err := rw.Exec(ctx, "update iam_user_role_expiration set expiration_time = now() - interval '1 minute' where role_id = ?", []interface{}{role.PublicId})
+		require.NoError(err)
+		_, err = rw.Exec(ctx, "update iam_group_role_expiration set expiration_time = now() - interval '1 minute' where role_id = ?", []interface{}{role.PublicId})
+		require.NoError(err)
+
+		// Expired assignments are ignored before they are removed
+		assert.True(hasRoleGrant(t, permanent.PublicId))
+		assert.False(hasRoleGrant(t, temporary.PublicId))
+
+		removed, err := repo.DeleteExpiredPrincipalRoles(ctx)
+		require.NoError(err)
+		assert.Len(removed, 2)
+
+		r, principals, _, err := repo.LookupRole(ctx, role.PublicId)
+		require.NoError(err)
+		assert.Equal(role.Version+3, r.Version)
+		require.Len(principals, 1)
+		assert.Equal(permanent.PublicId, principals[0].PrincipalId)
+
+		exps, err := repo.ListPrincipalRoleExpirations(ctx, role.PublicId)
+		require.NoError(err)
+		assert.Empty(exps)
+	})
+}
@@ -0,0 +1,277 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+)
+
+const (
+	projectTemplateQuery = `
+	select coalesce(target_default_port, 0),
+	       coalesce(target_session_max_seconds, 0),
+	       coalesce(target_session_connection_limit, 0)
+	  from iam_scope_project_template
+	 where org_id = $1`
+
+	projectTemplateRolesQuery = `
+	select r.name, coalesce(r.description, ''), g.raw_grant
+	  from iam_scope_project_template_role r
+	  left join iam_scope_project_template_role_grant g
+	    on g.org_id = r.org_id
+	   and g.role_name = r.name
+	 where r.org_id = $1
+	 order by r.name, g.raw_grant`
+)
+
+// A ProjectTemplate is applied to every project created in an org: its roles
+// are created in the new project, and its target settings become the
+// defaults of the targets created in the project. A target setting of 0
+// keeps the default of targets.
+type ProjectTemplate struct {
+	OrgId                        string
+	TargetDefaultPort            uint32
+	TargetSessionMaxSeconds      uint32
+	TargetSessionConnectionLimit int32
+	Roles                        []*ProjectTemplateRole
+}
+
+// A ProjectTemplateRole is a role created in every new project of an org.
+type ProjectTemplateRole struct {
+	Name        string
+	Description string
+	Grants      []string
+}
+
+// SetProjectTemplate sets the project template of the org, replacing its
+// current one. Projects already created are not changed. A nil template
+// removes it. No options are currently supported.
+func (r *Repository) SetProjectTemplate(ctx context.Context, orgId string, t *ProjectTemplate, opt ...Option) error {
+	if !strings.HasPrefix(orgId, scope.Org.Prefix()+"_") {
+		return fmt.Errorf("set project template: missing org id: %w", errors.ErrInvalidParameter)
+	}
+	if t != nil {
+		if err := t.validate(); err != nil {
+			return fmt.Errorf("set project template: %w", err)
+		}
+	}
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			// Roles and their grants are deleted by cascade
+			if _, err := w.Exec(ctx, "delete from iam_scope_project_template where org_id = ?", []interface{}{orgId}); err != nil {
+				return err
+			}
+			if t == nil {
+				return nil
+			}
+			if _, err := w.Exec(ctx,
+				`insert into iam_scope_project_template (org_id, target_default_port, target_session_max_seconds, target_session_connection_limit)
+				values (?, nullif(?, 0), nullif(?, 0), nullif(?, 0))`,
+				[]interface{}{orgId, t.TargetDefaultPort, t.TargetSessionMaxSeconds, t.TargetSessionConnectionLimit}); err != nil {
+				return err
+			}
+			for _, role := range t.Roles {
+				if _, err := w.Exec(ctx,
+					"insert into iam_scope_project_template_role (org_id, name, description) values (?, ?, nullif(?, ''))",
+					[]interface{}{orgId, role.Name, role.Description}); err != nil {
+					return err
+				}
+				for _, g := range role.Grants {
+					if _, err := w.Exec(ctx,
+						"insert into iam_scope_project_template_role_grant (org_id, role_name, raw_grant) values (?, ?, ?)",
+						[]interface{}{orgId, role.Name, g}); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("set project template: %w for %s", err, orgId)
+	}
+	return nil
+}
+
+// LookupProjectTemplate returns the project template of the org, or nil if
+// it has none. No options are currently supported.
+func (r *Repository) LookupProjectTemplate(ctx context.Context, orgId string, opt ...Option) (*ProjectTemplate, error) {
+	if orgId == "" {
+		return nil, fmt.Errorf("lookup project template: missing org id: %w", errors.ErrInvalidParameter)
+	}
+	t, err := lookupProjectTemplate(ctx, r.reader, orgId)
+	if err != nil {
+		return nil, fmt.Errorf("lookup project template: %w for %s", err, orgId)
+	}
+	return t, nil
+}
+
+func lookupProjectTemplate(ctx context.Context, reader db.Reader, orgId string) (*ProjectTemplate, error) {
+	rows, err := reader.Query(ctx, projectTemplateQuery, []interface{}{orgId})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var t *ProjectTemplate
+	for rows.Next() {
+		t = &ProjectTemplate{OrgId: orgId}
+		if err := rows.Scan(&t.TargetDefaultPort, &t.TargetSessionMaxSeconds, &t.TargetSessionConnectionLimit); err != nil {
+			return nil, fmt.Errorf("unable to scan row: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, nil
+	}
+
+	roleRows, err := reader.Query(ctx, projectTemplateRolesQuery, []interface{}{orgId})
+	if err != nil {
+		return nil, err
+	}
+	defer roleRows.Close()
+	var role *ProjectTemplateRole
+	for roleRows.Next() {
+		var name, description string
+		var grant *string
+		if err := roleRows.Scan(&name, &description, &grant); err != nil {
+			return nil, fmt.Errorf("unable to scan row: %w", err)
+		}
+		if role == nil || role.Name != name {
+			role = &ProjectTemplateRole{Name: name, Description: description}
+			t.Roles = append(t.Roles, role)
+		}
+		if grant != nil {
+			role.Grants = append(role.Grants, *grant)
+		}
+	}
+	if err := roleRows.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *ProjectTemplate) validate() error {
+	if t.TargetSessionConnectionLimit < -1 {
+		return fmt.Errorf("target session connection limit must be -1, 0 or greater: %w", errors.ErrInvalidParameter)
+	}
+	names := make(map[string]bool, len(t.Roles))
+	for _, role := range t.Roles {
+		if role == nil || strings.TrimSpace(role.Name) == "" {
+			return fmt.Errorf("missing role name: %w", errors.ErrInvalidParameter)
+		}
+		if names[role.Name] {
+			return fmt.Errorf("duplicate role name %q: %w", role.Name, errors.ErrInvalidParameter)
+		}
+		names[role.Name] = true
+		grants := make(map[string]bool, len(role.Grants))
+		for _, g := range role.Grants {
+			if grants[g] {
+				return fmt.Errorf("duplicate grant %q for role %q: %w", g, role.Name, errors.ErrInvalidParameter)
+			}
+			grants[g] = true
+			// As with role grants, the scope is faked since only parsing
+			// matters here
+			if _, err := perms.Parse("p_abcd1234", g); err != nil {
+				return fmt.Errorf("error parsing grant %q for role %q: %s: %w", g, role.Name, err, errors.ErrInvalidParameter)
+			}
+		}
+	}
+	return nil
+}
+
+// applyProjectTemplate applies the template of the org of a new project, if
+// it has one, within the transaction creating the project.
+func applyProjectTemplate(ctx context.Context, dbr db.Reader, w db.Writer, oplogWrapper wrapping.Wrapper, project *Scope) error {
+	t, err := lookupProjectTemplate(ctx, dbr, project.ParentId)
+	if err != nil {
+		return fmt.Errorf("unable to look up project template: %w", err)
+	}
+	if t == nil {
+		return nil
+	}
+
+	if _, err := w.Exec(ctx,
+		`insert into iam_scope_project_target_default (project_id, default_port, session_max_seconds, session_connection_limit)
+		values (?, nullif(?, 0), nullif(?, 0), nullif(?, 0))`,
+		[]interface{}{project.PublicId, t.TargetDefaultPort, t.TargetSessionMaxSeconds, t.TargetSessionConnectionLimit}); err != nil {
+		return fmt.Errorf("unable to set target defaults: %w", err)
+	}
+
+	for _, tr := range t.Roles {
+		role, err := NewRole(project.PublicId, WithName(tr.Name), WithDescription(tr.Description))
+		if err != nil {
+			return fmt.Errorf("unable to create in memory role: %w", err)
+		}
+		if role.PublicId, err = newRoleId(); err != nil {
+			return fmt.Errorf("unable to generate public id for role: %w", err)
+		}
+		metadata := oplog.Metadata{
+			"resource-public-id": []string{role.PublicId},
+			"scope-id":           []string{project.PublicId},
+			"scope-type":         []string{project.Type},
+			"resource-type":      []string{resource.Role.String()},
+			"op-type":            []string{oplog.OpType_OP_TYPE_CREATE.String()},
+		}
+		if err := w.Create(ctx, role, db.WithOplog(oplogWrapper, metadata)); err != nil {
+			return fmt.Errorf("error creating role: %w", err)
+		}
+		if len(tr.Grants) == 0 {
+			continue
+		}
+
+		roleTicket, err := w.GetTicket(role)
+		if err != nil {
+			return fmt.Errorf("unable to get ticket: %w", err)
+		}
+		msgs := make([]*oplog.Message, 0, len(tr.Grants)+1)
+
+		// We need to update the role version as that's the aggregate
+		var roleOplogMsg oplog.Message
+		rowsUpdated, err := w.Update(ctx, role, []string{"Version"}, nil, db.NewOplogMsg(&roleOplogMsg), db.WithVersion(&role.Version))
+		if err != nil {
+			return fmt.Errorf("unable to update role version for adding grant: %w", err)
+		}
+		if rowsUpdated != 1 {
+			return fmt.Errorf("updated role but %d rows updated", rowsUpdated)
+		}
+		msgs = append(msgs, &roleOplogMsg)
+
+		grants := make([]interface{}, 0, len(tr.Grants))
+		for _, g := range tr.Grants {
+			roleGrant, err := NewRoleGrant(role.PublicId, g)
+			if err != nil {
+				return fmt.Errorf("unable to create in memory role grant: %w", err)
+			}
+			grants = append(grants, roleGrant)
+		}
+		roleGrantOplogMsgs := make([]*oplog.Message, 0, len(grants))
+		if err := w.CreateItems(ctx, grants, db.NewOplogMsgs(&roleGrantOplogMsgs)); err != nil {
+			return fmt.Errorf("unable to add grants: %w", err)
+		}
+		msgs = append(msgs, roleGrantOplogMsgs...)
+
+		metadata = oplog.Metadata{
+			"op-type":            []string{oplog.OpType_OP_TYPE_CREATE.String()},
+			"scope-id":           []string{project.PublicId},
+			"scope-type":         []string{project.Type},
+			"resource-public-id": []string{role.PublicId},
+		}
+		if err := w.WriteOplogEntryWith(ctx, oplogWrapper, roleTicket, metadata, msgs); err != nil {
+			return fmt.Errorf("unable to write oplog: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package iam
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ProjectTemplate(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+	org := TestOrg(t, repo)
+
+	template := &ProjectTemplate{
+		OrgId:                        org.PublicId,
+		TargetSessionMaxSeconds:      3600,
+		TargetSessionConnectionLimit: -1,
+		Roles: []*ProjectTemplateRole{
+			{Name: "Operators", Description: "Connect to targets", Grants: []string{"id=*;type=target;actions=authorize-session,list,read"}},
+			{Name: "Viewers"},
+		},
+	}
+
+	t.Run("set-lookup-remove", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.LookupProjectTemplate(ctx, org.PublicId)
+		require.NoError(err)
+		assert.Nil(got)
+
+		require.NoError(repo.SetProjectTemplate(ctx, org.PublicId, template))
+		got, err = repo.LookupProjectTemplate(ctx, org.PublicId)
+		require.NoError(err)
+		assert.Equal(template, got)
+
+		// Setting replaces the whole template
+		replacement := &ProjectTemplate{
+			OrgId:             org.PublicId,
+			TargetDefaultPort: 22,
+			Roles:             []*ProjectTemplateRole{{Name: "Admins", Grants: []string{"id=*;type=*;actions=*"}}},
+		}
+		require.NoError(repo.SetProjectTemplate(ctx, org.PublicId, replacement))
+		got, err = repo.LookupProjectTemplate(ctx, org.PublicId)
+		require.NoError(err)
+		assert.Equal(replacement, got)
+
+		require.NoError(repo.SetProjectTemplate(ctx, org.PublicId, nil))
+		got, err = repo.LookupProjectTemplate(ctx, org.PublicId)
+		require.NoError(err)
+		assert.Nil(got)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			orgId string
+			t     *ProjectTemplate
+		}{
+			{name: "missing-org", t: &ProjectTemplate{}},
+			{name: "not-an-org", orgId: "global", t: &ProjectTemplate{}},
+			{name: "connection-limit", orgId: org.PublicId, t: &ProjectTemplate{TargetSessionConnectionLimit: -2}},
+			{name: "missing-role-name", orgId: org.PublicId, t: &ProjectTemplate{Roles: []*ProjectTemplateRole{{}}}},
+			{name: "duplicate-role", orgId: org.PublicId, t: &ProjectTemplate{Roles: []*ProjectTemplateRole{{Name: "a"}, {Name: "a"}}}},
+			{name: "bad-grant", orgId: org.PublicId, t: &ProjectTemplate{Roles: []*ProjectTemplateRole{{Name: "a", Grants: []string{"id=*"}}}}},
+			{name: "duplicate-grant", orgId: org.PublicId, t: &ProjectTemplate{Roles: []*ProjectTemplateRole{{Name: "a", Grants: []string{"id=*;actions=read", "id=*;actions=read"}}}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := repo.SetProjectTemplate(ctx, tt.orgId, tt.t)
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, errors.ErrInvalidParameter), err.Error())
+			})
+		}
+	})
+}
+
+func TestRepository_CreateScope_ProjectTemplate(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+	org := TestOrg(t, repo)
+
+	require.NoError(t, repo.SetProjectTemplate(ctx, org.PublicId, &ProjectTemplate{
+		OrgId:                   org.PublicId,
+		TargetSessionMaxSeconds: 3600,
+		Roles: []*ProjectTemplateRole{
+			{Name: "Operators", Description: "Connect to targets", Grants: []string{"id=*;type=target;actions=authorize-session", "id=*;type=target;actions=list,read"}},
+			{Name: "Viewers"},
+		},
+	}))
+
+	targetDefaults := func(t *testing.T, projectId string) (found bool, sessionMaxSeconds uint32) {
+		t.Helper()
+		rows, err := rw.Query(ctx, "select coalesce(session_max_seconds, 0) from iam_scope_project_target_default where project_id = $1", []interface{}{projectId})
+		require.NoError(t, err)
+		defer rows.Close()
+		for rows.Next() {
+			found = true
+			require.NoError(t, rows.Scan(&sessionMaxSeconds))
+		}
+		require.NoError(t, rows.Err())
+		return found, sessionMaxSeconds
+	}
+
+	t.Run("applied", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		p, err := NewProject(org.PublicId)
+		require.NoError(err)
+		prj, err := repo.CreateScope(ctx, p, "")
+		require.NoError(err)
+
+		roles, err := repo.ListRoles(ctx, prj.PublicId)
+		require.NoError(err)
+		byName := map[string]*Role{}
+		for _, r := range roles {
+			byName[r.Name] = r
+		}
+		require.Len(byName, 2)
+		require.Contains(byName, "Operators")
+		require.Contains(byName, "Viewers")
+		assert.Equal("Connect to targets", byName["Operators"].Description)
+
+		grants, err := repo.ListRoleGrants(ctx, byName["Operators"].PublicId)
+		require.NoError(err)
+		var raw []string
+		for _, g := range grants {
+			raw = append(raw, g.RawGrant)
+		}
+		assert.ElementsMatch([]string{"id=*;type=target;actions=authorize-session", "id=*;type=target;actions=list,read"}, raw)
+		grants, err = repo.ListRoleGrants(ctx, byName["Viewers"].PublicId)
+		require.NoError(err)
+		assert.Empty(grants)
+
+		// The role with grants is written to the oplog as the default roles are
+		require.NoError(db.TestVerifyOplog(t, rw, byName["Operators"].PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
+
+		found, sessionMaxSeconds := targetDefaults(t, prj.PublicId)
+		assert.True(found)
+		assert.Equal(uint32(3600), sessionMaxSeconds)
+	})
+
+	t.Run("skipped", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		p, err := NewProject(org.PublicId)
+		require.NoError(err)
+		prj, err := repo.CreateScope(ctx, p, "", WithSkipProjectTemplate(true))
+		require.NoError(err)
+		roles, err := repo.ListRoles(ctx, prj.PublicId)
+		require.NoError(err)
+		assert.Empty(roles)
+		found, _ := targetDefaults(t, prj.PublicId)
+		assert.False(found)
+	})
+
+	t.Run("other-org", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := TestScopes(t, repo)
+		roles, err := repo.ListRoles(ctx, prj.PublicId)
+		require.NoError(err)
+		assert.Empty(roles)
+		found, _ := targetDefaults(t, prj.PublicId)
+		assert.False(found)
+	})
+}
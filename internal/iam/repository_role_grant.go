@@ -381,11 +381,21 @@ func (r *Repository) ListRoleGrants(ctx context.Context, roleId string, opt ...O
 	return roleGrants, nil
 }
 
+// GrantsForUser returns the grants of the user, including those of u_anon and
+// u_auth for authenticated users. They are served from the grants cache of
+// the repository, if it has one.
 func (r *Repository) GrantsForUser(ctx context.Context, userId string, opt ...Option) ([]perms.GrantPair, error) {
 	if userId == "" {
 		return nil, fmt.Errorf("get grants for user: missing user id: %w", errors.ErrInvalidParameter)
 	}
+	if r.grantsCache != nil {
+		return r.cachedGrantsForUser(ctx, userId)
+	}
+	return r.grantsForUser(ctx, userId)
+}
 
+// grantsForUser reads the grants of the user from the database.
+func (r *Repository) grantsForUser(ctx context.Context, userId string) ([]perms.GrantPair, error) {
 	const (
 		anonUser    = `where public_id in ($1)`
 		authUser    = `where public_id in ('u_anon', 'u_auth', $1)`
@@ -407,12 +417,26 @@ group_roles (role_id) as (
     from iam_group_role,
          user_groups
    where principal_id in (user_groups.id)
+     and not exists (
+           select
+             from iam_group_role_expiration e
+            where e.role_id = iam_group_role.role_id
+              and e.principal_id = iam_group_role.principal_id
+              and e.expiration_time <= now()
+         )
 ),
 user_roles (role_id) as (
   select role_id
     from iam_user_role,
          users
    where principal_id in (users.id)
+     and not exists (
+           select
+             from iam_user_role_expiration e
+            where e.role_id = iam_user_role.role_id
+              and e.principal_id = iam_user_role.principal_id
+              and e.expiration_time <= now()
+         )
 ),
 user_group_roles (role_id) as (
   select role_id
@@ -17,7 +17,9 @@ import (
 )
 
 // CreateScope will create a scope in the repository and return the written
-// scope. Supported options include: WithPublicId and WithRandomReader.
+// scope. A project is created with the project template of its org, if it has
+// one. Supported options include: WithPublicId, WithRandomReader and
+// WithSkipProjectTemplate.
 func (r *Repository) CreateScope(ctx context.Context, s *Scope, userId string, opt ...Option) (*Scope, error) {
 	if s == nil {
 		return nil, fmt.Errorf("create scope: missing scope %w", errors.ErrInvalidParameter)
@@ -166,6 +168,7 @@ func (r *Repository) CreateScope(ctx context.Context, s *Scope, userId string, o
 			}
 
 			s := scopeRaw.(*Scope)
+			w.NotifyOnCommit(ctx, db.Change{ResourceType: resource.Scope, Op: db.CreateOp, Id: s.PublicId})
 
 			// Create the scope's keys
 			_, err = kms.CreateKeysTx(ctx, dbr, w, externalWrappers.Root(), reader, s.PublicId)
@@ -330,6 +333,12 @@ func (r *Repository) CreateScope(ctx context.Context, s *Scope, userId string, o
 				}
 			}
 
+			if s.Type == scope.Project.String() && !opts.withSkipProjectTemplate {
+				if err := applyProjectTemplate(ctx, dbr, w, childOplogWrapper, s); err != nil {
+					return fmt.Errorf("error applying project template: %w", err)
+				}
+			}
+
 			return nil
 		},
 	)
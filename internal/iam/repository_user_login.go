@@ -0,0 +1,455 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam/store"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/securityevent"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultInactivityPolicyTableName = "iam_scope_inactivity_policy"
+	defaultUserDisabledTableName     = "iam_user_disabled"
+)
+
+// UserLogins holds the last logins of a user, which are recorded when auth
+// tokens are issued to it by authenticating.
+type UserLogins struct {
+	UserId string
+	// LastLoginTime is the last login of the user with any of its accounts,
+	// nil if it never logged in
+	LastLoginTime *time.Time
+	// Accounts holds the last login time of the user by account id
+	Accounts map[string]time.Time
+}
+
+// GetLastLoginTime returns the last login of the user, nil if it never logged
+// in or l is nil.
+func (l *UserLogins) GetLastLoginTime() *time.Time {
+	if l == nil {
+		return nil
+	}
+	return l.LastLoginTime
+}
+
+// GetAccounts returns the last login time of the user by account id, nil if
+// l is nil.
+func (l *UserLogins) GetAccounts() map[string]time.Time {
+	if l == nil {
+		return nil
+	}
+	return l.Accounts
+}
+
+// addLogin records the last login of the user with the account.
+func (l *UserLogins) addLogin(accountId string, lastLogin time.Time) {
+	l.Accounts[accountId] = lastLogin
+	if l.LastLoginTime == nil || lastLogin.After(*l.LastLoginTime) {
+		t := lastLogin
+		l.LastLoginTime = &t
+	}
+}
+
+// UserDisabled records that a user may not authenticate and why.
+type UserDisabled struct {
+	*store.UserDisabled
+	tableName string `gorm:"-"`
+}
+
+func allocUserDisabled() *UserDisabled {
+	return &UserDisabled{
+		UserDisabled: &store.UserDisabled{},
+	}
+}
+
+func (d *UserDisabled) clone() *UserDisabled {
+	cp := proto.Clone(d.UserDisabled)
+	return &UserDisabled{
+		UserDisabled: cp.(*store.UserDisabled),
+	}
+}
+
+// TableName returns the table name for disabled users.
+func (d *UserDisabled) TableName() string {
+	if d.tableName != "" {
+		return d.tableName
+	}
+	return defaultUserDisabledTableName
+}
+
+// SetTableName sets the table name.
+func (d *UserDisabled) SetTableName(n string) {
+	d.tableName = n
+}
+
+func (d *UserDisabled) oplog(op oplog.OpType, scopeId string) oplog.Metadata {
+	return oplog.Metadata{
+		"resource-public-id": []string{d.GetIamUserId()},
+		"resource-type":      []string{"disabled user"},
+		"op-type":            []string{op.String()},
+		"scope-id":           []string{scopeId},
+	}
+}
+
+// InactiveUser is a user which has not logged in for longer than allowed.
+type InactiveUser struct {
+	UserId     string
+	Name       string
+	CreateTime time.Time
+	// LastLoginTime is nil if the user never logged in
+	LastLoginTime *time.Time
+	Disabled      bool
+}
+
+// An InactivityPolicy is how many days the users of a scope may go without
+// logging in before they are inactive and, if DisableInactive is set,
+// disabled by the controllers.
+type InactivityPolicy struct {
+	ScopeId         string `gorm:"primary_key"`
+	MaxInactiveDays uint32
+	DisableInactive bool
+	CreateTime      *timestamp.Timestamp `gorm:"default:current_timestamp"`
+	UpdateTime      *timestamp.Timestamp `gorm:"default:current_timestamp"`
+}
+
+// TableName returns the table name for the inactivity policy.
+func (p *InactivityPolicy) TableName() string {
+	return defaultInactivityPolicyTableName
+}
+
+// LookupUserLogins returns the last logins of the user. No options are
+// currently supported.
+func (r *Repository) LookupUserLogins(ctx context.Context, userId string, opt ...Option) (*UserLogins, error) {
+	if userId == "" {
+		return nil, fmt.Errorf("lookup user logins: missing user id: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, userLoginsQuery, []interface{}{userId})
+	if err != nil {
+		return nil, fmt.Errorf("lookup user logins: %w for %s", err, userId)
+	}
+	defer rows.Close()
+	logins := &UserLogins{
+		UserId:   userId,
+		Accounts: map[string]time.Time{},
+	}
+	for rows.Next() {
+		var accountId string
+		var lastLogin time.Time
+		if err := rows.Scan(&accountId, &lastLogin); err != nil {
+			return nil, fmt.Errorf("lookup user logins: unable to scan row: %w", err)
+		}
+		logins.addLogin(accountId, lastLogin)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("lookup user logins: %w for %s", err, userId)
+	}
+	return logins, nil
+}
+
+// ListUserLogins returns the last logins of the users of the scope which
+// have logged in. No options are currently supported.
+func (r *Repository) ListUserLogins(ctx context.Context, scopeId string, opt ...Option) ([]*UserLogins, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list user logins: missing scope id: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, scopeUserLoginsQuery, []interface{}{scopeId})
+	if err != nil {
+		return nil, fmt.Errorf("list user logins: %w for %s", err, scopeId)
+	}
+	defer rows.Close()
+	var logins []*UserLogins
+	var current *UserLogins
+	for rows.Next() {
+		var userId, accountId string
+		var lastLogin time.Time
+		if err := rows.Scan(&userId, &accountId, &lastLogin); err != nil {
+			return nil, fmt.Errorf("list user logins: unable to scan row: %w", err)
+		}
+		if current == nil || current.UserId != userId {
+			current = &UserLogins{
+				UserId:   userId,
+				Accounts: map[string]time.Time{},
+			}
+			logins = append(logins, current)
+		}
+		current.addLogin(accountId, lastLogin)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list user logins: %w for %s", err, scopeId)
+	}
+	return logins, nil
+}
+
+// ListInactiveUsers returns the users of the scope which have not logged in
+// for the number of days, or were created that long ago and never logged in,
+// least recently active first. No options are currently supported.
+func (r *Repository) ListInactiveUsers(ctx context.Context, scopeId string, days uint32, opt ...Option) ([]*InactiveUser, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list inactive users: missing scope id: %w", errors.ErrInvalidParameter)
+	}
+	if days == 0 {
+		return nil, fmt.Errorf("list inactive users: missing days: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, inactiveUsersQuery, []interface{}{scopeId, days})
+	if err != nil {
+		return nil, fmt.Errorf("list inactive users: %w for %s", err, scopeId)
+	}
+	defer rows.Close()
+	var users []*InactiveUser
+	for rows.Next() {
+		u := &InactiveUser{}
+		if err := rows.Scan(&u.UserId, &u.Name, &u.CreateTime, &u.LastLoginTime, &u.Disabled); err != nil {
+			return nil, fmt.Errorf("list inactive users: unable to scan row: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list inactive users: %w for %s", err, scopeId)
+	}
+	return users, nil
+}
+
+// SetInactivityPolicy sets the inactivity policy of the scope. Setting
+// maxInactiveDays to 0 removes it. No options are currently supported.
+func (r *Repository) SetInactivityPolicy(ctx context.Context, scopeId string, maxInactiveDays uint32, disableInactive bool, opt ...Option) error {
+	if scopeId == "" {
+		return fmt.Errorf("set inactivity policy: missing scope id: %w", errors.ErrInvalidParameter)
+	}
+	var err error
+	switch {
+	case maxInactiveDays == 0:
+		_, err = r.writer.Exec(ctx,
+			"delete from iam_scope_inactivity_policy where scope_id = ?",
+			[]interface{}{scopeId})
+	default:
+		_, err = r.writer.Exec(ctx,
+			`insert into iam_scope_inactivity_policy (scope_id, max_inactive_days, disable_inactive) values (?, ?, ?)
+			on conflict (scope_id) do update set
+				max_inactive_days = excluded.max_inactive_days,
+				disable_inactive = excluded.disable_inactive`,
+			[]interface{}{scopeId, maxInactiveDays, disableInactive})
+	}
+	if err != nil {
+		return fmt.Errorf("set inactivity policy: %w for %s", err, scopeId)
+	}
+	return nil
+}
+
+// LookupInactivityPolicy returns the inactivity policy of the scope. A scope
+// without a policy returns an InactivityPolicy with MaxInactiveDays 0. No
+// options are currently supported.
+func (r *Repository) LookupInactivityPolicy(ctx context.Context, scopeId string, opt ...Option) (*InactivityPolicy, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("lookup inactivity policy: missing scope id: %w", errors.ErrInvalidParameter)
+	}
+	p := &InactivityPolicy{}
+	if err := r.reader.LookupWhere(ctx, p, "scope_id = ?", scopeId); err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return &InactivityPolicy{ScopeId: scopeId}, nil
+		}
+		return nil, fmt.Errorf("lookup inactivity policy: %w for %s", err, scopeId)
+	}
+	return p, nil
+}
+
+// DisableUser disables the user, deleting its auth tokens, so it may not
+// authenticate until it is enabled again, and records a lockout security
+// event. Disabling a disabled user keeps the original reason. No options are
+// currently supported.
+func (r *Repository) DisableUser(ctx context.Context, userId, reason string, opt ...Option) error {
+	if userId == "" {
+		return fmt.Errorf("disable user: missing user id: %w", errors.ErrInvalidParameter)
+	}
+	if reason == "" {
+		return fmt.Errorf("disable user: missing reason: %w", errors.ErrInvalidParameter)
+	}
+	user, _, err := r.LookupUser(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("disable user: %w", err)
+	}
+	if user == nil {
+		return fmt.Errorf("disable user: %w for %s", errors.ErrRecordNotFound, userId)
+	}
+	oplogWrapper, err := r.kms.GetWrapper(ctx, user.GetScopeId(), kms.KeyPurposeOplog)
+	if err != nil {
+		return fmt.Errorf("disable user: unable to get oplog wrapper: %w", err)
+	}
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(read db.Reader, w db.Writer) error {
+			existing := allocUserDisabled()
+			err := read.LookupWhere(ctx, existing, "iam_user_id = ?", userId)
+			switch {
+			case err == nil:
+				return nil
+			case !errors.IsNotFoundError(err):
+				return err
+			}
+			return r.disableUser(ctx, w, oplogWrapper, user.GetScopeId(), userId, reason)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("disable user: %w for %s", err, userId)
+	}
+	return nil
+}
+
+// disableUser records the user of the scope as disabled, deletes its auth
+// tokens and records a lockout security event.
+func (r *Repository) disableUser(ctx context.Context, w db.Writer, oplogWrapper wrapping.Wrapper, scopeId, userId, reason string) error {
+	d := allocUserDisabled()
+	d.IamUserId = userId
+	d.Reason = reason
+	if err := w.Create(ctx, d, db.WithOplog(oplogWrapper, d.oplog(oplog.OpType_OP_TYPE_CREATE, scopeId))); err != nil {
+		return err
+	}
+	if _, err := w.Exec(ctx, deleteUserAuthTokensQuery, []interface{}{userId}); err != nil {
+		return err
+	}
+	return securityevent.Enqueue(ctx, w, &securityevent.Event{Kind: securityevent.Lockout, ScopeId: scopeId})
+}
+
+// EnableUser enables the disabled user. Enabling a user restarts its
+// inactivity: it is inactive again only once it does not log in for the
+// scope's maximum inactive days. It does not count as a login. Enabling a
+// user which is not disabled does nothing. No options are currently
+// supported.
+func (r *Repository) EnableUser(ctx context.Context, userId string, opt ...Option) error {
+	if userId == "" {
+		return fmt.Errorf("enable user: missing user id: %w", errors.ErrInvalidParameter)
+	}
+	user, _, err := r.LookupUser(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("enable user: %w", err)
+	}
+	if user == nil {
+		return fmt.Errorf("enable user: %w for %s", errors.ErrRecordNotFound, userId)
+	}
+	oplogWrapper, err := r.kms.GetWrapper(ctx, user.GetScopeId(), kms.KeyPurposeOplog)
+	if err != nil {
+		return fmt.Errorf("enable user: unable to get oplog wrapper: %w", err)
+	}
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			d := allocUserDisabled()
+			d.IamUserId = userId
+			rowsDeleted, err := w.Delete(ctx, d.clone(), db.WithOplog(oplogWrapper, d.oplog(oplog.OpType_OP_TYPE_DELETE, user.GetScopeId())))
+			switch {
+			case err != nil:
+				return err
+			case rowsDeleted > 1:
+				return errors.ErrMultipleRecords
+			case rowsDeleted == 0:
+				return nil
+			}
+			_, err = w.Exec(ctx,
+				`insert into iam_user_enabled (iam_user_id) values (?)
+				on conflict (iam_user_id) do update
+					set enable_time = current_timestamp`,
+				[]interface{}{userId})
+			return err
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("enable user: %w for %s", err, userId)
+	}
+	return nil
+}
+
+// LookupUserDisabled returns why the user is disabled, or nil if it is not.
+// No options are currently supported.
+func (r *Repository) LookupUserDisabled(ctx context.Context, userId string, opt ...Option) (*UserDisabled, error) {
+	if userId == "" {
+		return nil, fmt.Errorf("lookup user disabled: missing user id: %w", errors.ErrInvalidParameter)
+	}
+	d := allocUserDisabled()
+	if err := r.reader.LookupWhere(ctx, d, "iam_user_id = ?", userId); err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup user disabled: %w for %s", err, userId)
+	}
+	return d, nil
+}
+
+// ListDisabledUsers returns why the disabled users of the scope are disabled.
+// No options are currently supported.
+func (r *Repository) ListDisabledUsers(ctx context.Context, scopeId string, opt ...Option) ([]*UserDisabled, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list disabled users: missing scope id: %w", errors.ErrInvalidParameter)
+	}
+	var disabled []*UserDisabled
+	if err := r.reader.SearchWhere(ctx, &disabled,
+		"iam_user_id in (select public_id from iam_user where scope_id = ?)",
+		[]interface{}{scopeId}, db.WithLimit(-1)); err != nil {
+		return nil, fmt.Errorf("list disabled users: %w for %s", err, scopeId)
+	}
+	return disabled, nil
+}
+
+// DisableInactiveUsers disables the inactive users of the scopes whose
+// inactivity policy disables them, deleting their auth tokens and recording
+// lockout security events, and returns their ids.
+func (r *Repository) DisableInactiveUsers(ctx context.Context) ([]string, error) {
+	var userIds []string
+	oplogWrappers := map[string]wrapping.Wrapper{}
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(read db.Reader, w db.Writer) error {
+			userIds = nil
+			rows, err := read.Query(ctx, inactiveUsersToDisableQuery, nil)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			var scopeIds, reasons []string
+			for rows.Next() {
+				var id, scopeId, reason string
+				if err := rows.Scan(&id, &scopeId, &reason); err != nil {
+					return err
+				}
+				userIds = append(userIds, id)
+				scopeIds = append(scopeIds, scopeId)
+				reasons = append(reasons, reason)
+			}
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			rows.Close()
+			for i, id := range userIds {
+				oplogWrapper, ok := oplogWrappers[scopeIds[i]]
+				if !ok {
+					oplogWrapper, err = r.kms.GetWrapper(ctx, scopeIds[i], kms.KeyPurposeOplog)
+					if err != nil {
+						return fmt.Errorf("unable to get oplog wrapper: %w", err)
+					}
+					oplogWrappers[scopeIds[i]] = oplogWrapper
+				}
+				if err := r.disableUser(ctx, w, oplogWrapper, scopeIds[i], id, reasons[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("disable inactive users: %w", err)
+	}
+	return userIds, nil
+}
@@ -0,0 +1,193 @@
+package iam
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_UserLogins(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+
+	org, _ := TestScopes(t, repo)
+	authMethodId := testAuthMethod(t, conn, org.PublicId)
+	active := TestUser(t, repo, org.PublicId)
+	inactive := TestUser(t, repo, org.PublicId)
+	activeAcct := testAccount(t, conn, org.PublicId, authMethodId, active.PublicId)
+	inactiveAcct := testAccount(t, conn, org.PublicId, authMethodId, inactive.PublicId)
+
+	logins, err := repo.LookupUserLogins(ctx, inactive.PublicId)
+	require.NoError(t, err)
+	assert.Nil(t, logins.LastLoginTime)
+	assert.Empty(t, logins.Accounts)
+
+	_, err = rw.Exec(ctx,
+		"insert into iam_user_login (iam_user_id, auth_account_id, last_login_time) values (?, ?, now() - interval '2 days')",
+		[]interface{}{inactive.PublicId, inactiveAcct.PublicId})
+	require.NoError(t, err)
+	_, err = rw.Exec(ctx,
+		"insert into iam_user_login (iam_user_id, auth_account_id) values (?, ?)",
+		[]interface{}{active.PublicId, activeAcct.PublicId})
+	require.NoError(t, err)
+
+	t.Run("lookup", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		logins, err := repo.LookupUserLogins(ctx, inactive.PublicId)
+		require.NoError(err)
+		require.NotNil(logins.LastLoginTime)
+		assert.Equal(*logins.LastLoginTime, logins.Accounts[inactiveAcct.PublicId])
+		assert.Len(logins.Accounts, 1)
+
+		_, err = repo.LookupUserLogins(ctx, "")
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+	t.Run("list", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		list, err := repo.ListUserLogins(ctx, org.PublicId)
+		require.NoError(err)
+		require.Len(list, 2)
+		byUser := map[string]*UserLogins{}
+		for _, l := range list {
+			byUser[l.UserId] = l
+		}
+		require.Contains(byUser, active.PublicId)
+		require.Contains(byUser, inactive.PublicId)
+		assert.Contains(byUser[active.PublicId].Accounts, activeAcct.PublicId)
+		assert.True(byUser[active.PublicId].LastLoginTime.After(*byUser[inactive.PublicId].LastLoginTime))
+
+		list, err = repo.ListUserLogins(ctx, "global")
+		require.NoError(err)
+		for _, l := range list {
+			assert.NotEqual(active.PublicId, l.UserId)
+		}
+
+		_, err = repo.ListUserLogins(ctx, "")
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+	t.Run("list-inactive", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		users, err := repo.ListInactiveUsers(ctx, org.PublicId, 1)
+		require.NoError(err)
+		require.Len(users, 1)
+		assert.Equal(inactive.PublicId, users[0].UserId)
+		assert.NotNil(users[0].LastLoginTime)
+		assert.False(users[0].Disabled)
+
+		users, err = repo.ListInactiveUsers(ctx, org.PublicId, 3)
+		require.NoError(err)
+		assert.Empty(users)
+
+		_, err = repo.ListInactiveUsers(ctx, org.PublicId, 0)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+	t.Run("policy", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		p, err := repo.LookupInactivityPolicy(ctx, org.PublicId)
+		require.NoError(err)
+		assert.Zero(p.MaxInactiveDays)
+
+		require.NoError(repo.SetInactivityPolicy(ctx, org.PublicId, 30, true))
+		p, err = repo.LookupInactivityPolicy(ctx, org.PublicId)
+		require.NoError(err)
+		assert.Equal(uint32(30), p.MaxInactiveDays)
+		assert.True(p.DisableInactive)
+
+		require.NoError(repo.SetInactivityPolicy(ctx, org.PublicId, 0, false))
+		p, err = repo.LookupInactivityPolicy(ctx, org.PublicId)
+		require.NoError(err)
+		assert.Zero(p.MaxInactiveDays)
+		assert.Nil(p.CreateTime)
+	})
+	t.Run("disable-inactive", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		require.NoError(repo.SetInactivityPolicy(ctx, org.PublicId, 1, false))
+		userIds, err := repo.DisableInactiveUsers(ctx)
+		require.NoError(err)
+		assert.NotContains(userIds, inactive.PublicId)
+
+		require.NoError(repo.SetInactivityPolicy(ctx, org.PublicId, 1, true))
+		userIds, err = repo.DisableInactiveUsers(ctx)
+		require.NoError(err)
+		assert.Contains(userIds, inactive.PublicId)
+		assert.NotContains(userIds, active.PublicId)
+
+		disabled, err := repo.LookupUserDisabled(ctx, inactive.PublicId)
+		require.NoError(err)
+		assert.NotNil(disabled)
+		list, err := repo.ListDisabledUsers(ctx, org.PublicId)
+		require.NoError(err)
+		require.Len(list, 1)
+		assert.Equal(inactive.PublicId, list[0].IamUserId)
+		assert.Equal("inactive for 1 days", list[0].Reason)
+
+		lockouts := func() int {
+			rows, err := rw.Query(ctx, "select count(*) from security_event where kind = 'lockout' and scope_id = ?", []interface{}{org.PublicId})
+			require.NoError(err)
+			defer rows.Close()
+			var count int
+			for rows.Next() {
+				require.NoError(rows.Scan(&count))
+			}
+			return count
+		}
+		assert.Equal(1, lockouts())
+
+		userIds, err = repo.DisableInactiveUsers(ctx)
+		require.NoError(err)
+		assert.NotContains(userIds, inactive.PublicId)
+		assert.Equal(1, lockouts())
+
+		assert.NoError(db.TestVerifyOplog(t, rw, inactive.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
+
+		require.NoError(repo.EnableUser(ctx, inactive.PublicId))
+		disabled, err = repo.LookupUserDisabled(ctx, inactive.PublicId)
+		require.NoError(err)
+		assert.Nil(disabled)
+		assert.NoError(db.TestVerifyOplog(t, rw, inactive.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_DELETE), db.WithCreateNotBefore(10*time.Second)))
+
+		// Enabling restarts the inactivity, so the user is not disabled
+		// again, but it isn't a login
+		userIds, err = repo.DisableInactiveUsers(ctx)
+		require.NoError(err)
+		assert.NotContains(userIds, inactive.PublicId)
+		inactiveUsers, err := repo.ListInactiveUsers(ctx, org.PublicId, 1)
+		require.NoError(err)
+		for _, u := range inactiveUsers {
+			assert.NotEqual(inactive.PublicId, u.UserId)
+		}
+		logins, err := repo.LookupUserLogins(ctx, inactive.PublicId)
+		require.NoError(err)
+		require.NotNil(logins.LastLoginTime)
+		assert.True(logins.LastLoginTime.Before(time.Now().Add(-24 * time.Hour)))
+	})
+	t.Run("disable-enable", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		require.NoError(repo.DisableUser(ctx, active.PublicId, "compromised"))
+		assert.NoError(db.TestVerifyOplog(t, rw, active.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
+		// Disabling again keeps the reason
+		require.NoError(repo.DisableUser(ctx, active.PublicId, "other"))
+		disabled, err := repo.LookupUserDisabled(ctx, active.PublicId)
+		require.NoError(err)
+		require.NotNil(disabled)
+		assert.Equal("compromised", disabled.Reason)
+
+		require.NoError(repo.EnableUser(ctx, active.PublicId))
+		assert.NoError(db.TestVerifyOplog(t, rw, active.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_DELETE), db.WithCreateNotBefore(10*time.Second)))
+		// Enabling an enabled user does nothing
+		require.NoError(repo.EnableUser(ctx, active.PublicId))
+
+		assert.True(errors.Is(repo.DisableUser(ctx, "u_1234567890", "reason"), errors.ErrRecordNotFound))
+		assert.True(errors.Is(repo.EnableUser(ctx, "u_1234567890"), errors.ErrRecordNotFound))
+	})
+}
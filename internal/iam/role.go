@@ -93,6 +93,9 @@ func (*Role) Actions() map[string]action.Type {
 	ret[action.AddPrincipals.String()] = action.AddPrincipals
 	ret[action.RemovePrincipals.String()] = action.RemovePrincipals
 	ret[action.SetPrincipals.String()] = action.SetPrincipals
+	ret[action.RequestAccess.String()] = action.RequestAccess
+	ret[action.Approve.String()] = action.Approve
+	ret[action.Deny.String()] = action.Deny
 	return ret
 }
 
@@ -545,6 +545,9 @@ func TestRole_Actions(t *testing.T) {
 	assert.Equal(a[action.AddPrincipals.String()], action.AddPrincipals)
 	assert.Equal(a[action.RemovePrincipals.String()], action.RemovePrincipals)
 	assert.Equal(a[action.SetPrincipals.String()], action.SetPrincipals)
+	assert.Equal(a[action.RequestAccess.String()], action.RequestAccess)
+	assert.Equal(a[action.Approve.String()], action.Approve)
+	assert.Equal(a[action.Deny.String()], action.Deny)
 }
 
 func TestRole_ResourceType(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/types/scope"
 	"github.com/hashicorp/go-hclog"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
@@ -192,6 +193,18 @@ func (k *Kms) GetWrapper(ctx context.Context, scopeId string, purpose KeyPurpose
 	return wrapper, nil
 }
 
+// DatabaseFieldWrapper returns a db.FieldWrapperFunc which resolves the
+// database wrapper for a scope, for use with the db.WithFieldWrapper option.
+func (k *Kms) DatabaseFieldWrapper() db.FieldWrapperFunc {
+	return func(ctx context.Context, scopeId, keyId string) (wrapping.Wrapper, error) {
+		var opt []Option
+		if keyId != "" {
+			opt = append(opt, WithKeyId(keyId))
+		}
+		return k.GetWrapper(ctx, scopeId, KeyPurposeDatabase, opt...)
+	}
+}
+
 func (k *Kms) loadRoot(ctx context.Context, scopeId string, opt ...Option) (*multiwrapper.MultiWrapper, string, error) {
 	opts := getOpts(opt...)
 	repo := opts.withRepository
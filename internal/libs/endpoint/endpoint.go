@@ -0,0 +1,66 @@
+// Package endpoint parses and formats the network addresses of listeners,
+// controllers, workers and hosts, handling IPv6 literals consistently.
+package endpoint
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// SplitHostPort splits addr into its host and port like net.SplitHostPort,
+// but also accepts addresses without a port, for which defaultPort is
+// returned. IPv6 literals may be bare, e.g. ::1, or bracketed, e.g. [::1] or
+// [::1]:9200; the returned host is never bracketed.
+func SplitHostPort(addr, defaultPort string) (string, string, error) {
+	// A bare IPv6 literal would otherwise be split at its last colon
+	if isIPv6Literal(addr) {
+		return addr, defaultPort, nil
+	}
+	if strings.HasPrefix(addr, "[") && strings.HasSuffix(addr, "]") {
+		host := addr[1 : len(addr)-1]
+		if !isIPv6Literal(host) {
+			return "", "", fmt.Errorf("address %s: invalid IPv6 literal", addr)
+		}
+		return host, defaultPort, nil
+	}
+	host, port, err := net.SplitHostPort(addr)
+	switch {
+	case err != nil && strings.Contains(err.Error(), "missing port"):
+		return addr, defaultPort, nil
+	case err != nil:
+		return "", "", err
+	case port == "":
+		port = defaultPort
+	}
+	return host, port, nil
+}
+
+// UrlHost returns the host of a URL for host and port, bracketing IPv6
+// literals. A port of 0 is left out. host may already be bracketed.
+func UrlHost(host string, port uint32) string {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if port != 0 {
+		return net.JoinHostPort(host, strconv.FormatUint(uint64(port), 10))
+	}
+	if isIPv6Literal(host) {
+		return "[" + host + "]"
+	}
+	return host
+}
+
+// IsIPv6 returns true if host is an IPv6 literal, bracketed or not.
+func IsIPv6(host string) bool {
+	return isIPv6Literal(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+}
+
+// isIPv6Literal returns true if s is an IPv6 address, optionally with a zone
+// as in fe80::1%eth0.
+func isIPv6Literal(s string) bool {
+	if i := strings.LastIndexByte(s, '%'); i > 0 {
+		s = s[:i]
+	}
+	ip := net.ParseIP(s)
+	return ip != nil && ip.To4() == nil
+}
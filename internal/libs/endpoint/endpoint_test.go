@@ -0,0 +1,89 @@
+package endpoint
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitHostPort(t *testing.T) {
+	tests := []struct {
+		addr     string
+		wantHost string
+		wantPort string
+		wantErr  bool
+	}{
+		{addr: "127.0.0.1", wantHost: "127.0.0.1", wantPort: "9200"},
+		{addr: "127.0.0.1:9400", wantHost: "127.0.0.1", wantPort: "9400"},
+		{addr: "127.0.0.1:", wantHost: "127.0.0.1", wantPort: "9200"},
+		{addr: "example.com", wantHost: "example.com", wantPort: "9200"},
+		{addr: "example.com:9400", wantHost: "example.com", wantPort: "9400"},
+		{addr: "::1", wantHost: "::1", wantPort: "9200"},
+		{addr: "::", wantHost: "::", wantPort: "9200"},
+		{addr: "2001:db8::1", wantHost: "2001:db8::1", wantPort: "9200"},
+		{addr: "fe80::1%eth0", wantHost: "fe80::1%eth0", wantPort: "9200"},
+		{addr: "[::1]", wantHost: "::1", wantPort: "9200"},
+		{addr: "[::1]:9400", wantHost: "::1", wantPort: "9400"},
+		{addr: "[2001:db8::1]:9400", wantHost: "2001:db8::1", wantPort: "9400"},
+		{addr: "[example.com]", wantErr: true},
+		{addr: "[::1", wantErr: true},
+		{addr: "1:2:3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			host, port, err := SplitHostPort(tt.addr, "9200")
+			if tt.wantErr {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tt.wantHost, host)
+			assert.Equal(tt.wantPort, port)
+		})
+	}
+}
+
+func TestUrlHost(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		port uint32
+		want string
+	}{
+		{name: "ipv4", host: "10.0.0.1", port: 22, want: "10.0.0.1:22"},
+		{name: "ipv4-no-port", host: "10.0.0.1", want: "10.0.0.1"},
+		{name: "name", host: "example.com", port: 22, want: "example.com:22"},
+		{name: "ipv6", host: "2001:db8::1", port: 22, want: "[2001:db8::1]:22"},
+		{name: "ipv6-no-port", host: "2001:db8::1", want: "[2001:db8::1]"},
+		{name: "bracketed-ipv6", host: "[2001:db8::1]", port: 22, want: "[2001:db8::1]:22"},
+		{name: "bracketed-ipv6-no-port", host: "[::1]", want: "[::1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got := UrlHost(tt.host, tt.port)
+			assert.Equal(tt.want, got)
+
+			// The endpoint URL of a session must round trip so workers
+			// dial the host it was created for
+			u, err := url.Parse((&url.URL{Scheme: "tcp", Host: got}).String())
+			require.NoError(err)
+			assert.Equal(got, u.Host)
+			assert.True(strings.Contains(tt.host, u.Hostname()))
+		})
+	}
+}
+
+func TestIsIPv6(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(IsIPv6("::1"))
+	assert.True(IsIPv6("[::1]"))
+	assert.True(IsIPv6("fe80::1%eth0"))
+	assert.False(IsIPv6("127.0.0.1"))
+	assert.False(IsIPv6("::ffff:127.0.0.1"))
+	assert.False(IsIPv6("example.com"))
+}
@@ -0,0 +1,7 @@
+// +build !fips,!fips_openssl
+
+package fips
+
+// buildMode is the FIPS mode the binary was built with; it is empty if the
+// binary was built without a FIPS module.
+const buildMode Mode = ""
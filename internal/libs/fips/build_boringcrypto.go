@@ -0,0 +1,12 @@
+// +build fips
+
+package fips
+
+// Importing fipsonly restricts all TLS configurations to approved settings,
+// and fails the build unless the toolchain links BoringCrypto, so a binary
+// built with the fips tag never reports a module it doesn't use.
+import _ "crypto/tls/fipsonly"
+
+// buildMode is the FIPS mode the binary was built with. Building with the
+// fips tag requires a BoringCrypto Go toolchain.
+const buildMode = ModeBoringCrypto
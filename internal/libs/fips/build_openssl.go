@@ -0,0 +1,7 @@
+// +build fips_openssl,!fips
+
+package fips
+
+// buildMode is the FIPS mode the binary was built with. Building with the
+// fips_openssl tag requires a Go toolchain using the system OpenSSL.
+const buildMode = ModeOpenSSL
@@ -0,0 +1,188 @@
+// Package fips implements the FIPS mode of Boundary, which restricts the
+// cryptography of controllers and workers to FIPS-approved algorithms.
+//
+// Binaries built with the fips build tag use the BoringCrypto module of a
+// BoringCrypto Go toolchain, and those built with the fips_openssl build tag
+// the system OpenSSL of a Go toolchain linking it; both always run in FIPS
+// mode. Other binaries can be restricted to approved algorithms at runtime
+// with the fips_mode configuration setting, without a validated module.
+//
+// FIPS mode restricts the TLS of API listeners and the KMS configurations
+// allowed. The mutual TLS between clients, workers and controllers uses
+// Ed25519 certificates generated by Boundary and is not restricted.
+package fips
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/shared-secure-libs/configutil"
+)
+
+// Mode is the FIPS mode of a controller or worker.
+type Mode string
+
+const (
+	// ModeDisabled does not restrict cryptography.
+	ModeDisabled Mode = "disabled"
+	// ModeRestricted restricts cryptography to approved algorithms using the
+	// Go standard library, which is not a validated module.
+	ModeRestricted Mode = "restricted"
+	// ModeBoringCrypto restricts cryptography to approved algorithms using
+	// the BoringCrypto module.
+	ModeBoringCrypto Mode = "boringcrypto"
+	// ModeOpenSSL restricts cryptography to approved algorithms using the
+	// system OpenSSL module.
+	ModeOpenSSL Mode = "openssl"
+)
+
+// Resolve returns the FIPS mode of the binary, or ModeRestricted if the binary
+// was built without a FIPS module but configured is true.
+func Resolve(configured bool) Mode {
+	switch {
+	case buildMode != "":
+		return buildMode
+	case configured:
+		return ModeRestricted
+	default:
+		return ModeDisabled
+	}
+}
+
+// Enabled returns true if m restricts cryptography.
+func (m Mode) Enabled() bool {
+	return m != "" && m != ModeDisabled
+}
+
+// String returns the name of m as shown in the health endpoint and server
+// startup output.
+func (m Mode) String() string {
+	if m == "" {
+		return string(ModeDisabled)
+	}
+	return string(m)
+}
+
+// CipherSuites are the approved TLS 1.2 cipher suites.
+var CipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// curves are the approved TLS key exchange curves.
+var curves = []tls.CurveID{tls.CurveP256, tls.CurveP384, tls.CurveP521}
+
+// kmsTypes are the KMS types allowed in FIPS mode. Cloud KMSes, Vault Transit
+// and PKCS#11 tokens keep their keys in validated modules; AEAD keys are
+// checked to be AES-GCM keys.
+var kmsTypes = map[string]bool{
+	"aead":          true,
+	"awskms":        true,
+	"azurekeyvault": true,
+	"gcpckms":       true,
+	"ocikms":        true,
+	"pkcs11":        true,
+	"transit":       true,
+}
+
+// ValidateKms returns an error if any of kmses is not allowed in FIPS mode.
+func ValidateKms(kmses []*configutil.KMS) error {
+	for _, k := range kmses {
+		if k == nil {
+			continue
+		}
+		typ := strings.ToLower(k.Type)
+		if !kmsTypes[typ] {
+			return fmt.Errorf("kms type %q is not allowed in FIPS mode", k.Type)
+		}
+		if typ != "aead" {
+			continue
+		}
+		if t := k.Config["aead_type"]; t != "" && !strings.EqualFold(t, "aes-gcm") {
+			return fmt.Errorf("aead kms type %q is not allowed in FIPS mode; use aes-gcm", t)
+		}
+		// The key may be given in the configuration or through the environment
+		if key := k.Config["key"]; key != "" {
+			raw, err := base64.StdEncoding.DecodeString(key)
+			if err != nil {
+				return fmt.Errorf("error decoding aead kms key: %w", err)
+			}
+			switch len(raw) {
+			case 16, 24, 32:
+			default:
+				return fmt.Errorf("aead kms key of %d bytes is not allowed in FIPS mode", len(raw))
+			}
+		}
+	}
+	return nil
+}
+
+// RestrictTLS restricts conf to TLS 1.2 with approved cipher suites and
+// curves, and returns an error if its certificate has a key not allowed in
+// FIPS mode. TLS 1.3 is disabled since its cipher suites are not
+// configurable in Go.
+func RestrictTLS(conf *tls.Config) error {
+	if conf == nil {
+		return errors.New("missing tls config")
+	}
+	conf.MinVersion = tls.VersionTLS12
+	conf.MaxVersion = tls.VersionTLS12
+	conf.CipherSuites = CipherSuites
+	conf.CurvePreferences = curves
+
+	certs := conf.Certificates
+	if len(certs) == 0 && conf.GetCertificate != nil {
+		cert, err := conf.GetCertificate(&tls.ClientHelloInfo{})
+		if err != nil {
+			return fmt.Errorf("error getting tls certificate: %w", err)
+		}
+		if cert != nil {
+			certs = []tls.Certificate{*cert}
+		}
+	}
+	for _, cert := range certs {
+		if err := validateCertificate(cert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCertificate returns an error if the key of cert is not an RSA key
+// of at least 2048 bits or an ECDSA key on an approved curve.
+func validateCertificate(cert tls.Certificate) error {
+	if len(cert.Certificate) == 0 {
+		return nil
+	}
+	leaf := cert.Leaf
+	if leaf == nil {
+		var err error
+		if leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return fmt.Errorf("error parsing tls certificate: %w", err)
+		}
+	}
+	switch key := leaf.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < 2048 {
+			return fmt.Errorf("tls certificate rsa key of %d bits is not allowed in FIPS mode", key.N.BitLen())
+		}
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+		default:
+			return fmt.Errorf("tls certificate ecdsa curve %s is not allowed in FIPS mode", key.Curve.Params().Name)
+		}
+	default:
+		return fmt.Errorf("tls certificate key type %T is not allowed in FIPS mode", leaf.PublicKey)
+	}
+	return nil
+}
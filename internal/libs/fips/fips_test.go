@@ -0,0 +1,148 @@
+package fips
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/shared-secure-libs/configutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	if buildMode != "" {
+		t.Skip("binary built with a FIPS module")
+	}
+	assert := assert.New(t)
+	assert.Equal(ModeDisabled, Resolve(false))
+	assert.False(Resolve(false).Enabled())
+	assert.Equal(ModeRestricted, Resolve(true))
+	assert.True(Resolve(true).Enabled())
+	assert.Equal("disabled", Mode("").String())
+}
+
+func TestValidateKms(t *testing.T) {
+	key := func(n int) string {
+		return base64.StdEncoding.EncodeToString(make([]byte, n))
+	}
+	tests := []struct {
+		name    string
+		kms     *configutil.KMS
+		wantErr bool
+	}{
+		{
+			name: "aes-gcm",
+			kms:  &configutil.KMS{Type: "aead", Config: map[string]string{"aead_type": "aes-gcm", "key": key(32)}},
+		},
+		{
+			name: "aead-key-from-env",
+			kms:  &configutil.KMS{Type: "aead", Config: map[string]string{"aead_type": "aes-gcm"}},
+		},
+		{
+			name:    "aead-bad-type",
+			kms:     &configutil.KMS{Type: "aead", Config: map[string]string{"aead_type": "xchacha20-poly1305"}},
+			wantErr: true,
+		},
+		{
+			name:    "aead-bad-key-size",
+			kms:     &configutil.KMS{Type: "aead", Config: map[string]string{"aead_type": "aes-gcm", "key": key(20)}},
+			wantErr: true,
+		},
+		{
+			name:    "aead-bad-key",
+			kms:     &configutil.KMS{Type: "aead", Config: map[string]string{"key": "not base64!"}},
+			wantErr: true,
+		},
+		{
+			name: "awskms",
+			kms:  &configutil.KMS{Type: "awskms", Config: map[string]string{}},
+		},
+		{
+			name:    "alicloudkms",
+			kms:     &configutil.KMS{Type: "alicloudkms", Config: map[string]string{}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateKms([]*configutil.KMS{tt.kms})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRestrictTLS(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	smallEcdsaKey, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	smallRsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		key     crypto.Signer
+		wantErr bool
+	}{
+		{name: "ecdsa-p256", key: ecdsaKey},
+		{name: "rsa-2048", key: rsaKey},
+		{name: "ecdsa-p224", key: smallEcdsaKey, wantErr: true},
+		{name: "rsa-1024", key: smallRsaKey, wantErr: true},
+		{name: "ed25519", key: ed25519Key, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			cert := testCertificate(t, tt.key)
+			conf := &tls.Config{
+				GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+					return &cert, nil
+				},
+			}
+			err := RestrictTLS(conf)
+			if tt.wantErr {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(uint16(tls.VersionTLS12), conf.MinVersion)
+			assert.Equal(uint16(tls.VersionTLS12), conf.MaxVersion)
+			assert.Equal(CipherSuites, conf.CipherSuites)
+		})
+	}
+	t.Run("nil", func(t *testing.T) {
+		assert.Error(t, RestrictTLS(nil))
+	})
+}
+
+func testCertificate(t *testing.T, key crypto.Signer) tls.Certificate {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
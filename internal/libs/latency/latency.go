@@ -0,0 +1,125 @@
+// Package latency breaks the time taken by an operation, such as authorizing a
+// session or establishing a connection, into named phases. Each phase is
+// recorded as a metric when it ends and kept with the operation, so the
+// breakdown can be attached to its trace and sent to clients.
+package latency
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+)
+
+// Phase is a timed phase of an operation.
+type Phase struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Budget times the phases of an operation. A nil Budget is valid and records
+// nothing, so callers need not check whether one was set up.
+type Budget struct {
+	key   []string
+	start time.Time
+
+	mu     sync.Mutex
+	phases []Phase
+}
+
+// New returns a Budget for the operation with the metric key, started now.
+// Phases are recorded under the key followed by the phase name.
+func New(key ...string) *Budget {
+	return &Budget{
+		key:   key,
+		start: time.Now(),
+	}
+}
+
+// Start starts timing the phase and returns the function ending it.
+func (b *Budget) Start(phase string) func() {
+	if b == nil {
+		return func() {}
+	}
+	start := time.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.record(phase, start)
+		})
+	}
+}
+
+func (b *Budget) record(phase string, start time.Time) {
+	metrics.MeasureSince(b.metricKey(phase), start)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.phases = append(b.phases, Phase{Name: phase, Duration: time.Since(start)})
+}
+
+// Finish records the total time since the Budget was created.
+func (b *Budget) Finish() {
+	if b == nil {
+		return
+	}
+	metrics.MeasureSince(b.metricKey("total"), b.start)
+}
+
+func (b *Budget) metricKey(name string) []string {
+	key := make([]string, 0, len(b.key)+1)
+	key = append(key, b.key...)
+	return append(key, name)
+}
+
+// Phases returns the phases ended so far, in the order they ended.
+func (b *Budget) Phases() []Phase {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ret := make([]Phase, len(b.phases))
+	copy(ret, b.phases)
+	return ret
+}
+
+// Attributes returns the phases as key/value pairs, the durations in
+// milliseconds, for logging them as attributes of the operation's span.
+func (b *Budget) Attributes() []interface{} {
+	phases := b.Phases()
+	ret := make([]interface{}, 0, len(phases)*2)
+	for _, p := range phases {
+		ret = append(ret, p.Name+"_ms", milliseconds(p.Duration))
+	}
+	return ret
+}
+
+// ServerTiming returns the phases as the value of a Server-Timing header.
+func (b *Budget) ServerTiming() string {
+	phases := b.Phases()
+	timings := make([]string, 0, len(phases))
+	for _, p := range phases {
+		timings = append(timings, fmt.Sprintf("%s;dur=%.3f", p.Name, milliseconds(p.Duration)))
+	}
+	return strings.Join(timings, ", ")
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+type budgetKey struct{}
+
+// NewContext returns a context carrying the Budget.
+func NewContext(ctx context.Context, b *Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// FromContext returns the Budget carried by the context, or nil.
+func FromContext(ctx context.Context) *Budget {
+	b, _ := ctx.Value(budgetKey{}).(*Budget)
+	return b
+}
@@ -0,0 +1,57 @@
+package latency
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudget(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	b := New("test", "operation")
+
+	endFirst := b.Start("first")
+	endSecond := b.Start("second")
+	time.Sleep(10 * time.Millisecond)
+	endSecond()
+	endFirst()
+	// Ending a phase again does not record it twice
+	endFirst()
+
+	phases := b.Phases()
+	require.Len(phases, 2)
+	assert.Equal("second", phases[0].Name)
+	assert.Equal("first", phases[1].Name)
+	assert.GreaterOrEqual(int64(phases[1].Duration), int64(phases[0].Duration))
+	assert.GreaterOrEqual(int64(phases[0].Duration), int64(10*time.Millisecond))
+
+	attrs := b.Attributes()
+	require.Len(attrs, 4)
+	assert.Equal("second_ms", attrs[0])
+	assert.Equal("first_ms", attrs[2])
+
+	assert.Regexp(regexp.MustCompile(`^second;dur=\d+\.\d{3}, first;dur=\d+\.\d{3}$`), b.ServerTiming())
+	b.Finish()
+}
+
+func TestBudget_Nil(t *testing.T) {
+	assert := assert.New(t)
+	var b *Budget
+	end := b.Start("phase")
+	end()
+	b.Finish()
+	assert.Empty(b.Phases())
+	assert.Empty(b.Attributes())
+	assert.Empty(b.ServerTiming())
+}
+
+func TestContext(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(FromContext(context.Background()))
+	b := New("test")
+	assert.Same(b, FromContext(NewContext(context.Background(), b)))
+}
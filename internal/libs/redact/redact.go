@@ -0,0 +1,169 @@
+// Package redact scrubs the values of sensitive fields from error messages,
+// so they don't leave the repository layer in API responses or logs.
+//
+// Sensitive fields are the fields encrypted with "wrapping" struct tags,
+// both plaintext and ciphertext, and string or bytes fields named like a
+// password, secret, token, salt or key.
+package redact
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Value replaces the values of sensitive fields.
+const Value = "[REDACTED]"
+
+// minValueLen is the length below which values aren't redacted, since
+// replacing them would mangle messages more than it protects: secrets are
+// longer.
+const minValueLen = 8
+
+// sensitiveNames are the parts of the names of fields holding secrets, which
+// are matched lower cased and without underscores.
+var sensitiveNames = []string{"password", "secret", "privatekey", "derivedkey", "salt"}
+
+// IsSensitiveName returns true if a field or column with the name holds
+// secrets. It matches both Go and database names, e.g. TofuToken and
+// tofu_token, but not the ids of resources named like secrets such as
+// auth_token_id or password_account_id.
+func IsSensitiveName(name string) bool {
+	name = strings.ToLower(strings.ReplaceAll(name, "_", ""))
+	if strings.HasSuffix(name, "id") {
+		return false
+	}
+	for _, s := range sensitiveNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return strings.HasSuffix(name, "token") || name == "key" || name == "ctkey"
+}
+
+// Values returns the values of the sensitive fields of the resources, which
+// must be structs or pointers to them. The fields of embedded structs, such as
+// the store messages of domain types, are included. Bytes are included in
+// the encodings they are likely to appear in within messages. Values shorter
+// than 8 characters are not included.
+func Values(resources ...interface{}) []string {
+	var values []string
+	for _, r := range resources {
+		values = appendValues(values, reflect.ValueOf(r))
+	}
+	return values
+}
+
+func appendValues(values []string, v reflect.Value) []string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return values
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return values
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+		if f.Anonymous {
+			values = appendValues(values, fv)
+			continue
+		}
+		if !sensitiveField(f) {
+			continue
+		}
+		switch {
+		case fv.Kind() == reflect.String:
+			values = appendLong(values, fv.String())
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Uint8:
+			b := fv.Bytes()
+			values = appendLong(values, string(b), base64.StdEncoding.EncodeToString(b), base64.RawURLEncoding.EncodeToString(b), hex.EncodeToString(b))
+		}
+	}
+	return values
+}
+
+func appendLong(values []string, vs ...string) []string {
+	for _, v := range vs {
+		if len(v) >= minValueLen {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func sensitiveField(f reflect.StructField) bool {
+	tag := f.Tag.Get("wrapping")
+	if strings.HasPrefix(tag, "pt,") || strings.HasPrefix(tag, "ct,") {
+		return true
+	}
+	return IsSensitiveName(f.Name)
+}
+
+// String returns s with the values of the sensitive fields of the resources
+// replaced by Value.
+func String(s string, resources ...interface{}) string {
+	values := Values(resources...)
+	// Longer values first, so values containing others are fully replaced
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, Value)
+	}
+	return s
+}
+
+// Error returns err with the values of the sensitive fields of the resources
+// redacted from its message, or err itself if its message has none of them.
+// The returned error wraps err, so it can still be matched with errors.Is and
+// errors.As.
+func Error(err error, resources ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	redacted := String(msg, resources...)
+	if redacted == msg {
+		return err
+	}
+	return &redactedError{msg: redacted, err: err}
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// KeyDetail redacts the values of sensitive columns from the detail of a
+// postgres key violation, e.g. "Key (token)=(...) already exists.".
+func KeyDetail(detail string) string {
+	const prefix = "Key ("
+	if !strings.HasPrefix(detail, prefix) {
+		return detail
+	}
+	end := strings.Index(detail, ")=(")
+	if end < 0 {
+		return detail
+	}
+	for _, col := range strings.Split(detail[len(prefix):end], ",") {
+		if IsSensitiveName(strings.TrimSpace(col)) {
+			valuesEnd := strings.LastIndex(detail, ")")
+			if valuesEnd <= end+len(")=(") {
+				return detail
+			}
+			return detail[:end+len(")=(")] + Value + detail[valuesEnd:]
+		}
+	}
+	return detail
+}
@@ -0,0 +1,195 @@
+package redact_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	pwstore "github.com/hashicorp/boundary/internal/auth/password/store"
+	atstore "github.com/hashicorp/boundary/internal/authtoken/store"
+	kmsstore "github.com/hashicorp/boundary/internal/kms/store"
+	"github.com/hashicorp/boundary/internal/libs/redact"
+	oplogstore "github.com/hashicorp/boundary/internal/oplog/store"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSensitiveName(t *testing.T) {
+	for _, name := range []string{"Password", "password", "ClientSecret", "Token", "CtToken", "tofu_token", "TofuToken", "Salt", "CtSalt", "DerivedKey", "Key", "CtKey", "private_key"} {
+		assert.True(t, redact.IsSensitiveName(name), name)
+	}
+	for _, name := range []string{"PublicId", "AuthTokenId", "auth_token_id", "KeyId", "RootKeyId", "PasswordAccountId", "password_method_id", "Name", "Certificate", "TokenKey"} {
+		assert.False(t, redact.IsSensitiveName(name), name)
+	}
+}
+
+func TestString(t *testing.T) {
+	assert := assert.New(t)
+	r := &struct {
+		PublicId string
+		Password string
+		Short    []byte `wrapping:"pt,short"`
+		Key      []byte `wrapping:"pt,key"`
+		CtKey    []byte `wrapping:"ct,key"`
+	}{
+		PublicId: "u_1234567890",
+		Password: "hunter2hunter2",
+		Short:    []byte("abc"),
+		Key:      []byte("0123456789abcdef"),
+		CtKey:    []byte{0xde, 0xad, 0xbe, 0xef, 0xca, 0xfe, 0xba, 0xbe},
+	}
+	msg := fmt.Sprintf("failed for %s with %s and %s, %x, abc", r.PublicId, r.Password, r.Key, r.CtKey)
+	got := redact.String(msg, r)
+	assert.Equal("failed for u_1234567890 with [REDACTED] and [REDACTED], [REDACTED], abc", got)
+	assert.Equal(msg, redact.String(msg, nil, (*struct{ Token string })(nil), "not a struct"))
+}
+
+func TestError(t *testing.T) {
+	assert := assert.New(t)
+	sentinel := stderrors.New("invalid input syntax")
+	r := &struct{ Token string }{Token: "at_s3cr3tt0ken"}
+
+	err := redact.Error(fmt.Errorf("%w: %q", sentinel, r.Token), r)
+	assert.Equal(`invalid input syntax: "[REDACTED]"`, err.Error())
+	assert.True(stderrors.Is(err, sentinel))
+
+	err = fmt.Errorf("%w: for id", sentinel)
+	assert.Equal(err, redact.Error(err, r))
+	assert.Nil(redact.Error(nil, r))
+}
+
+func TestKeyDetail(t *testing.T) {
+	tests := map[string]string{
+		"Key (name)=(alice) already exists.":                        "Key (name)=(alice) already exists.",
+		"Key (token)=(\\x0102) already exists.":                     "Key (token)=([REDACTED]) already exists.",
+		"Key (scope_id, password)=(o_1, (hunter2)) already exists.": "Key (scope_id, password)=([REDACTED]) already exists.",
+		"not a key detail":                                          "not a key detail",
+		"Key (token)=":                                              "Key (token)=",
+	}
+	for detail, want := range tests {
+		assert.Equal(t, want, redact.KeyDetail(detail), detail)
+	}
+}
+
+// storeTypes are the types with fields encrypted using "wrapping" tags.
+// TestStoreFields fails if a type with such fields is missing.
+var storeTypes = map[string]interface{}{
+	"internal/auth/password/store.Argon2Credential": &pwstore.Argon2Credential{},
+	"internal/authtoken/store.AuthToken":            &atstore.AuthToken{},
+	"internal/kms/store.DatabaseKeyVersion":         &kmsstore.DatabaseKeyVersion{},
+	"internal/kms/store.OplogKeyVersion":            &kmsstore.OplogKeyVersion{},
+	"internal/kms/store.RootKeyVersion":             &kmsstore.RootKeyVersion{},
+	"internal/kms/store.SessionKeyVersion":          &kmsstore.SessionKeyVersion{},
+	"internal/kms/store.TokenKeyVersion":            &kmsstore.TokenKeyVersion{},
+	"internal/oplog/store.Entry":                    &oplogstore.Entry{},
+	"internal/session.Session":                      &session.Session{},
+}
+
+// testedInPackage are the unexported types with encrypted fields, whose
+// redaction is tested in their own package.
+var testedInPackage = map[string]bool{
+	"internal/session.sessionView":        true,
+	"internal/servers.workerState":        true,
+	"internal/auth/password.recoveryTotp": true,
+	"internal/eventsink.wrappedSecret":    true,
+}
+
+// TestStoreFields asserts that the values of every sensitive field of the
+// types with encrypted fields are redacted.
+func TestStoreFields(t *testing.T) {
+	for name, r := range storeTypes {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			v := reflect.ValueOf(r).Elem()
+			var set []string
+			for i := 0; i < v.NumField(); i++ {
+				f := v.Type().Field(i)
+				tag := f.Tag.Get("wrapping")
+				if tag == "" && !redact.IsSensitiveName(f.Name) {
+					continue
+				}
+				value := fmt.Sprintf("secret-%s-%s", name, f.Name)
+				switch f.Type.Kind() {
+				case reflect.String:
+					v.Field(i).SetString(value)
+				case reflect.Slice:
+					v.Field(i).SetBytes([]byte(value))
+				default:
+					continue
+				}
+				set = append(set, value)
+			}
+			assert.NotEmpty(set)
+			msg := "failed: " + strings.Join(set, ", ")
+			got := redact.String(msg, r)
+			for _, value := range set {
+				assert.NotContains(got, value)
+			}
+			assert.Equal("failed: "+strings.TrimSuffix(strings.Repeat(redact.Value+", ", len(set)), ", "), got)
+		})
+	}
+
+	t.Run("all-types-covered", func(t *testing.T) {
+		for _, name := range typesWithWrappedFields(t, filepath.Join("..", "..")) {
+			_, ok := storeTypes[name]
+			assert.True(t, ok || testedInPackage[name], "redaction of the encrypted fields of %s is not tested", name)
+		}
+	})
+}
+
+// typesWithWrappedFields returns the structs declared under the internal
+// directory with "wrapping" tagged fields, named by their package directory
+// relative to the repository and type name.
+func typesWithWrappedFields(t *testing.T, internalDir string) []string {
+	t.Helper()
+	// filepath.Rel can't relate relative paths going up a different number
+	// of directories
+	internalDir, err := filepath.Abs(internalDir)
+	require.NoError(t, err)
+	var names []string
+	err = filepath.Walk(internalDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(filepath.Join(internalDir, ".."), filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			for _, field := range st.Fields.List {
+				if field.Tag != nil && strings.Contains(field.Tag.Value, `wrapping:"`) {
+					names = append(names, filepath.ToSlash(rel)+"."+ts.Name.Name)
+					break
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+	return names
+}
@@ -0,0 +1,221 @@
+package oplog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// DefaultHistoryLimit is the maximum number of entries returned by
+	// ReadHistory when no limit is provided.
+	DefaultHistoryLimit = 100
+
+	// RedactedValue replaces the values of sensitive fields in a Change.
+	RedactedValue = "[REDACTED]"
+)
+
+// HistoryEntry is an oplog entry of a resource rendered for display.
+type HistoryEntry struct {
+	Id         uint32    `json:"id"`
+	CreateTime time.Time `json:"create_time"`
+	// ActorId is the id of the user who made the change, if it was recorded.
+	ActorId string    `json:"actor_id,omitempty"`
+	Changes []*Change `json:"changes"`
+}
+
+// Change is a message of an oplog entry rendered for display.
+type Change struct {
+	// Type is the type name of the message in the type catalog.
+	Type string `json:"type"`
+	// Operation is one of create, update or delete.
+	Operation string `json:"operation"`
+	// Fields are the values of the fields set by the change, keyed by their
+	// proto names. An update only includes the fields it changed. The values
+	// of sensitive fields are RedactedValue.
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// NullFields are the fields set to null by an update.
+	NullFields []string `json:"null_fields,omitempty"`
+}
+
+// HistoryPage is a page of the oplog entries matching a SearchQuery rendered
+// for display, newest first.
+type HistoryPage struct {
+	Items []*HistoryEntry
+
+	// NextBeforeId is the BeforeId of the query of the next page, or zero if
+	// this is the last page.
+	NextBeforeId uint32
+}
+
+// ReadHistory returns up to limit of the most recent oplog entries of the
+// resource, newest first, decrypted with the wrappers returned by wrapperFn for
+// their scopes and rendered using types, which must hold all types of the
+// messages in the entries. A limit <= 0 uses DefaultHistoryLimit. Entries
+// still staged in asynchronous oplog mode are not returned.
+func ReadHistory(ctx context.Context, db *gorm.DB, resourceId string, wrapperFn WrapperFunc, types *TypeCatalog, limit int) ([]*HistoryEntry, error) {
+	if resourceId == "" {
+		return nil, errors.New("read history: missing resource id")
+	}
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	page, err := SearchHistory(ctx, db, &SearchQuery{
+		Metadata: Metadata{ResourcePublicIdMetadataKey: []string{resourceId}},
+		Limit:    limit,
+	}, wrapperFn, types)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// SearchHistory returns the page of the oplog entries matching the query,
+// decrypted with the wrappers returned by wrapperFn for their scopes and
+// rendered using types, which must hold all types of the messages in the
+// entries.
+func SearchHistory(ctx context.Context, db *gorm.DB, q *SearchQuery, wrapperFn WrapperFunc, types *TypeCatalog) (*HistoryPage, error) {
+	if wrapperFn == nil {
+		return nil, errors.New("read history: missing wrapper func")
+	}
+	if types == nil {
+		return nil, errors.New("read history: missing type catalog")
+	}
+	res, err := SearchEntries(ctx, db, q)
+	if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+
+	wrappers := map[string]wrapping.Wrapper{}
+	ret := &HistoryPage{
+		Items:        make([]*HistoryEntry, 0, len(res.Entries)),
+		NextBeforeId: res.NextBeforeId,
+	}
+	for _, se := range res.Entries {
+		var scopeId, actorId string
+		if v := res.Metadata[se.Id][ScopeIdMetadataKey]; len(v) > 0 {
+			scopeId = v[0]
+		}
+		if v := res.Metadata[se.Id][ActorIdMetadataKey]; len(v) > 0 {
+			actorId = v[0]
+		}
+		if scopeId == "" {
+			return nil, fmt.Errorf("read history: entry %d has no scope", se.Id)
+		}
+		w, ok := wrappers[scopeId]
+		if !ok {
+			var err error
+			if w, err = wrapperFn(ctx, scopeId); err != nil {
+				return nil, fmt.Errorf("read history: unable to get wrapper for entry %d: %w", se.Id, err)
+			}
+			wrappers[scopeId] = w
+		}
+		e := &Entry{Entry: se, Cipherer: w}
+		if err := e.DecryptData(ctx); err != nil {
+			return nil, fmt.Errorf("read history: entry %d: %w", se.Id, err)
+		}
+		msgs, err := e.UnmarshalData(types)
+		if err != nil {
+			return nil, fmt.Errorf("read history: entry %d: %w", se.Id, err)
+		}
+		h := &HistoryEntry{
+			Id:         se.Id,
+			CreateTime: se.GetCreateTime().GetTimestamp().AsTime(),
+			ActorId:    actorId,
+			Changes:    make([]*Change, 0, len(msgs)),
+		}
+		for _, m := range msgs {
+			c, err := renderChange(m)
+			if err != nil {
+				return nil, fmt.Errorf("read history: entry %d: %w", se.Id, err)
+			}
+			h.Changes = append(h.Changes, c)
+		}
+		ret.Items = append(ret.Items, h)
+	}
+	return ret, nil
+}
+
+// renderChange renders the message for display, redacting sensitive values.
+func renderChange(m Message) (*Change, error) {
+	c := &Change{
+		Type:      m.TypeName,
+		Operation: strings.ToLower(strings.TrimPrefix(m.OpType.String(), "OP_TYPE_")),
+		Fields:    map[string]interface{}{},
+	}
+	js, err := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(m.Message)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal %s: %w", m.TypeName, err)
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(js, &values); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal %s: %w", m.TypeName, err)
+	}
+
+	fields := m.Message.ProtoReflect().Descriptor().Fields()
+	set := func(fd protoreflect.FieldDescriptor) {
+		name := string(fd.Name())
+		switch {
+		case sensitiveField(fd):
+			c.Fields[name] = RedactedValue
+		default:
+			c.Fields[name] = values[name]
+		}
+	}
+	switch m.OpType {
+	case OpType_OP_TYPE_UPDATE:
+		for _, p := range m.FieldMaskPaths {
+			if fd := fieldByPath(fields, p); fd != nil {
+				set(fd)
+			}
+		}
+		for _, p := range m.SetToNullPaths {
+			if fd := fieldByPath(fields, p); fd != nil {
+				c.NullFields = append(c.NullFields, string(fd.Name()))
+			}
+		}
+	default:
+		m.Message.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+			set(fd)
+			return true
+		})
+	}
+	return c, nil
+}
+
+// fieldByPath returns the field of a field mask path, which names fields by
+// their Go names, e.g. DefaultPort for default_port.
+func fieldByPath(fields protoreflect.FieldDescriptors, path string) protoreflect.FieldDescriptor {
+	want := strings.ToLower(strings.ReplaceAll(path, "_", ""))
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if strings.ToLower(strings.ReplaceAll(string(fd.Name()), "_", "")) == want {
+			return fd
+		}
+	}
+	return nil
+}
+
+// sensitiveField returns true if the value of the field must not be shown:
+// bytes, which hold keys, certificates and ciphertexts, and anything named like
+// a credential.
+func sensitiveField(fd protoreflect.FieldDescriptor) bool {
+	if fd.Kind() == protoreflect.BytesKind {
+		return true
+	}
+	name := string(fd.Name())
+	for _, s := range []string{"password", "secret", "token", "credential"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package oplog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/oplog/oplog_test"
+	"github.com/hashicorp/boundary/internal/oplog/store"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadHistory(t *testing.T) {
+	cleanup, db := setup(t)
+	defer testCleanup(t, cleanup, db)
+	ctx := context.Background()
+	cipherer := testWrapper(t)
+	wrapperFn := func(context.Context, string) (wrapping.Wrapper, error) {
+		return cipherer, nil
+	}
+	types, err := NewTypeCatalog(Type{Interface: new(oplog_test.TestUser), Name: "user"})
+	require.NoError(t, err)
+
+	ticketer, err := NewGormTicketer(db, WithAggregateNames(true))
+	require.NoError(t, err)
+
+	resourceId := "u_" + testId(t)
+	write := func(t *testing.T, actor string, msg *Message) {
+		t.Helper()
+		ticket, err := ticketer.GetTicket("default")
+		require.NoError(t, err)
+		md := Metadata{
+			"resource-public-id": []string{resourceId},
+			"scope-id":           []string{"global"},
+		}
+		if actor != "" {
+			md[ActorIdMetadataKey] = []string{actor}
+		}
+		entry, err := NewEntry("test-users", md, cipherer, ticketer)
+		require.NoError(t, err)
+		require.NoError(t, entry.WriteEntryWith(ctx, &GormWriter{db}, ticket, msg))
+	}
+	write(t, "u_alice", &Message{
+		Message:  &oplog_test.TestUser{Name: "alice", PhoneNumber: "555-1234"},
+		TypeName: "user",
+		OpType:   OpType_OP_TYPE_CREATE,
+	})
+	write(t, "", &Message{
+		Message:        &oplog_test.TestUser{Name: "bob", Email: "ignored@example.com"},
+		TypeName:       "user",
+		OpType:         OpType_OP_TYPE_UPDATE,
+		FieldMaskPaths: []string{"Name"},
+		SetToNullPaths: []string{"PhoneNumber"},
+	})
+
+	t.Run("all", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := ReadHistory(ctx, db, resourceId, wrapperFn, types, 0)
+		require.NoError(err)
+		require.Len(got, 2)
+
+		update := got[0]
+		assert.Empty(update.ActorId)
+		require.Len(update.Changes, 1)
+		assert.Equal("update", update.Changes[0].Operation)
+		assert.Equal(map[string]interface{}{"name": "bob"}, update.Changes[0].Fields)
+		assert.Equal([]string{"phone_number"}, update.Changes[0].NullFields)
+
+		create := got[1]
+		assert.Equal("u_alice", create.ActorId)
+		assert.True(create.Id < update.Id)
+		require.Len(create.Changes, 1)
+		assert.Equal("user", create.Changes[0].Type)
+		assert.Equal("create", create.Changes[0].Operation)
+		assert.Equal("alice", create.Changes[0].Fields["name"])
+		assert.Equal("555-1234", create.Changes[0].Fields["phone_number"])
+		assert.NotContains(create.Changes[0].Fields, "email")
+	})
+	t.Run("limit", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := ReadHistory(ctx, db, resourceId, wrapperFn, types, 1)
+		require.NoError(err)
+		require.Len(got, 1)
+		assert.Equal("update", got[0].Changes[0].Operation)
+	})
+	t.Run("unknown-resource", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := ReadHistory(ctx, db, "u_unknown", wrapperFn, types, 0)
+		require.NoError(err)
+		assert.Empty(got)
+	})
+	t.Run("missing-resource-id", func(t *testing.T) {
+		_, err := ReadHistory(ctx, db, "", wrapperFn, types, 0)
+		require.Error(t, err)
+	})
+}
+
+func Test_renderChangeRedacts(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	c, err := renderChange(Message{
+		Message:  &store.Entry{AggregateName: "test-users", CtData: []byte("ciphertext")},
+		TypeName: "entry",
+		OpType:   OpType_OP_TYPE_CREATE,
+	})
+	require.NoError(err)
+	assert.Equal("test-users", c.Fields["aggregate_name"])
+	assert.Equal(RedactedValue, c.Fields["ct_data"])
+}
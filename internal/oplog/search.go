@@ -0,0 +1,137 @@
+package oplog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/boundary/internal/oplog/store"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// DefaultSearchLimit is the maximum number of entries returned by
+	// SearchEntries when no limit is provided.
+	DefaultSearchLimit = 100
+
+	// MaxSearchLimit is the maximum number of entries returned by
+	// SearchEntries.
+	MaxSearchLimit = 1000
+
+	// ResourcePublicIdMetadataKey is the metadata key of the id of the
+	// resource changed by an entry.
+	ResourcePublicIdMetadataKey = "resource-public-id"
+
+	// ScopeIdMetadataKey is the metadata key of the id of the scope of the
+	// resource changed by an entry, whose oplog key encrypts the entry.
+	ScopeIdMetadataKey = "scope-id"
+
+	// ActorIdMetadataKey is the metadata key of the id of the actor who made
+	// the change recorded by an entry.
+	ActorIdMetadataKey = "actor-id"
+)
+
+// SearchQuery selects oplog entries by their metadata.
+type SearchQuery struct {
+	// Metadata are the values of the metadata of the entries returned: an
+	// entry matches if for each key it has one of the values of the key. At
+	// least one key is required, e.g. ResourcePublicIdMetadataKey to find the
+	// entries changing a resource or ActorIdMetadataKey to find the changes
+	// made by a user.
+	Metadata Metadata
+
+	// BeforeId returns only the entries older than the entry with the id, to
+	// read the next page of a search: it is the NextBeforeId of the previous
+	// page. Zero returns the newest entries.
+	BeforeId uint32
+
+	// Limit is the maximum number of entries returned. A limit <= 0 uses
+	// DefaultSearchLimit; limits above MaxSearchLimit use MaxSearchLimit.
+	Limit int
+}
+
+// SearchResult is a page of the entries matching a SearchQuery, newest
+// first, with their metadata.
+type SearchResult struct {
+	Entries []*store.Entry
+
+	// Metadata is the metadata of the entries by entry id.
+	Metadata map[uint32]Metadata
+
+	// NextBeforeId is the BeforeId of the query of the next page, or zero if
+	// this is the last page.
+	NextBeforeId uint32
+}
+
+// SearchEntries returns the oplog entries matching the query, newest first.
+// The data of the entries is not decrypted. Entries still staged in
+// asynchronous oplog mode are not returned.
+func SearchEntries(ctx context.Context, db *gorm.DB, q *SearchQuery) (*SearchResult, error) {
+	if db == nil {
+		return nil, errors.New("search entries: missing db")
+	}
+	if q == nil || len(q.Metadata) == 0 {
+		return nil, errors.New("search entries: missing metadata")
+	}
+	limit := q.Limit
+	switch {
+	case limit <= 0:
+		limit = DefaultSearchLimit
+	case limit > MaxSearchLimit:
+		limit = MaxSearchLimit
+	}
+
+	// Sort the keys so the same query always builds the same statement
+	keys := make([]string, 0, len(q.Metadata))
+	for k := range q.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	tx := db
+	for _, k := range keys {
+		if k == "" {
+			return nil, errors.New("search entries: empty metadata key")
+		}
+		if len(q.Metadata[k]) == 0 {
+			return nil, fmt.Errorf("search entries: no values for metadata key %q", k)
+		}
+		tx = tx.Where("id in (select entry_id from oplog_metadata where key = ? and value in (?))", k, q.Metadata[k])
+	}
+	if q.BeforeId > 0 {
+		tx = tx.Where("id < ?", q.BeforeId)
+	}
+	var entries []*store.Entry
+	// One more entry than the limit is read to tell whether there is a next
+	// page
+	if err := tx.Order("id desc").Limit(limit + 1).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("search entries: unable to read entries: %w", err)
+	}
+	ret := &SearchResult{Metadata: map[uint32]Metadata{}}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		ret.NextBeforeId = entries[limit-1].Id
+	}
+	ret.Entries = entries
+	if len(entries) == 0 {
+		return ret, nil
+	}
+
+	ids := make([]uint32, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.Id)
+	}
+	var metadata []*store.Metadata
+	if err := db.Where("entry_id in (?)", ids).Order("id").Find(&metadata).Error; err != nil {
+		return nil, fmt.Errorf("search entries: unable to read metadata: %w", err)
+	}
+	for _, md := range metadata {
+		m, ok := ret.Metadata[md.EntryId]
+		if !ok {
+			m = Metadata{}
+			ret.Metadata[md.EntryId] = m
+		}
+		m[md.Key] = append(m[md.Key], md.Value)
+	}
+	return ret, nil
+}
@@ -0,0 +1,109 @@
+package oplog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/oplog/oplog_test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SearchEntries(t *testing.T) {
+	cleanup, db := setup(t)
+	defer testCleanup(t, cleanup, db)
+	ctx := context.Background()
+	cipherer := testWrapper(t)
+
+	ticketer, err := NewGormTicketer(db, WithAggregateNames(true))
+	require.NoError(t, err)
+
+	id := testId(t)
+	resource1, resource2 := "u_1"+id, "u_2"+id
+	alice, bob := "u_alice"+id, "u_bob"+id
+	write := func(t *testing.T, resourceId, actor string) uint32 {
+		t.Helper()
+		ticket, err := ticketer.GetTicket("default")
+		require.NoError(t, err)
+		entry, err := NewEntry("test-users", Metadata{
+			ResourcePublicIdMetadataKey: []string{resourceId},
+			ScopeIdMetadataKey:          []string{"global"},
+			ActorIdMetadataKey:          []string{actor},
+		}, cipherer, ticketer)
+		require.NoError(t, err)
+		require.NoError(t, entry.WriteEntryWith(ctx, &GormWriter{db}, ticket, &Message{
+			Message:  &oplog_test.TestUser{Name: resourceId},
+			TypeName: "user",
+			OpType:   OpType_OP_TYPE_CREATE,
+		}))
+		return entry.Id
+	}
+	ids := []uint32{
+		write(t, resource1, alice),
+		write(t, resource2, alice),
+		write(t, resource1, bob),
+		write(t, resource1, alice),
+	}
+	entryIds := func(res *SearchResult) []uint32 {
+		ret := make([]uint32, 0, len(res.Entries))
+		for _, e := range res.Entries {
+			ret = append(ret, e.Id)
+		}
+		return ret
+	}
+
+	t.Run("resource", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		res, err := SearchEntries(ctx, db, &SearchQuery{Metadata: Metadata{ResourcePublicIdMetadataKey: []string{resource1}}})
+		require.NoError(err)
+		assert.Equal([]uint32{ids[3], ids[2], ids[0]}, entryIds(res))
+		assert.Zero(res.NextBeforeId)
+		assert.Equal([]string{bob}, res.Metadata[ids[2]][ActorIdMetadataKey])
+		assert.Equal([]string{"global"}, res.Metadata[ids[2]][ScopeIdMetadataKey])
+	})
+	t.Run("actor", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		res, err := SearchEntries(ctx, db, &SearchQuery{Metadata: Metadata{ActorIdMetadataKey: []string{alice}}})
+		require.NoError(err)
+		assert.Equal([]uint32{ids[3], ids[1], ids[0]}, entryIds(res))
+	})
+	t.Run("all-keys-match", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		res, err := SearchEntries(ctx, db, &SearchQuery{Metadata: Metadata{
+			ResourcePublicIdMetadataKey: []string{resource1, resource2},
+			ActorIdMetadataKey:          []string{alice},
+		}})
+		require.NoError(err)
+		assert.Equal([]uint32{ids[3], ids[1], ids[0]}, entryIds(res))
+	})
+	t.Run("pages", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		q := &SearchQuery{Metadata: Metadata{ResourcePublicIdMetadataKey: []string{resource1}}, Limit: 2}
+		res, err := SearchEntries(ctx, db, q)
+		require.NoError(err)
+		assert.Equal([]uint32{ids[3], ids[2]}, entryIds(res))
+		assert.Equal(ids[2], res.NextBeforeId)
+
+		q.BeforeId = res.NextBeforeId
+		res, err = SearchEntries(ctx, db, q)
+		require.NoError(err)
+		assert.Equal([]uint32{ids[0]}, entryIds(res))
+		assert.Zero(res.NextBeforeId)
+	})
+	t.Run("no-match", func(t *testing.T) {
+		res, err := SearchEntries(ctx, db, &SearchQuery{Metadata: Metadata{ActorIdMetadataKey: []string{"u_unknown"}}})
+		require.NoError(t, err)
+		assert.Empty(t, res.Entries)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, q := range []*SearchQuery{
+			nil,
+			{},
+			{Metadata: Metadata{"": []string{"a"}}},
+			{Metadata: Metadata{ActorIdMetadataKey: nil}},
+		} {
+			_, err := SearchEntries(ctx, db, q)
+			assert.Error(t, err)
+		}
+	})
+}
@@ -0,0 +1,248 @@
+package oplog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/oplog/store"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// DefaultStagedEntryTableName is the table staged entries are written to
+	DefaultStagedEntryTableName = "oplog_entry_staging"
+
+	// DefaultFlushLimit is the maximum number of staged entries flushed in one
+	// transaction when no limit is provided.
+	DefaultFlushLimit = 100
+)
+
+// StagedEntry is an oplog entry written in asynchronous mode which has not yet
+// been moved to the oplog. Its data is encrypted unless it was staged before
+// entries were encrypted when staged.
+type StagedEntry struct {
+	Id            uint64    `gorm:"primary_key"`
+	CreateTime    time.Time `gorm:"default:current_timestamp"`
+	Version       string
+	AggregateName string
+	ScopeId       string
+	Data          []byte
+	// Metadata is the json encoded list of the entry's metadata
+	Metadata  []byte
+	Encrypted bool
+}
+
+// TableName returns the tablename to override the default gorm table name
+func (s *StagedEntry) TableName() string {
+	return DefaultStagedEntryTableName
+}
+
+// WrapperFunc returns the wrapper used to encrypt the oplog entries of a
+// scope.
+type WrapperFunc func(ctx context.Context, scopeId string) (wrapping.Wrapper, error)
+
+// StageEntryWith marshals the msgs into the entry's data and encrypts it with
+// the entry's Cipherer, the oplog wrapper of scopeId, in the same way as
+// WriteEntryWith, but writes the entry to the staging table rather than the
+// oplog. The ticket is redeemed in the same way as WriteEntryWith, so the
+// entry is only visible once tx is committed. The entry is moved to the oplog
+// by FlushStagedEntries.
+func (e *Entry) StageEntryWith(ctx context.Context, tx Writer, ticket *store.Ticket, scopeId string, msgs ...*Message) error {
+	if tx == nil {
+		return errors.New("bad writer")
+	}
+	if err := e.validate(); err != nil {
+		return fmt.Errorf("error vetting entry for staging: %w", err)
+	}
+	if ticket == nil || ticket.Version == 0 {
+		return errors.New("bad ticket")
+	}
+	if scopeId == "" {
+		return errors.New("missing scope id")
+	}
+	queue := Queue{}
+	for _, m := range msgs {
+		if m == nil {
+			return errors.New("bad message")
+		}
+		if err := queue.Add(m.Message, m.TypeName, m.OpType, WithFieldMaskPaths(m.FieldMaskPaths), WithSetToNullPaths(m.SetToNullPaths)); err != nil {
+			return fmt.Errorf("error adding message to queue: %w", err)
+		}
+	}
+	e.Data = append(e.Data, []byte(queue.Bytes())...)
+	if err := e.EncryptData(ctx); err != nil {
+		return fmt.Errorf("error encrypting entry: %w", err)
+	}
+
+	md, err := json.Marshal(e.Metadata)
+	if err != nil {
+		return fmt.Errorf("error marshaling metadata: %w", err)
+	}
+	staged := &StagedEntry{
+		Version:       e.Version,
+		AggregateName: e.AggregateName,
+		ScopeId:       scopeId,
+		Data:          e.CtData,
+		Metadata:      md,
+		Encrypted:     true,
+	}
+	if err := tx.Create(staged); err != nil {
+		return fmt.Errorf("error writing staged entry to storage: %w", err)
+	}
+	return e.Ticketer.Redeem(ticket)
+}
+
+// FlushStagedEntries writes up to limit staged entries to the oplog in the
+// order the transactions which staged them committed, and deletes them from
+// the staging table, all in one transaction. The staged entries are locked
+// until the transaction completes, so a concurrent flush waits rather than
+// writing entries out of order. If any entry cannot be flushed no entries are
+// flushed, so that a later entry is never written before an earlier one.
+// Entries staged unencrypted, before entries were encrypted when staged, are
+// encrypted with the wrapper wrapperFn returns for their scope. A limit <= 0
+// uses DefaultFlushLimit. The number of entries flushed is returned.
+func FlushStagedEntries(ctx context.Context, db *gorm.DB, wrapperFn WrapperFunc, limit int) (int, error) {
+	if db == nil {
+		return 0, errors.New("flush staged entries: db is nil")
+	}
+	if wrapperFn == nil {
+		return 0, errors.New("flush staged entries: wrapper func is nil")
+	}
+	if limit <= 0 {
+		limit = DefaultFlushLimit
+	}
+	tx := db.BeginTx(ctx, nil)
+	if tx.Error != nil {
+		return 0, fmt.Errorf("flush staged entries: unable to begin transaction: %w", tx.Error)
+	}
+	flushed, err := flushStagedEntries(ctx, tx, wrapperFn, limit)
+	if err != nil {
+		if rbErr := tx.Rollback().Error; rbErr != nil {
+			return 0, fmt.Errorf("flush staged entries: %v: rollback failed: %w", err, rbErr)
+		}
+		return 0, fmt.Errorf("flush staged entries: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return 0, fmt.Errorf("flush staged entries: unable to commit: %w", err)
+	}
+	return flushed, nil
+}
+
+func flushStagedEntries(ctx context.Context, tx *gorm.DB, wrapperFn WrapperFunc, limit int) (int, error) {
+	var staged []*StagedEntry
+	if err := tx.Set("gorm:query_option", "for update").Where("commit_seq is not null").Order("commit_seq asc").Limit(limit).Find(&staged).Error; err != nil {
+		return 0, fmt.Errorf("unable to read staged entries: %w", err)
+	}
+	if len(staged) == 0 {
+		return 0, nil
+	}
+	wrappers := map[string]wrapping.Wrapper{}
+	ids := make([]uint64, 0, len(staged))
+	for _, s := range staged {
+		var md []*store.Metadata
+		if err := json.Unmarshal(s.Metadata, &md); err != nil {
+			return 0, fmt.Errorf("unable to unmarshal metadata of staged entry %d: %w", s.Id, err)
+		}
+		entry := &Entry{
+			Entry: &store.Entry{
+				Version:       s.Version,
+				AggregateName: s.AggregateName,
+				Metadata:      md,
+				CtData:        s.Data,
+			},
+		}
+		if !s.Encrypted {
+			w, ok := wrappers[s.ScopeId]
+			if !ok {
+				var err error
+				if w, err = wrapperFn(ctx, s.ScopeId); err != nil {
+					return 0, fmt.Errorf("unable to get wrapper for staged entry %d: %w", s.Id, err)
+				}
+				wrappers[s.ScopeId] = w
+			}
+			entry.Cipherer = w
+			entry.Data, entry.CtData = s.Data, nil
+			if err := entry.EncryptData(ctx); err != nil {
+				return 0, fmt.Errorf("unable to encrypt staged entry %d: %w", s.Id, err)
+			}
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			return 0, fmt.Errorf("unable to write staged entry %d: %w", s.Id, err)
+		}
+		ids = append(ids, s.Id)
+	}
+	if err := tx.Where("id in (?)", ids).Delete(&StagedEntry{}).Error; err != nil {
+		return 0, fmt.Errorf("unable to delete flushed staged entries: %w", err)
+	}
+	return len(ids), nil
+}
+
+// StagingReport is the result of checking the consistency of the staging
+// table.
+type StagingReport struct {
+	// Count is the number of staged entries.
+	Count int
+	// Oldest is the time the oldest staged entry was staged. It is the zero
+	// time if there are no staged entries.
+	Oldest time.Time
+	// Stale is the number of staged entries staged more than the maximum age
+	// ago.
+	Stale int
+	// Unflushable lists the ids of staged entries which cannot be flushed, and
+	// why. Flushing is blocked until they are resolved.
+	Unflushable map[uint64]string
+}
+
+// CheckStagedEntries checks that every staged entry can be flushed to the oplog
+// and reports how many entries are staged and how many were staged more than
+// maxAge ago, which indicates the background worker is not keeping up or is
+// blocked. Entries are checked without being flushed.
+func CheckStagedEntries(ctx context.Context, db *gorm.DB, wrapperFn WrapperFunc, maxAge time.Duration) (*StagingReport, error) {
+	if db == nil {
+		return nil, errors.New("check staged entries: db is nil")
+	}
+	if wrapperFn == nil {
+		return nil, errors.New("check staged entries: wrapper func is nil")
+	}
+	var staged []*StagedEntry
+	if err := db.Where("commit_seq is not null").Order("commit_seq asc").Find(&staged).Error; err != nil {
+		return nil, fmt.Errorf("check staged entries: unable to read staged entries: %w", err)
+	}
+	report := &StagingReport{
+		Count:       len(staged),
+		Unflushable: map[uint64]string{},
+	}
+	wrapperErrs := map[string]error{}
+	for _, s := range staged {
+		if report.Oldest.IsZero() || s.CreateTime.Before(report.Oldest) {
+			report.Oldest = s.CreateTime
+		}
+		if time.Since(s.CreateTime) > maxAge {
+			report.Stale++
+		}
+		if !s.Encrypted {
+			err, ok := wrapperErrs[s.ScopeId]
+			if !ok {
+				_, err = wrapperFn(ctx, s.ScopeId)
+				wrapperErrs[s.ScopeId] = err
+			}
+			if err != nil {
+				report.Unflushable[s.Id] = fmt.Sprintf("unable to get wrapper for scope %s: %v", s.ScopeId, err)
+				continue
+			}
+		}
+		var md []*store.Metadata
+		if err := json.Unmarshal(s.Metadata, &md); err != nil {
+			report.Unflushable[s.Id] = fmt.Sprintf("unable to unmarshal metadata: %v", err)
+			continue
+		}
+		if len(s.Data) == 0 {
+			report.Unflushable[s.Id] = "no data"
+		}
+	}
+	return report, nil
+}
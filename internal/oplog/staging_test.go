@@ -0,0 +1,231 @@
+package oplog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/oplog/oplog_test"
+	"github.com/hashicorp/boundary/internal/oplog/store"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StageAndFlushEntries(t *testing.T) {
+	cleanup, db := setup(t)
+	defer testCleanup(t, cleanup, db)
+	ctx := context.Background()
+	cipherer := testWrapper(t)
+	wrapperFn := func(context.Context, string) (wrapping.Wrapper, error) {
+		return cipherer, nil
+	}
+
+	ticketer, err := NewGormTicketer(db, WithAggregateNames(true))
+	require.NoError(t, err)
+
+	stageWith := func(t *testing.T, w Writer, ticketName, name string) {
+		t.Helper()
+		ticket, err := ticketer.GetTicket(ticketName)
+		require.NoError(t, err)
+		entry, err := NewEntry(
+			"test-users",
+			Metadata{
+				"key-only":   nil,
+				"deployment": []string{"amex"},
+			},
+			cipherer,
+			ticketer,
+		)
+		require.NoError(t, err)
+		u := &oplog_test.TestUser{Name: name}
+		err = entry.StageEntryWith(ctx, w, ticket, "global",
+			&Message{Message: u, TypeName: "user", OpType: OpType_OP_TYPE_CREATE})
+		require.NoError(t, err)
+	}
+	stage := func(t *testing.T, name string) {
+		t.Helper()
+		stageWith(t, &GormWriter{db}, "default", name)
+	}
+	flushedNames := func(t *testing.T) []string {
+		t.Helper()
+		var entries []Entry
+		require.NoError(t, db.Where("aggregate_name = ?", "test-users").Order("id asc").Find(&entries).Error)
+		types, err := NewTypeCatalog(Type{new(oplog_test.TestUser), "user"})
+		require.NoError(t, err)
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			e.Cipherer = cipherer
+			require.NoError(t, e.DecryptData(ctx))
+			msgs, err := e.UnmarshalData(types)
+			require.NoError(t, err)
+			require.Len(t, msgs, 1)
+			names = append(names, msgs[0].Message.(*oplog_test.TestUser).Name)
+		}
+		return names
+	}
+
+	t.Run("flush", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		names := []string{"staged-" + testId(t), "staged-" + testId(t), "staged-" + testId(t)}
+		for _, n := range names {
+			stage(t, n)
+		}
+		var staged []*StagedEntry
+		require.NoError(db.Find(&staged).Error)
+		assert.Len(staged, len(names))
+		types, err := NewTypeCatalog(Type{new(oplog_test.TestUser), "user"})
+		require.NoError(err)
+		for _, s := range staged {
+			assert.True(s.Encrypted)
+			e := &Entry{Entry: &store.Entry{CtData: s.Data}, Cipherer: cipherer}
+			require.NoError(e.DecryptData(ctx))
+			_, err := e.UnmarshalData(types)
+			assert.NoError(err)
+		}
+
+		report, err := CheckStagedEntries(ctx, db, wrapperFn, time.Hour)
+		require.NoError(err)
+		assert.Equal(len(names), report.Count)
+		assert.Equal(0, report.Stale)
+		assert.Empty(report.Unflushable)
+
+		// Flush in two batches to check the order is kept across batches
+		flushed, err := FlushStagedEntries(ctx, db, wrapperFn, 2)
+		require.NoError(err)
+		assert.Equal(2, flushed)
+		flushed, err = FlushStagedEntries(ctx, db, wrapperFn, 2)
+		require.NoError(err)
+		assert.Equal(1, flushed)
+		flushed, err = FlushStagedEntries(ctx, db, wrapperFn, 2)
+		require.NoError(err)
+		assert.Equal(0, flushed)
+
+		staged = nil
+		require.NoError(db.Find(&staged).Error)
+		assert.Empty(staged)
+
+		var entries []Entry
+		require.NoError(db.Where("aggregate_name = ?", "test-users").Order("id asc").Find(&entries).Error)
+		require.Len(entries, len(names))
+		for i, e := range entries {
+			e.Cipherer = cipherer
+			require.NoError(e.DecryptData(ctx))
+			msgs, err := e.UnmarshalData(types)
+			require.NoError(err)
+			require.Len(msgs, 1)
+			assert.Equal(names[i], msgs[0].Message.(*oplog_test.TestUser).Name)
+			var mdCount int
+			require.NoError(db.Table("oplog_metadata").Where("entry_id = ?", e.Id).Count(&mdCount).Error)
+			assert.Equal(2, mdCount)
+		}
+	})
+	t.Run("commit-order", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		before := len(flushedNames(t))
+		first, second := "staged-"+testId(t), "staged-"+testId(t)
+
+		// Stage first in a transaction which commits after the one staging
+		// second, using separate tickets so neither waits on the other
+		tx1 := db.BeginTx(ctx, nil)
+		stageWith(t, &GormWriter{tx1}, "default", first)
+		tx2 := db.BeginTx(ctx, nil)
+		stageWith(t, &GormWriter{tx2}, "iam_user", second)
+		require.NoError(tx2.Commit().Error)
+		require.NoError(tx1.Commit().Error)
+
+		flushed, err := FlushStagedEntries(ctx, db, wrapperFn, 0)
+		require.NoError(err)
+		assert.Equal(2, flushed)
+		assert.Equal([]string{second, first}, flushedNames(t)[before:])
+	})
+	t.Run("unencrypted-entry", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		name := "staged-" + testId(t)
+		before := len(flushedNames(t))
+
+		// Entries staged before staged entries were encrypted are encrypted
+		// when flushed
+		entry, err := NewEntry("test-users", Metadata{"deployment": []string{"amex"}}, cipherer, ticketer)
+		require.NoError(err)
+		queue := Queue{}
+		require.NoError(queue.Add(&oplog_test.TestUser{Name: name}, "user", OpType_OP_TYPE_CREATE))
+		md, err := json.Marshal(entry.Metadata)
+		require.NoError(err)
+		require.NoError(db.Create(&StagedEntry{
+			Version:       entry.Version,
+			AggregateName: entry.AggregateName,
+			ScopeId:       "global",
+			Data:          queue.Bytes(),
+			Metadata:      md,
+		}).Error)
+		errFn := func(context.Context, string) (wrapping.Wrapper, error) {
+			return nil, errors.New("no wrapper")
+		}
+
+		report, err := CheckStagedEntries(ctx, db, errFn, 0)
+		require.NoError(err)
+		assert.Equal(1, report.Count)
+		assert.Equal(1, report.Stale)
+		assert.Len(report.Unflushable, 1)
+
+		flushed, err := FlushStagedEntries(ctx, db, errFn, 0)
+		require.Error(err)
+		assert.Equal(0, flushed)
+
+		var staged []*StagedEntry
+		require.NoError(db.Find(&staged).Error)
+		assert.Len(staged, 1)
+
+		flushed, err = FlushStagedEntries(ctx, db, wrapperFn, 0)
+		require.NoError(err)
+		assert.Equal(1, flushed)
+		assert.Equal([]string{name}, flushedNames(t)[before:])
+	})
+	t.Run("wrapper-error", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		stage(t, "staged-"+testId(t))
+		errFn := func(context.Context, string) (wrapping.Wrapper, error) {
+			return nil, errors.New("no wrapper")
+		}
+
+		// Entries encrypted when staged don't need the wrapper to be flushed
+		report, err := CheckStagedEntries(ctx, db, errFn, 0)
+		require.NoError(err)
+		assert.Equal(1, report.Count)
+		assert.Equal(1, report.Stale)
+		assert.Empty(report.Unflushable)
+
+		flushed, err := FlushStagedEntries(ctx, db, errFn, 0)
+		require.NoError(err)
+		assert.Equal(1, flushed)
+	})
+	t.Run("bad-args", func(t *testing.T) {
+		assert := assert.New(t)
+		ticket, err := ticketer.GetTicket("default")
+		require.NoError(t, err)
+		entry, err := NewEntry("test-users", Metadata{"deployment": []string{"amex"}}, cipherer, ticketer)
+		require.NoError(t, err)
+		u := &oplog_test.TestUser{Name: "staged-" + testId(t)}
+		msg := &Message{Message: u, TypeName: "user", OpType: OpType_OP_TYPE_CREATE}
+
+		assert.Error(entry.StageEntryWith(ctx, nil, ticket, "global", msg))
+		assert.Error(entry.StageEntryWith(ctx, &GormWriter{db}, nil, "global", msg))
+		assert.Error(entry.StageEntryWith(ctx, &GormWriter{db}, ticket, "", msg))
+		assert.Error(entry.StageEntryWith(ctx, &GormWriter{db}, ticket, "global", nil))
+		noCipherer, err := NewEntry("test-users", Metadata{"deployment": []string{"amex"}}, nil, ticketer)
+		require.NoError(t, err)
+		assert.Error(noCipherer.StageEntryWith(ctx, &GormWriter{db}, ticket, "global", msg))
+
+		_, err = FlushStagedEntries(ctx, nil, wrapperFn, 0)
+		assert.Error(err)
+		_, err = FlushStagedEntries(ctx, db, nil, 0)
+		assert.Error(err)
+		_, err = CheckStagedEntries(ctx, nil, wrapperFn, 0)
+		assert.Error(err)
+		_, err = CheckStagedEntries(ctx, db, nil, 0)
+		assert.Error(err)
+	})
+}
@@ -0,0 +1,177 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/go-multierror"
+)
+
+// A Handler delivers a message. A message whose Handler returns an error is
+// delivered again later.
+type Handler func(ctx context.Context, m *Message) error
+
+// A Dispatcher delivers the messages in the outbox to their handlers.
+type Dispatcher struct {
+	reader db.Reader
+	writer db.Writer
+	opts   options
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewDispatcher creates a new Dispatcher. Supported options: WithLimit,
+// WithMaxAttempts, WithBackoff and WithLeaseDuration.
+func NewDispatcher(r db.Reader, w db.Writer, opt ...Option) (*Dispatcher, error) {
+	switch {
+	case r == nil:
+		return nil, fmt.Errorf("db.Reader: outbox: %w", errors.ErrInvalidParameter)
+	case w == nil:
+		return nil, fmt.Errorf("db.Writer: outbox: %w", errors.ErrInvalidParameter)
+	}
+	return &Dispatcher{
+		reader:   r,
+		writer:   w,
+		opts:     getOpts(opt...),
+		handlers: map[string]Handler{},
+	}, nil
+}
+
+// RegisterHandler sets the handler which delivers messages of the kind.
+// Messages of kinds without a handler are not delivered and fail after their
+// maximum attempts.
+func (d *Dispatcher) RegisterHandler(kind string, h Handler) error {
+	if strings.TrimSpace(kind) == "" {
+		return fmt.Errorf("register handler: missing kind: %w", errors.ErrInvalidParameter)
+	}
+	if h == nil {
+		return fmt.Errorf("register handler: missing handler: %w", errors.ErrInvalidParameter)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers[kind] = h
+	return nil
+}
+
+func (d *Dispatcher) handler(kind string) Handler {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.handlers[kind]
+}
+
+// Dispatch delivers the messages which are due, oldest first, and returns the
+// number of messages delivered. Delivered messages are deleted. A message
+// whose delivery fails is delivered again after the backoff for its attempts
+// or, once it exceeds the maximum attempts, marked failed and no longer
+// delivered.
+//
+// The messages are claimed by leasing them in a short transaction, so
+// concurrent calls, e.g. from several controllers, deliver different messages
+// and no transaction is held open while handlers run. The outcome of each
+// delivery is recorded in its own transaction, which is retried without
+// delivering the message again. A message whose outcome cannot be recorded is
+// delivered again once its lease expires.
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	msgs, err := d.claim(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("dispatch: %w", err)
+	}
+	var delivered int
+	var result *multierror.Error
+	for _, m := range msgs {
+		deliverErr := d.deliver(ctx, m)
+		if err := d.record(ctx, m, deliverErr); err != nil {
+			result = multierror.Append(result, err)
+			continue
+		}
+		if deliverErr == nil {
+			delivered++
+		}
+	}
+	if err := result.ErrorOrNil(); err != nil {
+		return delivered, fmt.Errorf("dispatch: %w", err)
+	}
+	return delivered, nil
+}
+
+// claim leases up to the limit of messages which are due and returns them
+// oldest first.
+func (d *Dispatcher) claim(ctx context.Context) ([]*Message, error) {
+	var msgs []*Message
+	_, err := d.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(r db.Reader, _ db.Writer) error {
+			msgs = nil
+			rows, err := r.Query(ctx, claimQuery, []interface{}{d.opts.withLease.Milliseconds(), d.opts.withLimit})
+			if err != nil {
+				return fmt.Errorf("unable to claim messages: %w", err)
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var m Message
+				if err := r.ScanRows(rows, &m); err != nil {
+					return fmt.Errorf("unable to scan message: %w", err)
+				}
+				msgs = append(msgs, &m)
+			}
+			return rows.Err()
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].Id < msgs[j].Id })
+	return msgs, nil
+}
+
+// deliver calls the handler of the message, which is cancelled when the
+// message's lease expires.
+func (d *Dispatcher) deliver(ctx context.Context, m *Message) error {
+	h := d.handler(m.Kind)
+	if h == nil {
+		return fmt.Errorf("no handler for kind %q", m.Kind)
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.opts.withLease)
+	defer cancel()
+	return h(ctx, m)
+}
+
+// record deletes the message if it was delivered and otherwise reschedules it
+// or marks it failed. A failed attempt is only recorded if no other attempt
+// was recorded since the message was claimed.
+func (d *Dispatcher) record(ctx context.Context, m *Message, deliverErr error) error {
+	query, args := deleteMessageQuery, []interface{}{m.Id}
+	if deliverErr != nil {
+		attempts := m.Attempts + 1
+		query = retryMessageQuery
+		if attempts >= d.opts.withMaxAttempts {
+			query = failMessageQuery
+		}
+		backoff := d.opts.withBackoff.Duration(uint(attempts)).Milliseconds()
+		args = []interface{}{attempts, backoff, deliverErr.Error(), m.Id, m.Attempts}
+	}
+	_, err := d.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			_, err := w.Exec(ctx, query, args)
+			return err
+		},
+	)
+	if err != nil {
+		if deliverErr == nil {
+			return fmt.Errorf("unable to delete delivered message %d: %w", m.Id, err)
+		}
+		return fmt.Errorf("unable to reschedule message %d: %w", m.Id, err)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package outbox
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	ctx := context.Background()
+
+	enqueue := func(t *testing.T, kind string, payload string, commit bool) {
+		t.Helper()
+		rollback := stderrors.New("rollback")
+		_, err := rw.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{}, func(_ db.Reader, w db.Writer) error {
+			if err := Enqueue(ctx, w, kind, []byte(payload)); err != nil {
+				return err
+			}
+			if !commit {
+				return rollback
+			}
+			return nil
+		})
+		if commit {
+			require.NoError(t, err)
+		} else {
+			require.True(t, stderrors.Is(err, rollback))
+		}
+	}
+	lookup := func(t *testing.T, kind string) []*Message {
+		t.Helper()
+		var found []*Message
+		require.NoError(t, rw.SearchWhere(ctx, &found, "kind = ?", []interface{}{kind}))
+		return found
+	}
+
+	t.Run("delivered-after-commit", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		d, err := NewDispatcher(rw, rw)
+		require.NoError(err)
+		var got []string
+		require.NoError(d.RegisterHandler("delivered", func(_ context.Context, m *Message) error {
+			got = append(got, string(m.Payload))
+			return nil
+		}))
+		enqueue(t, "delivered", "first", true)
+		enqueue(t, "delivered", "rolled-back", false)
+		enqueue(t, "delivered", "second", true)
+
+		delivered, err := d.Dispatch(ctx)
+		require.NoError(err)
+		assert.Equal(2, delivered)
+		assert.Equal([]string{"first", "second"}, got)
+		assert.Empty(lookup(t, "delivered"))
+	})
+	t.Run("retried", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		d, err := NewDispatcher(rw, rw, WithBackoff(db.ConstBackoff{DurationMs: 0}))
+		require.NoError(err)
+		var calls int
+		require.NoError(d.RegisterHandler("retried", func(context.Context, *Message) error {
+			calls++
+			if calls == 1 {
+				return stderrors.New("unavailable")
+			}
+			return nil
+		}))
+		enqueue(t, "retried", "payload", true)
+
+		delivered, err := d.Dispatch(ctx)
+		require.NoError(err)
+		assert.Equal(0, delivered)
+		found := lookup(t, "retried")
+		require.Len(found, 1)
+		assert.Equal(uint32(1), found[0].Attempts)
+		assert.Equal("unavailable", found[0].LastError)
+		assert.Nil(found[0].FailedTime)
+
+		delivered, err = d.Dispatch(ctx)
+		require.NoError(err)
+		assert.Equal(1, delivered)
+		assert.Empty(lookup(t, "retried"))
+	})
+	t.Run("backoff", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		d, err := NewDispatcher(rw, rw, WithBackoff(Backoff{Base: time.Hour, Max: time.Hour}))
+		require.NoError(err)
+		var calls int
+		require.NoError(d.RegisterHandler("backoff", func(context.Context, *Message) error {
+			calls++
+			return stderrors.New("unavailable")
+		}))
+		enqueue(t, "backoff", "payload", true)
+
+		_, err = d.Dispatch(ctx)
+		require.NoError(err)
+		_, err = d.Dispatch(ctx)
+		require.NoError(err)
+		assert.Equal(1, calls)
+	})
+	t.Run("delivered-outside-transaction", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		d, err := NewDispatcher(rw, rw)
+		require.NoError(err)
+		other, err := NewDispatcher(rw, rw)
+		require.NoError(err)
+		var otherDelivered int
+		var otherErr error
+		var hasDeadline bool
+		require.NoError(d.RegisterHandler("leased", func(ctx context.Context, m *Message) error {
+			_, hasDeadline = ctx.Deadline()
+			// The claim is committed, so the message is neither locked nor
+			// due while it is being delivered
+			otherDelivered, otherErr = other.Dispatch(ctx)
+			return nil
+		}))
+		require.NoError(other.RegisterHandler("leased", func(context.Context, *Message) error {
+			return nil
+		}))
+		enqueue(t, "leased", "payload", true)
+
+		delivered, err := d.Dispatch(ctx)
+		require.NoError(err)
+		assert.Equal(1, delivered)
+		assert.True(hasDeadline)
+		require.NoError(otherErr)
+		assert.Equal(0, otherDelivered)
+		assert.Empty(lookup(t, "leased"))
+	})
+	t.Run("lease-expired", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		d, err := NewDispatcher(rw, rw, WithLeaseDuration(10*time.Millisecond), WithBackoff(db.ConstBackoff{DurationMs: 0}))
+		require.NoError(err)
+		require.NoError(d.RegisterHandler("expired", func(ctx context.Context, _ *Message) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+		enqueue(t, "expired", "payload", true)
+
+		delivered, err := d.Dispatch(ctx)
+		require.NoError(err)
+		assert.Equal(0, delivered)
+		found := lookup(t, "expired")
+		require.Len(found, 1)
+		assert.Equal(uint32(1), found[0].Attempts)
+		assert.Contains(found[0].LastError, "deadline exceeded")
+	})
+	t.Run("stale-attempt", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		d, err := NewDispatcher(rw, rw)
+		require.NoError(err)
+		require.NoError(d.RegisterHandler("stale", func(ctx context.Context, m *Message) error {
+			// Another dispatcher claimed the message after the lease expired
+			// and recorded its failed attempt first
+			_, err := rw.Exec(ctx, "update outbox_message set attempts = 5, last_error = 'other' where id = ?", []interface{}{m.Id})
+			require.NoError(err)
+			return stderrors.New("unavailable")
+		}))
+		enqueue(t, "stale", "payload", true)
+
+		delivered, err := d.Dispatch(ctx)
+		require.NoError(err)
+		assert.Equal(0, delivered)
+		found := lookup(t, "stale")
+		require.Len(found, 1)
+		assert.Equal(uint32(5), found[0].Attempts)
+		assert.Equal("other", found[0].LastError)
+	})
+	t.Run("failed", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		d, err := NewDispatcher(rw, rw, WithMaxAttempts(1))
+		require.NoError(err)
+		enqueue(t, "failed", "payload", true)
+
+		delivered, err := d.Dispatch(ctx)
+		require.NoError(err)
+		assert.Equal(0, delivered)
+		found := lookup(t, "failed")
+		require.Len(found, 1)
+		assert.NotNil(found[0].FailedTime)
+		assert.Contains(found[0].LastError, "no handler")
+	})
+}
+
+func TestEnqueue_Validation(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	ctx := context.Background()
+
+	err := Enqueue(ctx, nil, "kind", nil)
+	assert.True(t, errors.Is(err, errors.ErrInvalidParameter))
+	err = Enqueue(ctx, rw, " ", nil)
+	assert.True(t, errors.Is(err, errors.ErrInvalidParameter))
+
+	_, err = NewDispatcher(nil, rw)
+	assert.True(t, errors.Is(err, errors.ErrInvalidParameter))
+	d, err := NewDispatcher(rw, rw)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(d.RegisterHandler("", func(context.Context, *Message) error { return nil }), errors.ErrInvalidParameter))
+	assert.True(t, errors.Is(d.RegisterHandler("kind", nil), errors.ErrInvalidParameter))
+}
+
+func TestBackoff_Duration(t *testing.T) {
+	b := Backoff{Base: time.Second, Max: 10 * time.Second}
+	assert.Equal(t, time.Second, b.Duration(1))
+	assert.Equal(t, 2*time.Second, b.Duration(2))
+	assert.Equal(t, 8*time.Second, b.Duration(4))
+	assert.Equal(t, 10*time.Second, b.Duration(5))
+	assert.Equal(t, 10*time.Second, b.Duration(50))
+}
@@ -0,0 +1,114 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+const (
+	// DefaultLimit is the default maximum number of messages delivered by one
+	// call to Dispatch.
+	DefaultLimit = 20
+	// DefaultMaxAttempts is the default number of failed delivery attempts
+	// after which a message is no longer delivered.
+	DefaultMaxAttempts = 20
+	// DefaultLeaseDuration is the default time a message claimed by Dispatch
+	// has to be delivered before it may be claimed again.
+	DefaultLeaseDuration = 5 * time.Minute
+)
+
+// getOpts - iterate the inbound Options and return a struct
+func getOpts(opt ...Option) options {
+	opts := getDefaultOptions()
+	for _, o := range opt {
+		o(&opts)
+	}
+	return opts
+}
+
+// Option - how Options are passed as arguments
+type Option func(*options)
+
+// options = how options are represented
+type options struct {
+	withLimit       int
+	withMaxAttempts uint32
+	withBackoff     db.Backoff
+	withLease       time.Duration
+}
+
+func getDefaultOptions() options {
+	return options{
+		withLimit:       DefaultLimit,
+		withMaxAttempts: DefaultMaxAttempts,
+		withBackoff:     Backoff{Base: time.Second, Max: time.Hour},
+		withLease:       DefaultLeaseDuration,
+	}
+}
+
+// WithLimit provides an option to limit the number of messages delivered by
+// one call to Dispatch. A limit <= 0 uses DefaultLimit.
+func WithLimit(limit int) Option {
+	return func(o *options) {
+		if limit <= 0 {
+			limit = DefaultLimit
+		}
+		o.withLimit = limit
+	}
+}
+
+// WithMaxAttempts provides an option to set the number of failed delivery
+// attempts after which a message is no longer delivered. Zero uses
+// DefaultMaxAttempts.
+func WithMaxAttempts(attempts uint32) Option {
+	return func(o *options) {
+		if attempts == 0 {
+			attempts = DefaultMaxAttempts
+		}
+		o.withMaxAttempts = attempts
+	}
+}
+
+// WithBackoff provides an option to set the delay before the next delivery
+// attempt of a message after a failed attempt. The backoff is called with the
+// number of failed attempts.
+func WithBackoff(b db.Backoff) Option {
+	return func(o *options) {
+		if b != nil {
+			o.withBackoff = b
+		}
+	}
+}
+
+// WithLeaseDuration provides an option to set how long a message claimed by
+// Dispatch is leased for. The message is not claimed again until the lease
+// expires, and its handler is cancelled when the lease expires. A duration <= 0
+// uses DefaultLeaseDuration.
+func WithLeaseDuration(d time.Duration) Option {
+	return func(o *options) {
+		if d <= 0 {
+			d = DefaultLeaseDuration
+		}
+		o.withLease = d
+	}
+}
+
+// Backoff doubles the delay between delivery attempts, starting at Base, up to
+// Max.
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Duration returns the delay after the attempt.
+func (b Backoff) Duration(attempt uint) time.Duration {
+	d := b.Base
+	for i := uint(1); i < attempt && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	return d
+}
@@ -0,0 +1,63 @@
+// Package outbox delivers external side effects, e.g. webhooks, event bus
+// messages or emails, of changes made by repositories. A message is enqueued
+// with the writer of the transaction making the change, so it is stored if and
+// only if the change is committed. A Dispatcher delivers stored messages to the
+// Handler registered for their kind after the transaction commits, retrying
+// failed deliveries with backoff. Delivery is at least once: handlers must
+// tolerate receiving a message more than once.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+const defaultMessageTableName = "outbox_message"
+
+// A Message is an external side effect waiting to be delivered.
+type Message struct {
+	Id         uint64               `gorm:"primary_key"`
+	CreateTime *timestamp.Timestamp `gorm:"default:current_timestamp"`
+	// Kind selects the Handler which delivers the message.
+	Kind string
+	// Payload is the content of the message, opaque to the outbox.
+	Payload []byte
+	// Attempts is the number of failed delivery attempts.
+	Attempts        uint32
+	NextAttemptTime *timestamp.Timestamp `gorm:"default:current_timestamp"`
+	// LastError is the error of the last failed delivery attempt.
+	LastError string
+	// FailedTime is set when the message exceeded its maximum delivery
+	// attempts and will no longer be delivered.
+	FailedTime *timestamp.Timestamp
+}
+
+// TableName returns the table name for the message.
+func (m *Message) TableName() string {
+	return defaultMessageTableName
+}
+
+// Enqueue stores a message of the kind with the payload to be delivered by a
+// Dispatcher. w must be the writer of the transaction making the change the
+// message is about, e.g. the writer passed to a db.TxHandler, so that the
+// message is only delivered if the transaction commits.
+func Enqueue(ctx context.Context, w db.Writer, kind string, payload []byte) error {
+	if w == nil {
+		return fmt.Errorf("enqueue: missing writer: %w", errors.ErrInvalidParameter)
+	}
+	if strings.TrimSpace(kind) == "" {
+		return fmt.Errorf("enqueue: missing kind: %w", errors.ErrInvalidParameter)
+	}
+	if payload == nil {
+		payload = []byte{}
+	}
+	if err := w.Create(ctx, &Message{Kind: kind, Payload: payload}); err != nil {
+		return fmt.Errorf("enqueue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package outbox
+
+const (
+	// claimQuery leases the messages which are due by moving their next
+	// attempt time past the lease, so they are not claimed again until the
+	// lease expires.
+	claimQuery = `
+update outbox_message
+   set next_attempt_time = current_timestamp + ? * interval '1 millisecond'
+ where id in (
+       select id
+         from outbox_message
+        where failed_time is null
+          and next_attempt_time <= current_timestamp
+        order by id
+        limit ?
+          for update skip locked
+       )
+returning *;
+`
+	deleteMessageQuery = `delete from outbox_message where id = ?;`
+
+	retryMessageQuery = `
+update outbox_message
+   set attempts = ?,
+       next_attempt_time = current_timestamp + ? * interval '1 millisecond',
+       last_error = ?
+ where id = ?
+   and attempts = ?;
+`
+	failMessageQuery = `
+update outbox_message
+   set attempts = ?,
+       next_attempt_time = current_timestamp + ? * interval '1 millisecond',
+       last_error = ?,
+       failed_time = current_timestamp
+ where id = ?
+   and attempts = ?;
+`
+)
@@ -72,7 +72,9 @@ func (a ACL) Allowed(r Resource, aType action.Type) (results ACLResults) {
 
 	// Now, go through and check the cases indicated above
 	for _, grant := range grants {
-		if !(grant.actions[aType] || grant.actions[action.All]) {
+		// A grant of an action also allows its self variant, e.g. read allows
+		// read:self
+		if !(grant.actions[aType] || grant.actions[action.All] || grant.actions[action.SelfParent[aType]]) {
 			continue
 		}
 		switch {
@@ -61,6 +61,18 @@ func Test_ACLAllowed(t *testing.T) {
 				"id=*;type=*;actions=create,update",
 			},
 		},
+		{
+			scope: "p_a",
+			grants: []string{
+				"id=*;type=session;actions=read:self",
+			},
+		},
+		{
+			scope: "p_b",
+			grants: []string{
+				"id=*;type=session;actions=read,cancel",
+			},
+		},
 	}
 
 	// See acl.go for expected allowed formats. The goal here is to basically
@@ -252,6 +264,26 @@ func Test_ACLAllowed(t *testing.T) {
 			},
 			userId: "u_abcd1234",
 		},
+		{
+			name:        "self session action",
+			resource:    Resource{ScopeId: "p_a", Id: "s_1234567890", Type: resource.Session},
+			scopeGrants: commonGrants,
+			actionsAllowed: []actionAllowed{
+				{action: action.ReadSelf, allowed: true},
+				{action: action.Read},
+				{action: action.CancelSelf},
+			},
+		},
+		{
+			name:        "parent of self session action",
+			resource:    Resource{ScopeId: "p_b", Id: "s_1234567890", Type: resource.Session},
+			scopeGrants: commonGrants,
+			actionsAllowed: []actionAllowed{
+				{action: action.Read, allowed: true},
+				{action: action.ReadSelf, allowed: true},
+				{action: action.CancelSelf, allowed: true},
+			},
+		},
 	}
 
 	for _, test := range tests {
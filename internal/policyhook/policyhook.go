@@ -0,0 +1,281 @@
+// Package policyhook evaluates authorization decisions with an external
+// policy engine, such as an Open Policy Agent (OPA) serving a Rego policy.
+// The hook is called for the requests allowed by the grants of their caller,
+// which it can then deny with a message; it can't allow requests the grants
+// deny. It is called either for all authorization decisions or only for
+// session authorizations.
+//
+// The hook is sent a POST with the JSON {"input": Input} and answers in the
+// format of the OPA data API: {"result": true} allows the request, and
+// {"result": {"allow": false, "message": "..."}} denies it with the message.
+// The Url of a hook for an OPA is that of the rule of the decision, e.g.
+// http://opa:8181/v1/data/boundary/authz.
+//
+// The hook has a timeout and a failure policy applied when it can't be
+// called, times out, answers with an unexpected status or an undefined
+// decision: with the open policy the request is allowed, with the closed
+// policy it is denied. Decisions can be logged by the controller.
+package policyhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// DefaultTimeout is the timeout of hooks which don't set one.
+	DefaultTimeout = 2 * time.Second
+
+	// maxResponseSize is the most bytes of the body of an answer read.
+	maxResponseSize = 64 * 1024
+)
+
+// A Scope is which authorization decisions the hook is called for.
+type Scope string
+
+const (
+	// AllDecisions calls the hook for every authorization decision.
+	AllDecisions Scope = "all"
+
+	// SessionAuthorizations calls the hook only for the authorization of
+	// sessions to targets.
+	SessionAuthorizations Scope = "session-authorizations"
+)
+
+// A FailurePolicy is what happens to a request when the hook fails.
+type FailurePolicy string
+
+const (
+	// FailOpen allows the request.
+	FailOpen FailurePolicy = "open"
+
+	// FailClosed denies the request.
+	FailClosed FailurePolicy = "closed"
+)
+
+// A Hook is the policy engine authorization decisions are evaluated with.
+type Hook struct {
+	Url string
+
+	// Token is sent as a bearer token, if set, for engines requiring
+	// authentication.
+	Token string
+
+	// Scope is SessionAuthorizations if empty.
+	Scope Scope
+
+	// Timeout is DefaultTimeout if zero.
+	Timeout time.Duration
+
+	// FailurePolicy is FailClosed if empty.
+	FailurePolicy FailurePolicy
+
+	// DecisionLog logs every decision of the hook.
+	DecisionLog bool
+}
+
+// Resource is the resource of a request.
+type Resource struct {
+	Id      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	ScopeId string `json:"scope_id"`
+	Pin     string `json:"pin,omitempty"`
+}
+
+// Input is the context of a request sent to the hook.
+type Input struct {
+	RequestId   string    `json:"request_id,omitempty"`
+	UserId      string    `json:"user_id"`
+	AuthTokenId string    `json:"auth_token_id,omitempty"`
+	Resource    Resource  `json:"resource"`
+	Action      string    `json:"action"`
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	Time        time.Time `json:"time"`
+}
+
+// Decision is a decision of the hook returned as an object.
+type Decision struct {
+	Allow bool `json:"allow"`
+
+	// Message is returned to the client of a denied request.
+	Message string `json:"message"`
+}
+
+// DeniedError is returned for requests denied by the hook.
+type DeniedError struct {
+	// Message is the message of the hook, or a description of its failure if
+	// it failed with the closed policy.
+	Message string
+}
+
+// Error returns the message of the denial.
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("denied by policy: %s", e.Message)
+}
+
+// Evaluator evaluates authorization decisions with a hook.
+type Evaluator struct {
+	hook   *Hook
+	client *http.Client
+	logger hclog.Logger
+}
+
+// NewEvaluator returns an Evaluator calling the hook with the client, or with
+// http.DefaultClient if nil.
+func NewEvaluator(hook *Hook, client *http.Client, logger hclog.Logger) (*Evaluator, error) {
+	const op = "new policy hook evaluator"
+	switch {
+	case hook == nil:
+		return nil, fmt.Errorf("%s: missing hook: %w", op, errors.ErrInvalidParameter)
+	case hook.Url == "":
+		return nil, fmt.Errorf("%s: missing url: %w", op, errors.ErrInvalidParameter)
+	case logger == nil:
+		return nil, fmt.Errorf("%s: missing logger: %w", op, errors.ErrInvalidParameter)
+	}
+	switch hook.Scope {
+	case "", AllDecisions, SessionAuthorizations:
+	default:
+		return nil, fmt.Errorf("%s: unknown scope %q: %w", op, hook.Scope, errors.ErrInvalidParameter)
+	}
+	switch hook.FailurePolicy {
+	case "", FailOpen, FailClosed:
+	default:
+		return nil, fmt.Errorf("%s: unknown failure policy %q: %w", op, hook.FailurePolicy, errors.ErrInvalidParameter)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Evaluator{hook: hook, client: client, logger: logger}, nil
+}
+
+// Evaluate evaluates the request with the hook if it is in the scope of the
+// hook, setting the Time of in if zero. It returns a DeniedError if the hook
+// denies the request, or if it fails with the closed policy. A nil Evaluator
+// allows all requests.
+func (e *Evaluator) Evaluate(ctx context.Context, in *Input) error {
+	if e == nil || in == nil || !e.applies(in) {
+		return nil
+	}
+	if in.Time.IsZero() {
+		in.Time = time.Now()
+	}
+	start := time.Now()
+	d, err := e.call(ctx, in)
+	if err != nil {
+		e.logger.Warn("policy hook failed", "url", e.hook.Url, "request_id", in.RequestId, "failure_policy", e.failurePolicy(), "error", err)
+		if e.failurePolicy() == FailOpen {
+			return nil
+		}
+		d = &Decision{Message: "authorization policy failed"}
+	}
+	if e.hook.DecisionLog {
+		e.logger.Info("policy decision", "request_id", in.RequestId, "user_id", in.UserId,
+			"resource_id", in.Resource.Id, "resource_type", in.Resource.Type, "scope_id", in.Resource.ScopeId,
+			"action", in.Action, "allow", d.Allow, "message", d.Message, "failed", err != nil, "duration", time.Since(start))
+	}
+	if d.Allow {
+		return nil
+	}
+	if d.Message == "" {
+		d.Message = "denied by authorization policy"
+	}
+	return &DeniedError{Message: d.Message}
+}
+
+// applies returns true if the request is in the scope of the hook.
+func (e *Evaluator) applies(in *Input) bool {
+	if e.hook.Scope == AllDecisions {
+		return true
+	}
+	return in.Resource.Type == resource.Target.String() && in.Action == action.AuthorizeSession.String()
+}
+
+// call sends the input to the hook and returns its decision.
+func (e *Evaluator) call(ctx context.Context, in *Input) (*Decision, error) {
+	body, err := json.Marshal(struct {
+		Input *Input `json:"input"`
+	}{Input: in})
+	if err != nil {
+		return nil, err
+	}
+	timeout := e.hook.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.hook.Url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if e.hook.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+e.hook.Token)
+	}
+	httpResp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %d", httpResp.StatusCode)
+	}
+	respBody, err := ioutil.ReadAll(io.LimitReader(httpResp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	result := bytes.TrimSpace(resp.Result)
+	if len(result) == 0 || bytes.Equal(result, []byte("null")) {
+		return nil, fmt.Errorf("undefined decision")
+	}
+	d := new(Decision)
+	if err := json.Unmarshal(result, &d.Allow); err == nil {
+		return d, nil
+	}
+	if err := json.Unmarshal(result, d); err != nil {
+		return nil, fmt.Errorf("invalid decision: %w", err)
+	}
+	return d, nil
+}
+
+func (e *Evaluator) failurePolicy() FailurePolicy {
+	if e.hook.FailurePolicy == "" {
+		return FailClosed
+	}
+	return e.hook.FailurePolicy
+}
+
+type contextKey int
+
+var evaluatorKey contextKey
+
+// NewContext returns a context carrying the evaluator, for the authorization
+// checks of a request.
+func NewContext(ctx context.Context, e *Evaluator) context.Context {
+	return context.WithValue(ctx, evaluatorKey, e)
+}
+
+// FromContext returns the evaluator of the context, or nil if it has none.
+func FromContext(ctx context.Context) *Evaluator {
+	e, _ := ctx.Value(evaluatorKey).(*Evaluator)
+	return e
+}
@@ -0,0 +1,154 @@
+package policyhook
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluator_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	// policyServer answers calls with the status and body, recording their
+	// inputs
+	policyServer := func(t *testing.T, status int, body string, delay time.Duration) (*httptest.Server, *[]*Input) {
+		t.Helper()
+		var got []*Input
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req struct {
+				Input *Input `json:"input"`
+			}
+			require.NoError(t, json.Unmarshal(b, &req))
+			got = append(got, req.Input)
+			time.Sleep(delay)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}))
+		t.Cleanup(srv.Close)
+		return srv, &got
+	}
+	authorizeSession := func() *Input {
+		return &Input{
+			UserId:   "u_1234567890",
+			Resource: Resource{Id: "ttcp_1234567890", Type: "target", ScopeId: "p_1234567890"},
+			Action:   "authorize-session",
+		}
+	}
+	readTarget := func() *Input {
+		return &Input{
+			UserId:   "u_1234567890",
+			Resource: Resource{Id: "ttcp_1234567890", Type: "target", ScopeId: "p_1234567890"},
+			Action:   "read",
+		}
+	}
+	assertDenied := func(t *testing.T, err error, msg string) {
+		t.Helper()
+		var denied *DeniedError
+		require.True(t, stderrors.As(err, &denied))
+		assert.Equal(t, msg, denied.Message)
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		srv, got := policyServer(t, http.StatusOK, `{"result": true}`, 0)
+		e, err := NewEvaluator(&Hook{Url: srv.URL, DecisionLog: true}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		require.NoError(e.Evaluate(context.Background(), authorizeSession()))
+		require.Len(*got, 1)
+		assert.Equal("ttcp_1234567890", (*got)[0].Resource.Id)
+		assert.False((*got)[0].Time.IsZero())
+	})
+	t.Run("denied-with-message", func(t *testing.T) {
+		require := require.New(t)
+		srv, _ := policyServer(t, http.StatusOK, `{"result": {"allow": false, "message": "outside of business hours"}}`, 0)
+		e, err := NewEvaluator(&Hook{Url: srv.URL}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		assertDenied(t, e.Evaluate(context.Background(), authorizeSession()), "outside of business hours")
+	})
+	t.Run("denied", func(t *testing.T) {
+		require := require.New(t)
+		srv, _ := policyServer(t, http.StatusOK, `{"result": false}`, 0)
+		e, err := NewEvaluator(&Hook{Url: srv.URL}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		assertDenied(t, e.Evaluate(context.Background(), authorizeSession()), "denied by authorization policy")
+	})
+	t.Run("scope", func(t *testing.T) {
+		require := require.New(t)
+		srv, got := policyServer(t, http.StatusOK, `{"result": false}`, 0)
+		e, err := NewEvaluator(&Hook{Url: srv.URL}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		require.NoError(e.Evaluate(context.Background(), readTarget()))
+		require.Empty(*got)
+
+		e, err = NewEvaluator(&Hook{Url: srv.URL, Scope: AllDecisions}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		assertDenied(t, e.Evaluate(context.Background(), readTarget()), "denied by authorization policy")
+		require.Len(*got, 1)
+	})
+	t.Run("undefined-decision", func(t *testing.T) {
+		require := require.New(t)
+		srv, _ := policyServer(t, http.StatusOK, `{}`, 0)
+		e, err := NewEvaluator(&Hook{Url: srv.URL, FailurePolicy: FailOpen}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		require.NoError(e.Evaluate(context.Background(), authorizeSession()))
+
+		e, err = NewEvaluator(&Hook{Url: srv.URL}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		assertDenied(t, e.Evaluate(context.Background(), authorizeSession()), "authorization policy failed")
+	})
+	t.Run("failure", func(t *testing.T) {
+		require := require.New(t)
+		srv, _ := policyServer(t, http.StatusInternalServerError, "", 0)
+		e, err := NewEvaluator(&Hook{Url: srv.URL, FailurePolicy: FailOpen}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		require.NoError(e.Evaluate(context.Background(), authorizeSession()))
+
+		e, err = NewEvaluator(&Hook{Url: srv.URL, FailurePolicy: FailClosed}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		assertDenied(t, e.Evaluate(context.Background(), authorizeSession()), "authorization policy failed")
+	})
+	t.Run("timeout", func(t *testing.T) {
+		require := require.New(t)
+		srv, _ := policyServer(t, http.StatusOK, `{"result": true}`, 200*time.Millisecond)
+		e, err := NewEvaluator(&Hook{Url: srv.URL, Timeout: 10 * time.Millisecond}, nil, hclog.NewNullLogger())
+		require.NoError(err)
+		assertDenied(t, e.Evaluate(context.Background(), authorizeSession()), "authorization policy failed")
+	})
+	t.Run("nil", func(t *testing.T) {
+		var e *Evaluator
+		require.NoError(t, e.Evaluate(context.Background(), authorizeSession()))
+		require.Nil(t, FromContext(context.Background()))
+	})
+}
+
+func TestNewEvaluator(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		hook *Hook
+	}{
+		{name: "nil-hook"},
+		{name: "missing-url", hook: &Hook{}},
+		{name: "unknown-scope", hook: &Hook{Url: "http://localhost", Scope: "some"}},
+		{name: "unknown-failure-policy", hook: &Hook{Url: "http://localhost", FailurePolicy: "ajar"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEvaluator(tt.hook, nil, hclog.NewNullLogger())
+			assert.True(t, errors.Is(err, errors.ErrInvalidParameter))
+		})
+	}
+}
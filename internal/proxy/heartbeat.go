@@ -0,0 +1,52 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+const (
+	// DefaultHeartbeatInterval is how often the client and the worker ping
+	// each other over a proxied connection when no interval is configured.
+	DefaultHeartbeatInterval = 15 * time.Second
+
+	// DefaultTcpKeepAlive is the TCP keepalive period of proxied connections
+	// when none is configured.
+	DefaultTcpKeepAlive = 15 * time.Second
+)
+
+// Heartbeat pings the peer of conn every interval until ctx is done, so
+// connections whose peer went away without closing them are detected. It
+// returns an error once a ping is not answered within interval, in which case
+// the connection should be closed; it returns nil when ctx is done.
+//
+// Pongs are read by the readers of conn, so conn must be read concurrently.
+// An interval <= 0 disables the heartbeat and Heartbeat blocks until ctx is
+// done.
+func Heartbeat(ctx context.Context, conn *websocket.Conn, interval time.Duration) error {
+	if interval <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		pingCtx, cancel := context.WithTimeout(ctx, interval)
+		err := conn.Ping(pingCtx)
+		cancel()
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			return fmt.Errorf("peer did not answer heartbeat within %s: %w", interval, err)
+		}
+	}
+}
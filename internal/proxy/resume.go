@@ -0,0 +1,478 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+const (
+	// DefaultResumeGracePeriod is how long a worker keeps the endpoint
+	// connection of a resumable connection whose client went away open for
+	// the client to resume it, when no grace period is configured.
+	DefaultResumeGracePeriod = 30 * time.Second
+
+	// ResumeConnectionHeader and ResumeReceivedHeader are the headers of the
+	// requests of clients resuming a connection: the id of the connection and
+	// the number of bytes the client received over it.
+	ResumeConnectionHeader = "X-Boundary-Resume-Connection"
+	ResumeReceivedHeader   = "X-Boundary-Resume-Received"
+
+	// resumeWindow is the most bytes a side of a resumable connection sends
+	// without the peer acknowledging them, which it keeps to resend them on
+	// resumption.
+	resumeWindow = 4 << 20
+
+	// resumeChunkSize is the largest message a side of a resumable connection
+	// sends.
+	resumeChunkSize = 32 << 10
+
+	// resumeAckThreshold is how many bytes a side of a resumable connection
+	// reads before acknowledging them. It is below resumeWindow -
+	// resumeChunkSize, so a sender waiting for the window to open always gets
+	// an acknowledgement.
+	resumeAckThreshold = resumeWindow / 16
+)
+
+// ErrCannotResume indicates that a resumable connection can't be resumed,
+// because it is closed or the bytes the peer missed aren't kept anymore.
+var ErrCannotResume = errors.New("connection cannot be resumed")
+
+// ResumeHello is sent by workers as a text message after the handshake result
+// of a resumable connection: the id of the connection to resume it with, the
+// number of bytes the worker received over it, zero for a new connection, and
+// how long the worker waits for the client to resume it.
+type ResumeHello struct {
+	ConnectionId       string `json:"connection_id"`
+	Received           uint64 `json:"received"`
+	GracePeriodSeconds uint32 `json:"grace_period_seconds"`
+}
+
+// WriteResumeHello sends the hello of a resumable connection.
+func WriteResumeHello(ctx context.Context, ws *websocket.Conn, hello *ResumeHello) error {
+	return wsjson.Write(ctx, ws, hello)
+}
+
+// ReadResumeHello reads the hello of a resumable connection.
+func ReadResumeHello(ctx context.Context, ws *websocket.Conn) (*ResumeHello, error) {
+	var hello ResumeHello
+	if err := wsjson.Read(ctx, ws, &hello); err != nil {
+		return nil, err
+	}
+	return &hello, nil
+}
+
+// resumeAck acknowledges the bytes read from a resumable connection. It is
+// sent as a text message; the bytes proxied are sent as binary messages.
+type resumeAck struct {
+	Ack uint64 `json:"ack"`
+}
+
+// ResumableConn is a proxied connection over websockets which survives the
+// loss of its websocket: both sides keep the bytes they sent until the peer
+// acknowledges them, and once the client reconnects with Resume the bytes the
+// peer missed are sent again, so the client's tool and the endpoint see an
+// uninterrupted stream. A connection not resumed within its grace period is
+// closed.
+type ResumableConn struct {
+	ctx       context.Context
+	heartbeat time.Duration
+	grace     time.Duration
+	onBreak   func(error)
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// ws is the websocket of the current generation, nil while the
+	// connection waits to be resumed
+	ws      *websocket.Conn
+	gen     uint64
+	genDone chan struct{}
+	closed  bool
+	done    chan struct{}
+	// sent counts the bytes written, unacked holds those from acked to sent
+	// and wire is the offset of the next byte to send over ws
+	sent    uint64
+	acked   uint64
+	wire    uint64
+	unacked []byte
+	// received counts the bytes received, readBuf holds those not read yet
+	// and lastAck is the last count of bytes read acknowledged
+	received uint64
+	readBuf  []byte
+	lastAck  uint64
+}
+
+// NewResumableConn returns a resumable connection over ws. The websocket is
+// pinged every heartbeat interval. Once it is lost onBreak is called, if set,
+// and the connection is closed unless it is resumed within grace.
+func NewResumableConn(ctx context.Context, ws *websocket.Conn, heartbeat, grace time.Duration, onBreak func(error)) *ResumableConn {
+	c := &ResumableConn{
+		ctx:       ctx,
+		heartbeat: heartbeat,
+		grace:     grace,
+		onBreak:   onBreak,
+		done:      make(chan struct{}),
+	}
+	c.cond = sync.NewCond(&c.mu)
+	c.mu.Lock()
+	c.attach(ws)
+	c.mu.Unlock()
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-c.done:
+		}
+	}()
+	return c
+}
+
+// markClosed closes the connection. c.mu must be held.
+func (c *ResumableConn) markClosed() {
+	c.closed = true
+	c.ws = nil
+	close(c.done)
+	c.cond.Broadcast()
+}
+
+// attach makes ws the websocket of a new generation of the connection. c.mu
+// must be held.
+func (c *ResumableConn) attach(ws *websocket.Conn) {
+	c.ws = ws
+	c.gen++
+	c.genDone = make(chan struct{})
+	c.wire = c.acked
+	gen, done := c.gen, c.genDone
+	wsCtx, cancel := context.WithCancel(c.ctx)
+	go func() {
+		defer close(done)
+		defer cancel()
+		c.readLoop(wsCtx, ws, gen)
+	}()
+	go c.sendLoop(wsCtx, ws, gen)
+	go func() {
+		if err := Heartbeat(wsCtx, ws, c.heartbeat); err != nil {
+			ws.Close(websocket.StatusGoingAway, "heartbeat timed out")
+		}
+	}()
+}
+
+// readLoop reads the messages of the websocket of a generation until it is
+// lost or closed. The connection is closed if the peer sends more than
+// resumeWindow bytes which weren't acknowledged.
+func (c *ResumableConn) readLoop(ctx context.Context, ws *websocket.Conn, gen uint64) {
+	for {
+		typ, b, err := ws.Read(ctx)
+		if err != nil {
+			c.detach(gen, err)
+			return
+		}
+		c.mu.Lock()
+		if c.gen != gen {
+			// The connection was resumed over another websocket
+			c.mu.Unlock()
+			return
+		}
+		switch typ {
+		case websocket.MessageBinary:
+			if c.received+uint64(len(b))-c.lastAck > resumeWindow {
+				// A peer ignoring the window would make the bytes not read
+				// yet grow without bound
+				c.markClosed()
+				c.mu.Unlock()
+				ws.Close(websocket.StatusPolicyViolation, "acknowledgement window exceeded")
+				return
+			}
+			c.readBuf = append(c.readBuf, b...)
+			c.received += uint64(len(b))
+		case websocket.MessageText:
+			var ack resumeAck
+			if err := json.Unmarshal(b, &ack); err != nil {
+				c.mu.Unlock()
+				ws.Close(websocket.StatusUnsupportedData, "invalid acknowledgement")
+				c.detach(gen, fmt.Errorf("invalid acknowledgement: %w", err))
+				return
+			}
+			c.ackTo(ack.Ack)
+		}
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	}
+}
+
+// sendLoop sends the bytes written and the acknowledgements of the bytes read
+// over the websocket of a generation until it is replaced or lost.
+func (c *ResumableConn) sendLoop(ctx context.Context, ws *websocket.Conn, gen uint64) {
+	for {
+		c.mu.Lock()
+		for c.gen == gen && c.ws != nil && c.wire == c.sent && c.consumed()-c.lastAck < resumeAckThreshold {
+			c.cond.Wait()
+		}
+		if c.gen != gen || c.ws == nil {
+			c.mu.Unlock()
+			return
+		}
+		var typ websocket.MessageType
+		var b []byte
+		var ackTo, wireTo uint64
+		if consumed := c.consumed(); consumed-c.lastAck >= resumeAckThreshold {
+			typ, ackTo = websocket.MessageText, consumed
+			b, _ = json.Marshal(&resumeAck{Ack: consumed})
+		} else {
+			start := c.wire - c.acked
+			end := start + resumeChunkSize
+			if end > uint64(len(c.unacked)) {
+				end = uint64(len(c.unacked))
+			}
+			typ = websocket.MessageBinary
+			b = append([]byte(nil), c.unacked[start:end]...)
+			wireTo = c.wire + uint64(len(b))
+		}
+		c.mu.Unlock()
+
+		if err := ws.Write(ctx, typ, b); err != nil {
+			// The read loop detaches the websocket once it is closed
+			ws.Close(websocket.StatusGoingAway, "write failed")
+			return
+		}
+
+		c.mu.Lock()
+		if c.gen == gen {
+			if typ == websocket.MessageText {
+				c.lastAck = ackTo
+			} else if c.wire < wireTo {
+				// An acknowledgement of the bytes may have moved wire already
+				c.wire = wireTo
+			}
+			c.cond.Broadcast()
+		}
+		c.mu.Unlock()
+	}
+}
+
+// consumed returns the number of bytes read. c.mu must be held.
+func (c *ResumableConn) consumed() uint64 {
+	return c.received - uint64(len(c.readBuf))
+}
+
+// ackTo drops the bytes up to n, which the peer received. c.mu must be held.
+func (c *ResumableConn) ackTo(n uint64) {
+	if n <= c.acked || n > c.sent {
+		return
+	}
+	c.unacked = c.unacked[n-c.acked:]
+	c.acked = n
+	if c.wire < n {
+		c.wire = n
+	}
+}
+
+// detach handles the loss of the websocket of a generation. A normal closure
+// by the peer closes the connection; any other loss waits for it to be
+// resumed within the grace period.
+func (c *ResumableConn) detach(gen uint64, err error) {
+	c.mu.Lock()
+	if c.closed || c.gen != gen || c.ws == nil {
+		c.mu.Unlock()
+		return
+	}
+	if websocket.CloseStatus(err) == websocket.StatusNormalClosure || c.grace <= 0 {
+		c.markClosed()
+		c.mu.Unlock()
+		return
+	}
+	c.ws = nil
+	c.cond.Broadcast()
+	c.expireUnlessResumed(gen)
+	c.mu.Unlock()
+
+	if c.onBreak != nil {
+		c.onBreak(err)
+	}
+}
+
+// expireUnlessResumed closes the connection if it is still at generation gen
+// once the grace period is over. c.mu must be held.
+func (c *ResumableConn) expireUnlessResumed(gen uint64) {
+	if c.grace <= 0 {
+		return
+	}
+	time.AfterFunc(c.grace, func() {
+		c.mu.Lock()
+		if !c.closed && c.gen == gen {
+			c.markClosed()
+		}
+		c.mu.Unlock()
+	})
+}
+
+// checkResume returns an error unless the connection can be resumed by a
+// peer which received peerReceived bytes. c.mu must be held.
+func (c *ResumableConn) checkResume(peerReceived uint64) error {
+	switch {
+	case c.closed:
+		return fmt.Errorf("%w: connection is closed", ErrCannotResume)
+	case peerReceived < c.acked || peerReceived > c.sent:
+		return fmt.Errorf("%w: peer received %d bytes but bytes %d to %d are kept", ErrCannotResume, peerReceived, c.acked, c.sent)
+	}
+	return nil
+}
+
+// Resume continues the connection over ws, sending again the bytes after the
+// first peerReceived, which the peer received. If hello is set it is called
+// with the number of bytes received before ws is used, e.g. to send a
+// ResumeHello. It returns a channel closed once ws is lost or replaced, or
+// ErrCannotResume.
+func (c *ResumableConn) Resume(ws *websocket.Conn, peerReceived uint64, hello func(received uint64) error) (<-chan struct{}, error) {
+	c.mu.Lock()
+	if err := c.checkResume(peerReceived); err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	if old := c.ws; old != nil {
+		// The peer noticed the loss of the websocket first
+		go old.Close(websocket.StatusGoingAway, "connection resumed")
+	}
+	// A new generation without a websocket stops the current one, so no more
+	// bytes are received until ws is attached
+	c.gen++
+	c.ws = nil
+	c.expireUnlessResumed(c.gen)
+	gen, received := c.gen, c.received
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	if hello != nil {
+		if err := hello(received); err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != gen {
+		return nil, fmt.Errorf("%w: connection resumed concurrently", ErrCannotResume)
+	}
+	if err := c.checkResume(peerReceived); err != nil {
+		return nil, err
+	}
+	c.ackTo(peerReceived)
+	c.attach(ws)
+	c.cond.Broadcast()
+	return c.genDone, nil
+}
+
+// Received returns the number of bytes received, which the peer resumes
+// sending after.
+func (c *ResumableConn) Received() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.received
+}
+
+// Closed returns true once the connection is closed.
+func (c *ResumableConn) Closed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// Read reads the bytes received. It waits while the connection is being
+// resumed, and returns io.EOF once it is closed.
+func (c *ResumableConn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.readBuf) == 0 && !c.closed {
+		c.cond.Wait()
+	}
+	if len(c.readBuf) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, c.readBuf)
+	c.readBuf = c.readBuf[n:]
+	if c.consumed()-c.lastAck >= resumeAckThreshold {
+		c.cond.Broadcast()
+	}
+	return n, nil
+}
+
+// Write queues p to be sent. It waits while resumeWindow bytes aren't
+// acknowledged by the peer.
+func (c *ResumableConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var n int
+	for n < len(p) {
+		for !c.closed && len(c.unacked) >= resumeWindow {
+			c.cond.Wait()
+		}
+		if c.closed {
+			return n, io.ErrClosedPipe
+		}
+		chunk := p[n:]
+		if room := resumeWindow - len(c.unacked); len(chunk) > room {
+			chunk = chunk[:room]
+		}
+		c.unacked = append(c.unacked, chunk...)
+		c.sent += uint64(len(chunk))
+		n += len(chunk)
+		c.cond.Broadcast()
+	}
+	return n, nil
+}
+
+// Close sends the bytes written if the websocket is connected, then closes
+// the connection.
+func (c *ResumableConn) Close() error {
+	c.mu.Lock()
+	for !c.closed && c.ws != nil && c.wire < c.sent && c.ctx.Err() == nil {
+		c.cond.Wait()
+	}
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	ws := c.ws
+	c.markClosed()
+	c.mu.Unlock()
+	if ws != nil {
+		return ws.Close(websocket.StatusNormalClosure, "done")
+	}
+	return nil
+}
+
+// resumableAddr is the address of both ends of resumable connections, which
+// span several websockets.
+type resumableAddr struct{}
+
+func (resumableAddr) Network() string { return "websocket" }
+func (resumableAddr) String() string  { return "websocket/resumable" }
+
+// LocalAddr returns a placeholder address.
+func (c *ResumableConn) LocalAddr() net.Addr { return resumableAddr{} }
+
+// RemoteAddr returns a placeholder address.
+func (c *ResumableConn) RemoteAddr() net.Addr { return resumableAddr{} }
+
+// SetDeadline is not supported by resumable connections.
+func (c *ResumableConn) SetDeadline(time.Time) error {
+	return errors.New("deadlines are not supported by resumable connections")
+}
+
+// SetReadDeadline is not supported by resumable connections.
+func (c *ResumableConn) SetReadDeadline(time.Time) error {
+	return errors.New("deadlines are not supported by resumable connections")
+}
+
+// SetWriteDeadline is not supported by resumable connections.
+func (c *ResumableConn) SetWriteDeadline(time.Time) error {
+	return errors.New("deadlines are not supported by resumable connections")
+}
@@ -0,0 +1,164 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"nhooyr.io/websocket"
+)
+
+func TestResumableConn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The server echoes what it reads over a resumable connection, which
+	// clients resume by sending the number of bytes they received
+	var mu sync.Mutex
+	var server *ResumableConn
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		if received := r.Header.Get(ResumeReceivedHeader); received != "" {
+			n, err := strconv.ParseUint(received, 10, 64)
+			require.NoError(t, err)
+			mu.Lock()
+			rc := server
+			mu.Unlock()
+			done, err := rc.Resume(ws, n, func(received uint64) error {
+				return WriteResumeHello(ctx, ws, &ResumeHello{Received: received})
+			})
+			if err != nil {
+				ws.Close(websocket.StatusPolicyViolation, err.Error())
+				return
+			}
+			<-done
+			return
+		}
+		if err := WriteResumeHello(ctx, ws, &ResumeHello{ConnectionId: "c"}); err != nil {
+			return
+		}
+		rc := NewResumableConn(ctx, ws, 0, time.Minute, nil)
+		mu.Lock()
+		server = rc
+		mu.Unlock()
+		io.Copy(rc, rc)
+		rc.Close()
+	}))
+	defer srv.Close()
+
+	dial := func(h http.Header) *websocket.Conn {
+		ws, _, err := websocket.Dial(ctx, srv.URL, &websocket.DialOptions{HTTPHeader: h})
+		require.NoError(t, err)
+		return ws
+	}
+
+	assert, require := assert.New(t), require.New(t)
+	ws := dial(nil)
+	hello, err := ReadResumeHello(ctx, ws)
+	require.NoError(err)
+	assert.Equal("c", hello.ConnectionId)
+	broken := make(chan struct{}, 1)
+	client := NewResumableConn(ctx, ws, 0, time.Minute, func(error) { broken <- struct{}{} })
+
+	// More than a window of bytes, so both sides wait for acknowledgements
+	want := []byte(strings.Repeat("0123456789abcdef", resumeWindow/8))
+	go func() {
+		client.Write(want[:len(want)/2])
+		// Lose the websocket halfway
+		ws.Close(websocket.StatusGoingAway, "network lost")
+		<-broken
+		h := http.Header{}
+		h.Set(ResumeReceivedHeader, strconv.FormatUint(client.Received(), 10))
+		ws := dial(h)
+		hello, err := ReadResumeHello(ctx, ws)
+		if !assert.NoError(err) {
+			return
+		}
+		_, err = client.Resume(ws, hello.Received, nil)
+		assert.NoError(err)
+		client.Write(want[len(want)/2:])
+	}()
+
+	got := make([]byte, len(want))
+	_, err = io.ReadFull(client, got)
+	require.NoError(err)
+	assert.Equal(want, got)
+	require.NoError(client.Close())
+
+	mu.Lock()
+	rc := server
+	mu.Unlock()
+	assert.Eventually(rc.Closed, 5*time.Second, 10*time.Millisecond)
+	_, err = rc.Resume(dial(nil), 0, nil)
+	assert.True(errors.Is(err, ErrCannotResume))
+}
+
+func TestResumableConn_gracePeriod(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		ws.Close(websocket.StatusGoingAway, "going away")
+	}))
+	defer srv.Close()
+
+	ws, _, err := websocket.Dial(ctx, srv.URL, nil)
+	require.NoError(t, err)
+	rc := NewResumableConn(ctx, ws, 0, 50*time.Millisecond, nil)
+	_, err = rc.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+	assert.True(t, rc.Closed())
+}
+
+func TestResumableConn_windowExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The server never reads, so it acknowledges nothing
+	conns := make(chan *ResumableConn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		rc := NewResumableConn(ctx, ws, 0, time.Minute, nil)
+		conns <- rc
+		<-ctx.Done()
+	}))
+	defer srv.Close()
+
+	ws, _, err := websocket.Dial(ctx, srv.URL, nil)
+	require.NoError(t, err)
+	readCtx := ws.CloseRead(ctx)
+	rc := <-conns
+
+	// A peer ignoring the window keeps sending
+	chunk := make([]byte, resumeChunkSize)
+	for sent := 0; sent <= 2*resumeWindow; sent += len(chunk) {
+		if err = ws.Write(ctx, websocket.MessageBinary, chunk); err != nil {
+			break
+		}
+	}
+	<-readCtx.Done()
+	require.NoError(t, ctx.Err(), "connection not closed")
+	assert.Eventually(t, rc.Closed, 5*time.Second, 10*time.Millisecond)
+	assert.LessOrEqual(t, rc.Received(), uint64(resumeWindow))
+	err = ws.Write(ctx, websocket.MessageBinary, chunk)
+	assert.Equal(t, websocket.StatusPolicyViolation, websocket.CloseStatus(err), err)
+}
@@ -0,0 +1,94 @@
+package secretfingerprint
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// lookupQuery returns the registrations of a fingerprint, oldest first.
+const lookupQuery = `
+	select fingerprint, kind, session_id, target_id, user_id, scope_id, create_time
+		from secret_fingerprint
+	where fingerprint = ?
+	order by create_time, id`
+
+// A Repository registers and looks up the fingerprints of brokered secrets.
+type Repository struct {
+	reader db.Reader
+	writer db.Writer
+	key    []byte
+}
+
+// NewRepository creates a new Repository fingerprinting secrets with the
+// key, which must be at least MinKeyLength bytes.
+func NewRepository(r db.Reader, w db.Writer, key []byte) (*Repository, error) {
+	switch {
+	case r == nil:
+		return nil, fmt.Errorf("db.Reader: secret fingerprint: %w", errors.ErrInvalidParameter)
+	case w == nil:
+		return nil, fmt.Errorf("db.Writer: secret fingerprint: %w", errors.ErrInvalidParameter)
+	case len(key) < MinKeyLength:
+		return nil, fmt.Errorf("key shorter than %d bytes: secret fingerprint: %w", MinKeyLength, errors.ErrInvalidParameter)
+	}
+	return &Repository{
+		reader: r,
+		writer: w,
+		key:    key,
+	}, nil
+}
+
+// Fingerprint returns the fingerprint of the secret.
+func (r *Repository) Fingerprint(secret []byte) string {
+	return Fingerprint(r.key, secret)
+}
+
+// Register fingerprints the secret of the kind brokered for the session and
+// records the registration. The Fingerprint and Kind of reg are set from the
+// secret and kind.
+func (r *Repository) Register(ctx context.Context, kind Kind, secret []byte, reg *Registration) error {
+	if len(secret) == 0 {
+		return fmt.Errorf("register secret fingerprint: missing secret: %w", errors.ErrInvalidParameter)
+	}
+	if reg == nil {
+		return fmt.Errorf("register secret fingerprint: missing registration: %w", errors.ErrInvalidParameter)
+	}
+	reg.Kind = kind
+	reg.Fingerprint = r.Fingerprint(secret)
+	_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			return Enqueue(ctx, w, reg)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("register secret fingerprint: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the registrations of the fingerprint, oldest first, so a
+// leaked secret can be traced to the sessions it was brokered for.
+func (r *Repository) Lookup(ctx context.Context, fingerprint string) ([]*Registration, error) {
+	if fingerprint == "" {
+		return nil, fmt.Errorf("lookup secret fingerprint: missing fingerprint: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, lookupQuery, []interface{}{fingerprint})
+	if err != nil {
+		return nil, fmt.Errorf("lookup secret fingerprint: %w", err)
+	}
+	defer rows.Close()
+	var regs []*Registration
+	for rows.Next() {
+		reg := &Registration{SchemaVersion: SchemaVersion}
+		if err := rows.Scan(&reg.Fingerprint, &reg.Kind, &reg.SessionId, &reg.TargetId, &reg.UserId, &reg.ScopeId, &reg.Time); err != nil {
+			return nil, fmt.Errorf("lookup secret fingerprint: %w", err)
+		}
+		regs = append(regs, reg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("lookup secret fingerprint: %w", err)
+	}
+	return regs, nil
+}
@@ -0,0 +1,82 @@
+package secretfingerprint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestFingerprint(t *testing.T) {
+	assert := assert.New(t)
+	fp := Fingerprint(testKey, []byte("secret"))
+	assert.Len(fp, 64)
+	assert.Equal(fp, Fingerprint(testKey, []byte("secret")))
+	assert.NotEqual(fp, Fingerprint(testKey, []byte("other")))
+	assert.NotEqual(fp, Fingerprint([]byte("fedcba9876543210fedcba9876543210"), []byte("secret")))
+}
+
+func TestNewRepository(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	_, err := NewRepository(nil, rw, testKey)
+	assert.True(t, errors.Is(err, errors.ErrInvalidParameter))
+	_, err = NewRepository(rw, nil, testKey)
+	assert.True(t, errors.Is(err, errors.ErrInvalidParameter))
+	_, err = NewRepository(rw, rw, []byte("short"))
+	assert.True(t, errors.Is(err, errors.ErrInvalidParameter))
+}
+
+func TestRepository_RegisterLookup(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	repo, err := NewRepository(rw, rw, testKey)
+	require.NoError(err)
+	ctx := context.Background()
+
+	secret := []byte("brokered secret")
+	require.NoError(repo.Register(ctx, SessionPrivateKey, secret, &Registration{
+		SessionId: "s_1234567890",
+		TargetId:  "ttcp_1234567890",
+		UserId:    "u_1234567890",
+		ScopeId:   "p_1234567890",
+	}))
+
+	got, err := repo.Lookup(ctx, Fingerprint(testKey, secret))
+	require.NoError(err)
+	require.Len(got, 1)
+	assert.Equal(SessionPrivateKey, got[0].Kind)
+	assert.Equal("s_1234567890", got[0].SessionId)
+	assert.Equal("ttcp_1234567890", got[0].TargetId)
+	assert.Equal("u_1234567890", got[0].UserId)
+	assert.Equal("p_1234567890", got[0].ScopeId)
+
+	// The registration is enqueued for external scanning services
+	rows, err := rw.Query(ctx, "select count(*) from outbox_message where kind = $1", []interface{}{OutboxKind})
+	require.NoError(err)
+	var count int
+	for rows.Next() {
+		require.NoError(rows.Scan(&count))
+	}
+	rows.Close()
+	assert.Equal(1, count)
+
+	got, err = repo.Lookup(ctx, repo.Fingerprint([]byte("other secret")))
+	require.NoError(err)
+	assert.Empty(got)
+
+	err = repo.Register(ctx, Kind("password"), secret, &Registration{SessionId: "s_1234567890"})
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	err = repo.Register(ctx, SessionPrivateKey, nil, &Registration{SessionId: "s_1234567890"})
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	err = repo.Register(ctx, SessionPrivateKey, secret, &Registration{})
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+}
@@ -0,0 +1,114 @@
+// Package secretfingerprint registers fingerprints of the secrets brokered
+// to clients, so a leaked secret found by a scanning service can be traced to
+// the session it was brokered for. A fingerprint is the hex encoded
+// HMAC-SHA256 of the secret keyed with a key shared with the scanning
+// services, so the secret itself is never stored or sent. Each registration
+// is stored to be looked up by fingerprint and enqueued in the outbox, as a
+// message of OutboxKind, for external scanning services.
+package secretfingerprint
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/outbox"
+)
+
+// OutboxKind is the outbox message kind of registrations. The payload is a
+// Registration.
+const OutboxKind = "session.secret_fingerprint"
+
+// SchemaVersion is the version of the schema of the payloads of OutboxKind
+// messages.
+const SchemaVersion = 1
+
+// MinKeyLength is the minimum length in bytes of the fingerprint key.
+const MinKeyLength = 16
+
+// A Kind is a kind of brokered secret.
+type Kind string
+
+const (
+	// SessionPrivateKey is the private key of the certificate of a session,
+	// which clients present to workers to proxy connections.
+	SessionPrivateKey Kind = "session_private_key"
+)
+
+// Kinds are all kinds of brokered secrets.
+var Kinds = []Kind{SessionPrivateKey}
+
+// A Registration is the fingerprint of a secret brokered to the user of a
+// session of a target.
+type Registration struct {
+	SchemaVersion int       `json:"schema_version"`
+	Fingerprint   string    `json:"fingerprint"`
+	Kind          Kind      `json:"kind"`
+	SessionId     string    `json:"session_id"`
+	TargetId      string    `json:"target_id"`
+	UserId        string    `json:"user_id"`
+	ScopeId       string    `json:"scope_id"`
+	Time          time.Time `json:"time"`
+}
+
+// Fingerprint returns the hex encoded HMAC-SHA256 of the secret keyed with
+// the key.
+func Fingerprint(key, secret []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(secret)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Enqueue stores the registration and enqueues it in the outbox. w must be
+// the writer of the transaction brokering the secret, so the registration is
+// only recorded if the transaction commits. Time is set to the current time
+// if it is zero.
+func Enqueue(ctx context.Context, w db.Writer, r *Registration) error {
+	if w == nil {
+		return fmt.Errorf("enqueue secret fingerprint: missing writer: %w", errors.ErrInvalidParameter)
+	}
+	if r == nil {
+		return fmt.Errorf("enqueue secret fingerprint: missing registration: %w", errors.ErrInvalidParameter)
+	}
+	if len(r.Fingerprint) != hex.EncodedLen(sha256.Size) {
+		return fmt.Errorf("enqueue secret fingerprint: invalid fingerprint: %w", errors.ErrInvalidParameter)
+	}
+	if !validKind(r.Kind) {
+		return fmt.Errorf("enqueue secret fingerprint: unknown kind %q: %w", r.Kind, errors.ErrInvalidParameter)
+	}
+	if r.SessionId == "" {
+		return fmt.Errorf("enqueue secret fingerprint: missing session id: %w", errors.ErrInvalidParameter)
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	r.SchemaVersion = SchemaVersion
+	if _, err := w.Exec(ctx,
+		"insert into secret_fingerprint (fingerprint, kind, session_id, target_id, user_id, scope_id, create_time) values (?, ?, ?, ?, ?, ?, ?)",
+		[]interface{}{r.Fingerprint, string(r.Kind), r.SessionId, r.TargetId, r.UserId, r.ScopeId, r.Time}); err != nil {
+		return fmt.Errorf("enqueue secret fingerprint: %w", err)
+	}
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("enqueue secret fingerprint: %w", err)
+	}
+	if err := outbox.Enqueue(ctx, w, OutboxKind, payload); err != nil {
+		return fmt.Errorf("enqueue secret fingerprint: %w", err)
+	}
+	return nil
+}
+
+func validKind(k Kind) bool {
+	for _, v := range Kinds {
+		if k == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package securityevent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// A Bucket is the length of the time buckets security events are counted in.
+type Bucket string
+
+const (
+	BucketHour Bucket = "hour"
+	BucketDay  Bucket = "day"
+)
+
+const (
+	// rollupQuery moves the stored security events into their hourly counts.
+	rollupQuery = `
+	with rolled as (
+		delete from security_event
+		returning kind, scope_id, auth_method_id, create_time
+	)
+	insert into security_event_rollup (bucket_time, kind, scope_id, auth_method_id, event_count)
+	select date_trunc('hour', create_time at time zone 'utc') at time zone 'utc', kind, scope_id, auth_method_id, count(*)
+		from rolled
+	group by 1, 2, 3, 4
+	on conflict (bucket_time, kind, scope_id, auth_method_id) do update
+		set event_count = security_event_rollup.event_count + excluded.event_count`
+
+	// listCountsQuery counts the security events of scopes in a time range by
+	// time bucket, scope, auth method and kind, including the events not
+	// rolled up yet.
+	listCountsQuery = `
+	select date_trunc(?, e.bucket_time at time zone 'utc') at time zone 'utc', e.scope_id, e.auth_method_id, e.kind, sum(e.event_count)
+		from (
+			select bucket_time, scope_id, auth_method_id, kind, event_count
+				from security_event_rollup
+			where bucket_time >= ? and bucket_time < ?
+			union all
+			select create_time, scope_id, auth_method_id, kind, 1
+				from security_event
+			where create_time >= ? and create_time < ?
+		) e
+	where e.scope_id in (?)
+	group by 1, 2, 3, 4
+	order by 1, 2, 3, 4`
+)
+
+// A Repository stores and counts security events.
+type Repository struct {
+	reader db.Reader
+	writer db.Writer
+}
+
+// NewRepository creates a new Repository.
+func NewRepository(r db.Reader, w db.Writer) (*Repository, error) {
+	switch {
+	case r == nil:
+		return nil, fmt.Errorf("db.Reader: security event: %w", errors.ErrInvalidParameter)
+	case w == nil:
+		return nil, fmt.Errorf("db.Writer: security event: %w", errors.ErrInvalidParameter)
+	}
+	return &Repository{
+		reader: r,
+		writer: w,
+	}, nil
+}
+
+// Record stores the event and enqueues it in the outbox, for events which
+// are not about a change made in a transaction, such as failed
+// authentications.
+func (r *Repository) Record(ctx context.Context, e *Event) error {
+	_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			return Enqueue(ctx, w, e)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("record security event: %w", err)
+	}
+	return nil
+}
+
+// Rollup moves the stored security events into their hourly counts and
+// deletes the counts of the hours older than retention, if it is set. It
+// returns the number of hourly counts updated.
+func (r *Repository) Rollup(ctx context.Context, retention time.Duration) (int, error) {
+	var rolled int
+	_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			var err error
+			if rolled, err = w.Exec(ctx, rollupQuery, nil); err != nil {
+				return err
+			}
+			if retention > 0 {
+				if _, err := w.Exec(ctx,
+					"delete from security_event_rollup where bucket_time < ?",
+					[]interface{}{time.Now().Add(-retention)}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("rollup security events: %w", err)
+	}
+	return rolled, nil
+}
+
+// A Count is the number of security events of a kind of a scope and auth
+// method in the time bucket starting at BucketTime. AuthMethodId is empty for
+// events not about an auth method.
+type Count struct {
+	BucketTime   time.Time
+	ScopeId      string
+	AuthMethodId string
+	Kind         Kind
+	Count        uint64
+}
+
+// ListCounts returns the counts of the security events of the scopes from
+// start until end in time buckets of the bucket length, ordered by time,
+// scope, auth method and kind. Buckets are aligned to UTC and without events
+// are omitted. start is rounded down to the hour.
+func (r *Repository) ListCounts(ctx context.Context, scopeIds []string, start, end time.Time, bucket Bucket) ([]*Count, error) {
+	if len(scopeIds) == 0 {
+		return nil, fmt.Errorf("list security event counts: missing scope ids: %w", errors.ErrInvalidParameter)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("list security event counts: end not after start: %w", errors.ErrInvalidParameter)
+	}
+	switch bucket {
+	case BucketHour, BucketDay:
+	default:
+		return nil, fmt.Errorf("list security event counts: unknown bucket %q: %w", bucket, errors.ErrInvalidParameter)
+	}
+	start = start.UTC().Truncate(time.Hour)
+	rows, err := r.reader.Query(ctx, listCountsQuery, []interface{}{string(bucket), start, end, start, end, scopeIds})
+	if err != nil {
+		return nil, fmt.Errorf("list security event counts: %w", err)
+	}
+	defer rows.Close()
+	var counts []*Count
+	for rows.Next() {
+		var c Count
+		if err := rows.Scan(&c.BucketTime, &c.ScopeId, &c.AuthMethodId, &c.Kind, &c.Count); err != nil {
+			return nil, fmt.Errorf("list security event counts: %w", err)
+		}
+		counts = append(counts, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list security event counts: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,86 @@
+package securityevent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_Counts(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	repo, err := NewRepository(rw, rw)
+	require.NoError(err)
+	ctx := context.Background()
+	orgId := "o_1234567890"
+
+	hour := time.Now().UTC().Truncate(time.Hour)
+	record := func(kind Kind, scopeId, authMethodId string, at time.Time) {
+		require.NoError(repo.Record(ctx, &Event{Kind: kind, ScopeId: scopeId, AuthMethodId: authMethodId, Time: at}))
+	}
+	record(AuthenticationFailed, orgId, "ampw_1234567890", hour.Add(-2*time.Hour+time.Minute))
+	record(AuthenticationFailed, orgId, "ampw_1234567890", hour.Add(-2*time.Hour+2*time.Minute))
+	record(AuthenticationSucceeded, orgId, "ampw_1234567890", hour.Add(-time.Hour+time.Minute))
+	record(RecoveryKmsUsed, "global", "", hour.Add(-time.Hour+time.Minute))
+
+	// The counts are the same before and after the events are rolled up
+	want := []*Count{
+		{BucketTime: hour.Add(-2 * time.Hour), ScopeId: orgId, AuthMethodId: "ampw_1234567890", Kind: AuthenticationFailed, Count: 2},
+		{BucketTime: hour.Add(-time.Hour), ScopeId: orgId, AuthMethodId: "ampw_1234567890", Kind: AuthenticationSucceeded, Count: 1},
+	}
+	assertCounts := func(want []*Count, scopeIds []string, bucket Bucket) {
+		t.Helper()
+		got, err := repo.ListCounts(ctx, scopeIds, hour.Add(-3*time.Hour), hour.Add(time.Hour), bucket)
+		require.NoError(err)
+		for _, c := range got {
+			c.BucketTime = c.BucketTime.UTC()
+		}
+		assert.Equal(want, got)
+	}
+	assertCounts(want, []string{orgId}, BucketHour)
+
+	rolled, err := repo.Rollup(ctx, 0)
+	require.NoError(err)
+	assert.Equal(3, rolled)
+	assertCounts(want, []string{orgId}, BucketHour)
+
+	// Events recorded after a rollup are added to the counts
+	record(AuthenticationFailed, orgId, "ampw_1234567890", hour.Add(-2*time.Hour+3*time.Minute))
+	want[0].Count = 3
+	assertCounts(want, []string{orgId}, BucketHour)
+	_, err = repo.Rollup(ctx, 0)
+	require.NoError(err)
+	assertCounts(want, []string{orgId}, BucketHour)
+
+	day := hour.Add(-2 * time.Hour).Truncate(24 * time.Hour)
+	if day.Equal(hour.Add(-time.Hour).Truncate(24 * time.Hour)) {
+		assertCounts([]*Count{
+			{BucketTime: day, ScopeId: orgId, AuthMethodId: "ampw_1234567890", Kind: AuthenticationFailed, Count: 3},
+			{BucketTime: day, ScopeId: orgId, AuthMethodId: "ampw_1234567890", Kind: AuthenticationSucceeded, Count: 1},
+		}, []string{orgId}, BucketDay)
+	}
+	assertCounts([]*Count{
+		{BucketTime: hour.Add(-time.Hour), ScopeId: "global", Kind: RecoveryKmsUsed, Count: 1},
+	}, []string{"global"}, BucketHour)
+
+	// Counts older than the retention are deleted
+	_, err = repo.Rollup(ctx, time.Since(hour.Add(-90*time.Minute)))
+	require.NoError(err)
+	assertCounts(want[1:], []string{orgId}, BucketHour)
+
+	_, err = repo.ListCounts(ctx, nil, hour, hour.Add(time.Hour), BucketHour)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	_, err = repo.ListCounts(ctx, []string{orgId}, hour, hour, BucketHour)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	_, err = repo.ListCounts(ctx, []string{orgId}, hour, hour.Add(time.Hour), "week")
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	err = repo.Record(ctx, &Event{Kind: "unknown", ScopeId: orgId})
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+}
@@ -0,0 +1,107 @@
+// Package securityevent records the security relevant events of
+// authentication: successes and failures, lockouts of accounts and users,
+// auth token revocations and uses of the recovery KMS. Each event is stored
+// to be counted by the security events API and enqueued in the outbox, as a
+// message of OutboxKind, for external consumers. The controllers periodically
+// roll the stored events up into hourly counts per scope and auth method.
+package securityevent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/outbox"
+)
+
+// OutboxKind is the outbox message kind of security events. The payload is
+// an Event.
+const OutboxKind = "auth.security_event"
+
+// SchemaVersion is the version of the schema of the payloads of OutboxKind
+// messages.
+const SchemaVersion = 1
+
+// A Kind is a kind of security event.
+type Kind string
+
+const (
+	// AuthenticationSucceeded is an authentication which returned an auth
+	// token.
+	AuthenticationSucceeded Kind = "authentication_succeeded"
+
+	// AuthenticationFailed is an authentication refused for wrong
+	// credentials or a disabled account or user.
+	AuthenticationFailed Kind = "authentication_failed"
+
+	// Lockout is the disabling of an account or a user, by an administrator
+	// or for being inactive.
+	Lockout Kind = "lockout"
+
+	// AuthTokenRevoked is the deletion of an auth token before it expired.
+	AuthTokenRevoked Kind = "auth_token_revoked"
+
+	// RecoveryKmsUsed is a request authorized with the recovery KMS.
+	RecoveryKmsUsed Kind = "recovery_kms_used"
+)
+
+// Kinds are all kinds of security events.
+var Kinds = []Kind{AuthenticationSucceeded, AuthenticationFailed, Lockout, AuthTokenRevoked, RecoveryKmsUsed}
+
+// An Event is a security event of a scope, and of one of its auth methods if
+// AuthMethodId is set.
+type Event struct {
+	SchemaVersion int       `json:"schema_version"`
+	Kind          Kind      `json:"kind"`
+	ScopeId       string    `json:"scope_id"`
+	AuthMethodId  string    `json:"auth_method_id,omitempty"`
+	Time          time.Time `json:"time"`
+}
+
+// Enqueue stores the event and enqueues it in the outbox. w must be the
+// writer of the transaction making the change the event is about, so the
+// event is only recorded if the transaction commits. Time is set to the
+// current time if it is zero.
+func Enqueue(ctx context.Context, w db.Writer, e *Event) error {
+	if w == nil {
+		return fmt.Errorf("enqueue security event: missing writer: %w", errors.ErrInvalidParameter)
+	}
+	if e == nil {
+		return fmt.Errorf("enqueue security event: missing event: %w", errors.ErrInvalidParameter)
+	}
+	if e.ScopeId == "" {
+		return fmt.Errorf("enqueue security event: missing scope id: %w", errors.ErrInvalidParameter)
+	}
+	if !validKind(e.Kind) {
+		return fmt.Errorf("enqueue security event: unknown kind %q: %w", e.Kind, errors.ErrInvalidParameter)
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.SchemaVersion = SchemaVersion
+	if _, err := w.Exec(ctx,
+		"insert into security_event (kind, scope_id, auth_method_id, create_time) values (?, ?, ?, ?)",
+		[]interface{}{string(e.Kind), e.ScopeId, e.AuthMethodId, e.Time}); err != nil {
+		return fmt.Errorf("enqueue security event: %w", err)
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("enqueue security event: %w", err)
+	}
+	if err := outbox.Enqueue(ctx, w, OutboxKind, payload); err != nil {
+		return fmt.Errorf("enqueue security event: %w", err)
+	}
+	return nil
+}
+
+func validKind(k Kind) bool {
+	for _, v := range Kinds {
+		if k == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/outbox"
+)
+
+// ConfigDivergenceKind is the outbox message kind of the records of
+// controllers whose security relevant configuration differs from that of a
+// peer. The payload is a ConfigDivergence.
+const ConfigDivergenceKind = "controller.config_divergence"
+
+// ConfigDivergenceSchemaVersion is the version of the schema of the payloads
+// of ConfigDivergenceKind messages.
+const ConfigDivergenceSchemaVersion = 1
+
+// ConfigFingerprint is the fingerprint of the security relevant configuration
+// of a controller. Sections are the fingerprints of its sections by name, and
+// Fingerprint is the fingerprint of all of them.
+type ConfigFingerprint struct {
+	Controller  string
+	Fingerprint string
+	Sections    map[string]string
+	UpdateTime  time.Time
+}
+
+// DivergentSections returns the names of the sections whose fingerprints
+// differ between f and peer, sorted, including those only one of them has.
+func (f *ConfigFingerprint) DivergentSections(peer *ConfigFingerprint) []string {
+	var ret []string
+	for name, fp := range f.Sections {
+		if peer.Sections[name] != fp {
+			ret = append(ret, name)
+		}
+	}
+	for name := range peer.Sections {
+		if _, ok := f.Sections[name]; !ok {
+			ret = append(ret, name)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// ConfigDivergence records that the configuration of a controller differs
+// from that of a peer in the Sections, as detected by the controller.
+type ConfigDivergence struct {
+	SchemaVersion   int       `json:"schema_version"`
+	Controller      string    `json:"controller"`
+	Peer            string    `json:"peer"`
+	Fingerprint     string    `json:"fingerprint"`
+	PeerFingerprint string    `json:"peer_fingerprint"`
+	Sections        []string  `json:"sections"`
+	DetectTime      time.Time `json:"detect_time"`
+}
+
+// configFingerprint is the stored form of a ConfigFingerprint.
+type configFingerprint struct {
+	ControllerId string               `gorm:"primary_key"`
+	Fingerprint  string               `gorm:"not_null"`
+	Sections     string               `gorm:"not_null"`
+	CreateTime   *timestamp.Timestamp `gorm:"default:current_timestamp"`
+	UpdateTime   *timestamp.Timestamp `gorm:"default:current_timestamp"`
+}
+
+func (f *configFingerprint) TableName() string {
+	return "server_config_fingerprint"
+}
+
+// UpsertConfigFingerprint records the fingerprint of the configuration of a
+// controller. The controller must have upserted itself.
+func (r *Repository) UpsertConfigFingerprint(ctx context.Context, fp *ConfigFingerprint, opt ...Option) error {
+	if fp == nil {
+		return errors.New("cannot upsert nil config fingerprint")
+	}
+	if fp.Controller == "" {
+		return errors.New("cannot upsert config fingerprint with empty controller name")
+	}
+	if fp.Fingerprint == "" {
+		return errors.New("cannot upsert empty config fingerprint")
+	}
+	sections, err := json.Marshal(fp.Sections)
+	if err != nil {
+		return fmt.Errorf("error marshaling config fingerprint sections: %w", err)
+	}
+	q := `
+	insert into server_config_fingerprint
+		(controller_id, fingerprint, sections)
+	values
+		($1, $2, $3)
+	on conflict on constraint server_config_fingerprint_pkey
+	do update set
+		fingerprint = $2,
+		sections = $3,
+		update_time = current_timestamp;
+	`
+	if _, err := r.writer.Exec(ctx, q, []interface{}{fp.Controller, fp.Fingerprint, string(sections)}); err != nil {
+		return fmt.Errorf("error performing config fingerprint upsert: %w", err)
+	}
+	return nil
+}
+
+// ListConfigFingerprints returns the fingerprints of the configuration of the
+// controllers recorded within the liveness period, set by WithLiveness.
+func (r *Repository) ListConfigFingerprints(ctx context.Context, opt ...Option) ([]*ConfigFingerprint, error) {
+	opts := getOpts(opt...)
+	liveness := opts.withLiveness
+	if liveness == 0 {
+		liveness = defaultLiveness
+	}
+	updateTime := time.Now().Add(-1 * liveness)
+	var fps []*configFingerprint
+	if err := r.reader.SearchWhere(
+		ctx,
+		&fps,
+		"update_time > $1",
+		[]interface{}{updateTime.Format(time.RFC3339)},
+		db.WithLimit(-1),
+	); err != nil {
+		return nil, fmt.Errorf("error listing config fingerprints: %w", err)
+	}
+	ret := make([]*ConfigFingerprint, 0, len(fps))
+	for _, f := range fps {
+		fp := &ConfigFingerprint{
+			Controller:  f.ControllerId,
+			Fingerprint: f.Fingerprint,
+		}
+		if err := json.Unmarshal([]byte(f.Sections), &fp.Sections); err != nil {
+			return nil, fmt.Errorf("error unmarshaling config fingerprint sections of %s: %w", f.ControllerId, err)
+		}
+		if f.UpdateTime != nil {
+			fp.UpdateTime = f.UpdateTime.GetTimestamp().AsTime()
+		}
+		ret = append(ret, fp)
+	}
+	return ret, nil
+}
+
+// RecordConfigDivergence enqueues the divergence in the outbox.
+func (r *Repository) RecordConfigDivergence(ctx context.Context, d *ConfigDivergence, opt ...Option) error {
+	if d == nil {
+		return errors.New("cannot record nil config divergence")
+	}
+	d.SchemaVersion = ConfigDivergenceSchemaVersion
+	payload, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("error marshaling config divergence: %w", err)
+	}
+	if err := outbox.Enqueue(ctx, r.writer, ConfigDivergenceKind, payload); err != nil {
+		return fmt.Errorf("error recording config divergence: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package servers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFingerprints(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	repo, err := servers.NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(err)
+
+	for _, name := range []string{"controller-1", "controller-2"} {
+		_, _, err := repo.UpsertServer(ctx, &servers.Server{
+			PrivateId: name,
+			Name:      name,
+			Type:      servers.ServerTypeController.String(),
+			Address:   "127.0.0.1",
+		})
+		require.NoError(err)
+	}
+
+	fp1 := &servers.ConfigFingerprint{
+		Controller:  "controller-1",
+		Fingerprint: "fp-1",
+		Sections:    map[string]string{"kms": "a", "auth": "b", "rate_limits": "c"},
+	}
+	fp2 := &servers.ConfigFingerprint{
+		Controller:  "controller-2",
+		Fingerprint: "fp-2",
+		Sections:    map[string]string{"kms": "a", "auth": "x", "pii_retention": "d"},
+	}
+	assert.Error(repo.UpsertConfigFingerprint(ctx, nil))
+	assert.Error(repo.UpsertConfigFingerprint(ctx, &servers.ConfigFingerprint{Fingerprint: "fp"}))
+	assert.Error(repo.UpsertConfigFingerprint(ctx, &servers.ConfigFingerprint{Controller: "controller-1"}))
+	// The controller must be known
+	assert.Error(repo.UpsertConfigFingerprint(ctx, &servers.ConfigFingerprint{Controller: "controller-3", Fingerprint: "fp"}))
+
+	require.NoError(repo.UpsertConfigFingerprint(ctx, fp1))
+	require.NoError(repo.UpsertConfigFingerprint(ctx, fp2))
+	// Upserting replaces the fingerprint
+	fp2.Fingerprint = "fp-3"
+	require.NoError(repo.UpsertConfigFingerprint(ctx, fp2))
+
+	fps, err := repo.ListConfigFingerprints(ctx)
+	require.NoError(err)
+	require.Len(fps, 2)
+	got := make(map[string]*servers.ConfigFingerprint, len(fps))
+	for _, fp := range fps {
+		assert.False(fp.UpdateTime.IsZero())
+		got[fp.Controller] = fp
+	}
+	assert.Equal(fp1.Sections, got["controller-1"].Sections)
+	assert.Equal("fp-3", got["controller-2"].Fingerprint)
+
+	assert.Equal([]string{"auth", "pii_retention", "rate_limits"}, fp1.DivergentSections(fp2))
+	assert.Empty(fp1.DivergentSections(fp1))
+}
@@ -0,0 +1,159 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// ConnectionCheckPrefix is the prefix of the ids of connection checks
+const ConnectionCheckPrefix = "scc"
+
+// ConnectionCheck is a check of the connectivity from a worker to the
+// endpoint of a target. It is complete once the worker reported its result.
+type ConnectionCheck struct {
+	CheckId  string `gorm:"primary_key" json:"check_id"`
+	TargetId string `json:"-"`
+	WorkerId string `json:"-"`
+	Endpoint string `json:"endpoint,omitempty"`
+	// Tls is true if the worker must also perform a TLS handshake with the
+	// endpoint
+	Tls          bool       `json:"tls,omitempty"`
+	DispatchTime *time.Time `gorm:"default:null" json:"-"`
+	CompleteTime *time.Time `gorm:"default:null" json:"-"`
+	// Reachable is true if the worker connected to the endpoint and, when
+	// requested, completed a TLS handshake with it
+	Reachable bool `json:"reachable,omitempty"`
+	// LatencyMs is the time in milliseconds the worker took to connect
+	LatencyMs int64 `json:"latency_ms,omitempty"`
+	// Error is the reason the endpoint was not reachable
+	Error string `json:"error,omitempty"`
+}
+
+func (c *ConnectionCheck) TableName() string {
+	return "server_connection_check"
+}
+
+// Complete returns true if the worker reported the result of the check.
+func (c *ConnectionCheck) Complete() bool {
+	return c.CompleteTime != nil
+}
+
+// CreateConnectionChecks creates a check of each endpoint of the target by the
+// worker. They are dispatched to the worker with its next status.
+func (r *Repository) CreateConnectionChecks(ctx context.Context, targetId, workerId string, endpoints []string, tls bool, opt ...Option) ([]*ConnectionCheck, error) {
+	if targetId == "" {
+		return nil, errors.New("cannot create connection checks with empty target id")
+	}
+	if workerId == "" {
+		return nil, errors.New("cannot create connection checks with empty worker id")
+	}
+	if len(endpoints) == 0 {
+		return nil, errors.New("cannot create connection checks without endpoints")
+	}
+	checks := make([]*ConnectionCheck, 0, len(endpoints))
+	for _, e := range endpoints {
+		id, err := db.NewPrivateId(ConnectionCheckPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("error generating connection check id: %w", err)
+		}
+		c := &ConnectionCheck{
+			CheckId:  id,
+			TargetId: targetId,
+			WorkerId: workerId,
+			Endpoint: e,
+			Tls:      tls,
+		}
+		if err := r.writer.Create(ctx, c); err != nil {
+			return nil, fmt.Errorf("error creating connection check: %w", err)
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+// DispatchConnectionChecks returns the checks of the worker which have not
+// been dispatched to it yet and marks them as dispatched.
+func (r *Repository) DispatchConnectionChecks(ctx context.Context, workerId string, opt ...Option) ([]*ConnectionCheck, error) {
+	q := `
+	update server_connection_check
+	set dispatch_time = current_timestamp
+	where worker_id = $1 and dispatch_time is null
+	returning check_id, target_id, endpoint, tls;
+	`
+	rows, err := r.reader.Query(ctx, q, []interface{}{workerId})
+	if err != nil {
+		return nil, fmt.Errorf("error dispatching connection checks: %w", err)
+	}
+	defer rows.Close()
+	var checks []*ConnectionCheck
+	for rows.Next() {
+		c := &ConnectionCheck{WorkerId: workerId}
+		if err := rows.Scan(&c.CheckId, &c.TargetId, &c.Endpoint, &c.Tls); err != nil {
+			return nil, fmt.Errorf("error scanning dispatched connection check: %w", err)
+		}
+		checks = append(checks, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error dispatching connection checks: %w", err)
+	}
+	return checks, nil
+}
+
+// CompleteConnectionChecks records the results of checks reported by the
+// worker. Results of checks of other workers or which are already complete are
+// ignored.
+func (r *Repository) CompleteConnectionChecks(ctx context.Context, workerId string, results []*ConnectionCheck, opt ...Option) error {
+	q := `
+	update server_connection_check
+	set complete_time = current_timestamp,
+		reachable = $3,
+		latency_ms = $4,
+		error = $5
+	where check_id = $1 and worker_id = $2 and complete_time is null;
+	`
+	for _, c := range results {
+		if _, err := r.writer.Exec(ctx, q, []interface{}{c.CheckId, workerId, c.Reachable, c.LatencyMs, c.Error}); err != nil {
+			return fmt.Errorf("error completing connection check %s: %w", c.CheckId, err)
+		}
+	}
+	return nil
+}
+
+// LookupConnectionChecks returns the checks with the ids.
+func (r *Repository) LookupConnectionChecks(ctx context.Context, ids []string, opt ...Option) ([]*ConnectionCheck, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var checks []*ConnectionCheck
+	if err := r.reader.SearchWhere(ctx, &checks, "check_id in (?)", []interface{}{ids}, db.WithLimit(-1)); err != nil {
+		return nil, fmt.Errorf("error looking up connection checks: %w", err)
+	}
+	return checks, nil
+}
+
+// DeleteConnectionChecks deletes the checks with the ids, whether or not they
+// are complete.
+func (r *Repository) DeleteConnectionChecks(ctx context.Context, ids []string, opt ...Option) (int, error) {
+	if len(ids) == 0 {
+		return db.NoRowsAffected, nil
+	}
+	rows, err := r.writer.Delete(ctx, &ConnectionCheck{}, db.WithWhere("check_id in (?)", ids))
+	if err != nil {
+		return db.NoRowsAffected, fmt.Errorf("error deleting connection checks: %w", err)
+	}
+	return rows, nil
+}
+
+// CleanupConnectionChecks removes checks created before the max age, which
+// were left behind by controllers that stopped waiting for their results.
+func (r *Repository) CleanupConnectionChecks(ctx context.Context, maxAge time.Duration, opt ...Option) (int, error) {
+	rows, err := r.writer.Delete(ctx, &ConnectionCheck{}, db.WithWhere(deleteWhereSql, time.Now().Add(-maxAge)))
+	if err != nil {
+		return db.NoRowsAffected, fmt.Errorf("error performing connection check cleanup: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,132 @@
+package servers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionChecks(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	repo, err := servers.NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(err)
+
+	_, proj := iam.TestScopes(t, iamRepo)
+	tar := target.TestTcpTarget(t, conn, proj.GetPublicId(), "test target")
+	for _, name := range []string{"worker-1", "worker-2"} {
+		_, _, err := repo.UpsertServer(ctx, &servers.Server{
+			Name:    name,
+			Type:    servers.ServerTypeWorker.String(),
+			Address: "127.0.0.1",
+		})
+		require.NoError(err)
+	}
+
+	_, err = repo.CreateConnectionChecks(ctx, "", "worker-1", []string{"127.0.0.1:22"}, false)
+	assert.Error(err)
+	_, err = repo.CreateConnectionChecks(ctx, tar.GetPublicId(), "", []string{"127.0.0.1:22"}, false)
+	assert.Error(err)
+	_, err = repo.CreateConnectionChecks(ctx, tar.GetPublicId(), "worker-1", nil, false)
+	assert.Error(err)
+
+	checks, err := repo.CreateConnectionChecks(ctx, tar.GetPublicId(), "worker-1", []string{"127.0.0.1:22", "127.0.0.1:2222"}, true)
+	require.NoError(err)
+	require.Len(checks, 2)
+	ids := []string{checks[0].CheckId, checks[1].CheckId}
+
+	// Checks are dispatched once, to their worker only
+	dispatched, err := repo.DispatchConnectionChecks(ctx, "worker-2")
+	require.NoError(err)
+	assert.Empty(dispatched)
+	dispatched, err = repo.DispatchConnectionChecks(ctx, "worker-1")
+	require.NoError(err)
+	require.Len(dispatched, 2)
+	for _, c := range dispatched {
+		assert.Equal(tar.GetPublicId(), c.TargetId)
+		assert.True(c.Tls)
+	}
+	dispatched, err = repo.DispatchConnectionChecks(ctx, "worker-1")
+	require.NoError(err)
+	assert.Empty(dispatched)
+
+	// A reachable and an unreachable endpoint; results reported by another
+	// worker are ignored
+	require.NoError(repo.CompleteConnectionChecks(ctx, "worker-2", []*servers.ConnectionCheck{
+		{CheckId: checks[0].CheckId, Error: "wrong worker"},
+	}))
+	require.NoError(repo.CompleteConnectionChecks(ctx, "worker-1", []*servers.ConnectionCheck{
+		{CheckId: checks[0].CheckId, Reachable: true, LatencyMs: 3},
+		{CheckId: checks[1].CheckId, Error: "connection refused"},
+	}))
+	// Completed checks keep their first result
+	require.NoError(repo.CompleteConnectionChecks(ctx, "worker-1", []*servers.ConnectionCheck{
+		{CheckId: checks[0].CheckId, Error: "late result"},
+	}))
+	got, err := repo.LookupConnectionChecks(ctx, ids)
+	require.NoError(err)
+	require.Len(got, 2)
+	byId := map[string]*servers.ConnectionCheck{got[0].CheckId: got[0], got[1].CheckId: got[1]}
+	reachable, unreachable := byId[checks[0].CheckId], byId[checks[1].CheckId]
+	assert.True(reachable.Complete())
+	assert.True(reachable.Reachable)
+	assert.Equal(int64(3), reachable.LatencyMs)
+	assert.Empty(reachable.Error)
+	assert.True(unreachable.Complete())
+	assert.False(unreachable.Reachable)
+	assert.Equal("connection refused", unreachable.Error)
+
+	n, err := repo.DeleteConnectionChecks(ctx, ids)
+	require.NoError(err)
+	assert.Equal(2, n)
+	got, err = repo.LookupConnectionChecks(ctx, ids)
+	require.NoError(err)
+	assert.Empty(got)
+}
+
+func TestCleanupConnectionChecks(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	repo, err := servers.NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(err)
+
+	_, proj := iam.TestScopes(t, iamRepo)
+	tar := target.TestTcpTarget(t, conn, proj.GetPublicId(), "test target")
+	_, _, err = repo.UpsertServer(ctx, &servers.Server{
+		Name:    "worker-1",
+		Type:    servers.ServerTypeWorker.String(),
+		Address: "127.0.0.1",
+	})
+	require.NoError(err)
+
+	// Checks whose worker never reported a result are left behind once the
+	// controller stops waiting for them
+	checks, err := repo.CreateConnectionChecks(ctx, tar.GetPublicId(), "worker-1", []string{"127.0.0.1:22"}, false)
+	require.NoError(err)
+	n, err := repo.CleanupConnectionChecks(ctx, time.Hour)
+	require.NoError(err)
+	assert.Equal(0, n)
+	time.Sleep(10 * time.Millisecond)
+	n, err = repo.CleanupConnectionChecks(ctx, time.Millisecond)
+	require.NoError(err)
+	assert.Equal(1, n)
+	got, err := repo.LookupConnectionChecks(ctx, []string{checks[0].CheckId})
+	require.NoError(err)
+	assert.Empty(got)
+}
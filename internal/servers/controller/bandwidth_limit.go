@@ -0,0 +1,20 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+)
+
+// targetBandwidthLimit returns the bandwidth limits of the target, which are
+// sent to workers looking up its sessions.
+func (c *Controller) targetBandwidthLimit(ctx context.Context, targetId string) (uint64, uint64, error) {
+	repo, err := c.TargetRepoFn()
+	if err != nil {
+		return 0, 0, err
+	}
+	l, err := repo.LookupBandwidthLimit(ctx, targetId)
+	if err != nil {
+		return 0, 0, fmt.Errorf("target bandwidth limit: %w", err)
+	}
+	return l.ConnectionBytesPerSecond, l.SessionBytesPerSecond, nil
+}
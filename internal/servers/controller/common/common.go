@@ -1,21 +1,38 @@
 package common
 
 import (
+	"context"
+
+	"github.com/hashicorp/boundary/internal/annotation"
 	"github.com/hashicorp/boundary/internal/auth/password"
 	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/eventsink"
 	"github.com/hashicorp/boundary/internal/host/static"
 	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/secretfingerprint"
+	"github.com/hashicorp/boundary/internal/securityevent"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/session"
 	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/usage"
 )
 
 type (
-	AuthTokenRepoFactory    func() (*authtoken.Repository, error)
-	IamRepoFactory          func() (*iam.Repository, error)
-	PasswordAuthRepoFactory func() (*password.Repository, error)
-	ServersRepoFactory      func() (*servers.Repository, error)
-	StaticRepoFactory       func() (*static.Repository, error)
-	SessionRepoFactory      func() (*session.Repository, error)
-	TargetRepoFactory       func() (*target.Repository, error)
+	AnnotationRepoFactory        func() (*annotation.Repository, error)
+	AuthTokenRepoFactory         func() (*authtoken.Repository, error)
+	EventSinkRepoFactory         func() (*eventsink.Repository, error)
+	IamRepoFactory               func() (*iam.Repository, error)
+	PasswordAuthRepoFactory      func() (*password.Repository, error)
+	SecretFingerprintRepoFactory func() (*secretfingerprint.Repository, error)
+	SecurityEventRepoFactory     func() (*securityevent.Repository, error)
+	ServersRepoFactory           func() (*servers.Repository, error)
+	StaticRepoFactory            func() (*static.Repository, error)
+	SessionRepoFactory           func() (*session.Repository, error)
+	TargetRepoFactory            func() (*target.Repository, error)
+	UsageRepoFactory             func() (*usage.Repository, error)
 )
+
+// HistorySearchFn returns the page of the oplog entries matching the query,
+// rendered for display using types.
+type HistorySearchFn func(ctx context.Context, q *oplog.SearchQuery, types *oplog.TypeCatalog) (*oplog.HistoryPage, error)
@@ -0,0 +1,206 @@
+package common
+
+import (
+	"context"
+	"crypto/ed25519"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/hashicorp/boundary/internal/target"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+)
+
+// AuthTokenRepo is the auth token repository as used by the handlers, so
+// they can be constructed with another implementation, e.g. a mock in tests.
+type AuthTokenRepo interface {
+	LookupAuthToken(ctx context.Context, id string, opt ...authtoken.Option) (*authtoken.AuthToken, error)
+	ListAuthTokens(ctx context.Context, withOrgId string, opt ...authtoken.Option) ([]*authtoken.AuthToken, error)
+	DeleteAuthToken(ctx context.Context, id string, opt ...authtoken.Option) (int, error)
+	RefreshAuthToken(ctx context.Context, id string, opt ...authtoken.Option) (*authtoken.AuthToken, error)
+	ExchangeAuthToken(ctx context.Context, parentId string, grants []perms.GrantPair, ttl time.Duration, opt ...authtoken.Option) (*authtoken.AuthToken, error)
+	IssueScopedAuthToken(ctx context.Context, parentId string, preset authtoken.Preset, scopeIds []string, ttl time.Duration, opt ...authtoken.Option) (*authtoken.AuthToken, error)
+}
+
+// IamRepo is the iam repository as used by the handlers taking an IamRepo.
+type IamRepo interface {
+	LookupScope(ctx context.Context, withPublicId string, opt ...iam.Option) (*iam.Scope, error)
+	ListOrgs(ctx context.Context, opt ...iam.Option) ([]*iam.Scope, error)
+	ListProjects(ctx context.Context, withOrgId string, opt ...iam.Option) ([]*iam.Scope, error)
+	CreateScope(ctx context.Context, s *iam.Scope, userId string, opt ...iam.Option) (*iam.Scope, error)
+	UpdateScope(ctx context.Context, scope *iam.Scope, version uint32, fieldMaskPaths []string, opt ...iam.Option) (*iam.Scope, int, error)
+	DeleteScope(ctx context.Context, withPublicId string, opt ...iam.Option) (int, error)
+	LookupInactivityPolicy(ctx context.Context, scopeId string, opt ...iam.Option) (*iam.InactivityPolicy, error)
+	SetInactivityPolicy(ctx context.Context, scopeId string, maxInactiveDays uint32, disableInactive bool, opt ...iam.Option) error
+	LookupProjectTemplate(ctx context.Context, orgId string, opt ...iam.Option) (*iam.ProjectTemplate, error)
+	SetProjectTemplate(ctx context.Context, orgId string, t *iam.ProjectTemplate, opt ...iam.Option) error
+
+	LookupUser(ctx context.Context, userId string, opt ...iam.Option) (*iam.User, []string, error)
+	ListUsers(ctx context.Context, withOrgId string, opt ...iam.Option) ([]*iam.User, error)
+	CreateUser(ctx context.Context, user *iam.User, opt ...iam.Option) (*iam.User, error)
+	UpdateUser(ctx context.Context, user *iam.User, version uint32, fieldMaskPaths []string, opt ...iam.Option) (*iam.User, []string, int, error)
+	DeleteUser(ctx context.Context, withPublicId string, opt ...iam.Option) (int, error)
+	AddUserAccounts(ctx context.Context, userId string, userVersion uint32, accountIds []string, opt ...iam.Option) ([]string, error)
+	SetUserAccounts(ctx context.Context, userId string, userVersion uint32, accountIds []string, opt ...iam.Option) ([]string, error)
+	DeleteUserAccounts(ctx context.Context, userId string, userVersion uint32, accountIds []string, opt ...iam.Option) ([]string, error)
+	GrantsForUser(ctx context.Context, userId string, opt ...iam.Option) ([]perms.GrantPair, error)
+	DisableUser(ctx context.Context, userId, reason string, opt ...iam.Option) error
+	EnableUser(ctx context.Context, userId string, opt ...iam.Option) error
+	LookupUserDisabled(ctx context.Context, userId string, opt ...iam.Option) (*iam.UserDisabled, error)
+	ListDisabledUsers(ctx context.Context, scopeId string, opt ...iam.Option) ([]*iam.UserDisabled, error)
+	LookupUserLogins(ctx context.Context, userId string, opt ...iam.Option) (*iam.UserLogins, error)
+	ListUserLogins(ctx context.Context, scopeId string, opt ...iam.Option) ([]*iam.UserLogins, error)
+	ListInactiveUsers(ctx context.Context, scopeId string, days uint32, opt ...iam.Option) ([]*iam.InactiveUser, error)
+
+	LookupGroup(ctx context.Context, withPublicId string, opt ...iam.Option) (*iam.Group, []*iam.GroupMember, error)
+	ListGroups(ctx context.Context, withScopeId string, opt ...iam.Option) ([]*iam.Group, error)
+	CreateGroup(ctx context.Context, group *iam.Group, opt ...iam.Option) (*iam.Group, error)
+	UpdateGroup(ctx context.Context, group *iam.Group, version uint32, fieldMaskPaths []string, opt ...iam.Option) (*iam.Group, []*iam.GroupMember, int, error)
+	DeleteGroup(ctx context.Context, withPublicId string, opt ...iam.Option) (int, error)
+	AddGroupMembers(ctx context.Context, groupId string, groupVersion uint32, userIds []string, opt ...iam.Option) ([]*iam.GroupMember, error)
+	SetGroupMembers(ctx context.Context, groupId string, groupVersion uint32, userIds []string, opt ...iam.Option) ([]*iam.GroupMember, int, error)
+	DeleteGroupMembers(ctx context.Context, groupId string, groupVersion uint32, userIds []string, opt ...iam.Option) (int, error)
+
+	LookupRole(ctx context.Context, withPublicId string, opt ...iam.Option) (*iam.Role, []iam.PrincipalRole, []*iam.RoleGrant, error)
+	ListRoles(ctx context.Context, withScopeId string, opt ...iam.Option) ([]*iam.Role, error)
+	CreateRole(ctx context.Context, role *iam.Role, opt ...iam.Option) (*iam.Role, error)
+	UpdateRole(ctx context.Context, role *iam.Role, version uint32, fieldMaskPaths []string, opt ...iam.Option) (*iam.Role, []iam.PrincipalRole, []*iam.RoleGrant, int, error)
+	DeleteRole(ctx context.Context, withPublicId string, opt ...iam.Option) (int, error)
+	AddPrincipalRoles(ctx context.Context, roleId string, roleVersion uint32, principalIds []string, opt ...iam.Option) ([]iam.PrincipalRole, error)
+	SetPrincipalRoles(ctx context.Context, roleId string, roleVersion uint32, principalIds []string, opt ...iam.Option) ([]iam.PrincipalRole, int, error)
+	DeletePrincipalRoles(ctx context.Context, roleId string, roleVersion uint32, principalIds []string, opt ...iam.Option) (int, error)
+	ListPrincipalRoleExpirations(ctx context.Context, roleId string, opt ...iam.Option) ([]iam.PrincipalRoleExpiration, error)
+	AddRoleGrants(ctx context.Context, roleId string, roleVersion uint32, grants []string, opt ...iam.Option) ([]*iam.RoleGrant, error)
+	SetRoleGrants(ctx context.Context, roleId string, roleVersion uint32, grants []string, opt ...iam.Option) ([]*iam.RoleGrant, int, error)
+	DeleteRoleGrants(ctx context.Context, roleId string, roleVersion uint32, grants []string, opt ...iam.Option) (int, error)
+
+	LookupAccessRequest(ctx context.Context, withPublicId string, opt ...iam.Option) (*iam.AccessRequest, error)
+	ListAccessRequests(ctx context.Context, roleId string, opt ...iam.Option) ([]*iam.AccessRequest, error)
+	ListAccessRequestEvents(ctx context.Context, withPublicId string, opt ...iam.Option) ([]*iam.AccessRequestEvent, error)
+	CreateAccessRequest(ctx context.Context, roleId, requesterId, justification string, duration time.Duration, opt ...iam.Option) (*iam.AccessRequest, error)
+	ApproveAccessRequest(ctx context.Context, withPublicId, deciderId, comment string, opt ...iam.Option) (*iam.AccessRequest, error)
+	DenyAccessRequest(ctx context.Context, withPublicId, deciderId, comment string, opt ...iam.Option) (*iam.AccessRequest, error)
+	CancelAccessRequest(ctx context.Context, withPublicId, requesterId string, opt ...iam.Option) (*iam.AccessRequest, error)
+}
+
+// SessionRepo is the session repository as used by the handlers.
+type SessionRepo interface {
+	LookupSession(ctx context.Context, sessionId string, opt ...session.Option) (*session.Session, *session.ConnectionAuthzSummary, error)
+	ListSessions(ctx context.Context, opt ...session.Option) ([]*session.Session, error)
+	ListConnectionStats(ctx context.Context, scopeId string, opt ...session.Option) ([]*session.ConnectionStats, error)
+	CreateSession(ctx context.Context, sessionWrapper wrapping.Wrapper, newSession *session.Session, opt ...session.Option) (*session.Session, ed25519.PrivateKey, error)
+	CancelSession(ctx context.Context, sessionId string, sessionVersion uint32) (*session.Session, error)
+	LastSessionWorker(ctx context.Context, userId, targetId string, since time.Time) (string, error)
+}
+
+// TargetRepo is the target repository as used by the handlers.
+type TargetRepo interface {
+	LookupTarget(ctx context.Context, publicIdOrName string, opt ...target.Option) (target.Target, []*target.TargetSet, error)
+	LookupTargets(ctx context.Context, ids []string) ([]target.Target, map[string][]*target.TargetSet, error)
+	ListTargetsWithHostSets(ctx context.Context, opt ...target.Option) ([]target.Target, map[string][]*target.TargetSet, error)
+	CreateTcpTarget(ctx context.Context, t *target.TcpTarget, opt ...target.Option) (target.Target, []*target.TargetSet, error)
+	CloneTcpTarget(ctx context.Context, targetId, name string, opt ...target.Option) (target.Target, []*target.TargetSet, error)
+	UpdateTcpTarget(ctx context.Context, t *target.TcpTarget, version uint32, fieldMaskPaths []string, opt ...target.Option) (target.Target, []*target.TargetSet, int, error)
+	DeleteTarget(ctx context.Context, publicId string, opt ...target.Option) (int, error)
+	AddTargetHostSets(ctx context.Context, targetId string, targetVersion uint32, hostSetIds []string, opt ...target.Option) (target.Target, []*target.TargetSet, error)
+	SetTargetHostSets(ctx context.Context, targetId string, targetVersion uint32, hostSetIds []string, opt ...target.Option) ([]*target.TargetSet, int, error)
+	DeleteTargeHostSets(ctx context.Context, targetId string, targetVersion uint32, hostSetIds []string, opt ...target.Option) (int, error)
+
+	LookupBandwidthLimit(ctx context.Context, targetId string, opt ...target.Option) (*target.BandwidthLimit, error)
+	SetBandwidthLimit(ctx context.Context, targetId string, connectionBytesPerSecond, sessionBytesPerSecond uint64, opt ...target.Option) error
+	LookupConnectionAuthorization(ctx context.Context, targetId string, opt ...target.Option) (bool, error)
+	SetConnectionAuthorization(ctx context.Context, targetId string, required bool, opt ...target.Option) error
+	LookupConnectionMetadata(ctx context.Context, targetId string, opt ...target.Option) (map[string]string, error)
+	SetConnectionMetadata(ctx context.Context, targetId string, metadata map[string]string, opt ...target.Option) error
+	LookupConnectionRateLimit(ctx context.Context, targetId string, opt ...target.Option) (*target.ConnectionRateLimit, error)
+	SetConnectionRateLimit(ctx context.Context, targetId string, connectionsPerMinute uint32, opt ...target.Option) error
+	LookupPeerIdentity(ctx context.Context, targetId string, opt ...target.Option) (*target.PeerIdentity, error)
+	SetPeerIdentity(ctx context.Context, targetId, mode string, opt ...target.Option) error
+	LookupUserNameTemplate(ctx context.Context, targetId string, opt ...target.Option) (*target.UserNameTemplate, error)
+	SetUserNameTemplate(ctx context.Context, targetId, tmpl string, opt ...target.Option) error
+
+	LookupCredentialCheckoutPolicy(ctx context.Context, targetId string, opt ...target.Option) (*target.CredentialCheckoutPolicy, error)
+	SetCredentialCheckoutPolicy(ctx context.Context, targetId, mode string, queueTimeoutSeconds uint32, rotateOnCheckIn bool, opt ...target.Option) error
+	LookupCredentialCheckout(ctx context.Context, targetId string, opt ...target.Option) (*target.CredentialCheckout, error)
+	CheckOutCredential(ctx context.Context, targetId, userId string, opt ...target.Option) (*target.CredentialCheckout, error)
+	AttachCredentialCheckout(ctx context.Context, checkoutId, sessionId string, opt ...target.Option) error
+	CheckInCredential(ctx context.Context, checkoutId string, opt ...target.Option) error
+}
+
+var (
+	_ AuthTokenRepo = (*authtoken.Repository)(nil)
+	_ IamRepo       = (*iam.Repository)(nil)
+	_ SessionRepo   = (*session.Repository)(nil)
+	_ TargetRepo    = (*target.Repository)(nil)
+)
+
+// Providers return the repositories consumed by the handlers as their
+// interfaces. The repository factories are converted to them with Provider.
+type (
+	AuthTokenRepoProvider func() (AuthTokenRepo, error)
+	IamRepoProvider       func() (IamRepo, error)
+	SessionRepoProvider   func() (SessionRepo, error)
+	TargetRepoProvider    func() (TargetRepo, error)
+)
+
+// Provider returns a provider of the repositories of the factory, or nil if
+// the factory is nil.
+func (f AuthTokenRepoFactory) Provider() AuthTokenRepoProvider {
+	if f == nil {
+		return nil
+	}
+	return func() (AuthTokenRepo, error) {
+		r, err := f()
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+}
+
+// Provider returns a provider of the repositories of the factory, or nil if
+// the factory is nil.
+func (f IamRepoFactory) Provider() IamRepoProvider {
+	if f == nil {
+		return nil
+	}
+	return func() (IamRepo, error) {
+		r, err := f()
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+}
+
+// Provider returns a provider of the repositories of the factory, or nil if
+// the factory is nil.
+func (f SessionRepoFactory) Provider() SessionRepoProvider {
+	if f == nil {
+		return nil
+	}
+	return func() (SessionRepo, error) {
+		r, err := f()
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+}
+
+// Provider returns a provider of the repositories of the factory, or nil if
+// the factory is nil.
+func (f TargetRepoFactory) Provider() TargetRepoProvider {
+	if f == nil {
+		return nil
+	}
+	return func() (TargetRepo, error) {
+		r, err := f()
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+}
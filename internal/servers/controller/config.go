@@ -14,4 +14,8 @@ type Config struct {
 	RawConfig *config.Config
 	// If set, authorization checking occurrs but failures are ignored
 	DisableAuthorizationFailures bool
+	// If set, called in addition to re-checking grants for each new
+	// connection to a session of a target which requires connection
+	// authorization
+	ConnectionAuthorizer ConnectionAuthorizer
 }
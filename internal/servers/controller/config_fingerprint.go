@@ -0,0 +1,241 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/servers"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+)
+
+// configFingerprintLiveness is how recently the fingerprint of a peer must
+// have been recorded for it to be compared, so controllers which were shut
+// down aren't reported as diverging.
+const configFingerprintLiveness = 3 * statusInterval
+
+// configFingerprintSections returns the security relevant sections of the
+// configuration of the controller by name. Secrets, such as the secrets of
+// auth hooks and the token of the policy hook, are left out: only their
+// presence is part of the sections.
+func (c *Controller) configFingerprintSections() map[string]interface{} {
+	conf := c.conf.RawConfig.Controller
+
+	type kmsSection struct {
+		Type  string `json:"type"`
+		KeyId string `json:"key_id"`
+	}
+	kmses := make(map[string]*kmsSection)
+	for purpose, w := range map[string]wrapping.Wrapper{
+		"root":        c.conf.RootKms,
+		"worker-auth": c.conf.WorkerAuthKms,
+		"recovery":    c.conf.RecoveryKms,
+	} {
+		if w != nil {
+			kmses[purpose] = &kmsSection{Type: w.Type(), KeyId: w.KeyID()}
+		}
+	}
+
+	type authHookSection struct {
+		Stage         string        `json:"stage"`
+		Url           string        `json:"url"`
+		HasSecret     bool          `json:"has_secret"`
+		AuthMethodIds []string      `json:"auth_method_ids"`
+		Timeout       time.Duration `json:"timeout"`
+		FailurePolicy string        `json:"failure_policy"`
+	}
+	authHooks := make([]*authHookSection, 0, len(conf.AuthHooks))
+	for _, h := range conf.AuthHooks {
+		ids := append([]string(nil), h.AuthMethodIds...)
+		sort.Strings(ids)
+		authHooks = append(authHooks, &authHookSection{
+			Stage:         h.Stage,
+			Url:           h.Url,
+			HasSecret:     h.Secret != "",
+			AuthMethodIds: ids,
+			Timeout:       h.TimeoutDuration,
+			FailurePolicy: h.FailurePolicy,
+		})
+	}
+	sort.SliceStable(authHooks, func(i, j int) bool {
+		return authHooks[i].Stage+authHooks[i].Url < authHooks[j].Stage+authHooks[j].Url
+	})
+
+	type policyHookSection struct {
+		Url           string        `json:"url"`
+		HasToken      bool          `json:"has_token"`
+		Scope         string        `json:"scope"`
+		Timeout       time.Duration `json:"timeout"`
+		FailurePolicy string        `json:"failure_policy"`
+	}
+	var policyHook *policyHookSection
+	if h := conf.PolicyHook; h != nil {
+		policyHook = &policyHookSection{
+			Url:           h.Url,
+			HasToken:      h.Token != "",
+			Scope:         h.Scope,
+			Timeout:       h.TimeoutDuration,
+			FailurePolicy: h.FailurePolicy,
+		}
+	}
+
+	type authSection struct {
+		AuthTokenTimeToLive          time.Duration      `json:"auth_token_time_to_live"`
+		AuthTokenTimeToStale         time.Duration      `json:"auth_token_time_to_stale"`
+		AuthTokenMaxLifetime         time.Duration      `json:"auth_token_max_lifetime"`
+		AuthHooks                    []*authHookSection `json:"auth_hooks"`
+		PolicyHook                   *policyHookSection `json:"policy_hook"`
+		GrantsCache                  bool               `json:"grants_cache"`
+		DisableAuthorizationFailures bool               `json:"disable_authorization_failures"`
+		FipsMode                     string             `json:"fips_mode"`
+	}
+	auth := &authSection{
+		AuthTokenTimeToLive:          conf.AuthTokenTimeToLiveDuration,
+		AuthTokenTimeToStale:         conf.AuthTokenTimeToStaleDuration,
+		AuthTokenMaxLifetime:         conf.AuthTokenMaxLifetimeDuration,
+		AuthHooks:                    authHooks,
+		PolicyHook:                   policyHook,
+		GrantsCache:                  conf.GrantsCache != nil && conf.GrantsCache.Enabled,
+		DisableAuthorizationFailures: c.conf.DisableAuthorizationFailures,
+		FipsMode:                     c.conf.FipsMode.String(),
+	}
+
+	type listenerAccessSection struct {
+		Allow []string `json:"allow"`
+		Deny  []string `json:"deny"`
+	}
+	listenerAccess := make(map[string]*listenerAccessSection, len(conf.ListenerAccess))
+	for _, la := range conf.ListenerAccess {
+		s := &listenerAccessSection{
+			Allow: append([]string(nil), la.Allow...),
+			Deny:  append([]string(nil), la.Deny...),
+		}
+		sort.Strings(s.Allow)
+		sort.Strings(s.Deny)
+		listenerAccess[strings.ToLower(la.Purpose)] = s
+	}
+
+	type workerAccessSection struct {
+		AllowedCidrs []string          `json:"allowed_cidrs"`
+		NamePattern  string            `json:"name_pattern"`
+		RequiredTags map[string]string `json:"required_tags"`
+	}
+	var workerAccess *workerAccessSection
+	if wa := conf.WorkerAccess; wa != nil {
+		workerAccess = &workerAccessSection{
+			AllowedCidrs: append([]string(nil), wa.AllowedCidrs...),
+			NamePattern:  wa.NamePattern,
+			RequiredTags: wa.RequiredTags,
+		}
+		sort.Strings(workerAccess.AllowedCidrs)
+	}
+
+	type rateLimitsSection struct {
+		MaxBodySizes map[string]int64 `json:"max_body_sizes"`
+		MaxInFlight  int              `json:"max_in_flight"`
+		MaxQueued    int              `json:"max_queued"`
+		QueueTimeout time.Duration    `json:"queue_timeout"`
+	}
+	var rateLimits *rateLimitsSection
+	if rl := conf.RequestLimits; rl != nil {
+		rateLimits = &rateLimitsSection{
+			MaxBodySizes: rl.MaxBodySizes,
+			MaxInFlight:  rl.MaxInFlight,
+			MaxQueued:    rl.MaxQueued,
+			QueueTimeout: rl.QueueTimeoutDuration,
+		}
+	}
+
+	return map[string]interface{}{
+		"kms":             kmses,
+		"auth":            auth,
+		"rate_limits":     rateLimits,
+		"listener_access": listenerAccess,
+		"worker_access":   workerAccess,
+		"pii_retention":   conf.PiiRetentionDurations,
+	}
+}
+
+// configFingerprint returns the fingerprint of the security relevant
+// configuration of the controller. It is computed for each status update, as
+// some of the configuration, such as the worker-auth KMS, can be reloaded.
+func (c *Controller) configFingerprint() (*servers.ConfigFingerprint, error) {
+	hash := func(v interface{}) (string, error) {
+		// Maps are marshaled with sorted keys, so the JSON is canonical
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256(b)
+		return hex.EncodeToString(sum[:]), nil
+	}
+	fp := &servers.ConfigFingerprint{
+		Controller: c.conf.RawConfig.Controller.Name,
+		Sections:   make(map[string]string),
+	}
+	for name, section := range c.configFingerprintSections() {
+		h, err := hash(section)
+		if err != nil {
+			return nil, fmt.Errorf("error fingerprinting %s config: %w", name, err)
+		}
+		fp.Sections[name] = h
+	}
+	var err error
+	if fp.Fingerprint, err = hash(fp.Sections); err != nil {
+		return nil, fmt.Errorf("error fingerprinting config: %w", err)
+	}
+	return fp, nil
+}
+
+// checkConfigDivergence records the fingerprint of the configuration of the
+// controller and compares it with those of its peers. A peer whose
+// configuration diverges is warned about and recorded as a
+// servers.ConfigDivergence event once for each pair of fingerprints, so a
+// partially rolled out configuration change is reported once rather than at
+// each status update.
+func (c *Controller) checkConfigDivergence(ctx context.Context, repo *servers.Repository) error {
+	fp, err := c.configFingerprint()
+	if err != nil {
+		return err
+	}
+	if err := repo.UpsertConfigFingerprint(ctx, fp); err != nil {
+		return err
+	}
+	peers, err := repo.ListConfigFingerprints(ctx, servers.WithLiveness(configFingerprintLiveness))
+	if err != nil {
+		return err
+	}
+	reported := make(map[string]string, len(peers))
+	for _, peer := range peers {
+		if peer.Controller == fp.Controller || peer.Fingerprint == fp.Fingerprint {
+			continue
+		}
+		pair := fp.Fingerprint + ":" + peer.Fingerprint
+		reported[peer.Controller] = pair
+		if c.configDivergences[peer.Controller] == pair {
+			continue
+		}
+		sections := fp.DivergentSections(peer)
+		c.logger.Warn("configuration diverges from peer controller", "peer", peer.Controller,
+			"sections", strings.Join(sections, ","), "fingerprint", fp.Fingerprint, "peer_fingerprint", peer.Fingerprint)
+		if err := repo.RecordConfigDivergence(ctx, &servers.ConfigDivergence{
+			Controller:      fp.Controller,
+			Peer:            peer.Controller,
+			Fingerprint:     fp.Fingerprint,
+			PeerFingerprint: peer.Fingerprint,
+			Sections:        sections,
+			DetectTime:      time.Now(),
+		}); err != nil {
+			// Retried at the next status update
+			c.logger.Error("error recording config divergence", "peer", peer.Controller, "error", err)
+			delete(reported, peer.Controller)
+		}
+	}
+	c.configDivergences = reported
+	return nil
+}
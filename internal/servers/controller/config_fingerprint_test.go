@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFingerprint(t *testing.T) {
+	fingerprintOf := func(t *testing.T, change func(*config.Controller)) (string, map[string]string) {
+		t.Helper()
+		conf := &config.Controller{
+			Name:                        "controller-1",
+			AuthTokenTimeToLiveDuration: 24 * time.Hour,
+			AuthHooks: []*config.AuthHook{
+				{Stage: "pre", Url: "https://hooks.example.com/pre", Secret: "s3cr3t"},
+			},
+			RequestLimits: &config.RequestLimits{
+				MaxInFlight:          100,
+				QueueTimeout:         "30s",
+				QueueTimeoutDuration: 30 * time.Second,
+			},
+			ListenerAccess: []*config.ListenerAccess{
+				{Purpose: "api", Allow: []string{"10.0.0.0/8", "192.168.0.0/16"}},
+			},
+		}
+		if change != nil {
+			change(conf)
+		}
+		c := &Controller{conf: &Config{
+			Server:    &base.Server{},
+			RawConfig: &config.Config{Controller: conf},
+		}}
+		fp, err := c.configFingerprint()
+		require.NoError(t, err)
+		assert.Equal(t, "controller-1", fp.Controller)
+		return fp.Fingerprint, fp.Sections
+	}
+
+	want, wantSections := fingerprintOf(t, nil)
+	assert.Len(t, wantSections, 6)
+
+	t.Run("unchanged", func(t *testing.T) {
+		assert := assert.New(t)
+		for name, change := range map[string]func(*config.Controller){
+			"description":       func(c *config.Controller) { c.Description = "other" },
+			"secret":            func(c *config.Controller) { c.AuthHooks[0].Secret = "other" },
+			"duration-notation": func(c *config.Controller) { c.RequestLimits.QueueTimeout = 30 },
+			"cidr-order": func(c *config.Controller) {
+				c.ListenerAccess[0].Allow = []string{"192.168.0.0/16", "10.0.0.0/8"}
+			},
+		} {
+			fp, _ := fingerprintOf(t, change)
+			assert.Equal(want, fp, name)
+		}
+	})
+	t.Run("changed", func(t *testing.T) {
+		assert := assert.New(t)
+		for section, change := range map[string]func(*config.Controller){
+			"auth":            func(c *config.Controller) { c.AuthTokenTimeToLiveDuration = time.Hour },
+			"rate_limits":     func(c *config.Controller) { c.RequestLimits.MaxInFlight = 10 },
+			"listener_access": func(c *config.Controller) { c.ListenerAccess[0].Deny = []string{"10.1.0.0/16"} },
+			"worker_access": func(c *config.Controller) {
+				c.WorkerAccess = &config.WorkerAccess{NamePattern: "^worker-"}
+			},
+			"pii_retention": func(c *config.Controller) {
+				c.PiiRetentionDurations = map[string]time.Duration{"client_address": time.Hour}
+			},
+		} {
+			fp, sections := fingerprintOf(t, change)
+			assert.NotEqual(want, fp, section)
+			for name, h := range sections {
+				if name == section {
+					assert.NotEqual(wantSections[name], h, section)
+				} else {
+					assert.Equal(wantSections[name], h, "%s changed with %s", name, section)
+				}
+			}
+		}
+	})
+}
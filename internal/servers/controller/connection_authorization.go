@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+)
+
+// ConnectionAuthorizer is an additional check of each new connection to a
+// session whose target requires connection authorization. Returning false
+// denies the connection.
+type ConnectionAuthorizer func(ctx context.Context, sess *session.Session) (bool, error)
+
+// authorizeSessionConnection returns false if the target of the session
+// requires connection authorization and the session's auth token, or the
+// latest auth token refreshed from it, is no longer valid, its user may no
+// longer authorize sessions on the target or the configured
+// ConnectionAuthorizer denies the connection. This makes revoking a
+// permission apply to the new connections of sessions already established.
+func (c *Controller) authorizeSessionConnection(ctx context.Context, sessionId string) (bool, error) {
+	sessRepo, err := c.SessionRepoFn()
+	if err != nil {
+		return false, err
+	}
+	sess, _, err := sessRepo.LookupSession(ctx, sessionId)
+	if err != nil {
+		return false, fmt.Errorf("authorize session connection: %w", err)
+	}
+	if sess == nil {
+		// Authorizing the connection in the session repository reports the
+		// missing session
+		return true, nil
+	}
+	targetRepo, err := c.TargetRepoFn()
+	if err != nil {
+		return false, err
+	}
+	required, err := targetRepo.LookupConnectionAuthorization(ctx, sess.TargetId)
+	if err != nil {
+		return false, fmt.Errorf("authorize session connection: %w", err)
+	}
+	if !required {
+		return true, nil
+	}
+	allowed, err := c.sessionGrantsAllowed(ctx, sess)
+	if err != nil {
+		return false, fmt.Errorf("authorize session connection: %w", err)
+	}
+	if !allowed || c.conf.ConnectionAuthorizer == nil {
+		return allowed, nil
+	}
+	return c.conf.ConnectionAuthorizer(ctx, sess)
+}
+
+// sessionGrantsAllowed returns true if the auth token the session was
+// authorized with, or an auth token refreshed from it, is still valid and its
+// grants still allow authorizing sessions on the session's target.
+func (c *Controller) sessionGrantsAllowed(ctx context.Context, sess *session.Session) (bool, error) {
+	tokenRepo, err := c.AuthTokenRepoFn()
+	if err != nil {
+		return false, err
+	}
+	if sess.AuthTokenId == "" {
+		return false, nil
+	}
+	at, err := tokenRepo.LookupRefreshedAuthToken(ctx, sess.AuthTokenId)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case at == nil,
+		at.GetIamUserId() != sess.UserId,
+		at.GetExpirationTime().GetTimestamp().AsTime().Before(time.Now()):
+		return false, nil
+	}
+	att, err := tokenRepo.LookupAttenuation(ctx, at.GetPublicId())
+	if err != nil {
+		return false, err
+	}
+
+	targetRepo, err := c.TargetRepoFn()
+	if err != nil {
+		return false, err
+	}
+	t, _, err := targetRepo.LookupTarget(ctx, sess.TargetId)
+	if err != nil {
+		return false, err
+	}
+	if t == nil {
+		return false, nil
+	}
+	res := perms.Resource{
+		ScopeId: t.GetScopeId(),
+		Id:      t.GetPublicId(),
+		Type:    resource.Target,
+	}
+	if !att.AllowsResource(res) || !att.AllowsAction(res, action.AuthorizeSession) {
+		return false, nil
+	}
+
+	iamRepo, err := c.IamRepoFn()
+	if err != nil {
+		return false, err
+	}
+	grantPairs, err := iamRepo.GrantsForUser(ctx, sess.UserId)
+	if err != nil {
+		return false, err
+	}
+	if grantPairs, err = att.Filter(grantPairs); err != nil {
+		return false, err
+	}
+	grants := make([]perms.Grant, 0, len(grantPairs))
+	for _, pair := range grantPairs {
+		parsed, err := perms.Parse(
+			pair.ScopeId,
+			pair.Grant,
+			perms.WithUserId(sess.UserId),
+			perms.WithAccountId(at.GetAuthAccountId()),
+			perms.WithSkipFinalValidation(true))
+		if err != nil {
+			return false, fmt.Errorf("failed to parse grant %#v: %w", pair.Grant, err)
+		}
+		grants = append(grants, parsed)
+	}
+	return perms.NewACL(grants...).Allowed(res, action.AuthorizeSession).Allowed, nil
+}
@@ -0,0 +1,21 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+)
+
+// targetConnectionRateLimit returns the connections each session of the
+// target may open per minute, which is sent to workers looking up its
+// sessions.
+func (c *Controller) targetConnectionRateLimit(ctx context.Context, targetId string) (uint32, error) {
+	repo, err := c.TargetRepoFn()
+	if err != nil {
+		return 0, err
+	}
+	l, err := repo.LookupConnectionRateLimit(ctx, targetId)
+	if err != nil {
+		return 0, fmt.Errorf("target connection rate limit: %w", err)
+	}
+	return l.ConnectionsPerMinute, nil
+}
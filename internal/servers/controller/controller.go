@@ -4,26 +4,49 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
+	"net"
+	"net/http"
 	"sync"
+	"time"
 
+	"github.com/hashicorp/boundary/internal/annotation"
 	"github.com/hashicorp/boundary/internal/auth/password"
+	"github.com/hashicorp/boundary/internal/authhook"
 	"github.com/hashicorp/boundary/internal/authtoken"
 	"github.com/hashicorp/boundary/internal/cmd/config"
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/dnscache"
+	"github.com/hashicorp/boundary/internal/eventsink"
 	"github.com/hashicorp/boundary/internal/host/static"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/outbox"
+	"github.com/hashicorp/boundary/internal/policyhook"
+	"github.com/hashicorp/boundary/internal/secretfingerprint"
+	"github.com/hashicorp/boundary/internal/securityevent"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/session"
 	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/usage"
 	"github.com/hashicorp/go-hclog"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 	"github.com/hashicorp/vault/sdk/helper/mlock"
 	"github.com/patrickmn/go-cache"
 	ua "go.uber.org/atomic"
 )
 
+// scopedEventKinds are the kinds of outbox messages whose payloads have the
+// scope_id of the event, which are delivered to the event sinks of the scope.
+var scopedEventKinds = []string{
+	securityevent.OutboxKind,
+	session.AutoCancelEventKind,
+	secretfingerprint.OutboxKind,
+}
+
 type Controller struct {
 	conf   *Config
 	logger hclog.Logger
@@ -34,17 +57,91 @@ type Controller struct {
 
 	workerAuthCache *cache.Cache
 
+	// stmtCache and responseCache are nil unless enabled in the controller
+	// config
+	stmtCache     *db.StatementCache
+	responseCache *handlers.ResponseCache
+	// unregisterResponseCache stops the response cache from being
+	// invalidated by committed changes; it is nil while stopped
+	unregisterResponseCache func()
+
+	// grantsCache is nil unless enabled in the controller config
+	grantsCache *iam.GrantsCache
+
+	// uiAssets serves the UI from an external source; it is nil if the
+	// built in assets are served
+	uiAssets *uiAssets
+
+	// workerSelector orders the workers of sessions being authorized
+	workerSelector servers.WorkerSelector
+
+	// workerAffinity is how long the worker of a session is preferred for
+	// the next session of its user to its target; it is zero if disabled
+	workerAffinity time.Duration
+
+	// listenerAccess checks the addresses of connections to the listeners
+	listenerAccess *listenerAccess
+
+	// workerAccess checks the workers authenticating and updating their
+	// status against the worker access allow list
+	workerAccess *workerAccess
+
+	// requestLimiter limits the size and number of requests served; it is
+	// nil if not configured
+	requestLimiter *requestLimiter
+
+	// webhookClient calls the auth and policy hooks and the event sinks; it
+	// is nil, for http.DefaultClient, unless DNS lookups are cached
+	webhookClient *http.Client
+
+	// authHooks calls the webhooks configured to take part in authentication
+	authHooks *authhook.Runner
+
+	// policyHook evaluates authorization decisions with an external policy
+	// engine; it is nil if not configured
+	policyHook *policyhook.Evaluator
+
+	// piiRetention is how long personally identifiable information is kept
+	// by category; it is empty if it is kept as long as its records
+	piiRetention map[db.PiiCategory]time.Duration
+
+	// schema is the status of the schema of the database; until it is up to
+	// date with the binary, requests are rejected and background jobs don't
+	// run
+	schema schemaGate
+
+	// usageMeter counts the API calls of scopes until the usage ticker records
+	// them; it is nil on read-only controllers, which don't record usage
+	usageMeter *usage.Meter
+
+	// configDivergences are the pairs of config fingerprints of this
+	// controller and its peers already reported as diverging, by peer. It is
+	// only used by the status ticker.
+	configDivergences map[string]string
+
 	// Used for testing
 	workerStatusUpdateTimes *sync.Map
 
 	// Repo factory methods
-	AuthTokenRepoFn    common.AuthTokenRepoFactory
-	IamRepoFn          common.IamRepoFactory
-	PasswordAuthRepoFn common.PasswordAuthRepoFactory
-	ServersRepoFn      common.ServersRepoFactory
-	SessionRepoFn      common.SessionRepoFactory
-	StaticHostRepoFn   common.StaticRepoFactory
-	TargetRepoFn       common.TargetRepoFactory
+	AnnotationRepoFn    common.AnnotationRepoFactory
+	AuthTokenRepoFn     common.AuthTokenRepoFactory
+	EventSinkRepoFn     common.EventSinkRepoFactory
+	IamRepoFn           common.IamRepoFactory
+	PasswordAuthRepoFn  common.PasswordAuthRepoFactory
+	SecurityEventRepoFn common.SecurityEventRepoFactory
+	ServersRepoFn       common.ServersRepoFactory
+	SessionRepoFn       common.SessionRepoFactory
+	StaticHostRepoFn    common.StaticRepoFactory
+	TargetRepoFn        common.TargetRepoFactory
+	UsageRepoFn         common.UsageRepoFactory
+
+	// SecretFingerprintRepoFn is nil unless secret fingerprints are enabled
+	// in the controller config
+	SecretFingerprintRepoFn common.SecretFingerprintRepoFactory
+
+	// Outbox delivers the external side effects enqueued by repositories.
+	// Handlers for the kinds of messages must be registered before Start.
+	Outbox *outbox.Dispatcher
 
 	kms *kms.Kms
 }
@@ -89,7 +186,22 @@ func New(conf *Config) (*Controller, error) {
 	}
 
 	// Set up repo stuff
-	dbase := db.New(c.conf.Database)
+	dbOpts := []db.Option{db.WithAsyncOplog(c.conf.RawConfig.Controller.AsyncOplog)}
+	if sc := c.conf.RawConfig.Controller.StatementCache; sc != nil && sc.Enabled {
+		var err error
+		c.stmtCache, err = db.NewStatementCache(c.conf.Database, sc.MaxEntries)
+		if err != nil {
+			return nil, fmt.Errorf("error creating statement cache: %w", err)
+		}
+		dbOpts = append(dbOpts, db.WithStatementCache(c.stmtCache))
+	}
+	dbase := db.New(c.conf.Database, dbOpts...)
+	if err := c.migrateSchema(context.Background(), dbase); err != nil {
+		return nil, err
+	}
+	if err := c.verifySchema(context.Background(), dbase); err != nil {
+		return nil, err
+	}
 	kmsRepo, err := kms.NewRepository(dbase, dbase)
 	if err != nil {
 		return nil, fmt.Errorf("error creating kms repository: %w", err)
@@ -105,8 +217,11 @@ func New(conf *Config) (*Controller, error) {
 	); err != nil {
 		return nil, fmt.Errorf("error adding config keys to kms: %w", err)
 	}
+	if gc := c.conf.RawConfig.Controller.GrantsCache; gc != nil && gc.Enabled {
+		c.grantsCache = iam.NewGrantsCache(gc.VersionCheckIntervalDuration)
+	}
 	c.IamRepoFn = func() (*iam.Repository, error) {
-		return iam.NewRepository(dbase, dbase, c.kms, iam.WithRandomReader(c.conf.SecureRandomReader))
+		return iam.NewRepository(dbase, dbase, c.kms, iam.WithRandomReader(c.conf.SecureRandomReader), iam.WithGrantsCache(c.grantsCache))
 	}
 	c.StaticHostRepoFn = func() (*static.Repository, error) {
 		return static.NewRepository(dbase, dbase, c.kms)
@@ -114,7 +229,9 @@ func New(conf *Config) (*Controller, error) {
 	c.AuthTokenRepoFn = func() (*authtoken.Repository, error) {
 		return authtoken.NewRepository(dbase, dbase, c.kms,
 			authtoken.WithTokenTimeToLiveDuration(c.conf.RawConfig.Controller.AuthTokenTimeToLiveDuration),
-			authtoken.WithTokenTimeToStaleDuration(c.conf.RawConfig.Controller.AuthTokenTimeToStaleDuration))
+			authtoken.WithTokenTimeToStaleDuration(c.conf.RawConfig.Controller.AuthTokenTimeToStaleDuration),
+			authtoken.WithTokenMaxLifetimeDuration(c.conf.RawConfig.Controller.AuthTokenMaxLifetimeDuration),
+			authtoken.WithReadOnly(c.conf.RawConfig.Controller.ReadOnly))
 	}
 	c.ServersRepoFn = func() (*servers.Repository, error) {
 		return servers.NewRepository(dbase, dbase, c.kms)
@@ -128,9 +245,118 @@ func New(conf *Config) (*Controller, error) {
 	c.SessionRepoFn = func() (*session.Repository, error) {
 		return session.NewRepository(dbase, dbase, c.kms)
 	}
+	c.AnnotationRepoFn = func() (*annotation.Repository, error) {
+		return annotation.NewRepository(dbase, dbase)
+	}
+	c.SecurityEventRepoFn = func() (*securityevent.Repository, error) {
+		return securityevent.NewRepository(dbase, dbase)
+	}
+	c.UsageRepoFn = func() (*usage.Repository, error) {
+		return usage.NewRepository(dbase, dbase)
+	}
+	c.EventSinkRepoFn = func() (*eventsink.Repository, error) {
+		return eventsink.NewRepository(dbase, dbase, c.kms)
+	}
+	if !c.conf.RawConfig.Controller.ReadOnly {
+		c.usageMeter = usage.NewMeter()
+	}
+	if sf := c.conf.RawConfig.Controller.SecretFingerprints; sf != nil {
+		key := []byte(sf.Key)
+		c.SecretFingerprintRepoFn = func() (*secretfingerprint.Repository, error) {
+			return secretfingerprint.NewRepository(dbase, dbase, key)
+		}
+	}
+
+	if dc := c.conf.RawConfig.DnsCache; dc != nil && dc.Enabled {
+		c.webhookClient = newWebhookClient(dnscache.NewResolver(dc.TtlDuration, dc.NegativeTtlDuration))
+	}
+
+	c.Outbox, err = outbox.NewDispatcher(dbase, dbase)
+	if err != nil {
+		return nil, fmt.Errorf("error creating outbox dispatcher: %w", err)
+	}
+	eventSinks, err := eventsink.NewDeliverer(c.EventSinkRepoFn, c.webhookClient, c.logger.Named("event-sinks"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating event sink deliverer: %w", err)
+	}
+	for _, kind := range scopedEventKinds {
+		if err := c.Outbox.RegisterHandler(kind, eventSinks.Deliver); err != nil {
+			return nil, fmt.Errorf("error registering event sink handler: %w", err)
+		}
+	}
 
 	c.workerAuthCache = cache.New(0, 0)
 
+	if rc := c.conf.RawConfig.Controller.ResponseCache; rc != nil && rc.Enabled {
+		c.responseCache = handlers.NewResponseCache(rc.TimeToLiveDuration, rc.MaxEntries)
+	}
+
+	var strategy string
+	var tagWeights map[string]int
+	if ws := c.conf.RawConfig.Controller.WorkerSelection; ws != nil {
+		strategy, tagWeights = ws.Strategy, ws.TagWeights
+		if ws.Affinity {
+			c.workerAffinity = ws.AffinityWindowDuration
+			if c.workerAffinity <= 0 {
+				c.workerAffinity = servers.DefaultWorkerAffinityWindow
+			}
+		}
+	}
+	if c.workerSelector, err = servers.NewWorkerSelector(strategy, tagWeights); err != nil {
+		return nil, fmt.Errorf("error creating worker selector: %w", err)
+	}
+
+	if c.uiAssets, err = newUiAssets(c.conf.RawConfig.Controller.UiAssets, c.logger.Named("ui-assets")); err != nil {
+		return nil, fmt.Errorf("error creating ui asset source: %w", err)
+	}
+
+	if c.listenerAccess, err = newListenerAccess(c.conf.RawConfig.Controller.ListenerAccess); err != nil {
+		return nil, fmt.Errorf("error creating listener access lists: %w", err)
+	}
+
+	if c.workerAccess, err = newWorkerAccess(c.conf.RawConfig.Controller.WorkerAccess); err != nil {
+		return nil, fmt.Errorf("error creating worker access allow list: %w", err)
+	}
+
+	if c.requestLimiter, err = newRequestLimiter(c.conf.RawConfig.Controller.RequestLimits); err != nil {
+		return nil, fmt.Errorf("error creating request limits: %w", err)
+	}
+
+	if ph := c.conf.RawConfig.Controller.PolicyHook; ph != nil {
+		c.policyHook, err = policyhook.NewEvaluator(&policyhook.Hook{
+			Url:           ph.Url,
+			Token:         ph.Token,
+			Scope:         policyhook.Scope(ph.Scope),
+			Timeout:       ph.TimeoutDuration,
+			FailurePolicy: policyhook.FailurePolicy(ph.FailurePolicy),
+			DecisionLog:   ph.DecisionLog,
+		}, c.webhookClient, c.logger.Named("policy-hook"))
+		if err != nil {
+			return nil, fmt.Errorf("error creating policy hook: %w", err)
+		}
+	}
+
+	if c.piiRetention, err = newPiiRetention(c.conf.RawConfig.Controller.PiiRetentionDurations); err != nil {
+		return nil, fmt.Errorf("error creating pii retention: %w", err)
+	}
+
+	if hooks := c.conf.RawConfig.Controller.AuthHooks; len(hooks) > 0 {
+		runnerHooks := make([]*authhook.Hook, 0, len(hooks))
+		for _, h := range hooks {
+			runnerHooks = append(runnerHooks, &authhook.Hook{
+				Stage:         authhook.Stage(h.Stage),
+				Url:           h.Url,
+				Secret:        h.Secret,
+				AuthMethodIds: h.AuthMethodIds,
+				Timeout:       h.TimeoutDuration,
+				FailurePolicy: authhook.FailurePolicy(h.FailurePolicy),
+			})
+		}
+		if c.authHooks, err = authhook.NewRunner(runnerHooks, c.webhookClient, c.logger.Named("auth-hooks")); err != nil {
+			return nil, fmt.Errorf("error creating authentication hooks: %w", err)
+		}
+	}
+
 	return c, nil
 }
 
@@ -145,23 +371,72 @@ func (c *Controller) Start() error {
 		return fmt.Errorf("error starting controller listeners: %w", err)
 	}
 
-	c.startStatusTicking(c.baseContext)
-	c.startRecoveryNonceCleanupTicking(c.baseContext)
-	c.startTerminateCompletedSessionsTicking(c.baseContext)
+	if c.responseCache != nil {
+		c.unregisterResponseCache = db.RegisterCommitListener(c.responseCache.InvalidateChanges)
+	}
+	c.startDbBloatSamplingTicking(c.baseContext)
+	if c.uiAssets != nil {
+		c.startUiManifestRefreshTicking(c.baseContext)
+	}
+	if c.schema.ready() {
+		c.startBackgroundJobs(c.baseContext)
+	} else {
+		// The background jobs use the schema of this binary
+		ctx := c.baseContext
+		c.awaitSchema(ctx, func() { c.startBackgroundJobs(ctx) })
+	}
 	c.started.Store(true)
 
 	return nil
 }
 
+// startBackgroundJobs starts the background jobs which use the database.
+func (c *Controller) startBackgroundJobs(cancelCtx context.Context) {
+	// Read-only controllers still report their status, so the cluster lists
+	// them with their mode when the database accepts it
+	c.startStatusTicking(cancelCtx)
+	if c.conf.RawConfig.Controller.ReadOnly {
+		// The other background jobs write to the database
+		c.logger.Info("controller is read-only, not starting background jobs")
+		return
+	}
+	c.startRecoveryNonceCleanupTicking(cancelCtx)
+	c.startConnectionCheckCleanupTicking(cancelCtx)
+	c.startDisableInactiveUsersTicking(cancelCtx)
+	c.startDeleteExpiredPrincipalRolesTicking(cancelCtx)
+	c.startTerminateCompletedSessionsTicking(cancelCtx)
+	c.startOutboxDispatchTicking(cancelCtx)
+	c.startListenerAccessDenialTicking(cancelCtx)
+	c.startSecurityEventRollupTicking(cancelCtx)
+	c.startUsageTicking(cancelCtx)
+	if len(c.piiRetention) > 0 {
+		c.startPiiRedactionTicking(cancelCtx)
+	}
+	if len(db.ColumnRenames) > 0 {
+		c.startColumnRenameBackfillTicking(cancelCtx)
+	}
+	if c.conf.RawConfig.Controller.AsyncOplog {
+		c.startOplogFlushTicking(cancelCtx)
+	}
+}
+
 func (c *Controller) Shutdown(serversOnly bool) error {
 	if !c.started.Load() {
 		c.logger.Info("already shut down, skipping")
 		return nil
 	}
 	c.baseCancel()
+	if c.unregisterResponseCache != nil {
+		c.unregisterResponseCache()
+		c.unregisterResponseCache = nil
+	}
 	if err := c.stopListeners(serversOnly); err != nil {
 		return fmt.Errorf("error stopping controller listeners: %w", err)
 	}
+	// The statements are prepared again if the controller is restarted
+	if err := c.stmtCache.Close(); err != nil {
+		c.logger.Error("error closing statement cache", "error", err)
+	}
 	c.started.Store(false)
 	return nil
 }
@@ -173,3 +448,23 @@ func (c *Controller) Shutdown(serversOnly bool) error {
 func (c *Controller) WorkerStatusUpdateTimes() *sync.Map {
 	return c.workerStatusUpdateTimes
 }
+
+// newWebhookClient returns a client for calling webhooks which looks up their
+// hosts with the resolver.
+func newWebhookClient(r *dnscache.Resolver) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = r.DialContext(&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	})
+	return &http.Client{Transport: transport}
+}
+
+// searchHistory returns the page of the oplog entries matching the query,
+// decrypted with the oplog wrappers of their scopes and rendered using types.
+func (c *Controller) searchHistory(ctx context.Context, q *oplog.SearchQuery, types *oplog.TypeCatalog) (*oplog.HistoryPage, error) {
+	wrapperFn := func(ctx context.Context, scopeId string) (wrapping.Wrapper, error) {
+		return c.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	}
+	return oplog.SearchHistory(ctx, c.conf.Database, q, wrapperFn, types)
+}
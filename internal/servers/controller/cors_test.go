@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -239,7 +240,7 @@ func TestHandler_CORS(t *testing.T) {
 			// If options and we expect it to be successful, run some checks
 			if req.Method == http.MethodOptions && c.code == http.StatusNoContent {
 				assert.Equal(t, fmt.Sprintf("%s, %s, %s, %s, %s", http.MethodDelete, http.MethodGet, http.MethodOptions, http.MethodPost, http.MethodPatch), resp.HttpResponse().Header.Get("Access-Control-Allow-Methods"))
-				assert.Equal(t, fmt.Sprintf("%s, %s, %s, %s", "Content-Type", "X-Requested-With", "Authorization", "X-Foobar"), resp.HttpResponse().Header.Get("Access-Control-Allow-Headers"))
+				assert.Equal(t, fmt.Sprintf("%s, %s, %s, %s, %s", "Content-Type", "X-Requested-With", "Authorization", "X-Request-Id", "X-Foobar"), resp.HttpResponse().Header.Get("Access-Control-Allow-Headers"))
 				assert.Equal(t, "300", resp.HttpResponse().Header.Get("Access-Control-Max-Age"))
 			}
 
@@ -247,7 +248,65 @@ func TestHandler_CORS(t *testing.T) {
 			if c.origin != "" && c.code == http.StatusOK && c.listenerNum > 1 {
 				assert.Equal(t, c.origin, resp.HttpResponse().Header.Get("Access-Control-Allow-Origin"))
 				assert.Equal(t, "Origin", resp.HttpResponse().Header.Get("Vary"))
+				assert.Equal(t, "X-Request-Id", resp.HttpResponse().Header.Get("Access-Control-Expose-Headers"))
 			}
 		})
 	}
 }
+
+func TestHandler_ListenerHeaders(t *testing.T) {
+	cfg, err := config.Parse(`
+listener "tcp" {
+	purpose = "api"
+	cors_enabled = true
+	cors_allowed_origins = ["https://admin.example.com"]
+	cors_allowed_methods = ["GET", "OPTIONS"]
+	cors_exposed_headers = ["X-Total-Count"]
+	cors_max_age = "1h"
+	cors_allow_credentials = true
+	hsts_max_age = "24h"
+	ui_content_security_policy = "frame-ancestors https://portal.example.com"
+}
+`)
+	require.NoError(t, err)
+	props := HandlerProperties{ListenerConfig: cfg.Listeners[0]}
+	headers, err := config.ParseListenerHeaders(props.ListenerConfig)
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := wrapHandlerWithSecurityHeaders(wrapHandlerWithCors(wrapHandlerWithUiHeaders(next, headers), props, headers), headers)
+
+	t.Run("preflight", func(t *testing.T) {
+		assert := assert.New(t)
+		r := httptest.NewRequest(http.MethodOptions, "/v1/targets", nil)
+		r.Header.Set("Origin", "https://admin.example.com")
+		r.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(http.StatusNoContent, w.Code)
+		assert.Equal("GET, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal("3600", w.Header().Get("Access-Control-Max-Age"))
+		assert.Equal("true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal("max-age=86400", w.Header().Get("Strict-Transport-Security"))
+
+		r = httptest.NewRequest(http.MethodOptions, "/v1/targets", nil)
+		r.Header.Set("Origin", "https://admin.example.com")
+		r.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(http.StatusMethodNotAllowed, w.Code)
+	})
+	t.Run("request", func(t *testing.T) {
+		assert := assert.New(t)
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Origin", "https://admin.example.com")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Equal("X-Request-Id, X-Total-Count", w.Header().Get("Access-Control-Expose-Headers"))
+		assert.Equal("max-age=86400", w.Header().Get("Strict-Transport-Security"))
+		assert.Equal("frame-ancestors https://portal.example.com", w.Header().Get("Content-Security-Policy"))
+	})
+}
@@ -4,20 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/eventschema"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/policyhook"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/accounts"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/authmethods"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/host_sets"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/sessions"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/targets"
+	"github.com/hashicorp/boundary/internal/servers/controller/openapi"
+	"github.com/hashicorp/boundary/internal/usage"
 	"github.com/hashicorp/boundary/sdk/strutil"
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/shared-secure-libs/configutil"
@@ -50,14 +57,214 @@ func (c *Controller) handler(props HandlerProperties) (http.Handler, error) {
 	if err != nil {
 		return nil, err
 	}
+	mux.Handle("/health", handleHealth(c))
+	mux.Handle("/capabilities", handleCapabilities(c))
+	mux.Handle("/auth-discovery", handleAuthDiscovery(c))
+	mux.Handle("/openapi.json", handleOpenApi(c))
+	mux.Handle("/events/schemas", handleEventSchemas(c))
+	mux.Handle("/events/schemas/", handleEventSchemas(c))
+	if c.conf.RawConfig.Controller.EnableSwagger {
+		mux.Handle("/swagger", handleSwagger())
+	}
 	mux.Handle("/v1/", h)
-	mux.Handle("/", handleUi(c))
-
-	corsWrappedHandler := wrapHandlerWithCors(mux, props)
-	commonWrappedHandler := wrapHandlerWithCommonFuncs(corsWrappedHandler, c, props)
+	headers, err := config.ParseListenerHeaders(props.ListenerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid listener headers: %w", err)
+	}
+	mux.Handle("/", wrapHandlerWithUiHeaders(handleUi(c), headers))
+
+	schemaGateHandler := wrapHandlerWithSchemaGate(mux, c)
+	readOnlyHandler := wrapHandlerWithReadOnly(schemaGateHandler, c)
+	corsWrappedHandler := wrapHandlerWithCors(readOnlyHandler, props, headers)
+	securityHeadersHandler := wrapHandlerWithSecurityHeaders(corsWrappedHandler, headers)
+	requestLimitsHandler := wrapHandlerWithRequestLimits(securityHeadersHandler, c)
+	commonWrappedHandler := wrapHandlerWithCommonFuncs(requestLimitsHandler, c, props)
 	printablePathCheckHandler := cleanhttp.PrintablePathCheckHandler(commonWrappedHandler, nil)
+	listenerAccessHandler := wrapHandlerWithListenerAccess(printablePathCheckHandler, c)
+
+	return listenerAccessHandler, nil
+}
+
+// healthResponse is the response of the health endpoint.
+type healthResponse struct {
+	// Mode is "read-only" if the controller only serves reads, otherwise
+	// "read-write"
+	Mode     string `json:"mode"`
+	FipsMode string `json:"fips_mode"`
+	// Kms holds the health of the KMSes which check it, by purpose: "ok" or
+	// the error of their last check
+	Kms map[string]string `json:"kms,omitempty"`
+	// Schema is the status of the database schema for the binary of the
+	// controller
+	Schema *schemaHealth `json:"schema,omitempty"`
+}
+
+// schemaHealth is the status of the database schema: "ok", or "pending"
+// while the controller waits for the database to be migrated to the version
+// of its binary.
+type schemaHealth struct {
+	Status        string `json:"status"`
+	Version       uint   `json:"version"`
+	BinaryVersion uint   `json:"binary_version"`
+}
+
+// healthChecker is implemented by KMS wrappers which check their health
+// periodically, such as PKCS#11 wrappers.
+type healthChecker interface {
+	Health() error
+}
+
+// handleHealth serves the health of the controller: its mode, its FIPS mode,
+// the health of its KMSes and the status of the database schema. It responds
+// with 503 if a KMS is unhealthy or the controller waits for the database to
+// be migrated.
+func handleHealth(c *Controller) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		resp := &healthResponse{Mode: c.mode(), FipsMode: c.conf.FipsMode.String()}
+		status := http.StatusOK
+		for purpose, wrapper := range map[string]interface{}{
+			"root":        c.conf.RootKms,
+			"worker-auth": c.conf.WorkerAuthKms,
+			"recovery":    c.conf.RecoveryKms,
+		} {
+			hc, ok := wrapper.(healthChecker)
+			if !ok {
+				continue
+			}
+			if resp.Kms == nil {
+				resp.Kms = map[string]string{}
+			}
+			resp.Kms[purpose] = "ok"
+			if err := hc.Health(); err != nil {
+				resp.Kms[purpose] = err.Error()
+				status = http.StatusServiceUnavailable
+			}
+		}
+		if s := c.schema.get(); s != nil {
+			resp.Schema = &schemaHealth{Status: "ok", Version: s.Current, BinaryVersion: s.Required}
+			if !c.schema.ready() {
+				resp.Schema.Status = "pending"
+				status = http.StatusServiceUnavailable
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			c.logger.Error("failed to send health response", "error", err)
+		}
+	})
+}
+
+// handleOpenApi serves the OpenAPI v3 document of the API.
+func handleOpenApi(c *Controller) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		doc, err := openapi.Document()
+		if err != nil {
+			c.logger.Error("failed to build openapi document", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(doc); err != nil {
+			c.logger.Error("failed to send openapi document", "error", err)
+		}
+	})
+}
+
+// eventSchemasResponse is the response listing the event schemas.
+type eventSchemasResponse struct {
+	Schemas []*eventSchemaInfo `json:"schemas"`
+}
+
+type eventSchemaInfo struct {
+	Kind    string `json:"kind"`
+	Version int    `json:"version"`
+	Url     string `json:"url"`
+}
+
+// handleEventSchemas serves the schemas of event payloads: the list of
+// schemas at /events/schemas, and each version of them at
+// /events/schemas/<kind>/v<version>.
+func handleEventSchemas(c *Controller) http.Handler {
+	const prefix = "/events/schemas"
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+		if path == "" {
+			resp := &eventSchemasResponse{Schemas: []*eventSchemaInfo{}}
+			for _, kind := range eventschema.Kinds() {
+				for _, s := range eventschema.Versions(kind) {
+					resp.Schemas = append(resp.Schemas, &eventSchemaInfo{
+						Kind:    s.Kind,
+						Version: s.Version,
+						Url:     fmt.Sprintf("%s/%s/v%d", prefix, s.Kind, s.Version),
+					})
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(resp); err != nil {
+				c.logger.Error("failed to send event schemas response", "error", err)
+			}
+			return
+		}
 
-	return printablePathCheckHandler, nil
+		var s *eventschema.Schema
+		if parts := strings.Split(path, "/"); len(parts) == 2 && strings.HasPrefix(parts[1], "v") {
+			if version, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v")); err == nil {
+				s = eventschema.Lookup(parts[0], version)
+			}
+		}
+		if s == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/schema+json")
+		if _, err := io.WriteString(w, s.Document); err != nil {
+			c.logger.Error("failed to send event schema", "error", err)
+		}
+	})
+}
+
+// swaggerPage renders the OpenAPI document with Swagger UI, which is loaded
+// from a CDN so it doesn't need to be bundled with Boundary.
+const swaggerPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Boundary Controller HTTP API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@3.38.0/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@3.38.0/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// handleSwagger serves a Swagger UI for the OpenAPI document of the API.
+func handleSwagger() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, swaggerPage)
+	})
 }
 
 func handleGrpcGateway(c *Controller, props HandlerProperties) (http.Handler, error) {
@@ -82,56 +289,56 @@ func handleGrpcGateway(c *Controller, props HandlerProperties) (http.Handler, er
 		runtime.WithErrorHandler(handlers.ErrorHandler(c.logger)),
 		runtime.WithForwardResponseOption(handlers.OutgoingInterceptor),
 	)
-	hcs, err := host_catalogs.NewService(c.StaticHostRepoFn, c.IamRepoFn)
+	hcs, err := host_catalogs.NewService(c.StaticHostRepoFn, c.IamRepoFn, handlers.WithAnnotations(c.AnnotationRepoFn))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create host catalog handler service: %w", err)
 	}
 	if err := services.RegisterHostCatalogServiceHandlerServer(ctx, mux, hcs); err != nil {
 		return nil, fmt.Errorf("failed to register host catalog service handler: %w", err)
 	}
-	hss, err := host_sets.NewService(c.StaticHostRepoFn)
+	hss, err := host_sets.NewService(c.StaticHostRepoFn, handlers.WithAnnotations(c.AnnotationRepoFn))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create host set handler service: %w", err)
 	}
 	if err := services.RegisterHostSetServiceHandlerServer(ctx, mux, hss); err != nil {
 		return nil, fmt.Errorf("failed to register host set service handler: %w", err)
 	}
-	hs, err := hosts.NewService(c.StaticHostRepoFn)
+	hs, err := hosts.NewService(c.StaticHostRepoFn, handlers.WithAnnotations(c.AnnotationRepoFn))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create host handler service: %w", err)
 	}
 	if err := services.RegisterHostServiceHandlerServer(ctx, mux, hs); err != nil {
 		return nil, fmt.Errorf("failed to register host service handler: %w", err)
 	}
-	accts, err := accounts.NewService(c.PasswordAuthRepoFn)
+	accts, err := accounts.NewService(c.PasswordAuthRepoFn, handlers.WithAnnotations(c.AnnotationRepoFn))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create account handler service: %w", err)
 	}
 	if err := services.RegisterAccountServiceHandlerServer(ctx, mux, accts); err != nil {
 		return nil, fmt.Errorf("failed to register account service handler: %w", err)
 	}
-	authMethods, err := authmethods.NewService(c.kms, c.PasswordAuthRepoFn, c.IamRepoFn, c.AuthTokenRepoFn)
+	authMethods, err := authmethods.NewService(c.kms, c.PasswordAuthRepoFn, c.IamRepoFn, c.AuthTokenRepoFn, handlers.WithResponseCache(c.responseCache), handlers.WithSecurityEvents(c.SecurityEventRepoFn), handlers.WithAuthHooks(c.authHooks), handlers.WithAnnotations(c.AnnotationRepoFn))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create auth method handler service: %w", err)
 	}
 	if err := services.RegisterAuthMethodServiceHandlerServer(ctx, mux, authMethods); err != nil {
 		return nil, fmt.Errorf("failed to register auth method service handler: %w", err)
 	}
-	authtoks, err := authtokens.NewService(c.AuthTokenRepoFn, c.IamRepoFn)
+	authtoks, err := authtokens.NewService(c.AuthTokenRepoFn, c.IamRepoFn, handlers.WithKms(c.kms))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create auth token handler service: %w", err)
 	}
 	if err := services.RegisterAuthTokenServiceHandlerServer(ctx, mux, authtoks); err != nil {
 		return nil, fmt.Errorf("failed to register auth token service handler: %w", err)
 	}
-	os, err := scopes.NewService(c.IamRepoFn)
+	os, err := scopes.NewService(c.IamRepoFn, handlers.WithResponseCache(c.responseCache), handlers.WithAnnotations(c.AnnotationRepoFn), handlers.WithSecurityEvents(c.SecurityEventRepoFn), handlers.WithUsage(c.UsageRepoFn), handlers.WithEventSinks(c.EventSinkRepoFn))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create scope handler service: %w", err)
 	}
 	if err := services.RegisterScopeServiceHandlerServer(ctx, mux, os); err != nil {
 		return nil, fmt.Errorf("failed to register scope service handler: %w", err)
 	}
-	us, err := users.NewService(c.IamRepoFn)
+	us, err := users.NewService(c.IamRepoFn, handlers.WithAnnotations(c.AnnotationRepoFn))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user handler service: %w", err)
 	}
@@ -144,21 +351,26 @@ func handleGrpcGateway(c *Controller, props HandlerProperties) (http.Handler, er
 		c.IamRepoFn,
 		c.ServersRepoFn,
 		c.SessionRepoFn,
-		c.StaticHostRepoFn)
+		c.StaticHostRepoFn,
+		handlers.WithWorkerSelector(c.workerSelector),
+		handlers.WithWorkerAffinity(c.workerAffinity),
+		handlers.WithSecretFingerprints(c.SecretFingerprintRepoFn),
+		handlers.WithAnnotations(c.AnnotationRepoFn),
+		handlers.WithHistory(c.searchHistory))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create target handler service: %w", err)
 	}
 	if err := services.RegisterTargetServiceHandlerServer(ctx, mux, ts); err != nil {
 		return nil, fmt.Errorf("failed to register target service handler: %w", err)
 	}
-	gs, err := groups.NewService(c.IamRepoFn)
+	gs, err := groups.NewService(c.IamRepoFn, handlers.WithAnnotations(c.AnnotationRepoFn))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create group handler service: %w", err)
 	}
 	if err := services.RegisterGroupServiceHandlerServer(ctx, mux, gs); err != nil {
 		return nil, fmt.Errorf("failed to register group service handler: %w", err)
 	}
-	rs, err := roles.NewService(c.IamRepoFn)
+	rs, err := roles.NewService(c.IamRepoFn, handlers.WithAnnotations(c.AnnotationRepoFn))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create role handler service: %w", err)
 	}
@@ -186,6 +398,27 @@ func generatedTraceId() string {
 	return fmt.Sprintf("gtraceid_%s", t)
 }
 
+// maxRequestIdLength is the maximum length of request IDs provided by clients.
+const maxRequestIdLength = 128
+
+// requestId returns the ID of a request: the one provided by the client if it
+// is valid, otherwise a generated one. Provided IDs are logged, so only
+// letters, digits, '-', '_' and '.' are allowed.
+func requestId(r *http.Request) string {
+	id := r.Header.Get(handlers.RequestIdHeader)
+	if id == "" || len(id) > maxRequestIdLength {
+		return generatedTraceId()
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return generatedTraceId()
+		}
+	}
+	return id
+}
+
 func wrapHandlerWithCommonFuncs(h http.Handler, c *Controller, props HandlerProperties) http.Handler {
 	var maxRequestDuration time.Duration
 	var maxRequestSize int64
@@ -216,10 +449,17 @@ func wrapHandlerWithCommonFuncs(h http.Handler, c *Controller, props HandlerProp
 		// Set the Cache-Control header for all responses returned
 		w.Header().Set("Cache-Control", "no-store")
 
+		// Every response has the ID of its request so errors can be matched
+		// with the logs of the controller
+		reqId := requestId(r)
+		w.Header().Set(handlers.RequestIdHeader, reqId)
+
 		// Start with the request context and our timeout
 		ctx, cancelFunc := context.WithTimeout(r.Context(), maxRequestDuration)
 		defer cancelFunc()
 
+		ctx = context.WithValue(ctx, globals.ContextRequestIdTypeKey, reqId)
+
 		// Add a size limiter if desired
 		if maxRequestSize > 0 {
 			ctx = context.WithValue(ctx, globals.ContextMaxRequestSizeTypeKey, maxRequestSize)
@@ -234,6 +474,10 @@ func wrapHandlerWithCommonFuncs(h http.Handler, c *Controller, props HandlerProp
 
 		requestInfo.PublicId, requestInfo.EncryptedToken, requestInfo.TokenFormat = auth.GetTokenFromRequest(c.logger, c.kms, r)
 		ctx = auth.NewVerifierContext(ctx, c.logger, c.IamRepoFn, c.AuthTokenRepoFn, c.ServersRepoFn, c.kms, requestInfo)
+		if c.policyHook != nil {
+			ctx = policyhook.NewContext(ctx, c.policyHook)
+		}
+		ctx = usage.NewContext(ctx, c.usageMeter)
 
 		// Set the context back on the request
 		r = r.WithContext(ctx)
@@ -242,14 +486,8 @@ func wrapHandlerWithCommonFuncs(h http.Handler, c *Controller, props HandlerProp
 	})
 }
 
-func wrapHandlerWithCors(h http.Handler, props HandlerProperties) http.Handler {
-	allowedMethods := []string{
-		http.MethodDelete,
-		http.MethodGet,
-		http.MethodOptions,
-		http.MethodPost,
-		http.MethodPatch,
-	}
+func wrapHandlerWithCors(h http.Handler, props HandlerProperties, headers *config.ListenerHeaders) http.Handler {
+	allowedMethods := headers.CorsAllowedMethods
 
 	allowedOrigins := props.ListenerConfig.CorsAllowedOrigins
 
@@ -257,8 +495,13 @@ func wrapHandlerWithCors(h http.Handler, props HandlerProperties) http.Handler {
 		"Content-Type",
 		"X-Requested-With",
 		"Authorization",
+		handlers.RequestIdHeader,
 	}, props.ListenerConfig.CorsAllowedHeaders...)
 
+	exposedHeaders := append([]string{handlers.RequestIdHeader}, headers.CorsExposedHeaders...)
+
+	maxAge := strconv.FormatInt(int64(headers.CorsMaxAge/time.Second), 10)
+
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		if !props.ListenerConfig.CorsEnabled {
 			h.ServeHTTP(w, req)
@@ -304,20 +547,50 @@ func wrapHandlerWithCors(h http.Handler, props HandlerProperties) http.Handler {
 
 		w.Header().Set("Access-Control-Allow-Origin", origin)
 		w.Header().Set("Vary", "Origin")
+		if headers.CorsAllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
 
 		// Apply headers for preflight requests
 		if req.Method == http.MethodOptions {
 			w.Header().Set("Access-Control-Allow-Methods", strings.Join(allowedMethods, ", "))
 			w.Header().Set("Access-Control-Allow-Headers", strings.Join(allowedHeaders, ", "))
-			w.Header().Set("Access-Control-Max-Age", "300")
+			w.Header().Set("Access-Control-Max-Age", maxAge)
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
 
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(exposedHeaders, ", "))
+
 		h.ServeHTTP(w, req)
 	})
 }
 
+// wrapHandlerWithSecurityHeaders sets the Strict-Transport-Security header of
+// the listener on all responses, if it has one.
+func wrapHandlerWithSecurityHeaders(h http.Handler, headers *config.ListenerHeaders) http.Handler {
+	hsts := headers.StrictTransportSecurity()
+	if hsts == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", hsts)
+		h.ServeHTTP(w, r)
+	})
+}
+
+// wrapHandlerWithUiHeaders sets the Content-Security-Policy header of the
+// listener on the responses of the admin UI, if it has one.
+func wrapHandlerWithUiHeaders(h http.Handler, headers *config.ListenerHeaders) http.Handler {
+	if headers.UiContentSecurityPolicy == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", headers.UiContentSecurityPolicy)
+		h.ServeHTTP(w, r)
+	})
+}
+
 /*
 func WrapForwardedForHandler(h http.Handler, authorizedAddrs []*sockaddr.SockAddrMarshaler, rejectNotPresent, rejectNonAuthz bool, hopSkips int) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
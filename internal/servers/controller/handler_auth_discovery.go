@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/auth/password"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/hashicorp/boundary/version"
+)
+
+// authDiscoveryMaxAge is how long clients and proxies may cache the auth
+// discovery document for, in seconds.
+const authDiscoveryMaxAge = 60
+
+// authDiscoveryResponse is the response of the auth discovery endpoint.
+type authDiscoveryResponse struct {
+	Version string `json:"version"`
+	// PrimaryAuthMethod is the auth method clients should offer to log in
+	// with; it is omitted if there is none
+	PrimaryAuthMethod *authMethodDiscovery `json:"primary_auth_method,omitempty"`
+}
+
+type authMethodDiscovery struct {
+	Id          string `json:"id"`
+	ScopeId     string `json:"scope_id"`
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// AuthenticateUrl is the path of the API clients log in with
+	AuthenticateUrl string `json:"authenticate_url"`
+	// Attributes are the login parameters specific to the type of the auth
+	// method
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// primaryAuthMethod returns the auth method configured as primary or, if none
+// is, the oldest password auth method of the global scope. It returns nil if
+// there is none.
+func (c *Controller) primaryAuthMethod(ctx context.Context) (*password.AuthMethod, error) {
+	repo, err := c.PasswordAuthRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	if id := c.conf.RawConfig.Controller.PrimaryAuthMethodId; id != "" {
+		if auth.SubtypeFromId(id) != auth.PasswordSubtype {
+			return nil, fmt.Errorf("unsupported primary auth method %q", id)
+		}
+		return repo.LookupAuthMethod(ctx, id)
+	}
+	ams, err := repo.ListAuthMethods(ctx, scope.Global.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(ams) == 0 {
+		return nil, nil
+	}
+	sort.Slice(ams, func(i, j int) bool {
+		ti, tj := ams[i].GetCreateTime().GetTimestamp().AsTime(), ams[j].GetCreateTime().GetTimestamp().AsTime()
+		if ti.Equal(tj) {
+			return ams[i].GetPublicId() < ams[j].GetPublicId()
+		}
+		return ti.Before(tj)
+	})
+	return ams[0], nil
+}
+
+// authDiscovery returns the discovery document of the auth methods.
+func (c *Controller) authDiscovery(ctx context.Context) (*authDiscoveryResponse, error) {
+	resp := &authDiscoveryResponse{Version: version.Get().VersionNumber()}
+	am, err := c.primaryAuthMethod(ctx)
+	if err != nil || am == nil {
+		return resp, err
+	}
+	resp.PrimaryAuthMethod = &authMethodDiscovery{
+		Id:              am.GetPublicId(),
+		ScopeId:         am.GetScopeId(),
+		Type:            auth.PasswordSubtype.String(),
+		Name:            am.GetName(),
+		Description:     am.GetDescription(),
+		AuthenticateUrl: fmt.Sprintf("/v1/auth-methods/%s:authenticate", am.GetPublicId()),
+		Attributes: map[string]interface{}{
+			"min_login_name_length": am.GetMinLoginNameLength(),
+			"min_password_length":   am.GetMinPasswordLength(),
+		},
+	}
+	return resp, nil
+}
+
+// handleAuthDiscovery serves the primary auth method with the parameters
+// clients need to render its login flow, and the version of the controller.
+// It isn't authenticated, since clients request it before logging in, and
+// may be cached for authDiscoveryMaxAge.
+func handleAuthDiscovery(c *Controller) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		resp, err := c.authDiscovery(r.Context())
+		if err != nil {
+			c.logger.Error("failed to discover auth methods", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		b, err := json.Marshal(resp)
+		if err != nil {
+			c.logger.Error("failed to encode auth discovery response", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		sum := sha256.Sum256(b)
+		etag := strconv.Quote(hex.EncodeToString(sum[:]))
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", authDiscoveryMaxAge))
+		if match := r.Header.Get("If-None-Match"); match != "" && strings.Contains(match, etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		if r.Method == http.MethodHead {
+			return
+		}
+		if _, err := w.Write(b); err != nil {
+			c.logger.Error("failed to send auth discovery response", "error", err)
+		}
+	})
+}
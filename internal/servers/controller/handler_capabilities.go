@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/host"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/boundary/version"
+)
+
+// resourceActions are the actions the API authorizes on each type of
+// resource.
+var resourceActions = map[resource.Type][]action.Type{
+	resource.Scope:       {action.Create, action.Read, action.Update, action.Delete, action.List},
+	resource.User:        {action.Create, action.Read, action.Update, action.Delete, action.List, action.AddAccounts, action.SetAccounts, action.RemoveAccounts},
+	resource.Group:       {action.Create, action.Read, action.Update, action.Delete, action.List, action.AddMembers, action.SetMembers, action.RemoveMembers},
+	resource.Role:        {action.Create, action.Read, action.Update, action.Delete, action.List, action.AddPrincipals, action.SetPrincipals, action.RemovePrincipals, action.AddGrants, action.SetGrants, action.RemoveGrants, action.RequestAccess, action.Approve, action.Deny},
+	resource.AuthMethod:  {action.Create, action.Read, action.Update, action.Delete, action.List, action.Authenticate},
+	resource.Account:     {action.Create, action.Read, action.Update, action.Delete, action.List, action.SetPassword, action.ChangePassword},
+	resource.AuthToken:   {action.Read, action.Delete, action.List},
+	resource.HostCatalog: {action.Create, action.Read, action.Update, action.Delete, action.List},
+	resource.HostSet:     {action.Create, action.Read, action.Update, action.Delete, action.List, action.AddHostSets, action.SetHostSets, action.RemoveHostSets},
+	resource.Host:        {action.Create, action.Read, action.Update, action.Delete, action.List},
+	resource.Target:      {action.Create, action.Read, action.Update, action.Delete, action.List, action.AddHostSets, action.SetHostSets, action.RemoveHostSets, action.AuthorizeSession, action.TestConnection},
+	resource.Session:     {action.Read, action.ReadSelf, action.List, action.Cancel, action.CancelSelf},
+}
+
+// deprecatedActions are the actions which are still authorized on a type of
+// resource but will be removed, with the action replacing them, if any.
+// Clients should move their grants and requests to the replacement before
+// the action is removed from resourceActions.
+var deprecatedActions = map[resource.Type]map[action.Type]action.Type{}
+
+// resourceSubtypes are the subtypes of each type of resource which has them.
+var resourceSubtypes = map[resource.Type][]string{
+	resource.AuthMethod:  {auth.PasswordSubtype.String()},
+	resource.Account:     {auth.PasswordSubtype.String()},
+	resource.HostCatalog: {host.StaticSubtype.String()},
+	resource.HostSet:     {host.StaticSubtype.String()},
+	resource.Host:        {host.StaticSubtype.String()},
+	resource.Target:      {target.TcpSubType.String()},
+}
+
+// capabilitiesResponse is the response of the capabilities endpoint.
+type capabilitiesResponse struct {
+	Version   string                  `json:"version"`
+	Resources []*resourceCapabilities `json:"resources"`
+	// Features holds whether each optional feature of the controller is
+	// enabled by its configuration
+	Features map[string]bool `json:"features"`
+}
+
+type resourceCapabilities struct {
+	Type     string              `json:"type"`
+	Subtypes []string            `json:"subtypes,omitempty"`
+	Actions  []*actionCapability `json:"actions"`
+}
+
+type actionCapability struct {
+	Name       string `json:"name"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+	// ReplacedBy is the action replacing a deprecated action, if any
+	ReplacedBy string `json:"replaced_by,omitempty"`
+}
+
+// capabilities returns the resource types, actions, subtypes and features
+// this controller supports.
+func (c *Controller) capabilities() *capabilitiesResponse {
+	resp := &capabilitiesResponse{
+		Version:   version.Get().VersionNumber(),
+		Resources: make([]*resourceCapabilities, 0, len(resourceActions)),
+		Features:  map[string]bool{},
+	}
+	for typ, actions := range resourceActions {
+		rc := &resourceCapabilities{
+			Type:     typ.String(),
+			Subtypes: resourceSubtypes[typ],
+			Actions:  make([]*actionCapability, 0, len(actions)),
+		}
+		for _, a := range actions {
+			ac := &actionCapability{Name: a.String()}
+			if replacement, ok := deprecatedActions[typ][a]; ok {
+				ac.Deprecated = true
+				if replacement != action.Unknown {
+					ac.ReplacedBy = replacement.String()
+				}
+			}
+			rc.Actions = append(rc.Actions, ac)
+		}
+		resp.Resources = append(resp.Resources, rc)
+	}
+	sort.Slice(resp.Resources, func(i, j int) bool {
+		return resp.Resources[i].Type < resp.Resources[j].Type
+	})
+
+	conf := c.conf.RawConfig.Controller
+	resp.Features["read_only"] = conf.ReadOnly
+	resp.Features["async_oplog"] = conf.AsyncOplog
+	resp.Features["swagger"] = conf.EnableSwagger
+	resp.Features["response_cache"] = conf.ResponseCache != nil
+	resp.Features["request_limits"] = conf.RequestLimits != nil
+	resp.Features["auth_hooks"] = len(conf.AuthHooks) > 0
+	resp.Features["secret_fingerprints"] = conf.SecretFingerprints != nil
+	resp.Features["worker_affinity"] = conf.WorkerSelection != nil && conf.WorkerSelection.Affinity
+	return resp
+}
+
+// handleCapabilities serves the resource types, actions, subtypes and
+// features this controller supports, so clients of other versions can find
+// out what they may use rather than failing on what is unknown.
+func handleCapabilities(c *Controller) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(c.capabilities()); err != nil {
+			c.logger.Error("failed to send capabilities response", "error", err)
+		}
+	})
+}
@@ -26,6 +26,9 @@ var handleUi = func(c *Controller) http.Handler {
 	if c.conf.RawConfig.PassthroughDirectory != "" {
 		return devPassthroughHandler(c.logger, c.conf.RawConfig.PassthroughDirectory)
 	}
+	if c.uiAssets != nil {
+		return c.uiAssets
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 	})
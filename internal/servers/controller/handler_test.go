@@ -8,8 +8,16 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/eventschema"
+	"github.com/hashicorp/boundary/internal/libs/fips"
+	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/servers/controller/openapi"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -213,3 +221,275 @@ func TestHandleImplementedPaths(t *testing.T) {
 		}
 	}
 }
+
+func TestHealthHandler(t *testing.T) {
+	c := NewTestController(t, nil)
+	defer c.Shutdown()
+
+	resp, err := http.Get(fmt.Sprintf("%s/health", c.ApiAddrs()[0]))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Got response: %v", resp)
+
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	body := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(b, &body))
+	assert.Equal(t, fips.Resolve(false).String(), body["fips_mode"])
+	assert.Equal(t, readWriteMode, body["mode"])
+	if assert.IsType(t, map[string]interface{}{}, body["schema"]) {
+		schema := body["schema"].(map[string]interface{})
+		assert.Equal(t, "ok", schema["status"])
+		assert.Equal(t, schema["binary_version"], schema["version"])
+	}
+
+	resp, err = http.Post(fmt.Sprintf("%s/health", c.ApiAddrs()[0]), "application/json", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestOpenApiHandler(t *testing.T) {
+	c := NewTestController(t, nil)
+	defer c.Shutdown()
+
+	resp, err := http.Get(fmt.Sprintf("%s/openapi.json", c.ApiAddrs()[0]))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Got response: %v", resp)
+
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	body := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(b, &body))
+	assert.Equal(t, openapi.Version, body["openapi"])
+
+	// Swagger UI is disabled by default
+	resp, err = http.Get(fmt.Sprintf("%s/swagger", c.ApiAddrs()[0]))
+	require.NoError(t, err)
+	b, err = ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "swagger-ui")
+}
+
+func TestCapabilitiesHandler(t *testing.T) {
+	conf, err := config.DevController()
+	require.NoError(t, err)
+	conf.Controller.EnableSwagger = true
+	c := NewTestController(t, &TestControllerOpts{Config: conf})
+	defer c.Shutdown()
+
+	resp, err := http.Get(fmt.Sprintf("%s/capabilities", c.ApiAddrs()[0]))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Got response: %v", resp)
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var caps capabilitiesResponse
+	require.NoError(t, json.Unmarshal(b, &caps))
+	assert.NotEmpty(t, caps.Version)
+	assert.True(t, caps.Features["swagger"])
+	assert.False(t, caps.Features["read_only"])
+
+	require.Len(t, caps.Resources, len(resourceActions))
+	var tgt *resourceCapabilities
+	for _, r := range caps.Resources {
+		if r.Type == "target" {
+			tgt = r
+		}
+	}
+	require.NotNil(t, tgt)
+	assert.Equal(t, []string{"tcp"}, tgt.Subtypes)
+	var names []string
+	for _, a := range tgt.Actions {
+		names = append(names, a.Name)
+	}
+	assert.Contains(t, names, "authorize-session")
+
+	resp, err = http.Post(fmt.Sprintf("%s/capabilities", c.ApiAddrs()[0]), "application/json", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestAuthDiscoveryHandler(t *testing.T) {
+	c := NewTestController(t, &TestControllerOpts{
+		DefaultAuthMethodId: "ampw_1234567890",
+	})
+	defer c.Shutdown()
+
+	resp, err := http.Get(fmt.Sprintf("%s/auth-discovery", c.ApiAddrs()[0]))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Got response: %v", resp)
+	assert.Equal(t, "public, max-age=60", resp.Header.Get("Cache-Control"))
+	etag := resp.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var disc authDiscoveryResponse
+	require.NoError(t, json.Unmarshal(b, &disc))
+	assert.NotEmpty(t, disc.Version)
+	require.NotNil(t, disc.PrimaryAuthMethod)
+	assert.Equal(t, "ampw_1234567890", disc.PrimaryAuthMethod.Id)
+	assert.Equal(t, "global", disc.PrimaryAuthMethod.ScopeId)
+	assert.Equal(t, "password", disc.PrimaryAuthMethod.Type)
+	assert.Equal(t, "/v1/auth-methods/ampw_1234567890:authenticate", disc.PrimaryAuthMethod.AuthenticateUrl)
+	assert.Contains(t, disc.PrimaryAuthMethod.Attributes, "min_password_length")
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/auth-discovery", c.ApiAddrs()[0]), nil)
+	require.NoError(t, err)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	resp, err = http.Post(fmt.Sprintf("%s/auth-discovery", c.ApiAddrs()[0]), "application/json", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestCapabilities_Deprecated(t *testing.T) {
+	deprecatedActions[resource.HostSet] = map[action.Type]action.Type{action.AddHostSets: action.AddHosts}
+	defer delete(deprecatedActions, resource.HostSet)
+
+	c := &Controller{conf: &Config{RawConfig: &config.Config{Controller: &config.Controller{}}}}
+	for _, r := range c.capabilities().Resources {
+		if r.Type != resource.HostSet.String() {
+			continue
+		}
+		for _, a := range r.Actions {
+			if a.Name == action.AddHostSets.String() {
+				assert.True(t, a.Deprecated)
+				assert.Equal(t, action.AddHosts.String(), a.ReplacedBy)
+			} else {
+				assert.False(t, a.Deprecated, a.Name)
+			}
+		}
+	}
+}
+
+func TestSwaggerHandler(t *testing.T) {
+	conf, err := config.DevController()
+	require.NoError(t, err)
+	conf.Controller.EnableSwagger = true
+	c := NewTestController(t, &TestControllerOpts{Config: conf})
+	defer c.Shutdown()
+
+	resp, err := http.Get(fmt.Sprintf("%s/swagger", c.ApiAddrs()[0]))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Got response: %v", resp)
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "/openapi.json")
+}
+
+func TestReadOnlyController(t *testing.T) {
+	conf, err := config.DevController()
+	require.NoError(t, err)
+	conf.Controller.ReadOnly = true
+	c := NewTestController(t, &TestControllerOpts{Config: conf})
+	defer c.Shutdown()
+
+	resp, err := http.Get(fmt.Sprintf("%s/health", c.ApiAddrs()[0]))
+	require.NoError(t, err)
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	body := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(b, &body))
+	assert.Equal(t, readOnlyMode, body["mode"])
+
+	resp, err = http.Get(fmt.Sprintf("%s/v1/scopes", c.ApiAddrs()[0]))
+	require.NoError(t, err)
+	assert.NotEqual(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	resp, err = http.Post(fmt.Sprintf("%s/v1/scopes", c.ApiAddrs()[0]), "application/json", strings.NewReader(`{"scope_id": "global"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	b, err = ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "read-only")
+
+	// The test database accepts writes, so the controller reports its mode in
+	// the servers of the cluster
+	repo := c.ServersRepo()
+	require.Eventually(t, func() bool {
+		controllers, err := repo.ListServers(c.Context(), servers.ServerTypeController)
+		if err != nil {
+			return false
+		}
+		for _, s := range controllers {
+			if s.Name == conf.Controller.Name {
+				return s.Mode == readOnlyMode
+			}
+		}
+		return false
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
+func TestEventSchemasHandler(t *testing.T) {
+	c := NewTestController(t, nil)
+	defer c.Shutdown()
+
+	resp, err := http.Get(fmt.Sprintf("%s/events/schemas", c.ApiAddrs()[0]))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Got response: %v", resp)
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var list eventSchemasResponse
+	require.NoError(t, json.Unmarshal(b, &list))
+	require.NotEmpty(t, list.Schemas)
+
+	for _, s := range list.Schemas {
+		resp, err := http.Get(fmt.Sprintf("%s%s", c.ApiAddrs()[0], s.Url))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "Got response: %v", resp)
+		b, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, eventschema.Lookup(s.Kind, s.Version).Document, string(b))
+	}
+
+	for _, p := range []string{"unknown/v1", "iam.access_request/v0", "iam.access_request/1", "iam.access_request"} {
+		resp, err := http.Get(fmt.Sprintf("%s/events/schemas/%s", c.ApiAddrs()[0], p))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, p)
+	}
+}
+
+func TestRequestId(t *testing.T) {
+	c := NewTestController(t, nil)
+	defer c.Shutdown()
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "provided", header: "client-req_1.2", want: "client-req_1.2"},
+		{name: "generated"},
+		{name: "invalid", header: "bad id!"},
+		{name: "too-long", header: strings.Repeat("a", maxRequestIdLength+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/targets/ttcp_1234567890", c.ApiAddrs()[0]), nil)
+			require.NoError(err)
+			if tt.header != "" {
+				req.Header[handlers.RequestIdHeader] = []string{tt.header}
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(err)
+			defer resp.Body.Close()
+
+			id := resp.Header.Get(handlers.RequestIdHeader)
+			if tt.want != "" {
+				assert.Equal(tt.want, id)
+			} else {
+				assert.True(strings.HasPrefix(id, "gtraceid_"), id)
+			}
+
+			b, err := ioutil.ReadAll(resp.Body)
+			require.NoError(err)
+			body := make(map[string]interface{})
+			require.NoError(json.Unmarshal(b, &body))
+			assert.Equal(id, body["request_id"])
+			assert.NotEmpty(body["code"])
+			assert.NotContains(body, "retryable")
+		})
+	}
+}
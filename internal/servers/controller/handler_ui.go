@@ -14,6 +14,9 @@ func init() {
 }
 
 func handleUiWithAssets(c *Controller) http.Handler {
+	if c.conf.RawConfig.PassthroughDirectory == "" && c.uiAssets != nil {
+		return c.uiAssets
+	}
 	var nextHandler http.Handler
 	if c.conf.RawConfig.PassthroughDirectory != "" {
 		nextHandler = devPassthroughHandler(c.logger, c.conf.RawConfig.PassthroughDirectory)
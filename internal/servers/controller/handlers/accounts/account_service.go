@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/boundary/internal/types/action"
 	"github.com/hashicorp/boundary/internal/types/resource"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -29,19 +30,26 @@ func init() {
 	}
 }
 
+// defaultDisabledReason is the reason recorded for disabling an account when
+// none is given.
+const defaultDisabledReason = "disabled by an administrator"
+
 // Service handles request as described by the pbs.AccountServiceServer interface.
 type Service struct {
 	pbs.UnimplementedAccountServiceServer
 
-	repoFn common.PasswordAuthRepoFactory
+	repoFn           common.PasswordAuthRepoFactory
+	annotationRepoFn common.AnnotationRepoFactory
 }
 
 // NewService returns a user service which handles user related requests to boundary.
-func NewService(repo common.PasswordAuthRepoFactory) (Service, error) {
+// Supported options: handlers.WithAnnotations.
+func NewService(repo common.PasswordAuthRepoFactory, opt ...handlers.Option) (Service, error) {
 	if repo == nil {
 		return Service{}, fmt.Errorf("nil password repository provided")
 	}
-	return Service{repoFn: repo}, nil
+	opts := handlers.GetOpts(opt...)
+	return Service{repoFn: repo, annotationRepoFn: opts.WithAnnotations}, nil
 }
 
 var _ pbs.AccountServiceServer = Service{}
@@ -59,8 +67,17 @@ func (s Service) ListAccounts(ctx context.Context, req *pbs.ListAccountsRequest)
 	if err != nil {
 		return nil, err
 	}
+	ids := make([]string, 0, len(ul))
+	for _, item := range ul {
+		ids = append(ids, item.GetId())
+	}
+	annotations, err := handlers.ListAnnotations(ctx, s.annotationRepoFn, ids)
+	if err != nil {
+		return nil, err
+	}
 	for _, item := range ul {
 		item.Scope = authResults.Scope
+		item.Annotations = annotations[item.GetId()]
 	}
 	return &pbs.ListAccountsResponse{Items: ul}, nil
 }
@@ -78,6 +95,9 @@ func (s Service) GetAccount(ctx context.Context, req *pbs.GetAccountRequest) (*p
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.GetAccountResponse{Item: u}, nil
 }
@@ -112,6 +132,9 @@ func (s Service) UpdateAccount(ctx context.Context, req *pbs.UpdateAccountReques
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.UpdateAnnotations(ctx, s.annotationRepoFn, resource.Account, req.GetId(), req.GetItem().GetAnnotations(), req.GetUpdateMask().GetPaths()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.UpdateAccountResponse{Item: u}, nil
 }
@@ -133,11 +156,23 @@ func (s Service) DeleteAccount(ctx context.Context, req *pbs.DeleteAccountReques
 }
 
 // ChangePassword implements the interface pbs.AccountServiceServer.
+// The id may be auth.SelfId to change the password of the caller's own
+// account, which is allowed without a grant for the change-password action.
 func (s Service) ChangePassword(ctx context.Context, req *pbs.ChangePasswordRequest) (*pbs.ChangePasswordResponse, error) {
+	var authOpts []auth.Option
+	if req.GetId() == auth.SelfId {
+		_, accountId, err := auth.LookupSelf(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req = proto.Clone(req).(*pbs.ChangePasswordRequest)
+		req.Id = accountId
+		authOpts = append(authOpts, auth.WithSelf(true))
+	}
 	if err := validateChangePasswordRequest(req); err != nil {
 		return nil, err
 	}
-	_, authResults := s.parentAndAuthResult(ctx, req.GetId(), action.ChangePassword)
+	_, authResults := s.parentAndAuthResult(ctx, req.GetId(), action.ChangePassword, authOpts...)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
@@ -145,6 +180,9 @@ func (s Service) ChangePassword(ctx context.Context, req *pbs.ChangePasswordRequ
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.ChangePasswordResponse{Item: u}, nil
 }
@@ -162,6 +200,9 @@ func (s Service) SetPassword(ctx context.Context, req *pbs.SetPasswordRequest) (
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.SetPasswordResponse{Item: u}, nil
 }
@@ -181,7 +222,11 @@ func (s Service) getFromRepo(ctx context.Context, id string) (*pb.Account, error
 	if u == nil {
 		return nil, handlers.NotFoundErrorf("Account %q doesn't exist.", id)
 	}
-	return toProto(u)
+	d, err := repo.LookupAccountDisabled(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toProto(u, d)
 }
 
 func (s Service) createInRepo(ctx context.Context, authMethodId, scopeId string, item *pb.Account) (*pb.Account, error) {
@@ -217,7 +262,7 @@ func (s Service) createInRepo(ctx context.Context, authMethodId, scopeId string,
 	if out == nil {
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to create user but no error returned from repository.")
 	}
-	return toProto(out)
+	return toProto(out, nil)
 }
 
 func (s Service) updateInRepo(ctx context.Context, scopeId, authMethId, id string, mask []string, item *pb.Account) (*pb.Account, error) {
@@ -244,26 +289,59 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, authMethId, id strin
 	version := item.GetVersion()
 
 	dbMask := maskManager.Translate(mask)
-	if len(dbMask) == 0 {
+	// disabled and annotations are not stored with the account, so they are not
+	// in the db mask
+	updateDisabled := handlers.MaskContains(mask, "disabled")
+	if len(dbMask) == 0 && !updateDisabled && len(handlers.AnnotationsMask(mask)) == 0 {
 		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
 	}
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	out, rowsUpdated, err := repo.UpdateAccount(ctx, scopeId, u, version, dbMask)
-	if err != nil {
-		switch {
-		case errors.Is(err, password.ErrTooShort):
-			return nil, handlers.InvalidArgumentErrorf("Error in provided request.",
-				map[string]string{"attributes.login_name": "Length too short."})
+	var out *password.Account
+	if len(dbMask) > 0 {
+		var rowsUpdated int
+		out, rowsUpdated, err = repo.UpdateAccount(ctx, scopeId, u, version, dbMask)
+		if err != nil {
+			switch {
+			case errors.Is(err, password.ErrTooShort):
+				return nil, handlers.InvalidArgumentErrorf("Error in provided request.",
+					map[string]string{"attributes.login_name": "Length too short."})
+			}
+			return nil, fmt.Errorf("unable to update auth method: %w", err)
+		}
+		if rowsUpdated == 0 {
+			return nil, handlers.NotFoundErrorf("Account %q doesn't exist or incorrect version provided.", id)
+		}
+	} else {
+		out, err = repo.LookupAccount(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil || out.GetVersion() != version {
+			return nil, handlers.NotFoundErrorf("Account %q doesn't exist or incorrect version provided.", id)
+		}
+	}
+	if updateDisabled {
+		if item.GetDisabled().GetValue() {
+			reason := item.GetDisabledReason().GetValue()
+			if reason == "" {
+				reason = defaultDisabledReason
+			}
+			err = repo.DisableAccount(ctx, scopeId, id, reason)
+		} else {
+			err = repo.EnableAccount(ctx, scopeId, id)
+		}
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("unable to update auth method: %w", err)
 	}
-	if rowsUpdated == 0 {
-		return nil, handlers.NotFoundErrorf("Account %q doesn't exist or incorrect version provided.", id)
+	d, err := repo.LookupAccountDisabled(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	return toProto(out)
+	return toProto(out, d)
 }
 
 func (s Service) deleteFromRepo(ctx context.Context, scopeId, id string) (bool, error) {
@@ -290,9 +368,17 @@ func (s Service) listFromRepo(ctx context.Context, authMethodId string) ([]*pb.A
 	if err != nil {
 		return nil, err
 	}
+	dl, err := repo.ListDisabledAccounts(ctx, authMethodId)
+	if err != nil {
+		return nil, err
+	}
+	disabled := make(map[string]*password.AccountDisabled, len(dl))
+	for _, d := range dl {
+		disabled[d.GetAuthAccountId()] = d
+	}
 	var outUl []*pb.Account
 	for _, u := range ul {
-		ou, err := toProto(u)
+		ou, err := toProto(u, disabled[u.GetPublicId()])
 		if err != nil {
 			return nil, err
 		}
@@ -323,7 +409,11 @@ func (s Service) changePasswordInRepo(ctx context.Context, scopeId, id string, v
 	if out == nil {
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.PermissionDenied, "Failed to change password.")
 	}
-	return toProto(out)
+	d, err := repo.LookupAccountDisabled(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toProto(out, d)
 }
 
 func (s Service) setPasswordInRepo(ctx context.Context, scopeId, id string, version uint32, pw string) (*pb.Account, error) {
@@ -342,10 +432,14 @@ func (s Service) setPasswordInRepo(ctx context.Context, scopeId, id string, vers
 		}
 		return nil, fmt.Errorf("unable to set password: %w", err)
 	}
-	return toProto(out)
+	d, err := repo.LookupAccountDisabled(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toProto(out, d)
 }
 
-func (s Service) parentAndAuthResult(ctx context.Context, id string, a action.Type) (*password.AuthMethod, auth.VerifyResults) {
+func (s Service) parentAndAuthResult(ctx context.Context, id string, a action.Type, opt ...auth.Option) (*password.AuthMethod, auth.VerifyResults) {
 	res := auth.VerifyResults{}
 	repo, err := s.repoFn()
 	if err != nil {
@@ -383,10 +477,12 @@ func (s Service) parentAndAuthResult(ctx context.Context, id string, a action.Ty
 		return nil, res
 	}
 	opts = append(opts, auth.WithScopeId(authMeth.GetScopeId()), auth.WithPin(parentId))
+	opts = append(opts, opt...)
 	return authMeth, auth.Verify(ctx, opts...)
 }
 
-func toProto(in *password.Account) (*pb.Account, error) {
+// toProto returns the API account of in, which is disabled if d is set.
+func toProto(in *password.Account, d *password.AccountDisabled) (*pb.Account, error) {
 	out := pb.Account{
 		Id:           in.GetPublicId(),
 		CreatedTime:  in.GetCreateTime().GetTimestamp(),
@@ -401,6 +497,11 @@ func toProto(in *password.Account) (*pb.Account, error) {
 	if in.GetName() != "" {
 		out.Name = &wrapperspb.StringValue{Value: in.GetName()}
 	}
+	if d != nil {
+		out.Disabled = &wrapperspb.BoolValue{Value: true}
+		out.DisabledReason = &wrapperspb.StringValue{Value: d.GetReason()}
+		out.DisabledTime = d.GetCreateTime().GetTimestamp()
+	}
 	if st, err := handlers.ProtoToStruct(&pb.PasswordAccountAttributes{LoginName: in.GetLoginName()}); err == nil {
 		out.Attributes = st
 	} else {
@@ -424,6 +525,9 @@ func validateCreateRequest(req *pbs.CreateAccountRequest) error {
 		if req.GetItem().GetAuthMethodId() == "" {
 			badFields["auth_method_id"] = "This field is required."
 		}
+		if req.GetItem().GetDisabled() != nil || req.GetItem().GetDisabledReason() != nil || req.GetItem().GetDisabledTime() != nil {
+			badFields["disabled"] = "Accounts can only be disabled once created."
+		}
 		switch auth.SubtypeFromId(req.GetItem().GetAuthMethodId()) {
 		case auth.PasswordSubtype:
 			if req.GetItem().GetType() != "" && req.GetItem().GetType() != auth.PasswordSubtype.String() {
@@ -456,6 +560,12 @@ func validateUpdateRequest(req *pbs.UpdateAccountRequest) error {
 				badFields["attributes"] = "Attribute fields do not match the expected format."
 			}
 		}
+		if req.GetItem().GetDisabledReason() != nil && !req.GetItem().GetDisabled().GetValue() {
+			badFields["disabled_reason"] = "Can only be set when disabling the account."
+		}
+		if req.GetItem().GetDisabledTime() != nil {
+			badFields["disabled_time"] = "This is a read only field."
+		}
 		return badFields
 	})
 }
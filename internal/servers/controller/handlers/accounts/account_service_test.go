@@ -811,6 +811,73 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdate_Disabled(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrap := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrap)
+	repoFn := func() (*password.Repository, error) {
+		return password.NewRepository(rw, rw, kms)
+	}
+
+	o, _ := iam.TestScopes(t, iam.TestRepo(t, conn, wrap))
+	am := password.TestAuthMethods(t, conn, o.GetPublicId(), 1)[0]
+	acct := password.TestAccounts(t, conn, am.GetPublicId(), 1)[0]
+	tested, err := accounts.NewService(repoFn)
+	require.NoError(err)
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(o.GetPublicId()))
+
+	_, err = tested.UpdateAccount(ctx, &pbs.UpdateAccountRequest{
+		Id:         acct.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"disabled_time"}},
+		Item:       &pb.Account{Version: acct.GetVersion()},
+	})
+	assert.Truef(errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), "got error %v, wanted invalid argument", err)
+	_, err = tested.UpdateAccount(ctx, &pbs.UpdateAccountRequest{
+		Id:         acct.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"disabled", "disabled_reason"}},
+		Item:       &pb.Account{Version: acct.GetVersion(), DisabledReason: wrapperspb.String("reason")},
+	})
+	assert.Truef(errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), "got error %v, wanted invalid argument", err)
+
+	got, err := tested.UpdateAccount(ctx, &pbs.UpdateAccountRequest{
+		Id:         acct.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"disabled", "disabled_reason"}},
+		Item: &pb.Account{
+			Version:        acct.GetVersion(),
+			Disabled:       wrapperspb.Bool(true),
+			DisabledReason: wrapperspb.String("left the company"),
+		},
+	})
+	require.NoError(err)
+	assert.True(got.GetItem().GetDisabled().GetValue())
+	assert.Equal("left the company", got.GetItem().GetDisabledReason().GetValue())
+	assert.NotNil(got.GetItem().GetDisabledTime())
+	assert.Equal(acct.GetVersion(), got.GetItem().GetVersion(), "disabling does not change the account")
+
+	read, err := tested.GetAccount(ctx, &pbs.GetAccountRequest{Id: acct.GetPublicId()})
+	require.NoError(err)
+	assert.True(read.GetItem().GetDisabled().GetValue())
+
+	got, err = tested.UpdateAccount(ctx, &pbs.UpdateAccountRequest{
+		Id:         acct.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"disabled"}},
+		Item:       &pb.Account{Version: acct.GetVersion()},
+	})
+	require.NoError(err)
+	assert.Nil(got.GetItem().GetDisabled())
+	assert.Nil(got.GetItem().GetDisabledReason())
+	assert.Nil(got.GetItem().GetDisabledTime())
+
+	_, err = tested.UpdateAccount(ctx, &pbs.UpdateAccountRequest{
+		Id:         acct.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"disabled"}},
+		Item:       &pb.Account{Version: acct.GetVersion() + 1, Disabled: wrapperspb.Bool(true)},
+	})
+	assert.Truef(errors.Is(err, handlers.NotFoundError()), "got error %v, wanted not found", err)
+}
+
 func TestSetPassword(t *testing.T) {
 	conn, _ := db.TestSetup(t, "postgres")
 	rw := db.New(conn)
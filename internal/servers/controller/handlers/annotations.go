@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/annotation"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
+	"github.com/hashicorp/boundary/internal/types/resource"
+)
+
+// annotatedResource is an API resource with annotations.
+type annotatedResource interface {
+	GetAnnotations() map[string]string
+}
+
+// AnnotationsMask returns the paths of the update mask which update the
+// annotations of a resource: annotation.FieldName to replace all of them, or
+// annotation.FieldName followed by a dot and a key to update a single one.
+// They are not stored with the resource, so they are never translated by a
+// MaskManager.
+func AnnotationsMask(paths []string) []string {
+	var ret []string
+	for _, p := range paths {
+		switch {
+		case strings.EqualFold(p, annotation.FieldName),
+			len(p) > len(annotation.FieldName)+1 && strings.EqualFold(p[:len(annotation.FieldName)+1], annotation.FieldName+"."):
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+// LookupAnnotations returns the annotations of the resource, or nil if the
+// service handler has no annotation repository.
+func LookupAnnotations(ctx context.Context, repoFn common.AnnotationRepoFactory, id string) (map[string]string, error) {
+	if repoFn == nil {
+		return nil, nil
+	}
+	repo, err := repoFn()
+	if err != nil {
+		return nil, err
+	}
+	return repo.LookupAnnotations(ctx, id)
+}
+
+// ListAnnotations returns the annotations of the resources by id, or nil if
+// the service handler has no annotation repository.
+func ListAnnotations(ctx context.Context, repoFn common.AnnotationRepoFactory, ids []string) (map[string]map[string]string, error) {
+	if repoFn == nil {
+		return nil, nil
+	}
+	repo, err := repoFn()
+	if err != nil {
+		return nil, err
+	}
+	return repo.ListAnnotations(ctx, ids)
+}
+
+// UpdateAnnotations updates the annotations of the resource named by the
+// annotation paths of the update mask to their values in annotations and
+// returns all of its annotations. Without annotation paths it returns the
+// current annotations of the resource.
+func UpdateAnnotations(ctx context.Context, repoFn common.AnnotationRepoFactory, resType resource.Type, id string, annotations map[string]string, mask []string) (map[string]string, error) {
+	paths := AnnotationsMask(mask)
+	if len(paths) == 0 {
+		return LookupAnnotations(ctx, repoFn, id)
+	}
+	if repoFn == nil {
+		return nil, InvalidArgumentErrorf("Error in provided request.", map[string]string{annotation.FieldName: "Annotations are not supported."})
+	}
+	repo, err := repoFn()
+	if err != nil {
+		return nil, err
+	}
+	ret, err := repo.UpdateAnnotations(ctx, resType, id, annotations, paths)
+	if err != nil {
+		if errors.Is(err, errors.ErrInvalidParameter) {
+			return nil, InvalidArgumentErrorf("Error in provided request.", map[string]string{annotation.FieldName: err.Error()})
+		}
+		return nil, err
+	}
+	return ret, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/boundary/internal/auth"
 	"github.com/hashicorp/boundary/internal/auth/password"
 	"github.com/hashicorp/boundary/internal/auth/password/store"
+	"github.com/hashicorp/boundary/internal/authhook"
 	"github.com/hashicorp/boundary/internal/authtoken"
 	"github.com/hashicorp/boundary/internal/errors"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/authmethods"
@@ -17,6 +18,7 @@ import (
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/securityevent"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/types/action"
@@ -27,8 +29,10 @@ import (
 )
 
 const (
-	loginNameKey = "login_name"
-	pwKey        = "password"
+	loginNameKey    = "login_name"
+	pwKey           = "password"
+	recoveryCodeKey = "recovery_code"
+	totpCodeKey     = "totp_code"
 )
 
 var (
@@ -50,10 +54,18 @@ type Service struct {
 	pwRepoFn  common.PasswordAuthRepoFactory
 	iamRepoFn common.IamRepoFactory
 	atRepoFn  common.AuthTokenRepoFactory
+
+	responseCache       *handlers.ResponseCache
+	securityEventRepoFn common.SecurityEventRepoFactory
+	authHooks           *authhook.Runner
+	annotationRepoFn    common.AnnotationRepoFactory
 }
 
-// NewService returns a auth method service which handles auth method related requests to boundary.
-func NewService(kms *kms.Kms, pwRepoFn common.PasswordAuthRepoFactory, iamRepoFn common.IamRepoFactory, atRepoFn common.AuthTokenRepoFactory) (Service, error) {
+// NewService returns a auth method service which handles auth method related
+// requests to boundary. Supported options: handlers.WithResponseCache,
+// handlers.WithSecurityEvents, handlers.WithAuthHooks and
+// handlers.WithAnnotations.
+func NewService(kms *kms.Kms, pwRepoFn common.PasswordAuthRepoFactory, iamRepoFn common.IamRepoFactory, atRepoFn common.AuthTokenRepoFactory, opt ...handlers.Option) (Service, error) {
 	if kms == nil {
 		return Service{}, stderrors.New("nil kms provided")
 	}
@@ -63,7 +75,8 @@ func NewService(kms *kms.Kms, pwRepoFn common.PasswordAuthRepoFactory, iamRepoFn
 	if iamRepoFn == nil {
 		return Service{}, fmt.Errorf("nil iam repository provided")
 	}
-	return Service{kms: kms, pwRepoFn: pwRepoFn, iamRepoFn: iamRepoFn, atRepoFn: atRepoFn}, nil
+	opts := handlers.GetOpts(opt...)
+	return Service{kms: kms, pwRepoFn: pwRepoFn, iamRepoFn: iamRepoFn, atRepoFn: atRepoFn, responseCache: opts.WithResponseCache, securityEventRepoFn: opts.WithSecurityEvents, authHooks: opts.WithAuthHooks, annotationRepoFn: opts.WithAnnotations}, nil
 }
 
 var _ pbs.AuthMethodServiceServer = Service{}
@@ -77,14 +90,30 @@ func (s Service) ListAuthMethods(ctx context.Context, req *pbs.ListAuthMethodsRe
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
+	cacheKey := handlers.ResponseCacheKey("ListAuthMethods", authResults.Scope.GetId(), authResults.GrantsHash)
+	if cached, ok := s.responseCache.Get(resource.AuthMethod, cacheKey); ok {
+		return cached.(*pbs.ListAuthMethodsResponse), nil
+	}
+	generation := s.responseCache.Generation(resource.AuthMethod)
 	ul, err := s.listFromRepo(ctx, authResults.Scope.GetId())
 	if err != nil {
 		return nil, err
 	}
+	ids := make([]string, 0, len(ul))
+	for _, item := range ul {
+		ids = append(ids, item.GetId())
+	}
+	annotations, err := handlers.ListAnnotations(ctx, s.annotationRepoFn, ids)
+	if err != nil {
+		return nil, err
+	}
 	for _, item := range ul {
 		item.Scope = authResults.Scope
+		item.Annotations = annotations[item.GetId()]
 	}
-	return &pbs.ListAuthMethodsResponse{Items: ul}, nil
+	resp := &pbs.ListAuthMethodsResponse{Items: ul}
+	s.responseCache.Set(resource.AuthMethod, generation, cacheKey, resp)
+	return resp, nil
 }
 
 // GetAuthMethod implements the interface pbs.AuthMethodServiceServer.
@@ -100,6 +129,9 @@ func (s Service) GetAuthMethod(ctx context.Context, req *pbs.GetAuthMethodReques
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.GetAuthMethodResponse{Item: u}, nil
 }
@@ -134,6 +166,9 @@ func (s Service) UpdateAuthMethod(ctx context.Context, req *pbs.UpdateAuthMethod
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.UpdateAnnotations(ctx, s.annotationRepoFn, resource.AuthMethod, req.GetId(), req.GetItem().GetAnnotations(), req.GetUpdateMask().GetPaths()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.UpdateAuthMethodResponse{Item: u}, nil
 }
@@ -164,7 +199,7 @@ func (s Service) Authenticate(ctx context.Context, req *pbs.AuthenticateRequest)
 		return nil, authResults.Error
 	}
 	creds := req.GetCredentials().GetFields()
-	tok, err := s.authenticateWithRepo(ctx, authResults.Scope.GetId(), req.GetAuthMethodId(), creds[loginNameKey].GetStringValue(), creds[pwKey].GetStringValue())
+	tok, err := s.authenticateWithRepo(ctx, authResults.Scope.GetId(), req.GetAuthMethodId(), creds[loginNameKey].GetStringValue(), creds[pwKey].GetStringValue(), creds[recoveryCodeKey].GetStringValue(), creds[totpCodeKey].GetStringValue())
 	if err != nil {
 		return nil, err
 	}
@@ -264,7 +299,18 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, id string, mask []st
 	u.PublicId = id
 	dbMask := maskManager.Translate(mask)
 	if len(dbMask) == 0 {
-		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		if len(handlers.AnnotationsMask(mask)) == 0 {
+			return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		}
+		// Only annotations are updated, which are not stored with the auth method
+		out, err := s.getFromRepo(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if out.GetVersion() != item.GetVersion() {
+			return nil, handlers.NotFoundErrorf("AuthMethod %q doesn't exist or incorrect version provided.", id)
+		}
+		return out, nil
 	}
 	repo, err := s.pwRepoFn()
 	if err != nil {
@@ -295,7 +341,7 @@ func (s Service) deleteFromRepo(ctx context.Context, scopeId, id string) (bool,
 	return rows > 0, nil
 }
 
-func (s Service) authenticateWithRepo(ctx context.Context, scopeId, authMethodId, loginName, pw string) (*pba.AuthToken, error) {
+func (s Service) authenticateWithRepo(ctx context.Context, scopeId, authMethodId, loginName, pw, recoveryCode, totpCode string) (*pba.AuthToken, error) {
 	iamRepo, err := s.iamRepoFn()
 	if err != nil {
 		return nil, err
@@ -309,11 +355,21 @@ func (s Service) authenticateWithRepo(ctx context.Context, scopeId, authMethodId
 		return nil, err
 	}
 
-	acct, err := pwRepo.Authenticate(ctx, scopeId, authMethodId, loginName, pw)
+	// Disabled accounts, and accounts of disabled users, are checked before
+	// their credentials and do not authenticate
+	var acct *password.Account
+	if recoveryCode != "" {
+		acct, err = pwRepo.AuthenticateWithRecoveryCode(ctx, scopeId, authMethodId, loginName, recoveryCode, totpCode)
+	} else {
+		acct, err = pwRepo.Authenticate(ctx, scopeId, authMethodId, loginName, pw)
+	}
 	if err != nil {
 		return nil, err
 	}
 	if acct == nil {
+		if err := s.recordAuthentication(ctx, securityevent.AuthenticationFailed, scopeId, authMethodId); err != nil {
+			return nil, err
+		}
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Unauthenticated, "Unable to authenticate.")
 	}
 
@@ -321,10 +377,34 @@ func (s Service) authenticateWithRepo(ctx context.Context, scopeId, authMethodId
 	if err != nil {
 		return nil, err
 	}
+	hookReq := authhook.Request{
+		ScopeId:      scopeId,
+		AuthMethodId: authMethodId,
+		AccountId:    acct.GetPublicId(),
+		LoginName:    loginName,
+		UserId:       u.GetPublicId(),
+	}
+	preReq := hookReq
+	if err := s.authHooks.Run(ctx, authhook.PreAuthenticate, &preReq); err != nil {
+		return nil, s.authHookError(ctx, err, scopeId, authMethodId)
+	}
 	tok, err := atRepo.CreateAuthToken(ctx, u, acct.GetPublicId())
 	if err != nil {
 		return nil, err
 	}
+	postReq := hookReq
+	postReq.AuthTokenId = tok.GetPublicId()
+	if err := s.authHooks.Run(ctx, authhook.PostAuthenticate, &postReq); err != nil {
+		// A hook failing with the closed policy refuses the token already
+		// issued
+		if _, delErr := atRepo.DeleteAuthToken(ctx, tok.GetPublicId()); delErr != nil {
+			return nil, delErr
+		}
+		return nil, s.authHookError(ctx, err, scopeId, authMethodId)
+	}
+	if err := s.recordAuthentication(ctx, securityevent.AuthenticationSucceeded, scopeId, authMethodId); err != nil {
+		return nil, err
+	}
 
 	token, err := authtoken.EncryptToken(ctx, s.kms, scopeId, tok.GetPublicId(), tok.GetToken())
 	if err != nil {
@@ -350,6 +430,33 @@ func (s Service) authenticateWithRepo(ctx context.Context, scopeId, authMethodId
 	return prot, nil
 }
 
+// recordAuthentication records the outcome of an authentication with the
+// auth method as a security event, if the service records them.
+func (s Service) recordAuthentication(ctx context.Context, kind securityevent.Kind, scopeId, authMethodId string) error {
+	if s.securityEventRepoFn == nil {
+		return nil
+	}
+	repo, err := s.securityEventRepoFn()
+	if err != nil {
+		return err
+	}
+	return repo.Record(ctx, &securityevent.Event{Kind: kind, ScopeId: scopeId, AuthMethodId: authMethodId})
+}
+
+// authHookError returns the API error of an authentication refused by an
+// authentication hook, recording it as a failed authentication, or err if it
+// is not a refusal.
+func (s Service) authHookError(ctx context.Context, err error, scopeId, authMethodId string) error {
+	var denied *authhook.DeniedError
+	if !stderrors.As(err, &denied) {
+		return err
+	}
+	if err := s.recordAuthentication(ctx, securityevent.AuthenticationFailed, scopeId, authMethodId); err != nil {
+		return err
+	}
+	return handlers.ApiErrorWithCodeAndMessage(codes.PermissionDenied, "Authentication denied: %s", denied.Reason)
+}
+
 func (s Service) authResult(ctx context.Context, id string, a action.Type) auth.VerifyResults {
 	res := auth.VerifyResults{}
 
@@ -513,8 +620,16 @@ func validateAuthenticateRequest(req *pbs.AuthenticateRequest) error {
 	if _, ok := creds[loginNameKey]; !ok {
 		badFields["credentials.login_name"] = "This is a required field."
 	}
-	if _, ok := creds[pwKey]; !ok {
+	_, hasPw := creds[pwKey]
+	_, hasRecoveryCode := creds[recoveryCodeKey]
+	switch {
+	case !hasPw && !hasRecoveryCode:
 		badFields["credentials.password"] = "This is a required field."
+	case hasPw && hasRecoveryCode:
+		badFields["credentials.recovery_code"] = "Only one of password or recovery_code can be provided."
+	}
+	if _, ok := creds[totpCodeKey]; hasRecoveryCode && !ok {
+		badFields["credentials.totp_code"] = "This is a required field when recovery_code is provided."
 	}
 	tType := strings.ToLower(strings.TrimSpace(req.GetTokenType()))
 	if tType != "" && tType != "token" && tType != "cookie" {
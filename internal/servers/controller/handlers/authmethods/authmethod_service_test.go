@@ -1108,3 +1108,95 @@ func TestAuthenticate_AuthAccountConnectedToIamUser(t *testing.T) {
 	assert.NotEmpty(aToken.GetToken())
 	assert.True(strings.HasPrefix(aToken.GetToken(), aToken.GetId()))
 }
+
+func TestAuthenticate_RecoveryCode(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	o, _ := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	iamRepoFn := func() (*iam.Repository, error) {
+		return iam.TestRepo(t, conn, wrapper), nil
+	}
+	pwRepoFn := func() (*password.Repository, error) {
+		return password.NewRepository(rw, rw, kms)
+	}
+	atRepoFn := func() (*authtoken.Repository, error) {
+		return authtoken.NewRepository(rw, rw, kms)
+	}
+
+	am := password.TestAuthMethods(t, conn, o.GetPublicId(), 1)[0]
+	acct, err := password.NewAccount(am.GetPublicId(), password.WithLoginName(testLoginName))
+	require.NoError(t, err)
+
+	pwRepo, err := pwRepoFn()
+	require.NoError(t, err)
+	acct, err = pwRepo.CreateAccount(context.Background(), o.GetPublicId(), acct, password.WithPassword(testPassword))
+	require.NoError(t, err)
+	recoveryCodes, err := pwRepo.GenerateRecoveryCodes(context.Background(), o.GetPublicId(), acct.GetPublicId(), 2)
+	require.NoError(t, err)
+
+	credentials := func(fields map[string]string) *structpb.Struct {
+		creds := map[string]*structpb.Value{}
+		for k, v := range fields {
+			creds[k] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: v}}
+		}
+		return &structpb.Struct{Fields: creds}
+	}
+
+	cases := []struct {
+		name    string
+		creds   map[string]string
+		wantErr error
+	}{
+		{
+			name:    "password-and-recovery-code",
+			creds:   map[string]string{"login_name": testLoginName, "password": testPassword, "recovery_code": recoveryCodes.Codes[0], "totp_code": password.TestRecoveryTotpCode(t, recoveryCodes.TotpSecret, 0)},
+			wantErr: handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name:    "missing-totp-code",
+			creds:   map[string]string{"login_name": testLoginName, "recovery_code": recoveryCodes.Codes[0]},
+			wantErr: handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name:    "wrong-recovery-code",
+			creds:   map[string]string{"login_name": testLoginName, "recovery_code": "aaaa-aaaa-aaaa", "totp_code": password.TestRecoveryTotpCode(t, recoveryCodes.TotpSecret, 0)},
+			wantErr: handlers.ApiErrorWithCode(codes.Unauthenticated),
+		},
+		{
+			name:    "wrong-totp-code",
+			creds:   map[string]string{"login_name": testLoginName, "recovery_code": recoveryCodes.Codes[0], "totp_code": "000000"},
+			wantErr: handlers.ApiErrorWithCode(codes.Unauthenticated),
+		},
+		{
+			name:  "valid",
+			creds: map[string]string{"login_name": testLoginName, "recovery_code": recoveryCodes.Codes[0], "totp_code": password.TestRecoveryTotpCode(t, recoveryCodes.TotpSecret, 0)},
+		},
+		{
+			name:    "already-used",
+			creds:   map[string]string{"login_name": testLoginName, "recovery_code": recoveryCodes.Codes[0], "totp_code": password.TestRecoveryTotpCode(t, recoveryCodes.TotpSecret, 1)},
+			wantErr: handlers.ApiErrorWithCode(codes.Unauthenticated),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			s, err := authmethods.NewService(kms, pwRepoFn, iamRepoFn, atRepoFn)
+			require.NoError(err)
+
+			resp, err := s.Authenticate(auth.DisabledAuthTestContext(auth.WithScopeId(o.GetPublicId())), &pbs.AuthenticateRequest{
+				AuthMethodId: am.GetPublicId(),
+				Credentials:  credentials(tc.creds),
+			})
+			if tc.wantErr != nil {
+				assert.Error(err)
+				assert.Truef(errors.Is(err, tc.wantErr), "Got %#v, wanted %#v", err, tc.wantErr)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(acct.GetPublicId(), resp.GetItem().GetAccountId())
+		})
+	}
+}
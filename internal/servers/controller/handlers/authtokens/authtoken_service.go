@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/boundary/internal/errors"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/authtokens"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/types/action"
@@ -20,19 +21,28 @@ import (
 type Service struct {
 	pbs.UnimplementedAuthTokenServiceServer
 
-	repoFn    common.AuthTokenRepoFactory
-	iamRepoFn common.IamRepoFactory
+	repoFn    common.AuthTokenRepoProvider
+	iamRepoFn common.IamRepoProvider
+	kms       *kms.Kms
 }
 
 // NewService returns a user service which handles user related requests to boundary.
-func NewService(repo common.AuthTokenRepoFactory, iamRepoFn common.IamRepoFactory) (Service, error) {
+// The handlers.WithKms option is required to exchange auth tokens.
+func NewService(repo common.AuthTokenRepoFactory, iamRepoFn common.IamRepoFactory, opt ...handlers.Option) (Service, error) {
+	return NewServiceWithRepos(repo.Provider(), iamRepoFn.Provider(), opt...)
+}
+
+// NewServiceWithRepos returns an auth token service using the repositories of
+// the providers, which need not be the database backed ones.
+func NewServiceWithRepos(repo common.AuthTokenRepoProvider, iamRepoFn common.IamRepoProvider, opt ...handlers.Option) (Service, error) {
 	if repo == nil {
 		return Service{}, fmt.Errorf("nil auth token repository provided")
 	}
 	if iamRepoFn == nil {
 		return Service{}, fmt.Errorf("nil iam repository provided")
 	}
-	return Service{repoFn: repo, iamRepoFn: iamRepoFn}, nil
+	opts := handlers.GetOpts(opt...)
+	return Service{repoFn: repo, iamRepoFn: iamRepoFn, kms: opts.WithKms}, nil
 }
 
 var _ pbs.AuthTokenServiceServer = Service{}
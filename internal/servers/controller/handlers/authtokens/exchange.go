@@ -0,0 +1,86 @@
+package authtokens
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/errors"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/target"
+)
+
+// ExchangeAuthToken issues an auth token derived from the caller's auth token,
+// limited to a subset of its grants, a shorter time to live and optionally a
+// single target, so scripts can run with only the permissions they need.
+// Deleting the caller's token deletes the derived token. No grant is needed
+// since the derived token can never do more than the caller's token.
+func (s Service) ExchangeAuthToken(ctx context.Context, req *pbs.ExchangeAuthTokenRequest) (*pbs.ExchangeAuthTokenResponse, error) {
+	if s.kms == nil {
+		return nil, fmt.Errorf("auth token exchange: no kms provided")
+	}
+	if err := validateExchangeRequest(req); err != nil {
+		return nil, err
+	}
+	parent, _, err := auth.LookupSelfToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if parent.GetPublicId() == "" {
+		return nil, handlers.UnauthenticatedError()
+	}
+
+	grants := make([]perms.GrantPair, 0, len(req.GetGrants()))
+	for _, g := range req.GetGrants() {
+		grants = append(grants, perms.GrantPair{ScopeId: g.GetScopeId(), Grant: g.GetGrant()})
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	tok, err := repo.ExchangeAuthToken(ctx, parent.GetPublicId(), grants,
+		time.Duration(req.GetTimeToLiveSeconds())*time.Second, authtoken.WithTargetId(req.GetTargetId()))
+	if err != nil {
+		if errors.Is(err, errors.ErrInvalidParameter) {
+			return nil, handlers.InvalidArgumentErrorf("Unable to exchange auth token: grants and target must be held by the caller's token.", nil)
+		}
+		return nil, err
+	}
+	token, err := authtoken.EncryptToken(ctx, s.kms, tok.GetScopeId(), tok.GetPublicId(), tok.GetToken())
+	if err != nil {
+		return nil, err
+	}
+	out := toProto(tok)
+	out.Token = tok.GetPublicId() + "_" + token
+	return &pbs.ExchangeAuthTokenResponse{Item: out}, nil
+}
+
+func validateExchangeRequest(req *pbs.ExchangeAuthTokenRequest) error {
+	badFields := map[string]string{}
+	if req == nil {
+		return handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"grants": "This is a required field."})
+	}
+	if len(req.GetGrants()) == 0 {
+		badFields["grants"] = "At least one grant is required."
+	}
+	for _, g := range req.GetGrants() {
+		if g.GetScopeId() == "" || g.GetGrant() == "" {
+			badFields["grants"] = "Each grant requires a scope id and a grant."
+			break
+		}
+	}
+	if req.GetTimeToLiveSeconds() == 0 {
+		badFields["time_to_live_seconds"] = "This is a required field."
+	}
+	if req.GetTargetId() != "" && !handlers.ValidId(target.TcpTargetPrefix, req.GetTargetId()) {
+		badFields["target_id"] = "Improperly formatted identifier."
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	return nil
+}
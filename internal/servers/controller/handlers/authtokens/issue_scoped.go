@@ -0,0 +1,88 @@
+package authtokens
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/errors"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// IssueScopedAuthToken issues an auth token derived from the caller's auth
+// token which keeps only its grants in the requested scopes and is restricted
+// by a least privilege preset. The terraform preset lets provisioning
+// pipelines manage resources with a token that can't authorize sessions, even
+// if it leaks. Deleting the caller's token deletes the issued token. No grant
+// is needed since the issued token can never do more than the caller's token.
+func (s Service) IssueScopedAuthToken(ctx context.Context, req *pbs.IssueScopedAuthTokenRequest) (*pbs.IssueScopedAuthTokenResponse, error) {
+	if s.kms == nil {
+		return nil, fmt.Errorf("auth token issue scoped: no kms provided")
+	}
+	if err := validateIssueScopedRequest(req); err != nil {
+		return nil, err
+	}
+	parent, _, err := auth.LookupSelfToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if parent.GetPublicId() == "" {
+		return nil, handlers.UnauthenticatedError()
+	}
+
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	tok, err := repo.IssueScopedAuthToken(ctx, parent.GetPublicId(), authtoken.Preset(req.GetPreset()), req.GetScopeIds(),
+		time.Duration(req.GetTimeToLiveSeconds())*time.Second)
+	if err != nil {
+		if errors.Is(err, errors.ErrInvalidParameter) {
+			return nil, handlers.InvalidArgumentErrorf("Unable to issue scoped auth token: the caller's token must hold grants in the scopes and not be restricted by another preset.", nil)
+		}
+		return nil, err
+	}
+	token, err := authtoken.EncryptToken(ctx, s.kms, tok.GetScopeId(), tok.GetPublicId(), tok.GetToken())
+	if err != nil {
+		return nil, err
+	}
+	out := toProto(tok)
+	out.Token = tok.GetPublicId() + "_" + token
+	return &pbs.IssueScopedAuthTokenResponse{Item: out}, nil
+}
+
+func validateIssueScopedRequest(req *pbs.IssueScopedAuthTokenRequest) error {
+	badFields := map[string]string{}
+	validPreset := false
+	for _, p := range authtoken.Presets {
+		if string(p) == req.GetPreset() {
+			validPreset = true
+			break
+		}
+	}
+	if !validPreset {
+		badFields["preset"] = fmt.Sprintf("Must be one of %v.", authtoken.Presets)
+	}
+	if len(req.GetScopeIds()) == 0 {
+		badFields["scope_ids"] = "At least one scope id is required."
+	}
+	for _, id := range req.GetScopeIds() {
+		if id != scope.Global.String() &&
+			!handlers.ValidId(scope.Org.Prefix(), id) &&
+			!handlers.ValidId(scope.Project.Prefix(), id) {
+			badFields["scope_ids"] = "Improperly formatted identifier."
+			break
+		}
+	}
+	if req.GetTimeToLiveSeconds() == 0 {
+		badFields["time_to_live_seconds"] = "This is a required field."
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package authtokens
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/errors"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+)
+
+// RefreshAuthToken issues a new auth token for the account of the caller's
+// auth token, so a client can keep its login alive without the user
+// authenticating again. The caller's token stays valid until it expires. No
+// grant is needed since the new token can do no more than the caller's token.
+func (s Service) RefreshAuthToken(ctx context.Context, req *pbs.RefreshAuthTokenRequest) (*pbs.RefreshAuthTokenResponse, error) {
+	if s.kms == nil {
+		return nil, fmt.Errorf("auth token refresh: no kms provided")
+	}
+	self, _, err := auth.LookupSelfToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if self.GetPublicId() == "" {
+		return nil, handlers.UnauthenticatedError()
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	tok, err := repo.RefreshAuthToken(ctx, self.GetPublicId())
+	if err != nil {
+		if errors.Is(err, errors.ErrInvalidParameter) {
+			return nil, handlers.InvalidArgumentErrorf("Unable to refresh auth token: derived tokens can't be refreshed and logins can't be refreshed beyond the maximum auth token lifetime.", nil)
+		}
+		return nil, err
+	}
+	token, err := authtoken.EncryptToken(ctx, s.kms, tok.GetScopeId(), tok.GetPublicId(), tok.GetToken())
+	if err != nil {
+		return nil, err
+	}
+	out := toProto(tok)
+	out.Token = tok.GetPublicId() + "_" + token
+	return &pbs.RefreshAuthTokenResponse{Item: out}, nil
+}
@@ -9,11 +9,13 @@ import (
 	"strings"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/errors"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api"
 	"github.com/hashicorp/go-hclog"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -21,6 +23,78 @@ const (
 	genericNotFoundMsg   = "Unable to find requested resource."
 )
 
+// The codes of the errors returned by the API. Clients branch on codes rather
+// than messages, so once released a code must never change meaning. Codes
+// are mirrored by the api package.
+const (
+	CodeCanceled           = "canceled"
+	CodeUnknown            = "unknown"
+	CodeInvalidArgument    = "invalid_argument"
+	CodeDeadlineExceeded   = "deadline_exceeded"
+	CodeNotFound           = "not_found"
+	CodeAlreadyExists      = "already_exists"
+	CodePermissionDenied   = "permission_denied"
+	CodeResourceExhausted  = "resource_exhausted"
+	CodeFailedPrecondition = "failed_precondition"
+	CodeAborted            = "aborted"
+	CodeOutOfRange         = "out_of_range"
+	CodeUnimplemented      = "unimplemented"
+	CodeInternal           = "internal"
+	CodeUnavailable        = "unavailable"
+	CodeDataLoss           = "data_loss"
+	CodeUnauthenticated    = "unauthenticated"
+
+	// CodeNotUnique is returned when a resource would have the same value as
+	// another for a field which must be unique.
+	CodeNotUnique = "not_unique"
+	// CodeInvalidFieldMask is returned when the update mask of a request is
+	// empty or names fields which can't be updated.
+	CodeInvalidFieldMask = "invalid_field_mask"
+	// CodeRequestTooLarge is returned when the body of a request is larger
+	// than the controller accepts for its endpoint.
+	CodeRequestTooLarge = "request_too_large"
+)
+
+// grpcCodes are the codes of errors which are only known by their gRPC code.
+var grpcCodes = map[codes.Code]string{
+	codes.Canceled:           CodeCanceled,
+	codes.Unknown:            CodeUnknown,
+	codes.InvalidArgument:    CodeInvalidArgument,
+	codes.DeadlineExceeded:   CodeDeadlineExceeded,
+	codes.NotFound:           CodeNotFound,
+	codes.AlreadyExists:      CodeAlreadyExists,
+	codes.PermissionDenied:   CodePermissionDenied,
+	codes.ResourceExhausted:  CodeResourceExhausted,
+	codes.FailedPrecondition: CodeFailedPrecondition,
+	codes.Aborted:            CodeAborted,
+	codes.OutOfRange:         CodeOutOfRange,
+	codes.Unimplemented:      CodeUnimplemented,
+	codes.Internal:           CodeInternal,
+	codes.Unavailable:        CodeUnavailable,
+	codes.DataLoss:           CodeDataLoss,
+	codes.Unauthenticated:    CodeUnauthenticated,
+}
+
+// retryableCodes are the codes of errors for which the same request may
+// succeed if it is sent again.
+var retryableCodes = map[string]bool{
+	CodeDeadlineExceeded:  true,
+	CodeResourceExhausted: true,
+	CodeAborted:           true,
+	CodeUnavailable:       true,
+}
+
+// errorCode returns the code of an error of the provided kind, the string
+// form of its gRPC code.
+func errorCode(kind string) string {
+	for c, code := range grpcCodes {
+		if c.String() == kind {
+			return code
+		}
+	}
+	return CodeUnknown
+}
+
 type apiError struct {
 	status int32
 	inner  *pb.Error
@@ -104,6 +178,18 @@ func UnauthenticatedError() error {
 		}}
 }
 
+// RequestTooLargeError returns an ApiError indicating the body of a request
+// is larger than maxSize bytes.
+func RequestTooLargeError(maxSize int64) error {
+	return &apiError{
+		status: http.StatusRequestEntityTooLarge,
+		inner: &pb.Error{
+			Kind:    codes.InvalidArgument.String(),
+			Code:    CodeRequestTooLarge,
+			Message: fmt.Sprintf("Request body larger than %d bytes.", maxSize),
+		}}
+}
+
 func InvalidArgumentErrorf(msg string, fields map[string]string) error {
 	err := ApiErrorWithCodeAndMessage(codes.InvalidArgument, msg)
 	var apiErr *apiError
@@ -123,6 +209,16 @@ func InvalidArgumentErrorf(msg string, fields map[string]string) error {
 	return apiErr
 }
 
+// withCode sets the code of an api error to a code more specific than the one
+// of its kind.
+func withCode(err error, code string) error {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		apiErr.inner.Code = code
+	}
+	return err
+}
+
 // Converts a known errors into an error that can presented to an end user over the API.
 func backendErrorToApiError(inErr error) error {
 	stErr := status.Convert(inErr)
@@ -149,9 +245,9 @@ func backendErrorToApiError(inErr error) error {
 		return NotFoundErrorf(genericNotFoundMsg)
 	case errors.Is(inErr, errors.ErrInvalidFieldMask), errors.Is(inErr, errors.ErrEmptyFieldMask),
 		errors.Match(errors.T(errors.InvalidFieldMask), inErr), errors.Match(errors.T(errors.EmptyFieldMask), inErr):
-		return InvalidArgumentErrorf("Error in provided request", map[string]string{"update_mask": "Invalid update mask provided."})
+		return withCode(InvalidArgumentErrorf("Error in provided request", map[string]string{"update_mask": "Invalid update mask provided."}), CodeInvalidFieldMask)
 	case errors.IsUniqueError(inErr), errors.Is(inErr, errors.ErrNotUnique):
-		return InvalidArgumentErrorf(genericUniquenessMsg, nil)
+		return withCode(InvalidArgumentErrorf(genericUniquenessMsg, nil), CodeNotUnique)
 	}
 
 	// We haven't been able to identify what this backend error is, return it as an internal error
@@ -163,6 +259,10 @@ func backendErrorToApiError(inErr error) error {
 	}
 }
 
+// RequestIdHeader is the header the ID of a request is returned in. A client
+// can provide the ID of its request in the same header.
+const RequestIdHeader = "X-Request-Id"
+
 func ErrorHandler(logger hclog.Logger) runtime.ErrorHandlerFunc {
 	const errorFallback = `{"error": "failed to marshal error message"}`
 	return func(ctx context.Context, _ *runtime.ServeMux, mar runtime.Marshaler, w http.ResponseWriter, r *http.Request, inErr error) {
@@ -175,13 +275,22 @@ func ErrorHandler(logger hclog.Logger) runtime.ErrorHandlerFunc {
 			}
 		}
 
+		// The body is filled in on a copy so errors created once and
+		// returned by many requests aren't changed
+		inner := proto.Clone(apiErr.inner).(*pb.Error)
+		if inner.Code == "" {
+			inner.Code = errorCode(inner.GetKind())
+		}
+		inner.Retryable = retryableCodes[inner.Code]
+		inner.RequestId, _ = ctx.Value(globals.ContextRequestIdTypeKey).(string)
+
 		if apiErr.status == http.StatusInternalServerError {
-			logger.Error("internal error returned", "error", inErr)
+			logger.Error("internal error returned", "error", inErr, "request_id", inner.RequestId)
 		}
 
-		buf, merr := mar.Marshal(apiErr.inner)
+		buf, merr := mar.Marshal(inner)
 		if merr != nil {
-			logger.Error("failed to marshal error response", "response", fmt.Sprintf("%#v", apiErr.inner), "error", merr)
+			logger.Error("failed to marshal error response", "response", fmt.Sprintf("%#v", inner), "error", merr)
 			w.WriteHeader(http.StatusInternalServerError)
 			if _, err := io.WriteString(w, errorFallback); err != nil {
 				logger.Error("failed to write response", "error", err)
@@ -189,7 +298,7 @@ func ErrorHandler(logger hclog.Logger) runtime.ErrorHandlerFunc {
 			return
 		}
 
-		w.Header().Set("Content-Type", mar.ContentType(apiErr.inner))
+		w.Header().Set("Content-Type", mar.ContentType(inner))
 		w.WriteHeader(int(apiErr.status))
 		if _, err := w.Write(buf); err != nil {
 			logger.Error("failed to send response chunk", "error", err)
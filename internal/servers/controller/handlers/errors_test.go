@@ -11,6 +11,8 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/errors"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api"
 	"github.com/hashicorp/go-hclog"
@@ -42,6 +44,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusNotFound,
 				inner: &pb.Error{
 					Kind:    "NotFound",
+					Code:    CodeNotFound,
 					Message: "Test",
 				},
 			},
@@ -56,6 +59,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusBadRequest,
 				inner: &pb.Error{
 					Kind:    "InvalidArgument",
+					Code:    CodeInvalidArgument,
 					Message: "Test",
 					Details: &pb.ErrorDetails{
 						RequestFields: []*pb.FieldError{
@@ -79,6 +83,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusNotFound,
 				inner: &pb.Error{
 					Kind:    "NotFound",
+					Code:    CodeNotFound,
 					Message: http.StatusText(http.StatusNotFound),
 				},
 			},
@@ -90,6 +95,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusMethodNotAllowed,
 				inner: &pb.Error{
 					Kind:    "Unimplemented",
+					Code:    CodeUnimplemented,
 					Message: "Test",
 				},
 			},
@@ -101,6 +107,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusInternalServerError,
 				inner: &pb.Error{
 					Kind:    "Internal",
+					Code:    CodeInternal,
 					Message: "Some random error",
 				},
 			},
@@ -112,6 +119,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusInternalServerError,
 				inner: &pb.Error{
 					Kind:    "Internal",
+					Code:    CodeInternal,
 					Message: fmt.Sprintf("test error: %s", errors.ErrInvalidPublicId),
 				},
 			},
@@ -123,6 +131,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusInternalServerError,
 				inner: &pb.Error{
 					Kind:    "Internal",
+					Code:    CodeInternal,
 					Message: "invalid public id, parameter violation: error #102",
 				},
 			},
@@ -134,6 +143,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusInternalServerError,
 				inner: &pb.Error{
 					Kind:    "Internal",
+					Code:    CodeInternal,
 					Message: fmt.Sprintf("test error: %s", errors.ErrInvalidParameter),
 				},
 			},
@@ -145,6 +155,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusInternalServerError,
 				inner: &pb.Error{
 					Kind:    "Internal",
+					Code:    CodeInternal,
 					Message: "invalid parameter, parameter violation: error #100",
 				},
 			},
@@ -156,6 +167,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusBadRequest,
 				inner: &pb.Error{
 					Kind:    "InvalidArgument",
+					Code:    CodeInvalidFieldMask,
 					Message: "Error in provided request",
 					Details: &pb.ErrorDetails{RequestFields: []*pb.FieldError{{Name: "update_mask", Description: "Invalid update mask provided."}}},
 				},
@@ -168,6 +180,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusBadRequest,
 				inner: &pb.Error{
 					Kind:    "InvalidArgument",
+					Code:    CodeInvalidFieldMask,
 					Message: "Error in provided request",
 					Details: &pb.ErrorDetails{RequestFields: []*pb.FieldError{{Name: "update_mask", Description: "Invalid update mask provided."}}},
 				},
@@ -180,6 +193,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusBadRequest,
 				inner: &pb.Error{
 					Kind:    "InvalidArgument",
+					Code:    CodeInvalidFieldMask,
 					Message: "Error in provided request",
 					Details: &pb.ErrorDetails{RequestFields: []*pb.FieldError{{Name: "update_mask", Description: "Invalid update mask provided."}}},
 				},
@@ -192,6 +206,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusBadRequest,
 				inner: &pb.Error{
 					Kind:    "InvalidArgument",
+					Code:    CodeInvalidFieldMask,
 					Message: "Error in provided request",
 					Details: &pb.ErrorDetails{RequestFields: []*pb.FieldError{{Name: "update_mask", Description: "Invalid update mask provided."}}},
 				},
@@ -204,6 +219,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusBadRequest,
 				inner: &pb.Error{
 					Kind:    "InvalidArgument",
+					Code:    CodeNotUnique,
 					Message: genericUniquenessMsg,
 				},
 			},
@@ -215,6 +231,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusBadRequest,
 				inner: &pb.Error{
 					Kind:    "InvalidArgument",
+					Code:    CodeNotUnique,
 					Message: genericUniquenessMsg,
 				},
 			},
@@ -226,6 +243,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusNotFound,
 				inner: &pb.Error{
 					Kind:    "NotFound",
+					Code:    CodeNotFound,
 					Message: genericNotFoundMsg,
 				},
 			},
@@ -237,6 +255,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusNotFound,
 				inner: &pb.Error{
 					Kind:    "NotFound",
+					Code:    CodeNotFound,
 					Message: genericNotFoundMsg,
 				},
 			},
@@ -248,6 +267,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusInternalServerError,
 				inner: &pb.Error{
 					Kind:    "Internal",
+					Code:    CodeInternal,
 					Message: fmt.Sprintf("test error: %s", errors.ErrMultipleRecords),
 				},
 			},
@@ -259,6 +279,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusInternalServerError,
 				inner: &pb.Error{
 					Kind:    "Internal",
+					Code:    CodeInternal,
 					Message: "multiple records, search issue: error #1101",
 				},
 			},
@@ -270,6 +291,7 @@ func TestApiErrorHandler(t *testing.T) {
 				status: http.StatusInternalServerError,
 				inner: &pb.Error{
 					Kind:    "Internal",
+					Code:    CodeInternal,
 					Message: "test msg: parameter violation: error #101: inner msg: integrity violation: error #1001",
 				},
 			},
@@ -295,3 +317,73 @@ func TestApiErrorHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestApiErrorHandler_Body(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.WithValue(context.Background(), globals.ContextRequestIdTypeKey, "req_1234")
+	req, err := http.NewRequest("GET", "madeup/for/the/test", nil)
+	require.NoError(err)
+	mux := runtime.NewServeMux()
+	inMarsh, outMarsh := runtime.MarshalerForRequest(mux, req)
+	tested := ErrorHandler(hclog.L())
+
+	inErr := ApiErrorWithCodeAndMessage(codes.Unavailable, "Test")
+	w := httptest.NewRecorder()
+	tested(ctx, mux, outMarsh, w, req, inErr)
+	resp := w.Result()
+	assert.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+
+	got, err := ioutil.ReadAll(resp.Body)
+	require.NoError(err)
+	gotErr := &pb.Error{}
+	require.NoError(inMarsh.Unmarshal(got, gotErr))
+	assert.Empty(cmp.Diff(&pb.Error{
+		Kind:      "Unavailable",
+		Code:      CodeUnavailable,
+		Message:   "Test",
+		Retryable: true,
+		RequestId: "req_1234",
+	}, gotErr, protocmp.Transform()))
+
+	// The returned error isn't changed by filling in the body
+	var apiErr *apiError
+	require.True(errors.As(inErr, &apiErr))
+	assert.Empty(apiErr.inner.GetCode())
+	assert.Empty(apiErr.inner.GetRequestId())
+}
+
+// TestErrorCodes checks that every gRPC code has an error code, and that the
+// codes are mirrored by the api package.
+func TestErrorCodes(t *testing.T) {
+	assert := assert.New(t)
+	for c := codes.Canceled; c <= codes.Unauthenticated; c++ {
+		assert.Contains(grpcCodes, c)
+		assert.Equal(grpcCodes[c], errorCode(c.String()))
+	}
+	assert.Equal(CodeUnknown, errorCode("madeup"))
+
+	mirrored := map[string]string{
+		CodeCanceled:           api.ErrCodeCanceled,
+		CodeUnknown:            api.ErrCodeUnknown,
+		CodeInvalidArgument:    api.ErrCodeInvalidArgument,
+		CodeDeadlineExceeded:   api.ErrCodeDeadlineExceeded,
+		CodeNotFound:           api.ErrCodeNotFound,
+		CodeAlreadyExists:      api.ErrCodeAlreadyExists,
+		CodePermissionDenied:   api.ErrCodePermissionDenied,
+		CodeResourceExhausted:  api.ErrCodeResourceExhausted,
+		CodeFailedPrecondition: api.ErrCodeFailedPrecondition,
+		CodeAborted:            api.ErrCodeAborted,
+		CodeOutOfRange:         api.ErrCodeOutOfRange,
+		CodeUnimplemented:      api.ErrCodeUnimplemented,
+		CodeInternal:           api.ErrCodeInternal,
+		CodeUnavailable:        api.ErrCodeUnavailable,
+		CodeDataLoss:           api.ErrCodeDataLoss,
+		CodeUnauthenticated:    api.ErrCodeUnauthenticated,
+		CodeNotUnique:          api.ErrCodeNotUnique,
+		CodeInvalidFieldMask:   api.ErrCodeInvalidFieldMask,
+		CodeRequestTooLarge:    api.ErrCodeRequestTooLarge,
+	}
+	for code, apiCode := range mirrored {
+		assert.Equal(code, apiCode)
+	}
+}
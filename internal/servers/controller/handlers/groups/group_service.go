@@ -35,15 +35,25 @@ func init() {
 type Service struct {
 	pbs.UnimplementedGroupServiceServer
 
-	repoFn common.IamRepoFactory
+	repoFn           common.IamRepoProvider
+	annotationRepoFn common.AnnotationRepoFactory
 }
 
 // NewService returns a group service which handles group related requests to boundary.
-func NewService(repo common.IamRepoFactory) (Service, error) {
+// Supported options: handlers.WithAnnotations.
+func NewService(repo common.IamRepoFactory, opt ...handlers.Option) (Service, error) {
+	return NewServiceWithRepos(repo.Provider(), opt...)
+}
+
+// NewServiceWithRepos returns a group service using the iam repositories of the
+// provider, which need not be the database backed ones. It takes the same
+// options as NewService.
+func NewServiceWithRepos(repo common.IamRepoProvider, opt ...handlers.Option) (Service, error) {
 	if repo == nil {
 		return Service{}, fmt.Errorf("nil iam repository provided")
 	}
-	return Service{repoFn: repo}, nil
+	opts := handlers.GetOpts(opt...)
+	return Service{repoFn: repo, annotationRepoFn: opts.WithAnnotations}, nil
 }
 
 var _ pbs.GroupServiceServer = Service{}
@@ -61,8 +71,17 @@ func (s Service) ListGroups(ctx context.Context, req *pbs.ListGroupsRequest) (*p
 	if err != nil {
 		return nil, err
 	}
+	ids := make([]string, 0, len(gl))
+	for _, item := range gl {
+		ids = append(ids, item.GetId())
+	}
+	annotations, err := handlers.ListAnnotations(ctx, s.annotationRepoFn, ids)
+	if err != nil {
+		return nil, err
+	}
 	for _, item := range gl {
 		item.Scope = authResults.Scope
+		item.Annotations = annotations[item.GetId()]
 	}
 	return &pbs.ListGroupsResponse{Items: gl}, nil
 }
@@ -80,6 +99,9 @@ func (s Service) GetGroup(ctx context.Context, req *pbs.GetGroupRequest) (*pbs.G
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.GetGroupResponse{Item: u}, nil
 }
@@ -114,6 +136,9 @@ func (s Service) UpdateGroup(ctx context.Context, req *pbs.UpdateGroupRequest) (
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.UpdateAnnotations(ctx, s.annotationRepoFn, resource.Group, req.GetId(), req.GetItem().GetAnnotations(), req.GetUpdateMask().GetPaths()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.UpdateGroupResponse{Item: u}, nil
 }
@@ -147,6 +172,9 @@ func (s Service) AddGroupMembers(ctx context.Context, req *pbs.AddGroupMembersRe
 	if err != nil {
 		return nil, err
 	}
+	if g.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	g.Scope = authResults.Scope
 	return &pbs.AddGroupMembersResponse{Item: g}, nil
 }
@@ -164,6 +192,9 @@ func (s Service) SetGroupMembers(ctx context.Context, req *pbs.SetGroupMembersRe
 	if err != nil {
 		return nil, err
 	}
+	if g.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	g.Scope = authResults.Scope
 	return &pbs.SetGroupMembersResponse{Item: g}, nil
 }
@@ -181,6 +212,9 @@ func (s Service) RemoveGroupMembers(ctx context.Context, req *pbs.RemoveGroupMem
 	if err != nil {
 		return nil, err
 	}
+	if g.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	g.Scope = authResults.Scope
 	return &pbs.RemoveGroupMembersResponse{Item: g}, nil
 }
@@ -245,7 +279,18 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, id string, mask []st
 	g.PublicId = id
 	dbMask := maskManager.Translate(mask)
 	if len(dbMask) == 0 {
-		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		if len(handlers.AnnotationsMask(mask)) == 0 {
+			return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		}
+		// Only annotations are updated, which are not stored with the group
+		out, err := s.getFromRepo(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if out.GetVersion() != item.GetVersion() {
+			return nil, handlers.NotFoundErrorf("Group %q doesn't exist or incorrect version provided.", id)
+		}
+		return out, nil
 	}
 	repo, err := s.repoFn()
 	if err != nil {
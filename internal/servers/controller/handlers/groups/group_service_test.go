@@ -9,12 +9,15 @@ import (
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/boundary/internal/annotation"
 	"github.com/hashicorp/boundary/internal/auth"
 	"github.com/hashicorp/boundary/internal/db"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/groups"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/iam/store"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/groups"
 	"github.com/hashicorp/boundary/internal/types/scope"
@@ -903,6 +906,78 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdate_Annotations(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	wrap := db.TestWrapper(t)
+	rw := db.New(conn)
+	iamRepo := iam.TestRepo(t, conn, wrap)
+	repoFn := func() (*iam.Repository, error) {
+		return iamRepo, nil
+	}
+	annotationRepoFn := func() (*annotation.Repository, error) {
+		return annotation.NewRepository(rw, rw)
+	}
+	o, _ := iam.TestScopes(t, iamRepo)
+	og := iam.TestGroup(t, conn, o.GetPublicId())
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(o.GetPublicId()))
+
+	unsupported, err := groups.NewService(repoFn)
+	require.NoError(err)
+	_, err = unsupported.UpdateGroup(ctx, &pbs.UpdateGroupRequest{
+		Id:         og.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"annotations"}},
+		Item:       &pb.Group{Version: og.GetVersion(), Annotations: map[string]string{"owner": "ops"}},
+	})
+	assert.Truef(errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), "got error %v, wanted invalid argument", err)
+
+	tested, err := groups.NewService(repoFn, handlers.WithAnnotations(annotationRepoFn))
+	require.NoError(err)
+	_, err = tested.UpdateGroup(ctx, &pbs.UpdateGroupRequest{
+		Id:         og.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"annotations"}},
+		Item:       &pb.Group{Version: og.GetVersion() + 1, Annotations: map[string]string{"owner": "ops"}},
+	})
+	assert.Truef(errors.Is(err, handlers.ApiErrorWithCode(codes.NotFound)), "got error %v, wanted not found", err)
+
+	got, err := tested.UpdateGroup(ctx, &pbs.UpdateGroupRequest{
+		Id:         og.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"annotations"}},
+		Item:       &pb.Group{Version: og.GetVersion(), Annotations: map[string]string{"owner": "ops", "cost-center": "42"}},
+	})
+	require.NoError(err)
+	assert.Equal(map[string]string{"owner": "ops", "cost-center": "42"}, got.GetItem().GetAnnotations())
+	assert.Equal(og.GetVersion(), got.GetItem().GetVersion(), "annotating does not change the group")
+
+	got, err = tested.UpdateGroup(ctx, &pbs.UpdateGroupRequest{
+		Id:         og.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"name", "annotations.owner", "annotations.cost-center"}},
+		Item: &pb.Group{
+			Version:     og.GetVersion(),
+			Name:        wrapperspb.String("annotated"),
+			Annotations: map[string]string{"owner": "dev"},
+		},
+	})
+	require.NoError(err)
+	assert.Equal("annotated", got.GetItem().GetName().GetValue())
+	assert.Equal(map[string]string{"owner": "dev"}, got.GetItem().GetAnnotations())
+
+	read, err := tested.GetGroup(ctx, &pbs.GetGroupRequest{Id: og.GetPublicId()})
+	require.NoError(err)
+	assert.Equal(map[string]string{"owner": "dev"}, read.GetItem().GetAnnotations())
+
+	list, err := tested.ListGroups(ctx, &pbs.ListGroupsRequest{ScopeId: o.GetPublicId()})
+	require.NoError(err)
+	require.Len(list.GetItems(), 1)
+	assert.Equal(map[string]string{"owner": "dev"}, list.GetItems()[0].GetAnnotations())
+
+	_, err = tested.CreateGroup(ctx, &pbs.CreateGroupRequest{Item: &pb.Group{
+		ScopeId:     o.GetPublicId(),
+		Annotations: map[string]string{"owner": "ops"},
+	}})
+	assert.Truef(errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), "got error %v, wanted invalid argument", err)
+}
+
 func TestAddMember(t *testing.T) {
 	conn, _ := db.TestSetup(t, "postgres")
 	wrap := db.TestWrapper(t)
@@ -1297,3 +1372,36 @@ func TestRemoveMember(t *testing.T) {
 		})
 	}
 }
+
+// mockIamRepo serves the groups it holds, panicking on the methods of the
+// repository it doesn't implement.
+type mockIamRepo struct {
+	common.IamRepo
+	groups map[string]*iam.Group
+}
+
+func (m *mockIamRepo) LookupGroup(_ context.Context, id string, _ ...iam.Option) (*iam.Group, []*iam.GroupMember, error) {
+	return m.groups[id], nil, nil
+}
+
+func TestNewServiceWithRepos(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	repo := &mockIamRepo{groups: map[string]*iam.Group{
+		"g_1234567890": {Group: &store.Group{PublicId: "g_1234567890", ScopeId: "o_1234567890", Name: "admins"}},
+	}}
+	repoFn := func() (common.IamRepo, error) { return repo, nil }
+
+	_, err := groups.NewServiceWithRepos(nil)
+	assert.Error(err)
+	s, err := groups.NewServiceWithRepos(repoFn)
+	require.NoError(err)
+
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId("o_1234567890"))
+	got, err := s.GetGroup(ctx, &pbs.GetGroupRequest{Id: "g_1234567890"})
+	require.NoError(err)
+	assert.Equal("g_1234567890", got.GetItem().GetId())
+	assert.Equal("admins", got.GetItem().GetName().GetValue())
+
+	_, err = s.GetGroup(ctx, &pbs.GetGroupRequest{Id: "g_0987654321"})
+	assert.True(errors.Is(err, handlers.NotFoundError()))
+}
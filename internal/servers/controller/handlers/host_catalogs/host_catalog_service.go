@@ -34,22 +34,24 @@ func init() {
 type Service struct {
 	pbs.UnimplementedHostCatalogServiceServer
 
-	staticRepoFn common.StaticRepoFactory
-	iamRepoFn    common.IamRepoFactory
+	staticRepoFn     common.StaticRepoFactory
+	iamRepoFn        common.IamRepoFactory
+	annotationRepoFn common.AnnotationRepoFactory
 }
 
 var _ pbs.HostCatalogServiceServer = Service{}
 
 // NewService returns a host catalog Service which handles host catalog related requests to boundary and uses the provided
-// repositories for storage and retrieval.
-func NewService(repoFn common.StaticRepoFactory, iamRepoFn common.IamRepoFactory) (Service, error) {
+// repositories for storage and retrieval. Supported options: handlers.WithAnnotations.
+func NewService(repoFn common.StaticRepoFactory, iamRepoFn common.IamRepoFactory, opt ...handlers.Option) (Service, error) {
 	if repoFn == nil {
 		return Service{}, fmt.Errorf("nil static repository provided")
 	}
 	if iamRepoFn == nil {
 		return Service{}, fmt.Errorf("nil iam repository provided")
 	}
-	return Service{staticRepoFn: repoFn, iamRepoFn: iamRepoFn}, nil
+	opts := handlers.GetOpts(opt...)
+	return Service{staticRepoFn: repoFn, iamRepoFn: iamRepoFn, annotationRepoFn: opts.WithAnnotations}, nil
 }
 
 func (s Service) ListHostCatalogs(ctx context.Context, req *pbs.ListHostCatalogsRequest) (*pbs.ListHostCatalogsResponse, error) {
@@ -64,8 +66,17 @@ func (s Service) ListHostCatalogs(ctx context.Context, req *pbs.ListHostCatalogs
 	if err != nil {
 		return nil, err
 	}
+	ids := make([]string, 0, len(ul))
+	for _, item := range ul {
+		ids = append(ids, item.GetId())
+	}
+	annotations, err := handlers.ListAnnotations(ctx, s.annotationRepoFn, ids)
+	if err != nil {
+		return nil, err
+	}
 	for _, item := range ul {
 		item.Scope = authResults.Scope
+		item.Annotations = annotations[item.GetId()]
 	}
 	return &pbs.ListHostCatalogsResponse{Items: ul}, nil
 }
@@ -83,6 +94,9 @@ func (s Service) GetHostCatalog(ctx context.Context, req *pbs.GetHostCatalogRequ
 	if err != nil {
 		return nil, err
 	}
+	if hc.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	hc.Scope = authResults.Scope
 	return &pbs.GetHostCatalogResponse{Item: hc}, nil
 }
@@ -120,6 +134,9 @@ func (s Service) UpdateHostCatalog(ctx context.Context, req *pbs.UpdateHostCatal
 	if err != nil {
 		return nil, err
 	}
+	if hc.Annotations, err = handlers.UpdateAnnotations(ctx, s.annotationRepoFn, resource.HostCatalog, req.GetId(), req.GetItem().GetAnnotations(), req.GetUpdateMask().GetPaths()); err != nil {
+		return nil, err
+	}
 	hc.Scope = authResults.Scope
 	return &pbs.UpdateHostCatalogResponse{Item: hc}, nil
 }
@@ -225,7 +242,18 @@ func (s Service) updateInRepo(ctx context.Context, projId, id string, mask []str
 	h.PublicId = id
 	dbMask := maskManager.Translate(mask)
 	if len(dbMask) == 0 {
-		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		if len(handlers.AnnotationsMask(mask)) == 0 {
+			return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		}
+		// Only annotations are updated, which are not stored with the host catalog
+		out, err := s.getFromRepo(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if out.GetVersion() != item.GetVersion() {
+			return nil, handlers.NotFoundErrorf("Host Catalog %q doesn't exist or incorrect version provided.", id)
+		}
+		return out, nil
 	}
 	repo, err := s.staticRepoFn()
 	if err != nil {
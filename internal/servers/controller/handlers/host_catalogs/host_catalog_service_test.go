@@ -750,3 +750,102 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestImportHostCatalogHosts(t *testing.T) {
+	t.Parallel()
+	hc, proj, repoFn, iamRepoFn := createDefaultHostCatalogAndRepo(t)
+	s, err := host_catalogs.NewService(repoFn, iamRepoFn)
+	require.NoError(t, err, "Couldn't create new host catalog service.")
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(proj.GetPublicId()))
+
+	inventory := "[web]\nweb1 ansible_host=10.0.0.1\nweb2 ansible_host=10.0.0.2\n"
+
+	t.Run("dry-run", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := s.ImportHostCatalogHosts(ctx, &pbs.ImportHostCatalogHostsRequest{
+			Id:      hc.GetPublicId(),
+			Format:  static.InventoryAnsible,
+			Content: inventory,
+			DryRun:  true,
+		})
+		require.NoError(err)
+		want := &pbs.ImportHostCatalogHostsResponse{
+			HostCatalogId: hc.GetPublicId(),
+			DryRun:        true,
+			Hosts: []*pb.ImportedHost{
+				{Name: "web1", Address: "10.0.0.1"},
+				{Name: "web2", Address: "10.0.0.2"},
+			},
+			HostSets: []*pb.ImportedHostSet{
+				{Name: "web", AddedHosts: []string{"web1", "web2"}},
+			},
+		}
+		assert.Empty(cmp.Diff(want, got, protocmp.Transform()))
+	})
+	t.Run("import", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := s.ImportHostCatalogHosts(ctx, &pbs.ImportHostCatalogHostsRequest{
+			Id:          hc.GetPublicId(),
+			Format:      static.InventoryAnsible,
+			Content:     inventory,
+			HostSetName: "all",
+		})
+		require.NoError(err)
+		assert.False(got.GetDryRun())
+		require.Len(got.GetHosts(), 2)
+		require.Len(got.GetHostSets(), 2)
+		for _, h := range got.GetHosts() {
+			assert.True(strings.HasPrefix(h.GetId(), static.HostPrefix+"_"))
+			assert.False(h.GetExists())
+		}
+		assert.Equal("all", got.GetHostSets()[1].GetName())
+		assert.Equal([]string{"web1", "web2"}, got.GetHostSets()[1].GetAddedHosts())
+
+		repo, err := repoFn()
+		require.NoError(err)
+		_, hosts, err := repo.LookupSet(context.Background(), got.GetHostSets()[1].GetId())
+		require.NoError(err)
+		assert.Len(hosts, 2)
+	})
+
+	cases := []struct {
+		name string
+		req  *pbs.ImportHostCatalogHostsRequest
+		err  error
+	}{
+		{
+			name: "Bad id",
+			req:  &pbs.ImportHostCatalogHostsRequest{Id: "j_1234567890", Format: static.InventoryKnownHosts, Content: "web1 ssh-rsa AAAA"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Unknown format",
+			req:  &pbs.ImportHostCatalogHostsRequest{Id: hc.GetPublicId(), Format: "hosts", Content: "127.0.0.1 localhost"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Empty content",
+			req:  &pbs.ImportHostCatalogHostsRequest{Id: hc.GetPublicId(), Format: static.InventoryKnownHosts},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Malformed content",
+			req:  &pbs.ImportHostCatalogHostsRequest{Id: hc.GetPublicId(), Format: static.InventoryKnownHosts, Content: "web1 ssh-rsa"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Non existing catalog",
+			req:  &pbs.ImportHostCatalogHostsRequest{Id: static.HostCatalogPrefix + "_DoesntExis", Format: static.InventoryKnownHosts, Content: "web1 ssh-rsa AAAA"},
+			err:  handlers.ApiErrorWithCode(codes.NotFound),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := s.ImportHostCatalogHosts(ctx, tc.req)
+			require.Error(err)
+			assert.True(errors.Is(err, tc.err), "ImportHostCatalogHosts(%q) got error %v, wanted %v", tc.req.GetId(), err, tc.err)
+			assert.Nil(got)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package host_catalogs
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/errors"
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/hostcatalogs"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+)
+
+// ImportHostCatalogHosts creates the hosts and host sets of an inventory file
+// in the catalog. Hosts and host sets are matched to those of the catalog by
+// name. A dry run requires reading the catalog; an import also requires
+// creating hosts and host sets in it.
+func (s Service) ImportHostCatalogHosts(ctx context.Context, req *pbs.ImportHostCatalogHostsRequest) (*pbs.ImportHostCatalogHostsResponse, error) {
+	id := req.GetId()
+	badFields := map[string]string{}
+	if !handlers.ValidId(static.HostCatalogPrefix, id) {
+		badFields["id"] = "Improperly formatted identifier."
+	}
+	switch req.GetFormat() {
+	case static.InventoryKnownHosts, static.InventorySshConfig, static.InventoryAnsible:
+	case "":
+		badFields["format"] = "This field is required."
+	default:
+		badFields["format"] = "Must be one of known_hosts, ssh_config or ansible_inventory."
+	}
+	if strings.TrimSpace(req.GetContent()) == "" {
+		badFields["content"] = "This field is required."
+	}
+	if len(badFields) > 0 {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+
+	authResults := s.authResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	if !req.GetDryRun() {
+		for _, r := range []resource.Type{resource.Host, resource.HostSet} {
+			res := auth.Verify(ctx,
+				auth.WithType(r),
+				auth.WithAction(action.Create),
+				auth.WithScopeId(authResults.Scope.GetId()),
+				auth.WithPin(id))
+			if res.Error != nil {
+				return nil, res.Error
+			}
+		}
+	}
+
+	inv, err := static.ParseInventory(req.GetFormat(), strings.NewReader(req.GetContent()))
+	if err != nil {
+		if e := errors.Convert(err); e != nil {
+			return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"content": e.Msg})
+		}
+		return nil, err
+	}
+	if req.GetHostSetName() != "" {
+		set := &static.InventorySet{Name: req.GetHostSetName()}
+		for _, h := range inv.Hosts {
+			set.Hosts = append(set.Hosts, h.Name)
+		}
+		inv.Sets = append(inv.Sets, set)
+	}
+
+	repo, err := s.staticRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	plan, err := repo.ImportInventory(ctx, authResults.Scope.GetId(), id, inv, static.WithDryRun(req.GetDryRun()))
+	if err != nil {
+		if e := errors.Convert(err); e != nil {
+			// This is a domain error, push this error through so the error interceptor can interpret it correctly.
+			return nil, e
+		}
+		return nil, err
+	}
+
+	res := &pbs.ImportHostCatalogHostsResponse{
+		HostCatalogId: id,
+		DryRun:        req.GetDryRun(),
+		Hosts:         []*pb.ImportedHost{},
+		HostSets:      []*pb.ImportedHostSet{},
+	}
+	for _, h := range plan.Hosts {
+		res.Hosts = append(res.Hosts, &pb.ImportedHost{
+			Id:      h.PublicId,
+			Name:    h.Name,
+			Address: h.Address,
+			Exists:  h.Exists,
+		})
+	}
+	for _, hs := range plan.Sets {
+		added := hs.AddHosts
+		if added == nil {
+			added = []string{}
+		}
+		res.HostSets = append(res.HostSets, &pb.ImportedHostSet{
+			Id:         hs.PublicId,
+			Name:       hs.Name,
+			Exists:     hs.Exists,
+			AddedHosts: added,
+		})
+	}
+	return res, nil
+}
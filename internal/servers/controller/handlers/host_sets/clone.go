@@ -0,0 +1,61 @@
+package host_sets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"google.golang.org/grpc/codes"
+)
+
+// CloneHostSet creates a new host set with the requested name in the catalog
+// of the host set with its hosts. The description of the host set is copied
+// unless one is requested. The caller must be allowed to read the host set
+// and to create host sets in its catalog.
+func (s Service) CloneHostSet(ctx context.Context, req *pbs.CloneHostSetRequest) (*pbs.CloneHostSetResponse, error) {
+	id, name, description := req.GetId(), req.GetName(), req.GetDescription()
+	badFields := map[string]string{}
+	if !handlers.ValidId(static.HostSetPrefix, id) {
+		badFields["id"] = "Improperly formatted identifier."
+	}
+	if name == "" {
+		badFields["name"] = "This field is required."
+	}
+	if len(badFields) > 0 {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	cat, readResults := s.parentAndAuthResult(ctx, id, action.Read)
+	if readResults.Error != nil {
+		return nil, readResults.Error
+	}
+	_, authResults := s.parentAndAuthResult(ctx, cat.GetPublicId(), action.Create)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.staticRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	var opts []static.Option
+	if description != "" {
+		opts = append(opts, static.WithDescription(description))
+	}
+	out, hs, err := repo.CloneSet(ctx, authResults.Scope.GetId(), id, name, opts...)
+	if err != nil {
+		if e := errors.Convert(err); e != nil {
+			// This is a domain error, push this error through so the error interceptor can interpret it correctly.
+			return nil, e
+		}
+		return nil, fmt.Errorf("unable to clone host set: %w", err)
+	}
+	if out == nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to clone host set but no error returned from repository.")
+	}
+	item := toProto(out, hs)
+	item.Scope = authResults.Scope
+	return &pbs.CloneHostSetResponse{Item: item}, nil
+}
@@ -34,18 +34,20 @@ func init() {
 type Service struct {
 	pbs.UnimplementedHostSetServiceServer
 
-	staticRepoFn common.StaticRepoFactory
+	staticRepoFn     common.StaticRepoFactory
+	annotationRepoFn common.AnnotationRepoFactory
 }
 
 var _ pbs.HostSetServiceServer = Service{}
 
 // NewService returns a host set Service which handles host set related requests to boundary and uses the provided
-// repositories for storage and retrieval.
-func NewService(repoFn common.StaticRepoFactory) (Service, error) {
+// repositories for storage and retrieval. Supported options: handlers.WithAnnotations.
+func NewService(repoFn common.StaticRepoFactory, opt ...handlers.Option) (Service, error) {
 	if repoFn == nil {
 		return Service{}, fmt.Errorf("nil static repository provided")
 	}
-	return Service{staticRepoFn: repoFn}, nil
+	opts := handlers.GetOpts(opt...)
+	return Service{staticRepoFn: repoFn, annotationRepoFn: opts.WithAnnotations}, nil
 }
 
 func (s Service) ListHostSets(ctx context.Context, req *pbs.ListHostSetsRequest) (*pbs.ListHostSetsResponse, error) {
@@ -60,8 +62,17 @@ func (s Service) ListHostSets(ctx context.Context, req *pbs.ListHostSetsRequest)
 	if err != nil {
 		return nil, err
 	}
+	ids := make([]string, 0, len(hl))
+	for _, item := range hl {
+		ids = append(ids, item.GetId())
+	}
+	annotations, err := handlers.ListAnnotations(ctx, s.annotationRepoFn, ids)
+	if err != nil {
+		return nil, err
+	}
 	for _, item := range hl {
 		item.Scope = authResults.Scope
+		item.Annotations = annotations[item.GetId()]
 	}
 	return &pbs.ListHostSetsResponse{Items: hl}, nil
 }
@@ -79,6 +90,9 @@ func (s Service) GetHostSet(ctx context.Context, req *pbs.GetHostSetRequest) (*p
 	if err != nil {
 		return nil, err
 	}
+	if hc.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	hc.Scope = authResults.Scope
 	return &pbs.GetHostSetResponse{Item: hc}, nil
 }
@@ -116,6 +130,9 @@ func (s Service) UpdateHostSet(ctx context.Context, req *pbs.UpdateHostSetReques
 	if err != nil {
 		return nil, err
 	}
+	if hc.Annotations, err = handlers.UpdateAnnotations(ctx, s.annotationRepoFn, resource.HostSet, req.GetId(), req.GetItem().GetAnnotations(), req.GetUpdateMask().GetPaths()); err != nil {
+		return nil, err
+	}
 	hc.Scope = authResults.Scope
 	return &pbs.UpdateHostSetResponse{Item: hc}, nil
 }
@@ -149,6 +166,9 @@ func (s Service) AddHostSetHosts(ctx context.Context, req *pbs.AddHostSetHostsRe
 	if err != nil {
 		return nil, err
 	}
+	if g.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	g.Scope = authResults.Scope
 	return &pbs.AddHostSetHostsResponse{Item: g}, nil
 }
@@ -166,6 +186,9 @@ func (s Service) SetHostSetHosts(ctx context.Context, req *pbs.SetHostSetHostsRe
 	if err != nil {
 		return nil, err
 	}
+	if g.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	g.Scope = authResults.Scope
 	return &pbs.SetHostSetHostsResponse{Item: g}, nil
 }
@@ -183,6 +206,9 @@ func (s Service) RemoveHostSetHosts(ctx context.Context, req *pbs.RemoveHostSetH
 	if err != nil {
 		return nil, err
 	}
+	if g.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	g.Scope = authResults.Scope
 	return &pbs.RemoveHostSetHostsResponse{Item: g}, nil
 }
@@ -255,7 +281,18 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, catalogId, id string
 	h.PublicId = id
 	dbMask := maskManager.Translate(mask)
 	if len(dbMask) == 0 {
-		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		if len(handlers.AnnotationsMask(mask)) == 0 {
+			return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		}
+		// Only annotations are updated, which are not stored with the host set
+		out, err := s.getFromRepo(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if out.GetVersion() != item.GetVersion() {
+			return nil, handlers.NotFoundErrorf("Host Set %q doesn't exist or incorrect version provided.", id)
+		}
+		return out, nil
 	}
 	repo, err := s.staticRepoFn()
 	if err != nil {
@@ -1110,3 +1110,77 @@ func TestRemoveHostSetHosts(t *testing.T) {
 		})
 	}
 }
+
+func TestCloneHostSet(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	rw := db.New(conn)
+	repoFn := func() (*static.Repository, error) {
+		return static.NewRepository(rw, rw, kms)
+	}
+	hc := static.TestCatalogs(t, conn, proj.GetPublicId(), 1)[0]
+	hs := static.TestSets(t, conn, hc.GetPublicId(), 1)[0]
+	h := static.TestHosts(t, conn, hc.GetPublicId(), 2)
+	static.TestSetMembers(t, conn, hs.GetPublicId(), h)
+	hIds := []string{h[0].GetPublicId(), h[1].GetPublicId()}
+
+	s, err := host_sets.NewService(repoFn)
+	require.NoError(t, err, "Couldn't create a new host set service.")
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(proj.GetPublicId()))
+
+	t.Run("clone", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		res, err := s.CloneHostSet(ctx, &pbs.CloneHostSetRequest{Id: hs.GetPublicId(), Name: "clone", Description: "clone description"})
+		require.NoError(err)
+		got := res.GetItem()
+		assert.NotEqual(hs.GetPublicId(), got.GetId())
+		assert.Equal(hc.GetPublicId(), got.GetHostCatalogId())
+		assert.Equal("clone", got.GetName().GetValue())
+		assert.Equal("clone description", got.GetDescription().GetValue())
+		assert.Equal(&scopes.ScopeInfo{Id: proj.GetPublicId(), Type: scope.Project.String()}, got.GetScope())
+		assert.ElementsMatch(hIds, got.GetHostIds())
+
+		gotSet, err := s.GetHostSet(ctx, &pbs.GetHostSetRequest{Id: got.GetId()})
+		require.NoError(err)
+		assert.ElementsMatch(hIds, gotSet.GetItem().GetHostIds())
+	})
+
+	cases := []struct {
+		name    string
+		id      string
+		setName string
+		err     error
+	}{
+		{
+			name:    "Non existing Host Set",
+			id:      static.HostSetPrefix + "_DoesntExis",
+			setName: "missing",
+			err:     handlers.ApiErrorWithCode(codes.NotFound),
+		},
+		{
+			name:    "Wrong id prefix",
+			id:      "j_1234567890",
+			setName: "wrong",
+			err:     handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Missing name",
+			id:   hs.GetPublicId(),
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := s.CloneHostSet(ctx, &pbs.CloneHostSetRequest{Id: tc.id, Name: tc.setName})
+			require.Error(err)
+			assert.True(errors.Is(err, tc.err), "CloneHostSet(%q) got error %v, wanted %v", tc.id, err, tc.err)
+			assert.Nil(got)
+		})
+	}
+}
@@ -3,7 +3,6 @@ package hosts
 import (
 	"context"
 	"fmt"
-	"net"
 	"strings"
 
 	"github.com/hashicorp/boundary/internal/auth"
@@ -13,6 +12,7 @@ import (
 	"github.com/hashicorp/boundary/internal/host"
 	"github.com/hashicorp/boundary/internal/host/static"
 	"github.com/hashicorp/boundary/internal/host/static/store"
+	"github.com/hashicorp/boundary/internal/libs/endpoint"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/types/action"
@@ -35,18 +35,20 @@ func init() {
 type Service struct {
 	pbs.UnimplementedHostServiceServer
 
-	staticRepoFn common.StaticRepoFactory
+	staticRepoFn     common.StaticRepoFactory
+	annotationRepoFn common.AnnotationRepoFactory
 }
 
 var _ pbs.HostServiceServer = Service{}
 
 // NewService returns a host Service which handles host related requests to boundary and uses the provided
-// repositories for storage and retrieval.
-func NewService(repoFn common.StaticRepoFactory) (Service, error) {
+// repositories for storage and retrieval. Supported options: handlers.WithAnnotations.
+func NewService(repoFn common.StaticRepoFactory, opt ...handlers.Option) (Service, error) {
 	if repoFn == nil {
 		return Service{}, fmt.Errorf("nil static repository provided")
 	}
-	return Service{staticRepoFn: repoFn}, nil
+	opts := handlers.GetOpts(opt...)
+	return Service{staticRepoFn: repoFn, annotationRepoFn: opts.WithAnnotations}, nil
 }
 
 func (s Service) ListHosts(ctx context.Context, req *pbs.ListHostsRequest) (*pbs.ListHostsResponse, error) {
@@ -61,8 +63,17 @@ func (s Service) ListHosts(ctx context.Context, req *pbs.ListHostsRequest) (*pbs
 	if err != nil {
 		return nil, err
 	}
+	ids := make([]string, 0, len(hl))
+	for _, item := range hl {
+		ids = append(ids, item.GetId())
+	}
+	annotations, err := handlers.ListAnnotations(ctx, s.annotationRepoFn, ids)
+	if err != nil {
+		return nil, err
+	}
 	for _, item := range hl {
 		item.Scope = authResults.Scope
+		item.Annotations = annotations[item.GetId()]
 	}
 	return &pbs.ListHostsResponse{Items: hl}, nil
 }
@@ -80,6 +91,9 @@ func (s Service) GetHost(ctx context.Context, req *pbs.GetHostRequest) (*pbs.Get
 	if err != nil {
 		return nil, err
 	}
+	if hc.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	hc.Scope = authResults.Scope
 	return &pbs.GetHostResponse{Item: hc}, nil
 }
@@ -117,6 +131,9 @@ func (s Service) UpdateHost(ctx context.Context, req *pbs.UpdateHostRequest) (*p
 	if err != nil {
 		return nil, err
 	}
+	if hc.Annotations, err = handlers.UpdateAnnotations(ctx, s.annotationRepoFn, resource.Host, req.GetId(), req.GetItem().GetAnnotations(), req.GetUpdateMask().GetPaths()); err != nil {
+		return nil, err
+	}
 	hc.Scope = authResults.Scope
 	return &pbs.UpdateHostResponse{Item: hc}, nil
 }
@@ -220,7 +237,18 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, catalogId, id string
 	h.PublicId = id
 	dbMask := maskManager.Translate(mask)
 	if len(dbMask) == 0 {
-		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		if len(handlers.AnnotationsMask(mask)) == 0 {
+			return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		}
+		// Only annotations are updated, which are not stored with the host
+		out, err := s.getFromRepo(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if out.GetVersion() != item.GetVersion() {
+			return nil, handlers.NotFoundErrorf("Host %q doesn't exist or incorrect version provided.", id)
+		}
+		return out, nil
 	}
 	repo, err := s.staticRepoFn()
 	if err != nil {
@@ -380,14 +408,8 @@ func validateCreateRequest(req *pbs.CreateHostRequest) error {
 				len(attrs.GetAddress().GetValue()) > static.MaxHostAddressLength {
 				badFields["attributes.address"] = fmt.Sprintf("Address length must be between %d and %d characters.", static.MinHostAddressLength, static.MaxHostAddressLength)
 			}
-			_, _, err := net.SplitHostPort(attrs.GetAddress().GetValue())
-			switch {
-			case err == nil:
-				badFields["attributes.address"] = "Address for static hosts does not support a port."
-			case strings.Contains(err.Error(), "missing port in address"):
-				// Bare hostname, which we want
-			default:
-				badFields["attributes.address"] = fmt.Sprintf("Error parsing address: %v.", err)
+			if msg := validateAddress(attrs.GetAddress().GetValue()); msg != "" {
+				badFields["attributes.address"] = msg
 			}
 		}
 		return badFields
@@ -401,18 +423,20 @@ func validateUpdateRequest(req *pbs.UpdateHostRequest) error {
 		case host.StaticSubtype:
 			if req.GetItem().GetType() != "" && req.GetItem().GetType() != host.StaticSubtype.String() {
 				badFields["type"] = "Cannot modify the resource type."
+			}
 
-				attrs := &pb.StaticHostAttributes{}
-				if err := handlers.StructToProto(req.GetItem().GetAttributes(), attrs); err != nil {
-					badFields["attributes"] = "Attribute fields do not match the expected format."
-				}
+			attrs := &pb.StaticHostAttributes{}
+			if err := handlers.StructToProto(req.GetItem().GetAttributes(), attrs); err != nil {
+				badFields["attributes"] = "Attribute fields do not match the expected format."
+			}
 
-				if handlers.MaskContains(req.GetUpdateMask().GetPaths(), "attributes.address") {
-					if attrs.GetAddress() == nil ||
-						len(strings.TrimSpace(attrs.GetAddress().GetValue())) < static.MinHostAddressLength ||
-						len(strings.TrimSpace(attrs.GetAddress().GetValue())) > static.MaxHostAddressLength {
-						badFields["attributes.address"] = fmt.Sprintf("Address length must be between %d and %d characters.", static.MinHostAddressLength, static.MaxHostAddressLength)
-					}
+			if handlers.MaskContains(req.GetUpdateMask().GetPaths(), "attributes.address") {
+				if attrs.GetAddress() == nil ||
+					len(strings.TrimSpace(attrs.GetAddress().GetValue())) < static.MinHostAddressLength ||
+					len(strings.TrimSpace(attrs.GetAddress().GetValue())) > static.MaxHostAddressLength {
+					badFields["attributes.address"] = fmt.Sprintf("Address length must be between %d and %d characters.", static.MinHostAddressLength, static.MaxHostAddressLength)
+				} else if msg := validateAddress(attrs.GetAddress().GetValue()); msg != "" {
+					badFields["attributes.address"] = msg
 				}
 			}
 		default:
@@ -422,6 +446,20 @@ func validateUpdateRequest(req *pbs.UpdateHostRequest) error {
 	})
 }
 
+// validateAddress returns why address is not a valid static host address,
+// or an empty string if it is. IPv6 literals may be bracketed but, like all
+// addresses, must not include a port.
+func validateAddress(address string) string {
+	_, port, err := endpoint.SplitHostPort(address, "")
+	switch {
+	case err != nil:
+		return fmt.Sprintf("Error parsing address: %v.", err)
+	case port != "":
+		return "Address for static hosts does not support a port."
+	}
+	return ""
+}
+
 func validateDeleteRequest(req *pbs.DeleteHostRequest) error {
 	return handlers.ValidateDeleteRequest(static.HostPrefix, req, handlers.NoopValidatorFn)
 }
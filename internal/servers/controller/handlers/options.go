@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/hashicorp/boundary/internal/authhook"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
+)
+
+// GetOpts - iterate the inbound Options and return a struct
+func GetOpts(opt ...Option) Options {
+	opts := getDefaultOptions()
+	for _, o := range opt {
+		o(&opts)
+	}
+	return opts
+}
+
+// Option - how Options are passed as arguments
+type Option func(*Options)
+
+// Options - how Options are represented; they are exported so that service
+// handlers in other packages can read them.
+type Options struct {
+	WithResponseCache      *ResponseCache
+	WithKms                *kms.Kms
+	WithWorkerSelector     servers.WorkerSelector
+	WithWorkerAffinity     time.Duration
+	WithSecurityEvents     common.SecurityEventRepoFactory
+	WithAuthHooks          *authhook.Runner
+	WithSecretFingerprints common.SecretFingerprintRepoFactory
+	WithUsage              common.UsageRepoFactory
+	WithEventSinks         common.EventSinkRepoFactory
+	WithAnnotations        common.AnnotationRepoFactory
+	WithHistory            common.HistorySearchFn
+}
+
+func getDefaultOptions() Options {
+	return Options{}
+}
+
+// WithResponseCache provides an optional response cache to a service handler.
+func WithResponseCache(c *ResponseCache) Option {
+	return func(o *Options) {
+		o.WithResponseCache = c
+	}
+}
+
+// WithKms provides an optional kms to a service handler.
+func WithKms(k *kms.Kms) Option {
+	return func(o *Options) {
+		o.WithKms = k
+	}
+}
+
+// WithWorkerSelector provides an optional worker selector to a service handler.
+func WithWorkerSelector(ws servers.WorkerSelector) Option {
+	return func(o *Options) {
+		o.WithWorkerSelector = ws
+	}
+}
+
+// WithWorkerAffinity provides an optional window to a service handler, for
+// which the worker of a session is preferred for the next session of its user
+// to its target. Worker affinity is disabled if it is not positive.
+func WithWorkerAffinity(window time.Duration) Option {
+	return func(o *Options) {
+		o.WithWorkerAffinity = window
+	}
+}
+
+// WithSecurityEvents provides an optional security event repository to a
+// service handler, which records the security events it handles in it.
+func WithSecurityEvents(fn common.SecurityEventRepoFactory) Option {
+	return func(o *Options) {
+		o.WithSecurityEvents = fn
+	}
+}
+
+// WithAuthHooks provides optional authentication hooks to a service handler,
+// which calls them when authenticating.
+func WithAuthHooks(r *authhook.Runner) Option {
+	return func(o *Options) {
+		o.WithAuthHooks = r
+	}
+}
+
+// WithSecretFingerprints provides an optional secret fingerprint repository to
+// a service handler, which registers the fingerprints of the secrets it
+// brokers in it.
+func WithSecretFingerprints(fn common.SecretFingerprintRepoFactory) Option {
+	return func(o *Options) {
+		o.WithSecretFingerprints = fn
+	}
+}
+
+// WithUsage provides an optional usage repository to a service handler, which
+// lists the daily usage of scopes from it.
+func WithUsage(fn common.UsageRepoFactory) Option {
+	return func(o *Options) {
+		o.WithUsage = fn
+	}
+}
+
+// WithEventSinks provides an optional event sink repository to a service
+// handler, which manages the event sinks of scopes in it.
+func WithEventSinks(fn common.EventSinkRepoFactory) Option {
+	return func(o *Options) {
+		o.WithEventSinks = fn
+	}
+}
+
+// WithAnnotations provides an optional annotation repository to a service
+// handler, which reads and updates the annotations of its resources in it.
+// Without it the resources have no annotations.
+func WithAnnotations(fn common.AnnotationRepoFactory) Option {
+	return func(o *Options) {
+		o.WithAnnotations = fn
+	}
+}
+
+// WithHistory provides an optional oplog search to a service handler, which
+// reads the history of its resources with it.
+func WithHistory(fn common.HistorySearchFn) Option {
+	return func(o *Options) {
+		o.WithHistory = fn
+	}
+}
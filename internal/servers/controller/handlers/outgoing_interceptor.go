@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"google.golang.org/protobuf/proto"
 )
@@ -12,10 +13,26 @@ import (
 const (
 	HttpOnlyCookieName  = "wt-http-token-cookie"
 	JsVisibleCookieName = "wt-js-token-cookie"
+
+	// ServerTimingMetadataKey is the gRPC header metadata key handlers set
+	// to the phases they timed, sent to clients as the Server-Timing header
+	ServerTimingMetadataKey = "server-timing"
+
+	// ContentDispositionMetadataKey is the gRPC header metadata key handlers
+	// returning files set to the Content-Disposition header of the response
+	ContentDispositionMetadataKey = "content-disposition"
 )
 
 func OutgoingInterceptor(ctx context.Context, w http.ResponseWriter, m proto.Message) error {
 	m = m.ProtoReflect().Interface()
+	if md, ok := runtime.ServerMetadataFromContext(ctx); ok {
+		if timing := md.HeaderMD.Get(ServerTimingMetadataKey); len(timing) > 0 {
+			w.Header().Set("Server-Timing", strings.Join(timing, ", "))
+		}
+		if cd := md.HeaderMD.Get(ContentDispositionMetadataKey); len(cd) > 0 {
+			w.Header().Set("Content-Disposition", cd[0])
+		}
+	}
 	switch m := m.(type) {
 	case *pbs.AuthenticateResponse:
 		if strings.EqualFold(m.GetTokenType(), "cookie") {
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultResponseCacheTimeToLive is the time a cached response is served
+	// for when no time to live is configured.
+	DefaultResponseCacheTimeToLive = 5 * time.Second
+
+	// DefaultResponseCacheMaxEntries is the maximum number of cached responses
+	// per resource type when no maximum is configured.
+	DefaultResponseCacheMaxEntries = 1000
+)
+
+// ResponseCache is an in-memory cache of responses for frequently read
+// endpoints. Entries are grouped by the resource type they contain so that a
+// change to any resource of that type invalidates every cached response which
+// may include it. A nil *ResponseCache is valid and caches nothing, so services
+// can use it unconditionally.
+type ResponseCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	l       sync.Mutex
+	entries map[resource.Type]map[string]responseCacheEntry
+	// generations is incremented every time a resource type is invalidated
+	// and is used to discard responses computed before the invalidation.
+	generations map[resource.Type]uint64
+}
+
+type responseCacheEntry struct {
+	msg     proto.Message
+	expires time.Time
+}
+
+// NewResponseCache creates a ResponseCache. If ttl or maxEntries are not
+// positive the defaults are used.
+func NewResponseCache(ttl time.Duration, maxEntries int) *ResponseCache {
+	if ttl <= 0 {
+		ttl = DefaultResponseCacheTimeToLive
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultResponseCacheMaxEntries
+	}
+	return &ResponseCache{
+		ttl:         ttl,
+		maxEntries:  maxEntries,
+		entries:     make(map[resource.Type]map[string]responseCacheEntry),
+		generations: make(map[resource.Type]uint64),
+	}
+}
+
+// ResponseCacheKey builds a cache key for the method, the scope the request is
+// made against and the hash of the caller's grants, so that callers with
+// different permissions never share a cached response.
+func ResponseCacheKey(method, scopeId, grantsHash string) string {
+	return strings.Join([]string{method, scopeId, grantsHash}, "|")
+}
+
+// GrantsHash returns a stable hash of a set of grants. The order of the grants
+// does not affect the result.
+func GrantsHash(grants []string) string {
+	sorted := make([]string, len(grants))
+	copy(sorted, grants)
+	sort.Strings(sorted)
+	h := sha256.New()
+	for _, g := range sorted {
+		_, _ = h.Write([]byte(g))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Generation returns the current generation for the resource type. It should
+// be called before computing a response which will be passed to Set.
+func (c *ResponseCache) Generation(resType resource.Type) uint64 {
+	if c == nil {
+		return 0
+	}
+	c.l.Lock()
+	defer c.l.Unlock()
+	return c.generations[resType]
+}
+
+// Get returns a copy of the cached response for the key, if one exists and
+// has not expired.
+func (c *ResponseCache) Get(resType resource.Type, key string) (proto.Message, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.l.Lock()
+	defer c.l.Unlock()
+	entry, ok := c.entries[resType][key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries[resType], key)
+		return nil, false
+	}
+	return proto.Clone(entry.msg), true
+}
+
+// Set caches a copy of msg for the key. generation must be the value returned
+// by Generation before the response was computed; if the resource type has
+// been invalidated since then the response is not cached.
+func (c *ResponseCache) Set(resType resource.Type, generation uint64, key string, msg proto.Message) {
+	if c == nil || msg == nil {
+		return
+	}
+	c.l.Lock()
+	defer c.l.Unlock()
+	if c.generations[resType] != generation {
+		return
+	}
+	entries, ok := c.entries[resType]
+	if !ok {
+		entries = make(map[string]responseCacheEntry)
+		c.entries[resType] = entries
+	}
+	now := time.Now()
+	if _, ok := entries[key]; !ok && len(entries) >= c.maxEntries {
+		for k, e := range entries {
+			if now.After(e.expires) {
+				delete(entries, k)
+			}
+		}
+		if len(entries) >= c.maxEntries {
+			// Still full, so don't cache rather than evicting live entries
+			return
+		}
+	}
+	entries[key] = responseCacheEntry{
+		msg:     proto.Clone(msg),
+		expires: now.Add(c.ttl),
+	}
+}
+
+// Invalidate removes all cached responses for the given resource types. It
+// should be called whenever a resource of one of the types is changed.
+func (c *ResponseCache) Invalidate(resTypes ...resource.Type) {
+	if c == nil {
+		return
+	}
+	c.l.Lock()
+	defer c.l.Unlock()
+	for _, t := range resTypes {
+		delete(c.entries, t)
+		c.generations[t]++
+	}
+}
+
+// InvalidateChanges removes all cached responses for the resource types of the
+// committed changes, and of the resources a deleted scope contains. It is a
+// db.CommitListener, so the cache is invalidated by every write of the
+// controller which records its changes, whichever service or background job
+// made it.
+func (c *ResponseCache) InvalidateChanges(_ context.Context, changes []db.Change) {
+	if c == nil {
+		return
+	}
+	resTypes := make([]resource.Type, 0, len(changes))
+	for _, ch := range changes {
+		resTypes = append(resTypes, ch.ResourceType)
+		if ch.ResourceType == resource.Scope && ch.Op == db.DeleteOp {
+			resTypes = append(resTypes, scopedResourceTypes...)
+		}
+	}
+	c.Invalidate(resTypes...)
+}
+
+// scopedResourceTypes are the types of the cached responses which list
+// resources within scopes, which are deleted along with their scope.
+var scopedResourceTypes = []resource.Type{resource.Scope, resource.AuthMethod}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestResponseCache_GetSet(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	c := NewResponseCache(time.Minute, 0)
+	key := ResponseCacheKey("ListScopes", "global", GrantsHash([]string{"id=*;actions=*"}))
+
+	_, ok := c.Get(resource.Scope, key)
+	assert.False(ok)
+
+	msg := wrapperspb.String("first")
+	c.Set(resource.Scope, c.Generation(resource.Scope), key, msg)
+
+	got, ok := c.Get(resource.Scope, key)
+	require.True(ok)
+	assert.True(proto.Equal(msg, got))
+
+	// Mutating the returned message must not affect the cached copy
+	got.(*wrapperspb.StringValue).Value = "changed"
+	got, ok = c.Get(resource.Scope, key)
+	require.True(ok)
+	assert.Equal("first", got.(*wrapperspb.StringValue).GetValue())
+
+	// Entries are stored per resource type
+	_, ok = c.Get(resource.AuthMethod, key)
+	assert.False(ok)
+}
+
+func TestResponseCache_Expiry(t *testing.T) {
+	assert := assert.New(t)
+	c := NewResponseCache(10*time.Millisecond, 0)
+	c.Set(resource.Scope, c.Generation(resource.Scope), "key", wrapperspb.String("value"))
+	_, ok := c.Get(resource.Scope, "key")
+	assert.True(ok)
+
+	time.Sleep(20 * time.Millisecond)
+	_, ok = c.Get(resource.Scope, "key")
+	assert.False(ok)
+}
+
+func TestResponseCache_Invalidate(t *testing.T) {
+	assert := assert.New(t)
+	c := NewResponseCache(time.Minute, 0)
+	c.Set(resource.Scope, c.Generation(resource.Scope), "key", wrapperspb.String("scope"))
+	c.Set(resource.AuthMethod, c.Generation(resource.AuthMethod), "key", wrapperspb.String("authmethod"))
+
+	c.Invalidate(resource.Scope)
+	_, ok := c.Get(resource.Scope, "key")
+	assert.False(ok)
+	_, ok = c.Get(resource.AuthMethod, "key")
+	assert.True(ok)
+
+	// A response computed before an invalidation must not be cached
+	gen := c.Generation(resource.AuthMethod)
+	c.Invalidate(resource.AuthMethod)
+	c.Set(resource.AuthMethod, gen, "key", wrapperspb.String("stale"))
+	_, ok = c.Get(resource.AuthMethod, "key")
+	assert.False(ok)
+}
+
+func TestResponseCache_InvalidateChanges(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	c := NewResponseCache(time.Minute, 0)
+	unregister := db.RegisterCommitListener(c.InvalidateChanges)
+	defer unregister()
+	set := func() {
+		c.Set(resource.Scope, c.Generation(resource.Scope), "key", wrapperspb.String("scope"))
+		c.Set(resource.AuthMethod, c.Generation(resource.AuthMethod), "key", wrapperspb.String("authmethod"))
+	}
+	cached := func(resType resource.Type) bool {
+		_, ok := c.Get(resType, "key")
+		return ok
+	}
+
+	// Changes committed by any writer invalidate the responses of their type
+	set()
+	db.New(nil).NotifyOnCommit(ctx, db.Change{ResourceType: resource.AuthMethod, Op: db.UpdateOp, Id: "ampw_1234567890"})
+	assert.True(cached(resource.Scope))
+	assert.False(cached(resource.AuthMethod))
+
+	// Deleting a scope deletes the resources within it
+	set()
+	db.New(nil).NotifyOnCommit(ctx, db.Change{ResourceType: resource.Scope, Op: db.DeleteOp, Id: "o_1234567890"})
+	assert.False(cached(resource.Scope))
+	assert.False(cached(resource.AuthMethod))
+
+	// Once unregistered, changes don't invalidate the cache anymore
+	set()
+	unregister()
+	db.New(nil).NotifyOnCommit(ctx, db.Change{ResourceType: resource.AuthMethod, Op: db.DeleteOp, Id: "ampw_1234567890"})
+	assert.True(cached(resource.AuthMethod))
+}
+
+func TestResponseCache_MaxEntries(t *testing.T) {
+	assert := assert.New(t)
+	c := NewResponseCache(time.Minute, 1)
+	c.Set(resource.Scope, c.Generation(resource.Scope), "one", wrapperspb.String("one"))
+	c.Set(resource.Scope, c.Generation(resource.Scope), "two", wrapperspb.String("two"))
+
+	_, ok := c.Get(resource.Scope, "one")
+	assert.True(ok)
+	_, ok = c.Get(resource.Scope, "two")
+	assert.False(ok)
+}
+
+func TestResponseCache_Nil(t *testing.T) {
+	assert := assert.New(t)
+	var c *ResponseCache
+	assert.Equal(uint64(0), c.Generation(resource.Scope))
+	c.Set(resource.Scope, 0, "key", wrapperspb.String("value"))
+	_, ok := c.Get(resource.Scope, "key")
+	assert.False(ok)
+	c.Invalidate(resource.Scope)
+}
+
+func TestGrantsHash(t *testing.T) {
+	assert := assert.New(t)
+	a := GrantsHash([]string{"global:id=*;actions=read", "o_1234:id=*;actions=*"})
+	b := GrantsHash([]string{"o_1234:id=*;actions=*", "global:id=*;actions=read"})
+	assert.Equal(a, b)
+
+	c := GrantsHash([]string{"global:id=*;actions=read"})
+	assert.NotEqual(a, c)
+	assert.NotEqual(GrantsHash([]string{"ab", "c"}), GrantsHash([]string{"a", "bc"}))
+}
@@ -0,0 +1,245 @@
+package roles
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/errors"
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/roles"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxAccessRequestDuration is the longest a role may be requested for.
+const maxAccessRequestDuration = 7 * 24 * time.Hour
+
+// RequestRoleAccess creates a pending request by the caller to be assigned
+// the role for the duration. It requires the request-access action on the
+// role.
+func (s Service) RequestRoleAccess(ctx context.Context, req *pbs.RequestRoleAccessRequest) (*pbs.RequestRoleAccessResponse, error) {
+	roleId, justification := req.GetId(), req.GetJustification()
+	duration := time.Duration(req.GetDurationSeconds()) * time.Second
+	badFields := map[string]string{}
+	if !handlers.ValidId(iam.RolePrefix, roleId) {
+		badFields["id"] = "Incorrectly formatted identifier."
+	}
+	if strings.TrimSpace(justification) == "" {
+		badFields["justification"] = "Required field."
+	}
+	if duration < time.Second || duration > maxAccessRequestDuration {
+		badFields["duration_seconds"] = fmt.Sprintf("Must be between 1 and %d.", int64(maxAccessRequestDuration/time.Second))
+	}
+	if len(badFields) > 0 {
+		return nil, handlers.InvalidArgumentErrorf("Errors in provided fields.", badFields)
+	}
+	authResults := s.authResult(ctx, roleId, action.RequestAccess)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	if authResults.UserId == "" || authResults.UserId == "u_anon" {
+		return nil, handlers.UnauthenticatedError()
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	ar, err := repo.CreateAccessRequest(ctx, roleId, authResults.UserId, justification, duration)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.RequestRoleAccessResponse{Item: toAccessRequestProto(ar)}, nil
+}
+
+// ListRoleAccessRequests returns the access requests for the role. Callers
+// which may read the role get all of them, callers which may only request
+// access to it get their own.
+func (s Service) ListRoleAccessRequests(ctx context.Context, req *pbs.ListRoleAccessRequestsRequest) (*pbs.ListRoleAccessRequestsResponse, error) {
+	roleId := req.GetId()
+	if !handlers.ValidId(iam.RolePrefix, roleId) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Incorrectly formatted identifier."})
+	}
+	var opts []iam.Option
+	authResults := s.authResult(ctx, roleId, action.Read)
+	if authResults.Error != nil {
+		requesterResults := s.authResult(ctx, roleId, action.RequestAccess)
+		if requesterResults.Error != nil || requesterResults.UserId == "" {
+			return nil, authResults.Error
+		}
+		opts = append(opts, iam.WithUserId(requesterResults.UserId))
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	reqs, err := repo.ListAccessRequests(ctx, roleId, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := &pbs.ListRoleAccessRequestsResponse{
+		RoleId: roleId,
+		Items:  make([]*pb.AccessRequest, 0, len(reqs)),
+	}
+	for _, ar := range reqs {
+		out.Items = append(out.Items, toAccessRequestProto(ar))
+	}
+	return out, nil
+}
+
+// GetAccessRequest returns the access request with its events. Callers which
+// may read its role may read any request, its requester may read its own.
+func (s Service) GetAccessRequest(ctx context.Context, req *pbs.GetAccessRequestRequest) (*pbs.GetAccessRequestResponse, error) {
+	id := req.GetId()
+	ar, authResults := s.accessRequestAndAuthResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		if ar == nil {
+			return nil, authResults.Error
+		}
+		requesterResults := s.authResult(ctx, ar.RoleId, action.RequestAccess)
+		if requesterResults.Error != nil || requesterResults.UserId != ar.RequesterId {
+			return nil, authResults.Error
+		}
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	events, err := repo.ListAccessRequestEvents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	out := toAccessRequestProto(ar)
+	for _, e := range events {
+		out.Events = append(out.Events, &pb.AccessRequestEvent{
+			Event:       e.Event,
+			ActorId:     e.ActorId,
+			Comment:     e.Comment,
+			CreatedTime: timestamppb.New(e.CreateTime),
+		})
+	}
+	return &pbs.GetAccessRequestResponse{Item: out}, nil
+}
+
+// ApproveAccessRequest approves the pending access request, assigning its
+// role to its requester until its duration has passed. It requires the
+// approve action on the role and the caller may not be the requester.
+func (s Service) ApproveAccessRequest(ctx context.Context, req *pbs.ApproveAccessRequestRequest) (*pbs.ApproveAccessRequestResponse, error) {
+	id := req.GetId()
+	_, authResults := s.accessRequestAndAuthResult(ctx, id, action.Approve)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	ar, err := repo.ApproveAccessRequest(ctx, id, authResults.UserId, req.GetComment())
+	if err != nil {
+		return nil, accessRequestError(err)
+	}
+	return &pbs.ApproveAccessRequestResponse{Item: toAccessRequestProto(ar)}, nil
+}
+
+// DenyAccessRequest denies the pending access request. It requires the deny
+// action on the role and the caller may not be the requester.
+func (s Service) DenyAccessRequest(ctx context.Context, req *pbs.DenyAccessRequestRequest) (*pbs.DenyAccessRequestResponse, error) {
+	id := req.GetId()
+	_, authResults := s.accessRequestAndAuthResult(ctx, id, action.Deny)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	ar, err := repo.DenyAccessRequest(ctx, id, authResults.UserId, req.GetComment())
+	if err != nil {
+		return nil, accessRequestError(err)
+	}
+	return &pbs.DenyAccessRequestResponse{Item: toAccessRequestProto(ar)}, nil
+}
+
+// CancelAccessRequest cancels the caller's pending access request. It
+// requires the request-access action on the role.
+func (s Service) CancelAccessRequest(ctx context.Context, req *pbs.CancelAccessRequestRequest) (*pbs.CancelAccessRequestResponse, error) {
+	id := req.GetId()
+	current, authResults := s.accessRequestAndAuthResult(ctx, id, action.RequestAccess)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	if current.RequesterId != authResults.UserId {
+		return nil, handlers.ForbiddenError()
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	ar, err := repo.CancelAccessRequest(ctx, id, authResults.UserId)
+	if err != nil {
+		return nil, accessRequestError(err)
+	}
+	return &pbs.CancelAccessRequestResponse{Item: toAccessRequestProto(ar)}, nil
+}
+
+// accessRequestAndAuthResult looks up the access request and authorizes the
+// action on its role.
+func (s Service) accessRequestAndAuthResult(ctx context.Context, id string, a action.Type) (*iam.AccessRequest, auth.VerifyResults) {
+	res := auth.VerifyResults{}
+	if !handlers.ValidId(iam.AccessRequestPrefix, id) {
+		res.Error = handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Incorrectly formatted identifier."})
+		return nil, res
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		res.Error = err
+		return nil, res
+	}
+	req, err := repo.LookupAccessRequest(ctx, id)
+	if err != nil {
+		res.Error = err
+		return nil, res
+	}
+	if req == nil {
+		res.Error = handlers.NotFoundError()
+		return nil, res
+	}
+	return req, s.authResult(ctx, req.RoleId, a)
+}
+
+func accessRequestError(err error) error {
+	switch {
+	case errors.Is(err, iam.ErrAccessRequestNotPending):
+		return handlers.ApiErrorWithCodeAndMessage(codes.FailedPrecondition, "Access request is not pending.")
+	case errors.Is(err, iam.ErrAccessRequestSelfDecision):
+		return handlers.ApiErrorWithCodeAndMessage(codes.PermissionDenied, "Access requests can't be decided by their requester.")
+	}
+	return err
+}
+
+func toAccessRequestProto(in *iam.AccessRequest) *pb.AccessRequest {
+	out := &pb.AccessRequest{
+		Id:              in.PublicId,
+		RoleId:          in.RoleId,
+		RequesterId:     in.RequesterId,
+		Justification:   in.Justification,
+		DurationSeconds: uint32(in.Duration / time.Second),
+		Status:          in.Status,
+		DeciderId:       in.DeciderId,
+		DecisionComment: in.DecisionComment,
+		CreatedTime:     timestamppb.New(in.CreateTime),
+		UpdatedTime:     timestamppb.New(in.UpdateTime),
+	}
+	if in.DecisionTime != nil {
+		out.DecisionTime = timestamppb.New(*in.DecisionTime)
+	}
+	if in.ExpirationTime != nil {
+		out.ExpirationTime = timestamppb.New(*in.ExpirationTime)
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package roles
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/roles"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/sdk/strutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AddTemporaryRolePrincipals adds the principals to the role until the
+// expiration time, after which they no longer get its grants and are removed
+// from it, and returns the role's temporary principals.
+func (s Service) AddTemporaryRolePrincipals(ctx context.Context, req *pbs.AddTemporaryRolePrincipalsRequest) (*pbs.AddTemporaryRolePrincipalsResponse, error) {
+	id := req.GetId()
+	if err := validateAddRolePrincipalsRequest(&pbs.AddRolePrincipalsRequest{Id: id, Version: req.GetVersion(), PrincipalIds: req.GetPrincipalIds()}); err != nil {
+		return nil, err
+	}
+	if req.GetExpirationTime() == nil {
+		return nil, handlers.InvalidArgumentErrorf("Errors in provided fields.", map[string]string{"expiration_time": "This field is required."})
+	}
+	expirationTime := req.GetExpirationTime().AsTime()
+	if !expirationTime.After(time.Now()) {
+		return nil, handlers.InvalidArgumentErrorf("Errors in provided fields.", map[string]string{"expiration_time": "Must be in the future."})
+	}
+	authResults := s.authResult(ctx, id, action.AddPrincipals)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	_, err = repo.AddPrincipalRoles(ctx, id, req.GetVersion(), strutil.RemoveDuplicates(req.GetPrincipalIds(), false), iam.WithExpirationTime(expirationTime))
+	if err != nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to add principals to role: %v.", err)
+	}
+	version, items, err := s.principalExpirations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.AddTemporaryRolePrincipalsResponse{RoleId: id, Version: version, Items: items}, nil
+}
+
+// ListRolePrincipalExpirations returns the temporary principals of the role.
+func (s Service) ListRolePrincipalExpirations(ctx context.Context, req *pbs.ListRolePrincipalExpirationsRequest) (*pbs.ListRolePrincipalExpirationsResponse, error) {
+	id := req.GetId()
+	if !handlers.ValidId(iam.RolePrefix, id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Incorrectly formatted identifier."})
+	}
+	authResults := s.authResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	version, items, err := s.principalExpirations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.ListRolePrincipalExpirationsResponse{RoleId: id, Version: version, Items: items}, nil
+}
+
+// principalExpirations returns the version of the role and its temporary
+// principals.
+func (s Service) principalExpirations(ctx context.Context, id string) (uint32, []*pb.PrincipalExpiration, error) {
+	repo, err := s.repoFn()
+	if err != nil {
+		return 0, nil, err
+	}
+	r, _, _, err := repo.LookupRole(ctx, id)
+	if err != nil {
+		return 0, nil, err
+	}
+	if r == nil {
+		return 0, nil, handlers.NotFoundErrorf("Role %q doesn't exist.", id)
+	}
+	exps, err := repo.ListPrincipalRoleExpirations(ctx, id)
+	if err != nil {
+		return 0, nil, err
+	}
+	items := make([]*pb.PrincipalExpiration, 0, len(exps))
+	for _, e := range exps {
+		items = append(items, &pb.PrincipalExpiration{
+			PrincipalId:    e.PrincipalId,
+			ExpirationTime: timestamppb.New(e.ExpirationTime),
+		})
+	}
+	return r.GetVersion(), items, nil
+}
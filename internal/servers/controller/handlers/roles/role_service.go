@@ -36,15 +36,25 @@ func init() {
 type Service struct {
 	pbs.UnimplementedRoleServiceServer
 
-	repoFn common.IamRepoFactory
+	repoFn           common.IamRepoProvider
+	annotationRepoFn common.AnnotationRepoFactory
 }
 
 // NewService returns a role service which handles role related requests to boundary.
-func NewService(repo common.IamRepoFactory) (Service, error) {
+// Supported options: handlers.WithAnnotations.
+func NewService(repo common.IamRepoFactory, opt ...handlers.Option) (Service, error) {
+	return NewServiceWithRepos(repo.Provider(), opt...)
+}
+
+// NewServiceWithRepos returns a role service using the iam repositories of the
+// provider, which need not be the database backed ones. It takes the same
+// options as NewService.
+func NewServiceWithRepos(repo common.IamRepoProvider, opt ...handlers.Option) (Service, error) {
 	if repo == nil {
 		return Service{}, fmt.Errorf("nil iam repository provided")
 	}
-	return Service{repoFn: repo}, nil
+	opts := handlers.GetOpts(opt...)
+	return Service{repoFn: repo, annotationRepoFn: opts.WithAnnotations}, nil
 }
 
 var _ pbs.RoleServiceServer = Service{}
@@ -62,8 +72,17 @@ func (s Service) ListRoles(ctx context.Context, req *pbs.ListRolesRequest) (*pbs
 	if err != nil {
 		return nil, err
 	}
+	ids := make([]string, 0, len(gl))
+	for _, item := range gl {
+		ids = append(ids, item.GetId())
+	}
+	annotations, err := handlers.ListAnnotations(ctx, s.annotationRepoFn, ids)
+	if err != nil {
+		return nil, err
+	}
 	for _, item := range gl {
 		item.Scope = authResults.Scope
+		item.Annotations = annotations[item.GetId()]
 	}
 	return &pbs.ListRolesResponse{Items: gl}, nil
 }
@@ -81,6 +100,9 @@ func (s Service) GetRole(ctx context.Context, req *pbs.GetRoleRequest) (*pbs.Get
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.GetRoleResponse{Item: u}, nil
 }
@@ -115,6 +137,9 @@ func (s Service) UpdateRole(ctx context.Context, req *pbs.UpdateRoleRequest) (*p
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.UpdateAnnotations(ctx, s.annotationRepoFn, resource.Role, req.GetId(), req.GetItem().GetAnnotations(), req.GetUpdateMask().GetPaths()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.UpdateRoleResponse{Item: u}, nil
 }
@@ -148,6 +173,9 @@ func (s Service) AddRolePrincipals(ctx context.Context, req *pbs.AddRolePrincipa
 	if err != nil {
 		return nil, err
 	}
+	if r.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	r.Scope = authResults.Scope
 	return &pbs.AddRolePrincipalsResponse{Item: r}, nil
 }
@@ -165,6 +193,9 @@ func (s Service) SetRolePrincipals(ctx context.Context, req *pbs.SetRolePrincipa
 	if err != nil {
 		return nil, err
 	}
+	if r.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	r.Scope = authResults.Scope
 	return &pbs.SetRolePrincipalsResponse{Item: r}, nil
 }
@@ -182,6 +213,9 @@ func (s Service) RemoveRolePrincipals(ctx context.Context, req *pbs.RemoveRolePr
 	if err != nil {
 		return nil, err
 	}
+	if r.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	r.Scope = authResults.Scope
 	return &pbs.RemoveRolePrincipalsResponse{Item: r}, nil
 }
@@ -199,6 +233,9 @@ func (s Service) AddRoleGrants(ctx context.Context, req *pbs.AddRoleGrantsReques
 	if err != nil {
 		return nil, err
 	}
+	if r.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	r.Scope = authResults.Scope
 	return &pbs.AddRoleGrantsResponse{Item: r}, nil
 }
@@ -216,6 +253,9 @@ func (s Service) SetRoleGrants(ctx context.Context, req *pbs.SetRoleGrantsReques
 	if err != nil {
 		return nil, err
 	}
+	if r.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	r.Scope = authResults.Scope
 	return &pbs.SetRoleGrantsResponse{Item: r}, nil
 }
@@ -233,6 +273,9 @@ func (s Service) RemoveRoleGrants(ctx context.Context, req *pbs.RemoveRoleGrants
 	if err != nil {
 		return nil, err
 	}
+	if r.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	r.Scope = authResults.Scope
 	return &pbs.RemoveRoleGrantsResponse{Item: r}, nil
 }
@@ -304,7 +347,18 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, id string, mask []st
 	u.PublicId = id
 	dbMask := maskManager.Translate(mask)
 	if len(dbMask) == 0 {
-		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		if len(handlers.AnnotationsMask(mask)) == 0 {
+			return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		}
+		// Only annotations are updated, which are not stored with the role
+		out, err := s.getFromRepo(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if out.GetVersion() != item.GetVersion() {
+			return nil, handlers.NotFoundErrorf("Role %q doesn't exist or incorrect version provided.", id)
+		}
+		return out, nil
 	}
 	repo, err := s.repoFn()
 	if err != nil {
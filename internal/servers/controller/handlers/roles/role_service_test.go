@@ -15,7 +15,9 @@ import (
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/iam/store"
 	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/roles"
 	"github.com/hashicorp/boundary/internal/types/scope"
@@ -1821,3 +1823,36 @@ func TestRemoveGrants(t *testing.T) {
 		})
 	}
 }
+
+// mockIamRepo serves the roles it holds, panicking on the methods of the
+// repository it doesn't implement.
+type mockIamRepo struct {
+	common.IamRepo
+	roles map[string]*iam.Role
+}
+
+func (m *mockIamRepo) LookupRole(_ context.Context, id string, _ ...iam.Option) (*iam.Role, []iam.PrincipalRole, []*iam.RoleGrant, error) {
+	return m.roles[id], nil, nil, nil
+}
+
+func TestNewServiceWithRepos(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	repo := &mockIamRepo{roles: map[string]*iam.Role{
+		"r_1234567890": {Role: &store.Role{PublicId: "r_1234567890", ScopeId: "o_1234567890", Name: "readers"}},
+	}}
+	repoFn := func() (common.IamRepo, error) { return repo, nil }
+
+	_, err := roles.NewServiceWithRepos(nil)
+	assert.Error(err)
+	s, err := roles.NewServiceWithRepos(repoFn)
+	require.NoError(err)
+
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId("o_1234567890"))
+	got, err := s.GetRole(ctx, &pbs.GetRoleRequest{Id: "r_1234567890"})
+	require.NoError(err)
+	assert.Equal("r_1234567890", got.GetItem().GetId())
+	assert.Equal("readers", got.GetItem().GetName().GetValue())
+
+	_, err = s.GetRole(ctx, &pbs.GetRoleRequest{Id: "r_0987654321"})
+	assert.True(errors.Is(err, handlers.NotFoundError()))
+}
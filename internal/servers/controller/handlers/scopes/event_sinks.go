@@ -0,0 +1,138 @@
+package scopes
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/eventsink"
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListScopeEventSinks returns the event sinks of the org.
+func (s Service) ListScopeEventSinks(ctx context.Context, req *pbs.ListScopeEventSinksRequest) (*pbs.ListScopeEventSinksResponse, error) {
+	id := req.GetId()
+	if err := validateEventSinkScope(id); err != nil {
+		return nil, err
+	}
+	if s.eventSinkRepoFn == nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Unimplemented, "Event sinks are not supported.")
+	}
+	authResults := s.authResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.eventSinkRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	sinks, err := repo.ListSinks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pbs.ListScopeEventSinksResponse{Items: make([]*pb.EventSink, 0, len(sinks))}
+	for _, es := range sinks {
+		resp.Items = append(resp.Items, toEventSinkProto(es))
+	}
+	return resp, nil
+}
+
+// AddScopeEventSink adds an event sink to the org delivering to the url,
+// signed with the secret, with the values of the redacted fields of the
+// payloads replaced.
+func (s Service) AddScopeEventSink(ctx context.Context, req *pbs.AddScopeEventSinkRequest) (*pbs.AddScopeEventSinkResponse, error) {
+	id, url, secret, redactFields := req.GetId(), req.GetUrl(), req.GetSecret(), req.GetRedactFields()
+	if err := validateEventSinkScope(id); err != nil {
+		return nil, err
+	}
+	badFields := map[string]string{}
+	if err := eventsink.ValidateUrl(url); err != nil {
+		badFields["url"] = "Must be an absolute http or https URL."
+	}
+	if secret == "" {
+		badFields["secret"] = "This is a required field."
+	}
+	for _, f := range redactFields {
+		if strings.TrimSpace(f) == "" {
+			badFields["redact_fields"] = "Must not contain empty fields."
+		}
+	}
+	if len(badFields) > 0 {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	if s.eventSinkRepoFn == nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Unimplemented, "Event sinks are not supported.")
+	}
+	authResults := s.authResult(ctx, id, action.Update)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.eventSinkRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	es, err := repo.CreateSink(ctx, &eventsink.Sink{
+		ScopeId:      id,
+		Url:          url,
+		Secret:       secret,
+		RedactFields: redactFields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.AddScopeEventSinkResponse{Item: toEventSinkProto(es)}, nil
+}
+
+// RemoveScopeEventSink deletes the event sink of the org.
+func (s Service) RemoveScopeEventSink(ctx context.Context, req *pbs.RemoveScopeEventSinkRequest) (*pbs.RemoveScopeEventSinkResponse, error) {
+	id, sinkId := req.GetId(), req.GetSinkId()
+	if err := validateEventSinkScope(id); err != nil {
+		return nil, err
+	}
+	if !handlers.ValidId(eventsink.SinkPrefix, sinkId) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"sink_id": "Must be a valid event sink id."})
+	}
+	if s.eventSinkRepoFn == nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Unimplemented, "Event sinks are not supported.")
+	}
+	authResults := s.authResult(ctx, id, action.Update)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.eventSinkRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	n, err := repo.DeleteSink(ctx, id, sinkId)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, handlers.NotFoundErrorf("Event sink %q not found.", sinkId)
+	}
+	return &pbs.RemoveScopeEventSinkResponse{}, nil
+}
+
+func toEventSinkProto(es *eventsink.Sink) *pb.EventSink {
+	return &pb.EventSink{
+		Id:           es.PublicId,
+		ScopeId:      es.ScopeId,
+		Url:          es.Url,
+		RedactFields: es.RedactFields,
+		CreatedTime:  timestamppb.New(es.CreateTime),
+	}
+}
+
+// validateEventSinkScope returns an error if the id is not of an org, the
+// only scopes with event sinks.
+func validateEventSinkScope(id string) error {
+	if !handlers.ValidId(scope.Org.Prefix(), id) {
+		return handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Must be a valid org scope id."})
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package scopes
+
+import (
+	"context"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// GetScopeInactivityPolicy returns the inactivity policy of the scope.
+func (s Service) GetScopeInactivityPolicy(ctx context.Context, req *pbs.GetScopeInactivityPolicyRequest) (*pbs.GetScopeInactivityPolicyResponse, error) {
+	id := req.GetId()
+	if err := validateInactivityPolicyScope(id); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	p, err := s.inactivityPolicy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetScopeInactivityPolicyResponse{Item: p}, nil
+}
+
+// SetScopeInactivityPolicy sets the inactivity policy of the scope. Setting
+// max_inactive_days to 0 removes it.
+func (s Service) SetScopeInactivityPolicy(ctx context.Context, req *pbs.SetScopeInactivityPolicyRequest) (*pbs.SetScopeInactivityPolicyResponse, error) {
+	id, maxInactiveDays, disableInactive := req.GetId(), req.GetMaxInactiveDays(), req.GetDisableInactive()
+	if err := validateInactivityPolicyScope(id); err != nil {
+		return nil, err
+	}
+	if maxInactiveDays == 0 && disableInactive {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"disable_inactive": "Requires max_inactive_days to be set."})
+	}
+	authResults := s.authResult(ctx, id, action.Update)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.SetInactivityPolicy(ctx, id, maxInactiveDays, disableInactive); err != nil {
+		return nil, err
+	}
+	p, err := s.inactivityPolicy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.SetScopeInactivityPolicyResponse{Item: p}, nil
+}
+
+func (s Service) inactivityPolicy(ctx context.Context, id string) (*pb.InactivityPolicy, error) {
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	p, err := repo.LookupInactivityPolicy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.InactivityPolicy{
+		ScopeId:         p.ScopeId,
+		MaxInactiveDays: p.MaxInactiveDays,
+		DisableInactive: p.DisableInactive,
+	}, nil
+}
+
+// validateInactivityPolicyScope returns an error if the id is not of a scope
+// which can have users.
+func validateInactivityPolicyScope(id string) error {
+	if id != scope.Global.String() && !handlers.ValidId(scope.Org.Prefix(), id) {
+		return handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Must be 'global' or a valid org scope id."})
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package scopes
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// GetScopeProjectTemplate returns the project template of the org. An org
+// without a template returns a template with no roles and no target settings.
+func (s Service) GetScopeProjectTemplate(ctx context.Context, req *pbs.GetScopeProjectTemplateRequest) (*pbs.GetScopeProjectTemplateResponse, error) {
+	id := req.GetId()
+	if err := validateProjectTemplateScope(id); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	t, err := s.projectTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetScopeProjectTemplateResponse{Item: t}, nil
+}
+
+// SetScopeProjectTemplate sets the project template of the org, replacing its
+// current one. Projects already created in the org are not changed.
+func (s Service) SetScopeProjectTemplate(ctx context.Context, req *pbs.SetScopeProjectTemplateRequest) (*pbs.SetScopeProjectTemplateResponse, error) {
+	id, t := req.GetId(), req.GetItem()
+	if err := validateProjectTemplateScope(id); err != nil {
+		return nil, err
+	}
+	if err := validateProjectTemplate(t); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, id, action.Update)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	it := &iam.ProjectTemplate{
+		OrgId:                        id,
+		TargetDefaultPort:            t.GetTargetDefaultPort(),
+		TargetSessionMaxSeconds:      t.GetTargetSessionMaxSeconds(),
+		TargetSessionConnectionLimit: t.GetTargetSessionConnectionLimit(),
+	}
+	for _, r := range t.GetRoles() {
+		it.Roles = append(it.Roles, &iam.ProjectTemplateRole{
+			Name:        r.GetName(),
+			Description: r.GetDescription(),
+			Grants:      r.GetGrants(),
+		})
+	}
+	if err := repo.SetProjectTemplate(ctx, id, it); err != nil {
+		return nil, err
+	}
+	out, err := s.projectTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.SetScopeProjectTemplateResponse{Item: out}, nil
+}
+
+// DeleteScopeProjectTemplate removes the project template of the org.
+func (s Service) DeleteScopeProjectTemplate(ctx context.Context, req *pbs.DeleteScopeProjectTemplateRequest) (*pbs.DeleteScopeProjectTemplateResponse, error) {
+	id := req.GetId()
+	if err := validateProjectTemplateScope(id); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, id, action.Update)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.SetProjectTemplate(ctx, id, nil); err != nil {
+		return nil, err
+	}
+	t, err := s.projectTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.DeleteScopeProjectTemplateResponse{Item: t}, nil
+}
+
+func (s Service) projectTemplate(ctx context.Context, id string) (*pb.ProjectTemplate, error) {
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	t, err := repo.LookupProjectTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pt := &pb.ProjectTemplate{
+		ScopeId: id,
+		Roles:   []*pb.ProjectTemplateRole{},
+	}
+	if t == nil {
+		return pt, nil
+	}
+	pt.TargetDefaultPort = t.TargetDefaultPort
+	pt.TargetSessionMaxSeconds = t.TargetSessionMaxSeconds
+	pt.TargetSessionConnectionLimit = t.TargetSessionConnectionLimit
+	for _, r := range t.Roles {
+		grants := r.Grants
+		if grants == nil {
+			grants = []string{}
+		}
+		pt.Roles = append(pt.Roles, &pb.ProjectTemplateRole{
+			Name:        r.Name,
+			Description: r.Description,
+			Grants:      grants,
+		})
+	}
+	return pt, nil
+}
+
+// validateProjectTemplateScope returns an error if the id is not of an org.
+func validateProjectTemplateScope(id string) error {
+	if !handlers.ValidId(scope.Org.Prefix(), id) {
+		return handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Must be a valid org scope id."})
+	}
+	return nil
+}
+
+func validateProjectTemplate(t *pb.ProjectTemplate) error {
+	badFields := map[string]string{}
+	if t.GetTargetSessionConnectionLimit() < -1 {
+		badFields["target_session_connection_limit"] = "Must be -1, 0 or greater."
+	}
+	names := map[string]bool{}
+	for i, r := range t.GetRoles() {
+		field := fmt.Sprintf("roles[%d]", i)
+		switch {
+		case r.GetName() == "":
+			badFields[field+".name"] = "This field is required."
+			continue
+		case names[r.GetName()]:
+			badFields[field+".name"] = "Must be unique within the template."
+		}
+		names[r.GetName()] = true
+		grants := map[string]bool{}
+		for j, g := range r.GetGrants() {
+			if grants[g] {
+				badFields[fmt.Sprintf("%s.grants[%d]", field, j)] = "Duplicate grant."
+				continue
+			}
+			grants[g] = true
+			if _, err := perms.Parse("p_abcd1234", g); err != nil {
+				badFields[fmt.Sprintf("%s.grants[%d]", field, j)] = fmt.Sprintf("Improperly formatted grant: %v.", err)
+			}
+		}
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	return nil
+}
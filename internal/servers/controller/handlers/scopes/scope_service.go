@@ -36,15 +36,31 @@ func init() {
 type Service struct {
 	pbs.UnimplementedScopeServiceServer
 
-	repoFn common.IamRepoFactory
+	repoFn              common.IamRepoProvider
+	responseCache       *handlers.ResponseCache
+	securityEventRepoFn common.SecurityEventRepoFactory
+	usageRepoFn         common.UsageRepoFactory
+	eventSinkRepoFn     common.EventSinkRepoFactory
+	annotationRepoFn    common.AnnotationRepoFactory
 }
 
-// NewService returns a project service which handles project related requests to boundary.
-func NewService(repo common.IamRepoFactory) (Service, error) {
+// NewService returns a project service which handles project related requests
+// to boundary. Supported options: handlers.WithResponseCache,
+// handlers.WithSecurityEvents, handlers.WithUsage, handlers.WithEventSinks and
+// handlers.WithAnnotations.
+func NewService(repo common.IamRepoFactory, opt ...handlers.Option) (Service, error) {
+	return NewServiceWithRepos(repo.Provider(), opt...)
+}
+
+// NewServiceWithRepos returns a scope service using the iam repositories of the
+// provider, which need not be the database backed ones. It takes the same
+// options as NewService.
+func NewServiceWithRepos(repo common.IamRepoProvider, opt ...handlers.Option) (Service, error) {
 	if repo == nil {
 		return Service{}, fmt.Errorf("nil iam repository provided")
 	}
-	return Service{repoFn: repo}, nil
+	opts := handlers.GetOpts(opt...)
+	return Service{repoFn: repo, responseCache: opts.WithResponseCache, securityEventRepoFn: opts.WithSecurityEvents, usageRepoFn: opts.WithUsage, eventSinkRepoFn: opts.WithEventSinks, annotationRepoFn: opts.WithAnnotations}, nil
 }
 
 var _ pbs.ScopeServiceServer = Service{}
@@ -61,16 +77,32 @@ func (s Service) ListScopes(ctx context.Context, req *pbs.ListScopesRequest) (*p
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
+	cacheKey := handlers.ResponseCacheKey("ListScopes", authResults.Scope.GetId(), authResults.GrantsHash)
+	if cached, ok := s.responseCache.Get(resource.Scope, cacheKey); ok {
+		return cached.(*pbs.ListScopesResponse), nil
+	}
+	generation := s.responseCache.Generation(resource.Scope)
 	pl, err := s.listFromRepo(ctx, authResults.Scope.GetId())
 	if err != nil {
 		return nil, err
 	}
 
+	ids := make([]string, 0, len(pl))
+	for _, item := range pl {
+		ids = append(ids, item.GetId())
+	}
+	annotations, err := handlers.ListAnnotations(ctx, s.annotationRepoFn, ids)
+	if err != nil {
+		return nil, err
+	}
 	for _, item := range pl {
 		item.Scope = authResults.Scope
+		item.Annotations = annotations[item.GetId()]
 	}
 
-	return &pbs.ListScopesResponse{Items: pl}, nil
+	resp := &pbs.ListScopesResponse{Items: pl}
+	s.responseCache.Set(resource.Scope, generation, cacheKey, resp)
+	return resp, nil
 }
 
 // GetScopes implements the interface pbs.ScopeServiceServer.
@@ -86,6 +118,9 @@ func (s Service) GetScope(ctx context.Context, req *pbs.GetScopeRequest) (*pbs.G
 	if err != nil {
 		return nil, err
 	}
+	if p.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	p.Scope = authResults.Scope
 	return &pbs.GetScopeResponse{Item: p}, nil
 }
@@ -120,6 +155,9 @@ func (s Service) UpdateScope(ctx context.Context, req *pbs.UpdateScopeRequest) (
 	if err != nil {
 		return nil, err
 	}
+	if p.Annotations, err = handlers.UpdateAnnotations(ctx, s.annotationRepoFn, resource.Scope, req.GetId(), req.GetItem().GetAnnotations(), req.GetUpdateMask().GetPaths()); err != nil {
+		return nil, err
+	}
 	p.Scope = authResults.Scope
 	return &pbs.UpdateScopeResponse{Item: p}, nil
 }
@@ -217,7 +255,18 @@ func (s Service) updateInRepo(ctx context.Context, parentScope *scopes.ScopeInfo
 	iamScope.PublicId = scopeId
 	dbMask := maskManager.Translate(mask)
 	if len(dbMask) == 0 {
-		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		if len(handlers.AnnotationsMask(mask)) == 0 {
+			return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
+		}
+		// Only annotations are updated, which are not stored with the scope
+		out, err := s.getFromRepo(ctx, scopeId)
+		if err != nil {
+			return nil, err
+		}
+		if out.GetVersion() != item.GetVersion() {
+			return nil, handlers.NotFoundErrorf("Scope %q doesn't exist or incorrect version provided.", scopeId)
+		}
+		return out, nil
 	}
 	repo, err := s.repoFn()
 	if err != nil {
@@ -15,6 +15,8 @@ import (
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/iam/store"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/scopes"
 	"github.com/hashicorp/boundary/internal/types/scope"
@@ -1014,3 +1016,92 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectTemplate(t *testing.T) {
+	org, proj, repoFn := createDefaultScopesAndRepo(t)
+	s, err := scopes.NewService(repoFn)
+	require.NoError(t, err)
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(org.GetPublicId()))
+
+	t.Run("get-set-delete", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		empty := &pb.ProjectTemplate{ScopeId: org.GetPublicId(), Roles: []*pb.ProjectTemplateRole{}}
+		got, err := s.GetScopeProjectTemplate(ctx, &pbs.GetScopeProjectTemplateRequest{Id: org.GetPublicId()})
+		require.NoError(err)
+		assert.Empty(cmp.Diff(empty, got.GetItem(), protocmp.Transform()))
+
+		want := &pb.ProjectTemplate{
+			ScopeId:                 org.GetPublicId(),
+			TargetSessionMaxSeconds: 3600,
+			Roles: []*pb.ProjectTemplateRole{
+				{Name: "Operators", Grants: []string{"id=*;type=target;actions=authorize-session"}},
+				{Name: "Viewers", Grants: []string{}},
+			},
+		}
+		set, err := s.SetScopeProjectTemplate(ctx, &pbs.SetScopeProjectTemplateRequest{Id: org.GetPublicId(), Item: want})
+		require.NoError(err)
+		assert.Empty(cmp.Diff(want, set.GetItem(), protocmp.Transform()))
+		got, err = s.GetScopeProjectTemplate(ctx, &pbs.GetScopeProjectTemplateRequest{Id: org.GetPublicId()})
+		require.NoError(err)
+		assert.Empty(cmp.Diff(want, got.GetItem(), protocmp.Transform()))
+
+		del, err := s.DeleteScopeProjectTemplate(ctx, &pbs.DeleteScopeProjectTemplateRequest{Id: org.GetPublicId()})
+		require.NoError(err)
+		assert.Empty(cmp.Diff(empty, del.GetItem(), protocmp.Transform()))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name string
+			id   string
+			t    *pb.ProjectTemplate
+		}{
+			{name: "global", id: scope.Global.String(), t: &pb.ProjectTemplate{}},
+			{name: "project", id: proj.GetPublicId(), t: &pb.ProjectTemplate{}},
+			{name: "connection-limit", id: org.GetPublicId(), t: &pb.ProjectTemplate{TargetSessionConnectionLimit: -2}},
+			{name: "missing-role-name", id: org.GetPublicId(), t: &pb.ProjectTemplate{Roles: []*pb.ProjectTemplateRole{{}}}},
+			{name: "duplicate-role", id: org.GetPublicId(), t: &pb.ProjectTemplate{Roles: []*pb.ProjectTemplateRole{{Name: "a"}, {Name: "a"}}}},
+			{name: "bad-grant", id: org.GetPublicId(), t: &pb.ProjectTemplate{Roles: []*pb.ProjectTemplateRole{{Name: "a", Grants: []string{"id=*"}}}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := s.SetScopeProjectTemplate(ctx, &pbs.SetScopeProjectTemplateRequest{Id: tt.id, Item: tt.t})
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), err.Error())
+			})
+		}
+	})
+}
+
+// mockIamRepo serves the scopes it holds, panicking on the methods of the
+// repository it doesn't implement.
+type mockIamRepo struct {
+	common.IamRepo
+	scopes map[string]*iam.Scope
+}
+
+func (m *mockIamRepo) LookupScope(_ context.Context, id string, _ ...iam.Option) (*iam.Scope, error) {
+	return m.scopes[id], nil
+}
+
+func TestNewServiceWithRepos(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	repo := &mockIamRepo{scopes: map[string]*iam.Scope{
+		"o_1234567890": {Scope: &store.Scope{PublicId: "o_1234567890", ParentId: scope.Global.String(), Type: scope.Org.String(), Name: "engineering"}},
+	}}
+	repoFn := func() (common.IamRepo, error) { return repo, nil }
+
+	_, err := scopes.NewServiceWithRepos(nil)
+	assert.Error(err)
+	s, err := scopes.NewServiceWithRepos(repoFn)
+	require.NoError(err)
+
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(scope.Global.String()))
+	got, err := s.GetScope(ctx, &pbs.GetScopeRequest{Id: "o_1234567890"})
+	require.NoError(err)
+	assert.Equal("o_1234567890", got.GetItem().GetId())
+	assert.Equal("engineering", got.GetItem().GetName().GetValue())
+
+	_, err = s.GetScope(ctx, &pbs.GetScopeRequest{Id: "o_0987654321"})
+	assert.True(errors.Is(err, handlers.NotFoundError()))
+}
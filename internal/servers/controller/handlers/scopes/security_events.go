@@ -0,0 +1,105 @@
+package scopes
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/securityevent"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultSecurityEventsRange is how far back the counts of security events
+// go when the request has no start.
+const defaultSecurityEventsRange = 24 * time.Hour
+
+// GetScopeSecurityEvents returns the counts of the security events of the
+// scope from start until end in buckets of the bucket length, "hour" or
+// "day". If recursive is set the global scope includes the events of the orgs
+// the caller may read. Time buckets without events are omitted.
+func (s Service) GetScopeSecurityEvents(ctx context.Context, req *pbs.GetScopeSecurityEventsRequest) (*pbs.GetScopeSecurityEventsResponse, error) {
+	id, recursive := req.GetId(), req.GetRecursive()
+	end := time.Now()
+	if req.GetEnd() != nil {
+		end = req.GetEnd().AsTime()
+	}
+	start := end.Add(-defaultSecurityEventsRange)
+	if req.GetStart() != nil {
+		start = req.GetStart().AsTime()
+	}
+	bucket := req.GetBucket()
+	if bucket == "" {
+		bucket = string(securityevent.BucketHour)
+	}
+	if id != scope.Global.String() && !handlers.ValidId(scope.Org.Prefix(), id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Must be 'global' or a valid org scope id."})
+	}
+	badFields := map[string]string{}
+	switch securityevent.Bucket(bucket) {
+	case securityevent.BucketHour, securityevent.BucketDay:
+	default:
+		badFields["bucket"] = "Must be 'hour' or 'day'."
+	}
+	if !end.After(start) {
+		badFields["end"] = "Must be after start."
+	}
+	if len(badFields) > 0 {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	if s.securityEventRepoFn == nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Unimplemented, "Security events are not recorded.")
+	}
+	authResults := s.authResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+
+	scopeIds := []string{id}
+	if recursive && id == scope.Global.String() {
+		repo, err := s.repoFn()
+		if err != nil {
+			return nil, err
+		}
+		orgs, err := repo.ListOrgs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range orgs {
+			if s.authResult(ctx, o.GetPublicId(), action.Read).Error == nil {
+				scopeIds = append(scopeIds, o.GetPublicId())
+			}
+		}
+	}
+
+	repo, err := s.securityEventRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := repo.ListCounts(ctx, scopeIds, start, end, securityevent.Bucket(bucket))
+	if err != nil {
+		return nil, err
+	}
+	out := &pbs.GetScopeSecurityEventsResponse{
+		ScopeId:   id,
+		Recursive: recursive,
+		Start:     timestamppb.New(start.Truncate(time.Hour)),
+		End:       timestamppb.New(end),
+		Bucket:    bucket,
+		Items:     make([]*pb.SecurityEventCount, 0, len(counts)),
+	}
+	for _, c := range counts {
+		out.Items = append(out.Items, &pb.SecurityEventCount{
+			BucketTime:   timestamppb.New(c.BucketTime),
+			ScopeId:      c.ScopeId,
+			AuthMethodId: c.AuthMethodId,
+			Kind:         string(c.Kind),
+			Count:        c.Count,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,187 @@
+package scopes
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// usageDateFormat is the format of the days of usage.
+const usageDateFormat = "2006-01-02"
+
+// GetScopeUsage returns the daily usage of the scope from the UTC day start
+// through end, with the totals of each scope over these days. If recursive
+// is set the global scope includes the usage of the orgs and projects, and an
+// org that of the projects, the caller may read. API calls are counted in the
+// scope of the resource they are about, and session minutes in the project
+// of the session. Days without usage are omitted.
+func (s Service) GetScopeUsage(ctx context.Context, req *pbs.GetScopeUsageRequest) (*pbs.GetScopeUsageResponse, error) {
+	return s.usage(ctx, req.GetId(), req.GetStart(), req.GetEnd(), req.GetRecursive())
+}
+
+// ExportScopeUsage returns the daily usage items of GetScopeUsage as CSV
+// with a header row. The totals are left out, since they are the sums of the
+// rows of each scope.
+func (s Service) ExportScopeUsage(ctx context.Context, req *pbs.ExportScopeUsageRequest) (*httpbody.HttpBody, error) {
+	u, err := s.usage(ctx, req.GetId(), req.GetStart(), req.GetEnd(), req.GetRecursive())
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := writeUsageCsv(&buf, u.GetItems()); err != nil {
+		return nil, err
+	}
+	// This fails when not called through a gRPC server, such as in tests,
+	// and only names the downloaded file.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(handlers.ContentDispositionMetadataKey,
+		fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("usage-%s-%s-%s.csv", u.GetScopeId(), u.GetStart(), u.GetEnd()))))
+	return &httpbody.HttpBody{
+		ContentType: "text/csv",
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+func (s Service) usage(ctx context.Context, id, startDay, endDay string, recursive bool) (*pbs.GetScopeUsageResponse, error) {
+	if id != scope.Global.String() && !handlers.ValidId(scope.Org.Prefix(), id) && !handlers.ValidId(scope.Project.Prefix(), id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Must be 'global' or a valid org or project scope id."})
+	}
+	badFields := map[string]string{}
+	parseDay := func(name, v string, def time.Time) time.Time {
+		if v == "" {
+			return def
+		}
+		t, err := time.Parse(usageDateFormat, v)
+		if err != nil {
+			badFields[name] = "Must be a day formatted as YYYY-MM-DD."
+		}
+		return t
+	}
+	now := time.Now().UTC()
+	end := parseDay("end", endDay, now)
+	start := parseDay("start", startDay, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if len(badFields) == 0 && end.Before(start) {
+		badFields["end"] = "Must not be before start."
+	}
+	if len(badFields) > 0 {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	if s.usageRepoFn == nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Unimplemented, "Usage is not recorded.")
+	}
+	authResults := s.authResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+
+	scopeIds := []string{id}
+	if recursive && !handlers.ValidId(scope.Project.Prefix(), id) {
+		repo, err := s.repoFn()
+		if err != nil {
+			return nil, err
+		}
+		orgIds := []string{id}
+		if id == scope.Global.String() {
+			orgs, err := repo.ListOrgs(ctx)
+			if err != nil {
+				return nil, err
+			}
+			orgIds = orgIds[:0]
+			for _, o := range orgs {
+				orgIds = append(orgIds, o.GetPublicId())
+				if s.authResult(ctx, o.GetPublicId(), action.Read).Error == nil {
+					scopeIds = append(scopeIds, o.GetPublicId())
+				}
+			}
+		}
+		for _, orgId := range orgIds {
+			projects, err := repo.ListProjects(ctx, orgId)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range projects {
+				if s.authResult(ctx, p.GetPublicId(), action.Read).Error == nil {
+					scopeIds = append(scopeIds, p.GetPublicId())
+				}
+			}
+		}
+	}
+
+	repo, err := s.usageRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	usages, err := repo.ListUsage(ctx, scopeIds, start, end)
+	if err != nil {
+		return nil, err
+	}
+	out := &pbs.GetScopeUsageResponse{
+		ScopeId:   id,
+		Recursive: recursive,
+		Start:     start.Format(usageDateFormat),
+		End:       end.Format(usageDateFormat),
+		Items:     make([]*pb.UsageItem, 0, len(usages)),
+	}
+	totalCalls := make(map[string]uint64)
+	totalSeconds := make(map[string]uint64)
+	for _, u := range usages {
+		out.Items = append(out.Items, &pb.UsageItem{
+			Date:           u.Date.Format(usageDateFormat),
+			ScopeId:        u.ScopeId,
+			ApiCalls:       u.ApiCalls,
+			SessionMinutes: sessionMinutes(u.SessionSeconds),
+		})
+		totalCalls[u.ScopeId] += u.ApiCalls
+		totalSeconds[u.ScopeId] += u.SessionSeconds
+	}
+	out.Totals = make([]*pb.UsageItem, 0, len(totalCalls))
+	for scopeId, calls := range totalCalls {
+		out.Totals = append(out.Totals, &pb.UsageItem{
+			ScopeId:        scopeId,
+			ApiCalls:       calls,
+			SessionMinutes: sessionMinutes(totalSeconds[scopeId]),
+		})
+	}
+	sort.Slice(out.Totals, func(i, j int) bool { return out.Totals[i].ScopeId < out.Totals[j].ScopeId })
+	return out, nil
+}
+
+// writeUsageCsv writes the daily usage items as CSV with a header row.
+func writeUsageCsv(w io.Writer, items []*pb.UsageItem) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"date", "scope_id", "api_calls", "session_minutes"}); err != nil {
+		return err
+	}
+	for _, i := range items {
+		if err := cw.Write([]string{
+			i.GetDate(),
+			i.GetScopeId(),
+			strconv.FormatUint(i.GetApiCalls(), 10),
+			strconv.FormatFloat(i.GetSessionMinutes(), 'f', 2, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// sessionMinutes returns the seconds in minutes, rounded to the hundredth.
+func sessionMinutes(seconds uint64) float64 {
+	return math.Round(float64(seconds)/60*100) / 100
+}
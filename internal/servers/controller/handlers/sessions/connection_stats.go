@@ -0,0 +1,44 @@
+package sessions
+
+import (
+	"context"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/sessions"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// ListSessionConnectionStats returns the connection stats of the sessions in
+// the project scope. It requires permission to list the sessions of the scope.
+func (s Service) ListSessionConnectionStats(ctx context.Context, req *pbs.ListSessionConnectionStatsRequest) (*pbs.ListSessionConnectionStatsResponse, error) {
+	if !handlers.ValidId(scope.Project.Prefix(), req.GetScopeId()) {
+		return nil, handlers.InvalidArgumentErrorf("Improperly formatted identifier.", map[string]string{"scope_id": "This field is required to have a properly formatted project scope id."})
+	}
+	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := repo.ListConnectionStats(ctx, authResults.Scope.GetId())
+	if err != nil {
+		return nil, err
+	}
+	out := &pbs.ListSessionConnectionStatsResponse{
+		Items: make([]*pb.ConnectionStats, 0, len(stats)),
+	}
+	for _, cs := range stats {
+		out.Items = append(out.Items, &pb.ConnectionStats{
+			SessionId:             cs.SessionId,
+			ActiveConnectionCount: cs.ActiveConnectionCount,
+			TotalConnectionCount:  cs.TotalConnectionCount,
+			BytesUp:               cs.BytesUp,
+			BytesDown:             cs.BytesDown,
+		})
+	}
+	return out, nil
+}
@@ -22,12 +22,18 @@ import (
 type Service struct {
 	pbs.UnimplementedSessionServiceServer
 
-	repoFn    common.SessionRepoFactory
-	iamRepoFn common.IamRepoFactory
+	repoFn    common.SessionRepoProvider
+	iamRepoFn common.IamRepoProvider
 }
 
 // NewService returns a session service which handles session related requests to boundary.
 func NewService(repoFn common.SessionRepoFactory, iamRepoFn common.IamRepoFactory) (Service, error) {
+	return NewServiceWithRepos(repoFn.Provider(), iamRepoFn.Provider())
+}
+
+// NewServiceWithRepos returns a session service using the repositories of
+// the providers, which need not be the database backed ones.
+func NewServiceWithRepos(repoFn common.SessionRepoProvider, iamRepoFn common.IamRepoProvider) (Service, error) {
 	if repoFn == nil {
 		return Service{}, fmt.Errorf("nil session repository provided")
 	}
@@ -39,7 +45,15 @@ func NewService(repoFn common.SessionRepoFactory, iamRepoFn common.IamRepoFactor
 
 var _ pbs.SessionServiceServer = Service{}
 
-// GetSessions implements the interface pbs.SessionServiceServer.
+// selfActions are the self variants of the actions on sessions, with which
+// the caller's own sessions are verified.
+var selfActions = map[action.Type]action.Type{
+	action.Read:   action.ReadSelf,
+	action.Cancel: action.CancelSelf,
+}
+
+// GetSessions implements the interface pbs.SessionServiceServer. Callers may
+// read their own sessions without a grant for the read action.
 func (s Service) GetSession(ctx context.Context, req *pbs.GetSessionRequest) (*pbs.GetSessionResponse, error) {
 	if err := validateGetRequest(req); err != nil {
 		return nil, err
@@ -56,16 +70,26 @@ func (s Service) GetSession(ctx context.Context, req *pbs.GetSessionRequest) (*p
 	return &pbs.GetSessionResponse{Item: ses}, nil
 }
 
-// ListSessions implements the interface pbs.SessionServiceServer.
+// ListSessions implements the interface pbs.SessionServiceServer. Callers
+// without a grant for the list action on the sessions of the scope get their
+// own sessions only.
 func (s Service) ListSessions(ctx context.Context, req *pbs.ListSessionsRequest) (*pbs.ListSessionsResponse, error) {
 	if err := validateListRequest(req); err != nil {
 		return nil, err
 	}
+	var userId string
 	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
 	if authResults.Error != nil {
-		return nil, authResults.Error
+		var err error
+		if userId, _, err = auth.LookupSelf(ctx); err != nil {
+			return nil, authResults.Error
+		}
+		authResults = s.authResult(ctx, req.GetScopeId(), action.List, auth.WithSelf(true))
+		if authResults.Error != nil {
+			return nil, authResults.Error
+		}
 	}
-	seslist, err := s.listFromRepo(ctx, authResults.Scope.GetId())
+	seslist, err := s.listFromRepo(ctx, authResults.Scope.GetId(), userId)
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +99,8 @@ func (s Service) ListSessions(ctx context.Context, req *pbs.ListSessionsRequest)
 	return &pbs.ListSessionsResponse{Items: seslist}, nil
 }
 
-// CancelSession implements the interface pbs.SessionServiceServer.
+// CancelSession implements the interface pbs.SessionServiceServer. Callers
+// may cancel their own sessions without a grant for the cancel action.
 func (s Service) CancelSession(ctx context.Context, req *pbs.CancelSessionRequest) (*pbs.CancelSessionResponse, error) {
 	if err := validateCancelRequest(req); err != nil {
 		return nil, err
@@ -110,12 +135,18 @@ func (s Service) getFromRepo(ctx context.Context, id string) (*pb.Session, error
 	return toProto(sess), nil
 }
 
-func (s Service) listFromRepo(ctx context.Context, scopeId string) ([]*pb.Session, error) {
+// listFromRepo lists the sessions of the scope, only those of the user if
+// userId is not empty.
+func (s Service) listFromRepo(ctx context.Context, scopeId, userId string) ([]*pb.Session, error) {
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	seslist, err := repo.ListSessions(ctx, session.WithScopeId(scopeId))
+	opts := []session.Option{session.WithScopeId(scopeId)}
+	if userId != "" {
+		opts = append(opts, session.WithUserId(userId))
+	}
+	seslist, err := repo.ListSessions(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -138,7 +169,7 @@ func (s Service) cancelInRepo(ctx context.Context, id string, version uint32) (*
 	return toProto(out), nil
 }
 
-func (s Service) authResult(ctx context.Context, id string, a action.Type) auth.VerifyResults {
+func (s Service) authResult(ctx context.Context, id string, a action.Type, opt ...auth.Option) auth.VerifyResults {
 	res := auth.VerifyResults{}
 
 	var parentId string
@@ -177,10 +208,16 @@ func (s Service) authResult(ctx context.Context, id string, a action.Type) auth.
 		}
 		parentId = t.ScopeId
 		opts = append(opts, auth.WithId(id))
+		// The caller's own sessions are verified with the self variant of the
+		// action, which is allowed by the grants of either
+		if userId, _, err := auth.LookupSelf(ctx); err == nil && userId == t.UserId {
+			opts = append(opts, auth.WithAction(selfActions[a]), auth.WithSelf(true))
+		}
 	default:
 		res.Error = stderrors.New("unsupported action")
 		return res
 	}
+	opts = append(opts, opt...)
 	opts = append(opts, auth.WithScopeId(parentId))
 	return auth.Verify(ctx, opts...)
 }
@@ -1,6 +1,7 @@
 package sessions_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/hashicorp/boundary/internal/host/static"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/sessions"
 	"github.com/hashicorp/boundary/internal/session"
@@ -238,6 +240,44 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestListSessionConnectionStats(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	wrap := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrap)
+
+	iamRepo := iam.TestRepo(t, conn, wrap)
+
+	rw := db.New(conn)
+	sessRepo, err := session.NewRepository(rw, rw, kms)
+	require.NoError(err)
+
+	iamRepoFn := func() (*iam.Repository, error) {
+		return iamRepo, nil
+	}
+	sessRepoFn := func() (*session.Repository, error) {
+		return sessRepo, nil
+	}
+
+	sess := session.TestDefaultSession(t, conn, wrap, iamRepo)
+	session.TestConnection(t, conn, sess.GetPublicId(), "127.0.0.1", 22, "127.0.0.1", 2222)
+
+	s, err := sessions.NewService(sessRepoFn, iamRepoFn)
+	require.NoError(err, "Couldn't create new session service.")
+
+	_, err = s.ListSessionConnectionStats(auth.DisabledAuthTestContext(auth.WithScopeId(sess.ScopeId)), &pbs.ListSessionConnectionStatsRequest{ScopeId: "o_1234567890"})
+	require.Error(err)
+	assert.True(errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), "got error %v", err)
+
+	got, err := s.ListSessionConnectionStats(auth.DisabledAuthTestContext(auth.WithScopeId(sess.ScopeId)), &pbs.ListSessionConnectionStatsRequest{ScopeId: sess.ScopeId})
+	require.NoError(err)
+	assert.Empty(cmp.Diff(&pbs.ListSessionConnectionStatsResponse{Items: []*pb.ConnectionStats{{
+		SessionId:             sess.GetPublicId(),
+		ActiveConnectionCount: 1,
+		TotalConnectionCount:  1,
+	}}}, got, protocmp.Transform()))
+}
+
 func convertStates(in []*session.State) (string, []*pb.SessionState) {
 	out := make([]*pb.SessionState, 0, len(in))
 	for _, s := range in {
@@ -398,3 +438,38 @@ func TestCancel(t *testing.T) {
 		})
 	}
 }
+
+// mockSessionRepo serves the sessions it holds, panicking on the methods of
+// the repository it doesn't implement.
+type mockSessionRepo struct {
+	common.SessionRepo
+	sessions map[string]*session.Session
+}
+
+func (m *mockSessionRepo) LookupSession(_ context.Context, id string, _ ...session.Option) (*session.Session, *session.ConnectionAuthzSummary, error) {
+	return m.sessions[id], nil, nil
+}
+
+func TestNewServiceWithRepos(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	repo := &mockSessionRepo{sessions: map[string]*session.Session{
+		"s_1234567890": {PublicId: "s_1234567890", ScopeId: "p_1234567890", UserId: "u_1234567890", TargetId: "ttcp_1234567890"},
+	}}
+	repoFn := func() (common.SessionRepo, error) { return repo, nil }
+	iamRepoFn := func() (common.IamRepo, error) { return nil, errors.New("unexpected iam repository use") }
+
+	_, err := sessions.NewServiceWithRepos(nil, iamRepoFn)
+	assert.Error(err)
+	s, err := sessions.NewServiceWithRepos(repoFn, iamRepoFn)
+	require.NoError(err)
+
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId("p_1234567890"))
+	got, err := s.GetSession(ctx, &pbs.GetSessionRequest{Id: "s_1234567890"})
+	require.NoError(err)
+	assert.Equal("s_1234567890", got.GetItem().GetId())
+	assert.Equal("u_1234567890", got.GetItem().GetUserId())
+	assert.Equal("tcp", got.GetItem().GetType())
+
+	_, err = s.GetSession(ctx, &pbs.GetSessionRequest{Id: "s_0987654321"})
+	assert.True(errors.Is(err, handlers.NotFoundError()))
+}
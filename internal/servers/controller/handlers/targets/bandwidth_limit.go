@@ -0,0 +1,76 @@
+package targets
+
+import (
+	"context"
+	"math"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/action"
+)
+
+// GetTargetBandwidthLimit returns the bandwidth caps workers enforce on the
+// connections of the target, in bytes per second of both directions combined.
+// A limit of 0 is unlimited.
+func (s Service) GetTargetBandwidthLimit(ctx context.Context, req *pbs.GetTargetBandwidthLimitRequest) (*pbs.GetTargetBandwidthLimitResponse, error) {
+	if !handlers.ValidId(target.TcpTargetPrefix, req.GetId()) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	authResults := s.authResult(ctx, req.GetId(), action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	l, err := repo.LookupBandwidthLimit(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetTargetBandwidthLimitResponse{
+		Item: &pb.BandwidthLimit{
+			TargetId:                 req.GetId(),
+			ConnectionBytesPerSecond: l.ConnectionBytesPerSecond,
+			SessionBytesPerSecond:    l.SessionBytesPerSecond,
+		},
+	}, nil
+}
+
+// SetTargetBandwidthLimit sets the bandwidth limits of the target. They apply
+// to sessions looked up by workers afterwards.
+func (s Service) SetTargetBandwidthLimit(ctx context.Context, req *pbs.SetTargetBandwidthLimitRequest) (*pbs.SetTargetBandwidthLimitResponse, error) {
+	if !handlers.ValidId(target.TcpTargetPrefix, req.GetId()) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	badFields := map[string]string{}
+	if req.GetConnectionBytesPerSecond() > math.MaxInt64 {
+		badFields["connection_bytes_per_second"] = "Value is too large."
+	}
+	if req.GetSessionBytesPerSecond() > math.MaxInt64 {
+		badFields["session_bytes_per_second"] = "Value is too large."
+	}
+	if len(badFields) > 0 {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	authResults := s.authResult(ctx, req.GetId(), action.Update)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.SetBandwidthLimit(ctx, req.GetId(), req.GetConnectionBytesPerSecond(), req.GetSessionBytesPerSecond()); err != nil {
+		return nil, err
+	}
+	return &pbs.SetTargetBandwidthLimitResponse{
+		Item: &pb.BandwidthLimit{
+			TargetId:                 req.GetId(),
+			ConnectionBytesPerSecond: req.GetConnectionBytesPerSecond(),
+			SessionBytesPerSecond:    req.GetSessionBytesPerSecond(),
+		},
+	}, nil
+}
@@ -0,0 +1,60 @@
+package targets
+
+import (
+	"context"
+	"fmt"
+
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"google.golang.org/grpc/codes"
+)
+
+// CloneTarget creates a new target with the requested name in the scope of
+// the target with its configuration, host sets, connection authorization,
+// bandwidth limit and credential checkout policy. The description of the
+// target is copied unless one is requested. The caller must be allowed to
+// read the target and to create targets in its scope.
+func (s Service) CloneTarget(ctx context.Context, req *pbs.CloneTargetRequest) (*pbs.CloneTargetResponse, error) {
+	id, name, description := req.GetId(), req.GetName(), req.GetDescription()
+	badFields := map[string]string{}
+	if !handlers.ValidId(target.TcpTargetPrefix, id) {
+		badFields["id"] = "Improperly formatted identifier."
+	}
+	if name == "" {
+		badFields["name"] = "This field is required."
+	}
+	if len(badFields) > 0 {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	readResults := s.authResult(ctx, id, action.Read)
+	if readResults.Error != nil {
+		return nil, readResults.Error
+	}
+	authResults := s.authResult(ctx, readResults.Scope.GetId(), action.Create)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	var opts []target.Option
+	if description != "" {
+		opts = append(opts, target.WithDescription(description))
+	}
+	out, m, err := repo.CloneTcpTarget(ctx, id, name, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to clone target: %w", err)
+	}
+	if out == nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to clone target but no error returned from repository.")
+	}
+	item, err := toProto(out, m)
+	if err != nil {
+		return nil, err
+	}
+	item.Scope = authResults.Scope
+	return &pbs.CloneTargetResponse{Item: item}, nil
+}
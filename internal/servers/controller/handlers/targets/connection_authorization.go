@@ -0,0 +1,58 @@
+package targets
+
+import (
+	"context"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/action"
+)
+
+// GetTargetConnectionAuthorization returns whether connections to sessions of
+// the target must be authorized again. If required, the grants of the auth
+// token the session was authorized with are checked again for each new
+// connection, so revoking them applies to sessions already established.
+func (s Service) GetTargetConnectionAuthorization(ctx context.Context, req *pbs.GetTargetConnectionAuthorizationRequest) (*pbs.GetTargetConnectionAuthorizationResponse, error) {
+	if !handlers.ValidId(target.TcpTargetPrefix, req.GetId()) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	authResults := s.authResult(ctx, req.GetId(), action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	required, err := repo.LookupConnectionAuthorization(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetTargetConnectionAuthorizationResponse{
+		Item: &pb.ConnectionAuthorization{TargetId: req.GetId(), Required: required},
+	}, nil
+}
+
+// SetTargetConnectionAuthorization sets whether connections to sessions of
+// the target must be authorized again.
+func (s Service) SetTargetConnectionAuthorization(ctx context.Context, req *pbs.SetTargetConnectionAuthorizationRequest) (*pbs.SetTargetConnectionAuthorizationResponse, error) {
+	if !handlers.ValidId(target.TcpTargetPrefix, req.GetId()) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	authResults := s.authResult(ctx, req.GetId(), action.Update)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.SetConnectionAuthorization(ctx, req.GetId(), req.GetRequired()); err != nil {
+		return nil, err
+	}
+	return &pbs.SetTargetConnectionAuthorizationResponse{
+		Item: &pb.ConnectionAuthorization{TargetId: req.GetId(), Required: req.GetRequired()},
+	}, nil
+}
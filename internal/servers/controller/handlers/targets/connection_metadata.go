@@ -0,0 +1,93 @@
+package targets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/action"
+)
+
+// GetTargetConnectionMetadata returns the connection metadata of the target.
+func (s Service) GetTargetConnectionMetadata(ctx context.Context, req *pbs.GetTargetConnectionMetadataRequest) (*pbs.GetTargetConnectionMetadataResponse, error) {
+	id := req.GetId()
+	if !handlers.ValidId(target.TcpTargetPrefix, id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	authResults := s.authResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	md, err := s.sessionConnectionMetadata(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetTargetConnectionMetadataResponse{
+		Item: &pb.ConnectionMetadata{
+			TargetId: id,
+			Metadata: md,
+		},
+	}, nil
+}
+
+// SetTargetConnectionMetadata replaces the connection metadata of the target.
+// An empty map removes it. It is returned with sessions authorized
+// afterwards.
+func (s Service) SetTargetConnectionMetadata(ctx context.Context, req *pbs.SetTargetConnectionMetadataRequest) (*pbs.SetTargetConnectionMetadataResponse, error) {
+	id, metadata := req.GetId(), req.GetMetadata()
+	if !handlers.ValidId(target.TcpTargetPrefix, id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	badFields := map[string]string{}
+	if len(metadata) > target.MaxConnectionMetadataEntries {
+		badFields["metadata"] = fmt.Sprintf("At most %d entries are allowed.", target.MaxConnectionMetadataEntries)
+	}
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		switch {
+		case !target.ValidConnectionMetadataKey(k):
+			badFields["metadata."+k] = "Keys must start with a lowercase letter followed by up to 63 lowercase letters, digits or underscores."
+		case len(metadata[k]) > target.MaxConnectionMetadataValueLength:
+			badFields["metadata."+k] = fmt.Sprintf("Values must be at most %d bytes long.", target.MaxConnectionMetadataValueLength)
+		}
+	}
+	if len(badFields) > 0 {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	authResults := s.authResult(ctx, id, action.Update)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.SetConnectionMetadata(ctx, id, metadata); err != nil {
+		return nil, err
+	}
+	return &pbs.SetTargetConnectionMetadataResponse{
+		Item: &pb.ConnectionMetadata{
+			TargetId: id,
+			Metadata: metadata,
+		},
+	}, nil
+}
+
+// sessionConnectionMetadata returns the connection metadata of the target
+// without authorizing the request; it is for adding the metadata to session
+// authorizations which were already authorized.
+func (s Service) sessionConnectionMetadata(ctx context.Context, id string) (map[string]string, error) {
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	return repo.LookupConnectionMetadata(ctx, id)
+}
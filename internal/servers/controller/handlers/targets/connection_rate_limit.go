@@ -0,0 +1,68 @@
+package targets
+
+import (
+	"context"
+	"math"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/action"
+)
+
+// GetTargetConnectionRateLimit returns the number of connections workers let
+// each session of the target open per minute. A limit of 0 is unlimited.
+func (s Service) GetTargetConnectionRateLimit(ctx context.Context, req *pbs.GetTargetConnectionRateLimitRequest) (*pbs.GetTargetConnectionRateLimitResponse, error) {
+	id := req.GetId()
+	if !handlers.ValidId(target.TcpTargetPrefix, id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	authResults := s.authResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	l, err := repo.LookupConnectionRateLimit(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetTargetConnectionRateLimitResponse{
+		Item: &pb.ConnectionRateLimit{
+			TargetId:             id,
+			ConnectionsPerMinute: l.ConnectionsPerMinute,
+		},
+	}, nil
+}
+
+// SetTargetConnectionRateLimit sets the connection rate limit of the target.
+// It applies to sessions looked up by workers afterwards.
+func (s Service) SetTargetConnectionRateLimit(ctx context.Context, req *pbs.SetTargetConnectionRateLimitRequest) (*pbs.SetTargetConnectionRateLimitResponse, error) {
+	id, connectionsPerMinute := req.GetId(), req.GetConnectionsPerMinute()
+	if !handlers.ValidId(target.TcpTargetPrefix, id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	if connectionsPerMinute > math.MaxInt32 {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"connections_per_minute": "Value is too large."})
+	}
+	authResults := s.authResult(ctx, id, action.Update)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.SetConnectionRateLimit(ctx, id, connectionsPerMinute); err != nil {
+		return nil, err
+	}
+	return &pbs.SetTargetConnectionRateLimitResponse{
+		Item: &pb.ConnectionRateLimit{
+			TargetId:             id,
+			ConnectionsPerMinute: connectionsPerMinute,
+		},
+	}, nil
+}
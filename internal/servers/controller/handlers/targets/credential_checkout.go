@@ -0,0 +1,100 @@
+package targets
+
+import (
+	"context"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/action"
+)
+
+// GetTargetCredentialCheckout returns the credential checkout policy of the
+// target and the current holder of its credential.
+func (s Service) GetTargetCredentialCheckout(ctx context.Context, req *pbs.GetTargetCredentialCheckoutRequest) (*pbs.GetTargetCredentialCheckoutResponse, error) {
+	id := req.GetId()
+	if !handlers.ValidId(target.TcpTargetPrefix, id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	authResults := s.authResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	cc, err := s.credentialCheckout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetTargetCredentialCheckoutResponse{Item: cc}, nil
+}
+
+// SetTargetCredentialCheckout sets the credential checkout policy of the
+// target. An empty mode removes it. It applies to sessions authorized
+// afterwards.
+func (s Service) SetTargetCredentialCheckout(ctx context.Context, req *pbs.SetTargetCredentialCheckoutRequest) (*pbs.SetTargetCredentialCheckoutResponse, error) {
+	id, mode, queueTimeoutSeconds, rotateOnCheckIn := req.GetId(), req.GetMode(), req.GetQueueTimeoutSeconds(), req.GetRotateOnCheckIn()
+	if !handlers.ValidId(target.TcpTargetPrefix, id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	badFields := map[string]string{}
+	switch mode {
+	case "", target.CredentialCheckoutReject:
+		if queueTimeoutSeconds != 0 {
+			badFields["queue_timeout_seconds"] = "Only allowed in the queue mode."
+		}
+	case target.CredentialCheckoutQueue:
+		if queueTimeoutSeconds == 0 {
+			badFields["queue_timeout_seconds"] = "Required in the queue mode."
+		}
+	default:
+		badFields["mode"] = "Must be reject, queue or empty."
+	}
+	if mode == "" && rotateOnCheckIn {
+		badFields["rotate_on_check_in"] = "Requires a mode."
+	}
+	if len(badFields) > 0 {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	authResults := s.authResult(ctx, id, action.Update)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.SetCredentialCheckoutPolicy(ctx, id, mode, queueTimeoutSeconds, rotateOnCheckIn); err != nil {
+		return nil, err
+	}
+	cc, err := s.credentialCheckout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.SetTargetCredentialCheckoutResponse{Item: cc}, nil
+}
+
+func (s Service) credentialCheckout(ctx context.Context, id string) (*pb.CredentialCheckout, error) {
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	p, err := repo.LookupCredentialCheckoutPolicy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c, err := repo.LookupCredentialCheckout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ret := &pb.CredentialCheckout{TargetId: id}
+	if p != nil {
+		ret.Mode = p.Mode
+		ret.QueueTimeoutSeconds = p.QueueTimeoutSeconds
+		ret.RotateOnCheckIn = p.RotateOnCheckIn
+	}
+	if c != nil {
+		ret.HolderUserId = c.UserId
+		ret.HolderSessionId = c.SessionId
+	}
+	return ret, nil
+}
@@ -0,0 +1,86 @@
+package targets
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GetTargetHistory returns a page of the history of the target, rendered from
+// its oplog entries, newest first. Reading the history requires reading the
+// target. The values of sensitive fields are redacted.
+func (s Service) GetTargetHistory(ctx context.Context, req *pbs.GetTargetHistoryRequest) (*pbs.GetTargetHistoryResponse, error) {
+	if !handlers.ValidId(target.TcpTargetPrefix, req.GetId()) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	if s.historyFn == nil {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Unimplemented, "Target history is not supported.")
+	}
+	authResults := s.authResult(ctx, req.GetId(), action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	types, err := target.HistoryTypes()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create target history types: %w", err)
+	}
+	query := &oplog.SearchQuery{
+		Metadata: oplog.Metadata{oplog.ResourcePublicIdMetadataKey: []string{req.GetId()}},
+		Limit:    oplog.DefaultHistoryLimit,
+		BeforeId: req.GetBefore(),
+	}
+	if req.GetLimit() > 0 {
+		query.Limit = int(req.GetLimit())
+	}
+	if req.GetActorId() != "" {
+		query.Metadata[oplog.ActorIdMetadataKey] = []string{req.GetActorId()}
+	}
+	page, err := s.historyFn(ctx, query, types)
+	if err != nil {
+		return nil, err
+	}
+	out := &pbs.GetTargetHistoryResponse{
+		Id:         req.GetId(),
+		Items:      make([]*pb.HistoryEntry, 0, len(page.Items)),
+		NextBefore: page.NextBeforeId,
+	}
+	for _, e := range page.Items {
+		item, err := toHistoryProto(e)
+		if err != nil {
+			return nil, err
+		}
+		out.Items = append(out.Items, item)
+	}
+	return out, nil
+}
+
+func toHistoryProto(e *oplog.HistoryEntry) (*pb.HistoryEntry, error) {
+	out := &pb.HistoryEntry{
+		Id:         e.Id,
+		CreateTime: timestamppb.New(e.CreateTime),
+		ActorId:    e.ActorId,
+		Changes:    make([]*pb.HistoryChange, 0, len(e.Changes)),
+	}
+	for _, c := range e.Changes {
+		fields, err := structpb.NewStruct(c.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert fields of history entry %d: %w", e.Id, err)
+		}
+		out.Changes = append(out.Changes, &pb.HistoryChange{
+			Type:       c.Type,
+			Operation:  c.Operation,
+			Fields:     fields,
+			NullFields: c.NullFields,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,68 @@
+package targets
+
+import (
+	"context"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/action"
+)
+
+// GetTargetPeerIdentity returns the peer identity setting of the target.
+func (s Service) GetTargetPeerIdentity(ctx context.Context, req *pbs.GetTargetPeerIdentityRequest) (*pbs.GetTargetPeerIdentityResponse, error) {
+	id := req.GetId()
+	if !handlers.ValidId(target.TcpTargetPrefix, id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	authResults := s.authResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	p, err := repo.LookupPeerIdentity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetTargetPeerIdentityResponse{
+		Item: &pb.PeerIdentity{
+			TargetId: id,
+			Mode:     p.Mode,
+		},
+	}, nil
+}
+
+// SetTargetPeerIdentity sets the peer identity setting of the target. It
+// applies to sessions looked up by workers afterwards.
+func (s Service) SetTargetPeerIdentity(ctx context.Context, req *pbs.SetTargetPeerIdentityRequest) (*pbs.SetTargetPeerIdentityResponse, error) {
+	id, mode := req.GetId(), req.GetMode()
+	if !handlers.ValidId(target.TcpTargetPrefix, id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	switch mode {
+	case "", target.PeerIdentityProxyV2:
+	default:
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"mode": "Must be empty or proxy_v2."})
+	}
+	authResults := s.authResult(ctx, id, action.Update)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.SetPeerIdentity(ctx, id, mode); err != nil {
+		return nil, err
+	}
+	return &pbs.SetTargetPeerIdentityResponse{
+		Item: &pb.PeerIdentity{
+			TargetId: id,
+			Mode:     mode,
+		},
+	}, nil
+}
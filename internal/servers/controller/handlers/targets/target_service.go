@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/hashicorp/boundary/internal/auth"
@@ -17,6 +18,9 @@ import (
 	"github.com/hashicorp/boundary/internal/host"
 	"github.com/hashicorp/boundary/internal/host/static"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/libs/endpoint"
+	"github.com/hashicorp/boundary/internal/libs/latency"
+	"github.com/hashicorp/boundary/internal/secretfingerprint"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
@@ -28,7 +32,9 @@ import (
 	"github.com/hashicorp/boundary/internal/types/scope"
 	"github.com/hashicorp/boundary/sdk/strutil"
 	"github.com/mr-tron/base58"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -49,12 +55,24 @@ func init() {
 type Service struct {
 	pbs.UnimplementedTargetServiceServer
 
-	repoFn           common.TargetRepoFactory
-	iamRepoFn        common.IamRepoFactory
+	repoFn           common.TargetRepoProvider
+	iamRepoFn        common.IamRepoProvider
 	serversRepoFn    common.ServersRepoFactory
-	sessionRepoFn    common.SessionRepoFactory
+	sessionRepoFn    common.SessionRepoProvider
 	staticHostRepoFn common.StaticRepoFactory
 	kmsCache         *kms.Kms
+	workerSelector   servers.WorkerSelector
+	// workerAffinity is how long the worker of a session is preferred for
+	// the next session of its user to its target, if positive
+	workerAffinity time.Duration
+
+	// secretFingerprintRepoFn is nil unless the fingerprints of brokered
+	// secrets are registered
+	secretFingerprintRepoFn common.SecretFingerprintRepoFactory
+	// annotationRepoFn is nil unless targets may be annotated
+	annotationRepoFn common.AnnotationRepoFactory
+	// historyFn is nil unless the history of targets may be read
+	historyFn common.HistorySearchFn
 }
 
 // NewService returns a target service which handles target related requests to boundary.
@@ -64,7 +82,22 @@ func NewService(
 	iamRepoFn common.IamRepoFactory,
 	serversRepoFn common.ServersRepoFactory,
 	sessionRepoFn common.SessionRepoFactory,
-	staticHostRepoFn common.StaticRepoFactory) (Service, error) {
+	staticHostRepoFn common.StaticRepoFactory,
+	opt ...handlers.Option) (Service, error) {
+	return NewServiceWithRepos(kmsCache, repoFn.Provider(), iamRepoFn.Provider(), serversRepoFn, sessionRepoFn.Provider(), staticHostRepoFn, opt...)
+}
+
+// NewServiceWithRepos returns a target service using the target, iam and
+// session repositories of the providers, which need not be the database
+// backed ones.
+func NewServiceWithRepos(
+	kmsCache *kms.Kms,
+	repoFn common.TargetRepoProvider,
+	iamRepoFn common.IamRepoProvider,
+	serversRepoFn common.ServersRepoFactory,
+	sessionRepoFn common.SessionRepoProvider,
+	staticHostRepoFn common.StaticRepoFactory,
+	opt ...handlers.Option) (Service, error) {
 	if repoFn == nil {
 		return Service{}, fmt.Errorf("nil target repository provided")
 	}
@@ -80,6 +113,12 @@ func NewService(
 	if staticHostRepoFn == nil {
 		return Service{}, fmt.Errorf("nil static host repository provided")
 	}
+	opts := handlers.GetOpts(opt...)
+	workerSelector := opts.WithWorkerSelector
+	if workerSelector == nil {
+		// The default strategy never fails
+		workerSelector, _ = servers.NewWorkerSelector("", nil)
+	}
 	return Service{
 		repoFn:           repoFn,
 		iamRepoFn:        iamRepoFn,
@@ -87,6 +126,12 @@ func NewService(
 		sessionRepoFn:    sessionRepoFn,
 		staticHostRepoFn: staticHostRepoFn,
 		kmsCache:         kmsCache,
+		workerSelector:   workerSelector,
+		workerAffinity:   opts.WithWorkerAffinity,
+
+		secretFingerprintRepoFn: opts.WithSecretFingerprints,
+		annotationRepoFn:        opts.WithAnnotations,
+		historyFn:               opts.WithHistory,
 	}, nil
 }
 
@@ -105,8 +150,17 @@ func (s Service) ListTargets(ctx context.Context, req *pbs.ListTargetsRequest) (
 	if err != nil {
 		return nil, err
 	}
+	ids := make([]string, 0, len(ul))
+	for _, item := range ul {
+		ids = append(ids, item.GetId())
+	}
+	annotations, err := handlers.ListAnnotations(ctx, s.annotationRepoFn, ids)
+	if err != nil {
+		return nil, err
+	}
 	for _, item := range ul {
 		item.Scope = authResults.Scope
+		item.Annotations = annotations[item.GetId()]
 	}
 	return &pbs.ListTargetsResponse{Items: ul}, nil
 }
@@ -124,10 +178,54 @@ func (s Service) GetTarget(ctx context.Context, req *pbs.GetTargetRequest) (*pbs
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.GetTargetResponse{Item: u}, nil
 }
 
+// BatchGetTargets returns the targets with the ids, in the order of the ids,
+// looking them all up with a single query. Targets which don't exist or which
+// the caller may not read are omitted rather than failing the request, so
+// clients can fetch the targets they found with one request instead of one
+// per target.
+func (s Service) BatchGetTargets(ctx context.Context, req *pbs.BatchGetTargetsRequest) (*pbs.BatchGetTargetsResponse, error) {
+	ids := req.GetIds()
+	if err := handlers.ValidateBatchGetRequest(target.TcpTargetPrefix, ids); err != nil {
+		return nil, err
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	tl, hostSets, err := repo.LookupTargets(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*pb.Target, 0, len(tl))
+	for _, t := range tl {
+		authResults := auth.Verify(ctx,
+			auth.WithType(resource.Target),
+			auth.WithAction(action.Read),
+			auth.WithId(t.GetPublicId()),
+			auth.WithScopeId(t.GetScopeId()))
+		if authResults.Error != nil {
+			if errors.Is(authResults.Error, handlers.ForbiddenError()) {
+				continue
+			}
+			return nil, authResults.Error
+		}
+		item, err := toProto(t, hostSets[t.GetPublicId()])
+		if err != nil {
+			return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to convert value to proto: %v.", err)
+		}
+		item.Scope = authResults.Scope
+		items = append(items, item)
+	}
+	return &pbs.BatchGetTargetsResponse{Items: items}, nil
+}
+
 // CreateTarget implements the interface pbs.TargetServiceServer.
 func (s Service) CreateTarget(ctx context.Context, req *pbs.CreateTargetRequest) (*pbs.CreateTargetResponse, error) {
 	if err := validateCreateRequest(req); err != nil {
@@ -158,6 +256,9 @@ func (s Service) UpdateTarget(ctx context.Context, req *pbs.UpdateTargetRequest)
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.UpdateAnnotations(ctx, s.annotationRepoFn, resource.Target, req.GetId(), req.GetItem().GetAnnotations(), req.GetUpdateMask().GetPaths()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.UpdateTargetResponse{Item: u}, nil
 }
@@ -191,6 +292,9 @@ func (s Service) AddTargetHostSets(ctx context.Context, req *pbs.AddTargetHostSe
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.AddTargetHostSetsResponse{Item: u}, nil
 }
@@ -208,6 +312,9 @@ func (s Service) SetTargetHostSets(ctx context.Context, req *pbs.SetTargetHostSe
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.SetTargetHostSetsResponse{Item: u}, nil
 }
@@ -225,6 +332,9 @@ func (s Service) RemoveTargetHostSets(ctx context.Context, req *pbs.RemoveTarget
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.RemoveTargetHostSetsResponse{Item: u}, nil
 }
@@ -233,11 +343,18 @@ func (s Service) AuthorizeSession(ctx context.Context, req *pbs.AuthorizeSession
 	if err := validateAuthorizeSessionRequest(req); err != nil {
 		return nil, err
 	}
+	// Time the phases of the authorization so slow sessions can be broken
+	// down; they are recorded as metrics and sent back as Server-Timing.
+	budget := latency.New("controller", "authorize_session")
+	defer budget.Finish()
+
+	endGrantEval := budget.Start("grant_eval")
 	authResults := s.authResult(ctx, req.GetId(), action.AuthorizeSession,
 		target.WithName(req.GetName()),
 		target.WithScopeId(req.GetScopeId()),
 		target.WithScopeName(req.GetScopeName()),
 	)
+	endGrantEval()
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
@@ -271,6 +388,7 @@ func (s Service) AuthorizeSession(ctx context.Context, req *pbs.AuthorizeSession
 		return nil, handlers.ForbiddenError()
 	}
 
+	endHostSelection := budget.Start("host_selection")
 	// Get the target information
 	repo, err := s.repoFn()
 	if err != nil {
@@ -286,6 +404,12 @@ func (s Service) AuthorizeSession(ctx context.Context, req *pbs.AuthorizeSession
 	if t == nil {
 		return nil, handlers.NotFoundErrorf("Target %q not found.", t.GetPublicId())
 	}
+	// Looked up before the session is created so failing to look it up
+	// doesn't leave an unused session behind
+	connectionMetadata, err := s.sessionConnectionMetadata(ctx, t.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
 
 	// Instantiate some repos
 	sessionRepo, err := s.sessionRepoFn()
@@ -365,11 +489,8 @@ HostSetIterationLoop:
 			return nil, stderrors.New("host had empty address")
 		}
 	}
-	if defaultPort != 0 {
-		endpointUrl.Host = fmt.Sprintf("%s:%d", endpointHost, defaultPort)
-	} else {
-		endpointUrl.Host = endpointHost
-	}
+	endpointUrl.Host = endpoint.UrlHost(endpointHost, defaultPort)
+	endHostSelection()
 
 	expTime := timestamppb.Now()
 	expTime.Seconds += int64(t.GetSessionMaxSeconds())
@@ -389,22 +510,88 @@ HostSetIterationLoop:
 	if err != nil {
 		return nil, err
 	}
-	wrapper, err := s.kmsCache.GetWrapper(ctx, authResults.Scope.Id, kms.KeyPurposeSessions)
+
+	endWorkerSelection := budget.Start("worker_selection")
+	var workers []*pb.WorkerInfo
+	var workerNames []string
+	workerServers, err := serversRepo.ListServers(ctx, servers.ServerTypeWorker)
 	if err != nil {
 		return nil, err
 	}
-	sess, privKey, err := sessionRepo.CreateSession(ctx, wrapper, sess)
+	// Worker states are shared by all controllers, so this takes into account
+	// the load of workers connected to other controllers.
+	workerStates, err := serversRepo.ListWorkerStates(ctx)
 	if err != nil {
 		return nil, err
 	}
+	s.workerSelector.SelectWorkers(workerServers, workerStates)
+	if s.workerAffinity > 0 {
+		// Prefer the worker of the previous session of the user to the
+		// target, unless it is unhealthy, for connection reuse and locality
+		lastWorkerId, err := sessionRepo.LastSessionWorker(ctx, authResults.UserId, t.GetPublicId(), time.Now().Add(-s.workerAffinity))
+		if err != nil {
+			return nil, err
+		}
+		servers.PreferWorker(workerServers, workerStates, lastWorkerId)
+	}
+	for _, v := range workerServers {
+		wi := &pb.WorkerInfo{Address: v.Address}
+		// The location and latency of workers are hints for clients picking
+		// the nearest worker themselves
+		if st := workerStates[v.PrivateId]; st != nil {
+			wi.Region = st.Region
+			wi.Zone = st.Zone
+			wi.ControllerRttMs = st.ControllerRttMs
+		}
+		workers = append(workers, wi)
+		workerNames = append(workerNames, v.PrivateId)
+	}
+	endWorkerSelection()
 
-	var workers []*pb.WorkerInfo
-	servers, err := serversRepo.ListServers(ctx, servers.ServerTypeWorker)
+	// Targets backed by a shared credential are held by one session at a
+	// time; this may wait in line for the credential to be checked in.
+	endCheckout := budget.Start("credential_checkout")
+	checkout, err := repo.CheckOutCredential(ctx, t.GetPublicId(), authResults.UserId)
+	switch {
+	case errors.Is(err, target.ErrCredentialCheckedOut):
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.FailedPrecondition, "The credential of target %q is checked out by another session.", t.GetPublicId())
+	case errors.Is(err, target.ErrCredentialCheckoutTimeout):
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Unavailable, "Timed out waiting for the credential of target %q to be checked in.", t.GetPublicId())
+	case err != nil:
+		return nil, err
+	}
+	endCheckout()
+
+	// Creating the session issues the certificate and key clients present
+	// to workers
+	endCredentials := budget.Start("session_credentials")
+	wrapper, err := s.kmsCache.GetWrapper(ctx, authResults.Scope.Id, kms.KeyPurposeSessions)
+	var privKey []byte
+	if err == nil {
+		sess, privKey, err = sessionRepo.CreateSession(ctx, wrapper, sess, session.WithWorkers(workerNames))
+	}
 	if err != nil {
+		if checkout != nil {
+			// A checkout left behind goes stale after a minute anyway
+			_ = repo.CheckInCredential(ctx, checkout.CheckoutId)
+		}
 		return nil, err
 	}
-	for _, v := range servers {
-		workers = append(workers, &pb.WorkerInfo{Address: v.Address})
+	endCredentials()
+	if checkout != nil {
+		if err := repo.AttachCredentialCheckout(ctx, checkout.CheckoutId, sess.PublicId); err != nil {
+			// The checkout went stale while creating the session, so the
+			// session must not be used
+			_, _ = sessionRepo.CancelSession(ctx, sess.PublicId, sess.Version)
+			return nil, fmt.Errorf("error attaching credential checkout to session: %w", err)
+		}
+	}
+	if s.secretFingerprintRepoFn != nil {
+		if err := s.registerSecretFingerprints(ctx, sess, privKey, authResults.UserId, authResults.Scope.Id); err != nil {
+			// A secret which can't be traced must not be brokered
+			_, _ = sessionRepo.CancelSession(ctx, sess.PublicId, sess.Version)
+			return nil, err
+		}
 	}
 
 	sad := &pb.SessionAuthorizationData{
@@ -437,10 +624,33 @@ HostSetIterationLoop:
 		HostId:             chosenId.hostId,
 		HostSetId:          chosenId.hostSetId,
 		Endpoint:           endpointUrl.String(),
+		ConnectionMetadata: connectionMetadata,
 	}
+	// This fails when not called through a gRPC server, such as in tests,
+	// and the timings are only informational.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(handlers.ServerTimingMetadataKey, budget.ServerTiming()))
 	return &pbs.AuthorizeSessionResponse{Item: ret}, nil
 }
 
+// registerSecretFingerprints registers the fingerprints of the secrets
+// brokered for the session, so they can be traced to it if they leak.
+func (s Service) registerSecretFingerprints(ctx context.Context, sess *session.Session, privKey []byte, userId, scopeId string) error {
+	repo, err := s.secretFingerprintRepoFn()
+	if err != nil {
+		return err
+	}
+	reg := &secretfingerprint.Registration{
+		SessionId: sess.PublicId,
+		TargetId:  sess.TargetId,
+		UserId:    userId,
+		ScopeId:   scopeId,
+	}
+	if err := repo.Register(ctx, secretfingerprint.SessionPrivateKey, privKey, reg); err != nil {
+		return fmt.Errorf("error registering session secret fingerprints: %w", err)
+	}
+	return nil
+}
+
 func (s Service) getFromRepo(ctx context.Context, id string) (*pb.Target, error) {
 	repo, err := s.repoFn()
 	if err != nil {
@@ -524,7 +734,18 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, id string, mask []st
 	u.PublicId = id
 	dbMask := maskManager.Translate(mask)
 	if len(dbMask) == 0 {
-		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid paths provided in the update mask."})
+		if len(handlers.AnnotationsMask(mask)) == 0 {
+			return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid paths provided in the update mask."})
+		}
+		// Only annotations are updated, which are not stored with the target
+		out, err := s.getFromRepo(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if out.GetVersion() != item.GetVersion() {
+			return nil, handlers.NotFoundErrorf("Target %q not found or incorrect version provided.", id)
+		}
+		return out, nil
 	}
 	repo, err := s.repoFn()
 	if err != nil {
@@ -560,13 +781,13 @@ func (s Service) listFromRepo(ctx context.Context, scopeId string) ([]*pb.Target
 	if err != nil {
 		return nil, err
 	}
-	ul, err := repo.ListTargets(ctx, target.WithScopeId(scopeId))
+	ul, hostSets, err := repo.ListTargetsWithHostSets(ctx, target.WithScopeId(scopeId))
 	if err != nil {
 		return nil, err
 	}
 	var outUl []*pb.Target
 	for _, u := range ul {
-		o, err := toProto(u, nil)
+		o, err := toProto(u, hostSets[u.GetPublicId()])
 		if err != nil {
 			return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to convert value to proto: %v.", err)
 		}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-cmp/cmp"
@@ -146,6 +148,9 @@ func TestList(t *testing.T) {
 	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
 	hc := static.TestCatalogs(t, conn, proj.GetPublicId(), 1)[0]
 	hss := static.TestSets(t, conn, hc.GetPublicId(), 2)
+	sort.Slice(hss, func(i, j int) bool {
+		return hss[i].GetPublicId() < hss[j].GetPublicId()
+	})
 
 	var wantTars []*pb.Target
 	for i := 0; i < 5; i++ {
@@ -163,6 +168,11 @@ func TestList(t *testing.T) {
 			Attributes:             new(structpb.Struct),
 			SessionMaxSeconds:      wrapperspb.UInt32(28800),
 			SessionConnectionLimit: wrapperspb.Int32(1),
+			HostSetIds:             []string{hss[0].GetPublicId(), hss[1].GetPublicId()},
+			HostSets: []*pb.HostSet{
+				{Id: hss[0].GetPublicId(), HostCatalogId: hc.GetPublicId()},
+				{Id: hss[1].GetPublicId(), HostCatalogId: hc.GetPublicId()},
+			},
 		})
 	}
 
@@ -1136,3 +1146,357 @@ func TestRemoveTargetHostSets(t *testing.T) {
 		})
 	}
 }
+
+func TestBatchGetTargets(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	hc := static.TestCatalogs(t, conn, proj.GetPublicId(), 1)[0]
+	hs := static.TestSets(t, conn, hc.GetPublicId(), 1)
+
+	t1 := target.TestTcpTarget(t, conn, proj.GetPublicId(), "test-1", target.WithHostSets([]string{hs[0].GetPublicId()}))
+	t2 := target.TestTcpTarget(t, conn, proj.GetPublicId(), "test-2")
+
+	s, err := testService(t, conn, kms, wrapper)
+	require.NoError(t, err, "Couldn't create a new target service.")
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(proj.GetPublicId()))
+
+	t.Run("ordered", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		res, err := s.BatchGetTargets(ctx, &pbs.BatchGetTargetsRequest{Ids: []string{t2.GetPublicId(), target.TcpTargetPrefix + "_DoesntExis", t1.GetPublicId()}})
+		require.NoError(err)
+		got := res.GetItems()
+		require.Len(got, 2)
+		for i, want := range []*target.TcpTarget{t2, t1} {
+			gotTarget, err := s.GetTarget(ctx, &pbs.GetTargetRequest{Id: want.GetPublicId()})
+			require.NoError(err)
+			assert.Empty(cmp.Diff(gotTarget.GetItem(), got[i], protocmp.Transform()))
+		}
+	})
+
+	cases := []struct {
+		name string
+		ids  []string
+	}{
+		{
+			name: "No ids",
+		},
+		{
+			name: "Wrong id prefix",
+			ids:  []string{t1.GetPublicId(), "j_1234567890"},
+		},
+		{
+			name: "Too many ids",
+			ids:  make([]string, handlers.MaxBatchGetIds+1),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := s.BatchGetTargets(ctx, &pbs.BatchGetTargetsRequest{Ids: tc.ids})
+			require.Error(err)
+			assert.True(errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), "BatchGetTargets(%q) got error %v", tc.ids, err)
+			assert.Nil(got)
+		})
+	}
+}
+
+func TestCloneTarget(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	hc := static.TestCatalogs(t, conn, proj.GetPublicId(), 1)[0]
+	hs := static.TestSets(t, conn, hc.GetPublicId(), 2)
+
+	tar := target.TestTcpTarget(t, conn, proj.GetPublicId(), "test",
+		target.WithDescription("source"),
+		target.WithDefaultPort(22),
+		target.WithHostSets([]string{hs[0].GetPublicId(), hs[1].GetPublicId()}))
+
+	s, err := testService(t, conn, kms, wrapper)
+	require.NoError(t, err, "Couldn't create a new target service.")
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(proj.GetPublicId()))
+
+	t.Run("clone", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		res, err := s.CloneTarget(ctx, &pbs.CloneTargetRequest{Id: tar.GetPublicId(), Name: "clone"})
+		require.NoError(err)
+		got := res.GetItem()
+		assert.NotEqual(tar.GetPublicId(), got.GetId())
+		assert.Equal(proj.GetPublicId(), got.GetScopeId())
+		assert.Equal("clone", got.GetName().GetValue())
+		assert.Equal("source", got.GetDescription().GetValue())
+		assert.Equal(&scopes.ScopeInfo{Id: proj.GetPublicId(), Type: scope.Project.String()}, got.GetScope())
+		assert.ElementsMatch([]string{hs[0].GetPublicId(), hs[1].GetPublicId()}, got.GetHostSetIds())
+		assert.Equal(float64(22), got.GetAttributes().GetFields()["default_port"].GetNumberValue())
+
+		gotTarget, err := s.GetTarget(ctx, &pbs.GetTargetRequest{Id: got.GetId()})
+		require.NoError(err)
+		assert.Empty(cmp.Diff(got, gotTarget.GetItem(), protocmp.Transform()))
+	})
+	t.Run("description", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := s.CloneTarget(ctx, &pbs.CloneTargetRequest{Id: tar.GetPublicId(), Name: "clone-description", Description: "clone"})
+		require.NoError(err)
+		assert.Equal("clone", got.GetItem().GetDescription().GetValue())
+	})
+
+	cases := []struct {
+		name   string
+		id     string
+		tgName string
+		err    error
+	}{
+		{
+			name:   "Non existing Target",
+			id:     target.TcpTargetPrefix + "_DoesntExis",
+			tgName: "missing",
+			err:    handlers.ApiErrorWithCode(codes.NotFound),
+		},
+		{
+			name:   "Wrong id prefix",
+			id:     "j_1234567890",
+			tgName: "wrong",
+			err:    handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Missing name",
+			id:   tar.GetPublicId(),
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := s.CloneTarget(ctx, &pbs.CloneTargetRequest{Id: tc.id, Name: tc.tgName})
+			require.Error(err)
+			assert.True(errors.Is(err, tc.err), "CloneTarget(%q) got error %v, wanted %v", tc.id, err, tc.err)
+			assert.Nil(got)
+		})
+	}
+}
+
+func TestTestTargetConnection(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	rw := db.New(conn)
+	serversRepo, err := servers.NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	hc := static.TestCatalogs(t, conn, proj.GetPublicId(), 1)[0]
+	hs := static.TestSets(t, conn, hc.GetPublicId(), 1)[0]
+	hosts := static.TestHosts(t, conn, hc.GetPublicId(), 2)
+	static.TestSetMembers(t, conn, hs.GetPublicId(), hosts)
+	tar := target.TestTcpTarget(t, conn, proj.GetPublicId(), "test",
+		target.WithDefaultPort(22),
+		target.WithHostSets([]string{hs.GetPublicId()}))
+
+	s, err := testService(t, conn, kms, wrapper)
+	require.NoError(t, err, "Couldn't create a new target service.")
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(proj.GetPublicId()))
+
+	oldTimeout := targets.ConnectionTestTimeout
+	targets.ConnectionTestTimeout = time.Second
+	t.Cleanup(func() { targets.ConnectionTestTimeout = oldTimeout })
+
+	t.Run("no workers", func(t *testing.T) {
+		_, err := s.TestTargetConnection(ctx, &pbs.TestTargetConnectionRequest{Id: tar.GetPublicId()})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, handlers.ApiErrorWithCode(codes.FailedPrecondition)), "got error %v", err)
+	})
+
+	_, _, err = serversRepo.UpsertServer(context.Background(), &servers.Server{
+		Name:    "worker-1",
+		Type:    servers.ServerTypeWorker.String(),
+		Address: "127.0.0.1",
+	})
+	require.NoError(t, err)
+
+	// fakeWorker completes the checks dispatched to the worker with the
+	// results of report until the test ends.
+	fakeWorker := func(t *testing.T, report func(*servers.ConnectionCheck) *servers.ConnectionCheck) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			cancel()
+			<-done
+		})
+		go func() {
+			defer close(done)
+			for ctx.Err() == nil {
+				checks, err := serversRepo.DispatchConnectionChecks(ctx, "worker-1")
+				if err == nil && len(checks) > 0 {
+					results := make([]*servers.ConnectionCheck, 0, len(checks))
+					for _, c := range checks {
+						results = append(results, report(c))
+					}
+					_ = serversRepo.CompleteConnectionChecks(ctx, "worker-1", results)
+				}
+				time.Sleep(50 * time.Millisecond)
+			}
+		}()
+	}
+
+	t.Run("reachable", func(t *testing.T) {
+		fakeWorker(t, func(c *servers.ConnectionCheck) *servers.ConnectionCheck {
+			assert.True(t, c.Tls)
+			return &servers.ConnectionCheck{CheckId: c.CheckId, Reachable: true, LatencyMs: 5}
+		})
+		got, err := s.TestTargetConnection(ctx, &pbs.TestTargetConnectionRequest{Id: tar.GetPublicId(), Tls: true})
+		require.NoError(t, err)
+		assert.Equal(t, tar.GetPublicId(), got.GetItem().GetTargetId())
+		assert.Equal(t, "worker-1", got.GetItem().GetWorkerId())
+		require.Len(t, got.GetItem().GetResults(), 2)
+		var hostIds []string
+		for _, r := range got.GetItem().GetResults() {
+			hostIds = append(hostIds, r.GetHostId())
+			assert.True(t, r.GetReachable())
+			assert.Equal(t, uint32(5), r.GetLatencyMs())
+			assert.Empty(t, r.GetError())
+			assert.True(t, strings.HasSuffix(r.GetEndpoint(), ":22"), r.GetEndpoint())
+		}
+		assert.ElementsMatch(t, []string{hosts[0].GetPublicId(), hosts[1].GetPublicId()}, hostIds)
+	})
+	t.Run("unreachable", func(t *testing.T) {
+		fakeWorker(t, func(c *servers.ConnectionCheck) *servers.ConnectionCheck {
+			return &servers.ConnectionCheck{CheckId: c.CheckId, Error: "connection refused"}
+		})
+		got, err := s.TestTargetConnection(ctx, &pbs.TestTargetConnectionRequest{Id: tar.GetPublicId(), HostId: hosts[0].GetPublicId()})
+		require.NoError(t, err)
+		require.Len(t, got.GetItem().GetResults(), 1)
+		r := got.GetItem().GetResults()[0]
+		assert.Equal(t, hosts[0].GetPublicId(), r.GetHostId())
+		assert.False(t, r.GetReachable())
+		assert.Equal(t, "connection refused", r.GetError())
+	})
+	t.Run("timeout", func(t *testing.T) {
+		// No worker reports the results
+		got, err := s.TestTargetConnection(ctx, &pbs.TestTargetConnectionRequest{Id: tar.GetPublicId(), HostId: hosts[1].GetPublicId()})
+		require.NoError(t, err)
+		require.Len(t, got.GetItem().GetResults(), 1)
+		r := got.GetItem().GetResults()[0]
+		assert.False(t, r.GetReachable())
+		assert.Equal(t, "The worker did not report a result in time.", r.GetError())
+	})
+
+	cases := []struct {
+		name string
+		req  *pbs.TestTargetConnectionRequest
+		err  error
+	}{
+		{
+			name: "Wrong id prefix",
+			req:  &pbs.TestTargetConnectionRequest{Id: "j_1234567890"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Bad host id",
+			req:  &pbs.TestTargetConnectionRequest{Id: tar.GetPublicId(), HostId: "j_1234567890"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Host not in target",
+			req:  &pbs.TestTargetConnectionRequest{Id: tar.GetPublicId(), HostId: static.HostPrefix + "_1234567890"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Non existing Target",
+			req:  &pbs.TestTargetConnectionRequest{Id: target.TcpTargetPrefix + "_DoesntExis"},
+			err:  handlers.ApiErrorWithCode(codes.NotFound),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := s.TestTargetConnection(ctx, tc.req)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tc.err), "TestTargetConnection(%q) got error %v, wanted %v", tc.req, err, tc.err)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestTargetConnectionMetadata(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	tar := target.TestTcpTarget(t, conn, proj.GetPublicId(), "test")
+
+	s, err := testService(t, conn, kms, wrapper)
+	require.NoError(t, err, "Couldn't create a new target service.")
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(proj.GetPublicId()))
+
+	t.Run("set and get", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		md := map[string]string{"database": "orders", "username": "app"}
+		set, err := s.SetTargetConnectionMetadata(ctx, &pbs.SetTargetConnectionMetadataRequest{Id: tar.GetPublicId(), Metadata: md})
+		require.NoError(err)
+		want := &pb.ConnectionMetadata{TargetId: tar.GetPublicId(), Metadata: md}
+		assert.Empty(cmp.Diff(want, set.GetItem(), protocmp.Transform()))
+
+		got, err := s.GetTargetConnectionMetadata(ctx, &pbs.GetTargetConnectionMetadataRequest{Id: tar.GetPublicId()})
+		require.NoError(err)
+		assert.Empty(cmp.Diff(want, got.GetItem(), protocmp.Transform()))
+	})
+	t.Run("remove", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, err := s.SetTargetConnectionMetadata(ctx, &pbs.SetTargetConnectionMetadataRequest{Id: tar.GetPublicId()})
+		require.NoError(err)
+		got, err := s.GetTargetConnectionMetadata(ctx, &pbs.GetTargetConnectionMetadataRequest{Id: tar.GetPublicId()})
+		require.NoError(err)
+		assert.Empty(got.GetItem().GetMetadata())
+	})
+
+	tooMany := map[string]string{}
+	for i := 0; i <= target.MaxConnectionMetadataEntries; i++ {
+		tooMany[fmt.Sprintf("key_%d", i)] = "value"
+	}
+	cases := []struct {
+		name string
+		req  *pbs.SetTargetConnectionMetadataRequest
+		err  error
+	}{
+		{
+			name: "Wrong id prefix",
+			req:  &pbs.SetTargetConnectionMetadataRequest{Id: "j_1234567890"},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Invalid key",
+			req:  &pbs.SetTargetConnectionMetadataRequest{Id: tar.GetPublicId(), Metadata: map[string]string{"Database": "orders"}},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Value too long",
+			req:  &pbs.SetTargetConnectionMetadataRequest{Id: tar.GetPublicId(), Metadata: map[string]string{"database": strings.Repeat("a", target.MaxConnectionMetadataValueLength+1)}},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Too many entries",
+			req:  &pbs.SetTargetConnectionMetadataRequest{Id: tar.GetPublicId(), Metadata: tooMany},
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := s.SetTargetConnectionMetadata(ctx, tc.req)
+			require.Error(err)
+			assert.True(errors.Is(err, tc.err), "SetTargetConnectionMetadata(%+v) got error %v, wanted %v", tc.req, err, tc.err)
+			assert.Nil(got)
+		})
+	}
+}
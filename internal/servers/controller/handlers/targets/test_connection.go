@@ -0,0 +1,190 @@
+package targets
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/host"
+	"github.com/hashicorp/boundary/internal/libs/endpoint"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// maxConnectionTestHosts is the most hosts tested at once; a host must be
+	// chosen to test targets with more hosts
+	maxConnectionTestHosts = 20
+
+	// connectionTestPollInterval is how often the results of the checks of a
+	// connection test are looked up while waiting for the worker
+	connectionTestPollInterval = 250 * time.Millisecond
+)
+
+// ConnectionTestTimeout is how long a connection test waits for the worker to
+// report its results. Workers pick up checks with their status, so it must be
+// longer than their status interval and the timeout of their checks. It is
+// exported so it can be tweaked in tests.
+var ConnectionTestTimeout = 30 * time.Second
+
+// TestTargetConnection asks the worker sessions of the target would use to
+// connect to its hosts, or only to the requested host if set, and to perform a
+// TLS handshake with them if requested. It waits for the worker to report the
+// results.
+func (s Service) TestTargetConnection(ctx context.Context, req *pbs.TestTargetConnectionRequest) (*pbs.TestTargetConnectionResponse, error) {
+	id, hostId := req.GetId(), req.GetHostId()
+	if !handlers.ValidId(target.TcpTargetPrefix, id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	if hostId != "" && host.SubtypeFromId(hostId) == host.UnknownSubtype {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"host_id": "Incorrectly formatted identifier."})
+	}
+	authResults := s.authResult(ctx, id, action.TestConnection)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+
+	hostIds, endpoints, err := s.hostEndpoints(ctx, id, hostId)
+	if err != nil {
+		return nil, err
+	}
+
+	serversRepo, err := s.serversRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	workers, err := serversRepo.ListServers(ctx, servers.ServerTypeWorker)
+	if err != nil {
+		return nil, err
+	}
+	if len(workers) == 0 {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.FailedPrecondition, "No workers are available to test the connection.")
+	}
+	workerStates, err := serversRepo.ListWorkerStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.workerSelector.SelectWorkers(workers, workerStates)
+	workerId := workers[0].PrivateId
+
+	checks, err := serversRepo.CreateConnectionChecks(ctx, id, workerId, endpoints, req.GetTls())
+	if err != nil {
+		return nil, err
+	}
+	checkIds := make([]string, 0, len(checks))
+	for _, c := range checks {
+		checkIds = append(checkIds, c.CheckId)
+	}
+	defer func() {
+		// The request may be canceled, so use a context of its own. Checks
+		// which fail to be deleted are cleaned up by the controller later.
+		_, _ = serversRepo.DeleteConnectionChecks(context.Background(), checkIds)
+	}()
+
+	completed, err := waitForConnectionChecks(ctx, serversRepo, checkIds)
+	if err != nil {
+		return nil, err
+	}
+	ret := &pb.ConnectionTest{TargetId: id, WorkerId: workerId}
+	for i, c := range checks {
+		result := &pb.ConnectionTestResult{
+			HostId:   hostIds[i],
+			Endpoint: c.Endpoint,
+			Error:    "The worker did not report a result in time.",
+		}
+		if cc, ok := completed[c.CheckId]; ok {
+			result.Reachable, result.LatencyMs, result.Error = cc.Reachable, uint32(cc.LatencyMs), cc.Error
+		}
+		ret.Results = append(ret.Results, result)
+	}
+	return &pbs.TestTargetConnectionResponse{Item: ret}, nil
+}
+
+// hostEndpoints returns the ids and endpoints of the hosts of the target, or
+// only of hostId if set.
+func (s Service) hostEndpoints(ctx context.Context, id, hostId string) ([]string, []string, error) {
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, nil, err
+	}
+	t, hostSets, err := repo.LookupTarget(ctx, id)
+	if err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return nil, nil, handlers.NotFoundErrorf("Target %q not found.", id)
+		}
+		return nil, nil, err
+	}
+	if t == nil {
+		return nil, nil, handlers.NotFoundErrorf("Target %q not found.", id)
+	}
+	staticHostRepo, err := s.staticHostRepoFn()
+	if err != nil {
+		return nil, nil, err
+	}
+	var hostIds, endpoints []string
+	seen := map[string]bool{}
+	for _, hs := range hostSets {
+		switch host.SubtypeFromId(hs.PublicId) {
+		case host.StaticSubtype:
+			_, hosts, err := staticHostRepo.LookupSet(ctx, hs.PublicId)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, h := range hosts {
+				if seen[h.PublicId] || (hostId != "" && h.PublicId != hostId) {
+					continue
+				}
+				seen[h.PublicId] = true
+				hostIds = append(hostIds, h.PublicId)
+				endpoints = append(endpoints, endpoint.UrlHost(h.Address, t.GetDefaultPort()))
+			}
+		}
+	}
+	switch {
+	case hostId != "" && len(hostIds) == 0:
+		return nil, nil, handlers.InvalidArgumentErrorf("Errors in provided fields.", map[string]string{"host_id": "The requested host id is not available."})
+	case len(hostIds) == 0:
+		return nil, nil, handlers.NotFoundErrorf("No hosts found from available target host sets.")
+	case len(hostIds) > maxConnectionTestHosts:
+		return nil, nil, handlers.InvalidArgumentErrorf("Errors in provided fields.", map[string]string{"host_id": fmt.Sprintf("The target has more than %d hosts; a host id must be provided.", maxConnectionTestHosts)})
+	}
+	return hostIds, endpoints, nil
+}
+
+// waitForConnectionChecks waits for the checks to complete, until the
+// ConnectionTestTimeout, and returns the completed checks by id.
+func waitForConnectionChecks(ctx context.Context, repo *servers.Repository, checkIds []string) (map[string]*servers.ConnectionCheck, error) {
+	ctx, cancel := context.WithTimeout(ctx, ConnectionTestTimeout)
+	defer cancel()
+	ticker := time.NewTicker(connectionTestPollInterval)
+	defer ticker.Stop()
+	completed := make(map[string]*servers.ConnectionCheck, len(checkIds))
+	for {
+		checks, err := repo.LookupConnectionChecks(ctx, checkIds)
+		if err != nil {
+			if ctx.Err() != nil {
+				return completed, nil
+			}
+			return nil, err
+		}
+		for _, c := range checks {
+			if c.Complete() {
+				completed[c.CheckId] = c
+			}
+		}
+		if len(completed) == len(checkIds) {
+			return completed, nil
+		}
+		select {
+		case <-ctx.Done():
+			return completed, nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package targets
+
+import (
+	"context"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/action"
+)
+
+// GetTargetUserNameTemplate returns the user name template of the target.
+func (s Service) GetTargetUserNameTemplate(ctx context.Context, req *pbs.GetTargetUserNameTemplateRequest) (*pbs.GetTargetUserNameTemplateResponse, error) {
+	id := req.GetId()
+	if !handlers.ValidId(target.TcpTargetPrefix, id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	authResults := s.authResult(ctx, id, action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	ut, err := repo.LookupUserNameTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetTargetUserNameTemplateResponse{
+		Item: &pb.UserNameTemplate{
+			TargetId: id,
+			Template: ut.Template,
+		},
+	}, nil
+}
+
+// SetTargetUserNameTemplate sets the user name template of the target. It
+// applies to sessions looked up by workers afterwards.
+func (s Service) SetTargetUserNameTemplate(ctx context.Context, req *pbs.SetTargetUserNameTemplateRequest) (*pbs.SetTargetUserNameTemplateResponse, error) {
+	id, tmpl := req.GetId(), req.GetTemplate()
+	if !handlers.ValidId(target.TcpTargetPrefix, id) {
+		return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"id": "Improperly formatted identifier."})
+	}
+	if tmpl != "" {
+		if err := target.CheckUserNameTemplate(tmpl); err != nil {
+			return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"template": err.Error()})
+		}
+	}
+	authResults := s.authResult(ctx, id, action.Update)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.SetUserNameTemplate(ctx, id, tmpl); err != nil {
+		return nil, err
+	}
+	return &pbs.SetTargetUserNameTemplateResponse{
+		Item: &pb.UserNameTemplate{
+			TargetId: id,
+			Template: tmpl,
+		},
+	}, nil
+}
@@ -0,0 +1,62 @@
+package users
+
+import (
+	"context"
+
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/users"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListInactiveUsers returns the users of the scope which have not logged in
+// for the number of days, least recently active first. If days is 0 the
+// maximum inactive days of the scope's inactivity policy is used.
+func (s Service) ListInactiveUsers(ctx context.Context, req *pbs.ListInactiveUsersRequest) (*pbs.ListInactiveUsersResponse, error) {
+	scopeId, days := req.GetScopeId(), req.GetDays()
+	if !handlers.ValidId(scope.Org.Prefix(), scopeId) && scopeId != scope.Global.String() {
+		return nil, handlers.InvalidArgumentErrorf("Improperly formatted identifier.", map[string]string{"scope_id": "Must be 'global' or a valid org scope id when listing."})
+	}
+	authResults := s.authResult(ctx, scopeId, action.List)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	if days == 0 {
+		p, err := repo.LookupInactivityPolicy(ctx, scopeId)
+		if err != nil {
+			return nil, err
+		}
+		if p.MaxInactiveDays == 0 {
+			return nil, handlers.InvalidArgumentErrorf("Error in provided request.", map[string]string{"days": "Must be provided when the scope has no inactivity policy."})
+		}
+		days = p.MaxInactiveDays
+	}
+	ul, err := repo.ListInactiveUsers(ctx, scopeId, days)
+	if err != nil {
+		return nil, err
+	}
+	out := &pbs.ListInactiveUsersResponse{
+		ScopeId: scopeId,
+		Days:    days,
+		Items:   make([]*pb.InactiveUser, 0, len(ul)),
+	}
+	for _, u := range ul {
+		item := &pb.InactiveUser{
+			Id:          u.UserId,
+			Name:        u.Name,
+			CreatedTime: timestamppb.New(u.CreateTime),
+			Disabled:    u.Disabled,
+		}
+		if u.LastLoginTime != nil {
+			item.LastLoginTime = timestamppb.New(*u.LastLoginTime)
+		}
+		out.Items = append(out.Items, item)
+	}
+	return out, nil
+}
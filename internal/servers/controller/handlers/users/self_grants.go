@@ -0,0 +1,68 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/users"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/types/action"
+)
+
+// GetSelfGrants returns the grants which apply to the caller, including the
+// grants given to the anonymous and authenticated users, so end users can see
+// their effective permissions without needing grants to read roles. If the
+// caller's token was derived by an exchange only the grants it keeps are
+// returned.
+func (s Service) GetSelfGrants(ctx context.Context, req *pbs.GetSelfGrantsRequest) (*pbs.GetSelfGrantsResponse, error) {
+	at, att, err := auth.LookupSelfToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userId, accountId := at.GetIamUserId(), at.GetAuthAccountId()
+	authResults := s.authResult(ctx, userId, action.Read, auth.WithSelf(true))
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	pairs, err := repo.GrantsForUser(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get grants for user: %w", err)
+	}
+	if pairs, err = att.Filter(pairs); err != nil {
+		return nil, fmt.Errorf("unable to filter grants of derived token: %w", err)
+	}
+	out := &pbs.GetSelfGrantsResponse{
+		UserId: userId,
+		Grants: make([]*pb.Grant, 0, len(pairs)),
+	}
+	for _, p := range pairs {
+		parsed, err := perms.Parse(
+			p.ScopeId,
+			p.Grant,
+			perms.WithUserId(userId),
+			perms.WithAccountId(accountId),
+			perms.WithSkipFinalValidation(true))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse grant %q: %w", p.Grant, err)
+		}
+		out.Grants = append(out.Grants, &pb.Grant{
+			ScopeId:  p.ScopeId,
+			Grant:    p.Grant,
+			Resolved: parsed.CanonicalString(),
+		})
+	}
+	sort.Slice(out.Grants, func(i, j int) bool {
+		if out.Grants[i].ScopeId != out.Grants[j].ScopeId {
+			return out.Grants[i].ScopeId < out.Grants[j].ScopeId
+		}
+		return out.Grants[i].Grant < out.Grants[j].Grant
+	})
+	return out, nil
+}
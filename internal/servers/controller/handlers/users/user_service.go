@@ -18,6 +18,7 @@ import (
 	"github.com/hashicorp/boundary/internal/types/scope"
 	"github.com/hashicorp/boundary/sdk/strutil"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -32,19 +33,33 @@ func init() {
 	}
 }
 
+// defaultDisabledReason is the reason recorded for disabling a user when none
+// is given.
+const defaultDisabledReason = "disabled by an administrator"
+
 // Service handles request as described by the pbs.UserServiceServer interface.
 type Service struct {
 	pbs.UnimplementedUserServiceServer
 
-	repoFn common.IamRepoFactory
+	repoFn           common.IamRepoProvider
+	annotationRepoFn common.AnnotationRepoFactory
 }
 
 // NewService returns a user service which handles user related requests to boundary.
-func NewService(repo common.IamRepoFactory) (Service, error) {
+// Supported options: handlers.WithAnnotations.
+func NewService(repo common.IamRepoFactory, opt ...handlers.Option) (Service, error) {
+	return NewServiceWithRepos(repo.Provider(), opt...)
+}
+
+// NewServiceWithRepos returns a user service using the iam repositories of the
+// provider, which need not be the database backed ones. It takes the same
+// options as NewService.
+func NewServiceWithRepos(repo common.IamRepoProvider, opt ...handlers.Option) (Service, error) {
 	if repo == nil {
 		return Service{}, fmt.Errorf("nil iam repository provided")
 	}
-	return Service{repoFn: repo}, nil
+	opts := handlers.GetOpts(opt...)
+	return Service{repoFn: repo, annotationRepoFn: opts.WithAnnotations}, nil
 }
 
 var _ pbs.UserServiceServer = Service{}
@@ -62,18 +77,38 @@ func (s Service) ListUsers(ctx context.Context, req *pbs.ListUsersRequest) (*pbs
 	if err != nil {
 		return nil, err
 	}
+	ids := make([]string, 0, len(ul))
+	for _, item := range ul {
+		ids = append(ids, item.GetId())
+	}
+	annotations, err := handlers.ListAnnotations(ctx, s.annotationRepoFn, ids)
+	if err != nil {
+		return nil, err
+	}
 	for _, item := range ul {
 		item.Scope = authResults.Scope
+		item.Annotations = annotations[item.GetId()]
 	}
 	return &pbs.ListUsersResponse{Items: ul}, nil
 }
 
-// GetUsers implements the interface pbs.UserServiceServer.
+// GetUsers implements the interface pbs.UserServiceServer. The id may be
+// auth.SelfId to read the caller's own user, which is allowed without a grant
+// for the read action.
 func (s Service) GetUser(ctx context.Context, req *pbs.GetUserRequest) (*pbs.GetUserResponse, error) {
+	var authOpts []auth.Option
+	if req.GetId() == auth.SelfId {
+		userId, _, err := auth.LookupSelf(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req = &pbs.GetUserRequest{Id: userId}
+		authOpts = append(authOpts, auth.WithSelf(true))
+	}
 	if err := validateGetRequest(req); err != nil {
 		return nil, err
 	}
-	authResults := s.authResult(ctx, req.GetId(), action.Read)
+	authResults := s.authResult(ctx, req.GetId(), action.Read, authOpts...)
 	if authResults.Error != nil {
 		return nil, authResults.Error
 	}
@@ -81,6 +116,9 @@ func (s Service) GetUser(ctx context.Context, req *pbs.GetUserRequest) (*pbs.Get
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.GetUserResponse{Item: u}, nil
 }
@@ -115,6 +153,9 @@ func (s Service) UpdateUser(ctx context.Context, req *pbs.UpdateUserRequest) (*p
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.UpdateAnnotations(ctx, s.annotationRepoFn, resource.User, req.GetId(), req.GetItem().GetAnnotations(), req.GetUpdateMask().GetPaths()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.UpdateUserResponse{Item: u}, nil
 }
@@ -148,6 +189,9 @@ func (s Service) AddUserAccounts(ctx context.Context, req *pbs.AddUserAccountsRe
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.AddUserAccountsResponse{Item: u}, nil
 }
@@ -165,6 +209,9 @@ func (s Service) SetUserAccounts(ctx context.Context, req *pbs.SetUserAccountsRe
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.SetUserAccountsResponse{Item: u}, nil
 }
@@ -182,6 +229,9 @@ func (s Service) RemoveUserAccounts(ctx context.Context, req *pbs.RemoveUserAcco
 	if err != nil {
 		return nil, err
 	}
+	if u.Annotations, err = handlers.LookupAnnotations(ctx, s.annotationRepoFn, req.GetId()); err != nil {
+		return nil, err
+	}
 	u.Scope = authResults.Scope
 	return &pbs.RemoveUserAccountsResponse{Item: u}, nil
 }
@@ -201,7 +251,11 @@ func (s Service) getFromRepo(ctx context.Context, id string) (*pb.User, error) {
 	if u == nil {
 		return nil, handlers.NotFoundErrorf("User %q doesn't exist.", id)
 	}
-	return toProto(u, accts), nil
+	d, logins, err := lookupUserStatus(ctx, repo, id)
+	if err != nil {
+		return nil, err
+	}
+	return toProto(u, accts, d, logins), nil
 }
 
 func (s Service) createInRepo(ctx context.Context, orgId string, item *pb.User) (*pb.User, error) {
@@ -227,7 +281,7 @@ func (s Service) createInRepo(ctx context.Context, orgId string, item *pb.User)
 	if out == nil {
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to create user but no error returned from repository.")
 	}
-	return toProto(out, nil), nil
+	return toProto(out, nil, nil, nil), nil
 }
 
 func (s Service) updateInRepo(ctx context.Context, orgId, id string, mask []string, item *pb.User) (*pb.User, error) {
@@ -245,21 +299,55 @@ func (s Service) updateInRepo(ctx context.Context, orgId, id string, mask []stri
 	}
 	u.PublicId = id
 	dbMask := maskManager.Translate(mask)
-	if len(dbMask) == 0 {
+	// disabled and annotations are not stored with the user, so they are not
+	// in the db mask
+	updateDisabled := handlers.MaskContains(mask, "disabled")
+	if len(dbMask) == 0 && !updateDisabled && len(handlers.AnnotationsMask(mask)) == 0 {
 		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid fields provided in the update mask."})
 	}
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	out, accts, rowsUpdated, err := repo.UpdateUser(ctx, u, version, dbMask)
-	if err != nil {
-		return nil, fmt.Errorf("unable to update user: %w", err)
+	var out *iam.User
+	var accts []string
+	if len(dbMask) > 0 {
+		var rowsUpdated int
+		out, accts, rowsUpdated, err = repo.UpdateUser(ctx, u, version, dbMask)
+		if err != nil {
+			return nil, fmt.Errorf("unable to update user: %w", err)
+		}
+		if rowsUpdated == 0 {
+			return nil, handlers.NotFoundErrorf("User %q doesn't exist or incorrect version provided.", id)
+		}
+	} else {
+		out, accts, err = repo.LookupUser(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil || out.GetVersion() != version {
+			return nil, handlers.NotFoundErrorf("User %q doesn't exist or incorrect version provided.", id)
+		}
 	}
-	if rowsUpdated == 0 {
-		return nil, handlers.NotFoundErrorf("User %q doesn't exist or incorrect version provided.", id)
+	if updateDisabled {
+		if item.GetDisabled().GetValue() {
+			reason := item.GetDisabledReason().GetValue()
+			if reason == "" {
+				reason = defaultDisabledReason
+			}
+			err = repo.DisableUser(ctx, id, reason)
+		} else {
+			err = repo.EnableUser(ctx, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	d, logins, err := lookupUserStatus(ctx, repo, id)
+	if err != nil {
+		return nil, err
 	}
-	return toProto(out, accts), nil
+	return toProto(out, accts, d, logins), nil
 }
 
 func (s Service) deleteFromRepo(ctx context.Context, id string) (bool, error) {
@@ -286,9 +374,25 @@ func (s Service) listFromRepo(ctx context.Context, orgId string) ([]*pb.User, er
 	if err != nil {
 		return nil, err
 	}
+	dl, err := repo.ListDisabledUsers(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+	disabled := make(map[string]*iam.UserDisabled, len(dl))
+	for _, d := range dl {
+		disabled[d.IamUserId] = d
+	}
+	ll, err := repo.ListUserLogins(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+	logins := make(map[string]*iam.UserLogins, len(ll))
+	for _, l := range ll {
+		logins[l.UserId] = l
+	}
 	var outUl []*pb.User
 	for _, u := range ul {
-		outUl = append(outUl, toProto(u, nil))
+		outUl = append(outUl, toProto(u, nil, disabled[u.GetPublicId()], logins[u.GetPublicId()]))
 	}
 	return outUl, nil
 }
@@ -309,7 +413,11 @@ func (s Service) addInRepo(ctx context.Context, userId string, accountIds []stri
 	if out == nil {
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to lookup user after adding accounts to it.")
 	}
-	return toProto(out, accts), nil
+	d, logins, err := lookupUserStatus(ctx, repo, userId)
+	if err != nil {
+		return nil, err
+	}
+	return toProto(out, accts, d, logins), nil
 }
 
 func (s Service) setInRepo(ctx context.Context, userId string, accountIds []string, version uint32) (*pb.User, error) {
@@ -328,7 +436,11 @@ func (s Service) setInRepo(ctx context.Context, userId string, accountIds []stri
 	if out == nil {
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to lookup user after setting accounts for it.")
 	}
-	return toProto(out, accts), nil
+	d, logins, err := lookupUserStatus(ctx, repo, userId)
+	if err != nil {
+		return nil, err
+	}
+	return toProto(out, accts, d, logins), nil
 }
 
 func (s Service) removeInRepo(ctx context.Context, userId string, accountIds []string, version uint32) (*pb.User, error) {
@@ -347,10 +459,14 @@ func (s Service) removeInRepo(ctx context.Context, userId string, accountIds []s
 	if out == nil {
 		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to lookup user after removing accounts from it.")
 	}
-	return toProto(out, accts), nil
+	d, logins, err := lookupUserStatus(ctx, repo, userId)
+	if err != nil {
+		return nil, err
+	}
+	return toProto(out, accts, d, logins), nil
 }
 
-func (s Service) authResult(ctx context.Context, id string, a action.Type) auth.VerifyResults {
+func (s Service) authResult(ctx context.Context, id string, a action.Type, opt ...auth.Option) auth.VerifyResults {
 	res := auth.VerifyResults{}
 	repo, err := s.repoFn()
 	if err != nil {
@@ -386,10 +502,27 @@ func (s Service) authResult(ctx context.Context, id string, a action.Type) auth.
 		opts = append(opts, auth.WithId(id))
 	}
 	opts = append(opts, auth.WithScopeId(parentId))
+	opts = append(opts, opt...)
 	return auth.Verify(ctx, opts...)
 }
 
-func toProto(in *iam.User, accts []string) *pb.User {
+// lookupUserStatus returns why the user is disabled, or nil if it is not, and
+// its last logins.
+func lookupUserStatus(ctx context.Context, repo common.IamRepo, id string) (*iam.UserDisabled, *iam.UserLogins, error) {
+	d, err := repo.LookupUserDisabled(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	logins, err := repo.LookupUserLogins(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, logins, nil
+}
+
+// toProto returns the API user of in with the accounts, which is disabled if d
+// is set and has the last logins of logins if it is set.
+func toProto(in *iam.User, accts []string, d *iam.UserDisabled, logins *iam.UserLogins) *pb.User {
 	out := pb.User{
 		Id:          in.GetPublicId(),
 		ScopeId:     in.GetScopeId(),
@@ -404,12 +537,24 @@ func toProto(in *iam.User, accts []string) *pb.User {
 	if in.GetName() != "" {
 		out.Name = &wrapperspb.StringValue{Value: in.GetName()}
 	}
+	if d != nil {
+		out.Disabled = &wrapperspb.BoolValue{Value: true}
+		out.DisabledReason = &wrapperspb.StringValue{Value: d.Reason}
+		out.DisabledTime = d.CreateTime.GetTimestamp()
+	}
+	if t := logins.GetLastLoginTime(); t != nil {
+		out.LastLoginTime = timestamppb.New(*t)
+	}
 	for _, a := range accts {
-		out.Accounts = append(out.Accounts, &pb.Account{
+		acct := &pb.Account{
 			Id: a,
 			// TODO: Update this when an account can be associated with a user from a different scope.
 			ScopeId: in.GetScopeId(),
-		})
+		}
+		if t, ok := logins.GetAccounts()[a]; ok {
+			acct.LastLoginTime = timestamppb.New(t)
+		}
+		out.Accounts = append(out.Accounts, acct)
 	}
 	return &out
 }
@@ -430,12 +575,30 @@ func validateCreateRequest(req *pbs.CreateUserRequest) error {
 			scope.Global.String() != req.GetItem().GetScopeId() {
 			badFields["scope_id"] = "Must be 'global' or a valid org scope id."
 		}
+		if req.GetItem().GetDisabled() != nil || req.GetItem().GetDisabledReason() != nil || req.GetItem().GetDisabledTime() != nil {
+			badFields["disabled"] = "Users can only be disabled once created."
+		}
+		if req.GetItem().GetLastLoginTime() != nil {
+			badFields["last_login_time"] = "This is a read only field."
+		}
 		return badFields
 	})
 }
 
 func validateUpdateRequest(req *pbs.UpdateUserRequest) error {
-	return handlers.ValidateUpdateRequest(iam.UserPrefix, req, req.GetItem(), handlers.NoopValidatorFn)
+	return handlers.ValidateUpdateRequest(iam.UserPrefix, req, req.GetItem(), func() map[string]string {
+		badFields := map[string]string{}
+		if req.GetItem().GetDisabledReason() != nil && !req.GetItem().GetDisabled().GetValue() {
+			badFields["disabled_reason"] = "Can only be set when disabling the user."
+		}
+		if req.GetItem().GetDisabledTime() != nil {
+			badFields["disabled_time"] = "This is a read only field."
+		}
+		if req.GetItem().GetLastLoginTime() != nil {
+			badFields["last_login_time"] = "This is a read only field."
+		}
+		return badFields
+	})
 }
 
 func validateDeleteRequest(req *pbs.DeleteUserRequest) error {
@@ -16,6 +16,8 @@ import (
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/users"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
 	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/iam/store"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/users"
 	"github.com/hashicorp/boundary/internal/types/scope"
@@ -108,6 +110,111 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func TestGet_LastLogin(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrap := db.TestWrapper(t)
+	repo := iam.TestRepo(t, conn, wrap)
+	repoFn := func() (*iam.Repository, error) {
+		return repo, nil
+	}
+	ctx := context.Background()
+	o, _ := iam.TestScopes(t, repo)
+	u := iam.TestUser(t, repo, o.GetPublicId())
+	am := password.TestAuthMethods(t, conn, o.GetPublicId(), 1)[0]
+	accts := password.TestAccounts(t, conn, am.GetPublicId(), 2)
+	_, err := repo.AddUserAccounts(ctx, u.GetPublicId(), u.GetVersion(), []string{accts[0].GetPublicId(), accts[1].GetPublicId()})
+	require.NoError(err)
+	_, err = rw.Exec(ctx,
+		"insert into iam_user_login (iam_user_id, auth_account_id, last_login_time) values (?, ?, now() - interval '1 day')",
+		[]interface{}{u.GetPublicId(), accts[0].GetPublicId()})
+	require.NoError(err)
+
+	s, err := users.NewService(repoFn)
+	require.NoError(err)
+	authCtx := auth.DisabledAuthTestContext(auth.WithScopeId(o.GetPublicId()))
+	got, err := s.GetUser(authCtx, &pbs.GetUserRequest{Id: u.GetPublicId()})
+	require.NoError(err)
+	require.NotNil(got.GetItem().GetLastLoginTime())
+	require.Len(got.GetItem().GetAccounts(), 2)
+	for _, a := range got.GetItem().GetAccounts() {
+		if a.GetId() == accts[0].GetPublicId() {
+			assert.True(proto.Equal(got.GetItem().GetLastLoginTime(), a.GetLastLoginTime()))
+			continue
+		}
+		assert.Nil(a.GetLastLoginTime(), "the user never logged in with %s", a.GetId())
+	}
+
+	list, err := s.ListUsers(authCtx, &pbs.ListUsersRequest{ScopeId: o.GetPublicId()})
+	require.NoError(err)
+	var found bool
+	for _, item := range list.GetItems() {
+		if item.GetId() != u.GetPublicId() {
+			assert.Nil(item.GetLastLoginTime())
+			continue
+		}
+		found = true
+		assert.True(proto.Equal(got.GetItem().GetLastLoginTime(), item.GetLastLoginTime()))
+	}
+	assert.True(found)
+
+	_, err = s.UpdateUser(authCtx, &pbs.UpdateUserRequest{
+		Id:         u.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"name"}},
+		Item:       &pb.User{Version: got.GetItem().GetVersion(), Name: wrapperspb.String("name"), LastLoginTime: got.GetItem().GetLastLoginTime()},
+	})
+	assert.Truef(errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), "got error %v, wanted invalid argument", err)
+}
+
+func TestGet_Self(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	u, repoFn := createDefaultUserAndRepo(t)
+	s, err := users.NewService(repoFn)
+	require.NoError(err, "Couldn't create new user service.")
+
+	got, err := s.GetUser(auth.DisabledAuthTestContext(auth.WithScopeId(u.GetScopeId()), auth.WithUserId(u.GetPublicId())), &pbs.GetUserRequest{Id: auth.SelfId})
+	require.NoError(err)
+	assert.Equal(u.GetPublicId(), got.GetItem().GetId())
+
+	_, err = s.GetUser(auth.DisabledAuthTestContext(auth.WithScopeId(u.GetScopeId())), &pbs.GetUserRequest{Id: auth.SelfId})
+	require.Error(err)
+	assert.True(errors.Is(err, handlers.ApiErrorWithCode(codes.Unauthenticated)), "got error %v, wanted unauthenticated", err)
+}
+
+func TestGetSelfGrants(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	wrap := db.TestWrapper(t)
+	repo := iam.TestRepo(t, conn, wrap)
+	repoFn := func() (*iam.Repository, error) {
+		return repo, nil
+	}
+	o, _ := iam.TestScopes(t, repo)
+	u := iam.TestUser(t, repo, o.GetPublicId())
+	r := iam.TestRole(t, conn, o.GetPublicId())
+	iam.TestUserRole(t, conn, r.GetPublicId(), u.GetPublicId())
+	iam.TestRoleGrant(t, conn, r.GetPublicId(), "id={{user.id}};actions=read")
+
+	s, err := users.NewService(repoFn)
+	require.NoError(err, "Couldn't create new user service.")
+	got, err := s.GetSelfGrants(auth.DisabledAuthTestContext(auth.WithScopeId(o.GetPublicId()), auth.WithUserId(u.GetPublicId())), &pbs.GetSelfGrantsRequest{})
+	require.NoError(err)
+	assert.Equal(u.GetPublicId(), got.GetUserId())
+
+	var found bool
+	for _, g := range got.GetGrants() {
+		if g.GetScopeId() == o.GetPublicId() && g.GetGrant() == "id={{user.id}};actions=read" {
+			found = true
+			assert.Equal(fmt.Sprintf("id=%s;actions=read", u.GetPublicId()), g.GetResolved())
+		}
+	}
+	assert.True(found, "grant not found in %v", got.GetGrants())
+
+	_, err = s.GetSelfGrants(auth.DisabledAuthTestContext(auth.WithScopeId(o.GetPublicId())), &pbs.GetSelfGrantsRequest{})
+	require.Error(err)
+}
+
 func TestList(t *testing.T) {
 	conn, _ := db.TestSetup(t, "postgres")
 	wrap := db.TestWrapper(t)
@@ -606,6 +713,49 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdate_Disabled(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	u, repoFn := createDefaultUserAndRepo(t)
+	tested, err := users.NewService(repoFn)
+	require.NoError(err)
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId(u.GetScopeId()))
+
+	_, err = tested.UpdateUser(ctx, &pbs.UpdateUserRequest{
+		Id:         u.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"disabled", "disabled_reason"}},
+		Item:       &pb.User{Version: u.GetVersion(), DisabledReason: wrapperspb.String("reason")},
+	})
+	assert.Truef(errors.Is(err, handlers.ApiErrorWithCode(codes.InvalidArgument)), "got error %v, wanted invalid argument", err)
+
+	got, err := tested.UpdateUser(ctx, &pbs.UpdateUserRequest{
+		Id:         u.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"disabled", "disabled_reason"}},
+		Item: &pb.User{
+			Version:        u.GetVersion(),
+			Disabled:       wrapperspb.Bool(true),
+			DisabledReason: wrapperspb.String("left the company"),
+		},
+	})
+	require.NoError(err)
+	assert.True(got.GetItem().GetDisabled().GetValue())
+	assert.Equal("left the company", got.GetItem().GetDisabledReason().GetValue())
+	assert.NotNil(got.GetItem().GetDisabledTime())
+	assert.Equal(u.GetVersion(), got.GetItem().GetVersion(), "disabling does not change the user")
+
+	read, err := tested.GetUser(ctx, &pbs.GetUserRequest{Id: u.GetPublicId()})
+	require.NoError(err)
+	assert.True(read.GetItem().GetDisabled().GetValue())
+
+	got, err = tested.UpdateUser(ctx, &pbs.UpdateUserRequest{
+		Id:         u.GetPublicId(),
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"disabled"}},
+		Item:       &pb.User{Version: u.GetVersion()},
+	})
+	require.NoError(err)
+	assert.Nil(got.GetItem().GetDisabled())
+	assert.Nil(got.GetItem().GetDisabledTime())
+}
+
 func TestAddAccount(t *testing.T) {
 	conn, _ := db.TestSetup(t, "postgres")
 	wrap := db.TestWrapper(t)
@@ -983,3 +1133,44 @@ func TestRemoveAccount(t *testing.T) {
 		})
 	}
 }
+
+// mockIamRepo serves the users it holds, panicking on the methods of the
+// repository it doesn't implement.
+type mockIamRepo struct {
+	common.IamRepo
+	users map[string]*iam.User
+}
+
+func (m *mockIamRepo) LookupUser(_ context.Context, id string, _ ...iam.Option) (*iam.User, []string, error) {
+	return m.users[id], nil, nil
+}
+
+func (m *mockIamRepo) LookupUserDisabled(context.Context, string, ...iam.Option) (*iam.UserDisabled, error) {
+	return nil, nil
+}
+
+func (m *mockIamRepo) LookupUserLogins(context.Context, string, ...iam.Option) (*iam.UserLogins, error) {
+	return nil, nil
+}
+
+func TestNewServiceWithRepos(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	repo := &mockIamRepo{users: map[string]*iam.User{
+		"u_1234567890": {User: &store.User{PublicId: "u_1234567890", ScopeId: "o_1234567890", Name: "alice"}},
+	}}
+	repoFn := func() (common.IamRepo, error) { return repo, nil }
+
+	_, err := users.NewServiceWithRepos(nil)
+	assert.Error(err)
+	s, err := users.NewServiceWithRepos(repoFn)
+	require.NoError(err)
+
+	ctx := auth.DisabledAuthTestContext(auth.WithScopeId("o_1234567890"))
+	got, err := s.GetUser(ctx, &pbs.GetUserRequest{Id: "u_1234567890"})
+	require.NoError(err)
+	assert.Equal("u_1234567890", got.GetItem().GetId())
+	assert.Equal("alice", got.GetItem().GetName().GetValue())
+
+	_, err = s.GetUser(ctx, &pbs.GetUserRequest{Id: "u_0987654321"})
+	assert.True(errors.Is(err, handlers.NotFoundError()))
+}
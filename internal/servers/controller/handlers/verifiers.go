@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 	"unicode"
@@ -61,6 +62,9 @@ func ValidateCreateRequest(i ApiResource, fn CustomValidatorFunc) error {
 	if i.GetVersion() != 0 {
 		badFields["version"] = "Cannot specify this field in a create request."
 	}
+	if a, ok := i.(annotatedResource); ok && len(a.GetAnnotations()) > 0 {
+		badFields["annotations"] = "Annotations can only be set once the resource is created."
+	}
 	for k, v := range fn() {
 		badFields[k] = v
 	}
@@ -170,6 +174,33 @@ func ValidateDeleteRequest(prefix string, r DeleteRequest, fn CustomValidatorFun
 	return nil
 }
 
+// MaxBatchGetIds is the most ids of resources which can be fetched with a
+// single batch get request.
+const MaxBatchGetIds = 100
+
+// ValidateBatchGetRequest returns an error if there are no ids, more than
+// MaxBatchGetIds or ids without the prefix.
+func ValidateBatchGetRequest(prefix string, ids []string) error {
+	badFields := map[string]string{}
+	switch {
+	case len(ids) == 0:
+		badFields["ids"] = "This field is required."
+	case len(ids) > MaxBatchGetIds:
+		badFields["ids"] = fmt.Sprintf("At most %d identifiers may be provided.", MaxBatchGetIds)
+	default:
+		for _, id := range ids {
+			if !ValidId(prefix, id) {
+				badFields["ids"] = fmt.Sprintf("Invalid formatted identifier %q.", id)
+				break
+			}
+		}
+	}
+	if len(badFields) > 0 {
+		return InvalidArgumentErrorf("Error in provided request.", badFields)
+	}
+	return nil
+}
+
 var reInvalidID = regexp.MustCompile("[^A-Za-z0-9]")
 
 func ValidId(prefix, id string) bool {
@@ -170,6 +170,50 @@ func TestValidateDeleteRequest(t *testing.T) {
 	}
 }
 
+func TestValidateBatchGetRequest(t *testing.T) {
+	tooMany := make([]string, MaxBatchGetIds+1)
+	for i := range tooMany {
+		tooMany[i] = "prefix_someidentifier"
+	}
+	cases := []struct {
+		name      string
+		ids       []string
+		badFields []string
+	}{
+		{
+			name: "valid",
+			ids:  []string{"prefix_someidentifier", "prefix_otheridentifier"},
+		},
+		{
+			name:      "no ids",
+			badFields: []string{"ids"},
+		},
+		{
+			name:      "too many ids",
+			ids:       tooMany,
+			badFields: []string{"ids"},
+		},
+		{
+			name:      "bad prefix",
+			ids:       []string{"prefix_someidentifier", "bad_someidentifier"},
+			badFields: []string{"ids"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateBatchGetRequest("prefix", tc.ids)
+			if len(tc.badFields) == 0 {
+				if !assert.NoError(t, err) {
+					errorIncludesFields(t, err, []string{})
+				}
+				return
+			}
+			errorIncludesFields(t, err, tc.badFields)
+		})
+	}
+}
+
 func TestValidateCreateRequest(t *testing.T) {
 	cases := []struct {
 		name      string
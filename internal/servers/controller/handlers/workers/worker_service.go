@@ -2,51 +2,195 @@ package workers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/session"
 	"github.com/hashicorp/boundary/internal/types/resource"
 	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+// ConnectionAuthorizer is called before authorizing each new connection to a
+// session. Returning false denies the connection.
+type ConnectionAuthorizer func(ctx context.Context, sessionId string) (bool, error)
+
+// BandwidthLimitLookup returns the bandwidth limits of a target in bytes per
+// second for each connection and each session, 0 being unlimited.
+type BandwidthLimitLookup func(ctx context.Context, targetId string) (connection, session uint64, err error)
+
+// ConnectionRateLimitLookup returns the connections each session of a target
+// may open per minute on a worker, 0 being unlimited.
+type ConnectionRateLimitLookup func(ctx context.Context, targetId string) (uint32, error)
+
+// PeerIdentity is the identity of the user of a session which workers record
+// with its connections and pass to the endpoint as set by Mode.
+type PeerIdentity struct {
+	// Mode is how the identity is passed to the endpoint, empty if it isn't.
+	Mode     string
+	UserName string
+	// InjectedUserName is the user name protocol handlers inject into the
+	// connections of the session, produced by the user name template of the
+	// target.
+	InjectedUserName string
+	// CredentialCheckoutId is the id of the checkout of the shared credential
+	// of the target by the session, if any.
+	CredentialCheckoutId string
+}
+
+// PeerIdentityLookup returns the identity of the user of a session.
+type PeerIdentityLookup func(ctx context.Context, sessionId, targetId, userId string) (*PeerIdentity, error)
+
+// WorkerAccessCheck is called with the name and tags of a worker on each of
+// its status updates. Returning false refuses the update, so the worker isn't
+// registered.
+type WorkerAccessCheck func(ctx context.Context, name string, tags map[string]string) bool
+
 type workerServiceServer struct {
 	pbs.UnimplementedServerCoordinationServiceServer
 	pbs.UnimplementedSessionServiceServer
 
-	logger        hclog.Logger
-	serversRepoFn common.ServersRepoFactory
-	sessionRepoFn common.SessionRepoFactory
-	updateTimes   *sync.Map
-	kms           *kms.Kms
+	logger         hclog.Logger
+	controllerName string
+	serversRepoFn  common.ServersRepoFactory
+	sessionRepoFn  common.SessionRepoFactory
+	updateTimes    *sync.Map
+	kms            *kms.Kms
+
+	connAuthorizer ConnectionAuthorizer
+	bandwidthLimit BandwidthLimitLookup
+	connRateLimit  ConnectionRateLimitLookup
+	peerIdentity   PeerIdentityLookup
+	workerAccess   WorkerAccessCheck
 }
 
 func NewWorkerServiceServer(
 	logger hclog.Logger,
+	controllerName string,
 	serversRepoFn common.ServersRepoFactory,
 	sessionRepoFn common.SessionRepoFactory,
 	updateTimes *sync.Map,
-	kms *kms.Kms) *workerServiceServer {
+	kms *kms.Kms,
+	connAuthorizer ConnectionAuthorizer,
+	bandwidthLimit BandwidthLimitLookup,
+	connRateLimit ConnectionRateLimitLookup,
+	peerIdentity PeerIdentityLookup,
+	workerAccess WorkerAccessCheck) *workerServiceServer {
 	return &workerServiceServer{
-		logger:        logger,
-		serversRepoFn: serversRepoFn,
-		sessionRepoFn: sessionRepoFn,
-		updateTimes:   updateTimes,
-		kms:           kms,
+		logger:         logger,
+		controllerName: controllerName,
+		serversRepoFn:  serversRepoFn,
+		sessionRepoFn:  sessionRepoFn,
+		updateTimes:    updateTimes,
+		kms:            kms,
+		connAuthorizer: connAuthorizer,
+		bandwidthLimit: bandwidthLimit,
+		connRateLimit:  connRateLimit,
+		peerIdentity:   peerIdentity,
+		workerAccess:   workerAccess,
 	}
 }
 
 var _ pbs.SessionServiceServer = &workerServiceServer{}
 var _ pbs.ServerCoordinationServiceServer = &workerServiceServer{}
 
+// workerState returns the state of a worker from the jobs in its status and
+// the throughput, tags, location and controller round trip time it sends as
+// metadata.
+func workerState(jobs []*pbs.JobStatus, md metadata.MD) *servers.WorkerState {
+	state := &servers.WorkerState{}
+	if v := md.Get(globals.WorkerBytesPerSecondMetadataKey); len(v) > 0 {
+		// An invalid value is ignored, as if the worker had no throughput
+		state.BytesPerSecond, _ = strconv.ParseUint(v[0], 10, 64)
+	}
+	for _, tag := range md.Get(globals.WorkerTagsMetadataKey) {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if state.Tags == nil {
+			state.Tags = map[string]string{}
+		}
+		state.Tags[kv[0]] = kv[1]
+	}
+	if v := md.Get(globals.WorkerRegionMetadataKey); len(v) > 0 {
+		state.Region = v[0]
+	}
+	if v := md.Get(globals.WorkerZoneMetadataKey); len(v) > 0 {
+		state.Zone = v[0]
+	}
+	if v := md.Get(globals.WorkerControllerRttMetadataKey); len(v) > 0 {
+		// An invalid value is ignored, as if the worker had not measured it
+		if rtt, err := strconv.ParseUint(v[0], 10, 32); err == nil {
+			state.ControllerRttMs = uint32(rtt)
+		}
+	}
+	for _, job := range jobs {
+		si := job.GetJob().GetSessionInfo()
+		if si == nil {
+			continue
+		}
+		switch si.GetStatus() {
+		case pbs.SESSIONSTATUS_SESSIONSTATUS_PENDING,
+			pbs.SESSIONSTATUS_SESSIONSTATUS_ACTIVE:
+			state.ActiveSessions++
+		}
+		for _, conn := range si.GetConnections() {
+			if conn.GetStatus() != pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CLOSED {
+				state.ActiveConnections++
+			}
+		}
+	}
+	return state
+}
+
+// exchangeConnectionChecks records the results of the connection checks the
+// worker sent and sends it the checks it must perform. Both are sent as
+// metadata since status messages have no fields for them.
+func (ws *workerServiceServer) exchangeConnectionChecks(ctx context.Context, repo *servers.Repository, workerName string, md metadata.MD) error {
+	for _, v := range md.Get(globals.ConnectionCheckResultsMetadataKey) {
+		var results []*servers.ConnectionCheck
+		if err := json.Unmarshal([]byte(v), &results); err != nil {
+			return fmt.Errorf("error unmarshaling connection check results: %w", err)
+		}
+		if err := repo.CompleteConnectionChecks(ctx, workerName, results); err != nil {
+			return err
+		}
+	}
+	checks, err := repo.DispatchConnectionChecks(ctx, workerName)
+	if err != nil {
+		return err
+	}
+	if len(checks) == 0 {
+		return nil
+	}
+	marshaled, err := json.Marshal(checks)
+	if err != nil {
+		return fmt.Errorf("error marshaling connection checks: %w", err)
+	}
+	return grpc.SetHeader(ctx, metadata.Pairs(globals.ConnectionChecksMetadataKey, string(marshaled)))
+}
+
 func (ws *workerServiceServer) Status(ctx context.Context, req *pbs.StatusRequest) (*pbs.StatusResponse, error) {
 	ws.logger.Trace("got status request from worker", "name", req.Worker.Name, "address", req.Worker.Address, "jobs", req.GetJobs())
+	md, _ := metadata.FromIncomingContext(ctx)
+	state := workerState(req.GetJobs(), md)
+	if ws.workerAccess != nil && !ws.workerAccess(ctx, req.Worker.GetName(), state.Tags) {
+		return &pbs.StatusResponse{}, status.Error(codes.PermissionDenied, "Worker is not allowed to register with this controller.")
+	}
 	ws.updateTimes.Store(req.Worker.Name, time.Now())
 	repo, err := ws.serversRepoFn()
 	if err != nil {
@@ -59,6 +203,14 @@ func (ws *workerServiceServer) Status(ctx context.Context, req *pbs.StatusReques
 		ws.logger.Error("error storing worker status", "error", err)
 		return &pbs.StatusResponse{}, status.Errorf(codes.Internal, "Error storing worker status: %v", err)
 	}
+	// Share the state of the worker with the other controllers. Failing to do
+	// so only affects worker selection, so it doesn't fail the status update.
+	if err := repo.UpsertWorkerState(ctx, req.Worker.Name, ws.controllerName, state); err != nil {
+		ws.logger.Error("error storing worker state", "error", err)
+	}
+	if err := ws.exchangeConnectionChecks(ctx, repo, req.Worker.Name, md); err != nil {
+		ws.logger.Error("error exchanging connection checks", "error", err)
+	}
 	ret := &pbs.StatusResponse{
 		Controllers: controllers,
 	}
@@ -180,6 +332,44 @@ func (ws *workerServiceServer) LookupSession(ctx context.Context, req *pbs.Looku
 		return nil, status.Errorf(codes.Internal, "Error deriving session key: %v", err)
 	}
 
+	if ws.bandwidthLimit != nil {
+		resp.ConnectionBytesPerSecond, resp.SessionBytesPerSecond, err = ws.bandwidthLimit(ctx, sessionInfo.TargetId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Error looking up bandwidth limits: %v", err)
+		}
+	}
+
+	// The response has no field for the connection rate limit of the target,
+	// so it is sent as header metadata
+	if ws.connRateLimit != nil {
+		perMinute, err := ws.connRateLimit(ctx, sessionInfo.TargetId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Error looking up connection rate limit: %v", err)
+		}
+		if err := grpc.SetHeader(ctx, metadata.Pairs(
+			globals.ConnectionRateLimitMetadataKey, strconv.FormatUint(uint64(perMinute), 10),
+		)); err != nil {
+			return nil, status.Errorf(codes.Internal, "Error sending connection rate limit: %v", err)
+		}
+	}
+
+	// Likewise for the identity of the user, which workers record with the
+	// connections of the session and may pass to the endpoint
+	if ws.peerIdentity != nil {
+		peer, err := ws.peerIdentity(ctx, sessionInfo.GetPublicId(), sessionInfo.TargetId, sessionInfo.UserId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Error looking up peer identity: %v", err)
+		}
+		if err := grpc.SetHeader(ctx, metadata.Pairs(
+			globals.PeerIdentityModeMetadataKey, peer.Mode,
+			globals.PeerUserNameMetadataKey, peer.UserName,
+			globals.PeerInjectedUserNameMetadataKey, peer.InjectedUserName,
+			globals.PeerCredentialCheckoutMetadataKey, peer.CredentialCheckoutId,
+		)); err != nil {
+			return nil, status.Errorf(codes.Internal, "Error sending peer identity: %v", err)
+		}
+	}
+
 	return resp, nil
 }
 
@@ -228,6 +418,17 @@ func (ws *workerServiceServer) AuthorizeConnection(ctx context.Context, req *pbs
 		return nil, status.Errorf(codes.Internal, "error getting session repo: %v", err)
 	}
 
+	if ws.connAuthorizer != nil {
+		allowed, err := ws.connAuthorizer(ctx, req.GetSessionId())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error checking connection authorization: %v", err)
+		}
+		if !allowed {
+			ws.logger.Info("connection not authorized", "session_id", req.GetSessionId())
+			return nil, status.Error(codes.PermissionDenied, "Connection not authorized.")
+		}
+	}
+
 	connectionInfo, connStates, authzSummary, err := sessRepo.AuthorizeConnection(ctx, req.GetSessionId())
 	if err != nil {
 		return nil, err
@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/servers"
+)
+
+// maxDeniedAddrs is the most addresses whose refused connections are counted
+// between two recordings of them, so a flood from many addresses can't exhaust
+// memory. Connections from other addresses are still refused but not counted.
+const maxDeniedAddrs = 1000
+
+// errListenerAccessDenied is returned for connections refused by the allow
+// and deny lists of a listener.
+var errListenerAccessDenied = errors.New("address not allowed to connect to listener")
+
+// accessList is the allow and deny lists of the listeners of a purpose.
+type accessList struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// permits returns true if ip is in none of the deny CIDRs and, if there are
+// allow CIDRs, in one of them.
+func (l *accessList) permits(ip net.IP) bool {
+	for _, n := range l.deny {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	if len(l.allow) == 0 {
+		return true
+	}
+	for _, n := range l.allow {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseListenerAccess returns the access lists of the configuration by
+// purpose.
+func parseListenerAccess(conf []*config.ListenerAccess) (map[string]*accessList, error) {
+	lists := make(map[string]*accessList, len(conf))
+	parse := func(cidrs []string) ([]*net.IPNet, error) {
+		nets := make([]*net.IPNet, 0, len(cidrs))
+		for _, c := range cidrs {
+			_, n, err := net.ParseCIDR(c)
+			if err != nil {
+				return nil, err
+			}
+			nets = append(nets, n)
+		}
+		return nets, nil
+	}
+	for _, la := range conf {
+		switch la.Purpose {
+		case "api", "cluster":
+		default:
+			return nil, fmt.Errorf("unknown listener access purpose %q", la.Purpose)
+		}
+		if _, ok := lists[la.Purpose]; ok {
+			return nil, fmt.Errorf("more than one listener access block with purpose %q", la.Purpose)
+		}
+		allow, err := parse(la.Allow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow list for %q listeners: %w", la.Purpose, err)
+		}
+		deny, err := parse(la.Deny)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deny list for %q listeners: %w", la.Purpose, err)
+		}
+		lists[la.Purpose] = &accessList{allow: allow, deny: deny}
+	}
+	return lists, nil
+}
+
+// deniedKey identifies the refused connections counted together.
+type deniedKey struct {
+	purpose string
+	addr    string
+}
+
+// listenerAccess checks the addresses of the connections to the listeners of
+// the controller against their allow and deny lists, and counts the refused
+// ones until they are recorded.
+type listenerAccess struct {
+	sync.RWMutex
+	lists map[string]*accessList
+
+	deniedLock sync.Mutex
+	denied     map[deniedKey]*servers.ListenerAccessDenial
+}
+
+func newListenerAccess(conf []*config.ListenerAccess) (*listenerAccess, error) {
+	lists, err := parseListenerAccess(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &listenerAccess{
+		lists:  lists,
+		denied: make(map[deniedKey]*servers.ListenerAccessDenial),
+	}, nil
+}
+
+// permits returns true if a connection from addr may use the listeners of the
+// purpose, and counts it otherwise. Addresses which aren't IP addresses, e.g.
+// of Unix sockets, are permitted.
+func (a *listenerAccess) permits(purpose string, addr net.Addr) bool {
+	if a == nil {
+		return true
+	}
+	ip := addrIP(addr)
+	if ip == nil {
+		return true
+	}
+
+	a.RLock()
+	l := a.lists[purpose]
+	a.RUnlock()
+	if l == nil || l.permits(ip) {
+		return true
+	}
+
+	a.deniedLock.Lock()
+	defer a.deniedLock.Unlock()
+	now := time.Now()
+	key := deniedKey{purpose: purpose, addr: ip.String()}
+	switch d, ok := a.denied[key]; {
+	case ok:
+		d.Count++
+		d.LastTime = now
+	case len(a.denied) < maxDeniedAddrs:
+		a.denied[key] = &servers.ListenerAccessDenial{
+			Purpose:    purpose,
+			RemoteAddr: key.addr,
+			Count:      1,
+			FirstTime:  now,
+			LastTime:   now,
+		}
+	}
+	return false
+}
+
+// addrIP returns the IP address of addr, or nil if it isn't an IP address,
+// e.g. of a Unix socket.
+func addrIP(addr net.Addr) net.IP {
+	switch addr := addr.(type) {
+	case nil:
+		return nil
+	case *net.TCPAddr:
+		return addr.IP
+	default:
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			return nil
+		}
+		return net.ParseIP(host)
+	}
+}
+
+// reload replaces the access lists with those of the configuration.
+func (a *listenerAccess) reload(conf []*config.ListenerAccess) error {
+	lists, err := parseListenerAccess(conf)
+	if err != nil {
+		return err
+	}
+	a.Lock()
+	a.lists = lists
+	a.Unlock()
+	return nil
+}
+
+// takeDenied returns the refused connections counted since it was last
+// called.
+func (a *listenerAccess) takeDenied() []*servers.ListenerAccessDenial {
+	if a == nil {
+		return nil
+	}
+	a.deniedLock.Lock()
+	defer a.deniedLock.Unlock()
+	if len(a.denied) == 0 {
+		return nil
+	}
+	ret := make([]*servers.ListenerAccessDenial, 0, len(a.denied))
+	for _, d := range a.denied {
+		ret = append(ret, d)
+	}
+	a.denied = make(map[deniedKey]*servers.ListenerAccessDenial)
+	return ret
+}
+
+// ReloadListenerAccess replaces the allow and deny lists of the listeners of
+// the controller with those of the configuration. The current lists are kept
+// if the configuration is invalid.
+func (c *Controller) ReloadListenerAccess(conf []*config.ListenerAccess) error {
+	if err := c.listenerAccess.reload(conf); err != nil {
+		return fmt.Errorf("error reloading listener access: %w", err)
+	}
+	c.logger.Info("listener access reloaded")
+	return nil
+}
+
+// recordListenerAccessDenials records the connections refused since it was
+// last called as events.
+func (c *Controller) recordListenerAccessDenials(ctx context.Context) error {
+	denials := c.listenerAccess.takeDenied()
+	if len(denials) == 0 {
+		return nil
+	}
+	for _, d := range denials {
+		d.Controller = c.conf.RawConfig.Controller.Name
+		c.logger.Warn("refused connections to listener", "purpose", d.Purpose, "remote_addr", d.RemoteAddr, "count", d.Count)
+	}
+	repo, err := c.ServersRepoFn()
+	if err != nil {
+		return err
+	}
+	return repo.RecordListenerAccessDenials(ctx, denials)
+}
+
+// wrapHandlerWithListenerAccess refuses the requests from addresses the
+// allow and deny lists of the API listeners don't permit, before anything else
+// is done with them.
+func wrapHandlerWithListenerAccess(h http.Handler, c *Controller) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil && !c.listenerAccess.permits("api", addr) {
+			w.Header().Set("Connection", "close")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenerAccess(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	a, err := newListenerAccess([]*config.ListenerAccess{
+		{Purpose: "api", Allow: []string{"10.0.0.0/8", "2001:db8::/32"}, Deny: []string{"10.1.0.0/16"}},
+		{Purpose: "cluster", Deny: []string{"192.0.2.0/24"}},
+	})
+	require.NoError(err)
+
+	tcp := func(ip string) net.Addr { return &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234} }
+	assert.True(a.permits("api", tcp("10.0.0.1")))
+	assert.True(a.permits("api", tcp("2001:db8::1")))
+	assert.False(a.permits("api", tcp("10.1.0.1")))
+	assert.False(a.permits("api", tcp("192.168.0.1")))
+	assert.False(a.permits("api", tcp("10.1.0.1")))
+	assert.True(a.permits("cluster", tcp("10.1.0.1")))
+	assert.False(a.permits("cluster", tcp("192.0.2.7")))
+	assert.True(a.permits("proxy", tcp("192.0.2.7")))
+	assert.True(a.permits("api", &net.UnixAddr{Name: "/tmp/boundary.sock", Net: "unix"}))
+
+	denied := a.takeDenied()
+	require.Len(denied, 3)
+	counts := map[string]uint64{}
+	for _, d := range denied {
+		counts[d.Purpose+" "+d.RemoteAddr] = d.Count
+		assert.False(d.FirstTime.After(d.LastTime))
+	}
+	assert.Equal(map[string]uint64{
+		"api 10.1.0.1":      2,
+		"api 192.168.0.1":   1,
+		"cluster 192.0.2.7": 1,
+	}, counts)
+	assert.Empty(a.takeDenied())
+
+	// A reload replaces the lists, and keeps them if invalid
+	require.NoError(a.reload([]*config.ListenerAccess{{Purpose: "api", Deny: []string{"10.0.0.0/8"}}}))
+	assert.False(a.permits("api", tcp("10.0.0.1")))
+	assert.True(a.permits("api", tcp("192.168.0.1")))
+	assert.True(a.permits("cluster", tcp("192.0.2.7")))
+	assert.Error(a.reload([]*config.ListenerAccess{{Purpose: "api", Allow: []string{"10.0.0.1"}}}))
+	assert.False(a.permits("api", tcp("10.0.0.1")))
+
+	// The number of addresses counted is capped
+	for i := 0; i < maxDeniedAddrs+10; i++ {
+		a.permits("api", tcp(net.IPv4(10, byte(i>>16), byte(i>>8), byte(i)).String()))
+	}
+	assert.Len(a.takeDenied(), maxDeniedAddrs)
+
+	_, err = newListenerAccess([]*config.ListenerAccess{{Purpose: "proxy"}})
+	assert.Error(err)
+	_, err = newListenerAccess([]*config.ListenerAccess{{Purpose: "api"}, {Purpose: "api"}})
+	assert.Error(err)
+}
+
+func TestWrapHandlerWithListenerAccess(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	a, err := newListenerAccess([]*config.ListenerAccess{{Purpose: "api", Deny: []string{"192.0.2.0/24"}}})
+	require.NoError(err)
+	c := &Controller{listenerAccess: a}
+	h := wrapHandlerWithListenerAccess(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}), c)
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/scopes", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(http.StatusForbidden, w.Code)
+
+	r.RemoteAddr = "198.51.100.1:1234"
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(http.StatusTeapot, w.Code)
+}
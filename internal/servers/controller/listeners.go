@@ -109,7 +109,14 @@ func (c *Controller) startListeners() error {
 		// Clear out in case this is a second start of the controller
 		ln.Mux.UnregisterProto(alpnmux.DefaultProto)
 		l, err := ln.Mux.RegisterProto(alpnmux.DefaultProto, &tls.Config{
-			GetConfigForClient: c.validateWorkerTls,
+			GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+				// Refuse workers from addresses which aren't allowed before
+				// authenticating them
+				if !c.listenerAccess.permits("cluster", hello.Conn.RemoteAddr()) {
+					return nil, errListenerAccessDenied
+				}
+				return c.validateWorkerTls(hello)
+			},
 		})
 		if err != nil {
 			return fmt.Errorf("error getting sub-listener for worker proto: %w", err)
@@ -118,8 +125,9 @@ func (c *Controller) startListeners() error {
 		workerServer := grpc.NewServer(
 			grpc.MaxRecvMsgSize(math.MaxInt32),
 			grpc.MaxSendMsgSize(math.MaxInt32),
+			grpc.ChainUnaryInterceptor(c.schemaGateUnaryInterceptor, c.readOnlyUnaryInterceptor),
 		)
-		workerService := workers.NewWorkerServiceServer(c.logger.Named("worker-handler"), c.ServersRepoFn, c.SessionRepoFn, c.workerStatusUpdateTimes, c.kms)
+		workerService := workers.NewWorkerServiceServer(c.logger.Named("worker-handler"), c.conf.RawConfig.Controller.Name, c.ServersRepoFn, c.SessionRepoFn, c.workerStatusUpdateTimes, c.kms, c.authorizeSessionConnection, c.targetBandwidthLimit, c.targetConnectionRateLimit, c.sessionPeerIdentity, c.permitsWorkerStatus)
 		pbs.RegisterServerCoordinationServiceServer(workerServer, workerService)
 		pbs.RegisterSessionServiceServer(workerServer, workerService)
 
@@ -0,0 +1,194 @@
+package openapi
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	accountspb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/accounts"
+	authmethodspb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/authmethods"
+	hostspb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/hosts"
+	targetspb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	"github.com/hashicorp/boundary/internal/host"
+	"github.com/hashicorp/boundary/internal/target"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// subtype is a subtype of a resource and the message of its attributes. The
+// message is nil for subtypes without attributes.
+type subtype struct {
+	name  string
+	attrs proto.Message
+	// schemaName names the empty attributes schema of a subtype without
+	// attributes
+	schemaName string
+}
+
+// subtypes are the subtypes of resources with attributes, by the name of
+// the resource schema.
+var subtypes = map[string][]subtype{
+	"controller.api.resources.accounts.v1.Account": {
+		{name: auth.PasswordSubtype.String(), attrs: &accountspb.PasswordAccountAttributes{}},
+	},
+	"controller.api.resources.authmethods.v1.AuthMethod": {
+		{name: auth.PasswordSubtype.String(), attrs: &authmethodspb.PasswordAuthMethodAttributes{}},
+	},
+	"controller.api.resources.hostcatalogs.v1.HostCatalog": {
+		{name: host.StaticSubtype.String(), schemaName: "controller.api.resources.hostcatalogs.v1.StaticHostCatalogAttributes"},
+	},
+	"controller.api.resources.hosts.v1.Host": {
+		{name: host.StaticSubtype.String(), attrs: &hostspb.StaticHostAttributes{}},
+	},
+	"controller.api.resources.hostsets.v1.HostSet": {
+		{name: host.StaticSubtype.String(), schemaName: "controller.api.resources.hostsets.v1.StaticHostSetAttributes"},
+	},
+	"controller.api.resources.targets.v1.Target": {
+		{name: target.TcpSubType.String(), attrs: &targetspb.TcpTargetAttributes{}},
+	},
+}
+
+// addSubtypeAttributes adds the attributes schema of each subtype to schemas
+// and replaces the attributes of resources with a oneOf of them. The type of
+// the resources is restricted to their subtypes.
+func addSubtypeAttributes(schemas map[string]interface{}) error {
+	for resource, sts := range subtypes {
+		rs, ok := schemas[resource].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("schema %q not found", resource)
+		}
+		props, ok := rs["properties"].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("schema %q has no properties", resource)
+		}
+		attrs, ok := props["attributes"].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("schema %q has no attributes", resource)
+		}
+
+		var oneOf, names []interface{}
+		for _, st := range sts {
+			name := st.schemaName
+			schema := map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			}
+			if st.attrs != nil {
+				md := st.attrs.ProtoReflect().Descriptor()
+				name = string(md.FullName())
+				schema = messageSchema(md)
+			}
+			schema["description"] = fmt.Sprintf("The attributes of the %q type.", st.name)
+			schemas[name] = schema
+			oneOf = append(oneOf, map[string]interface{}{"$ref": schemaRefPrefix + name})
+			names = append(names, st.name)
+		}
+		props["attributes"] = map[string]interface{}{
+			"description": attrs["description"],
+			"oneOf":       oneOf,
+		}
+		if typ, ok := props["type"].(map[string]interface{}); ok {
+			typ["enum"] = names
+		}
+	}
+	return nil
+}
+
+// messageSchema returns the schema of the JSON encoding of a message, using
+// the same types and formats as the generated swagger document. Attributes
+// are encoded with their proto field names.
+func messageSchema(md protoreflect.MessageDescriptor) map[string]interface{} {
+	props := map[string]interface{}{}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		props[string(fd.Name())] = fieldSchema(fd)
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+	}
+}
+
+func fieldSchema(fd protoreflect.FieldDescriptor) map[string]interface{} {
+	switch {
+	case fd.IsMap():
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": fieldSchema(fd.MapValue()),
+		}
+	case fd.IsList():
+		return map[string]interface{}{
+			"type":  "array",
+			"items": kindSchema(fd),
+		}
+	}
+	return kindSchema(fd)
+}
+
+func kindSchema(fd protoreflect.FieldDescriptor) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return map[string]interface{}{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]interface{}{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case protoreflect.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		var values []interface{}
+		evs := fd.Enum().Values()
+		for i := 0; i < evs.Len(); i++ {
+			values = append(values, string(evs.Get(i).Name()))
+		}
+		return map[string]interface{}{"type": "string", "enum": values}
+	}
+	return wellKnownSchema(fd.Message())
+}
+
+// wellKnownSchema returns the schema of a message field. Wrappers are
+// encoded as their value, and are nullable so they can be cleared.
+func wellKnownSchema(md protoreflect.MessageDescriptor) map[string]interface{} {
+	var schema map[string]interface{}
+	switch md.FullName() {
+	case "google.protobuf.StringValue":
+		schema = map[string]interface{}{"type": "string"}
+	case "google.protobuf.BoolValue":
+		schema = map[string]interface{}{"type": "boolean"}
+	case "google.protobuf.Int32Value":
+		schema = map[string]interface{}{"type": "integer", "format": "int32"}
+	case "google.protobuf.UInt32Value":
+		schema = map[string]interface{}{"type": "integer", "format": "int64"}
+	case "google.protobuf.Int64Value":
+		schema = map[string]interface{}{"type": "string", "format": "int64"}
+	case "google.protobuf.UInt64Value":
+		schema = map[string]interface{}{"type": "string", "format": "uint64"}
+	case "google.protobuf.FloatValue":
+		schema = map[string]interface{}{"type": "number", "format": "float"}
+	case "google.protobuf.DoubleValue":
+		schema = map[string]interface{}{"type": "number", "format": "double"}
+	case "google.protobuf.BytesValue":
+		schema = map[string]interface{}{"type": "string", "format": "byte"}
+	case "google.protobuf.Timestamp":
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case "google.protobuf.Struct":
+		return map[string]interface{}{"type": "object"}
+	default:
+		return messageSchema(md)
+	}
+	schema["nullable"] = true
+	return schema
+}
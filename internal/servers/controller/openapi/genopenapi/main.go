@@ -0,0 +1,29 @@
+// genopenapi writes the OpenAPI v3 document of the controller API, from
+// which the API clients for other languages are generated.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/hashicorp/boundary/internal/servers/controller/openapi"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Println("usage: genopenapi <output json file>")
+		os.Exit(1)
+	}
+	out := os.Args[1]
+
+	doc, err := openapi.Document()
+	if err != nil {
+		fmt.Printf("error building openapi document: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ioutil.WriteFile(out, doc, 0644); err != nil {
+		fmt.Printf("error writing file %q: %v\n", out, err)
+		os.Exit(1)
+	}
+}
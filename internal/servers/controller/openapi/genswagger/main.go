@@ -0,0 +1,51 @@
+// genswagger embeds the swagger document generated from the controller
+// protos into the openapi package, so the controller can serve it.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/template"
+)
+
+var swaggerTemplate = template.Must(template.New("").Parse(`// Code generated by "genswagger"; DO NOT EDIT.
+
+package openapi
+
+// swaggerJSON is the swagger 2.0 document generated from the controller
+// protos.
+const swaggerJSON = {{ .Contents }}
+`))
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Println("usage: genswagger <swagger json file> <output go file>")
+		os.Exit(1)
+	}
+	in, out := os.Args[1], os.Args[2]
+
+	contents, err := ioutil.ReadFile(in)
+	if err != nil {
+		fmt.Printf("error reading swagger file %q: %v\n", in, err)
+		os.Exit(1)
+	}
+
+	outBuf := bytes.NewBuffer(nil)
+	if err := swaggerTemplate.Execute(outBuf, struct {
+		Contents string
+	}{
+		// Raw string literals can't hold backticks, so they are spliced in
+		Contents: "`" + strings.ReplaceAll(string(contents), "`", "` + \"`\" + `") + "`",
+	}); err != nil {
+		fmt.Printf("error executing swagger template: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := ioutil.WriteFile(out, outBuf.Bytes(), 0644); err != nil {
+		fmt.Printf("error writing file %q: %v\n", out, err)
+		os.Exit(1)
+	}
+}
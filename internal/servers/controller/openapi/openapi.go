@@ -0,0 +1,141 @@
+// Package openapi builds the OpenAPI v3 document of the controller API from
+// the swagger 2.0 document generated from the controller protos.
+package openapi
+
+//go:generate go run ./genswagger ../../../gen/controller.swagger.json swagger.gen.go
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/boundary/version"
+)
+
+// Version is the OpenAPI version of the document.
+const Version = "3.0.3"
+
+var (
+	documentOnce sync.Once
+	document     []byte
+	documentErr  error
+)
+
+// Document returns the OpenAPI v3 document of the controller API as JSON.
+// The attributes of resources with subtypes are described by a oneOf of the
+// attributes of each subtype.
+func Document() ([]byte, error) {
+	documentOnce.Do(func() {
+		document, documentErr = build()
+	})
+	return document, documentErr
+}
+
+func build() ([]byte, error) {
+	// Refs are rewritten before parsing so they don't need to be found in
+	// every schema
+	raw := strings.ReplaceAll(swaggerJSON, "#/definitions/", schemaRefPrefix)
+	var v2 map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &v2); err != nil {
+		return nil, fmt.Errorf("error parsing swagger document: %w", err)
+	}
+
+	info, _ := v2["info"].(map[string]interface{})
+	if info == nil {
+		info = map[string]interface{}{}
+	}
+	info["version"] = version.Get().VersionNumber()
+
+	schemas, _ := v2["definitions"].(map[string]interface{})
+	if schemas == nil {
+		schemas = map[string]interface{}{}
+	}
+	if err := addSubtypeAttributes(schemas); err != nil {
+		return nil, err
+	}
+
+	paths, _ := v2["paths"].(map[string]interface{})
+	for p, item := range paths {
+		ops, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected path item for %q", p)
+		}
+		for method, op := range ops {
+			o, ok := op.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unexpected operation %s %q", method, p)
+			}
+			convertOperation(o)
+		}
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"openapi": Version,
+		"info":    info,
+		"servers": []interface{}{map[string]interface{}{"url": "/"}},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+		},
+	})
+}
+
+// convertOperation converts a swagger 2.0 operation to OpenAPI v3 in place:
+// body parameters become the request body, the types of other parameters
+// become their schema and response schemas become their content.
+func convertOperation(op map[string]interface{}) {
+	params, _ := op["parameters"].([]interface{})
+	var converted []interface{}
+	for _, p := range params {
+		param, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if param["in"] == "body" {
+			op["requestBody"] = map[string]interface{}{
+				"required": param["required"] == true,
+				"content":  jsonContent(param["schema"]),
+			}
+			continue
+		}
+		schema := map[string]interface{}{}
+		for _, k := range []string{"type", "format", "items", "enum", "default"} {
+			if v, ok := param[k]; ok {
+				schema[k] = v
+				delete(param, k)
+			}
+		}
+		if cf, ok := param["collectionFormat"]; ok {
+			// Arrays passed as repeated parameters are exploded in v3
+			param["explode"] = cf == "multi"
+			delete(param, "collectionFormat")
+		}
+		param["schema"] = schema
+		converted = append(converted, param)
+	}
+	delete(op, "parameters")
+	if len(converted) > 0 {
+		op["parameters"] = converted
+	}
+
+	responses, _ := op["responses"].(map[string]interface{})
+	for _, r := range responses {
+		resp, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if schema, ok := resp["schema"]; ok {
+			resp["content"] = jsonContent(schema)
+			delete(resp, "schema")
+		}
+	}
+}
+
+func jsonContent(schema interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{
+			"schema": schema,
+		},
+	}
+}
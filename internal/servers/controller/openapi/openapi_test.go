@@ -0,0 +1,160 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	_ "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func TestDocument(t *testing.T) {
+	b, err := Document()
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "#/definitions/")
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &doc))
+	assert.Equal(t, Version, doc["openapi"])
+	assert.NotContains(t, doc, "swagger")
+
+	paths := doc["paths"].(map[string]interface{})
+	for _, p := range []string{
+		"/v1/targets",
+		"/v1/users/self:grants",
+		"/v1/auth-tokens:exchange",
+		"/v1/targets/{id}:connection-authorization",
+		"/v1/targets/{id}/history",
+		"/v1/sessions:connection-stats",
+		"/v1/auth-tokens:refresh",
+		"/v1/targets/{id}:bandwidth-limit",
+		"/v1/targets/{id}:test-connection",
+		"/v1/scopes/{scope_id}:inactive-users",
+		"/v1/scopes/{id}:inactivity-policy",
+		"/v1/roles/{id}:add-temporary-principals",
+		"/v1/roles/{id}:principal-expirations",
+		"/v1/roles/{id}:request-access",
+		"/v1/roles/{id}:access-requests",
+		"/v1/access-requests/{id}",
+		"/v1/access-requests/{id}:approve",
+		"/v1/access-requests/{id}:deny",
+		"/v1/access-requests/{id}:cancel",
+		"/v1/targets/{id}:credential-checkout",
+		"/v1/scopes/{id}:project-template",
+		"/v1/targets/{id}:clone",
+		"/v1/host-sets/{id}:clone",
+		"/v1/host-catalogs/{id}:import-hosts",
+		"/v1/targets/{id}:peer-identity",
+		"/v1/scopes/{id}:security-events",
+		"/v1/auth-tokens:issue-scoped",
+		"/v1/targets:batch-get",
+		"/v1/targets/{id}:connection-metadata",
+		"/v1/targets/{id}:user-name-template",
+		"/v1/scopes/{id}:usage",
+		"/v1/scopes/{id}:export-usage",
+		"/v1/targets/{id}:connection-rate-limit",
+		"/v1/scopes/{id}:event-sinks",
+	} {
+		require.Contains(t, paths, p)
+	}
+	for p, item := range paths {
+		for method, op := range item.(map[string]interface{}) {
+			o := op.(map[string]interface{})
+			params, _ := o["parameters"].([]interface{})
+			for _, param := range params {
+				pm := param.(map[string]interface{})
+				assert.NotEqual(t, "body", pm["in"], "%s %s", method, p)
+				assert.Contains(t, pm, "schema", "%s %s", method, p)
+				assert.NotContains(t, pm, "type", "%s %s", method, p)
+			}
+			for code, resp := range o["responses"].(map[string]interface{}) {
+				assert.NotContains(t, resp, "schema", "%s %s %s", method, p, code)
+			}
+		}
+	}
+	create := paths["/v1/targets"].(map[string]interface{})["post"].(map[string]interface{})
+	assert.Contains(t, create, "requestBody")
+
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for resource, sts := range subtypes {
+		props := schemas[resource].(map[string]interface{})["properties"].(map[string]interface{})
+		oneOf := props["attributes"].(map[string]interface{})["oneOf"].([]interface{})
+		require.Len(t, oneOf, len(sts), resource)
+		for _, ref := range oneOf {
+			name := strings.TrimPrefix(ref.(map[string]interface{})["$ref"].(string), schemaRefPrefix)
+			assert.Contains(t, schemas, name, resource)
+		}
+		assert.Len(t, props["type"].(map[string]interface{})["enum"], len(sts), resource)
+	}
+
+	tcp := schemas["controller.api.resources.targets.v1.TcpTargetAttributes"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"default_port": map[string]interface{}{"type": "integer", "format": "int64", "nullable": true},
+	}, tcp["properties"])
+
+	password := schemas["controller.api.resources.accounts.v1.PasswordAccountAttributes"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"login_name": map[string]interface{}{"type": "string"},
+		"password":   map[string]interface{}{"type": "string", "nullable": true},
+	}, password["properties"])
+}
+
+// TestDocument_Routes checks that every route of the controller services is
+// in the document, so routes served outside of the gateway can't go
+// unnoticed.
+func TestDocument_Routes(t *testing.T) {
+	b, err := Document()
+	require.NoError(t, err)
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &doc))
+	paths := doc["paths"].(map[string]interface{})
+
+	var routes int
+	protoregistry.GlobalFiles.RangeFilesByPackage("controller.api.services.v1", func(fd protoreflect.FileDescriptor) bool {
+		for i := 0; i < fd.Services().Len(); i++ {
+			methods := fd.Services().Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				m := methods.Get(j)
+				rule, _ := proto.GetExtension(m.Options(), annotations.E_Http).(*annotations.HttpRule)
+				if rule == nil {
+					continue
+				}
+				for _, r := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+					method, path := httpRuleRoute(r)
+					routes++
+					item, ok := paths[path].(map[string]interface{})
+					if assert.True(t, ok, "%s: path %s not found", m.FullName(), path) {
+						assert.Contains(t, item, method, "%s: %s %s not found", m.FullName(), method, path)
+					}
+				}
+			}
+		}
+		return true
+	})
+	assert.Greater(t, routes, 0)
+}
+
+// httpRuleRoute returns the lowercase method and the path of the rule.
+func httpRuleRoute(r *annotations.HttpRule) (string, string) {
+	switch p := r.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return "get", p.Get
+	case *annotations.HttpRule_Put:
+		return "put", p.Put
+	case *annotations.HttpRule_Post:
+		return "post", p.Post
+	case *annotations.HttpRule_Delete:
+		return "delete", p.Delete
+	case *annotations.HttpRule_Patch:
+		return "patch", p.Patch
+	case *annotations.HttpRule_Custom:
+		return strings.ToLower(p.Custom.GetKind()), p.Custom.GetPath()
+	}
+	return "", ""
+}
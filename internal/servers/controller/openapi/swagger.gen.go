@@ -0,0 +1,7406 @@
+// Code generated by "genswagger"; DO NOT EDIT.
+
+package openapi
+
+// swaggerJSON is the swagger 2.0 document generated from the controller
+// protos.
+const swaggerJSON = `{
+  "swagger": "2.0",
+  "info": {
+    "title": "Boundary Controller HTTP API",
+    "version": "version not set"
+  },
+  "schemes": [
+    "https",
+    "http"
+  ],
+  "consumes": [
+    "application/json"
+  ],
+  "produces": [
+    "application/json"
+  ],
+  "paths": {
+    "/v1/access-requests/{id}": {
+      "get": {
+        "summary": "Gets a single access request.",
+        "operationId": "RoleService_GetAccessRequest",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.AccessRequest"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/access-requests/{id}:approve": {
+      "post": {
+        "summary": "Approves an access request.",
+        "operationId": "RoleService_ApproveAccessRequest",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.AccessRequest"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ApproveAccessRequestRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/access-requests/{id}:cancel": {
+      "post": {
+        "summary": "Cancels an access request.",
+        "operationId": "RoleService_CancelAccessRequest",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.AccessRequest"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.CancelAccessRequestRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/access-requests/{id}:deny": {
+      "post": {
+        "summary": "Denies an access request.",
+        "operationId": "RoleService_DenyAccessRequest",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.AccessRequest"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.DenyAccessRequestRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/accounts": {
+      "get": {
+        "summary": "Lists all Accounts in a specific Auth Method.",
+        "operationId": "AccountService_ListAccounts",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListAccountsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "auth_method_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AccountService"
+        ]
+      },
+      "post": {
+        "summary": "Creates a single Account in the provided Auth Method.",
+        "operationId": "AccountService_CreateAccount",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AccountService"
+        ]
+      }
+    },
+    "/v1/accounts/{id}": {
+      "get": {
+        "summary": "Gets a single Account.",
+        "operationId": "AccountService_GetAccount",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AccountService"
+        ]
+      },
+      "delete": {
+        "summary": "Deletes an Account.",
+        "operationId": "AccountService_DeleteAccount",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.DeleteAccountResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AccountService"
+        ]
+      },
+      "patch": {
+        "summary": "Updates an Account.",
+        "operationId": "AccountService_UpdateAccount",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+            }
+          },
+          {
+            "name": "update_mask",
+            "in": "query",
+            "required": false,
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AccountService"
+        ]
+      }
+    },
+    "/v1/accounts/{id}:change-password": {
+      "post": {
+        "summary": "Sets the password for the provided Account.",
+        "operationId": "AccountService_ChangePassword",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ChangePasswordRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AccountService"
+        ]
+      }
+    },
+    "/v1/accounts/{id}:set-password": {
+      "post": {
+        "summary": "Sets the password for the provided Account.",
+        "operationId": "AccountService_SetPassword",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetPasswordRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AccountService"
+        ]
+      }
+    },
+    "/v1/auth-methods": {
+      "get": {
+        "summary": "Lists all Auth Methods.",
+        "operationId": "AuthMethodService_ListAuthMethods",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListAuthMethodsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "scope_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AuthMethodService"
+        ]
+      },
+      "post": {
+        "summary": "Creates a single Auth Method.",
+        "operationId": "AuthMethodService_CreateAuthMethod",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.authmethods.v1.AuthMethod"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.authmethods.v1.AuthMethod"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AuthMethodService"
+        ]
+      }
+    },
+    "/v1/auth-methods/{auth_method_id}:authenticate": {
+      "post": {
+        "summary": "Authenticate a user to an scope and retrieve an authentication token.",
+        "operationId": "AuthMethodService_Authenticate",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.authtokens.v1.AuthToken"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "auth_method_id",
+            "description": "The ID of the Auth Method in the system that should be used for authentication.",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.AuthenticateRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AuthMethodService"
+        ]
+      }
+    },
+    "/v1/auth-methods/{id}": {
+      "get": {
+        "summary": "Gets a single Auth Method.",
+        "operationId": "AuthMethodService_GetAuthMethod",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.authmethods.v1.AuthMethod"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AuthMethodService"
+        ]
+      },
+      "delete": {
+        "summary": "Deletes an AuthMethod",
+        "operationId": "AuthMethodService_DeleteAuthMethod",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.DeleteAuthMethodResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AuthMethodService"
+        ]
+      },
+      "patch": {
+        "summary": "Updates an Auth Method.",
+        "operationId": "AuthMethodService_UpdateAuthMethod",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.authmethods.v1.AuthMethod"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.authmethods.v1.AuthMethod"
+            }
+          },
+          {
+            "name": "update_mask",
+            "in": "query",
+            "required": false,
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AuthMethodService"
+        ]
+      }
+    },
+    "/v1/auth-tokens": {
+      "get": {
+        "summary": "Lists all Auth Tokens.",
+        "operationId": "AuthTokenService_ListAuthTokens",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListAuthTokensResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "scope_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AuthTokenService"
+        ]
+      }
+    },
+    "/v1/auth-tokens/{id}": {
+      "get": {
+        "summary": "Gets a single Auth Token.",
+        "operationId": "AuthTokenService_GetAuthToken",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.authtokens.v1.AuthToken"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AuthTokenService"
+        ]
+      },
+      "delete": {
+        "summary": "Deletes an Auth Token.",
+        "operationId": "AuthTokenService_DeleteAuthToken",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.DeleteAuthTokenResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AuthTokenService"
+        ]
+      }
+    },
+    "/v1/auth-tokens:exchange": {
+      "post": {
+        "summary": "Exchanges the caller's Auth Token for a derived Auth Token with fewer permissions.",
+        "operationId": "AuthTokenService_ExchangeAuthToken",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.authtokens.v1.AuthToken"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ExchangeAuthTokenRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AuthTokenService"
+        ]
+      }
+    },
+    "/v1/auth-tokens:issue-scoped": {
+      "post": {
+        "summary": "Issues an Auth Token limited to Scopes from the caller's Auth Token.",
+        "operationId": "AuthTokenService_IssueScopedAuthToken",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.authtokens.v1.AuthToken"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.IssueScopedAuthTokenRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AuthTokenService"
+        ]
+      }
+    },
+    "/v1/auth-tokens:refresh": {
+      "post": {
+        "summary": "Refreshes the caller's Auth Token.",
+        "operationId": "AuthTokenService_RefreshAuthToken",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.authtokens.v1.AuthToken"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.RefreshAuthTokenRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.AuthTokenService"
+        ]
+      }
+    },
+    "/v1/groups": {
+      "get": {
+        "summary": "Lists all Groups.",
+        "operationId": "GroupService_ListGroups",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListGroupsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "scope_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.GroupService"
+        ]
+      },
+      "post": {
+        "summary": "Creates a single Group.",
+        "operationId": "GroupService_CreateGroup",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.GroupService"
+        ]
+      }
+    },
+    "/v1/groups/{id}": {
+      "get": {
+        "summary": "Gets a single Group.",
+        "operationId": "GroupService_GetGroup",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.GroupService"
+        ]
+      },
+      "delete": {
+        "summary": "Deletes a Group.",
+        "operationId": "GroupService_DeleteGroup",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.DeleteGroupResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.GroupService"
+        ]
+      },
+      "patch": {
+        "summary": "Updates a Group.",
+        "operationId": "GroupService_UpdateGroup",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+            }
+          },
+          {
+            "name": "update_mask",
+            "in": "query",
+            "required": false,
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.GroupService"
+        ]
+      }
+    },
+    "/v1/groups/{id}:add-members": {
+      "post": {
+        "summary": "Adds members to a Group.",
+        "operationId": "GroupService_AddGroupMembers",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.AddGroupMembersRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.GroupService"
+        ]
+      }
+    },
+    "/v1/groups/{id}:remove-members": {
+      "post": {
+        "summary": "Removes the specified members from a Group.",
+        "operationId": "GroupService_RemoveGroupMembers",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.RemoveGroupMembersRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.GroupService"
+        ]
+      }
+    },
+    "/v1/groups/{id}:set-members": {
+      "post": {
+        "summary": "Set a Group's members to exactly the list of provided in the request, removing any members that are not specified.",
+        "operationId": "GroupService_SetGroupMembers",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetGroupMembersRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.GroupService"
+        ]
+      }
+    },
+    "/v1/host-catalogs": {
+      "get": {
+        "summary": "Gets a list of Host Catalogs.",
+        "operationId": "HostCatalogService_ListHostCatalogs",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListHostCatalogsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "scope_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostCatalogService"
+        ]
+      },
+      "post": {
+        "summary": "Creates a Host Catalog",
+        "operationId": "HostCatalogService_CreateHostCatalog",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostcatalogs.v1.HostCatalog"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostcatalogs.v1.HostCatalog"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostCatalogService"
+        ]
+      }
+    },
+    "/v1/host-catalogs/{id}": {
+      "get": {
+        "summary": "Gets a single Host Catalog.",
+        "operationId": "HostCatalogService_GetHostCatalog",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostcatalogs.v1.HostCatalog"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostCatalogService"
+        ]
+      },
+      "delete": {
+        "summary": "Deletes a Host Catalog",
+        "operationId": "HostCatalogService_DeleteHostCatalog",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.DeleteHostCatalogResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostCatalogService"
+        ]
+      },
+      "patch": {
+        "summary": "Updates a Host Catalog",
+        "operationId": "HostCatalogService_UpdateHostCatalog",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostcatalogs.v1.HostCatalog"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostcatalogs.v1.HostCatalog"
+            }
+          },
+          {
+            "name": "update_mask",
+            "in": "query",
+            "required": false,
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostCatalogService"
+        ]
+      }
+    },
+    "/v1/host-catalogs/{id}:import-hosts": {
+      "post": {
+        "summary": "Imports the Hosts of an inventory file into a Host Catalog.",
+        "operationId": "HostCatalogService_ImportHostCatalogHosts",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ImportHostCatalogHostsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ImportHostCatalogHostsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostCatalogService"
+        ]
+      }
+    },
+    "/v1/host-sets": {
+      "get": {
+        "summary": "List all Host Sets under the specific Catalog.",
+        "operationId": "HostSetService_ListHostSets",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListHostSetsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "host_catalog_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostSetService"
+        ]
+      },
+      "post": {
+        "summary": "Create a Host Set.",
+        "operationId": "HostSetService_CreateHostSet",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostSetService"
+        ]
+      }
+    },
+    "/v1/host-sets/{id}": {
+      "get": {
+        "summary": "Get a single Host Set.",
+        "operationId": "HostSetService_GetHostSet",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostSetService"
+        ]
+      },
+      "delete": {
+        "summary": "Delete a Host Set.",
+        "operationId": "HostSetService_DeleteHostSet",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.DeleteHostSetResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostSetService"
+        ]
+      },
+      "patch": {
+        "summary": "Update a Host Set.",
+        "operationId": "HostSetService_UpdateHostSet",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+            }
+          },
+          {
+            "name": "update_mask",
+            "in": "query",
+            "required": false,
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostSetService"
+        ]
+      }
+    },
+    "/v1/host-sets/{id}:add-hosts": {
+      "post": {
+        "summary": "Adds existing Hosts to a Host Set.",
+        "operationId": "HostSetService_AddHostSetHosts",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.AddHostSetHostsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostSetService"
+        ]
+      }
+    },
+    "/v1/host-sets/{id}:clone": {
+      "post": {
+        "summary": "Creates a copy of a Host Set.",
+        "operationId": "HostSetService_CloneHostSet",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.CloneHostSetRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostSetService"
+        ]
+      }
+    },
+    "/v1/host-sets/{id}:remove-hosts": {
+      "post": {
+        "summary": "Removes Hosts from the Host Set.",
+        "operationId": "HostSetService_RemoveHostSetHosts",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.RemoveHostSetHostsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostSetService"
+        ]
+      }
+    },
+    "/v1/host-sets/{id}:set-hosts": {
+      "post": {
+        "summary": "Sets the Hosts on the Host Set.",
+        "operationId": "HostSetService_SetHostSetHosts",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetHostSetHostsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostSetService"
+        ]
+      }
+    },
+    "/v1/hosts": {
+      "get": {
+        "summary": "List all Hosts for the specified Catalog.",
+        "operationId": "HostService_ListHosts",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListHostsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "host_catalog_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostService"
+        ]
+      },
+      "post": {
+        "summary": "Create a single Host.",
+        "operationId": "HostService_CreateHost",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hosts.v1.Host"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hosts.v1.Host"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostService"
+        ]
+      }
+    },
+    "/v1/hosts/{id}": {
+      "get": {
+        "summary": "Gets a single Host.",
+        "operationId": "HostService_GetHost",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hosts.v1.Host"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostService"
+        ]
+      },
+      "delete": {
+        "summary": "Delete a Host.",
+        "operationId": "HostService_DeleteHost",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.DeleteHostResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostService"
+        ]
+      },
+      "patch": {
+        "summary": "Update a Host.",
+        "operationId": "HostService_UpdateHost",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hosts.v1.Host"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.hosts.v1.Host"
+            }
+          },
+          {
+            "name": "update_mask",
+            "in": "query",
+            "required": false,
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.HostService"
+        ]
+      }
+    },
+    "/v1/roles": {
+      "get": {
+        "summary": "Lists all Roles.",
+        "operationId": "RoleService_ListRoles",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListRolesResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "scope_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      },
+      "post": {
+        "summary": "Creates a single Role.",
+        "operationId": "RoleService_CreateRole",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/roles/{id}": {
+      "get": {
+        "summary": "Gets a single Role.",
+        "operationId": "RoleService_GetRole",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      },
+      "delete": {
+        "summary": "Deletes a Role.",
+        "operationId": "RoleService_DeleteRole",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.DeleteRoleResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      },
+      "patch": {
+        "summary": "Updates a Role.",
+        "operationId": "RoleService_UpdateRole",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+            }
+          },
+          {
+            "name": "update_mask",
+            "in": "query",
+            "required": false,
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/roles/{id}:access-requests": {
+      "get": {
+        "summary": "Lists the access requests for a Role.",
+        "operationId": "RoleService_ListRoleAccessRequests",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListRoleAccessRequestsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/roles/{id}:add-grants": {
+      "post": {
+        "summary": "Adds grants to a Role",
+        "operationId": "RoleService_AddRoleGrants",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.AddRoleGrantsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/roles/{id}:add-principals": {
+      "post": {
+        "summary": "Adds Users and/or Groups to a Role.",
+        "operationId": "RoleService_AddRolePrincipals",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.AddRolePrincipalsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/roles/{id}:add-temporary-principals": {
+      "post": {
+        "summary": "Adds principals to a Role until an expiration time.",
+        "operationId": "RoleService_AddTemporaryRolePrincipals",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.AddTemporaryRolePrincipalsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.AddTemporaryRolePrincipalsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/roles/{id}:principal-expirations": {
+      "get": {
+        "summary": "Lists the temporary principals of a Role.",
+        "operationId": "RoleService_ListRolePrincipalExpirations",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListRolePrincipalExpirationsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/roles/{id}:remove-grants": {
+      "post": {
+        "summary": "Removes grants from a Role.",
+        "operationId": "RoleService_RemoveRoleGrants",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.RemoveRoleGrantsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/roles/{id}:remove-principals": {
+      "post": {
+        "summary": "Removes the specified Users and/or Groups from a Role.",
+        "operationId": "RoleService_RemoveRolePrincipals",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.RemoveRolePrincipalsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/roles/{id}:request-access": {
+      "post": {
+        "summary": "Requests to be assigned a Role for a limited duration.",
+        "operationId": "RoleService_RequestRoleAccess",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.AccessRequest"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.RequestRoleAccessRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/roles/{id}:set-grants": {
+      "post": {
+        "summary": "Set grants for a Role, removing any grants that are not specified in the request.",
+        "operationId": "RoleService_SetRoleGrants",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetRoleGrantsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/roles/{id}:set-principals": {
+      "post": {
+        "summary": "Set Users and/or Groups to a Role, removing any principals that are not specified in the request.",
+        "operationId": "RoleService_SetRolePrincipals",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetRolePrincipalsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.RoleService"
+        ]
+      }
+    },
+    "/v1/scopes": {
+      "get": {
+        "summary": "Lists all Scopes within the Scope provided in the request.",
+        "operationId": "ScopeService_ListScopes",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListScopesResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "scope_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      },
+      "post": {
+        "summary": "Creates a single Scope.",
+        "operationId": "ScopeService_CreateScope",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.scopes.v1.Scope"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.scopes.v1.Scope"
+            }
+          },
+          {
+            "name": "skip_admin_role_creation",
+            "in": "query",
+            "required": false,
+            "type": "boolean"
+          },
+          {
+            "name": "skip_default_role_creation",
+            "in": "query",
+            "required": false,
+            "type": "boolean"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      }
+    },
+    "/v1/scopes/{id}": {
+      "get": {
+        "summary": "Gets a single Scope.",
+        "operationId": "ScopeService_GetScope",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.scopes.v1.Scope"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      },
+      "delete": {
+        "summary": "Deletes a Scope.",
+        "operationId": "ScopeService_DeleteScope",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.DeleteScopeResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      },
+      "patch": {
+        "summary": "Updates a Scope.",
+        "operationId": "ScopeService_UpdateScope",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.scopes.v1.Scope"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.scopes.v1.Scope"
+            }
+          },
+          {
+            "name": "update_mask",
+            "in": "query",
+            "required": false,
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      }
+    },
+    "/v1/scopes/{id}:event-sinks": {
+      "get": {
+        "summary": "Lists the event sinks of an org.",
+        "operationId": "ScopeService_ListScopeEventSinks",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListScopeEventSinksResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      },
+      "delete": {
+        "summary": "Removes an event sink from an org.",
+        "operationId": "ScopeService_RemoveScopeEventSink",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.RemoveScopeEventSinkResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "sink_id",
+            "description": "The ID of the event sink.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      },
+      "post": {
+        "summary": "Adds an event sink to an org.",
+        "operationId": "ScopeService_AddScopeEventSink",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.scopes.v1.EventSink"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.AddScopeEventSinkRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      }
+    },
+    "/v1/scopes/{id}:export-usage": {
+      "get": {
+        "summary": "Exports the usage of a Scope as CSV.",
+        "operationId": "ScopeService_ExportScopeUsage",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/google.api.HttpBody"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "start",
+            "description": "The first UTC day, formatted as YYYY-MM-DD. It defaults to the first day of the current UTC month.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "end",
+            "description": "The last UTC day, formatted as YYYY-MM-DD. It defaults to today.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "recursive",
+            "in": "query",
+            "required": false,
+            "type": "boolean"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      }
+    },
+    "/v1/scopes/{id}:inactivity-policy": {
+      "get": {
+        "summary": "Gets the inactivity policy of a Scope.",
+        "operationId": "ScopeService_GetScopeInactivityPolicy",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.scopes.v1.InactivityPolicy"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      },
+      "post": {
+        "summary": "Sets the inactivity policy of a Scope.",
+        "operationId": "ScopeService_SetScopeInactivityPolicy",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.scopes.v1.InactivityPolicy"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetScopeInactivityPolicyRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      }
+    },
+    "/v1/scopes/{id}:project-template": {
+      "get": {
+        "summary": "Gets the project template of an org.",
+        "operationId": "ScopeService_GetScopeProjectTemplate",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.scopes.v1.ProjectTemplate"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      },
+      "delete": {
+        "summary": "Removes the project template of an org.",
+        "operationId": "ScopeService_DeleteScopeProjectTemplate",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.scopes.v1.ProjectTemplate"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      },
+      "post": {
+        "summary": "Sets the project template of an org.",
+        "operationId": "ScopeService_SetScopeProjectTemplate",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.scopes.v1.ProjectTemplate"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.scopes.v1.ProjectTemplate"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      }
+    },
+    "/v1/scopes/{id}:security-events": {
+      "get": {
+        "summary": "Gets the counts of the security events of a Scope.",
+        "operationId": "ScopeService_GetScopeSecurityEvents",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.GetScopeSecurityEventsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "start",
+            "description": "The start of the counted events. It defaults to a day before the end.",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "date-time"
+          },
+          {
+            "name": "end",
+            "description": "The end of the counted events. It defaults to now.",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "date-time"
+          },
+          {
+            "name": "bucket",
+            "description": "The length of the time buckets: hour, the default, or day.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "recursive",
+            "in": "query",
+            "required": false,
+            "type": "boolean"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      }
+    },
+    "/v1/scopes/{id}:usage": {
+      "get": {
+        "summary": "Gets the usage of a Scope.",
+        "operationId": "ScopeService_GetScopeUsage",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.GetScopeUsageResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "start",
+            "description": "The first UTC day, formatted as YYYY-MM-DD. It defaults to the first day of the current UTC month.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "end",
+            "description": "The last UTC day, formatted as YYYY-MM-DD. It defaults to today.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "recursive",
+            "in": "query",
+            "required": false,
+            "type": "boolean"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.ScopeService"
+        ]
+      }
+    },
+    "/v1/scopes/{scope_id}:inactive-users": {
+      "get": {
+        "summary": "Lists the inactive Users of a Scope.",
+        "operationId": "UserService_ListInactiveUsers",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListInactiveUsersResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "scope_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "days",
+            "in": "query",
+            "required": false,
+            "type": "integer",
+            "format": "int64"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.UserService"
+        ]
+      }
+    },
+    "/v1/sessions": {
+      "get": {
+        "summary": "Lists all Sessions.",
+        "operationId": "SessionService_ListSessions",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListSessionsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "scope_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.SessionService"
+        ]
+      }
+    },
+    "/v1/sessions/{id}": {
+      "get": {
+        "summary": "Gets a single Session.",
+        "operationId": "SessionService_GetSession",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.sessions.v1.Session"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.SessionService"
+        ]
+      }
+    },
+    "/v1/sessions/{id}:cancel": {
+      "post": {
+        "summary": "Cancels a Session.",
+        "operationId": "SessionService_CancelSession",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.sessions.v1.Session"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.CancelSessionRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.SessionService"
+        ]
+      }
+    },
+    "/v1/sessions:connection-stats": {
+      "get": {
+        "summary": "Lists the connection stats of the Sessions of a scope.",
+        "operationId": "SessionService_ListSessionConnectionStats",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListSessionConnectionStatsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "scope_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.SessionService"
+        ]
+      }
+    },
+    "/v1/targets": {
+      "get": {
+        "summary": "Lists all Targets.",
+        "operationId": "TargetService_ListTargets",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListTargetsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "scope_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      },
+      "post": {
+        "summary": "Creates a single Target.",
+        "operationId": "TargetService_CreateTarget",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}": {
+      "get": {
+        "summary": "Gets a single Target.",
+        "operationId": "TargetService_GetTarget",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      },
+      "delete": {
+        "summary": "Deletes a Target.",
+        "operationId": "TargetService_DeleteTarget",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.DeleteTargetResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      },
+      "patch": {
+        "summary": "Updates a Target.",
+        "operationId": "TargetService_UpdateTarget",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+            }
+          },
+          {
+            "name": "update_mask",
+            "in": "query",
+            "required": false,
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}/history": {
+      "get": {
+        "summary": "Gets the history of a Target.",
+        "operationId": "TargetService_GetTargetHistory",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.GetTargetHistoryResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "limit",
+            "description": "The maximum number of entries returned.",
+            "in": "query",
+            "required": false,
+            "type": "integer",
+            "format": "int64"
+          },
+          {
+            "name": "before",
+            "description": "Returns only the entries older than the entry with this ID, to read the next page.",
+            "in": "query",
+            "required": false,
+            "type": "integer",
+            "format": "int64"
+          },
+          {
+            "name": "actor_id",
+            "description": "Returns only the changes made by the User with this ID.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:add-host-sets": {
+      "post": {
+        "summary": "Adds existing Host Sets to a Target.",
+        "operationId": "TargetService_AddTargetHostSets",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.AddTargetHostSetsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:authorize-session": {
+      "post": {
+        "summary": "Authorizes a Session.",
+        "operationId": "TargetService_AuthorizeSession",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.SessionAuthorization"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "description": "The ID of the target. Required unless some combination of scope_id/scope_name and name are set.",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.AuthorizeSessionRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:bandwidth-limit": {
+      "get": {
+        "summary": "Gets the bandwidth limits of a Target.",
+        "operationId": "TargetService_GetTargetBandwidthLimit",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.BandwidthLimit"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      },
+      "post": {
+        "summary": "Sets the bandwidth limits of a Target.",
+        "operationId": "TargetService_SetTargetBandwidthLimit",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.BandwidthLimit"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetTargetBandwidthLimitRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:clone": {
+      "post": {
+        "summary": "Creates a copy of a Target.",
+        "operationId": "TargetService_CloneTarget",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.CloneTargetRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:connection-authorization": {
+      "get": {
+        "summary": "Gets whether connections to Sessions of the Target must be authorized again.",
+        "operationId": "TargetService_GetTargetConnectionAuthorization",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionAuthorization"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      },
+      "post": {
+        "summary": "Sets whether connections to Sessions of the Target must be authorized again.",
+        "operationId": "TargetService_SetTargetConnectionAuthorization",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionAuthorization"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetTargetConnectionAuthorizationRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:connection-metadata": {
+      "get": {
+        "summary": "Gets the connection metadata of a Target.",
+        "operationId": "TargetService_GetTargetConnectionMetadata",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionMetadata"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      },
+      "post": {
+        "summary": "Sets the connection metadata of a Target.",
+        "operationId": "TargetService_SetTargetConnectionMetadata",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionMetadata"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetTargetConnectionMetadataRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:connection-rate-limit": {
+      "get": {
+        "summary": "Gets the connection rate limit of a Target.",
+        "operationId": "TargetService_GetTargetConnectionRateLimit",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionRateLimit"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      },
+      "post": {
+        "summary": "Sets the connection rate limit of a Target.",
+        "operationId": "TargetService_SetTargetConnectionRateLimit",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionRateLimit"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetTargetConnectionRateLimitRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:credential-checkout": {
+      "get": {
+        "summary": "Gets the credential checkout policy of a Target.",
+        "operationId": "TargetService_GetTargetCredentialCheckout",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.CredentialCheckout"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      },
+      "post": {
+        "summary": "Sets the credential checkout policy of a Target.",
+        "operationId": "TargetService_SetTargetCredentialCheckout",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.CredentialCheckout"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetTargetCredentialCheckoutRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:peer-identity": {
+      "get": {
+        "summary": "Gets the peer identity setting of a Target.",
+        "operationId": "TargetService_GetTargetPeerIdentity",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.PeerIdentity"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      },
+      "post": {
+        "summary": "Sets the peer identity setting of a Target.",
+        "operationId": "TargetService_SetTargetPeerIdentity",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.PeerIdentity"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetTargetPeerIdentityRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:remove-host-sets": {
+      "post": {
+        "summary": "Removes Host Sets from the Target.",
+        "operationId": "TargetService_RemoveTargetHostSets",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.RemoveTargetHostSetsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:set-host-sets": {
+      "post": {
+        "summary": "Sets the Host Sets on the Target.",
+        "operationId": "TargetService_SetTargetHostSets",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetTargetHostSetsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:test-connection": {
+      "post": {
+        "summary": "Tests the connectivity from a worker to the Hosts of a Target.",
+        "operationId": "TargetService_TestTargetConnection",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionTest"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.TestTargetConnectionRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets/{id}:user-name-template": {
+      "get": {
+        "summary": "Gets the user name template of a Target.",
+        "operationId": "TargetService_GetTargetUserNameTemplate",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.UserNameTemplate"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      },
+      "post": {
+        "summary": "Sets the user name template of a Target.",
+        "operationId": "TargetService_SetTargetUserNameTemplate",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.targets.v1.UserNameTemplate"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetTargetUserNameTemplateRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/targets:batch-get": {
+      "post": {
+        "summary": "Gets multiple Targets with a single request.",
+        "operationId": "TargetService_BatchGetTargets",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.BatchGetTargetsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.BatchGetTargetsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.TargetService"
+        ]
+      }
+    },
+    "/v1/users": {
+      "get": {
+        "summary": "Lists all Users.",
+        "operationId": "UserService_ListUsers",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.ListUsersResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "scope_id",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.UserService"
+        ]
+      },
+      "post": {
+        "summary": "Creates a single User.",
+        "operationId": "UserService_CreateUser",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.users.v1.User"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.users.v1.User"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.UserService"
+        ]
+      }
+    },
+    "/v1/users/self:grants": {
+      "get": {
+        "summary": "Lists the grants which apply to the caller.",
+        "operationId": "UserService_GetSelfGrants",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.GetSelfGrantsResponse"
+            }
+          }
+        },
+        "tags": [
+          "controller.api.services.v1.UserService"
+        ]
+      }
+    },
+    "/v1/users/{id}": {
+      "get": {
+        "summary": "Gets a single User.",
+        "operationId": "UserService_GetUser",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.users.v1.User"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.UserService"
+        ]
+      },
+      "delete": {
+        "summary": "Deletes a User.",
+        "operationId": "UserService_DeleteUser",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.DeleteUserResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.UserService"
+        ]
+      },
+      "patch": {
+        "summary": "Updates a User.",
+        "operationId": "UserService_UpdateUser",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.users.v1.User"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.users.v1.User"
+            }
+          },
+          {
+            "name": "update_mask",
+            "in": "query",
+            "required": false,
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi"
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.UserService"
+        ]
+      }
+    },
+    "/v1/users/{id}:add-accounts": {
+      "post": {
+        "summary": "Associates an Account to a User.",
+        "operationId": "UserService_AddUserAccounts",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.users.v1.User"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.AddUserAccountsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.UserService"
+        ]
+      }
+    },
+    "/v1/users/{id}:remove-accounts": {
+      "post": {
+        "summary": "Removes the specified Accounts from being associated with the provided User.",
+        "operationId": "UserService_RemoveUserAccounts",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.users.v1.User"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.RemoveUserAccountsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.UserService"
+        ]
+      }
+    },
+    "/v1/users/{id}:set-accounts": {
+      "post": {
+        "summary": "Set the Accounts associated to the User to exactly the list of provided in the request, removing any Accounts that are not specified.",
+        "operationId": "UserService_SetUserAccounts",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/controller.api.resources.users.v1.User"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/controller.api.services.v1.SetUserAccountsRequest"
+            }
+          }
+        ],
+        "tags": [
+          "controller.api.services.v1.UserService"
+        ]
+      }
+    }
+  },
+  "definitions": {
+    "controller.api.resources.accounts.v1.Account": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Account.",
+          "readOnly": true
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for the Account.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Optional name for identification purposes."
+        },
+        "description": {
+          "type": "string",
+          "description": "Optional user-set description for identification purposes."
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used in mutation requests, after the initial creation, to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "type": {
+          "type": "string",
+          "description": "The type of this Account."
+        },
+        "auth_method_id": {
+          "type": "string",
+          "description": "The ID of the Auth Method that is associated with this Account."
+        },
+        "attributes": {
+          "type": "object",
+          "description": "The attributes that are applicable for the specific Account type."
+        },
+        "disabled": {
+          "type": "boolean",
+          "description": "Whether the Account may not authenticate. Disabling an Account deletes its auth tokens, while it keeps its User."
+        },
+        "disabled_reason": {
+          "type": "string",
+          "description": "The reason the Account was disabled. It can only be set when disabling the Account."
+        },
+        "disabled_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time the Account was disabled.",
+          "readOnly": true
+        },
+        "annotations": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          },
+          "description": "Custom string metadata of the Account, such as its owner or cost center. An update mask path of \"annotations\" replaces all of them, while \"annotations.\u003ckey\u003e\" sets a single one or, if it is not given, removes it."
+        }
+      },
+      "title": "Account contains all fields related to an Account resource"
+    },
+    "controller.api.resources.authmethods.v1.AuthMethod": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Auth Method.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "The ID of the Scope of which this Auth Method is a part."
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for this Auth method.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Optional name for identification purposes."
+        },
+        "description": {
+          "type": "string",
+          "description": "Optional user-set description for identification purposes."
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used in mutation requests, after the initial creation, to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "type": {
+          "type": "string",
+          "description": "The Auth Method type."
+        },
+        "attributes": {
+          "type": "object",
+          "description": "The attributes that are applicable for the specific Auth Method type."
+        },
+        "annotations": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          },
+          "description": "Custom string metadata of the Auth Method, such as its owner or cost center. An update mask path of \"annotations\" replaces all of them, while \"annotations.\u003ckey\u003e\" sets a single one or, if it is not given, removes it."
+        }
+      },
+      "title": "AuthMethod contains all fields related to an Auth Method resource"
+    },
+    "controller.api.resources.authtokens.v1.AuthToken": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Auth Token.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "The Scope in which this Auth Token was generated."
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for this resource.",
+          "readOnly": true
+        },
+        "token": {
+          "type": "string",
+          "description": "Output only. The token value, which will only be populated after authentication and is only ever visible to the end user whose login request resulted in this Auth Token being created.",
+          "readOnly": true
+        },
+        "user_id": {
+          "type": "string",
+          "description": "Output only. The ID of the User associated with this Auth Token.",
+          "readOnly": true
+        },
+        "auth_method_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Auth Method associated with this Auth Token.",
+          "readOnly": true
+        },
+        "account_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Account associated with this Auth Token.",
+          "readOnly": true
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "approximate_last_used_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The approximate time this Auth Token was last used.",
+          "readOnly": true
+        },
+        "expiration_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this Auth Token expires.",
+          "readOnly": true
+        }
+      },
+      "title": "AuthToken contains all fields related to an Auth Token resource"
+    },
+    "controller.api.resources.groups.v1.Group": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Group.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "The ID of the scope of which this Group is a part."
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for this Group.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Optional name for identification purposes."
+        },
+        "description": {
+          "type": "string",
+          "description": "Optional user-set descripton for identification purposes."
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used in mutation requests, after the initial creation, to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "member_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "Output only. Contains the list of member IDs in this Group.",
+          "readOnly": true
+        },
+        "members": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.groups.v1.Member"
+          },
+          "description": "Output only. The members of this Group.",
+          "readOnly": true
+        },
+        "annotations": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          },
+          "description": "Custom string metadata of the Group, such as its owner or cost center. An update mask path of \"annotations\" replaces all of them, while \"annotations.\u003ckey\u003e\" sets a single one or, if it is not given, removes it."
+        }
+      },
+      "title": "Group contains all fields related to a Group resource"
+    },
+    "controller.api.resources.groups.v1.Member": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the member.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "Output only. The Scope ID of the member.",
+          "readOnly": true
+        }
+      }
+    },
+    "controller.api.resources.hostcatalogs.v1.HostCatalog": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the host.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "The ID of the Scope of which this Host Catalog is a part."
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for this resource.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Optional name for identification purposes."
+        },
+        "description": {
+          "type": "string",
+          "description": "Optional user-set description for identification purposes."
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used in mutation requests, after the initial creation, to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "type": {
+          "type": "string",
+          "description": "The type of Host Catalog."
+        },
+        "attributes": {
+          "type": "object",
+          "description": "Attributes specific to the catalog type."
+        },
+        "annotations": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          },
+          "description": "Custom string metadata of the Host Catalog, such as its owner or cost center. An update mask path of \"annotations\" replaces all of them, while \"annotations.\u003ckey\u003e\" sets a single one or, if it is not given, removes it."
+        }
+      },
+      "title": "HostCatalog manages Hosts and Host Sets"
+    },
+    "controller.api.resources.hostcatalogs.v1.ImportedHost": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Host, unset in a dry run if it doesn't exist yet.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Output only. The name of the Host.",
+          "readOnly": true
+        },
+        "address": {
+          "type": "string",
+          "description": "Output only. The address of the Host.",
+          "readOnly": true
+        },
+        "exists": {
+          "type": "boolean",
+          "description": "Output only. Whether the catalog already had a Host with the name.",
+          "readOnly": true
+        }
+      },
+      "description": "ImportedHost is a Host of an imported inventory file. Existing Hosts of the catalog with the same name are left unchanged."
+    },
+    "controller.api.resources.hostcatalogs.v1.ImportedHostSet": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Host Set, unset in a dry run if it doesn't exist yet.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Output only. The name of the Host Set.",
+          "readOnly": true
+        },
+        "exists": {
+          "type": "boolean",
+          "description": "Output only. Whether the catalog already had a Host Set with the name.",
+          "readOnly": true
+        },
+        "added_hosts": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "Output only. The names of the Hosts added to the Host Set.",
+          "readOnly": true
+        }
+      },
+      "description": "ImportedHostSet is a Host Set of an imported inventory file."
+    },
+    "controller.api.resources.hosts.v1.Host": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Host.",
+          "readOnly": true
+        },
+        "host_catalog_id": {
+          "type": "string",
+          "description": "The Host Catalog of which this Host is a part."
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for this resource.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Optional name for identification purposes."
+        },
+        "description": {
+          "type": "string",
+          "description": "Optional user-set description for identification purposes."
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used in mutation requests, after the initial creation, to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "type": {
+          "type": "string",
+          "description": "The type of the resource."
+        },
+        "host_set_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "Output only. A list of Host Sets containing this Host.",
+          "readOnly": true
+        },
+        "attributes": {
+          "type": "object",
+          "description": "The attributes that are applicable to the specific Host type."
+        },
+        "annotations": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          },
+          "description": "Custom string metadata of the Host, such as its owner or cost center. An update mask path of \"annotations\" replaces all of them, while \"annotations.\u003ckey\u003e\" sets a single one or, if it is not given, removes it."
+        }
+      },
+      "title": "Host contains all fields related to a Host resource"
+    },
+    "controller.api.resources.hostsets.v1.HostSet": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Host Set.",
+          "readOnly": true
+        },
+        "host_catalog_id": {
+          "type": "string",
+          "description": "The Host Catalog of which this Host Set is a part."
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for this resource.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Optional name for identification purposes."
+        },
+        "description": {
+          "type": "string",
+          "description": "Optional user-set description for identification purposes."
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used in mutation requests, after the initial creation, to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "type": {
+          "type": "string",
+          "description": "The type of the Host Set."
+        },
+        "host_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "Output only. A list of Hosts in this Host Set.",
+          "readOnly": true
+        },
+        "attributes": {
+          "type": "object",
+          "description": "The attributes that are applicable for the specific Host Set type."
+        },
+        "annotations": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          },
+          "description": "Custom string metadata of the Host Set, such as its owner or cost center. An update mask path of \"annotations\" replaces all of them, while \"annotations.\u003ckey\u003e\" sets a single one or, if it is not given, removes it."
+        }
+      },
+      "title": "HostSet is a collection of Hosts created and managed by a Host Catalog"
+    },
+    "controller.api.resources.roles.v1.AccessRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the access request.",
+          "readOnly": true
+        },
+        "role_id": {
+          "type": "string",
+          "description": "Output only. The ID of the requested Role.",
+          "readOnly": true
+        },
+        "requester_id": {
+          "type": "string",
+          "description": "Output only. The ID of the User who made the request.",
+          "readOnly": true
+        },
+        "justification": {
+          "type": "string",
+          "description": "Output only. Why the requester needs the Role.",
+          "readOnly": true
+        },
+        "duration_seconds": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Output only. For how long the Role is requested.",
+          "readOnly": true
+        },
+        "status": {
+          "type": "string",
+          "description": "Output only. The status of the request: pending, approved, denied or cancelled.",
+          "readOnly": true
+        },
+        "decider_id": {
+          "type": "string",
+          "description": "Output only. The ID of the User who approved or denied the request.",
+          "readOnly": true
+        },
+        "decision_comment": {
+          "type": "string",
+          "description": "Output only. The comment recorded with the decision.",
+          "readOnly": true
+        },
+        "decision_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time the request was approved or denied.",
+          "readOnly": true
+        },
+        "expiration_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time the Role assignment of an approved request expires.",
+          "readOnly": true
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "events": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.roles.v1.AccessRequestEvent"
+          },
+          "description": "Output only. The audit trail of the request, oldest first. It is only set when reading a single request.",
+          "readOnly": true
+        }
+      },
+      "description": "AccessRequest is a request by a User to be assigned a Role for a limited duration."
+    },
+    "controller.api.resources.roles.v1.AccessRequestEvent": {
+      "type": "object",
+      "properties": {
+        "event": {
+          "type": "string",
+          "description": "Output only. What happened to the request.",
+          "readOnly": true
+        },
+        "actor_id": {
+          "type": "string",
+          "description": "Output only. The ID of the User who acted on the request.",
+          "readOnly": true
+        },
+        "comment": {
+          "type": "string",
+          "description": "Output only. The comment recorded with the event.",
+          "readOnly": true
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time of the event.",
+          "readOnly": true
+        }
+      },
+      "description": "AccessRequestEvent is the creation of an access request or a decision on it."
+    },
+    "controller.api.resources.roles.v1.Grant": {
+      "type": "object",
+      "properties": {
+        "raw": {
+          "type": "string",
+          "description": "Output only. The original user-supplied string.",
+          "readOnly": true
+        },
+        "canonical": {
+          "type": "string",
+          "description": "Output only. The canonically-formatted string.",
+          "readOnly": true
+        },
+        "json": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.GrantJson",
+          "description": "Output only. The JSON representation of the grant.",
+          "readOnly": true
+        }
+      }
+    },
+    "controller.api.resources.roles.v1.GrantJson": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID, if set.",
+          "readOnly": true
+        },
+        "type": {
+          "type": "string",
+          "description": "Output only. The type, if set.",
+          "readOnly": true
+        },
+        "actions": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "Output only. The actions.",
+          "readOnly": true
+        }
+      }
+    },
+    "controller.api.resources.roles.v1.Principal": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the principal.",
+          "readOnly": true
+        },
+        "type": {
+          "type": "string",
+          "description": "Output only. The type of the principal.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "Output only. The Scope of the principal.",
+          "readOnly": true
+        }
+      }
+    },
+    "controller.api.resources.roles.v1.PrincipalExpiration": {
+      "type": "object",
+      "properties": {
+        "principal_id": {
+          "type": "string",
+          "description": "Output only. The ID of the principal.",
+          "readOnly": true
+        },
+        "expiration_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time the principal is removed from the Role.",
+          "readOnly": true
+        }
+      },
+      "description": "PrincipalExpiration is when a temporary principal of a Role is removed from it."
+    },
+    "controller.api.resources.roles.v1.Role": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Role.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "The ID of the Scope containing this Role."
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for this resource.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Optional name for identification purposes."
+        },
+        "description": {
+          "type": "string",
+          "description": "Optional user-set description for identification purposes."
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used in mutation requests, after the initial creation, to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "grant_scope_id": {
+          "type": "string",
+          "description": "The Scope the grants will apply to. If the Role is at the global scope, this can be an org or project. If the Role is at an org scope, this can be a project within the org. It is invalid for this to be anything other than the Role's scope when the Role's scope is a project."
+        },
+        "principal_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "Output only. The IDs (only) of principals that are assigned to this role.",
+          "readOnly": true
+        },
+        "principals": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.roles.v1.Principal"
+          },
+          "description": "Output only. The principals that are assigned to this role.",
+          "readOnly": true
+        },
+        "grant_strings": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "Output only. The grants that this role provides for its principals.",
+          "readOnly": true
+        },
+        "grants": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.roles.v1.Grant"
+          },
+          "description": "Output only. The parsed grant information.",
+          "readOnly": true
+        },
+        "annotations": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          },
+          "description": "Custom string metadata of the Role, such as its owner or cost center. An update mask path of \"annotations\" replaces all of them, while \"annotations.\u003ckey\u003e\" sets a single one or, if it is not given, removes it."
+        }
+      },
+      "title": "Role contains all fields related to a Role resource"
+    },
+    "controller.api.resources.scopes.v1.EventSink": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the event sink.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "Output only. The ID of the org of the event sink.",
+          "readOnly": true
+        },
+        "url": {
+          "type": "string",
+          "description": "The absolute http or https URL the events are delivered to."
+        },
+        "redact_fields": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "The fields of the event payloads whose values are replaced before delivery."
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        }
+      },
+      "description": "EventSink is a webhook of an org receiving the events of the org and of its projects."
+    },
+    "controller.api.resources.scopes.v1.InactivityPolicy": {
+      "type": "object",
+      "properties": {
+        "scope_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Scope.",
+          "readOnly": true
+        },
+        "max_inactive_days": {
+          "type": "integer",
+          "format": "int64",
+          "description": "The number of days after which a User is inactive. 0 is no policy."
+        },
+        "disable_inactive": {
+          "type": "boolean",
+          "description": "Whether inactive Users are disabled."
+        }
+      },
+      "description": "InactivityPolicy is how many days the Users of a Scope may go without logging in before they are reported as inactive and, if disable_inactive is set, disabled."
+    },
+    "controller.api.resources.scopes.v1.ProjectTemplate": {
+      "type": "object",
+      "properties": {
+        "scope_id": {
+          "type": "string",
+          "description": "Output only. The ID of the org.",
+          "readOnly": true
+        },
+        "roles": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.scopes.v1.ProjectTemplateRole"
+          },
+          "description": "The roles created in every new project."
+        },
+        "target_default_port": {
+          "type": "integer",
+          "format": "int64",
+          "description": "The default port of the Targets of a new project."
+        },
+        "target_session_max_seconds": {
+          "type": "integer",
+          "format": "int64",
+          "description": "The default maximum session duration of the Targets of a new project."
+        },
+        "target_session_connection_limit": {
+          "type": "integer",
+          "format": "int32",
+          "description": "The default session connection limit of the Targets of a new project. -1 is unlimited."
+        }
+      },
+      "description": "ProjectTemplate is applied to every project created in an org: its roles are created in the new project and its target settings become the defaults of the Targets of the project. A target setting of 0 keeps the default of Targets."
+    },
+    "controller.api.resources.scopes.v1.ProjectTemplateRole": {
+      "type": "object",
+      "properties": {
+        "name": {
+          "type": "string",
+          "description": "The name of the role, unique within the template."
+        },
+        "description": {
+          "type": "string",
+          "description": "The description of the role."
+        },
+        "grants": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "The grants of the role."
+        }
+      },
+      "description": "ProjectTemplateRole is a role created in every new project of an org."
+    },
+    "controller.api.resources.scopes.v1.Scope": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Scope.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "The ID of the Scope this resource is in. If this is the \"global\" Scope this field will be empty."
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for this resource.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Optional name for identification purposes."
+        },
+        "description": {
+          "type": "string",
+          "description": "Optional user-set descripton for identification purposes."
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used in mutation requests, after the initial creation, to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "type": {
+          "type": "string",
+          "description": "The type of the resource."
+        },
+        "annotations": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          },
+          "description": "Custom string metadata of the Scope, such as its owner or cost center. An update mask path of \"annotations\" replaces all of them, while \"annotations.\u003ckey\u003e\" sets a single one or, if it is not given, removes it."
+        }
+      },
+      "title": "Scope contains all fields related to a Scope resource"
+    },
+    "controller.api.resources.scopes.v1.ScopeInfo": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Scope.",
+          "readOnly": true
+        },
+        "type": {
+          "type": "string",
+          "description": "Output only. The type of the Scope.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Output only. The name of the Scope, if any.",
+          "readOnly": true
+        },
+        "description": {
+          "type": "string",
+          "description": "Output only. The description of the Scope, if any.",
+          "readOnly": true
+        },
+        "parent_scope_id": {
+          "type": "string",
+          "description": "Output only. The ID of the parent Scope, if any. This field will be empty if this is the \"global\" scope.",
+          "readOnly": true
+        }
+      }
+    },
+    "controller.api.resources.scopes.v1.SecurityEventCount": {
+      "type": "object",
+      "properties": {
+        "bucket_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The start of the time bucket.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Scope of the events.",
+          "readOnly": true
+        },
+        "auth_method_id": {
+          "type": "string",
+          "description": "Output only. The ID of the auth method of the events, if they have one.",
+          "readOnly": true
+        },
+        "kind": {
+          "type": "string",
+          "description": "Output only. The kind of the events.",
+          "readOnly": true
+        },
+        "count": {
+          "type": "string",
+          "format": "uint64",
+          "description": "Output only. The number of events.",
+          "readOnly": true
+        }
+      },
+      "description": "SecurityEventCount is the number of security events of a kind of a Scope and auth method in a time bucket."
+    },
+    "controller.api.resources.scopes.v1.UsageItem": {
+      "type": "object",
+      "properties": {
+        "date": {
+          "type": "string",
+          "description": "Output only. The UTC day formatted as YYYY-MM-DD. Totals have none.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Scope.",
+          "readOnly": true
+        },
+        "api_calls": {
+          "type": "string",
+          "format": "uint64",
+          "description": "Output only. The number of API calls about resources of the Scope.",
+          "readOnly": true
+        },
+        "session_minutes": {
+          "type": "number",
+          "format": "double",
+          "description": "Output only. The minutes Sessions of the Scope were active, rounded to the hundredth.",
+          "readOnly": true
+        }
+      },
+      "description": "UsageItem is the number of API calls made in a Scope and the minutes its Sessions were active on a UTC day, or over all days of the range for totals."
+    },
+    "controller.api.resources.sessions.v1.ConnectionStats": {
+      "type": "object",
+      "properties": {
+        "session_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Session.",
+          "readOnly": true
+        },
+        "active_connection_count": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Output only. The number of authorized or connected connections of the Session.",
+          "readOnly": true
+        },
+        "total_connection_count": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Output only. The number of connections of the Session in any state.",
+          "readOnly": true
+        },
+        "bytes_up": {
+          "type": "string",
+          "format": "uint64",
+          "description": "Output only. The sum of the bytes up of the closed connections of the Session.",
+          "readOnly": true
+        },
+        "bytes_down": {
+          "type": "string",
+          "format": "uint64",
+          "description": "Output only. The sum of the bytes down of the closed connections of the Session.",
+          "readOnly": true
+        }
+      },
+      "description": "ConnectionStats summarizes the connections of a Session. Byte totals only include closed connections, since workers report them when a connection closes."
+    },
+    "controller.api.resources.sessions.v1.Session": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Session.",
+          "readOnly": true
+        },
+        "target_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Target that created this Session.",
+          "readOnly": true
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for this resource.",
+          "readOnly": true
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used when canceling this Session to ensure that the operation is acting on a known session state."
+        },
+        "type": {
+          "type": "string",
+          "description": "Output only. Type of the Session (e.g. tcp).",
+          "readOnly": true
+        },
+        "expiration_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. After this time the connection will be expired, e.g. forcefully terminated.",
+          "readOnly": true
+        },
+        "auth_token_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Auth Token used to authenticate.",
+          "readOnly": true
+        },
+        "user_id": {
+          "type": "string",
+          "description": "Output only. The ID of the User that requested the Session.",
+          "readOnly": true
+        },
+        "host_set_id": {
+          "type": "string",
+          "description": "Output only. The Host Set sourcing the Host for this Session.",
+          "readOnly": true
+        },
+        "host_id": {
+          "type": "string",
+          "description": "Output only. The Host used by the Session.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "Output only. The Scope of the Session.",
+          "readOnly": true
+        },
+        "endpoint": {
+          "type": "string",
+          "description": "Output only. The endpoint of the Session; that is, the address to which the worker is proxying data.",
+          "readOnly": true
+        },
+        "states": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.sessions.v1.SessionState"
+          },
+          "description": "Output only. The states of this Session in descending order from the current state to the first.",
+          "readOnly": true
+        },
+        "status": {
+          "type": "string",
+          "description": "Output only. The current status of this Session.",
+          "readOnly": true
+        },
+        "worker_info": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.sessions.v1.WorkerInfo"
+          },
+          "description": "Output only. Worker information given to the client.",
+          "readOnly": true
+        },
+        "certificate": {
+          "type": "string",
+          "format": "byte",
+          "description": "Output only. The certificate generated for the session. Raw DER bytes.",
+          "readOnly": true
+        },
+        "termination_reason": {
+          "type": "string",
+          "description": "Output only. If the session is terminated, this provides a short description as to why.",
+          "readOnly": true
+        }
+      },
+      "title": "Session contains all fields related to a Session resource"
+    },
+    "controller.api.resources.sessions.v1.SessionState": {
+      "type": "object",
+      "properties": {
+        "status": {
+          "type": "string",
+          "description": "The status of the Session, e.g. \"pending\", \"active\", \"canceling\", \"terminated\"."
+        },
+        "start_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time the Session entered this state.",
+          "readOnly": true
+        },
+        "end_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time the Session stopped being in this state.",
+          "readOnly": true
+        }
+      }
+    },
+    "controller.api.resources.sessions.v1.WorkerInfo": {
+      "type": "object",
+      "properties": {
+        "address": {
+          "type": "string",
+          "description": "The address of the worker."
+        }
+      }
+    },
+    "controller.api.resources.targets.v1.BandwidthLimit": {
+      "type": "object",
+      "properties": {
+        "target_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Target.",
+          "readOnly": true
+        },
+        "connection_bytes_per_second": {
+          "type": "string",
+          "format": "uint64",
+          "description": "The cap of each connection of a Session of the Target."
+        },
+        "session_bytes_per_second": {
+          "type": "string",
+          "format": "uint64",
+          "description": "The cap of all connections of a Session of the Target combined."
+        }
+      },
+      "description": "BandwidthLimit is the bandwidth caps workers enforce on the connections of a Target, in bytes per second of both directions combined. A limit of 0 is unlimited."
+    },
+    "controller.api.resources.targets.v1.ConnectionAuthorization": {
+      "type": "object",
+      "properties": {
+        "target_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Target.",
+          "readOnly": true
+        },
+        "required": {
+          "type": "boolean",
+          "description": "If true, the grants of the auth token a Session was authorized with are checked again for each new connection, so revoking them applies to Sessions already established."
+        }
+      },
+      "description": "ConnectionAuthorization is whether each new connection to a Session of a Target must be authorized again."
+    },
+    "controller.api.resources.targets.v1.ConnectionMetadata": {
+      "type": "object",
+      "properties": {
+        "target_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Target.",
+          "readOnly": true
+        },
+        "metadata": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          },
+          "description": "Keys start with a lowercase letter followed by up to 63 lowercase letters, digits or underscores."
+        }
+      },
+      "description": "ConnectionMetadata is the key/value metadata of a Target returned with its Session authorizations, such as the name of the database behind it, which connect helpers use to fill in client flags."
+    },
+    "controller.api.resources.targets.v1.ConnectionRateLimit": {
+      "type": "object",
+      "properties": {
+        "target_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Target.",
+          "readOnly": true
+        },
+        "connections_per_minute": {
+          "type": "integer",
+          "format": "int64",
+          "description": "The number of connections per minute. 0 is unlimited."
+        }
+      },
+      "description": "ConnectionRateLimit is the number of connections workers let each Session of a Target open per minute."
+    },
+    "controller.api.resources.targets.v1.ConnectionTest": {
+      "type": "object",
+      "properties": {
+        "target_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Target.",
+          "readOnly": true
+        },
+        "worker_id": {
+          "type": "string",
+          "description": "Output only. The name of the worker which tested the connections, the one clients would connect to for a new Session.",
+          "readOnly": true
+        },
+        "results": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionTestResult"
+          },
+          "description": "Output only. The results of the connections to the Hosts.",
+          "readOnly": true
+        }
+      },
+      "description": "ConnectionTest is the result of testing the connectivity from a worker to the Hosts of a Target."
+    },
+    "controller.api.resources.targets.v1.ConnectionTestResult": {
+      "type": "object",
+      "properties": {
+        "host_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Host.",
+          "readOnly": true
+        },
+        "endpoint": {
+          "type": "string",
+          "description": "Output only. The endpoint of the Host.",
+          "readOnly": true
+        },
+        "reachable": {
+          "type": "boolean",
+          "description": "Output only. True if the worker connected to the endpoint and, when requested, completed a TLS handshake with it.",
+          "readOnly": true
+        },
+        "latency_ms": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Output only. The time in milliseconds the worker took to connect.",
+          "readOnly": true
+        },
+        "error": {
+          "type": "string",
+          "description": "Output only. Why the endpoint is not reachable.",
+          "readOnly": true
+        }
+      },
+      "description": "ConnectionTestResult is the result of the connection from a worker to the endpoint of a Host."
+    },
+    "controller.api.resources.targets.v1.CredentialCheckout": {
+      "type": "object",
+      "properties": {
+        "target_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Target.",
+          "readOnly": true
+        },
+        "mode": {
+          "type": "string",
+          "description": "How sessions wait for the credential while it is checked out: reject or queue. Empty means the Target has no policy."
+        },
+        "queue_timeout_seconds": {
+          "type": "integer",
+          "format": "int64",
+          "description": "How long a queued session waits for the credential. Only allowed in the queue mode."
+        },
+        "rotate_on_check_in": {
+          "type": "boolean",
+          "description": "Whether the credential is rotated when it is checked back in."
+        },
+        "holder_user_id": {
+          "type": "string",
+          "description": "Output only. The User holding the credential, only set while it is checked out.",
+          "readOnly": true
+        },
+        "holder_session_id": {
+          "type": "string",
+          "description": "Output only. The Session holding the credential, only set while it is checked out.",
+          "readOnly": true
+        }
+      },
+      "description": "CredentialCheckout is the credential checkout policy of a Target and the current holder of its credential."
+    },
+    "controller.api.resources.targets.v1.HistoryChange": {
+      "type": "object",
+      "properties": {
+        "type": {
+          "type": "string",
+          "description": "Output only. The type name of the message, e.g. target_tcp.",
+          "readOnly": true
+        },
+        "operation": {
+          "type": "string",
+          "description": "Output only. One of create, update or delete.",
+          "readOnly": true
+        },
+        "fields": {
+          "type": "object",
+          "description": "Output only. The values of the fields set by the change, keyed by their proto names. An update only includes the fields it changed. The values of sensitive fields are redacted.",
+          "readOnly": true
+        },
+        "null_fields": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "Output only. The fields set to null by an update.",
+          "readOnly": true
+        }
+      },
+      "description": "HistoryChange is a message of an oplog entry rendered for display."
+    },
+    "controller.api.resources.targets.v1.HistoryEntry": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Output only. The ID of the oplog entry.",
+          "readOnly": true
+        },
+        "create_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time the change was made.",
+          "readOnly": true
+        },
+        "actor_id": {
+          "type": "string",
+          "description": "Output only. The ID of the User who made the change, if it was recorded.",
+          "readOnly": true
+        },
+        "changes": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.targets.v1.HistoryChange"
+          },
+          "description": "Output only. The changes of the entry.",
+          "readOnly": true
+        }
+      },
+      "description": "HistoryEntry is an oplog entry of a Target rendered for display."
+    },
+    "controller.api.resources.targets.v1.HostSet": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Host Set.",
+          "readOnly": true
+        },
+        "host_catalog_id": {
+          "type": "string",
+          "description": "Output only. The Host Catalog to which this Host Set belongs.",
+          "readOnly": true
+        }
+      }
+    },
+    "controller.api.resources.targets.v1.PeerIdentity": {
+      "type": "object",
+      "properties": {
+        "target_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Target.",
+          "readOnly": true
+        },
+        "mode": {
+          "type": "string",
+          "description": "proxy_v2, for a PROXY protocol version 2 header sent before each connection, or empty if the identity isn't passed."
+        }
+      },
+      "description": "PeerIdentity is how workers pass the identity of the User of a Session to the endpoints of a Target."
+    },
+    "controller.api.resources.targets.v1.SessionAuthorization": {
+      "type": "object",
+      "properties": {
+        "session_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Session.",
+          "readOnly": true
+        },
+        "target_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Target authorizing this Session.",
+          "readOnly": true
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for this resource.",
+          "readOnly": true
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "user_id": {
+          "type": "string",
+          "description": "Output only. The User for which this Session was authorized.",
+          "readOnly": true
+        },
+        "host_set_id": {
+          "type": "string",
+          "description": "Output only. The Host Set containing the Host being used for this Session.",
+          "readOnly": true
+        },
+        "host_id": {
+          "type": "string",
+          "description": "Output only. The Host whose address is being used as the endpoint for this Session.",
+          "readOnly": true
+        },
+        "type": {
+          "type": "string",
+          "description": "Output only. Type of the Session (e.g. tcp, ssh, etc.).",
+          "readOnly": true
+        },
+        "authorization_token": {
+          "type": "string",
+          "description": "Output only. The marshaled SessionAuthorizationData message containing all information that the proxy needs.",
+          "readOnly": true
+        },
+        "endpoint": {
+          "type": "string",
+          "description": "Output only. The endpoint address that the worker will connect to, useful for setting TLS parameters.",
+          "readOnly": true
+        },
+        "connection_metadata": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          },
+          "description": "Output only. The connection metadata of the Target, such as the name of the database behind it, which connect helpers use to fill in client flags.",
+          "readOnly": true
+        }
+      },
+      "description": "SessionAuthorization contains all fields related to authorization for a Session. It's in the Targets package because it's returned by a Target's authorize action."
+    },
+    "controller.api.resources.targets.v1.Target": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the resource.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "The Scope of of this resource. This must be defined for creation of this resource, but is otherwise output only."
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for this resource.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Required name for identification purposes."
+        },
+        "description": {
+          "type": "string",
+          "description": "Optional user-set description for identification purposes."
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used in mutation requests, after the initial creation, to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "type": {
+          "type": "string",
+          "description": "The type of the Target."
+        },
+        "host_set_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "The IDs of the Host Sets associated with this Target."
+        },
+        "host_sets": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.targets.v1.HostSet"
+          },
+          "description": "Output only. The Host Sets associated with this Target.",
+          "readOnly": true
+        },
+        "session_max_seconds": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Maximum total lifetime of a created Session, in seconds."
+        },
+        "session_connection_limit": {
+          "type": "integer",
+          "format": "int32",
+          "description": "Maximum number of connections allowed in a Session.  Unlimited is indicated by the value -1."
+        },
+        "attributes": {
+          "type": "object",
+          "description": "The attributes that are applicable for the specific Target."
+        },
+        "annotations": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          },
+          "description": "Custom string metadata of the Target, such as its owner or cost center. An update mask path of \"annotations\" replaces all of them, while \"annotations.\u003ckey\u003e\" sets a single one or, if it is not given, removes it."
+        }
+      },
+      "title": "Target contains all fields related to a Target resource"
+    },
+    "controller.api.resources.targets.v1.UserNameTemplate": {
+      "type": "object",
+      "properties": {
+        "target_id": {
+          "type": "string",
+          "description": "Output only. The ID of the Target.",
+          "readOnly": true
+        },
+        "template": {
+          "type": "string",
+          "description": "The template, e.g. \"{{user}}_admin\". An empty template injects the name of the User."
+        }
+      },
+      "description": "UserNameTemplate is the template of the user name protocol handlers inject into the connections of the Sessions of a Target."
+    },
+    "controller.api.resources.users.v1.Account": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the Account.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "Output only. The Scope containing the Account.",
+          "readOnly": true
+        },
+        "last_login_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The last time the User logged in with the Account, unset if it never did.",
+          "readOnly": true
+        }
+      }
+    },
+    "controller.api.resources.users.v1.Grant": {
+      "type": "object",
+      "properties": {
+        "scope_id": {
+          "type": "string",
+          "description": "Output only. The Scope of the role of the grant.",
+          "readOnly": true
+        },
+        "grant": {
+          "type": "string",
+          "description": "Output only. The canonical form of the grant on the role.",
+          "readOnly": true
+        },
+        "resolved": {
+          "type": "string",
+          "description": "Output only. The grant with its templates resolved for the User.",
+          "readOnly": true
+        }
+      },
+      "description": "Grant is a grant of a role which applies to a User."
+    },
+    "controller.api.resources.users.v1.InactiveUser": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the User.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Output only. The name of the User.",
+          "readOnly": true
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time the User was created.",
+          "readOnly": true
+        },
+        "last_login_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The last time the User logged in, unset if it never did.",
+          "readOnly": true
+        },
+        "disabled": {
+          "type": "boolean",
+          "description": "Output only. Whether the User is disabled.",
+          "readOnly": true
+        }
+      },
+      "description": "InactiveUser is a User which has not logged in for longer than allowed."
+    },
+    "controller.api.resources.users.v1.User": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Output only. The ID of the User.",
+          "readOnly": true
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "The ID of the Scope this resource is in."
+        },
+        "scope": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ScopeInfo",
+          "description": "Output only. Scope information for this resource.",
+          "readOnly": true
+        },
+        "name": {
+          "type": "string",
+          "description": "Optional name for identification purposes."
+        },
+        "description": {
+          "type": "string",
+          "description": "Optional user-set description for identification purposes."
+        },
+        "created_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was created.",
+          "readOnly": true
+        },
+        "updated_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time this resource was last updated.",
+          "readOnly": true
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used in mutation requests, after the initial creation, to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "account_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "Output only. Contains the list of Account IDs linked to this User.",
+          "readOnly": true
+        },
+        "accounts": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.users.v1.Account"
+          },
+          "description": "Output only. The Accounts linked to this User.",
+          "readOnly": true
+        },
+        "disabled": {
+          "type": "boolean",
+          "description": "Whether the User may not authenticate. Disabling a User deletes its auth tokens, while it keeps its Accounts, memberships and roles."
+        },
+        "disabled_reason": {
+          "type": "string",
+          "description": "The reason the User was disabled. It can only be set when disabling the User."
+        },
+        "disabled_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The time the User was disabled.",
+          "readOnly": true
+        },
+        "last_login_time": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Output only. The last time the User logged in with any of its Accounts, unset if it never did.",
+          "readOnly": true
+        },
+        "annotations": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          },
+          "description": "Custom string metadata of the User, such as its owner or cost center. An update mask path of \"annotations\" replaces all of them, while \"annotations.\u003ckey\u003e\" sets a single one or, if it is not given, removes it."
+        }
+      },
+      "title": "User contains all fields related to a User resource"
+    },
+    "controller.api.services.v1.AddGroupMembersRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "member_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.AddGroupMembersResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+        }
+      }
+    },
+    "controller.api.services.v1.AddHostSetHostsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "host_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "A list of Host IDs which will be added to this Host Set. Each Host referenced here must be a child of the same Host Catalog of which this Host Set is a child."
+        }
+      }
+    },
+    "controller.api.services.v1.AddHostSetHostsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+        }
+      }
+    },
+    "controller.api.services.v1.AddRoleGrantsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "grant_strings": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.AddRoleGrantsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+        }
+      }
+    },
+    "controller.api.services.v1.AddRolePrincipalsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "principal_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.AddRolePrincipalsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+        }
+      }
+    },
+    "controller.api.services.v1.AddScopeEventSinkRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "url": {
+          "type": "string"
+        },
+        "secret": {
+          "type": "string"
+        },
+        "redact_fields": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.AddScopeEventSinkResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.EventSink"
+        }
+      }
+    },
+    "controller.api.services.v1.AddTargetHostSetsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "host_set_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.AddTargetHostSetsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+        }
+      }
+    },
+    "controller.api.services.v1.AddTemporaryRolePrincipalsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "principal_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "expiration_time": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "controller.api.services.v1.AddTemporaryRolePrincipalsResponse": {
+      "type": "object",
+      "properties": {
+        "role_id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "The version of the Role, for further changes to it."
+        },
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.roles.v1.PrincipalExpiration"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.AddUserAccountsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "The version ensures the User hasn't changed since it was last retrieved and if it has the request will fail."
+        },
+        "account_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.AddUserAccountsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.users.v1.User"
+        }
+      }
+    },
+    "controller.api.services.v1.ApproveAccessRequestRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "comment": {
+          "type": "string",
+          "description": "The comment recorded with the decision."
+        }
+      }
+    },
+    "controller.api.services.v1.ApproveAccessRequestResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.AccessRequest"
+        }
+      }
+    },
+    "controller.api.services.v1.AuthenticateRequest": {
+      "type": "object",
+      "properties": {
+        "auth_method_id": {
+          "type": "string",
+          "description": "The ID of the Auth Method in the system that should be used for authentication."
+        },
+        "token_type": {
+          "type": "string",
+          "description": "This can be \"cookie\" or \"token\". If not provided, \"token\" will be used. \"cookie\" activates a split-cookie method where the token is split partially between http-only and regular cookies in order to keep it safe from rogue JS in the browser."
+        },
+        "credentials": {
+          "type": "object",
+          "description": "Credentials are passed to the Auth Method; the valid keys and values depend on the type of Auth Method."
+        }
+      }
+    },
+    "controller.api.services.v1.AuthenticateResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.authtokens.v1.AuthToken"
+        },
+        "token_type": {
+          "type": "string"
+        }
+      }
+    },
+    "controller.api.services.v1.AuthorizeSessionRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "The ID of the target. Required unless some combination of scope_id/scope_name and name are set."
+        },
+        "name": {
+          "type": "string",
+          "description": "The name of the target. When using this, scope_id or scope_name must be set."
+        },
+        "scope_id": {
+          "type": "string",
+          "description": "The scope ID containing the target, if specifying the target by name."
+        },
+        "scope_name": {
+          "type": "string",
+          "description": "The scope name containing the target, if specifying the target by name."
+        },
+        "host_id": {
+          "type": "string",
+          "description": "An optional parameter allowing specification of the particular Host within the Target's configured Host Sets to connect to during this Session."
+        }
+      }
+    },
+    "controller.api.services.v1.AuthorizeSessionResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.SessionAuthorization"
+        }
+      }
+    },
+    "controller.api.services.v1.BatchGetTargetsRequest": {
+      "type": "object",
+      "properties": {
+        "ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.BatchGetTargetsResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.CancelAccessRequestRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        }
+      }
+    },
+    "controller.api.services.v1.CancelAccessRequestResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.AccessRequest"
+        }
+      }
+    },
+    "controller.api.services.v1.CancelSessionRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64"
+        }
+      }
+    },
+    "controller.api.services.v1.CancelSessionResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.sessions.v1.Session"
+        }
+      }
+    },
+    "controller.api.services.v1.ChangePasswordRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "current_password": {
+          "type": "string"
+        },
+        "new_password": {
+          "type": "string"
+        }
+      }
+    },
+    "controller.api.services.v1.ChangePasswordResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+        }
+      }
+    },
+    "controller.api.services.v1.CloneHostSetRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "name": {
+          "type": "string",
+          "description": "The name of the new Host Set."
+        },
+        "description": {
+          "type": "string",
+          "description": "The description of the new Host Set. The one of the cloned Host Set is used if it is not set."
+        }
+      }
+    },
+    "controller.api.services.v1.CloneHostSetResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+        }
+      }
+    },
+    "controller.api.services.v1.CloneTargetRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "name": {
+          "type": "string",
+          "description": "The name of the new Target."
+        },
+        "description": {
+          "type": "string",
+          "description": "The description of the new Target. The one of the cloned Target is used if it is not set."
+        }
+      }
+    },
+    "controller.api.services.v1.CloneTargetResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+        }
+      }
+    },
+    "controller.api.services.v1.CreateAccountResponse": {
+      "type": "object",
+      "properties": {
+        "uri": {
+          "type": "string"
+        },
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+        }
+      }
+    },
+    "controller.api.services.v1.CreateAuthMethodResponse": {
+      "type": "object",
+      "properties": {
+        "uri": {
+          "type": "string"
+        },
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.authmethods.v1.AuthMethod"
+        }
+      }
+    },
+    "controller.api.services.v1.CreateGroupResponse": {
+      "type": "object",
+      "properties": {
+        "uri": {
+          "type": "string"
+        },
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+        }
+      }
+    },
+    "controller.api.services.v1.CreateHostCatalogResponse": {
+      "type": "object",
+      "properties": {
+        "uri": {
+          "type": "string"
+        },
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hostcatalogs.v1.HostCatalog"
+        }
+      }
+    },
+    "controller.api.services.v1.CreateHostResponse": {
+      "type": "object",
+      "properties": {
+        "uri": {
+          "type": "string"
+        },
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hosts.v1.Host"
+        }
+      }
+    },
+    "controller.api.services.v1.CreateHostSetResponse": {
+      "type": "object",
+      "properties": {
+        "uri": {
+          "type": "string"
+        },
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+        }
+      }
+    },
+    "controller.api.services.v1.CreateRoleResponse": {
+      "type": "object",
+      "properties": {
+        "uri": {
+          "type": "string"
+        },
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+        }
+      }
+    },
+    "controller.api.services.v1.CreateScopeResponse": {
+      "type": "object",
+      "properties": {
+        "uri": {
+          "type": "string"
+        },
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.Scope"
+        }
+      }
+    },
+    "controller.api.services.v1.CreateTargetResponse": {
+      "type": "object",
+      "properties": {
+        "uri": {
+          "type": "string"
+        },
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+        }
+      }
+    },
+    "controller.api.services.v1.CreateUserResponse": {
+      "type": "object",
+      "properties": {
+        "uri": {
+          "type": "string"
+        },
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.users.v1.User"
+        }
+      }
+    },
+    "controller.api.services.v1.DeleteAccountResponse": {
+      "type": "object"
+    },
+    "controller.api.services.v1.DeleteAuthMethodResponse": {
+      "type": "object"
+    },
+    "controller.api.services.v1.DeleteAuthTokenResponse": {
+      "type": "object"
+    },
+    "controller.api.services.v1.DeleteGroupResponse": {
+      "type": "object"
+    },
+    "controller.api.services.v1.DeleteHostCatalogResponse": {
+      "type": "object"
+    },
+    "controller.api.services.v1.DeleteHostResponse": {
+      "type": "object"
+    },
+    "controller.api.services.v1.DeleteHostSetResponse": {
+      "type": "object"
+    },
+    "controller.api.services.v1.DeleteRoleResponse": {
+      "type": "object"
+    },
+    "controller.api.services.v1.DeleteScopeProjectTemplateResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ProjectTemplate"
+        }
+      }
+    },
+    "controller.api.services.v1.DeleteScopeResponse": {
+      "type": "object"
+    },
+    "controller.api.services.v1.DeleteTargetResponse": {
+      "type": "object"
+    },
+    "controller.api.services.v1.DeleteUserResponse": {
+      "type": "object"
+    },
+    "controller.api.services.v1.DenyAccessRequestRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "comment": {
+          "type": "string",
+          "description": "The comment recorded with the decision."
+        }
+      }
+    },
+    "controller.api.services.v1.DenyAccessRequestResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.AccessRequest"
+        }
+      }
+    },
+    "controller.api.services.v1.ExchangeAuthTokenRequest": {
+      "type": "object",
+      "properties": {
+        "grants": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.services.v1.ExchangeAuthTokenRequest.Grant"
+          }
+        },
+        "time_to_live_seconds": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "target_id": {
+          "type": "string"
+        }
+      }
+    },
+    "controller.api.services.v1.ExchangeAuthTokenRequest.Grant": {
+      "type": "object",
+      "properties": {
+        "scope_id": {
+          "type": "string"
+        },
+        "grant": {
+          "type": "string"
+        }
+      },
+      "description": "Grant is a grant of the caller's Auth Token, given as returned by\nusers/self:grants, for the derived Auth Token to keep."
+    },
+    "controller.api.services.v1.ExchangeAuthTokenResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.authtokens.v1.AuthToken"
+        }
+      }
+    },
+    "controller.api.services.v1.GetAccessRequestResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.AccessRequest"
+        }
+      }
+    },
+    "controller.api.services.v1.GetAccountResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+        }
+      }
+    },
+    "controller.api.services.v1.GetAuthMethodResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.authmethods.v1.AuthMethod"
+        }
+      }
+    },
+    "controller.api.services.v1.GetAuthTokenResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.authtokens.v1.AuthToken"
+        }
+      }
+    },
+    "controller.api.services.v1.GetGroupResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+        }
+      }
+    },
+    "controller.api.services.v1.GetHostCatalogResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hostcatalogs.v1.HostCatalog"
+        }
+      }
+    },
+    "controller.api.services.v1.GetHostResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hosts.v1.Host"
+        }
+      }
+    },
+    "controller.api.services.v1.GetHostSetResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+        }
+      }
+    },
+    "controller.api.services.v1.GetRoleResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+        }
+      }
+    },
+    "controller.api.services.v1.GetScopeInactivityPolicyResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.InactivityPolicy"
+        }
+      }
+    },
+    "controller.api.services.v1.GetScopeProjectTemplateResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ProjectTemplate"
+        }
+      }
+    },
+    "controller.api.services.v1.GetScopeResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.Scope"
+        }
+      }
+    },
+    "controller.api.services.v1.GetScopeSecurityEventsResponse": {
+      "type": "object",
+      "properties": {
+        "scope_id": {
+          "type": "string"
+        },
+        "recursive": {
+          "type": "boolean"
+        },
+        "start": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "end": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "bucket": {
+          "type": "string"
+        },
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.scopes.v1.SecurityEventCount"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.GetScopeUsageResponse": {
+      "type": "object",
+      "properties": {
+        "scope_id": {
+          "type": "string"
+        },
+        "recursive": {
+          "type": "boolean"
+        },
+        "start": {
+          "type": "string"
+        },
+        "end": {
+          "type": "string"
+        },
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.scopes.v1.UsageItem"
+          }
+        },
+        "totals": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.scopes.v1.UsageItem"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.GetSelfGrantsResponse": {
+      "type": "object",
+      "properties": {
+        "user_id": {
+          "type": "string"
+        },
+        "grants": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.users.v1.Grant"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.GetSessionResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.sessions.v1.Session"
+        }
+      }
+    },
+    "controller.api.services.v1.GetTargetBandwidthLimitResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.BandwidthLimit"
+        }
+      }
+    },
+    "controller.api.services.v1.GetTargetConnectionAuthorizationResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionAuthorization"
+        }
+      }
+    },
+    "controller.api.services.v1.GetTargetConnectionMetadataResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionMetadata"
+        }
+      }
+    },
+    "controller.api.services.v1.GetTargetConnectionRateLimitResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionRateLimit"
+        }
+      }
+    },
+    "controller.api.services.v1.GetTargetCredentialCheckoutResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.CredentialCheckout"
+        }
+      }
+    },
+    "controller.api.services.v1.GetTargetHistoryResponse": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.targets.v1.HistoryEntry"
+          }
+        },
+        "next_before": {
+          "type": "integer",
+          "format": "int64",
+          "description": "The before parameter of the request for the next page, if any."
+        }
+      }
+    },
+    "controller.api.services.v1.GetTargetPeerIdentityResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.PeerIdentity"
+        }
+      }
+    },
+    "controller.api.services.v1.GetTargetResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+        }
+      }
+    },
+    "controller.api.services.v1.GetTargetUserNameTemplateResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.UserNameTemplate"
+        }
+      }
+    },
+    "controller.api.services.v1.GetUserResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.users.v1.User"
+        }
+      }
+    },
+    "controller.api.services.v1.ImportHostCatalogHostsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "format": {
+          "type": "string",
+          "description": "The format of the content: known_hosts, ssh_config or ansible_inventory."
+        },
+        "content": {
+          "type": "string",
+          "description": "The content of the inventory file."
+        },
+        "host_set_name": {
+          "type": "string",
+          "description": "If set, all Hosts of the file are also added to a Host Set with this name."
+        },
+        "dry_run": {
+          "type": "boolean",
+          "description": "Whether to return the changes without making them."
+        }
+      }
+    },
+    "controller.api.services.v1.ImportHostCatalogHostsResponse": {
+      "type": "object",
+      "properties": {
+        "host_catalog_id": {
+          "type": "string"
+        },
+        "dry_run": {
+          "type": "boolean"
+        },
+        "hosts": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.hostcatalogs.v1.ImportedHost"
+          }
+        },
+        "host_sets": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.hostcatalogs.v1.ImportedHostSet"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.IssueScopedAuthTokenRequest": {
+      "type": "object",
+      "properties": {
+        "preset": {
+          "type": "string",
+          "description": "The least privilege preset restricting the actions of the issued token, e.g. terraform."
+        },
+        "scope_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "The Scopes the issued token may act on resources of."
+        },
+        "time_to_live_seconds": {
+          "type": "integer",
+          "format": "int64",
+          "description": "How long the issued token is valid for. It is capped at the expiration of the caller's token."
+        }
+      }
+    },
+    "controller.api.services.v1.IssueScopedAuthTokenResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.authtokens.v1.AuthToken"
+        }
+      }
+    },
+    "controller.api.services.v1.ListAccountsResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListAuthMethodsResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.authmethods.v1.AuthMethod"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListAuthTokensResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.authtokens.v1.AuthToken"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListGroupsResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListHostCatalogsResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.hostcatalogs.v1.HostCatalog"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListHostSetsResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListHostsResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.hosts.v1.Host"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListInactiveUsersResponse": {
+      "type": "object",
+      "properties": {
+        "scope_id": {
+          "type": "string"
+        },
+        "days": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.users.v1.InactiveUser"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListRoleAccessRequestsResponse": {
+      "type": "object",
+      "properties": {
+        "role_id": {
+          "type": "string"
+        },
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.roles.v1.AccessRequest"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListRolePrincipalExpirationsResponse": {
+      "type": "object",
+      "properties": {
+        "role_id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "The version of the Role, for further changes to it."
+        },
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.roles.v1.PrincipalExpiration"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListRolesResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListScopeEventSinksResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.scopes.v1.EventSink"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListScopesResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.scopes.v1.Scope"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListSessionConnectionStatsResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.sessions.v1.ConnectionStats"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListSessionsResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.sessions.v1.Session"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListTargetsResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.ListUsersResponse": {
+      "type": "object",
+      "properties": {
+        "items": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/controller.api.resources.users.v1.User"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.RefreshAuthTokenRequest": {
+      "type": "object"
+    },
+    "controller.api.services.v1.RefreshAuthTokenResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.authtokens.v1.AuthToken"
+        }
+      }
+    },
+    "controller.api.services.v1.RemoveGroupMembersRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "member_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.RemoveGroupMembersResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+        }
+      }
+    },
+    "controller.api.services.v1.RemoveHostSetHostsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "host_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "A list of Host IDs which will be removed from this Host Set."
+        }
+      }
+    },
+    "controller.api.services.v1.RemoveHostSetHostsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+        }
+      }
+    },
+    "controller.api.services.v1.RemoveRoleGrantsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "grant_strings": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.RemoveRoleGrantsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+        }
+      }
+    },
+    "controller.api.services.v1.RemoveRolePrincipalsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "principal_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.RemoveRolePrincipalsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+        }
+      }
+    },
+    "controller.api.services.v1.RemoveScopeEventSinkResponse": {
+      "type": "object"
+    },
+    "controller.api.services.v1.RemoveTargetHostSetsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "host_set_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.RemoveTargetHostSetsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+        }
+      }
+    },
+    "controller.api.services.v1.RemoveUserAccountsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "The version ensures the User hasn't changed since it was last retrieved and if it has the request will fail."
+        },
+        "account_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.RemoveUserAccountsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.users.v1.User"
+        }
+      }
+    },
+    "controller.api.services.v1.RequestRoleAccessRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "justification": {
+          "type": "string"
+        },
+        "duration_seconds": {
+          "type": "integer",
+          "format": "int64"
+        }
+      }
+    },
+    "controller.api.services.v1.RequestRoleAccessResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.AccessRequest"
+        }
+      }
+    },
+    "controller.api.services.v1.SetGroupMembersRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "member_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.SetGroupMembersResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+        }
+      }
+    },
+    "controller.api.services.v1.SetHostSetHostsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "host_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "A list of Host IDs which will be set on this Host Set. Each Host referenced here must be a child of the same Host Catalog of which this Host Set is a child."
+        }
+      }
+    },
+    "controller.api.services.v1.SetHostSetHostsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+        }
+      }
+    },
+    "controller.api.services.v1.SetPasswordRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "password": {
+          "type": "string"
+        }
+      }
+    },
+    "controller.api.services.v1.SetPasswordResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+        }
+      }
+    },
+    "controller.api.services.v1.SetRoleGrantsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "grant_strings": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.SetRoleGrantsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+        }
+      }
+    },
+    "controller.api.services.v1.SetRolePrincipalsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "principal_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.SetRolePrincipalsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+        }
+      }
+    },
+    "controller.api.services.v1.SetScopeInactivityPolicyRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "max_inactive_days": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "disable_inactive": {
+          "type": "boolean"
+        }
+      }
+    },
+    "controller.api.services.v1.SetScopeInactivityPolicyResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.InactivityPolicy"
+        }
+      }
+    },
+    "controller.api.services.v1.SetScopeProjectTemplateResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.ProjectTemplate"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetBandwidthLimitRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "connection_bytes_per_second": {
+          "type": "string",
+          "format": "uint64"
+        },
+        "session_bytes_per_second": {
+          "type": "string",
+          "format": "uint64"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetBandwidthLimitResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.BandwidthLimit"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetConnectionAuthorizationRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "required": {
+          "type": "boolean"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetConnectionAuthorizationResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionAuthorization"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetConnectionMetadataRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "metadata": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetConnectionMetadataResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionMetadata"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetConnectionRateLimitRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "connections_per_minute": {
+          "type": "integer",
+          "format": "int64"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetConnectionRateLimitResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionRateLimit"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetCredentialCheckoutRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "mode": {
+          "type": "string"
+        },
+        "queue_timeout_seconds": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "rotate_on_check_in": {
+          "type": "boolean"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetCredentialCheckoutResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.CredentialCheckout"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetHostSetsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Version is used to ensure this resource has not changed.\nThe mutation will fail if the version does not match the latest known good version."
+        },
+        "host_set_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetHostSetsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetPeerIdentityRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "mode": {
+          "type": "string"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetPeerIdentityResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.PeerIdentity"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetUserNameTemplateRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "template": {
+          "type": "string"
+        }
+      }
+    },
+    "controller.api.services.v1.SetTargetUserNameTemplateResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.UserNameTemplate"
+        }
+      }
+    },
+    "controller.api.services.v1.SetUserAccountsRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "version": {
+          "type": "integer",
+          "format": "int64",
+          "description": "The version ensures the User hasn't changed since it was last retrieved and if it has the request will fail."
+        },
+        "account_ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "controller.api.services.v1.SetUserAccountsResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.users.v1.User"
+        }
+      }
+    },
+    "controller.api.services.v1.TestTargetConnectionRequest": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "host_id": {
+          "type": "string",
+          "description": "An optional Host of the Target to test only the connection to."
+        },
+        "tls": {
+          "type": "boolean",
+          "description": "Whether to perform a TLS handshake with the Hosts."
+        }
+      }
+    },
+    "controller.api.services.v1.TestTargetConnectionResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.ConnectionTest"
+        }
+      }
+    },
+    "controller.api.services.v1.UpdateAccountResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.accounts.v1.Account"
+        }
+      }
+    },
+    "controller.api.services.v1.UpdateAuthMethodResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.authmethods.v1.AuthMethod"
+        }
+      }
+    },
+    "controller.api.services.v1.UpdateGroupResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.groups.v1.Group"
+        }
+      }
+    },
+    "controller.api.services.v1.UpdateHostCatalogResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hostcatalogs.v1.HostCatalog"
+        }
+      }
+    },
+    "controller.api.services.v1.UpdateHostResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hosts.v1.Host"
+        }
+      }
+    },
+    "controller.api.services.v1.UpdateHostSetResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.hostsets.v1.HostSet"
+        }
+      }
+    },
+    "controller.api.services.v1.UpdateRoleResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.roles.v1.Role"
+        }
+      }
+    },
+    "controller.api.services.v1.UpdateScopeResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.scopes.v1.Scope"
+        }
+      }
+    },
+    "controller.api.services.v1.UpdateTargetResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.targets.v1.Target"
+        }
+      }
+    },
+    "controller.api.services.v1.UpdateUserResponse": {
+      "type": "object",
+      "properties": {
+        "item": {
+          "$ref": "#/definitions/controller.api.resources.users.v1.User"
+        }
+      }
+    },
+    "google.api.HttpBody": {
+      "type": "object",
+      "properties": {
+        "content_type": {
+          "type": "string",
+          "description": "The HTTP Content-Type header value specifying the content type of the body."
+        },
+        "data": {
+          "type": "string",
+          "format": "byte",
+          "description": "The HTTP request/response body as raw binary."
+        },
+        "extensions": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/google.protobuf.Any"
+          },
+          "description": "Application specific response metadata. Must be set in the first response\nfor streaming APIs."
+        }
+      },
+      "description": "Message that represents an arbitrary HTTP body. It should only be used for\npayload formats that can't be represented as JSON, such as raw binary or\nan HTML page.\n\n\nThis message can be used both in streaming and non-streaming API methods in\nthe request as well as the response.\n\nIt can be used as a top-level request field, which is convenient if one\nwants to extract parameters from either the URL or HTTP template into the\nrequest fields and also want access to the raw HTTP body.\n\nExample:\n\n    message GetResourceRequest {\n      // A unique request id.\n      string request_id = 1;\n\n      // The raw HTTP body is bound to this field.\n      google.api.HttpBody http_body = 2;\n    }\n\n    service ResourceService {\n      rpc GetResource(GetResourceRequest) returns (google.api.HttpBody);\n      rpc UpdateResource(google.api.HttpBody) returns\n      (google.protobuf.Empty);\n    }\n\nExample with streaming methods:\n\n    service CaldavService {\n      rpc GetCalendar(stream google.api.HttpBody)\n        returns (stream google.api.HttpBody);\n      rpc UpdateCalendar(stream google.api.HttpBody)\n        returns (stream google.api.HttpBody);\n    }\n\nUse of this type only changes how the request and response bodies are\nhandled, all other features will continue to work unchanged."
+    },
+    "google.protobuf.Any": {
+      "type": "object",
+      "properties": {
+        "type_url": {
+          "type": "string",
+          "description": "A URL/resource name that uniquely identifies the type of the serialized\nprotocol buffer message. This string must contain at least\none \"/\" character. The last segment of the URL's path must represent\nthe fully qualified name of the type (as in\n` + "`" + `path/google.protobuf.Duration` + "`" + `). The name should be in a canonical form\n(e.g., leading \".\" is not accepted).\n\nIn practice, teams usually precompile into the binary all types that they\nexpect it to use in the context of Any. However, for URLs which use the\nscheme ` + "`" + `http` + "`" + `, ` + "`" + `https` + "`" + `, or no scheme, one can optionally set up a type\nserver that maps type URLs to message definitions as follows:\n\n* If no scheme is provided, ` + "`" + `https` + "`" + ` is assumed.\n* An HTTP GET on the URL must yield a [google.protobuf.Type][]\n  value in binary format, or produce an error.\n* Applications are allowed to cache lookup results based on the\n  URL, or have them precompiled into a binary to avoid any\n  lookup. Therefore, binary compatibility needs to be preserved\n  on changes to types. (Use versioned type names to manage\n  breaking changes.)\n\nNote: this functionality is not currently available in the official\nprotobuf release, and it is not used for type URLs beginning with\ntype.googleapis.com. As of May 2023, there are no widely used type server\nimplementations and no plans to implement one.\n\nSchemes other than ` + "`" + `http` + "`" + `, ` + "`" + `https` + "`" + ` (or the empty scheme) might be\nused with implementation specific semantics."
+        },
+        "value": {
+          "type": "string",
+          "format": "byte",
+          "description": "Must be a valid serialized protocol buffer of the above specified type."
+        }
+      },
+      "description": "` + "`" + `Any` + "`" + ` contains an arbitrary serialized protocol buffer message along with a\nURL that describes the type of the serialized message.\n\nProtobuf library provides support to pack/unpack Any values in the form\nof utility functions or additional generated methods of the Any type.\n\nExample 1: Pack and unpack a message in C++.\n\n    Foo foo = ...;\n    Any any;\n    any.PackFrom(foo);\n    ...\n    if (any.UnpackTo(\u0026foo)) {\n      ...\n    }\n\nExample 2: Pack and unpack a message in Java.\n\n    Foo foo = ...;\n    Any any = Any.pack(foo);\n    ...\n    if (any.is(Foo.class)) {\n      foo = any.unpack(Foo.class);\n    }\n    // or ...\n    if (any.isSameTypeAs(Foo.getDefaultInstance())) {\n      foo = any.unpack(Foo.getDefaultInstance());\n    }\n\n Example 3: Pack and unpack a message in Python.\n\n    foo = Foo(...)\n    any = Any()\n    any.Pack(foo)\n    ...\n    if any.Is(Foo.DESCRIPTOR):\n      any.Unpack(foo)\n      ...\n\n Example 4: Pack and unpack a message in Go\n\n     foo := \u0026pb.Foo{...}\n     any, err := anypb.New(foo)\n     if err != nil {\n       ...\n     }\n     ...\n     foo := \u0026pb.Foo{}\n     if err := any.UnmarshalTo(foo); err != nil {\n       ...\n     }\n\nThe pack methods provided by protobuf library will by default use\n'type.googleapis.com/full.type.name' as the type URL and the unpack\nmethods only use the fully qualified type name after the last '/'\nin the type URL, for example \"foo.bar.com/x/y.z\" will yield type\nname \"y.z\".\n\nJSON\n====\nThe JSON representation of an ` + "`" + `Any` + "`" + ` value uses the regular\nrepresentation of the deserialized, embedded message, with an\nadditional field ` + "`" + `@type` + "`" + ` which contains the type URL. Example:\n\n    package google.profile;\n    message Person {\n      string first_name = 1;\n      string last_name = 2;\n    }\n\n    {\n      \"@type\": \"type.googleapis.com/google.profile.Person\",\n      \"firstName\": \u003cstring\u003e,\n      \"lastName\": \u003cstring\u003e\n    }\n\nIf the embedded message type is well-known and has a custom JSON\nrepresentation, that representation will be embedded adding a field\n` + "`" + `value` + "`" + ` which holds the custom JSON in addition to the ` + "`" + `@type` + "`" + `\nfield. Example (for message [google.protobuf.Duration][]):\n\n    {\n      \"@type\": \"type.googleapis.com/google.protobuf.Duration\",\n      \"value\": \"1.212s\"\n    }"
+    },
+    "google.protobuf.NullValue": {
+      "type": "string",
+      "enum": [
+        "NULL_VALUE"
+      ],
+      "default": "NULL_VALUE",
+      "description": "` + "`" + `NullValue` + "`" + ` is a singleton enumeration to represent the null value for the\n` + "`" + `Value` + "`" + ` type union.\n\n The JSON representation for ` + "`" + `NullValue` + "`" + ` is JSON ` + "`" + `null` + "`" + `.\n\n - NULL_VALUE: Null value."
+    }
+  }
+}
+`
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers/workers"
+	"github.com/hashicorp/boundary/internal/target"
+)
+
+// sessionPeerIdentity returns the identity of the user of a session, which is
+// sent to workers looking it up: the name of the user, the user name protocol
+// handlers inject as produced by the user name template of the target, the
+// checkout of the credential of the target by the session if any, and how the
+// target passes the identity to its endpoints.
+func (c *Controller) sessionPeerIdentity(ctx context.Context, sessionId, targetId, userId string) (*workers.PeerIdentity, error) {
+	targetRepo, err := c.TargetRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	p, err := targetRepo.LookupPeerIdentity(ctx, targetId)
+	if err != nil {
+		return nil, fmt.Errorf("session peer identity: %w", err)
+	}
+	peer := &workers.PeerIdentity{Mode: p.Mode}
+
+	checkout, err := targetRepo.LookupCredentialCheckout(ctx, targetId)
+	if err != nil {
+		return nil, fmt.Errorf("session peer identity: %w", err)
+	}
+	if checkout != nil && checkout.SessionId == sessionId {
+		peer.CredentialCheckoutId = checkout.CheckoutId
+	}
+
+	iamRepo, err := c.IamRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	u, _, err := iamRepo.LookupUser(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("session peer identity: %w", err)
+	}
+	if u != nil {
+		peer.UserName = u.Name
+	}
+
+	// Without a template the name of the user is injected as is, as it was
+	// before templates
+	peer.InjectedUserName = peer.UserName
+	ut, err := targetRepo.LookupUserNameTemplate(ctx, targetId)
+	if err != nil {
+		return nil, fmt.Errorf("session peer identity: %w", err)
+	}
+	if ut.Template != "" {
+		if peer.InjectedUserName, err = target.InjectedUserName(ut.Template, userId, peer.UserName); err != nil {
+			return nil, fmt.Errorf("session peer identity: %w", err)
+		}
+	}
+	return peer, nil
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// The modes of a controller, as advertised by its health endpoint and its
+// status in the servers of the cluster.
+const (
+	readWriteMode = servers.ModeReadWrite
+	readOnlyMode  = servers.ModeReadOnly
+)
+
+// readOnlyMessage is the message of the errors with which a read-only
+// controller rejects writes.
+const readOnlyMessage = "This controller is read-only and serves only reads; send this request to a read-write controller."
+
+// mode returns the mode of the controller.
+func (c *Controller) mode() string {
+	if c.conf.RawConfig.Controller.ReadOnly {
+		return readOnlyMode
+	}
+	return readWriteMode
+}
+
+// wrapHandlerWithReadOnly rejects the requests which may write to the
+// database, i.e. all but GET and HEAD requests, with 503 if the controller is
+// read-only, so clients retry them against another controller.
+func wrapHandlerWithReadOnly(h http.Handler, c *Controller) http.Handler {
+	if !c.conf.RawConfig.Controller.ReadOnly {
+		return h
+	}
+	errHandler := handlers.ErrorHandler(c.logger)
+	mar := &runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{
+			UseProtoNames: true,
+		},
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			h.ServeHTTP(w, r)
+		default:
+			errHandler(r.Context(), nil, mar, w, r, handlers.ApiErrorWithCodeAndMessage(codes.Unavailable, readOnlyMessage))
+		}
+	})
+}
+
+// readOnlyUnaryInterceptor rejects the calls of workers, which all write to
+// the database, if the controller is read-only. Workers then use another
+// controller.
+func (c *Controller) readOnlyUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if c.conf.RawConfig.Controller.ReadOnly {
+		return nil, status.Error(codes.Unavailable, "controller is read-only")
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/hashicorp/boundary/globals"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// The classes of endpoints whose request bodies are limited separately.
+const (
+	authenticateEndpointClass = "authenticate"
+	importEndpointClass       = "import"
+	defaultEndpointClass      = "default"
+)
+
+// importHostsSuffix is the suffix of the path of the host catalog action
+// importing hosts from an inventory file.
+const importHostsSuffix = ":import-hosts"
+
+// endpointClasses are all classes of endpoints.
+var endpointClasses = []string{authenticateEndpointClass, importEndpointClass, defaultEndpointClass}
+
+// defaultRequestQueueTimeout is how long a request waits to be served if the
+// configuration doesn't set it.
+const defaultRequestQueueTimeout = 5 * time.Second
+
+// overloadedMessage is the message of the errors with which requests are
+// rejected when the controller is serving as many as it can.
+const overloadedMessage = "This controller is serving too many requests; try again later."
+
+// endpointClass returns the class of the endpoint at path.
+func endpointClass(path string) string {
+	switch {
+	case strings.HasSuffix(path, ":authenticate"), strings.HasPrefix(path, "/v1/auth-tokens:"):
+		return authenticateEndpointClass
+	case strings.HasSuffix(path, importHostsSuffix):
+		return importEndpointClass
+	default:
+		return defaultEndpointClass
+	}
+}
+
+// requestLimiter limits the size of the bodies of requests by endpoint class
+// and the number of requests served at once. Requests which can't be served
+// right away wait in a queue per endpoint class, so a flood of requests to
+// one class can't keep the others from being served.
+type requestLimiter struct {
+	maxBodySizes map[string]int64
+
+	// slots has a value for each request being served; it is nil if the
+	// number isn't limited
+	slots        chan struct{}
+	queues       map[string]chan struct{}
+	queueTimeout time.Duration
+}
+
+// newRequestLimiter returns the limiter of the configuration, or nil if it is
+// nil.
+func newRequestLimiter(conf *config.RequestLimits) (*requestLimiter, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	l := &requestLimiter{
+		maxBodySizes: make(map[string]int64, len(conf.MaxBodySizes)),
+		queueTimeout: conf.QueueTimeoutDuration,
+	}
+	for class, size := range conf.MaxBodySizes {
+		if !validEndpointClass(class) {
+			return nil, fmt.Errorf("unknown endpoint class %q", class)
+		}
+		if size <= 0 {
+			return nil, fmt.Errorf("max body size of endpoint class %q must be positive", class)
+		}
+		l.maxBodySizes[class] = size
+	}
+	switch {
+	case conf.MaxInFlight < 0:
+		return nil, fmt.Errorf("max in flight requests must not be negative")
+	case conf.MaxQueued < 0:
+		return nil, fmt.Errorf("max queued requests must not be negative")
+	case conf.QueueTimeoutDuration < 0:
+		return nil, fmt.Errorf("queue timeout must not be negative")
+	}
+	if l.queueTimeout == 0 {
+		l.queueTimeout = defaultRequestQueueTimeout
+	}
+	if conf.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, conf.MaxInFlight)
+		l.queues = make(map[string]chan struct{}, len(endpointClasses))
+		for _, class := range endpointClasses {
+			l.queues[class] = make(chan struct{}, conf.MaxQueued)
+		}
+	}
+	return l, nil
+}
+
+func validEndpointClass(class string) bool {
+	for _, c := range endpointClasses {
+		if class == c {
+			return true
+		}
+	}
+	return false
+}
+
+// acquire waits until a request of the endpoint class may be served. It
+// returns false if the queue of the class is full, or if the request can't be
+// served before the queue timeout or the end of ctx. Otherwise the returned
+// function must be called once the request is served.
+func (l *requestLimiter) acquire(ctx context.Context, class string) (func(), bool) {
+	if l == nil || l.slots == nil {
+		return func() {}, true
+	}
+	release := func() { <-l.slots }
+	select {
+	case l.slots <- struct{}{}:
+		return release, true
+	default:
+	}
+
+	queue := l.queues[class]
+	select {
+	case queue <- struct{}{}:
+		defer func() { <-queue }()
+	default:
+		return nil, false
+	}
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return release, true
+	case <-timer.C:
+		return nil, false
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// retryAfter returns the number of seconds overloaded clients are asked to
+// wait before sending their requests again.
+func (l *requestLimiter) retryAfter() int {
+	if s := int(l.queueTimeout / time.Second); s > 0 {
+		return s
+	}
+	return 1
+}
+
+// wrapHandlerWithRequestLimits rejects the requests whose bodies are larger
+// than the limit of their endpoint class with 413, and those which can't be
+// served because the controller is serving as many requests as it can with
+// 503. The default class is limited to the max request size of the listener
+// unless configured otherwise. The health endpoint is never limited, so an
+// overloaded controller isn't taken for a dead one.
+func wrapHandlerWithRequestLimits(h http.Handler, c *Controller) http.Handler {
+	l := c.requestLimiter
+	if l == nil {
+		return h
+	}
+	errHandler := handlers.ErrorHandler(c.logger)
+	mar := &runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{
+			UseProtoNames: true,
+		},
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		ctx := r.Context()
+		class := endpointClass(r.URL.Path)
+		maxSize, ok := l.maxBodySizes[class]
+		if ok {
+			ctx = context.WithValue(ctx, globals.ContextMaxRequestSizeTypeKey, maxSize)
+		} else {
+			maxSize, _ = ctx.Value(globals.ContextMaxRequestSizeTypeKey).(int64)
+		}
+		if maxSize > 0 {
+			if r.ContentLength > maxSize {
+				errHandler(ctx, nil, mar, w, r, handlers.RequestTooLargeError(maxSize))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+		}
+
+		release, ok := l.acquire(ctx, class)
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(l.retryAfter()))
+			errHandler(ctx, nil, mar, w, r, handlers.ApiErrorWithCodeAndMessage(codes.Unavailable, overloadedMessage))
+			return
+		}
+		defer release()
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointClass(t *testing.T) {
+	tests := map[string]string{
+		"/v1/auth-methods/ampw_1234567890:authenticate":  authenticateEndpointClass,
+		"/v1/auth-tokens:exchange":                       authenticateEndpointClass,
+		"/v1/host-catalogs/hcst_1234567890:import-hosts": importEndpointClass,
+		"/v1/scopes":                    defaultEndpointClass,
+		"/v1/auth-tokens/at_1234567890": defaultEndpointClass,
+		"/v1/host-catalogs/hcst_1234567890:add-host-sets":  defaultEndpointClass,
+		"/v1/auth-methods/ampw_1234567890:change-password": defaultEndpointClass,
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, endpointClass(path), path)
+	}
+}
+
+func TestNewRequestLimiter(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	l, err := newRequestLimiter(nil)
+	require.NoError(err)
+	assert.Nil(l)
+
+	l, err = newRequestLimiter(&config.RequestLimits{MaxBodySizes: map[string]int64{"import": 1 << 20}})
+	require.NoError(err)
+	assert.Equal(int64(1<<20), l.maxBodySizes[importEndpointClass])
+	assert.Nil(l.slots)
+	assert.Equal(defaultRequestQueueTimeout, l.queueTimeout)
+
+	_, err = newRequestLimiter(&config.RequestLimits{MaxBodySizes: map[string]int64{"upload": 1}})
+	assert.Error(err)
+	_, err = newRequestLimiter(&config.RequestLimits{MaxBodySizes: map[string]int64{"default": 0}})
+	assert.Error(err)
+	_, err = newRequestLimiter(&config.RequestLimits{MaxInFlight: -1})
+	assert.Error(err)
+	_, err = newRequestLimiter(&config.RequestLimits{MaxQueued: -1})
+	assert.Error(err)
+}
+
+func TestRequestLimiter_Acquire(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	l, err := newRequestLimiter(&config.RequestLimits{
+		MaxInFlight:          1,
+		MaxQueued:            1,
+		QueueTimeoutDuration: time.Second,
+	})
+	require.NoError(err)
+	ctx := context.Background()
+
+	release, ok := l.acquire(ctx, defaultEndpointClass)
+	require.True(ok)
+
+	// The second request waits in the queue of its class until the first is
+	// served
+	acquired := make(chan func())
+	go func() {
+		r, ok := l.acquire(ctx, defaultEndpointClass)
+		if !ok {
+			r = nil
+		}
+		acquired <- r
+	}()
+	require.Eventually(func() bool { return len(l.queues[defaultEndpointClass]) == 1 }, time.Second, 10*time.Millisecond)
+
+	// The queue of the class is full, but not the queues of other classes
+	_, ok = l.acquire(ctx, defaultEndpointClass)
+	assert.False(ok)
+	shortCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, ok = l.acquire(shortCtx, authenticateEndpointClass)
+	assert.False(ok)
+
+	release()
+	queuedRelease := <-acquired
+	require.NotNil(queuedRelease)
+	queuedRelease()
+
+	// Requests time out in the queue
+	l.queueTimeout = 50 * time.Millisecond
+	release, ok = l.acquire(ctx, defaultEndpointClass)
+	require.True(ok)
+	_, ok = l.acquire(ctx, defaultEndpointClass)
+	assert.False(ok)
+	release()
+}
+
+func TestWrapHandlerWithRequestLimits(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	l, err := newRequestLimiter(&config.RequestLimits{
+		MaxBodySizes:         map[string]int64{"authenticate": 10},
+		MaxInFlight:          1,
+		QueueTimeoutDuration: 2 * time.Second,
+	})
+	require.NoError(err)
+	c := &Controller{requestLimiter: l, logger: hclog.NewNullLogger()}
+	h := wrapHandlerWithRequestLimits(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}), c)
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/auth-methods/ampw_1234567890:authenticate", strings.NewReader(`{"attributes":{}}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(w.Body.String(), "request_too_large")
+
+	r = httptest.NewRequest(http.MethodPost, "/v1/scopes", strings.NewReader(`{"attributes":{}}`))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(http.StatusTeapot, w.Code)
+
+	// An overloaded controller rejects requests, but still serves its health
+	release, ok := l.acquire(context.Background(), defaultEndpointClass)
+	require.True(ok)
+	defer release()
+	r = httptest.NewRequest(http.MethodGet, "/v1/scopes", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(http.StatusServiceUnavailable, w.Code)
+	assert.Equal("2", w.Header().Get("Retry-After"))
+
+	r = httptest.NewRequest(http.MethodGet, "/health", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(http.StatusTeapot, w.Code)
+}
@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// verifySchema returns an error if the schema of the database differs from
+// the schema its migrations created, unless the configuration only asks for a
+// warning.
+func (c *Controller) verifySchema(ctx context.Context, r db.Reader) error {
+	err := db.VerifySchemaFingerprint(ctx, r)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, db.ErrSchemaDrift) {
+		if dbConf := c.conf.RawConfig.Controller.Database; dbConf != nil && dbConf.SchemaDrift == "warn" {
+			c.logger.Warn("database schema differs from the schema created by its migrations", "error", err)
+			return nil
+		}
+		return fmt.Errorf("database schema differs from the schema created by its migrations; revert the changes made outside of migrations, or run \"boundary database repair\" once they have been checked to be harmless: %w", err)
+	}
+	return fmt.Errorf("error verifying database schema: %w", err)
+}
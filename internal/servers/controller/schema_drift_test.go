@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestController_VerifySchema(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	ctx := context.Background()
+
+	conf := &config.Config{Controller: &config.Controller{Database: &config.Database{}}}
+	c := &Controller{
+		conf:   &Config{Server: &base.Server{}, RawConfig: conf},
+		logger: hclog.NewNullLogger(),
+	}
+	require.NoError(c.verifySchema(ctx, rw))
+
+	_, err := rw.Exec(ctx, "alter table iam_scope add column drifted text", nil)
+	require.NoError(err)
+	err = c.verifySchema(ctx, rw)
+	require.Error(err)
+	assert.True(errors.Is(err, db.ErrSchemaDrift))
+
+	conf.Controller.Database.SchemaDrift = "warn"
+	assert.NoError(c.verifySchema(ctx, rw))
+}
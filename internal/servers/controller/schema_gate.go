@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// schemaCheckInterval is how often a controller waiting for the database to
+// be migrated checks the version of its schema.
+const schemaCheckInterval = 5 * time.Second
+
+// schemaPendingMessage is the message of the errors with which a controller
+// waiting for the database to be migrated rejects requests.
+const schemaPendingMessage = "This controller is waiting for the database to be migrated to the version of its binary; retry later or send this request to another controller."
+
+// schemaGate holds the status of the schema of the database for the binary of
+// the controller. Until the schema is up to date the controller serves only
+// its health, and doesn't run its background jobs.
+type schemaGate struct {
+	lock   sync.RWMutex
+	status *db.SchemaStatus
+}
+
+func (g *schemaGate) set(s *db.SchemaStatus) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.status = s
+}
+
+func (g *schemaGate) get() *db.SchemaStatus {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.status
+}
+
+// ready returns true once the schema is up to date.
+func (g *schemaGate) ready() bool {
+	s := g.get()
+	return s != nil && s.UpToDate()
+}
+
+// migrateSchema applies the migrations of the binary to the database if the
+// controller is the migrator, then records the status of the schema.
+func (c *Controller) migrateSchema(ctx context.Context, r db.Reader) error {
+	if dbConf := c.conf.RawConfig.Controller.Database; dbConf != nil && dbConf.Migrator {
+		url := c.conf.DatabaseUrl
+		if dbConf.MigrationUrl != "" {
+			migrationUrl, err := config.ParseAddress(dbConf.MigrationUrl)
+			if err != nil && err != config.ErrNotAUrl {
+				return fmt.Errorf("error parsing migration url: %w", err)
+			}
+			url = strings.TrimSpace(migrationUrl)
+		}
+		ran, err := db.InitStoreExclusive(ctx, "postgres", url)
+		if err != nil {
+			return fmt.Errorf("error migrating database: %w", err)
+		}
+		if ran {
+			c.logger.Info("database migrated")
+		}
+	}
+	s, err := db.GetSchemaStatus(ctx, "postgres", r)
+	if err != nil {
+		return fmt.Errorf("error getting database schema status: %w", err)
+	}
+	switch {
+	case s.Current > s.Required:
+		c.logger.Warn("database schema is newer than this controller; upgrade this controller", "schema_version", s.Current, "binary_version", s.Required)
+	case !s.UpToDate():
+		c.logger.Warn("database schema is older than this controller; waiting for the migrator controller to migrate it", "schema_version", s.Current, "binary_version", s.Required)
+	}
+	c.schema.set(s)
+	return nil
+}
+
+// awaitSchema checks the schema of the database until it is up to date, then
+// calls ready.
+func (c *Controller) awaitSchema(cancelCtx context.Context, ready func()) {
+	go func() {
+		r := db.New(c.conf.Database)
+		timer := time.NewTimer(schemaCheckInterval)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("schema wait shutting down")
+				return
+
+			case <-timer.C:
+				s, err := db.GetSchemaStatus(cancelCtx, "postgres", r)
+				switch {
+				case err != nil:
+					c.logger.Error("error getting database schema status", "error", err)
+				case !s.UpToDate():
+					c.logger.Trace("waiting for database to be migrated", "schema_version", s.Current, "binary_version", s.Required)
+					c.schema.set(s)
+				default:
+					if err := c.verifySchema(cancelCtx, r); err != nil {
+						c.logger.Error("database migrated but its schema could not be verified", "error", err)
+						break
+					}
+					c.logger.Info("database migrated, serving requests", "schema_version", s.Current)
+					c.schema.set(s)
+					ready()
+					return
+				}
+				timer.Reset(schemaCheckInterval)
+			}
+		}
+	}()
+}
+
+// wrapHandlerWithSchemaGate rejects the API requests until the schema of the
+// database is up to date. The health endpoint, the UI and the other static
+// endpoints are served in the meantime.
+func wrapHandlerWithSchemaGate(h http.Handler, c *Controller) http.Handler {
+	errHandler := handlers.ErrorHandler(c.logger)
+	mar := &runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{
+			UseProtoNames: true,
+		},
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.schema.ready() || !strings.HasPrefix(r.URL.Path, "/v1/") {
+			h.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Retry-After", fmt.Sprintf("%d", int64(schemaCheckInterval/time.Second)))
+		errHandler(r.Context(), nil, mar, w, r, handlers.ApiErrorWithCodeAndMessage(codes.Unavailable, schemaPendingMessage))
+	})
+}
+
+// schemaGateUnaryInterceptor rejects the calls of workers until the schema
+// of the database is up to date. Workers then use another controller.
+func (c *Controller) schemaGateUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !c.schema.ready() {
+		return nil, status.Error(codes.Unavailable, "controller is waiting for the database to be migrated")
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSchemaGate(t *testing.T) {
+	c := &Controller{logger: hclog.NewNullLogger()}
+	h := wrapHandlerWithSchemaGate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), c)
+	get := func(path string) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+	call := func() codes.Code {
+		_, err := c.schemaGateUnaryInterceptor(context.Background(), nil, nil, func(context.Context, interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		return status.Code(err)
+	}
+
+	t.Run("pending", func(t *testing.T) {
+		assert := assert.New(t)
+		c.schema.set(&db.SchemaStatus{Current: 96, Required: 97})
+		assert.False(c.schema.ready())
+		assert.Equal(http.StatusServiceUnavailable, get("/v1/scopes"))
+		assert.Equal(http.StatusOK, get("/health"))
+		assert.Equal(http.StatusOK, get("/"))
+		assert.Equal(codes.Unavailable, call())
+	})
+	t.Run("up-to-date", func(t *testing.T) {
+		assert := assert.New(t)
+		c.schema.set(&db.SchemaStatus{Current: 97, Required: 97})
+		assert.True(c.schema.ready())
+		assert.Equal(http.StatusOK, get("/v1/scopes"))
+		assert.Equal(codes.OK, call())
+	})
+	t.Run("newer-schema", func(t *testing.T) {
+		assert := assert.New(t)
+		c.schema.set(&db.SchemaStatus{Current: 98, Required: 97})
+		assert.True(c.schema.ready())
+		assert.Equal(http.StatusOK, get("/v1/scopes"))
+	})
+}
@@ -2,17 +2,73 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"time"
 
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/outbox"
 	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/internal/session"
 	"github.com/hashicorp/boundary/internal/types/resource"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
 )
 
 // In the future we could make this configurable
 const (
 	statusInterval      = 10 * time.Second
 	terminationInterval = 1 * time.Minute
+	oplogFlushInterval  = 1 * time.Second
+	outboxInterval      = 1 * time.Second
+
+	// oplogStagingCheckInterval is how often the oplog staging table is
+	// checked for entries which have not been flushed within
+	// oplogStagingMaxAge
+	oplogStagingCheckInterval = 1 * time.Minute
+	oplogStagingMaxAge        = 1 * time.Minute
+
+	// connectionCheckCleanupInterval is how often connection checks older
+	// than connectionCheckMaxAge are removed
+	connectionCheckCleanupInterval = 5 * time.Minute
+	connectionCheckMaxAge          = 10 * time.Minute
+
+	// inactiveUserInterval is how often users inactive for longer than the
+	// inactivity policy of their scope allows are disabled
+	inactiveUserInterval = 1 * time.Hour
+
+	// expiredPrincipalRoleInterval is how often expired temporary role
+	// assignments are removed
+	expiredPrincipalRoleInterval = 1 * time.Minute
+
+	// listenerAccessDenialInterval is how often the connections refused by
+	// the allow and deny lists of the listeners, and the workers refused by
+	// the worker access allow list, are recorded
+	listenerAccessDenialInterval = 1 * time.Minute
+
+	// securityEventRollupInterval is how often security events are rolled up
+	// into hourly counts, which are kept for securityEventRetention.
+	securityEventRollupInterval = 1 * time.Minute
+	securityEventRetention      = 90 * 24 * time.Hour
+
+	// usageInterval is how often the API calls counted by the controller are
+	// recorded and the active sessions rolled up into the daily usage of
+	// scopes
+	usageInterval = 1 * time.Minute
+
+	// piiRedactionInterval is how often personally identifiable information
+	// older than its retention is removed
+	piiRedactionInterval = 1 * time.Hour
+
+	// dbBloatSampleInterval is how often the bloat and vacuum statistics of
+	// the tables with the most churn are sampled
+	dbBloatSampleInterval = 10 * time.Minute
+
+	// columnRenameBackfillInterval is how often the columns being renamed are
+	// backfilled
+	columnRenameBackfillInterval = 1 * time.Minute
 )
 
 // This is exported so it can be tweaked in tests
@@ -34,16 +90,24 @@ func (c *Controller) startStatusTicking(cancelCtx context.Context) {
 					Type:        resource.Controller.String(),
 					Description: c.conf.RawConfig.Controller.Description,
 					Address:     c.conf.RawConfig.Controller.PublicClusterAddr,
+					Mode:        c.mode(),
 				}
 				repo, err := c.ServersRepoFn()
 				if err != nil {
 					c.logger.Error("error fetching repository for status update", "error", err)
 				} else {
 					_, _, err = repo.UpsertServer(cancelCtx, server)
-					if err != nil {
+					switch {
+					case err != nil && c.conf.RawConfig.Controller.ReadOnly:
+						// Expected when serving from a standby database
+						c.logger.Debug("read-only controller unable to perform status update", "error", err)
+					case err != nil:
 						c.logger.Error("error performing status update", "error", err)
-					} else {
+					default:
 						c.logger.Trace("controller status successfully saved")
+						if err := c.checkConfigDivergence(cancelCtx, repo); err != nil {
+							c.logger.Error("error checking config divergence", "error", err)
+						}
 					}
 				}
 				timer.Reset(statusInterval)
@@ -79,6 +143,88 @@ func (c *Controller) startRecoveryNonceCleanupTicking(cancelCtx context.Context)
 	}()
 }
 
+func (c *Controller) startConnectionCheckCleanupTicking(cancelCtx context.Context) {
+	go func() {
+		timer := time.NewTimer(0)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("connection check cleanup ticking shutting down")
+				return
+
+			case <-timer.C:
+				repo, err := c.ServersRepoFn()
+				if err != nil {
+					c.logger.Error("error fetching repository for connection check cleanup", "error", err)
+				} else {
+					checkCount, err := repo.CleanupConnectionChecks(cancelCtx, connectionCheckMaxAge)
+					if err != nil {
+						c.logger.Error("error performing connection check cleanup", "error", err)
+					} else if checkCount > 0 {
+						c.logger.Info("connection check cleanup successful", "checks_cleaned", checkCount)
+					}
+				}
+				timer.Reset(connectionCheckCleanupInterval)
+			}
+		}
+	}()
+}
+
+func (c *Controller) startDisableInactiveUsersTicking(cancelCtx context.Context) {
+	go func() {
+		timer := time.NewTimer(0)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("inactive user ticking shutting down")
+				return
+
+			case <-timer.C:
+				repo, err := c.IamRepoFn()
+				if err != nil {
+					c.logger.Error("error fetching repository for disabling inactive users", "error", err)
+				} else {
+					userIds, err := repo.DisableInactiveUsers(cancelCtx)
+					if err != nil {
+						c.logger.Error("error disabling inactive users", "error", err)
+					} else if len(userIds) > 0 {
+						c.logger.Info("disabled inactive users", "user_ids", userIds)
+					}
+				}
+				timer.Reset(inactiveUserInterval)
+			}
+		}
+	}()
+}
+
+func (c *Controller) startDeleteExpiredPrincipalRolesTicking(cancelCtx context.Context) {
+	go func() {
+		timer := time.NewTimer(0)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("expired principal role ticking shutting down")
+				return
+
+			case <-timer.C:
+				repo, err := c.IamRepoFn()
+				if err != nil {
+					c.logger.Error("error fetching repository for removing expired principal roles", "error", err)
+				} else {
+					removed, err := repo.DeleteExpiredPrincipalRoles(cancelCtx)
+					if err != nil {
+						c.logger.Error("error removing expired principal roles", "error", err)
+					}
+					for _, e := range removed {
+						c.logger.Info("removed expired principal role", "role_id", e.RoleId, "principal_id", e.PrincipalId, "expiration_time", e.ExpirationTime)
+					}
+				}
+				timer.Reset(expiredPrincipalRoleInterval)
+			}
+		}
+	}()
+}
+
 func (c *Controller) startTerminateCompletedSessionsTicking(cancelCtx context.Context) {
 	go func() {
 		r := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -104,6 +250,9 @@ func (c *Controller) startTerminateCompletedSessionsTicking(cancelCtx context.Co
 				if err != nil {
 					c.logger.Error("error fetching repository for terminating completed sessions", "error", err)
 				} else {
+					// Unused sessions are canceled first so they are
+					// terminated right away
+					c.cancelUnusedSessions(cancelCtx, repo)
 					terminationCount, err := repo.TerminateCompletedSessions(cancelCtx)
 					if err != nil {
 						c.logger.Error("error performing termination of completed sessions", "error", err)
@@ -116,3 +265,320 @@ func (c *Controller) startTerminateCompletedSessionsTicking(cancelCtx context.Co
 		}
 	}()
 }
+
+// cancelUnusedSessions cancels the sessions no client used within the pending
+// and idle session times to live, if configured.
+func (c *Controller) cancelUnusedSessions(ctx context.Context, repo *session.Repository) {
+	pending := c.conf.RawConfig.Controller.PendingSessionTimeToLiveDuration
+	idle := c.conf.RawConfig.Controller.IdleSessionTimeToLiveDuration
+	if pending <= 0 && idle <= 0 {
+		return
+	}
+	canceled, err := repo.CancelUnusedSessions(ctx, pending, idle)
+	if err != nil {
+		c.logger.Error("error canceling unused sessions", "error", err)
+		return
+	}
+	for _, e := range canceled {
+		c.logger.Info("canceled unused session", "session_id", e.SessionId, "target_id", e.TargetId, "user_id", e.UserId, "reason", e.Reason)
+	}
+}
+
+// startOplogFlushTicking starts the background worker which writes oplog
+// entries staged in asynchronous oplog mode to the oplog, and periodically
+// checks that staged entries are being flushed.
+func (c *Controller) startOplogFlushTicking(cancelCtx context.Context) {
+	wrapperFn := func(ctx context.Context, scopeId string) (wrapping.Wrapper, error) {
+		return c.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	}
+	go func() {
+		timer := time.NewTimer(0)
+		lastCheck := time.Now()
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("oplog flush ticking shutting down")
+				return
+
+			case <-timer.C:
+				// Keep flushing while full batches are being written so a
+				// backlog drains quickly
+				for {
+					flushed, err := oplog.FlushStagedEntries(cancelCtx, c.conf.Database, wrapperFn, oplog.DefaultFlushLimit)
+					if err != nil {
+						c.logger.Error("error flushing staged oplog entries", "error", err)
+						break
+					}
+					if flushed > 0 {
+						c.logger.Trace("flushed staged oplog entries", "entries_flushed", flushed)
+					}
+					if flushed < oplog.DefaultFlushLimit || cancelCtx.Err() != nil {
+						break
+					}
+				}
+				if time.Since(lastCheck) > oplogStagingCheckInterval {
+					lastCheck = time.Now()
+					report, err := oplog.CheckStagedEntries(cancelCtx, c.conf.Database, wrapperFn, oplogStagingMaxAge)
+					switch {
+					case err != nil:
+						c.logger.Error("error checking staged oplog entries", "error", err)
+					case report.Stale > 0 || len(report.Unflushable) > 0:
+						c.logger.Warn("staged oplog entries are not being flushed",
+							"staged", report.Count, "stale", report.Stale, "oldest", report.Oldest, "unflushable", report.Unflushable)
+					}
+				}
+				timer.Reset(oplogFlushInterval)
+			}
+		}
+	}()
+}
+
+// startOutboxDispatchTicking starts the background worker which delivers the
+// messages enqueued in the outbox.
+func (c *Controller) startOutboxDispatchTicking(cancelCtx context.Context) {
+	go func() {
+		timer := time.NewTimer(0)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("outbox dispatch ticking shutting down")
+				return
+
+			case <-timer.C:
+				// Keep dispatching while full batches are being delivered so
+				// a backlog drains quickly
+				for {
+					delivered, err := c.Outbox.Dispatch(cancelCtx)
+					if err != nil {
+						c.logger.Error("error dispatching outbox messages", "error", err)
+						break
+					}
+					if delivered > 0 {
+						c.logger.Trace("delivered outbox messages", "messages_delivered", delivered)
+					}
+					if delivered < outbox.DefaultLimit || cancelCtx.Err() != nil {
+						break
+					}
+				}
+				timer.Reset(outboxInterval)
+			}
+		}
+	}()
+}
+
+// startListenerAccessDenialTicking starts the background worker which records
+// the connections refused by the allow and deny lists of the listeners and the
+// workers refused by the worker access allow list.
+func (c *Controller) startListenerAccessDenialTicking(cancelCtx context.Context) {
+	go func() {
+		timer := time.NewTimer(listenerAccessDenialInterval)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("listener access denial ticking shutting down")
+				return
+
+			case <-timer.C:
+				if err := c.recordListenerAccessDenials(cancelCtx); err != nil {
+					c.logger.Error("error recording listener access denials", "error", err)
+				}
+				if err := c.recordWorkerAccessDenials(cancelCtx); err != nil {
+					c.logger.Error("error recording worker access denials", "error", err)
+				}
+				timer.Reset(listenerAccessDenialInterval)
+			}
+		}
+	}()
+}
+
+// startSecurityEventRollupTicking starts the background worker which rolls
+// security events up into hourly counts and deletes the expired counts.
+func (c *Controller) startSecurityEventRollupTicking(cancelCtx context.Context) {
+	go func() {
+		timer := time.NewTimer(securityEventRollupInterval)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("security event rollup ticking shutting down")
+				return
+
+			case <-timer.C:
+				repo, err := c.SecurityEventRepoFn()
+				if err != nil {
+					c.logger.Error("error fetching security event repository for rollup", "error", err)
+				} else {
+					rolled, err := repo.Rollup(cancelCtx, securityEventRetention)
+					if err != nil {
+						c.logger.Error("error rolling up security events", "error", err)
+					} else if rolled > 0 {
+						c.logger.Trace("rolled up security events", "counts_updated", rolled)
+					}
+				}
+				timer.Reset(securityEventRollupInterval)
+			}
+		}
+	}()
+}
+
+// startUsageTicking starts the background worker which records the API calls
+// counted by the controller and rolls up the time sessions were active into
+// the daily usage of scopes.
+func (c *Controller) startUsageTicking(cancelCtx context.Context) {
+	go func() {
+		timer := time.NewTimer(usageInterval)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("usage ticking shutting down")
+				return
+
+			case <-timer.C:
+				repo, err := c.UsageRepoFn()
+				if err != nil {
+					c.logger.Error("error fetching usage repository", "error", err)
+				} else {
+					calls := c.usageMeter.Take()
+					if err := repo.RecordApiCalls(cancelCtx, calls); err != nil {
+						c.logger.Error("error recording api calls", "error", err)
+						c.usageMeter.Restore(calls)
+					}
+					rolled, err := repo.RollupSessions(cancelCtx)
+					if err != nil {
+						c.logger.Error("error rolling up session usage", "error", err)
+					} else if rolled > 0 {
+						c.logger.Trace("rolled up session usage", "usages_updated", rolled)
+					}
+				}
+				timer.Reset(usageInterval)
+			}
+		}
+	}()
+}
+
+// newPiiRetention returns the retention of personally identifiable
+// information by category of the durations configured.
+func newPiiRetention(conf map[string]time.Duration) (map[db.PiiCategory]time.Duration, error) {
+	ret := make(map[db.PiiCategory]time.Duration, len(conf))
+	for category, r := range conf {
+		if !db.ValidPiiCategory(db.PiiCategory(category)) {
+			return nil, fmt.Errorf("unknown pii category %q", category)
+		}
+		if r <= 0 {
+			return nil, fmt.Errorf("retention of pii category %q must be positive", category)
+		}
+		ret[db.PiiCategory(category)] = r
+	}
+	return ret, nil
+}
+
+// startPiiRedactionTicking starts the background worker which removes the
+// personally identifiable information older than its retention from the
+// records holding it.
+func (c *Controller) startPiiRedactionTicking(cancelCtx context.Context) {
+	go func() {
+		w := db.New(c.conf.Database)
+		timer := time.NewTimer(piiRedactionInterval)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("pii redaction ticking shutting down")
+				return
+
+			case <-timer.C:
+				redacted, err := db.RedactExpiredPii(cancelCtx, w, db.PiiFields, c.piiRetention)
+				if err != nil {
+					c.logger.Error("error redacting expired pii", "error", err)
+				} else if redacted > 0 {
+					c.logger.Info("redacted expired pii", "records", redacted)
+				}
+				timer.Reset(piiRedactionInterval)
+			}
+		}
+	}()
+}
+
+// startColumnRenameBackfillTicking starts the background worker which copies
+// the old columns of the columns being renamed to their new columns.
+func (c *Controller) startColumnRenameBackfillTicking(cancelCtx context.Context) {
+	go func() {
+		w := db.New(c.conf.Database)
+		timer := time.NewTimer(0)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("column rename backfill ticking shutting down")
+				return
+
+			case <-timer.C:
+				backfilled, err := db.BackfillColumnRenames(cancelCtx, w, db.ColumnRenames, db.DefaultBackfillBatchSize)
+				if err != nil {
+					c.logger.Error("error backfilling column renames", "error", err)
+				} else if backfilled > 0 {
+					c.logger.Info("backfilled column renames", "records", backfilled)
+				}
+				timer.Reset(columnRenameBackfillInterval)
+			}
+		}
+	}()
+}
+
+// startUiManifestRefreshTicking starts the background worker which reloads
+// the manifest of the external UI asset source.
+func (c *Controller) startUiManifestRefreshTicking(cancelCtx context.Context) {
+	go func() {
+		timer := time.NewTimer(0)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("ui manifest refresh ticking shutting down")
+				return
+
+			case <-timer.C:
+				if err := c.uiAssets.loadManifest(cancelCtx); err != nil {
+					c.logger.Error("error loading ui manifest", "error", err)
+				}
+				timer.Reset(c.uiAssets.interval)
+			}
+		}
+	}()
+}
+
+// startDbBloatSamplingTicking starts the background worker which samples the
+// bloat and vacuum statistics of the tables with the most churn, exports them
+// as metrics labeled with the table and logs advisories for bloated tables.
+func (c *Controller) startDbBloatSamplingTicking(cancelCtx context.Context) {
+	go func() {
+		reader := db.New(c.conf.Database)
+		timer := time.NewTimer(0)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				c.logger.Info("db bloat sampling ticking shutting down")
+				return
+
+			case <-timer.C:
+				samples, err := db.SampleTableBloat(cancelCtx, reader, db.BloatTables)
+				if err != nil {
+					c.logger.Error("error sampling table bloat", "error", err)
+				}
+				now := time.Now()
+				for _, b := range samples {
+					labels := []metrics.Label{{Name: "table", Value: b.Table}}
+					metrics.SetGaugeWithLabels([]string{"controller", "db", "live_tuples"}, float32(b.LiveTuples), labels)
+					metrics.SetGaugeWithLabels([]string{"controller", "db", "dead_tuples"}, float32(b.DeadTuples), labels)
+					metrics.SetGaugeWithLabels([]string{"controller", "db", "dead_tuple_ratio"}, float32(b.DeadTupleRatio()), labels)
+					metrics.SetGaugeWithLabels([]string{"controller", "db", "table_bytes"}, float32(b.TableBytes), labels)
+					metrics.SetGaugeWithLabels([]string{"controller", "db", "index_bytes"}, float32(b.IndexBytes), labels)
+					metrics.SetGaugeWithLabels([]string{"controller", "db", "autovacuum_count"}, float32(b.AutovacuumCount), labels)
+					if !b.LastVacuum.IsZero() {
+						metrics.SetGaugeWithLabels([]string{"controller", "db", "seconds_since_vacuum"}, float32(now.Sub(b.LastVacuum).Seconds()), labels)
+					}
+					for _, a := range b.Advisories(now) {
+						c.logger.Warn("database table bloat advisory", "table", b.Table, "advisory", a)
+					}
+				}
+				timer.Reset(dbBloatSampleInterval)
+			}
+		}
+	}()
+}
@@ -0,0 +1,325 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// uiManifestName is the name of the integrity manifest at the root of
+	// an external UI asset source.
+	uiManifestName = "ui-manifest.json"
+
+	// uiIndexName is the entry point of the UI, served for all paths without
+	// an extension.
+	uiIndexName = "index.html"
+
+	// defaultUiCacheMaxAge is how long browsers may cache the assets of the
+	// UI other than its entry point by default.
+	defaultUiCacheMaxAge = 1 * time.Hour
+
+	// defaultUiManifestRefreshInterval is how often the manifest is reloaded
+	// by default, which is how long a new version of the UI takes to be
+	// served.
+	defaultUiManifestRefreshInterval = 1 * time.Minute
+
+	// maxUiManifestSize and maxUiAssetSize are the largest manifest and
+	// asset read from a source.
+	maxUiManifestSize = 1 << 20
+	maxUiAssetSize    = 32 << 20
+)
+
+// uiManifest is the integrity manifest of an external UI asset source. Files
+// are the paths of the assets, relative to the root of the source, with their
+// integrity in the format of subresource integrity, e.g. "sha256-<base64>".
+// Only the assets in the manifest are served, and only if they match their
+// integrity.
+type uiManifest struct {
+	Version string            `json:"version"`
+	Files   map[string]string `json:"files"`
+}
+
+// uiAssetSource is where the assets of the UI are read from.
+type uiAssetSource interface {
+	open(ctx context.Context, name string) (io.ReadCloser, error)
+	String() string
+}
+
+// dirAssetSource reads assets from a directory.
+type dirAssetSource struct {
+	dir string
+}
+
+func (s *dirAssetSource) open(_ context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.dir, filepath.FromSlash(name)))
+}
+
+func (s *dirAssetSource) String() string {
+	return s.dir
+}
+
+// urlAssetSource reads assets from a base URL, e.g. of a bucket of an object
+// store.
+type urlAssetSource struct {
+	base   *url.URL
+	client *http.Client
+}
+
+func (s *urlAssetSource) open(ctx context.Context, name string) (io.ReadCloser, error) {
+	u := *s.base
+	u.Path = path.Join(u.Path, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, u.String())
+	}
+	return resp.Body, nil
+}
+
+func (s *urlAssetSource) String() string {
+	return s.base.String()
+}
+
+// uiAssets serves the UI from an external source, verifying its assets
+// against the manifest of the source. The content of verified assets is kept
+// in memory, so each version of an asset is read once. Assets have their
+// integrity as their ETag; the entry point must always be revalidated, so new
+// versions of the UI are picked up by browsers once the manifest is reloaded,
+// while the other assets may be cached for the cache max age.
+type uiAssets struct {
+	source   uiAssetSource
+	logger   hclog.Logger
+	maxAge   time.Duration
+	interval time.Duration
+
+	lock     sync.RWMutex
+	manifest *uiManifest
+	content  map[string][]byte
+}
+
+// newUiAssets returns the external UI asset source of the configuration, or
+// nil if it is nil.
+func newUiAssets(conf *config.UiAssets, logger hclog.Logger) (*uiAssets, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	u := &uiAssets{
+		logger:   logger,
+		maxAge:   conf.CacheMaxAgeDuration,
+		interval: conf.RefreshIntervalDuration,
+		content:  make(map[string][]byte),
+	}
+	switch {
+	case conf.Directory != "" && conf.Url != "":
+		return nil, fmt.Errorf("only one of directory and url may be set")
+	case conf.Directory != "":
+		abs, err := filepath.Abs(conf.Directory)
+		if err != nil {
+			return nil, err
+		}
+		u.source = &dirAssetSource{dir: abs}
+	case conf.Url != "":
+		base, err := url.Parse(conf.Url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid url: %w", err)
+		}
+		if base.Scheme != "http" && base.Scheme != "https" {
+			return nil, fmt.Errorf("invalid url: scheme must be http or https")
+		}
+		u.source = &urlAssetSource{base: base, client: &http.Client{Timeout: 30 * time.Second}}
+	default:
+		return nil, fmt.Errorf("one of directory and url must be set")
+	}
+	switch {
+	case u.maxAge < 0:
+		return nil, fmt.Errorf("cache max age must not be negative")
+	case u.maxAge == 0:
+		u.maxAge = defaultUiCacheMaxAge
+	}
+	switch {
+	case u.interval < 0:
+		return nil, fmt.Errorf("refresh interval must not be negative")
+	case u.interval == 0:
+		u.interval = defaultUiManifestRefreshInterval
+	}
+	return u, nil
+}
+
+// loadManifest reads the manifest of the source, replacing the current one if
+// it is valid. The content of the assets not in the new manifest is dropped.
+func (u *uiAssets) loadManifest(ctx context.Context) error {
+	rc, err := u.source.open(ctx, uiManifestName)
+	if err != nil {
+		return fmt.Errorf("unable to read ui manifest: %w", err)
+	}
+	defer rc.Close()
+	b, err := ioutil.ReadAll(io.LimitReader(rc, maxUiManifestSize+1))
+	if err != nil {
+		return fmt.Errorf("unable to read ui manifest: %w", err)
+	}
+	if len(b) > maxUiManifestSize {
+		return fmt.Errorf("ui manifest is larger than %d bytes", maxUiManifestSize)
+	}
+	m := new(uiManifest)
+	if err := json.Unmarshal(b, m); err != nil {
+		return fmt.Errorf("invalid ui manifest: %w", err)
+	}
+	if _, ok := m.Files[uiIndexName]; !ok {
+		return fmt.Errorf("invalid ui manifest: missing %s", uiIndexName)
+	}
+	for name, integrity := range m.Files {
+		if name != path.Clean("/" + name)[1:] {
+			return fmt.Errorf("invalid ui manifest: invalid path %q", name)
+		}
+		if _, err := parseIntegrity(integrity); err != nil {
+			return fmt.Errorf("invalid ui manifest: %s: %w", name, err)
+		}
+	}
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if u.manifest == nil || u.manifest.Version != m.Version {
+		u.logger.Info("loaded ui manifest", "source", u.source.String(), "version", m.Version, "files", len(m.Files))
+	}
+	u.manifest = m
+	keep := make(map[string][]byte, len(u.content))
+	for _, integrity := range m.Files {
+		if c, ok := u.content[integrity]; ok {
+			keep[integrity] = c
+		}
+	}
+	u.content = keep
+	return nil
+}
+
+// parseIntegrity returns the SHA-256 digest of the integrity.
+func parseIntegrity(integrity string) ([]byte, error) {
+	if !strings.HasPrefix(integrity, "sha256-") {
+		return nil, fmt.Errorf("integrity must be sha256")
+	}
+	digest, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(integrity, "sha256-"))
+	if err != nil || len(digest) != sha256.Size {
+		return nil, fmt.Errorf("invalid sha256 integrity")
+	}
+	return digest, nil
+}
+
+// asset returns the content and integrity of the asset, reading and
+// verifying it if it isn't in memory yet. It returns os.ErrNotExist for
+// assets not in the manifest.
+func (u *uiAssets) asset(ctx context.Context, name string) ([]byte, string, error) {
+	u.lock.RLock()
+	if u.manifest == nil {
+		u.lock.RUnlock()
+		return nil, "", fmt.Errorf("ui manifest not loaded")
+	}
+	integrity, ok := u.manifest.Files[name]
+	content, cached := u.content[integrity]
+	u.lock.RUnlock()
+	switch {
+	case !ok:
+		return nil, "", os.ErrNotExist
+	case cached:
+		return content, integrity, nil
+	}
+
+	rc, err := u.source.open(ctx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rc.Close()
+	content, err = ioutil.ReadAll(io.LimitReader(rc, maxUiAssetSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(content) > maxUiAssetSize {
+		return nil, "", fmt.Errorf("asset %s is larger than %d bytes", name, maxUiAssetSize)
+	}
+	digest, err := parseIntegrity(integrity)
+	if err != nil {
+		return nil, "", err
+	}
+	if sum := sha256.Sum256(content); !bytes.Equal(sum[:], digest) {
+		return nil, "", fmt.Errorf("asset %s does not match its integrity", name)
+	}
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if u.manifest.Files[name] == integrity {
+		u.content[integrity] = content
+	}
+	return content, integrity, nil
+}
+
+// ServeHTTP serves the assets of the UI, and its entry point for the paths
+// without an extension so the UI can route them.
+func (u *uiAssets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	name := path.Clean("/" + r.URL.Path)[1:]
+	if name == "" || !strings.Contains(path.Base(name), ".") {
+		name = uiIndexName
+	}
+	content, integrity, err := u.asset(r.Context(), name)
+	switch {
+	case os.IsNotExist(err):
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case err != nil:
+		u.logger.Error("failed to serve ui asset", "asset", name, "source", u.source.String(), "error", err)
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	etag := strconv.Quote(integrity)
+	w.Header().Set("ETag", etag)
+	if name == uiIndexName {
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int64(u.maxAge/time.Second)))
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" && strings.Contains(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	ct := mime.TypeByExtension(path.Ext(name))
+	if ct == "" {
+		ct = http.DetectContentType(content)
+	}
+	w.Header().Set("Content-Type", ct)
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write(content); err != nil {
+		u.logger.Error("failed to send ui asset", "asset", name, "error", err)
+	}
+}
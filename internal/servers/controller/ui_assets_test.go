@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUiAssets(t *testing.T) {
+	integrity := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+	}
+	dir, err := ioutil.TempDir("", "boundary-ui-assets-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"index.html":        "index",
+		"assets/app-1.js":   "app",
+		"assets/tamper.css": "original",
+	}
+	manifest := uiManifest{Version: "1", Files: map[string]string{}}
+	for name, content := range files {
+		manifest.Files[name] = integrity(content)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "assets/tamper.css"), []byte("tampered"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "unlisted.js"), []byte("unlisted"), 0644))
+	b, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, uiManifestName), b, 0644))
+
+	srv := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer srv.Close()
+
+	sources := map[string]*config.UiAssets{
+		"directory": {Directory: dir},
+		"url":       {Url: srv.URL + "/"},
+	}
+	for name, conf := range sources {
+		conf := conf
+		t.Run(name, func(t *testing.T) {
+			u, err := newUiAssets(conf, hclog.NewNullLogger())
+			require.NoError(t, err)
+
+			get := func(path, etag string) *httptest.ResponseRecorder {
+				r := httptest.NewRequest(http.MethodGet, path, nil)
+				if etag != "" {
+					r.Header.Set("If-None-Match", etag)
+				}
+				w := httptest.NewRecorder()
+				u.ServeHTTP(w, r)
+				return w
+			}
+
+			// Nothing is served until the manifest is loaded
+			assert.Equal(t, http.StatusBadGateway, get("/", "").Code)
+			require.NoError(t, u.loadManifest(context.Background()))
+
+			t.Run("index", func(t *testing.T) {
+				assert := assert.New(t)
+				for _, p := range []string{"/", "/scopes/global/targets"} {
+					w := get(p, "")
+					assert.Equal(http.StatusOK, w.Code)
+					assert.Equal("index", w.Body.String())
+					assert.Equal("no-cache", w.Header().Get("Cache-Control"))
+					assert.Equal(`"`+integrity("index")+`"`, w.Header().Get("ETag"))
+				}
+			})
+			t.Run("asset", func(t *testing.T) {
+				assert := assert.New(t)
+				w := get("/assets/app-1.js", "")
+				assert.Equal(http.StatusOK, w.Code)
+				assert.Equal("app", w.Body.String())
+				assert.Equal("public, max-age=3600", w.Header().Get("Cache-Control"))
+
+				w = get("/assets/app-1.js", w.Header().Get("ETag"))
+				assert.Equal(http.StatusNotModified, w.Code)
+				assert.Empty(w.Body.String())
+			})
+			t.Run("unlisted", func(t *testing.T) {
+				assert.Equal(t, http.StatusNotFound, get("/unlisted.js", "").Code)
+			})
+			t.Run("tampered", func(t *testing.T) {
+				assert.Equal(t, http.StatusBadGateway, get("/assets/tamper.css", "").Code)
+			})
+		})
+	}
+
+	t.Run("invalid-config", func(t *testing.T) {
+		for _, conf := range []*config.UiAssets{
+			{},
+			{Directory: dir, Url: srv.URL},
+			{Url: "ftp://example.com/ui"},
+			{Directory: dir, CacheMaxAgeDuration: -1},
+		} {
+			_, err := newUiAssets(conf, hclog.NewNullLogger())
+			assert.Error(t, err)
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/servers"
+	"google.golang.org/grpc/peer"
+)
+
+// The reasons workers are refused by the worker access allow list, recorded
+// with their denials.
+const (
+	workerDeniedAddress = "address"
+	workerDeniedName    = "name"
+	workerDeniedTags    = "tags"
+)
+
+// errWorkerAccessDenied is returned for workers refused by the worker access
+// allow list when they authenticate.
+var errWorkerAccessDenied = errors.New("worker not allowed to connect to controller")
+
+// workerAccessRules is the parsed worker access allow list.
+type workerAccessRules struct {
+	cidrs []*net.IPNet
+	name  *regexp.Regexp
+	tags  map[string]string
+}
+
+// parseWorkerAccess returns the rules of the configuration, or nil if it is
+// nil.
+func parseWorkerAccess(conf *config.WorkerAccess) (*workerAccessRules, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	rules := &workerAccessRules{tags: conf.RequiredTags}
+	for _, c := range conf.AllowedCidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid worker access allowed CIDR: %w", err)
+		}
+		rules.cidrs = append(rules.cidrs, n)
+	}
+	if conf.NamePattern != "" {
+		var err error
+		if rules.name, err = regexp.Compile(conf.NamePattern); err != nil {
+			return nil, fmt.Errorf("invalid worker access name pattern: %w", err)
+		}
+	}
+	return rules, nil
+}
+
+// check returns the reason the worker isn't allowed, or an empty string if it
+// is. A nil ip, e.g. of a Unix socket, isn't checked, nor are the tags unless
+// checkTags is true.
+func (r *workerAccessRules) check(name string, ip net.IP, tags map[string]string, checkTags bool) string {
+	if ip != nil && len(r.cidrs) > 0 {
+		var found bool
+		for _, n := range r.cidrs {
+			if n.Contains(ip) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return workerDeniedAddress
+		}
+	}
+	if r.name != nil && !r.name.MatchString(name) {
+		return workerDeniedName
+	}
+	if checkTags {
+		for k, v := range r.tags {
+			if got, ok := tags[k]; !ok || got != v {
+				return workerDeniedTags
+			}
+		}
+	}
+	return ""
+}
+
+// workerDeniedKey identifies the refused workers counted together.
+type workerDeniedKey struct {
+	name string
+	addr string
+}
+
+// workerAccess checks the workers authenticating to the controller and
+// updating their status against the worker access allow list, and counts the
+// refused ones until they are recorded.
+type workerAccess struct {
+	sync.RWMutex
+	rules *workerAccessRules
+
+	deniedLock sync.Mutex
+	denied     map[workerDeniedKey]*servers.WorkerAccessDenial
+}
+
+func newWorkerAccess(conf *config.WorkerAccess) (*workerAccess, error) {
+	rules, err := parseWorkerAccess(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &workerAccess{
+		rules:  rules,
+		denied: make(map[workerDeniedKey]*servers.WorkerAccessDenial),
+	}, nil
+}
+
+// permits returns true if the worker with the name connecting from addr may
+// use the controller, and counts it otherwise. Tags are only checked if
+// checkTags is true, since workers only send them with their status.
+func (a *workerAccess) permits(name string, addr net.Addr, tags map[string]string, checkTags bool) bool {
+	if a == nil {
+		return true
+	}
+	a.RLock()
+	rules := a.rules
+	a.RUnlock()
+	if rules == nil {
+		return true
+	}
+	ip := addrIP(addr)
+	reason := rules.check(name, ip, tags, checkTags)
+	if reason == "" {
+		return true
+	}
+
+	a.deniedLock.Lock()
+	defer a.deniedLock.Unlock()
+	now := time.Now()
+	key := workerDeniedKey{name: name}
+	if ip != nil {
+		key.addr = ip.String()
+	}
+	switch d, ok := a.denied[key]; {
+	case ok:
+		d.Count++
+		d.LastTime = now
+		d.Reason = reason
+	case len(a.denied) < maxDeniedAddrs:
+		a.denied[key] = &servers.WorkerAccessDenial{
+			WorkerName: name,
+			RemoteAddr: key.addr,
+			Reason:     reason,
+			Count:      1,
+			FirstTime:  now,
+			LastTime:   now,
+		}
+	}
+	return false
+}
+
+// reload replaces the allow list with that of the configuration.
+func (a *workerAccess) reload(conf *config.WorkerAccess) error {
+	rules, err := parseWorkerAccess(conf)
+	if err != nil {
+		return err
+	}
+	a.Lock()
+	a.rules = rules
+	a.Unlock()
+	return nil
+}
+
+// takeDenied returns the refused workers counted since it was last called.
+func (a *workerAccess) takeDenied() []*servers.WorkerAccessDenial {
+	if a == nil {
+		return nil
+	}
+	a.deniedLock.Lock()
+	defer a.deniedLock.Unlock()
+	if len(a.denied) == 0 {
+		return nil
+	}
+	ret := make([]*servers.WorkerAccessDenial, 0, len(a.denied))
+	for _, d := range a.denied {
+		ret = append(ret, d)
+	}
+	a.denied = make(map[workerDeniedKey]*servers.WorkerAccessDenial)
+	return ret
+}
+
+// ReloadWorkerAccess replaces the worker access allow list of the controller
+// with that of the configuration. The current list is kept if the
+// configuration is invalid.
+func (c *Controller) ReloadWorkerAccess(conf *config.WorkerAccess) error {
+	if err := c.workerAccess.reload(conf); err != nil {
+		return fmt.Errorf("error reloading worker access: %w", err)
+	}
+	c.logger.Info("worker access reloaded")
+	return nil
+}
+
+// permitsWorkerStatus checks the worker sending a status update, with its
+// tags, against the worker access allow list.
+func (c *Controller) permitsWorkerStatus(ctx context.Context, name string, tags map[string]string) bool {
+	var addr net.Addr
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr
+	}
+	return c.workerAccess.permits(name, addr, tags, true)
+}
+
+// recordWorkerAccessDenials records the workers refused since it was last
+// called as events.
+func (c *Controller) recordWorkerAccessDenials(ctx context.Context) error {
+	denials := c.workerAccess.takeDenied()
+	if len(denials) == 0 {
+		return nil
+	}
+	for _, d := range denials {
+		d.Controller = c.conf.RawConfig.Controller.Name
+		c.logger.Warn("refused worker", "name", d.WorkerName, "remote_addr", d.RemoteAddr, "reason", d.Reason, "count", d.Count)
+	}
+	repo, err := c.ServersRepoFn()
+	if err != nil {
+		return err
+	}
+	return repo.RecordWorkerAccessDenials(ctx, denials)
+}
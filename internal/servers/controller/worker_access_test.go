@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"net"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerAccess(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	a, err := newWorkerAccess(&config.WorkerAccess{
+		AllowedCidrs: []string{"10.0.0.0/8"},
+		NamePattern:  "^worker-[0-9]+$",
+		RequiredTags: map[string]string{"region": "eu"},
+	})
+	require.NoError(err)
+
+	tcp := func(ip string) net.Addr { return &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234} }
+	eu := map[string]string{"region": "eu", "type": "prod"}
+
+	// Tags are only checked with status updates
+	assert.True(a.permits("worker-1", tcp("10.0.0.1"), nil, false))
+	assert.True(a.permits("worker-1", tcp("10.0.0.1"), eu, true))
+	assert.True(a.permits("worker-1", &net.UnixAddr{Name: "/tmp/boundary.sock", Net: "unix"}, eu, true))
+	assert.False(a.permits("worker-1", tcp("192.0.2.1"), eu, true))
+	assert.False(a.permits("rogue", tcp("10.0.0.1"), nil, false))
+	assert.False(a.permits("worker-1", tcp("10.0.0.1"), map[string]string{"region": "us"}, true))
+	assert.False(a.permits("worker-1", tcp("10.0.0.1"), nil, true))
+
+	denied := a.takeDenied()
+	require.Len(denied, 3)
+	reasons := map[string]string{}
+	counts := map[string]uint64{}
+	for _, d := range denied {
+		key := d.WorkerName + " " + d.RemoteAddr
+		reasons[key] = d.Reason
+		counts[key] = d.Count
+	}
+	assert.Equal(map[string]string{
+		"worker-1 192.0.2.1": workerDeniedAddress,
+		"rogue 10.0.0.1":     workerDeniedName,
+		"worker-1 10.0.0.1":  workerDeniedTags,
+	}, reasons)
+	assert.Equal(uint64(2), counts["worker-1 10.0.0.1"])
+	assert.Empty(a.takeDenied())
+
+	// A reload replaces the allow list, and keeps it if invalid
+	require.NoError(a.reload(&config.WorkerAccess{NamePattern: "^rogue$"}))
+	assert.True(a.permits("rogue", tcp("192.0.2.1"), nil, true))
+	assert.False(a.permits("worker-1", tcp("10.0.0.1"), eu, true))
+	assert.Error(a.reload(&config.WorkerAccess{NamePattern: "(["}))
+	assert.False(a.permits("worker-1", tcp("10.0.0.1"), eu, true))
+	require.NoError(a.reload(nil))
+	assert.True(a.permits("worker-1", tcp("192.0.2.1"), nil, true))
+
+	var none *workerAccess
+	assert.True(none.permits("rogue", tcp("192.0.2.1"), nil, true))
+	assert.Nil(none.takeDenied())
+}
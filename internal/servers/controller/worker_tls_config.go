@@ -20,11 +20,15 @@ type workerAuthEntry struct {
 	conn net.Conn
 }
 
-func (c Controller) validateWorkerTls(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+func (c *Controller) validateWorkerTls(hello *tls.ClientHelloInfo) (*tls.Config, error) {
 	for _, p := range hello.SupportedProtos {
 		switch {
 		case strings.HasPrefix(p, "v1workerauth-"):
 			tlsConf, workerInfo, err := c.v1WorkerAuthConfig(hello.SupportedProtos)
+			if err == nil && !c.workerAccess.permits(workerInfo.Name, hello.Conn.RemoteAddr(), nil, false) {
+				// The worker holds the worker-auth key but isn't allowed
+				return nil, errWorkerAccessDenied
+			}
 			if err == nil {
 				// Set the info we need to prevent replays
 				c.workerAuthCache.Set(workerInfo.ConnectionNonce, &workerAuthEntry{
@@ -37,7 +41,7 @@ func (c Controller) validateWorkerTls(hello *tls.ClientHelloInfo) (*tls.Config,
 	return nil, nil
 }
 
-func (c Controller) v1WorkerAuthConfig(protos []string) (*tls.Config, *base.WorkerAuthInfo, error) {
+func (c *Controller) v1WorkerAuthConfig(protos []string) (*tls.Config, *base.WorkerAuthInfo, error) {
 	var firstMatchProto string
 	var encString string
 	for _, p := range protos {
@@ -0,0 +1,62 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/outbox"
+)
+
+// ListenerAccessDeniedKind is the outbox message kind of the records of the
+// connections to the listeners of a controller refused by their allow and
+// deny lists. The payload is a ListenerAccessDenial.
+const ListenerAccessDeniedKind = "controller.listener_access_denied"
+
+// ListenerAccessDeniedSchemaVersion is the version of the schema of the
+// payloads of ListenerAccessDeniedKind messages.
+const ListenerAccessDeniedSchemaVersion = 1
+
+// ListenerAccessDenial records the connections from an address refused by the
+// listeners of a purpose of a controller between FirstTime and LastTime.
+// Controllers record refused connections in batches so a flood of them
+// doesn't cause a flood of writes.
+type ListenerAccessDenial struct {
+	SchemaVersion int       `json:"schema_version"`
+	Controller    string    `json:"controller"`
+	Purpose       string    `json:"purpose"`
+	RemoteAddr    string    `json:"remote_addr"`
+	Count         uint64    `json:"count"`
+	FirstTime     time.Time `json:"first_time"`
+	LastTime      time.Time `json:"last_time"`
+}
+
+// RecordListenerAccessDenials enqueues the denials in the outbox in one
+// transaction.
+func (r *Repository) RecordListenerAccessDenials(ctx context.Context, denials []*ListenerAccessDenial, opt ...Option) error {
+	if len(denials) == 0 {
+		return errors.New("cannot record empty listener access denials")
+	}
+	_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			for _, d := range denials {
+				d.SchemaVersion = ListenerAccessDeniedSchemaVersion
+				payload, err := json.Marshal(d)
+				if err != nil {
+					return err
+				}
+				if err := outbox.Enqueue(ctx, w, ListenerAccessDeniedKind, payload); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error recording listener access denials: %w", err)
+	}
+	return nil
+}
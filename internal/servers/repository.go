@@ -2,6 +2,7 @@ package servers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -9,7 +10,9 @@ import (
 	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/securityevent"
 	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/boundary/internal/types/scope"
 )
 
 const (
@@ -27,6 +30,13 @@ func (s ServerType) String() string {
 	return string(s)
 }
 
+// The modes of controllers. Read-only controllers serve only reads, so they
+// are not sent to workers.
+const (
+	ModeReadWrite = "read-write"
+	ModeReadOnly  = "read-only"
+)
+
 // Repository is the servers database repository
 type Repository struct {
 	reader db.Reader
@@ -82,18 +92,22 @@ func (r *Repository) UpsertServer(ctx context.Context, server *Server, opt ...Op
 	}
 	// Ensure, for now at least, the private ID is always equivalent to the name
 	server.PrivateId = server.Name
+	if server.Mode == "" {
+		server.Mode = ModeReadWrite
+	}
 	// Build query
 	q := `
 	insert into server
-		(private_id, type, name, description, address, update_time)
+		(private_id, type, name, description, address, mode, update_time)
 	values
-		($1, $2, $3, $4, $5, $6)
+		($1, $2, $3, $4, $5, $6, $7)
 	on conflict on constraint server_pkey
 	do update set
 		name = $3,
 		description = $4,
 		address = $5,
-		update_time = $6;
+		mode = $6,
+		update_time = $7;
 	`
 
 	rowsAffected, err := r.writer.Exec(ctx, q,
@@ -102,6 +116,7 @@ func (r *Repository) UpsertServer(ctx context.Context, server *Server, opt ...Op
 			server.Name,
 			server.Description,
 			server.Address,
+			server.Mode,
 			time.Now().Format(time.RFC3339)})
 	if err != nil {
 		return nil, db.NoRowsAffected, fmt.Errorf("error performing status upsert: %w", err)
@@ -110,22 +125,121 @@ func (r *Repository) UpsertServer(ctx context.Context, server *Server, opt ...Op
 	if server.Type == resource.Controller.String() {
 		return nil, int(rowsAffected), nil
 	}
-	// Fetch current controllers to feed to the workers
+	// Fetch current controllers to feed to the workers, leaving out the
+	// read-only ones, which reject the calls of workers
 	controllers, err := r.ListServers(ctx, ServerTypeController)
-	return controllers, len(controllers), err
+	if err != nil {
+		return nil, 0, err
+	}
+	readWrite := controllers[:0]
+	for _, c := range controllers {
+		if c.Mode != ModeReadOnly {
+			readWrite = append(readWrite, c)
+		}
+	}
+	return readWrite, len(readWrite), nil
+}
+
+// UpsertWorkerState records the state of a worker reported to the named
+// controller, encrypted with the global scope database key.
+func (r *Repository) UpsertWorkerState(ctx context.Context, workerName, controllerName string, state *WorkerState, opt ...Option) error {
+	if workerName == "" {
+		return errors.New("cannot update state of worker with empty name")
+	}
+	if controllerName == "" {
+		return errors.New("cannot update worker state with empty controller name")
+	}
+	if state == nil {
+		return errors.New("cannot update worker state that is nil")
+	}
+	marshaled, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("error marshaling worker state: %w", err)
+	}
+	ws := &workerState{
+		WorkerId:     workerName,
+		ControllerId: controllerName,
+		State:        marshaled,
+	}
+	if err := db.EncryptFields(ctx, ws, r.kms.DatabaseFieldWrapper()); err != nil {
+		return fmt.Errorf("error encrypting worker state: %w", err)
+	}
+	q := `
+	insert into server_worker_state
+		(worker_id, controller_id, key_id, state)
+	values
+		($1, $2, $3, $4)
+	on conflict on constraint server_worker_state_pkey
+	do update set
+		controller_id = $2,
+		key_id = $3,
+		state = $4,
+		update_time = current_timestamp;
+	`
+	if _, err := r.writer.Exec(ctx, q, []interface{}{ws.WorkerId, ws.ControllerId, ws.KeyId, ws.CtState}); err != nil {
+		return fmt.Errorf("error performing worker state upsert: %w", err)
+	}
+	return nil
+}
+
+// ListWorkerStates returns the states of the workers reported within the
+// liveness period, set by WithLiveness, by worker name. States are reported by
+// workers to any controller, so this includes workers connected to other
+// controllers.
+func (r *Repository) ListWorkerStates(ctx context.Context, opt ...Option) (map[string]*WorkerState, error) {
+	opts := getOpts(opt...)
+	liveness := opts.withLiveness
+	if liveness == 0 {
+		liveness = defaultLiveness
+	}
+	updateTime := time.Now().Add(-1 * liveness)
+	var states []*workerState
+	if err := r.reader.SearchWhere(
+		ctx,
+		&states,
+		"update_time > $1",
+		[]interface{}{updateTime.Format(time.RFC3339)},
+		db.WithLimit(-1),
+	); err != nil {
+		return nil, fmt.Errorf("error listing worker states: %w", err)
+	}
+	ret := make(map[string]*WorkerState, len(states))
+	for _, s := range states {
+		if err := db.DecryptFields(ctx, s, r.kms.DatabaseFieldWrapper()); err != nil {
+			return nil, fmt.Errorf("error decrypting worker state: %w", err)
+		}
+		state, err := s.toWorkerState()
+		if err != nil {
+			return nil, err
+		}
+		ret[s.WorkerId] = state
+	}
+	return ret, nil
 }
 
 type RecoveryNonce struct {
 	Nonce string
 }
 
-// AddRecoveryNonce adds a nonce
+// AddRecoveryNonce adds a nonce and records the use of the recovery KMS it is
+// added for as a security event of the global scope.
 func (r *Repository) AddRecoveryNonce(ctx context.Context, nonce string, opt ...Option) error {
 	if nonce == "" {
 		return errors.New("empty nonce provided")
 	}
-	rn := &RecoveryNonce{Nonce: nonce}
-	if err := r.writer.Create(ctx, rn); err != nil {
+	_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			rn := &RecoveryNonce{Nonce: nonce}
+			if err := w.Create(ctx, rn); err != nil {
+				return err
+			}
+			return securityevent.Enqueue(ctx, w, &securityevent.Event{
+				Kind:    securityevent.RecoveryKmsUsed,
+				ScopeId: scope.Global.String(),
+			})
+		},
+	)
+	if err != nil {
 		return fmt.Errorf("error performing nonce insertion: %w", err)
 	}
 	return nil
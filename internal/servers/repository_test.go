@@ -1,12 +1,15 @@
 package servers_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/boundary/api/roles"
 	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller"
 	"github.com/hashicorp/boundary/internal/types/scope"
 	"github.com/hashicorp/boundary/sdk/recovery"
@@ -78,3 +81,49 @@ func TestRecoveryNonces(t *testing.T) {
 		assert.Len(nonces, 0)
 	}
 }
+
+func TestUpsertServer_Mode(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	repo, err := servers.NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(err)
+
+	for name, mode := range map[string]string{"controller-rw": "", "controller-ro": servers.ModeReadOnly} {
+		_, _, err := repo.UpsertServer(ctx, &servers.Server{
+			Name:    name,
+			Type:    servers.ServerTypeController.String(),
+			Address: name,
+			Mode:    mode,
+		})
+		require.NoError(err)
+	}
+
+	// Both controllers are listed with their modes
+	controllers, err := repo.ListServers(ctx, servers.ServerTypeController)
+	require.NoError(err)
+	modes := make(map[string]string, len(controllers))
+	for _, c := range controllers {
+		modes[c.Name] = c.Mode
+	}
+	assert.Equal(map[string]string{"controller-rw": servers.ModeReadWrite, "controller-ro": servers.ModeReadOnly}, modes)
+
+	// Workers are only sent to the read-write controller
+	controllers, _, err = repo.UpsertServer(ctx, &servers.Server{
+		Name:    "worker",
+		Type:    servers.ServerTypeWorker.String(),
+		Address: "worker",
+	})
+	require.NoError(err)
+	require.Len(controllers, 1)
+	assert.Equal("controller-rw", controllers[0].Name)
+
+	_, _, err = repo.UpsertServer(ctx, &servers.Server{
+		Name: "controller-invalid",
+		Type: servers.ServerTypeController.String(),
+		Mode: "write-only",
+	})
+	assert.Error(err)
+}
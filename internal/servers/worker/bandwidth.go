@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// maxLimitedWrite is the largest chunk written at once by a limitedWriter, so
+// slow limits are enforced smoothly instead of in bursts of large buffers.
+const maxLimitedWrite = 16 * 1024
+
+// bandwidthLimiter is a token bucket limiting the bytes per second proxied.
+// It holds at most one second worth of bytes, and may go into debt so writes
+// larger than that wait for it to be paid back instead of being refused.
+type bandwidthLimiter struct {
+	sync.Mutex
+	rate   uint64
+	tokens float64
+	last   time.Time
+}
+
+// newBandwidthLimiter returns a limiter allowing rate bytes per second, or
+// nil if rate is 0, which is unlimited.
+func newBandwidthLimiter(rate uint64) *bandwidthLimiter {
+	if rate == 0 {
+		return nil
+	}
+	return &bandwidthLimiter{
+		rate:   rate,
+		tokens: float64(rate),
+		last:   time.Now(),
+	}
+}
+
+// wait takes n bytes from the bucket, waiting until the bucket is no longer in
+// debt or ctx is done. A nil limiter never waits.
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	if l == nil {
+		return nil
+	}
+	l.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * float64(l.rate)
+	if max := float64(l.rate); l.tokens > max {
+		l.tokens = max
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / float64(l.rate) * float64(time.Second))
+	}
+	l.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// limitedWriter writes to w no faster than each of its limiters allows.
+type limitedWriter struct {
+	ctx      context.Context
+	w        io.Writer
+	limiters []*bandwidthLimiter
+}
+
+func (l limitedWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > maxLimitedWrite {
+			chunk = chunk[:maxLimitedWrite]
+		}
+		for _, lim := range l.limiters {
+			if err := lim.wait(l.ctx, len(chunk)); err != nil {
+				return written, err
+			}
+		}
+		n, err := l.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBandwidthLimiter_Unlimited(t *testing.T) {
+	l := newBandwidthLimiter(0)
+	assert.Nil(t, l)
+	assert.NoError(t, l.wait(context.Background(), 1<<30))
+}
+
+func TestBandwidthLimiter_Burst(t *testing.T) {
+	const rate = 10000
+	l := newBandwidthLimiter(rate)
+	require.NotNil(t, l)
+
+	// A full bucket lets a second worth of bytes through at once
+	start := time.Now()
+	require.NoError(t, l.wait(context.Background(), rate))
+	assert.Less(t, int64(time.Since(start)), int64(100*time.Millisecond))
+
+	// After idling the bucket still holds at most a second worth of bytes
+	l.last = time.Now().Add(-10 * time.Second)
+	start = time.Now()
+	require.NoError(t, l.wait(context.Background(), rate))
+	assert.Less(t, int64(time.Since(start)), int64(100*time.Millisecond))
+	require.NoError(t, l.wait(context.Background(), rate/2))
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(400*time.Millisecond))
+}
+
+func TestBandwidthLimiter_Debt(t *testing.T) {
+	l := newBandwidthLimiter(1000)
+	require.NotNil(t, l)
+
+	// A write larger than the bucket goes into debt instead of being refused
+	// and waits for it to be paid back, as do the writes after it
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, l.wait(ctx, 3000))
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, l.wait(ctx, 1))
+}
+
+func TestLimitedWriter_Rate(t *testing.T) {
+	const rate = 32 * 1024
+	var buf bytes.Buffer
+	w := limitedWriter{
+		ctx:      context.Background(),
+		w:        &buf,
+		limiters: []*bandwidthLimiter{newBandwidthLimiter(rate), nil},
+	}
+	data := bytes.Repeat([]byte("a"), 2*rate)
+
+	// The first second worth of bytes is the burst, the second one is limited
+	start := time.Now()
+	n, err := w.Write(data)
+	elapsed := time.Since(start)
+	require.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, data, buf.Bytes())
+	assert.GreaterOrEqual(t, int64(elapsed), int64(800*time.Millisecond))
+	assert.Less(t, int64(elapsed), int64(3*time.Second))
+}
+
+func TestLimitedWriter_SlowestLimiter(t *testing.T) {
+	const rate = 16 * 1024
+	var buf bytes.Buffer
+	w := limitedWriter{
+		ctx:      context.Background(),
+		w:        &buf,
+		limiters: []*bandwidthLimiter{newBandwidthLimiter(100 * rate), newBandwidthLimiter(rate)},
+	}
+
+	start := time.Now()
+	_, err := w.Write(bytes.Repeat([]byte("a"), rate+rate/2))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(400*time.Millisecond))
+}
@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/globals"
+	"github.com/hashicorp/boundary/internal/servers"
+	"google.golang.org/grpc/metadata"
+)
+
+// connectionCheckTimeout bounds the connection to the endpoint of a
+// connection check, including the TLS handshake
+const connectionCheckTimeout = 10 * time.Second
+
+// connectionCheckResults holds the results of connection checks until they
+// are sent to a controller with a status.
+type connectionCheckResults struct {
+	sync.Mutex
+	results []*servers.ConnectionCheck
+}
+
+func (r *connectionCheckResults) add(results ...*servers.ConnectionCheck) {
+	r.Lock()
+	defer r.Unlock()
+	r.results = append(r.results, results...)
+}
+
+// take returns the results held and forgets them.
+func (r *connectionCheckResults) take() []*servers.ConnectionCheck {
+	r.Lock()
+	defer r.Unlock()
+	ret := r.results
+	r.results = nil
+	return ret
+}
+
+// startConnectionChecks performs the connection checks a controller sent in
+// the header of a status response in the background. Their results are sent
+// with a later status.
+func (w *Worker) startConnectionChecks(ctx context.Context, header metadata.MD) {
+	for _, v := range header.Get(globals.ConnectionChecksMetadataKey) {
+		var checks []*servers.ConnectionCheck
+		if err := json.Unmarshal([]byte(v), &checks); err != nil {
+			w.logger.Error("error unmarshaling connection checks", "error", err)
+			continue
+		}
+		for _, c := range checks {
+			go func(c *servers.ConnectionCheck) {
+				result := w.checkConnection(ctx, c)
+				w.logger.Debug("performed connection check", "check_id", c.CheckId, "endpoint", c.Endpoint, "reachable", result.Reachable, "error", result.Error)
+				w.connectionCheckResults.add(result)
+			}(c)
+		}
+	}
+}
+
+// checkConnection connects to the endpoint of the check the way proxied
+// connections are made and performs a TLS handshake with it if requested,
+// verifying its certificate against the system roots.
+func (w *Worker) checkConnection(ctx context.Context, c *servers.ConnectionCheck) *servers.ConnectionCheck {
+	result := &servers.ConnectionCheck{CheckId: c.CheckId}
+	ctx, cancel := context.WithTimeout(ctx, connectionCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := w.egressDialer.DialContext(ctx, c.Endpoint)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer conn.Close()
+	if c.Tls {
+		host, _, err := net.SplitHostPort(c.Endpoint)
+		if err != nil {
+			host = c.Endpoint
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			if err := conn.SetDeadline(deadline); err != nil {
+				result.Error = err.Error()
+				return result
+			}
+		}
+		if err := tls.Client(conn, &tls.Config{ServerName: host}).Handshake(); err != nil {
+			result.Error = "tls handshake failed: " + err.Error()
+			return result
+		}
+	}
+	result.Reachable = true
+	result.LatencyMs = time.Since(start).Milliseconds()
+	return result
+}
@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/globals"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+// testConnectionCheckWorker returns a worker dialing directly which can
+// perform connection checks.
+func testConnectionCheckWorker(t *testing.T) *Worker {
+	t.Helper()
+	d, err := newEgressDialer("", 0, nil)
+	require.NoError(t, err)
+	return &Worker{
+		logger:       hclog.NewNullLogger(),
+		egressDialer: d,
+	}
+}
+
+// closedAddr returns an address nothing listens on.
+func closedAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+	return addr
+}
+
+// silentListener returns the address of a listener which accepts connections
+// but never writes to them.
+func silentListener(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestWorker_CheckConnection(t *testing.T) {
+	w := testConnectionCheckWorker(t)
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(tlsServer.Close)
+
+	t.Run("reachable", func(t *testing.T) {
+		got := w.checkConnection(context.Background(), &servers.ConnectionCheck{CheckId: "scc_1", Endpoint: silentListener(t)})
+		assert.Equal(t, "scc_1", got.CheckId)
+		assert.True(t, got.Reachable)
+		assert.Empty(t, got.Error)
+		assert.GreaterOrEqual(t, got.LatencyMs, int64(0))
+	})
+	t.Run("unreachable", func(t *testing.T) {
+		got := w.checkConnection(context.Background(), &servers.ConnectionCheck{CheckId: "scc_2", Endpoint: closedAddr(t)})
+		assert.Equal(t, "scc_2", got.CheckId)
+		assert.False(t, got.Reachable)
+		assert.NotEmpty(t, got.Error)
+	})
+	t.Run("tls timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		got := w.checkConnection(ctx, &servers.ConnectionCheck{CheckId: "scc_3", Endpoint: silentListener(t), Tls: true})
+		assert.Less(t, int64(time.Since(start)), int64(connectionCheckTimeout))
+		assert.False(t, got.Reachable)
+		assert.True(t, strings.HasPrefix(got.Error, "tls handshake failed"), got.Error)
+	})
+	t.Run("untrusted tls", func(t *testing.T) {
+		// The test server's certificate isn't signed by the system roots
+		endpoint := strings.TrimPrefix(tlsServer.URL, "https://")
+		got := w.checkConnection(context.Background(), &servers.ConnectionCheck{CheckId: "scc_4", Endpoint: endpoint, Tls: true})
+		assert.False(t, got.Reachable)
+		assert.True(t, strings.HasPrefix(got.Error, "tls handshake failed"), got.Error)
+	})
+}
+
+func TestWorker_StartConnectionChecks(t *testing.T) {
+	w := testConnectionCheckWorker(t)
+	checks, err := json.Marshal([]*servers.ConnectionCheck{
+		{CheckId: "scc_1", Endpoint: silentListener(t)},
+		{CheckId: "scc_2", Endpoint: closedAddr(t)},
+	})
+	require.NoError(t, err)
+	header := metadata.Pairs(globals.ConnectionChecksMetadataKey, string(checks))
+	// Invalid checks are skipped
+	header.Append(globals.ConnectionChecksMetadataKey, "not json")
+
+	w.startConnectionChecks(context.Background(), header)
+	got := map[string]*servers.ConnectionCheck{}
+	require.Eventually(t, func() bool {
+		for _, c := range w.connectionCheckResults.take() {
+			got[c.CheckId] = c
+		}
+		return len(got) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, got["scc_1"].Reachable)
+	assert.False(t, got["scc_2"].Reachable)
+	assert.NotEmpty(t, got["scc_2"].Error)
+
+	// Results are only taken once
+	assert.Empty(t, w.connectionCheckResults.take())
+}
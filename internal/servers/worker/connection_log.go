@@ -0,0 +1,167 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/session"
+)
+
+const defaultConnectionLogSyslogTag = "boundary-worker"
+
+// connectionRecordSchemaVersion is the version of the schema of connection
+// records in the event schema registry.
+const connectionRecordSchemaVersion = 3
+
+// connectionRecord is the record of a proxied connection written to the
+// connection log when it is closed.
+type connectionRecord struct {
+	SchemaVersion int       `json:"schema_version"`
+	Time          time.Time `json:"time"`
+	Worker        string    `json:"worker"`
+	SessionId     string    `json:"session_id"`
+	ConnectionId  string    `json:"connection_id"`
+	UserId        string    `json:"user_id"`
+	UserName      string    `json:"user_name,omitempty"`
+	TargetId      string    `json:"target_id"`
+	HostId        string    `json:"host_id"`
+	ClientAddr    string    `json:"client_addr"`
+	Endpoint      string    `json:"endpoint"`
+	EndpointAddr  string    `json:"endpoint_addr,omitempty"`
+	BytesUp       uint64    `json:"bytes_up"`
+	BytesDown     uint64    `json:"bytes_down"`
+	StartTime     time.Time `json:"start_time"`
+	DurationMs    int64     `json:"duration_ms"`
+	CloseReason   string    `json:"close_reason"`
+	// CredentialCheckoutId is the checkout of the shared credential of the
+	// target used by the session, and PeerIdentity how the identity of the
+	// user was passed to the endpoint
+	CredentialCheckoutId string `json:"credential_checkout_id,omitempty"`
+	PeerIdentity         string `json:"peer_identity,omitempty"`
+	// Protocol is the subprotocol of the custom protocol handler which
+	// proxied the connection, if any, and ProtocolEvents the events it
+	// recorded
+	Protocol       string          `json:"protocol,omitempty"`
+	ProtocolEvents []protocolEvent `json:"protocol_events,omitempty"`
+}
+
+// connectionLog writes the records of proxied connections to a file and/or
+// syslog, so they are available locally even if no controller is reachable.
+type connectionLog struct {
+	sync.Mutex
+	writers []io.WriteCloser
+}
+
+// newConnectionLog opens the destinations of the connection log configured
+// by conf.
+func newConnectionLog(conf *config.ConnectionLog) (*connectionLog, error) {
+	l := &connectionLog{}
+	if conf.File != "" {
+		f, err := os.OpenFile(conf.File, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+		if err != nil {
+			return nil, fmt.Errorf("error opening connection log file: %w", err)
+		}
+		l.writers = append(l.writers, f)
+	}
+	if conf.Syslog {
+		tag := conf.SyslogTag
+		if tag == "" {
+			tag = defaultConnectionLogSyslogTag
+		}
+		s, err := newSyslogWriter(conf.SyslogFacility, tag)
+		if err != nil {
+			l.close()
+			return nil, fmt.Errorf("error connecting connection log to syslog: %w", err)
+		}
+		l.writers = append(l.writers, s)
+	}
+	return l, nil
+}
+
+// write writes the record to all destinations, returning the first error.
+func (l *connectionLog) write(rec *connectionRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	l.Lock()
+	defer l.Unlock()
+	var firstErr error
+	for _, w := range l.writers {
+		if _, err := w.Write(line); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (l *connectionLog) close() {
+	l.Lock()
+	defer l.Unlock()
+	for _, w := range l.writers {
+		_ = w.Close()
+	}
+	l.writers = nil
+}
+
+// logConnection writes the record of the closed connection to the connection
+// log, if one is configured.
+func (w *Worker) logConnection(si *sessionInfo, ci *connInfo, clientAddr, endpoint string) {
+	if w.connectionLog == nil {
+		return
+	}
+	closeTime := time.Now()
+	si.RLock()
+	resp := si.lookupSessionResponse
+	rec := &connectionRecord{
+		SchemaVersion: connectionRecordSchemaVersion,
+		Time:          closeTime,
+		Worker:        w.conf.RawConfig.Worker.Name,
+		SessionId:     si.id,
+		ConnectionId:  ci.id,
+		UserId:        resp.GetUserId(),
+		UserName:      si.peer.userName,
+		TargetId:      resp.GetTargetId(),
+		HostId:        resp.GetHostId(),
+		ClientAddr:    clientAddr,
+		Endpoint:      endpoint,
+		EndpointAddr:  ci.endpointAddr,
+		BytesUp:       ci.bytesUp.Load(),
+		BytesDown:     ci.bytesDown.Load(),
+		StartTime:     ci.connectTime,
+		DurationMs:    closeTime.Sub(ci.connectTime).Milliseconds(),
+		CloseReason:   connectionLogCloseReason(ci).String(),
+
+		CredentialCheckoutId: si.peer.credentialCheckoutId,
+		PeerIdentity:         ci.peerIdentityMode,
+		Protocol:             ci.protocol,
+		ProtocolEvents:       ci.protocolEvents,
+	}
+	si.RUnlock()
+	if err := w.connectionLog.write(rec); err != nil {
+		w.logger.Error("error writing connection log", "error", err, "connection_id", ci.id)
+	}
+}
+
+// connectionLogCloseReason returns why the connection was closed: the reason
+// set while proxying if any, or else derived from its context.
+func connectionLogCloseReason(ci *connInfo) session.ClosedReason {
+	if ci.closeReason != "" {
+		return ci.closeReason
+	}
+	switch ci.connCtx.Err() {
+	case nil:
+		return session.ConnectionClosedByUser
+	case context.DeadlineExceeded:
+		return session.ConnectionTimedOut
+	default:
+		return session.ConnectionCanceled
+	}
+}
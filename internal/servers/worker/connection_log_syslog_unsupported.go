@@ -0,0 +1,13 @@
+// +build windows plan9
+
+package worker
+
+import (
+	"errors"
+	"io"
+)
+
+// newSyslogWriter fails since syslog is not supported on this platform.
+func newSyslogWriter(facility, tag string) (io.WriteCloser, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/globals"
+	"google.golang.org/grpc/metadata"
+)
+
+// connectionRateLimiter is a token bucket limiting the connections a session
+// opens per minute. It holds at most a minute worth of connections, so a
+// session may open them all at once and then one more each time one is
+// earned back.
+type connectionRateLimiter struct {
+	sync.Mutex
+	perMinute uint32
+	tokens    float64
+	last      time.Time
+}
+
+// newConnectionRateLimiter returns a limiter allowing perMinute connections
+// per minute, or nil if perMinute is 0, which is unlimited.
+func newConnectionRateLimiter(perMinute uint32) *connectionRateLimiter {
+	if perMinute == 0 {
+		return nil
+	}
+	return &connectionRateLimiter{
+		perMinute: perMinute,
+		tokens:    float64(perMinute),
+		last:      time.Now(),
+	}
+}
+
+// allow takes a connection from the bucket, returning false without taking
+// one if it is empty. A nil limiter allows every connection.
+func (l *connectionRateLimiter) allow() bool {
+	if l == nil {
+		return true
+	}
+	l.Lock()
+	defer l.Unlock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Minutes() * float64(l.perMinute)
+	if max := float64(l.perMinute); l.tokens > max {
+		l.tokens = max
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// connectionRateLimit returns the connections per minute limit sent by the
+// controller in the header of a session lookup. A missing or invalid value is
+// unlimited.
+func connectionRateLimit(md metadata.MD) uint32 {
+	v := md.Get(globals.ConnectionRateLimitMetadataKey)
+	if len(v) == 0 {
+		return 0
+	}
+	n, err := strconv.ParseUint(v[0], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(n)
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/libs/endpoint"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/resolver"
@@ -32,11 +33,7 @@ func (w *Worker) startControllerConnections() error {
 		case strings.HasPrefix(addr, "/"):
 			initialAddrs = append(initialAddrs, resolver.Address{Addr: addr})
 		default:
-			host, port, err := net.SplitHostPort(addr)
-			if err != nil && strings.Contains(err.Error(), "missing port in address") {
-				w.logger.Trace("missing port in controller address, using port 9201", "address", addr)
-				host, port, err = net.SplitHostPort(net.JoinHostPort(addr, "9201"))
-			}
+			host, port, err := endpoint.SplitHostPort(addr, "9201")
 			if err != nil {
 				return fmt.Errorf("error parsing controller address: %w", err)
 			}
@@ -48,6 +45,7 @@ func (w *Worker) startControllerConnections() error {
 		return errors.New("no initial controller addresses found")
 	}
 
+	w.controllerAddrs.Store(initialAddrs)
 	w.Resolver().InitialState(resolver.State{
 		Addresses: initialAddrs,
 	})
@@ -58,7 +56,7 @@ func (w *Worker) startControllerConnections() error {
 	return nil
 }
 
-func (w Worker) controllerDialerFunc() func(context.Context, string) (net.Conn, error) {
+func (w *Worker) controllerDialerFunc() func(context.Context, string) (net.Conn, error) {
 	return func(ctx context.Context, addr string) (net.Conn, error) {
 		tlsConf, authInfo, err := w.workerAuthTLSConfig()
 		if err != nil {
@@ -70,7 +68,7 @@ func (w Worker) controllerDialerFunc() func(context.Context, string) (net.Conn,
 		case strings.HasPrefix(addr, "/"):
 			nonTlsConn, err = dialer.DialContext(ctx, "unix", addr)
 		default:
-			nonTlsConn, err = dialer.DialContext(ctx, "tcp", addr)
+			nonTlsConn, err = w.egressDialer.DialContext(ctx, addr)
 		}
 		if err != nil {
 			return nil, fmt.Errorf("unable to dial to controller: %w", err)
@@ -124,6 +122,7 @@ func (w *Worker) createClientConn(addr string) error {
 		return fmt.Errorf("error dialing controller for worker auth: %w", err)
 	}
 
+	w.controllerConn.Store(cc)
 	w.controllerStatusConn.Store(pbs.NewServerCoordinationServiceClient(cc))
 	w.controllerSessionConn.Store(pbs.NewSessionServiceClient(cc))
 
@@ -131,7 +130,7 @@ func (w *Worker) createClientConn(addr string) error {
 	return nil
 }
 
-func (w Worker) workerAuthTLSConfig() (*tls.Config, *base.WorkerAuthInfo, error) {
+func (w *Worker) workerAuthTLSConfig() (*tls.Config, *base.WorkerAuthInfo, error) {
 	var err error
 	info := &base.WorkerAuthInfo{
 		Name:        w.conf.RawConfig.Worker.Name,
@@ -189,7 +188,7 @@ func (w Worker) workerAuthTLSConfig() (*tls.Config, *base.WorkerAuthInfo, error)
 	if err != nil {
 		return nil, nil, err
 	}
-	encInfo, err := w.conf.WorkerAuthKms.Encrypt(context.Background(), marshaledInfo, nil)
+	encInfo, err := w.workerAuthKms().Encrypt(context.Background(), marshaledInfo, nil)
 	if err != nil {
 		return nil, nil, err
 	}
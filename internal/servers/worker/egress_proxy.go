@@ -0,0 +1,274 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/dnscache"
+	"golang.org/x/net/proxy"
+)
+
+// egressDialer dials the connections of the worker to controllers and
+// targets, through an egress proxy if one is configured. HTTP proxies are
+// used with CONNECT and SOCKS proxies with SOCKS5; both may require a user
+// name and password, given as the user info of the proxy URL.
+type egressDialer struct {
+	dialer *net.Dialer
+	// resolver looks up the hosts dialed directly; it is nil if lookups
+	// aren't cached
+	resolver *dnscache.Resolver
+	// proxyUrl is nil if connections are dialed directly
+	proxyUrl *url.URL
+	// socks5Auth authenticates to the proxy if it is a SOCKS proxy requiring
+	// a user name and password
+	socks5Auth *proxy.Auth
+}
+
+// dialFunc is a dial function usable as the forward dialer of a proxy.
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+func (f dialFunc) Dial(network, addr string) (net.Conn, error) {
+	return f(context.Background(), network, addr)
+}
+
+func (f dialFunc) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return f(ctx, network, addr)
+}
+
+// newEgressDialer returns a dialer using the proxy at rawUrl, or dialing
+// directly if rawUrl is empty, looking up hosts with the resolver. keepAlive
+// is the TCP keepalive period of the dialed connections, as in net.Dialer.
+func newEgressDialer(rawUrl string, keepAlive time.Duration, resolver *dnscache.Resolver) (*egressDialer, error) {
+	d := &egressDialer{
+		dialer:   &net.Dialer{KeepAlive: keepAlive},
+		resolver: resolver,
+	}
+	if rawUrl == "" {
+		return d, nil
+	}
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing egress proxy url: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported egress proxy scheme %q; supported schemes are http and socks5", u.Scheme)
+	}
+	if u.Port() == "" {
+		port := "3128"
+		if u.Scheme == "socks5" {
+			port = "1080"
+		}
+		u.Host = net.JoinHostPort(u.Hostname(), port)
+	}
+	d.proxyUrl = u
+	if u.Scheme == "socks5" && u.User != nil {
+		d.socks5Auth = &proxy.Auth{User: u.User.Username()}
+		d.socks5Auth.Password, _ = u.User.Password()
+	}
+	return d, nil
+}
+
+// proxied returns true if connections are dialed through a proxy.
+func (d *egressDialer) proxied() bool {
+	return d.proxyUrl != nil
+}
+
+// DialContext connects to addr, which must be a TCP host and port.
+func (d *egressDialer) DialContext(ctx context.Context, addr string) (net.Conn, error) {
+	dial := d.resolver.DialContext(d.dialer)
+	switch {
+	case !d.proxied():
+		return dial(ctx, "tcp", addr)
+	case d.proxyUrl.Scheme == "socks5":
+		return d.socks5Connect(ctx, addr)
+	}
+	proxyConn, err := dial(ctx, "tcp", d.proxyUrl.Host)
+	if err != nil {
+		return nil, fmt.Errorf("error dialing egress proxy: %w", err)
+	}
+	// Bound the proxy handshake by ctx; the connection has no deadline after
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			proxyConn.SetDeadline(time.Now())
+		case <-stop:
+		}
+	}()
+
+	conn, err := d.httpConnect(proxyConn, addr)
+	close(stop)
+	<-stopped
+	if err != nil {
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// DialPreferring connects to addr like DialContext, but when addr is a host
+// name with both IPv4 and IPv6 addresses, the addresses of the family given
+// by preferIPv6 are tried first. The worker uses this to reach dual-stack
+// targets over the family the client connected with. Proxied connections are
+// resolved by the proxy and dialed as with DialContext.
+func (d *egressDialer) DialPreferring(ctx context.Context, addr string, preferIPv6 bool) (net.Conn, error) {
+	if d.proxied() {
+		return d.DialContext(ctx, addr)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return d.DialContext(ctx, addr)
+	}
+	ips, err := d.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var firstErr error
+	for _, ip := range preferFamily(ips, preferIPv6) {
+		conn, err := d.dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	if firstErr == nil {
+		firstErr = fmt.Errorf("no addresses found for %s", host)
+	}
+	return nil, firstErr
+}
+
+// preferFamily returns the IPs of ips, those of the preferred family first,
+// otherwise keeping the order of the resolver.
+func preferFamily(ips []net.IPAddr, preferIPv6 bool) []net.IP {
+	preferred := make([]net.IP, 0, len(ips))
+	var others []net.IP
+	for _, ip := range ips {
+		if (ip.IP.To4() == nil) == preferIPv6 {
+			preferred = append(preferred, ip.IP)
+		} else {
+			others = append(others, ip.IP)
+		}
+	}
+	return append(preferred, others...)
+}
+
+// httpConnect asks the HTTP proxy at the other end of conn to connect to
+// addr with CONNECT.
+func (d *egressDialer) httpConnect(conn net.Conn, addr string) (net.Conn, error) {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if u := d.proxyUrl.User; u != nil {
+		password, _ := u.Password()
+		creds := base64.StdEncoding.EncodeToString([]byte(u.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+creds)
+	}
+	if err := req.Write(conn); err != nil {
+		return conn, fmt.Errorf("error writing CONNECT request to egress proxy: %w", err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return conn, fmt.Errorf("error reading CONNECT response from egress proxy: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return conn, fmt.Errorf("egress proxy refused to connect to %s: %s", addr, resp.Status)
+	}
+	if br.Buffered() > 0 {
+		// The proxy already sent data from addr, which must be read first
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+	return conn, nil
+}
+
+// socks5Connect connects to addr through the SOCKS5 proxy.
+func (d *egressDialer) socks5Connect(ctx context.Context, addr string) (net.Conn, error) {
+	// The connection returned by the SOCKS dialer hides CloseWrite, so the
+	// connection to the proxy is kept to be returned instead
+	var proxyConn net.Conn
+	dial := d.resolver.DialContext(d.dialer)
+	forward := dialFunc(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		proxyConn = conn
+		return conn, err
+	})
+	socks5, err := proxy.SOCKS5("tcp", d.proxyUrl.Host, d.socks5Auth, forward)
+	if err != nil {
+		return nil, fmt.Errorf("error creating egress proxy dialer: %w", err)
+	}
+	if _, err := socks5.(proxy.ContextDialer).DialContext(ctx, "tcp", addr); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("error connecting through egress proxy: %w", err)
+	}
+	return proxyConn, nil
+}
+
+// bufferedConn is a connection with data already read into r.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// CloseWrite half-closes the underlying connection if it supports it.
+func (c *bufferedConn) CloseWrite() error {
+	return closeWrite(c.Conn)
+}
+
+// endpointAddr returns the address of the target a connection dialed by d to
+// addr is connected to. Proxied connections are connected to the proxy, so
+// the host of addr is resolved instead, falling back to the address of the
+// proxy if the worker can't resolve it.
+func (d *egressDialer) endpointAddr(ctx context.Context, conn net.Conn, addr string) *net.TCPAddr {
+	tcpAddr, _ := conn.RemoteAddr().(*net.TCPAddr)
+	if !d.proxied() {
+		return tcpAddr
+	}
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return tcpAddr
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return tcpAddr
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return &net.TCPAddr{IP: ip, Port: port}
+	}
+	ips, err := d.resolver.LookupIPAddr(ctx, host)
+	if err != nil || len(ips) == 0 {
+		return tcpAddr
+	}
+	return &net.TCPAddr{IP: ips[0].IP, Port: port}
+}
@@ -5,11 +5,14 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/boundary/globals"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/libs/latency"
 	"github.com/hashicorp/boundary/internal/proxy"
 	"github.com/hashicorp/shared-secure-libs/configutil"
+	"google.golang.org/protobuf/proto"
 	"nhooyr.io/websocket"
 	"nhooyr.io/websocket/wspb"
 )
@@ -40,6 +43,11 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 		}
 		sessionId := r.TLS.ServerName
 
+		// Time the phases of establishing the connection; the endpoint dial
+		// is timed by the protocol handler, which finishes the budget.
+		budget := latency.New("worker", "establish_connection")
+		endHandshake := budget.Start("worker_handshake")
+
 		clientIp, clientPort, err := net.SplitHostPort(r.RemoteAddr)
 		if err != nil {
 			w.logger.Error("unable to understand remote address", "error", err, "remote_addr", r.RemoteAddr)
@@ -78,7 +86,7 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 		w.logger.Trace("found session in session info map")
 
 		opts := &websocket.AcceptOptions{
-			Subprotocols: []string{globals.TcpProxyV1},
+			Subprotocols: w.subprotocols(),
 		}
 		conn, err := websocket.Accept(wr, r, opts)
 		if err != nil {
@@ -106,8 +114,31 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 			return
 		}
 
+		endHandshake()
 		w.logger.Trace("proxy handshake finished")
 
+		endAuthorization := budget.Start("connection_authorization")
+
+		if resumeId := r.Header.Get(proxy.ResumeConnectionHeader); resumeId != "" {
+			// Connections are only resumed within activated sessions, by
+			// clients presenting the tofu token of the session
+			if tofuToken == "" || tofuToken != handshake.GetTofuToken() {
+				w.logger.Error("WARNING: mismatched tofu token resuming connection", "session_id", sessionId, "connection_id", resumeId)
+				conn.Close(websocket.StatusPolicyViolation, "tofu token not allowed")
+				return
+			}
+			si.RLock()
+			connectionLimit := si.lookupSessionResponse.GetConnectionLimit()
+			si.RUnlock()
+			w.resumeConnection(connCtx, conn, si, resumeId, r.Header.Get(proxy.ResumeReceivedHeader), &proxy.HandshakeResult{
+				Expiration:      expiration,
+				ConnectionLimit: connectionLimit,
+				// Resuming a connection doesn't use one of the session
+				ConnectionsLeft: -1,
+			})
+			return
+		}
+
 		if tofuToken != "" {
 			if tofuToken != handshake.GetTofuToken() {
 				w.logger.Error("WARNING: mismatched tofu token", "session_id", sessionId)
@@ -127,11 +158,29 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 				conn.Close(websocket.StatusInternalError, "unable to activate session")
 				return
 			}
+			// Record the tofu token so later connections to the session can be
+			// verified locally
+			si.Lock()
+			resp := proto.Clone(si.lookupSessionResponse).(*pbs.LookupSessionResponse)
+			resp.TofuToken = handshake.GetTofuToken()
+			si.lookupSessionResponse = resp
+			si.Unlock()
+		}
+
+		// Refuse connections over the rate limit of the session before they
+		// are authorized, so they don't count toward its connection limit
+		si.RLock()
+		connectionRate := si.connectionRate
+		si.RUnlock()
+		if !connectionRate.allow() {
+			w.logger.Warn("connection rate limit of session exceeded", "session_id", sessionId, "connections_per_minute", connectionRate.perMinute)
+			conn.Close(websocket.StatusTryAgainLater, "connection rate limit exceeded")
+			return
 		}
 
 		var ci *connInfo
 		var connsLeft int32
-		ci, connsLeft, err = w.authorizeConnection(r.Context(), sessionId)
+		ci, connsLeft, err = w.authorizeConnectionCached(r.Context(), si)
 		if err != nil {
 			w.logger.Error("unable to authorize connection", "error", err)
 			conn.Close(websocket.StatusInternalError, "unable to authorize connection")
@@ -140,6 +189,9 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 
 		defer func() {
 			connectionId := ci.id
+			// The connection is logged locally first, so it is recorded even
+			// if no controller is reachable
+			w.logConnection(si, ci, clientAddr.String(), endpoint)
 			if err := w.closeConnections(r.Context(), map[string]string{
 				connectionId: si.id,
 			}); err != nil {
@@ -150,11 +202,14 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 		si.Lock()
 		ci.connCtx = connCtx
 		ci.connCancel = connCancel
+		ci.connectTime = time.Now()
 		si.connInfoMap[ci.id] = ci
 		si.status = sessStatus
 		connectionLimit := si.lookupSessionResponse.GetConnectionLimit()
 		si.Unlock()
+		w.persistState()
 
+		endAuthorization()
 		w.logger.Trace("authorized connection", "connection_id", ci.id)
 
 		handshakeResult := &proxy.HandshakeResult{
@@ -168,9 +223,11 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 			return
 		}
 
-		switch conn.Subprotocol() {
-		case globals.TcpProxyV1:
-			w.handleTcpProxyV1(connCtx, clientAddr, conn, si, ci.id, endpoint)
+		switch sp := conn.Subprotocol(); {
+		case sp == globals.TcpProxyV1 || sp == globals.TcpProxyResumableV1:
+			w.handleTcpProxyV1(latency.NewContext(connCtx, budget), clientAddr, conn, si, ci.id, endpoint)
+		case w.protocolHandlers[sp] != nil && !w.protocolHandlers[sp].disabled():
+			w.handleProtocolHandler(latency.NewContext(connCtx, budget), clientAddr, conn, si, ci.id, endpoint, w.protocolHandlers[sp])
 		default:
 			conn.Close(websocket.StatusProtocolError, "unsupported-protocol")
 			return
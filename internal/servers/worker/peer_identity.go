@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/hashicorp/boundary/globals"
+	"github.com/hashicorp/boundary/internal/target"
+	"google.golang.org/grpc/metadata"
+)
+
+// The types of the TLVs of the PROXY protocol version 2 header sent to
+// endpoints, from the range the protocol reserves for custom use.
+const (
+	proxyV2TypeUserId               = 0xE0
+	proxyV2TypeUserName             = 0xE1
+	proxyV2TypeSessionId            = 0xE2
+	proxyV2TypeCredentialCheckoutId = 0xE3
+)
+
+// proxyV2Signature starts every PROXY protocol version 2 header.
+var proxyV2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+// peerIdentity is the identity of the user of a session sent by the
+// controller in the header of a session lookup. mode is how it is passed to
+// the endpoint, empty if it isn't.
+type peerIdentity struct {
+	mode                 string
+	userName             string
+	injectedUserName     string
+	credentialCheckoutId string
+}
+
+// peerIdentityFromMetadata returns the peer identity sent by the controller in
+// the header of a session lookup. Missing values are empty, except the
+// injected user name which controllers predating user name templates don't
+// send, and is then the name of the user.
+func peerIdentityFromMetadata(md metadata.MD) peerIdentity {
+	get := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	peer := peerIdentity{
+		mode:                 get(globals.PeerIdentityModeMetadataKey),
+		userName:             get(globals.PeerUserNameMetadataKey),
+		injectedUserName:     get(globals.PeerInjectedUserNameMetadataKey),
+		credentialCheckoutId: get(globals.PeerCredentialCheckoutMetadataKey),
+	}
+	if len(md.Get(globals.PeerInjectedUserNameMetadataKey)) == 0 {
+		peer.injectedUserName = peer.userName
+	}
+	return peer
+}
+
+// writeProxyV2Header writes a PROXY protocol version 2 header for a
+// connection from src to dst to w, with the identity of the user of the
+// session in custom TLVs so the endpoint can attribute the connection to the
+// user.
+func writeProxyV2Header(w io.Writer, src, dst *net.TCPAddr, sessionId, userId string, peer peerIdentity) error {
+	var addrs bytes.Buffer
+	family := byte(0x11) // TCP over IPv4
+	srcIp, dstIp := src.IP.To4(), dst.IP.To4()
+	if srcIp == nil || dstIp == nil {
+		family = 0x21 // TCP over IPv6
+		srcIp, dstIp = src.IP.To16(), dst.IP.To16()
+	}
+	if srcIp == nil || dstIp == nil {
+		return fmt.Errorf("invalid addresses %s and %s for proxy protocol header", src, dst)
+	}
+	addrs.Write(srcIp)
+	addrs.Write(dstIp)
+	_ = binary.Write(&addrs, binary.BigEndian, uint16(src.Port))
+	_ = binary.Write(&addrs, binary.BigEndian, uint16(dst.Port))
+
+	for _, tlv := range []struct {
+		typ   byte
+		value string
+	}{
+		{proxyV2TypeUserId, userId},
+		{proxyV2TypeUserName, peer.userName},
+		{proxyV2TypeSessionId, sessionId},
+		{proxyV2TypeCredentialCheckoutId, peer.credentialCheckoutId},
+	} {
+		if tlv.value == "" {
+			continue
+		}
+		if len(tlv.value) > 0xFFFF {
+			return fmt.Errorf("value of proxy protocol tlv %#x is too long", tlv.typ)
+		}
+		addrs.WriteByte(tlv.typ)
+		_ = binary.Write(&addrs, binary.BigEndian, uint16(len(tlv.value)))
+		addrs.WriteString(tlv.value)
+	}
+	if addrs.Len() > 0xFFFF {
+		return fmt.Errorf("proxy protocol header is too long")
+	}
+
+	header := make([]byte, 0, len(proxyV2Signature)+4+addrs.Len())
+	header = append(header, proxyV2Signature...)
+	header = append(header, 0x21, family) // Version 2, PROXY command
+	header = append(header, byte(addrs.Len()>>8), byte(addrs.Len()))
+	header = append(header, addrs.Bytes()...)
+	_, err := w.Write(header)
+	return err
+}
+
+// sendPeerIdentity passes the identity of the user of the session to the
+// endpoint over conn as set by the mode of peer. It does nothing if the mode
+// is empty.
+func sendPeerIdentity(conn io.Writer, src, dst *net.TCPAddr, sessionId, userId string, peer peerIdentity) error {
+	switch peer.mode {
+	case "":
+		return nil
+	case target.PeerIdentityProxyV2:
+		return writeProxyV2Header(conn, src, dst, sessionId, userId, peer)
+	default:
+		return fmt.Errorf("unknown peer identity mode %q", peer.mode)
+	}
+}
@@ -0,0 +1,216 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"runtime/debug"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/boundary/globals"
+	"github.com/hashicorp/boundary/sdk/protocol"
+	ua "go.uber.org/atomic"
+	"nhooyr.io/websocket"
+)
+
+const (
+	// protocolHandshakeTimeout is how long a protocol handler has to answer
+	// the handshake when the worker starts.
+	protocolHandshakeTimeout = 10 * time.Second
+
+	// maxProtocolHandlerPanics is how many times a protocol handler may panic
+	// before the worker stops using it.
+	maxProtocolHandlerPanics = 3
+
+	// maxProtocolEvents is the most events a protocol handler may record for
+	// a connection; later ones are dropped.
+	maxProtocolEvents = 1000
+)
+
+// protocolHandler is a custom protocol handler which completed the
+// handshake.
+type protocolHandler struct {
+	name        string
+	subprotocol string
+	apiVersion  int
+	handler     protocol.Handler
+	panics      ua.Int32
+}
+
+// disabled returns true if the handler panicked too often to be used.
+func (p *protocolHandler) disabled() bool {
+	return p.panics.Load() >= maxProtocolHandlerPanics
+}
+
+// protocolEvent is an event recorded by a protocol handler for a connection.
+type protocolEvent struct {
+	Time       time.Time         `json:"time"`
+	Kind       string            `json:"kind"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// handlerPanic is the error of a protocol handler which panicked.
+type handlerPanic struct {
+	value interface{}
+	stack []byte
+}
+
+func (p *handlerPanic) Error() string {
+	return fmt.Sprintf("panic: %v\n%s", p.value, p.stack)
+}
+
+// protected calls fn, turning a panic into a handlerPanic error so a faulty
+// protocol handler can't crash the worker.
+func protected(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &handlerPanic{value: r, stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
+
+// startProtocolHandlers performs the handshake with the registered protocol
+// handlers, keeping those which agree on a supported version of the
+// interface and ask for an unused subprotocol.
+func (w *Worker) startProtocolHandlers(ctx context.Context) {
+	registered := protocol.Handlers()
+	names := make([]string, 0, len(registered))
+	for name := range registered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	handlers := make(map[string]*protocolHandler, len(registered))
+	for _, name := range names {
+		h := registered[name]
+		var resp *protocol.HandshakeResponse
+		err := protected(func() error {
+			hsCtx, cancel := context.WithTimeout(ctx, protocolHandshakeTimeout)
+			defer cancel()
+			var err error
+			resp, err = h.Handshake(hsCtx, &protocol.HandshakeRequest{
+				ApiVersion:    protocol.ApiVersion,
+				MinApiVersion: protocol.MinApiVersion,
+				WorkerName:    w.conf.RawConfig.Worker.Name,
+			})
+			return err
+		})
+		switch {
+		case err != nil:
+			w.logger.Error("protocol handler handshake failed; not using it", "protocol_handler", name, "error", err)
+			continue
+		case resp == nil:
+			w.logger.Error("protocol handler sent no handshake response; not using it", "protocol_handler", name)
+			continue
+		case !protocol.Supported(resp.ApiVersion):
+			w.logger.Error("protocol handler implements an unsupported version of the interface; not using it", "protocol_handler", name,
+				"api_version", resp.ApiVersion, "min_supported", protocol.MinApiVersion, "max_supported", protocol.ApiVersion)
+			continue
+		case resp.Subprotocol == "" || resp.Subprotocol == globals.TcpProxyV1 || resp.Subprotocol == globals.TcpProxyResumableV1:
+			w.logger.Error("protocol handler asked for an invalid subprotocol; not using it", "protocol_handler", name, "subprotocol", resp.Subprotocol)
+			continue
+		}
+		if other, ok := handlers[resp.Subprotocol]; ok {
+			w.logger.Error("protocol handler asked for the subprotocol of another; not using it", "protocol_handler", name, "subprotocol", resp.Subprotocol, "other", other.name)
+			continue
+		}
+		handlers[resp.Subprotocol] = &protocolHandler{
+			name:        name,
+			subprotocol: resp.Subprotocol,
+			apiVersion:  resp.ApiVersion,
+			handler:     h,
+		}
+		w.logger.Info("protocol handler started", "protocol_handler", name, "subprotocol", resp.Subprotocol, "api_version", resp.ApiVersion)
+	}
+	w.protocolHandlers = handlers
+}
+
+// subprotocols returns the websocket subprotocols the worker proxies, the
+// resumable TCP proxy first unless resuming connections is disabled.
+func (w *Worker) subprotocols() []string {
+	var ret []string
+	if w.resumeGracePeriod() > 0 {
+		ret = append(ret, globals.TcpProxyResumableV1)
+	}
+	ret = append(ret, globals.TcpProxyV1)
+	builtin := len(ret)
+	for sp, h := range w.protocolHandlers {
+		if !h.disabled() {
+			ret = append(ret, sp)
+		}
+	}
+	sort.Strings(ret[builtin:])
+	return ret
+}
+
+// connRecorder records the events of a connection for the connection log.
+type connRecorder struct {
+	si *sessionInfo
+	ci *connInfo
+}
+
+func (r connRecorder) Record(kind string, attributes map[string]string) {
+	ev := protocolEvent{Time: time.Now(), Kind: kind}
+	if len(attributes) > 0 {
+		ev.Attributes = make(map[string]string, len(attributes))
+		for k, v := range attributes {
+			ev.Attributes[k] = v
+		}
+	}
+	r.si.Lock()
+	defer r.si.Unlock()
+	if len(r.ci.protocolEvents) < maxProtocolEvents {
+		r.ci.protocolEvents = append(r.ci.protocolEvents, ev)
+	}
+}
+
+// handleProtocolHandler proxies the connection with the custom protocol
+// handler. A panic of the handler only fails the connection; the handler is
+// disabled once it panicked maxProtocolHandlerPanics times.
+func (w *Worker) handleProtocolHandler(connCtx context.Context, clientAddr *net.TCPAddr, conn *websocket.Conn, si *sessionInfo, connectionId, endpoint string, ph *protocolHandler) {
+	e := w.connectEndpoint(connCtx, clientAddr, conn, si, connectionId, endpoint)
+	if e == nil {
+		return
+	}
+	defer e.close()
+
+	si.Lock()
+	e.ci.protocol = ph.subprotocol
+	resp := si.lookupSessionResponse
+	pc := &protocol.Connection{
+		SessionId:    e.sessionId,
+		ConnectionId: connectionId,
+		TargetId:     resp.GetTargetId(),
+		HostId:       resp.GetHostId(),
+		Identity: protocol.Identity{
+			UserId:               resp.GetUserId(),
+			UserName:             si.peer.userName,
+			InjectedUserName:     si.peer.injectedUserName,
+			CredentialCheckoutId: si.peer.credentialCheckoutId,
+		},
+		Client:     e.client,
+		Endpoint:   e.endpoint,
+		Expiration: resp.GetExpiration().AsTime(),
+		Recorder:   connRecorder{si: si, ci: e.ci},
+	}
+	si.Unlock()
+
+	err := protected(func() error {
+		return ph.handler.Proxy(connCtx, pc)
+	})
+	if err == nil {
+		w.logger.Debug("protocol handler done", "protocol_handler", ph.name, "session_id", e.sessionId, "connection_id", connectionId)
+		return
+	}
+	w.logger.Error("protocol handler failed", "protocol_handler", ph.name, "session_id", e.sessionId, "connection_id", connectionId, "error", err)
+	conn.Close(websocket.StatusInternalError, "protocol handler failed")
+	var hp *handlerPanic
+	if errors.As(err, &hp) {
+		if ph.panics.Inc() == maxProtocolHandlerPanics {
+			w.logger.Error("protocol handler panicked too often; disabling it", "protocol_handler", ph.name, "panics", maxProtocolHandlerPanics)
+		}
+	}
+}
@@ -12,19 +12,55 @@ import (
 	"time"
 
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/proxy"
 	"github.com/hashicorp/boundary/internal/session"
+	"github.com/hashicorp/vault/sdk/helper/base62"
+	ua "go.uber.org/atomic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	validateSessionTimeout = 90 * time.Second
 )
 
+// errCachedConnectionLimit is returned authorizing a connection from the
+// cached lookup of a session whose connection limit is reached.
+var errCachedConnectionLimit = errors.New("connection limit of session reached")
+
 type connInfo struct {
 	id         string
 	connCtx    context.Context
 	connCancel context.CancelFunc
 	status     pbs.CONNECTIONSTATUS
 	closeTime  time.Time
+	// bytesUp and bytesDown count the bytes proxied from the client to the
+	// endpoint and back
+	bytesUp   ua.Uint64
+	bytesDown ua.Uint64
+	// closeReason is reported when the connection is closed, if set
+	closeReason session.ClosedReason
+	// connectTime is when the connection was authorized and endpointAddr the
+	// address of the endpoint it was proxied to, for the connection log
+	connectTime  time.Time
+	endpointAddr string
+	// peerIdentityMode is how the identity of the user was passed to the
+	// endpoint, empty if it wasn't
+	peerIdentityMode string
+	// protocol is the subprotocol of the custom protocol handler proxying
+	// the connection, if any, and protocolEvents the events it recorded
+	protocol       string
+	protocolEvents []protocolEvent
+	// resumable is the client side of the connection if the client can
+	// resume it after losing its websocket
+	resumable *proxy.ResumableConn
+	// cached is set if the connection was authorized from the cached lookup
+	// of its session while no controller was reachable. Controllers don't
+	// know of such connections, so they are neither connected nor closed on
+	// them.
+	cached bool
 }
 
 type sessionInfo struct {
@@ -34,6 +70,135 @@ type sessionInfo struct {
 	status                pbs.SESSIONSTATUS
 	lookupSessionResponse *pbs.LookupSessionResponse
 	connInfoMap           map[string]*connInfo
+	// lookupTime is when the session was last looked up from a controller.
+	// It is zeroed when the session is canceled so the cached lookup is not
+	// used.
+	lookupTime time.Time
+	// connectionBandwidth is the bytes per second limit of each connection of
+	// the session, 0 being unlimited, and sessionLimiter limits all of them
+	// combined; it is nil if unlimited.
+	connectionBandwidth uint64
+	sessionLimiter      *bandwidthLimiter
+	// connectionRate limits the connections the session opens per minute; it
+	// is nil if unlimited.
+	connectionRate *connectionRateLimiter
+	// peer is the identity of the user of the session, recorded with its
+	// connections and passed to the endpoint if the target asks for it
+	peer peerIdentity
+}
+
+// cachedTls returns the TLS configuration of the session from its last
+// lookup if the lookup is less than window old and the session is still
+// usable, so new connections can be accepted while no controller is
+// reachable. It returns nil if the cached lookup can't be used.
+func (si *sessionInfo) cachedTls(window time.Duration) *tls.Config {
+	si.RLock()
+	defer si.RUnlock()
+	switch {
+	case window <= 0,
+		si.lookupTime.IsZero(),
+		time.Since(si.lookupTime) > window,
+		si.status == pbs.SESSIONSTATUS_SESSIONSTATUS_CANCELING,
+		si.status == pbs.SESSIONSTATUS_SESSIONSTATUS_TERMINATED,
+		si.lookupSessionResponse.GetExpiration().AsTime().Before(time.Now()):
+		return nil
+	}
+	return si.sessionTls
+}
+
+// sessionCacheWindow returns how long a session lookup may be used while no
+// controller is reachable.
+func (w *Worker) sessionCacheWindow() time.Duration {
+	if w.conf.RawConfig.Worker == nil {
+		return 0
+	}
+	return w.conf.RawConfig.Worker.SessionCacheWindowDuration
+}
+
+// isControllerUnavailable returns true if err indicates no controller could
+// be reached, as opposed to a controller rejecting the request.
+func isControllerUnavailable(err error) bool {
+	var st interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &st) {
+		return st.GRPCStatus().Code() == codes.Unavailable
+	}
+	return false
+}
+
+// authorizeConnectionCached authorizes a connection to the session with a
+// controller. While no controller is reachable the connection is authorized
+// from the session's cached lookup instead, for as long as the lookup may be
+// used, so a controller outage doesn't fail new connections to already
+// authorized sessions.
+func (w *Worker) authorizeConnectionCached(ctx context.Context, si *sessionInfo) (*connInfo, int32, error) {
+	ci, connsLeft, err := w.authorizeConnection(ctx, si.id)
+	if err == nil || !isControllerUnavailable(err) {
+		return ci, connsLeft, err
+	}
+	ci, connsLeft, cacheErr := si.authorizeCachedConnection(w.sessionCacheWindow())
+	if cacheErr != nil {
+		w.logger.Debug("unable to authorize connection from cached session", "session_id", si.id, "error", cacheErr)
+		return nil, 0, err
+	}
+	w.logger.Warn("controller unavailable; authorized connection from cached session", "session_id", si.id, "connection_id", ci.id)
+	return ci, connsLeft, nil
+}
+
+// authorizeCachedConnection authorizes a connection to the session from its
+// last lookup if the lookup may still be used, returning the connection and
+// the connections left, -1 being unlimited. Only the connections of the
+// session known to the worker count toward its connection limit, so the
+// connection is added to the session right away.
+func (si *sessionInfo) authorizeCachedConnection(window time.Duration) (*connInfo, int32, error) {
+	if si.cachedTls(window) == nil {
+		return nil, 0, errors.New("no usable cached lookup of session")
+	}
+	id, err := base62.Random(10)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error generating connection id: %w", err)
+	}
+	si.Lock()
+	defer si.Unlock()
+	connsLeft := int32(-1)
+	if limit := si.lookupSessionResponse.GetConnectionLimit(); limit >= 0 {
+		connsLeft = limit - int32(len(si.connInfoMap))
+		if connsLeft <= 0 {
+			return nil, 0, errCachedConnectionLimit
+		}
+		connsLeft--
+	}
+	ci := &connInfo{
+		id:     session.ConnectionPrefix + "_" + id,
+		status: pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_AUTHORIZED,
+		cached: true,
+	}
+	si.connInfoMap[ci.id] = ci
+	return ci, connsLeft, nil
+}
+
+// verifiedTls returns the TLS configuration of the session if it has been
+// activated and the authorization claims in its certificate verify with the
+// worker auth key, so new connections to the session don't each need a
+// controller lookup. It returns nil if the session must be looked up.
+func (w *Worker) verifiedTls(si *sessionInfo) *tls.Config {
+	if w.workerAuthKms() == nil || w.conf.RawConfig.Worker == nil {
+		return nil
+	}
+	si.RLock()
+	defer si.RUnlock()
+	if si.status != pbs.SESSIONSTATUS_SESSIONSTATUS_ACTIVE ||
+		si.lookupSessionResponse.GetTofuToken() == "" ||
+		si.sessionTls == nil ||
+		len(si.sessionTls.Certificates) == 0 {
+		return nil
+	}
+	if _, err := session.VerifyAuthorizationClaims(w.baseContext, w.workerAuthKms(), si.sessionTls.Certificates[0].Leaf, w.conf.RawConfig.Worker.Name); err != nil {
+		if !errors.Is(err, session.ErrNoAuthorizationClaims) {
+			w.logger.Debug("unable to verify session locally", "session_id", si.id, "error", err)
+		}
+		return nil
+	}
+	return si.sessionTls
 }
 
 func (w *Worker) getSessionTls(hello *tls.ClientHelloInfo) (*tls.Config, error) {
@@ -47,6 +212,13 @@ func (w *Worker) getSessionTls(hello *tls.ClientHelloInfo) (*tls.Config, error)
 		return nil, fmt.Errorf("could not find session ID in SNI")
 	}
 
+	if siRaw, ok := w.sessionInfoMap.Load(sessionId); ok {
+		if tlsConf := w.verifiedTls(siRaw.(*sessionInfo)); tlsConf != nil {
+			w.logger.Trace("session verified locally", "session_id", sessionId)
+			return tlsConf, nil
+		}
+	}
+
 	rawConn := w.controllerSessionConn.Load()
 	if rawConn == nil {
 		w.logger.Trace("could not get a controller client", "session_id", sessionId)
@@ -66,10 +238,19 @@ func (w *Worker) getSessionTls(hello *tls.ClientHelloInfo) (*tls.Config, error)
 	defer cancel()
 
 	w.logger.Trace("looking up session", "session_id", sessionId)
+	var header metadata.MD
 	resp, err := conn.LookupSession(timeoutContext, &pbs.LookupSessionRequest{
 		SessionId: sessionId,
-	})
+	}, grpc.Header(&header))
 	if err != nil {
+		if isControllerUnavailable(err) {
+			if siRaw, ok := w.sessionInfoMap.Load(sessionId); ok {
+				if tlsConf := siRaw.(*sessionInfo).cachedTls(w.sessionCacheWindow()); tlsConf != nil {
+					w.logger.Warn("controller unavailable; using cached session lookup", "session_id", sessionId)
+					return tlsConf, nil
+				}
+			}
+		}
 		return nil, fmt.Errorf("error validating session: %w", err)
 	}
 
@@ -109,7 +290,13 @@ func (w *Worker) getSessionTls(hello *tls.ClientHelloInfo) (*tls.Config, error)
 		lookupSessionResponse: resp,
 		status:                resp.GetStatus(),
 		connInfoMap:           make(map[string]*connInfo),
+		lookupTime:            time.Now(),
 	}
+	si.connectionBandwidth = resp.GetConnectionBytesPerSecond()
+	si.sessionLimiter = newBandwidthLimiter(resp.GetSessionBytesPerSecond())
+	connectionsPerMinute := connectionRateLimit(header)
+	si.connectionRate = newConnectionRateLimiter(connectionsPerMinute)
+	si.peer = peerIdentityFromMetadata(header)
 	// TODO: Periodicially clean this up. We can't rely on things in here but
 	// not in cancellation because they could be on the way to being
 	// established. However, since cert lifetimes are short, we can simply range
@@ -120,6 +307,19 @@ func (w *Worker) getSessionTls(hello *tls.ClientHelloInfo) (*tls.Config, error)
 		actualSi := actualSiRaw.(*sessionInfo)
 		actualSi.Lock()
 		actualSi.lookupSessionResponse = resp
+		actualSi.sessionTls = tlsConf
+		actualSi.lookupTime = si.lookupTime
+		actualSi.connectionBandwidth = si.connectionBandwidth
+		actualSi.peer = si.peer
+		// Keep the limiter of the session's current connections unless the
+		// limit changed
+		if actualSi.sessionLimiter == nil || si.sessionLimiter == nil || actualSi.sessionLimiter.rate != resp.GetSessionBytesPerSecond() {
+			actualSi.sessionLimiter = si.sessionLimiter
+		}
+		// Likewise for the connections the session already opened
+		if actualSi.connectionRate == nil || si.connectionRate == nil || actualSi.connectionRate.perMinute != connectionsPerMinute {
+			actualSi.connectionRate = si.connectionRate
+		}
 		actualSi.Unlock()
 	}
 
@@ -231,11 +431,37 @@ func (w *Worker) closeConnections(ctx context.Context, closeMap map[string]strin
 	w.logger.Trace("marking connections as closed", "session_and_connection_ids", fmt.Sprintf("%#v", closeMap))
 
 	closeData := make([]*pbs.CloseConnectionRequestData, 0, len(closeMap))
-	for connId := range closeMap {
-		closeData = append(closeData, &pbs.CloseConnectionRequestData{
+	for connId, sessionId := range closeMap {
+		data := &pbs.CloseConnectionRequestData{
 			ConnectionId: connId,
 			Reason:       session.UnknownReason.String(),
-		})
+		}
+		var cached bool
+		if siRaw, ok := w.sessionInfoMap.Load(sessionId); ok {
+			si := siRaw.(*sessionInfo)
+			si.Lock()
+			if ci, ok := si.connInfoMap[connId]; ok {
+				data.BytesUp = ci.bytesUp.Load()
+				data.BytesDown = ci.bytesDown.Load()
+				if ci.closeReason != "" {
+					data.Reason = ci.closeReason.String()
+				}
+				// Connections authorized from a cached session are only
+				// closed locally, since controllers don't know of them
+				if cached = ci.cached; cached {
+					ci.status = pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CLOSED
+					ci.closeTime = time.Now()
+				}
+			}
+			si.Unlock()
+		}
+		if !cached {
+			closeData = append(closeData, data)
+		}
+	}
+	if len(closeData) == 0 {
+		w.persistState()
+		return nil
 	}
 	closeInfo := &pbs.CloseConnectionRequest{
 		CloseRequestData: closeData,
@@ -270,6 +496,7 @@ func (w *Worker) closeConnections(ctx context.Context, closeMap map[string]strin
 		}
 		si.Unlock()
 	}
+	w.persistState()
 	w.logger.Trace("connections successfully marked closed", "connection_ids", closedIds)
 	return nil
 }
@@ -0,0 +1,185 @@
+package worker
+
+import (
+	"context"
+	"crypto/tls"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeSessionClient is a controller session client whose connection
+// authorizations fail with err if it is set.
+type fakeSessionClient struct {
+	pbs.SessionServiceClient
+	err    error
+	closed []string
+}
+
+func (c *fakeSessionClient) AuthorizeConnection(_ context.Context, req *pbs.AuthorizeConnectionRequest, _ ...grpc.CallOption) (*pbs.AuthorizeConnectionResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pbs.AuthorizeConnectionResponse{
+		ConnectionId:    "sc_fromcontrl",
+		Status:          pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_AUTHORIZED,
+		ConnectionsLeft: 4,
+	}, nil
+}
+
+func (c *fakeSessionClient) CloseConnection(_ context.Context, req *pbs.CloseConnectionRequest, _ ...grpc.CallOption) (*pbs.CloseConnectionResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	resp := &pbs.CloseConnectionResponse{}
+	for _, d := range req.GetCloseRequestData() {
+		c.closed = append(c.closed, d.GetConnectionId())
+		resp.CloseResponseData = append(resp.CloseResponseData, &pbs.CloseConnectionResponseData{
+			ConnectionId: d.GetConnectionId(),
+			Status:       pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CLOSED,
+		})
+	}
+	return resp, nil
+}
+
+// testSessionCacheWorker returns a worker using client as its controller
+// session client, caching session lookups for window.
+func testSessionCacheWorker(t *testing.T, client pbs.SessionServiceClient, window time.Duration) *Worker {
+	t.Helper()
+	w := &Worker{
+		conf: &Config{
+			RawConfig: &config.Config{
+				Worker: &config.Worker{SessionCacheWindowDuration: window},
+			},
+		},
+		logger:                hclog.NewNullLogger(),
+		controllerSessionConn: new(atomic.Value),
+		sessionInfoMap:        new(sync.Map),
+	}
+	w.controllerSessionConn.Store(client)
+	return w
+}
+
+// testCachedSession returns an active session looked up at lookupTime with
+// the connection limit.
+func testCachedSession(w *Worker, lookupTime time.Time, connectionLimit int32) *sessionInfo {
+	si := &sessionInfo{
+		id:         "s_1234567890",
+		sessionTls: &tls.Config{},
+		status:     pbs.SESSIONSTATUS_SESSIONSTATUS_ACTIVE,
+		lookupSessionResponse: &pbs.LookupSessionResponse{
+			Expiration:      timestamppb.New(time.Now().Add(time.Hour)),
+			ConnectionLimit: connectionLimit,
+		},
+		connInfoMap: make(map[string]*connInfo),
+		lookupTime:  lookupTime,
+	}
+	w.sessionInfoMap.Store(si.id, si)
+	return si
+}
+
+func TestWorker_AuthorizeConnectionCached(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "no controller")
+
+	t.Run("controller reachable", func(t *testing.T) {
+		w := testSessionCacheWorker(t, &fakeSessionClient{}, time.Minute)
+		si := testCachedSession(w, time.Now(), -1)
+		ci, connsLeft, err := w.authorizeConnectionCached(context.Background(), si)
+		require.NoError(t, err)
+		assert.Equal(t, "sc_fromcontrl", ci.id)
+		assert.False(t, ci.cached)
+		assert.Equal(t, int32(4), connsLeft)
+	})
+	t.Run("cache hit", func(t *testing.T) {
+		w := testSessionCacheWorker(t, &fakeSessionClient{err: unavailable}, time.Minute)
+		si := testCachedSession(w, time.Now(), -1)
+		ci, connsLeft, err := w.authorizeConnectionCached(context.Background(), si)
+		require.NoError(t, err)
+		assert.True(t, ci.cached)
+		assert.Equal(t, pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_AUTHORIZED, ci.status)
+		assert.Equal(t, int32(-1), connsLeft)
+		assert.Contains(t, si.connInfoMap, ci.id)
+	})
+	t.Run("cache hit within connection limit", func(t *testing.T) {
+		w := testSessionCacheWorker(t, &fakeSessionClient{err: unavailable}, time.Minute)
+		si := testCachedSession(w, time.Now(), 2)
+		_, connsLeft, err := w.authorizeConnectionCached(context.Background(), si)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), connsLeft)
+		_, connsLeft, err = w.authorizeConnectionCached(context.Background(), si)
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), connsLeft)
+		_, _, err = w.authorizeConnectionCached(context.Background(), si)
+		assert.True(t, isControllerUnavailable(err), err)
+		assert.Len(t, si.connInfoMap, 2)
+	})
+	t.Run("expired", func(t *testing.T) {
+		w := testSessionCacheWorker(t, &fakeSessionClient{err: unavailable}, time.Minute)
+		si := testCachedSession(w, time.Now().Add(-2*time.Minute), -1)
+		_, _, err := w.authorizeConnectionCached(context.Background(), si)
+		require.Error(t, err)
+		assert.True(t, isControllerUnavailable(err), err)
+		assert.Empty(t, si.connInfoMap)
+	})
+	t.Run("canceled", func(t *testing.T) {
+		w := testSessionCacheWorker(t, &fakeSessionClient{err: unavailable}, time.Minute)
+		si := testCachedSession(w, time.Now(), -1)
+		si.status = pbs.SESSIONSTATUS_SESSIONSTATUS_CANCELING
+		_, _, err := w.authorizeConnectionCached(context.Background(), si)
+		require.Error(t, err)
+		assert.Empty(t, si.connInfoMap)
+	})
+	t.Run("caching disabled", func(t *testing.T) {
+		w := testSessionCacheWorker(t, &fakeSessionClient{err: unavailable}, 0)
+		si := testCachedSession(w, time.Now(), -1)
+		_, _, err := w.authorizeConnectionCached(context.Background(), si)
+		require.Error(t, err)
+		assert.Empty(t, si.connInfoMap)
+	})
+	t.Run("controller failure", func(t *testing.T) {
+		// A controller refusing the connection isn't overridden by the cache
+		denied := status.Error(codes.PermissionDenied, "connection limit reached")
+		w := testSessionCacheWorker(t, &fakeSessionClient{err: denied}, time.Minute)
+		si := testCachedSession(w, time.Now(), -1)
+		_, _, err := w.authorizeConnectionCached(context.Background(), si)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "connection limit reached")
+		assert.Empty(t, si.connInfoMap)
+	})
+}
+
+func TestWorker_CloseCachedConnections(t *testing.T) {
+	client := &fakeSessionClient{}
+	w := testSessionCacheWorker(t, client, time.Minute)
+	si := testCachedSession(w, time.Now(), -1)
+	si.connInfoMap["sc_fromcontrl"] = &connInfo{id: "sc_fromcontrl", status: pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CONNECTED}
+	si.connInfoMap["sc_cachedconn"] = &connInfo{id: "sc_cachedconn", status: pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CONNECTED, cached: true}
+
+	// Only connections the controller knows of are closed on it
+	require.NoError(t, w.closeConnections(context.Background(), map[string]string{
+		"sc_fromcontrl": si.id,
+		"sc_cachedconn": si.id,
+	}))
+	assert.Equal(t, []string{"sc_fromcontrl"}, client.closed)
+	for _, ci := range si.connInfoMap {
+		assert.Equal(t, pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CLOSED, ci.status, ci.id)
+		assert.False(t, ci.closeTime.IsZero(), ci.id)
+	}
+
+	// Cached connections are closed even while no controller is reachable
+	client.err = status.Error(codes.Unavailable, "no controller")
+	si.connInfoMap["sc_cachedcon2"] = &connInfo{id: "sc_cachedcon2", cached: true}
+	require.NoError(t, w.closeConnections(context.Background(), map[string]string{"sc_cachedcon2": si.id}))
+	assert.False(t, si.connInfoMap["sc_cachedcon2"].closeTime.IsZero())
+}
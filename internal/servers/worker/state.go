@@ -0,0 +1,179 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/session"
+)
+
+// persistedConnection is a connection as stored in the worker state file.
+type persistedConnection struct {
+	SessionId string `json:"session_id"`
+	BytesUp   uint64 `json:"bytes_up"`
+	BytesDown uint64 `json:"bytes_down"`
+}
+
+// persistedState is the content of the worker state file.
+type persistedState struct {
+	// Connections are the connections not yet closed on the controller, keyed
+	// by connection id.
+	Connections map[string]*persistedConnection `json:"connections"`
+}
+
+// stateStore persists the connections of the worker to a file, so
+// connections which were open when the worker stopped can be closed on the
+// controller once it restarts. Without this their sessions would stay active
+// until they expire.
+type stateStore struct {
+	sync.Mutex
+	path string
+
+	// orphaned are the connections read from the state file when the worker
+	// started, which are closed on the controller as soon as one is reachable.
+	orphaned map[string]*persistedConnection
+}
+
+// newStateStore returns a store persisting to path, with the connections of
+// an existing state file as its orphaned connections.
+func newStateStore(path string) (*stateStore, error) {
+	s := &stateStore{
+		path:     path,
+		orphaned: make(map[string]*persistedConnection),
+	}
+	b, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return s, nil
+	case err != nil:
+		return nil, fmt.Errorf("error reading worker state file: %w", err)
+	}
+	var st persistedState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, fmt.Errorf("error decoding worker state file %s: %w", path, err)
+	}
+	for id, c := range st.Connections {
+		s.orphaned[id] = c
+	}
+	return s, nil
+}
+
+// write replaces the state file with st. The file is written next to it and
+// renamed into place, so a crash while writing never leaves a partial file.
+// It must be called with the lock held.
+func (s *stateStore) write(st *persistedState) error {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("error encoding worker state: %w", err)
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("error creating worker state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing worker state file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error syncing worker state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error closing worker state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("error replacing worker state file: %w", err)
+	}
+	return nil
+}
+
+// persistState writes the connections of the worker which are not closed on
+// the controller, including orphaned connections, to the state file.
+// Connections authorized from a cached session are left out since the
+// controller doesn't know of them. It is a no-op if no state file is
+// configured.
+func (w *Worker) persistState() {
+	if w.stateStore == nil {
+		return
+	}
+	w.stateStore.Lock()
+	defer w.stateStore.Unlock()
+
+	st := &persistedState{
+		Connections: make(map[string]*persistedConnection, len(w.stateStore.orphaned)),
+	}
+	for id, c := range w.stateStore.orphaned {
+		st.Connections[id] = c
+	}
+	w.sessionInfoMap.Range(func(key, value interface{}) bool {
+		si := value.(*sessionInfo)
+		si.RLock()
+		for id, ci := range si.connInfoMap {
+			if !ci.closeTime.IsZero() || ci.cached {
+				continue
+			}
+			st.Connections[id] = &persistedConnection{
+				SessionId: si.id,
+				BytesUp:   ci.bytesUp.Load(),
+				BytesDown: ci.bytesDown.Load(),
+			}
+		}
+		si.RUnlock()
+		return true
+	})
+
+	if err := w.stateStore.write(st); err != nil {
+		w.logger.Error("error persisting worker state", "error", err)
+	}
+}
+
+// closeOrphanedConnections closes the connections which were open when the
+// worker last stopped on the controller, with the bytes counted until their
+// state was last persisted. Connections the controller could not be told
+// about are retried on the next call.
+func (w *Worker) closeOrphanedConnections(ctx context.Context) {
+	if w.stateStore == nil {
+		return
+	}
+	w.stateStore.Lock()
+	closeData := make([]*pbs.CloseConnectionRequestData, 0, len(w.stateStore.orphaned))
+	for id, c := range w.stateStore.orphaned {
+		closeData = append(closeData, &pbs.CloseConnectionRequestData{
+			ConnectionId: id,
+			BytesUp:      c.BytesUp,
+			BytesDown:    c.BytesDown,
+			Reason:       session.ConnectionSystemError.String(),
+		})
+	}
+	w.stateStore.Unlock()
+	if len(closeData) == 0 {
+		return
+	}
+
+	resp, err := w.closeConnection(ctx, &pbs.CloseConnectionRequest{
+		CloseRequestData: closeData,
+	})
+	if err != nil {
+		w.logger.Error("error closing connections orphaned by a worker restart", "error", err)
+		return
+	}
+
+	w.stateStore.Lock()
+	for _, v := range resp.GetCloseResponseData() {
+		c, ok := w.stateStore.orphaned[v.GetConnectionId()]
+		if !ok {
+			continue
+		}
+		w.logger.Info("closed connection orphaned by a worker restart", "session_id", c.SessionId, "connection_id", v.GetConnectionId())
+		delete(w.stateStore.orphaned, v.GetConnectionId())
+	}
+	w.stateStore.Unlock()
+	w.persistState()
+}
@@ -0,0 +1,130 @@
+package worker
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testStateWorker returns a worker persisting its state to path, using
+// client as its controller session client.
+func testStateWorker(t *testing.T, client pbs.SessionServiceClient, path string) *Worker {
+	t.Helper()
+	w := testSessionCacheWorker(t, client, time.Minute)
+	var err error
+	w.stateStore, err = newStateStore(path)
+	require.NoError(t, err)
+	return w
+}
+
+func TestNewStateStore(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("missing file", func(t *testing.T) {
+		s, err := newStateStore(filepath.Join(dir, "missing.json"))
+		require.NoError(t, err)
+		assert.Empty(t, s.orphaned)
+	})
+	t.Run("corrupt file", func(t *testing.T) {
+		path := filepath.Join(dir, "corrupt.json")
+		require.NoError(t, ioutil.WriteFile(path, []byte(`{"connections":`), 0600))
+		_, err := newStateStore(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error decoding worker state file")
+	})
+	t.Run("unreadable file", func(t *testing.T) {
+		_, err := newStateStore(dir)
+		assert.Error(t, err)
+	})
+}
+
+func TestWorker_PersistState(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	w := testStateWorker(t, &fakeSessionClient{}, path)
+	w.stateStore.orphaned["sc_orphaned1"] = &persistedConnection{SessionId: "s_previous", BytesUp: 1, BytesDown: 2}
+
+	si := testCachedSession(w, time.Now(), -1)
+	open := &connInfo{id: "sc_open123456", status: pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CONNECTED}
+	open.bytesUp.Store(10)
+	open.bytesDown.Store(20)
+	si.connInfoMap[open.id] = open
+	si.connInfoMap["sc_closed12345"] = &connInfo{id: "sc_closed12345", closeTime: time.Now()}
+	si.connInfoMap["sc_cached12345"] = &connInfo{id: "sc_cached12345", cached: true}
+	w.persistState()
+
+	// Only connections the controller still has to close are read back, and
+	// the file is replaced without leaving temporary files behind
+	s, err := newStateStore(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*persistedConnection{
+		"sc_orphaned1":  {SessionId: "s_previous", BytesUp: 1, BytesDown: 2},
+		"sc_open123456": {SessionId: si.id, BytesUp: 10, BytesDown: 20},
+	}, s.orphaned)
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "state.json", files[0].Name())
+
+	// Once closed, a connection is dropped from the file
+	open.closeTime = time.Now()
+	delete(w.stateStore.orphaned, "sc_orphaned1")
+	w.persistState()
+	s, err = newStateStore(path)
+	require.NoError(t, err)
+	assert.Empty(t, s.orphaned)
+}
+
+func TestWorker_CloseOrphanedConnections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	// A worker stops with open connections
+	before := testStateWorker(t, &fakeSessionClient{}, path)
+	si := testCachedSession(before, time.Now(), -1)
+	for _, id := range []string{"sc_open123456", "sc_open234567"} {
+		ci := &connInfo{id: id, status: pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CONNECTED}
+		ci.bytesUp.Store(5)
+		si.connInfoMap[id] = ci
+	}
+	before.persistState()
+
+	// After the restart they are retried until a controller is reachable
+	client := &fakeSessionClient{err: status.Error(codes.Unavailable, "no controller")}
+	w := testStateWorker(t, client, path)
+	require.Len(t, w.stateStore.orphaned, 2)
+	w.closeOrphanedConnections(context.Background())
+	assert.Empty(t, client.closed)
+	assert.Len(t, w.stateStore.orphaned, 2)
+
+	client.err = nil
+	w.closeOrphanedConnections(context.Background())
+	sort.Strings(client.closed)
+	assert.Equal(t, []string{"sc_open123456", "sc_open234567"}, client.closed)
+	assert.Empty(t, w.stateStore.orphaned)
+	s, err := newStateStore(path)
+	require.NoError(t, err)
+	assert.Empty(t, s.orphaned)
+
+	// Nothing is sent once they are closed
+	client.closed = nil
+	w.closeOrphanedConnections(context.Background())
+	assert.Empty(t, client.closed)
+}
+
+func TestWorker_StateWithoutStore(t *testing.T) {
+	client := &fakeSessionClient{}
+	w := testSessionCacheWorker(t, client, time.Minute)
+	testCachedSession(w, time.Now(), -1).connInfoMap["sc_open123456"] = &connInfo{id: "sc_open123456"}
+	w.persistState()
+	w.closeOrphanedConnections(context.Background())
+	assert.Empty(t, client.closed)
+}
@@ -2,12 +2,18 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"math/rand"
+	"strconv"
 	"time"
 
+	"github.com/hashicorp/boundary/globals"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/types/resource"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/resolver"
 )
 
@@ -21,6 +27,37 @@ type LastStatusInformation struct {
 	StatusTime time.Time
 }
 
+// statusMetadata returns the metadata sent with status requests, since the
+// request has no fields for the throughput, the tags and the location of the
+// worker, the round trip time of its previous status report, or the results
+// of the connection checks it performed. The round trip time is only sent if
+// the worker reports it and it was measured.
+func (w *Worker) statusMetadata(bytesPerSecond uint64, controllerRtt time.Duration, checkResults []*servers.ConnectionCheck) ([]string, error) {
+	kv := []string{globals.WorkerBytesPerSecondMetadataKey, strconv.FormatUint(bytesPerSecond, 10)}
+	for k, v := range w.conf.RawConfig.Worker.Tags {
+		kv = append(kv, globals.WorkerTagsMetadataKey, k+"="+v)
+	}
+	if region := w.conf.RawConfig.Worker.Region; region != "" {
+		kv = append(kv, globals.WorkerRegionMetadataKey, region)
+	}
+	if zone := w.conf.RawConfig.Worker.Zone; zone != "" {
+		kv = append(kv, globals.WorkerZoneMetadataKey, zone)
+	}
+	if w.conf.RawConfig.Worker.ReportControllerRtt && controllerRtt > 0 {
+		// Round up, so a fast controller isn't reported as unmeasured
+		ms := (controllerRtt + time.Millisecond - 1) / time.Millisecond
+		kv = append(kv, globals.WorkerControllerRttMetadataKey, strconv.FormatInt(int64(ms), 10))
+	}
+	if len(checkResults) > 0 {
+		marshaled, err := json.Marshal(checkResults)
+		if err != nil {
+			return kv, fmt.Errorf("error marshaling connection check results: %w", err)
+		}
+		kv = append(kv, globals.ConnectionCheckResultsMetadataKey, string(marshaled))
+	}
+	return kv, nil
+}
+
 func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 	go func() {
 		r := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -37,6 +74,11 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 			return statusInterval + time.Duration(f*float64(time.Second))
 		}
 
+		// The throughput reported is that since the previous report
+		lastBytes, lastReport := w.bytesProxied.Load(), time.Now()
+		// The round trip time reported is that of the previous report
+		var lastRtt time.Duration
+
 		timer := time.NewTimer(0)
 		for {
 			select {
@@ -56,6 +98,9 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 					status := si.status
 					connections := make([]*pbs.Connection, 0, len(si.connInfoMap))
 					for k, v := range si.connInfoMap {
+						if v.cached {
+							continue
+						}
 						connections = append(connections, &pbs.Connection{
 							ConnectionId: k,
 							Status:       v.status,
@@ -77,8 +122,24 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 					})
 					return true
 				})
+				var bytesPerSecond uint64
+				bytes, now := w.bytesProxied.Load(), time.Now()
+				if elapsed := now.Sub(lastReport).Seconds(); elapsed > 0 {
+					bytesPerSecond = uint64(float64(bytes-lastBytes) / elapsed)
+				}
+				lastBytes, lastReport = bytes, now
+
+				checkResults := w.connectionCheckResults.take()
+				kv, err := w.statusMetadata(bytesPerSecond, lastRtt, checkResults)
+				if err != nil {
+					w.logger.Error("error building status metadata", "error", err)
+				}
+
 				client := w.controllerStatusConn.Load().(pbs.ServerCoordinationServiceClient)
-				result, err := client.Status(cancelCtx, &pbs.StatusRequest{
+				statusCtx := metadata.AppendToOutgoingContext(cancelCtx, kv...)
+				var header metadata.MD
+				statusStart := time.Now()
+				result, err := client.Status(statusCtx, &pbs.StatusRequest{
 					Jobs: activeJobs,
 					Worker: &servers.Server{
 						PrivateId:   w.conf.RawConfig.Worker.Name,
@@ -87,10 +148,15 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 						Description: w.conf.RawConfig.Worker.Description,
 						Address:     w.conf.RawConfig.Worker.PublicAddr,
 					},
-				})
+				}, grpc.Header(&header))
 				if err != nil {
 					w.logger.Error("error making status request to controller", "error", err)
+					// Send the results of connection checks with the next
+					// status instead
+					w.connectionCheckResults.add(checkResults...)
 				} else {
+					lastRtt = time.Since(statusStart)
+					w.startConnectionChecks(cancelCtx, header)
 					w.logger.Trace("successfully sent status to controller")
 					addrs := make([]resolver.Address, 0, len(result.Controllers))
 					strAddrs := make([]string, 0, len(result.Controllers))
@@ -103,7 +169,7 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 					case 0:
 						w.logger.Warn("got no controller addresses from controller; possibly prior to first status save, not persisting")
 					default:
-						w.Resolver().UpdateState(resolver.State{Addresses: addrs})
+						w.updateControllerAddrs(addrs)
 					}
 					w.lastStatusSuccess.Store(&LastStatusInformation{StatusResponse: result, StatusTime: time.Now()})
 
@@ -122,6 +188,13 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 								si := siRaw.(*sessionInfo)
 								si.Lock()
 								si.status = sessInfo.GetStatus()
+								switch si.status {
+								case pbs.SESSIONSTATUS_SESSIONSTATUS_CANCELING,
+									pbs.SESSIONSTATUS_SESSIONSTATUS_TERMINATED:
+									// Don't accept new connections from the
+									// cached lookup of a canceled session
+									si.lookupTime = time.Time{}
+								}
 								si.Unlock()
 							}
 						}
@@ -176,6 +249,11 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 					w.sessionInfoMap.Delete(v)
 				}
 
+				// Close connections left open by a previous run of the worker
+				// and persist the state of the current connections
+				w.closeOrphanedConnections(cancelCtx)
+				w.persistState()
+
 				timer.Reset(getRandomInterval())
 			}
 		}
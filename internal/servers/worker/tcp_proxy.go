@@ -5,14 +5,53 @@ import (
 	"io"
 	"net"
 	"net/url"
+	"strconv"
 	"sync"
+	"time"
 
+	ua "go.uber.org/atomic"
 	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wspb"
 
+	"github.com/hashicorp/boundary/globals"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/libs/latency"
+	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/boundary/internal/session"
 )
 
-func (w *Worker) handleTcpProxyV1(connCtx context.Context, clientAddr *net.TCPAddr, conn *websocket.Conn, si *sessionInfo, connectionId, endpoint string) {
+// proxiedConn is a connection whose writes go through w, so they are counted
+// and limited.
+type proxiedConn struct {
+	net.Conn
+	w io.Writer
+}
+
+func (c proxiedConn) Write(p []byte) (int, error) {
+	return c.w.Write(p)
+}
+
+// connectedEndpoint is a connection from a client which is connected to the
+// endpoint of its session, ready to be proxied.
+type connectedEndpoint struct {
+	sessionId string
+	ci        *connInfo
+	// client and endpoint are the connections from the client and to the
+	// endpoint; their writes count toward the bandwidth limits of the
+	// connection and of the session
+	client   proxiedConn
+	endpoint proxiedConn
+}
+
+func (e *connectedEndpoint) close() {
+	e.client.Close()
+	e.endpoint.Close()
+}
+
+// connectEndpoint dials the endpoint of the session for the client connection
+// and marks the connection connected. On failure it closes conn and returns
+// nil; otherwise the returned connection must be closed once proxied.
+func (w *Worker) connectEndpoint(connCtx context.Context, clientAddr *net.TCPAddr, conn *websocket.Conn, si *sessionInfo, connectionId, endpoint string) *connectedEndpoint {
 	si.RLock()
 	sessionId := si.lookupSessionResponse.GetAuthorization().GetSessionId()
 	si.RUnlock()
@@ -21,23 +60,37 @@ func (w *Worker) handleTcpProxyV1(connCtx context.Context, clientAddr *net.TCPAd
 	if err != nil {
 		w.logger.Error("error parsing endpoint information", "error", err, "session_id", sessionId, "endpoint", endpoint)
 		conn.Close(websocket.StatusInternalError, "cannot parse endpoint url")
-		return
+		return nil
 	}
 	if sessionUrl.Scheme != "tcp" {
 		w.logger.Error("invalid scheme for tcp proxy", "error", err, "session_id", sessionId, "endpoint", endpoint)
 		conn.Close(websocket.StatusInternalError, "invalid scheme for type")
-		return
+		return nil
 	}
-	remoteConn, err := net.Dial("tcp", sessionUrl.Host)
+	budget := latency.FromContext(connCtx)
+	endDial := budget.Start("endpoint_dial")
+	// Prefer reaching dual-stack targets over the family the client used
+	remoteConn, err := w.egressDialer.DialPreferring(connCtx, sessionUrl.Host, clientAddr.IP.To4() == nil)
+	endDial()
 	if err != nil {
 		w.logger.Error("error dialing endpoint", "error", err, "endpoint", endpoint)
 		conn.Close(websocket.StatusInternalError, "endpoint dialing failed")
-		return
+		return nil
 	}
-	// Assert this for better Go 1.11 splice support
-	tcpRemoteConn := remoteConn.(*net.TCPConn)
 
-	endpointAddr := tcpRemoteConn.RemoteAddr().(*net.TCPAddr)
+	endpointAddr := w.egressDialer.endpointAddr(connCtx, remoteConn, sessionUrl.Host)
+
+	si.RLock()
+	userId := si.lookupSessionResponse.GetUserId()
+	peer := si.peer
+	cached := si.connInfoMap[connectionId].cached
+	si.RUnlock()
+	if err := sendPeerIdentity(remoteConn, clientAddr, endpointAddr, sessionId, userId, peer); err != nil {
+		w.logger.Error("error sending peer identity to endpoint", "error", err, "session_id", sessionId, "endpoint", endpoint)
+		conn.Close(websocket.StatusInternalError, "failed to send peer identity to endpoint")
+		remoteConn.Close()
+		return nil
+	}
 	connectionInfo := &pbs.ConnectConnectionRequest{
 		ConnectionId:       connectionId,
 		ClientTcpAddress:   clientAddr.IP.String(),
@@ -47,31 +100,228 @@ func (w *Worker) handleTcpProxyV1(connCtx context.Context, clientAddr *net.TCPAd
 		Type:               "tcp",
 	}
 
-	connStatus, err := w.connectConnection(connCtx, connectionInfo)
-	if err != nil {
-		w.logger.Error("error marking connection as connected", "error", err)
-		conn.Close(websocket.StatusInternalError, "failed to mark connection as connected")
-		return
+	connStatus := pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CONNECTED
+	if !cached {
+		if connStatus, err = w.connectConnection(connCtx, connectionInfo); err != nil {
+			w.logger.Error("error marking connection as connected", "error", err)
+			conn.Close(websocket.StatusInternalError, "failed to mark connection as connected")
+			remoteConn.Close()
+			return nil
+		}
 	}
+	budget.Finish()
+	w.logger.Debug("connection established", append([]interface{}{"session_id", sessionId, "connection_id", connectionId}, budget.Attributes()...)...)
+
 	si.Lock()
-	si.connInfoMap[connectionId].status = connStatus
+	ci := si.connInfoMap[connectionId]
+	ci.status = connStatus
+	ci.endpointAddr = endpointAddr.String()
+	ci.peerIdentityMode = peer.mode
+	limiters := []*bandwidthLimiter{newBandwidthLimiter(si.connectionBandwidth), si.sessionLimiter}
 	si.Unlock()
 
-	// Get a wrapped net.Conn so we can use io.Copy
-	netConn := websocket.NetConn(connCtx, conn, websocket.MessageBinary)
+	var netConn net.Conn
+	if conn.Subprotocol() == globals.TcpProxyResumableV1 {
+		// The endpoint connection outlives the websocket for the grace
+		// period, so the client can resume the connection over another
+		grace := w.resumeGracePeriod()
+		hello := &proxy.ResumeHello{
+			ConnectionId:       connectionId,
+			GracePeriodSeconds: uint32(grace / time.Second),
+		}
+		if err := proxy.WriteResumeHello(connCtx, conn, hello); err != nil {
+			w.logger.Error("error sending resume hello to client", "error", err)
+			conn.Close(websocket.StatusProtocolError, "unable to send resume hello")
+			remoteConn.Close()
+			return nil
+		}
+		rc := proxy.NewResumableConn(connCtx, conn, w.heartbeatInterval(), grace, func(err error) {
+			w.logger.Info("waiting for client to resume connection", "session_id", sessionId, "connection_id", connectionId, "grace_period", grace, "error", err)
+			si.Lock()
+			ci.closeReason = session.ConnectionNetworkError
+			si.Unlock()
+		})
+		si.Lock()
+		ci.resumable = rc
+		si.Unlock()
+		netConn = rc
+	} else {
+		// Get a wrapped net.Conn so we can use io.Copy
+		netConn = websocket.NetConn(connCtx, conn, websocket.MessageBinary)
+
+		// Close the connection if the client stops answering heartbeats, so
+		// half-open connections don't stay connected until the session
+		// expires
+		go func() {
+			if err := proxy.Heartbeat(connCtx, conn, w.heartbeatInterval()); err != nil {
+				w.logger.Info("closing connection with unresponsive client", "session_id", sessionId, "connection_id", connectionId, "error", err)
+				si.Lock()
+				ci.closeReason = session.ConnectionNetworkError
+				si.Unlock()
+				ci.connCancel()
+			}
+		}()
+	}
+
+	// Both directions count toward the bandwidth limits of the connection and
+	// of the session
+	return &connectedEndpoint{
+		sessionId: sessionId,
+		ci:        ci,
+		client: proxiedConn{
+			Conn: netConn,
+			w:    countingWriter{w: limitedWriter{ctx: connCtx, w: netConn, limiters: limiters}, n: &ci.bytesDown, total: &w.bytesProxied},
+		},
+		endpoint: proxiedConn{
+			Conn: remoteConn,
+			w:    countingWriter{w: limitedWriter{ctx: connCtx, w: remoteConn, limiters: limiters}, n: &ci.bytesUp, total: &w.bytesProxied},
+		},
+	}
+}
+
+func (w *Worker) handleTcpProxyV1(connCtx context.Context, clientAddr *net.TCPAddr, conn *websocket.Conn, si *sessionInfo, connectionId, endpoint string) {
+	e := w.connectEndpoint(connCtx, clientAddr, conn, si, connectionId, endpoint)
+	if e == nil {
+		return
+	}
+	toEndpointErr, toClientErr := e.proxy()
+	w.logger.Debug("copy from client to endpoint done", "error", toEndpointErr)
+	w.logger.Debug("copy from endpoint to client done", "error", toClientErr)
+}
+
+// proxy copies between the client and the endpoint until both directions are
+// done, and returns the errors of the copies to the endpoint and to the
+// client. A direction which reads EOF only closes the writing side of its
+// destination, so a peer which half-closed its connection still receives the
+// rest of the other direction. Both connections are closed once both
+// directions are done, or as soon as either fails.
+func (e *connectedEndpoint) proxy() (toEndpointErr, toClientErr error) {
+	copyHalf := func(dst proxiedConn, src net.Conn) error {
+		_, err := io.Copy(dst, src)
+		if err == nil {
+			err = closeWrite(dst.Conn)
+		}
+		if err != nil {
+			e.close()
+		}
+		return err
+	}
 
 	connWg := new(sync.WaitGroup)
 	connWg.Add(2)
 	go func() {
 		defer connWg.Done()
-		_, err := io.Copy(netConn, tcpRemoteConn)
-		w.logger.Debug("copy from client to endpoint done", "error", err)
+		toEndpointErr = copyHalf(e.endpoint, e.client.Conn)
 	}()
 	go func() {
 		defer connWg.Done()
-		_, err := io.Copy(tcpRemoteConn, netConn)
-		w.logger.Debug("copy from endpoint to client done", "error", err)
+		toClientErr = copyHalf(e.client, e.endpoint.Conn)
 	}()
 	connWg.Wait()
+	e.close()
+	return toEndpointErr, toClientErr
+}
+
+// closeWriter is implemented by connections which can be half-closed, like
+// *net.TCPConn.
+type closeWriter interface {
+	CloseWrite() error
+}
+
+// closeWrite shuts down the writing side of conn, so its peer reads EOF while
+// conn can still be read. Connections which can't be half-closed, like
+// websockets, are closed.
+func closeWrite(conn net.Conn) error {
+	if cw, ok := conn.(closeWriter); ok {
+		return cw.CloseWrite()
+	}
+	return conn.Close()
+}
+
+// resumeConnection resumes the resumable connection connectionId of the
+// session over conn, for a client which received the number of bytes in
+// peerReceived, and waits until conn is lost or replaced. The connection keeps
+// being proxied by the request which established it.
+func (w *Worker) resumeConnection(connCtx context.Context, conn *websocket.Conn, si *sessionInfo, connectionId, peerReceived string, handshakeResult *proxy.HandshakeResult) {
+	si.RLock()
+	sessionId := si.id
+	ci := si.connInfoMap[connectionId]
+	var rc *proxy.ResumableConn
+	if ci != nil {
+		rc = ci.resumable
+	}
+	si.RUnlock()
+
+	received, err := strconv.ParseUint(peerReceived, 10, 64)
+	if err != nil || rc == nil || conn.Subprotocol() != globals.TcpProxyResumableV1 {
+		w.logger.Error("unable to resume connection", "session_id", sessionId, "connection_id", connectionId, "error", err)
+		conn.Close(websocket.StatusPolicyViolation, "connection cannot be resumed")
+		return
+	}
+	if err := wspb.Write(connCtx, conn, handshakeResult); err != nil {
+		w.logger.Error("error sending handshake result to client", "error", err)
+		conn.Close(websocket.StatusProtocolError, "unable to send handshake result")
+		return
+	}
+	done, err := rc.Resume(conn, received, func(received uint64) error {
+		return proxy.WriteResumeHello(connCtx, conn, &proxy.ResumeHello{
+			ConnectionId:       connectionId,
+			Received:           received,
+			GracePeriodSeconds: uint32(w.resumeGracePeriod() / time.Second),
+		})
+	})
+	if err != nil {
+		w.logger.Error("unable to resume connection", "session_id", sessionId, "connection_id", connectionId, "error", err)
+		conn.Close(websocket.StatusPolicyViolation, "connection cannot be resumed")
+		return
+	}
+	si.Lock()
+	ci.closeReason = ""
+	si.Unlock()
+	w.logger.Info("connection resumed", "session_id", sessionId, "connection_id", connectionId)
+	<-done
+}
 
+// resumeGracePeriod returns how long the worker waits for clients to resume
+// connections they lost, or 0 if resuming connections is disabled.
+func (w *Worker) resumeGracePeriod() time.Duration {
+	switch d := w.conf.RawConfig.Worker.ConnectionResumeGracePeriodDuration; {
+	case d < 0:
+		return 0
+	case d == 0:
+		return proxy.DefaultResumeGracePeriod
+	default:
+		return d
+	}
+}
+
+// heartbeatInterval returns how often the worker pings clients over proxied
+// connections, or 0 if heartbeats are disabled.
+func (w *Worker) heartbeatInterval() time.Duration {
+	switch d := w.conf.RawConfig.Worker.HeartbeatIntervalDuration; {
+	case d < 0:
+		return 0
+	case d == 0:
+		return proxy.DefaultHeartbeatInterval
+	default:
+		return d
+	}
+}
+
+// countingWriter adds the number of bytes written to w to n, so the bytes of
+// a connection can be persisted and reported while it is open. They are also
+// added to total if it is set.
+type countingWriter struct {
+	w     io.Writer
+	n     *ua.Uint64
+	total *ua.Uint64
+}
+
+func (c countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(uint64(n))
+	if c.total != nil {
+		c.total.Add(uint64(n))
+	}
+	return n, err
 }
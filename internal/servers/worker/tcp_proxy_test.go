@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tcpPair returns both ends of a TCP connection over the loopback interface.
+func tcpPair(t *testing.T) (*net.TCPConn, *net.TCPConn) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, _ := l.Accept()
+		accepted <- conn
+	}()
+	dialed, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	conn := <-accepted
+	require.NotNil(t, conn)
+	t.Cleanup(func() {
+		dialed.Close()
+		conn.Close()
+	})
+	return dialed.(*net.TCPConn), conn.(*net.TCPConn)
+}
+
+// testConnectedEndpoint returns a connected endpoint proxying between the
+// returned client and endpoint peers.
+func testConnectedEndpoint(t *testing.T) (*connectedEndpoint, *net.TCPConn, *net.TCPConn) {
+	t.Helper()
+	client, workerClient := tcpPair(t)
+	workerEndpoint, endpoint := tcpPair(t)
+	e := &connectedEndpoint{
+		client:   proxiedConn{Conn: workerClient, w: workerClient},
+		endpoint: proxiedConn{Conn: workerEndpoint, w: workerEndpoint},
+	}
+	return e, client, endpoint
+}
+
+func TestConnectedEndpoint_Proxy(t *testing.T) {
+	t.Run("client-half-close", func(t *testing.T) {
+		e, client, endpoint := testConnectedEndpoint(t)
+		done := make(chan struct{})
+		var toEndpointErr, toClientErr error
+		go func() {
+			defer close(done)
+			toEndpointErr, toClientErr = e.proxy()
+		}()
+
+		// The endpoint answers only once it read the whole request
+		go func() {
+			req, err := io.ReadAll(endpoint)
+			if err != nil {
+				endpoint.Close()
+				return
+			}
+			endpoint.Write(append([]byte("response to "), req...))
+			endpoint.Close()
+		}()
+
+		_, err := client.Write([]byte("request"))
+		require.NoError(t, err)
+		require.NoError(t, client.CloseWrite())
+		resp, err := io.ReadAll(client)
+		require.NoError(t, err)
+		assert.Equal(t, "response to request", string(resp))
+
+		<-done
+		assert.NoError(t, toEndpointErr)
+		assert.NoError(t, toClientErr)
+	})
+	t.Run("endpoint-half-close", func(t *testing.T) {
+		e, client, endpoint := testConnectedEndpoint(t)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			e.proxy()
+		}()
+
+		// The endpoint greets the client and stops sending, but still reads
+		// what the client sends
+		_, err := endpoint.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, endpoint.CloseWrite())
+		greeting, err := io.ReadAll(client)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(greeting))
+
+		_, err = client.Write([]byte("bye"))
+		require.NoError(t, err)
+		require.NoError(t, client.CloseWrite())
+		got, err := io.ReadAll(endpoint)
+		require.NoError(t, err)
+		assert.Equal(t, "bye", string(got))
+
+		<-done
+	})
+	t.Run("error-closes-both", func(t *testing.T) {
+		e, client, endpoint := testConnectedEndpoint(t)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			e.proxy()
+		}()
+
+		// Resetting the client connection fails the copy to the endpoint,
+		// which closes the connection to the endpoint too
+		require.NoError(t, client.SetLinger(0))
+		require.NoError(t, client.Close())
+		_, err := io.ReadAll(endpoint)
+		assert.NoError(t, err)
+		<-done
+	})
+}
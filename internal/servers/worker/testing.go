@@ -2,8 +2,8 @@ package worker
 
 import (
 	"context"
-	"fmt"
 	"net"
+	"strconv"
 	"testing"
 
 	"github.com/hashicorp/boundary/internal/cmd/base"
@@ -68,7 +68,7 @@ func (tw *TestWorker) ProxyAddrs() []string {
 			if !ok {
 				tw.t.Fatal("could not parse address as a TCP addr")
 			}
-			addr := fmt.Sprintf("%s:%d", tcpAddr.IP.String(), tcpAddr.Port)
+			addr := net.JoinHostPort(tcpAddr.IP.String(), strconv.Itoa(tcpAddr.Port))
 			tw.addrs = append(tw.addrs, addr)
 		}
 	}
@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/armon/go-metrics"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+)
+
+// controllerConnDrainTimeout is how long a replaced controller connection is
+// kept open so the calls made on it before its replacement can complete.
+const controllerConnDrainTimeout = 30 * time.Second
+
+// workerAuthKmsHolder holds the wrapper the worker-auth KMS was rotated to,
+// since the wrappers of different KMS types can't be stored in the same
+// atomic.Value directly.
+type workerAuthKmsHolder struct {
+	wrapper wrapping.Wrapper
+}
+
+// workerAuthKms returns the KMS the worker authenticates to controllers and
+// verifies the authorization claims of session certificates with: the one it
+// was rotated to, if any, or the configured one.
+func (w *Worker) workerAuthKms() wrapping.Wrapper {
+	if h, ok := w.rotatedWorkerAuthKms.Load().(workerAuthKmsHolder); ok {
+		return h.wrapper
+	}
+	return w.conf.WorkerAuthKms
+}
+
+// updateControllerAddrs sets the addresses of the controllers the worker
+// connects to, keeping them for the connections made on rotation.
+func (w *Worker) updateControllerAddrs(addrs []resolver.Address) {
+	w.controllerAddrs.Store(addrs)
+	w.Resolver().UpdateState(resolver.State{Addresses: addrs})
+}
+
+// RotateTls replaces the TLS material of the worker without a restart. If
+// workerAuthKms is set, it replaces the worker-auth KMS the worker
+// authenticates to controllers with and verifies the session certificates of
+// the proxy listener with. The session certificates the proxy listener serves
+// are dropped, so they are looked up again from a controller and verified
+// with the new KMS on the next handshake of their session. The connection to
+// the controllers is then re-established, authenticating with a new
+// certificate.
+//
+// Handshakes already under way and connections already established keep
+// their TLS material: proxied connections are not interrupted, and calls made
+// on the previous controller connection are given controllerConnDrainTimeout
+// to complete before it is closed. New connections use the new material.
+func (w *Worker) RotateTls(workerAuthKms wrapping.Wrapper) (retErr error) {
+	start := time.Now()
+	defer func() {
+		if retErr != nil {
+			metrics.IncrCounter([]string{"worker", "tls", "rotation_failure"}, 1)
+			w.logger.Error("error rotating tls", "error", retErr)
+			return
+		}
+		metrics.IncrCounter([]string{"worker", "tls", "rotation"}, 1)
+		metrics.MeasureSince([]string{"worker", "tls", "rotation_time"}, start)
+		w.logger.Info("tls rotated")
+	}()
+
+	w.rotationLock.Lock()
+	defer w.rotationLock.Unlock()
+	if !w.started.Load() {
+		return errors.New("worker is not started")
+	}
+	if workerAuthKms != nil {
+		w.rotatedWorkerAuthKms.Store(workerAuthKmsHolder{wrapper: workerAuthKms})
+	}
+	w.resetSessionTls()
+
+	addrs, _ := w.controllerAddrs.Load().([]resolver.Address)
+	if len(addrs) == 0 {
+		return errors.New("no controller addresses known")
+	}
+	old, _ := w.controllerConn.Load().(*grpc.ClientConn)
+	// The new connection starts from the addresses last received from the
+	// controllers rather than those configured
+	w.Resolver().InitialState(resolver.State{Addresses: addrs})
+	if err := w.createClientConn(addrs[0].Addr); err != nil {
+		return fmt.Errorf("error reconnecting to controller: %w", err)
+	}
+	if old != nil {
+		go func() {
+			timer := time.NewTimer(controllerConnDrainTimeout)
+			defer timer.Stop()
+			select {
+			case <-w.baseContext.Done():
+			case <-timer.C:
+			}
+			if err := old.Close(); err != nil {
+				w.logger.Debug("error closing replaced controller connection", "error", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// resetSessionTls drops the session certificates of the proxy listener. The
+// TLS configurations of handshakes under way were already handed out and are
+// not affected.
+func (w *Worker) resetSessionTls() {
+	w.sessionInfoMap.Range(func(_, value interface{}) bool {
+		si := value.(*sessionInfo)
+		si.Lock()
+		si.sessionTls = nil
+		si.lookupTime = time.Time{}
+		si.Unlock()
+		return true
+	})
+}
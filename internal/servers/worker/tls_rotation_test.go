@@ -0,0 +1,183 @@
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/go-hclog"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/go-kms-wrapping/wrappers/aead"
+	"github.com/hashicorp/shared-secure-libs/configutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// testWorkerAuthKms returns an AEAD wrapper with a random key, named by
+// keyId.
+func testWorkerAuthKms(t *testing.T, keyId string) wrapping.Wrapper {
+	t.Helper()
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	w := aead.NewWrapper(nil)
+	_, err = w.SetConfig(map[string]string{"key_id": keyId})
+	require.NoError(t, err)
+	require.NoError(t, w.SetAESGCMKeyBytes(key))
+	return w
+}
+
+// fakeWorkerAuthController returns the address of a listener completing the
+// worker-auth TLS handshake of workers as a controller would, decrypting
+// their authentication info with the wrapper held by kms. The key id of the
+// wrapper is sent on the returned channel for every worker which
+// authenticated.
+func fakeWorkerAuthController(t *testing.T, kms *atomic.Value) (string, <-chan string) {
+	t.Helper()
+	authenticated := make(chan string, 100)
+	addr := testServe(t, func(conn net.Conn) {
+		wrapper := kms.Load().(wrapping.Wrapper)
+		tlsConn := tls.Server(conn, &tls.Config{
+			GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+				var enc, firstProto string
+				for _, p := range hello.SupportedProtos {
+					if strings.HasPrefix(p, "v1workerauth-") {
+						enc += strings.TrimPrefix(p, "v1workerauth-")[3:]
+						if firstProto == "" {
+							firstProto = p
+						}
+					}
+				}
+				marshaledEncInfo, err := base64.RawStdEncoding.DecodeString(enc)
+				if err != nil {
+					return nil, err
+				}
+				encInfo := new(wrapping.EncryptedBlobInfo)
+				if err := proto.Unmarshal(marshaledEncInfo, encInfo); err != nil {
+					return nil, err
+				}
+				marshaledInfo, err := wrapper.Decrypt(context.Background(), encInfo, nil)
+				if err != nil {
+					return nil, errors.New("worker not authenticated")
+				}
+				info := new(base.WorkerAuthInfo)
+				if err := json.Unmarshal(marshaledInfo, info); err != nil {
+					return nil, err
+				}
+				cert, err := tls.X509KeyPair(info.CertPEM, info.KeyPEM)
+				if err != nil {
+					return nil, err
+				}
+				return &tls.Config{
+					Certificates: []tls.Certificate{cert},
+					NextProtos:   []string{firstProto},
+					MinVersion:   tls.VersionTLS13,
+				}, nil
+			},
+		})
+		if err := tlsConn.Handshake(); err != nil {
+			return
+		}
+		authenticated <- wrapper.KeyID()
+		io.Copy(ioutil.Discard, tlsConn)
+	})
+	return addr, authenticated
+}
+
+// waitForAuthentication waits until a worker authenticates to the fake
+// controller with the key.
+func waitForAuthentication(t *testing.T, authenticated <-chan string, keyId string) {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case got := <-authenticated:
+			if got == keyId {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("worker did not authenticate with key %q", keyId)
+		}
+	}
+}
+
+func TestWorker_RotateTls(t *testing.T) {
+	oldKms, newKms := testWorkerAuthKms(t, "old"), testWorkerAuthKms(t, "new")
+	controllerKms := new(atomic.Value)
+	controllerKms.Store(oldKms)
+	controllerAddr, authenticated := fakeWorkerAuthController(t, controllerKms)
+
+	w, err := New(&Config{
+		Server: &base.Server{
+			Logger:        hclog.NewNullLogger(),
+			WorkerAuthKms: oldKms,
+		},
+		RawConfig: &config.Config{
+			SharedConfig: &configutil.SharedConfig{DisableMlock: true},
+			Worker: &config.Worker{
+				Name:        "w_rotation",
+				Controllers: []string{controllerAddr},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// Rotating requires a running worker
+	assert.Error(t, w.RotateTls(newKms))
+
+	require.NoError(t, w.Start())
+	t.Cleanup(func() { w.Shutdown(false) })
+	waitForAuthentication(t, authenticated, "old")
+
+	// A session whose certificate the proxy listener serves
+	si := &sessionInfo{
+		id:         "s_1234567890",
+		sessionTls: &tls.Config{},
+		status:     pbs.SESSIONSTATUS_SESSIONSTATUS_ACTIVE,
+		lookupSessionResponse: &pbs.LookupSessionResponse{
+			Expiration: timestamppb.New(time.Now().Add(time.Hour)),
+		},
+		connInfoMap: make(map[string]*connInfo),
+		lookupTime:  time.Now(),
+	}
+	w.sessionInfoMap.Store(si.id, si)
+	sessionTls := si.sessionTls
+
+	// The controllers move to the new key; the worker follows
+	controllerKms.Store(newKms)
+	old := w.controllerConn.Load().(*grpc.ClientConn)
+	require.NoError(t, w.RotateTls(newKms))
+	assert.Same(t, newKms, w.workerAuthKms())
+	assert.NotSame(t, old, w.controllerConn.Load().(*grpc.ClientConn))
+	waitForAuthentication(t, authenticated, "new")
+
+	// The proxy listener no longer serves the previous session certificate;
+	// handshakes which already got it keep it
+	assert.Nil(t, si.cachedTls(time.Hour))
+	assert.Nil(t, w.verifiedTls(si))
+	assert.NotNil(t, sessionTls)
+
+	// The previous connection drains and is closed on shutdown
+	assert.NotEqual(t, connectivity.Shutdown, old.GetState())
+	require.NoError(t, w.Shutdown(false))
+	require.Eventually(t, func() bool {
+		return old.GetState() == connectivity.Shutdown
+	}, 5*time.Second, 10*time.Millisecond)
+}
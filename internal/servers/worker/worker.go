@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/dnscache"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 	"github.com/hashicorp/vault/sdk/helper/mlock"
@@ -33,6 +34,35 @@ type Worker struct {
 
 	controllerSessionConn *atomic.Value
 	sessionInfoMap        *sync.Map
+
+	// controllerConn is the connection the controller clients use, and
+	// controllerAddrs the addresses of the controllers last known, for
+	// replacing it when the TLS material is rotated
+	controllerConn  *atomic.Value
+	controllerAddrs *atomic.Value
+
+	// rotatedWorkerAuthKms holds the worker-auth KMS the worker was rotated
+	// to, if any, and rotationLock serializes rotations
+	rotatedWorkerAuthKms *atomic.Value
+	rotationLock         *sync.Mutex
+
+	// stateStore is nil if no state file is configured
+	stateStore *stateStore
+
+	egressDialer *egressDialer
+
+	// connectionLog is nil if no connection log is configured
+	connectionLog *connectionLog
+
+	// bytesProxied counts the bytes proxied by all connections, for reporting
+	// the throughput of the worker
+	bytesProxied ua.Uint64
+
+	connectionCheckResults connectionCheckResults
+
+	// protocolHandlers are the custom protocol handlers which completed the
+	// handshake, by subprotocol
+	protocolHandlers map[string]*protocolHandler
 }
 
 func New(conf *Config) (*Worker, error) {
@@ -44,6 +74,10 @@ func New(conf *Config) (*Worker, error) {
 		controllerResolver:    new(atomic.Value),
 		controllerSessionConn: new(atomic.Value),
 		sessionInfoMap:        new(sync.Map),
+		controllerConn:        new(atomic.Value),
+		controllerAddrs:       new(atomic.Value),
+		rotatedWorkerAuthKms:  new(atomic.Value),
+		rotationLock:          new(sync.Mutex),
 	}
 
 	w.lastStatusSuccess.Store((*LastStatusInformation)(nil))
@@ -64,6 +98,29 @@ func New(conf *Config) (*Worker, error) {
 		}
 	}
 
+	var dnsResolver *dnscache.Resolver
+	if dc := conf.RawConfig.DnsCache; dc != nil && dc.Enabled {
+		dnsResolver = dnscache.NewResolver(dc.TtlDuration, dc.NegativeTtlDuration)
+	}
+	if w.egressDialer, err = newEgressDialer(conf.RawConfig.Worker.EgressProxy, conf.RawConfig.Worker.TcpKeepAliveDuration, dnsResolver); err != nil {
+		return nil, err
+	}
+
+	if conf.RawConfig.Worker.StateFile != "" {
+		if w.stateStore, err = newStateStore(conf.RawConfig.Worker.StateFile); err != nil {
+			return nil, err
+		}
+		if n := len(w.stateStore.orphaned); n > 0 {
+			w.logger.Info("found connections left open by a previous run; closing them once a controller is reachable", "count", n)
+		}
+	}
+
+	if conf.RawConfig.Worker.ConnectionLog != nil {
+		if w.connectionLog, err = newConnectionLog(conf.RawConfig.Worker.ConnectionLog); err != nil {
+			return nil, err
+		}
+	}
+
 	if !conf.RawConfig.DisableMlock {
 		// Ensure our memory usage is locked into physical RAM
 		if err := mlock.LockMemory(); err != nil {
@@ -95,6 +152,7 @@ func (w *Worker) Start() error {
 	controllerResolver := manual.NewBuilderWithScheme(scheme)
 	w.controllerResolver.Store(controllerResolver)
 
+	w.startProtocolHandlers(w.baseContext)
 	if err := w.startListeners(); err != nil {
 		return fmt.Errorf("error starting worker listeners: %w", err)
 	}
@@ -0,0 +1,64 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/outbox"
+)
+
+// WorkerAccessDeniedKind is the outbox message kind of the records of the
+// workers refused by the worker access allow list of a controller. The
+// payload is a WorkerAccessDenial.
+const WorkerAccessDeniedKind = "controller.worker_access_denied"
+
+// WorkerAccessDeniedSchemaVersion is the version of the schema of the
+// payloads of WorkerAccessDeniedKind messages.
+const WorkerAccessDeniedSchemaVersion = 1
+
+// WorkerAccessDenial records the attempts of a worker from an address to
+// authenticate or update its status refused by a controller between
+// FirstTime and LastTime, and the reason of the last one. Controllers record
+// refused workers in batches so a flood of attempts doesn't cause a flood of
+// writes.
+type WorkerAccessDenial struct {
+	SchemaVersion int       `json:"schema_version"`
+	Controller    string    `json:"controller"`
+	WorkerName    string    `json:"worker_name"`
+	RemoteAddr    string    `json:"remote_addr"`
+	Reason        string    `json:"reason"`
+	Count         uint64    `json:"count"`
+	FirstTime     time.Time `json:"first_time"`
+	LastTime      time.Time `json:"last_time"`
+}
+
+// RecordWorkerAccessDenials enqueues the denials in the outbox in one
+// transaction.
+func (r *Repository) RecordWorkerAccessDenials(ctx context.Context, denials []*WorkerAccessDenial, opt ...Option) error {
+	if len(denials) == 0 {
+		return errors.New("cannot record empty worker access denials")
+	}
+	_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			for _, d := range denials {
+				d.SchemaVersion = WorkerAccessDeniedSchemaVersion
+				payload, err := json.Marshal(d)
+				if err != nil {
+					return err
+				}
+				if err := outbox.Enqueue(ctx, w, WorkerAccessDeniedKind, payload); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error recording worker access denials: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package servers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	ua "go.uber.org/atomic"
+)
+
+const (
+	// WorkerSelectionLeastConnections orders workers by their number of
+	// active connections, then by their throughput. It is the default.
+	WorkerSelectionLeastConnections = "least-connections"
+	// WorkerSelectionRoundRobin rotates through workers in turn, regardless
+	// of their load.
+	WorkerSelectionRoundRobin = "round-robin"
+	// WorkerSelectionWeighted orders workers like least-connections, with
+	// the load of each worker divided by the weight of its tags.
+	WorkerSelectionWeighted = "weighted"
+	// WorkerSelectionLowestLatency orders workers by the round trip time of
+	// their status reports to controllers, then like least-connections.
+	// Workers which don't report it come last.
+	WorkerSelectionLowestLatency = "lowest-latency"
+
+	// DefaultWorkerAffinityWindow is how long after a session its worker is
+	// preferred for the next session of its user to its target, when worker
+	// affinity is enabled without a window.
+	DefaultWorkerAffinityWindow = time.Hour
+)
+
+// WorkerSelector orders the workers which can handle a session, so that the
+// worker clients connect to comes first. It is given the states of the
+// workers by name, which may lack workers which did not report recently.
+type WorkerSelector interface {
+	SelectWorkers(workers []*Server, states map[string]*WorkerState)
+}
+
+// NewWorkerSelector returns the selector for the named strategy, the default
+// being least-connections. tagWeights are the weights of workers with a tag,
+// given as "key=value", and are only used by the weighted strategy.
+func NewWorkerSelector(strategy string, tagWeights map[string]int) (WorkerSelector, error) {
+	switch strategy {
+	case "", WorkerSelectionLeastConnections:
+		return leastConnectionsSelector{}, nil
+	case WorkerSelectionRoundRobin:
+		return &roundRobinSelector{}, nil
+	case WorkerSelectionWeighted:
+		for tag, weight := range tagWeights {
+			if !strings.Contains(tag, "=") {
+				return nil, fmt.Errorf("worker tag weight %q is not of the form key=value", tag)
+			}
+			if weight <= 0 {
+				return nil, fmt.Errorf("weight of worker tag %q must be positive", tag)
+			}
+		}
+		return weightedSelector{tagWeights: tagWeights}, nil
+	case WorkerSelectionLowestLatency:
+		return lowestLatencySelector{}, nil
+	default:
+		return nil, fmt.Errorf("unknown worker selection strategy %q", strategy)
+	}
+}
+
+// PreferWorker moves the worker with the id to the front of workers, which
+// are otherwise kept in order, so that clients connect to it first. The worker
+// is only preferred if it has a state, meaning it reported its status
+// recently, so sessions don't stick to a worker which is unhealthy. It
+// returns whether the worker was moved to the front.
+func PreferWorker(workers []*Server, states map[string]*WorkerState, workerId string) bool {
+	if workerId == "" || states[workerId] == nil {
+		return false
+	}
+	for i, w := range workers {
+		if w.PrivateId != workerId {
+			continue
+		}
+		copy(workers[1:i+1], workers[:i])
+		workers[0] = w
+		return true
+	}
+	return false
+}
+
+type leastConnectionsSelector struct{}
+
+func (leastConnectionsSelector) SelectWorkers(workers []*Server, states map[string]*WorkerState) {
+	sortByLoad(workers, states, func(*WorkerState) float64 { return 1 })
+}
+
+type weightedSelector struct {
+	tagWeights map[string]int
+}
+
+// weight returns the sum of the weights of the tags of a worker, or 1 if none
+// of its tags has a weight.
+func (s weightedSelector) weight(state *WorkerState) float64 {
+	var weight int
+	for k, v := range state.Tags {
+		weight += s.tagWeights[k+"="+v]
+	}
+	if weight == 0 {
+		return 1
+	}
+	return float64(weight)
+}
+
+func (s weightedSelector) SelectWorkers(workers []*Server, states map[string]*WorkerState) {
+	sortByLoad(workers, states, s.weight)
+}
+
+// sortByLoad sorts workers by their active connections divided by their
+// weight, then by their throughput divided by their weight. Workers without a
+// state come last.
+func sortByLoad(workers []*Server, states map[string]*WorkerState, weight func(*WorkerState) float64) {
+	sort.SliceStable(workers, func(i, j int) bool {
+		si, sj := states[workers[i].PrivateId], states[workers[j].PrivateId]
+		switch {
+		case si == nil:
+			return false
+		case sj == nil:
+			return true
+		}
+		wi, wj := weight(si), weight(sj)
+		// One is added to connections so that weights also apply to idle
+		// workers
+		ci, cj := float64(si.ActiveConnections+1)/wi, float64(sj.ActiveConnections+1)/wj
+		if ci != cj {
+			return ci < cj
+		}
+		return float64(si.BytesPerSecond)/wi < float64(sj.BytesPerSecond)/wj
+	})
+}
+
+type lowestLatencySelector struct{}
+
+func (lowestLatencySelector) SelectWorkers(workers []*Server, states map[string]*WorkerState) {
+	sortByLoad(workers, states, func(*WorkerState) float64 { return 1 })
+	rtt := func(w *Server) uint32 {
+		if s := states[w.PrivateId]; s != nil {
+			return s.ControllerRttMs
+		}
+		return 0
+	}
+	// The sort is stable, so workers with the same round trip time stay
+	// ordered by load
+	sort.SliceStable(workers, func(i, j int) bool {
+		ri, rj := rtt(workers[i]), rtt(workers[j])
+		switch {
+		case ri == 0:
+			return false
+		case rj == 0:
+			return true
+		}
+		return ri < rj
+	})
+}
+
+type roundRobinSelector struct {
+	next ua.Uint64
+}
+
+func (s *roundRobinSelector) SelectWorkers(workers []*Server, _ map[string]*WorkerState) {
+	if len(workers) == 0 {
+		return
+	}
+	sort.Slice(workers, func(i, j int) bool {
+		return workers[i].PrivateId < workers[j].PrivateId
+	})
+	n := int((s.next.Inc() - 1) % uint64(len(workers)))
+	rotated := append(append(make([]*Server, 0, len(workers)), workers[n:]...), workers[:n]...)
+	copy(workers, rotated)
+}
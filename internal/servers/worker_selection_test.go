@@ -0,0 +1,144 @@
+package servers_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWorkers(names ...string) []*servers.Server {
+	ret := make([]*servers.Server, 0, len(names))
+	for _, n := range names {
+		ret = append(ret, &servers.Server{PrivateId: n, Name: n})
+	}
+	return ret
+}
+
+func workerNames(workers []*servers.Server) []string {
+	ret := make([]string, 0, len(workers))
+	for _, w := range workers {
+		ret = append(ret, w.PrivateId)
+	}
+	return ret
+}
+
+func TestNewWorkerSelector(t *testing.T) {
+	tests := []struct {
+		name       string
+		strategy   string
+		tagWeights map[string]int
+		wantErr    bool
+	}{
+		{name: "default"},
+		{name: "least-connections", strategy: servers.WorkerSelectionLeastConnections},
+		{name: "round-robin", strategy: servers.WorkerSelectionRoundRobin},
+		{name: "weighted", strategy: servers.WorkerSelectionWeighted, tagWeights: map[string]int{"region=east": 2}},
+		{name: "lowest-latency", strategy: servers.WorkerSelectionLowestLatency},
+		{name: "unknown", strategy: "random", wantErr: true},
+		{name: "weight-not-key-value", strategy: servers.WorkerSelectionWeighted, tagWeights: map[string]int{"east": 2}, wantErr: true},
+		{name: "weight-not-positive", strategy: servers.WorkerSelectionWeighted, tagWeights: map[string]int{"region=east": 0}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, err := servers.NewWorkerSelector(tt.strategy, tt.tagWeights)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, ws)
+		})
+	}
+}
+
+func TestWorkerSelector_LeastConnections(t *testing.T) {
+	ws, err := servers.NewWorkerSelector(servers.WorkerSelectionLeastConnections, nil)
+	require.NoError(t, err)
+	workers := testWorkers("w1", "w2", "w3", "w4")
+	ws.SelectWorkers(workers, map[string]*servers.WorkerState{
+		"w1": {ActiveConnections: 5},
+		"w2": {ActiveConnections: 1, BytesPerSecond: 1000},
+		"w4": {ActiveConnections: 1, BytesPerSecond: 10},
+	})
+	// Ties are broken by throughput and workers without a state come last
+	assert.Equal(t, []string{"w4", "w2", "w1", "w3"}, workerNames(workers))
+}
+
+func TestWorkerSelector_RoundRobin(t *testing.T) {
+	ws, err := servers.NewWorkerSelector(servers.WorkerSelectionRoundRobin, nil)
+	require.NoError(t, err)
+	states := map[string]*servers.WorkerState{"w3": {ActiveConnections: 100}}
+	var firsts []string
+	for i := 0; i < 4; i++ {
+		workers := testWorkers("w3", "w1", "w2")
+		ws.SelectWorkers(workers, states)
+		assert.Len(t, workers, 3)
+		firsts = append(firsts, workers[0].PrivateId)
+	}
+	assert.Equal(t, []string{"w1", "w2", "w3", "w1"}, firsts)
+	// No workers is fine
+	ws.SelectWorkers(nil, states)
+}
+
+func TestWorkerSelector_Weighted(t *testing.T) {
+	ws, err := servers.NewWorkerSelector(servers.WorkerSelectionWeighted, map[string]int{
+		"region=east": 4,
+		"size=large":  2,
+	})
+	require.NoError(t, err)
+	workers := testWorkers("w1", "w2", "w3", "w4")
+	ws.SelectWorkers(workers, map[string]*servers.WorkerState{
+		// Load of (3+1)/1
+		"w1": {ActiveConnections: 3},
+		// Load of (7+1)/4
+		"w2": {ActiveConnections: 7, Tags: map[string]string{"region": "east"}},
+		// Load of (5+1)/6
+		"w3": {ActiveConnections: 5, Tags: map[string]string{"region": "east", "size": "large"}},
+		// Load of (1+1)/1, since the tag has no weight
+		"w4": {ActiveConnections: 1, Tags: map[string]string{"region": "west"}},
+	})
+	assert.Equal(t, []string{"w3", "w2", "w4", "w1"}, workerNames(workers))
+}
+
+func TestWorkerSelector_LowestLatency(t *testing.T) {
+	ws, err := servers.NewWorkerSelector(servers.WorkerSelectionLowestLatency, nil)
+	require.NoError(t, err)
+	workers := testWorkers("w1", "w2", "w3", "w4", "w5")
+	ws.SelectWorkers(workers, map[string]*servers.WorkerState{
+		"w1": {ControllerRttMs: 40},
+		// Same round trip time as w4, with more connections
+		"w2": {ControllerRttMs: 5, ActiveConnections: 3},
+		// No round trip time reported
+		"w3": {},
+		"w4": {ControllerRttMs: 5, ActiveConnections: 1},
+	})
+	assert.Equal(t, []string{"w4", "w2", "w1", "w3", "w5"}, workerNames(workers))
+}
+
+func TestPreferWorker(t *testing.T) {
+	states := map[string]*servers.WorkerState{
+		"w1": {}, "w2": {}, "w3": {},
+	}
+	tests := []struct {
+		name      string
+		workerId  string
+		want      []string
+		wantMoved bool
+	}{
+		{name: "healthy", workerId: "w3", want: []string{"w3", "w1", "w2", "w4"}, wantMoved: true},
+		{name: "already-first", workerId: "w1", want: []string{"w1", "w2", "w3", "w4"}, wantMoved: true},
+		// w4 has not reported its status recently
+		{name: "unhealthy", workerId: "w4", want: []string{"w1", "w2", "w3", "w4"}},
+		{name: "unknown", workerId: "w5", want: []string{"w1", "w2", "w3", "w4"}},
+		{name: "none", want: []string{"w1", "w2", "w3", "w4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workers := testWorkers("w1", "w2", "w3", "w4")
+			assert.Equal(t, tt.wantMoved, servers.PreferWorker(workers, states, tt.workerId))
+			assert.Equal(t, tt.want, workerNames(workers))
+		})
+	}
+}
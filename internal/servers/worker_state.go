@@ -0,0 +1,101 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/go-kms-wrapping/structwrapping"
+)
+
+// WorkerState is the state of a worker from its last status report. It is
+// shared between controllers through the database, so any controller can take
+// into account workers which report to other controllers.
+type WorkerState struct {
+	// Controller is the name of the controller the worker reported to
+	Controller string `json:"-"`
+	// ActiveSessions is the number of pending or active sessions of the worker
+	ActiveSessions uint32 `json:"active_sessions"`
+	// ActiveConnections is the number of connections of the worker which are
+	// not closed
+	ActiveConnections uint32 `json:"active_connections"`
+	// BytesPerSecond is the throughput of the connections of the worker since
+	// its previous status report
+	BytesPerSecond uint64 `json:"bytes_per_second"`
+	// Tags are the tags of the worker from its configuration
+	Tags map[string]string `json:"tags,omitempty"`
+	// Region and Zone locate the worker, from its configuration
+	Region string `json:"region,omitempty"`
+	Zone   string `json:"zone,omitempty"`
+	// ControllerRttMs is the round trip time in milliseconds of the previous
+	// status report of the worker, if it reports it
+	ControllerRttMs uint32 `json:"controller_rtt_ms,omitempty"`
+}
+
+// workerState is the stored form of a WorkerState, which is encrypted since
+// it describes the use of the cluster.
+type workerState struct {
+	WorkerId     string               `gorm:"primary_key"`
+	ControllerId string               `gorm:"not_null"`
+	KeyId        string               `gorm:"not_null"`
+	CtState      []byte               `gorm:"column:state;not_null" wrapping:"ct,state"`
+	State        []byte               `gorm:"-" wrapping:"pt,state"`
+	CreateTime   *timestamp.Timestamp `gorm:"default:current_timestamp"`
+	UpdateTime   *timestamp.Timestamp `gorm:"default:current_timestamp"`
+}
+
+func (s *workerState) TableName() string {
+	return "server_worker_state"
+}
+
+var _ db.FieldEncrypter = (*workerState)(nil)
+
+// GetScopeId returns the global scope, whose database key encrypts the states
+// of all workers.
+func (s *workerState) GetScopeId() string {
+	return scope.Global.String()
+}
+
+// GetKeyId returns the id of the key used to encrypt the state.
+func (s *workerState) GetKeyId() string {
+	return s.KeyId
+}
+
+// SetKeyId sets the id of the key used to encrypt the state.
+func (s *workerState) SetKeyId(keyId string) {
+	s.KeyId = keyId
+}
+
+// WrappedFields returns the worker state, whose state is encrypted. It
+// satisfies the db.FieldEncrypter interface.
+func (s *workerState) WrappedFields() interface{} {
+	return s
+}
+
+func (s *workerState) encrypt(ctx context.Context, cipher wrapping.Wrapper) error {
+	if err := structwrapping.WrapStruct(ctx, cipher, s, nil); err != nil {
+		return fmt.Errorf("error encrypting worker state: %w", err)
+	}
+	s.KeyId = cipher.KeyID()
+	return nil
+}
+
+func (s *workerState) decrypt(ctx context.Context, cipher wrapping.Wrapper) error {
+	if err := structwrapping.UnwrapStruct(ctx, cipher, s, nil); err != nil {
+		return fmt.Errorf("error decrypting worker state: %w", err)
+	}
+	return nil
+}
+
+func (s *workerState) toWorkerState() (*WorkerState, error) {
+	var ret WorkerState
+	if err := json.Unmarshal(s.State, &ret); err != nil {
+		return nil, fmt.Errorf("error unmarshaling worker state: %w", err)
+	}
+	ret.Controller = s.ControllerId
+	return &ret, nil
+}
@@ -0,0 +1,21 @@
+package servers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/libs/redact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerState_Redact(t *testing.T) {
+	assert := assert.New(t)
+	s := &workerState{
+		WorkerId: "w_1234567890",
+		State:    []byte(`{"active_sessions":2}`),
+		CtState:  []byte("worker-state-ciphertext"),
+	}
+	err := fmt.Errorf("failed for %s with %s and %s", s.WorkerId, s.State, s.CtState)
+	got := redact.Error(err, s).Error()
+	assert.Equal("failed for w_1234567890 with [REDACTED] and [REDACTED]", got)
+}
@@ -0,0 +1,77 @@
+package servers_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerStates(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	// Ensures the global scope has keys
+	iam.TestRepo(t, conn, wrapper)
+	repo, err := servers.NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(err)
+
+	for _, name := range []string{"worker-1", "worker-2"} {
+		_, _, err := repo.UpsertServer(ctx, &servers.Server{
+			Name:    name,
+			Type:    servers.ServerTypeWorker.String(),
+			Address: "127.0.0.1",
+		})
+		require.NoError(err)
+	}
+
+	assert.Error(repo.UpsertWorkerState(ctx, "", "controller-1", &servers.WorkerState{}))
+	assert.Error(repo.UpsertWorkerState(ctx, "worker-1", "", &servers.WorkerState{}))
+	assert.Error(repo.UpsertWorkerState(ctx, "worker-1", "controller-1", nil))
+	// The worker must be known
+	assert.Error(repo.UpsertWorkerState(ctx, "worker-3", "controller-1", &servers.WorkerState{}))
+
+	require.NoError(repo.UpsertWorkerState(ctx, "worker-1", "controller-1", &servers.WorkerState{ActiveSessions: 1, ActiveConnections: 2}))
+	require.NoError(repo.UpsertWorkerState(ctx, "worker-2", "controller-2", &servers.WorkerState{ActiveSessions: 3, ActiveConnections: 4}))
+
+	states, err := repo.ListWorkerStates(ctx)
+	require.NoError(err)
+	assert.Equal(map[string]*servers.WorkerState{
+		"worker-1": {Controller: "controller-1", ActiveSessions: 1, ActiveConnections: 2},
+		"worker-2": {Controller: "controller-2", ActiveSessions: 3, ActiveConnections: 4},
+	}, states)
+
+	// The worker reporting to another controller replaces its state
+	require.NoError(repo.UpsertWorkerState(ctx, "worker-1", "controller-2", &servers.WorkerState{ActiveConnections: 5}))
+	states, err = repo.ListWorkerStates(ctx)
+	require.NoError(err)
+	assert.Equal(&servers.WorkerState{Controller: "controller-2", ActiveConnections: 5}, states["worker-1"])
+
+	// The state is not stored in plaintext
+	plaintext, err := json.Marshal(&servers.WorkerState{ActiveConnections: 5})
+	require.NoError(err)
+	var stored []byte
+	require.NoError(conn.DB().QueryRow("select state from server_worker_state where worker_id = $1", "worker-1").Scan(&stored))
+	assert.NotEqual(plaintext, stored)
+
+	// States which are not fresh are not listed
+	_, err = rw.Exec(ctx, "update server_worker_state set update_time = $1 where worker_id = $2",
+		[]interface{}{time.Now().Add(-time.Minute).Format(time.RFC3339), "worker-2"})
+	require.NoError(err)
+	states, err = repo.ListWorkerStates(ctx)
+	require.NoError(err)
+	assert.Len(states, 1)
+	assert.Contains(states, "worker-1")
+	states, err = repo.ListWorkerStates(ctx, servers.WithLiveness(2*time.Minute))
+	require.NoError(err)
+	assert.Len(states, 2)
+}
@@ -0,0 +1,124 @@
+package session
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"google.golang.org/protobuf/proto"
+)
+
+// AuthorizationClaimsOid is the object identifier of the session certificate
+// extension holding the session's encrypted AuthorizationClaims.
+var AuthorizationClaimsOid = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57560, 1, 1}
+
+// AuthorizationClaims are the claims of a session authorization. They are
+// encrypted with the worker auth key and embedded in the session certificate,
+// so a worker can validate a session it has already seen without asking a
+// controller.
+type AuthorizationClaims struct {
+	SessionId      string    `json:"session_id"`
+	Endpoint       string    `json:"endpoint"`
+	ExpirationTime time.Time `json:"expiration_time"`
+	// Workers are the names of the workers allowed to handle the session. Any
+	// worker is allowed if it is empty.
+	Workers []string `json:"workers,omitempty"`
+}
+
+// newAuthorizationExtension returns a certificate extension holding the claims
+// encrypted with wrapper. The session id is used as additional authenticated
+// data so the extension can't be moved to another session's certificate.
+func newAuthorizationExtension(ctx context.Context, wrapper wrapping.Wrapper, claims *AuthorizationClaims) (pkix.Extension, error) {
+	if wrapper == nil {
+		return pkix.Extension{}, fmt.Errorf("new authorization extension: missing wrapper: %w", errors.ErrInvalidParameter)
+	}
+	if claims == nil || claims.SessionId == "" {
+		return pkix.Extension{}, fmt.Errorf("new authorization extension: missing session id: %w", errors.ErrInvalidParameter)
+	}
+	marshaledClaims, err := json.Marshal(claims)
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("new authorization extension: %w", err)
+	}
+	blobInfo, err := wrapper.Encrypt(ctx, marshaledClaims, []byte(claims.SessionId))
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("new authorization extension: %w", err)
+	}
+	marshaledBlob, err := proto.Marshal(blobInfo)
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("new authorization extension: %w", err)
+	}
+	value, err := asn1.Marshal(marshaledBlob)
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("new authorization extension: %w", err)
+	}
+	return pkix.Extension{
+		Id:    AuthorizationClaimsOid,
+		Value: value,
+	}, nil
+}
+
+// VerifyAuthorizationClaims decrypts the authorization claims of the session
+// certificate with wrapper and verifies they are for the session the
+// certificate is for, are not expired and allow workerName. It returns
+// ErrNoAuthorizationClaims if the certificate has no claims, in which case the
+// session must be validated by a controller.
+func VerifyAuthorizationClaims(ctx context.Context, wrapper wrapping.Wrapper, cert *x509.Certificate, workerName string) (*AuthorizationClaims, error) {
+	const op = "verify authorization claims"
+	if wrapper == nil {
+		return nil, fmt.Errorf("%s: missing wrapper: %w", op, errors.ErrInvalidParameter)
+	}
+	if cert == nil {
+		return nil, fmt.Errorf("%s: missing certificate: %w", op, errors.ErrInvalidParameter)
+	}
+	if len(cert.DNSNames) != 1 {
+		return nil, fmt.Errorf("%s: invalid length of DNS names (%d) in certificate", op, len(cert.DNSNames))
+	}
+	sessionId := cert.DNSNames[0]
+
+	var value []byte
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(AuthorizationClaimsOid) {
+			value = ext.Value
+			break
+		}
+	}
+	if value == nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrNoAuthorizationClaims)
+	}
+	var marshaledBlob []byte
+	rest, err := asn1.Unmarshal(value, &marshaledBlob)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%s: trailing data after claims", op)
+	}
+	blobInfo := new(wrapping.EncryptedBlobInfo)
+	if err := proto.Unmarshal(marshaledBlob, blobInfo); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	marshaledClaims, err := wrapper.Decrypt(ctx, blobInfo, []byte(sessionId))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	claims := new(AuthorizationClaims)
+	if err := json.Unmarshal(marshaledClaims, claims); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch {
+	case claims.SessionId != sessionId:
+		return nil, fmt.Errorf("%s: claims are for session %q not %q", op, claims.SessionId, sessionId)
+	case !claims.ExpirationTime.After(time.Now()):
+		return nil, fmt.Errorf("%s: session is expired", op)
+	case len(claims.Workers) > 0 && !contains(claims.Workers, workerName):
+		return nil, fmt.Errorf("%s: worker %q is not allowed to handle the session", op, workerName)
+	}
+	return claims, nil
+}
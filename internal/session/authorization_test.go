@@ -0,0 +1,133 @@
+package session
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyAuthorizationClaims(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	wrapper := db.TestWrapper(t)
+	workerAuth := db.TestWrapper(t)
+
+	newTestCert := func(t *testing.T, sessionId string, claims *AuthorizationClaims) *x509.Certificate {
+		t.Helper()
+		var exts []pkix.Extension
+		if claims != nil {
+			ext, err := newAuthorizationExtension(ctx, workerAuth, claims)
+			require.NoError(t, err)
+			exts = append(exts, ext)
+		}
+		_, certBytes, err := newCert(wrapper, "u_1234567890", sessionId, time.Now().Add(time.Hour), exts...)
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(certBytes)
+		require.NoError(t, err)
+		return cert
+	}
+
+	sessionId, err := newId()
+	require.NoError(t, err)
+	otherSessionId, err := newId()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		cert       *x509.Certificate
+		wrapper    bool
+		workerName string
+		wantErr    bool
+		wantIsErr  error
+	}{
+		{
+			name: "valid",
+			cert: newTestCert(t, sessionId, &AuthorizationClaims{
+				SessionId:      sessionId,
+				Endpoint:       "tcp://127.0.0.1:22",
+				ExpirationTime: time.Now().Add(time.Hour),
+			}),
+			workerName: "w_1",
+		},
+		{
+			name: "valid-allowed-worker",
+			cert: newTestCert(t, sessionId, &AuthorizationClaims{
+				SessionId:      sessionId,
+				ExpirationTime: time.Now().Add(time.Hour),
+				Workers:        []string{"w_1", "w_2"},
+			}),
+			workerName: "w_2",
+		},
+		{
+			name: "worker-not-allowed",
+			cert: newTestCert(t, sessionId, &AuthorizationClaims{
+				SessionId:      sessionId,
+				ExpirationTime: time.Now().Add(time.Hour),
+				Workers:        []string{"w_1"},
+			}),
+			workerName: "w_2",
+			wantErr:    true,
+		},
+		{
+			name: "expired",
+			cert: newTestCert(t, sessionId, &AuthorizationClaims{
+				SessionId:      sessionId,
+				ExpirationTime: time.Now().Add(-time.Minute),
+			}),
+			workerName: "w_1",
+			wantErr:    true,
+		},
+		{
+			name: "other-session",
+			cert: newTestCert(t, sessionId, &AuthorizationClaims{
+				SessionId:      otherSessionId,
+				ExpirationTime: time.Now().Add(time.Hour),
+			}),
+			workerName: "w_1",
+			wantErr:    true,
+		},
+		{
+			name:       "no-claims",
+			cert:       newTestCert(t, sessionId, nil),
+			workerName: "w_1",
+			wantErr:    true,
+			wantIsErr:  ErrNoAuthorizationClaims,
+		},
+		{
+			name: "other-key",
+			cert: newTestCert(t, sessionId, &AuthorizationClaims{
+				SessionId:      sessionId,
+				ExpirationTime: time.Now().Add(time.Hour),
+			}),
+			wrapper:    true,
+			workerName: "w_1",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			verifyWrapper := workerAuth
+			if tt.wrapper {
+				verifyWrapper = db.TestWrapper(t)
+			}
+			got, err := VerifyAuthorizationClaims(ctx, verifyWrapper, tt.cert, tt.workerName)
+			if tt.wantErr {
+				require.Error(err)
+				if tt.wantIsErr != nil {
+					assert.Truef(errors.Is(err, tt.wantIsErr), "unexpected error %s", err.Error())
+				}
+				return
+			}
+			require.NoError(err)
+			assert.Equal(sessionId, got.SessionId)
+		})
+	}
+}
@@ -16,4 +16,8 @@ var (
 	// ErrOpenConnection indicates that a session can not be terminated because
 	// it has open connections.
 	ErrOpenConnection = errors.New("session has open connections")
+
+	// ErrNoAuthorizationClaims indicates that a session certificate has no
+	// authorization claims.
+	ErrNoAuthorizationClaims = errors.New("session certificate has no authorization claims")
 )
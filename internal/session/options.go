@@ -26,6 +26,7 @@ type options struct {
 	withTestTofu       []byte
 	withListingConvert bool
 	withSessionIds     []string
+	withWorkers        []string
 }
 
 func getDefaultOptions() options {
@@ -89,3 +90,11 @@ func withListingConvert(withListingConvert bool) Option {
 		o.withListingConvert = withListingConvert
 	}
 }
+
+// WithWorkers allows specifying the names of the workers allowed to handle a
+// session.
+func WithWorkers(names []string) Option {
+	return func(o *options) {
+		o.withWorkers = names
+	}
+}
@@ -143,6 +143,27 @@ session_connection_limit(expiration_time, connection_limit) as (
 select expiration_time, connection_limit, current_connection_count 
 from  
 	session_connection_limit, session_connection_count;	
+`
+	// connectionStats aggregates the connections of the sessions in a scope.
+	// Byte counts are only reported by workers when a connection is closed,
+	// so they do not include open connections.
+	connectionStats = `
+select
+	s.public_id as session_id,
+	count(scs.connection_id) filter (where scs.state in ('authorized', 'connected')) as active_connection_count,
+	count(sc.public_id) as total_connection_count,
+	coalesce(sum(sc.bytes_up), 0)::bigint as bytes_up,
+	coalesce(sum(sc.bytes_down), 0)::bigint as bytes_down
+from
+	session s
+	left join session_connection sc
+		on sc.session_id = s.public_id
+	left join session_connection_state scs
+		on scs.connection_id = sc.public_id and scs.end_time is null
+where
+	s.scope_id = $1
+group by s.public_id
+order by s.public_id;
 `
 	sessionList = `
 select * 
@@ -233,3 +254,63 @@ where
 )
 `
 )
+
+const (
+	// unusedSessionsQuery returns the unexpired sessions pending for longer
+	// than $1 seconds and those active for longer than $2 seconds without any
+	// connection, with their current state, locking them. A time to live <= 0
+	// disables its check.
+	unusedSessionsQuery = `
+select
+	s.public_id,
+	coalesce(s.target_id, ''),
+	coalesce(s.user_id, ''),
+	coalesce(s.scope_id, ''),
+	ss.state
+from
+	session s
+	join session_state ss on
+		ss.session_id = s.public_id and
+		ss.end_time is null
+where
+	s.expiration_time > now() and
+	(
+		-- sessions no client activated in time
+		(
+			ss.state = 'pending' and
+			$1::double precision > 0 and
+			ss.start_time < now() - make_interval(secs => $1::double precision)
+		) or
+		-- active sessions without any connection in time
+		(
+			ss.state = 'active' and
+			$2::double precision > 0 and
+			ss.start_time < now() - make_interval(secs => $2::double precision) and
+			not exists (
+				select 1
+				from session_connection sc
+				where sc.session_id = s.public_id
+			)
+		)
+	)
+for update of s skip locked
+`
+
+	// lastSessionWorkerQuery returns the worker which proxied the most recent
+	// session of a user to a target created after a time.
+	lastSessionWorkerQuery = `
+select
+	server_id
+from
+	session
+where
+	user_id = $1 and
+	target_id = $2 and
+	create_time > $3 and
+	server_id is not null and
+	server_type = 'worker'
+order by
+	create_time desc
+limit 1;
+`
+)
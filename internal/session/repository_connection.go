@@ -54,6 +54,49 @@ func (r *Repository) ListConnections(ctx context.Context, sessionId string, opt
 	return connections, nil
 }
 
+// ConnectionStats summarizes the connections of a session.
+type ConnectionStats struct {
+	// SessionId of the session
+	SessionId string `json:"session_id"`
+	// ActiveConnectionCount is the number of authorized or connected
+	// connections
+	ActiveConnectionCount uint32 `json:"active_connection_count"`
+	// TotalConnectionCount is the number of connections in any state
+	TotalConnectionCount uint32 `json:"total_connection_count"`
+	// BytesUp is the sum of the bytes up of the closed connections
+	BytesUp uint64 `json:"bytes_up"`
+	// BytesDown is the sum of the bytes down of the closed connections
+	BytesDown uint64 `json:"bytes_down"`
+}
+
+// ListConnectionStats returns the connection stats of every session in the
+// scope, computed with a single query so it is cheap enough to poll. Bytes
+// are reported by workers when connections close, so the byte counts only
+// include closed connections. No options are currently supported.
+func (r *Repository) ListConnectionStats(ctx context.Context, scopeId string, opt ...Option) ([]*ConnectionStats, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list connection stats: missing scope id: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, connectionStats, []interface{}{scopeId})
+	if err != nil {
+		return nil, fmt.Errorf("list connection stats: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*ConnectionStats
+	for rows.Next() {
+		var s ConnectionStats
+		if err := r.reader.ScanRows(rows, &s); err != nil {
+			return nil, fmt.Errorf("list connection stats: scan row failed: %w", err)
+		}
+		stats = append(stats, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list connection stats: %w", err)
+	}
+	return stats, nil
+}
+
 // DeleteConnection will delete a connection from the repository.
 func (r *Repository) DeleteConnection(ctx context.Context, publicId string, opt ...Option) (int, error) {
 	if publicId == "" {
@@ -129,6 +129,57 @@ func TestRepository_ListConnection(t *testing.T) {
 	})
 }
 
+func TestRepository_ListConnectionStats(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(err)
+	ctx := context.Background()
+
+	composedOf := TestSessionParams(t, conn, wrapper, iamRepo)
+	busy := TestSession(t, conn, wrapper, composedOf)
+	idle := TestSession(t, conn, wrapper, composedOf)
+
+	open := TestConnection(t, conn, busy.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222)
+	closed := TestConnection(t, conn, busy.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222)
+	_, err = repo.CloseConnections(ctx, []CloseWith{{
+		ConnectionId: closed.PublicId,
+		BytesUp:      10,
+		BytesDown:    20,
+		ClosedReason: ConnectionClosedByUser,
+	}})
+	require.NoError(err)
+	require.NotEqual(open.PublicId, closed.PublicId)
+
+	_, err = repo.ListConnectionStats(ctx, "")
+	assert.Truef(errors.Is(err, errors.ErrInvalidParameter), "unexpected error %v", err)
+
+	got, err := repo.ListConnectionStats(ctx, busy.ScopeId)
+	require.NoError(err)
+	byId := make(map[string]*ConnectionStats, len(got))
+	for _, s := range got {
+		byId[s.SessionId] = s
+	}
+	assert.Len(byId, 2)
+	assert.Equal(&ConnectionStats{
+		SessionId:             busy.PublicId,
+		ActiveConnectionCount: 1,
+		TotalConnectionCount:  2,
+		BytesUp:               10,
+		BytesDown:             20,
+	}, byId[busy.PublicId])
+	assert.Equal(&ConnectionStats{SessionId: idle.PublicId}, byId[idle.PublicId])
+
+	got, err = repo.ListConnectionStats(ctx, "p_thisIsNotValid")
+	require.NoError(err)
+	assert.Empty(got)
+}
+
 func TestRepository_DeleteConnection(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -4,14 +4,15 @@ import (
 	"context"
 	"crypto/ed25519"
 	"crypto/subtle"
+	"crypto/x509/pkix"
 	stderrors "errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/db/timestamp"
 	"github.com/hashicorp/boundary/internal/errors"
-	"github.com/hashicorp/boundary/internal/kms"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -19,8 +20,10 @@ import (
 
 // CreateSession inserts into the repository and returns the new Session with
 // its State of "Pending".  The following fields must be empty when creating a
-// session: ServerId, ServerType, and PublicId.  No options are
-// currently supported.
+// session: ServerId, ServerType, and PublicId.  If a worker auth wrapper is
+// configured, the session certificate holds the session's encrypted
+// AuthorizationClaims. Supports the WithWorkers option to limit which workers
+// may handle the session.
 func (r *Repository) CreateSession(ctx context.Context, sessionWrapper wrapping.Wrapper, newSession *Session, opt ...Option) (*Session, ed25519.PrivateKey, error) {
 	if newSession == nil {
 		return nil, nil, fmt.Errorf("create session: missing session: %w", errors.ErrInvalidParameter)
@@ -70,7 +73,22 @@ func (r *Repository) CreateSession(ctx context.Context, sessionWrapper wrapping.
 		return nil, nil, fmt.Errorf("create session: %w", err)
 	}
 
-	privKey, certBytes, err := newCert(sessionWrapper, newSession.UserId, id, newSession.ExpirationTime.Timestamp.AsTime())
+	opts := getOpts(opt...)
+	var exts []pkix.Extension
+	if workerAuth := r.kms.GetExternalWrappers().WorkerAuth(); workerAuth != nil {
+		ext, err := newAuthorizationExtension(ctx, workerAuth, &AuthorizationClaims{
+			SessionId:      id,
+			Endpoint:       newSession.Endpoint,
+			ExpirationTime: newSession.ExpirationTime.Timestamp.AsTime(),
+			Workers:        opts.withWorkers,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("create session: %w", err)
+		}
+		exts = append(exts, ext)
+	}
+
+	privKey, certBytes, err := newCert(sessionWrapper, newSession.UserId, id, newSession.ExpirationTime.Timestamp.AsTime(), exts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("create session: %w", err)
 	}
@@ -126,7 +144,7 @@ func (r *Repository) LookupSession(ctx context.Context, sessionId string, opt ..
 		db.StdRetryCnt,
 		db.ExpBackoff{},
 		func(read db.Reader, w db.Writer) error {
-			if err := read.LookupById(ctx, &session); err != nil {
+			if err := read.LookupById(ctx, &session, db.WithFieldWrapper(r.kms.DatabaseFieldWrapper())); err != nil {
 				return fmt.Errorf("lookup session: failed %w for %s", err, sessionId)
 			}
 			states, err := fetchStates(ctx, read, sessionId, db.WithOrder("start_time desc"))
@@ -143,15 +161,7 @@ func (r *Repository) LookupSession(ctx context.Context, sessionId string, opt ..
 		}
 		return nil, nil, fmt.Errorf("lookup session: %w", err)
 	}
-	if len(session.CtTofuToken) > 0 {
-		databaseWrapper, err := r.kms.GetWrapper(ctx, session.ScopeId, kms.KeyPurposeDatabase, kms.WithKeyId(session.KeyId))
-		if err != nil {
-			return nil, nil, fmt.Errorf("lookup session: unable to get database wrapper: %w", err)
-		}
-		if err := session.decrypt(ctx, databaseWrapper); err != nil {
-			return nil, nil, fmt.Errorf("lookup session: cannot decrypt session value: %w", err)
-		}
-	} else {
+	if len(session.CtTofuToken) == 0 {
 		session.CtTofuToken = nil
 	}
 
@@ -163,18 +173,19 @@ func (r *Repository) LookupSession(ctx context.Context, sessionId string, opt ..
 	return &session, authzSummary, nil
 }
 
-// ListSessions will sessions.  Supports the WithLimit, WithScopeId and WithSessionIds options.
+// ListSessions will sessions.  Supports the WithLimit, WithScopeId, WithUserId
+// and WithSessionIds options.
 func (r *Repository) ListSessions(ctx context.Context, opt ...Option) ([]*Session, error) {
 	opts := getOpts(opt...)
 	var where []string
 	var args []interface{}
 
 	inClauseCnt := 0
-	switch {
-	case opts.withScopeId != "":
+	if opts.withScopeId != "" {
 		inClauseCnt += 1
 		where, args = append(where, fmt.Sprintf("scope_id = $%d", inClauseCnt)), append(args, opts.withScopeId)
-	case opts.withUserId != "":
+	}
+	if opts.withUserId != "" {
 		inClauseCnt += 1
 		where, args = append(where, fmt.Sprintf("user_id = $%d", inClauseCnt)), append(args, opts.withUserId)
 	}
@@ -203,7 +214,7 @@ func (r *Repository) ListSessions(ctx context.Context, opt ...Option) ([]*Sessio
 
 	var whereClause string
 	if len(where) > 0 {
-		whereClause = " and " + strings.Join(where, " and")
+		whereClause = " and " + strings.Join(where, " and ")
 	}
 	q := sessionList
 	query := fmt.Sprintf(q, limit, whereClause, opts.withOrder)
@@ -595,21 +606,16 @@ func (r *Repository) ActivateSession(ctx context.Context, sessionId string, sess
 			if err := reader.LookupById(ctx, &foundSession); err != nil {
 				return fmt.Errorf("lookup session: failed for %s: %w", sessionId, err)
 			}
-			databaseWrapper, err := r.kms.GetWrapper(ctx, foundSession.ScopeId, kms.KeyPurposeDatabase)
-			if err != nil {
-				return fmt.Errorf("unable to get database wrapper: %w", err)
-			}
 			if len(foundSession.TofuToken) > 0 && subtle.ConstantTimeCompare(foundSession.TofuToken, tofuToken) != 1 {
 				return fmt.Errorf("tofu token mismatch")
 			}
 
+			updatedSession.ScopeId = foundSession.ScopeId
 			updatedSession.TofuToken = tofuToken
 			updatedSession.ServerId = serverId
 			updatedSession.ServerType = serverType
-			if err := updatedSession.encrypt(ctx, databaseWrapper); err != nil {
-				return err
-			}
-			rowsUpdated, err := w.Update(ctx, &updatedSession, []string{"CtTofuToken"}, nil)
+			// the tofu token is encrypted and its key id recorded by the update
+			rowsUpdated, err := w.Update(ctx, &updatedSession, []string{"TofuToken"}, nil, db.WithFieldWrapper(r.kms.DatabaseFieldWrapper()))
 			if err != nil {
 				return err
 			}
@@ -660,22 +666,15 @@ func (r *Repository) updateState(ctx context.Context, sessionId string, sessionV
 			// We need to update the session version as that's the aggregate
 			updatedSession.PublicId = sessionId
 			updatedSession.Version = uint32(sessionVersion) + 1
-			rowsUpdated, err := w.Update(ctx, &updatedSession, []string{"Version"}, nil, db.WithVersion(&sessionVersion))
+			// the tofu token is decrypted by the lookup after the update
+			rowsUpdated, err := w.Update(ctx, &updatedSession, []string{"Version"}, nil, db.WithVersion(&sessionVersion), db.WithFieldWrapper(r.kms.DatabaseFieldWrapper()))
 			if err != nil {
 				return err
 			}
 			if rowsUpdated != 1 {
 				return fmt.Errorf("updated session and %d rows updated", rowsUpdated)
 			}
-			if len(updatedSession.CtTofuToken) > 0 {
-				databaseWrapper, err := r.kms.GetWrapper(ctx, updatedSession.ScopeId, kms.KeyPurposeDatabase, kms.WithKeyId(updatedSession.KeyId))
-				if err != nil {
-					return fmt.Errorf("lookup session: unable to get database wrapper: %w", err)
-				}
-				if err := updatedSession.decrypt(ctx, databaseWrapper); err != nil {
-					return fmt.Errorf("lookup session: cannot decrypt session value: %w", err)
-				}
-			} else {
+			if len(updatedSession.CtTofuToken) == 0 {
 				updatedSession.CtTofuToken = nil
 			}
 
@@ -712,3 +711,30 @@ func fetchStates(ctx context.Context, r db.Reader, sessionId string, opt ...db.O
 	}
 	return states, nil
 }
+
+// LastSessionWorker returns the id of the worker which proxied the most recent
+// session of the user to the target created after since, or an empty string
+// if there is none.
+func (r *Repository) LastSessionWorker(ctx context.Context, userId, targetId string, since time.Time) (string, error) {
+	if userId == "" {
+		return "", fmt.Errorf("last session worker: missing user id: %w", errors.ErrInvalidParameter)
+	}
+	if targetId == "" {
+		return "", fmt.Errorf("last session worker: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, lastSessionWorkerQuery, []interface{}{userId, targetId, since})
+	if err != nil {
+		return "", fmt.Errorf("last session worker: query failed: %w", err)
+	}
+	defer rows.Close()
+	var workerId string
+	for rows.Next() {
+		if err := rows.Scan(&workerId); err != nil {
+			return "", fmt.Errorf("last session worker: scan row failed: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("last session worker: %w", err)
+	}
+	return workerId, nil
+}
@@ -92,6 +92,24 @@ func TestRepository_ListSession(t *testing.T) {
 			wantCnt: 0,
 			wantErr: false,
 		},
+		{
+			name:      "withScopeId-and-withUserId",
+			createCnt: repo.defaultLimit + 1,
+			args: args{
+				opt: []Option{WithScopeId(composedOf.ScopeId), WithUserId(composedOf.UserId)},
+			},
+			wantCnt: repo.defaultLimit,
+			wantErr: false,
+		},
+		{
+			name:      "bad-withUserId",
+			createCnt: repo.defaultLimit + 1,
+			args: args{
+				opt: []Option{WithScopeId(composedOf.ScopeId), WithUserId("u_thisIsNotValid")},
+			},
+			wantCnt: 0,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -715,6 +733,53 @@ func TestRepository_TerminateSession(t *testing.T) {
 	}
 }
 
+func TestRepository_TerminateSessionChecksInCredential(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(err)
+	targetRepo, err := target.NewRepository(rw, rw, kms)
+	require.NoError(err)
+	ctx := context.Background()
+
+	countRotations := func() int {
+		rows, err := rw.Query(ctx, "select count(*) from outbox_message where kind = $1", []interface{}{target.CredentialRotationKind})
+		require.NoError(err)
+		defer rows.Close()
+		var n int
+		for rows.Next() {
+			require.NoError(rows.Scan(&n))
+		}
+		return n
+	}
+
+	s := TestDefaultSession(t, conn, wrapper, iamRepo)
+	require.NoError(targetRepo.SetCredentialCheckoutPolicy(ctx, s.TargetId, target.CredentialCheckoutReject, 0, true))
+	checkout, err := targetRepo.CheckOutCredential(ctx, s.TargetId, s.UserId)
+	require.NoError(err)
+	require.NoError(targetRepo.AttachCredentialCheckout(ctx, checkout.CheckoutId, s.PublicId))
+
+	// An attached checkout can only be checked in by terminating its session
+	require.NoError(targetRepo.CheckInCredential(ctx, checkout.CheckoutId))
+	got, err := targetRepo.LookupCredentialCheckout(ctx, s.TargetId)
+	require.NoError(err)
+	require.NotNil(got)
+	assert.Equal(s.PublicId, got.SessionId)
+
+	before := countRotations()
+	_, err = repo.TerminateSession(ctx, s.PublicId, s.Version, ClosedByUser)
+	require.NoError(err)
+	got, err = targetRepo.LookupCredentialCheckout(ctx, s.TargetId)
+	require.NoError(err)
+	assert.Nil(got)
+	assert.Equal(before+1, countRotations())
+}
+
 func TestRepository_TerminateCompletedSessions(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -1559,3 +1624,52 @@ func TestRepository_DeleteSession(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_LastSessionWorker(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(err)
+	ctx := context.Background()
+	since := time.Now().Add(-time.Hour)
+
+	composedOf := TestSessionParams(t, conn, wrapper, iamRepo)
+	got, err := repo.LastSessionWorker(ctx, composedOf.UserId, composedOf.TargetId, since)
+	require.NoError(err)
+	assert.Empty(got)
+
+	// A pending session has no worker yet
+	first := TestSession(t, conn, wrapper, composedOf)
+	got, err = repo.LastSessionWorker(ctx, composedOf.UserId, composedOf.TargetId, since)
+	require.NoError(err)
+	assert.Empty(got)
+
+	w1, w2 := TestWorker(t, conn, wrapper), TestWorker(t, conn, wrapper)
+	_, _, err = repo.ActivateSession(ctx, first.PublicId, first.Version, w1.PrivateId, w1.Type, TestTofu(t))
+	require.NoError(err)
+	got, err = repo.LastSessionWorker(ctx, composedOf.UserId, composedOf.TargetId, since)
+	require.NoError(err)
+	assert.Equal(w1.PrivateId, got)
+
+	second := TestSession(t, conn, wrapper, composedOf)
+	_, _, err = repo.ActivateSession(ctx, second.PublicId, second.Version, w2.PrivateId, w2.Type, TestTofu(t))
+	require.NoError(err)
+	got, err = repo.LastSessionWorker(ctx, composedOf.UserId, composedOf.TargetId, since)
+	require.NoError(err)
+	assert.Equal(w2.PrivateId, got)
+
+	// Sessions before since are not considered
+	got, err = repo.LastSessionWorker(ctx, composedOf.UserId, composedOf.TargetId, time.Now().Add(time.Minute))
+	require.NoError(err)
+	assert.Empty(got)
+
+	_, err = repo.LastSessionWorker(ctx, "", composedOf.TargetId, since)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	_, err = repo.LastSessionWorker(ctx, composedOf.UserId, "", since)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+}
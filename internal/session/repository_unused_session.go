@@ -0,0 +1,119 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/outbox"
+)
+
+// AutoCancelEventKind is the outbox message kind of the sessions canceled by
+// the controllers because no client used them in time. The payload is an
+// AutoCancelEvent.
+const AutoCancelEventKind = "session.auto_canceled"
+
+// AutoCancelSchemaVersion is the version of the schema of the payloads of
+// AutoCancelEventKind messages.
+const AutoCancelSchemaVersion = 1
+
+// The reasons unused sessions are canceled for.
+const (
+	// UnusedPending is a session no client activated in time.
+	UnusedPending = "pending"
+
+	// UnusedIdle is a session activated by a client which made no
+	// connection in time.
+	UnusedIdle = "idle"
+)
+
+// An AutoCancelEvent is the cancellation of a session no client used in time.
+type AutoCancelEvent struct {
+	SchemaVersion int       `json:"schema_version"`
+	SessionId     string    `json:"session_id"`
+	TargetId      string    `json:"target_id"`
+	UserId        string    `json:"user_id"`
+	ScopeId       string    `json:"scope_id"`
+	Reason        string    `json:"reason"`
+	Time          time.Time `json:"time"`
+}
+
+// CancelUnusedSessions cancels the sessions still pending pendingTtl after
+// they were created and those still without any connection idleTtl after
+// they were activated, so sessions which were authorized but never used don't
+// linger until they expire. A TTL <= 0 disables its check. Each cancellation
+// is enqueued in the outbox as an AutoCancelEvent, which are returned. The
+// sessions are then terminated like other canceled sessions by
+// TerminateCompletedSessions. Sessions locked by another transaction, e.g.
+// another controller canceling them, are skipped.
+func (r *Repository) CancelUnusedSessions(ctx context.Context, pendingTtl, idleTtl time.Duration, opt ...Option) ([]*AutoCancelEvent, error) {
+	if pendingTtl <= 0 && idleTtl <= 0 {
+		return nil, fmt.Errorf("cancel unused sessions: no time to live: %w", errors.ErrInvalidParameter)
+	}
+	var canceled []*AutoCancelEvent
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			var err error
+			if canceled, err = unusedSessions(ctx, reader, pendingTtl, idleTtl); err != nil {
+				return err
+			}
+			for _, e := range canceled {
+				// The session version is the aggregate of its states
+				if _, err := w.Exec(ctx, "update session set version = version + 1 where public_id = $1", []interface{}{e.SessionId}); err != nil {
+					return fmt.Errorf("unable to update session %s version: %w", e.SessionId, err)
+				}
+				if _, err := w.Exec(ctx, updateSessionState, []interface{}{e.SessionId, StatusCanceling.String()}); err != nil {
+					return fmt.Errorf("unable to update session %s state to %s: %w", e.SessionId, StatusCanceling.String(), err)
+				}
+				e.SchemaVersion = AutoCancelSchemaVersion
+				payload, err := json.Marshal(e)
+				if err != nil {
+					return err
+				}
+				if err := outbox.Enqueue(ctx, w, AutoCancelEventKind, payload); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cancel unused sessions: %w", err)
+	}
+	return canceled, nil
+}
+
+// unusedSessions returns the sessions to cancel, locking them. The rows are
+// read before the sessions are updated, since a transaction can't run
+// statements while reading rows.
+func unusedSessions(ctx context.Context, reader db.Reader, pendingTtl, idleTtl time.Duration) ([]*AutoCancelEvent, error) {
+	rows, err := reader.Query(ctx, unusedSessionsQuery, []interface{}{pendingTtl.Seconds(), idleTtl.Seconds()})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	now := time.Now()
+	var unused []*AutoCancelEvent
+	for rows.Next() {
+		var state string
+		e := &AutoCancelEvent{Time: now}
+		if err := rows.Scan(&e.SessionId, &e.TargetId, &e.UserId, &e.ScopeId, &state); err != nil {
+			return nil, err
+		}
+		e.Reason = UnusedIdle
+		if state == StatusPending.String() {
+			e.Reason = UnusedPending
+		}
+		unused = append(unused, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return unused, nil
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_CancelUnusedSessions(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	activate := func(t *testing.T, s *Session) *Session {
+		t.Helper()
+		srv := TestWorker(t, conn, wrapper)
+		s, _, err := repo.ActivateSession(ctx, s.PublicId, s.Version, srv.PrivateId, srv.Type, TestTofu(t))
+		require.NoError(t, err)
+		return s
+	}
+	reasons := func(canceled []*AutoCancelEvent) map[string]string {
+		ret := make(map[string]string, len(canceled))
+		for _, e := range canceled {
+			ret[e.SessionId] = e.Reason
+		}
+		return ret
+	}
+	state := func(t *testing.T, id string) Status {
+		t.Helper()
+		s, _, err := repo.LookupSession(ctx, id)
+		require.NoError(t, err)
+		return s.States[0].Status
+	}
+
+	pending := TestDefaultSession(t, conn, wrapper, iamRepo)
+	idle := activate(t, TestDefaultSession(t, conn, wrapper, iamRepo))
+	connected := activate(t, TestDefaultSession(t, conn, wrapper, iamRepo))
+	TestConnection(t, conn, connected.PublicId, "127.0.0.1", 22, "127.0.0.1", 222)
+
+	t.Run("not-yet-unused", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		canceled, err := repo.CancelUnusedSessions(ctx, time.Hour, time.Hour)
+		require.NoError(err)
+		assert.Empty(canceled)
+	})
+	t.Run("idle", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		canceled, err := repo.CancelUnusedSessions(ctx, 0, time.Nanosecond)
+		require.NoError(err)
+		assert.Equal(map[string]string{idle.PublicId: UnusedIdle}, reasons(canceled))
+		assert.Equal(StatusCanceling, state(t, idle.PublicId))
+		assert.Equal(StatusActive, state(t, connected.PublicId))
+		assert.Equal(StatusPending, state(t, pending.PublicId))
+	})
+	t.Run("pending", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		canceled, err := repo.CancelUnusedSessions(ctx, time.Nanosecond, time.Nanosecond)
+		require.NoError(err)
+		assert.Equal(map[string]string{pending.PublicId: UnusedPending}, reasons(canceled))
+		assert.Equal(StatusCanceling, state(t, pending.PublicId))
+		assert.Equal(StatusActive, state(t, connected.PublicId))
+		e := canceled[0]
+		assert.Equal(AutoCancelSchemaVersion, e.SchemaVersion)
+		assert.Equal(pending.TargetId, e.TargetId)
+		assert.Equal(pending.UserId, e.UserId)
+		assert.Equal(pending.ScopeId, e.ScopeId)
+	})
+	t.Run("no-ttl", func(t *testing.T) {
+		assert := assert.New(t)
+		_, err := repo.CancelUnusedSessions(ctx, 0, 0)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+}
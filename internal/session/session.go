@@ -5,6 +5,7 @@ import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"math/big"
 	mathrand "math/rand"
@@ -108,6 +109,7 @@ func (s *Session) GetPublicId() string {
 
 var _ Cloneable = (*Session)(nil)
 var _ db.VetForWriter = (*Session)(nil)
+var _ db.FieldEncrypter = (*Session)(nil)
 
 // New creates a new in memory session.
 func New(c ComposedOf, opt ...Option) (*Session, error) {
@@ -314,7 +316,9 @@ func contains(ss []string, t string) bool {
 	return false
 }
 
-func newCert(wrapper wrapping.Wrapper, userId, jobId string, exp time.Time) (ed25519.PrivateKey, []byte, error) {
+// newCert returns a new self signed certificate for the session, adding any
+// extra extensions.
+func newCert(wrapper wrapping.Wrapper, userId, jobId string, exp time.Time, ext ...pkix.Extension) (ed25519.PrivateKey, []byte, error) {
 	if wrapper == nil {
 		return nil, nil, fmt.Errorf("new session cert: missing wrapper: %w", errors.ErrInvalidParameter)
 	}
@@ -340,6 +344,7 @@ func newCert(wrapper wrapping.Wrapper, userId, jobId string, exp time.Time) (ed2
 		NotAfter:              exp,
 		BasicConstraintsValid: true,
 		IsCA:                  true,
+		ExtraExtensions:       ext,
 	}
 
 	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, pubKey, privKey)
@@ -349,6 +354,28 @@ func newCert(wrapper wrapping.Wrapper, userId, jobId string, exp time.Time) (ed2
 	return privKey, certBytes, nil
 }
 
+// GetScopeId returns the scope id of the session, whose database key is used
+// to encrypt the session's tofu token.
+func (s *Session) GetScopeId() string {
+	return s.ScopeId
+}
+
+// GetKeyId returns the id of the key used to encrypt the session's tofu token.
+func (s *Session) GetKeyId() string {
+	return s.KeyId
+}
+
+// SetKeyId sets the id of the key used to encrypt the session's tofu token.
+func (s *Session) SetKeyId(keyId string) {
+	s.KeyId = keyId
+}
+
+// WrappedFields returns the session, whose tofu token is encrypted. It
+// satisfies the db.FieldEncrypter interface.
+func (s *Session) WrappedFields() interface{} {
+	return s
+}
+
 func (s *Session) encrypt(ctx context.Context, cipher wrapping.Wrapper) error {
 	if err := structwrapping.WrapStruct(ctx, cipher, s, nil); err != nil {
 		return fmt.Errorf("error encrypting session: %w", err)
@@ -2,6 +2,7 @@ package session
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/hashicorp/boundary/internal/db/timestamp"
 	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/libs/redact"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -269,3 +271,15 @@ func TestSession_SetTableName(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionView_Redact(t *testing.T) {
+	assert := assert.New(t)
+	v := &sessionView{
+		PublicId:    "s_1234567890",
+		TofuToken:   []byte("tofu-token-plaintext"),
+		CtTofuToken: []byte("tofu-token-ciphertext"),
+	}
+	err := fmt.Errorf("failed for %s with %s and %s", v.PublicId, v.TofuToken, v.CtTofuToken)
+	got := redact.Error(err, v).Error()
+	assert.Equal("failed for s_1234567890 with [REDACTED] and [REDACTED]", got)
+}
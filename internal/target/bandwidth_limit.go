@@ -0,0 +1,71 @@
+package target
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+const defaultBandwidthLimitTableName = "target_bandwidth_limit"
+
+// A BandwidthLimit caps the bytes per second workers proxy for the
+// connections of a target, counting both directions. ConnectionBytesPerSecond
+// caps each connection and SessionBytesPerSecond all connections of a session
+// on a worker combined. A limit of 0 is unlimited.
+type BandwidthLimit struct {
+	TargetId                 string `gorm:"primary_key"`
+	ConnectionBytesPerSecond uint64
+	SessionBytesPerSecond    uint64
+	CreateTime               *timestamp.Timestamp `gorm:"default:current_timestamp"`
+	UpdateTime               *timestamp.Timestamp `gorm:"default:current_timestamp"`
+}
+
+// TableName returns the table name for the bandwidth limit.
+func (l *BandwidthLimit) TableName() string {
+	return defaultBandwidthLimitTableName
+}
+
+// SetBandwidthLimit sets the bandwidth limits of the target. Setting both
+// limits to 0 removes them. No options are currently supported.
+func (r *Repository) SetBandwidthLimit(ctx context.Context, targetId string, connectionBytesPerSecond, sessionBytesPerSecond uint64, opt ...Option) error {
+	if targetId == "" {
+		return fmt.Errorf("set bandwidth limit: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	var err error
+	switch {
+	case connectionBytesPerSecond == 0 && sessionBytesPerSecond == 0:
+		_, err = r.writer.Exec(ctx,
+			"delete from target_bandwidth_limit where target_id = ?",
+			[]interface{}{targetId})
+	default:
+		_, err = r.writer.Exec(ctx,
+			`insert into target_bandwidth_limit (target_id, connection_bytes_per_second, session_bytes_per_second) values (?, ?, ?)
+			on conflict (target_id) do update set
+				connection_bytes_per_second = excluded.connection_bytes_per_second,
+				session_bytes_per_second = excluded.session_bytes_per_second`,
+			[]interface{}{targetId, connectionBytesPerSecond, sessionBytesPerSecond})
+	}
+	if err != nil {
+		return fmt.Errorf("set bandwidth limit: %w for %s", err, targetId)
+	}
+	return nil
+}
+
+// LookupBandwidthLimit returns the bandwidth limits of the target. A target
+// without limits returns a BandwidthLimit with both limits 0. No options are
+// currently supported.
+func (r *Repository) LookupBandwidthLimit(ctx context.Context, targetId string, opt ...Option) (*BandwidthLimit, error) {
+	if targetId == "" {
+		return nil, fmt.Errorf("lookup bandwidth limit: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	l := &BandwidthLimit{}
+	if err := r.reader.LookupWhere(ctx, l, "target_id = ?", targetId); err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return &BandwidthLimit{TargetId: targetId}, nil
+		}
+		return nil, fmt.Errorf("lookup bandwidth limit: %w for %s", err, targetId)
+	}
+	return l, nil
+}
@@ -0,0 +1,80 @@
+package target
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_BandwidthLimit(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, proj := iam.TestScopes(t, iamRepo)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("set-and-lookup", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "set-and-lookup")
+
+		l, err := repo.LookupBandwidthLimit(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(tar.PublicId, l.TargetId)
+		assert.Zero(l.ConnectionBytesPerSecond)
+		assert.Zero(l.SessionBytesPerSecond)
+
+		require.NoError(repo.SetBandwidthLimit(ctx, tar.PublicId, 1024, 0))
+		l, err = repo.LookupBandwidthLimit(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(uint64(1024), l.ConnectionBytesPerSecond)
+		assert.Zero(l.SessionBytesPerSecond)
+
+		require.NoError(repo.SetBandwidthLimit(ctx, tar.PublicId, 512, 4096))
+		l, err = repo.LookupBandwidthLimit(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(uint64(512), l.ConnectionBytesPerSecond)
+		assert.Equal(uint64(4096), l.SessionBytesPerSecond)
+
+		require.NoError(repo.SetBandwidthLimit(ctx, tar.PublicId, 0, 0))
+		l, err = repo.LookupBandwidthLimit(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Zero(l.ConnectionBytesPerSecond)
+		assert.Zero(l.SessionBytesPerSecond)
+		assert.Nil(l.CreateTime)
+	})
+	t.Run("deleted-with-target", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "deleted-with-target")
+		require.NoError(repo.SetBandwidthLimit(ctx, tar.PublicId, 1024, 1024))
+
+		rowsDeleted, err := repo.DeleteTarget(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(1, rowsDeleted)
+		l, err := repo.LookupBandwidthLimit(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Zero(l.ConnectionBytesPerSecond)
+		assert.Zero(l.SessionBytesPerSecond)
+	})
+	t.Run("missing-target", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		require.Error(repo.SetBandwidthLimit(ctx, "ttcp_1234567890", 1024, 0))
+
+		err := repo.SetBandwidthLimit(ctx, "", 1024, 0)
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, err = repo.LookupBandwidthLimit(ctx, "")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+}
@@ -0,0 +1,137 @@
+package target
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+)
+
+// cloneSettingsQueries copy the settings of a target kept outside of the
+// target itself to its clone. Each takes the id of the clone followed by the
+// id of the target cloned.
+var cloneSettingsQueries = []string{
+	`insert into target_connection_authorization (target_id)
+	select ? from target_connection_authorization where target_id = ?`,
+	`insert into target_bandwidth_limit (target_id, connection_bytes_per_second, session_bytes_per_second)
+	select ?, connection_bytes_per_second, session_bytes_per_second
+	  from target_bandwidth_limit
+	 where target_id = ?`,
+	`insert into target_connection_rate_limit (target_id, connections_per_minute)
+	select ?, connections_per_minute
+	  from target_connection_rate_limit
+	 where target_id = ?`,
+	`insert into target_credential_checkout_policy (target_id, mode, queue_timeout_seconds, rotate_on_check_in)
+	select ?, mode, queue_timeout_seconds, rotate_on_check_in
+	  from target_credential_checkout_policy
+	 where target_id = ?`,
+	`insert into target_peer_identity (target_id, mode)
+	select ?, mode from target_peer_identity where target_id = ?`,
+	`insert into target_user_name_template (target_id, template)
+	select ?, template from target_user_name_template where target_id = ?`,
+}
+
+// CloneTcpTarget creates a new target in the scope of the target with the
+// given name and returns it with its list of host sets. The new target has
+// the configuration and host sets of the target, along with its connection
+// authorization, bandwidth and connection rate limits, credential checkout
+// policy, peer identity setting and user name template. Sessions, checkouts
+// and annotations of the target are not copied. WithDescription is the only supported option; without it the
+// description of the target is copied.
+func (r *Repository) CloneTcpTarget(ctx context.Context, targetId, name string, opt ...Option) (Target, []*TargetSet, error) {
+	opts := getOpts(opt...)
+	if targetId == "" {
+		return nil, nil, fmt.Errorf("clone tcp target: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	if name == "" {
+		return nil, nil, fmt.Errorf("clone tcp target: name empty: %w", errors.ErrInvalidParameter)
+	}
+
+	src := allocTcpTarget()
+	src.PublicId = targetId
+	if err := r.reader.LookupById(ctx, &src); err != nil {
+		return nil, nil, fmt.Errorf("clone tcp target: %w for %s", err, targetId)
+	}
+
+	t := src.Clone().(*TcpTarget)
+	id, err := newTcpTargetId()
+	if err != nil {
+		return nil, nil, fmt.Errorf("clone tcp target: %w", err)
+	}
+	t.PublicId = id
+	t.Name = name
+	if opts.withDescription != "" {
+		t.Description = opts.withDescription
+	}
+	t.CreateTime = nil
+	t.UpdateTime = nil
+	t.Version = 0
+
+	oplogWrapper, err := r.kms.GetWrapper(ctx, t.ScopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return nil, nil, fmt.Errorf("clone tcp target: unable to get oplog wrapper: %w", err)
+	}
+
+	metadata := t.oplog(oplog.OpType_OP_TYPE_CREATE)
+	var returnedTarget *TcpTarget
+	var returnedHostSets []*TargetSet
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(read db.Reader, w db.Writer) error {
+			// Read the host sets in the transaction so the clone gets those
+			// of the target when it is created
+			srcSets, err := fetchSets(ctx, read, targetId)
+			if err != nil {
+				return fmt.Errorf("unable to read host sets: %w", err)
+			}
+			newHostSets := make([]interface{}, 0, len(srcSets))
+			for _, s := range srcSets {
+				hostSet, err := NewTargetHostSet(t.PublicId, s.PublicId)
+				if err != nil {
+					return fmt.Errorf("unable to create in memory target host set: %w", err)
+				}
+				newHostSets = append(newHostSets, hostSet)
+			}
+
+			targetTicket, err := w.GetTicket(t)
+			if err != nil {
+				return fmt.Errorf("unable to get ticket: %w", err)
+			}
+			msgs := make([]*oplog.Message, 0, len(newHostSets)+1)
+			var targetOplogMsg oplog.Message
+			returnedTarget = t.Clone().(*TcpTarget)
+			if err := w.Create(ctx, returnedTarget, db.NewOplogMsg(&targetOplogMsg)); err != nil {
+				return err
+			}
+			msgs = append(msgs, &targetOplogMsg)
+			if len(newHostSets) > 0 {
+				hostSetOplogMsgs := make([]*oplog.Message, 0, len(newHostSets))
+				if err := w.CreateItems(ctx, newHostSets, db.NewOplogMsgs(&hostSetOplogMsgs)); err != nil {
+					return fmt.Errorf("unable to add host sets: %w", err)
+				}
+				if returnedHostSets, err = fetchSets(ctx, read, t.PublicId); err != nil {
+					return fmt.Errorf("unable to read host sets: %w", err)
+				}
+				msgs = append(msgs, hostSetOplogMsgs...)
+			}
+			for _, q := range cloneSettingsQueries {
+				if _, err := w.Exec(ctx, q, []interface{}{t.PublicId, targetId}); err != nil {
+					return fmt.Errorf("unable to copy settings: %w", err)
+				}
+			}
+			if err := w.WriteOplogEntryWith(ctx, oplogWrapper, targetTicket, metadata, msgs); err != nil {
+				return fmt.Errorf("unable to write oplog: %w", err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("clone tcp target: %w for %s", err, targetId)
+	}
+	return returnedTarget, returnedHostSets, nil
+}
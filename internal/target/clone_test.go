@@ -0,0 +1,141 @@
+package target
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_CloneTcpTarget(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, proj := iam.TestScopes(t, iamRepo)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	cats := static.TestCatalogs(t, conn, proj.PublicId, 1)
+	hsets := static.TestSets(t, conn, cats[0].GetPublicId(), 2)
+	hostSetIds := []string{hsets[0].PublicId, hsets[1].PublicId}
+
+	t.Run("full", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		src := TestTcpTarget(t, conn, proj.PublicId, "full",
+			WithDescription("source"),
+			WithDefaultPort(22),
+			WithSessionMaxSeconds(600),
+			WithSessionConnectionLimit(3),
+			WithHostSets(hostSetIds))
+		require.NoError(repo.SetConnectionAuthorization(ctx, src.PublicId, true))
+		require.NoError(repo.SetBandwidthLimit(ctx, src.PublicId, 1024, 4096))
+		require.NoError(repo.SetConnectionRateLimit(ctx, src.PublicId, 60))
+		require.NoError(repo.SetCredentialCheckoutPolicy(ctx, src.PublicId, CredentialCheckoutQueue, 30, true))
+		require.NoError(repo.SetPeerIdentity(ctx, src.PublicId, PeerIdentityProxyV2))
+		require.NoError(repo.SetUserNameTemplate(ctx, src.PublicId, "{{user}}_admin"))
+
+		got, gotSets, err := repo.CloneTcpTarget(ctx, src.PublicId, "full-clone")
+		require.NoError(err)
+		clone := got.(*TcpTarget)
+		assert.NotEqual(src.PublicId, clone.PublicId)
+		assert.Equal(src.ScopeId, clone.ScopeId)
+		assert.Equal("full-clone", clone.Name)
+		assert.Equal("source", clone.Description)
+		assert.Equal(uint32(22), clone.DefaultPort)
+		assert.Equal(uint32(600), clone.SessionMaxSeconds)
+		assert.Equal(int32(3), clone.SessionConnectionLimit)
+		assert.Equal(uint32(1), clone.Version)
+		var gotSetIds []string
+		for _, s := range gotSets {
+			gotSetIds = append(gotSetIds, s.PublicId)
+		}
+		assert.ElementsMatch(hostSetIds, gotSetIds)
+
+		required, err := repo.LookupConnectionAuthorization(ctx, clone.PublicId)
+		require.NoError(err)
+		assert.True(required)
+		l, err := repo.LookupBandwidthLimit(ctx, clone.PublicId)
+		require.NoError(err)
+		assert.Equal(uint64(1024), l.ConnectionBytesPerSecond)
+		assert.Equal(uint64(4096), l.SessionBytesPerSecond)
+		rl, err := repo.LookupConnectionRateLimit(ctx, clone.PublicId)
+		require.NoError(err)
+		assert.Equal(uint32(60), rl.ConnectionsPerMinute)
+		p, err := repo.LookupCredentialCheckoutPolicy(ctx, clone.PublicId)
+		require.NoError(err)
+		require.NotNil(p)
+		assert.Equal(CredentialCheckoutQueue, p.Mode)
+		assert.Equal(uint32(30), p.QueueTimeoutSeconds)
+		assert.True(p.RotateOnCheckIn)
+		pi, err := repo.LookupPeerIdentity(ctx, clone.PublicId)
+		require.NoError(err)
+		assert.Equal(PeerIdentityProxyV2, pi.Mode)
+		ut, err := repo.LookupUserNameTemplate(ctx, clone.PublicId)
+		require.NoError(err)
+		assert.Equal("{{user}}_admin", ut.Template)
+
+		require.NoError(db.TestVerifyOplog(t, rw, clone.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE)))
+
+		// The source is unchanged
+		_, srcSets, err := repo.LookupTarget(ctx, src.PublicId)
+		require.NoError(err)
+		assert.Len(srcSets, 2)
+	})
+	t.Run("minimal", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		src := TestTcpTarget(t, conn, proj.PublicId, "minimal", WithDescription("source"))
+
+		got, gotSets, err := repo.CloneTcpTarget(ctx, src.PublicId, "minimal-clone", WithDescription("clone"))
+		require.NoError(err)
+		clone := got.(*TcpTarget)
+		assert.Equal("clone", clone.Description)
+		assert.Empty(gotSets)
+
+		required, err := repo.LookupConnectionAuthorization(ctx, clone.PublicId)
+		require.NoError(err)
+		assert.False(required)
+		l, err := repo.LookupBandwidthLimit(ctx, clone.PublicId)
+		require.NoError(err)
+		assert.Zero(l.ConnectionBytesPerSecond)
+		p, err := repo.LookupCredentialCheckoutPolicy(ctx, clone.PublicId)
+		require.NoError(err)
+		assert.Nil(p)
+		pi, err := repo.LookupPeerIdentity(ctx, clone.PublicId)
+		require.NoError(err)
+		assert.Empty(pi.Mode)
+	})
+	t.Run("duplicate-name", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		src := TestTcpTarget(t, conn, proj.PublicId, "duplicate-name")
+		require.NoError(repo.SetBandwidthLimit(ctx, src.PublicId, 1024, 0))
+
+		_, _, err := repo.CloneTcpTarget(ctx, src.PublicId, "duplicate-name")
+		require.Error(err)
+		assert.True(errors.IsUniqueError(err))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		src := TestTcpTarget(t, conn, proj.PublicId, "invalid")
+
+		_, _, err := repo.CloneTcpTarget(ctx, "", "invalid-clone")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, _, err = repo.CloneTcpTarget(ctx, src.PublicId, "")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, _, err = repo.CloneTcpTarget(ctx, "ttcp_1234567890", "invalid-clone")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrRecordNotFound))
+	})
+}
@@ -0,0 +1,63 @@
+package target
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+const defaultConnectionAuthorizationTableName = "target_connection_authorization"
+
+// A ConnectionAuthorization marks a target which requires each new connection
+// to a session to be authorized again.
+type ConnectionAuthorization struct {
+	TargetId   string               `gorm:"primary_key"`
+	CreateTime *timestamp.Timestamp `gorm:"default:current_timestamp"`
+}
+
+// TableName returns the table name for the connection authorization.
+func (c *ConnectionAuthorization) TableName() string {
+	return defaultConnectionAuthorizationTableName
+}
+
+// SetConnectionAuthorization sets whether each new connection to a session of
+// the target must be authorized again. No options are currently supported.
+func (r *Repository) SetConnectionAuthorization(ctx context.Context, targetId string, required bool, opt ...Option) error {
+	if targetId == "" {
+		return fmt.Errorf("set connection authorization: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	var err error
+	switch required {
+	case true:
+		_, err = r.writer.Exec(ctx,
+			"insert into target_connection_authorization (target_id) values (?) on conflict do nothing",
+			[]interface{}{targetId})
+	default:
+		_, err = r.writer.Exec(ctx,
+			"delete from target_connection_authorization where target_id = ?",
+			[]interface{}{targetId})
+	}
+	if err != nil {
+		return fmt.Errorf("set connection authorization: %w for %s", err, targetId)
+	}
+	return nil
+}
+
+// LookupConnectionAuthorization returns true if each new connection to a
+// session of the target must be authorized again. No options are currently
+// supported.
+func (r *Repository) LookupConnectionAuthorization(ctx context.Context, targetId string, opt ...Option) (bool, error) {
+	if targetId == "" {
+		return false, fmt.Errorf("lookup connection authorization: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	ca := &ConnectionAuthorization{}
+	if err := r.reader.LookupWhere(ctx, ca, "target_id = ?", targetId); err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("lookup connection authorization: %w for %s", err, targetId)
+	}
+	return true, nil
+}
@@ -0,0 +1,70 @@
+package target
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ConnectionAuthorization(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, proj := iam.TestScopes(t, iamRepo)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("set-and-lookup", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "set-and-lookup")
+
+		required, err := repo.LookupConnectionAuthorization(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.False(required)
+
+		require.NoError(repo.SetConnectionAuthorization(ctx, tar.PublicId, true))
+		// Setting it again is a no-op
+		require.NoError(repo.SetConnectionAuthorization(ctx, tar.PublicId, true))
+		required, err = repo.LookupConnectionAuthorization(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.True(required)
+
+		require.NoError(repo.SetConnectionAuthorization(ctx, tar.PublicId, false))
+		required, err = repo.LookupConnectionAuthorization(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.False(required)
+	})
+	t.Run("deleted-with-target", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "deleted-with-target")
+		require.NoError(repo.SetConnectionAuthorization(ctx, tar.PublicId, true))
+
+		rowsDeleted, err := repo.DeleteTarget(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(1, rowsDeleted)
+		required, err := repo.LookupConnectionAuthorization(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.False(required)
+	})
+	t.Run("missing-target", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		require.Error(repo.SetConnectionAuthorization(ctx, "ttcp_1234567890", true))
+
+		err := repo.SetConnectionAuthorization(ctx, "", true)
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, err = repo.LookupConnectionAuthorization(ctx, "")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+}
@@ -0,0 +1,99 @@
+package target
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// Limits of the connection metadata of a target.
+const (
+	MaxConnectionMetadataEntries     = 32
+	MaxConnectionMetadataValueLength = 1024
+)
+
+// connectionMetadataKeyRegexp matches the valid keys of connection metadata:
+// they are used in the names of environment variables and template
+// placeholders by connect helpers.
+var connectionMetadataKeyRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// ValidConnectionMetadataKey returns true if key may be used as the key of
+// connection metadata: a lowercase letter followed by up to 63 lowercase
+// letters, digits or underscores.
+func ValidConnectionMetadataKey(key string) bool {
+	return connectionMetadataKeyRegexp.MatchString(key)
+}
+
+// SetConnectionMetadata replaces the connection metadata of the target, the
+// key/value pairs returned with its session authorizations. An empty map
+// removes it. No options are currently supported.
+func (r *Repository) SetConnectionMetadata(ctx context.Context, targetId string, metadata map[string]string, opt ...Option) error {
+	if targetId == "" {
+		return fmt.Errorf("set connection metadata: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	if len(metadata) > MaxConnectionMetadataEntries {
+		return fmt.Errorf("set connection metadata: more than %d entries: %w", MaxConnectionMetadataEntries, errors.ErrInvalidParameter)
+	}
+	for k, v := range metadata {
+		if !ValidConnectionMetadataKey(k) {
+			return fmt.Errorf("set connection metadata: invalid key %q: %w", k, errors.ErrInvalidParameter)
+		}
+		if len(v) > MaxConnectionMetadataValueLength {
+			return fmt.Errorf("set connection metadata: value of key %q is too long: %w", k, errors.ErrInvalidParameter)
+		}
+	}
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			if _, err := w.Exec(ctx,
+				"delete from target_connection_metadata where target_id = ?",
+				[]interface{}{targetId}); err != nil {
+				return err
+			}
+			for k, v := range metadata {
+				if _, err := w.Exec(ctx,
+					"insert into target_connection_metadata (target_id, key, value) values (?, ?, ?)",
+					[]interface{}{targetId, k, v}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("set connection metadata: %w for %s", err, targetId)
+	}
+	return nil
+}
+
+// LookupConnectionMetadata returns the connection metadata of the target, or
+// an empty map if it has none. No options are currently supported.
+func (r *Repository) LookupConnectionMetadata(ctx context.Context, targetId string, opt ...Option) (map[string]string, error) {
+	if targetId == "" {
+		return nil, fmt.Errorf("lookup connection metadata: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx,
+		"select key, value from target_connection_metadata where target_id = ?",
+		[]interface{}{targetId})
+	if err != nil {
+		return nil, fmt.Errorf("lookup connection metadata: %w for %s", err, targetId)
+	}
+	defer rows.Close()
+	metadata := map[string]string{}
+	for rows.Next() {
+		var k, v string
+		if err := rows.Scan(&k, &v); err != nil {
+			return nil, fmt.Errorf("lookup connection metadata: %w for %s", err, targetId)
+		}
+		metadata[k] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("lookup connection metadata: %w for %s", err, targetId)
+	}
+	return metadata, nil
+}
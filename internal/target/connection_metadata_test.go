@@ -0,0 +1,93 @@
+package target
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidConnectionMetadataKey(t *testing.T) {
+	for _, k := range []string{"database", "k8s_namespace", "a"} {
+		assert.True(t, ValidConnectionMetadataKey(k), k)
+	}
+	for _, k := range []string{"", "Database", "1st", "_db", "k8s-namespace", "db name", strings.Repeat("a", 65)} {
+		assert.False(t, ValidConnectionMetadataKey(k), k)
+	}
+}
+
+func TestRepository_ConnectionMetadata(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, proj := iam.TestScopes(t, iamRepo)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("set-and-lookup", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "set-and-lookup")
+
+		md, err := repo.LookupConnectionMetadata(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Empty(md)
+
+		require.NoError(repo.SetConnectionMetadata(ctx, tar.PublicId, map[string]string{"database": "orders", "k8s_namespace": "prod"}))
+		md, err = repo.LookupConnectionMetadata(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(map[string]string{"database": "orders", "k8s_namespace": "prod"}, md)
+
+		// Setting replaces all of it
+		require.NoError(repo.SetConnectionMetadata(ctx, tar.PublicId, map[string]string{"database": "billing"}))
+		md, err = repo.LookupConnectionMetadata(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(map[string]string{"database": "billing"}, md)
+
+		require.NoError(repo.SetConnectionMetadata(ctx, tar.PublicId, nil))
+		md, err = repo.LookupConnectionMetadata(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Empty(md)
+	})
+	t.Run("deleted-with-target", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "deleted-with-target")
+		require.NoError(repo.SetConnectionMetadata(ctx, tar.PublicId, map[string]string{"database": "orders"}))
+
+		rowsDeleted, err := repo.DeleteTarget(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(1, rowsDeleted)
+		md, err := repo.LookupConnectionMetadata(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Empty(md)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "invalid")
+		require.Error(repo.SetConnectionMetadata(ctx, "ttcp_1234567890", map[string]string{"database": "orders"}))
+
+		for _, md := range []map[string]string{
+			{"Database": "orders"},
+			{"database": strings.Repeat("a", MaxConnectionMetadataValueLength+1)},
+		} {
+			err := repo.SetConnectionMetadata(ctx, tar.PublicId, md)
+			require.Error(err)
+			assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		}
+		err := repo.SetConnectionMetadata(ctx, "", nil)
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, err = repo.LookupConnectionMetadata(ctx, "")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+}
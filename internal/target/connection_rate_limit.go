@@ -0,0 +1,74 @@
+package target
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+const defaultConnectionRateLimitTableName = "target_connection_rate_limit"
+
+// A ConnectionRateLimit caps how many connections each session of a target
+// may open per minute on a worker, so a client opening many short
+// connections through one session can't overwhelm the endpoints. A limit of
+// 0 is unlimited.
+type ConnectionRateLimit struct {
+	TargetId             string `gorm:"primary_key"`
+	ConnectionsPerMinute uint32
+	CreateTime           *timestamp.Timestamp `gorm:"default:current_timestamp"`
+	UpdateTime           *timestamp.Timestamp `gorm:"default:current_timestamp"`
+}
+
+// TableName returns the table name for the connection rate limit.
+func (l *ConnectionRateLimit) TableName() string {
+	return defaultConnectionRateLimitTableName
+}
+
+// SetConnectionRateLimit sets the connections each session of the target may
+// open per minute. Setting it to 0 removes the limit. No options are
+// currently supported.
+func (r *Repository) SetConnectionRateLimit(ctx context.Context, targetId string, connectionsPerMinute uint32, opt ...Option) error {
+	if targetId == "" {
+		return fmt.Errorf("set connection rate limit: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	if connectionsPerMinute > math.MaxInt32 {
+		return fmt.Errorf("set connection rate limit: limit too large: %w", errors.ErrInvalidParameter)
+	}
+	var err error
+	switch connectionsPerMinute {
+	case 0:
+		_, err = r.writer.Exec(ctx,
+			"delete from target_connection_rate_limit where target_id = ?",
+			[]interface{}{targetId})
+	default:
+		_, err = r.writer.Exec(ctx,
+			`insert into target_connection_rate_limit (target_id, connections_per_minute) values (?, ?)
+			on conflict (target_id) do update set
+				connections_per_minute = excluded.connections_per_minute`,
+			[]interface{}{targetId, connectionsPerMinute})
+	}
+	if err != nil {
+		return fmt.Errorf("set connection rate limit: %w for %s", err, targetId)
+	}
+	return nil
+}
+
+// LookupConnectionRateLimit returns the connection rate limit of the target.
+// A target without a limit returns a ConnectionRateLimit of 0. No options are
+// currently supported.
+func (r *Repository) LookupConnectionRateLimit(ctx context.Context, targetId string, opt ...Option) (*ConnectionRateLimit, error) {
+	if targetId == "" {
+		return nil, fmt.Errorf("lookup connection rate limit: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	l := &ConnectionRateLimit{}
+	if err := r.reader.LookupWhere(ctx, l, "target_id = ?", targetId); err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return &ConnectionRateLimit{TargetId: targetId}, nil
+		}
+		return nil, fmt.Errorf("lookup connection rate limit: %w for %s", err, targetId)
+	}
+	return l, nil
+}
@@ -0,0 +1,76 @@
+package target
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ConnectionRateLimit(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, proj := iam.TestScopes(t, iamRepo)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("set-and-lookup", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "set-and-lookup")
+
+		l, err := repo.LookupConnectionRateLimit(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(tar.PublicId, l.TargetId)
+		assert.Zero(l.ConnectionsPerMinute)
+
+		require.NoError(repo.SetConnectionRateLimit(ctx, tar.PublicId, 60))
+		l, err = repo.LookupConnectionRateLimit(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(uint32(60), l.ConnectionsPerMinute)
+		assert.NotNil(l.CreateTime)
+
+		require.NoError(repo.SetConnectionRateLimit(ctx, tar.PublicId, 10))
+		l, err = repo.LookupConnectionRateLimit(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(uint32(10), l.ConnectionsPerMinute)
+
+		require.NoError(repo.SetConnectionRateLimit(ctx, tar.PublicId, 0))
+		l, err = repo.LookupConnectionRateLimit(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Zero(l.ConnectionsPerMinute)
+		assert.Nil(l.CreateTime)
+	})
+	t.Run("deleted-with-target", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "deleted-with-target")
+		require.NoError(repo.SetConnectionRateLimit(ctx, tar.PublicId, 60))
+
+		rowsDeleted, err := repo.DeleteTarget(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(1, rowsDeleted)
+		l, err := repo.LookupConnectionRateLimit(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Zero(l.ConnectionsPerMinute)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		require.Error(repo.SetConnectionRateLimit(ctx, "ttcp_1234567890", 60))
+
+		err := repo.SetConnectionRateLimit(ctx, "", 60)
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, err = repo.LookupConnectionRateLimit(ctx, "")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+}
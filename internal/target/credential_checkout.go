@@ -0,0 +1,330 @@
+package target
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/go-uuid"
+)
+
+const (
+	defaultCredentialCheckoutPolicyTableName = "target_credential_checkout_policy"
+	defaultCredentialCheckoutTableName       = "target_credential_checkout"
+)
+
+// The modes of a credential checkout policy: when the credential of a target
+// is checked out, a new session is rejected immediately or waits in line for
+// it to be checked in.
+const (
+	CredentialCheckoutReject = "reject"
+	CredentialCheckoutQueue  = "queue"
+)
+
+// CredentialRotationKind is the outbox message kind of the requests to rotate
+// the credential of a target with RotateOnCheckIn set, enqueued when the
+// credential is checked in. The payload is a JSON object with the target_id,
+// checkout_id, session_id and user_id of the checkout, and the schema_version
+// of its schema in the event schema registry.
+const CredentialRotationKind = "target.credential_rotation"
+
+// CredentialRotationSchemaVersion is the version of the schema of the
+// payloads of CredentialRotationKind messages.
+const CredentialRotationSchemaVersion = 1
+
+var (
+	// ErrCredentialCheckedOut indicates that the credential of a target is
+	// checked out by another session.
+	ErrCredentialCheckedOut = stderrors.New("credential is checked out")
+
+	// ErrCredentialCheckoutTimeout indicates that the credential of a target
+	// was not checked in within the queue timeout of its policy.
+	ErrCredentialCheckoutTimeout = stderrors.New("timed out waiting for credential")
+)
+
+// credentialCheckoutPollInterval is how often a session waiting in line for
+// the credential of a target checks whether it can check it out. It is a
+// variable so it can be tweaked in tests.
+var credentialCheckoutPollInterval = 500 * time.Millisecond
+
+// A CredentialCheckoutPolicy marks a target as backed by a shared credential
+// which only one session may hold at a time.
+type CredentialCheckoutPolicy struct {
+	TargetId string `gorm:"primary_key"`
+	// Mode is CredentialCheckoutReject or CredentialCheckoutQueue
+	Mode string
+	// QueueTimeoutSeconds is how long a session waits for the credential in
+	// the CredentialCheckoutQueue mode
+	QueueTimeoutSeconds uint32
+	// RotateOnCheckIn requests a rotation of the credential through the
+	// outbox whenever it is checked in
+	RotateOnCheckIn bool
+	CreateTime      *timestamp.Timestamp `gorm:"default:current_timestamp"`
+	UpdateTime      *timestamp.Timestamp `gorm:"default:current_timestamp"`
+}
+
+// TableName returns the table name for the credential checkout policy.
+func (p *CredentialCheckoutPolicy) TableName() string {
+	return defaultCredentialCheckoutPolicyTableName
+}
+
+// A CredentialCheckout is the checkout of the credential of a target by a
+// user's session. SessionId is empty until the checkout is attached to the
+// session it was made for.
+type CredentialCheckout struct {
+	TargetId   string `gorm:"primary_key"`
+	CheckoutId string
+	UserId     string
+	SessionId  string
+	CreateTime *timestamp.Timestamp `gorm:"default:current_timestamp"`
+}
+
+// TableName returns the table name for the credential checkout.
+func (c *CredentialCheckout) TableName() string {
+	return defaultCredentialCheckoutTableName
+}
+
+// SetCredentialCheckoutPolicy sets the credential checkout policy of the
+// target. An empty mode removes it; the current checkout, if any, is kept
+// until it is checked in. No options are currently supported.
+func (r *Repository) SetCredentialCheckoutPolicy(ctx context.Context, targetId, mode string, queueTimeoutSeconds uint32, rotateOnCheckIn bool, opt ...Option) error {
+	if targetId == "" {
+		return fmt.Errorf("set credential checkout policy: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	var err error
+	switch mode {
+	case "":
+		_, err = r.writer.Exec(ctx,
+			"delete from target_credential_checkout_policy where target_id = ?",
+			[]interface{}{targetId})
+	case CredentialCheckoutReject, CredentialCheckoutQueue:
+		_, err = r.writer.Exec(ctx,
+			`insert into target_credential_checkout_policy (target_id, mode, queue_timeout_seconds, rotate_on_check_in) values (?, ?, ?, ?)
+			on conflict (target_id) do update set
+				mode = excluded.mode,
+				queue_timeout_seconds = excluded.queue_timeout_seconds,
+				rotate_on_check_in = excluded.rotate_on_check_in`,
+			[]interface{}{targetId, mode, queueTimeoutSeconds, rotateOnCheckIn})
+	default:
+		return fmt.Errorf("set credential checkout policy: unknown mode %q: %w", mode, errors.ErrInvalidParameter)
+	}
+	if err != nil {
+		return fmt.Errorf("set credential checkout policy: %w for %s", err, targetId)
+	}
+	return nil
+}
+
+// LookupCredentialCheckoutPolicy returns the credential checkout policy of
+// the target, or nil if it has none. No options are currently supported.
+func (r *Repository) LookupCredentialCheckoutPolicy(ctx context.Context, targetId string, opt ...Option) (*CredentialCheckoutPolicy, error) {
+	if targetId == "" {
+		return nil, fmt.Errorf("lookup credential checkout policy: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	p := &CredentialCheckoutPolicy{}
+	if err := r.reader.LookupWhere(ctx, p, "target_id = ?", targetId); err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup credential checkout policy: %w for %s", err, targetId)
+	}
+	return p, nil
+}
+
+// LookupCredentialCheckout returns the current checkout of the credential of
+// the target, or nil if it is checked in. No options are currently supported.
+func (r *Repository) LookupCredentialCheckout(ctx context.Context, targetId string, opt ...Option) (*CredentialCheckout, error) {
+	if targetId == "" {
+		return nil, fmt.Errorf("lookup credential checkout: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	c := &CredentialCheckout{}
+	if err := r.reader.LookupWhere(ctx, c, "target_id = ?", targetId); err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup credential checkout: %w for %s", err, targetId)
+	}
+	return c, nil
+}
+
+// CheckOutCredential checks out the credential of the target for a session
+// of the user which is about to be created, and returns the checkout, which
+// must be attached to the session with AttachCredentialCheckout once it is
+// created or checked in again with CheckInCredential if it is not. It returns
+// nil if the target has no credential checkout policy. If the credential is
+// checked out, it returns ErrCredentialCheckedOut in the
+// CredentialCheckoutReject mode, and waits in line for up to the queue timeout
+// before returning ErrCredentialCheckoutTimeout in the CredentialCheckoutQueue
+// mode. No options are currently supported.
+func (r *Repository) CheckOutCredential(ctx context.Context, targetId, userId string, opt ...Option) (*CredentialCheckout, error) {
+	if targetId == "" {
+		return nil, fmt.Errorf("check out credential: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	if userId == "" {
+		return nil, fmt.Errorf("check out credential: missing user id: %w", errors.ErrInvalidParameter)
+	}
+	p, err := r.LookupCredentialCheckoutPolicy(ctx, targetId)
+	if err != nil {
+		return nil, fmt.Errorf("check out credential: %w", err)
+	}
+	if p == nil {
+		return nil, nil
+	}
+	checkoutId, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, fmt.Errorf("check out credential: %w", err)
+	}
+	if p.Mode != CredentialCheckoutQueue {
+		ok, err := r.tryCheckOutCredential(ctx, targetId, checkoutId, userId, 0)
+		switch {
+		case err != nil:
+			return nil, fmt.Errorf("check out credential: %w for %s", err, targetId)
+		case !ok:
+			return nil, fmt.Errorf("check out credential: %w for %s", ErrCredentialCheckedOut, targetId)
+		}
+		return r.LookupCredentialCheckout(ctx, targetId)
+	}
+
+	waiterId, err := r.enterCredentialCheckoutLine(ctx, targetId, userId)
+	if err != nil {
+		return nil, fmt.Errorf("check out credential: %w for %s", err, targetId)
+	}
+	defer func() {
+		_, _ = r.writer.Exec(ctx, "delete from target_credential_checkout_waiter where id = ?", []interface{}{waiterId})
+	}()
+	deadline := time.Now().Add(time.Duration(p.QueueTimeoutSeconds) * time.Second)
+	ticker := time.NewTicker(credentialCheckoutPollInterval)
+	defer ticker.Stop()
+	for {
+		ok, err := r.tryCheckOutCredential(ctx, targetId, checkoutId, userId, waiterId)
+		if err != nil {
+			return nil, fmt.Errorf("check out credential: %w for %s", err, targetId)
+		}
+		if ok {
+			return r.LookupCredentialCheckout(ctx, targetId)
+		}
+		if !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("check out credential: %w for %s", ErrCredentialCheckoutTimeout, targetId)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("check out credential: %w for %s", ctx.Err(), targetId)
+		case <-ticker.C:
+		}
+		if _, err := r.writer.Exec(ctx,
+			"update target_credential_checkout_waiter set last_seen_time = now() where id = ?",
+			[]interface{}{waiterId}); err != nil {
+			return nil, fmt.Errorf("check out credential: %w for %s", err, targetId)
+		}
+	}
+}
+
+// tryCheckOutCredential checks out the credential of the target if it is
+// checked in, removing a stale reservation first. A non-zero waiterId only
+// checks it out if no earlier waiter is still in line.
+func (r *Repository) tryCheckOutCredential(ctx context.Context, targetId, checkoutId, userId string, waiterId int64) (bool, error) {
+	var rowsInserted int
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			if _, err := w.Exec(ctx,
+				"delete from target_credential_checkout where target_id = ? and session_id is null and create_time < now() - interval '1 minute'",
+				[]interface{}{targetId}); err != nil {
+				return err
+			}
+			var err error
+			rowsInserted, err = w.Exec(ctx,
+				`insert into target_credential_checkout (target_id, checkout_id, user_id)
+				select ?, ?, ?
+				where not exists (
+					select
+						from target_credential_checkout_waiter
+					where
+						target_id = ? and
+						id < ? and
+						last_seen_time > now() - interval '30 seconds'
+				)
+				on conflict (target_id) do nothing`,
+				[]interface{}{targetId, checkoutId, userId, targetId, waiterId})
+			return err
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return rowsInserted == 1, nil
+}
+
+// enterCredentialCheckoutLine adds a waiter for the credential of the target
+// and returns its id.
+func (r *Repository) enterCredentialCheckoutLine(ctx context.Context, targetId, userId string) (int64, error) {
+	var waiterId int64
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(read db.Reader, _ db.Writer) error {
+			rows, err := read.Query(ctx,
+				"insert into target_credential_checkout_waiter (target_id, user_id) values ($1, $2) returning id",
+				[]interface{}{targetId, userId})
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				if err := rows.Scan(&waiterId); err != nil {
+					return err
+				}
+			}
+			return rows.Err()
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return waiterId, nil
+}
+
+// AttachCredentialCheckout attaches the checkout to the session it was made
+// for, so it is checked in when the session is terminated. It fails if the
+// checkout was checked in in the meantime, e.g. because it went stale. No
+// options are currently supported.
+func (r *Repository) AttachCredentialCheckout(ctx context.Context, checkoutId, sessionId string, opt ...Option) error {
+	if checkoutId == "" {
+		return fmt.Errorf("attach credential checkout: missing checkout id: %w", errors.ErrInvalidParameter)
+	}
+	if sessionId == "" {
+		return fmt.Errorf("attach credential checkout: missing session id: %w", errors.ErrInvalidParameter)
+	}
+	rowsUpdated, err := r.writer.Exec(ctx,
+		"update target_credential_checkout set session_id = ? where checkout_id = ? and session_id is null",
+		[]interface{}{sessionId, checkoutId})
+	if err != nil {
+		return fmt.Errorf("attach credential checkout: %w for %s", err, checkoutId)
+	}
+	if rowsUpdated != 1 {
+		return fmt.Errorf("attach credential checkout: %w for %s", errors.ErrRecordNotFound, checkoutId)
+	}
+	return nil
+}
+
+// CheckInCredential checks in the credential of a checkout which was not
+// attached to a session, e.g. because creating the session failed. Checkouts
+// attached to sessions are checked in when their session is terminated. No
+// options are currently supported.
+func (r *Repository) CheckInCredential(ctx context.Context, checkoutId string, opt ...Option) error {
+	if checkoutId == "" {
+		return fmt.Errorf("check in credential: missing checkout id: %w", errors.ErrInvalidParameter)
+	}
+	if _, err := r.writer.Exec(ctx,
+		"delete from target_credential_checkout where checkout_id = ? and session_id is null",
+		[]interface{}{checkoutId}); err != nil {
+		return fmt.Errorf("check in credential: %w for %s", err, checkoutId)
+	}
+	return nil
+}
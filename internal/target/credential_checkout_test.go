@@ -0,0 +1,134 @@
+package target
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_CredentialCheckout(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, proj := iam.TestScopes(t, iamRepo)
+	user := iam.TestUser(t, iamRepo, org.PublicId)
+	other := iam.TestUser(t, iamRepo, org.PublicId)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("set-and-lookup-policy", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "set-and-lookup-policy")
+
+		p, err := repo.LookupCredentialCheckoutPolicy(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Nil(p)
+
+		require.NoError(repo.SetCredentialCheckoutPolicy(ctx, tar.PublicId, CredentialCheckoutQueue, 30, true))
+		p, err = repo.LookupCredentialCheckoutPolicy(ctx, tar.PublicId)
+		require.NoError(err)
+		require.NotNil(p)
+		assert.Equal(CredentialCheckoutQueue, p.Mode)
+		assert.Equal(uint32(30), p.QueueTimeoutSeconds)
+		assert.True(p.RotateOnCheckIn)
+
+		require.NoError(repo.SetCredentialCheckoutPolicy(ctx, tar.PublicId, CredentialCheckoutReject, 0, false))
+		p, err = repo.LookupCredentialCheckoutPolicy(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(CredentialCheckoutReject, p.Mode)
+		assert.False(p.RotateOnCheckIn)
+
+		require.NoError(repo.SetCredentialCheckoutPolicy(ctx, tar.PublicId, "", 0, false))
+		p, err = repo.LookupCredentialCheckoutPolicy(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Nil(p)
+
+		err = repo.SetCredentialCheckoutPolicy(ctx, tar.PublicId, "lottery", 0, false)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+	t.Run("no-policy", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "no-policy")
+		c, err := repo.CheckOutCredential(ctx, tar.PublicId, user.PublicId)
+		require.NoError(err)
+		assert.Nil(c)
+	})
+	t.Run("reject", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "reject")
+		require.NoError(repo.SetCredentialCheckoutPolicy(ctx, tar.PublicId, CredentialCheckoutReject, 0, false))
+
+		c, err := repo.CheckOutCredential(ctx, tar.PublicId, user.PublicId)
+		require.NoError(err)
+		require.NotNil(c)
+		assert.Equal(user.PublicId, c.UserId)
+		assert.Empty(c.SessionId)
+
+		_, err = repo.CheckOutCredential(ctx, tar.PublicId, other.PublicId)
+		assert.True(errors.Is(err, ErrCredentialCheckedOut))
+
+		require.NoError(repo.CheckInCredential(ctx, c.CheckoutId))
+		got, err := repo.LookupCredentialCheckout(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Nil(got)
+		c, err = repo.CheckOutCredential(ctx, tar.PublicId, other.PublicId)
+		require.NoError(err)
+		assert.Equal(other.PublicId, c.UserId)
+	})
+	t.Run("queue", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		credentialCheckoutPollInterval = 50 * time.Millisecond
+		tar := TestTcpTarget(t, conn, proj.PublicId, "queue")
+		require.NoError(repo.SetCredentialCheckoutPolicy(ctx, tar.PublicId, CredentialCheckoutQueue, 1, false))
+
+		c, err := repo.CheckOutCredential(ctx, tar.PublicId, user.PublicId)
+		require.NoError(err)
+
+		start := time.Now()
+		_, err = repo.CheckOutCredential(ctx, tar.PublicId, other.PublicId)
+		assert.True(errors.Is(err, ErrCredentialCheckoutTimeout))
+		assert.True(time.Since(start) >= time.Second)
+
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			_ = repo.CheckInCredential(ctx, c.CheckoutId)
+		}()
+		c, err = repo.CheckOutCredential(ctx, tar.PublicId, other.PublicId)
+		require.NoError(err)
+		assert.Equal(other.PublicId, c.UserId)
+
+		// The line is empty again
+		rows, err := rw.Query(ctx, "select count(*) from target_credential_checkout_waiter where target_id = $1", []interface{}{tar.PublicId})
+		require.NoError(err)
+		defer rows.Close()
+		var n int
+		for rows.Next() {
+			require.NoError(rows.Scan(&n))
+		}
+		assert.Zero(n)
+	})
+	t.Run("attach-missing-checkout", func(t *testing.T) {
+		err := repo.AttachCredentialCheckout(ctx, "missing", "s_1234567890")
+		assert.True(t, errors.Is(err, errors.ErrRecordNotFound))
+	})
+	t.Run("invalid-parameters", func(t *testing.T) {
+		assert := assert.New(t)
+		_, err := repo.CheckOutCredential(ctx, "", user.PublicId)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, err = repo.CheckOutCredential(ctx, "ttcp_1234567890", "")
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		assert.True(errors.Is(repo.AttachCredentialCheckout(ctx, "", "s_1234567890"), errors.ErrInvalidParameter))
+		assert.True(errors.Is(repo.CheckInCredential(ctx, ""), errors.ErrInvalidParameter))
+	})
+}
@@ -0,0 +1,15 @@
+package target
+
+import (
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/target/store"
+)
+
+// HistoryTypes returns the type catalog of the messages written to the oplog
+// entries of targets, for reading their history.
+func HistoryTypes() (*oplog.TypeCatalog, error) {
+	return oplog.NewTypeCatalog(
+		oplog.Type{Interface: new(store.TcpTarget), Name: DefaultTcpTableName},
+		oplog.Type{Interface: new(store.TargetHostSet), Name: DefaultTargetHostSetTableName},
+	)
+}
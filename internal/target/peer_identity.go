@@ -0,0 +1,78 @@
+package target
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+const defaultPeerIdentityTableName = "target_peer_identity"
+
+// PeerIdentityProxyV2 is the mode of a peer identity setting where workers
+// send a PROXY protocol version 2 header with the identity of the user of the
+// session to the endpoint before proxying each connection. The endpoint must
+// expect the header.
+const PeerIdentityProxyV2 = "proxy_v2"
+
+// A PeerIdentity is how workers pass the identity of the user of a session to
+// the endpoints of a target, so actions taken with a shared credential can be
+// attributed to the user in the logs of the endpoint.
+type PeerIdentity struct {
+	TargetId string `gorm:"primary_key"`
+	// Mode is PeerIdentityProxyV2, or empty if the identity is not passed.
+	Mode       string
+	CreateTime *timestamp.Timestamp `gorm:"default:current_timestamp"`
+	UpdateTime *timestamp.Timestamp `gorm:"default:current_timestamp"`
+}
+
+// TableName returns the table name for the peer identity setting.
+func (p *PeerIdentity) TableName() string {
+	return defaultPeerIdentityTableName
+}
+
+// SetPeerIdentity sets how workers pass the identity of the user of a session
+// to the endpoints of the target. An empty mode stops passing it. No options
+// are currently supported.
+func (r *Repository) SetPeerIdentity(ctx context.Context, targetId, mode string, opt ...Option) error {
+	if targetId == "" {
+		return fmt.Errorf("set peer identity: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	var err error
+	switch mode {
+	case "":
+		_, err = r.writer.Exec(ctx,
+			"delete from target_peer_identity where target_id = ?",
+			[]interface{}{targetId})
+	case PeerIdentityProxyV2:
+		_, err = r.writer.Exec(ctx,
+			`insert into target_peer_identity (target_id, mode) values (?, ?)
+			on conflict (target_id) do update set
+				mode = excluded.mode`,
+			[]interface{}{targetId, mode})
+	default:
+		return fmt.Errorf("set peer identity: unknown mode %q: %w", mode, errors.ErrInvalidParameter)
+	}
+	if err != nil {
+		return fmt.Errorf("set peer identity: %w for %s", err, targetId)
+	}
+	return nil
+}
+
+// LookupPeerIdentity returns the peer identity setting of the target. A
+// target which does not pass the identity returns a PeerIdentity with an
+// empty mode. No options are currently supported.
+func (r *Repository) LookupPeerIdentity(ctx context.Context, targetId string, opt ...Option) (*PeerIdentity, error) {
+	if targetId == "" {
+		return nil, fmt.Errorf("lookup peer identity: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	p := &PeerIdentity{}
+	if err := r.reader.LookupWhere(ctx, p, "target_id = ?", targetId); err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return &PeerIdentity{TargetId: targetId}, nil
+		}
+		return nil, fmt.Errorf("lookup peer identity: %w for %s", err, targetId)
+	}
+	return p, nil
+}
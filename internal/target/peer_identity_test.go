@@ -0,0 +1,78 @@
+package target
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_PeerIdentity(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, proj := iam.TestScopes(t, iamRepo)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("set-and-lookup", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "set-and-lookup")
+
+		p, err := repo.LookupPeerIdentity(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(tar.PublicId, p.TargetId)
+		assert.Empty(p.Mode)
+
+		require.NoError(repo.SetPeerIdentity(ctx, tar.PublicId, PeerIdentityProxyV2))
+		p, err = repo.LookupPeerIdentity(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(PeerIdentityProxyV2, p.Mode)
+		assert.NotNil(p.CreateTime)
+
+		// Setting it again is not an error
+		require.NoError(repo.SetPeerIdentity(ctx, tar.PublicId, PeerIdentityProxyV2))
+
+		require.NoError(repo.SetPeerIdentity(ctx, tar.PublicId, ""))
+		p, err = repo.LookupPeerIdentity(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Empty(p.Mode)
+		assert.Nil(p.CreateTime)
+	})
+	t.Run("deleted-with-target", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "deleted-with-target")
+		require.NoError(repo.SetPeerIdentity(ctx, tar.PublicId, PeerIdentityProxyV2))
+
+		rowsDeleted, err := repo.DeleteTarget(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(1, rowsDeleted)
+		p, err := repo.LookupPeerIdentity(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Empty(p.Mode)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "invalid")
+		require.Error(repo.SetPeerIdentity(ctx, "ttcp_1234567890", PeerIdentityProxyV2))
+
+		err := repo.SetPeerIdentity(ctx, tar.PublicId, "ssh_env")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		err = repo.SetPeerIdentity(ctx, "", PeerIdentityProxyV2)
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, err = repo.LookupPeerIdentity(ctx, "")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	stderrors "errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/boundary/internal/db"
@@ -149,9 +150,52 @@ func fetchSets(ctx context.Context, r db.Reader, targetId string) ([]*TargetSet,
 
 // ListTargets in targets in a scope.  Supports the WithScopeId, WithLimit, WithTargetType options.
 func (r *Repository) ListTargets(ctx context.Context, opt ...Option) ([]Target, error) {
+	targets, _, err := r.listTargets(ctx, false, opt...)
+	if err != nil {
+		return nil, fmt.Errorf("list targets: %w", err)
+	}
+	return targets, nil
+}
+
+// ListTargetsWithHostSets lists targets in the same way as ListTargets and
+// also returns the host sets of the targets, keyed by target id. The host sets
+// of all the listed targets are loaded with a single additional query.
+// Supports the WithScopeId, WithLimit, WithTargetType options.
+func (r *Repository) ListTargetsWithHostSets(ctx context.Context, opt ...Option) ([]Target, map[string][]*TargetSet, error) {
+	targets, hostSets, err := r.listTargets(ctx, true, opt...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list targets with host sets: %w", err)
+	}
+	return targets, hostSets, nil
+}
+
+// LookupTargets returns the targets with the ids, in the order of the ids,
+// and their host sets keyed by target id, with one query for the targets and
+// one for their host sets. Ids of targets which don't exist are skipped.
+func (r *Repository) LookupTargets(ctx context.Context, ids []string) ([]Target, map[string][]*TargetSet, error) {
+	if len(ids) == 0 {
+		return nil, nil, fmt.Errorf("lookup targets: missing ids: %w", errors.ErrInvalidParameter)
+	}
+	var foundTargets []*targetView
+	var foundSets []*targetSetView
+	if err := r.reader.LookupByPublicIds(ctx, &foundTargets, ids, db.WithPreload(&foundSets, "target_id", "PublicId")); err != nil {
+		return nil, nil, fmt.Errorf("lookup targets: %w", err)
+	}
+	targets := make([]Target, 0, len(foundTargets))
+	for _, t := range foundTargets {
+		subType, err := t.targetSubType()
+		if err != nil {
+			return nil, nil, fmt.Errorf("lookup targets: %w", err)
+		}
+		targets = append(targets, subType)
+	}
+	return targets, groupTargetSets(foundSets), nil
+}
+
+func (r *Repository) listTargets(ctx context.Context, withHostSets bool, opt ...Option) ([]Target, map[string][]*TargetSet, error) {
 	opts := getOpts(opt...)
 	if opts.withScopeId == "" && opts.withUserId == "" {
-		return nil, fmt.Errorf("list targets: must specify either a scope id or user id: %w", errors.ErrInvalidParameter)
+		return nil, nil, fmt.Errorf("must specify either a scope id or user id: %w", errors.ErrInvalidParameter)
 	}
 	// TODO (jimlambrt 8/2020) - implement WithUserId() optional filtering.
 	var where []string
@@ -163,10 +207,16 @@ func (r *Repository) ListTargets(ctx context.Context, opt ...Option) ([]Target,
 		where, args = append(where, "type = ?"), append(args, opts.withTargetType.String())
 	}
 
+	var dbOpts []db.Option
+	var foundSets []*targetSetView
+	if withHostSets {
+		dbOpts = append(dbOpts, db.WithPreload(&foundSets, "target_id", "PublicId"))
+	}
+
 	var foundTargets []*targetView
-	err := r.list(ctx, &foundTargets, strings.Join(where, " and "), args, opt...)
+	err := r.list(ctx, &foundTargets, strings.Join(where, " and "), args, opt, dbOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("list targets: %w", err)
+		return nil, nil, err
 	}
 
 	targets := make([]Target, 0, len(foundTargets))
@@ -174,19 +224,37 @@ func (r *Repository) ListTargets(ctx context.Context, opt ...Option) ([]Target,
 	for _, t := range foundTargets {
 		subType, err := t.targetSubType()
 		if err != nil {
-			return nil, fmt.Errorf("list targets: %w", err)
+			return nil, nil, err
 		}
 		targets = append(targets, subType)
 	}
-	return targets, nil
+	if !withHostSets {
+		return targets, nil, nil
+	}
+
+	return targets, groupTargetSets(foundSets), nil
+}
+
+// groupTargetSets returns the host sets keyed by target id, each sorted by
+// public id.
+func groupTargetSets(foundSets []*targetSetView) map[string][]*TargetSet {
+	hostSets := make(map[string][]*TargetSet)
+	for _, s := range foundSets {
+		hostSets[s.TargetId] = append(hostSets[s.TargetId], &TargetSet{Set: s.Set})
+	}
+	for _, sets := range hostSets {
+		sort.Slice(sets, func(i, j int) bool {
+			return sets[i].GetPublicId() < sets[j].GetPublicId()
+		})
+	}
+	return hostSets
 }
 
 // list will return a listing of resources and honor the WithLimit option or the
-// repo defaultLimit
-func (r *Repository) list(ctx context.Context, resources interface{}, where string, args []interface{}, opt ...Option) error {
+// repo defaultLimit. Any dbOpts are passed to the search.
+func (r *Repository) list(ctx context.Context, resources interface{}, where string, args []interface{}, opt []Option, dbOpts ...db.Option) error {
 	opts := getOpts(opt...)
 	limit := r.defaultLimit
-	var dbOpts []db.Option
 	if opts.withLimit != 0 {
 		// non-zero signals an override of the default limit for the repo.
 		limit = opts.withLimit
@@ -13,8 +13,16 @@ import (
 	"github.com/hashicorp/boundary/internal/oplog"
 )
 
+const projectTargetDefaultsQuery = `
+select coalesce(default_port, 0),
+       coalesce(session_max_seconds, 0),
+       coalesce(session_connection_limit, 0)
+  from iam_scope_project_target_default
+ where project_id = $1`
+
 // CreateTcpTarget inserts into the repository and returns the new Target with
-// its list of host sets.  WithHostSets is currently the only supported option.
+// its list of host sets. Settings left unset take the target defaults of the
+// project, if any.  WithHostSets is currently the only supported option.
 func (r *Repository) CreateTcpTarget(ctx context.Context, target *TcpTarget, opt ...Option) (Target, []*TargetSet, error) {
 	opts := getOpts(opt...)
 	if target == nil {
@@ -75,6 +83,9 @@ func (r *Repository) CreateTcpTarget(ctx context.Context, target *TcpTarget, opt
 			if err != nil {
 				return fmt.Errorf("create tcp target: unable to get ticket: %w", err)
 			}
+			if err := applyProjectTargetDefaults(ctx, read, t); err != nil {
+				return fmt.Errorf("create tcp target: %w", err)
+			}
 			msgs := make([]*oplog.Message, 0, 2)
 			var targetOplogMsg oplog.Message
 			returnedTarget = t.Clone()
@@ -105,6 +116,37 @@ func (r *Repository) CreateTcpTarget(ctx context.Context, target *TcpTarget, opt
 	return returnedTarget.(*TcpTarget), returnedHostSet, err
 }
 
+// applyProjectTargetDefaults sets the settings of the target left unset to
+// the target defaults of its project, copied from the project template of its
+// org when the project was created.
+func applyProjectTargetDefaults(ctx context.Context, r db.Reader, t *TcpTarget) error {
+	if t.DefaultPort != 0 && t.SessionMaxSeconds != 0 && t.SessionConnectionLimit != 0 {
+		return nil
+	}
+	rows, err := r.Query(ctx, projectTargetDefaultsQuery, []interface{}{t.ScopeId})
+	if err != nil {
+		return fmt.Errorf("unable to look up project target defaults: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var defaultPort, sessionMaxSeconds uint32
+		var sessionConnectionLimit int32
+		if err := rows.Scan(&defaultPort, &sessionMaxSeconds, &sessionConnectionLimit); err != nil {
+			return fmt.Errorf("unable to scan project target defaults: %w", err)
+		}
+		if t.DefaultPort == 0 {
+			t.DefaultPort = defaultPort
+		}
+		if t.SessionMaxSeconds == 0 {
+			t.SessionMaxSeconds = sessionMaxSeconds
+		}
+		if t.SessionConnectionLimit == 0 {
+			t.SessionConnectionLimit = sessionConnectionLimit
+		}
+	}
+	return rows.Err()
+}
+
 // UpdateTcpTarget will update a target in the repository and return the written
 // target. fieldMaskPaths provides field_mask.proto paths for fields that should
 // be updated.  Fields will be set to NULL if the field is a zero value and
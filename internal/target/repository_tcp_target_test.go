@@ -149,6 +149,70 @@ func TestRepository_CreateTcpTarget(t *testing.T) {
 	}
 }
 
+func TestRepository_CreateTcpTarget_ProjectDefaults(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	ctx := context.Background()
+
+	org := iam.TestOrg(t, iamRepo)
+	require.NoError(t, iamRepo.SetProjectTemplate(ctx, org.PublicId, &iam.ProjectTemplate{
+		OrgId:                        org.PublicId,
+		TargetDefaultPort:            22,
+		TargetSessionMaxSeconds:      3600,
+		TargetSessionConnectionLimit: -1,
+	}))
+	p, err := iam.NewProject(org.PublicId)
+	require.NoError(t, err)
+	proj, err := iamRepo.CreateScope(ctx, p, "")
+	require.NoError(t, err)
+
+	t.Run("defaults", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		target, err := NewTcpTarget(proj.PublicId, WithName("defaults"))
+		require.NoError(err)
+		created, _, err := repo.CreateTcpTarget(ctx, target)
+		require.NoError(err)
+		found, _, err := repo.LookupTarget(ctx, created.GetPublicId())
+		require.NoError(err)
+		assert.Equal(uint32(22), found.GetDefaultPort())
+		assert.Equal(uint32(3600), found.GetSessionMaxSeconds())
+		assert.Equal(int32(-1), found.GetSessionConnectionLimit())
+	})
+
+	t.Run("set", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		target, err := NewTcpTarget(proj.PublicId, WithName("set"), WithDefaultPort(80), WithSessionMaxSeconds(60), WithSessionConnectionLimit(5))
+		require.NoError(err)
+		created, _, err := repo.CreateTcpTarget(ctx, target)
+		require.NoError(err)
+		found, _, err := repo.LookupTarget(ctx, created.GetPublicId())
+		require.NoError(err)
+		assert.Equal(uint32(80), found.GetDefaultPort())
+		assert.Equal(uint32(60), found.GetSessionMaxSeconds())
+		assert.Equal(int32(5), found.GetSessionConnectionLimit())
+	})
+
+	t.Run("no-template", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, other := iam.TestScopes(t, iamRepo)
+		target, err := NewTcpTarget(other.PublicId, WithName("no-template"))
+		require.NoError(err)
+		created, _, err := repo.CreateTcpTarget(ctx, target)
+		require.NoError(err)
+		found, _, err := repo.LookupTarget(ctx, created.GetPublicId())
+		require.NoError(err)
+		assert.Zero(found.GetDefaultPort())
+		assert.Equal(uint32(28800), found.GetSessionMaxSeconds())
+		assert.Equal(int32(1), found.GetSessionConnectionLimit())
+	})
+}
+
 func TestRepository_UpdateTcpTarget(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
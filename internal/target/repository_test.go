@@ -318,6 +318,85 @@ func TestRepository_ListTargets(t *testing.T) {
 	}
 }
 
+func TestRepository_ListTargetsWithHostSets(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, proj := iam.TestScopes(t, iamRepo)
+	rw := db.New(conn)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(err)
+
+	cats := static.TestCatalogs(t, conn, proj.PublicId, 1)
+	hsets := static.TestSets(t, conn, cats[0].PublicId, 3)
+	wantSetIds := make([]string, 0, len(hsets))
+	for _, hs := range hsets {
+		wantSetIds = append(wantSetIds, hs.PublicId)
+	}
+	sort.Strings(wantSetIds)
+
+	withSets := TestTcpTarget(t, conn, proj.PublicId, "with-sets", WithHostSets(wantSetIds))
+	withOneSet := TestTcpTarget(t, conn, proj.PublicId, "with-one-set", WithHostSets(wantSetIds[:1]))
+	withoutSets := TestTcpTarget(t, conn, proj.PublicId, "without-sets")
+
+	got, gotSets, err := repo.ListTargetsWithHostSets(context.Background(), WithScopeId(proj.PublicId))
+	require.NoError(err)
+	assert.Len(got, 3)
+
+	setIds := func(sets []*TargetSet) []string {
+		var ids []string
+		for _, s := range sets {
+			ids = append(ids, s.PublicId)
+		}
+		return ids
+	}
+	assert.Equal(wantSetIds, setIds(gotSets[withSets.PublicId]))
+	assert.Equal(wantSetIds[:1], setIds(gotSets[withOneSet.PublicId]))
+	assert.Empty(gotSets[withoutSets.PublicId])
+
+	_, _, err = repo.ListTargetsWithHostSets(context.Background())
+	require.Error(err)
+}
+
+func TestRepository_LookupTargets(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, proj := iam.TestScopes(t, iamRepo)
+	rw := db.New(conn)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(err)
+
+	cats := static.TestCatalogs(t, conn, proj.PublicId, 1)
+	hsets := static.TestSets(t, conn, cats[0].PublicId, 2)
+	wantSetIds := []string{hsets[0].PublicId, hsets[1].PublicId}
+	sort.Strings(wantSetIds)
+
+	t1 := TestTcpTarget(t, conn, proj.PublicId, "target-1", WithHostSets(wantSetIds))
+	t2 := TestTcpTarget(t, conn, proj.PublicId, "target-2")
+	t3 := TestTcpTarget(t, conn, proj.PublicId, "target-3")
+
+	got, gotSets, err := repo.LookupTargets(context.Background(), []string{t3.PublicId, "ttcp_missing", t1.PublicId, t2.PublicId})
+	require.NoError(err)
+	require.Len(got, 3)
+	assert.Equal(t3.PublicId, got[0].GetPublicId())
+	assert.Equal(t1.PublicId, got[1].GetPublicId())
+	assert.Equal(t2.PublicId, got[2].GetPublicId())
+	require.Len(gotSets[t1.PublicId], 2)
+	assert.Equal(wantSetIds[0], gotSets[t1.PublicId][0].PublicId)
+	assert.Equal(wantSetIds[1], gotSets[t1.PublicId][1].PublicId)
+	assert.Empty(gotSets[t2.PublicId])
+
+	_, _, err = repo.LookupTargets(context.Background(), nil)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+}
+
 func TestRepository_DeleteTarget(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -103,3 +103,16 @@ type TargetSet struct {
 func (ts *TargetSet) TableName() string {
 	return "target_set"
 }
+
+// targetSetView is a row of the target_set view, which includes the id of the
+// target the host set belongs to so host sets for many targets can be loaded
+// at once.
+type targetSetView struct {
+	*hostStore.Set
+	TargetId string
+}
+
+// TableName returns the tablename to override the default gorm table name
+func (ts *targetSetView) TableName() string {
+	return "target_set"
+}
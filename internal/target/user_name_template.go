@@ -0,0 +1,146 @@
+package target
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+const defaultUserNameTemplateTableName = "target_user_name_template"
+
+// maxInjectedUserNameLength is the maximum length of the user names produced
+// by user name templates.
+const maxInjectedUserNameLength = 256
+
+// A UserNameTemplate is the template of the user name protocol handlers
+// inject into the connections of the sessions of a target, such as
+// "{{user}}_admin" or "{{user | lower}}@corp", so a shared target can map each
+// user to their own account on its endpoints. Templates use the text/template
+// syntax with the functions:
+//
+//	user        the name of the user
+//	user_id     the id of the user
+//	lower       the lowercase of its argument
+//	upper       the uppercase of its argument
+//	trim_prefix its second argument without the prefix of its first
+//	trim_suffix its second argument without the suffix of its first
+//	replace     its third argument with the first replaced by the second
+type UserNameTemplate struct {
+	TargetId   string `gorm:"primary_key"`
+	Template   string
+	CreateTime *timestamp.Timestamp `gorm:"default:current_timestamp"`
+	UpdateTime *timestamp.Timestamp `gorm:"default:current_timestamp"`
+}
+
+// TableName returns the table name for the user name template.
+func (t *UserNameTemplate) TableName() string {
+	return defaultUserNameTemplateTableName
+}
+
+// parseUserNameTemplate parses the template with the functions returning the
+// name and id of the user. The formatting builtins are disabled, since their
+// widths could make a template allocate without limit.
+func parseUserNameTemplate(tmpl, userId, userName string) (*template.Template, error) {
+	disabled := func(...interface{}) (string, error) {
+		return "", stderrors.New("function not available in user name templates")
+	}
+	return template.New("user_name").Funcs(template.FuncMap{
+		"user":        func() string { return userName },
+		"user_id":     func() string { return userId },
+		"lower":       strings.ToLower,
+		"upper":       strings.ToUpper,
+		"trim_prefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trim_suffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":     func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"printf":      disabled,
+		"print":       disabled,
+		"println":     disabled,
+	}).Parse(tmpl)
+}
+
+// InjectedUserName returns the user name to inject for the user produced by
+// the template, or the name of the user if the template is empty. It returns
+// an error if the user name is empty, too long or contains control or space
+// characters, so a handler never authenticates as an unexpected account.
+func InjectedUserName(tmpl, userId, userName string) (string, error) {
+	name := userName
+	if tmpl != "" {
+		t, err := parseUserNameTemplate(tmpl, userId, userName)
+		if err != nil {
+			return "", fmt.Errorf("injected user name: %w", err)
+		}
+		var b strings.Builder
+		if err := t.Execute(&b, nil); err != nil {
+			return "", fmt.Errorf("injected user name: %w", err)
+		}
+		name = b.String()
+	}
+	switch {
+	case name == "":
+		return "", fmt.Errorf("injected user name: empty user name for user %s", userId)
+	case len(name) > maxInjectedUserNameLength:
+		return "", fmt.Errorf("injected user name: user name of user %s longer than %d bytes", userId, maxInjectedUserNameLength)
+	case strings.IndexFunc(name, func(r rune) bool { return unicode.IsControl(r) || unicode.IsSpace(r) }) >= 0:
+		return "", fmt.Errorf("injected user name: user name of user %s contains control or space characters", userId)
+	}
+	return name, nil
+}
+
+// CheckUserNameTemplate returns an error if the template can't produce a
+// valid user name for a sample user.
+func CheckUserNameTemplate(tmpl string) error {
+	_, err := InjectedUserName(tmpl, "u_1234567890", "user")
+	return err
+}
+
+// SetUserNameTemplate sets the template of the user name injected into the
+// connections of the sessions of the target. An empty template injects the
+// name of the user. The template is checked with CheckUserNameTemplate. No
+// options are currently supported.
+func (r *Repository) SetUserNameTemplate(ctx context.Context, targetId, tmpl string, opt ...Option) error {
+	if targetId == "" {
+		return fmt.Errorf("set user name template: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	if tmpl == "" {
+		if _, err := r.writer.Exec(ctx,
+			"delete from target_user_name_template where target_id = ?",
+			[]interface{}{targetId}); err != nil {
+			return fmt.Errorf("set user name template: %w for %s", err, targetId)
+		}
+		return nil
+	}
+	if err := CheckUserNameTemplate(tmpl); err != nil {
+		return fmt.Errorf("set user name template: %s: %w", err, errors.ErrInvalidParameter)
+	}
+	if _, err := r.writer.Exec(ctx,
+		`insert into target_user_name_template (target_id, template) values (?, ?)
+		on conflict (target_id) do update set
+			template = excluded.template`,
+		[]interface{}{targetId, tmpl}); err != nil {
+		return fmt.Errorf("set user name template: %w for %s", err, targetId)
+	}
+	return nil
+}
+
+// LookupUserNameTemplate returns the user name template of the target. A
+// target which injects the name of the user returns a UserNameTemplate with an
+// empty template. No options are currently supported.
+func (r *Repository) LookupUserNameTemplate(ctx context.Context, targetId string, opt ...Option) (*UserNameTemplate, error) {
+	if targetId == "" {
+		return nil, fmt.Errorf("lookup user name template: missing target id: %w", errors.ErrInvalidParameter)
+	}
+	t := &UserNameTemplate{}
+	if err := r.reader.LookupWhere(ctx, t, "target_id = ?", targetId); err != nil {
+		if errors.Is(err, errors.ErrRecordNotFound) {
+			return &UserNameTemplate{TargetId: targetId}, nil
+		}
+		return nil, fmt.Errorf("lookup user name template: %w for %s", err, targetId)
+	}
+	return t, nil
+}
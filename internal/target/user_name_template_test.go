@@ -0,0 +1,123 @@
+package target
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectedUserName(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		template string
+		userName string
+		want     string
+		wantErr  bool
+	}{
+		{name: "no-template", userName: "alice", want: "alice"},
+		{name: "suffix", template: "{{user}}_admin", userName: "alice", want: "alice_admin"},
+		{name: "domain", template: "{{user | lower}}@corp", userName: "Alice", want: "alice@corp"},
+		{name: "user-id", template: "svc-{{user_id}}", userName: "alice", want: "svc-u_1234567890"},
+		{name: "trim-suffix", template: `{{user | trim_suffix "@example.com"}}`, userName: "alice@example.com", want: "alice"},
+		{name: "replace", template: `{{user | replace "." "_" | upper}}`, userName: "alice.smith", want: "ALICE_SMITH"},
+		{name: "no-user-name", template: "{{user}}", wantErr: true},
+		{name: "no-template-no-user-name", wantErr: true},
+		{name: "space", template: "{{user}} admin", userName: "alice", wantErr: true},
+		{name: "newline", userName: "alice\nbob", wantErr: true},
+		{name: "too-long", userName: strings.Repeat("a", maxInjectedUserNameLength+1), wantErr: true},
+		{name: "unknown-function", template: "{{env}}", userName: "alice", wantErr: true},
+		{name: "printf", template: `{{printf "%0999999999d" 1}}`, userName: "alice", wantErr: true},
+		{name: "invalid", template: "{{user", userName: "alice", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			got, err := InjectedUserName(tt.template, "u_1234567890", tt.userName)
+			if tt.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestRepository_UserNameTemplate(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, proj := iam.TestScopes(t, iamRepo)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("set-and-lookup", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "set-and-lookup")
+
+		ut, err := repo.LookupUserNameTemplate(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(tar.PublicId, ut.TargetId)
+		assert.Empty(ut.Template)
+
+		require.NoError(repo.SetUserNameTemplate(ctx, tar.PublicId, "{{user}}_admin"))
+		ut, err = repo.LookupUserNameTemplate(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal("{{user}}_admin", ut.Template)
+		assert.NotNil(ut.CreateTime)
+
+		require.NoError(repo.SetUserNameTemplate(ctx, tar.PublicId, "{{user}}@corp"))
+		ut, err = repo.LookupUserNameTemplate(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal("{{user}}@corp", ut.Template)
+
+		require.NoError(repo.SetUserNameTemplate(ctx, tar.PublicId, ""))
+		ut, err = repo.LookupUserNameTemplate(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Empty(ut.Template)
+		assert.Nil(ut.CreateTime)
+	})
+	t.Run("deleted-with-target", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "deleted-with-target")
+		require.NoError(repo.SetUserNameTemplate(ctx, tar.PublicId, "{{user}}_admin"))
+
+		rowsDeleted, err := repo.DeleteTarget(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Equal(1, rowsDeleted)
+		ut, err := repo.LookupUserNameTemplate(ctx, tar.PublicId)
+		require.NoError(err)
+		assert.Empty(ut.Template)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		tar := TestTcpTarget(t, conn, proj.PublicId, "invalid")
+		require.Error(repo.SetUserNameTemplate(ctx, "ttcp_1234567890", "{{user}}"))
+
+		err := repo.SetUserNameTemplate(ctx, tar.PublicId, "{{user")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		err = repo.SetUserNameTemplate(ctx, tar.PublicId, "{{user}} admin")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		err = repo.SetUserNameTemplate(ctx, "", "{{user}}")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+		_, err = repo.LookupUserNameTemplate(ctx, "")
+		require.Error(err)
+		assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	})
+}
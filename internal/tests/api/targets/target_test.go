@@ -112,6 +112,42 @@ func comparableSlice(in []*targets.Target) []targets.Target {
 	return filtered
 }
 
+func TestBatchGet(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	tc := controller.NewTestController(t, nil)
+	defer tc.Shutdown()
+
+	client := tc.Client()
+	token := tc.Token()
+	client.SetToken(token.Token)
+	_, proj := iam.TestScopes(t, tc.IamRepo(), iam.WithUserId(token.UserId))
+	_, otherProj := iam.TestScopes(t, tc.IamRepo())
+
+	tarClient := targets.NewClient(client)
+	var ids []string
+	for i := 0; i < 3; i++ {
+		tar, err := tarClient.Create(tc.Context(), "tcp", proj.GetPublicId(), targets.WithName(fmt.Sprint(i)))
+		require.NoError(err)
+		ids = append(ids, tar.Item.Id)
+	}
+	// The targets the caller may not read are omitted
+	other := target.TestTcpTarget(t, tc.DbConn(), otherProj.GetPublicId(), "other")
+
+	got, err := tarClient.BatchGet(tc.Context(), []string{ids[2], other.GetPublicId(), ids[0], ids[1]})
+	require.NoError(err)
+	require.Len(got.Items, 3)
+	assert.Equal(ids[2], got.Items[0].Id)
+	assert.Equal(ids[0], got.Items[1].Id)
+	assert.Equal(ids[1], got.Items[2].Id)
+	assert.Equal("2", got.Items[0].Name)
+
+	_, err = tarClient.BatchGet(tc.Context(), []string{"j_1234567890"})
+	require.Error(err)
+	apiErr := api.AsServerError(err)
+	require.NotNil(apiErr)
+	assert.EqualValues(http.StatusBadRequest, apiErr.ResponseStatus())
+}
+
 func TestCrud(t *testing.T) {
 	assert, require := assert.New(t), require.New(t)
 	tc := controller.NewTestController(t, nil)
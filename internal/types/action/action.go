@@ -37,8 +37,21 @@ const (
 	AddAccounts      Type = 28
 	SetAccounts      Type = 29
 	RemoveAccounts   Type = 30
+	TestConnection   Type = 31
+	RequestAccess    Type = 32
+	Approve          Type = 33
+	Deny             Type = 34
+	ReadSelf         Type = 35
+	CancelSelf       Type = 36
 )
 
+// SelfParent maps the self variants of actions, which are allowed on the
+// caller's own resources only, to the actions allowing them on any resource.
+var SelfParent = map[Type]Type{
+	ReadSelf:   Read,
+	CancelSelf: Cancel,
+}
+
 var Map = map[string]Type{
 	Create.String():           Create,
 	List.String():             List,
@@ -70,6 +83,12 @@ var Map = map[string]Type{
 	AddAccounts.String():      AddAccounts,
 	SetAccounts.String():      SetAccounts,
 	RemoveAccounts.String():   RemoveAccounts,
+	TestConnection.String():   TestConnection,
+	RequestAccess.String():    RequestAccess,
+	Approve.String():          Approve,
+	Deny.String():             Deny,
+	ReadSelf.String():         ReadSelf,
+	CancelSelf.String():       CancelSelf,
 }
 
 func (a Type) String() string {
@@ -105,5 +124,11 @@ func (a Type) String() string {
 		"add-accounts",
 		"set-accounts",
 		"remove-accounts",
+		"test-connection",
+		"request-access",
+		"approve",
+		"deny",
+		"read:self",
+		"cancel:self",
 	}[a]
 }
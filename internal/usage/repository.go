@@ -0,0 +1,160 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// dateFormat is the format of the days of usage passed to the queries.
+const dateFormat = "2006-01-02"
+
+const (
+	// recordApiCallsQuery adds API calls to the usage of a scope on the
+	// current UTC day.
+	recordApiCallsQuery = `
+	insert into scope_usage_daily (usage_date, scope_id, api_calls)
+	values ((now() at time zone 'utc')::date, ?, ?)
+	on conflict (usage_date, scope_id) do update
+		set api_calls = scope_usage_daily.api_calls + excluded.api_calls`
+
+	// rollupSessionsQuery sets the session seconds of the current and
+	// previous UTC days to the time sessions were active on them, by the
+	// project of the session. The previous day is rolled up again so the
+	// sessions active at midnight are fully counted. Session seconds never
+	// decrease, so the usage of deleted sessions is kept.
+	rollupSessionsQuery = `
+	with days as (
+		select d::date as usage_date,
+			d at time zone 'utc' as day_start,
+			(d + interval '1 day') at time zone 'utc' as day_end
+			from generate_series(
+				date_trunc('day', now() at time zone 'utc') - interval '1 day',
+				date_trunc('day', now() at time zone 'utc'),
+				interval '1 day') d
+	),
+	active as (
+		select days.usage_date, s.scope_id,
+			extract(epoch from least(coalesce(ss.end_time, now()), days.day_end) - greatest(ss.start_time, days.day_start)) as seconds
+			from session_state ss
+			join session s on s.public_id = ss.session_id
+			join days on ss.start_time < days.day_end and coalesce(ss.end_time, now()) > days.day_start
+		where ss.state = 'active' and s.scope_id is not null
+	)
+	insert into scope_usage_daily (usage_date, scope_id, session_seconds)
+	select usage_date, scope_id, floor(sum(seconds))::bigint
+		from active
+	group by 1, 2
+	on conflict (usage_date, scope_id) do update
+		set session_seconds = greatest(scope_usage_daily.session_seconds, excluded.session_seconds)`
+
+	// listUsageQuery returns the daily usage of scopes in a range of days.
+	listUsageQuery = `
+	select usage_date, scope_id, api_calls, session_seconds
+		from scope_usage_daily
+	where usage_date >= ?::date and usage_date <= ?::date and scope_id in (?)
+	order by usage_date, scope_id`
+)
+
+// A Repository records and lists the daily usage of scopes.
+type Repository struct {
+	reader db.Reader
+	writer db.Writer
+}
+
+// NewRepository creates a new Repository.
+func NewRepository(r db.Reader, w db.Writer) (*Repository, error) {
+	switch {
+	case r == nil:
+		return nil, fmt.Errorf("db.Reader: usage: %w", errors.ErrInvalidParameter)
+	case w == nil:
+		return nil, fmt.Errorf("db.Writer: usage: %w", errors.ErrInvalidParameter)
+	}
+	return &Repository{
+		reader: r,
+		writer: w,
+	}, nil
+}
+
+// RecordApiCalls adds the API calls by scope, as returned by Meter.Take, to
+// the usage of the scopes on the current UTC day.
+func (r *Repository) RecordApiCalls(ctx context.Context, calls map[string]uint64) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			for scopeId, n := range calls {
+				if _, err := w.Exec(ctx, recordApiCallsQuery, []interface{}{scopeId, n}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("record api calls: %w", err)
+	}
+	return nil
+}
+
+// RollupSessions updates the session seconds of the projects on the current
+// and previous UTC days with the time their sessions were active. It returns
+// the number of daily usages updated.
+func (r *Repository) RollupSessions(ctx context.Context) (int, error) {
+	var rolled int
+	_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			var err error
+			rolled, err = w.Exec(ctx, rollupSessionsQuery, nil)
+			return err
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("rollup session usage: %w", err)
+	}
+	return rolled, nil
+}
+
+// A Usage is the number of API calls made in a scope and the seconds its
+// sessions were active on the UTC day Date.
+type Usage struct {
+	Date           time.Time
+	ScopeId        string
+	ApiCalls       uint64
+	SessionSeconds uint64
+}
+
+// ListUsage returns the daily usage of the scopes from the UTC day of start
+// through that of end, ordered by day and scope. Days without usage are
+// omitted.
+func (r *Repository) ListUsage(ctx context.Context, scopeIds []string, start, end time.Time) ([]*Usage, error) {
+	if len(scopeIds) == 0 {
+		return nil, fmt.Errorf("list usage: missing scope ids: %w", errors.ErrInvalidParameter)
+	}
+	start, end = start.UTC(), end.UTC()
+	if end.Before(start) {
+		return nil, fmt.Errorf("list usage: end before start: %w", errors.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, listUsageQuery, []interface{}{start.Format(dateFormat), end.Format(dateFormat), scopeIds})
+	if err != nil {
+		return nil, fmt.Errorf("list usage: %w", err)
+	}
+	defer rows.Close()
+	var ret []*Usage
+	for rows.Next() {
+		var u Usage
+		if err := rows.Scan(&u.Date, &u.ScopeId, &u.ApiCalls, &u.SessionSeconds); err != nil {
+			return nil, fmt.Errorf("list usage: %w", err)
+		}
+		u.Date = u.Date.UTC()
+		ret = append(ret, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list usage: %w", err)
+	}
+	return ret, nil
+}
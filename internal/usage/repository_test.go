@@ -0,0 +1,67 @@
+package usage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_Usage(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw)
+	require.NoError(err)
+	ctx := context.Background()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	// API calls add up
+	require.NoError(repo.RecordApiCalls(ctx, map[string]uint64{"global": 2, "o_1234567890": 1}))
+	require.NoError(repo.RecordApiCalls(ctx, map[string]uint64{"global": 3}))
+	require.NoError(repo.RecordApiCalls(ctx, nil))
+	got, err := repo.ListUsage(ctx, []string{"global", "o_1234567890"}, today, today)
+	require.NoError(err)
+	assert.Equal([]*Usage{
+		{Date: today, ScopeId: "global", ApiCalls: 5},
+		{Date: today, ScopeId: "o_1234567890", ApiCalls: 1},
+	}, got)
+
+	// An active session counts for its project
+	s := session.TestDefaultSession(t, conn, wrapper, iamRepo)
+	sessionRepo, err := session.NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(err)
+	srv := session.TestWorker(t, conn, wrapper)
+	_, _, err = sessionRepo.ActivateSession(ctx, s.PublicId, s.Version, srv.PrivateId, srv.Type, session.TestTofu(t))
+	require.NoError(err)
+	time.Sleep(1100 * time.Millisecond)
+	rolled, err := repo.RollupSessions(ctx)
+	require.NoError(err)
+	assert.NotZero(rolled)
+	got, err = repo.ListUsage(ctx, []string{s.ScopeId}, today.Add(-24*time.Hour), today)
+	require.NoError(err)
+	require.Len(got, 1)
+	assert.Equal(today, got[0].Date)
+	assert.Equal(s.ScopeId, got[0].ScopeId)
+	assert.GreaterOrEqual(got[0].SessionSeconds, uint64(1))
+
+	// Days outside of the range are omitted
+	got, err = repo.ListUsage(ctx, []string{"global"}, today.Add(-48*time.Hour), today.Add(-24*time.Hour))
+	require.NoError(err)
+	assert.Empty(got)
+
+	_, err = repo.ListUsage(ctx, nil, today, today)
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+	_, err = repo.ListUsage(ctx, []string{"global"}, today, today.Add(-24*time.Hour))
+	assert.True(errors.Is(err, errors.ErrInvalidParameter))
+}
@@ -0,0 +1,78 @@
+// Package usage records the API calls and session minutes of scopes by day,
+// so platform teams offering Boundary internally can attribute its costs to
+// the teams using it. The controllers count the API calls of each scope in a
+// Meter, which they periodically record in the daily usage of the scope, and
+// roll the time sessions were active up into the daily usage of their
+// projects.
+package usage
+
+import (
+	"context"
+	"sync"
+)
+
+type key int
+
+var meterKey key
+
+// A Meter counts the API calls of scopes until they are recorded.
+type Meter struct {
+	mu    sync.Mutex
+	calls map[string]uint64
+}
+
+// NewMeter creates a new Meter.
+func NewMeter() *Meter {
+	return &Meter{calls: make(map[string]uint64)}
+}
+
+// CountApiCall counts an API call in the scope. Calls without a scope, and
+// calls to a nil Meter, are not counted.
+func (m *Meter) CountApiCall(scopeId string) {
+	if m == nil || scopeId == "" {
+		return
+	}
+	m.mu.Lock()
+	m.calls[scopeId]++
+	m.mu.Unlock()
+}
+
+// Take returns the API calls by scope counted since it was last called.
+func (m *Meter) Take() map[string]uint64 {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.calls) == 0 {
+		return nil
+	}
+	ret := m.calls
+	m.calls = make(map[string]uint64, len(ret))
+	return ret
+}
+
+// Restore counts again the API calls returned by Take which couldn't be
+// recorded, so they are recorded with the next ones.
+func (m *Meter) Restore(calls map[string]uint64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for scopeId, n := range calls {
+		m.calls[scopeId] += n
+	}
+}
+
+// NewContext returns a context carrying the meter, for counting the API call
+// of a request.
+func NewContext(ctx context.Context, m *Meter) context.Context {
+	return context.WithValue(ctx, meterKey, m)
+}
+
+// FromContext returns the meter of the context, or nil if it has none.
+func FromContext(ctx context.Context) *Meter {
+	m, _ := ctx.Value(meterKey).(*Meter)
+	return m
+}
@@ -0,0 +1,29 @@
+package usage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeter(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	m := NewMeter()
+	m.CountApiCall("global")
+	m.CountApiCall("o_1234567890")
+	m.CountApiCall("o_1234567890")
+	m.CountApiCall("")
+	calls := m.Take()
+	assert.Equal(map[string]uint64{"global": 1, "o_1234567890": 2}, calls)
+	assert.Nil(m.Take())
+
+	m.CountApiCall("global")
+	m.Restore(calls)
+	assert.Equal(map[string]uint64{"global": 2, "o_1234567890": 2}, m.Take())
+
+	// A context without a meter doesn't count calls
+	FromContext(context.Background()).CountApiCall("global")
+	assert.Same(m, FromContext(NewContext(context.Background(), m)))
+}
@@ -0,0 +1,34 @@
+#!/bin/sh
+
+set -e
+
+if [ -z "$CLIENTS_DIR" ]; then
+	echo "Must set CLIENTS_DIR"; exit 1
+fi
+
+if [ -z "$OPENAPI_GENERATOR_IMAGE" ]; then
+	echo "Must set OPENAPI_GENERATOR_IMAGE"; exit 1
+fi
+
+outdir="${CLIENTS_DIR}/.tmp"
+rm -rf "$outdir"
+mkdir -p "$outdir"
+
+go run ./internal/servers/controller/openapi/genopenapi "${outdir}/openapi.json"
+
+# The clients are versioned with the Boundary version the document is built from
+version="$(python3 -c 'import json, sys; print(json.load(open(sys.argv[1]))["info"]["version"])' "${outdir}/openapi.json")"
+
+for lang in python typescript; do
+	case "$lang" in
+	python) generator=python ;;
+	typescript) generator=typescript-fetch ;;
+	esac
+	echo "==> Generating ${lang} client version ${version}"
+	docker run --rm -u "$(id -u):$(id -g)" -v "$(pwd):/local" -w /local "$OPENAPI_GENERATOR_IMAGE" generate \
+		-g "$generator" \
+		-i "${outdir}/openapi.json" \
+		-c "${CLIENTS_DIR}/${lang}/config.yaml" \
+		-o "${outdir}/${lang}" \
+		--additional-properties "packageVersion=${version},npmVersion=${version}"
+done
@@ -0,0 +1,34 @@
+#!/bin/sh
+
+set -e
+
+if [ -z "$CLIENTS_DIR" ]; then
+	echo "Must set CLIENTS_DIR"; exit 1
+fi
+
+if [ -z "$PYPI_TOKEN" ]; then
+	echo "Must set PYPI_TOKEN"; exit 1
+fi
+
+if [ -z "$NPM_TOKEN" ]; then
+	echo "Must set NPM_TOKEN"; exit 1
+fi
+
+outdir="${CLIENTS_DIR}/.tmp"
+
+echo "==> Publishing python client"
+(
+	cd "${outdir}/python"
+	rm -rf dist
+	"${outdir}/venv/bin/pip" install --quiet build twine
+	"${outdir}/venv/bin/python" -m build
+	TWINE_USERNAME=__token__ TWINE_PASSWORD="$PYPI_TOKEN" "${outdir}/venv/bin/twine" upload dist/*
+)
+
+echo "==> Publishing typescript client"
+(
+	cd "${outdir}/typescript"
+	echo "//registry.npmjs.org/:_authToken=${NPM_TOKEN}" > .npmrc
+	npm publish --access public
+	rm .npmrc
+)
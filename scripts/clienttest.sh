@@ -0,0 +1,25 @@
+#!/bin/sh
+
+set -e
+
+if [ -z "$CLIENTS_DIR" ]; then
+	echo "Must set CLIENTS_DIR"; exit 1
+fi
+
+outdir="${CLIENTS_DIR}/.tmp"
+if [ ! -f "${outdir}/openapi.json" ]; then
+	echo "No generated clients found in ${outdir}, run clientgen.sh first"; exit 1
+fi
+
+echo "==> Testing python client"
+python3 -m venv "${outdir}/venv"
+"${outdir}/venv/bin/pip" install --quiet "${outdir}/python"
+"${outdir}/venv/bin/python" "${CLIENTS_DIR}/python/smoke_test.py" "${outdir}/openapi.json"
+
+echo "==> Testing typescript client"
+(
+	cd "${outdir}/typescript"
+	npm install --silent
+	npm run build --silent
+)
+node "${CLIENTS_DIR}/typescript/smoke_test.js" "${outdir}/typescript" "${outdir}/openapi.json"
@@ -0,0 +1,145 @@
+// Package protocol is the interface of the custom protocol handlers of
+// Boundary workers. A handler proxies the connections of clients which ask
+// for its websocket subprotocol to the endpoints of their sessions, e.g. to
+// speak a database wire protocol rather than copying bytes.
+//
+// Handlers are compiled into the worker: the package of a handler registers
+// it with Register from an init function, and is imported by the main package
+// of the worker binary. When the worker starts it performs a handshake with
+// each handler, agreeing on the version of this interface; handlers built
+// against versions the worker doesn't support are not used. A handler which
+// panics only fails the connection it was proxying, and is disabled after
+// panicking repeatedly; this doesn't cover goroutines started by the handler,
+// which must recover their own panics.
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ApiVersion is the version of the interface defined by this package. It is
+// incremented whenever the interface changes in a way handlers must be
+// updated for.
+const ApiVersion = 1
+
+// MinApiVersion is the oldest version of the interface still supported.
+const MinApiVersion = 1
+
+// HandshakeRequest is sent to a handler when the worker starts.
+type HandshakeRequest struct {
+	// ApiVersion and MinApiVersion are the newest and oldest versions of the
+	// interface the worker supports
+	ApiVersion    int
+	MinApiVersion int
+	// WorkerName is the name of the worker
+	WorkerName string
+}
+
+// HandshakeResponse is the answer of a handler to the handshake.
+type HandshakeResponse struct {
+	// ApiVersion is the version of the interface the handler implements; it
+	// must be one of the versions supported by the worker
+	ApiVersion int
+	// Subprotocol is the websocket subprotocol clients ask for to have their
+	// connections proxied by the handler
+	Subprotocol string
+}
+
+// A Handler proxies the connections of clients to the endpoints of their
+// sessions with its own protocol.
+type Handler interface {
+	// Handshake is called once when the worker starts. The handler is not
+	// used if it returns an error.
+	Handshake(ctx context.Context, req *HandshakeRequest) (*HandshakeResponse, error)
+
+	// Proxy proxies the connection until either side closes it or ctx is
+	// done. Both sides of the connection are closed by the worker once it
+	// returns.
+	Proxy(ctx context.Context, conn *Connection) error
+}
+
+// Identity is the identity of the user of a session, for handlers which
+// inject credentials into their protocol, e.g. to authenticate to the
+// endpoint as the user.
+type Identity struct {
+	UserId   string
+	UserName string
+	// InjectedUserName is the user name to authenticate to the endpoint
+	// with, produced from the name of the user by the user name template of
+	// the target, e.g. to map the user to their own account on the endpoint.
+	// It is the name of the user if the target has no template.
+	InjectedUserName string
+	// CredentialCheckoutId is the checkout of the shared credential of the
+	// target held by the session, if any
+	CredentialCheckoutId string
+}
+
+// A Recorder records the events of a connection, such as the queries of a
+// database protocol, in the record of the connection written to the
+// connection log of the worker.
+type Recorder interface {
+	// Record records an event of the kind with its attributes. Events past
+	// the limit of the worker are dropped.
+	Record(kind string, attributes map[string]string)
+}
+
+// Connection is a connection to proxy.
+type Connection struct {
+	SessionId    string
+	ConnectionId string
+	TargetId     string
+	HostId       string
+	Identity     Identity
+
+	// Client is the connection from the client, and Endpoint the connection
+	// to the endpoint of the session. Bytes written to them count toward the
+	// bandwidth limits of the target.
+	Client   net.Conn
+	Endpoint net.Conn
+
+	// Expiration is when the session expires
+	Expiration time.Time
+
+	Recorder Recorder
+}
+
+var (
+	handlersLock sync.Mutex
+	handlers     = map[string]Handler{}
+)
+
+// Register registers the handler with a name identifying it in logs. It
+// panics if a handler is already registered with the name, so it should be
+// called from an init function.
+func Register(name string, h Handler) {
+	handlersLock.Lock()
+	defer handlersLock.Unlock()
+	if name == "" || h == nil {
+		panic("protocol: Register called with an empty name or nil handler")
+	}
+	if _, ok := handlers[name]; ok {
+		panic(fmt.Sprintf("protocol: handler %q registered twice", name))
+	}
+	handlers[name] = h
+}
+
+// Handlers returns the registered handlers by name.
+func Handlers() map[string]Handler {
+	handlersLock.Lock()
+	defer handlersLock.Unlock()
+	ret := make(map[string]Handler, len(handlers))
+	for name, h := range handlers {
+		ret[name] = h
+	}
+	return ret
+}
+
+// Supported returns true if version is a version of the interface this
+// package supports.
+func Supported(version int) bool {
+	return version >= MinApiVersion && version <= ApiVersion
+}
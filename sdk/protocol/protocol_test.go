@@ -0,0 +1,45 @@
+package protocol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testHandler struct{}
+
+func (testHandler) Handshake(context.Context, *HandshakeRequest) (*HandshakeResponse, error) {
+	return &HandshakeResponse{ApiVersion: ApiVersion, Subprotocol: "test-v1"}, nil
+}
+
+func (testHandler) Proxy(context.Context, *Connection) error {
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	assert := assert.New(t)
+	Register("test", testHandler{})
+	defer func() {
+		handlersLock.Lock()
+		delete(handlers, "test")
+		handlersLock.Unlock()
+	}()
+
+	assert.Contains(Handlers(), "test")
+	assert.Panics(func() { Register("test", testHandler{}) })
+	assert.Panics(func() { Register("", testHandler{}) })
+	assert.Panics(func() { Register("nil", nil) })
+
+	// The returned map is a copy
+	delete(Handlers(), "test")
+	assert.Contains(Handlers(), "test")
+}
+
+func TestSupported(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(Supported(ApiVersion))
+	assert.True(Supported(MinApiVersion))
+	assert.False(Supported(MinApiVersion - 1))
+	assert.False(Supported(ApiVersion + 1))
+}
@@ -0,0 +1,141 @@
+package pkcs11
+
+import (
+	"fmt"
+	"sync"
+)
+
+type (
+	sessionHandle uint
+	objectHandle  uint
+)
+
+// module is a loaded PKCS#11 library. Only the operations the wrapper needs
+// are exposed.
+type module interface {
+	// slotWithToken returns the slot holding the token labeled label.
+	slotWithToken(label string) (uint, error)
+	// openSession opens a read-write session on slot.
+	openSession(slot uint) (sessionHandle, error)
+	closeSession(s sessionHandle) error
+	// login logs the user in to the token of s. As PKCS#11 logins apply to
+	// all sessions on a token, logging in again is not an error.
+	login(s sessionHandle, pin string) error
+	// findKey returns the secret key labeled label.
+	findKey(s sessionHandle, label string) (objectHandle, error)
+	// encrypt and decrypt use CKM_AES_GCM with a 128 bit tag.
+	encrypt(s sessionHandle, key objectHandle, iv, plaintext []byte) ([]byte, error)
+	decrypt(s sessionHandle, key objectHandle, iv, ciphertext []byte) ([]byte, error)
+	// finalize releases the library.
+	finalize() error
+}
+
+// loadModule loads the PKCS#11 library at path. It is a variable so tests
+// can replace the library.
+var loadModule = loadLibrary
+
+// modules holds the loaded libraries, as a library may only be initialized
+// once per process but several KMS blocks may use it.
+var modules = struct {
+	sync.Mutex
+	loaded map[string]*sharedModule
+}{loaded: map[string]*sharedModule{}}
+
+type sharedModule struct {
+	module
+	refs int
+}
+
+// acquireModule returns the library at path, loading it if no other wrapper
+// uses it.
+func acquireModule(path string) (module, error) {
+	modules.Lock()
+	defer modules.Unlock()
+	if m, ok := modules.loaded[path]; ok {
+		m.refs++
+		return m.module, nil
+	}
+	m, err := loadModule(path)
+	if err != nil {
+		return nil, err
+	}
+	modules.loaded[path] = &sharedModule{module: m, refs: 1}
+	return m, nil
+}
+
+// releaseModule finalizes the library at path once no wrapper uses it.
+func releaseModule(path string) error {
+	modules.Lock()
+	defer modules.Unlock()
+	m, ok := modules.loaded[path]
+	if !ok {
+		return nil
+	}
+	if m.refs--; m.refs > 0 {
+		return nil
+	}
+	delete(modules.loaded, path)
+	return m.finalize()
+}
+
+// PKCS#11 return values handled by the wrapper.
+const (
+	ckrOk                         = 0x000
+	ckrDeviceError                = 0x030
+	ckrDeviceRemoved              = 0x032
+	ckrKeyHandleInvalid           = 0x060
+	ckrObjectHandleInvalid        = 0x082
+	ckrPinIncorrect               = 0x0a0
+	ckrSessionClosed              = 0x0b0
+	ckrSessionHandleInvalid       = 0x0b3
+	ckrTokenNotPresent            = 0x0e0
+	ckrUserAlreadyLoggedIn        = 0x100
+	ckrUserNotLoggedIn            = 0x101
+	ckrCryptokiAlreadyInitialized = 0x191
+)
+
+var returnValueNames = map[returnValue]string{
+	ckrDeviceError:                "CKR_DEVICE_ERROR",
+	ckrDeviceRemoved:              "CKR_DEVICE_REMOVED",
+	ckrKeyHandleInvalid:           "CKR_KEY_HANDLE_INVALID",
+	ckrObjectHandleInvalid:        "CKR_OBJECT_HANDLE_INVALID",
+	ckrPinIncorrect:               "CKR_PIN_INCORRECT",
+	ckrSessionClosed:              "CKR_SESSION_CLOSED",
+	ckrSessionHandleInvalid:       "CKR_SESSION_HANDLE_INVALID",
+	ckrTokenNotPresent:            "CKR_TOKEN_NOT_PRESENT",
+	ckrUserAlreadyLoggedIn:        "CKR_USER_ALREADY_LOGGED_IN",
+	ckrUserNotLoggedIn:            "CKR_USER_NOT_LOGGED_IN",
+	ckrCryptokiAlreadyInitialized: "CKR_CRYPTOKI_ALREADY_INITIALIZED",
+}
+
+// returnValue is a failed return value of a PKCS#11 function.
+type returnValue uint
+
+func (rv returnValue) Error() string {
+	if name, ok := returnValueNames[rv]; ok {
+		return name
+	}
+	return fmt.Sprintf("CKR_0x%x", uint(rv))
+}
+
+// sessionLost returns true if err means the session can no longer be used,
+// so it should be replaced by a new one.
+func sessionLost(err error) bool {
+	switch err {
+	case returnValue(ckrSessionClosed), returnValue(ckrSessionHandleInvalid),
+		returnValue(ckrDeviceError), returnValue(ckrDeviceRemoved),
+		returnValue(ckrTokenNotPresent), returnValue(ckrUserNotLoggedIn):
+		return true
+	}
+	return false
+}
+
+// keyLost returns true if err means the key handle is no longer valid, so
+// the key should be looked up again.
+func keyLost(err error) bool {
+	switch err {
+	case returnValue(ckrKeyHandleInvalid), returnValue(ckrObjectHandleInvalid):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,305 @@
+// +build cgo,!windows
+
+package pkcs11
+
+/*
+#cgo linux LDFLAGS: -ldl
+
+#include <dlfcn.h>
+#include <stdlib.h>
+#include <string.h>
+
+// The subset of the PKCS#11 v2.40 types used, see pkcs11t.h.
+typedef unsigned long CK_ULONG;
+typedef CK_ULONG CK_RV;
+typedef unsigned char CK_BYTE;
+
+typedef struct { CK_BYTE major; CK_BYTE minor; } CK_VERSION;
+
+// CK_FUNCTION_LIST holds the functions in the order of pkcs11f.h.
+typedef struct { CK_VERSION version; void *fn[68]; } CK_FUNCTION_LIST;
+
+typedef struct {
+	void *CreateMutex, *DestroyMutex, *LockMutex, *UnlockMutex;
+	CK_ULONG flags;
+	void *pReserved;
+} CK_C_INITIALIZE_ARGS;
+
+typedef struct { CK_ULONG type; void *pValue; CK_ULONG ulValueLen; } CK_ATTRIBUTE;
+typedef struct { CK_ULONG mechanism; void *pParameter; CK_ULONG ulParameterLen; } CK_MECHANISM;
+typedef struct {
+	CK_BYTE *pIv;
+	CK_ULONG ulIvLen;
+	CK_ULONG ulIvBits;
+	CK_BYTE *pAAD;
+	CK_ULONG ulAADLen;
+	CK_ULONG ulTagBits;
+} CK_GCM_PARAMS;
+
+enum {
+	fnInitialize = 0,
+	fnFinalize = 1,
+	fnGetSlotList = 4,
+	fnGetTokenInfo = 6,
+	fnOpenSession = 12,
+	fnCloseSession = 13,
+	fnLogin = 18,
+	fnFindObjectsInit = 26,
+	fnFindObjects = 27,
+	fnFindObjectsFinal = 28,
+	fnEncryptInit = 29,
+	fnEncrypt = 30,
+	fnDecryptInit = 33,
+	fnDecrypt = 34,
+};
+
+// p11_load loads the library at path, returning 1 if it can't be opened, 2
+// if it isn't a PKCS#11 library and 3 if getting its functions failed with
+// the return value in rv.
+static int p11_load(const char *path, void **handle, CK_FUNCTION_LIST **list, CK_RV *rv) {
+	*handle = dlopen(path, RTLD_NOW | RTLD_LOCAL);
+	if (*handle == NULL) {
+		return 1;
+	}
+	CK_RV (*get)(CK_FUNCTION_LIST **) = (CK_RV (*)(CK_FUNCTION_LIST **))dlsym(*handle, "C_GetFunctionList");
+	if (get == NULL) {
+		dlclose(*handle);
+		return 2;
+	}
+	*rv = get(list);
+	if (*rv != 0) {
+		dlclose(*handle);
+		return 3;
+	}
+	return 0;
+}
+
+static char *p11_dlerror(void) {
+	return dlerror();
+}
+
+static void p11_unload(void *handle) {
+	dlclose(handle);
+}
+
+static CK_RV p11_initialize(CK_FUNCTION_LIST *f) {
+	CK_C_INITIALIZE_ARGS args;
+	memset(&args, 0, sizeof(args));
+	args.flags = 0x2; // CKF_OS_LOCKING_OK
+	return ((CK_RV (*)(void *))f->fn[fnInitialize])(&args);
+}
+
+static CK_RV p11_finalize(CK_FUNCTION_LIST *f) {
+	return ((CK_RV (*)(void *))f->fn[fnFinalize])(NULL);
+}
+
+static CK_RV p11_get_slot_list(CK_FUNCTION_LIST *f, CK_ULONG *slots, CK_ULONG *count) {
+	// Only slots with a token present
+	return ((CK_RV (*)(CK_BYTE, CK_ULONG *, CK_ULONG *))f->fn[fnGetSlotList])(1, slots, count);
+}
+
+static CK_RV p11_get_token_label(CK_FUNCTION_LIST *f, CK_ULONG slot, char *label) {
+	// CK_TOKEN_INFO is smaller than info and starts with the 32 byte label
+	CK_ULONG info[128];
+	CK_RV rv = ((CK_RV (*)(CK_ULONG, void *))f->fn[fnGetTokenInfo])(slot, info);
+	if (rv == 0) {
+		memcpy(label, info, 32);
+	}
+	return rv;
+}
+
+static CK_RV p11_open_session(CK_FUNCTION_LIST *f, CK_ULONG slot, CK_ULONG *session) {
+	// CKF_SERIAL_SESSION | CKF_RW_SESSION
+	return ((CK_RV (*)(CK_ULONG, CK_ULONG, void *, void *, CK_ULONG *))f->fn[fnOpenSession])(slot, 0x4 | 0x2, NULL, NULL, session);
+}
+
+static CK_RV p11_close_session(CK_FUNCTION_LIST *f, CK_ULONG session) {
+	return ((CK_RV (*)(CK_ULONG))f->fn[fnCloseSession])(session);
+}
+
+static CK_RV p11_login(CK_FUNCTION_LIST *f, CK_ULONG session, CK_BYTE *pin, CK_ULONG pinLen) {
+	// CKU_USER
+	return ((CK_RV (*)(CK_ULONG, CK_ULONG, CK_BYTE *, CK_ULONG))f->fn[fnLogin])(session, 1, pin, pinLen);
+}
+
+// p11_find_key finds up to two secret keys labeled label, so callers can
+// tell whether the label is ambiguous.
+static CK_RV p11_find_key(CK_FUNCTION_LIST *f, CK_ULONG session, CK_BYTE *label, CK_ULONG labelLen, CK_ULONG *keys, CK_ULONG *found) {
+	CK_ULONG class = 0x4; // CKO_SECRET_KEY
+	CK_ATTRIBUTE tmpl[2] = {
+		{ 0x0, &class, sizeof(class) }, // CKA_CLASS
+		{ 0x3, label, labelLen },       // CKA_LABEL
+	};
+	CK_RV rv = ((CK_RV (*)(CK_ULONG, CK_ATTRIBUTE *, CK_ULONG))f->fn[fnFindObjectsInit])(session, tmpl, 2);
+	if (rv != 0) {
+		return rv;
+	}
+	rv = ((CK_RV (*)(CK_ULONG, CK_ULONG *, CK_ULONG, CK_ULONG *))f->fn[fnFindObjects])(session, keys, 2, found);
+	CK_RV finalRv = ((CK_RV (*)(CK_ULONG))f->fn[fnFindObjectsFinal])(session);
+	if (rv != 0) {
+		return rv;
+	}
+	return finalRv;
+}
+
+static CK_RV p11_aes_gcm(CK_FUNCTION_LIST *f, int encrypt, CK_ULONG session, CK_ULONG key,
+		CK_BYTE *iv, CK_ULONG ivLen, CK_BYTE *in, CK_ULONG inLen, CK_BYTE *out, CK_ULONG *outLen) {
+	CK_GCM_PARAMS params = { iv, ivLen, ivLen * 8, NULL, 0, 128 };
+	CK_MECHANISM mech = { 0x1087, &params, sizeof(params) }; // CKM_AES_GCM
+	CK_RV rv = ((CK_RV (*)(CK_ULONG, CK_MECHANISM *, CK_ULONG))f->fn[encrypt ? fnEncryptInit : fnDecryptInit])(session, &mech, key);
+	if (rv != 0) {
+		return rv;
+	}
+	return ((CK_RV (*)(CK_ULONG, CK_BYTE *, CK_ULONG, CK_BYTE *, CK_ULONG *))f->fn[encrypt ? fnEncrypt : fnDecrypt])(session, in, inLen, out, outLen);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// gcmTagSize is the size of the tags appended by CKM_AES_GCM.
+const gcmTagSize = 16
+
+// library is a PKCS#11 library loaded with dlopen.
+type library struct {
+	handle unsafe.Pointer
+	funcs  *C.CK_FUNCTION_LIST
+}
+
+func loadLibrary(path string) (module, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	l := &library{}
+	var rv C.CK_RV
+	switch C.p11_load(cPath, &l.handle, &l.funcs, &rv) {
+	case 0:
+	case 1:
+		return nil, fmt.Errorf("error loading pkcs11 library %s: %s", path, C.GoString(C.p11_dlerror()))
+	case 2:
+		return nil, fmt.Errorf("%s is not a pkcs11 library", path)
+	default:
+		return nil, fmt.Errorf("error getting functions of pkcs11 library %s: %w", path, returnValue(rv))
+	}
+	if rv := C.p11_initialize(l.funcs); rv != ckrOk && rv != ckrCryptokiAlreadyInitialized {
+		C.p11_unload(l.handle)
+		return nil, fmt.Errorf("error initializing pkcs11 library %s: %w", path, returnValue(rv))
+	}
+	return l, nil
+}
+
+func (l *library) slotWithToken(label string) (uint, error) {
+	var count C.CK_ULONG
+	if rv := C.p11_get_slot_list(l.funcs, nil, &count); rv != ckrOk {
+		return 0, returnValue(rv)
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("no token labeled %q found", label)
+	}
+	slots := make([]C.CK_ULONG, count)
+	if rv := C.p11_get_slot_list(l.funcs, &slots[0], &count); rv != ckrOk {
+		return 0, returnValue(rv)
+	}
+	tokenLabel := make([]byte, 32)
+	for _, slot := range slots[:count] {
+		if rv := C.p11_get_token_label(l.funcs, slot, (*C.char)(unsafe.Pointer(&tokenLabel[0]))); rv != ckrOk {
+			continue
+		}
+		if strings.TrimRight(string(tokenLabel), " \x00") == label {
+			return uint(slot), nil
+		}
+	}
+	return 0, fmt.Errorf("no token labeled %q found", label)
+}
+
+func (l *library) openSession(slot uint) (sessionHandle, error) {
+	var s C.CK_ULONG
+	if rv := C.p11_open_session(l.funcs, C.CK_ULONG(slot), &s); rv != ckrOk {
+		return 0, returnValue(rv)
+	}
+	return sessionHandle(s), nil
+}
+
+func (l *library) closeSession(s sessionHandle) error {
+	if rv := C.p11_close_session(l.funcs, C.CK_ULONG(s)); rv != ckrOk {
+		return returnValue(rv)
+	}
+	return nil
+}
+
+func (l *library) login(s sessionHandle, pin string) error {
+	if pin == "" {
+		return errors.New("missing pin")
+	}
+	p := []byte(pin)
+	rv := C.p11_login(l.funcs, C.CK_ULONG(s), (*C.CK_BYTE)(&p[0]), C.CK_ULONG(len(p)))
+	if rv != ckrOk && rv != ckrUserAlreadyLoggedIn {
+		return returnValue(rv)
+	}
+	return nil
+}
+
+func (l *library) findKey(s sessionHandle, label string) (objectHandle, error) {
+	if label == "" {
+		return 0, errors.New("missing key label")
+	}
+	b := []byte(label)
+	keys := make([]C.CK_ULONG, 2)
+	var found C.CK_ULONG
+	if rv := C.p11_find_key(l.funcs, C.CK_ULONG(s), (*C.CK_BYTE)(&b[0]), C.CK_ULONG(len(b)), &keys[0], &found); rv != ckrOk {
+		return 0, returnValue(rv)
+	}
+	switch found {
+	case 0:
+		return 0, fmt.Errorf("no secret key labeled %q found", label)
+	case 1:
+		return objectHandle(keys[0]), nil
+	default:
+		return 0, fmt.Errorf("more than one secret key labeled %q found", label)
+	}
+}
+
+func (l *library) encrypt(s sessionHandle, key objectHandle, iv, plaintext []byte) ([]byte, error) {
+	return l.aesGcm(true, s, key, iv, plaintext, len(plaintext)+gcmTagSize)
+}
+
+func (l *library) decrypt(s sessionHandle, key objectHandle, iv, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < gcmTagSize {
+		return nil, errors.New("ciphertext too short")
+	}
+	return l.aesGcm(false, s, key, iv, ciphertext, len(ciphertext))
+}
+
+func (l *library) aesGcm(encrypt bool, s sessionHandle, key objectHandle, iv, in []byte, outSize int) ([]byte, error) {
+	if len(iv) == 0 || len(in) == 0 {
+		return nil, errors.New("missing iv or input")
+	}
+	var enc C.int
+	if encrypt {
+		enc = 1
+	}
+	out := make([]byte, outSize)
+	outLen := C.CK_ULONG(len(out))
+	rv := C.p11_aes_gcm(l.funcs, enc, C.CK_ULONG(s), C.CK_ULONG(key),
+		(*C.CK_BYTE)(&iv[0]), C.CK_ULONG(len(iv)),
+		(*C.CK_BYTE)(&in[0]), C.CK_ULONG(len(in)),
+		(*C.CK_BYTE)(&out[0]), &outLen)
+	if rv != ckrOk {
+		return nil, returnValue(rv)
+	}
+	return out[:outLen], nil
+}
+
+func (l *library) finalize() error {
+	rv := C.p11_finalize(l.funcs)
+	C.p11_unload(l.handle)
+	if rv != ckrOk {
+		return returnValue(rv)
+	}
+	return nil
+}
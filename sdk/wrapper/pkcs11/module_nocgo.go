@@ -0,0 +1,9 @@
+// +build !cgo windows
+
+package pkcs11
+
+import "errors"
+
+func loadLibrary(string) (module, error) {
+	return nil, errors.New("pkcs11 requires a binary built with cgo on a platform other than windows")
+}
@@ -0,0 +1,467 @@
+// Package pkcs11 implements a KMS wrapper keeping its key in an HSM or other
+// token accessed through a PKCS#11 library, for kms "pkcs11" blocks.
+//
+// Data is encrypted with a new AES-GCM data key each time, and the data key
+// is encrypted by the token with an AES key it holds, using CKM_AES_GCM. The
+// key never leaves the token. Operations run on a pool of sessions so they
+// can run in parallel, and the wrapper checks periodically that the token can
+// still encrypt and decrypt.
+//
+// Configuration values, which can also be given by the environment:
+//
+//   lib                   BOUNDARY_PKCS11_LIB          path of the PKCS#11 library
+//   slot                  BOUNDARY_PKCS11_SLOT         slot of the token
+//   token_label           BOUNDARY_PKCS11_TOKEN_LABEL  label of the token, instead of slot
+//   pin                   BOUNDARY_PKCS11_PIN          user PIN of the token
+//   key_label             BOUNDARY_PKCS11_KEY_LABEL    label of the AES key
+//   key_id                                             key ID of the wrapper; defaults to key_label
+//   max_parallel                                       sessions to use at most; defaults to 4
+//   health_check_interval                              time between health checks; defaults to 1m, 0 disables them
+package pkcs11
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/sdk/parseutil"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/shared-secure-libs/configutil"
+)
+
+// Type is the type of the wrapper, as used in kms blocks.
+const Type = "pkcs11"
+
+const (
+	// ckmAesGcm is the mechanism recorded in the key info of encrypted blobs.
+	ckmAesGcm = 0x1087
+
+	defaultMaxParallel         = 4
+	defaultHealthCheckInterval = time.Minute
+
+	// healthCheckTimeout bounds each health check
+	healthCheckTimeout = 10 * time.Second
+)
+
+var errPoolClosed = errors.New("pkcs11 wrapper is finalized")
+
+// Wrapper is a wrapping.Wrapper using a key held in a PKCS#11 token.
+type Wrapper struct {
+	lib                 string
+	slot                uint
+	tokenLabel          string
+	pin                 string
+	keyLabel            string
+	keyId               string
+	maxParallel         int
+	healthCheckInterval time.Duration
+
+	mod  module
+	pool *sessionPool
+
+	keyLock sync.Mutex
+	key     objectHandle
+	haveKey bool
+
+	healthLock sync.RWMutex
+	healthErr  error
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+var _ wrapping.Wrapper = (*Wrapper)(nil)
+
+// NewWrapper returns an unconfigured wrapper. SetConfig and Init must be
+// called before it is used.
+func NewWrapper() *Wrapper {
+	return &Wrapper{
+		maxParallel:         defaultMaxParallel,
+		healthCheckInterval: defaultHealthCheckInterval,
+	}
+}
+
+// ConfigureWrapper returns a wrapper configured and initialized from kms,
+// adding its properties to infoKeys and info if they are not nil, like
+// configutil.ConfigureWrapper does for other kms types.
+func ConfigureWrapper(kms *configutil.KMS, infoKeys *[]string, info *map[string]string) (*Wrapper, error) {
+	w := NewWrapper()
+	wrapperInfo, err := w.SetConfig(kms.Config)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Init(context.Background()); err != nil {
+		return nil, err
+	}
+	if infoKeys != nil && info != nil {
+		*infoKeys = append(*infoKeys, "KMS Type")
+		(*info)["KMS Type"] = Type
+		for _, k := range []string{"PKCS#11 Library", "PKCS#11 Slot", "PKCS#11 Token Label", "PKCS#11 Key Label"} {
+			if v, ok := wrapperInfo[k]; ok {
+				*infoKeys = append(*infoKeys, k)
+				(*info)[k] = v
+			}
+		}
+	}
+	return w, nil
+}
+
+// SetConfig sets the configuration of the wrapper, returning its properties
+// for display.
+func (w *Wrapper) SetConfig(config map[string]string) (map[string]string, error) {
+	if config == nil {
+		config = map[string]string{}
+	}
+	get := func(key, env string) string {
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+		return config[key]
+	}
+
+	w.lib = get("lib", "BOUNDARY_PKCS11_LIB")
+	if w.lib == "" {
+		return nil, errors.New("pkcs11: lib is required")
+	}
+	w.pin = get("pin", "BOUNDARY_PKCS11_PIN")
+	if w.pin == "" {
+		return nil, errors.New("pkcs11: pin is required")
+	}
+	w.keyLabel = get("key_label", "BOUNDARY_PKCS11_KEY_LABEL")
+	if w.keyLabel == "" {
+		return nil, errors.New("pkcs11: key_label is required")
+	}
+	w.keyId = config["key_id"]
+	if w.keyId == "" {
+		w.keyId = w.keyLabel
+	}
+
+	w.tokenLabel = get("token_label", "BOUNDARY_PKCS11_TOKEN_LABEL")
+	slot := get("slot", "BOUNDARY_PKCS11_SLOT")
+	switch {
+	case slot != "" && w.tokenLabel != "":
+		return nil, errors.New("pkcs11: only one of slot and token_label can be set")
+	case slot != "":
+		s, err := strconv.ParseUint(slot, 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("pkcs11: error parsing slot: %w", err)
+		}
+		w.slot = uint(s)
+	case w.tokenLabel == "":
+		return nil, errors.New("pkcs11: one of slot and token_label is required")
+	}
+
+	if v := config["max_parallel"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("pkcs11: max_parallel must be a positive integer")
+		}
+		w.maxParallel = n
+	}
+	if v, ok := config["health_check_interval"]; ok {
+		d, err := parseutil.ParseDurationSecond(v)
+		if err != nil {
+			return nil, fmt.Errorf("pkcs11: error parsing health_check_interval: %w", err)
+		}
+		w.healthCheckInterval = d
+	}
+
+	info := map[string]string{
+		"PKCS#11 Library":   w.lib,
+		"PKCS#11 Key Label": w.keyLabel,
+	}
+	if w.tokenLabel != "" {
+		info["PKCS#11 Token Label"] = w.tokenLabel
+	} else {
+		info["PKCS#11 Slot"] = strconv.FormatUint(uint64(w.slot), 10)
+	}
+	return info, nil
+}
+
+// Init loads the library, logs in to the token and checks that the key can
+// be used. It starts the periodic health checks.
+func (w *Wrapper) Init(ctx context.Context) error {
+	mod, err := acquireModule(w.lib)
+	if err != nil {
+		return fmt.Errorf("pkcs11: %w", err)
+	}
+	if w.tokenLabel != "" {
+		if w.slot, err = mod.slotWithToken(w.tokenLabel); err != nil {
+			_ = releaseModule(w.lib)
+			return fmt.Errorf("pkcs11: %w", err)
+		}
+	}
+	w.mod = mod
+	w.pool = newSessionPool(w.maxParallel, w.openSession, mod.closeSession)
+
+	if err := w.checkHealth(ctx); err != nil {
+		w.pool.closeIdle()
+		_ = releaseModule(w.lib)
+		return fmt.Errorf("pkcs11: error using key %q: %w", w.keyLabel, err)
+	}
+
+	w.stop, w.stopped = make(chan struct{}), make(chan struct{})
+	go w.runHealthChecks()
+	return nil
+}
+
+// Finalize stops the health checks, closes the sessions of the wrapper and
+// releases the library.
+func (w *Wrapper) Finalize(context.Context) error {
+	if w.pool == nil {
+		return nil
+	}
+	close(w.stop)
+	<-w.stopped
+	w.pool.closeIdle()
+	return releaseModule(w.lib)
+}
+
+// Type returns the type of the wrapper.
+func (w *Wrapper) Type() string {
+	return Type
+}
+
+// KeyID returns the key ID of the wrapper.
+func (w *Wrapper) KeyID() string {
+	return w.keyId
+}
+
+// HMACKeyID returns an empty string as the wrapper does not support HMAC.
+func (w *Wrapper) HMACKeyID() string {
+	return ""
+}
+
+// Encrypt encrypts plaintext with a new data key, which is encrypted by the
+// token.
+func (w *Wrapper) Encrypt(ctx context.Context, plaintext, aad []byte) (*wrapping.EncryptedBlobInfo, error) {
+	if plaintext == nil {
+		return nil, errors.New("pkcs11: given plaintext for encryption is nil")
+	}
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("pkcs11: error generating data key: %w", err)
+	}
+	gcm, err := newGcm(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("pkcs11: error generating iv: %w", err)
+	}
+	ciphertext := gcm.Seal(nil, iv, plaintext, aad)
+
+	wrappedKey, err := w.wrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: %w", err)
+	}
+	return &wrapping.EncryptedBlobInfo{
+		Ciphertext: ciphertext,
+		IV:         iv,
+		KeyInfo: &wrapping.KeyInfo{
+			Mechanism:  ckmAesGcm,
+			KeyID:      w.keyId,
+			WrappedKey: wrappedKey,
+		},
+	}, nil
+}
+
+// Decrypt decrypts in, having the token decrypt its data key.
+func (w *Wrapper) Decrypt(ctx context.Context, in *wrapping.EncryptedBlobInfo, aad []byte) ([]byte, error) {
+	if in == nil {
+		return nil, errors.New("pkcs11: given input for decryption is nil")
+	}
+	if in.KeyInfo == nil {
+		return nil, errors.New("pkcs11: key info is nil")
+	}
+	dataKey, err := w.unwrapKey(ctx, in.KeyInfo.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: %w", err)
+	}
+	gcm, err := newGcm(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, in.IV, in.Ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: error decrypting: %w", err)
+	}
+	return plaintext, nil
+}
+
+// Health returns the error of the last health check, or nil if it succeeded.
+func (w *Wrapper) Health() error {
+	w.healthLock.RLock()
+	defer w.healthLock.RUnlock()
+	return w.healthErr
+}
+
+// wrapKey encrypts dataKey with the key of the token, returning the IV used
+// followed by the encrypted key.
+func (w *Wrapper) wrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	iv := make([]byte, 12)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("error generating iv: %w", err)
+	}
+	var wrapped []byte
+	err := w.do(ctx, func(s sessionHandle, key objectHandle) error {
+		var err error
+		wrapped, err = w.mod.encrypt(s, key, iv, dataKey)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting data key: %w", err)
+	}
+	return append(iv, wrapped...), nil
+}
+
+// unwrapKey decrypts a data key encrypted by wrapKey.
+func (w *Wrapper) unwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	if len(wrappedKey) <= 12 {
+		return nil, errors.New("wrapped key is too short")
+	}
+	var dataKey []byte
+	err := w.do(ctx, func(s sessionHandle, key objectHandle) error {
+		var err error
+		dataKey, err = w.mod.decrypt(s, key, wrappedKey[:12], wrappedKey[12:])
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+// do runs fn with a session from the pool and the key. If the session or key
+// handle turns out to be invalid, e.g. after the token was reset, fn is
+// retried once with a new session or key handle.
+func (w *Wrapper) do(ctx context.Context, fn func(sessionHandle, objectHandle) error) error {
+	for attempt := 0; ; attempt++ {
+		s, err := w.pool.get(ctx)
+		if err != nil {
+			return err
+		}
+		key, err := w.keyHandle(s)
+		if err == nil {
+			err = fn(s, key)
+		}
+		switch {
+		case err == nil:
+			w.pool.put(s)
+			return nil
+		case sessionLost(err):
+			w.pool.discard(s)
+		case keyLost(err):
+			w.pool.put(s)
+			w.forgetKey()
+		default:
+			w.pool.put(s)
+			return err
+		}
+		if attempt > 0 {
+			return err
+		}
+	}
+}
+
+// openSession opens a session on the token and logs in.
+func (w *Wrapper) openSession() (sessionHandle, error) {
+	s, err := w.mod.openSession(w.slot)
+	if err != nil {
+		return 0, err
+	}
+	if err := w.mod.login(s, w.pin); err != nil {
+		_ = w.mod.closeSession(s)
+		return 0, err
+	}
+	return s, nil
+}
+
+// keyHandle returns the handle of the key, looking it up with s if needed.
+// Object handles are valid in all sessions.
+func (w *Wrapper) keyHandle(s sessionHandle) (objectHandle, error) {
+	w.keyLock.Lock()
+	defer w.keyLock.Unlock()
+	if w.haveKey {
+		return w.key, nil
+	}
+	key, err := w.mod.findKey(s, w.keyLabel)
+	if err != nil {
+		return 0, err
+	}
+	w.key, w.haveKey = key, true
+	return key, nil
+}
+
+func (w *Wrapper) forgetKey() {
+	w.keyLock.Lock()
+	defer w.keyLock.Unlock()
+	w.haveKey = false
+}
+
+// checkHealth checks that the token can encrypt and decrypt with the key,
+// recording the result for Health.
+func (w *Wrapper) checkHealth(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	err := func() error {
+		probe := make([]byte, 32)
+		if _, err := rand.Read(probe); err != nil {
+			return err
+		}
+		wrapped, err := w.wrapKey(ctx, probe)
+		if err != nil {
+			return err
+		}
+		unwrapped, err := w.unwrapKey(ctx, wrapped)
+		if err != nil {
+			return err
+		}
+		if string(unwrapped) != string(probe) {
+			return errors.New("health check decrypted a different value")
+		}
+		return nil
+	}()
+	w.healthLock.Lock()
+	w.healthErr = err
+	w.healthLock.Unlock()
+	return err
+}
+
+func (w *Wrapper) runHealthChecks() {
+	defer close(w.stopped)
+	if w.healthCheckInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(w.healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			// The result is recorded for Health
+			_ = w.checkHealth(context.Background())
+		}
+	}
+}
+
+func newGcm(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: error creating cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: error creating gcm: %w", err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,333 @@
+package pkcs11
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testModule is a module holding one AES key labeled "boundary" on the token
+// labeled "token" in slot 1.
+type testModule struct {
+	sync.Mutex
+	gcm       cipher.AEAD
+	sessions  map[sessionHandle]bool
+	next      sessionHandle
+	maxOpen   int
+	loggedIn  bool
+	fail      error
+	finalized int
+}
+
+func newTestModule(t *testing.T) *testModule {
+	block, err := aes.NewCipher(make([]byte, 32))
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	return &testModule{gcm: gcm, sessions: map[sessionHandle]bool{}}
+}
+
+func (m *testModule) slotWithToken(label string) (uint, error) {
+	if label != "token" {
+		return 0, fmt.Errorf("no token labeled %q found", label)
+	}
+	return 1, nil
+}
+
+func (m *testModule) openSession(slot uint) (sessionHandle, error) {
+	m.Lock()
+	defer m.Unlock()
+	if slot != 1 {
+		return 0, returnValue(ckrTokenNotPresent)
+	}
+	m.next++
+	m.sessions[m.next] = true
+	if len(m.sessions) > m.maxOpen {
+		m.maxOpen = len(m.sessions)
+	}
+	return m.next, nil
+}
+
+func (m *testModule) closeSession(s sessionHandle) error {
+	m.Lock()
+	defer m.Unlock()
+	if !m.sessions[s] {
+		return returnValue(ckrSessionHandleInvalid)
+	}
+	delete(m.sessions, s)
+	return nil
+}
+
+func (m *testModule) login(s sessionHandle, pin string) error {
+	m.Lock()
+	defer m.Unlock()
+	if pin != "1234" {
+		return returnValue(ckrPinIncorrect)
+	}
+	m.loggedIn = true
+	return nil
+}
+
+func (m *testModule) findKey(s sessionHandle, label string) (objectHandle, error) {
+	if label != "boundary" {
+		return 0, fmt.Errorf("no secret key labeled %q found", label)
+	}
+	return 7, nil
+}
+
+func (m *testModule) check(s sessionHandle, key objectHandle) error {
+	m.Lock()
+	defer m.Unlock()
+	switch {
+	case m.fail != nil:
+		return m.fail
+	case !m.sessions[s]:
+		return returnValue(ckrSessionHandleInvalid)
+	case !m.loggedIn:
+		return returnValue(ckrUserNotLoggedIn)
+	case key != 7:
+		return returnValue(ckrKeyHandleInvalid)
+	}
+	return nil
+}
+
+func (m *testModule) encrypt(s sessionHandle, key objectHandle, iv, plaintext []byte) ([]byte, error) {
+	if err := m.check(s, key); err != nil {
+		return nil, err
+	}
+	return m.gcm.Seal(nil, iv, plaintext, nil), nil
+}
+
+func (m *testModule) decrypt(s sessionHandle, key objectHandle, iv, ciphertext []byte) ([]byte, error) {
+	if err := m.check(s, key); err != nil {
+		return nil, err
+	}
+	return m.gcm.Open(nil, iv, ciphertext, nil)
+}
+
+func (m *testModule) finalize() error {
+	m.Lock()
+	defer m.Unlock()
+	m.finalized++
+	return nil
+}
+
+// reset invalidates all sessions and logins, as when a token is reset.
+func (m *testModule) reset() {
+	m.Lock()
+	defer m.Unlock()
+	m.sessions = map[sessionHandle]bool{}
+	m.loggedIn = false
+}
+
+func testWrapper(t *testing.T, config map[string]string) (*Wrapper, *testModule) {
+	t.Helper()
+	mod := newTestModule(t)
+	loads := 0
+	loadModule = func(string) (module, error) {
+		loads++
+		require.Equal(t, 1, loads, "library loaded more than once")
+		return mod, nil
+	}
+	t.Cleanup(func() { loadModule = loadLibrary })
+
+	c := map[string]string{
+		"lib":                   "/usr/lib/test-pkcs11.so",
+		"token_label":           "token",
+		"pin":                   "1234",
+		"key_label":             "boundary",
+		"health_check_interval": "0",
+	}
+	for k, v := range config {
+		c[k] = v
+	}
+	w := NewWrapper()
+	_, err := w.SetConfig(c)
+	require.NoError(t, err)
+	require.NoError(t, w.Init(context.Background()))
+	return w, mod
+}
+
+func TestWrapper_SetConfig(t *testing.T) {
+	base := func(overrides map[string]string) map[string]string {
+		c := map[string]string{"lib": "lib.so", "slot": "0", "pin": "1234", "key_label": "boundary"}
+		for k, v := range overrides {
+			if v == "" {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+	tests := []struct {
+		name    string
+		config  map[string]string
+		wantErr bool
+	}{
+		{name: "valid", config: base(nil)},
+		{name: "token-label", config: base(map[string]string{"slot": "", "token_label": "token"})},
+		{name: "no-lib", config: base(map[string]string{"lib": ""}), wantErr: true},
+		{name: "no-pin", config: base(map[string]string{"pin": ""}), wantErr: true},
+		{name: "no-key-label", config: base(map[string]string{"key_label": ""}), wantErr: true},
+		{name: "no-slot", config: base(map[string]string{"slot": ""}), wantErr: true},
+		{name: "slot-and-token-label", config: base(map[string]string{"token_label": "token"}), wantErr: true},
+		{name: "bad-slot", config: base(map[string]string{"slot": "first"}), wantErr: true},
+		{name: "bad-max-parallel", config: base(map[string]string{"max_parallel": "0"}), wantErr: true},
+		{name: "bad-health-check-interval", config: base(map[string]string{"health_check_interval": "often"}), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWrapper()
+			_, err := w.SetConfig(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+	t.Run("key-id", func(t *testing.T) {
+		w := NewWrapper()
+		_, err := w.SetConfig(base(nil))
+		require.NoError(t, err)
+		assert.Equal(t, "boundary", w.KeyID())
+		_, err = w.SetConfig(base(map[string]string{"key_id": "global_root"}))
+		require.NoError(t, err)
+		assert.Equal(t, "global_root", w.KeyID())
+	})
+}
+
+func TestWrapper_EncryptDecrypt(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	w, _ := testWrapper(t, nil)
+	defer w.Finalize(context.Background())
+	ctx := context.Background()
+
+	blob, err := w.Encrypt(ctx, []byte("secret"), []byte("aad"))
+	require.NoError(err)
+	assert.NotContains(string(blob.Ciphertext), "secret")
+	assert.Equal("boundary", blob.KeyInfo.KeyID)
+
+	pt, err := w.Decrypt(ctx, blob, []byte("aad"))
+	require.NoError(err)
+	assert.Equal("secret", string(pt))
+
+	_, err = w.Decrypt(ctx, blob, []byte("other"))
+	assert.Error(err)
+	_, err = w.Decrypt(ctx, nil, nil)
+	assert.Error(err)
+}
+
+func TestWrapper_Init(t *testing.T) {
+	mod := newTestModule(t)
+	loadModule = func(string) (module, error) { return mod, nil }
+	defer func() { loadModule = loadLibrary }()
+
+	tests := []struct {
+		name   string
+		config map[string]string
+	}{
+		{name: "bad-pin", config: map[string]string{"pin": "0000"}},
+		{name: "bad-token", config: map[string]string{"token_label": "other"}},
+		{name: "bad-key", config: map[string]string{"key_label": "other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := map[string]string{"lib": "lib.so", "token_label": "token", "pin": "1234", "key_label": "boundary"}
+			for k, v := range tt.config {
+				c[k] = v
+			}
+			w := NewWrapper()
+			_, err := w.SetConfig(c)
+			require.NoError(t, err)
+			assert.Error(t, w.Init(context.Background()))
+		})
+	}
+	assert.Equal(t, 3, mod.finalized, "library not released after failed init")
+}
+
+func TestWrapper_Parallel(t *testing.T) {
+	w, mod := testWrapper(t, map[string]string{"max_parallel": "3"})
+	defer w.Finalize(context.Background())
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			blob, err := w.Encrypt(ctx, []byte("secret"), nil)
+			if err == nil {
+				_, err = w.Decrypt(ctx, blob, nil)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.LessOrEqual(t, mod.maxOpen, 3)
+}
+
+func TestWrapper_TokenReset(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	w, mod := testWrapper(t, nil)
+	defer w.Finalize(context.Background())
+	ctx := context.Background()
+
+	blob, err := w.Encrypt(ctx, []byte("secret"), nil)
+	require.NoError(err)
+
+	// Sessions are reopened and logged in again after a reset
+	mod.reset()
+	pt, err := w.Decrypt(ctx, blob, nil)
+	require.NoError(err)
+	assert.Equal("secret", string(pt))
+}
+
+func TestWrapper_Health(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	w, mod := testWrapper(t, nil)
+	defer w.Finalize(context.Background())
+	ctx := context.Background()
+
+	assert.NoError(w.Health())
+	mod.Lock()
+	mod.fail = returnValue(ckrDeviceError)
+	mod.Unlock()
+	require.Error(w.checkHealth(ctx))
+	assert.True(errors.Is(w.Health(), returnValue(ckrDeviceError)))
+
+	mod.Lock()
+	mod.fail = nil
+	mod.Unlock()
+	require.NoError(w.checkHealth(ctx))
+	assert.NoError(w.Health())
+}
+
+func TestWrapper_SharedModule(t *testing.T) {
+	require := require.New(t)
+	w1, mod := testWrapper(t, nil)
+
+	// A second wrapper for the same library reuses the loaded library
+	w2 := NewWrapper()
+	_, err := w2.SetConfig(map[string]string{"lib": "/usr/lib/test-pkcs11.so", "slot": "1", "pin": "1234", "key_label": "boundary", "health_check_interval": "0"})
+	require.NoError(err)
+	require.NoError(w2.Init(context.Background()))
+
+	require.NoError(w1.Finalize(context.Background()))
+	require.Equal(0, mod.finalized)
+	require.NoError(w2.Finalize(context.Background()))
+	require.Equal(1, mod.finalized)
+}
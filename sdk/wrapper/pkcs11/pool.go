@@ -0,0 +1,86 @@
+package pkcs11
+
+import (
+	"context"
+	"sync"
+)
+
+// sessionPool holds up to max sessions, so that up to max operations can run
+// in parallel on the token while sessions are reused between them.
+type sessionPool struct {
+	open  func() (sessionHandle, error)
+	close func(sessionHandle) error
+
+	// idle holds the open sessions not in use
+	idle chan sessionHandle
+	// slots holds a value for each open session
+	slots chan struct{}
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newSessionPool(max int, open func() (sessionHandle, error), close func(sessionHandle) error) *sessionPool {
+	return &sessionPool{
+		open:   open,
+		close:  close,
+		idle:   make(chan sessionHandle, max),
+		slots:  make(chan struct{}, max),
+		closed: make(chan struct{}),
+	}
+}
+
+// get returns an idle session, opening a new one if fewer than max are open,
+// or waits for one to become idle.
+func (p *sessionPool) get(ctx context.Context) (sessionHandle, error) {
+	select {
+	case s := <-p.idle:
+		return s, nil
+	default:
+	}
+	select {
+	case s := <-p.idle:
+		return s, nil
+	case p.slots <- struct{}{}:
+		s, err := p.open()
+		if err != nil {
+			<-p.slots
+			return 0, err
+		}
+		return s, nil
+	case <-p.closed:
+		return 0, errPoolClosed
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// put returns s to the pool for reuse.
+func (p *sessionPool) put(s sessionHandle) {
+	select {
+	case <-p.closed:
+		p.discard(s)
+	default:
+		p.idle <- s
+	}
+}
+
+// discard closes s, which must not be reused.
+func (p *sessionPool) discard(s sessionHandle) {
+	_ = p.close(s)
+	<-p.slots
+}
+
+// closeIdle closes the idle sessions and makes get fail from then on.
+// Sessions in use are closed when they are put back.
+func (p *sessionPool) closeIdle() {
+	p.closeOnce.Do(func() { close(p.closed) })
+	for {
+		select {
+		case s := <-p.idle:
+			p.discard(s)
+		default:
+			return
+		}
+	}
+}
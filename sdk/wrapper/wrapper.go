@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/boundary/sdk/strutil"
+	"github.com/hashicorp/boundary/sdk/wrapper/pkcs11"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"github.com/hashicorp/shared-secure-libs/configutil"
 )
@@ -40,7 +41,14 @@ func getWrapper(kmses []*configutil.KMS, purpose string) (wrapping.Wrapper, erro
 		return nil, nil
 	}
 
-	wrapper, err := configutil.ConfigureWrapper(kms, nil, nil, nil)
+	var wrapper wrapping.Wrapper
+	var err error
+	switch kms.Type {
+	case pkcs11.Type:
+		wrapper, err = pkcs11.ConfigureWrapper(kms, nil, nil)
+	default:
+		wrapper, err = configutil.ConfigureWrapper(kms, nil, nil, nil)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Error configuring kms: %w", err)
 	}